@@ -12,20 +12,24 @@
 //	PAYBRIDGE_DATABASE_HOST=localhost \
 //	PAYBRIDGE_SERVER_PORT=3000 \
 //	go run cmd/api/main.go
+//
+//	# Write the OpenAPI 3.1 specification and exit
+//	go run cmd/api/main.go -openapi-out api/openapi.json
 package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/joho/godotenv"
 
+	httpadapter "github.com/Haleralex/wallethub/internal/adapters/http"
 	"github.com/Haleralex/wallethub/internal/config"
 	"github.com/Haleralex/wallethub/internal/container"
 )
@@ -46,6 +50,7 @@ func main() {
 	configName := flag.String("config-name", "config", "Config file name (without extension)")
 	envOnly := flag.Bool("env-only", false, "Load config only from environment variables")
 	showVersion := flag.Bool("version", false, "Show version and exit")
+	openapiOut := flag.String("openapi-out", "", "Write the OpenAPI 3.1 specification to this file and exit")
 	flag.Parse()
 
 	// Version flag
@@ -57,6 +62,14 @@ func main() {
 		os.Exit(0)
 	}
 
+	// OpenAPI flag - спецификация строится из маршрутов, конфиг не нужен
+	if *openapiOut != "" {
+		if err := writeOpenAPI(*openapiOut); err != nil {
+			log.Fatalf("Failed to write OpenAPI specification: %v", err)
+		}
+		os.Exit(0)
+	}
+
 	// Load configuration
 	var cfg *config.Config
 	var err error
@@ -82,11 +95,8 @@ func main() {
 	// Create container
 	c := container.New(cfg)
 
-	// Initialize with timeout
-	initCtx, initCancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer initCancel()
-
-	if err := c.Initialize(initCtx); err != nil {
+	// Initialize: бюджеты этапов и общий дедлайн - app.startup
+	if err := c.Initialize(context.Background()); err != nil {
 		log.Fatalf("Failed to initialize application: %v", err)
 	}
 
@@ -94,6 +104,16 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
+	// SIGHUP перечитывает конфигурацию и применяет уровни логирования
+	// (log.level, log.components); остальные настройки требуют рестарта
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			reloadLogLevels(c, *configPath, *configName, *envOnly)
+		}
+	}()
+
 	// Run server in goroutine
 	errChan := make(chan error, 1)
 	go func() {
@@ -132,6 +152,45 @@ func main() {
 	c.Logger().Info("Server stopped gracefully")
 }
 
+// reloadLogLevels перечитывает конфигурацию тем же способом, что и при
+// старте, и применяет уровни логирования. При ошибке уровни не меняются.
+func reloadLogLevels(c *container.Container, configPath, configName string, envOnly bool) {
+	var cfg *config.Config
+	var err error
+
+	if envOnly {
+		cfg, err = config.LoadFromEnv()
+	} else {
+		cfg, err = config.Load(configPath, configName)
+	}
+	if err == nil {
+		err = c.ReloadLogLevels(cfg.Log)
+	}
+
+	if err != nil {
+		c.Logger().Error("Failed to reload log levels", "error", err)
+		return
+	}
+	c.Logger().Info("Log levels reloaded",
+		"level", cfg.Log.Level,
+		"components", cfg.Log.Components,
+	)
+}
+
+// writeOpenAPI записывает спецификацию всех маршрутов API в path.
+func writeOpenAPI(path string) error {
+	doc, err := httpadapter.GenerateOpenAPI()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode specification: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
 func printBanner(cfg *config.Config) {
 	banner := `
 ╔═══════════════════════════════════════════════════════════════╗
@@ -0,0 +1,242 @@
+// Backfill tool for PayBridge.
+// Imports historical transactions from the legacy ledger (NDJSON, one
+// transaction per line) with their original IDs, statuses and timestamps.
+// Wallet balances are migrated separately: the import does not change them
+// and publishes no events. Re-running with the same file skips transactions
+// imported before, so an interrupted run can simply be restarted.
+//
+// With --expected, the imported ledger of every wallet is reconciled against
+// the legacy final balances (NDJSON: {"wallet_id": "...", "balance_cents": 0});
+// any mismatch makes the tool exit with a non-zero status.
+//
+// Usage:
+//
+//	backfill [--in transactions.ndjson] [--expected balances.ndjson] [--batch-size 5000]
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/usecases/backfill"
+	"github.com/Haleralex/wallethub/internal/config"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/postgres"
+)
+
+// maxLineBytes caps the length of a single NDJSON line.
+const maxLineBytes = 1 << 20
+
+func main() {
+	var (
+		in        string
+		expected  string
+		batchSize int
+	)
+	flag.StringVar(&in, "in", "", "Transactions NDJSON file (default: stdin)")
+	flag.StringVar(&expected, "expected", "", "Expected wallet balances NDJSON file (enables reconciliation)")
+	flag.IntVar(&batchSize, "batch-size", 5000, "Transactions per COPY batch")
+	flag.Parse()
+
+	if batchSize <= 0 {
+		log.Fatal("--batch-size must be positive")
+	}
+
+	// Load .env if present
+	_ = godotenv.Load()
+
+	cfg, err := config.Load("./configs", "config")
+	if err != nil {
+		cfg, err = config.LoadFromEnv()
+		if err != nil {
+			log.Fatalf("failed to load config: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	if err := run(ctx, cfg, in, expected, batchSize); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, cfg *config.Config, in, expectedPath string, batchSize int) error {
+	// Read expected balances first: a broken file must not surface
+	// after an hour of importing
+	var expected []dtos.BackfillExpectedBalance
+	if expectedPath != "" {
+		var err error
+		if expected, err = readExpected(expectedPath); err != nil {
+			return err
+		}
+	}
+
+	r := io.Reader(os.Stdin)
+	if in != "" {
+		f, err := os.Open(in)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", in, err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	pool, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	uc := backfill.NewBackfillTransactionsUseCase(postgres.NewTransactionRepository(pool))
+	reconciliation := backfill.NewReconciliation()
+
+	var (
+		imported, skipped int
+		started           = time.Now()
+		batch             = make([]dtos.BackfillTransactionRecord, 0, batchSize)
+		firstLine         int
+	)
+	flush := func(lastLine int) error {
+		if len(batch) == 0 {
+			return nil
+		}
+		result, err := uc.Execute(ctx, dtos.BackfillTransactionsCommand{Records: batch})
+		if err != nil {
+			return fmt.Errorf("batch at lines %d-%d: %w", firstLine, lastLine, err)
+		}
+		reconciliation.Add(result)
+		imported += result.Imported
+		skipped += result.Skipped
+
+		elapsed := time.Since(started)
+		log.Printf("Progress: line %d, imported %d, skipped %d (%.0f rows/s)",
+			lastLine, imported, skipped, float64(imported+skipped)/elapsed.Seconds())
+		batch = batch[:0]
+		return nil
+	}
+
+	line := 0
+	err = scanLines(r, func(n int, data []byte) error {
+		line = n
+		var record dtos.BackfillTransactionRecord
+		if err := decodeStrict(data, &record); err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		if len(batch) == 0 {
+			firstLine = n
+		}
+		batch = append(batch, record)
+		if len(batch) < batchSize {
+			return nil
+		}
+		return flush(n)
+	})
+	if err != nil {
+		return err
+	}
+	if err := flush(line); err != nil {
+		return err
+	}
+
+	log.Printf("Imported %d transactions, skipped %d already imported in %s",
+		imported, skipped, time.Since(started).Round(time.Second))
+
+	if expectedPath == "" {
+		return nil
+	}
+	report, err := reconciliation.Check(expected)
+	if err != nil {
+		return fmt.Errorf("reconciliation failed: %w", err)
+	}
+	if err := writeJSON(os.Stdout, report); err != nil {
+		return err
+	}
+	if len(report.Mismatches) > 0 {
+		return fmt.Errorf("reconciliation: %d of %d wallets do not match expected balances",
+			len(report.Mismatches), report.Wallets)
+	}
+	log.Printf("Reconciliation: all %d wallets match expected balances", report.Wallets)
+	return nil
+}
+
+// readExpected reads the expected balances file.
+func readExpected(path string) ([]dtos.BackfillExpectedBalance, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var expected []dtos.BackfillExpectedBalance
+	err = scanLines(f, func(n int, data []byte) error {
+		var balance dtos.BackfillExpectedBalance
+		if err := decodeStrict(data, &balance); err != nil {
+			return fmt.Errorf("%s line %d: %w", path, n, err)
+		}
+		expected = append(expected, balance)
+		return nil
+	})
+	return expected, err
+}
+
+// scanLines calls fn for every non-empty NDJSON line (numbered from 1).
+func scanLines(r io.Reader, fn func(n int, data []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLineBytes)
+
+	for n := 1; scanner.Scan(); n++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		if err := fn(n, data); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+	return nil
+}
+
+// decodeStrict rejects unknown fields: a typo in the export must not
+// silently become an empty value.
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return nil
+}
+
+func connect(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	pool, err := pgxpool.New(ctx, cfg.Database.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return pool, nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
@@ -5,8 +5,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -15,14 +17,21 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 
+	"github.com/Haleralex/wallethub/internal/adapters/http/middleware"
 	natsadapter "github.com/Haleralex/wallethub/internal/adapters/nats"
+	"github.com/Haleralex/wallethub/internal/application/ports"
 	"github.com/Haleralex/wallethub/internal/config"
 	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/cache"
+	"github.com/Haleralex/wallethub/internal/infrastructure/leaselock"
 	"github.com/Haleralex/wallethub/internal/infrastructure/notification"
 	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/postgres"
 	"github.com/Haleralex/wallethub/internal/infrastructure/poller"
 	"github.com/Haleralex/wallethub/internal/infrastructure/telemetry"
+	"github.com/Haleralex/wallethub/internal/infrastructure/workers"
 )
 
 func main() {
@@ -113,7 +122,8 @@ func main() {
 	}
 
 	// Create repositories
-	outboxRepo := postgres.NewOutboxRepository(pool)
+	// Notifier только читает outbox - producer_version пишет API
+	outboxRepo := postgres.NewOutboxRepository(pool, "", nil)
 	walletRepo := postgres.NewWalletRepository(pool)
 	userRepo := postgres.NewUserRepository(pool)
 
@@ -140,6 +150,8 @@ func main() {
 
 	// Register event handlers — only notify the recipient (wallet.credited)
 	subscriber.Handle("paybridge.events."+events.EventTypeWalletCredited, notifHandler.HandleWalletCredited)
+	// Owner of an automatically disabled webhook subscription
+	subscriber.Handle("paybridge.events."+events.EventTypeWebhookDisabled, notifHandler.HandleWebhookSubscriptionDisabled)
 
 	// Start NATS subscriber
 	if err := subscriber.Start(ctx); err != nil {
@@ -148,17 +160,74 @@ func main() {
 	}
 	defer subscriber.Stop()
 
+	// Heartbeat outbox relay: в API он виден через workers_heartbeat
+	var heartbeatRepo ports.WorkerHeartbeatRepository
+	if cfg.Workers.Persist {
+		heartbeatRepo = postgres.NewWorkerHeartbeatRepository(pool)
+	}
+	workerRegistry := workers.NewRegistry(logger, heartbeatRepo, workers.Config{
+		PersistInterval: cfg.Workers.PersistInterval,
+	})
+	relayStaleAfter := 3 * cfg.Notifier.PollInterval
+	if staleAfter, ok := cfg.Workers.StaleAfter["outbox-relay"]; ok {
+		relayStaleAfter = staleAfter
+	}
+	relayHeartbeat := workerRegistry.Register("outbox-relay", relayStaleAfter)
+	workerRegistry.Start()
+
+	// Выбор лидера: основную полосу outbox опрашивает один экземпляр
+	var rdb *redis.Client
+	if cfg.Locks.Backend == "redis" {
+		if rdb, err = cache.NewRedisClient(ctx, cfg.Redis); err != nil {
+			logger.Error("Failed to connect to Redis for leases", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer rdb.Close()
+	}
+	leader, err := leaselock.New(ctx, cfg.Locks, pool, rdb)
+	if err != nil {
+		logger.Error("Failed to create outbox relay leader lock", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
 	// Start outbox poller
 	outboxPoller := poller.New(outboxRepo, publisher, logger, poller.Config{
 		PollInterval: cfg.Notifier.PollInterval,
 		BatchSize:    cfg.Notifier.BatchSize,
 		MaxRetries:   cfg.Notifier.MaxRetries,
+
+		HighPollInterval: cfg.Notifier.HighPriorityPollInterval,
+		HighBatchSize:    cfg.Notifier.HighPriorityBatchSize,
+		OnQueueDepth:     middleware.SetOutboxQueueDepth,
+		OnDelivered:      middleware.RecordOutboxDelivery,
+		Heartbeat:        relayHeartbeat,
+		Leader:           leader,
+		LeaderTTL:        cfg.Locks.TTL,
 	})
 	go outboxPoller.Start(ctx)
 
+	// Expose outbox lane metrics
+	if cfg.Notifier.MetricsPort > 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		metricsServer := &http.Server{
+			Addr:              fmt.Sprintf(":%d", cfg.Notifier.MetricsPort),
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Metrics server failed", slog.String("error", err.Error()))
+			}
+		}()
+		defer metricsServer.Close()
+	}
+
 	logger.Info("Notification service is running",
 		slog.Duration("poll_interval", cfg.Notifier.PollInterval),
 		slog.Int("batch_size", cfg.Notifier.BatchSize),
+		slog.Duration("high_priority_poll_interval", cfg.Notifier.HighPriorityPollInterval),
+		slog.String("leader_lock", cfg.Locks.Backend),
 	)
 
 	// Wait for shutdown signal
@@ -174,6 +243,9 @@ func main() {
 	defer shutdownCancel()
 
 	outboxPoller.Stop()
+	if err := workerRegistry.Stop(shutdownCtx); err != nil {
+		logger.Warn("Failed to stop worker registry", slog.String("error", err.Error()))
+	}
 	_ = subscriber.Stop()
 	nc.Drain()
 
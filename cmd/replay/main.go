@@ -0,0 +1,108 @@
+// Replay tool for PayBridge.
+// Rebuilds a single wallet from its completed transactions with the live
+// domain rules and prints the diff against the stored wallet. Read-only.
+//
+// Usage:
+//
+//	replay --wallet <id> [--page-size 1000]
+//
+// Exit codes: 0 - consistent, 1 - error, 2 - diverged, 3 - unreplayable.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/usecases/replay"
+	"github.com/Haleralex/wallethub/internal/config"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/postgres"
+)
+
+func main() {
+	var (
+		walletID string
+		pageSize int
+	)
+
+	flag.StringVar(&walletID, "wallet", "", "Wallet ID to replay")
+	flag.IntVar(&pageSize, "page-size", replay.DefaultPageSize, "Transactions read per page")
+	flag.Parse()
+
+	if walletID == "" {
+		log.Fatal("usage: replay --wallet <id> [--page-size n]")
+	}
+
+	// Load .env if present
+	_ = godotenv.Load()
+
+	cfg, err := config.Load("./configs", "config")
+	if err != nil {
+		cfg, err = config.LoadFromEnv()
+		if err != nil {
+			log.Fatalf("failed to load config: %v", err)
+		}
+	}
+
+	report, err := run(context.Background(), cfg, walletID, pageSize)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := writeJSON(os.Stdout, report); err != nil {
+		log.Fatal(err)
+	}
+
+	switch report.Outcome {
+	case replay.OutcomeDiverged:
+		os.Exit(2)
+	case replay.OutcomeUnreplayable:
+		os.Exit(3)
+	}
+}
+
+func run(ctx context.Context, cfg *config.Config, walletID string, pageSize int) (*dtos.WalletReplayDTO, error) {
+	pool, err := connect(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer pool.Close()
+
+	uc := replay.NewReplayWalletUseCase(
+		postgres.NewWalletRepository(pool),
+		postgres.NewTransactionRepository(pool),
+	).WithPageSize(pageSize)
+	report, err := uc.Execute(ctx, dtos.ReplayWalletQuery{WalletID: walletID})
+	if err != nil {
+		return nil, fmt.Errorf("replay failed: %w", err)
+	}
+	return report, nil
+}
+
+func connect(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	pool, err := pgxpool.New(ctx, cfg.Database.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return pool, nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Snapshot tool for PayBridge.
+// Exports users with their wallets and recent transactions into a JSON bundle
+// (PII masked) and imports such a bundle into a non-production environment.
+//
+// Usage:
+//
+//	snapshot export --user <id> [--user <id>...] [--since 90d] [--out bundle.json]
+//	snapshot import [--in bundle.json]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/usecases/snapshot"
+	"github.com/Haleralex/wallethub/internal/config"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/postgres"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("usage: snapshot <export|import> [flags]")
+	}
+
+	// Load .env if present
+	_ = godotenv.Load()
+
+	cfg, err := config.Load("./configs", "config")
+	if err != nil {
+		cfg, err = config.LoadFromEnv()
+		if err != nil {
+			log.Fatalf("failed to load config: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "export":
+		err = runExport(ctx, cfg, os.Args[2:])
+	case "import":
+		err = runImport(ctx, cfg, os.Args[2:])
+	default:
+		log.Fatalf("unknown command: %s\nAvailable commands: export, import", os.Args[1])
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func runExport(ctx context.Context, cfg *config.Config, args []string) error {
+	var (
+		users userList
+		since string
+		out   string
+	)
+
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.Var(&users, "user", "User ID to export (repeatable)")
+	fs.StringVar(&since, "since", "90d", "Transaction history depth: 90d, 720h; 0 = all history")
+	fs.StringVar(&out, "out", "", "Output file (default: stdout)")
+	_ = fs.Parse(args)
+
+	window, err := parseSince(since)
+	if err != nil {
+		return err
+	}
+
+	pool, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	uc := snapshot.NewExportSnapshotUseCase(
+		postgres.NewUserRepository(pool).WithEmailPolicy(cfg.EmailPolicy.Policy()),
+		postgres.NewWalletRepository(pool),
+		postgres.NewTransactionRepository(pool),
+	)
+	bundle, err := uc.Execute(ctx, dtos.ExportSnapshotQuery{UserIDs: users, Since: window})
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	w := io.Writer(os.Stdout)
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writeJSON(w, bundle); err != nil {
+		return err
+	}
+
+	log.Printf("Exported %d users, %d wallets, %d transactions",
+		len(bundle.Users), len(bundle.Wallets), len(bundle.Transactions))
+	return nil
+}
+
+func runImport(ctx context.Context, cfg *config.Config, args []string) error {
+	var in string
+
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	fs.StringVar(&in, "in", "", "Bundle file (default: stdin)")
+	_ = fs.Parse(args)
+
+	// Fail fast before connecting; the use case enforces the same rule
+	if cfg.App.IsProduction() {
+		return fmt.Errorf("snapshot import is disabled in production")
+	}
+
+	r := io.Reader(os.Stdin)
+	if in != "" {
+		f, err := os.Open(in)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", in, err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var bundle dtos.SnapshotBundle
+	if err := json.NewDecoder(r).Decode(&bundle); err != nil {
+		return fmt.Errorf("failed to decode bundle: %w", err)
+	}
+
+	pool, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	uc := snapshot.NewImportSnapshotUseCase(
+		postgres.NewUserRepository(pool).WithEmailPolicy(cfg.EmailPolicy.Policy()),
+		postgres.NewWalletRepository(pool),
+		postgres.NewTransactionRepository(pool).WithEnvironment(cfg.App.Environment),
+		postgres.NewUnitOfWork(pool),
+		cfg.App.IsProduction(),
+	)
+	summary, err := uc.Execute(ctx, dtos.ImportSnapshotCommand{Bundle: &bundle})
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	return writeJSON(os.Stdout, summary)
+}
+
+func connect(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	pool, err := pgxpool.New(ctx, cfg.Database.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return pool, nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// parseSince accepts days ("90d") and Go durations ("720h").
+func parseSince(s string) (time.Duration, error) {
+	if s == "" || s == "0" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid --since %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid --since %q", s)
+	}
+	return d, nil
+}
+
+// userList implements a repeatable --user flag.
+type userList []string
+
+func (u *userList) String() string {
+	return strings.Join(*u, ",")
+}
+
+func (u *userList) Set(value string) error {
+	*u = append(*u, value)
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	pb "github.com/Haleralex/wallethub/internal/adapters/grpc/pb"
+	"github.com/Haleralex/wallethub/internal/pkg/logger"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -49,8 +50,8 @@ func NewFraudServer(pool *pgxpool.Pool, logger *slog.Logger, config FraudRulesCo
 // CheckTransaction evaluates a transaction for fraud risk.
 func (s *FraudServer) CheckTransaction(ctx context.Context, req *pb.CheckTransactionRequest) (*pb.CheckTransactionResponse, error) {
 	s.logger.Info("Fraud check requested",
-		slog.String("user_id", req.UserId),
-		slog.String("amount", req.Amount),
+		slog.String(logger.FieldUserID, req.UserId),
+		slog.String(logger.FieldAmount, req.Amount),
 		slog.String("currency", req.Currency),
 		slog.String("type", req.TransactionType),
 	)
@@ -69,8 +70,8 @@ func (s *FraudServer) CheckTransaction(ctx context.Context, req *pb.CheckTransac
 	// Rule 1: High amount threshold
 	if amountFloat > s.config.HighAmountThreshold {
 		s.logger.Warn("High amount detected",
-			slog.String("user_id", req.UserId),
-			slog.Float64("amount", amountFloat),
+			slog.String(logger.FieldUserID, req.UserId),
+			slog.String(logger.FieldAmount, req.Amount),
 			slog.Float64("threshold", s.config.HighAmountThreshold),
 		)
 		return &pb.CheckTransactionResponse{
@@ -83,7 +84,7 @@ func (s *FraudServer) CheckTransaction(ctx context.Context, req *pb.CheckTransac
 	// Rule 2: Transaction frequency (too many transactions in last hour)
 	txCount, err := s.countRecentTransactions(ctx, req.UserId)
 	if err != nil {
-		s.logger.Error("Failed to count recent transactions", slog.String("error", err.Error()))
+		s.logger.Error("Failed to count recent transactions", logger.Err(err))
 		// Don't block on internal errors — approve with warning
 		return &pb.CheckTransactionResponse{
 			Approved:  true,
@@ -94,7 +95,7 @@ func (s *FraudServer) CheckTransaction(ctx context.Context, req *pb.CheckTransac
 
 	if txCount >= s.config.MaxTransactionsPerHour {
 		s.logger.Warn("High transaction frequency",
-			slog.String("user_id", req.UserId),
+			slog.String(logger.FieldUserID, req.UserId),
 			slog.Int("count", txCount),
 			slog.Int("max", s.config.MaxTransactionsPerHour),
 		)
@@ -108,12 +109,12 @@ func (s *FraudServer) CheckTransaction(ctx context.Context, req *pb.CheckTransac
 	// Rule 3: New account + large amount
 	accountAge, err := s.getAccountAge(ctx, req.UserId)
 	if err != nil {
-		s.logger.Error("Failed to get account age", slog.String("error", err.Error()))
+		s.logger.Error("Failed to get account age", logger.Err(err))
 	} else if accountAge < s.config.NewAccountAge && amountFloat > s.config.NewAccountMaxAmount {
 		s.logger.Warn("New account with large amount",
-			slog.String("user_id", req.UserId),
+			slog.String(logger.FieldUserID, req.UserId),
 			slog.Duration("account_age", accountAge),
-			slog.Float64("amount", amountFloat),
+			slog.String(logger.FieldAmount, req.Amount),
 		)
 		return &pb.CheckTransactionResponse{
 			Approved:  false,
@@ -135,7 +136,7 @@ func (s *FraudServer) CheckTransaction(ctx context.Context, req *pb.CheckTransac
 	}
 
 	s.logger.Info("Fraud check passed",
-		slog.String("user_id", req.UserId),
+		slog.String(logger.FieldUserID, req.UserId),
 		slog.Float64("risk_score", riskScore),
 	)
 
@@ -0,0 +1,68 @@
+// Package common - conditional GET (ETag / If-None-Match) и HEAD.
+package common
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// ETag Helpers
+// ============================================
+
+// StrongETag собирает strong ETag из частей версии ресурса.
+//
+// Пример: StrongETag("7", "1705312800000000000") -> "\"7-1705312800000000000\""
+func StrongETag(parts ...string) string {
+	return `"` + strings.Join(parts, "-") + `"`
+}
+
+// ETagMatches проверяет заголовок If-None-Match против текущего ETag.
+//
+// Для If-None-Match используется weak comparison (RFC 9110 13.1.2):
+// префикс W/ игнорируется, "*" совпадает с любым ETag.
+func ETagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SuccessWithETag отправляет успешный ответ на GET/HEAD ресурса с ETag.
+//
+// Поведение:
+// - ETag всегда выставляется в заголовок
+// - If-None-Match совпал: 304 Not Modified без тела
+// - HEAD: 200 с заголовками GET, но без тела
+// - иначе: обычный Success с 200
+//
+// Проверки auth/ownership должны быть выполнены до вызова.
+func SuccessWithETag(c *gin.Context, etag string, data interface{}) {
+	c.Header("ETag", etag)
+
+	if ETagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+
+	if c.Request.Method == http.MethodHead {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		c.Status(http.StatusOK)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+
+	Success(c, http.StatusOK, data)
+}
@@ -0,0 +1,37 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrongETag(t *testing.T) {
+	assert.Equal(t, `"7-1705312800000000000"`, StrongETag("7", "1705312800000000000"))
+	assert.Equal(t, `"COMPLETED"`, StrongETag("COMPLETED"))
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := `"7-100"`
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{"Empty", "", false},
+		{"Exact", `"7-100"`, true},
+		{"Different", `"7-101"`, false},
+		{"Wildcard", "*", true},
+		{"WeakPrefix", `W/"7-100"`, true},
+		{"List", `"1-1", "7-100"`, true},
+		{"ListWithoutMatch", `"1-1", "2-2"`, false},
+		{"Unquoted", "7-100", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ETagMatches(tt.ifNoneMatch, etag))
+		})
+	}
+}
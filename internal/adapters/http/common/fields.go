@@ -0,0 +1,146 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Sparse Fieldsets
+// ============================================
+
+// FieldsQueryParam - имя query параметра для выбора полей.
+const FieldsQueryParam = "fields"
+
+// AlwaysIncludedField - поле, которое возвращается при любом выборе.
+const AlwaysIncludedField = "id"
+
+// FieldSet - выбранные клиентом поля ресурса (sparse fieldset).
+//
+// nil означает "все поля" (поведение по умолчанию).
+type FieldSet []string
+
+// ParseFieldSet разбирает значение параметра fields ("id,status,amount")
+// и проверяет каждое имя по allow-list ресурса.
+//
+// Возвращает nil, если параметр пустой, и список неизвестных полей, если они есть.
+// Поле id добавляется всегда.
+func ParseFieldSet(raw string, allowed []string) (FieldSet, []string) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	allowedSet := make(map[string]struct{}, len(allowed))
+	for _, name := range allowed {
+		allowedSet[name] = struct{}{}
+	}
+
+	fields := FieldSet{AlwaysIncludedField}
+	seen := map[string]struct{}{AlwaysIncludedField: {}}
+	var unknown []string
+
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := allowedSet[name]; !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		if _, dup := seen[name]; dup {
+			continue
+		}
+		seen[name] = struct{}{}
+		fields = append(fields, name)
+	}
+
+	return fields, unknown
+}
+
+// ProjectFields оставляет в каждом элементе только выбранные поля.
+//
+// Элемент сериализуется в map по json тегам DTO, поэтому один helper
+// обслуживает все ресурсы без отдельных структур под каждый набор полей.
+// Поля, опущенные DTO через omitempty, в проекции тоже отсутствуют.
+func ProjectFields[T any](items []T, fields FieldSet) ([]map[string]interface{}, error) {
+	projected := make([]map[string]interface{}, 0, len(items))
+
+	for _, item := range items {
+		full, err := toJSONMap(item)
+		if err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(fields))
+		for _, name := range fields {
+			if value, ok := full[name]; ok {
+				row[name] = value
+			}
+		}
+		projected = append(projected, row)
+	}
+
+	return projected, nil
+}
+
+// ProjectList проецирует элементы list DTO под ключом itemsKey.
+// Остальные поля list DTO (total_count, offset, limit) сохраняются как есть.
+func ProjectList[T any](list interface{}, itemsKey string, items []T, fields FieldSet) (map[string]interface{}, error) {
+	data, err := toJSONMap(list)
+	if err != nil {
+		return nil, err
+	}
+
+	projected, err := ProjectFields(items, fields)
+	if err != nil {
+		return nil, err
+	}
+	data[itemsKey] = projected
+
+	return data, nil
+}
+
+// toJSONMap превращает DTO в map по его json тегам. Числа остаются
+// json.Number: float64 округлил бы целые больше 2^53.
+func toJSONMap(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal DTO: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var m map[string]interface{}
+	if err := decoder.Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal DTO: %w", err)
+	}
+	return m, nil
+}
+
+// InvalidFieldsResponse создаёт ответ 400 для неизвестных полей со списком допустимых.
+func InvalidFieldsResponse(c *gin.Context, unknown, allowed []string) {
+	fields := make([]FieldError, 0, len(unknown))
+	for _, name := range unknown {
+		fields = append(fields, FieldError{
+			Field:   FieldsQueryParam,
+			Message: fmt.Sprintf("Unknown field '%s'", name),
+			Code:    FieldCodeUnknownField,
+		})
+	}
+
+	Error(c, http.StatusBadRequest, &APIError{
+		Code:    ErrCodeValidation,
+		Message: "Unknown fields requested; valid fields: " + strings.Join(allowed, ", "),
+		Details: map[string]interface{}{
+			"invalid_fields": unknown,
+			"valid_fields":   allowed,
+		},
+		Fields: fields,
+	})
+}
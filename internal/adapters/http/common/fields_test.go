@@ -0,0 +1,79 @@
+package common
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFieldSet(t *testing.T) {
+	allowed := []string{"id", "status", "amount", "metadata"}
+
+	tests := []struct {
+		name        string
+		raw         string
+		wantFields  FieldSet
+		wantUnknown []string
+	}{
+		{"Empty", "", nil, nil},
+		{"Blank", "  ", nil, nil},
+		{"IDAlwaysFirst", "status,amount", FieldSet{"id", "status", "amount"}, nil},
+		{"TrimsAndDeduplicates", " status , status,,id ", FieldSet{"id", "status"}, nil},
+		{"Unknown", "status,secret,other", FieldSet{"id", "status"}, []string{"secret", "other"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, unknown := ParseFieldSet(tt.raw, allowed)
+			assert.Equal(t, tt.wantFields, fields)
+			assert.Equal(t, tt.wantUnknown, unknown)
+		})
+	}
+}
+
+func TestProjectList(t *testing.T) {
+	type item struct {
+		ID       string            `json:"id"`
+		Status   string            `json:"status"`
+		Metadata map[string]string `json:"metadata,omitempty"`
+	}
+	type list struct {
+		Items      []item `json:"items"`
+		TotalCount int    `json:"total_count"`
+	}
+
+	l := list{
+		Items: []item{
+			{ID: "a", Status: "COMPLETED", Metadata: map[string]string{"k": "v"}},
+			{ID: "b", Status: "FAILED"},
+		},
+		TotalCount: 2,
+	}
+
+	data, err := ProjectList(l, "items", l.Items, FieldSet{"id", "metadata"})
+	require.NoError(t, err)
+
+	assert.Equal(t, json.Number("2"), data["total_count"])
+	assert.Equal(t, []map[string]interface{}{
+		{"id": "a", "metadata": map[string]interface{}{"k": "v"}},
+		{"id": "b"}, // omitempty: поля нет и в проекции
+	}, data["items"])
+}
+
+func TestProjectFields_KeepsLargeIntegers(t *testing.T) {
+	type item struct {
+		ID      string `json:"id"`
+		Balance int64  `json:"balance"`
+	}
+
+	projected, err := ProjectFields([]item{{ID: "a", Balance: math.MaxInt64}}, FieldSet{"id", "balance"})
+	require.NoError(t, err)
+
+	raw, err := json.Marshal(projected)
+	require.NoError(t, err)
+	// Строкой, а не JSONEq: JSONEq сравнивает числа как float64
+	assert.Equal(t, `[{"balance":9223372036854775807,"id":"a"}]`, string(raw))
+}
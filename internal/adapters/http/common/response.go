@@ -5,10 +5,15 @@
 package common
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
 	domainerrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
 	"github.com/gin-gonic/gin"
 )
 
@@ -24,6 +29,9 @@ type APIResponse struct {
 	Meta      *APIMeta    `json:"meta,omitempty"`
 	RequestID string      `json:"request_id"`
 	Timestamp time.Time   `json:"timestamp"`
+
+	// Pagination - описание страницы списка (data - массив элементов)
+	Pagination *dtos.PaginationDTO `json:"pagination,omitempty"`
 }
 
 // APIMeta - мета-информация для пагинации.
@@ -44,12 +52,27 @@ type APIError struct {
 }
 
 // FieldError - ошибка конкретного поля.
+//
+// Field - путь до поля в запросе: "amount", "metadata.source",
+// "items[3].idempotency_key".
 type FieldError struct {
 	Field   string `json:"field"`
 	Message string `json:"message"`
 	Code    string `json:"code"`
 }
 
+// Коды FieldError.Code. Первые пять совпадают с кодами domain
+// ValidationError, остальные возникают только при разборе запроса.
+const (
+	FieldCodeRequired      = domainerrors.ValidationCodeRequired
+	FieldCodeInvalidFormat = domainerrors.ValidationCodeInvalidFormat
+	FieldCodeInvalidValue  = domainerrors.ValidationCodeInvalidValue
+	FieldCodeOutOfRange    = domainerrors.ValidationCodeOutOfRange
+	FieldCodeSensitiveData = domainerrors.ValidationCodeSensitiveData
+	FieldCodeInvalidType   = "INVALID_TYPE"  // JSON тип не совпадает с полем (строка вместо числа)
+	FieldCodeUnknownField  = "UNKNOWN_FIELD" // Поле отсутствует в схеме запроса
+)
+
 // ============================================
 // Error Codes
 // ============================================
@@ -68,26 +91,33 @@ const (
 	ErrCodeConcurrency      = "CONCURRENCY_ERROR"
 	ErrCodeTimeout          = "TIMEOUT"
 	ErrCodeUnavailable      = "SERVICE_UNAVAILABLE"
+
+	// ErrCodeConcurrencyLimitExceeded - у клиента (или у инстанса) слишком
+	// много одновременных запросов класса. В отличие от TOO_MANY_REQUESTS
+	// помогает не пауза, а завершение уже отправленных запросов.
+	ErrCodeConcurrencyLimitExceeded = "CONCURRENCY_LIMIT_EXCEEDED"
+
+	// ErrCodePolicyDenied - запрос не прошёл политику доступа маршрута
+	// (middleware.AuthorizeForRoute).
+	ErrCodePolicyDenied = "POLICY_DENIED"
 )
 
 // ============================================
 // Request ID
 // ============================================
 
-const RequestIDKey = "X-Request-ID"
+// RequestIDHeader - имя заголовка для Request ID
+const RequestIDHeader = "X-Request-ID"
 
 // GetRequestID возвращает Request ID из контекста.
 func GetRequestID(c *gin.Context) string {
-	if id, exists := c.Get(RequestIDKey); exists {
-		return id.(string)
-	}
-	return ""
+	return httpctx.RequestID(c)
 }
 
-// SetRequestID устанавливает Request ID в контекст.
+// SetRequestID устанавливает Request ID в контекст и заголовок ответа.
 func SetRequestID(c *gin.Context, id string) {
-	c.Set(RequestIDKey, id)
-	c.Header(RequestIDKey, id)
+	httpctx.SetRequestID(c, id)
+	c.Header(RequestIDHeader, id)
 }
 
 // ============================================
@@ -115,6 +145,18 @@ func SuccessWithMeta(c *gin.Context, statusCode int, data interface{}, meta *API
 	})
 }
 
+// SuccessPage отправляет страницу списка: элементы в data, пагинация рядом.
+// items - элементы страницы, возможно уже спроецированные по fields.
+func SuccessPage(c *gin.Context, items interface{}, pagination dtos.PaginationDTO) {
+	c.JSON(http.StatusOK, APIResponse{
+		Success:    true,
+		Data:       items,
+		Pagination: &pagination,
+		RequestID:  GetRequestID(c),
+		Timestamp:  time.Now().UTC(),
+	})
+}
+
 // Error отправляет ответ с ошибкой.
 func Error(c *gin.Context, statusCode int, apiError *APIError) {
 	c.JSON(statusCode, APIResponse{
@@ -204,12 +246,10 @@ func InternalErrorResponse(c *gin.Context, message string) {
 
 // HandleDomainError преобразует domain error в HTTP response.
 func HandleDomainError(c *gin.Context, err error) {
-	// 1. Проверяем ValidationError
+	// 1. Проверяем ValidationError / ValidationErrors
 	if domainerrors.IsValidationError(err) {
-		if valErr := extractValidationError(err); valErr != nil {
-			ValidationErrorResponse(c, []FieldError{
-				{Field: valErr.Field, Message: valErr.Message, Code: "invalid"},
-			})
+		if fields := ValidationFieldErrors(err); len(fields) > 0 {
+			ValidationErrorResponse(c, fields)
 			return
 		}
 		BadRequestResponse(c, err.Error())
@@ -249,15 +289,56 @@ func HandleDomainError(c *gin.Context, err error) {
 		return
 	}
 
+	// 4a. Недостаточно средств: Wallet.Debit возвращает sentinel без кода
+	if errors.Is(err, domainerrors.ErrInsufficientBalance) {
+		Error(c, http.StatusUnprocessableEntity, &APIError{
+			Code:    "INSUFFICIENT_BALANCE",
+			Message: "Insufficient balance",
+		})
+		return
+	}
+
+	// 4b. Голый sentinel из Money: отдаём как CURRENCY_MISMATCH, не как 500
+	if errors.Is(err, valueobjects.ErrCurrencyMismatch) {
+		Error(c, http.StatusUnprocessableEntity, &APIError{
+			Code:    ErrCodeBusinessRule,
+			Message: err.Error(),
+			Details: map[string]interface{}{
+				"rule": domainerrors.CurrencyMismatchRule,
+			},
+		})
+		return
+	}
+
+	// 4c. Аварийный выключатель: 503 с причиной, чтобы клиент её показал
+	var disabled *domainerrors.OperationDisabledError
+	if errors.As(err, &disabled) {
+		Error(c, http.StatusServiceUnavailable, &APIError{
+			Code:    domainerrors.OperationDisabledCode,
+			Message: fmt.Sprintf("%s operations are temporarily disabled: %s", disabled.Operation, disabled.Reason),
+			Details: map[string]interface{}{
+				"operation": disabled.Operation,
+				"reason":    disabled.Reason,
+			},
+		})
+		return
+	}
+
 	// 5. Проверяем DomainError
 	if domainErr := extractDomainError(err); domainErr != nil {
 		statusCode := http.StatusBadRequest
 
 		switch domainErr.Code {
-		case "USER_NOT_FOUND", "WALLET_NOT_FOUND", "TRANSACTION_NOT_FOUND":
+		case "USER_NOT_FOUND", "WALLET_NOT_FOUND", "TRANSACTION_NOT_FOUND", "WALLET_NOTE_NOT_FOUND", "INCIDENT_NOT_FOUND", "RECEIPT_NOT_FOUND", "WEBHOOK_NOT_FOUND", "READ_SESSION_NOT_FOUND":
 			statusCode = http.StatusNotFound
 		case "INSUFFICIENT_BALANCE", "USER_NOT_VERIFIED":
 			statusCode = http.StatusUnprocessableEntity
+		case "ACTOR_REQUIRED":
+			statusCode = http.StatusUnauthorized
+		case "WALLET_NOTE_DELETE_FORBIDDEN", "KYC_AUTO_APPROVAL_FORBIDDEN", "PENDING_CAP_OVERRIDE_FORBIDDEN":
+			statusCode = http.StatusForbidden
+		case "RATE_LIMITED", "WALLET_BUSY", "READ_SESSION_LIMIT_EXCEEDED":
+			statusCode = http.StatusTooManyRequests
 		}
 
 		Error(c, statusCode, &APIError{
@@ -271,14 +352,30 @@ func HandleDomainError(c *gin.Context, err error) {
 	InternalErrorResponse(c, "An unexpected error occurred")
 }
 
-// extractValidationError извлекает ValidationError из цепочки ошибок.
-func extractValidationError(err error) *domainerrors.ValidationError {
+// ValidationFieldErrors разворачивает ValidationError или ValidationErrors
+// из цепочки ошибок в список FieldError. Пустой код становится INVALID_VALUE.
+func ValidationFieldErrors(err error) []FieldError {
+	var list domainerrors.ValidationErrors
 	for e := err; e != nil; e = unwrap(e) {
 		if v, ok := e.(domainerrors.ValidationError); ok {
-			return &v
+			list = domainerrors.ValidationErrors{v}
+			break
+		}
+		if v, ok := e.(domainerrors.ValidationErrors); ok {
+			list = v
+			break
 		}
 	}
-	return nil
+
+	fields := make([]FieldError, 0, len(list))
+	for _, v := range list {
+		code := v.Code
+		if code == "" {
+			code = FieldCodeInvalidValue
+		}
+		fields = append(fields, FieldError{Field: v.Field, Message: v.Message, Code: code})
+	}
+	return fields
 }
 
 // extractBusinessRuleViolation извлекает BusinessRuleViolation из цепочки ошибок.
@@ -2,6 +2,8 @@ package common
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -15,7 +17,7 @@ func setupTestContext() (*gin.Context, *httptest.ResponseRecorder) {
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	c.Set(RequestIDKey, "test-request-123")
+	SetRequestID(c, "test-request-123")
 	return c, w
 }
 
@@ -43,7 +45,7 @@ func TestSetRequestID(t *testing.T) {
 	SetRequestID(c, "new-id-456")
 
 	assert.Equal(t, "new-id-456", GetRequestID(c))
-	assert.Equal(t, "new-id-456", w.Header().Get(RequestIDKey))
+	assert.Equal(t, "new-id-456", w.Header().Get(RequestIDHeader))
 }
 
 // ============================================
@@ -245,6 +247,30 @@ func TestHandleDomainError(t *testing.T) {
 		HandleDomainError(c, err)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response APIResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, []FieldError{{Field: "email", Message: "invalid format", Code: FieldCodeInvalidValue}}, response.Error.Fields)
+	})
+
+	t.Run("ValidationErrors_AllFieldsReported", func(t *testing.T) {
+		c, w := setupTestContext()
+
+		var errs domainerrors.ValidationErrors
+		errs.AddCode("source_wallet_id", domainerrors.ValidationCodeInvalidFormat, "invalid source wallet ID format")
+		errs.AddCode("amount", domainerrors.ValidationCodeInvalidFormat, "invalid amount")
+
+		HandleDomainError(c, fmt.Errorf("transfer failed: %w", errs))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response APIResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, ErrCodeValidation, response.Error.Code)
+		assert.Equal(t, []FieldError{
+			{Field: "source_wallet_id", Message: "invalid source wallet ID format", Code: FieldCodeInvalidFormat},
+			{Field: "amount", Message: "invalid amount", Code: FieldCodeInvalidFormat},
+		}, response.Error.Fields)
 	})
 
 	t.Run("BusinessRuleViolation", func(t *testing.T) {
@@ -317,6 +343,69 @@ func TestHandleDomainError(t *testing.T) {
 		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 	})
 
+	t.Run("InsufficientBalanceSentinel", func(t *testing.T) {
+		c, w := setupTestContext()
+
+		err := fmt.Errorf("failed to debit wallet: %w", domainerrors.ErrInsufficientBalance)
+
+		HandleDomainError(c, err)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		var response APIResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "INSUFFICIENT_BALANCE", response.Error.Code)
+	})
+
+	t.Run("OperationDisabled", func(t *testing.T) {
+		c, w := setupTestContext()
+
+		err := fmt.Errorf("debit rejected: %w", &domainerrors.OperationDisabledError{Operation: "WITHDRAW", Reason: "payout provider incident"})
+
+		HandleDomainError(c, err)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		var response APIResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "OPERATION_TEMPORARILY_DISABLED", response.Error.Code)
+		assert.Contains(t, response.Error.Message, "payout provider incident")
+		assert.Equal(t, "WITHDRAW", response.Error.Details["operation"])
+		assert.Equal(t, "payout provider incident", response.Error.Details["reason"])
+	})
+
+	t.Run("DomainError_WalletBusy", func(t *testing.T) {
+		c, w := setupTestContext()
+
+		err := domainerrors.NewDomainError("WALLET_BUSY", "Wallet has too many operations in flight", nil)
+
+		HandleDomainError(c, err)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+		var response APIResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "WALLET_BUSY", response.Error.Code)
+	})
+
+	t.Run("DomainError_WalletNoteDeleteForbidden", func(t *testing.T) {
+		c, w := setupTestContext()
+
+		err := domainerrors.NewDomainError("WALLET_NOTE_DELETE_FORBIDDEN", "only the author or a superadmin can delete a note", nil)
+
+		HandleDomainError(c, err)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var response APIResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "WALLET_NOTE_DELETE_FORBIDDEN", response.Error.Code)
+	})
+
 	t.Run("GenericError", func(t *testing.T) {
 		c, w := setupTestContext()
 
@@ -332,11 +421,12 @@ func TestHandleDomainError(t *testing.T) {
 // Test Error Extractors
 // ============================================
 
-func TestExtractValidationError(t *testing.T) {
-	valErr := domainerrors.ValidationError{Field: "email", Message: "invalid"}
-	extracted := extractValidationError(valErr)
-	assert.NotNil(t, extracted)
-	assert.Equal(t, "email", extracted.Field)
+func TestValidationFieldErrors(t *testing.T) {
+	valErr := domainerrors.ValidationError{Field: "email", Message: "invalid", Code: FieldCodeInvalidFormat}
+	fields := ValidationFieldErrors(valErr)
+	assert.Equal(t, []FieldError{{Field: "email", Message: "invalid", Code: FieldCodeInvalidFormat}}, fields)
+
+	assert.Empty(t, ValidationFieldErrors(errors.New("plain")))
 }
 
 func TestExtractBusinessRuleViolation(t *testing.T) {
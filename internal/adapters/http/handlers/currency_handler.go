@@ -0,0 +1,105 @@
+// Package handlers - Currency metadata HTTP handler.
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Currency Handler
+// ============================================
+
+// CurrencyHandler отдаёт реестр валют с политикой кошельков и лимитами
+// сумм - всё, что нужно клиенту для ввода суммы, без захардкоженных
+// знаков после запятой.
+//
+// Ответ собирается из памяти на каждый запрос: смена политики
+// (ports.CurrencyPolicySource) сразу меняет и тело, и ETag.
+type CurrencyHandler struct {
+	policy ports.CurrencyPolicySource
+}
+
+// NewCurrencyHandler создаёт новый CurrencyHandler.
+func NewCurrencyHandler(policy ports.CurrencyPolicySource) *CurrencyHandler {
+	return &CurrencyHandler{policy: policy}
+}
+
+// ============================================
+// Response Types
+// ============================================
+
+// CurrencyResponse - валюта реестра.
+type CurrencyResponse struct {
+	Code           string `json:"code"`
+	Decimals       int    `json:"decimals"`
+	Type           string `json:"type"`                 // fiat или crypto
+	Symbol         string `json:"symbol"`               // код, если символа нет
+	WalletsEnabled bool   `json:"wallets_enabled"`      // можно ли сейчас открыть кошелёк
+	MinAmount      string `json:"min_amount,omitempty"` // минимум одной транзакции; пусто - без лимита
+	MaxAmount      string `json:"max_amount,omitempty"` // максимум одной транзакции; пусто - без лимита
+}
+
+// CurrencyListResponse - ответ GET /api/v1/meta/currencies.
+type CurrencyListResponse struct {
+	Currencies []CurrencyResponse `json:"currencies"`
+}
+
+// ============================================
+// HTTP Handlers
+// ============================================
+
+// Currencies возвращает реестр валют.
+//
+// @Summary Currency registry
+// @Description All supported currencies with decimals, type, display symbol, whether new wallets can be opened in them and per-transaction amount limits. Carries a strong ETag of the content: revalidate with If-None-Match
+// @Tags Meta
+// @Produce json
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} common.APIResponse{data=CurrencyListResponse}
+// @Header 200 {string} ETag "Content hash"
+// @Success 304 "Not Modified"
+// @Router /api/v1/meta/currencies [get]
+func (h *CurrencyHandler) Currencies(c *gin.Context) {
+	response := currencyList(h.policy.Current())
+	common.SuccessWithETag(c, currencyListETag(response), response)
+}
+
+// currencyList собирает ответ из реестра валют и политики.
+func currencyList(policy ports.CurrencyPolicy) CurrencyListResponse {
+	registry := valueobjects.SupportedCurrencies()
+	response := CurrencyListResponse{Currencies: make([]CurrencyResponse, 0, len(registry))}
+
+	for _, currency := range registry {
+		currencyType := "fiat"
+		if currency.IsCrypto() {
+			currencyType = "crypto"
+		}
+		limits := policy.Limits[currency.Code()]
+
+		response.Currencies = append(response.Currencies, CurrencyResponse{
+			Code:           currency.Code(),
+			Decimals:       currency.Decimals(),
+			Type:           currencyType,
+			Symbol:         currency.Symbol(),
+			WalletsEnabled: policy.AllowsWallet(currency),
+			MinAmount:      limits.Min,
+			MaxAmount:      limits.Max,
+		})
+	}
+
+	return response
+}
+
+// currencyListETag - strong ETag по хэшу содержимого ответа.
+func currencyListETag(response CurrencyListResponse) string {
+	body, _ := json.Marshal(response) // только строки, числа и bool
+	sum := sha256.Sum256(body)
+	return common.StrongETag(hex.EncodeToString(sum[:16]))
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// reloadablePolicy - источник политики, который меняется как при
+// перечитывании конфигурации.
+type reloadablePolicy struct {
+	mu     sync.Mutex
+	policy ports.CurrencyPolicy
+}
+
+func (r *reloadablePolicy) Current() ports.CurrencyPolicy {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.policy
+}
+
+func (r *reloadablePolicy) set(policy ports.CurrencyPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policy = policy
+}
+
+func TestCurrencyHandler_Currencies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	source := &reloadablePolicy{policy: ports.CurrencyPolicy{
+		WalletCurrencies: []string{"USD", "BTC"},
+		Limits:           map[string]ports.AmountLimits{"BTC": {Min: "0.0001", Max: "2"}},
+	}}
+	router := gin.New()
+	router.GET("/meta/currencies", NewCurrencyHandler(source).Currencies)
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/meta/currencies", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("")
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.NotContains(t, etag, "W/", "strong ETag")
+
+	var envelope struct {
+		Data CurrencyListResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+
+	t.Run("content matches the registry", func(t *testing.T) {
+		registry := valueobjects.SupportedCurrencies()
+		require.Len(t, envelope.Data.Currencies, len(registry))
+		for i, currency := range registry {
+			got := envelope.Data.Currencies[i]
+			assert.Equal(t, currency.Code(), got.Code)
+			assert.Equal(t, currency.Decimals(), got.Decimals)
+			assert.Equal(t, currency.Symbol(), got.Symbol)
+			assert.Equal(t, currency.IsCrypto(), got.Type == "crypto", currency.Code())
+		}
+	})
+
+	t.Run("wallets flag and limits follow the policy", func(t *testing.T) {
+		byCode := make(map[string]CurrencyResponse)
+		for _, currency := range envelope.Data.Currencies {
+			byCode[currency.Code] = currency
+		}
+		assert.True(t, byCode["USD"].WalletsEnabled)
+		assert.True(t, byCode["BTC"].WalletsEnabled)
+		assert.False(t, byCode["EUR"].WalletsEnabled)
+		assert.Equal(t, "0.0001", byCode["BTC"].MinAmount)
+		assert.Equal(t, "2", byCode["BTC"].MaxAmount)
+		assert.Empty(t, byCode["USD"].MaxAmount)
+	})
+
+	t.Run("revalidation and config change", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotModified, get(etag).Code)
+
+		source.set(ports.CurrencyPolicy{WalletCurrencies: []string{"USD", "BTC", "EUR"}})
+
+		w := get(etag)
+		require.Equal(t, http.StatusOK, w.Code, "changed policy must not revalidate")
+		assert.NotEqual(t, etag, w.Header().Get("ETag"))
+		assert.Contains(t, w.Body.String(), `"code":"EUR","decimals":2,"type":"fiat","symbol":"€","wallets_enabled":true`)
+	})
+}
@@ -10,11 +10,14 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/Haleralex/wallethub/internal/adapters/http/middleware"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -25,41 +28,61 @@ import (
 
 // HealthHandler обрабатывает health check запросы.
 type HealthHandler struct {
-	pool      *pgxpool.Pool
-	version   string
-	buildTime string
-	startTime time.Time
+	pool       *pgxpool.Pool
+	version    string
+	buildTime  string
+	startTime  time.Time
+	operations ports.OperationGate // nil - без аварийных выключателей
+	workers    ports.WorkerMonitor // nil - heartbeat не проверяются
 }
 
 // NewHealthHandler создаёт новый HealthHandler.
-func NewHealthHandler(pool *pgxpool.Pool, version, buildTime string) *HealthHandler {
+func NewHealthHandler(pool *pgxpool.Pool, version, buildTime string, operations ports.OperationGate) *HealthHandler {
 	return &HealthHandler{
-		pool:      pool,
-		version:   version,
-		buildTime: buildTime,
-		startTime: time.Now(),
+		pool:       pool,
+		version:    version,
+		buildTime:  buildTime,
+		startTime:  time.Now(),
+		operations: operations,
 	}
 }
 
+// WithWorkers включает проверку heartbeat фоновых компонентов в /ready.
+func (h *HealthHandler) WithWorkers(workers ports.WorkerMonitor) *HealthHandler {
+	h.workers = workers
+	return h
+}
+
 // ============================================
 // Response Types
 // ============================================
 
 // HealthResponse - ответ health check.
 type HealthResponse struct {
-	Status    string            `json:"status"`           // "healthy", "unhealthy", "degraded"
-	Version   string            `json:"version"`          // Версия приложения
-	BuildTime string            `json:"build_time"`       // Время сборки
-	Uptime    string            `json:"uptime"`           // Время работы
-	Timestamp time.Time         `json:"timestamp"`        // Текущее время
-	Checks    map[string]string `json:"checks,omitempty"` // Детали проверок
+	Status             string                    `json:"status"`                        // "healthy", "unhealthy", "degraded"
+	Version            string                    `json:"version"`                       // Версия приложения
+	BuildTime          string                    `json:"build_time"`                    // Время сборки
+	Uptime             string                    `json:"uptime"`                        // Время работы
+	Timestamp          time.Time                 `json:"timestamp"`                     // Текущее время
+	Checks             map[string]string         `json:"checks,omitempty"`              // Детали проверок
+	DisabledOperations []dtos.OperationSwitchDTO `json:"disabled_operations,omitempty"` // Выключенные виды операций
 }
 
 // ReadinessResponse - ответ readiness check.
+//
+// Выключенные операции и устаревшие heartbeat фоновых компонентов не делают
+// экземпляр неготовым: запросы продолжают обслуживаться, списки нужны дашбордам.
 type ReadinessResponse struct {
-	Ready     bool              `json:"ready"`
-	Checks    map[string]string `json:"checks"`
-	Timestamp time.Time         `json:"timestamp"`
+	Ready              bool                      `json:"ready"`
+	Checks             map[string]string         `json:"checks"`
+	Warnings           []string                  `json:"warnings,omitempty"`
+	DisabledOperations []dtos.OperationSwitchDTO `json:"disabled_operations,omitempty"`
+	Timestamp          time.Time                 `json:"timestamp"`
+}
+
+// LiveResponse - ответ liveness probe.
+type LiveResponse struct {
+	Status string `json:"status"`
 }
 
 // ============================================
@@ -78,11 +101,12 @@ func (h *HealthHandler) Health(c *gin.Context) {
 	uptime := time.Since(h.startTime).Round(time.Second).String()
 
 	c.JSON(http.StatusOK, HealthResponse{
-		Status:    "healthy",
-		Version:   h.version,
-		BuildTime: h.buildTime,
-		Uptime:    uptime,
-		Timestamp: time.Now().UTC(),
+		Status:             "healthy",
+		Version:            h.version,
+		BuildTime:          h.buildTime,
+		Uptime:             uptime,
+		Timestamp:          time.Now().UTC(),
+		DisabledOperations: h.disabledOperations(c.Request.Context()),
 	})
 }
 
@@ -119,30 +143,55 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 	// - Message Queue
 	// - External APIs
 
+	// Фоновые компоненты - только предупреждения
+	var warnings []string
+	if h.workers != nil {
+		warnings = h.staleWorkers()
+		if len(warnings) == 0 {
+			checks["workers"] = "healthy"
+		} else {
+			checks["workers"] = "stale"
+		}
+	}
+
 	statusCode := http.StatusOK
 	if !allReady {
 		statusCode = http.StatusServiceUnavailable
 	}
 
 	c.JSON(statusCode, ReadinessResponse{
-		Ready:     allReady,
-		Checks:    checks,
-		Timestamp: time.Now().UTC(),
+		Ready:              allReady,
+		Checks:             checks,
+		Warnings:           warnings,
+		DisabledOperations: h.disabledOperations(c.Request.Context()),
+		Timestamp:          time.Now().UTC(),
 	})
 }
 
+// staleWorkers - предупреждения об устаревших heartbeat компонентов этого экземпляра.
+func (h *HealthHandler) staleWorkers() []string {
+	now := time.Now().UTC()
+
+	var warnings []string
+	for _, w := range h.workers.Local() {
+		if w.Stale(now) {
+			warnings = append(warnings, fmt.Sprintf("worker %s: no heartbeat for %s (threshold %s)",
+				w.Worker, now.Sub(w.LastHeartbeatAt).Round(time.Second), w.StaleAfter))
+		}
+	}
+	return warnings
+}
+
 // Live возвращает статус "живости" приложения.
 //
 // @Summary Liveness check
 // @Description Simple liveness probe
 // @Tags Health
 // @Produce json
-// @Success 200 {object} map[string]string
+// @Success 200 {object} LiveResponse
 // @Router /live [get]
 func (h *HealthHandler) Live(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status": "alive",
-	})
+	c.JSON(http.StatusOK, LiveResponse{Status: "alive"})
 }
 
 // DetailedHealth возвращает детальную информацию о состоянии.
@@ -187,15 +236,29 @@ func (h *HealthHandler) DetailedHealth(c *gin.Context) {
 	uptime := time.Since(h.startTime).Round(time.Second).String()
 
 	c.JSON(http.StatusOK, HealthResponse{
-		Status:    status,
-		Version:   h.version,
-		BuildTime: h.buildTime,
-		Uptime:    uptime,
-		Timestamp: time.Now().UTC(),
-		Checks:    checks,
+		Status:             status,
+		Version:            h.version,
+		BuildTime:          h.buildTime,
+		Uptime:             uptime,
+		Timestamp:          time.Now().UTC(),
+		Checks:             checks,
+		DisabledOperations: h.disabledOperations(c.Request.Context()),
 	})
 }
 
+// disabledOperations - выключенные сейчас виды операций (nil, если их нет).
+func (h *HealthHandler) disabledOperations(ctx context.Context) []dtos.OperationSwitchDTO {
+	if h.operations == nil {
+		return nil
+	}
+
+	var disabled []dtos.OperationSwitchDTO
+	for _, sw := range h.operations.Disabled(ctx) {
+		disabled = append(disabled, dtos.ToOperationSwitchDTO(sw))
+	}
+	return disabled
+}
+
 // RegisterRoutes регистрирует health check маршруты.
 //
 // Routes:
@@ -10,9 +10,13 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
 )
 
 // ============================================
@@ -23,7 +27,7 @@ func setupHealthTestRouter() (*gin.Engine, *HealthHandler) {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 
-	handler := NewHealthHandler(nil, "1.0.0", "2024-01-01T00:00:00Z")
+	handler := NewHealthHandler(nil, "1.0.0", "2024-01-01T00:00:00Z", nil)
 	return router, handler
 }
 
@@ -38,7 +42,7 @@ func TestNewHealthHandler(t *testing.T) {
 		buildTime := "2024-01-15T10:30:00Z"
 
 		// Act
-		handler := NewHealthHandler(nil, version, buildTime)
+		handler := NewHealthHandler(nil, version, buildTime, nil)
 
 		// Assert
 		assert.NotNil(t, handler)
@@ -52,7 +56,7 @@ func TestNewHealthHandler(t *testing.T) {
 		var pool *pgxpool.Pool // В реальных тестах это был бы mock
 
 		// Act
-		handler := NewHealthHandler(pool, "1.0.0", "2024-01-01")
+		handler := NewHealthHandler(pool, "1.0.0", "2024-01-01", nil)
 
 		// Assert
 		assert.NotNil(t, handler)
@@ -169,6 +173,52 @@ func TestHealthHandler_Ready_WithoutPool(t *testing.T) {
 	})
 }
 
+// stubWorkerMonitor - heartbeat фоновых компонентов для /ready.
+type stubWorkerMonitor struct {
+	local []ports.WorkerStatus
+}
+
+func (m *stubWorkerMonitor) Instance() string            { return "api-1" }
+func (m *stubWorkerMonitor) Local() []ports.WorkerStatus { return m.local }
+func (m *stubWorkerMonitor) Workers(context.Context) ([]ports.WorkerStatus, error) {
+	return m.local, nil
+}
+
+func TestHealthHandler_Ready_StaleWorkers(t *testing.T) {
+	now := time.Now().UTC()
+	monitor := &stubWorkerMonitor{local: []ports.WorkerStatus{
+		{Worker: "balance-summary", State: ports.WorkerIdle, LastHeartbeatAt: now, StaleAfter: time.Minute},
+		{Worker: "wallet-balance-compare", State: ports.WorkerRunning, LastHeartbeatAt: now.Add(-time.Hour), StaleAfter: 30 * time.Minute},
+		{Worker: "outbox-cleanup", State: ports.WorkerStopped, LastHeartbeatAt: now.Add(-time.Hour), StaleAfter: time.Minute},
+	}}
+
+	router, handler := setupHealthTestRouter()
+	handler.WithWorkers(monitor)
+	router.GET("/ready", handler.Ready)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+	// Устаревший heartbeat - предупреждение, экземпляр остаётся готовым
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response ReadinessResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Ready)
+	assert.Equal(t, "stale", response.Checks["workers"])
+	require.Len(t, response.Warnings, 1)
+	assert.Contains(t, response.Warnings[0], "wallet-balance-compare")
+
+	monitor.local = monitor.local[:1]
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+	response = ReadinessResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "healthy", response.Checks["workers"])
+	assert.Empty(t, response.Warnings)
+}
+
 // ============================================
 // Test Ready Endpoint (With Mock Pool)
 // ============================================
@@ -279,7 +329,7 @@ func TestHealthHandler_RegisterRoutes(t *testing.T) {
 		// Arrange
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
-		handler := NewHealthHandler(nil, "1.0.0", "2024-01-01")
+		handler := NewHealthHandler(nil, "1.0.0", "2024-01-01", nil)
 
 		// Act
 		handler.RegisterRoutes(router)
@@ -302,7 +352,7 @@ func TestHealthHandler_RegisterRoutes(t *testing.T) {
 		// Arrange
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
-		handler := NewHealthHandler(nil, "1.0.0", "2024-01-01")
+		handler := NewHealthHandler(nil, "1.0.0", "2024-01-01", nil)
 		handler.RegisterRoutes(router)
 
 		testCases := []struct {
@@ -372,7 +422,7 @@ func TestHealthHandler_EdgeCases(t *testing.T) {
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
 
-		handler := NewHealthHandler(nil, "1.0.0", "2024-01-01")
+		handler := NewHealthHandler(nil, "1.0.0", "2024-01-01", nil)
 		router.GET("/ready", handler.Ready)
 
 		// Create request with already cancelled context
@@ -393,7 +443,7 @@ func TestHealthHandler_EdgeCases(t *testing.T) {
 	t.Run("EmptyVersion_StillWorks", func(t *testing.T) {
 		// Arrange
 		router := gin.New()
-		handler := NewHealthHandler(nil, "", "")
+		handler := NewHealthHandler(nil, "", "", nil)
 		router.GET("/health", handler.Health)
 
 		req := httptest.NewRequest(http.MethodGet, "/health", nil)
@@ -427,7 +477,7 @@ func TestHealthHandler_PoolPingFailures(t *testing.T) {
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
-		handler := NewHealthHandler(nil, "1.0.0", "2024-01-01")
+		handler := NewHealthHandler(nil, "1.0.0", "2024-01-01", nil)
 		router.GET("/health/detailed", handler.DetailedHealth)
 
 		req := httptest.NewRequest(http.MethodGet, "/health/detailed", nil)
@@ -442,6 +492,61 @@ func TestHealthHandler_PoolPingFailures(t *testing.T) {
 	})
 }
 
+// ============================================
+// Test Disabled Operations
+// ============================================
+
+// stubOperationGate - OperationGate с заданным списком выключенных операций.
+type stubOperationGate struct {
+	disabled []*entities.OperationSwitch
+}
+
+func (g stubOperationGate) RequireEnabled(context.Context, entities.TransactionType) error {
+	return nil
+}
+
+func (g stubOperationGate) Disabled(context.Context) []*entities.OperationSwitch {
+	return g.disabled
+}
+
+func (g stubOperationGate) Invalidate() {}
+
+func TestHealthHandler_DisabledOperations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	withdraw, err := entities.NewOperationSwitch(entities.TransactionTypeWithdraw, false, "payout provider incident", uuid.New())
+	require.NoError(t, err)
+
+	router := gin.New()
+	NewHealthHandler(nil, "1.0.0", "2024-01-01", stubOperationGate{disabled: []*entities.OperationSwitch{withdraw}}).RegisterRoutes(router)
+
+	for _, path := range []string{"/health", "/health/detailed", "/ready"} {
+		t.Run(path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+			// Выключенная операция не делает экземпляр неготовым
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response struct {
+				DisabledOperations []map[string]interface{} `json:"disabled_operations"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			require.Len(t, response.DisabledOperations, 1)
+			assert.Equal(t, "WITHDRAW", response.DisabledOperations[0]["type"])
+			assert.Equal(t, "payout provider incident", response.DisabledOperations[0]["reason"])
+		})
+	}
+
+	t.Run("NoneDisabled", func(t *testing.T) {
+		router := gin.New()
+		NewHealthHandler(nil, "1.0.0", "2024-01-01", stubOperationGate{}).RegisterRoutes(router)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+		assert.NotContains(t, w.Body.String(), "disabled_operations")
+	})
+}
+
 // ============================================
 // Benchmark Tests
 // ============================================
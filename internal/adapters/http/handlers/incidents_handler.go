@@ -0,0 +1,123 @@
+// Package handlers - Status page incidents admin HTTP handlers.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Incidents Handler
+// ============================================
+
+// IncidentsHandler обрабатывает admin запросы отметок инцидентов страницы статуса.
+// Роль admin/superadmin проверяется группой /admin в роутере.
+type IncidentsHandler struct {
+	commandBus *cqrs.CommandBus
+}
+
+// NewIncidentsHandler создаёт новый IncidentsHandler.
+func NewIncidentsHandler(commandBus *cqrs.CommandBus) *IncidentsHandler {
+	return &IncidentsHandler{commandBus: commandBus}
+}
+
+// ============================================
+// Request DTOs
+// ============================================
+
+// IncidentIDParam - параметр ID инцидента из URL.
+type IncidentIDParam struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// CreateIncidentRequest - новый инцидент страницы статуса.
+//
+// @Description Create status page incident request body
+type CreateIncidentRequest struct {
+	Title      string   `json:"title" binding:"required,max=200"`
+	Severity   string   `json:"severity" binding:"required,oneof=MINOR MAJOR CRITICAL"`
+	Components []string `json:"components" binding:"required,min=1,dive,oneof=api database payouts deposits"`
+	StartedAt  string   `json:"started_at"` // RFC3339 с зоной; пусто - сейчас
+}
+
+// ============================================
+// HTTP Handlers
+// ============================================
+
+// CreateIncident открывает инцидент на публичной странице статуса.
+//
+// @Summary Create status page incident
+// @Description Open an incident marker listed on the public GET /status page until resolved (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body CreateIncidentRequest true "Incident"
+// @Success 201 {object} common.APIResponse{data=dtos.IncidentDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Router /api/v1/admin/incidents [post]
+func (h *IncidentsHandler) CreateIncident(c *gin.Context) {
+	var req CreateIncidentRequest
+	if !BindJSON(c, &req) {
+		return
+	}
+
+	cmd := dtos.CreateIncidentCommand{
+		Title:      req.Title,
+		Severity:   req.Severity,
+		Components: req.Components,
+	}
+	if req.StartedAt != "" {
+		startedAt, err := ParseTimestamp(req.StartedAt)
+		if err != nil {
+			common.ValidationErrorResponse(c, []common.FieldError{
+				{Field: "started_at", Message: err.Error(), Code: common.FieldCodeInvalidFormat},
+			})
+			return
+		}
+		cmd.StartedAt = &startedAt
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.CreateIncidentCommand, *dtos.IncidentDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusCreated, result)
+}
+
+// ResolveIncident отмечает инцидент решённым.
+//
+// @Summary Resolve status page incident
+// @Description Mark an incident as resolved; it stays listed on GET /status for 24 hours (admin only)
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Incident ID" format(uuid)
+// @Success 200 {object} common.APIResponse{data=dtos.IncidentDTO}
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Failure 422 {object} common.APIResponse
+// @Router /api/v1/admin/incidents/{id}/resolve [post]
+func (h *IncidentsHandler) ResolveIncident(c *gin.Context) {
+	var params IncidentIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	cmd := dtos.ResolveIncidentCommand{IncidentID: params.ID}
+
+	result, err := cqrs.DispatchCommand[dtos.ResolveIncidentCommand, *dtos.IncidentDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
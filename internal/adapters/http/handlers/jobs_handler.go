@@ -0,0 +1,92 @@
+// Package handlers - Background jobs admin HTTP handlers.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Jobs Handler
+// ============================================
+
+// JobsHandler обрабатывает admin запросы фоновых задач.
+// Роль admin проверяется группой /admin в роутере.
+type JobsHandler struct {
+	commandBus *cqrs.CommandBus
+	queryBus   *cqrs.QueryBus
+}
+
+// NewJobsHandler создаёт новый JobsHandler.
+func NewJobsHandler(commandBus *cqrs.CommandBus, queryBus *cqrs.QueryBus) *JobsHandler {
+	return &JobsHandler{
+		commandBus: commandBus,
+		queryBus:   queryBus,
+	}
+}
+
+// ============================================
+// Request DTOs
+// ============================================
+
+// JobNameParam - имя задачи в URI.
+type JobNameParam struct {
+	Name string `uri:"name" binding:"required,max=100"`
+}
+
+// ============================================
+// HTTP Handlers
+// ============================================
+
+// ListJobs возвращает фоновые задачи, их расписание и последние прогоны.
+//
+// @Summary List background jobs
+// @Description Registered background jobs with schedule, next run and recent runs (admin only)
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} common.APIResponse{data=dtos.JobListDTO}
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Router /api/v1/admin/jobs [get]
+func (h *JobsHandler) ListJobs(c *gin.Context) {
+	result, err := cqrs.DispatchQuery[dtos.ListJobsQuery, *dtos.JobListDTO](h.queryBus, c.Request.Context(), dtos.ListJobsQuery{})
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// RunJob запускает фоновую задачу вне расписания.
+// Ответ возвращается сразу после начала прогона; итог - в ListJobs.
+//
+// @Summary Run background job now
+// @Description Start a background job outside its schedule; returns the started run (admin only)
+// @Tags Admin
+// @Produce json
+// @Param name path string true "Job name"
+// @Success 202 {object} common.APIResponse{data=dtos.JobRunDTO}
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Failure 422 {object} common.APIResponse
+// @Router /api/v1/admin/jobs/{name}/run [post]
+func (h *JobsHandler) RunJob(c *gin.Context) {
+	var params JobNameParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.RunJobCommand, *dtos.JobRunDTO](h.commandBus, c.Request.Context(), dtos.RunJobCommand{Name: params.Name})
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusAccepted, result)
+}
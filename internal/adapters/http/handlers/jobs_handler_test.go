@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockListJobsUseCase struct {
+	ExecuteFn func(ctx context.Context, query dtos.ListJobsQuery) (*dtos.JobListDTO, error)
+}
+
+func (m *mockListJobsUseCase) Execute(ctx context.Context, query dtos.ListJobsQuery) (*dtos.JobListDTO, error) {
+	return m.ExecuteFn(ctx, query)
+}
+
+type mockRunJobUseCase struct {
+	ExecuteFn func(ctx context.Context, cmd dtos.RunJobCommand) (*dtos.JobRunDTO, error)
+}
+
+func (m *mockRunJobUseCase) Execute(ctx context.Context, cmd dtos.RunJobCommand) (*dtos.JobRunDTO, error) {
+	return m.ExecuteFn(ctx, cmd)
+}
+
+func setupJobsTestRouter(list *mockListJobsUseCase, run *mockRunJobUseCase) *gin.Engine {
+	cmdBus := cqrs.NewCommandBus()
+	qBus := cqrs.NewQueryBus()
+	cqrs.RegisterQueryHandler[dtos.ListJobsQuery, *dtos.JobListDTO](qBus, list)
+	cqrs.RegisterCommandHandler[dtos.RunJobCommand, *dtos.JobRunDTO](cmdBus, run)
+
+	handler := NewJobsHandler(cmdBus, qBus)
+	router := gin.New()
+	router.GET("/api/v1/admin/jobs", handler.ListJobs)
+	router.POST("/api/v1/admin/jobs/:name/run", handler.RunJob)
+	return router
+}
+
+func TestJobsHandler_ListJobs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	next := time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC)
+	router := setupJobsTestRouter(&mockListJobsUseCase{
+		ExecuteFn: func(context.Context, dtos.ListJobsQuery) (*dtos.JobListDTO, error) {
+			return &dtos.JobListDTO{
+				Enabled: true,
+				Jobs: []dtos.JobDTO{{
+					Name:           "outbox-cleanup",
+					Schedule:       "0 3 * * *",
+					TimeoutSeconds: 300,
+					NextRunAt:      &next,
+					RecentRuns:     []dtos.JobRunDTO{},
+				}},
+				TotalCount: 1,
+			}, nil
+		},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/jobs", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data dtos.JobListDTO `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Data.Jobs, 1)
+	assert.Equal(t, "0 3 * * *", body.Data.Jobs[0].Schedule)
+	assert.Equal(t, next, *body.Data.Jobs[0].NextRunAt)
+}
+
+func TestJobsHandler_RunJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Accepted", func(t *testing.T) {
+		var got dtos.RunJobCommand
+		router := setupJobsTestRouter(nil, &mockRunJobUseCase{
+			ExecuteFn: func(_ context.Context, cmd dtos.RunJobCommand) (*dtos.JobRunDTO, error) {
+				got = cmd
+				return &dtos.JobRunDTO{Job: cmd.Name, Trigger: "MANUAL", Status: "RUNNING"}, nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/jobs/outbox-cleanup/run", nil))
+
+		require.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "outbox-cleanup", got.Name)
+		assert.Contains(t, w.Body.String(), `"status":"RUNNING"`)
+	})
+
+	t.Run("AlreadyRunning", func(t *testing.T) {
+		router := setupJobsTestRouter(nil, &mockRunJobUseCase{
+			ExecuteFn: func(_ context.Context, cmd dtos.RunJobCommand) (*dtos.JobRunDTO, error) {
+				return nil, domainErrors.NewBusinessRuleViolation("JOB_RUNNING", "job is already running",
+					map[string]interface{}{"job": cmd.Name})
+			},
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/jobs/outbox-cleanup/run", nil))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "JOB_RUNNING")
+	})
+
+	t.Run("UnknownJob", func(t *testing.T) {
+		router := setupJobsTestRouter(nil, &mockRunJobUseCase{
+			ExecuteFn: func(_ context.Context, cmd dtos.RunJobCommand) (*dtos.JobRunDTO, error) {
+				return nil, fmt.Errorf("%w: job %s", domainErrors.ErrEntityNotFound, cmd.Name)
+			},
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/jobs/nope/run", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
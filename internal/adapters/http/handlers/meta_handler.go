@@ -0,0 +1,162 @@
+// Package handlers - Meta handlers: машиночитаемое описание API.
+package handlers
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/adapters/http/openapi"
+	"github.com/Haleralex/wallethub/internal/adapters/http/routes"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/pkg/statemachine"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Meta Handler
+// ============================================
+
+// MetaHandler отдаёт route manifest, OpenAPI спецификацию и таблицы
+// переходов статусов для генераторов SDK.
+type MetaHandler struct {
+	registry *routes.Registry
+	version  string
+
+	// Спецификация строится при первом запросе: к этому моменту все
+	// маршруты уже зарегистрированы
+	specOnce sync.Once
+	spec     *openapi.Document
+	specErr  error
+}
+
+// NewMetaHandler создаёт новый MetaHandler.
+func NewMetaHandler(registry *routes.Registry, version string) *MetaHandler {
+	return &MetaHandler{registry: registry, version: version}
+}
+
+// ============================================
+// Response Types
+// ============================================
+
+// RouteManifestResponse - список маршрутов API.
+type RouteManifestResponse struct {
+	Version    string         `json:"version"`
+	SchemaBase string         `json:"schema_base"` // Откуда брать схемы из request_schema/response_schema
+	Routes     []routes.Route `json:"routes"`
+}
+
+// StateMachinesResponse - таблицы переходов статусов сущностей.
+type StateMachinesResponse struct {
+	Machines []StateMachineDTO `json:"machines"`
+}
+
+// StateMachineDTO - таблица переходов одной сущности.
+type StateMachineDTO struct {
+	Name        string          `json:"name"`
+	States      []string        `json:"states"`
+	Final       []string        `json:"final"` // Статусы без исходящих переходов
+	Transitions []TransitionDTO `json:"transitions"`
+}
+
+// TransitionDTO - разрешённый переход статуса.
+type TransitionDTO struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// routeManifestSchemaBase - схемы маршрутов ссылаются на этот документ.
+const routeManifestSchemaBase = "api/openapi.yaml"
+
+// OpenAPIInfo - описание API в генерируемой спецификации. Version - версия
+// контракта API, а не сборки: закоммиченный api/openapi.json не зависит
+// от того, какой бинарник его записал.
+var OpenAPIInfo = openapi.Info{
+	Title:       "WalletHub API",
+	Description: "Generated from the registered routes and the Go types of request and response bodies.",
+	Version:     "1.0.0",
+}
+
+// GenerateOpenAPI строит OpenAPI 3.1 спецификацию по маршрутам registry.
+func GenerateOpenAPI(registry *routes.Registry) (*openapi.Document, error) {
+	return openapi.Generate(OpenAPIInfo, registry.Routes(), OpenAPICatalog())
+}
+
+// ============================================
+// HTTP Handlers
+// ============================================
+
+// Routes возвращает manifest всех зарегистрированных маршрутов.
+//
+// @Summary Route manifest
+// @Description Machine-readable list of registered routes: method, path template, auth scope, request/response schema references, idempotency and rate-limit class
+// @Tags Meta
+// @Produce json
+// @Success 200 {object} common.APIResponse{data=RouteManifestResponse}
+// @Router /api/v1/meta/routes [get]
+func (h *MetaHandler) Routes(c *gin.Context) {
+	common.Success(c, http.StatusOK, RouteManifestResponse{
+		Version:    h.version,
+		SchemaBase: routeManifestSchemaBase,
+		Routes:     h.registry.Routes(),
+	})
+}
+
+// OpenAPI возвращает OpenAPI 3.1 спецификацию зарегистрированных маршрутов
+// (без конверта APIResponse - документ читают генераторы клиентов).
+//
+// @Summary OpenAPI specification
+// @Description OpenAPI 3.1 document generated from the route metadata and the Go types of request and response bodies
+// @Tags Meta
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/meta/openapi.json [get]
+func (h *MetaHandler) OpenAPI(c *gin.Context) {
+	h.specOnce.Do(func() {
+		h.spec, h.specErr = GenerateOpenAPI(h.registry)
+	})
+	if h.specErr != nil {
+		common.InternalErrorResponse(c, "Failed to generate OpenAPI specification")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.spec)
+}
+
+// StateMachines возвращает таблицы переходов статусов транзакций и кошельков -
+// те же, по которым сущности проверяют каждую смену статуса.
+//
+// @Summary State machines
+// @Description Allowed status transitions of transactions and wallets, rendered from the tables the domain enforces
+// @Tags Meta
+// @Produce json
+// @Success 200 {object} common.APIResponse{data=StateMachinesResponse}
+// @Router /api/v1/meta/state-machines [get]
+func (h *MetaHandler) StateMachines(c *gin.Context) {
+	common.Success(c, http.StatusOK, StateMachinesResponse{
+		Machines: []StateMachineDTO{
+			toStateMachineDTO("transaction", entities.TransactionTransitions),
+			toStateMachineDTO("wallet", entities.WalletTransitions),
+		},
+	})
+}
+
+// toStateMachineDTO раскладывает таблицу переходов в DTO.
+func toStateMachineDTO[S ~string](name string, table statemachine.Table[S]) StateMachineDTO {
+	dto := StateMachineDTO{
+		Name:        name,
+		States:      []string{},
+		Final:       []string{},
+		Transitions: []TransitionDTO{},
+	}
+	for _, state := range table.States() {
+		dto.States = append(dto.States, string(state))
+		if table.IsFinal(state) {
+			dto.Final = append(dto.Final, string(state))
+		}
+	}
+	for _, transition := range table.Transitions() {
+		dto.Transitions = append(dto.Transitions, TransitionDTO{From: string(transition.From), To: string(transition.To)})
+	}
+	return dto
+}
@@ -0,0 +1,113 @@
+// Package handlers - Go типы схем, на которые ссылаются маршруты.
+package handlers
+
+import (
+	"github.com/Haleralex/wallethub/internal/adapters/http/openapi"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+)
+
+// OpenAPICatalog связывает имена routes.SchemaRef с типами, которые
+// handlers разбирают (запросы) и отдают (data ответа).
+//
+// Новая ссылка в роутере без записи здесь - ошибка генерации спецификации.
+func OpenAPICatalog() openapi.Catalog {
+	return openapi.Catalog{
+		Schemas: map[string]openapi.Body{
+			// Health
+			"HealthResponse":    openapi.Raw(HealthResponse{}),
+			"ReadinessResponse": openapi.Raw(ReadinessResponse{}),
+			"LiveResponse":      openapi.Raw(LiveResponse{}),
+
+			// Status page
+			"StatusPageResponse": openapi.Raw(StatusPageResponse{}),
+
+			// Auth
+			"TelegramAuthRequest":  openapi.Raw(TelegramAuthRequest{}),
+			"TelegramAuthResponse": openapi.Envelope(TelegramAuthResponse{}),
+			"LogoutResponse":       openapi.Envelope(LogoutResponse{}),
+
+			// Users
+			"CreateUserRequest":   openapi.Raw(CreateUserRequest{}),
+			"UpdateUserRequest":   openapi.Raw(UpdateUserRequest{}),
+			"ApproveKYCRequest":   openapi.Raw(ReviewKYCRequest{}),
+			"RevokeKYCRequest":    openapi.Raw(RevokeKYCRequest{}),
+			"AcceptTermsRequest":  openapi.Raw(AcceptTermsRequest{}),
+			"UserCreatedResponse": openapi.Envelope(dtos.UserCreatedDTO{}),
+			"UserResponse":        openapi.Envelope(dtos.UserDTO{}),
+			"AdminUserResponse":   openapi.Envelope(dtos.AdminUserDTO{}),
+			"KYCHistoryResponse":  openapi.Envelope(dtos.KYCHistoryDTO{}),
+			"OnboardUserRequest":  openapi.Raw(OnboardUserRequest{}),
+			"OnboardingResponse":  openapi.Envelope(dtos.OnboardingResultDTO{}),
+
+			// Wallets
+			"CreateWalletRequest":         openapi.Raw(CreateWalletRequest{}),
+			"EnsureWalletRequest":         openapi.Raw(EnsureWalletRequest{}),
+			"CreditWalletRequest":         openapi.Raw(CreditWalletRequest{}),
+			"DebitWalletRequest":          openapi.Raw(DebitWalletRequest{}),
+			"TransferFundsRequest":        openapi.Raw(TransferFundsRequest{}),
+			"BulkTransferRequest":         openapi.Raw(BulkTransferRequest{}),
+			"ExchangeCurrencyRequest":     openapi.Raw(ExchangeCurrencyRequest{}),
+			"UpdateWalletSettingsRequest": openapi.Raw(UpdateWalletSettingsRequest{}),
+			"WalletResponse":              openapi.Envelope(dtos.WalletDTO{}),
+			"WalletListResponse":          openapi.Page(dtos.WalletDTO{}),
+			"WalletBalanceResponse":       openapi.Envelope(dtos.WalletBalanceDTO{}),
+			"EnsureWalletResponse":        openapi.Envelope(dtos.EnsureWalletResultDTO{}),
+			"WalletOperationResponse":     openapi.Envelope(dtos.WalletOperationDTO{}),
+			"TransferResultResponse":      openapi.Envelope(dtos.TransferResultDTO{}),
+			"BulkTransferResponse":        openapi.Envelope(dtos.BulkTransferResultDTO{}),
+			"ExchangeResponse":            openapi.Envelope(dtos.ExchangeResultDTO{}),
+			"CloseWalletResponse":         openapi.Envelope(dtos.CloseWalletResultDTO{}),
+			"WalletSettingsResponse":      openapi.Envelope(dtos.WalletSettingsDTO{}),
+			"ActivityHeatmapResponse":     openapi.Envelope(dtos.ActivityHeatmapDTO{}),
+
+			// Transactions
+			"CancelTransactionRequest": openapi.Raw(CancelTransactionRequest{}),
+			"TransactionResponse":      openapi.Envelope(dtos.TransactionDTO{}),
+			"TransactionListResponse":  openapi.Envelope(dtos.TransactionListDTO{}),
+			"ReceiptResponse":          openapi.Envelope(dtos.ReceiptDTO{}),
+
+			// Webhooks
+			"CreateWebhookRequest":               openapi.Raw(CreateWebhookRequest{}),
+			"UpdateWebhookRequest":               openapi.Raw(UpdateWebhookRequest{}),
+			"WebhookSubscriptionCreatedResponse": openapi.Envelope(dtos.WebhookSubscriptionCreatedDTO{}),
+			"WebhookSubscriptionResponse":        openapi.Envelope(dtos.WebhookSubscriptionDTO{}),
+			"WebhookSubscriptionListResponse":    openapi.Envelope(dtos.WebhookSubscriptionListDTO{}),
+			"WebhookDeliveryListResponse":        openapi.Page(dtos.WebhookDeliveryDTO{}),
+
+			// Read sessions
+			"ReadSessionResponse": openapi.Envelope(dtos.ReadSessionDTO{}),
+
+			// Sandbox
+			"ResetSandboxRequest":  openapi.Raw(ResetSandboxRequest{}),
+			"SandboxResetResponse": openapi.Envelope(dtos.SandboxResetDTO{}),
+
+			// Admin
+			"SecurityEventListResponse":       openapi.Envelope(dtos.SecurityEventListDTO{}),
+			"FailedRequestListResponse":       openapi.Page(dtos.FailedRequestDTO{}),
+			"WorkerListResponse":              openapi.Envelope(dtos.WorkerListDTO{}),
+			"ScreeningRuleListResponse":       openapi.Envelope(dtos.ScreeningRuleListDTO{}),
+			"DryRunScreeningRequest":          openapi.Raw(DryRunScreeningRequest{}),
+			"ScreeningDryRunResponse":         openapi.Envelope(dtos.ScreeningDryRunDTO{}),
+			"JobListResponse":                 openapi.Envelope(dtos.JobListDTO{}),
+			"JobRunResponse":                  openapi.Envelope(dtos.JobRunDTO{}),
+			"TransactionFailureStatsResponse": openapi.Envelope(dtos.TransactionFailureStatsDTO{}),
+			"WalletReplayResponse":            openapi.Envelope(dtos.WalletReplayDTO{}),
+			"WalletNoteListResponse":          openapi.Envelope(dtos.WalletNoteListDTO{}),
+			"CreateWalletNoteRequest":         openapi.Raw(CreateWalletNoteRequest{}),
+			"UpdateWalletNoteRequest":         openapi.Raw(UpdateWalletNoteRequest{}),
+			"WalletNoteResponse":              openapi.Envelope(dtos.WalletNoteDTO{}),
+			"SetOperationSwitchRequest":       openapi.Raw(SetOperationSwitchRequest{}),
+			"OperationSwitchResponse":         openapi.Envelope(dtos.OperationSwitchDTO{}),
+			"CreateIncidentRequest":           openapi.Raw(CreateIncidentRequest{}),
+			"IncidentResponse":                openapi.Envelope(dtos.IncidentDTO{}),
+
+			// Meta
+			"RouteManifestResponse": openapi.Envelope(RouteManifestResponse{}),
+			"StateMachinesResponse": openapi.Envelope(StateMachinesResponse{}),
+			"CurrencyListResponse":  openapi.Envelope(CurrencyListResponse{}),
+		},
+		Enums: []openapi.EnumValues{
+			openapi.Enum(dtos.PaginationModeOffset, dtos.PaginationModeCursor),
+		},
+	}
+}
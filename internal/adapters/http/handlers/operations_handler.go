@@ -0,0 +1,87 @@
+// Package handlers - Operation kill switches admin HTTP handlers.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Operations Handler
+// ============================================
+
+// OperationsHandler обрабатывает admin запросы аварийных выключателей операций.
+// Роль admin/superadmin проверяется группой /admin в роутере.
+type OperationsHandler struct {
+	commandBus *cqrs.CommandBus
+}
+
+// NewOperationsHandler создаёт новый OperationsHandler.
+func NewOperationsHandler(commandBus *cqrs.CommandBus) *OperationsHandler {
+	return &OperationsHandler{commandBus: commandBus}
+}
+
+// ============================================
+// Request DTOs
+// ============================================
+
+// OperationTypeParam - вид операций в URI.
+type OperationTypeParam struct {
+	Type string `uri:"type" binding:"required,oneof=DEPOSIT WITHDRAW TRANSFER PAYOUT"`
+}
+
+// SetOperationSwitchRequest - новое состояние выключателя.
+//
+// @Description Set operation switch request body
+type SetOperationSwitchRequest struct {
+	Enabled *bool  `json:"enabled" binding:"required"`
+	Reason  string `json:"reason" binding:"max=500"`
+}
+
+// ============================================
+// HTTP Handlers
+// ============================================
+
+// SetOperationSwitch включает или выключает вид операций.
+//
+// @Summary Set operation kill switch
+// @Description Disable (with a reason) or re-enable one kind of money movement. Disabled operations fail with 503 OPERATION_TEMPORARILY_DISABLED; disabling WITHDRAW also stops payouts (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param type path string true "Operation" Enums(DEPOSIT, WITHDRAW, TRANSFER, PAYOUT)
+// @Param request body SetOperationSwitchRequest true "Switch state"
+// @Success 200 {object} common.APIResponse{data=dtos.OperationSwitchDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Router /api/v1/admin/switches/{type} [put]
+func (h *OperationsHandler) SetOperationSwitch(c *gin.Context) {
+	var params OperationTypeParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	var req SetOperationSwitchRequest
+	if !BindJSON(c, &req) {
+		return
+	}
+
+	cmd := dtos.SetOperationSwitchCommand{
+		Type:    params.Type,
+		Enabled: *req.Enabled,
+		Reason:  req.Reason,
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.SetOperationSwitchCommand, *dtos.OperationSwitchDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
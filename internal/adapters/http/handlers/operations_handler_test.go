@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSetOperationSwitchUseCase struct {
+	ExecuteFn func(ctx context.Context, cmd dtos.SetOperationSwitchCommand) (*dtos.OperationSwitchDTO, error)
+}
+
+func (m *mockSetOperationSwitchUseCase) Execute(ctx context.Context, cmd dtos.SetOperationSwitchCommand) (*dtos.OperationSwitchDTO, error) {
+	return m.ExecuteFn(ctx, cmd)
+}
+
+func TestOperationsHandler_SetOperationSwitch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var got dtos.SetOperationSwitchCommand
+	cmdBus := cqrs.NewCommandBus()
+	cqrs.RegisterCommandHandler[dtos.SetOperationSwitchCommand, *dtos.OperationSwitchDTO](cmdBus, &mockSetOperationSwitchUseCase{
+		ExecuteFn: func(_ context.Context, cmd dtos.SetOperationSwitchCommand) (*dtos.OperationSwitchDTO, error) {
+			got = cmd
+			return &dtos.OperationSwitchDTO{Type: cmd.Type, Enabled: cmd.Enabled, Reason: cmd.Reason}, nil
+		},
+	})
+	router := gin.New()
+	router.PUT("/api/v1/admin/switches/:type", NewOperationsHandler(cmdBus).SetOperationSwitch)
+
+	put := func(operation, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/switches/"+operation, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Disable", func(t *testing.T) {
+		w := put("WITHDRAW", `{"enabled":false,"reason":"payout provider incident"}`)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, dtos.SetOperationSwitchCommand{Type: "WITHDRAW", Enabled: false, Reason: "payout provider incident"}, got)
+		assert.Contains(t, w.Body.String(), `"reason":"payout provider incident"`)
+	})
+
+	t.Run("EnabledRequired", func(t *testing.T) {
+		// Пустое тело не включает операцию молча
+		assert.Equal(t, http.StatusBadRequest, put("WITHDRAW", `{}`).Code)
+	})
+
+	t.Run("UnknownOperation", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, put("FEE", `{"enabled":true}`).Code)
+	})
+}
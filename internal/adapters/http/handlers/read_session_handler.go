@@ -0,0 +1,52 @@
+// Package handlers - Read session HTTP handlers.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Read Session Handler
+// ============================================
+
+// ReadSessionHandler открывает snapshot-сессии чтения для сверки.
+// Регистрируется роутером, только если сессии доступны (postgres).
+type ReadSessionHandler struct {
+	commandBus *cqrs.CommandBus
+}
+
+// NewReadSessionHandler создаёт новый ReadSessionHandler.
+func NewReadSessionHandler(commandBus *cqrs.CommandBus) *ReadSessionHandler {
+	return &ReadSessionHandler{commandBus: commandBus}
+}
+
+// ============================================
+// HTTP Handlers
+// ============================================
+
+// OpenReadSession открывает сессию чтения текущего пользователя.
+// GET запросы с токеном в X-Read-Session читают один snapshot БД.
+//
+// @Summary Open read session
+// @Description Open a short-lived REPEATABLE READ snapshot. GET requests carrying the token in the X-Read-Session header read wallets and transactions as of the moment the session was opened; mutations with the header are rejected with 409 READ_SESSION_READ_ONLY.
+// @Tags Read Sessions
+// @Produce json
+// @Success 201 {object} common.APIResponse{data=dtos.ReadSessionDTO}
+// @Failure 401 {object} common.APIResponse
+// @Failure 429 {object} common.APIResponse "READ_SESSION_LIMIT_EXCEEDED"
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/read-sessions [post]
+func (h *ReadSessionHandler) OpenReadSession(c *gin.Context) {
+	result, err := cqrs.DispatchCommand[dtos.OpenReadSessionCommand, *dtos.ReadSessionDTO](h.commandBus, c.Request.Context(), dtos.OpenReadSessionCommand{})
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusCreated, result)
+}
@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	domainerrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// ============================================
+// Request Validation
+// ============================================
+//
+// Запрос проверяется целиком, и клиент получает все ошибки в одном
+// ответе вместо исправления по одному полю за раунд:
+//
+//  1. строгий разбор JSON: неизвестные поля и несовпадения типов
+//  2. теги binding (required, uuid, money_amount, ...)
+//  3. requestValidator: проверки, которые не выражаются тегами
+//
+// Поле с ошибкой на раннем шаге не проверяется на следующих: строка
+// вместо числа не даёт дополнительно "This field is required".
+// Пути полей: "amount", "metadata[source]", "items[3].idempotency_key".
+
+// requestValidator - запрос с синхронными доменными проверками
+// (поддерживаемая валюта и т.п.). Validate вызывается после тегов.
+type requestValidator interface {
+	Validate() domainerrors.ValidationErrors
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// fieldErrorSet - ошибки запроса по одной на путь в порядке обнаружения.
+type fieldErrorSet struct {
+	fields []common.FieldError
+	seen   map[string]bool
+}
+
+func (s *fieldErrorSet) add(field, code, message string) {
+	if s.seen == nil {
+		s.seen = make(map[string]bool)
+	}
+	if s.seen[field] {
+		return
+	}
+	s.seen[field] = true
+	s.fields = append(s.fields, common.FieldError{Field: field, Message: message, Code: code})
+}
+
+// bindJSONBody разбирает тело запроса в req и собирает все ошибки полей.
+// error возвращается только для тела, которое не является JSON.
+func bindJSONBody(c *gin.Context, req any) ([]common.FieldError, error) {
+	if c.Request == nil || c.Request.Body == nil {
+		return nil, errors.New("request body is empty")
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var raw any
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	var errs fieldErrorSet
+
+	// 1. Неизвестные поля и типы: json.Decoder останавливается на первом
+	// неизвестном поле, поэтому тело сверяется с типом запроса целиком
+	checkJSONValue(&errs, raw, reflect.TypeOf(req), "")
+
+	if err := json.Unmarshal(body, req); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &typeErr):
+			errs.add(typeErr.Field, common.FieldCodeInvalidType, "Expected "+typeErr.Type.String())
+		case len(errs.fields) == 0:
+			return nil, err
+		}
+	}
+
+	// 2. Теги binding
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if !errors.As(err, &validationErrs) {
+			return nil, err
+		}
+		for _, fe := range validationErrs {
+			errs.add(fieldPath(fe), validationCode(fe.Tag()), getValidationMessage(fe))
+		}
+	}
+
+	// 3. Доменные проверки запроса
+	if v, ok := req.(requestValidator); ok {
+		for _, ve := range v.Validate() {
+			code := ve.Code
+			if code == "" {
+				code = common.FieldCodeInvalidValue
+			}
+			errs.add(ve.Field, code, ve.Message)
+		}
+	}
+
+	return errs.fields, nil
+}
+
+// checkJSONValue сверяет разобранный JSON с типом t так же, как это делает
+// encoding/json, но не останавливается на первой ошибке.
+func checkJSONValue(errs *fieldErrorSet, value any, t reflect.Type, path string) {
+	if value == nil || t == nil {
+		return
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// Собственный формат (time.Time, uuid.UUID, ...) проверит json.Unmarshal
+	pt := reflect.PointerTo(t)
+	if pt.Implements(jsonUnmarshalerType) || pt.Implements(textUnmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return
+
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			errs.add(path, common.FieldCodeInvalidType, "Expected string, got "+jsonKind(value))
+		}
+
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			errs.add(path, common.FieldCodeInvalidType, "Expected boolean, got "+jsonKind(value))
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if _, ok := value.(float64); !ok {
+			errs.add(path, common.FieldCodeInvalidType, "Expected number, got "+jsonKind(value))
+		}
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return // []byte приходит base64 строкой
+		}
+		items, ok := value.([]any)
+		if !ok {
+			errs.add(path, common.FieldCodeInvalidType, "Expected array, got "+jsonKind(value))
+			return
+		}
+		for i, item := range items {
+			checkJSONValue(errs, item, t.Elem(), path+"["+strconv.Itoa(i)+"]")
+		}
+
+	case reflect.Map:
+		object, ok := value.(map[string]any)
+		if !ok {
+			errs.add(path, common.FieldCodeInvalidType, "Expected object, got "+jsonKind(value))
+			return
+		}
+		for _, key := range sortedKeys(object) {
+			checkJSONValue(errs, object[key], t.Elem(), path+"["+key+"]")
+		}
+
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			errs.add(path, common.FieldCodeInvalidType, "Expected object, got "+jsonKind(value))
+			return
+		}
+		fields := jsonFields(t)
+		for _, key := range sortedKeys(object) {
+			fieldPath := joinPath(path, key)
+			fieldType, ok := lookupJSONField(fields, key)
+			if !ok {
+				errs.add(fieldPath, common.FieldCodeUnknownField, "Unknown field")
+				continue
+			}
+			checkJSONValue(errs, object[key], fieldType, fieldPath)
+		}
+	}
+}
+
+// jsonFields возвращает JSON имена полей структуры с учётом встроенных структур.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for k, v := range jsonFields(embedded) {
+					if _, exists := fields[k]; !exists {
+						fields[k] = v
+					}
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// lookupJSONField ищет поле как encoding/json: сначала точное имя, затем без учёта регистра.
+func lookupJSONField(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if t, ok := fields[key]; ok {
+		return t, true
+	}
+	for name, t := range fields {
+		if strings.EqualFold(name, key) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// fieldPath - путь поля из ошибки validator без имени корневой структуры.
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if _, rest, ok := strings.Cut(ns, "."); ok {
+		return rest
+	}
+	return fe.Field()
+}
+
+// validationCode переводит тег validator в код FieldError.
+func validationCode(tag string) string {
+	switch tag {
+	case "required":
+		return common.FieldCodeRequired
+	case "email", "uuid", "len", "alpha", "ip", "currency_code", "money_amount":
+		return common.FieldCodeInvalidFormat
+	case "min", "max", "gt", "gte", "lt", "lte":
+		return common.FieldCodeOutOfRange
+	default:
+		return common.FieldCodeInvalidValue
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for k := range object {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// jsonKind - название JSON типа значения для сообщений об ошибке.
+func jsonKind(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,80 @@
+// Package handlers - Sandbox HTTP handlers.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Sandbox Handler
+// ============================================
+
+// SandboxHandler обрабатывает HTTP запросы sandbox окружения.
+// Регистрируется роутером только при включённом sandbox режиме.
+type SandboxHandler struct {
+	commandBus *cqrs.CommandBus
+}
+
+// NewSandboxHandler создаёт новый SandboxHandler.
+func NewSandboxHandler(commandBus *cqrs.CommandBus) *SandboxHandler {
+	return &SandboxHandler{commandBus: commandBus}
+}
+
+// ============================================
+// Request DTOs (HTTP layer)
+// ============================================
+
+// ResetSandboxRequest - запрос на сброс sandbox данных.
+// Пустое тело запрашивает токен подтверждения.
+//
+// @Description Sandbox reset request body
+type ResetSandboxRequest struct {
+	ConfirmationToken string `json:"confirmation_token,omitempty" binding:"omitempty,max=64"`
+}
+
+// ============================================
+// HTTP Handlers
+// ============================================
+
+// Reset удаляет данные текущего пользователя в два шага.
+// Первый вызов возвращает confirmation_token, второй (с токеном) удаляет данные.
+//
+// @Summary Reset sandbox data
+// @Description Delete the caller's transactions, wallets and webhook subscriptions. The first call returns a confirmation token valid for 5 minutes; the second call must echo it. Limited to one reset per 10 minutes.
+// @Tags Sandbox
+// @Accept json
+// @Produce json
+// @Param request body ResetSandboxRequest false "Confirmation token"
+// @Success 200 {object} common.APIResponse{data=dtos.SandboxResetDTO}
+// @Success 202 {object} common.APIResponse{data=dtos.SandboxResetDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Failure 422 {object} common.APIResponse
+// @Failure 429 {object} common.APIResponse
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/sandbox/reset [post]
+func (h *SandboxHandler) Reset(c *gin.Context) {
+	var req ResetSandboxRequest
+	if c.Request.ContentLength != 0 && !BindJSON(c, &req) {
+		return
+	}
+
+	cmd := dtos.ResetSandboxCommand{ConfirmationToken: req.ConfirmationToken}
+
+	result, err := cqrs.DispatchCommand[dtos.ResetSandboxCommand, *dtos.SandboxResetDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.Status == dtos.SandboxResetConfirmationRequired {
+		status = http.StatusAccepted
+	}
+	common.Success(c, status, result)
+}
@@ -0,0 +1,104 @@
+// Package handlers - Transaction screening admin HTTP handlers.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Screening Handler
+// ============================================
+
+// ScreeningHandler обрабатывает admin запросы правил скрининга.
+// Роль admin проверяется группой /admin в роутере.
+type ScreeningHandler struct {
+	queryBus *cqrs.QueryBus
+}
+
+// NewScreeningHandler создаёт новый ScreeningHandler.
+func NewScreeningHandler(queryBus *cqrs.QueryBus) *ScreeningHandler {
+	return &ScreeningHandler{queryBus: queryBus}
+}
+
+// ============================================
+// Request DTOs
+// ============================================
+
+// DryRunScreeningRequest - гипотетическая транзакция для проверки правилами.
+//
+// @Description Hypothetical transaction to evaluate against screening rules
+type DryRunScreeningRequest struct {
+	Type      string `json:"type" binding:"required,oneof=DEPOSIT WITHDRAW PAYOUT TRANSFER FEE REFUND ADJUSTMENT EXCHANGE" example:"WITHDRAW"`
+	Amount    string `json:"amount" binding:"required,max=32" example:"1500.00"`
+	Currency  string `json:"currency" binding:"required,len=3" example:"USD"`
+	WalletAge string `json:"wallet_age,omitempty" binding:"omitempty,max=32" example:"36h"`
+	KYCStatus string `json:"kyc_status,omitempty" binding:"omitempty,oneof=UNVERIFIED PENDING VERIFIED REJECTED" example:"PENDING"`
+	Country   string `json:"country,omitempty" binding:"omitempty,len=2" example:"DE"`
+}
+
+// ============================================
+// HTTP Handlers
+// ============================================
+
+// ListScreeningRules возвращает правила скрининга, загруженные при старте.
+//
+// @Summary List screening rules
+// @Description Active transaction screening rules in evaluation order (admin only)
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} common.APIResponse{data=dtos.ScreeningRuleListDTO}
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Router /api/v1/admin/screening-rules [get]
+func (h *ScreeningHandler) ListScreeningRules(c *gin.Context) {
+	result, err := cqrs.DispatchQuery[dtos.ListScreeningRulesQuery, *dtos.ScreeningRuleListDTO](h.queryBus, c.Request.Context(), dtos.ListScreeningRulesQuery{})
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// DryRunScreening проверяет гипотетическую транзакцию активными правилами.
+// Транзакция не создаётся, события не публикуются.
+//
+// @Summary Dry-run screening rules
+// @Description Evaluate a hypothetical transaction against the active screening rules without creating it (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body DryRunScreeningRequest true "Hypothetical transaction"
+// @Success 200 {object} common.APIResponse{data=dtos.ScreeningDryRunDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Router /api/v1/admin/screening-rules/dry-run [post]
+func (h *ScreeningHandler) DryRunScreening(c *gin.Context) {
+	var req DryRunScreeningRequest
+	if !BindJSON(c, &req) {
+		return
+	}
+
+	query := dtos.DryRunScreeningQuery{
+		Type:      req.Type,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+		WalletAge: req.WalletAge,
+		KYCStatus: req.KYCStatus,
+		Country:   req.Country,
+	}
+
+	result, err := cqrs.DispatchQuery[dtos.DryRunScreeningQuery, *dtos.ScreeningDryRunDTO](h.queryBus, c.Request.Context(), query)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockListScreeningRulesUseCase struct {
+	ExecuteFn func(ctx context.Context, query dtos.ListScreeningRulesQuery) (*dtos.ScreeningRuleListDTO, error)
+}
+
+func (m *mockListScreeningRulesUseCase) Execute(ctx context.Context, query dtos.ListScreeningRulesQuery) (*dtos.ScreeningRuleListDTO, error) {
+	return m.ExecuteFn(ctx, query)
+}
+
+type mockDryRunScreeningUseCase struct {
+	ExecuteFn func(ctx context.Context, query dtos.DryRunScreeningQuery) (*dtos.ScreeningDryRunDTO, error)
+}
+
+func (m *mockDryRunScreeningUseCase) Execute(ctx context.Context, query dtos.DryRunScreeningQuery) (*dtos.ScreeningDryRunDTO, error) {
+	return m.ExecuteFn(ctx, query)
+}
+
+func setupScreeningTestRouter(list *mockListScreeningRulesUseCase, dryRun *mockDryRunScreeningUseCase) *gin.Engine {
+	qBus := cqrs.NewQueryBus()
+	cqrs.RegisterQueryHandler[dtos.ListScreeningRulesQuery, *dtos.ScreeningRuleListDTO](qBus, list)
+	cqrs.RegisterQueryHandler[dtos.DryRunScreeningQuery, *dtos.ScreeningDryRunDTO](qBus, dryRun)
+
+	handler := NewScreeningHandler(qBus)
+	router := gin.New()
+	router.GET("/api/v1/admin/screening-rules", handler.ListScreeningRules)
+	router.POST("/api/v1/admin/screening-rules/dry-run", handler.DryRunScreening)
+	return router
+}
+
+func TestScreeningHandler_ListScreeningRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := setupScreeningTestRouter(&mockListScreeningRulesUseCase{
+		ExecuteFn: func(context.Context, dtos.ListScreeningRulesQuery) (*dtos.ScreeningRuleListDTO, error) {
+			return &dtos.ScreeningRuleListDTO{
+				Enabled: true,
+				Rules: []dtos.ScreeningRuleDTO{{
+					ID:     "high-risk-country",
+					Action: "FLAG",
+					Conditions: []dtos.ScreeningConditionDTO{
+						{Attribute: "country", Operator: "in", Values: []string{"IR", "KP"}},
+					},
+				}},
+				TotalCount: 1,
+			}, nil
+		},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/screening-rules", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data dtos.ScreeningRuleListDTO `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.True(t, body.Data.Enabled)
+	require.Len(t, body.Data.Rules, 1)
+	assert.Equal(t, []string{"IR", "KP"}, body.Data.Rules[0].Conditions[0].Values)
+}
+
+func TestScreeningHandler_DryRunScreening(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		var got dtos.DryRunScreeningQuery
+		router := setupScreeningTestRouter(nil, &mockDryRunScreeningUseCase{
+			ExecuteFn: func(_ context.Context, query dtos.DryRunScreeningQuery) (*dtos.ScreeningDryRunDTO, error) {
+				got = query
+				return &dtos.ScreeningDryRunDTO{
+					Decision:  dtos.ScreeningDecisionBlock,
+					BlockedBy: "fresh-wallet-withdraw",
+					Flags:     []string{},
+					Matched:   []string{"fresh-wallet-withdraw"},
+				}, nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/screening-rules/dry-run", strings.NewReader(
+			`{"type":"WITHDRAW","amount":"1500.00","currency":"USD","wallet_age":"2h","kyc_status":"PENDING","country":"DE"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, dtos.DryRunScreeningQuery{
+			Type: "WITHDRAW", Amount: "1500.00", Currency: "USD", WalletAge: "2h", KYCStatus: "PENDING", Country: "DE",
+		}, got)
+		assert.Contains(t, w.Body.String(), `"decision":"BLOCK"`)
+		assert.Contains(t, w.Body.String(), `"blocked_by":"fresh-wallet-withdraw"`)
+	})
+
+	t.Run("InvalidBody", func(t *testing.T) {
+		router := setupScreeningTestRouter(nil, &mockDryRunScreeningUseCase{
+			ExecuteFn: func(context.Context, dtos.DryRunScreeningQuery) (*dtos.ScreeningDryRunDTO, error) {
+				t.Fatal("use case must not be called")
+				return nil, nil
+			},
+		})
+
+		for _, body := range []string{
+			`{"amount":"1","currency":"USD"}`,
+			`{"type":"CHARGEBACK","amount":"1","currency":"USD"}`,
+			`{"type":"DEPOSIT","amount":"1","currency":"US"}`,
+			`{"type":"DEPOSIT","amount":"1","currency":"USD","kyc_status":"MAYBE"}`,
+		} {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/screening-rules/dry-run", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+// Package handlers - Security monitoring HTTP handlers.
+package handlers
+
+import (
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Security Handler
+// ============================================
+
+// SecurityHandler обрабатывает admin запросы журнала безопасности.
+// Роль admin проверяется группой /admin в роутере.
+type SecurityHandler struct {
+	queryBus *cqrs.QueryBus
+}
+
+// NewSecurityHandler создаёт новый SecurityHandler.
+func NewSecurityHandler(queryBus *cqrs.QueryBus) *SecurityHandler {
+	return &SecurityHandler{queryBus: queryBus}
+}
+
+// ============================================
+// Request DTOs
+// ============================================
+
+// ListSecurityEventsParams - параметры фильтрации журнала безопасности.
+type ListSecurityEventsParams struct {
+	Principal string `form:"principal" binding:"omitempty,max=255"`
+	Type      string `form:"type" binding:"omitempty,oneof=AUTH_FAILED AUTH_SUCCEEDED SCOPE_DENIED POLICY_DENIED"`
+	IP        string `form:"ip" binding:"omitempty,ip"`
+}
+
+// ============================================
+// HTTP Handlers
+// ============================================
+
+// ListSecurityEvents возвращает журнал аутентификации, новые события первыми.
+//
+// @Summary List security events
+// @Description Authentication and authorization outcomes for security monitoring (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20) maximum(100)
+// @Param principal query string false "User ID or key:<prefix> of a rejected credential"
+// @Param type query string false "Filter by type" Enums(AUTH_FAILED, AUTH_SUCCEEDED, SCOPE_DENIED, POLICY_DENIED)
+// @Param ip query string false "Filter by client IP"
+// @Param occurred_from query string false "Occurred at or after (RFC3339 with time zone)" format(date-time)
+// @Param occurred_to query string false "Occurred before (RFC3339 with time zone)" format(date-time)
+// @Success 200 {object} common.APIResponse{data=dtos.SecurityEventListDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/admin/security-events [get]
+func (h *SecurityHandler) ListSecurityEvents(c *gin.Context) {
+	pagination := ParsePagination(c)
+
+	var filters ListSecurityEventsParams
+	if !BindQuery(c, &filters) {
+		return
+	}
+
+	occurred, ok := ParseTimeRange(c, "occurred_from", "occurred_to")
+	if !ok {
+		return
+	}
+
+	query := dtos.ListSecurityEventsQuery{
+		OccurredFrom: occurred.From,
+		OccurredTo:   occurred.To,
+		Offset:       pagination.Offset(),
+		Limit:        pagination.PerPage,
+	}
+
+	if filters.Principal != "" {
+		query.Principal = &filters.Principal
+	}
+	if filters.Type != "" {
+		query.Type = &filters.Type
+	}
+	if filters.IP != "" {
+		query.IP = &filters.IP
+	}
+
+	result, err := cqrs.DispatchQuery[dtos.ListSecurityEventsQuery, *dtos.SecurityEventListDTO](h.queryBus, c.Request.Context(), query)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	meta := BuildMeta(pagination, result.TotalCount)
+	SuccessList(c, nil, meta, result, "events", result.Events)
+}
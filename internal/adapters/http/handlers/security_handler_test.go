@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockListSecurityEventsUseCase - мок запроса журнала безопасности.
+type mockListSecurityEventsUseCase struct {
+	ExecuteFn func(ctx context.Context, query dtos.ListSecurityEventsQuery) (*dtos.SecurityEventListDTO, error)
+}
+
+func (m *mockListSecurityEventsUseCase) Execute(ctx context.Context, query dtos.ListSecurityEventsQuery) (*dtos.SecurityEventListDTO, error) {
+	return m.ExecuteFn(ctx, query)
+}
+
+func setupSecurityTestRouter(uc *mockListSecurityEventsUseCase) *gin.Engine {
+	qBus := cqrs.NewQueryBus()
+	cqrs.RegisterQueryHandler[dtos.ListSecurityEventsQuery, *dtos.SecurityEventListDTO](qBus, uc)
+
+	router := gin.New()
+	router.GET("/api/v1/admin/security-events", NewSecurityHandler(qBus).ListSecurityEvents)
+	return router
+}
+
+func TestSecurityHandler_ListSecurityEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("WithFilters", func(t *testing.T) {
+		var got dtos.ListSecurityEventsQuery
+		router := setupSecurityTestRouter(&mockListSecurityEventsUseCase{
+			ExecuteFn: func(_ context.Context, query dtos.ListSecurityEventsQuery) (*dtos.SecurityEventListDTO, error) {
+				got = query
+				return &dtos.SecurityEventListDTO{Events: []dtos.SecurityEventDTO{}}, nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+			"/api/v1/admin/security-events?principal=key:abcd1234&type=AUTH_FAILED&ip=10.0.0.1&occurred_from=2026-03-01T15:00:00%2B03:00&page=2&per_page=10", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NotNil(t, got.Principal)
+		assert.Equal(t, "key:abcd1234", *got.Principal)
+		assert.Equal(t, "AUTH_FAILED", *got.Type)
+		assert.Equal(t, "10.0.0.1", *got.IP)
+		require.NotNil(t, got.OccurredFrom)
+		assert.True(t, got.OccurredFrom.Equal(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)))
+		assert.Nil(t, got.OccurredTo)
+		assert.Equal(t, 10, got.Offset)
+		assert.Equal(t, 10, got.Limit)
+	})
+
+	t.Run("InvalidFilters", func(t *testing.T) {
+		router := setupSecurityTestRouter(&mockListSecurityEventsUseCase{
+			ExecuteFn: func(context.Context, dtos.ListSecurityEventsQuery) (*dtos.SecurityEventListDTO, error) {
+				t.Fatal("use case must not be called")
+				return nil, nil
+			},
+		})
+
+		for _, target := range []string{
+			"/api/v1/admin/security-events?type=LOGIN",
+			"/api/v1/admin/security-events?ip=not-an-ip",
+			"/api/v1/admin/security-events?occurred_from=2026-03-01T12:00:00",
+		} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code, target)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+// Package handlers - Public status page HTTP handler.
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Status Handler
+// ============================================
+
+// StatusHandler отдаёт данные публичной страницы статуса.
+//
+// Ответ собирается из памяти (ports.StatusPage) без обращения к БД и
+// кэшируется CDN/браузером на maxAge. Только грубые статусы и инциденты:
+// никаких ID пользователей, ошибок и имён экземпляров.
+type StatusHandler struct {
+	page    ports.StatusPage
+	version string
+	maxAge  time.Duration
+}
+
+// NewStatusHandler создаёт новый StatusHandler.
+func NewStatusHandler(page ports.StatusPage, version string, maxAge time.Duration) *StatusHandler {
+	return &StatusHandler{page: page, version: version, maxAge: maxAge}
+}
+
+// ============================================
+// Response Types
+// ============================================
+
+// StatusComponentResponse - статус компонента страницы статуса.
+type StatusComponentResponse struct {
+	Name   string `json:"name"`   // api, database, payouts, deposits
+	Status string `json:"status"` // operational, degraded, down
+}
+
+// StatusIncidentResponse - публичная отметка инцидента.
+type StatusIncidentResponse struct {
+	ID         string     `json:"id"`
+	Title      string     `json:"title"`
+	Severity   string     `json:"severity"` // MINOR, MAJOR, CRITICAL
+	Components []string   `json:"components"`
+	StartedAt  time.Time  `json:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// StatusPageResponse - ответ GET /status.
+type StatusPageResponse struct {
+	Status     string                    `json:"status"` // худший из статусов компонентов
+	Version    string                    `json:"version"`
+	Components []StatusComponentResponse `json:"components"`
+	Incidents  []StatusIncidentResponse  `json:"incidents"`
+	Stale      bool                      `json:"stale"`                // хранилище недоступно, данные - последние известные
+	UpdatedAt  *time.Time                `json:"updated_at,omitempty"` // последнее успешное чтение хранилища
+}
+
+// ============================================
+// HTTP Handlers
+// ============================================
+
+// Status возвращает данные публичной страницы статуса.
+//
+// @Summary Public status page
+// @Description Coarse-grained component statuses, API version and active or recently resolved incidents. Served from memory, so it keeps answering during a database outage with stale=true
+// @Tags Health
+// @Produce json
+// @Success 200 {object} StatusPageResponse
+// @Router /status [get]
+func (h *StatusHandler) Status(c *gin.Context) {
+	snapshot := h.page.Snapshot()
+
+	response := StatusPageResponse{
+		Status:     string(snapshot.Status),
+		Version:    h.version,
+		Components: make([]StatusComponentResponse, 0, len(snapshot.Components)),
+		Incidents:  make([]StatusIncidentResponse, 0, len(snapshot.Incidents)),
+		Stale:      snapshot.Stale,
+	}
+	if !snapshot.UpdatedAt.IsZero() {
+		updatedAt := snapshot.UpdatedAt.UTC()
+		response.UpdatedAt = &updatedAt
+	}
+	for _, component := range snapshot.Components {
+		response.Components = append(response.Components, StatusComponentResponse{
+			Name:   string(component.Component),
+			Status: string(component.Status),
+		})
+	}
+	for _, incident := range snapshot.Incidents {
+		components := make([]string, 0, len(incident.Components()))
+		for _, component := range incident.Components() {
+			components = append(components, string(component))
+		}
+		response.Incidents = append(response.Incidents, StatusIncidentResponse{
+			ID:         incident.ID().String(),
+			Title:      incident.Title(),
+			Severity:   string(incident.Severity()),
+			Components: components,
+			StartedAt:  incident.StartedAt().UTC(),
+			ResolvedAt: incident.ResolvedAt(),
+		})
+	}
+
+	if h.maxAge > 0 {
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.maxAge.Seconds())))
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+type statusPageStub struct {
+	snapshot ports.StatusSnapshot
+}
+
+func (s statusPageStub) Snapshot() ports.StatusSnapshot {
+	return s.snapshot
+}
+
+func TestStatusHandler_Status(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	admin := uuid.New()
+	incident, err := entities.NewIncident("Delayed payouts", entities.IncidentSeverityMajor,
+		[]entities.StatusComponent{entities.StatusComponentPayouts}, time.Time{}, admin)
+	require.NoError(t, err)
+
+	page := statusPageStub{snapshot: ports.StatusSnapshot{
+		Status: ports.ServiceDegraded,
+		Components: []ports.ComponentStatus{
+			{Component: entities.StatusComponentAPI, Status: ports.ServiceOperational},
+			{Component: entities.StatusComponentPayouts, Status: ports.ServiceDegraded},
+		},
+		Incidents: []*entities.Incident{incident},
+		Stale:     true,
+		UpdatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}}
+
+	router := gin.New()
+	router.GET("/status", NewStatusHandler(page, "1.4.0", 10*time.Second).Status)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=10", w.Header().Get("Cache-Control"))
+	assert.NotContains(t, w.Body.String(), admin.String(), "no admin IDs on the public page")
+
+	var response StatusPageResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "degraded", response.Status)
+	assert.Equal(t, "1.4.0", response.Version)
+	assert.True(t, response.Stale)
+	require.NotNil(t, response.UpdatedAt)
+	assert.Equal(t, []StatusComponentResponse{
+		{Name: "api", Status: "operational"},
+		{Name: "payouts", Status: "degraded"},
+	}, response.Components)
+	require.Len(t, response.Incidents, 1)
+	assert.Equal(t, incident.ID().String(), response.Incidents[0].ID)
+	assert.Equal(t, []string{"payouts"}, response.Incidents[0].Components)
+	assert.Nil(t, response.Incidents[0].ResolvedAt)
+}
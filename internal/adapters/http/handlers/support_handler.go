@@ -0,0 +1,94 @@
+// Package handlers - Support investigation HTTP handlers.
+package handlers
+
+import (
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Support Handler
+// ============================================
+
+// SupportHandler обрабатывает admin запросы образцов неудачных операций.
+// Роль admin проверяется группой /admin в роутере.
+type SupportHandler struct {
+	queryBus *cqrs.QueryBus
+}
+
+// NewSupportHandler создаёт новый SupportHandler.
+func NewSupportHandler(queryBus *cqrs.QueryBus) *SupportHandler {
+	return &SupportHandler{queryBus: queryBus}
+}
+
+// ============================================
+// Request DTOs
+// ============================================
+
+// ListFailedRequestsParams - параметры фильтрации образцов неудачных запросов.
+type ListFailedRequestsParams struct {
+	UserID string `form:"user_id" binding:"omitempty,uuid"`
+	Route  string `form:"route" binding:"omitempty,max=255"`
+}
+
+// ============================================
+// HTTP Handlers
+// ============================================
+
+// ListFailedRequests возвращает образцы неудачных денежных операций,
+// новые первыми.
+//
+// @Summary List failed request samples
+// @Description Redacted request/response samples of failed money operations for support (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20) maximum(100)
+// @Param user_id query string false "Filter by user who sent the request" format(uuid)
+// @Param route query string false "Filter by route template, e.g. /api/v1/wallets/{id}/debit"
+// @Param occurred_from query string false "Occurred at or after (RFC3339 with time zone)" format(date-time)
+// @Param occurred_to query string false "Occurred before (RFC3339 with time zone)" format(date-time)
+// @Success 200 {object} common.APIResponse{data=[]dtos.FailedRequestDTO,pagination=dtos.PaginationDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/admin/failed-requests [get]
+func (h *SupportHandler) ListFailedRequests(c *gin.Context) {
+	pagination := ParsePagination(c)
+
+	var filters ListFailedRequestsParams
+	if !BindQuery(c, &filters) {
+		return
+	}
+
+	occurred, ok := ParseTimeRange(c, "occurred_from", "occurred_to")
+	if !ok {
+		return
+	}
+
+	query := dtos.ListFailedRequestsQuery{
+		OccurredFrom: occurred.From,
+		OccurredTo:   occurred.To,
+		Offset:       pagination.Offset(),
+		Limit:        pagination.PerPage,
+	}
+
+	if filters.UserID != "" {
+		query.UserID = &filters.UserID
+	}
+	if filters.Route != "" {
+		query.Route = &filters.Route
+	}
+
+	result, err := cqrs.DispatchQuery[dtos.ListFailedRequestsQuery, *dtos.FailedRequestListDTO](h.queryBus, c.Request.Context(), query)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	SuccessPage(c, nil, result)
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
 	"github.com/Haleralex/wallethub/internal/adapters/http/middleware"
 	"github.com/Haleralex/wallethub/internal/application/ports"
 	"github.com/Haleralex/wallethub/internal/domain/entities"
@@ -24,24 +25,26 @@ import (
 
 // TelegramAuthHandler handles Telegram Mini App authentication.
 type TelegramAuthHandler struct {
-	userRepo    ports.UserRepository
-	walletRepo  ports.WalletRepository
-	botToken    string
-	jwtSecret   string
-	jwtIssuer   string
-	tokenExpiry time.Duration
-	blacklist   ports.TokenBlacklist // nil = blacklist disabled
+	userRepo            ports.UserRepository
+	walletRepo          ports.WalletRepository
+	botToken            string
+	jwtSecret           string
+	jwtIssuer           string
+	tokenExpiry         time.Duration
+	blacklist           ports.TokenBlacklist // nil = blacklist disabled
+	defaultJurisdiction string               // "" = new users are left untagged
 }
 
 // TelegramAuthConfig holds configuration for TelegramAuthHandler.
 type TelegramAuthConfig struct {
-	UserRepo    ports.UserRepository
-	WalletRepo  ports.WalletRepository
-	BotToken    string
-	JWTSecret   string
-	JWTIssuer   string
-	TokenExpiry time.Duration
-	Blacklist   ports.TokenBlacklist // optional
+	UserRepo            ports.UserRepository
+	WalletRepo          ports.WalletRepository
+	BotToken            string
+	JWTSecret           string
+	JWTIssuer           string
+	TokenExpiry         time.Duration
+	Blacklist           ports.TokenBlacklist // optional
+	DefaultJurisdiction string               // assigned to new Telegram users, optional
 }
 
 // NewTelegramAuthHandler creates a new TelegramAuthHandler.
@@ -51,13 +54,14 @@ func NewTelegramAuthHandler(cfg TelegramAuthConfig) *TelegramAuthHandler {
 		expiry = 15 * time.Minute
 	}
 	return &TelegramAuthHandler{
-		userRepo:    cfg.UserRepo,
-		walletRepo:  cfg.WalletRepo,
-		botToken:    cfg.BotToken,
-		jwtSecret:   cfg.JWTSecret,
-		jwtIssuer:   cfg.JWTIssuer,
-		tokenExpiry: expiry,
-		blacklist:   cfg.Blacklist,
+		userRepo:            cfg.UserRepo,
+		walletRepo:          cfg.WalletRepo,
+		botToken:            cfg.BotToken,
+		jwtSecret:           cfg.JWTSecret,
+		jwtIssuer:           cfg.JWTIssuer,
+		tokenExpiry:         expiry,
+		blacklist:           cfg.Blacklist,
+		defaultJurisdiction: cfg.DefaultJurisdiction,
 	}
 }
 
@@ -74,6 +78,11 @@ type TelegramAuthResponse struct {
 	IsNew  bool            `json:"is_new"`
 }
 
+// LogoutResponse - response for logout.
+type LogoutResponse struct {
+	Message string `json:"message"`
+}
+
 // TelegramUserDTO - user data in auth response.
 type TelegramUserDTO struct {
 	ID        string `json:"id"`
@@ -134,6 +143,16 @@ func (h *TelegramAuthHandler) Authenticate(c *gin.Context) {
 			return
 		}
 
+		if h.defaultJurisdiction != "" {
+			if err := user.ChangeJurisdiction(h.defaultJurisdiction); err != nil {
+				common.Error(c, http.StatusInternalServerError, &common.APIError{
+					Code:    "USER_CREATION_FAILED",
+					Message: fmt.Sprintf("Failed to create user: %v", err),
+				})
+				return
+			}
+		}
+
 		if err := h.userRepo.Save(c.Request.Context(), user); err != nil {
 			common.Error(c, http.StatusInternalServerError, &common.APIError{
 				Code:    "USER_SAVE_FAILED",
@@ -262,22 +281,22 @@ func (h *TelegramAuthHandler) validateInitData(initData string) (*TelegramWebApp
 func (h *TelegramAuthHandler) Logout(c *gin.Context) {
 	if h.blacklist == nil {
 		// Blacklist not configured — logout is a no-op (token will expire naturally)
-		common.Success(c, http.StatusOK, gin.H{"message": "logged out"})
+		common.Success(c, http.StatusOK, LogoutResponse{Message: "logged out"})
 		return
 	}
 
-	jti := middleware.GetAuthJTI(c)
+	jti := httpctx.AuthJTI(c)
 	if jti == "" {
 		// Token has no JTI (issued before Redis was added) — nothing to revoke
-		common.Success(c, http.StatusOK, gin.H{"message": "logged out"})
+		common.Success(c, http.StatusOK, LogoutResponse{Message: "logged out"})
 		return
 	}
 
-	exp := middleware.GetAuthExp(c)
+	exp, _ := httpctx.AuthExp(c)
 	ttl := time.Until(exp)
 	if ttl <= 0 {
 		// Token already expired — no need to blacklist
-		common.Success(c, http.StatusOK, gin.H{"message": "logged out"})
+		common.Success(c, http.StatusOK, LogoutResponse{Message: "logged out"})
 		return
 	}
 
@@ -289,5 +308,5 @@ func (h *TelegramAuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	common.Success(c, http.StatusOK, gin.H{"message": "logged out"})
+	common.Success(c, http.StatusOK, LogoutResponse{Message: "logged out"})
 }
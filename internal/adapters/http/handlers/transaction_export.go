@@ -0,0 +1,167 @@
+// Package handlers - CSV выгрузка списка транзакций.
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/pkg/csvsafe"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// MIMECSV - тип содержимого CSV выгрузки.
+	MIMECSV = "text/csv"
+
+	// HeaderTruncated выставляется, если выгрузка обрезана по
+	// transactions.export_max_rows.
+	HeaderTruncated = "X-Truncated"
+
+	// transactionCSVFlushEvery - через сколько строк ответ сбрасывается
+	// клиенту (размер страницы выгрузки).
+	transactionCSVFlushEvery = 500
+)
+
+// transactionCSVColumns - колонки CSV выгрузки транзакций.
+var transactionCSVColumns = []string{
+	"id", "created_at", "completed_at", "type", "direction", "status",
+	"currency_code", "gross_amount", "fee_amount", "net_amount", "fee_mode",
+	"wallet_id", "destination_wallet_id", "idempotency_key", "external_reference",
+	"description", "receipt_number", "failure_reason",
+}
+
+// wantsCSV определяет формат ответа: ?format=csv|json важнее заголовка
+// Accept. При неизвестном format отправляет 400 и возвращает ok=false.
+func wantsCSV(c *gin.Context) (csv bool, ok bool) {
+	switch format := c.Query("format"); format {
+	case "csv":
+		return true, true
+	case "json":
+		return false, true
+	case "":
+		return c.NegotiateFormat(gin.MIMEJSON, MIMECSV) == MIMECSV, true
+	default:
+		common.ValidationErrorResponse(c, []common.FieldError{
+			{Field: "format", Message: "format must be one of: json, csv", Code: common.FieldCodeInvalidFormat},
+		})
+		return false, false
+	}
+}
+
+// streamTransactionsCSV отдаёт выборку построчно, сбрасывая буфер после
+// каждой страницы, так что память не растёт с размером выгрузки. Ошибка
+// хранилища после начала ответа только логируется: статус уже отправлен.
+func (h *TransactionHandler) streamTransactionsCSV(c *gin.Context, query dtos.ExportTransactionsQuery) {
+	ctx := c.Request.Context()
+
+	result, err := cqrs.DispatchQuery[dtos.ExportTransactionsQuery, *dtos.TransactionExportDTO](h.queryBus, ctx, query)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", MIMECSV+"; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, transactionsCSVFilename(query, time.Now())))
+	if result.Truncated {
+		c.Header(HeaderTruncated, "true")
+	}
+	c.Status(http.StatusOK)
+
+	w := csvsafe.NewWriter(c.Writer)
+	w.WriteRaw(transactionCSVColumns)
+
+	rows := 0
+	for row, err := range result.Rows {
+		if err != nil {
+			slog.ErrorContext(ctx, "transactions CSV export interrupted", "rows", rows, "error", err)
+			_ = w.Flush()
+			return
+		}
+		w.WriteRaw(transactionCSVRecord(row))
+		rows++
+		if rows%transactionCSVFlushEvery == 0 {
+			if err := w.Flush(); err != nil {
+				// Клиент отключился
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+
+	if result.Truncated {
+		w.Comment(fmt.Sprintf("truncated after %d rows; narrow created_from/created_to to export the rest", result.MaxRows))
+	}
+	if err := w.Flush(); err == nil {
+		c.Writer.Flush()
+	}
+}
+
+// transactionCSVRecord - строка CSV. Поля, заданные клиентом, экранируются
+// от формул (csvsafe.Escape); служебные значения пишутся как есть.
+func transactionCSVRecord(row *dtos.TransactionExportRowDTO) []string {
+	return []string{
+		row.ID,
+		formatCSVTime(&row.CreatedAt),
+		formatCSVTime(row.CompletedAt),
+		row.Type,
+		row.Direction,
+		row.Status,
+		row.CurrencyCode,
+		row.GrossAmount,
+		row.FeeAmount,
+		row.NetAmount,
+		row.FeeMode,
+		row.WalletID,
+		derefString(row.DestinationWalletID),
+		csvsafe.Escape(row.IdempotencyKey),
+		csvsafe.Escape(row.ExternalReference),
+		csvsafe.Escape(row.Description),
+		row.ReceiptNumber,
+		csvsafe.Escape(row.FailureReason),
+	}
+}
+
+// transactionsCSVFilename кодирует фильтры и дату выгрузки в имя файла,
+// например transactions_wallet-<id>_status-COMPLETED_2026-01-31.csv.
+func transactionsCSVFilename(query dtos.ExportTransactionsQuery, now time.Time) string {
+	parts := []string{"transactions"}
+	add := func(name string, value *string) {
+		if value != nil {
+			parts = append(parts, name+"-"+*value)
+		}
+	}
+	add("wallet", query.WalletID)
+	add("user", query.UserID)
+	add("type", query.Type)
+	add("status", query.Status)
+	if query.CreatedFrom != nil {
+		parts = append(parts, "from-"+query.CreatedFrom.UTC().Format(time.DateOnly))
+	}
+	if query.CreatedTo != nil {
+		parts = append(parts, "to-"+query.CreatedTo.UTC().Format(time.DateOnly))
+	}
+	parts = append(parts, now.UTC().Format(time.DateOnly))
+	return strings.Join(parts, "_") + ".csv"
+}
+
+// formatCSVTime форматирует время так же, как JSON ответ; nil - пустая ячейка.
+func formatCSVTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// derefString - значение строки или пустая строка для nil.
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
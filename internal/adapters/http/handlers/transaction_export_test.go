@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/usecases/transaction"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// setupTransactionExportRouter собирает список и выгрузку поверх одного
+// in-memory хранилища с count транзакциями нового кошелька.
+func setupTransactionExportRouter(t *testing.T, count, maxRows int) (*gin.Engine, uuid.UUID) {
+	t.Helper()
+
+	store := memory.NewStore()
+	repo := memory.NewTransactionRepository(store)
+	usd, err := valueobjects.NewCurrency("USD")
+	require.NoError(t, err)
+	user, err := entities.NewUser("export@example.com", "Export Owner")
+	require.NoError(t, err)
+	require.NoError(t, memory.NewUserRepository(store).Save(context.Background(), user))
+	wallet, err := entities.NewWallet(user.ID(), usd)
+	require.NoError(t, err)
+	require.NoError(t, memory.NewWalletRepository(store).Save(context.Background(), wallet))
+	walletID := wallet.ID()
+	amount, err := valueobjects.NewMoney("10.00", usd)
+	require.NoError(t, err)
+
+	for i := range count {
+		description := "deposit"
+		if i == 0 {
+			description = "=HYPERLINK(\"http://evil\")"
+		}
+		tx, err := entities.NewTransaction(walletID, uuid.NewString(), entities.TransactionTypeDeposit, amount, description)
+		require.NoError(t, err)
+		require.NoError(t, repo.Save(context.Background(), tx))
+	}
+
+	qBus := cqrs.NewQueryBus()
+	cqrs.RegisterQueryHandler[dtos.ListTransactionsQuery, *dtos.TransactionListDTO](qBus, transaction.NewListTransactionsUseCase(repo))
+	cqrs.RegisterQueryHandler[dtos.ExportTransactionsQuery, *dtos.TransactionExportDTO](qBus, transaction.NewExportTransactionsUseCase(repo, maxRows))
+
+	return setupTransactionTestRouter(NewTransactionHandler(cqrs.NewCommandBus(), qBus)), walletID
+}
+
+func readTransactionsCSV(t *testing.T, body string) [][]string {
+	t.Helper()
+
+	reader := csv.NewReader(strings.NewReader(body))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	require.NoError(t, err)
+	require.NotEmpty(t, records)
+	assert.Equal(t, transactionCSVColumns, records[0])
+	return records[1:]
+}
+
+func TestTransactionHandler_ListTransactions_CSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("FormatNegotiation", func(t *testing.T) {
+		router, _ := setupTransactionExportRouter(t, 2, 0)
+
+		tests := []struct {
+			name    string
+			query   string
+			accept  string
+			wantCSV bool
+		}{
+			{"DefaultJSON", "", "", false},
+			{"AcceptCSV", "", "text/csv", true},
+			{"AcceptAny", "", "*/*", false},
+			{"FormatCSV", "?format=csv", "", true},
+			{"FormatOverridesAccept", "?format=json", "text/csv", false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions"+tt.query, nil)
+				if tt.accept != "" {
+					req.Header.Set("Accept", tt.accept)
+				}
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				require.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, tt.wantCSV, strings.HasPrefix(w.Header().Get("Content-Type"), MIMECSV))
+			})
+		}
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		router, _ := setupTransactionExportRouter(t, 0, 0)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions?format=xlsx", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("FiltersAreValidated", func(t *testing.T) {
+		router, _ := setupTransactionExportRouter(t, 0, 0)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions?format=csv&status=not-a-status", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("UnknownFilterValuePassesThrough", func(t *testing.T) {
+		router, _ := setupTransactionExportRouter(t, 2, 0)
+
+		// Тип из более нового релиза - обычное условие выборки
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions?format=csv&type=CHARGEBACK", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, readTransactionsCSV(t, w.Body.String()))
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		router, _ := setupTransactionExportRouter(t, 5, 3)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions?format=csv&per_page=1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "true", w.Header().Get(HeaderTruncated))
+
+		records := readTransactionsCSV(t, w.Body.String())
+		require.Len(t, records, 4, "3 rows and the comment")
+		assert.True(t, strings.HasPrefix(records[3][0], "# truncated after 3 rows"))
+	})
+
+	t.Run("NotTruncated", func(t *testing.T) {
+		router, _ := setupTransactionExportRouter(t, 3, 3)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions?format=csv", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(HeaderTruncated))
+		assert.Len(t, readTransactionsCSV(t, w.Body.String()), 3)
+	})
+
+	t.Run("SameRowsAsJSON", func(t *testing.T) {
+		router, walletID := setupTransactionExportRouter(t, 4, 0)
+		filter := "wallet_id=" + walletID.String() + "&type=DEPOSIT"
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions?per_page=100&"+filter, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var listed struct {
+			Data struct {
+				Transactions []dtos.TransactionDTO `json:"transactions"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/transactions?"+filter, nil)
+		req.Header.Set("Accept", "text/csv")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "transactions_wallet-"+walletID.String()+"_type-DEPOSIT_")
+
+		records := readTransactionsCSV(t, w.Body.String())
+		require.Len(t, records, len(listed.Data.Transactions))
+		for i, tx := range listed.Data.Transactions {
+			row := &dtos.TransactionExportRowDTO{TransactionDTO: tx, Direction: dtos.TransactionDirectionIn}
+			assert.Equal(t, transactionCSVRecord(row), records[i])
+		}
+	})
+
+	t.Run("FormulaEscaping", func(t *testing.T) {
+		router, _ := setupTransactionExportRouter(t, 1, 0)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions?format=csv", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		records := readTransactionsCSV(t, w.Body.String())
+		require.Len(t, records, 1)
+		assert.Equal(t, "'=HYPERLINK(\"http://evil\")", records[0][15])
+	})
+}
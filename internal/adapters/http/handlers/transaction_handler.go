@@ -3,6 +3,7 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/Haleralex/wallethub/internal/adapters/http/common"
 	"github.com/Haleralex/wallethub/internal/application/cqrs"
@@ -43,8 +44,19 @@ type TransactionIDParam struct {
 type ListTransactionsParams struct {
 	WalletID string `form:"wallet_id" binding:"omitempty,uuid"`
 	UserID   string `form:"user_id" binding:"omitempty,uuid"`
-	Type     string `form:"type" binding:"omitempty,oneof=DEPOSIT WITHDRAW PAYOUT TRANSFER FEE REFUND ADJUSTMENT"`
-	Status   string `form:"status" binding:"omitempty,oneof=PENDING PROCESSING COMPLETED FAILED CANCELLED"`
+	Type     string `form:"type" binding:"omitempty,enum_term"`
+	Status   string `form:"status" binding:"omitempty,enum_term"`
+}
+
+// transactionListFields - поля транзакции, доступные для выбора через ?fields=.
+// metadata отдаётся в выборке только если запрошена явно.
+var transactionListFields = []string{
+	"id", "wallet_id", "idempotency_key", "type", "status",
+	"amount", "gross_amount", "fee_amount", "net_amount", "fee_mode",
+	"currency_code",
+	"destination_wallet_id", "external_reference", "description", "metadata",
+	"failure_reason", "failure_message_key", "failure_category", "retry_count", "max_retries", "is_retryable", "next_retry_at", "jurisdiction",
+	"created_at", "updated_at", "processed_at", "completed_at", "receipt_number",
 }
 
 // CancelTransactionRequest - запрос на отмену транзакции.
@@ -66,11 +78,16 @@ type CancelTransactionRequest struct {
 // @Accept json
 // @Produce json
 // @Param id path string true "Transaction ID" format(uuid)
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} common.APIResponse{data=dtos.TransactionDTO}
+// @Header 200 {string} ETag "Transaction version"
+// @Success 304 "Not Modified"
 // @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
 // @Failure 404 {object} common.APIResponse
 // @Failure 500 {object} common.APIResponse
 // @Router /api/v1/transactions/{id} [get]
+// @Router /api/v1/transactions/{id} [head]
 func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 	var params TransactionIDParam
 	if !BindURI(c, &params) {
@@ -79,7 +96,7 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 
 	if _, err := uuid.Parse(params.ID); err != nil {
 		common.ValidationErrorResponse(c, []common.FieldError{
-			{Field: "id", Message: "Invalid UUID format", Code: "uuid"},
+			{Field: "id", Message: "Invalid UUID format", Code: common.FieldCodeInvalidFormat},
 		})
 		return
 	}
@@ -92,7 +109,7 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 		return
 	}
 
-	common.Success(c, http.StatusOK, result)
+	common.SuccessWithETag(c, transactionETag(result), result)
 }
 
 // ListTransactions возвращает список транзакций с фильтрацией.
@@ -108,11 +125,22 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 // @Param user_id query string false "Filter by user ID" format(uuid)
 // @Param type query string false "Filter by type" Enums(DEPOSIT, WITHDRAW, PAYOUT, TRANSFER, FEE, REFUND, ADJUSTMENT)
 // @Param status query string false "Filter by status" Enums(PENDING, PROCESSING, COMPLETED, FAILED, CANCELLED)
+// @Param created_from query string false "Created at or after (RFC3339 with time zone)" format(date-time)
+// @Param created_to query string false "Created before (RFC3339 with time zone)" format(date-time)
+// @Param fields query string false "Comma-separated fields to return (id is always included)" example(id,status,amount,created_at)
+// @Param format query string false "Response format; overrides the Accept header" Enums(json, csv)
+// @Produce text/csv
 // @Success 200 {object} common.APIResponse{data=dtos.TransactionListDTO}
+// @Header 200 {string} X-Truncated "CSV only: true when the export was cut at the row limit"
 // @Failure 400 {object} common.APIResponse
 // @Failure 500 {object} common.APIResponse
 // @Router /api/v1/transactions [get]
 func (h *TransactionHandler) ListTransactions(c *gin.Context) {
+	csvRequested, ok := wantsCSV(c)
+	if !ok {
+		return
+	}
+
 	pagination := ParsePagination(c)
 
 	var filters ListTransactionsParams
@@ -120,9 +148,21 @@ func (h *TransactionHandler) ListTransactions(c *gin.Context) {
 		return
 	}
 
+	fields, ok := ParseFields(c, transactionListFields)
+	if !ok {
+		return
+	}
+
+	createdRange, ok := ParseCreatedRange(c)
+	if !ok {
+		return
+	}
+
 	query := dtos.ListTransactionsQuery{
-		Offset: pagination.Offset(),
-		Limit:  pagination.PerPage,
+		CreatedFrom: createdRange.From,
+		CreatedTo:   createdRange.To,
+		Offset:      pagination.Offset(),
+		Limit:       pagination.PerPage,
 	}
 
 	if filters.WalletID != "" {
@@ -138,6 +178,19 @@ func (h *TransactionHandler) ListTransactions(c *gin.Context) {
 		query.Status = &filters.Status
 	}
 
+	if csvRequested {
+		// Пагинация и ?fields= к CSV не применяются: выгружается вся выборка
+		h.streamTransactionsCSV(c, dtos.ExportTransactionsQuery{
+			WalletID:    query.WalletID,
+			UserID:      query.UserID,
+			Type:        query.Type,
+			Status:      query.Status,
+			CreatedFrom: query.CreatedFrom,
+			CreatedTo:   query.CreatedTo,
+		})
+		return
+	}
+
 	result, err := cqrs.DispatchQuery[dtos.ListTransactionsQuery, *dtos.TransactionListDTO](h.queryBus, c.Request.Context(), query)
 	if err != nil {
 		common.HandleDomainError(c, err)
@@ -145,7 +198,7 @@ func (h *TransactionHandler) ListTransactions(c *gin.Context) {
 	}
 
 	meta := BuildMeta(pagination, result.TotalCount)
-	common.SuccessWithMeta(c, http.StatusOK, result, meta)
+	SuccessList(c, fields, meta, result, "transactions", result.Transactions)
 }
 
 // GetTransactionByIdempotencyKey возвращает транзакцию по ключу идемпотентности.
@@ -158,6 +211,7 @@ func (h *TransactionHandler) ListTransactions(c *gin.Context) {
 // @Param key path string true "Idempotency Key"
 // @Success 200 {object} common.APIResponse{data=dtos.TransactionDTO}
 // @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
 // @Failure 404 {object} common.APIResponse
 // @Failure 500 {object} common.APIResponse
 // @Router /api/v1/transactions/by-key/{key} [get]
@@ -165,7 +219,7 @@ func (h *TransactionHandler) GetTransactionByIdempotencyKey(c *gin.Context) {
 	key := c.Param("key")
 	if key == "" {
 		common.ValidationErrorResponse(c, []common.FieldError{
-			{Field: "key", Message: "Idempotency key is required", Code: "required"},
+			{Field: "key", Message: "Idempotency key is required", Code: common.FieldCodeRequired},
 		})
 		return
 	}
@@ -183,6 +237,32 @@ func (h *TransactionHandler) GetTransactionByIdempotencyKey(c *gin.Context) {
 	common.Success(c, http.StatusOK, result)
 }
 
+// GetReceipt возвращает чек по номеру.
+//
+// @Summary Get receipt by number
+// @Description Look up a completed transaction by its receipt number (PB-XXXX-XXXX). Case, dashes and the PB prefix are optional; a mistyped number fails the check character and returns 400. Only owners of the source or destination wallet and admins can see a receipt.
+// @Tags Transactions
+// @Accept json
+// @Produce json
+// @Param number path string true "Receipt number" example(PB-7F3K-9Q2M)
+// @Success 200 {object} common.APIResponse{data=dtos.ReceiptDTO}
+// @Failure 400 {object} common.APIResponse "Invalid or mistyped receipt number"
+// @Failure 401 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/receipts/{number} [get]
+func (h *TransactionHandler) GetReceipt(c *gin.Context) {
+	query := dtos.GetReceiptQuery{ReceiptNumber: c.Param("number")}
+
+	result, err := cqrs.DispatchQuery[dtos.GetReceiptQuery, *dtos.ReceiptDTO](h.queryBus, c.Request.Context(), query)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
 // RetryTransaction повторяет failed транзакцию.
 //
 // @Summary Retry a failed transaction
@@ -214,6 +294,67 @@ func (h *TransactionHandler) RetryTransaction(c *gin.Context) {
 	common.Success(c, http.StatusOK, result)
 }
 
+// AdminRetryTransaction повторяет failed транзакцию, не дожидаясь next_retry_at.
+//
+// @Summary Retry a failed transaction now (admin)
+// @Description Retry a failed transaction ignoring the backoff schedule
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Transaction ID" format(uuid)
+// @Success 200 {object} common.APIResponse{data=dtos.TransactionDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Failure 422 {object} common.APIResponse "Transaction is not in failed state or retries are exhausted"
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/admin/transactions/{id}/retry [post]
+func (h *TransactionHandler) AdminRetryTransaction(c *gin.Context) {
+	var params TransactionIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	cmd := dtos.RetryTransactionCommand{TransactionID: params.ID, IgnoreSchedule: true}
+
+	result, err := cqrs.DispatchCommand[dtos.RetryTransactionCommand, *dtos.TransactionDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// AdminFailureStats возвращает долю провалов транзакций по категориям.
+//
+// @Summary Transaction failure-rate breakdown (admin)
+// @Description Completed and failed transactions finalized in the window, failures split by category (CLIENT, PROVIDER, SYSTEM, FRAUD)
+// @Tags Admin
+// @Produce json
+// @Param from query string false "Finalized at or after (RFC3339 with time zone), default to - 24h" format(date-time)
+// @Param to query string false "Finalized before (RFC3339 with time zone), default now" format(date-time)
+// @Success 200 {object} common.APIResponse{data=dtos.TransactionFailureStatsDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/admin/transactions/failure-stats [get]
+func (h *TransactionHandler) AdminFailureStats(c *gin.Context) {
+	window, ok := ParseTimeRange(c, "from", "to")
+	if !ok {
+		return
+	}
+
+	query := dtos.GetTransactionFailureStatsQuery{From: window.From, To: window.To}
+
+	result, err := cqrs.DispatchQuery[dtos.GetTransactionFailureStatsQuery, *dtos.TransactionFailureStatsDTO](h.queryBus, c.Request.Context(), query)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
 // CancelTransaction отменяет pending транзакцию.
 //
 // @Summary Cancel a pending transaction
@@ -266,6 +407,9 @@ func (h *TransactionHandler) CancelTransaction(c *gin.Context) {
 // @Param per_page query int false "Items per page" default(20) maximum(100)
 // @Param type query string false "Filter by type" Enums(DEPOSIT, WITHDRAW, PAYOUT, TRANSFER, FEE, REFUND, ADJUSTMENT)
 // @Param status query string false "Filter by status" Enums(PENDING, PROCESSING, COMPLETED, FAILED, CANCELLED)
+// @Param created_from query string false "Created at or after (RFC3339 with time zone)" format(date-time)
+// @Param created_to query string false "Created before (RFC3339 with time zone)" format(date-time)
+// @Param fields query string false "Comma-separated fields to return (id is always included)" example(id,status,amount,created_at)
 // @Success 200 {object} common.APIResponse{data=dtos.TransactionListDTO}
 // @Failure 400 {object} common.APIResponse
 // @Failure 500 {object} common.APIResponse
@@ -274,14 +418,14 @@ func (h *TransactionHandler) GetWalletTransactions(c *gin.Context) {
 	walletID := c.Param("id")
 	if walletID == "" {
 		common.ValidationErrorResponse(c, []common.FieldError{
-			{Field: "wallet_id", Message: "Wallet ID is required", Code: "required"},
+			{Field: "wallet_id", Message: "Wallet ID is required", Code: common.FieldCodeRequired},
 		})
 		return
 	}
 
 	if _, err := uuid.Parse(walletID); err != nil {
 		common.ValidationErrorResponse(c, []common.FieldError{
-			{Field: "wallet_id", Message: "Invalid UUID format", Code: "uuid"},
+			{Field: "wallet_id", Message: "Invalid UUID format", Code: common.FieldCodeInvalidFormat},
 		})
 		return
 	}
@@ -293,10 +437,22 @@ func (h *TransactionHandler) GetWalletTransactions(c *gin.Context) {
 		return
 	}
 
+	fields, ok := ParseFields(c, transactionListFields)
+	if !ok {
+		return
+	}
+
+	createdRange, ok := ParseCreatedRange(c)
+	if !ok {
+		return
+	}
+
 	query := dtos.ListTransactionsQuery{
-		WalletID: &walletID,
-		Offset:   pagination.Offset(),
-		Limit:    pagination.PerPage,
+		WalletID:    &walletID,
+		CreatedFrom: createdRange.From,
+		CreatedTo:   createdRange.To,
+		Offset:      pagination.Offset(),
+		Limit:       pagination.PerPage,
 	}
 
 	if filters.Type != "" {
@@ -313,7 +469,7 @@ func (h *TransactionHandler) GetWalletTransactions(c *gin.Context) {
 	}
 
 	meta := BuildMeta(pagination, result.TotalCount)
-	common.SuccessWithMeta(c, http.StatusOK, result, meta)
+	SuccessList(c, fields, meta, result, "transactions", result.Transactions)
 }
 
 // RegisterRoutes регистрирует маршруты для TransactionHandler.
@@ -322,6 +478,7 @@ func (h *TransactionHandler) RegisterRoutes(router *gin.RouterGroup) {
 	{
 		transactions.GET("", h.ListTransactions)
 		transactions.GET("/:id", h.GetTransaction)
+		transactions.HEAD("/:id", h.GetTransaction)
 		transactions.GET("/by-key/:key", h.GetTransactionByIdempotencyKey)
 		transactions.POST("/:id/retry", h.RetryTransaction)
 		transactions.POST("/:id/cancel", h.CancelTransaction)
@@ -332,3 +489,11 @@ func (h *TransactionHandler) RegisterRoutes(router *gin.RouterGroup) {
 func (h *TransactionHandler) RegisterWalletTransactionsRoute(walletRoutes *gin.RouterGroup) {
 	walletRoutes.GET("/:id/transactions", h.GetWalletTransactions)
 }
+
+// transactionETag строит ETag транзакции из updated_at и статуса.
+func transactionETag(tx *dtos.TransactionDTO) string {
+	return common.StrongETag(
+		strconv.FormatInt(tx.UpdatedAt.UnixNano(), 10),
+		tx.Status,
+	)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -15,6 +16,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // ============================================
@@ -200,6 +202,80 @@ func TestTransactionHandler_GetTransaction(t *testing.T) {
 	})
 }
 
+func TestTransactionHandler_GetTransaction_ETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	txID := uuid.New().String()
+	updatedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	current := dtos.TransactionDTO{
+		ID:           txID,
+		WalletID:     uuid.New().String(),
+		Type:         "WITHDRAW",
+		Status:       "PENDING",
+		Amount:       "10.00",
+		CurrencyCode: "USD",
+		UpdatedAt:    updatedAt,
+	}
+	mockUseCase := &mockGetTransactionUseCase{
+		ExecuteFn: func(ctx context.Context, query dtos.GetTransactionQuery) (*dtos.TransactionDTO, error) {
+			tx := current
+			return &tx, nil
+		},
+	}
+
+	cmdBus, qBus := buildTransactionBuses(mockUseCase, nil, nil, nil)
+	router := setupTransactionTestRouter(NewTransactionHandler(cmdBus, qBus))
+
+	request := func(method, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/transactions/"+txID, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := request(http.MethodGet, "")
+	etag := first.Header().Get("ETag")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.NotEmpty(t, etag)
+
+	t.Run("MatchingIfNoneMatch", func(t *testing.T) {
+		w := request(http.MethodGet, etag)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("NonMatchingIfNoneMatch", func(t *testing.T) {
+		w := request(http.MethodGet, `"stale"`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+	})
+
+	t.Run("HeadMatchesGetHeaders", func(t *testing.T) {
+		get := request(http.MethodGet, "")
+		head := request(http.MethodHead, "")
+
+		assert.Equal(t, http.StatusOK, head.Code)
+		assert.Empty(t, head.Body.String())
+		assert.Equal(t, get.Header().Get("ETag"), head.Header().Get("ETag"))
+		assert.Equal(t, get.Header().Get("Content-Type"), head.Header().Get("Content-Type"))
+	})
+
+	t.Run("StatusChangeRotatesETag", func(t *testing.T) {
+		current.Status = "COMPLETED"
+
+		w := request(http.MethodGet, etag)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	})
+}
+
 func TestTransactionHandler_ListTransactions(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -257,6 +333,52 @@ func TestTransactionHandler_ListTransactions(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 
+	t.Run("WithCreatedRange", func(t *testing.T) {
+		mockUseCase := &mockListTransactionsUseCase{
+			ExecuteFn: func(ctx context.Context, query dtos.ListTransactionsQuery) (*dtos.TransactionListDTO, error) {
+				require.NotNil(t, query.CreatedFrom)
+				require.NotNil(t, query.CreatedTo)
+				assert.Equal(t, time.UTC, query.CreatedFrom.Location())
+				assert.Equal(t, "2024-03-09T21:00:00Z", query.CreatedFrom.Format(time.RFC3339))
+				assert.Equal(t, "2024-03-11T00:00:00Z", query.CreatedTo.Format(time.RFC3339))
+				return &dtos.TransactionListDTO{Transactions: []dtos.TransactionDTO{}, TotalCount: 0}, nil
+			},
+		}
+
+		cmdBus, qBus := buildTransactionBuses(nil, mockUseCase, nil, nil)
+		handler := NewTransactionHandler(cmdBus, qBus)
+		router := setupTransactionTestRouter(handler)
+
+		req := httptest.NewRequest(http.MethodGet,
+			"/api/v1/transactions?created_from=2024-03-10T00:00:00%2B03:00&created_to=2024-03-11T00:00:00Z", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("AmbiguousCreatedFrom", func(t *testing.T) {
+		mockUseCase := &mockListTransactionsUseCase{
+			ExecuteFn: func(ctx context.Context, query dtos.ListTransactionsQuery) (*dtos.TransactionListDTO, error) {
+				t.Fatal("use case must not be called")
+				return nil, nil
+			},
+		}
+
+		cmdBus, qBus := buildTransactionBuses(nil, mockUseCase, nil, nil)
+		handler := NewTransactionHandler(cmdBus, qBus)
+		router := setupTransactionTestRouter(handler)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions?created_from=2024-03-10", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "created_from")
+	})
+
 	t.Run("NoHandlerRegistered", func(t *testing.T) {
 		cmdBus := cqrs.NewCommandBus()
 		qBus := cqrs.NewQueryBus()
@@ -272,6 +394,125 @@ func TestTransactionHandler_ListTransactions(t *testing.T) {
 	})
 }
 
+// seedTransactionPage возвращает use case со страницей из n транзакций с metadata.
+func seedTransactionPage(n int) *mockListTransactionsUseCase {
+	transactions := make([]dtos.TransactionDTO, n)
+	for i := range transactions {
+		transactions[i] = dtos.TransactionDTO{
+			ID:             uuid.New().String(),
+			WalletID:       uuid.New().String(),
+			IdempotencyKey: uuid.New().String(),
+			Type:           "DEPOSIT",
+			Status:         "COMPLETED",
+			Amount:         fmt.Sprintf("%d.00", 100+i),
+			CurrencyCode:   "USD",
+			Description:    "Card top-up",
+			Metadata: map[string]string{
+				"card_bin":      "424242",
+				"gateway":       "stripe",
+				"gateway_ref":   uuid.New().String(),
+				"customer_ip":   "203.0.113.10",
+				"user_agent":    "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) AppleWebKit/605.1.15",
+				"risk_decision": "approve",
+			},
+			Jurisdiction: "DE",
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+		}
+	}
+
+	return &mockListTransactionsUseCase{
+		ExecuteFn: func(ctx context.Context, query dtos.ListTransactionsQuery) (*dtos.TransactionListDTO, error) {
+			return &dtos.TransactionListDTO{Transactions: transactions, TotalCount: n, Limit: n}, nil
+		},
+	}
+}
+
+func TestTransactionHandler_ListTransactions_Fields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cmdBus, qBus := buildTransactionBuses(nil, seedTransactionPage(100), nil, nil)
+	router := setupTransactionTestRouter(NewTransactionHandler(cmdBus, qBus))
+
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		w := get("/api/v1/transactions?per_page=100")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data dtos.TransactionListDTO `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Data.Transactions, 100)
+		assert.Equal(t, 100, response.Data.TotalCount)
+		assert.NotEmpty(t, response.Data.Transactions[0].Metadata)
+		assert.NotEmpty(t, response.Data.Transactions[0].WalletID)
+	})
+
+	t.Run("ValidSelection", func(t *testing.T) {
+		w := get("/api/v1/transactions?per_page=100&fields=status,amount,created_at")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data struct {
+				Transactions []map[string]interface{} `json:"transactions"`
+				TotalCount   int                      `json:"total_count"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Data.Transactions, 100)
+		assert.Equal(t, 100, response.Data.TotalCount)
+
+		// id возвращается всегда, metadata - только по запросу
+		row := response.Data.Transactions[0]
+		assert.ElementsMatch(t, []string{"id", "status", "amount", "created_at"}, mapKeys(row))
+		assert.NotContains(t, row, "metadata")
+	})
+
+	t.Run("MetadataOnRequest", func(t *testing.T) {
+		w := get("/api/v1/transactions?fields=metadata")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"gateway":"stripe"`)
+	})
+
+	t.Run("InvalidField", func(t *testing.T) {
+		w := get("/api/v1/transactions?fields=id,status,secret_notes")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		apiErr := response["error"].(map[string]interface{})
+		details := apiErr["details"].(map[string]interface{})
+		assert.Equal(t, []interface{}{"secret_notes"}, details["invalid_fields"])
+		assert.Contains(t, details["valid_fields"], "metadata")
+		assert.Contains(t, apiErr["message"], "valid fields: id, wallet_id")
+	})
+
+	// На 100 строках с типичной metadata: ~63 KB полный ответ против ~13 KB (-79%).
+	t.Run("PayloadSize", func(t *testing.T) {
+		full := get("/api/v1/transactions?per_page=100").Body.Len()
+		sparse := get("/api/v1/transactions?per_page=100&fields=id,status,amount,created_at").Body.Len()
+
+		reduction := 100 - sparse*100/full
+		t.Logf("100-row page: full=%d bytes, fields=id,status,amount,created_at -> %d bytes (-%d%%)", full, sparse, reduction)
+		assert.Greater(t, reduction, 70)
+	})
+}
+
+// mapKeys возвращает ключи map.
+func mapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
 func TestTransactionHandler_RetryTransaction(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -555,6 +796,7 @@ func TestTransactionHandler_RegisterRoutes(t *testing.T) {
 	expectedRoutes := []string{
 		"GET /api/v1/transactions",
 		"GET /api/v1/transactions/:id",
+		"HEAD /api/v1/transactions/:id",
 		"GET /api/v1/transactions/by-key/:key",
 		"POST /api/v1/transactions/:id/retry",
 		"POST /api/v1/transactions/:id/cancel",
@@ -5,11 +5,11 @@ import (
 	"net/http"
 
 	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
 	"github.com/Haleralex/wallethub/internal/adapters/http/middleware"
 	"github.com/Haleralex/wallethub/internal/application/cqrs"
 	"github.com/Haleralex/wallethub/internal/application/dtos"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 // ============================================
@@ -39,8 +39,58 @@ func NewUserHandler(commandBus *cqrs.CommandBus, queryBus *cqrs.QueryBus) *UserH
 //
 // @Description Create user request body
 type CreateUserRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	FullName string `json:"full_name" binding:"required,min=2,max=100"`
+	Email        string `json:"email" binding:"required,email"`
+	FullName     string `json:"full_name" binding:"required,min=2,max=100"`
+	Jurisdiction string `json:"jurisdiction,omitempty" binding:"omitempty,len=2,alpha"` // ISO 3166-1 alpha-2
+}
+
+// UpdateUserRequest - запрос на обновление профиля (все поля опциональны).
+//
+// @Description Update user profile request body
+type UpdateUserRequest struct {
+	Email        *string `json:"email,omitempty" binding:"omitempty,email"`
+	FullName     *string `json:"full_name,omitempty" binding:"omitempty,min=2,max=100"`
+	Jurisdiction *string `json:"jurisdiction,omitempty" binding:"omitempty,len=2,alpha"` // ISO 3166-1 alpha-2
+}
+
+// ReviewKYCRequest - решение по KYC (только admin).
+//
+// @Description KYC review decision request body
+type ReviewKYCRequest struct {
+	Approved *bool  `json:"approved" binding:"required"`
+	Reason   string `json:"reason,omitempty" binding:"max=500"` // Обязательна при отказе, минимум 10 символов
+}
+
+// RevokeKYCRequest - отзыв ранее одобренного KYC (только admin).
+//
+// @Description KYC revocation request body
+type RevokeKYCRequest struct {
+	Reason string `json:"reason" binding:"required,min=10,max=500"`
+}
+
+// AcceptTermsRequest - принятие версии условий обслуживания.
+//
+// @Description Accept terms of service request body
+type AcceptTermsRequest struct {
+	Version int `json:"version" binding:"required,min=1"`
+}
+
+// OnboardUserRequest - onboarding пользователя партнёром.
+//
+// @Description Partner onboarding request body
+type OnboardUserRequest struct {
+	IdempotencyKey string                    `json:"idempotency_key" binding:"required,client_idempotency_key,uuid"`
+	Email          string                    `json:"email" binding:"required,email"`
+	FullName       string                    `json:"full_name" binding:"required,min=2,max=100"`
+	Jurisdiction   string                    `json:"jurisdiction,omitempty" binding:"omitempty,len=2,alpha"` // ISO 3166-1 alpha-2
+	AutoApproveKYC bool                      `json:"auto_approve_kyc"`
+	Wallets        []OnboardingWalletRequest `json:"wallets,omitempty" binding:"omitempty,dive"`
+}
+
+// OnboardingWalletRequest - кошелёк onboarding.
+type OnboardingWalletRequest struct {
+	CurrencyCode  string `json:"currency_code" binding:"required,len=3"`
+	InitialCredit string `json:"initial_credit,omitempty"` // Decimal string
 }
 
 // UserIDParam - параметр ID пользователя из URL.
@@ -48,6 +98,11 @@ type UserIDParam struct {
 	ID string `uri:"id" binding:"required,uuid"`
 }
 
+// AdminGetUserParams - query-параметры получения пользователя администратором.
+type AdminGetUserParams struct {
+	IncludeSummary bool `form:"include_summary"`
+}
+
 // ============================================
 // HTTP Handlers
 // ============================================
@@ -72,8 +127,9 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	}
 
 	cmd := dtos.CreateUserCommand{
-		Email:    req.Email,
-		FullName: req.FullName,
+		Email:        req.Email,
+		FullName:     req.FullName,
+		Jurisdiction: req.Jurisdiction,
 	}
 
 	result, err := cqrs.DispatchCommand[dtos.CreateUserCommand, *dtos.UserCreatedDTO](h.commandBus, c.Request.Context(), cmd)
@@ -95,6 +151,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 // @Param id path string true "User ID" format(uuid)
 // @Success 200 {object} common.APIResponse{data=dtos.UserDTO}
 // @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
 // @Failure 404 {object} common.APIResponse
 // @Failure 500 {object} common.APIResponse
 // @Router /api/v1/users/{id} [get]
@@ -104,28 +161,297 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	requestedID, err := uuid.Parse(params.ID)
+	query := dtos.GetUserQuery{UserID: params.ID}
+
+	result, err := cqrs.DispatchQuery[dtos.GetUserQuery, *dtos.UserDTO](h.queryBus, c.Request.Context(), query)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// AdminGetUser возвращает пользователя для admin консоли.
+//
+// С include_summary=true ответ содержит wallet_count, total_transactions
+// и last_activity_at (одна агрегация в репозитории).
+//
+// @Summary Get user (admin)
+// @Description Get any user by UUID, optionally with an activity summary
+// @Tags Admin
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param include_summary query bool false "Include wallet and transaction counts"
+// @Success 200 {object} common.APIResponse{data=dtos.AdminUserDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/admin/users/{id} [get]
+func (h *UserHandler) AdminGetUser(c *gin.Context) {
+	var params UserIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	var options AdminGetUserParams
+	if !BindQuery(c, &options) {
+		return
+	}
+
+	query := dtos.GetAdminUserQuery{UserID: params.ID, IncludeSummary: options.IncludeSummary}
+
+	result, err := cqrs.DispatchQuery[dtos.GetAdminUserQuery, *dtos.AdminUserDTO](h.queryBus, c.Request.Context(), query)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// UpdateUser обновляет профиль пользователя.
+//
+// Смена jurisdiction влияет только на новые кошельки и транзакции.
+//
+// @Summary Update user profile
+// @Description Update email, full name or jurisdiction of the authenticated user
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param request body UpdateUserRequest true "Fields to update"
+// @Success 200 {object} common.APIResponse{data=dtos.UserDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Failure 409 {object} common.APIResponse
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/users/{id} [patch]
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	var params UserIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	var req UpdateUserRequest
+	if !BindJSON(c, &req) {
+		return
+	}
+
+	cmd := dtos.UpdateUserCommand{
+		UserID:       params.ID,
+		Email:        req.Email,
+		FullName:     req.FullName,
+		Jurisdiction: req.Jurisdiction,
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.UpdateUserCommand, *dtos.UserDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// ReviewKYC одобряет или отклоняет KYC пользователя.
+// Reviewer (actor) берётся из auth context, решение пишется в историю KYC.
+//
+// @Summary Approve/Reject KYC
+// @Description Approve or reject user's KYC verification (admin only)
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param request body ReviewKYCRequest true "Decision"
+// @Success 200 {object} common.APIResponse{data=dtos.UserDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Failure 422 {object} common.APIResponse
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/users/{id}/kyc [post]
+func (h *UserHandler) ReviewKYC(c *gin.Context) {
+	var params UserIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	var req ReviewKYCRequest
+	if !BindJSON(c, &req) {
+		return
+	}
+
+	cmd := dtos.ApproveKYCCommand{
+		UserID:   params.ID,
+		Verified: *req.Approved,
+		Reason:   req.Reason,
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.ApproveKYCCommand, *dtos.UserDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// RevokeKYC отзывает ранее одобренный KYC пользователя (VERIFIED -> REJECTED).
+// Дальнейшие списания владельца проверяет KYC gate согласно kyc.enforcement.
+//
+// @Summary Revoke KYC
+// @Description Revoke previously approved KYC verification (admin only)
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param request body RevokeKYCRequest true "Revocation reason"
+// @Success 200 {object} common.APIResponse{data=dtos.UserDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Failure 422 {object} common.APIResponse "KYC is not verified"
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/users/{id}/kyc/revoke [post]
+func (h *UserHandler) RevokeKYC(c *gin.Context) {
+	var params UserIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	var req RevokeKYCRequest
+	if !BindJSON(c, &req) {
+		return
+	}
+
+	cmd := dtos.RevokeKYCCommand{UserID: params.ID, Reason: req.Reason}
+	result, err := cqrs.DispatchCommand[dtos.RevokeKYCCommand, *dtos.UserDTO](h.commandBus, c.Request.Context(), cmd)
 	if err != nil {
-		common.ValidationErrorResponse(c, []common.FieldError{
-			{Field: "id", Message: "Invalid UUID format", Code: "uuid"},
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// OnboardUser создаёт пользователя, кошельки и начальные зачисления одним
+// атомарным запросом партнёра.
+//
+// auto_approve_kyc выполняется только для доверенного партнёра: токен с
+// capability trusted_partner и ID из onboarding.trusted_partners. Повтор с
+// тем же idempotency_key возвращает первый результат со статусом 200.
+//
+// @Summary Onboard user
+// @Description Atomically create a user, wallets and initial credits on behalf of a partner
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body OnboardUserRequest true "Onboarding data"
+// @Success 201 {object} common.APIResponse{data=dtos.OnboardingResultDTO}
+// @Success 200 {object} common.APIResponse{data=dtos.OnboardingResultDTO} "Idempotent replay"
+// @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse "KYC auto-approval forbidden"
+// @Failure 409 {object} common.APIResponse "User already exists"
+// @Failure 422 {object} common.APIResponse "Idempotency key reused or currency not allowed"
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/onboarding [post]
+func (h *UserHandler) OnboardUser(c *gin.Context) {
+	var req OnboardUserRequest
+	if !BindJSON(c, &req) {
+		return
+	}
+
+	cmd := dtos.OnboardUserCommand{
+		IdempotencyKey:      req.IdempotencyKey,
+		Email:               req.Email,
+		FullName:            req.FullName,
+		Jurisdiction:        req.Jurisdiction,
+		AutoApproveKYC:      req.AutoApproveKYC,
+		Wallets:             make([]dtos.OnboardingWalletItem, 0, len(req.Wallets)),
+		TrustedPartnerScope: httpctx.HasAuthCapability(c, middleware.CapabilityTrustedPartner),
+	}
+	for _, w := range req.Wallets {
+		cmd.Wallets = append(cmd.Wallets, dtos.OnboardingWalletItem{
+			CurrencyCode:  w.CurrencyCode,
+			InitialCredit: w.InitialCredit,
 		})
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.OnboardUserCommand, *dtos.OnboardingResultDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, operationStatus(result.IdempotentReplay), result)
+}
+
+// AcceptTerms фиксирует принятие пользователем версии условий обслуживания.
+// Повторное принятие той же версии ничего не меняет.
+//
+// @Summary Accept terms of service
+// @Description Record acceptance of a terms-of-service version by the authenticated user
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param request body AcceptTermsRequest true "Accepted version"
+// @Success 200 {object} common.APIResponse{data=dtos.UserDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Failure 422 {object} common.APIResponse
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/users/{id}/accept-terms [post]
+func (h *UserHandler) AcceptTerms(c *gin.Context) {
+	var params UserIDParam
+	if !BindURI(c, &params) {
 		return
 	}
 
-	// Self-only: users can only fetch their own profile.
-	authUserID := middleware.GetAuthUserID(c)
-	if authUserID == uuid.Nil {
-		common.UnauthorizedResponse(c, "User not authenticated")
+	var req AcceptTermsRequest
+	if !BindJSON(c, &req) {
 		return
 	}
-	if requestedID != authUserID {
-		common.ForbiddenResponse(c, "You can only access your own profile")
+
+	cmd := dtos.AcceptTermsCommand{
+		UserID:  params.ID,
+		Version: req.Version,
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.AcceptTermsCommand, *dtos.UserDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
 		return
 	}
 
-	query := dtos.GetUserQuery{UserID: params.ID}
+	common.Success(c, http.StatusOK, result)
+}
 
-	result, err := cqrs.DispatchQuery[dtos.GetUserQuery, *dtos.UserDTO](h.queryBus, c.Request.Context(), query)
+// GetKYCHistory возвращает историю KYC решений пользователя.
+//
+// @Summary Get KYC history
+// @Description Chronological list of KYC status changes (owner or admin)
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Success 200 {object} common.APIResponse{data=dtos.KYCHistoryDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/users/{id}/kyc/history [get]
+func (h *UserHandler) GetKYCHistory(c *gin.Context) {
+	var params UserIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	query := dtos.GetKYCHistoryQuery{UserID: params.ID}
+	result, err := cqrs.DispatchQuery[dtos.GetKYCHistoryQuery, *dtos.KYCHistoryDTO](h.queryBus, c.Request.Context(), query)
 	if err != nil {
 		common.HandleDomainError(c, err)
 		return
@@ -137,12 +463,24 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 // RegisterRoutes регистрирует маршруты для UserHandler.
 //
 // Routes:
-// - POST   /users          - Create user
-// - GET    /users/:id      - Get user by ID (self only)
+// - POST   /users                  - Create user
+// - GET    /users/:id              - Get user by ID (self only)
+// - PATCH  /users/:id              - Update user profile (self only)
+// - POST   /users/:id/kyc          - Approve/reject KYC (admin only)
+// - POST   /users/:id/kyc/revoke   - Revoke KYC (admin only)
+// - POST   /users/:id/accept-terms - Accept terms of service (self only)
+// - GET    /users/:id/kyc/history  - KYC history (owner or admin)
+// - POST   /onboarding             - Partner onboarding
 func (h *UserHandler) RegisterRoutes(router *gin.RouterGroup) {
 	users := router.Group("/users")
 	{
 		users.POST("", h.CreateUser)
 		users.GET("/:id", h.GetUser)
+		users.PATCH("/:id", h.UpdateUser)
+		users.POST("/:id/kyc", middleware.RequireRole("admin"), h.ReviewKYC)
+		users.POST("/:id/kyc/revoke", middleware.RequireRole("admin"), h.RevokeKYC)
+		users.GET("/:id/kyc/history", h.GetKYCHistory)
+		users.POST("/:id/accept-terms", h.AcceptTerms)
 	}
+	router.POST("/onboarding", h.OnboardUser)
 }
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
 	"github.com/Haleralex/wallethub/internal/adapters/http/middleware"
 	"github.com/Haleralex/wallethub/internal/application/cqrs"
 	"github.com/Haleralex/wallethub/internal/application/dtos"
@@ -57,6 +58,72 @@ func (m *MockListUsersUseCase) Execute(ctx context.Context, query dtos.ListUsers
 	return nil, errors.New("not implemented")
 }
 
+type MockUpdateUserUseCase struct {
+	ExecuteFn func(ctx context.Context, cmd dtos.UpdateUserCommand) (*dtos.UserDTO, error)
+}
+
+func (m *MockUpdateUserUseCase) Execute(ctx context.Context, cmd dtos.UpdateUserCommand) (*dtos.UserDTO, error) {
+	if m.ExecuteFn != nil {
+		return m.ExecuteFn(ctx, cmd)
+	}
+	return nil, errors.New("not implemented")
+}
+
+type MockReviewKYCUseCase struct {
+	ExecuteFn func(ctx context.Context, cmd dtos.ApproveKYCCommand) (*dtos.UserDTO, error)
+}
+
+func (m *MockReviewKYCUseCase) Execute(ctx context.Context, cmd dtos.ApproveKYCCommand) (*dtos.UserDTO, error) {
+	if m.ExecuteFn != nil {
+		return m.ExecuteFn(ctx, cmd)
+	}
+	return nil, errors.New("not implemented")
+}
+
+type MockRevokeKYCUseCase struct {
+	ExecuteFn func(ctx context.Context, cmd dtos.RevokeKYCCommand) (*dtos.UserDTO, error)
+}
+
+func (m *MockRevokeKYCUseCase) Execute(ctx context.Context, cmd dtos.RevokeKYCCommand) (*dtos.UserDTO, error) {
+	if m.ExecuteFn != nil {
+		return m.ExecuteFn(ctx, cmd)
+	}
+	return nil, errors.New("not implemented")
+}
+
+type MockGetKYCHistoryUseCase struct {
+	ExecuteFn func(ctx context.Context, query dtos.GetKYCHistoryQuery) (*dtos.KYCHistoryDTO, error)
+}
+
+func (m *MockGetKYCHistoryUseCase) Execute(ctx context.Context, query dtos.GetKYCHistoryQuery) (*dtos.KYCHistoryDTO, error) {
+	if m.ExecuteFn != nil {
+		return m.ExecuteFn(ctx, query)
+	}
+	return nil, errors.New("not implemented")
+}
+
+type MockGetAdminUserUseCase struct {
+	ExecuteFn func(ctx context.Context, query dtos.GetAdminUserQuery) (*dtos.AdminUserDTO, error)
+}
+
+func (m *MockGetAdminUserUseCase) Execute(ctx context.Context, query dtos.GetAdminUserQuery) (*dtos.AdminUserDTO, error) {
+	if m.ExecuteFn != nil {
+		return m.ExecuteFn(ctx, query)
+	}
+	return nil, errors.New("not implemented")
+}
+
+type MockOnboardUserUseCase struct {
+	ExecuteFn func(ctx context.Context, cmd dtos.OnboardUserCommand) (*dtos.OnboardingResultDTO, error)
+}
+
+func (m *MockOnboardUserUseCase) Execute(ctx context.Context, cmd dtos.OnboardUserCommand) (*dtos.OnboardingResultDTO, error) {
+	if m.ExecuteFn != nil {
+		return m.ExecuteFn(ctx, cmd)
+	}
+	return nil, errors.New("not implemented")
+}
+
 // ============================================
 // Helper Functions
 // ============================================
@@ -89,7 +156,7 @@ func setupUserTestRouter(handler *UserHandler) *gin.Engine {
 
 	// Add request ID middleware (needed for response helpers)
 	router.Use(func(c *gin.Context) {
-		c.Set("X-Request-ID", "test-request-123")
+		httpctx.SetRequestID(c, "test-request-123")
 		c.Next()
 	})
 
@@ -100,7 +167,7 @@ func setupUserTestRouter(handler *UserHandler) *gin.Engine {
 // Used to satisfy self-only checks in GetUser without spinning up real JWT auth.
 func withAuth(userID string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Set(middleware.AuthUserIDKey, userID)
+		httpctx.SetAuthUserID(c, uuid.MustParse(userID))
 		c.Next()
 	}
 }
@@ -269,21 +336,6 @@ func TestUserHandler_GetUser(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
-	t.Run("ForbiddenWhenAccessingOtherUser", func(t *testing.T) {
-		cmdBus, qBus := buildUserBuses(nil, &MockGetUserUseCase{}, nil)
-		handler := NewUserHandler(cmdBus, qBus)
-		router := setupUserTestRouter(handler)
-		router.Use(withAuth(uuid.New().String())) // authenticated as someone else
-		router.GET("/users/:id", handler.GetUser)
-
-		req := httptest.NewRequest(http.MethodGet, "/users/"+uuid.New().String(), nil)
-		w := httptest.NewRecorder()
-
-		router.ServeHTTP(w, req)
-
-		assert.Equal(t, http.StatusForbidden, w.Code)
-	})
-
 	t.Run("NotFound", func(t *testing.T) {
 		userID := uuid.New().String()
 		mockUseCase := &MockGetUserUseCase{
@@ -324,10 +376,399 @@ func TestUserHandler_GetUser(t *testing.T) {
 	})
 }
 
+// ============================================
+// Test UpdateUser Handler
+// ============================================
+
+func TestUserHandler_UpdateUser(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		userID := uuid.New().String()
+		var received dtos.UpdateUserCommand
+		cmdBus, qBus := buildUserBuses(nil, nil, nil)
+		cqrs.RegisterCommandHandler[dtos.UpdateUserCommand, *dtos.UserDTO](cmdBus, &MockUpdateUserUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.UpdateUserCommand) (*dtos.UserDTO, error) {
+				received = cmd
+				return &dtos.UserDTO{ID: userID, Email: "john@example.com", FullName: "John Doe", Jurisdiction: "FR"}, nil
+			},
+		})
+
+		handler := NewUserHandler(cmdBus, qBus)
+		router := setupUserTestRouter(handler)
+		router.Use(withAuth(userID))
+		router.PATCH("/users/:id", handler.UpdateUser)
+
+		body, _ := json.Marshal(map[string]string{"jurisdiction": "FR"})
+		req := httptest.NewRequest(http.MethodPatch, "/users/"+userID, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, userID, received.UserID)
+		require.NotNil(t, received.Jurisdiction)
+		assert.Equal(t, "FR", *received.Jurisdiction)
+		assert.Nil(t, received.Email)
+		assert.Nil(t, received.FullName)
+	})
+
+	t.Run("InvalidJurisdiction", func(t *testing.T) {
+		userID := uuid.New().String()
+		cmdBus, qBus := buildUserBuses(nil, nil, nil)
+		cqrs.RegisterCommandHandler[dtos.UpdateUserCommand, *dtos.UserDTO](cmdBus, &MockUpdateUserUseCase{})
+
+		handler := NewUserHandler(cmdBus, qBus)
+		router := setupUserTestRouter(handler)
+		router.Use(withAuth(userID))
+		router.PATCH("/users/:id", handler.UpdateUser)
+
+		body, _ := json.Marshal(map[string]string{"jurisdiction": "FRA"})
+		req := httptest.NewRequest(http.MethodPatch, "/users/"+userID, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+}
+
+// ============================================
+// Test KYC Handlers
+// ============================================
+
+func TestUserHandler_ReviewKYC(t *testing.T) {
+	t.Run("Reject", func(t *testing.T) {
+		userID := uuid.New().String()
+		var received dtos.ApproveKYCCommand
+		cmdBus, qBus := buildUserBuses(nil, nil, nil)
+		cqrs.RegisterCommandHandler[dtos.ApproveKYCCommand, *dtos.UserDTO](cmdBus, &MockReviewKYCUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.ApproveKYCCommand) (*dtos.UserDTO, error) {
+				received = cmd
+				return &dtos.UserDTO{ID: userID, KYCStatus: "REJECTED", LastKYCRejectionReason: cmd.Reason}, nil
+			},
+		})
+
+		handler := NewUserHandler(cmdBus, qBus)
+		router := setupUserTestRouter(handler)
+		router.POST("/users/:id/kyc", handler.ReviewKYC)
+
+		body, _ := json.Marshal(map[string]any{"approved": false, "reason": "Blurry passport photo"})
+		req := httptest.NewRequest(http.MethodPost, "/users/"+userID+"/kyc", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, userID, received.UserID)
+		assert.False(t, received.Verified)
+		assert.Equal(t, "Blurry passport photo", received.Reason)
+		assert.Contains(t, w.Body.String(), `"last_kyc_rejection_reason":"Blurry passport photo"`)
+	})
+
+	t.Run("MissingDecision", func(t *testing.T) {
+		cmdBus, qBus := buildUserBuses(nil, nil, nil)
+		cqrs.RegisterCommandHandler[dtos.ApproveKYCCommand, *dtos.UserDTO](cmdBus, &MockReviewKYCUseCase{})
+
+		handler := NewUserHandler(cmdBus, qBus)
+		router := setupUserTestRouter(handler)
+		router.POST("/users/:id/kyc", handler.ReviewKYC)
+
+		body, _ := json.Marshal(map[string]string{"reason": "Blurry passport photo"})
+		req := httptest.NewRequest(http.MethodPost, "/users/"+uuid.New().String()+"/kyc", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("ShortReason", func(t *testing.T) {
+		cmdBus, qBus := buildUserBuses(nil, nil, nil)
+		cqrs.RegisterCommandHandler[dtos.ApproveKYCCommand, *dtos.UserDTO](cmdBus, &MockReviewKYCUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.ApproveKYCCommand) (*dtos.UserDTO, error) {
+				return nil, domainerrors.ValidationError{Field: "reason", Message: "rejection reason must be at least 10 characters"}
+			},
+		})
+
+		handler := NewUserHandler(cmdBus, qBus)
+		router := setupUserTestRouter(handler)
+		router.POST("/users/:id/kyc", handler.ReviewKYC)
+
+		body, _ := json.Marshal(map[string]any{"approved": false, "reason": "blurry"})
+		req := httptest.NewRequest(http.MethodPost, "/users/"+uuid.New().String()+"/kyc", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUserHandler_RevokeKYC(t *testing.T) {
+	newRouter := func(fn func(ctx context.Context, cmd dtos.RevokeKYCCommand) (*dtos.UserDTO, error)) *gin.Engine {
+		cmdBus, qBus := buildUserBuses(nil, nil, nil)
+		cqrs.RegisterCommandHandler[dtos.RevokeKYCCommand, *dtos.UserDTO](cmdBus, &MockRevokeKYCUseCase{ExecuteFn: fn})
+
+		handler := NewUserHandler(cmdBus, qBus)
+		router := setupUserTestRouter(handler)
+		router.POST("/users/:id/kyc/revoke", handler.RevokeKYC)
+		return router
+	}
+	post := func(router *gin.Engine, userID string, body any) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/users/"+userID+"/kyc/revoke", bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		userID := uuid.New().String()
+		var received dtos.RevokeKYCCommand
+		router := newRouter(func(ctx context.Context, cmd dtos.RevokeKYCCommand) (*dtos.UserDTO, error) {
+			received = cmd
+			return &dtos.UserDTO{ID: userID, KYCStatus: "REJECTED", LastKYCRejectionReason: cmd.Reason}, nil
+		})
+
+		w := post(router, userID, map[string]string{"reason": "Sanctions list match"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, userID, received.UserID)
+		assert.Equal(t, "Sanctions list match", received.Reason)
+		assert.Contains(t, w.Body.String(), `"kyc_status":"REJECTED"`)
+	})
+
+	t.Run("ShortReason", func(t *testing.T) {
+		router := newRouter(nil)
+
+		w := post(router, uuid.New().String(), map[string]string{"reason": "fraud"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("NotVerified", func(t *testing.T) {
+		router := newRouter(func(ctx context.Context, cmd dtos.RevokeKYCCommand) (*dtos.UserDTO, error) {
+			return nil, domainerrors.NewBusinessRuleViolation("KYC_NOT_VERIFIED", "only verified KYC can be revoked", nil)
+		})
+
+		w := post(router, uuid.New().String(), map[string]string{"reason": "Sanctions list match"})
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
+
+func TestUserHandler_GetKYCHistory(t *testing.T) {
+	newRouter := func(authUserID, role string) *gin.Engine {
+		cmdBus, qBus := buildUserBuses(nil, nil, nil)
+		cqrs.RegisterQueryHandler[dtos.GetKYCHistoryQuery, *dtos.KYCHistoryDTO](qBus, &MockGetKYCHistoryUseCase{
+			ExecuteFn: func(ctx context.Context, query dtos.GetKYCHistoryQuery) (*dtos.KYCHistoryDTO, error) {
+				return &dtos.KYCHistoryDTO{
+					UserID:    query.UserID,
+					KYCStatus: "REJECTED",
+					Transitions: []dtos.KYCTransitionDTO{
+						{FromStatus: "PENDING", ToStatus: "REJECTED", Reason: "Blurry passport photo", OccurredAt: time.Now()},
+					},
+				}, nil
+			},
+		})
+
+		handler := NewUserHandler(cmdBus, qBus)
+		router := setupUserTestRouter(handler)
+		router.Use(withAuth(authUserID))
+		if role != "" {
+			router.Use(func(c *gin.Context) {
+				httpctx.SetAuthRole(c, role)
+				c.Next()
+			})
+		}
+		router.GET("/users/:id/kyc/history", handler.GetKYCHistory)
+		return router
+	}
+
+	t.Run("Owner", func(t *testing.T) {
+		userID := uuid.New().String()
+		router := newRouter(userID, "")
+
+		req := httptest.NewRequest(http.MethodGet, "/users/"+userID+"/kyc/history", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		data := response["data"].(map[string]interface{})
+		assert.Len(t, data["transitions"], 1)
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		router := newRouter(uuid.New().String(), "admin")
+
+		req := httptest.NewRequest(http.MethodGet, "/users/"+uuid.New().String()+"/kyc/history", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+}
+
+func TestUserHandler_AdminGetUser(t *testing.T) {
+	newRouter := func() *gin.Engine {
+		cmdBus, qBus := buildUserBuses(nil, nil, nil)
+		cqrs.RegisterQueryHandler[dtos.GetAdminUserQuery, *dtos.AdminUserDTO](qBus, &MockGetAdminUserUseCase{
+			ExecuteFn: func(ctx context.Context, query dtos.GetAdminUserQuery) (*dtos.AdminUserDTO, error) {
+				result := &dtos.AdminUserDTO{UserDTO: dtos.UserDTO{ID: query.UserID, Email: "john@example.com"}}
+				if query.IncludeSummary {
+					result.UserActivitySummaryDTO = &dtos.UserActivitySummaryDTO{WalletCount: 0, TotalTransactions: 0}
+				}
+				return result, nil
+			},
+		})
+
+		handler := NewUserHandler(cmdBus, qBus)
+		router := setupUserTestRouter(handler)
+		router.GET("/admin/users/:id", handler.AdminGetUser)
+		return router
+	}
+
+	get := func(t *testing.T, path string) map[string]interface{} {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response["data"].(map[string]interface{})
+	}
+
+	t.Run("WithSummary", func(t *testing.T) {
+		data := get(t, "/admin/users/"+uuid.New().String()+"?include_summary=true")
+
+		assert.Equal(t, "john@example.com", data["email"])
+		assert.Equal(t, float64(0), data["wallet_count"])
+		assert.Equal(t, float64(0), data["total_transactions"])
+		lastActivity, ok := data["last_activity_at"]
+		assert.True(t, ok, "last_activity_at is present")
+		assert.Nil(t, lastActivity, "null without transactions")
+	})
+
+	t.Run("WithoutSummary", func(t *testing.T) {
+		data := get(t, "/admin/users/"+uuid.New().String())
+
+		assert.Equal(t, "john@example.com", data["email"])
+		assert.NotContains(t, data, "wallet_count")
+		assert.NotContains(t, data, "last_activity_at")
+	})
+
+	t.Run("InvalidID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users/not-a-uuid", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 // ============================================
 // Test RegisterRoutes
 // ============================================
 
+func TestUserHandler_OnboardUser(t *testing.T) {
+	partnerID := uuid.New().String()
+	key := uuid.New().String()
+
+	// serve отправляет запрос onboarding от партнёра с заданными capabilities
+	serve := func(useCase *MockOnboardUserUseCase, capabilities []string, body any) *httptest.ResponseRecorder {
+		cmdBus, qBus := buildUserBuses(nil, nil, nil)
+		cqrs.RegisterCommandHandler[dtos.OnboardUserCommand, *dtos.OnboardingResultDTO](cmdBus, useCase)
+
+		handler := NewUserHandler(cmdBus, qBus)
+		router := setupUserTestRouter(handler)
+		router.POST("/onboarding", withAuth(partnerID), func(c *gin.Context) {
+			httpctx.SetAuthCapabilities(c, capabilities)
+			c.Next()
+		}, handler.OnboardUser)
+
+		raw, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/onboarding", bytes.NewBuffer(raw))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	request := map[string]any{
+		"idempotency_key":  key,
+		"email":            "partner-user@example.com",
+		"full_name":        "Partner User",
+		"auto_approve_kyc": true,
+		"wallets": []map[string]string{
+			{"currency_code": "USD", "initial_credit": "100.00"},
+			{"currency_code": "EUR"},
+		},
+	}
+
+	t.Run("TrustedPartner", func(t *testing.T) {
+		var received dtos.OnboardUserCommand
+		w := serve(&MockOnboardUserUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.OnboardUserCommand) (*dtos.OnboardingResultDTO, error) {
+				received = cmd
+				return &dtos.OnboardingResultDTO{User: dtos.UserDTO{KYCStatus: "VERIFIED"}, KYCAutoApproved: true}, nil
+			},
+		}, []string{middleware.CapabilityTrustedPartner}, request)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.True(t, received.TrustedPartnerScope)
+		assert.True(t, received.AutoApproveKYC)
+		assert.Equal(t, key, received.IdempotencyKey)
+		assert.Equal(t, []dtos.OnboardingWalletItem{
+			{CurrencyCode: "USD", InitialCredit: "100.00"},
+			{CurrencyCode: "EUR"},
+		}, received.Wallets)
+		assert.Contains(t, w.Body.String(), `"kyc_auto_approved":true`)
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		var received dtos.OnboardUserCommand
+		w := serve(&MockOnboardUserUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.OnboardUserCommand) (*dtos.OnboardingResultDTO, error) {
+				received = cmd
+				return &dtos.OnboardingResultDTO{IdempotentReplay: true}, nil
+			},
+		}, nil, request)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, received.TrustedPartnerScope)
+	})
+
+	t.Run("AutoApprovalForbidden", func(t *testing.T) {
+		w := serve(&MockOnboardUserUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.OnboardUserCommand) (*dtos.OnboardingResultDTO, error) {
+				return nil, domainerrors.NewDomainError("KYC_AUTO_APPROVAL_FORBIDDEN", "not a trusted partner", nil)
+			},
+		}, nil, request)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("MissingIdempotencyKey", func(t *testing.T) {
+		w := serve(&MockOnboardUserUseCase{}, nil, map[string]any{
+			"email":     "partner-user@example.com",
+			"full_name": "Partner User",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestUserHandler_RegisterRoutes(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
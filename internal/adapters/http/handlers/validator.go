@@ -12,12 +12,18 @@
 package handlers
 
 import (
+	"fmt"
+	"net/http"
 	"reflect"
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
@@ -50,6 +56,8 @@ func SetupValidator() {
 			_ = v.RegisterValidation("kyc_status", validateKYCStatus)
 			_ = v.RegisterValidation("wallet_status", validateWalletStatus)
 			_ = v.RegisterValidation("transaction_type", validateTransactionType)
+			_ = v.RegisterValidation("client_idempotency_key", validateClientIdempotencyKey)
+			_ = v.RegisterValidation("enum_term", validateEnumTerm)
 		}
 	})
 }
@@ -122,20 +130,34 @@ func validateTransactionType(fl validator.FieldLevel) bool {
 	return validTypes[txType]
 }
 
+// validateClientIdempotencyKey отклоняет клиентские ключи с зарезервированным
+// префиксом "sys:" - под ним система выводит ключи своих транзакций.
+func validateClientIdempotencyKey(fl validator.FieldLevel) bool {
+	return !valueobjects.IsSystemIdempotencyKey(fl.Field().String())
+}
+
+// validateEnumTerm проверяет только форму значения перечисления в фильтре
+// списка: значение, неизвестное этой версии (тип из более нового релиза),
+// передаётся в выборку как есть.
+func validateEnumTerm(fl validator.FieldLevel) bool {
+	return entities.IsWellFormedEnum(fl.Field().String())
+}
+
 // ============================================
 // Validation Error Handling
 // ============================================
 
 // HandleValidationErrors преобразует ошибки валидации в HTTP ответ.
+// Ответ содержит все нарушения с путями полей и кодами (REQUIRED, INVALID_FORMAT, ...).
 func HandleValidationErrors(c *gin.Context, err error) {
 	var fieldErrors []common.FieldError
 
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {
 		for _, fieldErr := range validationErrors {
 			fieldErrors = append(fieldErrors, common.FieldError{
-				Field:   fieldErr.Field(),
+				Field:   fieldPath(fieldErr),
 				Message: getValidationMessage(fieldErr),
-				Code:    fieldErr.Tag(),
+				Code:    validationCode(fieldErr.Tag()),
 			})
 		}
 	}
@@ -176,6 +198,10 @@ func getValidationMessage(fe validator.FieldError) string {
 		return "Invalid wallet status"
 	case "transaction_type":
 		return "Invalid transaction type"
+	case "enum_term":
+		return "Invalid value (must be upper-case letters, digits and underscores)"
+	case "client_idempotency_key":
+		return "Idempotency key prefix '" + valueobjects.SystemIdempotencyKeyPrefix + "' is reserved"
 	default:
 		return "Invalid value"
 	}
@@ -187,9 +213,16 @@ func getValidationMessage(fe validator.FieldError) string {
 
 // BindJSON биндит JSON тело запроса и возвращает ошибку если что-то не так.
 // Возвращает true если успешно, false если была ошибка (ответ уже отправлен).
+// Все ошибки полей (неизвестные поля, типы, теги, Validate) отдаются одним
+// ответом, см. request_binding.go.
 func BindJSON[T any](c *gin.Context, req *T) bool {
-	if err := c.ShouldBindJSON(req); err != nil {
-		HandleValidationErrors(c, err)
+	fields, err := bindJSONBody(c, req)
+	if err != nil {
+		common.BadRequestResponse(c, "Invalid request body: "+err.Error())
+		return false
+	}
+	if len(fields) > 0 {
+		common.ValidationErrorResponse(c, fields)
 		return false
 	}
 	return true
@@ -281,3 +314,125 @@ func BuildMeta(params PaginationParams, total int) *common.APIMeta {
 		TotalPages: totalPages,
 	}
 }
+
+// ============================================
+// Timestamp Helpers
+// ============================================
+
+// ParseTimestamp разбирает timestamp от клиента и приводит его к UTC.
+//
+// Принимается только RFC3339 с явной зоной: Z или смещение (+03:00).
+// Форматы без зоны ("2024-01-02T10:00:00", "2024-01-02") неоднозначны -
+// момент времени зависел бы от TZ сервера, поэтому они отклоняются.
+func ParseTimestamp(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("timestamp must be RFC3339 with a time zone (e.g. 2024-01-02T15:04:05Z): %q", value)
+	}
+	return t.UTC(), nil
+}
+
+// TimeRange - диапазон времени из query string: [from, to).
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// CreatedRange - диапазон created_at.
+type CreatedRange = TimeRange
+
+// ParseCreatedRange читает query параметры created_from/created_to.
+// При ошибке отправляет 400 с описанием поля и возвращает false.
+func ParseCreatedRange(c *gin.Context) (CreatedRange, bool) {
+	return ParseTimeRange(c, "created_from", "created_to")
+}
+
+// ParseTimeRange читает пару query параметров [fromField, toField).
+// При ошибке отправляет 400 с описанием поля и возвращает false.
+func ParseTimeRange(c *gin.Context, fromField, toField string) (TimeRange, bool) {
+	var (
+		result TimeRange
+		errs   []common.FieldError
+	)
+
+	parse := func(field string) *time.Time {
+		value := c.Query(field)
+		if value == "" {
+			return nil
+		}
+		t, err := ParseTimestamp(value)
+		if err != nil {
+			errs = append(errs, common.FieldError{Field: field, Message: err.Error(), Code: common.FieldCodeInvalidFormat})
+			return nil
+		}
+		return &t
+	}
+
+	result.From = parse(fromField)
+	result.To = parse(toField)
+
+	if result.From != nil && result.To != nil && !result.From.Before(*result.To) {
+		errs = append(errs, common.FieldError{
+			Field:   toField,
+			Message: toField + " must be after " + fromField,
+			Code:    common.FieldCodeInvalidValue,
+		})
+	}
+
+	if len(errs) > 0 {
+		common.ValidationErrorResponse(c, errs)
+		return TimeRange{}, false
+	}
+	return result, true
+}
+
+// ============================================
+// Sparse Fieldsets Helper
+// ============================================
+
+// ParseFields читает query параметр fields и проверяет его по allow-list ресурса.
+// Возвращает nil FieldSet, если параметр не задан. При неизвестных полях
+// отправляет 400 со списком допустимых и возвращает false.
+func ParseFields(c *gin.Context, allowed []string) (common.FieldSet, bool) {
+	fields, unknown := common.ParseFieldSet(c.Query(common.FieldsQueryParam), allowed)
+	if len(unknown) > 0 {
+		common.InvalidFieldsResponse(c, unknown, allowed)
+		return nil, false
+	}
+	return fields, true
+}
+
+// SuccessList отправляет страницу списка (data = list DTO целиком).
+// Если клиент выбрал поля, элементы под ключом itemsKey заменяются проекцией,
+// остальные поля list DTO (total_count, offset, limit) сохраняются.
+func SuccessList[T any](c *gin.Context, fields common.FieldSet, meta *common.APIMeta, list interface{}, itemsKey string, items []T) {
+	if fields == nil {
+		common.SuccessWithMeta(c, http.StatusOK, list, meta)
+		return
+	}
+
+	data, err := common.ProjectList(list, itemsKey, items, fields)
+	if err != nil {
+		common.InternalErrorResponse(c, "Failed to select fields")
+		return
+	}
+
+	common.SuccessWithMeta(c, http.StatusOK, data, meta)
+}
+
+// SuccessPage отправляет страницу списка в едином конверте
+// {data: [...], pagination: {...}} с учётом sparse fieldset.
+func SuccessPage[T any](c *gin.Context, fields common.FieldSet, page *dtos.Page[T]) {
+	if fields == nil {
+		common.SuccessPage(c, page.Data, page.Pagination)
+		return
+	}
+
+	data, err := common.ProjectFields(page.Data, fields)
+	if err != nil {
+		common.InternalErrorResponse(c, "Failed to select fields")
+		return
+	}
+
+	common.SuccessPage(c, data, page.Pagination)
+}
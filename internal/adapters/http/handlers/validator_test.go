@@ -6,9 +6,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
+	domainerrors "github.com/Haleralex/wallethub/internal/domain/errors"
 )
 
 func init() {
@@ -240,6 +247,88 @@ func TestParsePagination(t *testing.T) {
 	})
 }
 
+func TestParseTimestamp(t *testing.T) {
+	t.Run("ConvertsOffsetToUTC", func(t *testing.T) {
+		ts, err := ParseTimestamp("2024-03-10T12:30:00+03:00")
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.UTC, ts.Location())
+		assert.Equal(t, "2024-03-10T09:30:00Z", ts.Format(time.RFC3339))
+	})
+
+	t.Run("AcceptsZuluAndFraction", func(t *testing.T) {
+		ts, err := ParseTimestamp("2024-03-10T09:30:00.250Z")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 250*time.Millisecond, time.Duration(ts.Nanosecond()))
+	})
+
+	t.Run("RejectsAmbiguousFormats", func(t *testing.T) {
+		for _, value := range []string{
+			"2024-03-10T09:30:00", // без зоны
+			"2024-03-10",          // только дата
+			"2024-03-10 09:30:00Z",
+			"1710063000",
+			"10/03/2024 09:30",
+		} {
+			_, err := ParseTimestamp(value)
+			assert.Error(t, err, value)
+		}
+	})
+}
+
+func TestParseCreatedRange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Empty", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/test", nil)
+
+		createdRange, ok := ParseCreatedRange(c)
+
+		assert.True(t, ok)
+		assert.Nil(t, createdRange.From)
+		assert.Nil(t, createdRange.To)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet,
+			"/test?created_from=2024-03-01T00:00:00%2B02:00&created_to=2024-04-01T00:00:00Z", nil)
+
+		createdRange, ok := ParseCreatedRange(c)
+
+		assert.True(t, ok)
+		assert.Equal(t, "2024-02-29T22:00:00Z", createdRange.From.Format(time.RFC3339))
+		assert.Equal(t, "2024-04-01T00:00:00Z", createdRange.To.Format(time.RFC3339))
+	})
+
+	t.Run("NaiveTimestamp", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/test?created_from=2024-03-01T00:00:00", nil)
+
+		_, ok := ParseCreatedRange(c)
+
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "created_from")
+	})
+
+	t.Run("InvertedRange", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet,
+			"/test?created_from=2024-04-01T00:00:00Z&created_to=2024-03-01T00:00:00Z", nil)
+
+		_, ok := ParseCreatedRange(c)
+
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "created_to")
+	})
+}
+
 func TestBuildMeta(t *testing.T) {
 	t.Run("FullPages", func(t *testing.T) {
 		params := PaginationParams{Page: 1, PerPage: 20}
@@ -283,7 +372,7 @@ func TestBindJSON(t *testing.T) {
 		body := []byte(`{"name":"John","email":"john@example.com"}`)
 		c.Request = httptest.NewRequest(http.MethodPost, "/test", bytes.NewBuffer(body))
 		c.Request.Header.Set("Content-Type", "application/json")
-		c.Set("X-Request-ID", "test-123")
+		httpctx.SetRequestID(c, "test-123")
 
 		var req TestRequest
 		result := BindJSON(c, &req)
@@ -298,13 +387,120 @@ func TestBindJSON(t *testing.T) {
 		body := []byte(`{"name":"John"}`) // Missing email
 		c.Request = httptest.NewRequest(http.MethodPost, "/test", bytes.NewBuffer(body))
 		c.Request.Header.Set("Content-Type", "application/json")
-		c.Set("X-Request-ID", "test-123")
+		httpctx.SetRequestID(c, "test-123")
 
 		var req TestRequest
 		result := BindJSON(c, &req)
 
 		assert.False(t, result)
 		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []common.FieldError{
+			{Field: "email", Message: "This field is required", Code: common.FieldCodeRequired},
+		}, decodeFieldErrors(t, w))
+	})
+
+	t.Run("MalformedJSON", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := newBindContext(w, `{"name":`)
+
+		var req TestRequest
+		assert.False(t, BindJSON(c, &req))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), common.ErrCodeBadRequest)
+	})
+}
+
+// bindItem / bindBatchRequest - вложенный запрос для проверки путей полей.
+type bindItem struct {
+	IdempotencyKey string `json:"idempotency_key" binding:"required,uuid"`
+	Amount         string `json:"amount" binding:"required,money_amount"`
+}
+
+type bindBatchRequest struct {
+	Currency string            `json:"currency" binding:"required,len=3"`
+	Count    int               `json:"count" binding:"min=1,max=10"`
+	Items    []bindItem        `json:"items" binding:"required,dive"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+func (r *bindBatchRequest) Validate() domainerrors.ValidationErrors {
+	var errs domainerrors.ValidationErrors
+	if r.Currency == "XXX" {
+		errs.AddCode("currency", domainerrors.ValidationCodeInvalidValue, "Unsupported currency")
+	}
+	return errs
+}
+
+func newBindContext(w *httptest.ResponseRecorder, body string) *gin.Context {
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	httpctx.SetRequestID(c, "test-123")
+	return c
+}
+
+func decodeFieldErrors(t *testing.T, w *httptest.ResponseRecorder) []common.FieldError {
+	t.Helper()
+
+	var resp common.APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, common.ErrCodeValidation, resp.Error.Code)
+	return resp.Error.Fields
+}
+
+func TestBindJSON_ReportsAllFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("NestedPathsAndUnknownFields", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := newBindContext(w, `{
+			"currency": "XXX",
+			"count": 0,
+			"items": [
+				{"idempotency_key": "`+uuid.NewString()+`", "amount": "10.00"},
+				{"idempotency_key": "not-a-uuid", "amount": "10.00", "memo": "x"},
+				{"amount": "1.2.3"}
+			],
+			"extra": true
+		}`)
+
+		var req bindBatchRequest
+		assert.False(t, BindJSON(c, &req))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []common.FieldError{
+			{Field: "extra", Message: "Unknown field", Code: common.FieldCodeUnknownField},
+			{Field: "items[1].memo", Message: "Unknown field", Code: common.FieldCodeUnknownField},
+			{Field: "count", Message: "Value is too short (minimum: 1)", Code: common.FieldCodeOutOfRange},
+			{Field: "items[1].idempotency_key", Message: "Invalid UUID format", Code: common.FieldCodeInvalidFormat},
+			{Field: "items[2].idempotency_key", Message: "This field is required", Code: common.FieldCodeRequired},
+			{Field: "items[2].amount", Message: "Invalid amount format (use decimal like '100.50')", Code: common.FieldCodeInvalidFormat},
+			{Field: "currency", Message: "Unsupported currency", Code: common.FieldCodeInvalidValue},
+		}, decodeFieldErrors(t, w))
+	})
+
+	t.Run("TypeMismatchReportedOncePerField", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := newBindContext(w, `{"currency": 840, "count": "3", "items": {}, "metadata": {"a": 1}}`)
+
+		var req bindBatchRequest
+		assert.False(t, BindJSON(c, &req))
+		assert.Equal(t, []common.FieldError{
+			{Field: "count", Message: "Expected number, got string", Code: common.FieldCodeInvalidType},
+			{Field: "currency", Message: "Expected string, got number", Code: common.FieldCodeInvalidType},
+			{Field: "items", Message: "Expected array, got object", Code: common.FieldCodeInvalidType},
+			{Field: "metadata[a]", Message: "Expected string, got number", Code: common.FieldCodeInvalidType},
+		}, decodeFieldErrors(t, w))
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := newBindContext(w, `{"Currency": "USD", "count": 1, "items": [{"idempotency_key": "`+uuid.NewString()+`", "amount": "1"}]}`)
+
+		var req bindBatchRequest
+		assert.True(t, BindJSON(c, &req))
+		assert.Equal(t, "USD", req.Currency)
+		assert.Len(t, req.Items, 1)
 	})
 }
 
@@ -318,7 +514,7 @@ func TestBindURI(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		router := gin.New()
 		router.GET("/users/:id", func(c *gin.Context) {
-			c.Set("X-Request-ID", "test-123")
+			httpctx.SetRequestID(c, "test-123")
 			var params URIParams
 			if BindURI(c, &params) {
 				c.JSON(200, gin.H{"id": params.ID})
@@ -336,7 +532,7 @@ func TestBindURI(t *testing.T) {
 	t.Run("InvalidUUID", func(t *testing.T) {
 		router := gin.New()
 		router.GET("/users/:id", func(c *gin.Context) {
-			c.Set("X-Request-ID", "test-123")
+			httpctx.SetRequestID(c, "test-123")
 			var params URIParams
 			if !BindURI(c, &params) {
 				return
@@ -496,7 +692,7 @@ func TestBindQuery(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		router := gin.New()
 		router.GET("/test", func(c *gin.Context) {
-			c.Set("X-Request-ID", "test-123")
+			httpctx.SetRequestID(c, "test-123")
 			var params QueryParams
 			if BindQuery(c, &params) {
 				c.JSON(200, gin.H{"status": params.Status, "page": params.Page})
@@ -514,7 +710,7 @@ func TestBindQuery(t *testing.T) {
 	t.Run("MissingRequired", func(t *testing.T) {
 		router := gin.New()
 		router.GET("/test", func(c *gin.Context) {
-			c.Set("X-Request-ID", "test-123")
+			httpctx.SetRequestID(c, "test-123")
 			var params QueryParams
 			if !BindQuery(c, &params) {
 				return
@@ -585,7 +781,9 @@ func TestGetValidationMessage(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.Contains(t, w.Body.String(), "email")
+		assert.Equal(t, []common.FieldError{
+			{Field: "email", Message: "Invalid email format", Code: common.FieldCodeInvalidFormat},
+		}, decodeFieldErrors(t, w))
 	})
 
 	t.Run("MinValidation", func(t *testing.T) {
@@ -597,6 +795,8 @@ func TestGetValidationMessage(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.Contains(t, w.Body.String(), "short")
+		assert.Equal(t, []common.FieldError{
+			{Field: "name", Message: "Value is too short (minimum: 2)", Code: common.FieldCodeOutOfRange},
+		}, decodeFieldErrors(t, w))
 	})
 }
@@ -2,12 +2,16 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/Haleralex/wallethub/internal/adapters/http/common"
-	"github.com/Haleralex/wallethub/internal/adapters/http/middleware"
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
 	"github.com/Haleralex/wallethub/internal/application/cqrs"
 	"github.com/Haleralex/wallethub/internal/application/dtos"
+	domainerrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -21,6 +25,10 @@ import (
 type WalletHandler struct {
 	commandBus *cqrs.CommandBus
 	queryBus   *cqrs.QueryBus
+
+	// legacyListShape - отдавать списки кошельков в прежней форме
+	// {wallets, total_count, offset, limit} + meta
+	legacyListShape bool
 }
 
 // NewWalletHandler создаёт новый WalletHandler.
@@ -31,6 +39,33 @@ func NewWalletHandler(commandBus *cqrs.CommandBus, queryBus *cqrs.QueryBus) *Wal
 	}
 }
 
+// WithLegacyListShape включает прежнюю форму списков кошельков вместо
+// конверта {data, pagination}. Переходный режим на один релиз.
+func (h *WalletHandler) WithLegacyListShape(enabled bool) *WalletHandler {
+	h.legacyListShape = enabled
+	return h
+}
+
+// legacyWalletList - форма списка кошельков до единого конверта страниц.
+//
+// Deprecated: отдаётся только при app.legacy_wallet_list_shape и будет
+// удалена в следующем релизе.
+type legacyWalletList struct {
+	Wallets    []dtos.WalletDTO `json:"wallets"`
+	TotalCount int              `json:"total_count"`
+	Offset     int              `json:"offset"`
+	Limit      int              `json:"limit"`
+}
+
+func newLegacyWalletList(page *dtos.WalletListDTO, query dtos.ListWalletsQuery) legacyWalletList {
+	return legacyWalletList{
+		Wallets:    page.Data,
+		TotalCount: len(page.Data),
+		Offset:     query.Offset,
+		Limit:      query.Limit,
+	}
+}
+
 // ============================================
 // Request DTOs
 // ============================================
@@ -42,12 +77,22 @@ type CreateWalletRequest struct {
 	CurrencyCode string `json:"currency_code" binding:"required,len=3,currency_code"`
 }
 
+// Validate проверяет, что валюта поддерживается (тег проверяет только формат).
+func (r *CreateWalletRequest) Validate() domainerrors.ValidationErrors {
+	var errs domainerrors.ValidationErrors
+	if _, err := valueobjects.NewCurrency(r.CurrencyCode); err != nil {
+		errs.AddCode("currency_code", domainerrors.ValidationCodeInvalidValue, "Unsupported currency")
+	}
+	return errs
+}
+
 // CreditWalletRequest - запрос на пополнение кошелька.
 //
 // @Description Credit wallet request body
 type CreditWalletRequest struct {
-	Amount            string `json:"amount" binding:"required,money_amount"`
-	IdempotencyKey    string `json:"idempotency_key" binding:"required,uuid"`
+	Amount            string `json:"amount,omitempty" binding:"omitempty,money_amount"`
+	AmountMinor       *int64 `json:"amount_minor,omitempty" binding:"omitempty,min=0"`
+	IdempotencyKey    string `json:"idempotency_key" binding:"required,client_idempotency_key,uuid"`
 	Description       string `json:"description" binding:"required,min=1,max=500"`
 	ExternalReference string `json:"external_reference,omitempty"`
 }
@@ -56,8 +101,9 @@ type CreditWalletRequest struct {
 //
 // @Description Debit wallet request body
 type DebitWalletRequest struct {
-	Amount            string `json:"amount" binding:"required,money_amount"`
-	IdempotencyKey    string `json:"idempotency_key" binding:"required,uuid"`
+	Amount            string `json:"amount,omitempty" binding:"omitempty,money_amount"`
+	AmountMinor       *int64 `json:"amount_minor,omitempty" binding:"omitempty,min=0"`
+	IdempotencyKey    string `json:"idempotency_key" binding:"required,client_idempotency_key,uuid"`
 	Description       string `json:"description" binding:"required,min=1,max=500"`
 	ExternalReference string `json:"external_reference,omitempty"`
 }
@@ -67,16 +113,105 @@ type DebitWalletRequest struct {
 // @Description Transfer funds request body
 type TransferFundsRequest struct {
 	DestinationWalletID string `json:"destination_wallet_id" binding:"required,uuid"`
-	Amount              string `json:"amount" binding:"required,money_amount"`
-	IdempotencyKey      string `json:"idempotency_key" binding:"required,uuid"`
+	Amount              string `json:"amount,omitempty" binding:"omitempty,money_amount"`
+	AmountMinor         *int64 `json:"amount_minor,omitempty" binding:"omitempty,min=0"`
+	IdempotencyKey      string `json:"idempotency_key" binding:"required,client_idempotency_key,uuid"`
 	Description         string `json:"description" binding:"required,min=1,max=500"`
+	ExternalReference   string `json:"external_reference,omitempty"`
+	ConfirmNewPayee     bool   `json:"confirm_new_payee" example:"false"` // подтверждение первого перевода новому получателю
+}
+
+// UpdateWalletSettingsRequest - настройки представления кошелька (заменяются целиком).
+//
+// @Description Update wallet settings request body
+type UpdateWalletSettingsRequest struct {
+	IncomingDescriptionTemplate string   `json:"incoming_description_template" binding:"max=200" example:"order {external_reference}"`
+	AutoTags                    []string `json:"auto_tags" binding:"max=10"`
+	DisplayCurrency             string   `json:"display_currency" binding:"omitempty,max=10" example:"USD"` // пересчёт балансов для показа, пусто - выключен
+}
+
+// BulkTransferRequest - пакет переводов с одного кошелька.
+//
+// @Description Bulk transfer request body
+type BulkTransferRequest struct {
+	Transfers []BulkTransferItemRequest `json:"transfers" binding:"required,min=1,dive"`
+}
+
+// BulkTransferItemRequest - один перевод пакета.
+//
+// @Description Bulk transfer instruction
+type BulkTransferItemRequest struct {
+	DestinationWalletID string `json:"destination_wallet_id" binding:"required,uuid"`
+	Amount              string `json:"amount,omitempty" binding:"omitempty,money_amount"`
+	AmountMinor         *int64 `json:"amount_minor,omitempty" binding:"omitempty,min=0"`
+	IdempotencyKey      string `json:"idempotency_key" binding:"required,client_idempotency_key,uuid"`
+	Reference           string `json:"reference" binding:"required,min=1,max=500"`
+	ConfirmNewPayee     bool   `json:"confirm_new_payee" example:"false"`
+}
+
+// Validate проверяет суммы переводов, см. validateRequestAmount.
+func (r *BulkTransferRequest) Validate() domainerrors.ValidationErrors {
+	var errs domainerrors.ValidationErrors
+	for i, item := range r.Transfers {
+		for _, e := range validateRequestAmount(item.Amount, item.AmountMinor) {
+			errs.AddCode(fmt.Sprintf("transfers[%d].%s", i, e.Field), e.Code, e.Message)
+		}
+	}
+	return errs
 }
 
 // ExchangeCurrencyRequest - запрос на обмен валюты.
 type ExchangeCurrencyRequest struct {
 	DestinationWalletID string `json:"destination_wallet_id" binding:"required,uuid"`
-	Amount              string `json:"amount" binding:"required,money_amount"`
-	IdempotencyKey      string `json:"idempotency_key" binding:"required,uuid"`
+	Amount              string `json:"amount,omitempty" binding:"omitempty,money_amount"`
+	AmountMinor         *int64 `json:"amount_minor,omitempty" binding:"omitempty,min=0"`
+	IdempotencyKey      string `json:"idempotency_key" binding:"required,client_idempotency_key,uuid"`
+}
+
+// Validate проверяет сумму, см. validateRequestAmount.
+func (r *CreditWalletRequest) Validate() domainerrors.ValidationErrors {
+	return validateRequestAmount(r.Amount, r.AmountMinor)
+}
+
+// Validate проверяет сумму, см. validateRequestAmount.
+func (r *DebitWalletRequest) Validate() domainerrors.ValidationErrors {
+	return validateRequestAmount(r.Amount, r.AmountMinor)
+}
+
+// Validate проверяет сумму, см. validateRequestAmount.
+func (r *TransferFundsRequest) Validate() domainerrors.ValidationErrors {
+	return validateRequestAmount(r.Amount, r.AmountMinor)
+}
+
+// Validate проверяет сумму, см. validateRequestAmount.
+func (r *ExchangeCurrencyRequest) Validate() domainerrors.ValidationErrors {
+	return validateRequestAmount(r.Amount, r.AmountMinor)
+}
+
+// validateRequestAmount: сумма задаётся десятичной строкой amount ("100.50")
+// или целым числом минорных единиц amount_minor (10050 центов, сатоши для
+// BTC), но не обоими сразу.
+func validateRequestAmount(amount string, amountMinor *int64) domainerrors.ValidationErrors {
+	var errs domainerrors.ValidationErrors
+	dtos.CheckCommandAmount(&errs, amount, amountMinor)
+	return errs
+}
+
+// EnsureWalletRequest - желаемые лимиты кошелька для PUT /users/:id/wallets/:currency.
+// Пропущенный лимит не управляется. MaxPendingTransactions меняет только
+// админ; 0 возвращает лимит по умолчанию.
+//
+// @Description Ensure wallet request body
+type EnsureWalletRequest struct {
+	DailyLimit             string `json:"daily_limit,omitempty" binding:"omitempty,money_amount"`
+	MonthlyLimit           string `json:"monthly_limit,omitempty" binding:"omitempty,money_amount"`
+	MaxPendingTransactions *int   `json:"max_pending_transactions,omitempty" binding:"omitempty,min=0"`
+}
+
+// EnsureWalletParams - параметры пути ensure кошелька.
+type EnsureWalletParams struct {
+	UserID       string `uri:"id" binding:"required,uuid"`
+	CurrencyCode string `uri:"currency" binding:"required,len=3"`
 }
 
 // WalletIDParam - параметр ID кошелька из URL.
@@ -84,39 +219,36 @@ type WalletIDParam struct {
 	ID string `uri:"id" binding:"required,uuid"`
 }
 
+// GetWalletParams - query-параметры получения кошелька.
+type GetWalletParams struct {
+	IncludeUsage bool `form:"include_usage"`
+}
+
+// CloseWalletParams - query-параметры закрытия кошелька.
+type CloseWalletParams struct {
+	SweepTo         string `form:"sweep_to" binding:"required,uuid"`
+	ConfirmNewPayee bool   `form:"confirm_new_payee"` // подтверждение первого перевода новому получателю
+}
+
+// ActivityHeatmapParams - query-параметры heatmap активности.
+type ActivityHeatmapParams struct {
+	Days     int    `form:"days" binding:"omitempty,min=1,max=730"` // По умолчанию 365
+	Timezone string `form:"tz"`                                     // IANA, по умолчанию UTC
+}
+
 // ListWalletsParams - параметры для списка кошельков.
 type ListWalletsParams struct {
 	UserID       string `form:"user_id" binding:"omitempty,uuid"`
 	CurrencyCode string `form:"currency_code" binding:"omitempty,len=3"`
-	Status       string `form:"status" binding:"omitempty,oneof=ACTIVE SUSPENDED LOCKED CLOSED"`
+	Status       string `form:"status" binding:"omitempty,enum_term"`
 }
 
-// ============================================
-// Ownership Verification
-// ============================================
-
-// checkWalletOwnership verifies the authenticated user owns the given wallet.
-// Returns true if ownership is confirmed, false if an error response was sent.
-func (h *WalletHandler) checkWalletOwnership(c *gin.Context, walletID string) bool {
-	authUserID := middleware.GetAuthUserID(c)
-	if authUserID == uuid.Nil {
-		common.UnauthorizedResponse(c, "User not authenticated")
-		return false
-	}
-
-	query := dtos.GetWalletQuery{WalletID: walletID}
-	wallet, err := cqrs.DispatchQuery[dtos.GetWalletQuery, *dtos.WalletDTO](h.queryBus, c.Request.Context(), query)
-	if err != nil {
-		common.HandleDomainError(c, err)
-		return false
-	}
-
-	if wallet.UserID != authUserID.String() {
-		common.ForbiddenResponse(c, "You do not have access to this wallet")
-		return false
-	}
-
-	return true
+// walletListFields - поля кошелька, доступные для выбора через ?fields=.
+var walletListFields = []string{
+	"id", "user_id", "currency_code", "wallet_type", "status",
+	"available_balance", "pending_balance", "total_balance",
+	"daily_limit", "monthly_limit", "balance_version", "jurisdiction",
+	"created_at", "updated_at", "max_pending_transactions",
 }
 
 // ============================================
@@ -139,8 +271,8 @@ func (h *WalletHandler) checkWalletOwnership(c *gin.Context, walletID string) bo
 // @Failure 500 {object} common.APIResponse
 // @Router /api/v1/wallets [post]
 func (h *WalletHandler) CreateWallet(c *gin.Context) {
-	authUserID := middleware.GetAuthUserID(c)
-	if authUserID == uuid.Nil {
+	authUserID, ok := httpctx.AuthUserID(c)
+	if !ok {
 		common.UnauthorizedResponse(c, "User not authenticated")
 		return
 	}
@@ -172,11 +304,17 @@ func (h *WalletHandler) CreateWallet(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Wallet ID" format(uuid)
+// @Param include_usage query bool false "Include pending transactions usage"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} common.APIResponse{data=dtos.WalletDTO}
+// @Header 200 {string} ETag "Wallet version"
+// @Success 304 "Not Modified"
 // @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
 // @Failure 404 {object} common.APIResponse
 // @Failure 500 {object} common.APIResponse
 // @Router /api/v1/wallets/{id} [get]
+// @Router /api/v1/wallets/{id} [head]
 func (h *WalletHandler) GetWallet(c *gin.Context) {
 	var params WalletIDParam
 	if !BindURI(c, &params) {
@@ -185,17 +323,17 @@ func (h *WalletHandler) GetWallet(c *gin.Context) {
 
 	if _, err := uuid.Parse(params.ID); err != nil {
 		common.ValidationErrorResponse(c, []common.FieldError{
-			{Field: "id", Message: "Invalid UUID format", Code: "uuid"},
+			{Field: "id", Message: "Invalid UUID format", Code: common.FieldCodeInvalidFormat},
 		})
 		return
 	}
 
-	// Ownership check: only wallet owner can view
-	if !h.checkWalletOwnership(c, params.ID) {
+	var options GetWalletParams
+	if !BindQuery(c, &options) {
 		return
 	}
 
-	query := dtos.GetWalletQuery{WalletID: params.ID}
+	query := dtos.GetWalletQuery{WalletID: params.ID, IncludeUsage: options.IncludeUsage}
 
 	result, err := cqrs.DispatchQuery[dtos.GetWalletQuery, *dtos.WalletDTO](h.queryBus, c.Request.Context(), query)
 	if err != nil {
@@ -203,7 +341,56 @@ func (h *WalletHandler) GetWallet(c *gin.Context) {
 		return
 	}
 
-	common.Success(c, http.StatusOK, result)
+	common.SuccessWithETag(c, walletETag(result), result)
+}
+
+// GetWalletBalance возвращает только балансы кошелька.
+//
+// Быстрый путь для клиентов, которые опрашивают баланс: кошелёк не
+// загружается целиком. Доступ проверяет политика маршрута (владелец или
+// администратор).
+// ETag - balance_version (и курс пересчёта в валюту отображения), поэтому
+// If-None-Match даёт 304, пока баланс не изменился.
+//
+// @Summary Get wallet balance
+// @Description Only the balances of a wallet; cheaper than GET /wallets/{id}
+// @Tags Wallets
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID" format(uuid)
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} common.APIResponse{data=dtos.WalletBalanceDTO}
+// @Header 200 {string} ETag "Balance version"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/wallets/{id}/balance [get]
+// @Router /api/v1/wallets/{id}/balance [head]
+func (h *WalletHandler) GetWalletBalance(c *gin.Context) {
+	var params WalletIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	if _, err := uuid.Parse(params.ID); err != nil {
+		common.ValidationErrorResponse(c, []common.FieldError{
+			{Field: "id", Message: "Invalid UUID format", Code: common.FieldCodeInvalidFormat},
+		})
+		return
+	}
+
+	query := dtos.GetWalletBalanceQuery{WalletID: params.ID}
+
+	result, err := cqrs.DispatchQuery[dtos.GetWalletBalanceQuery, *dtos.WalletBalanceDTO](h.queryBus, c.Request.Context(), query)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	parts := append([]string{strconv.FormatInt(result.BalanceVersion, 10)}, convertedETagParts(result.Converted)...)
+	common.SuccessWithETag(c, common.StrongETag(parts...), result)
 }
 
 // ListWallets возвращает список кошельков с фильтрацией.
@@ -218,7 +405,8 @@ func (h *WalletHandler) GetWallet(c *gin.Context) {
 // @Param user_id query string false "Filter by user ID" format(uuid)
 // @Param currency_code query string false "Filter by currency code"
 // @Param status query string false "Filter by status" Enums(ACTIVE, SUSPENDED, LOCKED, CLOSED)
-// @Success 200 {object} common.APIResponse{data=dtos.WalletListDTO}
+// @Param fields query string false "Comma-separated fields to return (id is always included)" example(id,status,available_balance)
+// @Success 200 {object} common.APIResponse{data=[]dtos.WalletDTO,pagination=dtos.PaginationDTO}
 // @Failure 400 {object} common.APIResponse
 // @Failure 500 {object} common.APIResponse
 // @Router /api/v1/wallets [get]
@@ -230,6 +418,11 @@ func (h *WalletHandler) ListWallets(c *gin.Context) {
 		return
 	}
 
+	fields, ok := ParseFields(c, walletListFields)
+	if !ok {
+		return
+	}
+
 	query := dtos.ListWalletsQuery{
 		Offset: pagination.Offset(),
 		Limit:  pagination.PerPage,
@@ -251,8 +444,13 @@ func (h *WalletHandler) ListWallets(c *gin.Context) {
 		return
 	}
 
-	meta := BuildMeta(pagination, result.TotalCount)
-	common.SuccessWithMeta(c, http.StatusOK, result, meta)
+	if h.legacyListShape {
+		list := newLegacyWalletList(result, query)
+		SuccessList(c, fields, BuildMeta(pagination, list.TotalCount), list, "wallets", list.Wallets)
+		return
+	}
+
+	SuccessPage(c, fields, result)
 }
 
 // CreditWallet пополняет кошелёк.
@@ -264,11 +462,13 @@ func (h *WalletHandler) ListWallets(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Wallet ID" format(uuid)
 // @Param request body CreditWalletRequest true "Credit data"
-// @Success 200 {object} common.APIResponse{data=dtos.WalletOperationDTO}
+// @Success 201 {object} common.APIResponse{data=dtos.WalletOperationDTO}
+// @Success 200 {object} common.APIResponse{data=dtos.WalletOperationDTO} "Idempotent replay"
 // @Failure 400 {object} common.APIResponse
 // @Failure 404 {object} common.APIResponse "Wallet not found"
 // @Failure 409 {object} common.APIResponse "Concurrency error"
 // @Failure 422 {object} common.APIResponse "Wallet not active"
+// @Failure 429 {object} common.APIResponse "Wallet busy"
 // @Failure 500 {object} common.APIResponse
 // @Router /api/v1/wallets/{id}/credit [post]
 func (h *WalletHandler) CreditWallet(c *gin.Context) {
@@ -277,11 +477,6 @@ func (h *WalletHandler) CreditWallet(c *gin.Context) {
 		return
 	}
 
-	// Ownership check: only wallet owner can credit
-	if !h.checkWalletOwnership(c, params.ID) {
-		return
-	}
-
 	var req CreditWalletRequest
 	if !BindJSON(c, &req) {
 		return
@@ -290,6 +485,7 @@ func (h *WalletHandler) CreditWallet(c *gin.Context) {
 	cmd := dtos.CreditWalletCommand{
 		WalletID:          params.ID,
 		Amount:            req.Amount,
+		AmountMinor:       req.AmountMinor,
 		IdempotencyKey:    req.IdempotencyKey,
 		Description:       req.Description,
 		ExternalReference: req.ExternalReference,
@@ -301,7 +497,7 @@ func (h *WalletHandler) CreditWallet(c *gin.Context) {
 		return
 	}
 
-	common.Success(c, http.StatusOK, result)
+	common.Success(c, operationStatus(result.IdempotentReplay), result)
 }
 
 // DebitWallet списывает средства с кошелька.
@@ -313,11 +509,13 @@ func (h *WalletHandler) CreditWallet(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Wallet ID" format(uuid)
 // @Param request body DebitWalletRequest true "Debit data"
-// @Success 200 {object} common.APIResponse{data=dtos.WalletOperationDTO}
+// @Success 201 {object} common.APIResponse{data=dtos.WalletOperationDTO}
+// @Success 200 {object} common.APIResponse{data=dtos.WalletOperationDTO} "Idempotent replay"
 // @Failure 400 {object} common.APIResponse
 // @Failure 404 {object} common.APIResponse "Wallet not found"
 // @Failure 409 {object} common.APIResponse "Concurrency error"
 // @Failure 422 {object} common.APIResponse "Insufficient balance"
+// @Failure 429 {object} common.APIResponse "Wallet busy"
 // @Failure 500 {object} common.APIResponse
 // @Router /api/v1/wallets/{id}/debit [post]
 func (h *WalletHandler) DebitWallet(c *gin.Context) {
@@ -326,11 +524,6 @@ func (h *WalletHandler) DebitWallet(c *gin.Context) {
 		return
 	}
 
-	// Ownership check: only wallet owner can debit
-	if !h.checkWalletOwnership(c, params.ID) {
-		return
-	}
-
 	var req DebitWalletRequest
 	if !BindJSON(c, &req) {
 		return
@@ -339,6 +532,7 @@ func (h *WalletHandler) DebitWallet(c *gin.Context) {
 	cmd := dtos.DebitWalletCommand{
 		WalletID:          params.ID,
 		Amount:            req.Amount,
+		AmountMinor:       req.AmountMinor,
 		IdempotencyKey:    req.IdempotencyKey,
 		Description:       req.Description,
 		ExternalReference: req.ExternalReference,
@@ -350,7 +544,7 @@ func (h *WalletHandler) DebitWallet(c *gin.Context) {
 		return
 	}
 
-	common.Success(c, http.StatusOK, result)
+	common.Success(c, operationStatus(result.IdempotentReplay), result)
 }
 
 // Transfer переводит средства между кошельками.
@@ -362,11 +556,13 @@ func (h *WalletHandler) DebitWallet(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Source Wallet ID" format(uuid)
 // @Param request body TransferFundsRequest true "Transfer data"
-// @Success 200 {object} common.APIResponse{data=dtos.TransferResultDTO}
+// @Success 201 {object} common.APIResponse{data=dtos.TransferResultDTO}
+// @Success 200 {object} common.APIResponse{data=dtos.TransferResultDTO} "Idempotent replay"
 // @Failure 400 {object} common.APIResponse
 // @Failure 404 {object} common.APIResponse "Wallet not found"
 // @Failure 409 {object} common.APIResponse "Concurrency error"
-// @Failure 422 {object} common.APIResponse "Insufficient balance or currency mismatch"
+// @Failure 422 {object} common.APIResponse "Insufficient balance, currency mismatch or new payee restriction"
+// @Failure 429 {object} common.APIResponse "Wallet busy"
 // @Failure 500 {object} common.APIResponse
 // @Router /api/v1/wallets/{id}/transfer [post]
 func (h *WalletHandler) Transfer(c *gin.Context) {
@@ -375,11 +571,6 @@ func (h *WalletHandler) Transfer(c *gin.Context) {
 		return
 	}
 
-	// Ownership check: only source wallet owner can transfer
-	if !h.checkWalletOwnership(c, params.ID) {
-		return
-	}
-
 	var req TransferFundsRequest
 	if !BindJSON(c, &req) {
 		return
@@ -389,8 +580,11 @@ func (h *WalletHandler) Transfer(c *gin.Context) {
 		SourceWalletID:      params.ID,
 		DestinationWalletID: req.DestinationWalletID,
 		Amount:              req.Amount,
+		AmountMinor:         req.AmountMinor,
 		IdempotencyKey:      req.IdempotencyKey,
 		Description:         req.Description,
+		ExternalReference:   req.ExternalReference,
+		ConfirmNewPayee:     req.ConfirmNewPayee,
 	}
 
 	result, err := cqrs.DispatchCommand[dtos.TransferFundsCommand, *dtos.TransferResultDTO](h.commandBus, c.Request.Context(), cmd)
@@ -399,17 +593,203 @@ func (h *WalletHandler) Transfer(c *gin.Context) {
 		return
 	}
 
+	common.Success(c, operationStatus(result.IdempotentReplay), result)
+}
+
+// UpdateSettings заменяет настройки представления кошелька.
+//
+// @Summary Update wallet settings
+// @Description Replace the incoming transfer description template, auto tags and display currency of a wallet. Template and tags apply to operations made after the update
+// @Tags Wallets
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID" format(uuid)
+// @Param request body UpdateWalletSettingsRequest true "Wallet settings"
+// @Success 200 {object} common.APIResponse{data=dtos.WalletSettingsDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse "Wallet not found"
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/wallets/{id}/settings [put]
+func (h *WalletHandler) UpdateSettings(c *gin.Context) {
+	var params WalletIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	var req UpdateWalletSettingsRequest
+	if !BindJSON(c, &req) {
+		return
+	}
+
+	cmd := dtos.UpdateWalletSettingsCommand{
+		WalletID:                    params.ID,
+		IncomingDescriptionTemplate: req.IncomingDescriptionTemplate,
+		AutoTags:                    req.AutoTags,
+		DisplayCurrency:             req.DisplayCurrency,
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.UpdateWalletSettingsCommand, *dtos.WalletSettingsDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
 	common.Success(c, http.StatusOK, result)
 }
 
-// ExchangeCurrency обрабатывает обмен валюты между кошельками пользователя.
-func (h *WalletHandler) ExchangeCurrency(c *gin.Context) {
+// BulkTransfer выполняет пакет переводов с кошелька (выплата зарплат).
+// Переводы независимы: ответ перечисляет статус каждого и общий статус
+// COMPLETED, PARTIAL или FAILED. Повтор пакета пропускает выполненные
+// переводы по idempotency_key.
+//
+// @Summary Bulk transfer from a wallet
+// @Description Execute up to transactions.bulk_max_items transfers from the source wallet; each transfer succeeds or fails on its own
+// @Tags Wallets
+// @Accept json
+// @Produce json
+// @Param id path string true "Source Wallet ID" format(uuid)
+// @Param request body BulkTransferRequest true "Transfer instructions"
+// @Success 200 {object} common.APIResponse{data=dtos.BulkTransferResultDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse "Wallet not found"
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/wallets/{id}/transfers/bulk [post]
+func (h *WalletHandler) BulkTransfer(c *gin.Context) {
 	var params WalletIDParam
 	if !BindURI(c, &params) {
 		return
 	}
 
-	if !h.checkWalletOwnership(c, params.ID) {
+	var req BulkTransferRequest
+	if !BindJSON(c, &req) {
+		return
+	}
+
+	cmd := dtos.BulkTransferCommand{
+		SourceWalletID: params.ID,
+		Transfers:      make([]dtos.BulkTransferItem, len(req.Transfers)),
+	}
+	for i, item := range req.Transfers {
+		cmd.Transfers[i] = dtos.BulkTransferItem{
+			DestinationWalletID: item.DestinationWalletID,
+			Amount:              item.Amount,
+			AmountMinor:         item.AmountMinor,
+			IdempotencyKey:      item.IdempotencyKey,
+			Reference:           item.Reference,
+			ConfirmNewPayee:     item.ConfirmNewPayee,
+		}
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.BulkTransferCommand, *dtos.BulkTransferResultDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// CloseWallet закрывает кошелёк, переводя остаток на другой кошелёк.
+//
+// @Summary Close wallet with balance sweep
+// @Description Lock the wallet, move its full available balance to sweep_to and close it atomically
+// @Tags Wallets
+// @Produce json
+// @Param id path string true "Wallet ID" format(uuid)
+// @Param sweep_to query string true "Destination wallet ID (owner's wallet, same currency)" format(uuid)
+// @Param confirm_new_payee query bool false "Confirm the first transfer to a new payee"
+// @Success 200 {object} common.APIResponse{data=dtos.CloseWalletResultDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse "Wallet not found"
+// @Failure 409 {object} common.APIResponse "Concurrency error"
+// @Failure 422 {object} common.APIResponse "Pending balance, foreign or invalid destination, or transfer check failed"
+// @Failure 429 {object} common.APIResponse "Wallet busy"
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/wallets/{id}/close [post]
+func (h *WalletHandler) CloseWallet(c *gin.Context) {
+	var params WalletIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	var query CloseWalletParams
+	if !BindQuery(c, &query) {
+		return
+	}
+
+	cmd := dtos.CloseWalletCommand{
+		WalletID:        params.ID,
+		SweepToWalletID: query.SweepTo,
+		ConfirmNewPayee: query.ConfirmNewPayee,
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.CloseWalletCommand, *dtos.CloseWalletResultDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// EnsureWallet создаёт кошелёк пользователя в валюте или приводит его лимиты к запрошенным.
+//
+// @Summary Ensure wallet
+// @Description Idempotently make sure the user has a wallet in the currency with the given limits (owner or admin).
+// @Description Returns 201 when the wallet was created and 200 when it already existed, with or without changes.
+// @Tags Wallets
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param currency path string true "Currency code" minLength(3) maxLength(3)
+// @Param request body EnsureWalletRequest true "Desired limits"
+// @Success 200 {object} common.APIResponse{data=dtos.EnsureWalletResultDTO}
+// @Success 201 {object} common.APIResponse{data=dtos.EnsureWalletResultDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse "User not found"
+// @Failure 409 {object} common.APIResponse "Concurrency error"
+// @Failure 422 {object} common.APIResponse "User not verified or wallet closed"
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/users/{id}/wallets/{currency} [put]
+func (h *WalletHandler) EnsureWallet(c *gin.Context) {
+	var params EnsureWalletParams
+	if !BindURI(c, &params) {
+		return
+	}
+
+	var req EnsureWalletRequest
+	if !BindJSON(c, &req) {
+		return
+	}
+
+	cmd := dtos.EnsureWalletCommand{
+		UserID:                 params.UserID,
+		CurrencyCode:           params.CurrencyCode,
+		DailyLimit:             req.DailyLimit,
+		MonthlyLimit:           req.MonthlyLimit,
+		MaxPendingTransactions: req.MaxPendingTransactions,
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.EnsureWalletCommand, *dtos.EnsureWalletResultDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.Created {
+		status = http.StatusCreated
+	}
+	common.Success(c, status, result)
+}
+
+// ExchangeCurrency обрабатывает обмен валюты между кошельками пользователя.
+func (h *WalletHandler) ExchangeCurrency(c *gin.Context) {
+	var params WalletIDParam
+	if !BindURI(c, &params) {
 		return
 	}
 
@@ -422,6 +802,7 @@ func (h *WalletHandler) ExchangeCurrency(c *gin.Context) {
 		SourceWalletID:      params.ID,
 		DestinationWalletID: req.DestinationWalletID,
 		Amount:              req.Amount,
+		AmountMinor:         req.AmountMinor,
 		IdempotencyKey:      req.IdempotencyKey,
 	}
 
@@ -442,13 +823,13 @@ func (h *WalletHandler) ExchangeCurrency(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} common.APIResponse{data=dtos.WalletListDTO}
+// @Success 200 {object} common.APIResponse{data=[]dtos.WalletDTO,pagination=dtos.PaginationDTO}
 // @Failure 401 {object} common.APIResponse
 // @Failure 500 {object} common.APIResponse
 // @Router /api/v1/wallets/me [get]
 func (h *WalletHandler) GetMyWallets(c *gin.Context) {
-	userID := middleware.GetAuthUserID(c)
-	if userID == uuid.Nil {
+	userID, ok := httpctx.AuthUserID(c)
+	if !ok {
 		common.UnauthorizedResponse(c, "User not authenticated")
 		return
 	}
@@ -466,6 +847,123 @@ func (h *WalletHandler) GetMyWallets(c *gin.Context) {
 		return
 	}
 
+	if h.legacyListShape {
+		common.Success(c, http.StatusOK, newLegacyWalletList(result, query))
+		return
+	}
+
+	SuccessPage(c, nil, result)
+}
+
+// GetActivityHeatmap возвращает активность кошелька по дням.
+//
+// @Summary Wallet activity heatmap
+// @Description Transactions per day for the trailing days (365 by default, up to 730), counted in the given IANA time zone. Every day of the range is returned, days without transactions with zero sums.
+// @Tags Wallets
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID" format(uuid)
+// @Param days query int false "Number of days including today" default(365) maximum(730)
+// @Param tz query string false "IANA time zone of day boundaries" default(UTC) example(Europe/Berlin)
+// @Success 200 {object} common.APIResponse{data=dtos.ActivityHeatmapDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/wallets/{id}/activity/heatmap [get]
+func (h *WalletHandler) GetActivityHeatmap(c *gin.Context) {
+	var uri WalletIDParam
+	if !BindURI(c, &uri) {
+		return
+	}
+
+	var params ActivityHeatmapParams
+	if !BindQuery(c, &params) {
+		return
+	}
+
+	h.respondActivityHeatmap(c, dtos.GetActivityHeatmapQuery{
+		WalletID: uri.ID,
+		Days:     params.Days,
+		Timezone: params.Timezone,
+	})
+}
+
+// GetMyActivityHeatmap возвращает активность всех кошельков авторизованного
+// пользователя по дням.
+//
+// @Summary My activity heatmap
+// @Description Same as the wallet heatmap, across all wallets of the authenticated user; sums are per currency
+// @Tags Wallets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Number of days including today" default(365) maximum(730)
+// @Param tz query string false "IANA time zone of day boundaries" default(UTC) example(Europe/Berlin)
+// @Success 200 {object} common.APIResponse{data=dtos.ActivityHeatmapDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/users/me/activity/heatmap [get]
+func (h *WalletHandler) GetMyActivityHeatmap(c *gin.Context) {
+	userID, ok := httpctx.AuthUserID(c)
+	if !ok {
+		common.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var params ActivityHeatmapParams
+	if !BindQuery(c, &params) {
+		return
+	}
+
+	h.respondActivityHeatmap(c, dtos.GetActivityHeatmapQuery{
+		UserID:   userID.String(),
+		Days:     params.Days,
+		Timezone: params.Timezone,
+	})
+}
+
+func (h *WalletHandler) respondActivityHeatmap(c *gin.Context, query dtos.GetActivityHeatmapQuery) {
+	result, err := cqrs.DispatchQuery[dtos.GetActivityHeatmapQuery, *dtos.ActivityHeatmapDTO](h.queryBus, c.Request.Context(), query)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// AdminReplayWallet воспроизводит историю транзакций кошелька и сравнивает
+// результат с сохранённым кошельком. Только чтение.
+//
+// @Summary Replay wallet history (admin)
+// @Description Rebuild the wallet from its COMPLETED transactions in booking order with the live domain rules and diff it against the stored wallet: balances, balance version, closure, the first transaction the history cannot explain, or the transaction this build cannot replay
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Wallet ID" format(uuid)
+// @Success 200 {object} common.APIResponse{data=dtos.WalletReplayDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Failure 409 {object} common.APIResponse "Wallet was modified during replay"
+// @Failure 500 {object} common.APIResponse
+// @Router /api/v1/admin/wallets/{id}/replay [get]
+func (h *WalletHandler) AdminReplayWallet(c *gin.Context) {
+	var uri WalletIDParam
+	if !BindURI(c, &uri) {
+		return
+	}
+
+	query := dtos.ReplayWalletQuery{WalletID: uri.ID}
+
+	result, err := cqrs.DispatchQuery[dtos.ReplayWalletQuery, *dtos.WalletReplayDTO](h.queryBus, c.Request.Context(), query)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
 	common.Success(c, http.StatusOK, result)
 }
 
@@ -477,8 +975,49 @@ func (h *WalletHandler) RegisterRoutes(router *gin.RouterGroup) {
 		wallets.GET("", h.ListWallets)
 		wallets.GET("/me", h.GetMyWallets)
 		wallets.GET("/:id", h.GetWallet)
+		wallets.HEAD("/:id", h.GetWallet)
+		wallets.GET("/:id/balance", h.GetWalletBalance)
+		wallets.GET("/:id/activity/heatmap", h.GetActivityHeatmap)
+		wallets.HEAD("/:id/balance", h.GetWalletBalance)
 		wallets.POST("/:id/credit", h.CreditWallet)
 		wallets.POST("/:id/debit", h.DebitWallet)
 		wallets.POST("/:id/transfer", h.Transfer)
+		wallets.POST("/:id/transfers/bulk", h.BulkTransfer)
+		wallets.POST("/:id/close", h.CloseWallet)
+		wallets.PUT("/:id/settings", h.UpdateSettings)
+	}
+	router.PUT("/users/:id/wallets/:currency", h.EnsureWallet)
+	router.GET("/users/me/activity/heatmap", h.GetMyActivityHeatmap)
+}
+
+// operationStatus возвращает HTTP статус денежной операции:
+// 201 для новой транзакции, 200 для повтора с тем же idempotency_key.
+func operationStatus(idempotentReplay bool) int {
+	if idempotentReplay {
+		return http.StatusOK
+	}
+	return http.StatusCreated
+}
+
+// walletETag строит ETag кошелька из balance_version и updated_at.
+// updated_at нужен, потому что смена статуса не меняет balance_version.
+func walletETag(wallet *dtos.WalletDTO) string {
+	parts := []string{
+		strconv.FormatInt(wallet.BalanceVersion, 10),
+		strconv.FormatInt(wallet.UpdatedAt.UnixNano(), 10),
+	}
+	// Число незавершённых транзакций меняется и без изменения кошелька
+	if wallet.Usage != nil {
+		parts = append(parts, "p"+strconv.Itoa(wallet.Usage.PendingTransactions))
+	}
+	return common.StrongETag(append(parts, convertedETagParts(wallet.Converted)...)...)
+}
+
+// convertedETagParts - части ETag от пересчёта в валюту отображения: курс
+// меняется без изменения кошелька.
+func convertedETagParts(converted *dtos.ConvertedBalanceDTO) []string {
+	if converted == nil {
+		return nil
 	}
+	return []string{"fx" + converted.Currency, converted.Rate}
 }
@@ -4,17 +4,22 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
 	"github.com/Haleralex/wallethub/internal/application/cqrs"
 	"github.com/Haleralex/wallethub/internal/application/dtos"
 	domerrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // ============================================
@@ -65,6 +70,39 @@ func (m *mockTransferFundsUseCase) Execute(ctx context.Context, cmd dtos.Transfe
 	return nil, nil
 }
 
+type mockBulkTransferUseCase struct {
+	ExecuteFn func(ctx context.Context, cmd dtos.BulkTransferCommand) (*dtos.BulkTransferResultDTO, error)
+}
+
+func (m *mockBulkTransferUseCase) Execute(ctx context.Context, cmd dtos.BulkTransferCommand) (*dtos.BulkTransferResultDTO, error) {
+	if m.ExecuteFn != nil {
+		return m.ExecuteFn(ctx, cmd)
+	}
+	return nil, nil
+}
+
+type mockCloseWalletUseCase struct {
+	ExecuteFn func(ctx context.Context, cmd dtos.CloseWalletCommand) (*dtos.CloseWalletResultDTO, error)
+}
+
+func (m *mockCloseWalletUseCase) Execute(ctx context.Context, cmd dtos.CloseWalletCommand) (*dtos.CloseWalletResultDTO, error) {
+	if m.ExecuteFn != nil {
+		return m.ExecuteFn(ctx, cmd)
+	}
+	return nil, nil
+}
+
+type mockEnsureWalletUseCase struct {
+	ExecuteFn func(ctx context.Context, cmd dtos.EnsureWalletCommand) (*dtos.EnsureWalletResultDTO, error)
+}
+
+func (m *mockEnsureWalletUseCase) Execute(ctx context.Context, cmd dtos.EnsureWalletCommand) (*dtos.EnsureWalletResultDTO, error) {
+	if m.ExecuteFn != nil {
+		return m.ExecuteFn(ctx, cmd)
+	}
+	return nil, nil
+}
+
 type mockGetWalletUseCase struct {
 	ExecuteFn func(ctx context.Context, query dtos.GetWalletQuery) (*dtos.WalletDTO, error)
 }
@@ -76,6 +114,17 @@ func (m *mockGetWalletUseCase) Execute(ctx context.Context, query dtos.GetWallet
 	return nil, nil
 }
 
+type mockGetWalletBalanceUseCase struct {
+	ExecuteFn func(ctx context.Context, query dtos.GetWalletBalanceQuery) (*dtos.WalletBalanceDTO, error)
+}
+
+func (m *mockGetWalletBalanceUseCase) Execute(ctx context.Context, query dtos.GetWalletBalanceQuery) (*dtos.WalletBalanceDTO, error) {
+	if m.ExecuteFn != nil {
+		return m.ExecuteFn(ctx, query)
+	}
+	return nil, nil
+}
+
 type mockListWalletsUseCase struct {
 	ExecuteFn func(ctx context.Context, query dtos.ListWalletsQuery) (*dtos.WalletListDTO, error)
 }
@@ -135,7 +184,7 @@ func setupWalletTestRouter(handler *WalletHandler) *gin.Engine {
 func setupWalletTestRouterWithAuth(handler *WalletHandler, userID string) *gin.Engine {
 	router := gin.New()
 	router.Use(func(c *gin.Context) {
-		c.Set("auth_user_id", userID)
+		httpctx.SetAuthUserID(c, uuid.MustParse(userID))
 		c.Next()
 	})
 	handler.RegisterRoutes(router.Group("/api/v1"))
@@ -157,6 +206,17 @@ func ownerGetWalletMock(userID string) *mockGetWalletUseCase {
 	}
 }
 
+// decodeResponseData decodes the "data" field of an APIResponse body.
+func decodeResponseData(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response.Data
+}
+
 // ============================================
 // Test Cases
 // ============================================
@@ -243,6 +303,29 @@ func TestWalletHandler_CreateWallet(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []common.FieldError{
+			{Field: "currency_code", Message: "Invalid currency code (must be 3 uppercase letters)", Code: common.FieldCodeInvalidFormat},
+		}, decodeFieldErrors(t, w))
+	})
+
+	t.Run("UnsupportedCurrency", func(t *testing.T) {
+		userID := uuid.New().String()
+		cmdBus, qBus := buildWalletBuses(&mockCreateWalletUseCase{}, nil, nil, nil, nil, nil)
+		handler := NewWalletHandler(cmdBus, qBus)
+		router := setupWalletTestRouterWithAuth(handler, userID)
+
+		// Формат верный, но валюта не поддерживается: проверка домена до use case
+		body, _ := json.Marshal(CreateWalletRequest{CurrencyCode: "XYZ"})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []common.FieldError{
+			{Field: "currency_code", Message: "Unsupported currency", Code: common.FieldCodeInvalidValue},
+		}, decodeFieldErrors(t, w))
 	})
 
 	t.Run("UserNotFound", func(t *testing.T) {
@@ -301,30 +384,36 @@ func TestWalletHandler_GetWallet(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 
-	t.Run("ForbiddenOtherUser", func(t *testing.T) {
-		authUserID := uuid.New().String()
-		ownerUserID := uuid.New().String()
+	t.Run("IncludeUsage", func(t *testing.T) {
+		userID := uuid.New().String()
 		walletID := uuid.New().String()
 
 		mockUseCase := &mockGetWalletUseCase{
 			ExecuteFn: func(ctx context.Context, query dtos.GetWalletQuery) (*dtos.WalletDTO, error) {
-				return &dtos.WalletDTO{
-					ID:     walletID,
-					UserID: ownerUserID, // Different from auth user
-				}, nil
+				wallet := &dtos.WalletDTO{ID: walletID, UserID: userID}
+				if query.IncludeUsage {
+					wallet.Usage = &dtos.WalletUsageDTO{PendingTransactions: 7, MaxPendingTransactions: 100}
+				}
+				return wallet, nil
 			},
 		}
 
 		cmdBus, qBus := buildWalletBuses(nil, nil, nil, nil, mockUseCase, nil)
-		handler := NewWalletHandler(cmdBus, qBus)
-		router := setupWalletTestRouterWithAuth(handler, authUserID)
+		router := setupWalletTestRouterWithAuth(NewWalletHandler(cmdBus, qBus), userID)
 
-		req := httptest.NewRequest(http.MethodGet, "/api/v1/wallets/"+walletID, nil)
 		w := httptest.NewRecorder()
-
-		router.ServeHTTP(w, req)
-
-		assert.Equal(t, http.StatusForbidden, w.Code)
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/wallets/"+walletID+"?include_usage=true", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, map[string]interface{}{"pending_transactions": float64(7), "max_pending_transactions": float64(100)},
+			decodeResponseData(t, w)["usage"])
+		withUsage := w.Header().Get("ETag")
+
+		// Без include_usage usage не отдаётся, и ETag отличается
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/wallets/"+walletID, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, decodeResponseData(t, w), "usage")
+		assert.NotEqual(t, withUsage, w.Header().Get("ETag"))
 	})
 
 	t.Run("InvalidUUID", func(t *testing.T) {
@@ -374,21 +463,205 @@ func TestWalletHandler_GetWallet(t *testing.T) {
 	})
 }
 
+func TestWalletHandler_GetWallet_ETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New().String()
+	walletID := uuid.New().String()
+	updatedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Текущее состояние кошелька; тесты меняют его, имитируя операции
+	current := dtos.WalletDTO{
+		ID:               walletID,
+		UserID:           userID,
+		CurrencyCode:     "USD",
+		AvailableBalance: "100.00",
+		Status:           "ACTIVE",
+		BalanceVersion:   3,
+		UpdatedAt:        updatedAt,
+	}
+	mockUseCase := &mockGetWalletUseCase{
+		ExecuteFn: func(ctx context.Context, query dtos.GetWalletQuery) (*dtos.WalletDTO, error) {
+			wallet := current
+			return &wallet, nil
+		},
+	}
+
+	cmdBus, qBus := buildWalletBuses(nil, nil, nil, nil, mockUseCase, nil)
+	handler := NewWalletHandler(cmdBus, qBus)
+	router := setupWalletTestRouterWithAuth(handler, userID)
+
+	request := func(method, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/wallets/"+walletID, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := request(http.MethodGet, "")
+	etag := first.Header().Get("ETag")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.NotEmpty(t, etag)
+
+	t.Run("MatchingIfNoneMatch", func(t *testing.T) {
+		w := request(http.MethodGet, etag)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("MatchingOneOfList", func(t *testing.T) {
+		w := request(http.MethodGet, `"stale", W/`+etag)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+	})
+
+	t.Run("NonMatchingIfNoneMatch", func(t *testing.T) {
+		w := request(http.MethodGet, `"stale"`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+		assert.Equal(t, walletID, decodeResponseData(t, w)["id"])
+	})
+
+	t.Run("HeadMatchesGetHeaders", func(t *testing.T) {
+		get := request(http.MethodGet, "")
+		head := request(http.MethodHead, "")
+
+		assert.Equal(t, http.StatusOK, head.Code)
+		assert.Empty(t, head.Body.String())
+		assert.Equal(t, get.Header().Get("ETag"), head.Header().Get("ETag"))
+		assert.Equal(t, get.Header().Get("Content-Type"), head.Header().Get("Content-Type"))
+	})
+
+	t.Run("HeadWithMatchingIfNoneMatch", func(t *testing.T) {
+		w := request(http.MethodHead, etag)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+	})
+
+	t.Run("BalanceChangeRotatesETag", func(t *testing.T) {
+		current.AvailableBalance = "150.00"
+		current.BalanceVersion++
+		current.UpdatedAt = updatedAt.Add(time.Second)
+
+		w := request(http.MethodGet, etag)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, etag, w.Header().Get("ETag"))
+		assert.Equal(t, "150.00", decodeResponseData(t, w)["available_balance"])
+	})
+}
+
+func TestWalletHandler_GetWalletBalance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New().String()
+	walletID := uuid.New().String()
+
+	// Текущий баланс; тесты меняют его, имитируя операции
+	current := dtos.WalletBalanceDTO{
+		WalletID:         walletID,
+		CurrencyCode:     "USD",
+		AvailableBalance: "100.50",
+		PendingBalance:   "0.00",
+		BalanceVersion:   7,
+		UserID:           userID,
+	}
+	var queries int
+	newRouter := func(authUserID string) *gin.Engine {
+		qBus := cqrs.NewQueryBus()
+		cqrs.RegisterQueryHandler[dtos.GetWalletBalanceQuery, *dtos.WalletBalanceDTO](qBus, &mockGetWalletBalanceUseCase{
+			ExecuteFn: func(ctx context.Context, query dtos.GetWalletBalanceQuery) (*dtos.WalletBalanceDTO, error) {
+				queries++
+				if query.WalletID != walletID {
+					return nil, domerrors.ErrEntityNotFound
+				}
+				balance := current
+				return &balance, nil
+			},
+		})
+		// GetWallet не зарегистрирован: быстрый путь не должен его вызывать
+		return setupWalletTestRouterWithAuth(NewWalletHandler(cqrs.NewCommandBus(), qBus), authUserID)
+	}
+	router := newRouter(userID)
+
+	request := func(method, id, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/wallets/"+id+"/balance", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		queries = 0
+		w := request(http.MethodGet, walletID, "")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"7"`, w.Header().Get("ETag"))
+		assert.Equal(t, 1, queries, "one query for ownership and balance")
+
+		data := decodeResponseData(t, w)
+		assert.Equal(t, walletID, data["wallet_id"])
+		assert.Equal(t, "USD", data["currency_code"])
+		assert.Equal(t, "100.50", data["available_balance"])
+		assert.Equal(t, "0.00", data["pending_balance"])
+		assert.Equal(t, float64(7), data["balance_version"])
+		assert.NotContains(t, data, "user_id")
+	})
+
+	t.Run("NotModifiedUntilBalanceChanges", func(t *testing.T) {
+		w := request(http.MethodGet, walletID, `"7"`)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+
+		current.BalanceVersion = 8
+		current.AvailableBalance = "90.50"
+		defer func() { current.BalanceVersion, current.AvailableBalance = 7, "100.50" }()
+
+		w = request(http.MethodGet, walletID, `"7"`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"8"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("Head", func(t *testing.T) {
+		w := request(http.MethodHead, walletID, "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, `"7"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		w := request(http.MethodGet, uuid.New().String(), "")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("InvalidUUID", func(t *testing.T) {
+		w := request(http.MethodGet, "not-a-uuid", "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestWalletHandler_ListWallets(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("Success", func(t *testing.T) {
 		mockUseCase := &mockListWalletsUseCase{
 			ExecuteFn: func(ctx context.Context, query dtos.ListWalletsQuery) (*dtos.WalletListDTO, error) {
-				return &dtos.WalletListDTO{
-					Wallets: []dtos.WalletDTO{
-						{ID: uuid.New().String(), CurrencyCode: "USD", AvailableBalance: "100.00"},
-						{ID: uuid.New().String(), CurrencyCode: "EUR", AvailableBalance: "50.00"},
-					},
-					TotalCount: 2,
-					Offset:     0,
-					Limit:      20,
-				}, nil
+				return dtos.NewOffsetPage([]dtos.WalletDTO{
+					{ID: uuid.New().String(), CurrencyCode: "USD", AvailableBalance: "100.00"},
+					{ID: uuid.New().String(), CurrencyCode: "EUR", AvailableBalance: "50.00"},
+				}, 0, 20), nil
 			},
 		}
 
@@ -405,7 +678,66 @@ func TestWalletHandler_ListWallets(t *testing.T) {
 
 		var response map[string]interface{}
 		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response["data"], 2)
+		assert.Equal(t, map[string]interface{}{
+			"mode": "offset", "offset": float64(0), "limit": float64(20), "has_more": false,
+		}, response["pagination"])
+		assert.Nil(t, response["meta"])
+	})
+
+	t.Run("HasMore", func(t *testing.T) {
+		mockUseCase := &mockListWalletsUseCase{
+			ExecuteFn: func(ctx context.Context, query dtos.ListWalletsQuery) (*dtos.WalletListDTO, error) {
+				assert.Equal(t, 2, query.Offset)
+				assert.Equal(t, 2, query.Limit)
+				rows := []dtos.WalletDTO{{ID: uuid.New().String()}, {ID: uuid.New().String()}, {ID: uuid.New().String()}}
+				return dtos.NewOffsetPage(rows, query.Offset, query.Limit), nil
+			},
+		}
+
+		cmdBus, qBus := buildWalletBuses(nil, nil, nil, nil, nil, mockUseCase)
+		router := setupWalletTestRouter(NewWalletHandler(cmdBus, qBus))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/wallets?page=2&per_page=2&fields=status", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data       []map[string]interface{} `json:"data"`
+			Pagination dtos.PaginationDTO       `json:"pagination"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Data, 2)
+		assert.Contains(t, response.Data[0], "id")
+		assert.True(t, response.Pagination.HasMore)
+		assert.Equal(t, 2, *response.Pagination.Offset)
+	})
+
+	t.Run("LegacyShape", func(t *testing.T) {
+		mockUseCase := &mockListWalletsUseCase{
+			ExecuteFn: func(ctx context.Context, query dtos.ListWalletsQuery) (*dtos.WalletListDTO, error) {
+				return dtos.NewOffsetPage([]dtos.WalletDTO{{ID: uuid.New().String(), CurrencyCode: "USD"}}, query.Offset, query.Limit), nil
+			},
+		}
+
+		cmdBus, qBus := buildWalletBuses(nil, nil, nil, nil, nil, mockUseCase)
+		router := setupWalletTestRouter(NewWalletHandler(cmdBus, qBus).WithLegacyListShape(true))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/wallets", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		data := response["data"].(map[string]interface{})
+		assert.Len(t, data["wallets"], 1)
+		assert.Equal(t, float64(1), data["total_count"])
 		assert.NotNil(t, response["meta"])
+		assert.Nil(t, response["pagination"])
 	})
 
 	t.Run("WithFilters", func(t *testing.T) {
@@ -413,7 +745,7 @@ func TestWalletHandler_ListWallets(t *testing.T) {
 			ExecuteFn: func(ctx context.Context, query dtos.ListWalletsQuery) (*dtos.WalletListDTO, error) {
 				assert.NotNil(t, query.UserID)
 				assert.NotNil(t, query.CurrencyCode)
-				return &dtos.WalletListDTO{Wallets: []dtos.WalletDTO{}, TotalCount: 0}, nil
+				return dtos.NewOffsetPage([]dtos.WalletDTO{}, query.Offset, query.Limit), nil
 			},
 		}
 
@@ -452,6 +784,8 @@ func TestWalletHandler_CreditWallet(t *testing.T) {
 		userID := uuid.New().String()
 		walletID := uuid.New().String()
 
+		transactionID := uuid.New().String()
+
 		mockCredit := &mockCreditWalletUseCase{
 			ExecuteFn: func(ctx context.Context, cmd dtos.CreditWalletCommand) (*dtos.WalletOperationDTO, error) {
 				return &dtos.WalletOperationDTO{
@@ -459,7 +793,13 @@ func TestWalletHandler_CreditWallet(t *testing.T) {
 						ID:               walletID,
 						AvailableBalance: "150.00",
 					},
-					TransactionID: uuid.New().String(),
+					Transaction: dtos.TransactionDTO{
+						ID:     transactionID,
+						Type:   "DEPOSIT",
+						Status: "COMPLETED",
+						Amount: "50.00",
+					},
+					TransactionID: transactionID,
 					Message:       "Wallet credited successfully",
 				}, nil
 			},
@@ -480,29 +820,53 @@ func TestWalletHandler_CreditWallet(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		data := decodeResponseData(t, w)
+		assert.Equal(t, false, data["idempotent_replay"])
+		assert.Equal(t, transactionID, data["transaction_id"])
+		transaction, ok := data["transaction"].(map[string]interface{})
+		require.True(t, ok, "transaction must be embedded")
+		assert.Equal(t, transactionID, transaction["id"])
+		assert.Equal(t, "COMPLETED", transaction["status"])
+		assert.Equal(t, "DEPOSIT", transaction["type"])
 	})
 
-	t.Run("ForbiddenOtherUser", func(t *testing.T) {
-		authUserID := uuid.New().String()
-		ownerUserID := uuid.New().String()
+	t.Run("IdempotentReplay", func(t *testing.T) {
+		userID := uuid.New().String()
+		walletID := uuid.New().String()
+		createdAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
 
-		cmdBus, qBus := buildWalletBuses(nil, &mockCreditWalletUseCase{}, nil, nil, ownerGetWalletMock(ownerUserID), nil)
+		mockCredit := &mockCreditWalletUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.CreditWalletCommand) (*dtos.WalletOperationDTO, error) {
+				return &dtos.WalletOperationDTO{
+					Wallet:           dtos.WalletDTO{ID: walletID, AvailableBalance: "150.00"},
+					TransactionID:    uuid.New().String(),
+					Message:          "Wallet credited successfully",
+					CreatedAt:        createdAt,
+					IdempotentReplay: true,
+				}, nil
+			},
+		}
+
+		cmdBus, qBus := buildWalletBuses(nil, mockCredit, nil, nil, ownerGetWalletMock(userID), nil)
 		handler := NewWalletHandler(cmdBus, qBus)
-		router := setupWalletTestRouterWithAuth(handler, authUserID)
+		router := setupWalletTestRouterWithAuth(handler, userID)
 
 		body, _ := json.Marshal(CreditWalletRequest{
 			Amount:         "50.00",
 			IdempotencyKey: uuid.New().String(),
-			Description:    "Test",
+			Description:    "Test deposit",
 		})
-		req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+uuid.New().String()+"/credit", bytes.NewBuffer(body))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+walletID+"/credit", bytes.NewBuffer(body))
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, http.StatusOK, w.Code)
+		data := decodeResponseData(t, w)
+		assert.Equal(t, true, data["idempotent_replay"])
+		assert.Equal(t, "2024-01-15T10:00:00Z", data["created_at"])
 	})
 
 	t.Run("InvalidAmount", func(t *testing.T) {
@@ -523,6 +887,97 @@ func TestWalletHandler_CreditWallet(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []common.FieldError{
+			{Field: "amount", Message: "Invalid amount format (use decimal like '100.50')", Code: common.FieldCodeInvalidFormat},
+		}, decodeFieldErrors(t, w))
+	})
+
+	t.Run("AmountMinor", func(t *testing.T) {
+		userID := uuid.New().String()
+		var got dtos.CreditWalletCommand
+		mockCredit := &mockCreditWalletUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.CreditWalletCommand) (*dtos.WalletOperationDTO, error) {
+				got = cmd
+				return &dtos.WalletOperationDTO{TransactionID: uuid.New().String()}, nil
+			},
+		}
+		cmdBus, qBus := buildWalletBuses(nil, mockCredit, nil, nil, ownerGetWalletMock(userID), nil)
+		handler := NewWalletHandler(cmdBus, qBus)
+		router := setupWalletTestRouterWithAuth(handler, userID)
+
+		body := `{"amount_minor":9223372036854775807,"idempotency_key":"` + uuid.New().String() + `","description":"Test"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+uuid.New().String()+"/credit", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Empty(t, got.Amount)
+		if assert.NotNil(t, got.AmountMinor) {
+			assert.Equal(t, int64(math.MaxInt64), *got.AmountMinor)
+		}
+	})
+
+	t.Run("AmountRepresentations", func(t *testing.T) {
+		userID := uuid.New().String()
+		cmdBus, qBus := buildWalletBuses(nil, &mockCreditWalletUseCase{}, nil, nil, ownerGetWalletMock(userID), nil)
+		handler := NewWalletHandler(cmdBus, qBus)
+		router := setupWalletTestRouterWithAuth(handler, userID)
+
+		tests := []struct {
+			name   string
+			amount string
+			want   common.FieldError
+		}{
+			{"Both", `"amount":"100.50","amount_minor":10050`,
+				common.FieldError{Field: "amount_minor", Message: "amount and amount_minor are mutually exclusive", Code: common.FieldCodeInvalidValue}},
+			{"Neither", `"amount_minor":null`,
+				common.FieldError{Field: "amount", Message: "amount or amount_minor is required", Code: common.FieldCodeRequired}},
+			{"NegativeMinor", `"amount_minor":-1`,
+				common.FieldError{Field: "amount_minor", Message: "Value is too short (minimum: 0)", Code: common.FieldCodeOutOfRange}},
+			{"FractionalMinor", `"amount_minor":100.5`,
+				common.FieldError{Field: "amount_minor", Message: "Expected int64", Code: common.FieldCodeInvalidType}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				body := `{` + tt.amount + `,"idempotency_key":"` + uuid.New().String() + `","description":"Test"}`
+				req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+uuid.New().String()+"/credit", bytes.NewBufferString(body))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Equal(t, []common.FieldError{tt.want}, decodeFieldErrors(t, w))
+			})
+		}
+	})
+
+	t.Run("ReservedSystemKey", func(t *testing.T) {
+		userID := uuid.New().String()
+		cmdBus, qBus := buildWalletBuses(nil, &mockCreditWalletUseCase{}, nil, nil, ownerGetWalletMock(userID), nil)
+		handler := NewWalletHandler(cmdBus, qBus)
+		router := setupWalletTestRouterWithAuth(handler, userID)
+
+		// Ключ под "sys:" зарезервирован за системными транзакциями
+		for _, key := range []string{valueobjects.DerivedIdempotencyKey("fee", uuid.New().String()), "SYS:" + uuid.New().String()} {
+			body, _ := json.Marshal(CreditWalletRequest{
+				Amount:         "50.00",
+				IdempotencyKey: key,
+				Description:    "Test",
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+uuid.New().String()+"/credit", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, []common.FieldError{
+				{Field: "idempotency_key", Message: "Idempotency key prefix 'sys:' is reserved", Code: common.FieldCodeInvalidValue},
+			}, decodeFieldErrors(t, w))
+		}
 	})
 
 	t.Run("WalletNotActive", func(t *testing.T) {
@@ -587,7 +1042,47 @@ func TestWalletHandler_DebitWallet(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
+		assert.Equal(t, http.StatusCreated, w.Code)
+		data := decodeResponseData(t, w)
+		assert.Equal(t, false, data["idempotent_replay"])
+	})
+
+	t.Run("IdempotentReplay", func(t *testing.T) {
+		userID := uuid.New().String()
+		walletID := uuid.New().String()
+		createdAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+		mockDebit := &mockDebitWalletUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.DebitWalletCommand) (*dtos.WalletOperationDTO, error) {
+				return &dtos.WalletOperationDTO{
+					Wallet:           dtos.WalletDTO{ID: walletID, AvailableBalance: "50.00"},
+					TransactionID:    uuid.New().String(),
+					Message:          "Wallet debited successfully",
+					CreatedAt:        createdAt,
+					IdempotentReplay: true,
+				}, nil
+			},
+		}
+
+		cmdBus, qBus := buildWalletBuses(nil, nil, mockDebit, nil, ownerGetWalletMock(userID), nil)
+		handler := NewWalletHandler(cmdBus, qBus)
+		router := setupWalletTestRouterWithAuth(handler, userID)
+
+		body, _ := json.Marshal(DebitWalletRequest{
+			Amount:         "50.00",
+			IdempotencyKey: uuid.New().String(),
+			Description:    "Test withdrawal",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+walletID+"/debit", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
 		assert.Equal(t, http.StatusOK, w.Code)
+		data := decodeResponseData(t, w)
+		assert.Equal(t, true, data["idempotent_replay"])
+		assert.Equal(t, "2024-01-15T10:00:00Z", data["created_at"])
 	})
 
 	t.Run("InsufficientBalance", func(t *testing.T) {
@@ -644,12 +1139,57 @@ func TestWalletHandler_Transfer(t *testing.T) {
 		userID := uuid.New().String()
 		sourceID := uuid.New().String()
 		destID := uuid.New().String()
+		transactionID := uuid.New().String()
+
+		mockTransfer := &mockTransferFundsUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.TransferFundsCommand) (*dtos.TransferResultDTO, error) {
+				return &dtos.TransferResultDTO{
+					SourceWallet:      dtos.WalletDTO{ID: sourceID, AvailableBalance: "50.00"},
+					DestinationWallet: dtos.WalletDTO{ID: destID, AvailableBalance: "150.00"},
+					Transaction:       dtos.TransactionDTO{ID: transactionID, Type: "TRANSFER", Status: "COMPLETED"},
+					TransactionID:     transactionID,
+				}, nil
+			},
+		}
+
+		cmdBus, qBus := buildWalletBuses(nil, nil, nil, mockTransfer, ownerGetWalletMock(userID), nil)
+		handler := NewWalletHandler(cmdBus, qBus)
+		router := setupWalletTestRouterWithAuth(handler, userID)
+
+		body, _ := json.Marshal(TransferFundsRequest{
+			DestinationWalletID: destID,
+			Amount:              "100.00",
+			IdempotencyKey:      uuid.New().String(),
+			Description:         "Test transfer",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+sourceID+"/transfer", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		data := decodeResponseData(t, w)
+		assert.Equal(t, false, data["idempotent_replay"])
+		assert.Equal(t, transactionID, data["transaction_id"])
+		transaction, ok := data["transaction"].(map[string]interface{})
+		require.True(t, ok, "transaction must be embedded")
+		assert.Equal(t, "TRANSFER", transaction["type"])
+		assert.Equal(t, "COMPLETED", transaction["status"])
+	})
+
+	t.Run("IdempotentReplay", func(t *testing.T) {
+		userID := uuid.New().String()
+		sourceID := uuid.New().String()
+		destID := uuid.New().String()
 
 		mockTransfer := &mockTransferFundsUseCase{
 			ExecuteFn: func(ctx context.Context, cmd dtos.TransferFundsCommand) (*dtos.TransferResultDTO, error) {
 				return &dtos.TransferResultDTO{
 					SourceWallet:      dtos.WalletDTO{ID: sourceID, AvailableBalance: "50.00"},
 					DestinationWallet: dtos.WalletDTO{ID: destID, AvailableBalance: "150.00"},
+					CreatedAt:         time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+					IdempotentReplay:  true,
 				}, nil
 			},
 		}
@@ -671,6 +1211,9 @@ func TestWalletHandler_Transfer(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
+		data := decodeResponseData(t, w)
+		assert.Equal(t, true, data["idempotent_replay"])
+		assert.Equal(t, "2024-01-15T10:00:00Z", data["created_at"])
 	})
 
 	t.Run("CurrencyMismatch", func(t *testing.T) {
@@ -700,6 +1243,61 @@ func TestWalletHandler_Transfer(t *testing.T) {
 		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 	})
 
+	t.Run("AllValidationErrorsReported", func(t *testing.T) {
+		userID := uuid.New().String()
+		cmdBus, qBus := buildWalletBuses(nil, nil, nil, &mockTransferFundsUseCase{}, ownerGetWalletMock(userID), nil)
+		handler := NewWalletHandler(cmdBus, qBus)
+		router := setupWalletTestRouterWithAuth(handler, userID)
+
+		body := `{"destination_wallet_id":"not-a-uuid","amount":"ten","confirm_new_payee":"yes","note":"x"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+uuid.New().String()+"/transfer", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []common.FieldError{
+			{Field: "confirm_new_payee", Message: "Expected boolean, got string", Code: common.FieldCodeInvalidType},
+			{Field: "note", Message: "Unknown field", Code: common.FieldCodeUnknownField},
+			{Field: "destination_wallet_id", Message: "Invalid UUID format", Code: common.FieldCodeInvalidFormat},
+			{Field: "amount", Message: "Invalid amount format (use decimal like '100.50')", Code: common.FieldCodeInvalidFormat},
+			{Field: "idempotency_key", Message: "This field is required", Code: common.FieldCodeRequired},
+			{Field: "description", Message: "This field is required", Code: common.FieldCodeRequired},
+		}, decodeFieldErrors(t, w))
+	})
+
+	t.Run("UseCaseValidationErrorsFlattened", func(t *testing.T) {
+		userID := uuid.New().String()
+		mockTransfer := &mockTransferFundsUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.TransferFundsCommand) (*dtos.TransferResultDTO, error) {
+				var errs domerrors.ValidationErrors
+				errs.AddCode("destination_wallet_id", domerrors.ValidationCodeInvalidFormat, "invalid destination wallet ID format")
+				errs.AddCode("amount", domerrors.ValidationCodeInvalidFormat, "invalid amount")
+				return nil, errs
+			},
+		}
+
+		cmdBus, qBus := buildWalletBuses(nil, nil, nil, mockTransfer, ownerGetWalletMock(userID), nil)
+		handler := NewWalletHandler(cmdBus, qBus)
+		router := setupWalletTestRouterWithAuth(handler, userID)
+
+		body, _ := json.Marshal(TransferFundsRequest{
+			DestinationWalletID: uuid.New().String(),
+			Amount:              "100.00",
+			IdempotencyKey:      uuid.New().String(),
+			Description:         "Test",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+uuid.New().String()+"/transfer", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Len(t, decodeFieldErrors(t, w), 2)
+	})
+
 	t.Run("NoHandlerRegistered", func(t *testing.T) {
 		cmdBus := cqrs.NewCommandBus()
 		qBus := cqrs.NewQueryBus()
@@ -722,6 +1320,191 @@ func TestWalletHandler_Transfer(t *testing.T) {
 	})
 }
 
+func TestWalletHandler_CloseWallet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		userID := uuid.New().String()
+		walletID := uuid.New().String()
+		destID := uuid.New().String()
+
+		var got dtos.CloseWalletCommand
+		cmdBus, qBus := buildWalletBuses(nil, nil, nil, nil, ownerGetWalletMock(userID), nil)
+		cqrs.RegisterCommandHandler[dtos.CloseWalletCommand, *dtos.CloseWalletResultDTO](cmdBus, &mockCloseWalletUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.CloseWalletCommand) (*dtos.CloseWalletResultDTO, error) {
+				got = cmd
+				return &dtos.CloseWalletResultDTO{
+					Wallet:            dtos.WalletDTO{ID: walletID, Status: "CLOSED"},
+					DestinationWallet: dtos.WalletDTO{ID: destID},
+					SweptAmount:       "100.00",
+				}, nil
+			},
+		})
+		router := setupWalletTestRouterWithAuth(NewWalletHandler(cmdBus, qBus), userID)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+walletID+"/close?sweep_to="+destID, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, dtos.CloseWalletCommand{WalletID: walletID, SweepToWalletID: destID}, got)
+		data := decodeResponseData(t, w)
+		assert.Equal(t, "100.00", data["swept_amount"])
+	})
+
+	t.Run("MissingSweepTo", func(t *testing.T) {
+		userID := uuid.New().String()
+
+		cmdBus, qBus := buildWalletBuses(nil, nil, nil, nil, ownerGetWalletMock(userID), nil)
+		router := setupWalletTestRouterWithAuth(NewWalletHandler(cmdBus, qBus), userID)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+uuid.New().String()+"/close", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("PendingBalance", func(t *testing.T) {
+		userID := uuid.New().String()
+
+		cmdBus, qBus := buildWalletBuses(nil, nil, nil, nil, ownerGetWalletMock(userID), nil)
+		cqrs.RegisterCommandHandler[dtos.CloseWalletCommand, *dtos.CloseWalletResultDTO](cmdBus, &mockCloseWalletUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.CloseWalletCommand) (*dtos.CloseWalletResultDTO, error) {
+				return nil, domerrors.NewBusinessRuleViolation("PENDING_BALANCE_ON_CLOSE", "cannot sweep wallet with pending balance", nil)
+			},
+		})
+		router := setupWalletTestRouterWithAuth(NewWalletHandler(cmdBus, qBus), userID)
+
+		req := httptest.NewRequest(http.MethodPost,
+			"/api/v1/wallets/"+uuid.New().String()+"/close?sweep_to="+uuid.New().String(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
+
+func TestWalletHandler_EnsureWallet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// ensureRouter регистрирует ensure use case, который возвращает result и запоминает команду.
+	ensureRouter := func(authUserID, role string, result *dtos.EnsureWalletResultDTO, got *dtos.EnsureWalletCommand) *gin.Engine {
+		cmdBus, qBus := buildWalletBuses(nil, nil, nil, nil, nil, nil)
+		cqrs.RegisterCommandHandler[dtos.EnsureWalletCommand, *dtos.EnsureWalletResultDTO](cmdBus, &mockEnsureWalletUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.EnsureWalletCommand) (*dtos.EnsureWalletResultDTO, error) {
+				*got = cmd
+				return result, nil
+			},
+		})
+
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			httpctx.SetAuthUserID(c, uuid.MustParse(authUserID))
+			httpctx.SetAuthRole(c, role)
+			c.Next()
+		})
+		NewWalletHandler(cmdBus, qBus).RegisterRoutes(router.Group("/api/v1"))
+		return router
+	}
+
+	ensure := func(router *gin.Engine, userID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/users/"+userID+"/wallets/usd", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Created", func(t *testing.T) {
+		userID := uuid.New().String()
+		var got dtos.EnsureWalletCommand
+		router := ensureRouter(userID, "user", &dtos.EnsureWalletResultDTO{
+			Wallet:        dtos.WalletDTO{ID: uuid.New().String(), UserID: userID},
+			Created:       true,
+			Changed:       true,
+			ChangedFields: []string{},
+		}, &got)
+
+		w := ensure(router, userID, `{"daily_limit": "500.00"}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, dtos.EnsureWalletCommand{UserID: userID, CurrencyCode: "usd", DailyLimit: "500.00"}, got)
+		data := decodeResponseData(t, w)
+		assert.Equal(t, true, data["created"])
+		assert.Equal(t, true, data["changed"])
+	})
+
+	t.Run("AlreadyExists", func(t *testing.T) {
+		userID := uuid.New().String()
+		var got dtos.EnsureWalletCommand
+		router := ensureRouter(userID, "user", &dtos.EnsureWalletResultDTO{
+			Wallet:        dtos.WalletDTO{ID: uuid.New().String(), UserID: userID},
+			Changed:       true,
+			ChangedFields: []string{dtos.EnsureWalletFieldMonthlyLimit},
+		}, &got)
+
+		w := ensure(router, userID, `{"monthly_limit": "2500"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		data := decodeResponseData(t, w)
+		assert.Equal(t, false, data["created"])
+		assert.Equal(t, []interface{}{"monthly_limit"}, data["changed_fields"])
+	})
+
+	t.Run("AdminForOtherUser", func(t *testing.T) {
+		userID := uuid.New().String()
+		var got dtos.EnsureWalletCommand
+		router := ensureRouter(uuid.New().String(), "admin", &dtos.EnsureWalletResultDTO{ChangedFields: []string{}}, &got)
+
+		w := ensure(router, userID, `{}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, userID, got.UserID)
+	})
+
+	t.Run("InvalidLimit", func(t *testing.T) {
+		userID := uuid.New().String()
+		var got dtos.EnsureWalletCommand
+		router := ensureRouter(userID, "user", nil, &got)
+
+		w := ensure(router, userID, `{"daily_limit": "lots"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, got.UserID)
+	})
+
+	t.Run("MaxPendingTransactionsOwnerForbidden", func(t *testing.T) {
+		cmdBus, qBus := buildWalletBuses(nil, nil, nil, nil, nil, nil)
+		cqrs.RegisterCommandHandler[dtos.EnsureWalletCommand, *dtos.EnsureWalletResultDTO](cmdBus, &mockEnsureWalletUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.EnsureWalletCommand) (*dtos.EnsureWalletResultDTO, error) {
+				return nil, domerrors.NewDomainError("PENDING_CAP_OVERRIDE_FORBIDDEN", "only admins can override max_pending_transactions", nil)
+			},
+		})
+		userID := uuid.New().String()
+		router := setupWalletTestRouterWithAuth(NewWalletHandler(cmdBus, qBus), userID)
+
+		w := ensure(router, userID, `{"max_pending_transactions": 1000}`)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("MaxPendingTransactionsAdmin", func(t *testing.T) {
+		userID := uuid.New().String()
+		var got dtos.EnsureWalletCommand
+		router := ensureRouter(uuid.New().String(), "admin", &dtos.EnsureWalletResultDTO{ChangedFields: []string{}}, &got)
+
+		w := ensure(router, userID, `{"max_pending_transactions": 1000}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.NotNil(t, got.MaxPendingTransactions)
+		assert.Equal(t, 1000, *got.MaxPendingTransactions)
+
+		w = ensure(router, userID, `{"max_pending_transactions": -1}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestWalletHandler_GetMyWallets(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -732,10 +1515,7 @@ func TestWalletHandler_GetMyWallets(t *testing.T) {
 			ExecuteFn: func(ctx context.Context, query dtos.ListWalletsQuery) (*dtos.WalletListDTO, error) {
 				assert.NotNil(t, query.UserID)
 				assert.Equal(t, userID.String(), *query.UserID)
-				return &dtos.WalletListDTO{
-					Wallets:    []dtos.WalletDTO{{ID: uuid.New().String(), CurrencyCode: "USD"}},
-					TotalCount: 1,
-				}, nil
+				return dtos.NewOffsetPage([]dtos.WalletDTO{{ID: uuid.New().String(), CurrencyCode: "USD"}}, query.Offset, query.Limit), nil
 			},
 		}
 
@@ -744,7 +1524,7 @@ func TestWalletHandler_GetMyWallets(t *testing.T) {
 		router := gin.New()
 
 		router.Use(func(c *gin.Context) {
-			c.Set("auth_user_id", userID.String())
+			httpctx.SetAuthUserID(c, userID)
 			c.Next()
 		})
 
@@ -780,7 +1560,7 @@ func TestWalletHandler_GetMyWallets(t *testing.T) {
 		router := gin.New()
 
 		router.Use(func(c *gin.Context) {
-			c.Set("auth_user_id", userID.String())
+			httpctx.SetAuthUserID(c, userID)
 			c.Next()
 		})
 
@@ -795,6 +1575,90 @@ func TestWalletHandler_GetMyWallets(t *testing.T) {
 	})
 }
 
+type mockGetActivityHeatmapUseCase struct {
+	ExecuteFn func(ctx context.Context, query dtos.GetActivityHeatmapQuery) (*dtos.ActivityHeatmapDTO, error)
+}
+
+func (m *mockGetActivityHeatmapUseCase) Execute(ctx context.Context, query dtos.GetActivityHeatmapQuery) (*dtos.ActivityHeatmapDTO, error) {
+	return m.ExecuteFn(ctx, query)
+}
+
+func TestWalletHandler_GetActivityHeatmap(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New().String()
+	walletID := uuid.New().String()
+
+	var received *dtos.GetActivityHeatmapQuery
+	newRouter := func(authUserID string) *gin.Engine {
+		received = nil
+		_, qBus := buildWalletBuses(nil, nil, nil, nil, ownerGetWalletMock(userID), nil)
+		cqrs.RegisterQueryHandler[dtos.GetActivityHeatmapQuery, *dtos.ActivityHeatmapDTO](qBus, &mockGetActivityHeatmapUseCase{
+			ExecuteFn: func(ctx context.Context, query dtos.GetActivityHeatmapQuery) (*dtos.ActivityHeatmapDTO, error) {
+				received = &query
+				return &dtos.ActivityHeatmapDTO{
+					WalletID: query.WalletID,
+					UserID:   query.UserID,
+					Timezone: "Europe/Berlin",
+					From:     "2024-06-10",
+					To:       "2024-06-10",
+					Days: []dtos.ActivityDayDTO{{
+						Date:  "2024-06-10",
+						Count: 2,
+						Sums:  []dtos.ActivitySumDTO{{CurrencyCode: "USD", CreditSum: "10.00 USD", DebitSum: "0.25 USD"}},
+					}},
+				}, nil
+			},
+		})
+		return setupWalletTestRouterWithAuth(NewWalletHandler(cqrs.NewCommandBus(), qBus), authUserID)
+	}
+	get := func(router *gin.Engine, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("Wallet", func(t *testing.T) {
+		w := get(newRouter(userID), "/api/v1/wallets/"+walletID+"/activity/heatmap?days=30&tz=Europe/Berlin")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, &dtos.GetActivityHeatmapQuery{WalletID: walletID, Days: 30, Timezone: "Europe/Berlin"}, received)
+
+		data := decodeResponseData(t, w)
+		assert.Equal(t, walletID, data["wallet_id"])
+		assert.Equal(t, []interface{}{map[string]interface{}{
+			"date":  "2024-06-10",
+			"count": float64(2),
+			"sums": []interface{}{map[string]interface{}{
+				"currency_code": "USD", "credit_sum": "10.00 USD", "debit_sum": "0.25 USD",
+			}},
+		}}, data["days"])
+	})
+
+	t.Run("Me", func(t *testing.T) {
+		w := get(newRouter(userID), "/api/v1/users/me/activity/heatmap")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, &dtos.GetActivityHeatmapQuery{UserID: userID}, received)
+	})
+
+	t.Run("DaysOutOfRange", func(t *testing.T) {
+		for _, days := range []string{"-1", "731", "many"} {
+			w := get(newRouter(userID), "/api/v1/users/me/activity/heatmap?days="+days)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, days)
+			assert.Nil(t, received, days)
+		}
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		_, qBus := buildWalletBuses(nil, nil, nil, nil, nil, nil)
+		w := get(setupWalletTestRouter(NewWalletHandler(cqrs.NewCommandBus(), qBus)), "/api/v1/users/me/activity/heatmap")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
 func TestWalletHandler_RegisterRoutes(t *testing.T) {
 	cmdBus := cqrs.NewCommandBus()
 	qBus := cqrs.NewQueryBus()
@@ -808,9 +1672,18 @@ func TestWalletHandler_RegisterRoutes(t *testing.T) {
 		"GET /api/v1/wallets",
 		"GET /api/v1/wallets/me",
 		"GET /api/v1/wallets/:id",
+		"HEAD /api/v1/wallets/:id",
+		"GET /api/v1/wallets/:id/balance",
+		"HEAD /api/v1/wallets/:id/balance",
+		"GET /api/v1/wallets/:id/activity/heatmap",
 		"POST /api/v1/wallets/:id/credit",
 		"POST /api/v1/wallets/:id/debit",
 		"POST /api/v1/wallets/:id/transfer",
+		"POST /api/v1/wallets/:id/transfers/bulk",
+		"POST /api/v1/wallets/:id/close",
+		"PUT /api/v1/wallets/:id/settings",
+		"PUT /api/v1/users/:id/wallets/:currency",
+		"GET /api/v1/users/me/activity/heatmap",
 	}
 
 	assert.Len(t, routes, len(expectedRoutes))
@@ -826,3 +1699,72 @@ func TestWalletHandler_RegisterRoutes(t *testing.T) {
 		assert.True(t, found, "Route %s not found", expected)
 	}
 }
+
+func TestWalletHandler_BulkTransfer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("PartialReport", func(t *testing.T) {
+		userID := uuid.New().String()
+		sourceID := uuid.New().String()
+		destID := uuid.New().String()
+
+		var received dtos.BulkTransferCommand
+		mockBulk := &mockBulkTransferUseCase{
+			ExecuteFn: func(ctx context.Context, cmd dtos.BulkTransferCommand) (*dtos.BulkTransferResultDTO, error) {
+				received = cmd
+				return &dtos.BulkTransferResultDTO{
+					SourceWallet: dtos.WalletDTO{ID: sourceID, AvailableBalance: "900.00"},
+					Status:       dtos.BulkTransferStatusPartial,
+					Totals:       dtos.BulkTransferTotalsDTO{Requested: 2, Completed: 1, Failed: 1, CompletedAmount: "100.00 USD"},
+					Items: []dtos.BulkTransferItemResultDTO{
+						{Index: 0, Status: dtos.BulkTransferItemCompleted},
+						{Index: 1, Status: dtos.BulkTransferItemFailed, ErrorCode: "WALLET_NOT_FOUND"},
+					},
+				}, nil
+			},
+		}
+
+		cmdBus, qBus := buildWalletBuses(nil, nil, nil, nil, ownerGetWalletMock(userID), nil)
+		cqrs.RegisterCommandHandler[dtos.BulkTransferCommand, *dtos.BulkTransferResultDTO](cmdBus, mockBulk)
+		handler := NewWalletHandler(cmdBus, qBus)
+		router := setupWalletTestRouterWithAuth(handler, userID)
+
+		body, _ := json.Marshal(BulkTransferRequest{Transfers: []BulkTransferItemRequest{
+			{DestinationWalletID: destID, Amount: "100.00", IdempotencyKey: uuid.New().String(), Reference: "salary"},
+			{DestinationWalletID: uuid.New().String(), Amount: "50.00", IdempotencyKey: uuid.New().String(), Reference: "salary"},
+		}})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+sourceID+"/transfers/bulk", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, sourceID, received.SourceWalletID)
+		assert.Len(t, received.Transfers, 2)
+		data := decodeResponseData(t, w)
+		assert.Equal(t, "PARTIAL", data["status"])
+		assert.Len(t, data["items"], 2)
+	})
+
+	t.Run("InvalidItemAmount", func(t *testing.T) {
+		userID := uuid.New().String()
+
+		cmdBus, qBus := buildWalletBuses(nil, nil, nil, nil, ownerGetWalletMock(userID), nil)
+		cqrs.RegisterCommandHandler[dtos.BulkTransferCommand, *dtos.BulkTransferResultDTO](cmdBus, &mockBulkTransferUseCase{})
+		handler := NewWalletHandler(cmdBus, qBus)
+		router := setupWalletTestRouterWithAuth(handler, userID)
+
+		body, _ := json.Marshal(BulkTransferRequest{Transfers: []BulkTransferItemRequest{
+			{DestinationWalletID: uuid.New().String(), IdempotencyKey: uuid.New().String(), Reference: "salary"},
+		}})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+uuid.New().String()+"/transfers/bulk", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "transfers[0].amount")
+	})
+}
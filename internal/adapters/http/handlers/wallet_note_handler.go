@@ -0,0 +1,212 @@
+// Package handlers - Wallet support notes admin HTTP handlers.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Wallet Note Handler
+// ============================================
+
+// WalletNoteHandler обрабатывает admin запросы заметок поддержки на кошельке.
+// Роль admin/superadmin проверяется группой /admin в роутере; удалить чужую
+// заметку может только superadmin (проверяется в use case).
+type WalletNoteHandler struct {
+	commandBus *cqrs.CommandBus
+	queryBus   *cqrs.QueryBus
+}
+
+// NewWalletNoteHandler создаёт новый WalletNoteHandler.
+func NewWalletNoteHandler(commandBus *cqrs.CommandBus, queryBus *cqrs.QueryBus) *WalletNoteHandler {
+	return &WalletNoteHandler{
+		commandBus: commandBus,
+		queryBus:   queryBus,
+	}
+}
+
+// ============================================
+// Request DTOs
+// ============================================
+
+// CreateWalletNoteRequest - текст новой заметки.
+//
+// @Description Create wallet note request body
+type CreateWalletNoteRequest struct {
+	Body string `json:"body" binding:"required,min=1,max=2000"`
+}
+
+// UpdateWalletNoteRequest - закрепление заметки.
+//
+// @Description Update wallet note request body
+type UpdateWalletNoteRequest struct {
+	Pinned *bool `json:"pinned" binding:"required"`
+}
+
+// WalletNoteParams - ID кошелька и заметки из URL.
+type WalletNoteParams struct {
+	ID     string `uri:"id" binding:"required,uuid"`
+	NoteID string `uri:"note_id" binding:"required,uuid"`
+}
+
+// ============================================
+// HTTP Handlers
+// ============================================
+
+// ListWalletNotes возвращает заметки кошелька: закреплённые первыми, затем новые.
+//
+// @Summary List wallet notes
+// @Description Support notes on a wallet, pinned first then newest first; deleted notes are hidden (admin only)
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Wallet ID" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20) maximum(100)
+// @Success 200 {object} common.APIResponse{data=dtos.WalletNoteListDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Router /api/v1/admin/wallets/{id}/notes [get]
+func (h *WalletNoteHandler) ListWalletNotes(c *gin.Context) {
+	var params WalletIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	pagination := ParsePagination(c)
+	query := dtos.ListWalletNotesQuery{
+		WalletID: params.ID,
+		Offset:   pagination.Offset(),
+		Limit:    pagination.PerPage,
+	}
+
+	result, err := cqrs.DispatchQuery[dtos.ListWalletNotesQuery, *dtos.WalletNoteListDTO](h.queryBus, c.Request.Context(), query)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	meta := BuildMeta(pagination, result.TotalCount)
+	SuccessList(c, nil, meta, result, "notes", result.Notes)
+}
+
+// CreateWalletNote добавляет заметку к кошельку от имени текущего администратора.
+//
+// @Summary Add wallet note
+// @Description Attach an internal support note to a wallet; never shown to the wallet owner (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID" format(uuid)
+// @Param request body CreateWalletNoteRequest true "Note"
+// @Success 201 {object} common.APIResponse{data=dtos.WalletNoteDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Router /api/v1/admin/wallets/{id}/notes [post]
+func (h *WalletNoteHandler) CreateWalletNote(c *gin.Context) {
+	var params WalletIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	var req CreateWalletNoteRequest
+	if !BindJSON(c, &req) {
+		return
+	}
+
+	cmd := dtos.CreateWalletNoteCommand{
+		WalletID: params.ID,
+		Body:     req.Body,
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.CreateWalletNoteCommand, *dtos.WalletNoteDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusCreated, result)
+}
+
+// UpdateWalletNote закрепляет или открепляет заметку.
+//
+// @Summary Pin or unpin wallet note
+// @Description Pinned notes are listed first (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID" format(uuid)
+// @Param note_id path string true "Note ID" format(uuid)
+// @Param request body UpdateWalletNoteRequest true "Pinned flag"
+// @Success 200 {object} common.APIResponse{data=dtos.WalletNoteDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Router /api/v1/admin/wallets/{id}/notes/{note_id} [patch]
+func (h *WalletNoteHandler) UpdateWalletNote(c *gin.Context) {
+	var params WalletNoteParams
+	if !BindURI(c, &params) {
+		return
+	}
+
+	var req UpdateWalletNoteRequest
+	if !BindJSON(c, &req) {
+		return
+	}
+
+	cmd := dtos.SetWalletNotePinnedCommand{
+		WalletID: params.ID,
+		NoteID:   params.NoteID,
+		Pinned:   *req.Pinned,
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.SetWalletNotePinnedCommand, *dtos.WalletNoteDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// DeleteWalletNote мягко удаляет заметку: она скрывается из списка,
+// но остаётся в хранилище для аудита.
+//
+// @Summary Delete wallet note
+// @Description Soft-delete a note; allowed for its author or a superadmin
+// @Tags Admin
+// @Param id path string true "Wallet ID" format(uuid)
+// @Param note_id path string true "Note ID" format(uuid)
+// @Success 204
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Router /api/v1/admin/wallets/{id}/notes/{note_id} [delete]
+func (h *WalletNoteHandler) DeleteWalletNote(c *gin.Context) {
+	var params WalletNoteParams
+	if !BindURI(c, &params) {
+		return
+	}
+
+	cmd := dtos.DeleteWalletNoteCommand{
+		WalletID: params.ID,
+		NoteID:   params.NoteID,
+	}
+
+	if _, err := cqrs.DispatchCommand[dtos.DeleteWalletNoteCommand, *dtos.WalletNoteDTO](h.commandBus, c.Request.Context(), cmd); err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
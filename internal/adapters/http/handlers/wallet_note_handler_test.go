@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockListWalletNotesUseCase struct {
+	ExecuteFn func(ctx context.Context, query dtos.ListWalletNotesQuery) (*dtos.WalletNoteListDTO, error)
+}
+
+func (m *mockListWalletNotesUseCase) Execute(ctx context.Context, query dtos.ListWalletNotesQuery) (*dtos.WalletNoteListDTO, error) {
+	return m.ExecuteFn(ctx, query)
+}
+
+type mockCreateWalletNoteUseCase struct {
+	ExecuteFn func(ctx context.Context, cmd dtos.CreateWalletNoteCommand) (*dtos.WalletNoteDTO, error)
+}
+
+func (m *mockCreateWalletNoteUseCase) Execute(ctx context.Context, cmd dtos.CreateWalletNoteCommand) (*dtos.WalletNoteDTO, error) {
+	return m.ExecuteFn(ctx, cmd)
+}
+
+type mockSetWalletNotePinnedUseCase struct {
+	ExecuteFn func(ctx context.Context, cmd dtos.SetWalletNotePinnedCommand) (*dtos.WalletNoteDTO, error)
+}
+
+func (m *mockSetWalletNotePinnedUseCase) Execute(ctx context.Context, cmd dtos.SetWalletNotePinnedCommand) (*dtos.WalletNoteDTO, error) {
+	return m.ExecuteFn(ctx, cmd)
+}
+
+type mockDeleteWalletNoteUseCase struct {
+	ExecuteFn func(ctx context.Context, cmd dtos.DeleteWalletNoteCommand) (*dtos.WalletNoteDTO, error)
+}
+
+func (m *mockDeleteWalletNoteUseCase) Execute(ctx context.Context, cmd dtos.DeleteWalletNoteCommand) (*dtos.WalletNoteDTO, error) {
+	return m.ExecuteFn(ctx, cmd)
+}
+
+// walletNoteMocks - use cases заметок; незаданные не регистрируются в шине.
+type walletNoteMocks struct {
+	list   *mockListWalletNotesUseCase
+	create *mockCreateWalletNoteUseCase
+	pin    *mockSetWalletNotePinnedUseCase
+	delete *mockDeleteWalletNoteUseCase
+}
+
+func setupWalletNoteTestRouter(m walletNoteMocks) *gin.Engine {
+	cmdBus := cqrs.NewCommandBus()
+	qBus := cqrs.NewQueryBus()
+	if m.list != nil {
+		cqrs.RegisterQueryHandler[dtos.ListWalletNotesQuery, *dtos.WalletNoteListDTO](qBus, m.list)
+	}
+	if m.create != nil {
+		cqrs.RegisterCommandHandler[dtos.CreateWalletNoteCommand, *dtos.WalletNoteDTO](cmdBus, m.create)
+	}
+	if m.pin != nil {
+		cqrs.RegisterCommandHandler[dtos.SetWalletNotePinnedCommand, *dtos.WalletNoteDTO](cmdBus, m.pin)
+	}
+	if m.delete != nil {
+		cqrs.RegisterCommandHandler[dtos.DeleteWalletNoteCommand, *dtos.WalletNoteDTO](cmdBus, m.delete)
+	}
+
+	handler := NewWalletNoteHandler(cmdBus, qBus)
+	router := gin.New()
+	router.GET("/api/v1/admin/wallets/:id/notes", handler.ListWalletNotes)
+	router.POST("/api/v1/admin/wallets/:id/notes", handler.CreateWalletNote)
+	router.PATCH("/api/v1/admin/wallets/:id/notes/:note_id", handler.UpdateWalletNote)
+	router.DELETE("/api/v1/admin/wallets/:id/notes/:note_id", handler.DeleteWalletNote)
+	return router
+}
+
+func TestWalletNoteHandler_ListWalletNotes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	walletID := uuid.NewString()
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var got dtos.ListWalletNotesQuery
+	router := setupWalletNoteTestRouter(walletNoteMocks{list: &mockListWalletNotesUseCase{
+		ExecuteFn: func(_ context.Context, query dtos.ListWalletNotesQuery) (*dtos.WalletNoteListDTO, error) {
+			got = query
+			return &dtos.WalletNoteListDTO{
+				Notes: []dtos.WalletNoteDTO{
+					{ID: "pinned", WalletID: walletID, Body: "pinned", Pinned: true, CreatedAt: createdAt},
+					{ID: "newest", WalletID: walletID, Body: "newest", CreatedAt: createdAt.Add(time.Hour)},
+				},
+				TotalCount: 2,
+				Offset:     query.Offset,
+				Limit:      query.Limit,
+			}, nil
+		},
+	}})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/wallets/"+walletID+"/notes?page=2&per_page=10", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, dtos.ListWalletNotesQuery{WalletID: walletID, Offset: 10, Limit: 10}, got)
+
+	var body struct {
+		Data struct {
+			Notes []dtos.WalletNoteDTO `json:"notes"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Data.Notes, 2)
+	assert.True(t, body.Data.Notes[0].Pinned)
+	assert.Equal(t, "newest", body.Data.Notes[1].ID)
+}
+
+func TestWalletNoteHandler_CreateWalletNote(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	walletID := uuid.NewString()
+	create := &mockCreateWalletNoteUseCase{
+		ExecuteFn: func(_ context.Context, cmd dtos.CreateWalletNoteCommand) (*dtos.WalletNoteDTO, error) {
+			return &dtos.WalletNoteDTO{ID: uuid.NewString(), WalletID: cmd.WalletID, Body: cmd.Body}, nil
+		},
+	}
+	router := setupWalletNoteTestRouter(walletNoteMocks{create: create})
+
+	t.Run("Created", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/wallets/"+walletID+"/notes",
+			strings.NewReader(`{"body":"customer called about failed payout"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"body":"customer called about failed payout"`)
+	})
+
+	t.Run("TooLong", func(t *testing.T) {
+		// 2001 руна: лимит в рунах, а не в байтах
+		body, _ := json.Marshal(map[string]string{"body": strings.Repeat("ж", 2001)})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/wallets/"+walletID+"/notes", strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"body"`)
+	})
+
+	t.Run("WalletNotFound", func(t *testing.T) {
+		router := setupWalletNoteTestRouter(walletNoteMocks{create: &mockCreateWalletNoteUseCase{
+			ExecuteFn: func(context.Context, dtos.CreateWalletNoteCommand) (*dtos.WalletNoteDTO, error) {
+				return nil, domainErrors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", nil)
+			},
+		}})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/wallets/"+walletID+"/notes", strings.NewReader(`{"body":"note"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestWalletNoteHandler_UpdateWalletNote(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	walletID, noteID := uuid.NewString(), uuid.NewString()
+	var got dtos.SetWalletNotePinnedCommand
+	router := setupWalletNoteTestRouter(walletNoteMocks{pin: &mockSetWalletNotePinnedUseCase{
+		ExecuteFn: func(_ context.Context, cmd dtos.SetWalletNotePinnedCommand) (*dtos.WalletNoteDTO, error) {
+			got = cmd
+			return &dtos.WalletNoteDTO{ID: cmd.NoteID, WalletID: cmd.WalletID, Pinned: cmd.Pinned}, nil
+		},
+	}})
+	path := "/api/v1/admin/wallets/" + walletID + "/notes/" + noteID
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, path, strings.NewReader(`{"pinned":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, dtos.SetWalletNotePinnedCommand{WalletID: walletID, NoteID: noteID, Pinned: true}, got)
+
+	// pinned обязателен: пустое тело не открепляет заметку молча
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPatch, path, strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWalletNoteHandler_DeleteWalletNote(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	walletID, noteID := uuid.NewString(), uuid.NewString()
+	path := "/api/v1/admin/wallets/" + walletID + "/notes/" + noteID
+
+	t.Run("Deleted", func(t *testing.T) {
+		router := setupWalletNoteTestRouter(walletNoteMocks{delete: &mockDeleteWalletNoteUseCase{
+			ExecuteFn: func(_ context.Context, cmd dtos.DeleteWalletNoteCommand) (*dtos.WalletNoteDTO, error) {
+				return &dtos.WalletNoteDTO{ID: cmd.NoteID}, nil
+			},
+		}})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, path, nil))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("NotAuthor", func(t *testing.T) {
+		router := setupWalletNoteTestRouter(walletNoteMocks{delete: &mockDeleteWalletNoteUseCase{
+			ExecuteFn: func(context.Context, dtos.DeleteWalletNoteCommand) (*dtos.WalletNoteDTO, error) {
+				return nil, domainErrors.NewDomainError("WALLET_NOTE_DELETE_FORBIDDEN", "only the author or a superadmin can delete a note", nil)
+			},
+		}})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, path, nil))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "WALLET_NOTE_DELETE_FORBIDDEN")
+	})
+
+	t.Run("InvalidNoteID", func(t *testing.T) {
+		router := setupWalletNoteTestRouter(walletNoteMocks{})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/wallets/"+walletID+"/notes/not-a-uuid", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,245 @@
+// Package handlers - Webhook subscriptions HTTP handlers.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Webhook Handler
+// ============================================
+
+// WebhookHandler обрабатывает запросы webhook подписок текущего пользователя.
+// Владелец берётся из токена; чужая подписка отвечает 404.
+type WebhookHandler struct {
+	commandBus *cqrs.CommandBus
+	queryBus   *cqrs.QueryBus
+}
+
+// NewWebhookHandler создаёт новый WebhookHandler.
+func NewWebhookHandler(commandBus *cqrs.CommandBus, queryBus *cqrs.QueryBus) *WebhookHandler {
+	return &WebhookHandler{
+		commandBus: commandBus,
+		queryBus:   queryBus,
+	}
+}
+
+// ============================================
+// Request DTOs
+// ============================================
+
+// WebhookIDParam - параметр ID подписки из URL.
+type WebhookIDParam struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// CreateWebhookRequest - новая webhook подписка.
+//
+// @Description Create webhook subscription request body
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,max=2048"`
+	EventTypes []string `json:"event_types" binding:"max=20"` // пусто - все типы
+}
+
+// UpdateWebhookRequest - изменение подписки; отсутствующие поля не меняются.
+//
+// @Description Update webhook subscription request body
+type UpdateWebhookRequest struct {
+	URL         *string   `json:"url" binding:"omitempty,max=2048"`
+	EventTypes  *[]string `json:"event_types" binding:"omitempty,max=20"`
+	Active      *bool     `json:"active"`
+	SkipBacklog bool      `json:"skip_backlog"` // с active=true: пропустить накопленные доставки
+}
+
+// ============================================
+// HTTP Handlers
+// ============================================
+
+// CreateWebhook создаёт webhook подписку.
+//
+// @Summary Create webhook subscription
+// @Description Subscribe an HTTPS endpoint to events of the current user. The signing secret is returned only in this response.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param request body CreateWebhookRequest true "Subscription"
+// @Success 201 {object} common.APIResponse{data=dtos.WebhookSubscriptionCreatedDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Router /api/v1/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req CreateWebhookRequest
+	if !BindJSON(c, &req) {
+		return
+	}
+
+	cmd := dtos.CreateWebhookCommand{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.CreateWebhookCommand, *dtos.WebhookSubscriptionCreatedDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusCreated, result)
+}
+
+// ListWebhooks возвращает подписки текущего пользователя.
+//
+// @Summary List webhook subscriptions
+// @Description Webhook subscriptions of the current user, newest first; deleted subscriptions are hidden
+// @Tags Webhooks
+// @Produce json
+// @Success 200 {object} common.APIResponse{data=dtos.WebhookSubscriptionListDTO}
+// @Failure 401 {object} common.APIResponse
+// @Router /api/v1/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	result, err := cqrs.DispatchQuery[dtos.ListWebhooksQuery, *dtos.WebhookSubscriptionListDTO](h.queryBus, c.Request.Context(), dtos.ListWebhooksQuery{})
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// GetWebhook возвращает подписку со статусом здоровья.
+//
+// @Summary Get webhook subscription
+// @Description Subscription with its status (ACTIVE, DISABLED_MANUAL, DISABLED_AUTO) and failure counters
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Subscription ID" format(uuid)
+// @Success 200 {object} common.APIResponse{data=dtos.WebhookSubscriptionDTO}
+// @Failure 401 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Router /api/v1/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	var params WebhookIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	query := dtos.GetWebhookQuery{SubscriptionID: params.ID}
+
+	result, err := cqrs.DispatchQuery[dtos.GetWebhookQuery, *dtos.WebhookSubscriptionDTO](h.queryBus, c.Request.Context(), query)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// UpdateWebhook изменяет подписку, включая её отключение и повторное включение.
+//
+// @Summary Update webhook subscription
+// @Description Change URL or event types, disable (active=false) or re-enable (active=true) the subscription. Re-enabling resumes the pending backlog unless skip_backlog is set.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID" format(uuid)
+// @Param request body UpdateWebhookRequest true "Changes"
+// @Success 200 {object} common.APIResponse{data=dtos.WebhookSubscriptionDTO}
+// @Failure 400 {object} common.APIResponse
+// @Failure 401 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Failure 409 {object} common.APIResponse
+// @Router /api/v1/webhooks/{id} [patch]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	var params WebhookIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if !BindJSON(c, &req) {
+		return
+	}
+
+	cmd := dtos.UpdateWebhookCommand{
+		SubscriptionID: params.ID,
+		URL:            req.URL,
+		EventTypes:     req.EventTypes,
+		Active:         req.Active,
+		SkipBacklog:    req.SkipBacklog,
+	}
+
+	result, err := cqrs.DispatchCommand[dtos.UpdateWebhookCommand, *dtos.WebhookSubscriptionDTO](h.commandBus, c.Request.Context(), cmd)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
+
+// DeleteWebhook мягко удаляет подписку.
+//
+// @Summary Delete webhook subscription
+// @Description Stop deliveries and hide the subscription; its delivery history is kept until the retention purge
+// @Tags Webhooks
+// @Param id path string true "Subscription ID" format(uuid)
+// @Success 204
+// @Failure 401 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	var params WebhookIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	cmd := dtos.DeleteWebhookCommand{SubscriptionID: params.ID}
+
+	if _, err := cqrs.DispatchCommand[dtos.DeleteWebhookCommand, *dtos.WebhookSubscriptionDTO](h.commandBus, c.Request.Context(), cmd); err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries возвращает историю доставок подписки.
+//
+// @Summary List webhook deliveries
+// @Description Deliveries of a subscription, newest first, including the pending backlog of a disabled subscription
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Subscription ID" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20) maximum(100)
+// @Success 200 {object} common.APIResponse{data=dtos.WebhookDeliveryListDTO}
+// @Failure 401 {object} common.APIResponse
+// @Failure 404 {object} common.APIResponse
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+	var params WebhookIDParam
+	if !BindURI(c, &params) {
+		return
+	}
+
+	pagination := ParsePagination(c)
+	query := dtos.ListWebhookDeliveriesQuery{
+		SubscriptionID: params.ID,
+		Offset:         pagination.Offset(),
+		Limit:          pagination.PerPage,
+	}
+
+	result, err := cqrs.DispatchQuery[dtos.ListWebhookDeliveriesQuery, *dtos.WebhookDeliveryListDTO](h.queryBus, c.Request.Context(), query)
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	meta := BuildMeta(pagination, result.TotalCount)
+	SuccessList(c, nil, meta, result, "deliveries", result.Deliveries)
+}
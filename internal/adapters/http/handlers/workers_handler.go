@@ -0,0 +1,50 @@
+// Package handlers - Background workers admin HTTP handlers.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Workers Handler
+// ============================================
+
+// WorkersHandler обрабатывает admin запросы heartbeat фоновых компонентов.
+// Роль admin проверяется группой /admin в роутере.
+type WorkersHandler struct {
+	queryBus *cqrs.QueryBus
+}
+
+// NewWorkersHandler создаёт новый WorkersHandler.
+func NewWorkersHandler(queryBus *cqrs.QueryBus) *WorkersHandler {
+	return &WorkersHandler{queryBus: queryBus}
+}
+
+// ============================================
+// HTTP Handlers
+// ============================================
+
+// ListWorkers возвращает последний heartbeat каждого фонового компонента.
+//
+// @Summary List background workers
+// @Description Heartbeat of every background worker: state, last run, last success, items processed, last error and staleness (admin only)
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} common.APIResponse{data=dtos.WorkerListDTO}
+// @Failure 401 {object} common.APIResponse
+// @Failure 403 {object} common.APIResponse
+// @Router /api/v1/admin/workers [get]
+func (h *WorkersHandler) ListWorkers(c *gin.Context) {
+	result, err := cqrs.DispatchQuery[dtos.ListWorkersQuery, *dtos.WorkerListDTO](h.queryBus, c.Request.Context(), dtos.ListWorkersQuery{})
+	if err != nil {
+		common.HandleDomainError(c, err)
+		return
+	}
+
+	common.Success(c, http.StatusOK, result)
+}
@@ -0,0 +1,188 @@
+// Package httpctx содержит типизированные helpers для значений в gin.Context.
+//
+// Middleware и handlers обмениваются данными запроса (auth claims, Request ID,
+// locale) только через эти функции. Ключи - неэкспортируемого типа, поэтому
+// внешний пакет не может записать значение в обход setter'а или опечататься
+// в строковом ключе.
+//
+// Getters никогда не паникуют: отсутствующее значение или значение неожиданного
+// типа возвращаются как zero value (и false там, где есть флаг).
+package httpctx
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// contextKey - тип ключей gin.Context этого пакета.
+// gin хранит Keys как map[any]any, так что ключ не пересекается со строковыми.
+type contextKey int
+
+const (
+	authUserIDKey contextKey = iota
+	authEmailKey
+	authRoleKey
+	authJTIKey
+	authExpKey
+	authCapabilitiesKey
+	authScopesKey
+	requestIDKey
+	localeKey
+	securityRecorderKey
+)
+
+// ============================================
+// Auth
+// ============================================
+
+// SetAuthUserID сохраняет ID авторизованного пользователя.
+func SetAuthUserID(c *gin.Context, id uuid.UUID) {
+	c.Set(authUserIDKey, id)
+}
+
+// AuthUserID возвращает ID авторизованного пользователя.
+// false - пользователь не авторизован (значения нет или оно пустое).
+func AuthUserID(c *gin.Context) (uuid.UUID, bool) {
+	id, ok := get[uuid.UUID](c, authUserIDKey)
+	if !ok || id == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// SetAuthEmail сохраняет email авторизованного пользователя.
+func SetAuthEmail(c *gin.Context, email string) {
+	c.Set(authEmailKey, email)
+}
+
+// AuthEmail возвращает email авторизованного пользователя.
+func AuthEmail(c *gin.Context) string {
+	email, _ := get[string](c, authEmailKey)
+	return email
+}
+
+// SetAuthRole сохраняет роль авторизованного пользователя.
+func SetAuthRole(c *gin.Context, role string) {
+	c.Set(authRoleKey, role)
+}
+
+// AuthRole возвращает роль авторизованного пользователя.
+func AuthRole(c *gin.Context) string {
+	role, _ := get[string](c, authRoleKey)
+	return role
+}
+
+// SetAuthJTI сохраняет JTI (JWT ID) текущего токена.
+func SetAuthJTI(c *gin.Context, jti string) {
+	c.Set(authJTIKey, jti)
+}
+
+// AuthJTI возвращает JTI (JWT ID) текущего токена.
+func AuthJTI(c *gin.Context) string {
+	jti, _ := get[string](c, authJTIKey)
+	return jti
+}
+
+// SetAuthExp сохраняет время истечения текущего токена.
+func SetAuthExp(c *gin.Context, exp time.Time) {
+	c.Set(authExpKey, exp)
+}
+
+// AuthExp возвращает время истечения текущего токена.
+func AuthExp(c *gin.Context) (time.Time, bool) {
+	return get[time.Time](c, authExpKey)
+}
+
+// SetAuthCapabilities сохраняет capabilities текущего токена.
+func SetAuthCapabilities(c *gin.Context, capabilities []string) {
+	c.Set(authCapabilitiesKey, capabilities)
+}
+
+// HasAuthCapability сообщает, выдана ли токену capability.
+func HasAuthCapability(c *gin.Context, capability string) bool {
+	capabilities, _ := get[[]string](c, authCapabilitiesKey)
+	for _, granted := range capabilities {
+		if granted == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// SetAuthScopes сохраняет scopes токена API ключа (nil - токен без scopes).
+func SetAuthScopes(c *gin.Context, scopes []string) {
+	c.Set(authScopesKey, scopes)
+}
+
+// AuthScopes возвращает scopes токена API ключа. nil - токен пользователя,
+// не ограниченный scopes; пустой срез - API ключ без scopes.
+func AuthScopes(c *gin.Context) []string {
+	scopes, _ := get[[]string](c, authScopesKey)
+	return scopes
+}
+
+// ============================================
+// Request
+// ============================================
+
+// SetRequestID сохраняет Request ID.
+func SetRequestID(c *gin.Context, id string) {
+	c.Set(requestIDKey, id)
+}
+
+// RequestID возвращает Request ID или пустую строку.
+func RequestID(c *gin.Context) string {
+	id, _ := get[string](c, requestIDKey)
+	return id
+}
+
+// SetLocale сохраняет выбранную локаль запроса (например, "ru" или "en-US").
+func SetLocale(c *gin.Context, locale string) {
+	c.Set(localeKey, locale)
+}
+
+// Locale возвращает локаль запроса или пустую строку.
+func Locale(c *gin.Context) string {
+	locale, _ := get[string](c, localeKey)
+	return locale
+}
+
+// ============================================
+// Security
+// ============================================
+
+// SetSecurityRecorder сохраняет журнал событий безопасности, которым Auth
+// делится с последующими middleware (RequireRole).
+func SetSecurityRecorder(c *gin.Context, recorder ports.SecurityEventRecorder) {
+	c.Set(securityRecorderKey, recorder)
+}
+
+// SecurityRecorder возвращает журнал событий безопасности или nil.
+func SecurityRecorder(c *gin.Context) ports.SecurityEventRecorder {
+	recorder, _ := get[ports.SecurityEventRecorder](c, securityRecorderKey)
+	return recorder
+}
+
+// get достаёт значение по ключу с проверкой типа.
+func get[T any](c *gin.Context, key contextKey) (T, bool) {
+	var zero T
+	if c == nil {
+		return zero, false
+	}
+
+	raw, exists := c.Get(key)
+	if !exists {
+		return zero, false
+	}
+
+	value, ok := raw.(T)
+	if !ok {
+		return zero, false
+	}
+
+	return value, true
+}
@@ -0,0 +1,180 @@
+package httpctx
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+func newTestContext() *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	return c
+}
+
+func TestAuthUserID(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		c := newTestContext()
+		expected := uuid.New()
+		SetAuthUserID(c, expected)
+
+		id, ok := AuthUserID(c)
+
+		assert.True(t, ok)
+		assert.Equal(t, expected, id)
+	})
+
+	t.Run("NotSet", func(t *testing.T) {
+		id, ok := AuthUserID(newTestContext())
+
+		assert.False(t, ok)
+		assert.Equal(t, uuid.Nil, id)
+	})
+
+	t.Run("NilUUID", func(t *testing.T) {
+		c := newTestContext()
+		SetAuthUserID(c, uuid.Nil)
+
+		_, ok := AuthUserID(c)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("WrongTypeStored", func(t *testing.T) {
+		c := newTestContext()
+		c.Set(authUserIDKey, uuid.NewString()) // строка вместо uuid.UUID
+
+		id, ok := AuthUserID(c)
+
+		assert.False(t, ok)
+		assert.Equal(t, uuid.Nil, id)
+	})
+
+	t.Run("StringKeyDoesNotCollide", func(t *testing.T) {
+		c := newTestContext()
+		c.Set("auth_user_id", uuid.New())
+
+		_, ok := AuthUserID(c)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("NilContext", func(t *testing.T) {
+		_, ok := AuthUserID(nil)
+
+		assert.False(t, ok)
+	})
+}
+
+func TestAuthClaims(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		c := newTestContext()
+		exp := time.Now().Add(time.Hour)
+		SetAuthEmail(c, "test@example.com")
+		SetAuthRole(c, "admin")
+		SetAuthJTI(c, "jti-1")
+		SetAuthExp(c, exp)
+		SetAuthCapabilities(c, []string{"no_confirm"})
+		SetAuthScopes(c, []string{"wallets:read"})
+
+		gotExp, ok := AuthExp(c)
+
+		assert.Equal(t, "test@example.com", AuthEmail(c))
+		assert.Equal(t, "admin", AuthRole(c))
+		assert.Equal(t, "jti-1", AuthJTI(c))
+		assert.True(t, ok)
+		assert.Equal(t, exp, gotExp)
+		assert.True(t, HasAuthCapability(c, "no_confirm"))
+		assert.False(t, HasAuthCapability(c, "other"))
+		assert.Equal(t, []string{"wallets:read"}, AuthScopes(c))
+	})
+
+	t.Run("NotSet", func(t *testing.T) {
+		c := newTestContext()
+
+		_, ok := AuthExp(c)
+
+		assert.Empty(t, AuthEmail(c))
+		assert.Empty(t, AuthRole(c))
+		assert.Empty(t, AuthJTI(c))
+		assert.False(t, ok)
+		assert.False(t, HasAuthCapability(c, "no_confirm"))
+		assert.Nil(t, AuthScopes(c), "a token without scopes is not restricted")
+	})
+
+	t.Run("WrongTypeStored", func(t *testing.T) {
+		c := newTestContext()
+		c.Set(authEmailKey, 12345)
+		c.Set(authRoleKey, 12345)
+		c.Set(authJTIKey, 12345)
+		c.Set(authExpKey, "tomorrow")
+
+		_, ok := AuthExp(c)
+
+		assert.Empty(t, AuthEmail(c))
+		assert.Empty(t, AuthRole(c))
+		assert.Empty(t, AuthJTI(c))
+		assert.False(t, ok)
+	})
+}
+
+func TestRequestID(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		c := newTestContext()
+		SetRequestID(c, "req-123")
+
+		assert.Equal(t, "req-123", RequestID(c))
+	})
+
+	t.Run("NotSet", func(t *testing.T) {
+		assert.Empty(t, RequestID(newTestContext()))
+	})
+
+	t.Run("WrongTypeStored", func(t *testing.T) {
+		c := newTestContext()
+		c.Set(requestIDKey, 12345)
+
+		assert.Empty(t, RequestID(c))
+	})
+}
+
+func TestLocale(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		c := newTestContext()
+		SetLocale(c, "ru")
+
+		assert.Equal(t, "ru", Locale(c))
+	})
+
+	t.Run("WrongTypeStored", func(t *testing.T) {
+		c := newTestContext()
+		c.Set(localeKey, []string{"ru"})
+
+		assert.Empty(t, Locale(c))
+	})
+}
+
+// recorderStub - пустая реализация ports.SecurityEventRecorder.
+type recorderStub struct{}
+
+func (recorderStub) Record(*entities.SecurityEvent) {}
+
+func TestSecurityRecorder(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		c := newTestContext()
+		recorder := &recorderStub{}
+		SetSecurityRecorder(c, recorder)
+
+		assert.Same(t, recorder, SecurityRecorder(c))
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		assert.Nil(t, SecurityRecorder(newTestContext()))
+	})
+}
@@ -15,20 +15,9 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
 	"github.com/Haleralex/wallethub/internal/application/ports"
-)
-
-const (
-	// AuthUserIDKey - ключ для хранения User ID в контексте
-	AuthUserIDKey = "auth_user_id"
-	// AuthUserEmailKey - ключ для хранения email пользователя
-	AuthUserEmailKey = "auth_user_email"
-	// AuthUserRoleKey - ключ для хранения роли пользователя
-	AuthUserRoleKey = "auth_user_role"
-	// AuthJTIKey - ключ для хранения JTI (JWT ID) в контексте
-	AuthJTIKey = "auth_jti"
-	// AuthExpKey - ключ для хранения времени истечения токена в контексте
-	AuthExpKey = "auth_exp"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
 )
 
 // AuthConfig - конфигурация для authentication middleware.
@@ -38,6 +27,9 @@ type AuthConfig struct {
 	TokenValidator func(token string) (*AuthClaims, error)
 	// SkipPaths - пути, которые не требуют авторизации
 	SkipPaths []string
+	// SecurityLog - журнал исходов проверки (AUTH_FAILED, AUTH_SUCCEEDED,
+	// SCOPE_DENIED в RequireRole). nil - не записывать.
+	SecurityLog ports.SecurityEventRecorder
 }
 
 // AuthClaims - данные из токена авторизации.
@@ -49,8 +41,20 @@ type AuthClaims struct {
 	Role   string
 	Exp    time.Time
 	JTI    string // JWT ID — unique token identifier, used for revocation
+	// Capabilities - дополнительные права токена (claim "capabilities"),
+	// например CapabilityNoConfirm для автоматизации
+	Capabilities []string
+	// Scopes - scopes API ключа (claim "scopes"): токен с claim проходит
+	// только политики policy.Scope из списка. nil - токен пользователя
+	// без ограничений
+	Scopes []string
 }
 
+// CapabilityTrustedPartner - capability токена партнёра, которому разрешено
+// одобрять KYC при onboarding; действует только для партнёров из
+// onboarding.trusted_partners.
+const CapabilityTrustedPartner = "trusted_partner"
+
 // Auth middleware для проверки авторизации.
 //
 // Схема работы:
@@ -74,50 +78,97 @@ func Auth(config *AuthConfig) gin.HandlerFunc {
 			return
 		}
 
+		if config.SecurityLog != nil {
+			httpctx.SetSecurityRecorder(c, config.SecurityLog)
+		}
+		fail := func(principal, message string) {
+			recordSecurityEvent(c, config.SecurityLog, principal, entities.SecurityEventAuthFailed)
+			abortWithUnauthorized(c, message)
+		}
+
 		// Извлекаем токен из заголовка
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			abortWithUnauthorized(c, "Authorization header is required")
+			fail("", "Authorization header is required")
 			return
 		}
 
 		// Проверяем формат "Bearer <token>"
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			abortWithUnauthorized(c, "Invalid authorization header format")
+			fail("", "Invalid authorization header format")
 			return
 		}
 
 		token := parts[1]
 		if token == "" {
-			abortWithUnauthorized(c, "Token is required")
+			fail("", "Token is required")
 			return
 		}
 
 		// Валидируем токен
 		claims, err := config.TokenValidator(token)
 		if err != nil {
-			abortWithUnauthorized(c, "Invalid or expired token")
+			fail(failedTokenPrincipal(token), "Invalid or expired token")
 			return
 		}
 
 		// Проверяем expiration
 		if claims.Exp.Before(time.Now()) {
-			abortWithUnauthorized(c, "Token has expired")
+			fail(claims.UserID, "Token has expired")
 			return
 		}
 
-		// Сохраняем claims в контекст
-		c.Set(AuthUserIDKey, claims.UserID)
-		c.Set(AuthUserEmailKey, claims.Email)
-		c.Set(AuthUserRoleKey, claims.Role)
-		c.Set(AuthJTIKey, claims.JTI)
-		c.Set(AuthExpKey, claims.Exp)
-
+		// Сохраняем claims в контекст.
+		// Subject не в формате UUID не записывается - handlers ответят 401.
+		if userID, err := uuid.Parse(claims.UserID); err == nil {
+			httpctx.SetAuthUserID(c, userID)
+			// Actor для use cases (request context, а не gin.Context)
+			c.Request = c.Request.WithContext(
+				ports.WithActor(c.Request.Context(), ports.Actor{ID: userID, Role: claims.Role}),
+			)
+		}
+		httpctx.SetAuthEmail(c, claims.Email)
+		httpctx.SetAuthRole(c, claims.Role)
+		httpctx.SetAuthJTI(c, claims.JTI)
+		httpctx.SetAuthExp(c, claims.Exp)
+		httpctx.SetAuthCapabilities(c, claims.Capabilities)
+		httpctx.SetAuthScopes(c, claims.Scopes)
+
+		recordSecurityEvent(c, config.SecurityLog, claims.UserID, entities.SecurityEventAuthSucceeded)
 		c.Next()
 	}
 }
 
+// credentialPrefixLen - сколько символов отклонённого токена попадает в журнал.
+const credentialPrefixLen = 8
+
+// failedTokenPrincipal определяет, к кому отнести отклонённый токен.
+//
+// Подпись не проверяется: sub из JWT указывает, на чей аккаунт направлена
+// попытка, даже если токен поддельный. Токен без sub (или не JWT)
+// журналируется коротким префиксом "key:<8 символов>" - целиком его не храним.
+func failedTokenPrincipal(token string) string {
+	var claims jwt.RegisteredClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err == nil && claims.Subject != "" {
+		return claims.Subject
+	}
+
+	prefix := token
+	if len(prefix) > credentialPrefixLen {
+		prefix = prefix[:credentialPrefixLen]
+	}
+	return "key:" + prefix
+}
+
+// recordSecurityEvent передаёт исход проверки в журнал (без блокировки).
+func recordSecurityEvent(c *gin.Context, recorder ports.SecurityEventRecorder, principal string, eventType entities.SecurityEventType) {
+	if recorder == nil {
+		return
+	}
+	recorder.Record(entities.NewSecurityEvent(principal, eventType, c.ClientIP(), c.Request.UserAgent()))
+}
+
 // abortWithUnauthorized отправляет 401 ответ.
 func abortWithUnauthorized(c *gin.Context, message string) {
 	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
@@ -126,7 +177,7 @@ func abortWithUnauthorized(c *gin.Context, message string) {
 			"code":    "UNAUTHORIZED",
 			"message": message,
 		},
-		"request_id": GetRequestID(c),
+		"request_id": httpctx.RequestID(c),
 		"timestamp":  time.Now().UTC(),
 	})
 }
@@ -134,6 +185,7 @@ func abortWithUnauthorized(c *gin.Context, message string) {
 // RequireRole middleware проверяет роль пользователя.
 //
 // Используется после Auth middleware для проверки разрешений.
+// Отказ записывается как SCOPE_DENIED в журнал, переданный Auth.
 func RequireRole(roles ...string) gin.HandlerFunc {
 	roleMap := make(map[string]bool)
 	for _, role := range roles {
@@ -141,14 +193,23 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 	}
 
 	return func(c *gin.Context) {
-		userRole := GetAuthUserRole(c)
+		deny := func(message string) {
+			var principal string
+			if userID, ok := httpctx.AuthUserID(c); ok {
+				principal = userID.String()
+			}
+			recordSecurityEvent(c, httpctx.SecurityRecorder(c), principal, entities.SecurityEventScopeDenied)
+			abortWithForbidden(c, message)
+		}
+
+		userRole := httpctx.AuthRole(c)
 		if userRole == "" {
-			abortWithForbidden(c, "User role not found")
+			deny("User role not found")
 			return
 		}
 
 		if !roleMap[userRole] {
-			abortWithForbidden(c, "Insufficient permissions")
+			deny("Insufficient permissions")
 			return
 		}
 
@@ -164,67 +225,11 @@ func abortWithForbidden(c *gin.Context, message string) {
 			"code":    "FORBIDDEN",
 			"message": message,
 		},
-		"request_id": GetRequestID(c),
+		"request_id": httpctx.RequestID(c),
 		"timestamp":  time.Now().UTC(),
 	})
 }
 
-// ============================================
-// Helper functions для извлечения auth данных
-// ============================================
-
-// GetAuthUserID возвращает ID авторизованного пользователя.
-func GetAuthUserID(c *gin.Context) uuid.UUID {
-	if id, exists := c.Get(AuthUserIDKey); exists {
-		if strID, ok := id.(string); ok {
-			if uid, err := uuid.Parse(strID); err == nil {
-				return uid
-			}
-		}
-	}
-	return uuid.Nil
-}
-
-// GetAuthUserEmail возвращает email авторизованного пользователя.
-func GetAuthUserEmail(c *gin.Context) string {
-	if email, exists := c.Get(AuthUserEmailKey); exists {
-		if strEmail, ok := email.(string); ok {
-			return strEmail
-		}
-	}
-	return ""
-}
-
-// GetAuthUserRole возвращает роль авторизованного пользователя.
-func GetAuthUserRole(c *gin.Context) string {
-	if role, exists := c.Get(AuthUserRoleKey); exists {
-		if strRole, ok := role.(string); ok {
-			return strRole
-		}
-	}
-	return ""
-}
-
-// GetAuthJTI возвращает JTI (JWT ID) из контекста.
-func GetAuthJTI(c *gin.Context) string {
-	if jti, exists := c.Get(AuthJTIKey); exists {
-		if strJTI, ok := jti.(string); ok {
-			return strJTI
-		}
-	}
-	return ""
-}
-
-// GetAuthExp возвращает время истечения токена из контекста.
-func GetAuthExp(c *gin.Context) time.Time {
-	if exp, exists := c.Get(AuthExpKey); exists {
-		if t, ok := exp.(time.Time); ok {
-			return t
-		}
-	}
-	return time.Time{}
-}
-
 // ============================================
 // Development/Testing Helpers
 // ============================================
@@ -286,15 +291,41 @@ func NewJWTTokenValidator(secret string, issuer string, blacklist ports.TokenBla
 		}
 
 		return &AuthClaims{
-			UserID: userID,
-			Email:  email,
-			Role:   role,
-			Exp:    exp,
-			JTI:    jti,
+			UserID:       userID,
+			Email:        email,
+			Role:         role,
+			Exp:          exp,
+			JTI:          jti,
+			Capabilities: stringListClaim(claims["capabilities"]),
+			Scopes:       scopesClaim(claims),
 		}, nil
 	}
 }
 
+// scopesClaim returns the "scopes" claim. A token without the claim gets nil
+// (not restricted); a present but empty claim gets an empty non-nil slice,
+// so an API key without scopes passes no Scope policy.
+func scopesClaim(claims jwt.MapClaims) []string {
+	raw, ok := claims["scopes"]
+	if !ok {
+		return nil
+	}
+	return append([]string{}, stringListClaim(raw)...)
+}
+
+// stringListClaim converts a JSON array claim to []string, skipping
+// non-string items.
+func stringListClaim(raw interface{}) []string {
+	items, _ := raw.([]interface{})
+	var result []string
+	for _, item := range items {
+		if value, ok := item.(string); ok && value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
 // GenerateJWT creates a signed JWT token with HS256.
 // Each token has a unique JTI (JWT ID) claim for revocation support.
 func GenerateJWT(secret, issuer, userID, email, role string, expiry time.Duration) (string, error) {
@@ -10,6 +10,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
 )
 
 func TestAuth(t *testing.T) {
@@ -197,14 +201,50 @@ func TestAuth(t *testing.T) {
 		router := gin.New()
 		router.Use(Auth(config))
 		router.GET("/test", func(c *gin.Context) {
-			gotUserID := GetAuthUserID(c)
-			gotEmail := GetAuthUserEmail(c)
-			gotRole := GetAuthUserRole(c)
+			gotUserID, ok := httpctx.AuthUserID(c)
+			gotEmail := httpctx.AuthEmail(c)
+			gotRole := httpctx.AuthRole(c)
 
+			assert.True(t, ok)
 			assert.Equal(t, userID, gotUserID.String())
 			assert.Equal(t, email, gotEmail)
 			assert.Equal(t, role, gotRole)
 
+			actor, ok := ports.ActorFromContext(c.Request.Context())
+			assert.True(t, ok)
+			assert.Equal(t, userID, actor.ID.String())
+			assert.True(t, actor.IsAdmin())
+
+			c.JSON(200, gin.H{"status": "ok"})
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", "Bearer valid-token")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("NonUUIDSubjectNotStored", func(t *testing.T) {
+		config := &AuthConfig{
+			TokenValidator: func(token string) (*AuthClaims, error) {
+				return &AuthClaims{
+					UserID: "user-123",
+					Role:   "user",
+					Exp:    time.Now().Add(1 * time.Hour),
+				}, nil
+			},
+		}
+
+		router := gin.New()
+		router.Use(Auth(config))
+		router.GET("/test", func(c *gin.Context) {
+			_, ok := httpctx.AuthUserID(c)
+			assert.False(t, ok)
+			assert.Equal(t, "user", httpctx.AuthRole(c))
+
 			c.JSON(200, gin.H{"status": "ok"})
 		})
 
@@ -224,7 +264,7 @@ func TestRequireRole(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		router := gin.New()
 		router.Use(func(c *gin.Context) {
-			c.Set(AuthUserRoleKey, "admin")
+			httpctx.SetAuthRole(c, "admin")
 			c.Next()
 		})
 		router.Use(RequireRole("admin", "moderator"))
@@ -243,7 +283,7 @@ func TestRequireRole(t *testing.T) {
 	t.Run("InsufficientPermissions", func(t *testing.T) {
 		router := gin.New()
 		router.Use(func(c *gin.Context) {
-			c.Set(AuthUserRoleKey, "user")
+			httpctx.SetAuthRole(c, "user")
 			c.Next()
 		})
 		router.Use(RequireRole("admin"))
@@ -275,105 +315,103 @@ func TestRequireRole(t *testing.T) {
 	})
 }
 
-func TestGetAuthUserID(t *testing.T) {
-	gin.SetMode(gin.TestMode)
-
-	t.Run("ValidID", func(t *testing.T) {
-		c, _ := gin.CreateTestContext(httptest.NewRecorder())
-		expectedID := uuid.New()
-		c.Set(AuthUserIDKey, expectedID.String())
-
-		result := GetAuthUserID(c)
-
-		assert.Equal(t, expectedID, result)
-	})
-
-	t.Run("NotSet", func(t *testing.T) {
-		c, _ := gin.CreateTestContext(httptest.NewRecorder())
-
-		result := GetAuthUserID(c)
-
-		assert.Equal(t, uuid.Nil, result)
-	})
-
-	t.Run("InvalidType", func(t *testing.T) {
-		c, _ := gin.CreateTestContext(httptest.NewRecorder())
-		c.Set(AuthUserIDKey, 12345) // Wrong type
-
-		result := GetAuthUserID(c)
-
-		assert.Equal(t, uuid.Nil, result)
-	})
-
-	t.Run("InvalidUUID", func(t *testing.T) {
-		c, _ := gin.CreateTestContext(httptest.NewRecorder())
-		c.Set(AuthUserIDKey, "not-a-uuid")
-
-		result := GetAuthUserID(c)
+// securityEventSink собирает события, переданные в журнал безопасности.
+type securityEventSink struct {
+	events []*entities.SecurityEvent
+}
 
-		assert.Equal(t, uuid.Nil, result)
-	})
+func (s *securityEventSink) Record(event *entities.SecurityEvent) {
+	s.events = append(s.events, event)
 }
 
-func TestGetAuthUserEmail(t *testing.T) {
+func TestAuth_SecurityLog(t *testing.T) {
 	gin.SetMode(gin.TestMode)
+	const secret = "security-log-secret"
 
-	t.Run("ValidEmail", func(t *testing.T) {
-		c, _ := gin.CreateTestContext(httptest.NewRecorder())
-		expectedEmail := "test@example.com"
-		c.Set(AuthUserEmailKey, expectedEmail)
+	serve := func(sink *securityEventSink, authHeader string, middlewares ...gin.HandlerFunc) int {
+		router := gin.New()
+		router.Use(Auth(&AuthConfig{
+			TokenValidator: NewJWTTokenValidator(secret, "", nil),
+			SecurityLog:    sink,
+		}))
+		router.Use(middlewares...)
+		router.GET("/test", func(c *gin.Context) {
+			c.JSON(200, gin.H{"status": "ok"})
+		})
 
-		result := GetAuthUserEmail(c)
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("User-Agent", "auth-test/1.0")
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
 
-		assert.Equal(t, expectedEmail, result)
-	})
+	userID := uuid.NewString()
 
-	t.Run("NotSet", func(t *testing.T) {
-		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	t.Run("Succeeded", func(t *testing.T) {
+		sink := &securityEventSink{}
+		token, err := GenerateJWT(secret, "", userID, "user@example.com", "user", time.Hour)
+		assert.NoError(t, err)
 
-		result := GetAuthUserEmail(c)
+		assert.Equal(t, http.StatusOK, serve(sink, "Bearer "+token))
 
-		assert.Equal(t, "", result)
+		if assert.Len(t, sink.events, 1) {
+			assert.Equal(t, entities.SecurityEventAuthSucceeded, sink.events[0].Type())
+			assert.Equal(t, userID, sink.events[0].Principal())
+			assert.Equal(t, "auth-test/1.0", sink.events[0].UserAgent())
+			assert.NotEmpty(t, sink.events[0].IP())
+		}
 	})
 
-	t.Run("InvalidType", func(t *testing.T) {
-		c, _ := gin.CreateTestContext(httptest.NewRecorder())
-		c.Set(AuthUserEmailKey, 12345)
+	t.Run("ForgedTokenAttributedToSubject", func(t *testing.T) {
+		sink := &securityEventSink{}
+		token, err := GenerateJWT("wrong-secret", "", userID, "user@example.com", "user", time.Hour)
+		assert.NoError(t, err)
 
-		result := GetAuthUserEmail(c)
+		assert.Equal(t, http.StatusUnauthorized, serve(sink, "Bearer "+token))
 
-		assert.Equal(t, "", result)
+		if assert.Len(t, sink.events, 1) {
+			assert.Equal(t, entities.SecurityEventAuthFailed, sink.events[0].Type())
+			assert.Equal(t, userID, sink.events[0].Principal())
+		}
 	})
-}
-
-func TestGetAuthUserRole(t *testing.T) {
-	gin.SetMode(gin.TestMode)
 
-	t.Run("ValidRole", func(t *testing.T) {
-		c, _ := gin.CreateTestContext(httptest.NewRecorder())
-		expectedRole := "admin"
-		c.Set(AuthUserRoleKey, expectedRole)
+	t.Run("OpaqueTokenStoredAsPrefix", func(t *testing.T) {
+		sink := &securityEventSink{}
 
-		result := GetAuthUserRole(c)
+		assert.Equal(t, http.StatusUnauthorized, serve(sink, "Bearer sk_live_0123456789abcdef"))
 
-		assert.Equal(t, expectedRole, result)
+		if assert.Len(t, sink.events, 1) {
+			assert.Equal(t, "key:sk_live_", sink.events[0].Principal())
+		}
 	})
 
-	t.Run("NotSet", func(t *testing.T) {
-		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	t.Run("MissingHeaderHasNoPrincipal", func(t *testing.T) {
+		sink := &securityEventSink{}
 
-		result := GetAuthUserRole(c)
+		assert.Equal(t, http.StatusUnauthorized, serve(sink, ""))
 
-		assert.Equal(t, "", result)
+		if assert.Len(t, sink.events, 1) {
+			assert.Equal(t, entities.SecurityEventAuthFailed, sink.events[0].Type())
+			assert.Empty(t, sink.events[0].Principal())
+		}
 	})
 
-	t.Run("InvalidType", func(t *testing.T) {
-		c, _ := gin.CreateTestContext(httptest.NewRecorder())
-		c.Set(AuthUserRoleKey, 12345)
+	t.Run("ScopeDenied", func(t *testing.T) {
+		sink := &securityEventSink{}
+		token, err := GenerateJWT(secret, "", userID, "user@example.com", "user", time.Hour)
+		assert.NoError(t, err)
 
-		result := GetAuthUserRole(c)
+		assert.Equal(t, http.StatusForbidden, serve(sink, "Bearer "+token, RequireRole("admin")))
 
-		assert.Equal(t, "", result)
+		if assert.Len(t, sink.events, 2) {
+			assert.Equal(t, entities.SecurityEventAuthSucceeded, sink.events[0].Type())
+			assert.Equal(t, entities.SecurityEventScopeDenied, sink.events[1].Type())
+			assert.Equal(t, userID, sink.events[1].Principal())
+		}
 	})
 }
 
@@ -0,0 +1,109 @@
+// Package middleware - проверка политик доступа маршрутов.
+//
+// Маршрут объявляет политику в routes.Meta.Policy (владелец ресурса, роль,
+// scope API ключа и их комбинации), handlers проверок доступа не содержат.
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
+	"github.com/Haleralex/wallethub/internal/adapters/http/policy"
+	"github.com/Haleralex/wallethub/internal/adapters/http/routes"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// policyDeniedMessage - сообщение отказа для пользователя: какая политика
+// не прошла, видит только администратор.
+const policyDeniedMessage = "You do not have access to this resource"
+
+// AuthorizeForRoute строит middleware проверки route.Policy. Подключается
+// к группе через WithRouteMiddleware после Auth; маршрут без политики
+// middleware не получает.
+//
+// Паникует при сборке роутера, если для Owner нет Resolver'а или
+// параметра пути - как routes.Group на незаполненных метаданных.
+//
+// Исходы запроса:
+// - политика прошла - запрос идёт дальше
+// - не прошла - 403 POLICY_DENIED; в журнал безопасности SCOPE_DENIED, если
+// не хватило scope, иначе POLICY_DENIED. Администратор получает в
+// details.policy не прошедшую политику
+// - параметр пути не UUID - 400, ресурс не найден - 404
+func AuthorizeForRoute(resolvers policy.Resolvers) routes.RouteMiddleware {
+	return func(route routes.Route) gin.HandlerFunc {
+		if route.Policy == nil {
+			return nil
+		}
+		if err := policy.Check(route.Policy, resolvers, routeParams(route.Path)); err != nil {
+			panic(fmt.Sprintf("authorize: %s %s: %v", route.Method, route.Path, err))
+		}
+		return authorize(route.Policy, resolvers)
+	}
+}
+
+// authorize проверяет политику для actor из context.
+func authorize(p policy.Policy, resolvers policy.Resolvers) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := ports.ActorFromContext(c.Request.Context())
+		if !ok {
+			common.UnauthorizedResponse(c, "User not authenticated")
+			c.Abort()
+			return
+		}
+
+		denied, err := policy.Evaluate(c.Request.Context(), p, policy.Request{
+			Actor:     actor,
+			Scopes:    httpctx.AuthScopes(c),
+			Param:     c.Param,
+			Resolvers: resolvers,
+		})
+		if err != nil {
+			common.HandleDomainError(c, err)
+			c.Abort()
+			return
+		}
+		if denied != nil {
+			eventType := entities.SecurityEventPolicyDenied
+			if policy.ScopeOnly(denied) {
+				eventType = entities.SecurityEventScopeDenied
+			}
+			recordSecurityEvent(c, httpctx.SecurityRecorder(c), actor.ID.String(), eventType)
+			abortWithPolicyDenied(c, actor, p, denied)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// abortWithPolicyDenied отправляет 403 POLICY_DENIED.
+func abortWithPolicyDenied(c *gin.Context, actor ports.Actor, routePolicy, denied policy.Policy) {
+	apiErr := &common.APIError{Code: common.ErrCodePolicyDenied, Message: policyDeniedMessage}
+	if actor.IsAdmin() {
+		apiErr.Message = "Access denied by policy " + denied.String()
+		apiErr.Details = map[string]interface{}{
+			"policy":       denied.String(),
+			"route_policy": routePolicy.String(),
+		}
+	}
+	common.Error(c, http.StatusForbidden, apiErr)
+	c.Abort()
+}
+
+// routeParams возвращает имена параметров шаблона пути ("/wallets/{id}" -> ["id"]).
+func routeParams(path string) []string {
+	var params []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params = append(params, segment[1:len(segment)-1])
+		}
+	}
+	return params
+}
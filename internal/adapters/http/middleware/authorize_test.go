@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/policy"
+	"github.com/Haleralex/wallethub/internal/adapters/http/routes"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// ownersOf - Resolver по таблице владельцев; неизвестный ID - не найден.
+func ownersOf(owners map[uuid.UUID]uuid.UUID) policy.Resolver {
+	return policy.ResolverFunc(func(_ context.Context, id uuid.UUID) (uuid.UUID, error) {
+		owner, ok := owners[id]
+		if !ok {
+			return uuid.Nil, errors.ErrEntityNotFound
+		}
+		return owner, nil
+	})
+}
+
+// newAuthorizeRouter собирает Auth (JWT) и маршруты с политиками.
+func newAuthorizeRouter(secret string, sink *securityEventSink, resolvers policy.Resolvers) *gin.Engine {
+	router := gin.New()
+	router.Use(Auth(&AuthConfig{TokenValidator: NewJWTTokenValidator(secret, "", nil), SecurityLog: sink}))
+
+	group := routes.NewRegistry().Group(&router.RouterGroup, routes.Meta{Auth: routes.AuthUser, RateLimit: routes.RateLimitGlobal}).
+		WithRouteMiddleware(AuthorizeForRoute(resolvers))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	group.GET("/wallets/:id", routes.Meta{
+		Policy: policy.And(policy.Scope("wallets:read"), policy.Or(policy.Owner("wallet", "id"), policy.Admin())),
+	}, ok)
+	group.GET("/public", routes.Meta{}, ok)
+	return router
+}
+
+func TestAuthorizeForRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const secret = "authorize-secret"
+
+	ownerID, walletID := uuid.New(), uuid.New()
+	sink := &securityEventSink{}
+	router := newAuthorizeRouter(secret, sink, policy.Resolvers{
+		"wallet": ownersOf(map[uuid.UUID]uuid.UUID{walletID: ownerID}),
+	})
+
+	serve := func(path string, claims jwt.MapClaims) *httptest.ResponseRecorder {
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	errorOf := func(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+		var body struct {
+			Error map[string]interface{} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body.Error
+	}
+	walletPath := "/wallets/" + walletID.String()
+
+	t.Run("Owner", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(walletPath, jwt.MapClaims{"sub": ownerID.String(), "role": "user"}).Code)
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(walletPath, jwt.MapClaims{"sub": uuid.NewString(), "role": "superadmin"}).Code)
+	})
+
+	t.Run("OtherUserDenied", func(t *testing.T) {
+		sink.events = nil
+		otherID := uuid.NewString()
+
+		w := serve(walletPath, jwt.MapClaims{"sub": otherID, "role": "user"})
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+		apiErr := errorOf(t, w)
+		assert.Equal(t, "POLICY_DENIED", apiErr["code"])
+		assert.Equal(t, "You do not have access to this resource", apiErr["message"])
+		assert.Nil(t, apiErr["details"])
+
+		if assert.Len(t, sink.events, 2) {
+			assert.Equal(t, entities.SecurityEventPolicyDenied, sink.events[1].Type())
+			assert.Equal(t, otherID, sink.events[1].Principal())
+		}
+	})
+
+	t.Run("APIKeyScopes", func(t *testing.T) {
+		withScopes := func(scopes ...interface{}) jwt.MapClaims {
+			return jwt.MapClaims{"sub": ownerID.String(), "role": "user", "scopes": scopes}
+		}
+
+		assert.Equal(t, http.StatusOK, serve(walletPath, withScopes("wallets:read")).Code)
+
+		sink.events = nil
+		assert.Equal(t, http.StatusForbidden, serve(walletPath, withScopes("transactions:read")).Code)
+		if assert.Len(t, sink.events, 2) {
+			assert.Equal(t, entities.SecurityEventScopeDenied, sink.events[1].Type())
+		}
+
+		assert.Equal(t, http.StatusForbidden, serve(walletPath, withScopes()).Code, "an empty scopes claim grants nothing")
+	})
+
+	t.Run("AdminSeesFailedPolicy", func(t *testing.T) {
+		w := serve(walletPath, jwt.MapClaims{"sub": uuid.NewString(), "role": "admin", "scopes": []interface{}{}})
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+		details, _ := errorOf(t, w)["details"].(map[string]interface{})
+		assert.Equal(t, "scope(wallets:read)", details["policy"])
+	})
+
+	t.Run("InvalidIDAndUnknownWallet", func(t *testing.T) {
+		claims := jwt.MapClaims{"sub": ownerID.String(), "role": "user"}
+
+		assert.Equal(t, http.StatusBadRequest, serve("/wallets/not-a-uuid", claims).Code)
+		assert.Equal(t, http.StatusNotFound, serve("/wallets/"+uuid.NewString(), claims).Code)
+	})
+
+	t.Run("RouteWithoutPolicy", func(t *testing.T) {
+		w := serve("/public", jwt.MapClaims{"sub": uuid.NewString(), "role": "user", "scopes": []interface{}{}})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("NoActor", func(t *testing.T) {
+		// Subject не UUID: Auth пропускает запрос без actor
+		w := serve(walletPath, jwt.MapClaims{"sub": "not-a-uuid", "role": "admin"})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestAuthorizeForRoute_PanicsOnInvalidPolicy(t *testing.T) {
+	build := func(path string, p policy.Policy, resolvers policy.Resolvers) func() {
+		return func() {
+			router := gin.New()
+			group := routes.NewRegistry().Group(&router.RouterGroup, routes.Meta{Auth: routes.AuthUser, RateLimit: routes.RateLimitGlobal}).
+				WithRouteMiddleware(AuthorizeForRoute(resolvers))
+			group.GET(path, routes.Meta{Policy: p}, func(c *gin.Context) {})
+		}
+	}
+	resolvers := policy.Resolvers{"wallet": ownersOf(nil)}
+
+	assert.NotPanics(t, build("/wallets/:id", policy.Owner("wallet", "id"), resolvers))
+	assert.Panics(t, build("/transactions/:id", policy.Owner("transaction", "id"), resolvers), "no resolver")
+	assert.Panics(t, build("/wallets/:wallet_id", policy.Owner("wallet", "id"), resolvers), "no path parameter")
+	assert.Panics(t, build("/wallets", policy.Or(), resolvers), "empty Or")
+}
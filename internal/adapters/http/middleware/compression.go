@@ -0,0 +1,380 @@
+// Package middleware - сжатие ответов (gzip, опционально zstd).
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+)
+
+// DefaultCompressionMinSize - ответы короче этого не сжимаются по умолчанию:
+// заголовки gzip и CPU дороже выигрыша на маленьком JSON.
+const DefaultCompressionMinSize = 1024
+
+// Кодировки Content-Encoding.
+const (
+	EncodingGzip     = "gzip"
+	EncodingZstd     = "zstd"
+	encodingIdentity = "identity"
+)
+
+// CompressionConfig - конфигурация сжатия ответов.
+type CompressionConfig struct {
+	// MinSize - минимальный размер тела для сжатия
+	// (по умолчанию DefaultCompressionMinSize)
+	MinSize int
+	// Zstd - предлагать zstd клиентам, которые его принимают (иначе только gzip)
+	Zstd bool
+}
+
+// incompressibleTypes - уже сжатые форматы: повторное сжатие только тратит CPU.
+var incompressibleTypes = []string{
+	"image/png", "image/jpeg", "image/gif", "image/webp", "image/avif",
+	"video/", "audio/", "font/woff", "font/woff2",
+	"application/zip", "application/gzip", "application/x-gzip", "application/zstd",
+	"application/x-7z-compressed", "application/x-rar-compressed", "application/pdf",
+}
+
+// Compression middleware сжимает ответ кодировкой из Accept-Encoding
+// (zstd - только если включён, при равном q предпочтительнее gzip).
+//
+// Правила:
+//   - Vary: Accept-Encoding добавляется к каждому ответу - кэш не должен
+//     отдать сжатый ответ клиенту без поддержки сжатия
+//   - Тело копится до MinSize байт: ответ короче уходит без сжатия
+//   - Flush (streaming, например CSV выгрузка) начинает сжатие сразу,
+//     не дожидаясь MinSize, и сбрасывает уже сжатые данные клиенту -
+//     ответ не буферизуется целиком
+//   - Не сжимаются HEAD, 204, 304, 206, ответы с Content-Encoding и уже
+//     сжатые форматы (incompressibleTypes)
+//   - При сжатии Content-Length удаляется, а strong ETag становится weak
+//     (W/): байты представления отличаются от несжатого, а If-None-Match
+//     сравнивается weak comparison (common.ETagMatches), так что 304
+//     работает для обеих кодировок
+//
+// Status() и Size() обёртки отражают ответ handler'а до сжатия.
+func Compression(config *CompressionConfig) gin.HandlerFunc {
+	if config == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	minSize := config.MinSize
+	if minSize <= 0 {
+		minSize = DefaultCompressionMinSize
+	}
+
+	return func(c *gin.Context) {
+		addVary(c.Writer.Header(), "Accept-Encoding")
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"), config.Zstd)
+		if encoding == encodingIdentity || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{ResponseWriter: c.Writer, encoding: encoding, minSize: minSize}
+		c.Writer = writer
+		defer writer.close()
+
+		c.Next()
+	}
+}
+
+// ============================================
+// Negotiation
+// ============================================
+
+// negotiateEncoding выбирает кодировку по Accept-Encoding (RFC 9110 12.5.3):
+// наибольший q, при равенстве - zstd, затем gzip; q=0 - запрет.
+func negotiateEncoding(header string, zstdEnabled bool) string {
+	if header == "" {
+		return encodingIdentity
+	}
+
+	best, bestQ := encodingIdentity, 0.0
+	consider := func(encoding string, q float64) {
+		if q <= 0 || q < bestQ {
+			return
+		}
+		if q == bestQ && best == EncodingZstd {
+			return
+		}
+		best, bestQ = encoding, q
+	}
+
+	explicit := map[string]bool{}
+	var wildcard float64 = -1
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := parseQuality(params)
+
+		switch name {
+		case EncodingGzip, "x-gzip":
+			explicit[EncodingGzip] = true
+			consider(EncodingGzip, q)
+		case EncodingZstd:
+			if zstdEnabled {
+				explicit[EncodingZstd] = true
+				consider(EncodingZstd, q)
+			}
+		case "*":
+			wildcard = q
+		}
+	}
+
+	// "*" разрешает кодировки, не названные явно
+	if wildcard > 0 && !explicit[EncodingGzip] {
+		consider(EncodingGzip, wildcard)
+	}
+	return best
+}
+
+// parseQuality читает q из параметров ("q=0.5"); без q - 1.
+func parseQuality(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.ToLower(strings.TrimSpace(key)) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0
+		}
+		return q
+	}
+	return 1
+}
+
+// addVary добавляет значение в Vary, если его там ещё нет.
+func addVary(header http.Header, value string) {
+	for _, existing := range header.Values("Vary") {
+		for _, v := range strings.Split(existing, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), value) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", value)
+}
+
+// compressible - можно ли сжимать ответ с текущими заголовками и статусом.
+func compressible(header http.Header, status int) bool {
+	switch {
+	case status < http.StatusOK,
+		status == http.StatusNoContent,
+		status == http.StatusPartialContent,
+		status == http.StatusNotModified:
+		return false
+	case header.Get("Content-Encoding") != "":
+		return false
+	}
+
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	for _, prefix := range incompressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// ============================================
+// Encoders
+// ============================================
+
+// encoder - общий интерфейс gzip.Writer и zstd.Encoder.
+type encoder interface {
+	io.Writer
+	Flush() error
+	Close() error
+	Reset(w io.Writer)
+}
+
+// Энкодеры дороги в создании (особенно zstd) - переиспользуем.
+var (
+	gzipPool = sync.Pool{New: func() any {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}}
+	zstdPool = sync.Pool{New: func() any {
+		w, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstd.SpeedDefault))
+		return w
+	}}
+)
+
+func acquireEncoder(encoding string, w io.Writer) encoder {
+	var enc encoder
+	if encoding == EncodingZstd {
+		enc = zstdPool.Get().(*zstd.Encoder)
+	} else {
+		enc = gzipPool.Get().(*gzip.Writer)
+	}
+	enc.Reset(w)
+	return enc
+}
+
+func releaseEncoder(encoding string, enc encoder) {
+	enc.Reset(io.Discard)
+	if encoding == EncodingZstd {
+		zstdPool.Put(enc)
+	} else {
+		gzipPool.Put(enc)
+	}
+}
+
+// ============================================
+// Writer
+// ============================================
+
+// compressState - решение о сжатии ответа.
+type compressState int
+
+const (
+	stateUndecided   compressState = iota // тело копится до minSize
+	stateIdentity                         // пишется как есть
+	stateCompressing                      // пишется через encoder
+)
+
+// compressWriter откладывает решение о сжатии до minSize байт тела,
+// Flush или конца запроса.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+
+	state    compressState
+	buf      bytes.Buffer
+	enc      encoder
+	accepted int // байт тела от handler'а (до сжатия)
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	w.accepted += len(data)
+
+	switch w.state {
+	case stateIdentity:
+		return w.ResponseWriter.Write(data)
+	case stateCompressing:
+		return w.enc.Write(data)
+	}
+
+	if !compressible(w.Header(), w.ResponseWriter.Status()) || w.knownSmall() {
+		if err := w.startIdentity(); err != nil {
+			return 0, err
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf.Write(data)
+	if w.buf.Len() < w.minSize {
+		return len(data), nil
+	}
+	if err := w.startCompression(); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow - handler отправляет заголовки без тела (304, HEAD):
+// сжимать нечего.
+func (w *compressWriter) WriteHeaderNow() {
+	if w.state == stateUndecided {
+		_ = w.startIdentity()
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush - streaming ответ: сжатие начинается сразу (длина заранее не
+// известна), накопленное отправляется клиенту.
+func (w *compressWriter) Flush() {
+	if w.state == stateUndecided {
+		if compressible(w.Header(), w.ResponseWriter.Status()) {
+			_ = w.startCompression()
+		} else {
+			_ = w.startIdentity()
+		}
+	}
+	if w.state == stateCompressing {
+		_ = w.enc.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Written - handler уже что-то записал (возможно, пока в буфер).
+func (w *compressWriter) Written() bool {
+	return w.accepted > 0 || w.ResponseWriter.Written()
+}
+
+// Size - размер тела до сжатия.
+func (w *compressWriter) Size() int {
+	if w.accepted > 0 {
+		return w.accepted
+	}
+	return w.ResponseWriter.Size()
+}
+
+// knownSmall - handler сам выставил Content-Length меньше minSize.
+func (w *compressWriter) knownSmall() bool {
+	length, err := strconv.Atoi(w.Header().Get("Content-Length"))
+	return err == nil && length < w.minSize
+}
+
+// startIdentity отправляет накопленное тело без сжатия.
+func (w *compressWriter) startIdentity() error {
+	w.state = stateIdentity
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// startCompression выставляет заголовки сжатого ответа и сжимает накопленное.
+func (w *compressWriter) startCompression() error {
+	w.state = stateCompressing
+
+	header := w.Header()
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+
+	w.enc = acquireEncoder(w.encoding, w.ResponseWriter)
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.enc.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// close завершает ответ после handler'ов: короткое тело уходит как есть,
+// сжатый поток закрывается. Дальнейшие записи (например, ответ Recovery
+// после panic) идут без сжатия.
+func (w *compressWriter) close() {
+	switch w.state {
+	case stateUndecided:
+		_ = w.startIdentity()
+	case stateCompressing:
+		_ = w.enc.Close()
+		releaseEncoder(w.encoding, w.enc)
+		w.enc = nil
+	}
+	w.state = stateIdentity
+}
+
+// Compile-time check
+var _ http.Flusher = (*compressWriter)(nil)
@@ -0,0 +1,311 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// newCompressionRouter - роутер со сжатием и одним handler'ом на GET /r.
+func newCompressionRouter(config *CompressionConfig, handler gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+	router.Use(Compression(config))
+	router.GET("/r", handler)
+	router.HEAD("/r", handler)
+	return router
+}
+
+func serveCompression(router *gin.Engine, method, acceptEncoding string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/r", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func gunzip(t *testing.T, data []byte) string {
+	t.Helper()
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestCompression(t *testing.T) {
+	large := strings.Repeat(`{"id":"tx","amount":"10.00"},`, 100)
+	config := &CompressionConfig{MinSize: 1024}
+
+	text := func(body string) gin.HandlerFunc {
+		return func(c *gin.Context) { c.String(http.StatusOK, body) }
+	}
+
+	t.Run("LargeBodyGzipped", func(t *testing.T) {
+		w := serveCompression(newCompressionRouter(config, text(large)), http.MethodGet, "gzip, deflate")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, EncodingGzip, w.Header().Get("Content-Encoding"))
+		assert.Empty(t, w.Header().Get("Content-Length"))
+		assert.Less(t, w.Body.Len(), len(large))
+		assert.Equal(t, large, gunzip(t, w.Body.Bytes()))
+	})
+
+	t.Run("BelowThresholdNotCompressed", func(t *testing.T) {
+		small := large[:1023]
+		w := serveCompression(newCompressionRouter(config, text(small)), http.MethodGet, "gzip")
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, small, w.Body.String())
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	})
+
+	t.Run("ThresholdReachedAcrossWrites", func(t *testing.T) {
+		handler := func(c *gin.Context) {
+			c.Header("Content-Type", "application/json")
+			c.Status(http.StatusOK)
+			for i := 0; i < 4; i++ {
+				_, _ = c.Writer.WriteString(large[i*300 : (i+1)*300])
+			}
+		}
+		w := serveCompression(newCompressionRouter(config, handler), http.MethodGet, "gzip")
+
+		assert.Equal(t, EncodingGzip, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, large[:1200], gunzip(t, w.Body.Bytes()))
+	})
+
+	t.Run("KnownSmallContentLengthKept", func(t *testing.T) {
+		handler := func(c *gin.Context) {
+			c.Header("Content-Length", "5")
+			c.Data(http.StatusOK, "text/plain", []byte("hello"))
+		}
+		w := serveCompression(newCompressionRouter(config, handler), http.MethodGet, "gzip")
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "5", w.Header().Get("Content-Length"))
+	})
+
+	t.Run("NoAcceptEncodingStillVaries", func(t *testing.T) {
+		w := serveCompression(newCompressionRouter(config, text(large)), http.MethodGet, "")
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, large, w.Body.String())
+		assert.Equal(t, []string{"Accept-Encoding"}, w.Header().Values("Vary"))
+	})
+
+	t.Run("VaryNotDuplicated", func(t *testing.T) {
+		router := gin.New()
+		router.Use(func(c *gin.Context) { c.Header("Vary", "Origin, accept-encoding") })
+		router.Use(Compression(config))
+		router.GET("/r", text(large))
+
+		w := serveCompression(router, http.MethodGet, "gzip")
+		assert.Equal(t, []string{"Origin, accept-encoding"}, w.Header().Values("Vary"))
+	})
+
+	t.Run("StrongETagWeakened", func(t *testing.T) {
+		handler := func(c *gin.Context) {
+			c.Header("ETag", `"v42"`)
+			c.String(http.StatusOK, large)
+		}
+		router := newCompressionRouter(config, handler)
+
+		assert.Equal(t, `W/"v42"`, serveCompression(router, http.MethodGet, "gzip").Header().Get("ETag"))
+		assert.Equal(t, `"v42"`, serveCompression(router, http.MethodGet, "").Header().Get("ETag"))
+	})
+
+	t.Run("NotModifiedUntouched", func(t *testing.T) {
+		handler := func(c *gin.Context) {
+			c.Header("ETag", `"v42"`)
+			c.Status(http.StatusNotModified)
+		}
+		w := serveCompression(newCompressionRouter(config, handler), http.MethodGet, "gzip")
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, `"v42"`, w.Header().Get("ETag"))
+		assert.Zero(t, w.Body.Len())
+	})
+
+	t.Run("HeadNotCompressed", func(t *testing.T) {
+		w := serveCompression(newCompressionRouter(config, text(large)), http.MethodHead, "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("AlreadyCompressedTypesSkipped", func(t *testing.T) {
+		for _, contentType := range []string{"image/png", "application/zip", "application/pdf", "video/mp4"} {
+			handler := func(c *gin.Context) { c.Data(http.StatusOK, contentType, []byte(large)) }
+			w := serveCompression(newCompressionRouter(config, handler), http.MethodGet, "gzip")
+
+			assert.Empty(t, w.Header().Get("Content-Encoding"), contentType)
+			assert.Equal(t, large, w.Body.String(), contentType)
+		}
+	})
+
+	t.Run("ExistingEncodingKept", func(t *testing.T) {
+		handler := func(c *gin.Context) {
+			c.Header("Content-Encoding", "br")
+			c.Data(http.StatusOK, "application/json", []byte(large))
+		}
+		w := serveCompression(newCompressionRouter(config, handler), http.MethodGet, "gzip")
+
+		assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, large, w.Body.String())
+	})
+
+	t.Run("ZstdWhenEnabled", func(t *testing.T) {
+		router := newCompressionRouter(&CompressionConfig{MinSize: 1024, Zstd: true}, text(large))
+		w := serveCompression(router, http.MethodGet, "gzip, zstd")
+		require.Equal(t, EncodingZstd, w.Header().Get("Content-Encoding"))
+
+		decoder, err := zstd.NewReader(nil)
+		require.NoError(t, err)
+		defer decoder.Close()
+		body, err := decoder.DecodeAll(w.Body.Bytes(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, large, string(body))
+
+		disabled := newCompressionRouter(config, text(large))
+		assert.Equal(t, EncodingGzip, serveCompression(disabled, http.MethodGet, "zstd, gzip").Header().Get("Content-Encoding"))
+	})
+
+	t.Run("NilConfigDisablesCompression", func(t *testing.T) {
+		w := serveCompression(newCompressionRouter(nil, text(large)), http.MethodGet, "gzip")
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Empty(t, w.Header().Get("Vary"))
+	})
+}
+
+// flushRecorder записывает, сколько байт дошло до клиента к каждому Flush.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushedAt []int
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushedAt = append(r.flushedAt, r.Body.Len())
+	r.ResponseRecorder.Flush()
+}
+
+func TestCompression_Streaming(t *testing.T) {
+	const rows = 50
+	router := newCompressionRouter(&CompressionConfig{MinSize: 1024}, func(c *gin.Context) {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		_, _ = c.Writer.WriteString("id,amount\n")
+		for i := 0; i < rows; i++ {
+			_, _ = fmt.Fprintf(c.Writer, "%d,10.00\n", i)
+			c.Writer.Flush()
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/r", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	router.ServeHTTP(w, req)
+
+	// Сжатие начинается на первом Flush, хотя тело меньше MinSize,
+	// и каждая строка доходит до клиента, не дожидаясь конца ответа
+	require.Equal(t, EncodingGzip, w.Header().Get("Content-Encoding"))
+	require.Len(t, w.flushedAt, rows)
+	for i := 1; i < rows; i++ {
+		assert.Greater(t, w.flushedAt[i], w.flushedAt[i-1], "flush %d", i)
+	}
+
+	body := gunzip(t, w.Body.Bytes())
+	assert.True(t, strings.HasPrefix(body, "id,amount\n0,10.00\n"))
+	assert.Equal(t, rows+1, strings.Count(body, "\n"))
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	cases := []struct {
+		header string
+		zstd   bool
+		want   string
+	}{
+		{"", true, encodingIdentity},
+		{"identity", true, encodingIdentity},
+		{"gzip", false, EncodingGzip},
+		{"GZIP;q=0.5", false, EncodingGzip},
+		{"gzip;q=0", false, encodingIdentity},
+		{"zstd", false, encodingIdentity},
+		{"zstd", true, EncodingZstd},
+		{"gzip, zstd", true, EncodingZstd},
+		{"zstd;q=0.5, gzip", true, EncodingGzip},
+		{"*", true, EncodingGzip},
+		{"*;q=0", true, encodingIdentity},
+		{"gzip;q=0, *", true, encodingIdentity},
+		{"br, x-gzip", false, EncodingGzip},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, negotiateEncoding(tc.header, tc.zstd), "%q zstd=%v", tc.header, tc.zstd)
+	}
+}
+
+// listPayload - страница из n транзакций, как её отдаёт GET /transactions.
+func listPayload(b *testing.B, n int) dtos.TransactionListDTO {
+	b.Helper()
+	createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	list := dtos.TransactionListDTO{Transactions: make([]dtos.TransactionDTO, 0, n), TotalCount: n, Limit: n}
+	for i := 0; i < n; i++ {
+		completedAt := createdAt.Add(time.Duration(i) * time.Minute)
+		tx, err := entities.ReconstructForImport(entities.TransactionImport{
+			ID:           uuid.New(),
+			WalletID:     uuid.New(),
+			Type:         entities.TransactionTypeDeposit,
+			Status:       entities.TransactionStatusCompleted,
+			CurrencyCode: "USD",
+			AmountCents:  int64(1000 + i),
+			Description:  fmt.Sprintf("Top-up #%d", i),
+			CreatedAt:    createdAt,
+			CompletedAt:  &completedAt,
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		list.Transactions = append(list.Transactions, dtos.ToTransactionDTO(tx))
+	}
+	return list
+}
+
+// BenchmarkCompression_TransactionList сравнивает размер и время ответа
+// со списком из 500 транзакций с разными кодировками.
+func BenchmarkCompression_TransactionList(b *testing.B) {
+	gin.SetMode(gin.ReleaseMode)
+	payload := listPayload(b, 500)
+	router := newCompressionRouter(&CompressionConfig{MinSize: DefaultCompressionMinSize, Zstd: true}, func(c *gin.Context) {
+		c.JSON(http.StatusOK, payload)
+	})
+
+	for _, acceptEncoding := range []string{"identity", "gzip", "zstd"} {
+		b.Run(acceptEncoding, func(b *testing.B) {
+			var size int
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				w := serveCompression(router, http.MethodGet, acceptEncoding)
+				size = w.Body.Len()
+			}
+			b.ReportMetric(float64(size), "bytes/resp")
+		})
+	}
+}
@@ -0,0 +1,299 @@
+// Package middleware - ограничение одновременных запросов (concurrency limit).
+//
+// Rate limit ограничивает число запросов за окно, но не их длительность:
+// один клиент с сотней медленных выгрузок займёт все воркеры, уложившись
+// в лимит. ConcurrencyLimiter ограничивает число запросов в обработке -
+// для каждого клиента по классу маршрута (routes.Meta.Concurrency) и для
+// инстанса в целом.
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/semaphore"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
+	"github.com/Haleralex/wallethub/internal/adapters/http/routes"
+)
+
+// Значения по умолчанию.
+const (
+	DefaultConcurrencyGlobalMax = 256
+	DefaultConcurrencyMaxWait   = 100 * time.Millisecond
+	DefaultConcurrencyIdleTTL   = 5 * time.Minute
+)
+
+// Области отказа (метка scope метрики отказов).
+const (
+	concurrencyScopeClient = "client"
+	concurrencyScopeGlobal = "global"
+)
+
+var (
+	// concurrencyRejections считает отказы CONCURRENCY_LIMIT_EXCEEDED
+	concurrencyRejections = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "paybridge",
+			Subsystem: "http",
+			Name:      "concurrency_rejections_total",
+			Help:      "Requests rejected by the concurrency limiter",
+		},
+		[]string{"class", "scope"},
+	)
+
+	// concurrencyWait измеряет ожидание слота (в том числе неудачное)
+	concurrencyWait = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "paybridge",
+			Subsystem: "http",
+			Name:      "concurrency_wait_seconds",
+			Help:      "Time requests spent queued for a concurrency slot",
+			Buckets:   []float64{.0005, .001, .005, .01, .025, .05, .1, .25, .5, 1},
+		},
+		[]string{"class"},
+	)
+)
+
+// ConcurrencyClass - лимиты класса маршрутов.
+type ConcurrencyClass struct {
+	// MaxPerClient - запросов одного клиента в обработке одновременно
+	MaxPerClient int64
+	// Weight - сколько единиц GlobalMax занимает один запрос класса
+	Weight int64
+}
+
+// DefaultConcurrencyClasses возвращает лимиты классов по умолчанию.
+func DefaultConcurrencyClasses() map[string]ConcurrencyClass {
+	return map[string]ConcurrencyClass{
+		routes.ConcurrencyRead:     {MaxPerClient: 32, Weight: 1},
+		routes.ConcurrencyMutation: {MaxPerClient: 8, Weight: 2},
+		routes.ConcurrencyReport:   {MaxPerClient: 2, Weight: 8},
+	}
+}
+
+// ConcurrencyLimitConfig - конфигурация ConcurrencyLimiter.
+type ConcurrencyLimitConfig struct {
+	// Classes - лимиты по классам; маршруты класса без лимитов не ограничиваются
+	// (по умолчанию DefaultConcurrencyClasses)
+	Classes map[string]ConcurrencyClass
+	// GlobalMax - суммарный вес запросов в обработке на инстанс
+	GlobalMax int64
+	// MaxWait - сколько запрос ждёт слот до отказа; отрицательное - отказ сразу
+	MaxWait time.Duration
+	// IdleTTL - через сколько удалять семафор клиента без запросов
+	IdleTTL time.Duration
+	// KeyFunc - идентификатор клиента (по умолчанию ClientKey)
+	KeyFunc func(*gin.Context) string
+}
+
+// clientClass - ключ семафора клиента.
+type clientClass struct {
+	client string
+	class  string
+}
+
+// clientSlot - семафор клиента в классе и число текущих пользователей.
+type clientSlot struct {
+	sem      *semaphore.Weighted
+	refs     int
+	lastUsed time.Time
+}
+
+// ConcurrencyLimiter - in-memory weighted semaphores на (клиент, класс) и
+// общий семафор инстанса. Работает в пределах одного инстанса.
+type ConcurrencyLimiter struct {
+	config ConcurrencyLimitConfig
+	global *semaphore.Weighted
+	now    func() time.Time
+
+	mu        sync.Mutex
+	clients   map[clientClass]*clientSlot
+	lastSweep time.Time
+}
+
+// NewConcurrencyLimiter создаёт limiter. Нулевые поля config заменяются
+// значениями по умолчанию.
+func NewConcurrencyLimiter(config ConcurrencyLimitConfig) *ConcurrencyLimiter {
+	if config.Classes == nil {
+		config.Classes = DefaultConcurrencyClasses()
+	}
+	if config.GlobalMax <= 0 {
+		config.GlobalMax = DefaultConcurrencyGlobalMax
+	}
+	if config.MaxWait == 0 {
+		config.MaxWait = DefaultConcurrencyMaxWait
+	}
+	if config.IdleTTL <= 0 {
+		config.IdleTTL = DefaultConcurrencyIdleTTL
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = ClientKey
+	}
+
+	return &ConcurrencyLimiter{
+		config:  config,
+		global:  semaphore.NewWeighted(config.GlobalMax),
+		now:     time.Now,
+		clients: make(map[clientClass]*clientSlot),
+	}
+}
+
+// ForRoute - routes.RouteMiddleware: middleware по классу маршрута.
+func (l *ConcurrencyLimiter) ForRoute(route routes.Route) gin.HandlerFunc {
+	return l.Middleware(route.Concurrency)
+}
+
+// Middleware ограничивает запросы класса class (nil, если у класса нет
+// лимитов).
+//
+// Запрос занимает слот клиента в классе и Weight единиц общего лимита,
+// ожидая их не дольше MaxWait; иначе - 429 CONCURRENCY_LIMIT_EXCEEDED
+// с Retry-After. Слоты освобождаются после handlers, в том числе при
+// панике (её дальше обрабатывает Recovery).
+func (l *ConcurrencyLimiter) Middleware(class string) gin.HandlerFunc {
+	limits, ok := l.config.Classes[class]
+	if !ok || limits.MaxPerClient <= 0 {
+		return nil
+	}
+	weight := max(limits.Weight, 1)
+
+	return func(c *gin.Context) {
+		key := clientClass{client: l.config.KeyFunc(c), class: class}
+
+		release, scope, err := l.acquire(c.Request.Context(), key, limits.MaxPerClient, weight)
+		if err != nil {
+			if c.Request.Context().Err() != nil {
+				// Клиент ушёл, пока ждал: отвечать некому
+				c.Abort()
+				return
+			}
+			concurrencyRejections.WithLabelValues(class, scope).Inc()
+			c.Header("Retry-After", "1")
+			common.Error(c, http.StatusTooManyRequests, &common.APIError{
+				Code:       common.ErrCodeConcurrencyLimitExceeded,
+				Message:    fmt.Sprintf("Too many concurrent %s requests, wait for in-flight requests to complete", class),
+				RetryAfter: 1,
+			})
+			c.Abort()
+			return
+		}
+		defer release()
+
+		c.Next()
+	}
+}
+
+// acquire занимает слот клиента, затем вес в общем лимите. Ожидание
+// ограничено MaxWait на оба семафора; при ошибке всё захваченное
+// возвращается, scope - где не хватило места.
+func (l *ConcurrencyLimiter) acquire(ctx context.Context, key clientClass, perClient, weight int64) (func(), string, error) {
+	start := l.now()
+	defer func() {
+		concurrencyWait.WithLabelValues(key.class).Observe(l.now().Sub(start).Seconds())
+	}()
+
+	waitCtx, cancel := context.WithTimeout(ctx, max(l.config.MaxWait, 0))
+	defer cancel()
+
+	sem := l.checkout(key, perClient)
+	if err := acquireSemaphore(waitCtx, sem, 1, l.config.MaxWait); err != nil {
+		l.checkin(key)
+		return nil, concurrencyScopeClient, err
+	}
+	if err := acquireSemaphore(waitCtx, l.global, weight, l.config.MaxWait); err != nil {
+		sem.Release(1)
+		l.checkin(key)
+		return nil, concurrencyScopeGlobal, err
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.global.Release(weight)
+			sem.Release(1)
+			l.checkin(key)
+		})
+	}, "", nil
+}
+
+// acquireSemaphore ждёт n единиц sem до отмены ctx; при отрицательном
+// wait - только если они свободны сразу.
+func acquireSemaphore(ctx context.Context, sem *semaphore.Weighted, n int64, wait time.Duration) error {
+	if wait < 0 {
+		if !sem.TryAcquire(n) {
+			return context.DeadlineExceeded
+		}
+		return nil
+	}
+	return sem.Acquire(ctx, n)
+}
+
+// checkout возвращает семафор клиента в классе, создавая его при
+// необходимости. Запись не удаляется, пока refs > 0.
+func (l *ConcurrencyLimiter) checkout(key clientClass, perClient int64) *semaphore.Weighted {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	slot, ok := l.clients[key]
+	if !ok {
+		slot = &clientSlot{sem: semaphore.NewWeighted(perClient)}
+		l.clients[key] = slot
+	}
+	slot.refs++
+	slot.lastUsed = now
+	return slot.sem
+}
+
+// checkin снимает ссылку на семафор клиента.
+func (l *ConcurrencyLimiter) checkin(key clientClass) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if slot, ok := l.clients[key]; ok {
+		slot.refs--
+		slot.lastUsed = l.now()
+	}
+}
+
+// sweep удаляет семафоры без запросов, простаивающие дольше IdleTTL.
+// Выполняется не чаще раза в IdleTTL; вызывается под l.mu.
+func (l *ConcurrencyLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.config.IdleTTL {
+		return
+	}
+	l.lastSweep = now
+
+	for key, slot := range l.clients {
+		if slot.refs == 0 && now.Sub(slot.lastUsed) >= l.config.IdleTTL {
+			delete(l.clients, key)
+		}
+	}
+}
+
+// size возвращает число семафоров клиентов.
+func (l *ConcurrencyLimiter) size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.clients)
+}
+
+// ClientKey - клиент для лимитов: пользователь, если запрос
+// аутентифицирован, иначе IP.
+func ClientKey(c *gin.Context) string {
+	if userID, ok := httpctx.AuthUserID(c); ok {
+		return "user:" + userID.String()
+	}
+	return "ip:" + c.ClientIP()
+}
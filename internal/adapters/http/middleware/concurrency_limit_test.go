@@ -0,0 +1,223 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/adapters/http/routes"
+)
+
+// clientHeader - клиент тестового запроса.
+const clientHeader = "X-Test-Client"
+
+func newTestConcurrencyLimiter(config ConcurrencyLimitConfig) *ConcurrencyLimiter {
+	config.KeyFunc = func(c *gin.Context) string { return c.GetHeader(clientHeader) }
+	return NewConcurrencyLimiter(config)
+}
+
+// serveAsync выполняет запрос в горутине; ответ приходит в канал.
+func serveAsync(router http.Handler, path, client string) <-chan *httptest.ResponseRecorder {
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(clientHeader, client)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		done <- w
+	}()
+	return done
+}
+
+func requireConcurrencyRejected(t *testing.T, w *httptest.ResponseRecorder) {
+	t.Helper()
+
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	var response common.APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Error)
+	assert.Equal(t, common.ErrCodeConcurrencyLimitExceeded, response.Error.Code)
+}
+
+func TestConcurrencyLimit_PerClient(t *testing.T) {
+	limiter := newTestConcurrencyLimiter(ConcurrencyLimitConfig{
+		Classes: map[string]ConcurrencyClass{routes.ConcurrencyReport: {MaxPerClient: 2, Weight: 1}},
+		MaxWait: -1,
+	})
+
+	release := make(chan struct{})
+	entered := make(chan struct{}, 10)
+	router := gin.New()
+	router.GET("/export", limiter.Middleware(routes.ConcurrencyReport), func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+
+	// Две медленные выгрузки клиента A занимают его слоты
+	first, second := serveAsync(router, "/export", "a"), serveAsync(router, "/export", "a")
+	<-entered
+	<-entered
+
+	// Третья отклоняется сразу, не дожидаясь первых
+	requireConcurrencyRejected(t, <-serveAsync(router, "/export", "a"))
+
+	// Клиент B не затронут
+	other := serveAsync(router, "/export", "b")
+	select {
+	case <-entered:
+	case <-time.After(time.Second):
+		t.Fatal("request of another client must not be limited")
+	}
+
+	close(release)
+	for _, done := range []<-chan *httptest.ResponseRecorder{first, second, other} {
+		assert.Equal(t, http.StatusOK, (<-done).Code)
+	}
+
+	// Слоты освобождены
+	w := <-serveAsync(router, "/export", "a")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestConcurrencyLimit_WaitsForSlot(t *testing.T) {
+	limiter := newTestConcurrencyLimiter(ConcurrencyLimitConfig{
+		Classes: map[string]ConcurrencyClass{routes.ConcurrencyRead: {MaxPerClient: 1, Weight: 1}},
+		MaxWait: time.Second,
+	})
+
+	release := make(chan struct{})
+	entered := make(chan struct{}, 2)
+	router := gin.New()
+	router.GET("/read", limiter.Middleware(routes.ConcurrencyRead), func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+
+	first := serveAsync(router, "/read", "a")
+	<-entered
+	queued := serveAsync(router, "/read", "a")
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	assert.Equal(t, http.StatusOK, (<-first).Code)
+	assert.Equal(t, http.StatusOK, (<-queued).Code, "queued request gets the released slot")
+}
+
+func TestConcurrencyLimit_GlobalCapUnderMixedLoad(t *testing.T) {
+	const globalMax = 4
+	limiter := newTestConcurrencyLimiter(ConcurrencyLimitConfig{
+		Classes: map[string]ConcurrencyClass{
+			routes.ConcurrencyRead:   {MaxPerClient: 10, Weight: 1},
+			routes.ConcurrencyReport: {MaxPerClient: 10, Weight: 3},
+		},
+		GlobalMax: globalMax,
+		MaxWait:   5 * time.Second,
+	})
+
+	var inFlight, peak atomic.Int64
+	handler := func(weight int64) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			current := inFlight.Add(weight)
+			for {
+				observed := peak.Load()
+				if current <= observed || peak.CompareAndSwap(observed, current) {
+					break
+				}
+			}
+			time.Sleep(2 * time.Millisecond)
+			inFlight.Add(-weight)
+			c.Status(http.StatusOK)
+		}
+	}
+	router := gin.New()
+	router.GET("/read", limiter.Middleware(routes.ConcurrencyRead), handler(1))
+	router.GET("/report", limiter.Middleware(routes.ConcurrencyReport), handler(3))
+
+	var wg sync.WaitGroup
+	codes := make(chan int, 40)
+	for i := range 40 {
+		path := "/read"
+		if i%3 == 0 {
+			path = "/report"
+		}
+		client := string(rune('a' + i%8))
+		wg.Go(func() {
+			codes <- (<-serveAsync(router, path, client)).Code
+		})
+	}
+	wg.Wait()
+	close(codes)
+
+	for code := range codes {
+		assert.Equal(t, http.StatusOK, code, "requests queue for the global cap instead of failing")
+	}
+	assert.LessOrEqual(t, peak.Load(), int64(globalMax))
+	assert.Positive(t, peak.Load())
+}
+
+func TestConcurrencyLimit_ReleasesOnPanic(t *testing.T) {
+	limiter := newTestConcurrencyLimiter(ConcurrencyLimitConfig{
+		Classes: map[string]ConcurrencyClass{routes.ConcurrencyMutation: {MaxPerClient: 1, Weight: 1}},
+		MaxWait: -1,
+	})
+
+	router := gin.New()
+	router.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	router.GET("/panic", limiter.Middleware(routes.ConcurrencyMutation), func(c *gin.Context) {
+		panic("handler failure")
+	})
+
+	for range 3 {
+		w := <-serveAsync(router, "/panic", "a")
+		assert.Equal(t, http.StatusInternalServerError, w.Code, "slot must be released after a panic")
+	}
+}
+
+func TestConcurrencyLimit_UnlimitedClass(t *testing.T) {
+	limiter := NewConcurrencyLimiter(ConcurrencyLimitConfig{
+		Classes: map[string]ConcurrencyClass{routes.ConcurrencyRead: {MaxPerClient: 1, Weight: 1}},
+	})
+
+	assert.Nil(t, limiter.Middleware(routes.ConcurrencyReport))
+	assert.Nil(t, limiter.ForRoute(routes.Route{Meta: routes.Meta{Concurrency: "unknown"}}))
+	assert.NotNil(t, limiter.ForRoute(routes.Route{Meta: routes.Meta{Concurrency: routes.ConcurrencyRead}}))
+}
+
+func TestConcurrencyLimit_IdleCleanup(t *testing.T) {
+	limiter := newTestConcurrencyLimiter(ConcurrencyLimitConfig{
+		Classes: map[string]ConcurrencyClass{routes.ConcurrencyRead: {MaxPerClient: 1, Weight: 1}},
+		IdleTTL: time.Minute,
+	})
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	router := gin.New()
+	router.GET("/read", limiter.Middleware(routes.ConcurrencyRead), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, client := range []string{"a", "b"} {
+		<-serveAsync(router, "/read", client)
+	}
+	assert.Equal(t, 2, limiter.size())
+
+	now = now.Add(2 * time.Minute)
+	<-serveAsync(router, "/read", "c")
+	assert.Equal(t, 1, limiter.size(), "idle clients are removed")
+}
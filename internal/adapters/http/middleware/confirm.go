@@ -0,0 +1,232 @@
+// Package middleware - Confirmation middleware для разрушительных admin операций.
+//
+// Интерактивная API консоль позволяет отправить admin запрос одним кликом.
+// Разрушительные endpoints требуют повторить запрос с токеном подтверждения:
+// случайно отправленный запрос ничего не меняет.
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+const (
+	// ConfirmOperationHeader - заголовок с токеном подтверждения.
+	ConfirmOperationHeader = "X-Confirm-Operation"
+	// DefaultConfirmationTTL - время жизни токена подтверждения.
+	DefaultConfirmationTTL = 2 * time.Minute
+	// CapabilityNoConfirm - capability токена автоматизации, с которой
+	// подтверждение не требуется.
+	CapabilityNoConfirm = "no_confirm"
+)
+
+// ConfirmationConfig - конфигурация confirmation middleware.
+type ConfirmationConfig struct {
+	// Store - хранилище токенов (Redis или in-memory)
+	Store ports.ConfirmationStore
+	// TTL - время жизни токена (по умолчанию DefaultConfirmationTTL)
+	TTL time.Duration
+	// Logger для ошибок хранилища
+	Logger *slog.Logger
+}
+
+// RequireConfirmation middleware требует подтверждения операции.
+//
+// Схема работы:
+// 1. Запрос без X-Confirm-Operation получает 428 CONFIRMATION_REQUIRED
+// с токеном в error.details.confirmation_token (и в заголовке ответа)
+// 2. Повтор того же запроса с токеном в X-Confirm-Operation выполняется
+// 3. Токен одноразовый и привязан к пользователю, методу, пути и хешу тела:
+// неизвестный, истёкший, использованный или выданный для другого запроса
+// токен отклоняется с 428 и новым токеном
+//
+// Токен с capability CapabilityNoConfirm (автоматизация) проходит без
+// подтверждения. Ошибка хранилища - 503: операция не выполняется без проверки.
+func RequireConfirmation(config *ConfirmationConfig) gin.HandlerFunc {
+	ttl := config.TTL
+	if ttl <= 0 {
+		ttl = DefaultConfirmationTTL
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(c *gin.Context) {
+		if httpctx.HasAuthCapability(c, CapabilityNoConfirm) {
+			c.Next()
+			return
+		}
+
+		fingerprint, err := requestFingerprint(c)
+		if err != nil {
+			abortWithConfirmationError(c, http.StatusBadRequest, "INVALID_REQUEST", "Failed to read request body", nil)
+			return
+		}
+		ctx := c.Request.Context()
+
+		if token := c.GetHeader(ConfirmOperationHeader); token != "" {
+			issuedFor, found, err := config.Store.Consume(ctx, token)
+			if err != nil {
+				logger.Error("Failed to consume confirmation token", slog.String("error", err.Error()))
+				abortWithConfirmationError(c, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "Confirmation is temporarily unavailable", nil)
+				return
+			}
+			if found && issuedFor == fingerprint {
+				c.Next()
+				return
+			}
+			requireConfirmation(c, config.Store, logger, fingerprint, ttl, "CONFIRMATION_INVALID",
+				"Confirmation token is invalid, expired or issued for another request; repeat the request with the new token")
+			return
+		}
+
+		requireConfirmation(c, config.Store, logger, fingerprint, ttl, "CONFIRMATION_REQUIRED",
+			"This operation must be confirmed: repeat the request with the token in the "+ConfirmOperationHeader+" header")
+	}
+}
+
+// requireConfirmation выдаёт новый токен и отвечает 428.
+func requireConfirmation(c *gin.Context, store ports.ConfirmationStore, logger *slog.Logger, fingerprint string, ttl time.Duration, code, message string) {
+	token, err := newConfirmationToken()
+	if err == nil {
+		err = store.Issue(c.Request.Context(), token, fingerprint, ttl)
+	}
+	if err != nil {
+		logger.Error("Failed to issue confirmation token", slog.String("error", err.Error()))
+		abortWithConfirmationError(c, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "Confirmation is temporarily unavailable", nil)
+		return
+	}
+
+	c.Header(ConfirmOperationHeader, token)
+	abortWithConfirmationError(c, http.StatusPreconditionRequired, code, message, gin.H{
+		"confirmation_token": token,
+		"expires_at":         time.Now().Add(ttl).UTC(),
+	})
+}
+
+// requestFingerprint - хеш пользователя, метода, пути с query и тела запроса.
+// Тело возвращается в запрос для handler'а.
+func requestFingerprint(c *gin.Context) (string, error) {
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(c.Request.Body)
+		if err != nil {
+			return "", err
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	bodyHash := sha256.Sum256(body)
+
+	var principal string
+	if userID, ok := httpctx.AuthUserID(c); ok {
+		principal = userID.String()
+	}
+
+	h := sha256.New()
+	for _, part := range []string{principal, c.Request.Method, c.Request.URL.RequestURI(), hex.EncodeToString(bodyHash[:])} {
+		h.Write([]byte(part))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// newConfirmationToken генерирует случайный токен (128 бит).
+func newConfirmationToken() (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf[:]), nil
+}
+
+// abortWithConfirmationError отправляет ответ об ошибке подтверждения.
+func abortWithConfirmationError(c *gin.Context, status int, code, message string, details gin.H) {
+	apiError := gin.H{
+		"code":    code,
+		"message": message,
+	}
+	if details != nil {
+		apiError["details"] = details
+	}
+	c.AbortWithStatusJSON(status, gin.H{
+		"success":    false,
+		"error":      apiError,
+		"request_id": httpctx.RequestID(c),
+		"timestamp":  time.Now().UTC(),
+	})
+}
+
+// ============================================
+// In-memory Confirmation Store
+// ============================================
+
+// Compile-time check
+var _ ports.ConfirmationStore = (*MemoryConfirmationStore)(nil)
+
+// MemoryConfirmationStore - in-memory хранилище токенов подтверждения.
+//
+// Используется без Redis: токен действует только на выдавшем его инстансе.
+type MemoryConfirmationStore struct {
+	mu     sync.Mutex
+	tokens map[string]confirmationEntry
+	now    func() time.Time
+}
+
+type confirmationEntry struct {
+	fingerprint string
+	expiresAt   time.Time
+}
+
+// NewMemoryConfirmationStore создаёт in-memory хранилище.
+func NewMemoryConfirmationStore() *MemoryConfirmationStore {
+	return &MemoryConfirmationStore{
+		tokens: make(map[string]confirmationEntry),
+		now:    time.Now,
+	}
+}
+
+// Issue сохраняет токен; заодно удаляет истёкшие.
+func (s *MemoryConfirmationStore) Issue(_ context.Context, token, fingerprint string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for key, entry := range s.tokens {
+		if !now.Before(entry.expiresAt) {
+			delete(s.tokens, key)
+		}
+	}
+	s.tokens[token] = confirmationEntry{fingerprint: fingerprint, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// Consume удаляет токен и возвращает его fingerprint, если токен не истёк.
+func (s *MemoryConfirmationStore) Consume(_ context.Context, token string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.tokens[token]
+	if !ok {
+		return "", false, nil
+	}
+	delete(s.tokens, token)
+	if !s.now().Before(entry.expiresAt) {
+		return "", false, nil
+	}
+	return entry.fingerprint, true, nil
+}
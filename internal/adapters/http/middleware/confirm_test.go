@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
+)
+
+// confirmHarness - роутер с RequireConfirmation перед handler'ом, который
+// считает выполнения и возвращает полученное тело.
+type confirmHarness struct {
+	router   *gin.Engine
+	store    *MemoryConfirmationStore
+	executed int
+}
+
+func newConfirmHarness(t *testing.T, capabilities ...string) *confirmHarness {
+	t.Helper()
+
+	h := &confirmHarness{store: NewMemoryConfirmationStore()}
+	userID := uuid.New()
+	h.router = gin.New()
+	h.router.Use(func(c *gin.Context) {
+		httpctx.SetAuthUserID(c, userID)
+		httpctx.SetAuthCapabilities(c, capabilities)
+	})
+	h.router.Use(RequireConfirmation(&ConfirmationConfig{Store: h.store}))
+	h.router.POST("/admin/jobs/:name/run", func(c *gin.Context) {
+		h.executed++
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusAccepted, string(body))
+	})
+	return h
+}
+
+func (h *confirmHarness) do(path, body, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set(ConfirmOperationHeader, token)
+	}
+	w := httptest.NewRecorder()
+	h.router.ServeHTTP(w, req)
+	return w
+}
+
+// confirmationError разбирает 428 ответ: код ошибки и выданный токен.
+func confirmationError(t *testing.T, w *httptest.ResponseRecorder) (string, string) {
+	t.Helper()
+
+	require.Equal(t, http.StatusPreconditionRequired, w.Code)
+	var resp struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details struct {
+				ConfirmationToken string    `json:"confirmation_token"`
+				ExpiresAt         time.Time `json:"expires_at"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp.Error.Details.ConfirmationToken)
+	assert.Equal(t, resp.Error.Details.ConfirmationToken, w.Header().Get(ConfirmOperationHeader))
+	assert.WithinDuration(t, time.Now().Add(DefaultConfirmationTTL), resp.Error.Details.ExpiresAt, 5*time.Second)
+	return resp.Error.Code, resp.Error.Details.ConfirmationToken
+}
+
+func TestRequireConfirmation(t *testing.T) {
+	const path = "/admin/jobs/outbox-cleanup/run"
+	const body = `{"reason":"manual"}`
+
+	t.Run("IssuesTokenAndExecutesOnRepeat", func(t *testing.T) {
+		h := newConfirmHarness(t)
+
+		code, token := confirmationError(t, h.do(path, body, ""))
+		assert.Equal(t, "CONFIRMATION_REQUIRED", code)
+		assert.Zero(t, h.executed)
+
+		w := h.do(path, body, token)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, body, w.Body.String(), "handler must receive the original body")
+		assert.Equal(t, 1, h.executed)
+	})
+
+	t.Run("AlteredBodyRejected", func(t *testing.T) {
+		h := newConfirmHarness(t)
+		_, token := confirmationError(t, h.do(path, body, ""))
+
+		code, fresh := confirmationError(t, h.do(path, `{"reason":"other"}`, token))
+		assert.Equal(t, "CONFIRMATION_INVALID", code)
+		assert.NotEqual(t, token, fresh)
+		assert.Zero(t, h.executed)
+
+		// Новый токен выдан для изменённого запроса
+		assert.Equal(t, http.StatusAccepted, h.do(path, `{"reason":"other"}`, fresh).Code)
+	})
+
+	t.Run("OtherPathRejected", func(t *testing.T) {
+		h := newConfirmHarness(t)
+		_, token := confirmationError(t, h.do(path, body, ""))
+
+		code, _ := confirmationError(t, h.do("/admin/jobs/retention/run", body, token))
+		assert.Equal(t, "CONFIRMATION_INVALID", code)
+		assert.Zero(t, h.executed)
+	})
+
+	t.Run("ExpiredTokenRejected", func(t *testing.T) {
+		h := newConfirmHarness(t)
+		_, token := confirmationError(t, h.do(path, body, ""))
+
+		h.store.now = func() time.Time { return time.Now().Add(DefaultConfirmationTTL + time.Second) }
+		code, _ := confirmationError(t, h.do(path, body, token))
+		assert.Equal(t, "CONFIRMATION_INVALID", code)
+		assert.Zero(t, h.executed)
+	})
+
+	t.Run("TokenIsSingleUse", func(t *testing.T) {
+		h := newConfirmHarness(t)
+		_, token := confirmationError(t, h.do(path, body, ""))
+
+		assert.Equal(t, http.StatusAccepted, h.do(path, body, token).Code)
+		code, _ := confirmationError(t, h.do(path, body, token))
+		assert.Equal(t, "CONFIRMATION_INVALID", code)
+		assert.Equal(t, 1, h.executed)
+	})
+
+	t.Run("UnknownTokenRejected", func(t *testing.T) {
+		h := newConfirmHarness(t)
+
+		code, _ := confirmationError(t, h.do(path, body, "made-up"))
+		assert.Equal(t, "CONFIRMATION_INVALID", code)
+		assert.Zero(t, h.executed)
+	})
+
+	t.Run("NoConfirmCapabilityBypasses", func(t *testing.T) {
+		h := newConfirmHarness(t, CapabilityNoConfirm)
+
+		assert.Equal(t, http.StatusAccepted, h.do(path, body, "").Code)
+		assert.Equal(t, 1, h.executed)
+	})
+}
+
+// TestRequireConfirmation_JWTCapabilities проверяет, что capability
+// no_confirm читается из claim "capabilities" JWT токена.
+func TestRequireConfirmation_JWTCapabilities(t *testing.T) {
+	const secret = "test-secret"
+
+	serve := func(capabilities []interface{}) int {
+		claims := jwt.MapClaims{
+			"sub":  uuid.NewString(),
+			"role": "admin",
+			"exp":  time.Now().Add(time.Hour).Unix(),
+		}
+		if capabilities != nil {
+			claims["capabilities"] = capabilities
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		require.NoError(t, err)
+
+		router := gin.New()
+		router.Use(Auth(&AuthConfig{TokenValidator: NewJWTTokenValidator(secret, "", nil)}))
+		router.Use(RequireConfirmation(&ConfirmationConfig{Store: NewMemoryConfirmationStore()}))
+		router.DELETE("/admin/wallets/:id/notes/:note_id", func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+
+		req := httptest.NewRequest(http.MethodDelete, "/admin/wallets/1/notes/2", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, serve([]interface{}{"no_confirm"}))
+	assert.Equal(t, http.StatusPreconditionRequired, serve([]interface{}{"other"}))
+	assert.Equal(t, http.StatusPreconditionRequired, serve(nil))
+}
@@ -35,6 +35,7 @@ func DefaultCORSConfig() *CORSConfig {
 		AllowOrigins: []string{"*"},
 		AllowMethods: []string{
 			http.MethodGet,
+			http.MethodHead,
 			http.MethodPost,
 			http.MethodPut,
 			http.MethodPatch,
@@ -48,8 +49,10 @@ func DefaultCORSConfig() *CORSConfig {
 			"Authorization",
 			"X-Request-ID",
 			"X-Idempotency-Key",
+			"If-None-Match",
 		},
 		ExposeHeaders: []string{
+			"ETag",
 			"X-Request-ID",
 			"X-RateLimit-Limit",
 			"X-RateLimit-Remaining",
@@ -0,0 +1,149 @@
+// Package middleware - Debug stats middleware: стоимость запроса в заголовках ответа.
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// Заголовки debug статистики.
+const (
+	DebugStatsHeader       = "X-Debug-Stats"
+	HeaderDBQueries        = "X-DB-Queries"
+	HeaderDBTimeMs         = "X-DB-Time-Ms"
+	HeaderLockRetries      = "X-Lock-Retries"
+	HeaderEventsPublished  = "X-Events-Published"
+	HeaderPublishTimeMs    = "X-Publish-Time-Ms"
+	debugStatsEnabledValue = "1"
+)
+
+// DebugStatsConfig - конфигурация debug stats middleware.
+type DebugStatsConfig struct {
+	Logger      *slog.Logger
+	Environment string // В production статистика доступна только admin
+}
+
+// DebugStats добавляет в ответ счётчики стоимости запроса.
+//
+// Включается заголовком X-Debug-Stats: 1. Счётчики (ports.RequestStats)
+// кладутся в context только в этом случае - без заголовка репозитории
+// ничего не считают. Вне production статистику видит любой вызывающий,
+// в production - только admin (роль проверяется после auth middleware,
+// перед записью ответа).
+//
+// Заголовки: X-DB-Queries, X-DB-Time-Ms, X-Lock-Retries, X-Events-Published,
+// X-Publish-Time-Ms. Та же запись пишется в лог.
+func DebugStats(config *DebugStatsConfig) gin.HandlerFunc {
+	if config == nil {
+		config = &DebugStatsConfig{}
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(c *gin.Context) {
+		if c.GetHeader(DebugStatsHeader) != debugStatsEnabledValue {
+			c.Next()
+			return
+		}
+
+		stats := &ports.RequestStats{}
+		c.Request = c.Request.WithContext(ports.WithRequestStats(c.Request.Context(), stats))
+
+		allowed := func() bool {
+			return config.Environment != "production" || httpctx.AuthRole(c) == "admin"
+		}
+
+		writer := &debugStatsWriter{ResponseWriter: c.Writer, stats: stats, allowed: allowed}
+		c.Writer = writer
+
+		c.Next()
+
+		// Ответ без тела (например, 204) - заголовки ещё не отправлены
+		writer.setHeaders()
+
+		if !allowed() {
+			return
+		}
+
+		snapshot := stats.Snapshot()
+		logger.InfoContext(c.Request.Context(), "Request stats",
+			slog.String("request_id", httpctx.RequestID(c)),
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.FullPath()),
+			slog.Int("status", c.Writer.Status()),
+			slog.Int64("db_queries", snapshot.DBQueries),
+			slog.Float64("db_time_ms", milliseconds(snapshot.DBTime)),
+			slog.Int64("lock_retries", snapshot.LockRetries),
+			slog.Int64("events_published", snapshot.EventsPublished),
+			slog.Float64("publish_time_ms", milliseconds(snapshot.PublishTime)),
+		)
+	}
+}
+
+// debugStatsWriter добавляет заголовки статистики непосредственно перед
+// отправкой ответа - к этому моменту handler уже отработал.
+type debugStatsWriter struct {
+	gin.ResponseWriter
+	stats   *ports.RequestStats
+	allowed func() bool
+	done    bool
+}
+
+func (w *debugStatsWriter) setHeaders() {
+	if w.done || w.ResponseWriter.Written() {
+		return
+	}
+	w.done = true
+
+	if !w.allowed() {
+		return
+	}
+
+	snapshot := w.stats.Snapshot()
+	header := w.Header()
+	header.Set(HeaderDBQueries, strconv.FormatInt(snapshot.DBQueries, 10))
+	header.Set(HeaderDBTimeMs, formatMilliseconds(snapshot.DBTime))
+	header.Set(HeaderLockRetries, strconv.FormatInt(snapshot.LockRetries, 10))
+	header.Set(HeaderEventsPublished, strconv.FormatInt(snapshot.EventsPublished, 10))
+	header.Set(HeaderPublishTimeMs, formatMilliseconds(snapshot.PublishTime))
+}
+
+func (w *debugStatsWriter) WriteHeaderNow() {
+	w.setHeaders()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *debugStatsWriter) Write(data []byte) (int, error) {
+	w.setHeaders()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *debugStatsWriter) WriteString(s string) (int, error) {
+	w.setHeaders()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Flush отправляет заголовки статистики перед streaming ответом.
+func (w *debugStatsWriter) Flush() {
+	w.setHeaders()
+	w.ResponseWriter.Flush()
+}
+
+// Compile-time check
+var _ http.Flusher = (*debugStatsWriter)(nil)
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func formatMilliseconds(d time.Duration) string {
+	return strconv.FormatFloat(milliseconds(d), 'f', 2, 64)
+}
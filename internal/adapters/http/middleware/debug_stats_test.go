@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+func TestDebugStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(environment, role string) *gin.Engine {
+		router := gin.New()
+		if role != "" {
+			router.Use(func(c *gin.Context) {
+				httpctx.SetAuthRole(c, role)
+				c.Next()
+			})
+		}
+		router.Use(DebugStats(&DebugStatsConfig{Environment: environment}))
+		router.GET("/test", func(c *gin.Context) {
+			stats := ports.RequestStatsFromContext(c.Request.Context())
+			stats.RecordQuery(2 * time.Millisecond)
+			stats.RecordQuery(time.Millisecond)
+			stats.RecordLockRetry()
+			stats.RecordPublish(2, time.Millisecond)
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		})
+		router.DELETE("/test", func(c *gin.Context) {
+			ports.RequestStatsFromContext(c.Request.Context()).RecordQuery(time.Millisecond)
+			c.Status(http.StatusNoContent)
+		})
+		return router
+	}
+
+	serve := func(router *gin.Engine, method string, debug bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/test", nil)
+		if debug {
+			req.Header.Set(DebugStatsHeader, "1")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("NoHeadersWithoutOptIn", func(t *testing.T) {
+		w := serve(newRouter("development", ""), http.MethodGet, false)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(HeaderDBQueries))
+		assert.Empty(t, w.Header().Get(HeaderEventsPublished))
+	})
+
+	t.Run("HeadersWithOptIn", func(t *testing.T) {
+		w := serve(newRouter("development", ""), http.MethodGet, true)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get(HeaderDBQueries))
+		assert.Equal(t, "3.00", w.Header().Get(HeaderDBTimeMs))
+		assert.Equal(t, "1", w.Header().Get(HeaderLockRetries))
+		assert.Equal(t, "2", w.Header().Get(HeaderEventsPublished))
+		assert.Equal(t, "1.00", w.Header().Get(HeaderPublishTimeMs))
+	})
+
+	t.Run("HeadersOnEmptyBody", func(t *testing.T) {
+		w := serve(newRouter("development", ""), http.MethodDelete, true)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "1", w.Header().Get(HeaderDBQueries))
+	})
+
+	t.Run("ProductionHiddenForNonAdmin", func(t *testing.T) {
+		w := serve(newRouter("production", "user"), http.MethodGet, true)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(HeaderDBQueries))
+		assert.Empty(t, w.Header().Get(HeaderLockRetries))
+	})
+
+	t.Run("ProductionVisibleForAdmin", func(t *testing.T) {
+		w := serve(newRouter("production", "admin"), http.MethodGet, true)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get(HeaderDBQueries))
+		assert.Equal(t, "2", w.Header().Get(HeaderEventsPublished))
+	})
+}
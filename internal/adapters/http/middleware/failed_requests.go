@@ -0,0 +1,169 @@
+// Package middleware - захват образцов неудачных денежных операций.
+//
+// Поддержка разбирает обращения "перевод не прошёл": middleware сохраняет
+// запрос и ответ каждой неудачной мутирующей операции с кошельками и
+// транзакциями. Успешные ответы не сохраняются никогда.
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
+	"github.com/Haleralex/wallethub/internal/adapters/http/routes"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// DefaultFailedRequestMaxBodyBytes - лимит каждого тела в образце по умолчанию.
+const DefaultFailedRequestMaxBodyBytes = 16 << 10
+
+// FailedRequestCaptureConfig - конфигурация захвата неудачных запросов.
+type FailedRequestCaptureConfig struct {
+	// Recorder - очередь записи образцов (nil - захват выключен)
+	Recorder ports.FailedRequestRecorder
+	// MaxBodyBytes - лимит тела запроса и тела ответа; длиннее - обрезается
+	// (по умолчанию DefaultFailedRequestMaxBodyBytes)
+	MaxBodyBytes int
+}
+
+// CaptureFailedRequests middleware передаёт в Recorder запрос и ответ
+// мутирующих запросов (POST, PUT, PATCH, DELETE), завершившихся 4xx/5xx.
+//
+// Не захватываются:
+//   - успешные ответы (< 400)
+//   - 401, 403 и 429 - отказы аутентификации и rate limit, а не провалы
+//     операций (они видны в журнале безопасности и метриках)
+//
+// Тела копируются по мере чтения handler'ом и записи ответа, не больше
+// MaxBodyBytes каждое; очистка от чувствительных данных и запись
+// выполняются Recorder'ом асинхронно, запрос их не ждёт.
+func CaptureFailedRequests(config *FailedRequestCaptureConfig) gin.HandlerFunc {
+	if config == nil || config.Recorder == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	maxBytes := config.MaxBodyBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultFailedRequestMaxBodyBytes
+	}
+
+	return func(c *gin.Context) {
+		if !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		var requestBody *captureReader
+		if c.Request.Body != nil {
+			requestBody = &captureReader{ReadCloser: c.Request.Body, limit: maxBytes}
+			c.Request.Body = requestBody
+		}
+		writer := &captureWriter{ResponseWriter: c.Writer, limit: maxBytes}
+		c.Writer = writer
+
+		c.Next()
+
+		status := writer.Status()
+		if !isCapturedFailure(status) {
+			return
+		}
+
+		capture := ports.FailedRequestCapture{
+			Method:       c.Request.Method,
+			Route:        routes.PathTemplate(c.FullPath()),
+			StatusCode:   status,
+			RequestID:    httpctx.RequestID(c),
+			ResponseBody: writer.body.Bytes(),
+			Truncated:    writer.truncated,
+			OccurredAt:   time.Now().UTC(),
+		}
+		if userID, ok := httpctx.AuthUserID(c); ok {
+			capture.ActorID = userID
+		}
+		if requestBody != nil {
+			// Handler мог ответить, не дочитав тело: добираем до лимита
+			requestBody.drain()
+			capture.RequestBody = requestBody.body.Bytes()
+			capture.Truncated = capture.Truncated || requestBody.truncated
+		}
+
+		config.Recorder.Record(capture)
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isCapturedFailure - 4xx/5xx, кроме отказов аутентификации и rate limit.
+func isCapturedFailure(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return status >= http.StatusBadRequest
+}
+
+// captureReader копирует прочитанное тело запроса (не больше limit байт).
+type captureReader struct {
+	io.ReadCloser
+	body      bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (r *captureReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.keep(p[:n])
+	return n, err
+}
+
+func (r *captureReader) keep(p []byte) {
+	if room := r.limit - r.body.Len(); len(p) > room {
+		p = p[:room]
+		r.truncated = true
+	}
+	r.body.Write(p)
+}
+
+// drain дочитывает непрочитанное тело, пока оно помещается в лимит.
+func (r *captureReader) drain() {
+	if r.truncated {
+		return
+	}
+	rest, _ := io.ReadAll(io.LimitReader(r.ReadCloser, int64(r.limit-r.body.Len()+1)))
+	r.keep(rest)
+}
+
+// captureWriter копирует тело ответа (не больше limit байт).
+type captureWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	w.keep(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.keep([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *captureWriter) keep(p []byte) {
+	if room := w.limit - w.body.Len(); len(p) > room {
+		p = p[:room]
+		w.truncated = true
+	}
+	w.body.Write(p)
+}
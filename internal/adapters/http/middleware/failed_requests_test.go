@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// recordedCaptures - FailedRequestRecorder, запоминающий образцы.
+type recordedCaptures []ports.FailedRequestCapture
+
+func (r *recordedCaptures) Record(capture ports.FailedRequestCapture) {
+	*r = append(*r, capture)
+}
+
+// newCaptureRouter - роутер с захватом перед handler'ом, который читает
+// тело (если read) и отвечает заданным статусом.
+func newCaptureRouter(recorder ports.FailedRequestRecorder, userID uuid.UUID, maxBytes int, status int, read bool) *gin.Engine {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		httpctx.SetRequestID(c, "req-1")
+		httpctx.SetAuthUserID(c, userID)
+	})
+	router.Use(CaptureFailedRequests(&FailedRequestCaptureConfig{Recorder: recorder, MaxBodyBytes: maxBytes}))
+	handler := func(c *gin.Context) {
+		if read {
+			body, _ := io.ReadAll(c.Request.Body)
+			if len(body) == 0 {
+				c.Status(http.StatusInternalServerError)
+				return
+			}
+		}
+		c.JSON(status, gin.H{"success": status < 400})
+	}
+	router.POST("/api/v1/wallets/:id/debit", handler)
+	router.GET("/api/v1/wallets/:id", handler)
+	return router
+}
+
+func TestCaptureFailedRequests(t *testing.T) {
+	const body = `{"amount":"10.00","currency":"USD"}`
+	userID := uuid.New()
+
+	serve := func(router *gin.Engine, method, body string) int {
+		req := httptest.NewRequest(method, "/api/v1/wallets/42/debit", strings.NewReader(body))
+		if method == http.MethodGet {
+			req = httptest.NewRequest(method, "/api/v1/wallets/42", nil)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("ClientErrorCaptured", func(t *testing.T) {
+		var recorder recordedCaptures
+		router := newCaptureRouter(&recorder, userID, 0, http.StatusUnprocessableEntity, true)
+
+		require.Equal(t, http.StatusUnprocessableEntity, serve(router, http.MethodPost, body))
+		require.Len(t, recorder, 1)
+
+		capture := recorder[0]
+		assert.Equal(t, http.MethodPost, capture.Method)
+		assert.Equal(t, "/api/v1/wallets/{id}/debit", capture.Route)
+		assert.Equal(t, http.StatusUnprocessableEntity, capture.StatusCode)
+		assert.Equal(t, "req-1", capture.RequestID)
+		assert.Equal(t, userID, capture.ActorID)
+		assert.Equal(t, body, string(capture.RequestBody))
+		assert.JSONEq(t, `{"success":false}`, string(capture.ResponseBody))
+		assert.False(t, capture.Truncated)
+		assert.False(t, capture.OccurredAt.IsZero())
+	})
+
+	t.Run("UnreadBodyStillCaptured", func(t *testing.T) {
+		var recorder recordedCaptures
+		router := newCaptureRouter(&recorder, userID, 0, http.StatusNotFound, false)
+
+		require.Equal(t, http.StatusNotFound, serve(router, http.MethodPost, body))
+		require.Len(t, recorder, 1)
+		assert.Equal(t, body, string(recorder[0].RequestBody))
+	})
+
+	t.Run("BodiesCutAtLimit", func(t *testing.T) {
+		var recorder recordedCaptures
+		router := newCaptureRouter(&recorder, userID, 8, http.StatusInternalServerError, true)
+
+		require.Equal(t, http.StatusInternalServerError, serve(router, http.MethodPost, body))
+		require.Len(t, recorder, 1)
+		assert.Equal(t, body[:8], string(recorder[0].RequestBody))
+		assert.Len(t, recorder[0].ResponseBody, 8)
+		assert.True(t, recorder[0].Truncated)
+	})
+
+	t.Run("SuccessNotCaptured", func(t *testing.T) {
+		var recorder recordedCaptures
+		router := newCaptureRouter(&recorder, userID, 0, http.StatusOK, true)
+
+		require.Equal(t, http.StatusOK, serve(router, http.MethodPost, body))
+		assert.Empty(t, recorder)
+	})
+
+	t.Run("AuthAndRateLimitNotCaptured", func(t *testing.T) {
+		for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests} {
+			var recorder recordedCaptures
+			router := newCaptureRouter(&recorder, userID, 0, status, true)
+
+			require.Equal(t, status, serve(router, http.MethodPost, body))
+			assert.Empty(t, recorder, "status %d", status)
+		}
+	})
+
+	t.Run("ReadOnlyMethodNotCaptured", func(t *testing.T) {
+		var recorder recordedCaptures
+		router := newCaptureRouter(&recorder, userID, 0, http.StatusNotFound, false)
+
+		require.Equal(t, http.StatusNotFound, serve(router, http.MethodGet, ""))
+		assert.Empty(t, recorder)
+	})
+
+	t.Run("NilRecorderDisablesCapture", func(t *testing.T) {
+		router := newCaptureRouter(nil, userID, 0, http.StatusUnprocessableEntity, true)
+		assert.Equal(t, http.StatusUnprocessableEntity, serve(router, http.MethodPost, body))
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
 	"github.com/Haleralex/wallethub/internal/pkg/logger"
 	"github.com/gin-gonic/gin"
 )
@@ -65,14 +66,12 @@ func Logging(config *LoggingConfig) gin.HandlerFunc {
 		start := time.Now()
 
 		// Add correlation IDs to context for downstream logging
-		requestID := GetRequestID(c)
+		requestID := httpctx.RequestID(c)
 		ctx := c.Request.Context()
 		ctx = logger.WithRequestID(ctx, requestID)
 		ctx = logger.WithCorrelationID(ctx, requestID) // Use request ID as correlation ID
-		if userID, exists := c.Get("user_id"); exists {
-			if uid, ok := userID.(string); ok {
-				ctx = logger.WithUserID(ctx, uid)
-			}
+		if userID, ok := httpctx.AuthUserID(c); ok {
+			ctx = logger.WithUserID(ctx, userID.String())
 		}
 		c.Request = c.Request.WithContext(ctx)
 
@@ -105,7 +104,7 @@ func Logging(config *LoggingConfig) gin.HandlerFunc {
 			slog.String("query", c.Request.URL.RawQuery),
 			slog.Int("status", c.Writer.Status()),
 			slog.Duration("duration", duration),
-			slog.String("request_id", GetRequestID(c)),
+			slog.String("request_id", httpctx.RequestID(c)),
 			slog.String("client_ip", c.ClientIP()),
 			slog.String("user_agent", c.Request.UserAgent()),
 			slog.Int("response_size", c.Writer.Size()),
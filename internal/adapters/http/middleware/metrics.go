@@ -141,6 +141,104 @@ var (
 	)
 )
 
+// Security metrics
+var (
+	// securityEventsDropped counts security events lost by the async log
+	SecurityEventsDropped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "paybridge",
+			Subsystem: "security",
+			Name:      "events_dropped_total",
+			Help:      "Total number of security events dropped before being stored",
+		},
+		[]string{"reason"}, // queue_full, write_failed
+	)
+)
+
+// Failed request capture metrics
+var (
+	// FailedRequestsDropped counts failed request samples lost by the async recorder
+	FailedRequestsDropped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "paybridge",
+			Subsystem: "http",
+			Name:      "failed_request_samples_dropped_total",
+			Help:      "Total number of failed request samples dropped before being stored",
+		},
+		[]string{"reason"}, // queue_full, write_failed
+	)
+)
+
+// Outbox metrics
+var (
+	// outboxQueueDepth tracks pending outbox events per delivery lane
+	OutboxQueueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "paybridge",
+			Subsystem: "outbox",
+			Name:      "queue_depth",
+			Help:      "Number of pending outbox events",
+		},
+		[]string{"priority"}, // HIGH, NORMAL, LOW
+	)
+
+	// outboxDeliveryLatency measures time from event creation to publication
+	OutboxDeliveryLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "paybridge",
+			Subsystem: "outbox",
+			Name:      "delivery_latency_seconds",
+			Help:      "Time from outbox insert to publication in seconds",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300},
+		},
+		[]string{"priority"},
+	)
+)
+
+// Wallet migration metrics
+var (
+	// walletMigrationSampled tracks wallets checked by the last comparison run
+	WalletMigrationSampled = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "paybridge",
+			Subsystem: "wallet_migration",
+			Name:      "sampled_wallets",
+			Help:      "Number of wallets checked by the last balance schema comparison",
+		},
+	)
+
+	// walletMigrationDivergent tracks wallets whose old and new balances differ
+	WalletMigrationDivergent = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "paybridge",
+			Subsystem: "wallet_migration",
+			Name:      "divergent_wallets",
+			Help:      "Number of sampled wallets whose balance schemas diverge",
+		},
+	)
+
+	// walletMigrationShadowMismatches counts reads that saw diverging schemas
+	WalletMigrationShadowMismatches = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "paybridge",
+			Subsystem: "wallet_migration",
+			Name:      "shadow_read_mismatches_total",
+			Help:      "Total number of wallet reads whose old and new balances differ",
+		},
+	)
+
+	// DBReadHedgesTotal counts replica reads that were hedged to the primary
+	DBReadHedgesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "paybridge",
+			Subsystem: "db",
+			Name:      "read_hedges_total",
+			Help:      "Total number of slow replica reads duplicated to the primary, by winner",
+		},
+		[]string{"group", "winner"},
+	)
+)
+
 // Metrics returns Prometheus metrics middleware
 func Metrics() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -187,6 +285,46 @@ func RecordDBError(operation, errorType string) {
 	DBErrorsTotal.WithLabelValues(operation, errorType).Inc()
 }
 
+// RecordSecurityEventDropped records a security event lost by the async log
+func RecordSecurityEventDropped(reason string) {
+	SecurityEventsDropped.WithLabelValues(reason).Inc()
+}
+
+// RecordFailedRequestDropped records a failed request sample lost by the async recorder
+func RecordFailedRequestDropped(reason string) {
+	FailedRequestsDropped.WithLabelValues(reason).Inc()
+}
+
+// SetOutboxQueueDepth records pending outbox events of a lane
+func SetOutboxQueueDepth(priority string, depth int) {
+	OutboxQueueDepth.WithLabelValues(priority).Set(float64(depth))
+}
+
+// RecordOutboxDelivery records the delivery latency of an outbox event
+func RecordOutboxDelivery(priority string, latency time.Duration) {
+	OutboxDeliveryLatency.WithLabelValues(priority).Observe(latency.Seconds())
+}
+
+// RecordWalletDivergenceSample records the result of a balance schema comparison
+func RecordWalletDivergenceSample(sampled, divergent int) {
+	WalletMigrationSampled.Set(float64(sampled))
+	WalletMigrationDivergent.Set(float64(divergent))
+}
+
+// RecordWalletShadowReadMismatch records a wallet read that saw diverging schemas
+func RecordWalletShadowReadMismatch() {
+	WalletMigrationShadowMismatches.Inc()
+}
+
+// RecordReadHedge records a hedged replica read and which side answered first
+func RecordReadHedge(group string, primaryWon bool) {
+	winner := "replica"
+	if primaryWon {
+		winner = "primary"
+	}
+	DBReadHedgesTotal.WithLabelValues(group, winner).Inc()
+}
+
 // UpdateDBConnections updates database connection metrics
 func UpdateDBConnections(idle, inUse, max int32) {
 	DBConnectionsTotal.WithLabelValues("idle").Set(float64(idle))
@@ -15,6 +15,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
 	"github.com/Haleralex/wallethub/internal/infrastructure/cache"
 )
 
@@ -169,7 +170,7 @@ func RateLimit(config *RateLimitConfig) gin.HandlerFunc {
 					"message":     "Rate limit exceeded, please try again later",
 					"retry_after": retrySeconds,
 				},
-				"request_id": GetRequestID(c),
+				"request_id": httpctx.RequestID(c),
 				"timestamp":  time.Now().UTC(),
 			})
 			return
@@ -224,16 +225,9 @@ func SensitiveEndpointRateLimit() gin.HandlerFunc {
 // TransactionRateLimit - лимит для финансовых операций.
 func TransactionRateLimit() gin.HandlerFunc {
 	return RateLimit(&RateLimitConfig{
-		Limit:  30,          // 30 транзакций
-		Window: time.Minute, // в минуту
-		KeyFunc: func(c *gin.Context) string {
-			// По user ID если авторизован, иначе по IP
-			userID := GetAuthUserID(c)
-			if userID.String() != "00000000-0000-0000-0000-000000000000" {
-				return "user:" + userID.String()
-			}
-			return "ip:" + c.ClientIP()
-		},
+		Limit:   30,          // 30 транзакций
+		Window:  time.Minute, // в минуту
+		KeyFunc: ClientKey,   // По user ID если авторизован, иначе по IP
 	})
 }
 
@@ -285,7 +279,7 @@ func RedisRateLimit(rdb *redis.Client, config *RateLimitConfig) gin.HandlerFunc
 					"message":     "Rate limit exceeded, please try again later",
 					"retry_after": retrySeconds,
 				},
-				"request_id": GetRequestID(c),
+				"request_id": httpctx.RequestID(c),
 				"timestamp":  time.Now().UTC(),
 			})
 			return
@@ -0,0 +1,85 @@
+// Package middleware - выполнение запросов в snapshot сессии чтения.
+//
+// Сверка выгружает кошельки и транзакции постранично; запросы с токеном
+// сессии в X-Read-Session читают одно состояние БД (ports.ReadSessions),
+// поэтому страницы сходятся между собой.
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
+	"github.com/Haleralex/wallethub/internal/adapters/http/routes"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// ReadSessionHeader - заголовок с токеном сессии чтения.
+const ReadSessionHeader = "X-Read-Session"
+
+// ReadSessionForRoute строит middleware сессии чтения по метаданным
+// маршрута. Подключается к группе через WithRouteMiddleware после Auth.
+//
+// Запрос без заголовка проходит как обычно. С заголовком:
+// - безопасный маршрут (routes.IdempotencySafe) читает через snapshot
+// сессии; запросы одной сессии выполняются по очереди
+// - остальные маршруты отклоняются 409 READ_SESSION_READ_ONLY
+// - неизвестный, истёкший или чужой токен - 404 READ_SESSION_NOT_FOUND
+func ReadSessionForRoute(sessions ports.ReadSessions) routes.RouteMiddleware {
+	return func(route routes.Route) gin.HandlerFunc {
+		if route.Idempotency == routes.IdempotencySafe {
+			return attachReadSession(sessions)
+		}
+		return rejectInReadSession
+	}
+}
+
+// attachReadSession выполняет запрос в транзакции сессии из заголовка.
+func attachReadSession(sessions ports.ReadSessions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(ReadSessionHeader)
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		userID, ok := httpctx.AuthUserID(c)
+		if !ok {
+			common.HandleDomainError(c, ports.ErrReadSessionNotFound)
+			c.Abort()
+			return
+		}
+
+		ctx, release, err := sessions.Attach(c.Request.Context(), userID.String(), token)
+		if err != nil {
+			if c.Request.Context().Err() != nil {
+				// Клиент ушёл, пока ждал очереди сессии
+				c.Abort()
+				return
+			}
+			common.HandleDomainError(c, err)
+			c.Abort()
+			return
+		}
+		defer release()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// rejectInReadSession отклоняет мутирующий запрос с токеном сессии чтения.
+func rejectInReadSession(c *gin.Context) {
+	if c.GetHeader(ReadSessionHeader) == "" {
+		c.Next()
+		return
+	}
+
+	common.Error(c, http.StatusConflict, &common.APIError{
+		Code:    ports.ReadSessionReadOnlyCode,
+		Message: "Read sessions are read-only, send this request without the " + ReadSessionHeader + " header",
+	})
+	c.Abort()
+}
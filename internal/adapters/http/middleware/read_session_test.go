@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
+	"github.com/Haleralex/wallethub/internal/adapters/http/routes"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// sessionMarkerKey - метка сессии в context запроса.
+type sessionMarkerKey struct{}
+
+// fakeReadSessions знает одну сессию и помечает context её токеном.
+type fakeReadSessions struct {
+	clientID string
+	token    string
+	released int
+}
+
+func (f *fakeReadSessions) Open(context.Context, string) (*ports.ReadSession, error) {
+	return nil, nil
+}
+
+func (f *fakeReadSessions) Attach(ctx context.Context, clientID, token string) (context.Context, func(), error) {
+	if clientID != f.clientID || token != f.token {
+		return nil, nil, ports.ErrReadSessionNotFound
+	}
+	return context.WithValue(ctx, sessionMarkerKey{}, token), func() { f.released++ }, nil
+}
+
+func newReadSessionRouter(userID uuid.UUID, sessions *fakeReadSessions) *gin.Engine {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		httpctx.SetAuthUserID(c, userID)
+	})
+
+	group := routes.NewRegistry().Group(&router.RouterGroup, routes.Meta{Auth: routes.AuthUser, RateLimit: routes.RateLimitGlobal}).
+		WithRouteMiddleware(ReadSessionForRoute(sessions))
+	marker := func(c *gin.Context) {
+		token, _ := c.Request.Context().Value(sessionMarkerKey{}).(string)
+		c.String(http.StatusOK, token)
+	}
+	group.GET("/wallets", routes.Meta{}, marker)
+	group.POST("/wallets/:id/transactions", routes.Meta{Idempotency: routes.IdempotencySafe}, marker)
+	group.POST("/wallets", routes.Meta{Idempotency: routes.IdempotencyNone}, marker)
+	return router
+}
+
+func TestReadSessionForRoute(t *testing.T) {
+	userID := uuid.New()
+	sessions := &fakeReadSessions{clientID: userID.String(), token: "snapshot"}
+	router := newReadSessionRouter(userID, sessions)
+
+	do := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set(ReadSessionHeader, token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	errorCode := func(w *httptest.ResponseRecorder) string {
+		var body struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body.Error.Code
+	}
+
+	t.Run("NoHeader", func(t *testing.T) {
+		w := do(http.MethodGet, "/wallets", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Body.String())
+
+		w = do(http.MethodPost, "/wallets", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("SafeRoutesReadFromSession", func(t *testing.T) {
+		released := sessions.released
+
+		w := do(http.MethodGet, "/wallets", "snapshot")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "snapshot", w.Body.String())
+
+		// POST дубль чтения (IdempotencySafe) - тоже чтение
+		w = do(http.MethodPost, "/wallets/"+uuid.NewString()+"/transactions", "snapshot")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "snapshot", w.Body.String())
+
+		assert.Equal(t, released+2, sessions.released)
+	})
+
+	t.Run("MutationRejected", func(t *testing.T) {
+		w := do(http.MethodPost, "/wallets", "snapshot")
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, ports.ReadSessionReadOnlyCode, errorCode(w))
+	})
+
+	t.Run("UnknownToken", func(t *testing.T) {
+		w := do(http.MethodGet, "/wallets", "expired")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, ports.ReadSessionNotFoundCode, errorCode(w))
+	})
+
+	t.Run("OtherClient", func(t *testing.T) {
+		other := newReadSessionRouter(uuid.New(), sessions)
+		req := httptest.NewRequest(http.MethodGet, "/wallets", nil)
+		req.Header.Set(ReadSessionHeader, "snapshot")
+		w := httptest.NewRecorder()
+		other.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
 )
 
 // RecoveryConfig - конфигурация для recovery middleware.
@@ -51,7 +53,7 @@ func Recovery(config *RecoveryConfig) gin.HandlerFunc {
 					slog.String("error", fmt.Sprintf("%v", err)),
 					slog.String("path", c.Request.URL.Path),
 					slog.String("method", c.Request.Method),
-					slog.String("request_id", GetRequestID(c)),
+					slog.String("request_id", httpctx.RequestID(c)),
 					slog.String("client_ip", c.ClientIP()),
 				}
 
@@ -73,7 +75,7 @@ func Recovery(config *RecoveryConfig) gin.HandlerFunc {
 						"code":    "INTERNAL_ERROR",
 						"message": "An unexpected error occurred",
 					},
-					"request_id": GetRequestID(c),
+					"request_id": httpctx.RequestID(c),
 					"timestamp":  time.Now().UTC(),
 				})
 			}
@@ -13,15 +13,13 @@ package middleware
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-)
 
-const (
-	// RequestIDHeader - имя заголовка для Request ID
-	RequestIDHeader = "X-Request-ID"
-	// RequestIDContextKey - ключ для хранения Request ID в контексте
-	RequestIDContextKey = "request_id"
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
 )
 
+// RequestIDHeader - имя заголовка для Request ID
+const RequestIDHeader = "X-Request-ID"
+
 // RequestID middleware добавляет уникальный ID к каждому запросу.
 //
 // Зачем нужен Request ID:
@@ -40,7 +38,7 @@ func RequestID() gin.HandlerFunc {
 		}
 
 		// Сохраняем в контекст
-		c.Set(RequestIDContextKey, requestID)
+		httpctx.SetRequestID(c, requestID)
 
 		// Добавляем в response headers
 		c.Header(RequestIDHeader, requestID)
@@ -48,13 +46,3 @@ func RequestID() gin.HandlerFunc {
 		c.Next()
 	}
 }
-
-// GetRequestID извлекает Request ID из контекста Gin.
-func GetRequestID(c *gin.Context) string {
-	if id, exists := c.Get(RequestIDContextKey); exists {
-		if strID, ok := id.(string); ok {
-			return strID
-		}
-	}
-	return ""
-}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/httpctx"
 )
 
 func TestRequestID(t *testing.T) {
@@ -53,7 +55,7 @@ func TestRequestID(t *testing.T) {
 
 		var contextID string
 		router.GET("/test", func(c *gin.Context) {
-			contextID = GetRequestID(c)
+			contextID = httpctx.RequestID(c)
 			c.String(200, "ok")
 		})
 
@@ -67,34 +69,3 @@ func TestRequestID(t *testing.T) {
 		assert.NotEmpty(t, contextID)
 	})
 }
-
-func TestGetRequestID(t *testing.T) {
-	t.Run("ReturnsRequestID", func(t *testing.T) {
-		gin.SetMode(gin.TestMode)
-		c, _ := gin.CreateTestContext(httptest.NewRecorder())
-
-		expectedID := "test-id-123"
-		c.Set(RequestIDContextKey, expectedID)
-
-		actualID := GetRequestID(c)
-		assert.Equal(t, expectedID, actualID)
-	})
-
-	t.Run("ReturnsEmptyWhenNotSet", func(t *testing.T) {
-		gin.SetMode(gin.TestMode)
-		c, _ := gin.CreateTestContext(httptest.NewRecorder())
-
-		actualID := GetRequestID(c)
-		assert.Empty(t, actualID)
-	})
-
-	t.Run("ReturnsEmptyWhenWrongType", func(t *testing.T) {
-		gin.SetMode(gin.TestMode)
-		c, _ := gin.CreateTestContext(httptest.NewRecorder())
-
-		c.Set(RequestIDContextKey, 12345) // Wrong type
-
-		actualID := GetRequestID(c)
-		assert.Empty(t, actualID)
-	})
-}
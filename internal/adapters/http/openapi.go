@@ -0,0 +1,30 @@
+// Package http - OpenAPI спецификация всех маршрутов API.
+package http
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/handlers"
+	"github.com/Haleralex/wallethub/internal/adapters/http/openapi"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+)
+
+// GenerateOpenAPI строит спецификацию всех маршрутов API (для записи в
+// api/openapi.json). Роутер собирается со всеми опциональными группами -
+// CQRS, Telegram auth, sandbox, webhooks, manifest - но без зависимостей: handlers
+// не вызываются, нужны только зарегистрированные маршруты.
+func GenerateOpenAPI() (*openapi.Document, error) {
+	config := DefaultRouterConfig()
+	config.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	config.SandboxEnabled = true
+	config.WebhooksEnabled = true
+	config.RouteManifestEnabled = true
+
+	builder := NewRouterBuilder(config).
+		WithCQRS(cqrs.NewCommandBus(), cqrs.NewQueryBus()).
+		WithTelegramAuth(&TelegramAuthDeps{})
+	builder.Build()
+
+	return handlers.GenerateOpenAPI(builder.Registry())
+}
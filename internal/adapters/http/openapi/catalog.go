@@ -0,0 +1,71 @@
+// Package openapi - генерация OpenAPI 3.1 документа из метаданных маршрутов.
+//
+// Маршруты берутся из routes.Registry (метод, путь, auth, ссылки на схемы),
+// схемы тел - отражением Go типов, которые handlers реально разбирают и
+// отдают (json теги, binding/validate правила). Catalog связывает имя из
+// routes.SchemaRef с Go типом; маршрут без нужных метаданных или ссылка
+// без типа в каталоге - ошибка генерации, поэтому спецификация полна по
+// построению.
+package openapi
+
+import (
+	"reflect"
+)
+
+// Shape - как тело ответа обёрнуто в конверт API.
+type Shape int
+
+const (
+	// ShapeRaw - тип описывает тело целиком (тела запросов, /health).
+	ShapeRaw Shape = iota
+	// ShapeEnvelope - {success, data, meta, request_id, timestamp}, data - тип.
+	ShapeEnvelope
+	// ShapePage - {success, data: [...], pagination, request_id, timestamp},
+	// тип - элемент страницы.
+	ShapePage
+)
+
+// Body - Go тип тела и его конверт.
+type Body struct {
+	Type  reflect.Type
+	Shape Shape
+}
+
+// Raw описывает тело типом v целиком.
+func Raw(v any) Body {
+	return Body{Type: reflect.TypeOf(v), Shape: ShapeRaw}
+}
+
+// Envelope описывает ответ common.Success с data типа v.
+func Envelope(v any) Body {
+	return Body{Type: reflect.TypeOf(v), Shape: ShapeEnvelope}
+}
+
+// Page описывает страницу списка (common.SuccessPage) с элементами типа v.
+func Page(v any) Body {
+	return Body{Type: reflect.TypeOf(v), Shape: ShapePage}
+}
+
+// EnumValues - допустимые значения именованного строкового типа.
+type EnumValues struct {
+	Type   reflect.Type
+	Values []string
+}
+
+// Enum перечисляет значения типа T: константы отражением не найти.
+func Enum[T ~string](values ...T) EnumValues {
+	result := EnumValues{Type: reflect.TypeOf(values).Elem(), Values: make([]string, len(values))}
+	for i, v := range values {
+		result.Values[i] = string(v)
+	}
+	return result
+}
+
+// Catalog - Go типы схем, на которые ссылаются маршруты.
+type Catalog struct {
+	// Schemas - имя из routes.SchemaRef -> тело
+	Schemas map[string]Body
+
+	// Enums - значения именованных строковых типов, встречающихся в телах
+	Enums []EnumValues
+}
@@ -0,0 +1,137 @@
+// Package openapi - типы OpenAPI 3.1 документа.
+package openapi
+
+// Version - версия спецификации OpenAPI генерируемого документа.
+const Version = "3.1.0"
+
+// Document - корневой объект OpenAPI.
+type Document struct {
+	OpenAPI           string               `json:"openapi"`
+	JSONSchemaDialect string               `json:"jsonSchemaDialect"`
+	Info              Info                 `json:"info"`
+	Paths             map[string]*PathItem `json:"paths"`
+	Components        Components           `json:"components"`
+}
+
+// Info - описание API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Components - переиспользуемые схемы, ответы и схемы аутентификации.
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	Responses       map[string]*Response       `json:"responses,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme - способ аутентификации.
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// PathItem - операции одного пути по методам.
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Head   *Operation `json:"head,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+}
+
+// Operations возвращает операции пути по методам (HTTP метод -> операция).
+func (p *PathItem) Operations() map[string]*Operation {
+	result := make(map[string]*Operation)
+	for method, op := range map[string]*Operation{
+		"GET": p.Get, "HEAD": p.Head, "POST": p.Post, "PUT": p.Put, "PATCH": p.Patch, "DELETE": p.Delete,
+	} {
+		if op != nil {
+			result[method] = op
+		}
+	}
+	return result
+}
+
+// SecurityRequirement - схема аутентификации -> scopes.
+type SecurityRequirement map[string][]string
+
+// Operation - описание одного метода пути.
+//
+// Расширения x-* повторяют метаданные route manifest, которых нет в OpenAPI.
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []SecurityRequirement `json:"security"`
+
+	Auth         string `json:"x-auth"`
+	Idempotency  string `json:"x-idempotency"`
+	RateLimit    string `json:"x-rate-limit"`
+	Concurrency  string `json:"x-concurrency"`
+	Confirmation bool   `json:"x-confirmation,omitempty"`
+	Policy       string `json:"x-policy,omitempty"`
+}
+
+// Parameter - параметр пути, query или заголовка.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody - тело запроса.
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response - ответ с одним кодом статуса или ссылка на components/responses.
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType - схема тела для content type.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Schema - JSON Schema (draft 2020-12, диалект OpenAPI 3.1).
+//
+// Type - строка или список типов (["string", "null"] для nullable полей).
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Examples             []any              `json:"examples,omitempty"`
+}
+
+// componentRef возвращает ссылку на схему components/schemas.
+func componentRef(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
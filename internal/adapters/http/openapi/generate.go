@@ -0,0 +1,433 @@
+// Package openapi - сборка документа из маршрутов.
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+	"github.com/Haleralex/wallethub/internal/adapters/http/routes"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	domainerrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// jsonSchemaDialect - диалект схем OpenAPI 3.1 по умолчанию.
+const jsonSchemaDialect = "https://spec.openapis.org/oas/3.1/dialect/base"
+
+// errorResponseSchema - конверт ошибки, на который ссылаются все ответы 4xx/5xx.
+const errorResponseSchema = "ErrorResponse"
+
+// bearerAuth - схема аутентификации маршрутов с auth user/admin.
+const bearerAuth = "bearerAuth"
+
+// Generate строит OpenAPI 3.1 документ по зарегистрированным маршрутам.
+//
+// Возвращает ошибку, перечисляющую все маршруты, которые нельзя описать:
+// без схемы ответа, со ссылкой вне каталога, с телом запроса в конверте,
+// а также Go типы, которые нельзя перевести в JSON Schema.
+func Generate(info Info, routeList []routes.Route, catalog Catalog) (*Document, error) {
+	g := &generator{
+		catalog:      catalog,
+		builder:      newSchemaBuilder(catalog),
+		operationIDs: make(map[string]string),
+		used:         make(map[string]direction),
+		responses:    make(map[string]*Response),
+	}
+
+	doc := &Document{
+		OpenAPI:           Version,
+		JSONSchemaDialect: jsonSchemaDialect,
+		Info:              info,
+		Paths:             make(map[string]*PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	for _, route := range routeList {
+		op := g.operation(route)
+		if op == nil {
+			continue
+		}
+		item := doc.Paths[route.Path]
+		if item == nil {
+			item = &PathItem{}
+			doc.Paths[route.Path] = item
+		}
+		if !setOperation(item, route.Method, op) {
+			g.fail(route, "method is not supported by OpenAPI path items")
+		}
+	}
+
+	g.defineSchemas()
+	doc.Components.Schemas = g.builder.schemas
+	doc.Components.Responses = g.responses
+
+	errs := append(g.errs, g.builder.errs...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("openapi: %w", errors.Join(errs...))
+	}
+	return doc, nil
+}
+
+// generator - состояние одной генерации.
+type generator struct {
+	catalog      Catalog
+	builder      *schemaBuilder
+	operationIDs map[string]string    // operationId -> "METHOD path"
+	used         map[string]direction // схемы каталога, на которые сослались маршруты
+	responses    map[string]*Response // ответы с ошибкой, на которые сослались маршруты
+	errs         []error
+}
+
+func (g *generator) fail(route routes.Route, format string, args ...any) {
+	g.errs = append(g.errs, fmt.Errorf("%s %s: %s", route.Method, route.Path, fmt.Sprintf(format, args...)))
+}
+
+// operation описывает маршрут. nil - маршрут описать нельзя (ошибка записана).
+func (g *generator) operation(route routes.Route) *Operation {
+	op := &Operation{
+		OperationID:  operationID(route),
+		Tags:         []string{tag(route.Path)},
+		Responses:    make(map[string]*Response),
+		Security:     []SecurityRequirement{},
+		Auth:         route.Auth,
+		Idempotency:  route.Idempotency,
+		RateLimit:    route.RateLimit,
+		Concurrency:  route.Concurrency,
+		Confirmation: route.Confirmation,
+	}
+	if route.Auth != routes.AuthPublic {
+		op.Security = []SecurityRequirement{{bearerAuth: {}}}
+	}
+	if route.Policy != nil {
+		op.Policy = route.Policy.String()
+	}
+
+	if other, ok := g.operationIDs[op.OperationID]; ok {
+		g.fail(route, "operationId %s is already used by %s", op.OperationID, other)
+		return nil
+	}
+	g.operationIDs[op.OperationID] = route.Method + " " + route.Path
+
+	for _, name := range pathParams(route.Path) {
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	if route.Confirmation {
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:        "X-Confirm-Operation",
+			In:          "header",
+			Description: "Confirmation token from a previous 428 response to the same request",
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+
+	if route.Request != "" {
+		name, ok := g.use(route, route.Request, directionRequest)
+		if !ok {
+			return nil
+		}
+		if g.catalog.Schemas[name].Shape != ShapeRaw {
+			g.fail(route, "request schema %s must describe the body as is", name)
+			return nil
+		}
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: componentRef(name)}},
+		}
+	}
+
+	if !g.successResponses(route, op) {
+		return nil
+	}
+	for status, e := range errorResponses(route) {
+		op.Responses[strconv.Itoa(status)] = &Response{Ref: "#/components/responses/" + e.Name}
+		g.responses[e.Name] = e.response(status)
+	}
+	return op
+}
+
+// successResponses добавляет успешные ответы маршрута.
+func (g *generator) successResponses(route routes.Route, op *Operation) bool {
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	switch {
+	case route.Method == http.MethodHead:
+		op.Responses[strconv.Itoa(status)] = &Response{Description: "Headers of the matching GET response"}
+		return true
+	case status == http.StatusNoContent:
+		op.Responses[strconv.Itoa(status)] = &Response{Description: http.StatusText(status)}
+		return true
+	case route.ContentType != "":
+		op.Responses[strconv.Itoa(status)] = &Response{
+			Description: http.StatusText(status),
+			Content:     map[string]*MediaType{route.ContentType: {}},
+		}
+		return true
+	case route.Response == "":
+		g.fail(route, "missing response schema (set Response, Status 204 or ContentType)")
+		return false
+	}
+
+	name, ok := g.use(route, route.Response, directionResponse)
+	if !ok {
+		return false
+	}
+	content := map[string]*MediaType{"application/json": {Schema: componentRef(name)}}
+	op.Responses[strconv.Itoa(status)] = &Response{Description: http.StatusText(status), Content: content}
+
+	// Повтор с тем же idempotency_key возвращает первый результат с 200
+	if status == http.StatusCreated && route.Idempotency == routes.IdempotencyKey {
+		op.Responses[strconv.Itoa(http.StatusOK)] = &Response{
+			Description: "Idempotent replay of an earlier request",
+			Content:     content,
+		}
+	}
+	return true
+}
+
+// use проверяет ссылку маршрута на схему каталога и запоминает её.
+func (g *generator) use(route routes.Route, ref string, dir direction) (string, bool) {
+	name := routes.SchemaName(ref)
+	if name == "" {
+		g.fail(route, "schema reference %q is not a routes.SchemaRef", ref)
+		return "", false
+	}
+	if _, ok := g.catalog.Schemas[name]; !ok {
+		g.fail(route, "schema %s has no Go type in the catalog", name)
+		return "", false
+	}
+	if prev, ok := g.used[name]; ok && prev != dir {
+		g.fail(route, "schema %s is used as both request and response", name)
+		return "", false
+	}
+	g.used[name] = dir
+	return name, true
+}
+
+// defineSchemas строит схемы каталога, на которые сослались маршруты,
+// и конверт ошибки.
+func (g *generator) defineSchemas() {
+	names := make([]string, 0, len(g.used))
+	for name := range g.used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b := g.builder
+	for _, name := range names {
+		body := g.catalog.Schemas[name]
+		switch body.Shape {
+		case ShapeRaw:
+			b.define(name, body.Type, g.used[name])
+		default:
+			b.schemas[name] = b.envelope(body)
+		}
+	}
+
+	b.schemas[errorResponseSchema] = &Schema{
+		Type:     "object",
+		Required: []string{"error", "request_id", "success", "timestamp"},
+		Properties: map[string]*Schema{
+			"success":    {Type: "boolean", Enum: []any{false}},
+			"error":      b.schema(reflect.TypeOf(common.APIError{}), directionResponse),
+			"request_id": {Type: "string"},
+			"timestamp":  {Type: "string", Format: "date-time"},
+		},
+	}
+}
+
+// envelope описывает ответ в конверте common.APIResponse.
+func (b *schemaBuilder) envelope(body Body) *Schema {
+	s := &Schema{
+		Type:     "object",
+		Required: []string{"data", "request_id", "success", "timestamp"},
+		Properties: map[string]*Schema{
+			"success":    {Type: "boolean", Enum: []any{true}},
+			"request_id": {Type: "string"},
+			"timestamp":  {Type: "string", Format: "date-time"},
+		},
+	}
+
+	switch body.Shape {
+	case ShapePage:
+		s.Properties["data"] = &Schema{Type: "array", Items: b.schema(body.Type, directionResponse)}
+		s.Properties["pagination"] = b.schema(reflect.TypeOf(dtos.PaginationDTO{}), directionResponse)
+		s.Required = []string{"data", "pagination", "request_id", "success", "timestamp"}
+	default:
+		s.Properties["data"] = b.schema(body.Type, directionResponse)
+		s.Properties["meta"] = b.schema(reflect.TypeOf(common.APIMeta{}), directionResponse)
+	}
+	return s
+}
+
+// ============================================
+// Error Responses
+// ============================================
+
+// errorResponse - ответ с ошибкой в components/responses.
+type errorResponse struct {
+	Name  string
+	Codes []string // значения error.code
+}
+
+// Варианты ответов с ошибкой. Денежные операции (rate limit financial)
+// возвращают больше кодов 422 и 429, чем остальные маршруты.
+var (
+	validationError            = errorResponse{"ValidationError", []string{common.ErrCodeValidation, common.ErrCodeBadRequest}}
+	unauthorizedError          = errorResponse{"UnauthorizedError", []string{common.ErrCodeUnauthorized}}
+	forbiddenError             = errorResponse{"ForbiddenError", []string{common.ErrCodeForbidden}}
+	policyDeniedError          = errorResponse{"PolicyDeniedError", []string{common.ErrCodePolicyDenied}}
+	notFoundError              = errorResponse{"NotFoundError", []string{common.ErrCodeNotFound}}
+	conflictError              = errorResponse{"ConflictError", []string{common.ErrCodeConflict, common.ErrCodeConcurrency}}
+	businessRuleError          = errorResponse{"BusinessRuleError", []string{common.ErrCodeBusinessRule}}
+	financialBusinessRuleError = errorResponse{"FinancialBusinessRuleError", []string{common.ErrCodeBusinessRule, "INSUFFICIENT_BALANCE"}}
+	confirmationRequired       = errorResponse{"ConfirmationRequired", []string{"CONFIRMATION_REQUIRED", "CONFIRMATION_INVALID"}}
+	rateLimitError             = errorResponse{"RateLimitError", []string{common.ErrCodeTooManyRequests, common.ErrCodeConcurrencyLimitExceeded}}
+	financialRateLimitError    = errorResponse{"FinancialRateLimitError", []string{common.ErrCodeTooManyRequests, common.ErrCodeConcurrencyLimitExceeded, "WALLET_BUSY"}}
+	operationDisabledError     = errorResponse{"OperationDisabledError", []string{domainerrors.OperationDisabledCode}}
+	internalError              = errorResponse{"InternalError", []string{common.ErrCodeInternal}}
+)
+
+// errorResponses - ответы с ошибкой, которые маршрут может вернуть, по
+// статусам. Выводятся из метаданных: auth scope, политика доступа,
+// параметры пути, тело, rate limit, идемпотентность и подтверждение.
+func errorResponses(route routes.Route) map[int]errorResponse {
+	result := map[int]errorResponse{
+		http.StatusTooManyRequests:     rateLimitError,
+		http.StatusInternalServerError: internalError,
+	}
+	if route.ContentType != "" {
+		return result
+	}
+
+	hasParams := len(pathParams(route.Path)) > 0
+	if route.Request != "" || hasParams {
+		result[http.StatusBadRequest] = validationError
+	}
+	if hasParams {
+		result[http.StatusNotFound] = notFoundError
+	}
+	if route.Auth != routes.AuthPublic {
+		result[http.StatusUnauthorized] = unauthorizedError
+	}
+	if route.Auth == routes.AuthAdmin {
+		result[http.StatusForbidden] = forbiddenError
+	}
+	if route.Policy != nil {
+		result[http.StatusForbidden] = policyDeniedError
+	}
+	if route.Idempotency != routes.IdempotencySafe {
+		result[http.StatusConflict] = conflictError
+		result[http.StatusUnprocessableEntity] = businessRuleError
+	}
+	if route.RateLimit == routes.RateLimitFinancial {
+		result[http.StatusUnprocessableEntity] = financialBusinessRuleError
+		result[http.StatusTooManyRequests] = financialRateLimitError
+		result[http.StatusServiceUnavailable] = operationDisabledError
+	}
+	if route.Confirmation {
+		result[http.StatusPreconditionRequired] = confirmationRequired
+	}
+	return result
+}
+
+// response описывает ошибку конвертом ErrorResponse с перечислением error.code.
+func (e errorResponse) response(status int) *Response {
+	enum := make([]any, len(e.Codes))
+	for i, code := range e.Codes {
+		enum[i] = code
+	}
+
+	return &Response{
+		Description: http.StatusText(status),
+		Content: map[string]*MediaType{"application/json": {Schema: &Schema{
+			AllOf: []*Schema{
+				componentRef(errorResponseSchema),
+				{Properties: map[string]*Schema{
+					"error": {Properties: map[string]*Schema{"code": {Enum: enum}}},
+				}},
+			},
+		}}},
+	}
+}
+
+// ============================================
+// Helpers
+// ============================================
+
+// setOperation кладёт операцию в path item. false - метод не поддерживается.
+func setOperation(item *PathItem, method string, op *Operation) bool {
+	switch method {
+	case http.MethodGet:
+		item.Get = op
+	case http.MethodHead:
+		item.Head = op
+	case http.MethodPost:
+		item.Post = op
+	case http.MethodPut:
+		item.Put = op
+	case http.MethodPatch:
+		item.Patch = op
+	case http.MethodDelete:
+		item.Delete = op
+	default:
+		return false
+	}
+	return true
+}
+
+// pathParams возвращает имена параметров шаблона пути ({id}).
+func pathParams(path string) []string {
+	var params []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params = append(params, segment[1:len(segment)-1])
+		}
+	}
+	return params
+}
+
+// operationID строит operationId из метода и пути:
+// POST /api/v1/wallets/{id}/credit -> postWalletsByIdCredit.
+func operationID(route routes.Route) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(route.Method))
+	for _, segment := range strings.Split(strings.TrimPrefix(route.Path, "/api/v1"), "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, "{") {
+			sb.WriteString("By")
+			segment = strings.Trim(segment, "{}")
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return sb.String()
+}
+
+// tag группирует операции по первому сегменту пути после /api/v1.
+func tag(path string) string {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/v1"), "/"), "/")
+	if segments[0] == "" {
+		return "root"
+	}
+	return segments[0]
+}
@@ -0,0 +1,195 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/policy"
+	"github.com/Haleralex/wallethub/internal/adapters/http/routes"
+)
+
+type testStatus string
+
+type testAudit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testItemDTO struct {
+	testAudit
+	ID       string            `json:"id"`
+	Status   testStatus        `json:"status"`
+	Note     *string           `json:"note"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Raw      json.RawMessage   `json:"raw,omitempty"`
+	Internal string            `json:"-"`
+	hidden   string
+}
+
+type testCreateRequest struct {
+	Kind   string   `json:"kind" binding:"required,oneof=A B"`
+	Key    string   `json:"key" binding:"required,uuid"`
+	Name   string   `json:"name,omitempty" binding:"omitempty,min=2,max=10"`
+	Amount *int64   `json:"amount,omitempty" binding:"omitempty,min=0"`
+	Tags   []string `json:"tags" binding:"max=3,dive,min=1"`
+}
+
+func testCatalog() Catalog {
+	return Catalog{
+		Schemas: map[string]Body{
+			"CreateItemRequest": Raw(testCreateRequest{}),
+			"ItemResponse":      Envelope(testItemDTO{}),
+			"ItemListResponse":  Page(testItemDTO{}),
+		},
+		Enums: []EnumValues{Enum[testStatus]("ACTIVE", "CLOSED")},
+	}
+}
+
+func testRoute(method, path string, meta Meta) routes.Route {
+	if meta.Auth == "" {
+		meta.Auth = routes.AuthUser
+	}
+	if meta.Idempotency == "" {
+		meta.Idempotency = routes.IdempotencySafe
+	}
+	if meta.RateLimit == "" {
+		meta.RateLimit = routes.RateLimitGlobal
+	}
+	return routes.Route{Method: method, Path: path, Meta: meta}
+}
+
+// Meta - сокращение для таблиц маршрутов в тестах.
+type Meta = routes.Meta
+
+func TestGenerate_Schemas(t *testing.T) {
+	doc, err := Generate(Info{Title: "Test", Version: "1"}, []routes.Route{
+		testRoute(http.MethodPost, "/items", Meta{
+			Idempotency: routes.IdempotencyNone,
+			Request:     routes.SchemaRef("CreateItemRequest"),
+			Response:    routes.SchemaRef("ItemResponse"),
+			Status:      http.StatusCreated,
+		}),
+		testRoute(http.MethodGet, "/items", Meta{Response: routes.SchemaRef("ItemListResponse")}),
+		testRoute(http.MethodGet, "/items/{id}", Meta{
+			Response: routes.SchemaRef("ItemResponse"),
+			Policy:   policy.Or(policy.Owner("item", "id"), policy.Admin()),
+		}),
+	}, testCatalog())
+	require.NoError(t, err)
+	schemas := doc.Components.Schemas
+
+	t.Run("RequestRequiredFromBindingRules", func(t *testing.T) {
+		req := schemas["CreateItemRequest"]
+		require.NotNil(t, req)
+		assert.Equal(t, []string{"key", "kind"}, req.Required)
+		assert.Equal(t, []any{"A", "B"}, req.Properties["kind"].Enum)
+		assert.Equal(t, "uuid", req.Properties["key"].Format)
+		assert.Equal(t, 2, *req.Properties["name"].MinLength)
+		assert.Equal(t, 10, *req.Properties["name"].MaxLength)
+		assert.Equal(t, "integer", req.Properties["amount"].Type)
+		assert.Equal(t, 3, *req.Properties["tags"].MaxItems)
+		assert.Nil(t, req.Properties["tags"].MinItems, "rules after dive apply to items")
+	})
+
+	t.Run("ResponseRequiredFromOmitempty", func(t *testing.T) {
+		item := schemas["testItemDTO"]
+		require.NotNil(t, item)
+		assert.Equal(t, []string{"created_at", "id", "note", "status"}, item.Required)
+		assert.Equal(t, "date-time", item.Properties["created_at"].Format, "embedded fields are inlined")
+		assert.Equal(t, []string{"string", "null"}, item.Properties["note"].Type)
+		assert.Equal(t, []any{"ACTIVE", "CLOSED"}, item.Properties["status"].Enum)
+		assert.Equal(t, "string", item.Properties["labels"].AdditionalProperties.Type)
+		assert.NotContains(t, item.Properties, "Internal")
+		assert.NotContains(t, item.Properties, "hidden")
+	})
+
+	t.Run("Envelopes", func(t *testing.T) {
+		envelope := schemas["ItemResponse"]
+		assert.Equal(t, "#/components/schemas/testItemDTO", envelope.Properties["data"].Ref)
+		assert.Contains(t, envelope.Required, "success")
+
+		page := schemas["ItemListResponse"]
+		assert.Equal(t, "array", page.Properties["data"].Type)
+		assert.Equal(t, "#/components/schemas/testItemDTO", page.Properties["data"].Items.Ref)
+		assert.Contains(t, page.Required, "pagination")
+	})
+
+	t.Run("Operation", func(t *testing.T) {
+		op := doc.Paths["/items"].Post
+		require.NotNil(t, op)
+		assert.Equal(t, "postItems", op.OperationID)
+		assert.Contains(t, op.Responses, "201")
+		assert.Equal(t, "#/components/responses/ValidationError", op.Responses["400"].Ref)
+		assert.Equal(t, "#/components/responses/UnauthorizedError", op.Responses["401"].Ref)
+		assert.Equal(t, "#/components/responses/BusinessRuleError", op.Responses["422"].Ref)
+		assert.NotContains(t, op.Responses, "403", "user routes are not admin-only")
+		assert.Equal(t, []SecurityRequirement{{bearerAuth: {}}}, op.Security)
+		assert.Empty(t, op.Policy)
+	})
+
+	t.Run("Policy", func(t *testing.T) {
+		op := doc.Paths["/items/{id}"].Get
+		require.NotNil(t, op)
+		assert.Equal(t, "or(owner(item:id), role(admin|superadmin))", op.Policy)
+		assert.Equal(t, "#/components/responses/PolicyDeniedError", op.Responses["403"].Ref)
+	})
+}
+
+func TestGenerate_IncompleteMetadata(t *testing.T) {
+	tests := []struct {
+		name  string
+		route routes.Route
+		err   string
+	}{
+		{
+			name:  "MissingResponse",
+			route: testRoute(http.MethodGet, "/items/{id}", Meta{}),
+			err:   "GET /items/{id}: missing response schema",
+		},
+		{
+			name:  "UnknownSchema",
+			route: testRoute(http.MethodGet, "/items", Meta{Response: routes.SchemaRef("NoSuchResponse")}),
+			err:   "GET /items: schema NoSuchResponse has no Go type in the catalog",
+		},
+		{
+			name: "EnvelopedRequest",
+			route: testRoute(http.MethodPost, "/items", Meta{
+				Request:  routes.SchemaRef("ItemResponse"),
+				Response: routes.SchemaRef("ItemResponse"),
+			}),
+			err: "POST /items: request schema ItemResponse must describe the body as is",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(Info{Title: "Test", Version: "1"}, []routes.Route{tt.route}, testCatalog())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestGenerate_RoutesWithoutJSONBody(t *testing.T) {
+	doc, err := Generate(Info{Title: "Test", Version: "1"}, []routes.Route{
+		testRoute(http.MethodHead, "/items/{id}", Meta{}),
+		testRoute(http.MethodDelete, "/items/{id}", Meta{Idempotency: routes.IdempotencyNone, Status: http.StatusNoContent}),
+		testRoute(http.MethodGet, "/metrics", Meta{Auth: routes.AuthPublic, ContentType: "text/plain"}),
+	}, testCatalog())
+	require.NoError(t, err)
+
+	assert.Nil(t, doc.Paths["/items/{id}"].Head.Responses["200"].Content)
+	assert.Contains(t, doc.Paths["/items/{id}"].Delete.Responses, "204")
+	assert.Contains(t, doc.Paths["/metrics"].Get.Responses["200"].Content, "text/plain")
+	assert.Empty(t, doc.Paths["/metrics"].Get.Security, "public routes need no auth")
+}
+
+func TestOperationID(t *testing.T) {
+	assert.Equal(t, "postWalletsByIdCredit", operationID(routes.Route{Method: http.MethodPost, Path: "/api/v1/wallets/{id}/credit"}))
+	assert.Equal(t, "getMetaOpenapiJson", operationID(routes.Route{Method: http.MethodGet, Path: "/api/v1/meta/openapi.json"}))
+	assert.Equal(t, "getAdminTransactionsFailureStats", operationID(routes.Route{Method: http.MethodGet, Path: "/api/v1/admin/transactions/failure-stats"}))
+}
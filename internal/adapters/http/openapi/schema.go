@@ -0,0 +1,355 @@
+// Package openapi - JSON Schema из Go типов.
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// direction - тело запроса или ответа: от него зависит, какие поля обязательны.
+type direction int
+
+const (
+	// directionRequest - обязательны поля с правилом binding/validate "required"
+	directionRequest direction = iota
+	// directionResponse - обязательны поля без omitempty
+	directionResponse
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshaler     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler     = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	genericArgsRegexp = regexp.MustCompile(`[\w./-]*\.`)
+)
+
+// component - схема именованного Go типа в components/schemas.
+type component struct {
+	typ       reflect.Type
+	direction direction
+}
+
+// schemaBuilder строит схемы и собирает компоненты для именованных структур.
+type schemaBuilder struct {
+	enums      map[reflect.Type][]string
+	reserved   map[string]reflect.Type // имена каталога; чужой Go тип с таким именем получает суффикс Data
+	schemas    map[string]*Schema
+	components map[string]component
+	names      map[reflect.Type]string
+	errs       []error
+}
+
+func newSchemaBuilder(catalog Catalog) *schemaBuilder {
+	b := &schemaBuilder{
+		enums:      make(map[reflect.Type][]string),
+		reserved:   make(map[string]reflect.Type),
+		schemas:    make(map[string]*Schema),
+		components: make(map[string]component),
+		names:      make(map[reflect.Type]string),
+	}
+	for _, enum := range catalog.Enums {
+		b.enums[enum.Type] = enum.Values
+	}
+	for name, body := range catalog.Schemas {
+		if body.Shape == ShapeRaw {
+			b.reserved[name] = derefType(body.Type)
+		} else {
+			b.reserved[name] = nil
+		}
+	}
+	return b
+}
+
+// define добавляет в components схему name, описывающую t целиком
+// (тело запроса или ответ без конверта). Структура получает имя каталога,
+// если на неё ещё не ссылались под своим.
+func (b *schemaBuilder) define(name string, t reflect.Type, dir direction) {
+	t = derefType(t)
+	if _, named := b.names[t]; t.Kind() == reflect.Struct && t.Name() != "" && !named {
+		b.component(name, t, dir)
+		return
+	}
+	b.schemas[name] = b.schema(t, dir)
+}
+
+// schema возвращает схему значения типа t.
+func (b *schemaBuilder) schema(t reflect.Type, dir direction) *Schema {
+	if t.Kind() == reflect.Pointer {
+		return b.schema(t.Elem(), dir)
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{Description: "Arbitrary JSON value"}
+	case t.Implements(jsonMarshaler) || reflect.PointerTo(t).Implements(jsonMarshaler):
+		b.errs = append(b.errs, fmt.Errorf("type %s has custom JSON encoding and no schema mapping", t))
+		return &Schema{}
+	case t.Implements(textMarshaler) || reflect.PointerTo(t).Implements(textMarshaler):
+		return &Schema{Type: "string"}
+	}
+
+	var s *Schema
+	switch t.Kind() {
+	case reflect.Bool:
+		s = &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64:
+		s = &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		s = &Schema{Type: "integer", Format: "int32"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = &Schema{Type: "integer", Minimum: float(0)}
+	case reflect.Float32, reflect.Float64:
+		s = &Schema{Type: "number"}
+	case reflect.String:
+		s = &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+		s = &Schema{Type: "array", Items: b.schema(t.Elem(), dir)}
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			b.errs = append(b.errs, fmt.Errorf("type %s: map keys must be strings", t))
+		}
+		s = &Schema{Type: "object", AdditionalProperties: b.schema(t.Elem(), dir)}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t, dir)
+		}
+		return b.ref(t, dir)
+	default:
+		b.errs = append(b.errs, fmt.Errorf("type %s: kind %s has no JSON representation", t, t.Kind()))
+		return &Schema{}
+	}
+
+	if values, ok := b.enums[t]; ok {
+		for _, v := range values {
+			s.Enum = append(s.Enum, v)
+		}
+	}
+	return s
+}
+
+// ref возвращает ссылку на компонент именованной структуры, при первом
+// обращении строя его схему.
+func (b *schemaBuilder) ref(t reflect.Type, dir direction) *Schema {
+	if existing, ok := b.names[t]; ok {
+		if c := b.components[existing]; c.direction != dir {
+			b.errs = append(b.errs, fmt.Errorf("type %s is used in both request and response bodies", t))
+		}
+		return componentRef(existing)
+	}
+
+	name := typeName(t)
+	if owner, ok := b.reserved[name]; ok && owner != t {
+		name += "Data"
+	}
+	b.component(name, t, dir)
+	return componentRef(name)
+}
+
+// component строит схему структуры t под именем name.
+func (b *schemaBuilder) component(name string, t reflect.Type, dir direction) {
+	if c, ok := b.components[name]; ok {
+		b.errs = append(b.errs, fmt.Errorf("schema name %s is used by both %s and %s", name, c.typ, t))
+		return
+	}
+
+	b.names[t] = name
+	b.components[name] = component{typ: t, direction: dir}
+	b.schemas[name] = b.structSchema(t, dir)
+}
+
+// structSchema описывает поля структуры так же, как их кодирует encoding/json.
+func (b *schemaBuilder) structSchema(t reflect.Type, dir direction) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	b.addFields(s, t, dir)
+	sort.Strings(s.Required)
+	return s
+}
+
+func (b *schemaBuilder) addFields(s *Schema, t reflect.Type, dir direction) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// Встроенная структура без имени в json - поля поднимаются наверх
+		if field.Anonymous && name == "" {
+			if ft := derefType(field.Type); ft.Kind() == reflect.Struct {
+				b.addFields(s, ft, dir)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		omitEmpty := hasOption(opts, "omitempty")
+		fs := b.schema(field.Type, dir)
+		if hasOption(opts, "string") {
+			fs = &Schema{Type: "string"}
+		}
+		rules := validationRules(field.Tag)
+		applyRules(fs, field.Type, rules)
+		if example := field.Tag.Get("example"); example != "" && fs.Type == "string" {
+			fs.Examples = []any{example}
+		}
+
+		if field.Type.Kind() == reflect.Pointer && dir == directionResponse && !omitEmpty {
+			fs = nullable(fs)
+		}
+		s.Properties[name] = fs
+
+		required := !omitEmpty
+		if dir == directionRequest {
+			required = hasRule(rules, "required")
+		}
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// ============================================
+// Validation Rules
+// ============================================
+
+// validationRules возвращает правила binding (gin) или validate тега.
+// Правила после dive относятся к элементам и не учитываются.
+func validationRules(tag reflect.StructTag) []string {
+	value := tag.Get("binding")
+	if value == "" {
+		value = tag.Get("validate")
+	}
+	if value == "" {
+		return nil
+	}
+
+	var rules []string
+	for _, rule := range strings.Split(value, ",") {
+		if rule == "dive" {
+			break
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func hasRule(rules []string, name string) bool {
+	for _, rule := range rules {
+		if rule == name {
+			return true
+		}
+	}
+	return false
+}
+
+// applyRules переносит правила валидатора в ограничения схемы.
+func applyRules(s *Schema, t reflect.Type, rules []string) {
+	kind := derefType(t).Kind()
+	for _, rule := range rules {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "uuid":
+			s.Format = "uuid"
+		case "email":
+			s.Format = "email"
+		case "url":
+			s.Format = "uri"
+		case "oneof":
+			s.Enum = nil
+			for _, v := range strings.Fields(value) {
+				s.Enum = append(s.Enum, v)
+			}
+		case "min", "max", "len":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			switch kind {
+			case reflect.String:
+				if key != "max" {
+					s.MinLength = &n
+				}
+				if key != "min" {
+					s.MaxLength = &n
+				}
+			case reflect.Slice, reflect.Array:
+				if key != "max" {
+					s.MinItems = &n
+				}
+				if key != "min" {
+					s.MaxItems = &n
+				}
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+				reflect.Float32, reflect.Float64:
+				if key != "max" {
+					s.Minimum = float(float64(n))
+				}
+				if key != "min" {
+					s.Maximum = float(float64(n))
+				}
+			}
+		}
+	}
+}
+
+// ============================================
+// Helpers
+// ============================================
+
+// nullable разрешает схеме значение null.
+func nullable(s *Schema) *Schema {
+	if typ, ok := s.Type.(string); ok && s.Ref == "" {
+		s.Type = []string{typ, "null"}
+		return s
+	}
+	return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+}
+
+// typeName - имя компонента для Go типа. Аргументы generic типа
+// приклеиваются к имени: Page[dtos.WalletDTO] -> PageWalletDTO.
+func typeName(t reflect.Type) string {
+	name := genericArgsRegexp.ReplaceAllString(t.Name(), "")
+	return strings.NewReplacer("[", "", "]", "", ",", "", "*", "").Replace(name)
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func hasOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+func float(v float64) *float64 {
+	return &v
+}
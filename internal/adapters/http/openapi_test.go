@@ -0,0 +1,425 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+)
+
+// generatedSpec возвращает спецификацию всех маршрутов как JSON объект.
+func generatedSpec(t *testing.T) map[string]any {
+	t.Helper()
+
+	doc, err := GenerateOpenAPI()
+	require.NoError(t, err, "every registered route must carry enough metadata to be described")
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(data, &spec))
+	return spec
+}
+
+// Правила из схемы OpenAPI 3.1 (https://spec.openapis.org/oas/3.1/schema),
+// которые может нарушить генератор.
+var (
+	specVersionPattern   = regexp.MustCompile(`^3\.1\.\d+(-.+)?$`)
+	responseCodePattern  = regexp.MustCompile(`^[1-5](?:[0-9]{2}|XX)$`)
+	componentNamePattern = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+	pathParamPattern     = regexp.MustCompile(`\{([^}]+)\}`)
+
+	operationKeys = map[string]bool{
+		"tags": true, "summary": true, "description": true, "externalDocs": true, "operationId": true,
+		"parameters": true, "requestBody": true, "responses": true, "callbacks": true, "deprecated": true,
+		"security": true, "servers": true,
+	}
+	pathItemKeys = map[string]bool{
+		"$ref": true, "summary": true, "description": true, "servers": true, "parameters": true,
+		"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true, "trace": true,
+	}
+	parameterLocations = map[string]bool{"query": true, "header": true, "path": true, "cookie": true}
+	jsonSchemaTypes    = map[string]bool{
+		"null": true, "boolean": true, "object": true, "array": true, "number": true, "string": true, "integer": true,
+	}
+)
+
+// validateOpenAPI31 проверяет документ по обязательным требованиям схемы
+// OpenAPI 3.1 и ссылочную целостность, возвращая все нарушения.
+func validateOpenAPI31(spec map[string]any) []string {
+	var problems []string
+	fail := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	version, _ := spec["openapi"].(string)
+	if !specVersionPattern.MatchString(version) {
+		fail("openapi: %q is not a 3.1 version", version)
+	}
+	info, _ := spec["info"].(map[string]any)
+	if title, _ := info["title"].(string); title == "" {
+		fail("info.title is required")
+	}
+	if v, _ := info["version"].(string); v == "" {
+		fail("info.version is required")
+	}
+
+	components, _ := spec["components"].(map[string]any)
+	schemas, _ := components["schemas"].(map[string]any)
+	responses, _ := components["responses"].(map[string]any)
+	securitySchemes, _ := components["securitySchemes"].(map[string]any)
+	for _, group := range []map[string]any{schemas, responses, securitySchemes} {
+		for name := range group {
+			if !componentNamePattern.MatchString(name) {
+				fail("components: invalid name %q", name)
+			}
+		}
+	}
+
+	// Все $ref в документе разрешаются
+	walkJSON(spec, "#", func(path string, node map[string]any) {
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return
+		}
+		if resolveRef(spec, ref) == nil {
+			fail("%s: unresolved $ref %s", path, ref)
+		}
+	})
+
+	// Схемы - JSON Schema с допустимыми типами
+	walkJSON(schemas, "#/components/schemas", func(path string, node map[string]any) {
+		if strings.HasSuffix(path, "/properties") {
+			return // имена свойств, а не схема
+		}
+		switch typ := node["type"].(type) {
+		case nil:
+		case string:
+			if !jsonSchemaTypes[typ] {
+				fail("%s: invalid type %q", path, typ)
+			}
+		case []any:
+			for _, item := range typ {
+				if s, _ := item.(string); !jsonSchemaTypes[s] {
+					fail("%s: invalid type %v", path, item)
+				}
+			}
+		default:
+			fail("%s: type must be a string or an array", path)
+		}
+		if required, ok := node["required"].([]any); ok {
+			properties, _ := node["properties"].(map[string]any)
+			for _, name := range required {
+				if _, ok := properties[name.(string)]; !ok && properties != nil {
+					fail("%s: required property %v is not defined", path, name)
+				}
+			}
+		}
+	})
+
+	for name, response := range responses {
+		if d, _ := response.(map[string]any)["description"].(string); d == "" {
+			fail("components.responses.%s: description is required", name)
+		}
+	}
+
+	paths, ok := spec["paths"].(map[string]any)
+	if !ok {
+		fail("paths is required")
+	}
+	operationIDs := make(map[string]string)
+	for path, rawItem := range paths {
+		if !strings.HasPrefix(path, "/") {
+			fail("paths: %q must start with /", path)
+		}
+		templateParams := make(map[string]bool)
+		for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+			templateParams[match[1]] = true
+		}
+
+		item := rawItem.(map[string]any)
+		for method, rawOp := range item {
+			where := strings.ToUpper(method) + " " + path
+			if !pathItemKeys[method] && !strings.HasPrefix(method, "x-") {
+				fail("%s: unknown path item field", where)
+				continue
+			}
+			op := rawOp.(map[string]any)
+			for key := range op {
+				if !operationKeys[key] && !strings.HasPrefix(key, "x-") {
+					fail("%s: unknown operation field %q", where, key)
+				}
+			}
+
+			id, _ := op["operationId"].(string)
+			if other, dup := operationIDs[id]; dup {
+				fail("%s: operationId %s already used by %s", where, id, other)
+			}
+			operationIDs[id] = where
+
+			declared := make(map[string]bool)
+			params, _ := op["parameters"].([]any)
+			for _, rawParam := range params {
+				param := rawParam.(map[string]any)
+				name, _ := param["name"].(string)
+				in, _ := param["in"].(string)
+				if name == "" || !parameterLocations[in] {
+					fail("%s: parameter needs name and a valid in, got %v", where, param)
+				}
+				if _, ok := param["schema"]; !ok {
+					fail("%s: parameter %s has no schema", where, name)
+				}
+				if in == "path" {
+					declared[name] = true
+					if required, _ := param["required"].(bool); !required {
+						fail("%s: path parameter %s must be required", where, name)
+					}
+				}
+			}
+			for name := range templateParams {
+				if !declared[name] {
+					fail("%s: path parameter %s is not declared", where, name)
+				}
+			}
+			for name := range declared {
+				if !templateParams[name] {
+					fail("%s: parameter %s is not in the path template", where, name)
+				}
+			}
+
+			if body, ok := op["requestBody"].(map[string]any); ok {
+				if content, _ := body["content"].(map[string]any); len(content) == 0 {
+					fail("%s: requestBody.content is required", where)
+				}
+			}
+
+			opResponses, _ := op["responses"].(map[string]any)
+			if len(opResponses) == 0 {
+				fail("%s: at least one response is required", where)
+			}
+			for code, rawResponse := range opResponses {
+				if code != "default" && !responseCodePattern.MatchString(code) {
+					fail("%s: invalid response code %q", where, code)
+				}
+				response := rawResponse.(map[string]any)
+				if _, isRef := response["$ref"]; isRef {
+					continue
+				}
+				if d, _ := response["description"].(string); d == "" {
+					fail("%s %s: description is required", where, code)
+				}
+			}
+
+			security, _ := op["security"].([]any)
+			for _, requirement := range security {
+				for scheme := range requirement.(map[string]any) {
+					if _, ok := securitySchemes[scheme]; !ok {
+						fail("%s: unknown security scheme %s", where, scheme)
+					}
+				}
+			}
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+func TestOpenAPI_ValidDocument(t *testing.T) {
+	spec := generatedSpec(t)
+
+	assert.Empty(t, validateOpenAPI31(spec))
+	assert.Equal(t, "3.1.0", spec["openapi"])
+
+	t.Run("EveryRouteDescribed", func(t *testing.T) {
+		cfg := DefaultRouterConfig()
+		cfg.SandboxEnabled = true
+		cfg.RouteManifestEnabled = true
+		router := NewRouterBuilder(cfg).
+			WithCQRS(cqrs.NewCommandBus(), cqrs.NewQueryBus()).
+			WithTelegramAuth(&TelegramAuthDeps{}).
+			Build()
+
+		paths := spec["paths"].(map[string]any)
+		for _, route := range router.Routes() {
+			item, ok := paths[pathParamGin.ReplaceAllString(route.Path, "{$1}")].(map[string]any)
+			require.True(t, ok, "path %s missing", route.Path)
+			assert.Contains(t, item, strings.ToLower(route.Method), "%s %s missing", route.Method, route.Path)
+		}
+	})
+
+	t.Run("ValidatorRejectsBrokenDocument", func(t *testing.T) {
+		broken := generatedSpec(t)
+		broken["openapi"] = "3.0.3"
+		credit := broken["paths"].(map[string]any)["/api/v1/wallets/{id}/credit"].(map[string]any)["post"].(map[string]any)
+		credit["parameters"] = []any{}
+		credit["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"] =
+			map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/Missing"}}
+
+		problems := validateOpenAPI31(broken)
+		assert.Contains(t, problems, `openapi: "3.0.3" is not a 3.1 version`)
+		assert.Contains(t, problems, "POST /api/v1/wallets/{id}/credit: path parameter id is not declared")
+		assert.Contains(t, problems,
+			"#/paths//api/v1/wallets/{id}/credit/post/requestBody/content/application/json/schema: unresolved $ref #/components/schemas/Missing")
+	})
+}
+
+func TestOpenAPI_Endpoints(t *testing.T) {
+	spec := generatedSpec(t)
+	paths := spec["paths"].(map[string]any)
+	operation := func(path, method string) map[string]any {
+		item, ok := paths[path].(map[string]any)
+		require.True(t, ok, "path %s missing", path)
+		op, ok := item[method].(map[string]any)
+		require.True(t, ok, "%s %s missing", method, path)
+		return op
+	}
+	schemaOf := func(ref any) map[string]any {
+		schema := resolveRef(spec, ref.(map[string]any)["$ref"].(string))
+		require.NotNil(t, schema)
+		return schema
+	}
+	responseCodes := func(op map[string]any, status string) []any {
+		response := schemaOf(op["responses"].(map[string]any)[status])
+		schema := response["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+		return schema["allOf"].([]any)[1].(map[string]any)["properties"].(map[string]any)["error"].(map[string]any)["properties"].(map[string]any)["code"].(map[string]any)["enum"].([]any)
+	}
+
+	t.Run("CreditWallet", func(t *testing.T) {
+		op := operation("/api/v1/wallets/{id}/credit", "post")
+		assert.Equal(t, "financial", op["x-rate-limit"])
+		assert.Equal(t, "mutation", op["x-concurrency"])
+		assert.Equal(t, "idempotency_key", op["x-idempotency"])
+		assert.Equal(t, []any{map[string]any{"bearerAuth": []any{}}}, op["security"])
+
+		body := op["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"]
+		request := schemaOf(body)
+		assert.ElementsMatch(t, []any{"idempotency_key", "description"}, request["required"])
+		properties := request["properties"].(map[string]any)
+		assert.Equal(t, "uuid", properties["idempotency_key"].(map[string]any)["format"])
+		assert.Equal(t, float64(500), properties["description"].(map[string]any)["maxLength"])
+		assert.Contains(t, properties, "amount_minor")
+
+		responses := op["responses"].(map[string]any)
+		assert.Contains(t, responses, "201")
+		assert.Contains(t, responses, "200", "idempotent replay")
+		envelope := schemaOf(responses["201"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"])
+		data := schemaOf(envelope["properties"].(map[string]any)["data"])
+		assert.Contains(t, data["properties"], "transaction")
+		assert.Contains(t, data["properties"], "wallet")
+
+		assert.ElementsMatch(t, []any{"BUSINESS_RULE_VIOLATION", "INSUFFICIENT_BALANCE"}, responseCodes(op, "422"))
+		assert.ElementsMatch(t, []any{"TOO_MANY_REQUESTS", "CONCURRENCY_LIMIT_EXCEEDED", "WALLET_BUSY"}, responseCodes(op, "429"))
+		assert.Equal(t, []any{"OPERATION_TEMPORARILY_DISABLED"}, responseCodes(op, "503"))
+		assert.Equal(t, "owner(wallet:id)", op["x-policy"], "only the wallet owner moves funds")
+		assert.Contains(t, responses, "403")
+	})
+
+	t.Run("AdminGetUser", func(t *testing.T) {
+		op := operation("/api/v1/admin/users/{id}", "get")
+		assert.Equal(t, "admin", op["x-auth"])
+		assert.Nil(t, op["requestBody"])
+
+		responses := op["responses"].(map[string]any)
+		assert.Equal(t, []any{"FORBIDDEN"}, responseCodes(op, "403"))
+		assert.Equal(t, []any{"NOT_FOUND"}, responseCodes(op, "404"))
+		assert.NotContains(t, responses, "422", "safe routes change nothing")
+
+		envelope := schemaOf(responses["200"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"])
+		assert.ElementsMatch(t, []any{"success", "data", "request_id", "timestamp"}, envelope["required"])
+		user := schemaOf(envelope["properties"].(map[string]any)["data"])
+		properties := user["properties"].(map[string]any)
+		assert.Equal(t, "integer", properties["wallet_count"].(map[string]any)["type"])
+		assert.Equal(t, []any{"string", "null"}, properties["last_activity_at"].(map[string]any)["type"])
+	})
+
+	t.Run("DeleteWalletNote", func(t *testing.T) {
+		op := operation("/api/v1/admin/wallets/{id}/notes/{note_id}", "delete")
+		assert.Equal(t, true, op["x-confirmation"])
+		assert.Contains(t, op["responses"], "204")
+		assert.ElementsMatch(t, []any{"CONFIRMATION_REQUIRED", "CONFIRMATION_INVALID"}, responseCodes(op, "428"))
+	})
+}
+
+// Закоммиченный api/openapi.json совпадает с маршрутами: после изменения
+// роутера или DTO нужно выполнить make openapi.
+func TestOpenAPI_CommittedSpecIsCurrent(t *testing.T) {
+	committed, err := os.ReadFile("../../../api/openapi.json")
+	require.NoError(t, err)
+
+	var want map[string]any
+	require.NoError(t, json.Unmarshal(committed, &want))
+	assert.Equal(t, want, generatedSpec(t), "api/openapi.json is stale: run make openapi")
+}
+
+func TestRouter_OpenAPIEndpoint(t *testing.T) {
+	cfg := DefaultRouterConfig()
+	router := NewRouterBuilder(cfg).
+		WithCQRS(cqrs.NewCommandBus(), cqrs.NewQueryBus()).
+		Build()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/meta/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Empty(t, validateOpenAPI31(spec))
+
+	// Спецификация описывает маршруты этого экземпляра: без sandbox и Telegram auth
+	paths := spec["paths"].(map[string]any)
+	assert.Contains(t, paths, "/api/v1/meta/openapi.json")
+	assert.NotContains(t, paths, "/api/v1/sandbox/reset")
+	assert.NotContains(t, paths, "/api/v1/auth/telegram")
+}
+
+// ============================================
+// Helpers
+// ============================================
+
+var pathParamGin = regexp.MustCompile(`[:*]([^/]+)`)
+
+// resolveRef возвращает объект по локальной ссылке "#/a/b" (nil - не найден).
+func resolveRef(spec map[string]any, ref string) map[string]any {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+	var node any = spec
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil
+		}
+		node = object[part]
+	}
+	result, _ := node.(map[string]any)
+	return result
+}
+
+// walkJSON обходит все объекты JSON дерева.
+func walkJSON(node any, path string, visit func(path string, node map[string]any)) {
+	switch v := node.(type) {
+	case map[string]any:
+		visit(path, v)
+		for key, child := range v {
+			walkJSON(child, path+"/"+key, visit)
+		}
+	case []any:
+		for i, child := range v {
+			walkJSON(child, path+"/"+strconv.Itoa(i), visit)
+		}
+	}
+}
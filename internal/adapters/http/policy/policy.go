@@ -0,0 +1,299 @@
+// Package policy - декларативные политики доступа к маршрутам.
+//
+// Маршрут объявляет политику в метаданных (routes.Meta.Policy), а
+// middleware.AuthorizeForRoute проверяет её до handler'а, поэтому handlers
+// не содержат проверок владельца и роли. Политики:
+//   - Owner("wallet", "id") - ресурс из параметра пути принадлежит actor;
+//     ресурсы с KeyResolver адресуются не UUID, а ключом (transaction_key)
+//   - Role("admin") / Admin() - роль actor
+//   - Scope("wallets:read") - scope токена API ключа
+//
+// и их комбинации And / Or. Владелец ресурса определяется Resolver'ом,
+// зарегистрированным под именем ресурса (см. resolvers.go).
+package policy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Policy - требование доступа к маршруту.
+//
+// String - запись политики в route manifest, OpenAPI (x-policy) и в ответе
+// POLICY_DENIED, например "and(scope(wallets:read), or(owner(wallet:id), role(admin|superadmin)))".
+type Policy interface {
+	fmt.Stringer
+
+	// evaluate возвращает nil, если доступ разрешён, иначе - политику,
+	// на которой доступ не прошёл.
+	evaluate(ctx context.Context, req *Request) (Policy, error)
+	// check проверяет, что политика применима к маршруту.
+	check(resolvers Resolvers, params []string) error
+}
+
+// Request - данные запроса для проверки политики.
+type Request struct {
+	Actor ports.Actor
+	// Scopes - scopes токена API ключа; nil - токен пользователя без
+	// ограничений, проходит любую Scope
+	Scopes []string
+	// Param возвращает значение параметра пути
+	Param func(name string) string
+	// Resolvers - владельцы ресурсов по имени ресурса
+	Resolvers Resolvers
+}
+
+// Evaluate проверяет политику. Возвращает nil, если доступ разрешён, или
+// политику, на которой он не прошёл (для And - первую не прошедшую часть,
+// для Or - весь Or).
+//
+// Ошибка - доступ не удалось проверить: параметр пути не UUID
+// (errors.ValidationError) или ресурс не найден (errors.ErrEntityNotFound).
+func Evaluate(ctx context.Context, p Policy, req Request) (Policy, error) {
+	return p.evaluate(ctx, &req)
+}
+
+// Check проверяет при сборке роутера, что для каждой Owner есть Resolver,
+// а её параметр есть в пути маршрута.
+func Check(p Policy, resolvers Resolvers, params []string) error {
+	return p.check(resolvers, params)
+}
+
+// ============================================
+// Owner
+// ============================================
+
+// ownerPolicy - ресурс из параметра пути принадлежит actor.
+type ownerPolicy struct {
+	resource string
+	param    string
+}
+
+// Owner требует, чтобы ресурс resource с ID из параметра пути param
+// принадлежал actor.
+func Owner(resource, param string) Policy {
+	return ownerPolicy{resource: resource, param: param}
+}
+
+func (p ownerPolicy) String() string {
+	return "owner(" + p.resource + ":" + p.param + ")"
+}
+
+func (p ownerPolicy) evaluate(ctx context.Context, req *Request) (Policy, error) {
+	owner, err := p.ownerOf(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if owner != req.Actor.ID {
+		return p, nil
+	}
+	return nil, nil
+}
+
+// ownerOf определяет владельца ресурса. KeyResolver получает параметр пути
+// как есть, остальным Resolver'ам нужен UUID.
+func (p ownerPolicy) ownerOf(ctx context.Context, req *Request) (uuid.UUID, error) {
+	resolver := req.Resolvers[p.resource]
+	value := req.Param(p.param)
+	if keys, ok := resolver.(KeyResolver); ok {
+		return keys.OwnerOfKey(ctx, value)
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, errors.ValidationError{Field: p.param, Message: "Invalid UUID format", Code: errors.ValidationCodeInvalidFormat}
+	}
+	return resolver.OwnerOf(ctx, id)
+}
+
+func (p ownerPolicy) check(resolvers Resolvers, params []string) error {
+	if resolvers[p.resource] == nil {
+		return fmt.Errorf("%s: no resolver for resource %q", p, p.resource)
+	}
+	if !slices.Contains(params, p.param) {
+		return fmt.Errorf("%s: route has no path parameter %q", p, p.param)
+	}
+	return nil
+}
+
+func (p ownerPolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// ============================================
+// Role
+// ============================================
+
+// rolePolicy - роль actor одна из roles.
+type rolePolicy struct {
+	roles []string
+}
+
+// Role требует одну из ролей.
+func Role(roles ...string) Policy {
+	return rolePolicy{roles: roles}
+}
+
+// Admin требует роль администратора (admin или superadmin).
+func Admin() Policy {
+	return Role("admin", "superadmin")
+}
+
+func (p rolePolicy) String() string {
+	return "role(" + strings.Join(p.roles, "|") + ")"
+}
+
+func (p rolePolicy) evaluate(_ context.Context, req *Request) (Policy, error) {
+	if req.Actor.Role == "" || !slices.Contains(p.roles, req.Actor.Role) {
+		return p, nil
+	}
+	return nil, nil
+}
+
+func (p rolePolicy) check(Resolvers, []string) error {
+	if len(p.roles) == 0 {
+		return fmt.Errorf("%s: no roles", p)
+	}
+	return nil
+}
+
+func (p rolePolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// ============================================
+// Scope
+// ============================================
+
+// scopePolicy - токен API ключа выдан со scope.
+type scopePolicy struct {
+	scope string
+}
+
+// Scope требует scope у токена API ключа. Токен пользователя (без claim
+// scopes) проходит: он не ограничен scopes.
+func Scope(scope string) Policy {
+	return scopePolicy{scope: scope}
+}
+
+func (p scopePolicy) String() string {
+	return "scope(" + p.scope + ")"
+}
+
+func (p scopePolicy) evaluate(_ context.Context, req *Request) (Policy, error) {
+	if req.Scopes != nil && !slices.Contains(req.Scopes, p.scope) {
+		return p, nil
+	}
+	return nil, nil
+}
+
+func (p scopePolicy) check(Resolvers, []string) error {
+	if p.scope == "" {
+		return fmt.Errorf("scope(): empty scope")
+	}
+	return nil
+}
+
+func (p scopePolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// ScopeOnly сообщает, что политика состоит только из Scope - отказ по ней
+// означает нехватку scope у API ключа, а не чужой ресурс или роль.
+func ScopeOnly(p Policy) bool {
+	switch p := p.(type) {
+	case scopePolicy:
+		return true
+	case combinedPolicy:
+		for _, part := range p.parts {
+			if !ScopeOnly(part) {
+				return false
+			}
+		}
+		return len(p.parts) > 0
+	}
+	return false
+}
+
+// ============================================
+// And / Or
+// ============================================
+
+// combinedPolicy - And или Or над частями.
+type combinedPolicy struct {
+	op    string // "and" или "or"
+	parts []Policy
+}
+
+// And требует все политики. Проверяются по порядку до первого отказа.
+func And(parts ...Policy) Policy {
+	return combinedPolicy{op: "and", parts: parts}
+}
+
+// Or требует хотя бы одну политику. Проверяются по порядку до первой
+// прошедшей, поэтому дешёвые проверки стоит ставить первыми.
+func Or(parts ...Policy) Policy {
+	return combinedPolicy{op: "or", parts: parts}
+}
+
+func (p combinedPolicy) String() string {
+	parts := make([]string, len(p.parts))
+	for i, part := range p.parts {
+		parts[i] = part.String()
+	}
+	return p.op + "(" + strings.Join(parts, ", ") + ")"
+}
+
+func (p combinedPolicy) evaluate(ctx context.Context, req *Request) (Policy, error) {
+	if p.op == "and" {
+		for _, part := range p.parts {
+			if denied, err := part.evaluate(ctx, req); denied != nil || err != nil {
+				return denied, err
+			}
+		}
+		return nil, nil
+	}
+
+	// Or: ошибка одной части (например, ресурс не найден) не мешает
+	// пройти по другой; возвращается, только если не прошла ни одна
+	var firstErr error
+	for _, part := range p.parts {
+		denied, err := part.evaluate(ctx, req)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if denied == nil {
+			return nil, nil
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return p, nil
+}
+
+func (p combinedPolicy) check(resolvers Resolvers, params []string) error {
+	if len(p.parts) == 0 {
+		return fmt.Errorf("%s: no policies", p)
+	}
+	for _, part := range p.parts {
+		if err := part.check(resolvers, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p combinedPolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
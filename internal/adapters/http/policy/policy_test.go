@@ -0,0 +1,190 @@
+package policy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// countingResolver - владельцы по таблице со счётчиком обращений.
+type countingResolver struct {
+	owners map[uuid.UUID]uuid.UUID
+	calls  int
+}
+
+func (r *countingResolver) OwnerOf(_ context.Context, id uuid.UUID) (uuid.UUID, error) {
+	r.calls++
+	owner, ok := r.owners[id]
+	if !ok {
+		return uuid.Nil, errors.ErrEntityNotFound
+	}
+	return owner, nil
+}
+
+func TestEvaluate(t *testing.T) {
+	ownerID, walletID := uuid.New(), uuid.New()
+	resolvers := Resolvers{ResourceWallet: &countingResolver{owners: map[uuid.UUID]uuid.UUID{walletID: ownerID}}}
+	request := func(actor ports.Actor, scopes []string, id string) Request {
+		return Request{
+			Actor:     actor,
+			Scopes:    scopes,
+			Param:     func(string) string { return id },
+			Resolvers: resolvers,
+		}
+	}
+	owner := ports.Actor{ID: ownerID, Role: "user"}
+	other := ports.Actor{ID: uuid.New(), Role: "user"}
+	admin := ports.Actor{ID: uuid.New(), Role: "admin"}
+
+	isOwner := Owner(ResourceWallet, "id")
+	ownerOrAdmin := Or(isOwner, Admin())
+	wallet := walletID.String()
+
+	tests := []struct {
+		name       string
+		policy     Policy
+		req        Request
+		wantDenied Policy
+	}{
+		{"OwnerAllowed", isOwner, request(owner, nil, wallet), nil},
+		{"OtherUserDenied", isOwner, request(other, nil, wallet), isOwner},
+		{"RoleAllowed", Admin(), request(admin, nil, wallet), nil},
+		{"RoleDenied", Role("superadmin"), request(admin, nil, wallet), Role("superadmin")},
+		{"UserTokenNotRestrictedByScope", Scope("wallets:read"), request(owner, nil, wallet), nil},
+		{"APIKeyWithScope", Scope("wallets:read"), request(owner, []string{"wallets:read"}, wallet), nil},
+		{"APIKeyWithoutScope", Scope("wallets:read"), request(owner, []string{}, wallet), Scope("wallets:read")},
+		{"OrDeniedNamesWholeOr", ownerOrAdmin, request(other, nil, wallet), ownerOrAdmin},
+		{"OrSecondBranch", ownerOrAdmin, request(admin, nil, wallet), nil},
+		{"AndNamesFailedPart", And(Scope("wallets:read"), ownerOrAdmin), request(admin, []string{"x"}, wallet), Scope("wallets:read")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			denied, err := Evaluate(context.Background(), tt.policy, tt.req)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDenied, denied)
+		})
+	}
+
+	t.Run("InvalidID", func(t *testing.T) {
+		_, err := Evaluate(context.Background(), isOwner, request(owner, nil, "not-a-uuid"))
+
+		var validation errors.ValidationError
+		require.ErrorAs(t, err, &validation)
+		assert.Equal(t, "id", validation.Field)
+		assert.Equal(t, errors.ValidationCodeInvalidFormat, validation.Code)
+	})
+
+	t.Run("UnknownResource", func(t *testing.T) {
+		unknown := uuid.NewString()
+
+		// Администратор проходит по второй ветке Or, handler ответит 404 сам
+		denied, err := Evaluate(context.Background(), ownerOrAdmin, request(admin, nil, unknown))
+		require.NoError(t, err)
+		assert.Nil(t, denied)
+
+		_, err = Evaluate(context.Background(), ownerOrAdmin, request(other, nil, unknown))
+		assert.True(t, errors.IsNotFound(err))
+	})
+
+	t.Run("KeyResolverGetsRawParam", func(t *testing.T) {
+		var got string
+		keys := KeyResolverFunc(func(_ context.Context, key string) (uuid.UUID, error) {
+			got = key
+			return ownerID, nil
+		})
+		req := request(owner, nil, "order-42")
+		req.Resolvers = Resolvers{ResourceTransactionKey: keys}
+
+		denied, err := Evaluate(context.Background(), Owner(ResourceTransactionKey, "key"), req)
+		require.NoError(t, err)
+		assert.Nil(t, denied)
+		assert.Equal(t, "order-42", got)
+	})
+
+	t.Run("UserOwnsOnlyItself", func(t *testing.T) {
+		req := request(owner, nil, ownerID.String())
+		req.Resolvers = Resolvers{ResourceUser: Users()}
+		self := Owner(ResourceUser, "id")
+
+		denied, err := Evaluate(context.Background(), self, req)
+		require.NoError(t, err)
+		assert.Nil(t, denied)
+
+		req.Actor = other
+		denied, err = Evaluate(context.Background(), self, req)
+		require.NoError(t, err)
+		assert.Equal(t, self, denied)
+	})
+}
+
+func TestPolicy_String(t *testing.T) {
+	p := And(Scope("transactions:read"), Or(Owner(ResourceTransaction, "id"), Admin()))
+
+	assert.Equal(t, "and(scope(transactions:read), or(owner(transaction:id), role(admin|superadmin)))", p.String())
+
+	text, err := p.(combinedPolicy).MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, p.String(), string(text))
+}
+
+func TestScopeOnly(t *testing.T) {
+	assert.True(t, ScopeOnly(Scope("wallets:read")))
+	assert.True(t, ScopeOnly(Or(Scope("wallets:read"), Scope("wallets:write"))))
+	assert.False(t, ScopeOnly(Owner(ResourceWallet, "id")))
+	assert.False(t, ScopeOnly(Admin()))
+	assert.False(t, ScopeOnly(Or(Owner(ResourceWallet, "id"), Admin())))
+	assert.False(t, ScopeOnly(And(Scope("wallets:read"), Admin())))
+}
+
+func TestCheck(t *testing.T) {
+	resolvers := Resolvers{ResourceWallet: &countingResolver{}}
+
+	assert.NoError(t, Check(Or(Owner(ResourceWallet, "id"), Admin()), resolvers, []string{"id"}))
+	assert.Error(t, Check(Owner(ResourceTransaction, "id"), resolvers, []string{"id"}))
+	assert.Error(t, Check(Owner(ResourceWallet, "id"), resolvers, []string{"wallet_id"}))
+	assert.Error(t, Check(And(), resolvers, nil))
+	assert.Error(t, Check(Role(), resolvers, nil))
+	assert.Error(t, Check(Scope(""), resolvers, nil))
+}
+
+func TestCached(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	defer clock.SetClock(fake)()
+
+	ownerID, walletID := uuid.New(), uuid.New()
+	next := &countingResolver{owners: map[uuid.UUID]uuid.UUID{walletID: ownerID}}
+	cached := Cached(next, time.Minute)
+	ctx := context.Background()
+
+	for range 3 {
+		owner, err := cached.OwnerOf(ctx, walletID)
+		require.NoError(t, err)
+		assert.Equal(t, ownerID, owner)
+	}
+	assert.Equal(t, 1, next.calls)
+
+	fake.Advance(time.Minute)
+	_, err := cached.OwnerOf(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, next.calls, "expired owner is resolved again")
+
+	// Ошибки не кэшируются
+	unknown := uuid.New()
+	for range 2 {
+		_, err := cached.OwnerOf(ctx, unknown)
+		assert.True(t, errors.IsNotFound(err))
+	}
+	assert.Equal(t, 4, next.calls)
+
+	assert.Same(t, next, Cached(next, 0), "ttl 0 disables the cache")
+}
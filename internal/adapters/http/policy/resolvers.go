@@ -0,0 +1,177 @@
+// Package policy - владельцы ресурсов для Owner.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/pkg/ttlcache"
+)
+
+// Имена ресурсов для Owner.
+const (
+	ResourceUser           = "user"
+	ResourceWallet         = "wallet"
+	ResourceTransaction    = "transaction"
+	ResourceTransactionKey = "transaction_key" // Транзакция по ключу идемпотентности
+)
+
+// DefaultOwnerCacheTTL - сколько живёт закэшированный владелец ресурса.
+// Владелец кошелька и кошелёк транзакции не меняются, TTL лишь ограничивает
+// память под удалённые ресурсы.
+const DefaultOwnerCacheTTL = time.Minute
+
+// maxCachedOwners - сколько владельцев держит кэш одного ресурса (см. ttlcache).
+const maxCachedOwners = 10_000
+
+// Resolver определяет владельца ресурса.
+type Resolver interface {
+	// OwnerOf возвращает ID пользователя-владельца. Ресурс не найден -
+	// ошибка с errors.ErrEntityNotFound.
+	OwnerOf(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+}
+
+// ResolverFunc - Resolver из функции.
+type ResolverFunc func(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+
+// OwnerOf вызывает f.
+func (f ResolverFunc) OwnerOf(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	return f(ctx, id)
+}
+
+// KeyResolver - Resolver ресурса, который адресуется строковым ключом, а не
+// UUID: Owner передаёт ему параметр пути без разбора.
+type KeyResolver interface {
+	Resolver
+	// OwnerOfKey возвращает ID пользователя-владельца ресурса с ключом key.
+	OwnerOfKey(ctx context.Context, key string) (uuid.UUID, error)
+}
+
+// KeyResolverFunc - KeyResolver из функции.
+type KeyResolverFunc func(ctx context.Context, key string) (uuid.UUID, error)
+
+// OwnerOfKey вызывает f.
+func (f KeyResolverFunc) OwnerOfKey(ctx context.Context, key string) (uuid.UUID, error) {
+	return f(ctx, key)
+}
+
+// OwnerOf вызывает f с ключом id.String().
+func (f KeyResolverFunc) OwnerOf(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	return f(ctx, id.String())
+}
+
+// Resolvers - Resolver по имени ресурса.
+type Resolvers map[string]Resolver
+
+// NewResolvers создаёт Resolver'ы пользователей, кошельков и транзакций
+// поверх Query Bus. Владельцы по ID кэшируются на DefaultOwnerCacheTTL,
+// по ключу идемпотентности - нет.
+func NewResolvers(queryBus *cqrs.QueryBus) Resolvers {
+	wallets := Cached(WalletOwners(queryBus), DefaultOwnerCacheTTL)
+	return Resolvers{
+		ResourceUser:           Users(),
+		ResourceWallet:         wallets,
+		ResourceTransaction:    Cached(TransactionOwners(queryBus, wallets), DefaultOwnerCacheTTL),
+		ResourceTransactionKey: TransactionKeyOwners(queryBus, wallets),
+	}
+}
+
+// Users - пользователь владеет только своей записью. Существование не
+// проверяется: несуществующего пользователя вернёт 404 сам handler.
+func Users() Resolver {
+	return ResolverFunc(func(_ context.Context, id uuid.UUID) (uuid.UUID, error) {
+		return id, nil
+	})
+}
+
+// WalletOwners определяет владельца кошелька быстрым путём GET
+// /wallets/{id}/balance: читаются только балансы и владелец.
+func WalletOwners(queryBus *cqrs.QueryBus) Resolver {
+	return ResolverFunc(func(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+		query := dtos.GetWalletBalanceQuery{WalletID: id.String()}
+		balance, err := cqrs.DispatchQuery[dtos.GetWalletBalanceQuery, *dtos.WalletBalanceDTO](queryBus, ctx, query)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return parseOwner(balance.UserID)
+	})
+}
+
+// TransactionOwners определяет владельца транзакции - владельца её кошелька.
+func TransactionOwners(queryBus *cqrs.QueryBus, wallets Resolver) Resolver {
+	return ResolverFunc(func(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+		query := dtos.GetTransactionQuery{TransactionID: id.String()}
+		tx, err := cqrs.DispatchQuery[dtos.GetTransactionQuery, *dtos.TransactionDTO](queryBus, ctx, query)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return transactionWalletOwner(ctx, wallets, tx)
+	})
+}
+
+// TransactionKeyOwners определяет владельца транзакции по ключу
+// идемпотентности - владельца её кошелька.
+func TransactionKeyOwners(queryBus *cqrs.QueryBus, wallets Resolver) KeyResolver {
+	return KeyResolverFunc(func(ctx context.Context, key string) (uuid.UUID, error) {
+		query := dtos.GetTransactionByIdempotencyKeyQuery{IdempotencyKey: key}
+		tx, err := cqrs.DispatchQuery[dtos.GetTransactionByIdempotencyKeyQuery, *dtos.TransactionDTO](queryBus, ctx, query)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return transactionWalletOwner(ctx, wallets, tx)
+	})
+}
+
+// transactionWalletOwner возвращает владельца кошелька транзакции.
+func transactionWalletOwner(ctx context.Context, wallets Resolver, tx *dtos.TransactionDTO) (uuid.UUID, error) {
+	walletID, err := uuid.Parse(tx.WalletID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid transaction wallet ID %q: %w", tx.WalletID, err)
+	}
+	return wallets.OwnerOf(ctx, walletID)
+}
+
+func parseOwner(userID string) (uuid.UUID, error) {
+	owner, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid resource owner ID %q: %w", userID, err)
+	}
+	return owner, nil
+}
+
+// ============================================
+// Cache
+// ============================================
+
+// cachedResolver кэширует найденных владельцев на ttl. Ошибки не кэшируются.
+type cachedResolver struct {
+	next   Resolver
+	owners *ttlcache.Cache[uuid.UUID, uuid.UUID]
+}
+
+// Cached оборачивает Resolver кэшем владельцев. ttl <= 0 - без кэша.
+func Cached(next Resolver, ttl time.Duration) Resolver {
+	if ttl <= 0 {
+		return next
+	}
+	return &cachedResolver{next: next, owners: ttlcache.New[uuid.UUID, uuid.UUID](ttl, maxCachedOwners)}
+}
+
+// OwnerOf возвращает владельца из кэша или из next.
+func (r *cachedResolver) OwnerOf(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	if owner, ok := r.owners.Get(id); ok {
+		return owner, nil
+	}
+
+	owner, err := r.next.OwnerOf(ctx, id)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	r.owners.Set(id, owner)
+	return owner, nil
+}
@@ -18,6 +18,8 @@ import (
 	"github.com/Haleralex/wallethub/internal/adapters/http/common"
 	"github.com/Haleralex/wallethub/internal/adapters/http/handlers"
 	"github.com/Haleralex/wallethub/internal/adapters/http/middleware"
+	"github.com/Haleralex/wallethub/internal/adapters/http/policy"
+	"github.com/Haleralex/wallethub/internal/adapters/http/routes"
 	"github.com/Haleralex/wallethub/internal/application/cqrs"
 	"github.com/Haleralex/wallethub/internal/application/ports"
 	"github.com/gin-gonic/gin"
@@ -58,6 +60,49 @@ type RouterConfig struct {
 	RedisClient *redis.Client
 	// TokenBlacklist - optional token blacklist for logout support.
 	TokenBlacklist ports.TokenBlacklist
+	// DefaultJurisdiction - jurisdiction assigned to users created via Telegram auth
+	DefaultJurisdiction string
+	// SandboxEnabled - регистрирует /sandbox endpoints (никогда в production)
+	SandboxEnabled bool
+	// RouteManifestEnabled - регистрирует GET /api/v1/meta/routes, /api/v1/meta/openapi.json
+	// и /api/v1/meta/state-machines
+	RouteManifestEnabled bool
+	// SecurityLog - optional журнал исходов аутентификации (nil - не пишется)
+	SecurityLog ports.SecurityEventRecorder
+	// LegacyWalletListShape - списки кошельков в прежней форме вместо {data, pagination}
+	LegacyWalletListShape bool
+	// ConfirmationStore - токены подтверждения разрушительных admin операций
+	// (nil - in-memory, токен действует только на этом инстансе)
+	ConfirmationStore ports.ConfirmationStore
+	// FailedRequests - optional запись образцов неудачных денежных операций
+	// (nil - не пишутся)
+	FailedRequests ports.FailedRequestRecorder
+	// FailedRequestMaxBodyBytes - лимит каждого тела в образце (0 - по умолчанию)
+	FailedRequestMaxBodyBytes int
+	// Operations - optional аварийные выключатели операций; выключенные
+	// операции перечисляются в /health и /ready (nil - не перечисляются)
+	Operations ports.OperationGate
+	// Compression - optional сжатие ответов gzip/zstd (nil - без сжатия)
+	Compression *middleware.CompressionConfig
+	// ConcurrencyLimit - optional ограничение одновременных запросов
+	// клиента по классам маршрутов /api/v1 (nil - без ограничения)
+	ConcurrencyLimit *middleware.ConcurrencyLimitConfig
+	// Workers - optional heartbeat фоновых компонентов; устаревшие
+	// перечисляются в /ready как предупреждения (nil - не проверяются)
+	Workers ports.WorkerMonitor
+	// StatusPage - optional данные публичной страницы статуса; регистрирует
+	// GET /status (nil - не регистрируется)
+	StatusPage ports.StatusPage
+	// StatusPageMaxAge - Cache-Control max-age ответа GET /status (0 - без заголовка)
+	StatusPageMaxAge time.Duration
+	// Currencies - политика валют для GET /api/v1/meta/currencies
+	// (nil - все валюты открыты, без лимитов сумм)
+	Currencies ports.CurrencyPolicySource
+	// WebhooksEnabled - регистрирует /api/v1/webhooks (webhooks.enabled)
+	WebhooksEnabled bool
+	// ReadSessions - optional snapshot-сессии чтения для сверки; регистрирует
+	// POST /api/v1/read-sessions и заголовок X-Read-Session (nil - недоступны)
+	ReadSessions ports.ReadSessions
 }
 
 // DefaultRouterConfig - конфигурация по умолчанию для development.
@@ -68,7 +113,8 @@ func DefaultRouterConfig() *RouterConfig {
 		BuildTime:          "unknown",
 		Environment:        "development",
 		AllowedOrigins:     []string{"*"},
-		AuthTokenValidator: middleware.MockTokenValidator,
+		AuthTokenValidator:   middleware.MockTokenValidator,
+		RouteManifestEnabled: true,
 	}
 }
 
@@ -93,6 +139,7 @@ type RouterBuilder struct {
 	commandBus   *cqrs.CommandBus
 	queryBus     *cqrs.QueryBus
 	telegramAuth *TelegramAuthDeps
+	registry     *routes.Registry
 }
 
 // NewRouterBuilder создаёт новый builder.
@@ -118,6 +165,11 @@ func (b *RouterBuilder) WithTelegramAuth(deps *TelegramAuthDeps) *RouterBuilder
 	return b
 }
 
+// Registry возвращает маршруты, зарегистрированные последним Build (nil до Build).
+func (b *RouterBuilder) Registry() *routes.Registry {
+	return b.registry
+}
+
 // Build создаёт сконфигурированный Gin Engine.
 func (b *RouterBuilder) Build() *gin.Engine {
 	// Настраиваем режим Gin
@@ -154,10 +206,22 @@ func (b *RouterBuilder) Build() *gin.Engine {
 		router.Use(middleware.CORS(middleware.DefaultCORSConfig()))
 	}
 
+	// 3a. Compression - до Logging: логи, debug stats и образцы неудачных
+	// запросов видят несжатый ответ
+	if b.config.Compression != nil {
+		router.Use(middleware.Compression(b.config.Compression))
+	}
+
 	// 4. Logging
 	router.Use(middleware.Logging(&middleware.LoggingConfig{
 		Logger:    b.config.Logger,
-		SkipPaths: []string{"/health", "/live", "/ready", "/metrics"},
+		SkipPaths: []string{"/health", "/live", "/ready", "/metrics", "/status"},
+	}))
+
+	// 4a. Debug stats (только с X-Debug-Stats: 1; в production - только admin)
+	router.Use(middleware.DebugStats(&middleware.DebugStatsConfig{
+		Logger:      b.config.Logger,
+		Environment: b.config.Environment,
 	}))
 
 	// 5. Rate Limiting (global) — Redis if available, otherwise in-memory.
@@ -177,7 +241,15 @@ func (b *RouterBuilder) Build() *gin.Engine {
 	// Metrics Endpoint (no auth)
 	// ============================================
 
-	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	// Все маршруты регистрируются через registry - из него строится route manifest
+	registry := routes.NewRegistry()
+	b.registry = registry
+	root := registry.Group(&router.RouterGroup, routes.Meta{
+		Auth:      routes.AuthPublic,
+		RateLimit: routes.RateLimitGlobal,
+	})
+
+	root.GET("/metrics", routes.Meta{ContentType: "text/plain"}, gin.WrapH(promhttp.Handler()))
 
 	// ============================================
 	// Health Check Routes (no auth)
@@ -187,81 +259,248 @@ func (b *RouterBuilder) Build() *gin.Engine {
 		b.config.Pool,
 		b.config.Version,
 		b.config.BuildTime,
-	)
-	healthHandler.RegisterRoutes(router)
+		b.config.Operations,
+	).WithWorkers(b.config.Workers)
+	root.GET("/health", routes.Meta{Response: routes.SchemaRef("HealthResponse")}, healthHandler.Health)
+	root.GET("/health/detailed", routes.Meta{Response: routes.SchemaRef("HealthResponse")}, healthHandler.DetailedHealth)
+	root.GET("/ready", routes.Meta{Response: routes.SchemaRef("ReadinessResponse")}, healthHandler.Ready)
+	root.GET("/live", routes.Meta{Response: routes.SchemaRef("LiveResponse")}, healthHandler.Live)
+
+	// Публичная страница статуса: отвечает из памяти, переживает недоступность БД
+	if b.config.StatusPage != nil {
+		statusHandler := handlers.NewStatusHandler(b.config.StatusPage, b.config.Version, b.config.StatusPageMaxAge)
+		root.GET("/status", routes.Meta{Response: routes.SchemaRef("StatusPageResponse")}, statusHandler.Status)
+	}
 
 	// ============================================
 	// API v1 Routes
 	// ============================================
 
-	v1 := router.Group("/api/v1")
+	v1 := root.Group("/api/v1", routes.Meta{})
+
+	// Concurrency limit - после Auth групп: клиент определяется по
+	// пользователю. Health, метрики и статика не ограничиваются
+	if b.config.ConcurrencyLimit != nil {
+		v1 = v1.WithRouteMiddleware(middleware.NewConcurrencyLimiter(*b.config.ConcurrencyLimit).ForRoute)
+	}
 
 	// Public routes (no auth required)
-	publicGroup := v1.Group("")
+	publicGroup := v1.Group("", routes.Meta{})
+
+	// Реестр валют: публичный, клиенты кэшируют его по ETag
+	currencies := b.config.Currencies
+	if currencies == nil {
+		currencies = ports.CurrencyPolicy{}
+	}
+	currencyHandler := handlers.NewCurrencyHandler(currencies)
+	publicGroup.GET("/meta/currencies", routes.Meta{Response: routes.SchemaRef("CurrencyListResponse")}, currencyHandler.Currencies)
 	{
 		// User registration (public)
 		if b.commandBus != nil {
 			userHandler := handlers.NewUserHandler(b.commandBus, b.queryBus)
-			publicGroup.POST("/users", userHandler.CreateUser)
+			publicGroup.POST("/users", routes.Meta{
+				Idempotency: routes.IdempotencyNone,
+				Request:     routes.SchemaRef("CreateUserRequest"),
+				Response:    routes.SchemaRef("UserCreatedResponse"),
+				Status:      http.StatusCreated,
+			}, userHandler.CreateUser)
 		}
 
 		// Telegram Mini App authentication (public)
 		if b.telegramAuth != nil {
 			tgHandler := handlers.NewTelegramAuthHandler(handlers.TelegramAuthConfig{
-				UserRepo:    b.telegramAuth.UserRepo,
-				WalletRepo:  b.telegramAuth.WalletRepo,
-				BotToken:    b.config.TelegramBotToken,
-				JWTSecret:   b.config.JWTSecret,
-				JWTIssuer:   b.config.JWTIssuer,
-				TokenExpiry: 15 * time.Minute,
-				Blacklist:   b.config.TokenBlacklist,
+				UserRepo:            b.telegramAuth.UserRepo,
+				WalletRepo:          b.telegramAuth.WalletRepo,
+				BotToken:            b.config.TelegramBotToken,
+				JWTSecret:           b.config.JWTSecret,
+				JWTIssuer:           b.config.JWTIssuer,
+				TokenExpiry:         15 * time.Minute,
+				Blacklist:           b.config.TokenBlacklist,
+				DefaultJurisdiction: b.config.DefaultJurisdiction,
 			})
-			publicGroup.POST("/auth/telegram", tgHandler.Authenticate)
+			publicGroup.POST("/auth/telegram", routes.Meta{
+				Idempotency: routes.IdempotencyNone,
+				Request:     routes.SchemaRef("TelegramAuthRequest"),
+				Response:    routes.SchemaRef("TelegramAuthResponse"),
+			}, tgHandler.Authenticate)
 
 			// Logout requires auth (token must be valid to be revoked)
-			logoutGroup := v1.Group("")
-			logoutGroup.Use(middleware.Auth(&middleware.AuthConfig{
+			logoutGroup := v1.Group("", routes.Meta{Auth: routes.AuthUser}, middleware.Auth(&middleware.AuthConfig{
 				TokenValidator: b.config.AuthTokenValidator,
+				SecurityLog:    b.config.SecurityLog,
 			}))
-			logoutGroup.POST("/auth/logout", tgHandler.Logout)
+			logoutGroup.POST("/auth/logout", routes.Meta{
+				Idempotency: routes.IdempotencyNone,
+				Response:    routes.SchemaRef("LogoutResponse"),
+			}, tgHandler.Logout)
 		}
 	}
 
 	// Protected routes (auth required)
-	protectedGroup := v1.Group("")
-	protectedGroup.Use(middleware.Auth(&middleware.AuthConfig{
+	protectedGroup := v1.Group("", routes.Meta{Auth: routes.AuthUser}, middleware.Auth(&middleware.AuthConfig{
 		TokenValidator: b.config.AuthTokenValidator,
 		SkipPaths:      []string{}, // Auth обязательна
+		SecurityLog:    b.config.SecurityLog,
 	}))
+	// Политики доступа маршрутов (routes.Meta.Policy): владелец ресурса,
+	// роль, scope API ключа - до handlers и до snapshot сессии
+	protectedGroup = protectedGroup.WithRouteMiddleware(middleware.AuthorizeForRoute(policy.NewResolvers(b.queryBus)))
+	if b.config.ReadSessions != nil {
+		// Запросы с X-Read-Session: чтения из snapshot сессии, мутации отклоняются
+		protectedGroup = protectedGroup.WithRouteMiddleware(middleware.ReadSessionForRoute(b.config.ReadSessions))
+	}
 	{
+		// Неудачные мутирующие операции с кошельками и транзакциями
+		// сохраняются (очищенными) для разбора обращений поддержкой
+		captureFailed := middleware.CaptureFailedRequests(&middleware.FailedRequestCaptureConfig{
+			Recorder:     b.config.FailedRequests,
+			MaxBodyBytes: b.config.FailedRequestMaxBodyBytes,
+		})
+
 		// User routes
 		if b.commandBus != nil {
 			userHandler := handlers.NewUserHandler(b.commandBus, b.queryBus)
-			users := protectedGroup.Group("/users")
+			users := protectedGroup.Group("/users", routes.Meta{})
 			{
-				users.GET("/:id", userHandler.GetUser)
+				// Профиль и согласие с условиями - только сам пользователь,
+				// историю KYC видит и администратор
+				self := policy.Owner(policy.ResourceUser, "id")
+				selfOrAdmin := policy.Or(self, policy.Admin())
+
+				users.GET("/:id", routes.Meta{Response: routes.SchemaRef("UserResponse"), Policy: self}, userHandler.GetUser)
+				users.PATCH("/:id", routes.Meta{
+					Idempotency: routes.IdempotencyNone,
+					Request:     routes.SchemaRef("UpdateUserRequest"),
+					Response:    routes.SchemaRef("UserResponse"),
+					Policy:      self,
+				}, userHandler.UpdateUser)
+				users.POST("/:id/kyc", routes.Meta{
+					Auth:        routes.AuthAdmin,
+					Idempotency: routes.IdempotencyNone,
+					Request:     routes.SchemaRef("ApproveKYCRequest"),
+					Response:    routes.SchemaRef("UserResponse"),
+				}, middleware.RequireRole("admin"), userHandler.ReviewKYC)
+				users.POST("/:id/kyc/revoke", routes.Meta{
+					Auth:        routes.AuthAdmin,
+					Idempotency: routes.IdempotencyNone,
+					Request:     routes.SchemaRef("RevokeKYCRequest"),
+					Response:    routes.SchemaRef("UserResponse"),
+				}, middleware.RequireRole("admin"), userHandler.RevokeKYC)
+				users.GET("/:id/kyc/history", routes.Meta{Response: routes.SchemaRef("KYCHistoryResponse"), Policy: selfOrAdmin}, userHandler.GetKYCHistory)
+				users.POST("/:id/accept-terms", routes.Meta{
+					Idempotency: routes.IdempotencyNone,
+					Request:     routes.SchemaRef("AcceptTermsRequest"),
+					Response:    routes.SchemaRef("UserResponse"),
+					Policy:      self,
+				}, userHandler.AcceptTerms)
 			}
+
+			// Партнёрский onboarding: пользователь, кошельки и зачисления атомарно
+			protectedGroup.POST("/onboarding", routes.Meta{
+				Idempotency: routes.IdempotencyKey,
+				Request:     routes.SchemaRef("OnboardUserRequest"),
+				Response:    routes.SchemaRef("OnboardingResponse"),
+				Status:      http.StatusCreated,
+			}, captureFailed, userHandler.OnboardUser)
 		}
 
 		// Wallet routes
 		if b.commandBus != nil {
-			walletHandler := handlers.NewWalletHandler(b.commandBus, b.queryBus)
-			wallets := protectedGroup.Group("/wallets")
+			walletHandler := handlers.NewWalletHandler(b.commandBus, b.queryBus).
+				WithLegacyListShape(b.config.LegacyWalletListShape)
+			wallets := protectedGroup.Group("/wallets", routes.Meta{}, captureFailed)
 			{
-				wallets.POST("", walletHandler.CreateWallet)
-				wallets.GET("", walletHandler.ListWallets)
-				wallets.GET("/me", walletHandler.GetMyWallets)
-				wallets.POST("/me", walletHandler.GetMyWallets) // POST duplicate for ngrok compatibility
-				wallets.GET("/:id", walletHandler.GetWallet)
+				walletList := routes.Meta{Response: routes.SchemaRef("WalletListResponse")}
+				// Кошелёк читает владелец или администратор; API ключу нужен scope wallets:read
+				walletsRead := policy.Scope("wallets:read")
+				walletOwner := policy.And(walletsRead, policy.Or(policy.Owner(policy.ResourceWallet, "id"), policy.Admin()))
+				// Операции с кошельком - только владелец
+				walletOwnerOnly := policy.Owner(policy.ResourceWallet, "id")
+				myWallets := routes.Meta{Response: walletList.Response, Policy: walletsRead}
+
+				wallets.POST("", routes.Meta{
+					Idempotency: routes.IdempotencyNone,
+					Request:     routes.SchemaRef("CreateWalletRequest"),
+					Response:    routes.SchemaRef("WalletResponse"),
+					Status:      http.StatusCreated,
+				}, walletHandler.CreateWallet)
+				wallets.GET("", walletList, walletHandler.ListWallets)
+				wallets.GET("/me", myWallets, walletHandler.GetMyWallets)
+				wallets.POST("/me", routes.Meta{ // POST duplicate for ngrok compatibility
+					Idempotency: routes.IdempotencySafe,
+					Response:    myWallets.Response,
+					Policy:      myWallets.Policy,
+				}, walletHandler.GetMyWallets)
+				wallets.GET("/:id", routes.Meta{Response: routes.SchemaRef("WalletResponse"), Policy: walletOwner}, walletHandler.GetWallet)
+				wallets.HEAD("/:id", routes.Meta{Policy: walletOwner}, walletHandler.GetWallet)
+				wallets.GET("/:id/balance", routes.Meta{Response: routes.SchemaRef("WalletBalanceResponse"), Policy: walletOwner}, walletHandler.GetWalletBalance)
+				wallets.HEAD("/:id/balance", routes.Meta{Policy: walletOwner}, walletHandler.GetWalletBalance)
+				wallets.GET("/:id/activity/heatmap", routes.Meta{
+					Response:    routes.SchemaRef("ActivityHeatmapResponse"),
+					Concurrency: routes.ConcurrencyReport,
+					Policy:      walletOwner,
+				}, walletHandler.GetActivityHeatmap)
+				wallets.PUT("/:id/settings", routes.Meta{
+					Idempotency: routes.IdempotencyNone,
+					Request:     routes.SchemaRef("UpdateWalletSettingsRequest"),
+					Response:    routes.SchemaRef("WalletSettingsResponse"),
+					Policy:      walletOwnerOnly,
+				}, walletHandler.UpdateSettings)
+
+				// Nested route: /users/:id/wallets/:currency - владелец или администратор
+				protectedGroup.PUT("/users/:id/wallets/:currency", routes.Meta{
+					Idempotency: routes.IdempotencyNone,
+					Request:     routes.SchemaRef("EnsureWalletRequest"),
+					Response:    routes.SchemaRef("EnsureWalletResponse"),
+					Policy:      policy.Or(policy.Owner(policy.ResourceUser, "id"), policy.Admin()),
+				}, walletHandler.EnsureWallet)
+
+				// Nested route: /users/me/activity/heatmap - по всем кошелькам пользователя
+				protectedGroup.GET("/users/me/activity/heatmap", routes.Meta{
+					Response:    routes.SchemaRef("ActivityHeatmapResponse"),
+					Concurrency: routes.ConcurrencyReport,
+				}, walletHandler.GetMyActivityHeatmap)
 
 				// Financial operations with stricter rate limiting
-				financialOps := wallets.Group("")
-				financialOps.Use(middleware.TransactionRateLimit())
+				financialOps := wallets.Group("", routes.Meta{
+					Idempotency: routes.IdempotencyKey,
+					RateLimit:   routes.RateLimitFinancial,
+				}, middleware.TransactionRateLimit())
 				{
-					financialOps.POST("/:id/credit", walletHandler.CreditWallet)
-					financialOps.POST("/:id/debit", walletHandler.DebitWallet)
-					financialOps.POST("/:id/transfer", walletHandler.Transfer)
-					financialOps.POST("/:id/exchange", walletHandler.ExchangeCurrency)
+					financialOps.POST("/:id/credit", routes.Meta{
+						Request:  routes.SchemaRef("CreditWalletRequest"),
+						Response: routes.SchemaRef("WalletOperationResponse"),
+						Status:   http.StatusCreated,
+						Policy:   walletOwnerOnly,
+					}, walletHandler.CreditWallet)
+					financialOps.POST("/:id/debit", routes.Meta{
+						Request:  routes.SchemaRef("DebitWalletRequest"),
+						Response: routes.SchemaRef("WalletOperationResponse"),
+						Status:   http.StatusCreated,
+						Policy:   walletOwnerOnly,
+					}, walletHandler.DebitWallet)
+					financialOps.POST("/:id/transfer", routes.Meta{
+						Request:  routes.SchemaRef("TransferFundsRequest"),
+						Response: routes.SchemaRef("TransferResultResponse"),
+						Status:   http.StatusCreated,
+						Policy:   walletOwnerOnly,
+					}, walletHandler.Transfer)
+					financialOps.POST("/:id/transfers/bulk", routes.Meta{
+						Idempotency: routes.IdempotencyKey,
+						Request:     routes.SchemaRef("BulkTransferRequest"),
+						Response:    routes.SchemaRef("BulkTransferResponse"),
+						Policy:      walletOwnerOnly,
+					}, walletHandler.BulkTransfer)
+					financialOps.POST("/:id/exchange", routes.Meta{
+						Request:  routes.SchemaRef("ExchangeCurrencyRequest"),
+						Response: routes.SchemaRef("ExchangeResponse"),
+						Policy:   walletOwnerOnly,
+					}, walletHandler.ExchangeCurrency)
+					financialOps.POST("/:id/close", routes.Meta{
+						Idempotency: routes.IdempotencyNone,
+						Response:    routes.SchemaRef("CloseWalletResponse"),
+						Policy:      walletOwnerOnly,
+					}, walletHandler.CloseWallet)
 				}
 			}
 		}
@@ -269,32 +508,233 @@ func (b *RouterBuilder) Build() *gin.Engine {
 		// Transaction routes
 		if b.commandBus != nil {
 			txHandler := handlers.NewTransactionHandler(b.commandBus, b.queryBus)
-			transactions := protectedGroup.Group("/transactions")
+			transactions := protectedGroup.Group("/transactions", routes.Meta{}, captureFailed)
+			{
+				// Список с фильтрами и CSV выгрузка - тяжёлые выборки
+				transactionList := routes.Meta{
+					Response:    routes.SchemaRef("TransactionListResponse"),
+					Concurrency: routes.ConcurrencyReport,
+				}
+				transaction := routes.Meta{Response: routes.SchemaRef("TransactionResponse")}
+				// Транзакцию читает владелец её кошелька или администратор
+				transactionOwner := policy.And(policy.Scope("transactions:read"),
+					policy.Or(policy.Owner(policy.ResourceTransaction, "id"), policy.Admin()))
+				transactionKeyOwner := policy.And(policy.Scope("transactions:read"),
+					policy.Or(policy.Owner(policy.ResourceTransactionKey, "key"), policy.Admin()))
+				// Транзакции кошелька читает его владелец или администратор
+				walletOwner := policy.And(policy.Scope("transactions:read"),
+					policy.Or(policy.Owner(policy.ResourceWallet, "id"), policy.Admin()))
+
+				transactions.GET("", transactionList, txHandler.ListTransactions)
+				transactions.GET("/:id", routes.Meta{Response: transaction.Response, Policy: transactionOwner}, txHandler.GetTransaction)
+				transactions.HEAD("/:id", routes.Meta{Policy: transactionOwner}, txHandler.GetTransaction)
+				transactions.GET("/by-key/:key", routes.Meta{Response: transaction.Response, Policy: transactionKeyOwner}, txHandler.GetTransactionByIdempotencyKey)
+				transactions.POST("/:id/retry", routes.Meta{
+					Idempotency: routes.IdempotencyNone,
+					Response:    transaction.Response,
+					Policy:      transactionOwner,
+				}, txHandler.RetryTransaction)
+				transactions.POST("/:id/cancel", routes.Meta{
+					Idempotency: routes.IdempotencyNone,
+					Request:     routes.SchemaRef("CancelTransactionRequest"),
+					Response:    transaction.Response,
+					Policy:      transactionOwner,
+				}, txHandler.CancelTransaction)
+
+				// Nested route: /wallets/:id/transactions
+				protectedGroup.GET("/wallets/:id/transactions", routes.Meta{
+					Response:    transactionList.Response,
+					Concurrency: transactionList.Concurrency,
+					Policy:      walletOwner,
+				}, txHandler.GetWalletTransactions)
+				protectedGroup.POST("/wallets/:id/transactions", routes.Meta{ // POST duplicate for ngrok compatibility
+					Idempotency: routes.IdempotencySafe,
+					Response:    transactionList.Response,
+					Concurrency: transactionList.Concurrency,
+					Policy:      walletOwner,
+				}, txHandler.GetWalletTransactions)
+
+				// Чек по номеру: номер читают вслух в поддержку, поэтому
+				// он не UUID и живёт отдельно от /transactions/:id. Политики
+				// нет: владельца кошельков чека проверяет GetReceiptUseCase
+				protectedGroup.GET("/receipts/:number", routes.Meta{Response: routes.SchemaRef("ReceiptResponse")}, txHandler.GetReceipt)
+			}
+		}
+
+		// Webhook subscriptions текущего пользователя
+		if b.commandBus != nil && b.config.WebhooksEnabled {
+			webhookHandler := handlers.NewWebhookHandler(b.commandBus, b.queryBus)
+			webhooks := protectedGroup.Group("/webhooks", routes.Meta{})
 			{
-				transactions.GET("", txHandler.ListTransactions)
-				transactions.GET("/:id", txHandler.GetTransaction)
-				transactions.GET("/by-key/:key", txHandler.GetTransactionByIdempotencyKey)
-				transactions.POST("/:id/retry", txHandler.RetryTransaction)
-				transactions.POST("/:id/cancel", txHandler.CancelTransaction)
+				webhook := routes.Meta{Response: routes.SchemaRef("WebhookSubscriptionResponse")}
+
+				webhooks.POST("", routes.Meta{
+					Idempotency: routes.IdempotencyNone,
+					Request:     routes.SchemaRef("CreateWebhookRequest"),
+					Response:    routes.SchemaRef("WebhookSubscriptionCreatedResponse"),
+					Status:      http.StatusCreated,
+				}, webhookHandler.CreateWebhook)
+				webhooks.GET("", routes.Meta{Response: routes.SchemaRef("WebhookSubscriptionListResponse")}, webhookHandler.ListWebhooks)
+				webhooks.GET("/:id", webhook, webhookHandler.GetWebhook)
+				webhooks.PATCH("/:id", routes.Meta{
+					Idempotency: routes.IdempotencyNone,
+					Request:     routes.SchemaRef("UpdateWebhookRequest"),
+					Response:    webhook.Response,
+				}, webhookHandler.UpdateWebhook)
+				webhooks.DELETE("/:id", routes.Meta{
+					Idempotency: routes.IdempotencyNone,
+					Status:      http.StatusNoContent,
+				}, webhookHandler.DeleteWebhook)
+				webhooks.GET("/:id/deliveries", routes.Meta{Response: routes.SchemaRef("WebhookDeliveryListResponse")}, webhookHandler.ListWebhookDeliveries)
 			}
+		}
 
-			// Nested route: /wallets/:id/transactions
-			protectedGroup.GET("/wallets/:id/transactions", txHandler.GetWalletTransactions)
-			protectedGroup.POST("/wallets/:id/transactions", txHandler.GetWalletTransactions) // POST duplicate for ngrok compatibility
+		// Snapshot-сессии чтения для сверки (только postgres)
+		if b.commandBus != nil && b.config.ReadSessions != nil {
+			readSessionHandler := handlers.NewReadSessionHandler(b.commandBus)
+			protectedGroup.POST("/read-sessions", routes.Meta{
+				Idempotency: routes.IdempotencyNone,
+				Response:    routes.SchemaRef("ReadSessionResponse"),
+				Status:      http.StatusCreated,
+			}, readSessionHandler.OpenReadSession)
+		}
+
+		// Sandbox routes (только sandbox режим вне production)
+		if b.commandBus != nil && b.config.SandboxEnabled && b.config.Environment != "production" {
+			sandboxHandler := handlers.NewSandboxHandler(b.commandBus)
+			protectedGroup.POST("/sandbox/reset", routes.Meta{
+				Idempotency: routes.IdempotencyNone,
+				Request:     routes.SchemaRef("ResetSandboxRequest"),
+				Response:    routes.SchemaRef("SandboxResetResponse"),
+			}, sandboxHandler.Reset)
 		}
 	}
 
 	// ============================================
-	// Admin Routes (admin role required)
+	// Admin Routes (admin or superadmin role required)
 	// ============================================
 
-	adminGroup := v1.Group("/admin")
-	adminGroup.Use(middleware.Auth(&middleware.AuthConfig{
-		TokenValidator: b.config.AuthTokenValidator,
-	}))
-	adminGroup.Use(middleware.RequireRole("admin"))
+	adminGroup := v1.Group("/admin", routes.Meta{Auth: routes.AuthAdmin},
+		middleware.Auth(&middleware.AuthConfig{
+			TokenValidator: b.config.AuthTokenValidator,
+			SecurityLog:    b.config.SecurityLog,
+		}),
+		middleware.RequireRole("admin", "superadmin"),
+	)
 	{
-		// Admin-only endpoints можно добавить здесь
+		if b.queryBus != nil {
+			securityHandler := handlers.NewSecurityHandler(b.queryBus)
+			adminGroup.GET("/security-events", routes.Meta{
+				Response: routes.SchemaRef("SecurityEventListResponse"),
+			}, securityHandler.ListSecurityEvents)
+
+			adminUserHandler := handlers.NewUserHandler(b.commandBus, b.queryBus)
+			adminGroup.GET("/users/:id", routes.Meta{
+				Response: routes.SchemaRef("AdminUserResponse"),
+			}, adminUserHandler.AdminGetUser)
+
+			supportHandler := handlers.NewSupportHandler(b.queryBus)
+			adminGroup.GET("/failed-requests", routes.Meta{
+				Response: routes.SchemaRef("FailedRequestListResponse"),
+			}, supportHandler.ListFailedRequests)
+
+			screeningHandler := handlers.NewScreeningHandler(b.queryBus)
+			workersHandler := handlers.NewWorkersHandler(b.queryBus)
+			adminGroup.GET("/workers", routes.Meta{
+				Response: routes.SchemaRef("WorkerListResponse"),
+			}, workersHandler.ListWorkers)
+
+			adminGroup.GET("/screening-rules", routes.Meta{
+				Response: routes.SchemaRef("ScreeningRuleListResponse"),
+			}, screeningHandler.ListScreeningRules)
+			adminGroup.POST("/screening-rules/dry-run", routes.Meta{
+				Idempotency: routes.IdempotencyNone,
+				Request:     routes.SchemaRef("DryRunScreeningRequest"),
+				Response:    routes.SchemaRef("ScreeningDryRunResponse"),
+			}, screeningHandler.DryRunScreening)
+
+			// Воспроизведение истории кошелька: только чтение, но вся история
+			adminWalletHandler := handlers.NewWalletHandler(b.commandBus, b.queryBus)
+			adminGroup.GET("/wallets/:id/replay", routes.Meta{
+				Response:    routes.SchemaRef("WalletReplayResponse"),
+				Concurrency: routes.ConcurrencyReport,
+			}, adminWalletHandler.AdminReplayWallet)
+		}
+
+		if b.commandBus != nil && b.queryBus != nil {
+			// Разрушительные операции выполняются только повтором запроса
+			// с токеном X-Confirm-Operation (защита от случайного клика в консоли)
+			confirmationStore := b.config.ConfirmationStore
+			if confirmationStore == nil {
+				confirmationStore = middleware.NewMemoryConfirmationStore()
+			}
+			confirm := middleware.RequireConfirmation(&middleware.ConfirmationConfig{
+				Store:  confirmationStore,
+				Logger: b.config.Logger,
+			})
+
+			jobsHandler := handlers.NewJobsHandler(b.commandBus, b.queryBus)
+			adminGroup.GET("/jobs", routes.Meta{
+				Response: routes.SchemaRef("JobListResponse"),
+			}, jobsHandler.ListJobs)
+			adminGroup.POST("/jobs/:name/run", routes.Meta{
+				Idempotency:  routes.IdempotencyNone,
+				Response:     routes.SchemaRef("JobRunResponse"),
+				Status:       http.StatusAccepted,
+				Confirmation: true,
+			}, confirm, jobsHandler.RunJob)
+
+			txHandler := handlers.NewTransactionHandler(b.commandBus, b.queryBus)
+			adminGroup.POST("/transactions/:id/retry", routes.Meta{
+				Idempotency:  routes.IdempotencyNone,
+				Response:     routes.SchemaRef("TransactionResponse"),
+				Confirmation: true,
+			}, confirm, txHandler.AdminRetryTransaction)
+			adminGroup.GET("/transactions/failure-stats", routes.Meta{
+				Response:    routes.SchemaRef("TransactionFailureStatsResponse"),
+				Concurrency: routes.ConcurrencyReport,
+			}, txHandler.AdminFailureStats)
+
+			noteHandler := handlers.NewWalletNoteHandler(b.commandBus, b.queryBus)
+			adminGroup.GET("/wallets/:id/notes", routes.Meta{
+				Response: routes.SchemaRef("WalletNoteListResponse"),
+			}, noteHandler.ListWalletNotes)
+			adminGroup.POST("/wallets/:id/notes", routes.Meta{
+				Idempotency: routes.IdempotencyNone,
+				Request:     routes.SchemaRef("CreateWalletNoteRequest"),
+				Response:    routes.SchemaRef("WalletNoteResponse"),
+				Status:      http.StatusCreated,
+			}, noteHandler.CreateWalletNote)
+			adminGroup.PATCH("/wallets/:id/notes/:note_id", routes.Meta{
+				Idempotency: routes.IdempotencyNone,
+				Request:     routes.SchemaRef("UpdateWalletNoteRequest"),
+				Response:    routes.SchemaRef("WalletNoteResponse"),
+			}, noteHandler.UpdateWalletNote)
+			adminGroup.DELETE("/wallets/:id/notes/:note_id", routes.Meta{
+				Idempotency:  routes.IdempotencyNone,
+				Status:       http.StatusNoContent,
+				Confirmation: true,
+			}, confirm, noteHandler.DeleteWalletNote)
+
+			operationsHandler := handlers.NewOperationsHandler(b.commandBus)
+			adminGroup.PUT("/switches/:type", routes.Meta{
+				Idempotency: routes.IdempotencyNone,
+				Request:     routes.SchemaRef("SetOperationSwitchRequest"),
+				Response:    routes.SchemaRef("OperationSwitchResponse"),
+			}, operationsHandler.SetOperationSwitch)
+
+			incidentsHandler := handlers.NewIncidentsHandler(b.commandBus)
+			adminGroup.POST("/incidents", routes.Meta{
+				Idempotency: routes.IdempotencyNone,
+				Request:     routes.SchemaRef("CreateIncidentRequest"),
+				Response:    routes.SchemaRef("IncidentResponse"),
+				Status:      http.StatusCreated,
+			}, incidentsHandler.CreateIncident)
+			adminGroup.POST("/incidents/:id/resolve", routes.Meta{
+				Idempotency: routes.IdempotencyNone,
+				Response:    routes.SchemaRef("IncidentResponse"),
+			}, incidentsHandler.ResolveIncident)
+		}
 	}
 
 	// ============================================
@@ -323,8 +763,22 @@ func (b *RouterBuilder) Build() *gin.Engine {
 		c.Header("Expires", "0")
 		c.File(fullPath)
 	}
-	router.GET("/app/*filepath", serveWebapp)
-	router.GET("/m/*filepath", serveWebapp) // alternate path to bust WebView cache
+	webapp := routes.Meta{ContentType: "text/html"}
+	root.GET("/app/*filepath", webapp, serveWebapp)
+	root.GET("/m/*filepath", webapp, serveWebapp) // alternate path to bust WebView cache
+
+	// ============================================
+	// Route Manifest (для генераторов SDK)
+	// ============================================
+
+	// Регистрируется последним, но сам тоже попадает в manifest -
+	// registry читается в момент запроса
+	if b.config.RouteManifestEnabled {
+		metaHandler := handlers.NewMetaHandler(registry, b.config.Version)
+		v1.GET("/meta/routes", routes.Meta{Response: routes.SchemaRef("RouteManifestResponse")}, metaHandler.Routes)
+		v1.GET("/meta/openapi.json", routes.Meta{ContentType: "application/json"}, metaHandler.OpenAPI)
+		v1.GET("/meta/state-machines", routes.Meta{Response: routes.SchemaRef("StateMachinesResponse")}, metaHandler.StateMachines)
+	}
 
 	// ============================================
 	// 404 Handler
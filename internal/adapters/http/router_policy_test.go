@@ -0,0 +1,281 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/middleware"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/transaction"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// policyTestTokens - токен "<role>|<user id>" или "<role>|<user id>|<scopes>":
+// третья часть (даже пустая) делает токен API ключом со scopes через запятую.
+func policyTestTokens(token string) (*middleware.AuthClaims, error) {
+	parts := strings.Split(token, "|")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid test token")
+	}
+	claims := &middleware.AuthClaims{UserID: parts[1], Role: parts[0], Exp: time.Now().Add(time.Hour)}
+	if len(parts) == 3 {
+		claims.Scopes = []string{}
+		if parts[2] != "" {
+			claims.Scopes = strings.Split(parts[2], ",")
+		}
+	}
+	return claims, nil
+}
+
+// setupPolicyRouter собирает роутер с чтениями кошельков и транзакций
+// поверх in-memory хранилища с одним кошельком и одной транзакцией;
+// txKey - ключ идемпотентности транзакции.
+func setupPolicyRouter(t *testing.T) (router http.Handler, ownerID, walletID, txID uuid.UUID, txKey string) {
+	t.Helper()
+
+	ctx := context.Background()
+	store := memory.NewStore()
+	wallets := memory.NewWalletRepository(store)
+	transactions := memory.NewTransactionRepository(store)
+
+	owner, err := entities.NewUser("owner@example.com", "Wallet Owner")
+	require.NoError(t, err)
+	require.NoError(t, memory.NewUserRepository(store).Save(ctx, owner))
+	usd := valueobjects.MustNewCurrency("USD")
+	w, err := entities.NewWallet(owner.ID(), usd)
+	require.NoError(t, err)
+	require.NoError(t, wallets.Save(ctx, w))
+	amount, err := valueobjects.NewMoney("10.00", usd)
+	require.NoError(t, err)
+	txKey = uuid.NewString()
+	tx, err := entities.NewTransaction(w.ID(), txKey, entities.TransactionTypeDeposit, amount, "deposit")
+	require.NoError(t, err)
+	require.NoError(t, transactions.Save(ctx, tx))
+
+	qBus := cqrs.NewQueryBus()
+	cqrs.RegisterQueryHandler[dtos.GetWalletQuery, *dtos.WalletDTO](qBus,
+		wallet.NewGetWalletUseCase(wallets, transactions, ports.PendingTransactionsPolicy{}, ports.MetadataQuota{}, ports.DisplayCurrency{}))
+	cqrs.RegisterQueryHandler[dtos.GetWalletBalanceQuery, *dtos.WalletBalanceDTO](qBus, wallet.NewGetWalletBalanceUseCase(wallets, ports.DisplayCurrency{}))
+	cqrs.RegisterQueryHandler[dtos.ListWalletsQuery, *dtos.WalletListDTO](qBus, wallet.NewListWalletsUseCase(wallets, ports.DisplayCurrency{}))
+	cqrs.RegisterQueryHandler[dtos.GetTransactionQuery, *dtos.TransactionDTO](qBus, transaction.NewGetTransactionUseCase(transactions))
+	cqrs.RegisterQueryHandler[dtos.GetTransactionByIdempotencyKeyQuery, *dtos.TransactionDTO](qBus,
+		transaction.NewGetTransactionByIdempotencyKeyUseCase(transactions))
+
+	cfg := DefaultRouterConfig()
+	cfg.AuthTokenValidator = policyTestTokens
+	router = NewRouterBuilder(cfg).WithCQRS(cqrs.NewCommandBus(), qBus).Build()
+	return router, owner.ID(), w.ID(), tx.ID(), txKey
+}
+
+func policyRequest(router http.Handler, token, path string, header ...string) *httptest.ResponseRecorder {
+	return policyRequestMethod(router, http.MethodGet, token, path, header...)
+}
+
+func policyRequestMethod(router http.Handler, method, token, path string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// policyError - error из ответа с ошибкой.
+type policyError struct {
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details"`
+}
+
+func decodePolicyError(t *testing.T, w *httptest.ResponseRecorder) policyError {
+	t.Helper()
+
+	var response struct {
+		Error policyError `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response.Error
+}
+
+func TestRouter_PolicyMatrix(t *testing.T) {
+	router, ownerID, walletID, txID, txKey := setupPolicyRouter(t)
+	otherID, adminID := uuid.New(), uuid.New()
+
+	actors := map[string]string{
+		"owner":             "user|" + ownerID.String(),
+		"other user":        "user|" + otherID.String(),
+		"admin":             "admin|" + adminID.String(),
+		"key with scope":    "user|" + ownerID.String() + "|wallets:read,transactions:read",
+		"key without scope": "user|" + ownerID.String() + "|webhooks:write",
+	}
+
+	allowed, denied := http.StatusOK, http.StatusForbidden
+	tests := []struct {
+		path string
+		want map[string]int
+	}{
+		{
+			path: "/api/v1/wallets/me",
+			want: map[string]int{"owner": allowed, "other user": allowed, "admin": allowed, "key with scope": allowed, "key without scope": denied},
+		},
+		{
+			path: "/api/v1/wallets/" + walletID.String(),
+			want: map[string]int{"owner": allowed, "other user": denied, "admin": allowed, "key with scope": allowed, "key without scope": denied},
+		},
+		{
+			path: "/api/v1/wallets/" + walletID.String() + "/balance",
+			want: map[string]int{"owner": allowed, "other user": denied, "admin": allowed, "key with scope": allowed, "key without scope": denied},
+		},
+		{
+			path: "/api/v1/transactions/" + txID.String(),
+			want: map[string]int{"owner": allowed, "other user": denied, "admin": allowed, "key with scope": allowed, "key without scope": denied},
+		},
+		{
+			path: "/api/v1/transactions/by-key/" + txKey,
+			want: map[string]int{"owner": allowed, "other user": denied, "admin": allowed, "key with scope": allowed, "key without scope": denied},
+		},
+	}
+
+	for _, tt := range tests {
+		for actor, want := range tt.want {
+			t.Run(tt.path+"/"+actor, func(t *testing.T) {
+				w := policyRequest(router, actors[actor], tt.path)
+
+				require.Equal(t, want, w.Code, w.Body.String())
+				if want == denied {
+					assert.Equal(t, "POLICY_DENIED", decodePolicyError(t, w).Code)
+				}
+			})
+		}
+	}
+}
+
+func TestRouter_PolicyDenied(t *testing.T) {
+	router, ownerID, walletID, _, _ := setupPolicyRouter(t)
+	walletPath := "/api/v1/wallets/" + walletID.String()
+
+	t.Run("UserGetsGenericMessage", func(t *testing.T) {
+		w := policyRequest(router, "user|"+uuid.NewString(), walletPath)
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+		apiErr := decodePolicyError(t, w)
+		assert.Equal(t, "You do not have access to this resource", apiErr.Message)
+		assert.Empty(t, apiErr.Details, "the failed policy is not disclosed to end users")
+	})
+
+	t.Run("AdminSeesFailedPolicy", func(t *testing.T) {
+		w := policyRequest(router, "admin|"+uuid.NewString()+"|webhooks:write", walletPath)
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+		apiErr := decodePolicyError(t, w)
+		assert.Equal(t, "scope(wallets:read)", apiErr.Details["policy"])
+		assert.Equal(t, "and(scope(wallets:read), or(owner(wallet:id), role(admin|superadmin)))", apiErr.Details["route_policy"])
+		assert.Contains(t, apiErr.Message, "scope(wallets:read)")
+	})
+
+	t.Run("NotModifiedStillChecksOwnership", func(t *testing.T) {
+		w := policyRequest(router, "user|"+uuid.NewString(), walletPath, "If-None-Match", "*")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+	})
+
+	t.Run("InvalidUUID", func(t *testing.T) {
+		w := policyRequest(router, "user|"+ownerID.String(), "/api/v1/wallets/not-a-uuid/balance")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("UnknownWallet", func(t *testing.T) {
+		for _, token := range []string{"user|" + ownerID.String(), "admin|" + uuid.NewString()} {
+			w := policyRequest(router, token, "/api/v1/wallets/"+uuid.NewString())
+
+			assert.Equal(t, http.StatusNotFound, w.Code, token)
+		}
+	})
+}
+
+// TestRouter_OwnerPolicies: операции с кошельком и профилем пользователя
+// отклоняются политикой маршрута до handler'а. Что handler делает с
+// пропущенным запросом, здесь не важно - важно, что он не получил 403.
+func TestRouter_OwnerPolicies(t *testing.T) {
+	router, ownerID, walletID, txID, _ := setupPolicyRouter(t)
+	owner := "user|" + ownerID.String()
+	other := "user|" + uuid.NewString()
+	admin := "admin|" + uuid.NewString()
+
+	wallet := "/api/v1/wallets/" + walletID.String()
+	user := "/api/v1/users/" + ownerID.String()
+	tx := "/api/v1/transactions/" + txID.String()
+
+	tests := []struct {
+		method  string
+		path    string
+		allowed []string
+		denied  []string
+	}{
+		{http.MethodPost, wallet + "/credit", []string{owner}, []string{other, admin}},
+		{http.MethodPost, wallet + "/debit", []string{owner}, []string{other, admin}},
+		{http.MethodPost, wallet + "/transfer", []string{owner}, []string{other, admin}},
+		{http.MethodPost, wallet + "/transfers/bulk", []string{owner}, []string{other, admin}},
+		{http.MethodPost, wallet + "/exchange", []string{owner}, []string{other, admin}},
+		{http.MethodPost, wallet + "/close", []string{owner}, []string{other, admin}},
+		{http.MethodPut, wallet + "/settings", []string{owner}, []string{other, admin}},
+		{http.MethodGet, wallet + "/transactions", []string{owner, admin}, []string{other}},
+		{http.MethodPost, wallet + "/transactions", []string{owner, admin}, []string{other}},
+		{http.MethodPost, tx + "/retry", []string{owner, admin}, []string{other}},
+		{http.MethodPost, tx + "/cancel", []string{owner, admin}, []string{other}},
+		{http.MethodGet, wallet + "/activity/heatmap", []string{owner, admin}, []string{other}},
+		{http.MethodGet, user, []string{owner}, []string{other, admin}},
+		{http.MethodPatch, user, []string{owner}, []string{other, admin}},
+		{http.MethodPost, user + "/accept-terms", []string{owner}, []string{other, admin}},
+		{http.MethodGet, user + "/kyc/history", []string{owner, admin}, []string{other}},
+		{http.MethodPut, user + "/wallets/USD", []string{owner, admin}, []string{other}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			for _, token := range tt.denied {
+				w := policyRequestMethod(router, tt.method, token, tt.path, "Idempotency-Key", uuid.NewString())
+
+				require.Equal(t, http.StatusForbidden, w.Code, token)
+				assert.Equal(t, "POLICY_DENIED", decodePolicyError(t, w).Code, token)
+			}
+			for _, token := range tt.allowed {
+				w := policyRequestMethod(router, tt.method, token, tt.path, "Idempotency-Key", uuid.NewString())
+
+				assert.NotEqual(t, http.StatusForbidden, w.Code, "%s: %s", token, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("UnknownWallet", func(t *testing.T) {
+		w := policyRequestMethod(router, http.MethodPost, owner, "/api/v1/wallets/"+uuid.NewString()+"/credit", "Idempotency-Key", uuid.NewString())
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("UnknownIdempotencyKey", func(t *testing.T) {
+		w := policyRequest(router, owner, "/api/v1/transactions/by-key/"+uuid.NewString())
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
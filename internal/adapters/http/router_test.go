@@ -1,13 +1,17 @@
 package http
 
 import (
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/Haleralex/wallethub/internal/adapters/http/handlers"
 	"github.com/Haleralex/wallethub/internal/adapters/http/middleware"
+	"github.com/Haleralex/wallethub/internal/adapters/http/routes"
 	"github.com/Haleralex/wallethub/internal/application/cqrs"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -215,6 +219,22 @@ func TestRouter_RequestID(t *testing.T) {
 	assert.NotEmpty(t, w.Header().Get("X-Request-ID"))
 }
 
+func TestRouter_RequestIDInResponseBody(t *testing.T) {
+	cfg := DefaultRouterConfig()
+	router := NewRouterBuilder(cfg).Build()
+
+	req := httptest.NewRequest("GET", "/does-not-exist", nil)
+	req.Header.Set("X-Request-ID", "client-request-1")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	// Request ID из middleware попадает в конверт ответа
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "client-request-1", response["request_id"])
+}
+
 func TestRouter_WithCQRSOnly(t *testing.T) {
 	cfg := DefaultRouterConfig()
 	cmdBus := cqrs.NewCommandBus()
@@ -235,6 +255,196 @@ func TestRouter_WithoutCQRS(t *testing.T) {
 	require.NotNil(t, router)
 }
 
+func TestRouter_SandboxRoutes(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		enabled     bool
+		registered  bool
+	}{
+		{"Disabled", "development", false, false},
+		{"Enabled", "development", true, true},
+		{"Enabled in production", "production", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultRouterConfig()
+			cfg.Environment = tt.environment
+			cfg.SandboxEnabled = tt.enabled
+
+			router := NewRouterBuilder(cfg).
+				WithCQRS(cqrs.NewCommandBus(), cqrs.NewQueryBus()).
+				Build()
+
+			registered := false
+			for _, route := range router.Routes() {
+				if route.Method == http.MethodPost && route.Path == "/api/v1/sandbox/reset" {
+					registered = true
+				}
+			}
+			assert.Equal(t, tt.registered, registered)
+		})
+	}
+}
+
+// manifestRoute - маршрут из ответа manifest: политика в JSON - строка.
+type manifestRoute struct {
+	routes.Route
+	Policy string `json:"policy,omitempty"`
+}
+
+// fetchRouteManifest запрашивает manifest и возвращает маршруты из ответа.
+func fetchRouteManifest(t *testing.T, router *gin.Engine) []manifestRoute {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/meta/routes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data struct {
+			Routes []manifestRoute `json:"routes"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	return response.Data.Routes
+}
+
+func TestRouter_RouteManifest(t *testing.T) {
+	cfg := DefaultRouterConfig()
+	cfg.SandboxEnabled = true
+
+	router := NewRouterBuilder(cfg).
+		WithCQRS(cqrs.NewCommandBus(), cqrs.NewQueryBus()).
+		WithTelegramAuth(&TelegramAuthDeps{}).
+		Build()
+
+	manifest := fetchRouteManifest(t, router)
+
+	t.Run("EveryGinRouteListed", func(t *testing.T) {
+		listed := make(map[string]bool, len(manifest))
+		for _, route := range manifest {
+			listed[route.Method+" "+route.Path] = true
+		}
+
+		for _, route := range router.Routes() {
+			key := route.Method + " " + routes.PathTemplate(route.Path)
+			assert.True(t, listed[key], "route %s %s missing from manifest", route.Method, route.Path)
+		}
+		assert.Len(t, manifest, len(router.Routes()))
+		assert.True(t, listed["GET /api/v1/meta/routes"], "manifest lists itself")
+	})
+
+	t.Run("RequiredMetadata", func(t *testing.T) {
+		auth := []string{routes.AuthPublic, routes.AuthUser, routes.AuthAdmin}
+		idempotency := []string{routes.IdempotencySafe, routes.IdempotencyKey, routes.IdempotencyNone}
+		rateLimit := []string{routes.RateLimitGlobal, routes.RateLimitFinancial}
+
+		for _, route := range manifest {
+			name := route.Method + " " + route.Path
+			assert.Empty(t, route.Missing(), name)
+			assert.Contains(t, auth, route.Auth, name)
+			assert.Contains(t, idempotency, route.Idempotency, name)
+			assert.Contains(t, rateLimit, route.RateLimit, name)
+		}
+	})
+
+	t.Run("SchemaRefsResolve", func(t *testing.T) {
+		spec, err := os.ReadFile("../../../api/openapi.yaml")
+		require.NoError(t, err)
+
+		for _, route := range manifest {
+			for _, ref := range []string{route.Request, route.Response} {
+				if ref == "" {
+					continue
+				}
+				name := routes.SchemaName(ref)
+				require.NotEmpty(t, name, "%s %s: unexpected ref %q", route.Method, route.Path, ref)
+				assert.True(t, strings.Contains(string(spec), "\n    "+name+":\n"),
+					"%s %s: schema %s not found in api/openapi.yaml", route.Method, route.Path, name)
+			}
+		}
+	})
+
+	t.Run("KnownRoutes", func(t *testing.T) {
+		find := func(method, path string) manifestRoute {
+			for _, route := range manifest {
+				if route.Method == method && route.Path == path {
+					return route
+				}
+			}
+			t.Fatalf("route %s %s not found", method, path)
+			return manifestRoute{}
+		}
+
+		credit := find(http.MethodPost, "/api/v1/wallets/{id}/credit")
+		assert.Equal(t, routes.AuthUser, credit.Auth)
+		assert.Equal(t, routes.IdempotencyKey, credit.Idempotency)
+		assert.Equal(t, routes.RateLimitFinancial, credit.RateLimit)
+		assert.Equal(t, routes.SchemaRef("CreditWalletRequest"), credit.Request)
+
+		assert.Equal(t, routes.AuthAdmin, find(http.MethodPost, "/api/v1/users/{id}/kyc").Auth)
+		assert.Equal(t, routes.AuthPublic, find(http.MethodPost, "/api/v1/users").Auth)
+		assert.Equal(t, routes.IdempotencySafe, find(http.MethodGet, "/api/v1/transactions/{id}").Idempotency)
+
+		deleteNote := find(http.MethodDelete, "/api/v1/admin/wallets/{id}/notes/{note_id}")
+		assert.Equal(t, routes.AuthAdmin, deleteNote.Auth)
+		assert.Equal(t, routes.IdempotencyNone, deleteNote.Idempotency)
+		assert.True(t, deleteNote.Confirmation)
+		assert.False(t, find(http.MethodGet, "/api/v1/admin/wallets/{id}/notes").Confirmation)
+
+		assert.Equal(t, "and(scope(wallets:read), or(owner(wallet:id), role(admin|superadmin)))",
+			find(http.MethodGet, "/api/v1/wallets/{id}").Policy)
+		assert.Equal(t, "scope(wallets:read)", find(http.MethodGet, "/api/v1/wallets/me").Policy)
+		assert.Equal(t, "owner(wallet:id)", credit.Policy)
+		assert.Equal(t, "and(scope(transactions:read), or(owner(transaction_key:key), role(admin|superadmin)))",
+			find(http.MethodGet, "/api/v1/transactions/by-key/{key}").Policy)
+		assert.Empty(t, find(http.MethodPost, "/api/v1/wallets").Policy)
+	})
+}
+
+func TestRouter_RouteManifestDisabled(t *testing.T) {
+	cfg := DefaultRouterConfig()
+	cfg.RouteManifestEnabled = false
+
+	router := NewRouterBuilder(cfg).Build()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/meta/routes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRouter_StateMachines(t *testing.T) {
+	router := NewRouterBuilder(DefaultRouterConfig()).Build()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/meta/state-machines", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data handlers.StateMachinesResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Data.Machines, 2)
+
+	transaction := response.Data.Machines[0]
+	assert.Equal(t, "transaction", transaction.Name)
+	assert.Equal(t, []string{"CANCELLED", "COMPLETED"}, transaction.Final)
+	assert.Contains(t, transaction.Transitions, handlers.TransitionDTO{From: "FAILED", To: "PENDING"})
+	assert.NotContains(t, transaction.Transitions, handlers.TransitionDTO{From: "COMPLETED", To: "PENDING"})
+
+	wallet := response.Data.Machines[1]
+	assert.Equal(t, "wallet", wallet.Name)
+	assert.Equal(t, []string{"CLOSED"}, wallet.Final)
+}
+
 func TestRouterConfig_AllFields(t *testing.T) {
 	logger := slog.Default()
 	validator := middleware.MockTokenValidator
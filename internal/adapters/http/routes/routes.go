@@ -0,0 +1,315 @@
+// Package routes - регистрация HTTP маршрутов вместе с метаданными.
+//
+// Роутер регистрирует handlers не через gin напрямую, а через Group: вместе
+// с маршрутом сохраняются auth scope, ссылки на схемы запроса/ответа,
+// требования идемпотентности, классы rate limit и concurrency. Route manifest
+// (GET /api/v1/meta/routes) строится из того же Registry, поэтому не может
+// разойтись с реально зарегистрированными маршрутами.
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/policy"
+)
+
+// ============================================
+// Metadata Values
+// ============================================
+
+// Auth scopes.
+const (
+	AuthPublic = "public" // Без токена
+	AuthUser   = "user"   // Любой аутентифицированный пользователь
+	AuthAdmin  = "admin"  // Роль admin или superadmin
+)
+
+// Требования идемпотентности.
+const (
+	IdempotencySafe = "safe"            // Без побочных эффектов (GET, HEAD)
+	IdempotencyKey  = "idempotency_key" // Повтор с тем же idempotency_key в теле возвращает первый результат
+	IdempotencyNone = "none"            // Повтор выполняет операцию заново
+)
+
+// Классы rate limit.
+const (
+	RateLimitGlobal    = "global"    // Общий лимит на клиента
+	RateLimitFinancial = "financial" // Общий + лимит денежных операций
+)
+
+// Классы concurrency limit: сколько запросов клиента класса выполняется
+// одновременно (middleware.ConcurrencyLimiter).
+const (
+	ConcurrencyRead     = "read"     // Дешёвые чтения (по умолчанию для GET/HEAD)
+	ConcurrencyMutation = "mutation" // Изменения и денежные операции (по умолчанию для остальных)
+	ConcurrencyReport   = "report"   // Тяжёлые выборки и выгрузки
+)
+
+// schemaRefPrefix - схемы описаны в api/openapi.yaml.
+const schemaRefPrefix = "#/components/schemas/"
+
+// SchemaRef возвращает ссылку на схему components/schemas из api/openapi.yaml.
+func SchemaRef(name string) string {
+	return schemaRefPrefix + name
+}
+
+// SchemaName возвращает имя схемы из ссылки SchemaRef ("" для чужих ссылок).
+func SchemaName(ref string) string {
+	if !strings.HasPrefix(ref, schemaRefPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(ref, schemaRefPrefix)
+}
+
+// ============================================
+// Route Metadata
+// ============================================
+
+// Meta - метаданные маршрута.
+//
+// Auth, Idempotency и RateLimit обязательны: пустые значения наследуются
+// от группы, а для GET/HEAD Idempotency по умолчанию IdempotencySafe.
+// Concurrency по умолчанию ConcurrencyRead для GET/HEAD и
+// ConcurrencyMutation для остальных методов.
+// Request/Response - ссылки SchemaRef. Response пуст только у HEAD,
+// ответов 204 и маршрутов с ContentType - ответ не в JSON конверте
+// (метрики, статика, сама спецификация). Status - код успешного ответа
+// (0 - 200). Confirmation - маршрут требует повторить запрос с
+// токеном X-Confirm-Operation (middleware.RequireConfirmation). Policy -
+// политика доступа (middleware.AuthorizeForRoute), наследуется от группы;
+// nil - достаточно Auth.
+type Meta struct {
+	Auth         string        `json:"auth"`
+	Idempotency  string        `json:"idempotency"`
+	RateLimit    string        `json:"rate_limit"`
+	Concurrency  string        `json:"concurrency"`
+	Request      string        `json:"request_schema,omitempty"`
+	Response     string        `json:"response_schema,omitempty"`
+	Status       int           `json:"status,omitempty"`
+	ContentType  string        `json:"content_type,omitempty"`
+	Confirmation bool          `json:"confirmation,omitempty"`
+	Policy       policy.Policy `json:"policy,omitempty"`
+}
+
+// merge возвращает m, дополненные значениями по умолчанию из defaults.
+func (m Meta) merge(defaults Meta) Meta {
+	if m.Auth == "" {
+		m.Auth = defaults.Auth
+	}
+	if m.Idempotency == "" {
+		m.Idempotency = defaults.Idempotency
+	}
+	if m.RateLimit == "" {
+		m.RateLimit = defaults.RateLimit
+	}
+	if m.Concurrency == "" {
+		m.Concurrency = defaults.Concurrency
+	}
+	if m.Request == "" {
+		m.Request = defaults.Request
+	}
+	if m.Response == "" {
+		m.Response = defaults.Response
+	}
+	if m.Policy == nil {
+		m.Policy = defaults.Policy
+	}
+	m.Confirmation = m.Confirmation || defaults.Confirmation
+	return m
+}
+
+// Missing возвращает имена незаполненных обязательных полей.
+func (m Meta) Missing() []string {
+	var missing []string
+	if m.Auth == "" {
+		missing = append(missing, "auth")
+	}
+	if m.Idempotency == "" {
+		missing = append(missing, "idempotency")
+	}
+	if m.RateLimit == "" {
+		missing = append(missing, "rate_limit")
+	}
+	return missing
+}
+
+// Route - зарегистрированный маршрут.
+type Route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"` // Шаблон в стиле OpenAPI: /wallets/{id}
+	Meta
+}
+
+// PathTemplate переводит gin путь в шаблон OpenAPI: ":id" и "*filepath"
+// становятся "{id}" и "{filepath}".
+func PathTemplate(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// ============================================
+// Registry
+// ============================================
+
+// Registry хранит все маршруты, зарегистрированные через Group.
+type Registry struct {
+	mu     sync.RWMutex
+	routes []Route
+}
+
+// NewRegistry создаёт пустой Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Group оборачивает gin группу. defaults применяются ко всем маршрутам группы.
+func (r *Registry) Group(group *gin.RouterGroup, defaults Meta) *Group {
+	return &Group{group: group, registry: r, defaults: defaults}
+}
+
+// Routes возвращает копию маршрутов, отсортированную по пути и методу.
+func (r *Registry) Routes() []Route {
+	r.mu.RLock()
+	result := make([]Route, len(r.routes))
+	copy(result, r.routes)
+	r.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Path != result[j].Path {
+			return result[i].Path < result[j].Path
+		}
+		return result[i].Method < result[j].Method
+	})
+	return result
+}
+
+func (r *Registry) add(route Route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, route)
+}
+
+// ============================================
+// Group
+// ============================================
+
+// RouteMiddleware строит middleware маршрута по его метаданным (nil - не
+// нужен). Выполняется после middleware групп, перед handlers маршрута.
+type RouteMiddleware func(route Route) gin.HandlerFunc
+
+// Group - gin.RouterGroup, который записывает маршруты в Registry.
+type Group struct {
+	group           *gin.RouterGroup
+	registry        *Registry
+	defaults        Meta
+	routeMiddleware []RouteMiddleware
+}
+
+// Group создаёт подгруппу с middleware. defaults дополняют значения родителя.
+func (g *Group) Group(relativePath string, defaults Meta, middleware ...gin.HandlerFunc) *Group {
+	return &Group{
+		group:           g.group.Group(relativePath, middleware...),
+		registry:        g.registry,
+		defaults:        defaults.merge(g.defaults),
+		routeMiddleware: g.routeMiddleware,
+	}
+}
+
+// WithRouteMiddleware возвращает копию группы, маршруты которой (и её
+// подгрупп, созданных после вызова) получают middleware из mw.
+func (g *Group) WithRouteMiddleware(mw RouteMiddleware) *Group {
+	clone := *g
+	clone.routeMiddleware = append(slices.Clip(g.routeMiddleware), mw)
+	return &clone
+}
+
+// Handle регистрирует маршрут в gin и в Registry.
+//
+// Паникует, если обязательные метаданные не заданы ни маршрутом, ни группой -
+// так же, как gin паникует на конфликтующих маршрутах: это ошибка сборки
+// роутера, а не запроса.
+func (g *Group) Handle(method, relativePath string, meta Meta, handlers ...gin.HandlerFunc) {
+	meta = meta.merge(g.defaults)
+	safe := method == http.MethodGet || method == http.MethodHead
+	if meta.Idempotency == "" && safe {
+		meta.Idempotency = IdempotencySafe
+	}
+	if meta.Concurrency == "" {
+		meta.Concurrency = ConcurrencyMutation
+		if safe {
+			meta.Concurrency = ConcurrencyRead
+		}
+	}
+
+	fullPath := joinPaths(g.group.BasePath(), relativePath)
+	if missing := meta.Missing(); len(missing) > 0 {
+		panic(fmt.Sprintf("routes: %s %s: missing metadata %v", method, fullPath, missing))
+	}
+
+	route := Route{Method: method, Path: PathTemplate(fullPath), Meta: meta}
+	var chain []gin.HandlerFunc
+	for _, mw := range g.routeMiddleware {
+		if h := mw(route); h != nil {
+			chain = append(chain, h)
+		}
+	}
+
+	g.group.Handle(method, relativePath, append(chain, handlers...)...)
+	g.registry.add(route)
+}
+
+// GET регистрирует GET маршрут.
+func (g *Group) GET(relativePath string, meta Meta, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodGet, relativePath, meta, handlers...)
+}
+
+// HEAD регистрирует HEAD маршрут.
+func (g *Group) HEAD(relativePath string, meta Meta, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodHead, relativePath, meta, handlers...)
+}
+
+// POST регистрирует POST маршрут.
+func (g *Group) POST(relativePath string, meta Meta, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPost, relativePath, meta, handlers...)
+}
+
+// PATCH регистрирует PATCH маршрут.
+func (g *Group) PATCH(relativePath string, meta Meta, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPatch, relativePath, meta, handlers...)
+}
+
+// PUT регистрирует PUT маршрут.
+func (g *Group) PUT(relativePath string, meta Meta, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPut, relativePath, meta, handlers...)
+}
+
+// DELETE регистрирует DELETE маршрут.
+func (g *Group) DELETE(relativePath string, meta Meta, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodDelete, relativePath, meta, handlers...)
+}
+
+// joinPaths склеивает пути так же, как gin (с сохранением завершающего "/").
+func joinPaths(absolutePath, relativePath string) string {
+	if relativePath == "" {
+		return absolutePath
+	}
+
+	finalPath := path.Join(absolutePath, relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(finalPath, "/") {
+		return finalPath + "/"
+	}
+	return finalPath
+}
@@ -0,0 +1,108 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup_Handle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	noop := func(c *gin.Context) {}
+
+	t.Run("InheritsGroupDefaults", func(t *testing.T) {
+		engine := gin.New()
+		registry := NewRegistry()
+		root := registry.Group(&engine.RouterGroup, Meta{Auth: AuthPublic, RateLimit: RateLimitGlobal})
+
+		api := root.Group("/api", Meta{Auth: AuthUser})
+		money := api.Group("/wallets", Meta{Idempotency: IdempotencyKey, RateLimit: RateLimitFinancial})
+		money.POST("/:id/credit", Meta{Request: SchemaRef("CreditWalletRequest")}, noop)
+		api.GET("/wallets/:id", Meta{Auth: AuthAdmin}, noop)
+
+		require.Equal(t, []Route{
+			{Method: http.MethodGet, Path: "/api/wallets/{id}", Meta: Meta{
+				Auth: AuthAdmin, Idempotency: IdempotencySafe, RateLimit: RateLimitGlobal, Concurrency: ConcurrencyRead,
+			}},
+			{Method: http.MethodPost, Path: "/api/wallets/{id}/credit", Meta: Meta{
+				Auth: AuthUser, Idempotency: IdempotencyKey, RateLimit: RateLimitFinancial, Concurrency: ConcurrencyMutation,
+				Request: "#/components/schemas/CreditWalletRequest",
+			}},
+		}, registry.Routes())
+
+		// Маршруты действительно зарегистрированы в gin
+		assert.Len(t, engine.Routes(), 2)
+	})
+
+	t.Run("MissingMetadataPanics", func(t *testing.T) {
+		engine := gin.New()
+		root := NewRegistry().Group(&engine.RouterGroup, Meta{Auth: AuthUser, RateLimit: RateLimitGlobal})
+
+		assert.PanicsWithValue(t, "routes: POST /users: missing metadata [idempotency]", func() {
+			root.POST("/users", Meta{}, noop)
+		})
+		assert.Empty(t, engine.Routes(), "route must not reach gin without metadata")
+	})
+
+	t.Run("RouteMiddleware", func(t *testing.T) {
+		engine := gin.New()
+		root := NewRegistry().Group(&engine.RouterGroup, Meta{Auth: AuthPublic, RateLimit: RateLimitGlobal})
+
+		var seen []string
+		api := root.Group("/api", Meta{}).WithRouteMiddleware(func(route Route) gin.HandlerFunc {
+			if route.Concurrency == ConcurrencyRead {
+				return nil
+			}
+			return func(c *gin.Context) { seen = append(seen, route.Concurrency) }
+		})
+		reports := api.Group("/reports", Meta{Concurrency: ConcurrencyReport})
+		reports.GET("", Meta{}, noop)
+		api.GET("/wallets", Meta{}, noop)
+		api.POST("/wallets", Meta{Idempotency: IdempotencyNone}, noop)
+		root.POST("/login", Meta{Idempotency: IdempotencyNone}, noop)
+
+		for _, target := range []struct{ method, path string }{
+			{http.MethodGet, "/api/reports"},
+			{http.MethodGet, "/api/wallets"},
+			{http.MethodPost, "/api/wallets"},
+			{http.MethodPost, "/login"},
+		} {
+			engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(target.method, target.path, nil))
+		}
+		assert.Equal(t, []string{ConcurrencyReport, ConcurrencyMutation}, seen, "groups outside WithRouteMiddleware are not wrapped")
+	})
+
+	t.Run("PathJoining", func(t *testing.T) {
+		engine := gin.New()
+		registry := NewRegistry()
+		root := registry.Group(&engine.RouterGroup, Meta{Auth: AuthPublic, RateLimit: RateLimitGlobal})
+
+		root.Group("/api/v1", Meta{}).GET("", Meta{}, noop)
+		root.GET("/app/*filepath", Meta{}, noop)
+
+		var paths []string
+		for _, route := range registry.Routes() {
+			paths = append(paths, route.Path)
+		}
+		assert.Equal(t, []string{"/api/v1", "/app/{filepath}"}, paths)
+	})
+}
+
+func TestPathTemplate(t *testing.T) {
+	assert.Equal(t, "/wallets/{id}/transactions", PathTemplate("/wallets/:id/transactions"))
+	assert.Equal(t, "/transactions/by-key/{key}", PathTemplate("/transactions/by-key/:key"))
+	assert.Equal(t, "/m/{filepath}", PathTemplate("/m/*filepath"))
+	assert.Equal(t, "/health", PathTemplate("/health"))
+}
+
+func TestSchemaRef(t *testing.T) {
+	ref := SchemaRef("WalletResponse")
+
+	assert.Equal(t, "#/components/schemas/WalletResponse", ref)
+	assert.Equal(t, "WalletResponse", SchemaName(ref))
+	assert.Empty(t, SchemaName("WalletResponse"))
+}
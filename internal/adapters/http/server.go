@@ -91,6 +91,12 @@ func NewServer(config *ServerConfig, router *gin.Engine) *Server {
 	}
 }
 
+// Handler возвращает обработчик запросов сервера (для httptest без
+// прослушивания порта).
+func (s *Server) Handler() http.Handler {
+	return s.httpServer.Handler
+}
+
 // Start запускает сервер.
 func (s *Server) Start() error {
 	s.config.Logger.Info("Starting HTTP server",
@@ -28,6 +28,10 @@ type EventMessage struct {
 	AggregateID string          `json:"aggregate_id"`
 	Payload     json.RawMessage `json:"payload"`
 	OccurredAt  time.Time       `json:"occurred_at"`
+
+	// ProducerVersion is the build (version+commit) that produced the event;
+	// null for events stored before versions were recorded.
+	ProducerVersion *string `json:"producer_version"`
 }
 
 // NewPublisher creates a new NATS publisher.
@@ -0,0 +1,156 @@
+// Package compliance содержит правила юрисдикций и сроков хранения данных.
+//
+// Юрисдикция (ISO 3166-1 alpha-2) задаётся пользователю, а кошельки и
+// транзакции получают её копию в момент создания. Срок хранения всегда
+// вычисляется по тегу самой записи, а не по текущей юрисдикции пользователя.
+package compliance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Policy - правила юрисдикций и хранения данных для конкретного деплоя.
+//
+// Thread-safe: после создания не изменяется.
+type Policy struct {
+	allowed                map[string]struct{} // пусто = разрешён любой валидный код
+	defaultJurisdiction    string
+	retentionMonths        map[string]int
+	defaultRetentionMonths int
+}
+
+// NewPolicy создаёт Policy и нормализует коды юрисдикций.
+//
+// Parameters:
+//   - allowed: Разрешённые юрисдикции (пусто = любые)
+//   - defaultJurisdiction: Fallback для пользователей и записей без тега
+//   - retentionMonths: Срок хранения по юрисдикциям, в месяцах
+//   - defaultRetentionMonths: Срок хранения для юрисдикций без явного правила
+func NewPolicy(
+	allowed []string,
+	defaultJurisdiction string,
+	retentionMonths map[string]int,
+	defaultRetentionMonths int,
+) (*Policy, error) {
+	p := &Policy{
+		allowed:                make(map[string]struct{}, len(allowed)),
+		retentionMonths:        make(map[string]int, len(retentionMonths)),
+		defaultRetentionMonths: defaultRetentionMonths,
+	}
+
+	for _, code := range allowed {
+		normalized, err := entities.NormalizeJurisdiction(code)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed jurisdiction %q: %w", code, err)
+		}
+		p.allowed[normalized] = struct{}{}
+	}
+
+	// Ключи map из конфигурации могут прийти в нижнем регистре (viper)
+	for code, months := range retentionMonths {
+		normalized, err := entities.NormalizeJurisdiction(code)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retention jurisdiction %q: %w", code, err)
+		}
+		if months <= 0 {
+			return nil, fmt.Errorf("retention for %s must be positive, got %d months", normalized, months)
+		}
+		p.retentionMonths[normalized] = months
+	}
+
+	if defaultRetentionMonths <= 0 {
+		return nil, fmt.Errorf("default retention must be positive, got %d months", defaultRetentionMonths)
+	}
+
+	normalized, err := entities.NormalizeJurisdiction(defaultJurisdiction)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default jurisdiction %q: %w", defaultJurisdiction, err)
+	}
+	if !p.IsAllowed(normalized) {
+		return nil, fmt.Errorf("default jurisdiction %s is not in the allowed list", normalized)
+	}
+	p.defaultJurisdiction = normalized
+
+	return p, nil
+}
+
+// DefaultJurisdiction возвращает fallback-юрисдикцию.
+func (p *Policy) DefaultJurisdiction() string {
+	return p.defaultJurisdiction
+}
+
+// IsAllowed проверяет, разрешена ли юрисдикция (код должен быть нормализован).
+func (p *Policy) IsAllowed(code string) bool {
+	if len(p.allowed) == 0 {
+		return true
+	}
+	_, ok := p.allowed[code]
+	return ok
+}
+
+// ResolveJurisdiction валидирует юрисдикцию из запроса пользователя.
+// Пустое значение заменяется на fallback.
+//
+// Errors:
+//   - ValidationError: Невалидный код или юрисдикция не разрешена
+func (p *Policy) ResolveJurisdiction(code string) (string, error) {
+	if code == "" {
+		return p.defaultJurisdiction, nil
+	}
+
+	return p.ValidateJurisdiction(code)
+}
+
+// ValidateJurisdiction нормализует код и проверяет, что юрисдикция разрешена.
+// В отличие от ResolveJurisdiction пустое значение - ошибка.
+//
+// Errors:
+//   - ValidationError: Невалидный код или юрисдикция не разрешена
+func (p *Policy) ValidateJurisdiction(code string) (string, error) {
+	normalized, err := entities.NormalizeJurisdiction(code)
+	if err != nil {
+		return "", err
+	}
+
+	if !p.IsAllowed(normalized) {
+		return "", errors.ValidationError{
+			Field:   "jurisdiction",
+			Message: fmt.Sprintf("jurisdiction %s is not supported", normalized),
+		}
+	}
+
+	return normalized, nil
+}
+
+// RecordJurisdiction возвращает юрисдикцию записи для правил хранения.
+// Записи без тега (созданные до миграции) считаются fallback-юрисдикцией.
+func (p *Policy) RecordJurisdiction(tag string) string {
+	if tag == "" {
+		return p.defaultJurisdiction
+	}
+	return tag
+}
+
+// RetentionMonths возвращает срок хранения для тега записи.
+func (p *Policy) RetentionMonths(tag string) int {
+	if months, ok := p.retentionMonths[p.RecordJurisdiction(tag)]; ok {
+		return months
+	}
+	return p.defaultRetentionMonths
+}
+
+// RetentionCutoff возвращает момент, до которого записи с данным тегом
+// считаются вышедшими за срок хранения.
+func (p *Policy) RetentionCutoff(tag string, now time.Time) time.Time {
+	return now.AddDate(0, -p.RetentionMonths(tag), 0)
+}
+
+// IsExpired проверяет, истёк ли срок хранения записи.
+// Используется архивацией и удалением - всегда по тегу самой записи.
+func (p *Policy) IsExpired(tag string, createdAt, now time.Time) bool {
+	return createdAt.Before(p.RetentionCutoff(tag, now))
+}
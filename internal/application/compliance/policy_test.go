@@ -0,0 +1,106 @@
+package compliance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+func newTestPolicy(t *testing.T) *Policy {
+	t.Helper()
+
+	// Ключи в нижнем регистре - так их отдаёт viper
+	policy, err := NewPolicy([]string{"de", "FR", "US"}, "us", map[string]int{"de": 120, "fr": 72}, 60)
+	require.NoError(t, err)
+	return policy
+}
+
+func TestNewPolicy_Validation(t *testing.T) {
+	tests := []struct {
+		name            string
+		allowed         []string
+		defaultCode     string
+		retention       map[string]int
+		defaultMonths   int
+		wantErrContains string
+	}{
+		{"InvalidAllowed", []string{"DEU"}, "DE", nil, 60, "invalid allowed jurisdiction"},
+		{"InvalidDefault", nil, "", nil, 60, "invalid default jurisdiction"},
+		{"DefaultNotAllowed", []string{"DE"}, "FR", nil, 60, "not in the allowed list"},
+		{"InvalidRetentionKey", nil, "DE", map[string]int{"GER": 12}, 60, "invalid retention jurisdiction"},
+		{"NonPositiveRetention", nil, "DE", map[string]int{"DE": 0}, 60, "must be positive"},
+		{"NonPositiveDefaultRetention", nil, "DE", nil, 0, "default retention must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPolicy(tt.allowed, tt.defaultCode, tt.retention, tt.defaultMonths)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErrContains)
+		})
+	}
+}
+
+func TestPolicy_ResolveJurisdiction(t *testing.T) {
+	policy := newTestPolicy(t)
+
+	t.Run("EmptyUsesDefault", func(t *testing.T) {
+		code, err := policy.ResolveJurisdiction("")
+		require.NoError(t, err)
+		assert.Equal(t, "US", code)
+	})
+
+	t.Run("Normalizes", func(t *testing.T) {
+		code, err := policy.ResolveJurisdiction(" fr ")
+		require.NoError(t, err)
+		assert.Equal(t, "FR", code)
+	})
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		_, err := policy.ResolveJurisdiction("JP")
+		var validationErr domainErrors.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "jurisdiction", validationErr.Field)
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		_, err := policy.ResolveJurisdiction("USA")
+		assert.Error(t, err)
+	})
+
+	t.Run("ValidateRejectsEmpty", func(t *testing.T) {
+		_, err := policy.ValidateJurisdiction("")
+		assert.Error(t, err)
+	})
+
+	t.Run("EmptyAllowedListAcceptsAny", func(t *testing.T) {
+		open, err := NewPolicy(nil, "DE", nil, 60)
+		require.NoError(t, err)
+
+		code, err := open.ResolveJurisdiction("jp")
+		require.NoError(t, err)
+		assert.Equal(t, "JP", code)
+	})
+}
+
+func TestPolicy_Retention(t *testing.T) {
+	policy := newTestPolicy(t)
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 120, policy.RetentionMonths("DE"))
+	assert.Equal(t, 72, policy.RetentionMonths("FR"))
+	assert.Equal(t, 60, policy.RetentionMonths("US"), "no explicit rule - default retention")
+	assert.Equal(t, 60, policy.RetentionMonths(""), "untagged record - default jurisdiction")
+
+	assert.Equal(t, time.Date(2016, 6, 15, 12, 0, 0, 0, time.UTC), policy.RetentionCutoff("DE", now))
+
+	// Одинаковый возраст записи, разные теги - разный результат
+	createdAt := now.AddDate(-7, 0, 0)
+	assert.False(t, policy.IsExpired("DE", createdAt, now))
+	assert.True(t, policy.IsExpired("FR", createdAt, now))
+	assert.True(t, policy.IsExpired("", createdAt, now))
+}
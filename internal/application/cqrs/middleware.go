@@ -12,6 +12,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
 )
 
 // requestName extracts the short type name from a request value.
@@ -119,3 +121,19 @@ func RecoveryMiddleware(logger *slog.Logger) Middleware {
 		}
 	}
 }
+
+// UnitOfWorkGuardMiddleware puts a ports.UnitOfWorkGuard into the context of
+// every dispatch: repository calls made with a context captured outside
+// uow.Execute are logged (or panic in strict mode), and a re-entrant Execute
+// fails with ports.ErrNestedUnitOfWork. A dispatch nested in another one
+// keeps the outer guard.
+func UnitOfWorkGuardMiddleware(logger *slog.Logger, strict bool) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request any) (any, error) {
+			if ports.UnitOfWorkGuardFromContext(ctx) == nil {
+				ctx = ports.WithUnitOfWorkGuard(ctx, ports.NewUnitOfWorkGuard(logger, strict))
+			}
+			return next(ctx, request)
+		}
+	}
+}
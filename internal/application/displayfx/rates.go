@@ -0,0 +1,97 @@
+// Package displayfx - курсы для приблизительного пересчёта балансов в
+// валюту отображения кошелька ("≈ $43,210" рядом с BTC балансом).
+//
+// Rates реализует ports.DisplayRates поверх ports.ExchangeRateProvider:
+// курс каждой пары кэшируется на CacheTTL, а Quotes запрашивает каждую
+// уникальную пару не больше одного раза за вызов - список из N кошельков с
+// одной валютой отображения стоит одного обращения к провайдеру.
+//
+// Пересчёт только для показа: ошибки провайдера не кэшируются и не
+// возвращаются, пара без курса просто отсутствует в результате.
+package displayfx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// DefaultCacheTTL - сколько курс живёт в кэше по умолчанию.
+const DefaultCacheTTL = time.Minute
+
+// Config - параметры Rates.
+type Config struct {
+	CacheTTL time.Duration // Время жизни курса в кэше
+}
+
+type cachedQuote struct {
+	quote     *ports.RateQuote
+	expiresAt time.Time
+}
+
+// Rates - кэширующий источник курсов для отображения.
+type Rates struct {
+	provider ports.ExchangeRateProvider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[ports.CurrencyPair]cachedQuote
+}
+
+// Compile-time check
+var _ ports.DisplayRates = (*Rates)(nil)
+
+// NewRates создаёт Rates. Нулевой CacheTTL заменяется DefaultCacheTTL.
+func NewRates(provider ports.ExchangeRateProvider, config Config) *Rates {
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = DefaultCacheTTL
+	}
+
+	return &Rates{
+		provider: provider,
+		ttl:      config.CacheTTL,
+		now:      time.Now,
+		cache:    make(map[ports.CurrencyPair]cachedQuote),
+	}
+}
+
+// Quotes возвращает курсы пар: из кэша или одним обращением к провайдеру на
+// уникальную пару.
+func (r *Rates) Quotes(ctx context.Context, pairs []ports.CurrencyPair) map[ports.CurrencyPair]*ports.RateQuote {
+	result := make(map[ports.CurrencyPair]*ports.RateQuote, len(pairs))
+	missing := make([]ports.CurrencyPair, 0, len(pairs))
+	seen := make(map[ports.CurrencyPair]bool, len(pairs))
+
+	r.mu.Lock()
+	now := r.now()
+	for _, pair := range pairs {
+		if seen[pair] {
+			continue
+		}
+		seen[pair] = true
+
+		if cached, ok := r.cache[pair]; ok && now.Before(cached.expiresAt) {
+			result[pair] = cached.quote
+			continue
+		}
+		missing = append(missing, pair)
+	}
+	r.mu.Unlock()
+
+	for _, pair := range missing {
+		quote, err := r.provider.Quote(ctx, pair.From, pair.To)
+		if err != nil || quote == nil || quote.Rate == nil {
+			continue
+		}
+		result[pair] = quote
+
+		r.mu.Lock()
+		r.cache[pair] = cachedQuote{quote: quote, expiresAt: r.now().Add(r.ttl)}
+		r.mu.Unlock()
+	}
+
+	return result
+}
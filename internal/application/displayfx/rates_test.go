@@ -0,0 +1,75 @@
+package displayfx
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// stubProvider отдаёт курс 2 для всех пар, кроме failing, и считает вызовы.
+type stubProvider struct {
+	calls   int
+	failing bool
+}
+
+func (p *stubProvider) GetRate(ctx context.Context, from, to string) (*big.Rat, error) {
+	quote, err := p.Quote(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return quote.Rate, nil
+}
+
+func (p *stubProvider) Quote(context.Context, string, string) (*ports.RateQuote, error) {
+	p.calls++
+	if p.failing {
+		return nil, errors.New("provider down")
+	}
+	return &ports.RateQuote{Rate: big.NewRat(2, 1), FetchedAt: time.Now()}, nil
+}
+
+func TestRates_Quotes(t *testing.T) {
+	btcUSD := ports.CurrencyPair{From: "BTC", To: "USD"}
+	ethUSD := ports.CurrencyPair{From: "ETH", To: "USD"}
+
+	t.Run("OneCallPerDistinctPair", func(t *testing.T) {
+		provider := &stubProvider{}
+		rates := NewRates(provider, Config{})
+
+		quotes := rates.Quotes(context.Background(), []ports.CurrencyPair{btcUSD, btcUSD, ethUSD, btcUSD})
+		assert.Len(t, quotes, 2)
+		assert.Equal(t, 2, provider.calls)
+	})
+
+	t.Run("CachedUntilTTL", func(t *testing.T) {
+		provider := &stubProvider{}
+		rates := NewRates(provider, Config{CacheTTL: time.Minute})
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		rates.now = func() time.Time { return now }
+
+		rates.Quotes(context.Background(), []ports.CurrencyPair{btcUSD})
+		rates.Quotes(context.Background(), []ports.CurrencyPair{btcUSD})
+		assert.Equal(t, 1, provider.calls)
+
+		now = now.Add(time.Minute)
+		rates.Quotes(context.Background(), []ports.CurrencyPair{btcUSD})
+		assert.Equal(t, 2, provider.calls, "expired quote is fetched again")
+	})
+
+	t.Run("FailuresOmittedAndNotCached", func(t *testing.T) {
+		provider := &stubProvider{failing: true}
+		rates := NewRates(provider, Config{})
+
+		assert.Empty(t, rates.Quotes(context.Background(), []ports.CurrencyPair{btcUSD}))
+
+		provider.failing = false
+		assert.Len(t, rates.Quotes(context.Background(), []ports.CurrencyPair{btcUSD}), 1)
+		assert.Equal(t, 2, provider.calls)
+	})
+}
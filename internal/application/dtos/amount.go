@@ -0,0 +1,53 @@
+// Package dtos - сумма команды: десятичная строка или минорные единицы.
+package dtos
+
+import (
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// CheckCommandAmount добавляет в errs ошибки суммы команды без учёта валюты:
+// задано ровно одно из amount ("100.50") и amount_minor (10050), и оно
+// корректно. Вызывается до загрузки кошелька вместе с остальными проверками.
+func CheckCommandAmount(errs *errors.ValidationErrors, amount string, amountMinor *int64) {
+	switch {
+	case amountMinor == nil && amount == "":
+		errs.AddCode("amount", errors.ValidationCodeRequired, "amount or amount_minor is required")
+	case amountMinor != nil && amount != "":
+		errs.AddCode("amount_minor", errors.ValidationCodeInvalidValue, "amount and amount_minor are mutually exclusive")
+	case amountMinor != nil:
+		if *amountMinor < 0 {
+			errs.AddCode("amount_minor", errors.ValidationCodeOutOfRange, fmt.Sprintf("invalid amount: %v", valueobjects.ErrNegativeAmount))
+		}
+	default:
+		if _, err := valueobjects.ParseAmount(amount); err != nil {
+			errs.AddCode("amount", errors.ValidationCodeInvalidFormat, fmt.Sprintf("invalid amount: %v", err))
+		}
+	}
+}
+
+// ParseCommandAmount возвращает сумму команды в валюте кошелька.
+// amount_minor переводится по currency.Decimals(): 10050 USD = 100.50,
+// 10050 BTC = 0.0001005.
+func ParseCommandAmount(amount string, amountMinor *int64, currency valueobjects.Currency) (valueobjects.Money, error) {
+	var invalid errors.ValidationErrors
+	CheckCommandAmount(&invalid, amount, amountMinor)
+	if err := invalid.Err(); err != nil {
+		return valueobjects.Money{}, invalid[0]
+	}
+
+	if amountMinor != nil {
+		return valueobjects.NewMoneyFromMinorUnits(*amountMinor, currency)
+	}
+	money, err := valueobjects.NewMoney(amount, currency)
+	if err != nil {
+		return valueobjects.Money{}, errors.ValidationError{
+			Field:   "amount",
+			Message: fmt.Sprintf("invalid amount: %v", err),
+			Code:    errors.ValidationCodeInvalidFormat,
+		}
+	}
+	return money, nil
+}
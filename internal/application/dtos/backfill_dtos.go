@@ -0,0 +1,72 @@
+package dtos
+
+import (
+	"time"
+)
+
+// ============================================
+// Commands
+// ============================================
+
+// BackfillTransactionRecord - историческая транзакция из выгрузки legacy
+// системы (одна строка NDJSON). Статус финальный, timestamps исходные.
+type BackfillTransactionRecord struct {
+	ID                  string                 `json:"id"`
+	WalletID            string                 `json:"wallet_id"`
+	IdempotencyKey      string                 `json:"idempotency_key,omitempty"` // Пусто - "legacy-<id>"
+	Type                string                 `json:"type"`
+	Status              string                 `json:"status"`
+	CurrencyCode        string                 `json:"currency_code"`
+	AmountCents         int64                  `json:"amount_cents"`
+	FeeCents            int64                  `json:"fee_cents,omitempty"`
+	NetCents            int64                  `json:"net_cents,omitempty"` // 0 - amount - fee
+	DestinationWalletID *string                `json:"destination_wallet_id,omitempty"`
+	ExternalReference   string                 `json:"external_reference,omitempty"`
+	Description         string                 `json:"description,omitempty"`
+	Metadata            map[string]interface{} `json:"metadata,omitempty"`
+	FailureReason       string                 `json:"failure_reason,omitempty"`
+	Jurisdiction        string                 `json:"jurisdiction,omitempty"`
+	CreatedAt           time.Time              `json:"created_at"`
+	UpdatedAt           time.Time              `json:"updated_at"`
+	CompletedAt         *time.Time             `json:"completed_at,omitempty"`
+}
+
+// BackfillTransactionsCommand - пачка записей для импорта.
+type BackfillTransactionsCommand struct {
+	Records []BackfillTransactionRecord
+}
+
+// BackfillExpectedBalance - итоговый баланс кошелька в legacy системе
+// (строка NDJSON файла ожидаемых балансов).
+type BackfillExpectedBalance struct {
+	WalletID     string `json:"wallet_id"`
+	BalanceCents int64  `json:"balance_cents"`
+}
+
+// ============================================
+// Results
+// ============================================
+
+// BackfillBatchResultDTO - результат импорта пачки.
+type BackfillBatchResultDTO struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"` // Уже импортированы прошлым запуском
+
+	// LedgerCents - изменение баланса кошельков от всех записей пачки,
+	// включая пропущенные (wallet ID -> сумма в минимальных единицах)
+	LedgerCents map[string]int64 `json:"ledger_cents"`
+}
+
+// BackfillMismatchDTO - кошелёк, чей импортированный ledger не сходится
+// с ожидаемым балансом.
+type BackfillMismatchDTO struct {
+	WalletID      string `json:"wallet_id"`
+	ExpectedCents int64  `json:"expected_cents"`
+	ImportedCents int64  `json:"imported_cents"`
+}
+
+// BackfillReconciliationDTO - итог сверки импорта с ожидаемыми балансами.
+type BackfillReconciliationDTO struct {
+	Wallets    int                   `json:"wallets"` // Сколько кошельков сверено
+	Mismatches []BackfillMismatchDTO `json:"mismatches,omitempty"`
+}
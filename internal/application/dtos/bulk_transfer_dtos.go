@@ -0,0 +1,65 @@
+// Package dtos - DTOs пакетных переводов (выплата зарплат и т.п.).
+package dtos
+
+// Статусы пакета переводов.
+const (
+	BulkTransferStatusCompleted = "COMPLETED" // Все переводы выполнены
+	BulkTransferStatusPartial   = "PARTIAL"   // Часть переводов выполнена
+	BulkTransferStatusFailed    = "FAILED"    // Ни один перевод не выполнен
+)
+
+// Статусы перевода внутри пакета.
+const (
+	BulkTransferItemCompleted = "COMPLETED"
+	BulkTransferItemFailed    = "FAILED"
+)
+
+// BulkTransferCommand - пакет переводов с одного кошелька.
+//
+// Каждый перевод выполняется независимо: ошибка одного не откатывает
+// другие. Повтор того же пакета пропускает уже выполненные переводы
+// по их idempotency_key.
+type BulkTransferCommand struct {
+	SourceWalletID string             `json:"source_wallet_id" validate:"required,uuid"`
+	Transfers      []BulkTransferItem `json:"transfers" validate:"required,min=1,dive"`
+}
+
+// BulkTransferItem - один перевод пакета.
+type BulkTransferItem struct {
+	DestinationWalletID string `json:"destination_wallet_id" validate:"required,uuid"`
+	Amount              string `json:"amount,omitempty"`
+	AmountMinor         *int64 `json:"amount_minor,omitempty"`
+	IdempotencyKey      string `json:"idempotency_key" validate:"required,uuid"`
+	Reference           string `json:"reference" validate:"required"` // Описание перевода
+	ConfirmNewPayee     bool   `json:"confirm_new_payee"`             // подтверждение первого перевода новому получателю
+}
+
+// BulkTransferResultDTO - отчёт о выполнении пакета.
+type BulkTransferResultDTO struct {
+	SourceWallet WalletDTO                   `json:"source_wallet"` // Состояние после пакета
+	Status       string                      `json:"status"`        // COMPLETED, PARTIAL или FAILED
+	Totals       BulkTransferTotalsDTO       `json:"totals"`
+	Items        []BulkTransferItemResultDTO `json:"items"` // В порядке команды
+}
+
+// BulkTransferTotalsDTO - агрегаты пакета.
+type BulkTransferTotalsDTO struct {
+	Requested       int    `json:"requested"`
+	Completed       int    `json:"completed"` // Включая выполненные ранее (idempotent_replay)
+	Failed          int    `json:"failed"`
+	CompletedAmount string `json:"completed_amount"` // Сумма выполненных переводов (gross)
+}
+
+// BulkTransferItemResultDTO - результат одного перевода пакета.
+type BulkTransferItemResultDTO struct {
+	Index               int    `json:"index"`
+	DestinationWalletID string `json:"destination_wallet_id"`
+	IdempotencyKey      string `json:"idempotency_key"`
+	Status              string `json:"status"` // COMPLETED или FAILED
+	TransactionID       string `json:"transaction_id,omitempty"`
+	Amount              string `json:"amount,omitempty"` // = gross_amount перевода
+	FeeAmount           string `json:"fee_amount,omitempty"`
+	IdempotentReplay    bool   `json:"idempotent_replay,omitempty"` // Выполнен предыдущей отправкой пакета
+	ErrorCode           string `json:"error_code,omitempty"`        // Код ошибки API: INSUFFICIENT_BALANCE, WALLET_NOT_FOUND, правило бизнес-ошибки
+	ErrorMessage        string `json:"error_message,omitempty"`
+}
@@ -0,0 +1,58 @@
+package dtos
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ============================================
+// Queries
+// ============================================
+
+// ListFailedRequestsQuery - запрос образцов неудачных денежных операций (admin).
+type ListFailedRequestsQuery struct {
+	UserID *string `json:"user_id,omitempty" validate:"omitempty,uuid"`
+	Route  *string `json:"route,omitempty"` // Шаблон маршрута: /api/v1/wallets/{id}/debit
+
+	// Диапазон occurred_at [from, to), UTC
+	OccurredFrom *time.Time `json:"occurred_from,omitempty"`
+	OccurredTo   *time.Time `json:"occurred_to,omitempty"`
+
+	Offset int `json:"offset" validate:"min=0"`
+	Limit  int `json:"limit" validate:"min=1,max=100"`
+}
+
+// ============================================
+// Results
+// ============================================
+
+// FailedRequestDTO - образец неудачного запроса. Тела уже очищены от
+// чувствительных данных; JSON тело отдаётся как есть, иначе - строкой.
+type FailedRequestDTO struct {
+	ID           string          `json:"id"`
+	Method       string          `json:"method"`
+	Route        string          `json:"route"`
+	StatusCode   int             `json:"status_code"`
+	RequestID    string          `json:"request_id,omitempty"`
+	UserID       string          `json:"user_id,omitempty"` // пусто - запрос без аутентификации
+	RequestBody  json.RawMessage `json:"request_body,omitempty"`
+	ResponseBody json.RawMessage `json:"response_body,omitempty"`
+	Truncated    bool            `json:"truncated"` // тело обрезано по лимиту размера
+	OccurredAt   time.Time       `json:"occurred_at"`
+}
+
+// FailedRequestListDTO - страница образцов, новые первыми.
+type FailedRequestListDTO = Page[FailedRequestDTO]
+
+// capturedBody - тело образца для ответа: валидный JSON без изменений,
+// остальное (обрезанный JSON, текст) - JSON строкой.
+func capturedBody(body string) json.RawMessage {
+	if body == "" {
+		return nil
+	}
+	if json.Valid([]byte(body)) {
+		return json.RawMessage(body)
+	}
+	encoded, _ := json.Marshal(body)
+	return encoded
+}
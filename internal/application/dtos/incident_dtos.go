@@ -0,0 +1,40 @@
+package dtos
+
+import "time"
+
+// Инциденты показываются на публичной странице статуса (GET /status) без
+// created_by/resolved_by; IncidentDTO с ними отдаётся только администраторам.
+
+// ============================================
+// Commands
+// ============================================
+
+// CreateIncidentCommand - открыть инцидент на странице статуса (admin).
+// Инициатор берётся из context (ports.ActorFromContext).
+type CreateIncidentCommand struct {
+	Title      string     `json:"title" validate:"required,max=200"`
+	Severity   string     `json:"severity" validate:"required,oneof=MINOR MAJOR CRITICAL"`
+	Components []string   `json:"components" validate:"required,min=1,dive,oneof=api database payouts deposits"`
+	StartedAt  *time.Time `json:"started_at,omitempty"` // nil - сейчас
+}
+
+// ResolveIncidentCommand - отметить инцидент решённым (admin).
+type ResolveIncidentCommand struct {
+	IncidentID string `json:"incident_id" validate:"required,uuid"`
+}
+
+// ============================================
+// Results
+// ============================================
+
+// IncidentDTO - инцидент страницы статуса с данными аудита.
+type IncidentDTO struct {
+	ID         string     `json:"id"`
+	Title      string     `json:"title"`
+	Severity   string     `json:"severity"`
+	Components []string   `json:"components"`
+	StartedAt  time.Time  `json:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedBy  string     `json:"created_by"`
+	ResolvedBy string     `json:"resolved_by,omitempty"`
+}
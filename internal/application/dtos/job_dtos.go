@@ -0,0 +1,48 @@
+package dtos
+
+import "time"
+
+// ============================================
+// Queries / Commands
+// ============================================
+
+// ListJobsQuery - фоновые задачи и их последние прогоны (admin).
+type ListJobsQuery struct{}
+
+// RunJobCommand - запуск фоновой задачи вне расписания (admin).
+type RunJobCommand struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// ============================================
+// Results
+// ============================================
+
+// JobRunDTO - один прогон фоновой задачи.
+type JobRunDTO struct {
+	ID             string     `json:"id"`
+	Job            string     `json:"job"`
+	Trigger        string     `json:"trigger"` // SCHEDULE, MANUAL
+	Instance       string     `json:"instance"`
+	Status         string     `json:"status"` // RUNNING, SUCCEEDED, FAILED
+	Error          string     `json:"error,omitempty"`
+	ItemsProcessed int        `json:"items_processed"`
+	StartedAt      time.Time  `json:"started_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+}
+
+// JobDTO - зарегистрированная фоновая задача.
+type JobDTO struct {
+	Name           string      `json:"name"`
+	Schedule       string      `json:"schedule"`
+	TimeoutSeconds int64       `json:"timeout_seconds"`
+	NextRunAt      *time.Time  `json:"next_run_at,omitempty"` // пусто - планировщик не запущен
+	RecentRuns     []JobRunDTO `json:"recent_runs"`
+}
+
+// JobListDTO - список фоновых задач в порядке регистрации.
+type JobListDTO struct {
+	Enabled    bool     `json:"enabled"`
+	Jobs       []JobDTO `json:"jobs"`
+	TotalCount int      `json:"total_count"`
+}
@@ -10,6 +10,9 @@ package dtos
 
 import (
 	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/Haleralex/wallethub/internal/domain/entities"
 )
@@ -20,14 +23,154 @@ import (
 
 // ToUserDTO конвертирует domain entity User в DTO.
 func ToUserDTO(user *entities.User) UserDTO {
+	terms := user.TermsAcceptance()
 	return UserDTO{
-		ID:        user.ID().String(),
-		Email:     user.Email(),
-		FullName:  user.FullName(),
-		KYCStatus: string(user.KYCStatus()),
-		CreatedAt: user.CreatedAt(),
-		UpdatedAt: user.UpdatedAt(),
+		ID:           user.ID().String(),
+		Email:        user.Email(),
+		FullName:     user.FullName(),
+		KYCStatus:    string(user.KYCStatus()),
+		Jurisdiction: user.Jurisdiction(),
+		CreatedAt:    user.CreatedAt().UTC(),
+		UpdatedAt:    user.UpdatedAt().UTC(),
+
+		LastKYCRejectionReason: user.LastKYCRejectionReason(),
+
+		TermsVersion:       terms.Version,
+		TermsAcceptedAt:    terms.AcceptedAt,
+		TermsGrandfathered: terms.Grandfathered,
+	}
+}
+
+// ToKYCTransitionDTO конвертирует запись истории KYC в DTO.
+func ToKYCTransitionDTO(transition *entities.KYCTransition) KYCTransitionDTO {
+	return KYCTransitionDTO{
+		FromStatus: string(transition.FromStatus()),
+		ToStatus:   string(transition.ToStatus()),
+		Reason:     transition.Reason(),
+		ActorID:    transition.ActorID().String(),
+		OccurredAt: transition.OccurredAt().UTC(),
+	}
+}
+
+// ToSecurityEventDTO конвертирует запись журнала безопасности в DTO.
+func ToSecurityEventDTO(event *entities.SecurityEvent) SecurityEventDTO {
+	return SecurityEventDTO{
+		ID:         event.ID().String(),
+		Principal:  event.Principal(),
+		Type:       string(event.Type()),
+		IP:         event.IP(),
+		UserAgent:  event.UserAgent(),
+		OccurredAt: event.OccurredAt().UTC(),
+	}
+}
+
+// ToFailedRequestDTO конвертирует образец неудачного запроса в DTO.
+func ToFailedRequestDTO(sample *entities.FailedRequest) FailedRequestDTO {
+	dto := FailedRequestDTO{
+		ID:           sample.ID().String(),
+		Method:       sample.Method(),
+		Route:        sample.Route(),
+		StatusCode:   sample.StatusCode(),
+		RequestID:    sample.RequestID(),
+		RequestBody:  capturedBody(sample.RequestBody()),
+		ResponseBody: capturedBody(sample.ResponseBody()),
+		Truncated:    sample.Truncated(),
+		OccurredAt:   sample.OccurredAt().UTC(),
+	}
+	if actorID := sample.ActorID(); actorID != uuid.Nil {
+		dto.UserID = actorID.String()
+	}
+	return dto
+}
+
+// ToWalletNoteDTO конвертирует заметку поддержки в DTO.
+func ToWalletNoteDTO(note *entities.WalletNote) WalletNoteDTO {
+	return WalletNoteDTO{
+		ID:        note.ID().String(),
+		WalletID:  note.WalletID().String(),
+		AuthorID:  note.AuthorID().String(),
+		Body:      note.Body(),
+		Pinned:    note.Pinned(),
+		CreatedAt: note.CreatedAt().UTC(),
+	}
+}
+
+// ToWalletSettingsDTO конвертирует настройки представления кошелька в DTO.
+func ToWalletSettingsDTO(settings *entities.WalletSettings) WalletSettingsDTO {
+	return WalletSettingsDTO{
+		WalletID:                    settings.WalletID().String(),
+		IncomingDescriptionTemplate: settings.IncomingDescriptionTemplate(),
+		AutoTags:                    settings.AutoTags(),
+		DisplayCurrency:             settings.DisplayCurrency(),
+		UpdatedAt:                   settings.UpdatedAt().UTC(),
+	}
+}
+
+// ToOperationSwitchDTO конвертирует entities.OperationSwitch в DTO.
+func ToOperationSwitchDTO(sw *entities.OperationSwitch) OperationSwitchDTO {
+	return OperationSwitchDTO{
+		Type:      string(sw.Operation()),
+		Enabled:   sw.Enabled(),
+		Reason:    sw.Reason(),
+		UpdatedBy: sw.UpdatedBy().String(),
+		UpdatedAt: sw.UpdatedAt().UTC(),
+	}
+}
+
+// ToIncidentDTO конвертирует entities.Incident в DTO.
+func ToIncidentDTO(incident *entities.Incident) IncidentDTO {
+	dto := IncidentDTO{
+		ID:         incident.ID().String(),
+		Title:      incident.Title(),
+		Severity:   string(incident.Severity()),
+		Components: make([]string, 0, len(incident.Components())),
+		StartedAt:  incident.StartedAt().UTC(),
+		ResolvedAt: incident.ResolvedAt(),
+		CreatedBy:  incident.CreatedBy().String(),
+	}
+	for _, component := range incident.Components() {
+		dto.Components = append(dto.Components, string(component))
+	}
+	if resolvedBy := incident.ResolvedBy(); resolvedBy != nil {
+		dto.ResolvedBy = resolvedBy.String()
+	}
+	return dto
+}
+
+// ToWebhookSubscriptionDTO конвертирует webhook подписку в DTO без секрета.
+func ToWebhookSubscriptionDTO(subscription *entities.WebhookSubscription) WebhookSubscriptionDTO {
+	return WebhookSubscriptionDTO{
+		ID:                  subscription.ID().String(),
+		URL:                 subscription.URL(),
+		EventTypes:          append([]string{}, subscription.EventTypes()...),
+		Status:              string(subscription.Status()),
+		ConsecutiveFailures: subscription.ConsecutiveFailures(),
+		FailingSince:        utcTime(subscription.FailingSince()),
+		LastFailureReason:   subscription.LastFailureReason(),
+		DisabledAt:          utcTime(subscription.DisabledAt()),
+		CreatedAt:           subscription.CreatedAt().UTC(),
+		UpdatedAt:           subscription.UpdatedAt().UTC(),
+	}
+}
+
+// ToWebhookDeliveryDTO конвертирует доставку webhook в DTO.
+func ToWebhookDeliveryDTO(delivery *entities.WebhookDelivery) WebhookDeliveryDTO {
+	dto := WebhookDeliveryDTO{
+		ID:             delivery.ID().String(),
+		EventID:        delivery.EventID().String(),
+		EventType:      delivery.EventType(),
+		Status:         string(delivery.Status()),
+		Attempts:       delivery.Attempts(),
+		LastError:      delivery.LastError(),
+		LastStatusCode: delivery.LastStatusCode(),
+		CreatedAt:      delivery.CreatedAt().UTC(),
+		FinishedAt:     utcTime(delivery.FinishedAt()),
+	}
+	if delivery.IsPending() {
+		next := delivery.NextAttemptAt().UTC()
+		dto.NextAttemptAt = &next
 	}
+	return dto
 }
 
 // ToUserDTOList конвертирует список users.
@@ -58,8 +201,12 @@ func ToWalletDTO(wallet *entities.Wallet) WalletDTO {
 		TotalBalance:     totalBalance.String(),
 		DailyLimit:       wallet.DailyLimit().String(),
 		MonthlyLimit:     wallet.MonthlyLimit().String(),
-		CreatedAt:        wallet.CreatedAt(),
-		UpdatedAt:        wallet.UpdatedAt(),
+		BalanceVersion:   wallet.BalanceVersion(),
+		Jurisdiction:     wallet.Jurisdiction(),
+		CreatedAt:        wallet.CreatedAt().UTC(),
+		UpdatedAt:        wallet.UpdatedAt().UTC(),
+
+		MaxPendingTransactions: wallet.MaxPendingTransactions(),
 	}
 }
 
@@ -85,14 +232,23 @@ func ToTransactionDTO(tx *entities.Transaction) TransactionDTO {
 		Type:              string(tx.Type()),
 		Status:            string(tx.Status()),
 		Amount:            tx.Amount().String(),
+		GrossAmount:       tx.GrossAmount().String(),
+		FeeAmount:         tx.FeeAmount().String(),
+		NetAmount:         tx.NetAmount().String(),
+		FeeMode:           string(tx.FeeMode()),
 		CurrencyCode:      tx.Amount().Currency().Code(),
 		ExternalReference: tx.ExternalReference(),
 		Description:       tx.Description(),
 		Metadata:          convertMetadataToStringMap(tx.Metadata()),
 		FailureReason:     tx.FailureReason(),
+		FailureMessageKey: entities.FailureMessageKey(tx.FailureReason()),
+		FailureCategory:   string(tx.FailureCategory()),
 		RetryCount:        tx.RetryCount(),
-		CreatedAt:         tx.CreatedAt(),
-		UpdatedAt:         tx.UpdatedAt(),
+		MaxRetries:        entities.DefaultMaxRetries,
+		IsRetryable:       tx.CanRetry(entities.DefaultMaxRetries) && tx.IsRetryable(),
+		Jurisdiction:      tx.Jurisdiction(),
+		CreatedAt:         tx.CreatedAt().UTC(),
+		UpdatedAt:         tx.UpdatedAt().UTC(),
 	}
 
 	// Optional fields
@@ -101,12 +257,49 @@ func ToTransactionDTO(tx *entities.Transaction) TransactionDTO {
 		dto.DestinationWalletID = &destStr
 	}
 
+	if version := tx.CreatedByVersion(); version != "" {
+		dto.CreatedByVersion = &version
+	}
+
 	if processedAt := tx.ProcessedAt(); processedAt != nil {
-		dto.ProcessedAt = processedAt
+		dto.ProcessedAt = utcTime(processedAt)
+	}
+
+	if completedAt := tx.CompletedAt(); completedAt != nil {
+		dto.CompletedAt = utcTime(completedAt)
+	}
+
+	dto.ReceiptNumber = tx.ReceiptNumber()
+
+	if nextRetryAt := tx.NextRetryAt(); nextRetryAt != nil && dto.IsRetryable {
+		dto.NextRetryAt = utcTime(nextRetryAt)
+	}
+
+	return dto
+}
+
+// ToReceiptDTO конвертирует завершённую транзакцию с номером чека в ReceiptDTO.
+func ToReceiptDTO(tx *entities.Transaction) ReceiptDTO {
+	dto := ReceiptDTO{
+		ReceiptNumber:    tx.ReceiptNumber(),
+		TransactionID:    tx.ID().String(),
+		Type:             string(tx.Type()),
+		WalletID:         tx.WalletID().String(),
+		GrossAmount:      tx.GrossAmount().String(),
+		FeeAmount:        tx.FeeAmount().String(),
+		NetAmount:        tx.NetAmount().String(),
+		CurrencyCode:     tx.Amount().Currency().Code(),
+		Description:      tx.Description(),
+		VerificationHash: tx.ReceiptVerificationHash(),
+	}
+
+	if destWalletID := tx.DestinationWalletID(); destWalletID != nil {
+		destStr := destWalletID.String()
+		dto.DestinationWalletID = &destStr
 	}
 
 	if completedAt := tx.CompletedAt(); completedAt != nil {
-		dto.CompletedAt = completedAt
+		dto.CompletedAt = completedAt.UTC()
 	}
 
 	return dto
@@ -131,6 +324,16 @@ func MapTransactionToDTO(tx *entities.Transaction) *TransactionDTO {
 // Helper functions
 // ============================================
 
+// utcTime возвращает копию времени в UTC (nil для nil).
+// Все timestamps в API отдаются в UTC (RFC3339 с суффиксом Z).
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
 // convertMetadataToStringMap конвертирует map[string]interface{} в map[string]string.
 // Для упрощения JSON сериализации.
 func convertMetadataToStringMap(metadata map[string]interface{}) map[string]string {
@@ -1,6 +1,7 @@
 package dtos
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -67,6 +68,7 @@ func TestToWalletDTO(t *testing.T) {
 	assert.Equal(t, "0.00 USD", dto.AvailableBalance)
 	assert.Equal(t, "0.00 USD", dto.PendingBalance)
 	assert.Equal(t, "0.00 USD", dto.TotalBalance)
+	assert.Equal(t, int64(0), dto.BalanceVersion)
 	assert.False(t, dto.CreatedAt.IsZero())
 }
 
@@ -89,6 +91,7 @@ func TestToWalletDTO_WithBalance(t *testing.T) {
 
 	assert.Contains(t, dto.AvailableBalance, "100.00")
 	assert.Contains(t, dto.TotalBalance, "100.00")
+	assert.Equal(t, wallet.BalanceVersion(), dto.BalanceVersion)
 }
 
 func TestToWalletDTO_CryptoWallet(t *testing.T) {
@@ -156,12 +159,118 @@ func TestToTransactionDTO(t *testing.T) {
 	assert.Equal(t, "DEPOSIT", dto.Type)
 	assert.Equal(t, "PENDING", dto.Status)
 	assert.Contains(t, dto.Amount, "50.00")
+	assert.Equal(t, dto.Amount, dto.GrossAmount)
+	assert.Equal(t, dto.Amount, dto.NetAmount, "no fee: net == gross")
+	assert.Contains(t, dto.FeeAmount, "0.00")
+	assert.Empty(t, dto.FeeMode)
 	assert.Equal(t, "USD", dto.CurrencyCode)
 	assert.Equal(t, "Test deposit", dto.Description)
 	assert.Equal(t, 0, dto.RetryCount)
 	assert.Nil(t, dto.DestinationWalletID)
 	assert.Nil(t, dto.ProcessedAt)
 	assert.Nil(t, dto.CompletedAt)
+	assert.Nil(t, dto.CreatedByVersion)
+}
+
+func TestToTransactionDTO_Retry(t *testing.T) {
+	amount, err := valueobjects.NewMoney("50.00", valueobjects.USD)
+	require.NoError(t, err)
+	tx, err := entities.NewTransaction(uuid.New(), "idem-key-retry", entities.TransactionTypeWithdraw, amount, "")
+	require.NoError(t, err)
+
+	dto := ToTransactionDTO(tx)
+	assert.Equal(t, entities.DefaultMaxRetries, dto.MaxRetries)
+	assert.False(t, dto.IsRetryable, "pending transaction is not retryable")
+
+	require.NoError(t, tx.MarkFailed("TIMEOUT", entities.FailureCategoryProvider))
+	dto = ToTransactionDTO(tx)
+	assert.True(t, dto.IsRetryable)
+	assert.Nil(t, dto.NextRetryAt, "first retry is due immediately")
+
+	require.NoError(t, tx.Retry(entities.DefaultMaxRetries))
+	require.NoError(t, tx.MarkFailed("TIMEOUT", entities.FailureCategoryProvider))
+	dto = ToTransactionDTO(tx)
+	assert.True(t, dto.IsRetryable)
+	assert.Equal(t, 1, dto.RetryCount)
+	require.NotNil(t, dto.NextRetryAt)
+	assert.Equal(t, time.UTC, dto.NextRetryAt.Location())
+
+	// Окончательная причина: повтор не выполняется, время не показывается
+	require.NoError(t, tx.Retry(entities.DefaultMaxRetries))
+	require.NoError(t, tx.MarkFailed("INSUFFICIENT_BALANCE", entities.FailureCategoryProvider))
+	dto = ToTransactionDTO(tx)
+	assert.False(t, dto.IsRetryable)
+	assert.Nil(t, dto.NextRetryAt)
+}
+
+func TestToTransactionDTO_CreatedByVersion(t *testing.T) {
+	amount, err := valueobjects.NewMoneyFromCents(5000, valueobjects.USD)
+	require.NoError(t, err)
+
+	tx, err := entities.NewTransaction(uuid.New(), "idem-key-version", entities.TransactionTypeDeposit, amount, "")
+	require.NoError(t, err)
+
+	// Старые транзакции без штампа - явный null, а не отсутствующее поле
+	data, err := json.Marshal(ToTransactionDTO(tx))
+	require.NoError(t, err)
+	var wire map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &wire))
+	value, present := wire["created_by_version"]
+	assert.True(t, present)
+	assert.Nil(t, value)
+
+	require.NoError(t, tx.StampCreatedByVersion("1.4.0+abc1234"))
+	dto := ToTransactionDTO(tx)
+	require.NotNil(t, dto.CreatedByVersion)
+	assert.Equal(t, "1.4.0+abc1234", *dto.CreatedByVersion)
+}
+
+func TestToTransactionDTO_WireFormatUTC(t *testing.T) {
+	currency, err := valueobjects.NewCurrency("USD")
+	require.NoError(t, err)
+	amount, err := valueobjects.NewMoneyFromCents(5000, currency)
+	require.NoError(t, err)
+
+	// Время в зоне клиента/сервера - на выходе всегда UTC с суффиксом Z
+	moscow := time.FixedZone("MSK", 3*60*60)
+	createdAt := time.Date(2024, 3, 10, 12, 30, 0, 0, moscow)
+	completedAt := createdAt.Add(90 * time.Second)
+
+	tx, err := entities.ReconstructTransaction(
+		uuid.New(), uuid.New(), "idem-key-utc",
+		entities.TransactionTypeDeposit, entities.TransactionStatusCompleted,
+		amount, valueobjects.Zero(currency), amount,
+		nil, "", "", "", nil, "", "", 0, nil, "", "",
+		createdAt, completedAt, &completedAt, &completedAt,
+		"",
+	)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(ToTransactionDTO(tx))
+	require.NoError(t, err)
+
+	var wire map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &wire))
+	assert.Equal(t, "2024-03-10T09:30:00Z", wire["created_at"])
+	assert.Equal(t, "2024-03-10T09:31:30Z", wire["updated_at"])
+	assert.Equal(t, "2024-03-10T09:31:30Z", wire["processed_at"])
+	assert.Equal(t, "2024-03-10T09:31:30Z", wire["completed_at"])
+}
+
+func TestToUserDTO_WireFormatUTC(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	createdAt := time.Date(2024, 1, 1, 8, 0, 0, 123000000, tokyo)
+
+	user := entities.ReconstructUser(uuid.New(), "utc@example.com", "UTC User",
+		entities.KYCStatusVerified, nil, "", "", entities.TermsAcceptance{}, createdAt, createdAt)
+
+	data, err := json.Marshal(ToUserDTO(user))
+	require.NoError(t, err)
+
+	var wire map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &wire))
+	assert.Equal(t, "2023-12-31T23:00:00.123Z", wire["created_at"])
+	assert.Equal(t, "2023-12-31T23:00:00.123Z", wire["updated_at"])
 }
 
 func TestToTransactionDTO_WithDestinationWallet(t *testing.T) {
@@ -242,7 +351,7 @@ func TestToTransactionDTO_FailedTransaction(t *testing.T) {
 	err = tx.StartProcessing()
 	require.NoError(t, err)
 
-	err = tx.MarkFailed("Insufficient funds")
+	err = tx.MarkFailed("Insufficient funds", entities.FailureCategoryProvider)
 	require.NoError(t, err)
 
 	dto := ToTransactionDTO(tx)
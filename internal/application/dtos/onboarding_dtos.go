@@ -0,0 +1,44 @@
+// Package dtos - DTOs onboarding партнёрами: пользователь, кошельки и
+// начальные зачисления одним запросом.
+package dtos
+
+import "time"
+
+// OnboardUserCommand - команда onboarding.
+//
+// Выполняется целиком в одной UnitOfWork: ошибка любого шага откатывает
+// пользователя, кошельки и зачисления. Повтор с тем же IdempotencyKey
+// возвращает первый результат.
+//
+// TrustedPartnerScope заполняет handler из capability токена, не из тела
+// запроса.
+type OnboardUserCommand struct {
+	IdempotencyKey string                 `json:"idempotency_key" validate:"required,uuid"`
+	Email          string                 `json:"email" validate:"required,email"`
+	FullName       string                 `json:"full_name" validate:"required,min=2,max=100"`
+	Jurisdiction   string                 `json:"jurisdiction,omitempty" validate:"omitempty,len=2"`
+	AutoApproveKYC bool                   `json:"auto_approve_kyc"` // только для доверенных партнёров
+	Wallets        []OnboardingWalletItem `json:"wallets,omitempty" validate:"dive"`
+
+	TrustedPartnerScope bool `json:"-"`
+}
+
+// OnboardingWalletItem - кошелёк onboarding и необязательное начальное зачисление.
+type OnboardingWalletItem struct {
+	CurrencyCode  string `json:"currency_code" validate:"required,len=3"`
+	InitialCredit string `json:"initial_credit,omitempty"` // Decimal string; пусто - без зачисления
+}
+
+// OnboardingResultDTO - результат onboarding.
+//
+// Wallets - в порядке валют команды, Transactions - начальные зачисления
+// в том же порядке. При IdempotentReplay пользователь и кошельки - в
+// текущем состоянии, CreatedAt - время исходного onboarding.
+type OnboardingResultDTO struct {
+	User             UserDTO          `json:"user"`
+	Wallets          []WalletDTO      `json:"wallets"`
+	Transactions     []TransactionDTO `json:"transactions"`
+	KYCAutoApproved  bool             `json:"kyc_auto_approved"`
+	CreatedAt        time.Time        `json:"created_at"`
+	IdempotentReplay bool             `json:"idempotent_replay"`
+}
@@ -0,0 +1,28 @@
+package dtos
+
+import "time"
+
+// ============================================
+// Commands
+// ============================================
+
+// SetOperationSwitchCommand - включить или выключить вид операций (admin).
+// Выключение требует причины; инициатор берётся из context (ports.ActorFromContext).
+type SetOperationSwitchCommand struct {
+	Type    string `json:"type" validate:"required,oneof=DEPOSIT WITHDRAW TRANSFER PAYOUT"`
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason" validate:"max=500"`
+}
+
+// ============================================
+// Results
+// ============================================
+
+// OperationSwitchDTO - состояние выключателя вида операций.
+type OperationSwitchDTO struct {
+	Type      string    `json:"type"`
+	Enabled   bool      `json:"enabled"`
+	Reason    string    `json:"reason,omitempty"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,90 @@
+// Package dtos - единый конверт страницы для list endpoints.
+package dtos
+
+// PaginationMode - способ перехода к следующей странице.
+type PaginationMode string
+
+const (
+	// PaginationModeOffset - следующая страница по offset/page.
+	PaginationModeOffset PaginationMode = "offset"
+	// PaginationModeCursor - следующая страница по next_cursor.
+	PaginationModeCursor PaginationMode = "cursor"
+)
+
+// PaginationDTO - описание страницы в ответе списка.
+//
+// Offset заполняется только в режиме offset, NextCursor - только в режиме
+// cursor при HasMore. TotalCount опционален: endpoint заполняет его, если
+// умеет дёшево посчитать (WithTotalCount).
+type PaginationDTO struct {
+	Mode       PaginationMode `json:"mode"`
+	TotalCount *int           `json:"total_count,omitempty"`
+	Offset     *int           `json:"offset,omitempty"`
+	Limit      int            `json:"limit"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	HasMore    bool           `json:"has_more"`
+}
+
+// Page - страница списка: элементы и описание пагинации.
+// HTTP слой кладёт Data в data ответа, а Pagination - рядом с ним.
+type Page[T any] struct {
+	Data       []T           `json:"data"`
+	Pagination PaginationDTO `json:"pagination"`
+}
+
+// FetchLimit - сколько строк запрашивать у хранилища для страницы limit:
+// лишняя строка показывает, есть ли следующая страница, без COUNT(*).
+func FetchLimit(limit int) int {
+	return limit + 1
+}
+
+// NewOffsetPage собирает offset-страницу из rows, прочитанных с FetchLimit(limit).
+func NewOffsetPage[T any](rows []T, offset, limit int) *Page[T] {
+	data, hasMore := trimPage(rows, limit)
+	return &Page[T]{
+		Data: data,
+		Pagination: PaginationDTO{
+			Mode:    PaginationModeOffset,
+			Offset:  &offset,
+			Limit:   limit,
+			HasMore: hasMore,
+		},
+	}
+}
+
+// NewCursorPage собирает cursor-страницу из rows, прочитанных с FetchLimit(limit).
+// cursor строит next_cursor по последнему элементу страницы.
+func NewCursorPage[T any](rows []T, limit int, cursor func(last T) string) *Page[T] {
+	data, hasMore := trimPage(rows, limit)
+	page := &Page[T]{
+		Data: data,
+		Pagination: PaginationDTO{
+			Mode:    PaginationModeCursor,
+			Limit:   limit,
+			HasMore: hasMore,
+		},
+	}
+	if hasMore {
+		page.Pagination.NextCursor = cursor(data[len(data)-1])
+	}
+	return page
+}
+
+// WithTotalCount добавляет в страницу общее число элементов.
+func (p *Page[T]) WithTotalCount(total int) *Page[T] {
+	p.Pagination.TotalCount = &total
+	return p
+}
+
+// trimPage отрезает лишнюю строку FetchLimit. Пустой список остаётся
+// [] (а не null) в JSON.
+func trimPage[T any](rows []T, limit int) ([]T, bool) {
+	hasMore := limit > 0 && len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	if rows == nil {
+		rows = []T{}
+	}
+	return rows, hasMore
+}
@@ -0,0 +1,43 @@
+package dtos
+
+import "testing"
+
+func TestNewOffsetPage(t *testing.T) {
+	page := NewOffsetPage([]int{1, 2, 3}, 4, 2)
+	if len(page.Data) != 2 || !page.Pagination.HasMore {
+		t.Fatalf("Expected 2 items with has_more, got %v has_more=%v", page.Data, page.Pagination.HasMore)
+	}
+	if page.Pagination.Mode != PaginationModeOffset || *page.Pagination.Offset != 4 || page.Pagination.Limit != 2 {
+		t.Fatalf("Unexpected pagination: %+v", page.Pagination)
+	}
+
+	last := NewOffsetPage([]int{1, 2}, 0, 2)
+	if len(last.Data) != 2 || last.Pagination.HasMore {
+		t.Fatalf("Expected last page without has_more, got %+v", last.Pagination)
+	}
+
+	empty := NewOffsetPage[int](nil, 0, 2)
+	if empty.Data == nil || empty.Pagination.TotalCount != nil {
+		t.Fatalf("Expected empty non-nil data without total_count, got %+v", empty)
+	}
+	if total := *empty.WithTotalCount(0).Pagination.TotalCount; total != 0 {
+		t.Fatalf("Expected total_count 0, got %d", total)
+	}
+}
+
+func TestNewCursorPage(t *testing.T) {
+	cursor := func(last string) string { return "after:" + last }
+
+	page := NewCursorPage([]string{"a", "b", "c"}, 2, cursor)
+	if len(page.Data) != 2 || !page.Pagination.HasMore || page.Pagination.NextCursor != "after:b" {
+		t.Fatalf("Unexpected cursor page: %+v", page)
+	}
+	if page.Pagination.Offset != nil {
+		t.Fatalf("Expected no offset in cursor mode, got %d", *page.Pagination.Offset)
+	}
+
+	last := NewCursorPage([]string{"a"}, 2, cursor)
+	if last.Pagination.HasMore || last.Pagination.NextCursor != "" {
+		t.Fatalf("Expected last cursor page without next_cursor, got %+v", last.Pagination)
+	}
+}
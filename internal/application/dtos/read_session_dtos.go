@@ -0,0 +1,25 @@
+package dtos
+
+import "time"
+
+// ============================================
+// Commands
+// ============================================
+
+// OpenReadSessionCommand - открытие snapshot-сессии чтения для сверки.
+// Клиент - аутентифицированный пользователь (actor из context).
+type OpenReadSessionCommand struct{}
+
+// ============================================
+// Results
+// ============================================
+
+// ReadSessionDTO - открытая сессия чтения. Token передаётся в заголовке
+// X-Read-Session GET запросов, которые должны читать из её snapshot.
+type ReadSessionDTO struct {
+	Token      string    `json:"token"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	TTLSeconds int       `json:"ttl_seconds"`
+	Isolation  string    `json:"isolation"` // Всегда "REPEATABLE READ"
+	Source     string    `json:"source"`    // "replica" или "primary"
+}
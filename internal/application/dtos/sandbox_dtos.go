@@ -0,0 +1,35 @@
+package dtos
+
+import "time"
+
+// ============================================
+// Commands
+// ============================================
+
+// ResetSandboxCommand - команда сброса sandbox данных tenant'а.
+// Tenant - аутентифицированный пользователь (actor из context).
+//
+// Первый вызов без токена возвращает ConfirmationToken, второй вызов
+// должен передать его обратно - только тогда данные удаляются.
+type ResetSandboxCommand struct {
+	ConfirmationToken string `json:"confirmation_token,omitempty"`
+}
+
+// ============================================
+// Results
+// ============================================
+
+// Статусы сброса sandbox.
+const (
+	SandboxResetConfirmationRequired = "confirmation_required"
+	SandboxResetCompleted            = "completed"
+)
+
+// SandboxResetDTO - результат шага сброса sandbox.
+type SandboxResetDTO struct {
+	Status            string           `json:"status"`
+	ConfirmationToken string           `json:"confirmation_token,omitempty"`
+	ExpiresAt         *time.Time       `json:"expires_at,omitempty"`
+	Deleted           map[string]int64 `json:"deleted,omitempty"` // Число удалённых строк по таблицам
+	ResetAt           *time.Time       `json:"reset_at,omitempty"`
+}
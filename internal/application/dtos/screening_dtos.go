@@ -0,0 +1,61 @@
+package dtos
+
+// ============================================
+// Queries
+// ============================================
+
+// ListScreeningRulesQuery - активные правила скрининга (admin).
+type ListScreeningRulesQuery struct{}
+
+// DryRunScreeningQuery - проверка гипотетической транзакции правилами
+// скрининга без создания транзакции (admin).
+type DryRunScreeningQuery struct {
+	Type      string `json:"type" validate:"required"`
+	Amount    string `json:"amount" validate:"required"`   // В валюте кошелька
+	Currency  string `json:"currency" validate:"required"` // Валюта кошелька
+	WalletAge string `json:"wallet_age,omitempty"`         // Длительность ("36h"), пусто - только что создан
+	KYCStatus string `json:"kyc_status,omitempty"`         // Пусто - неизвестен
+	Country   string `json:"country,omitempty"`            // ISO 3166-1 alpha-2, пусто - неизвестна
+}
+
+// ============================================
+// Results
+// ============================================
+
+// Решения скрининга.
+const (
+	ScreeningDecisionAllow = "ALLOW"
+	ScreeningDecisionFlag  = "FLAG"
+	ScreeningDecisionBlock = "BLOCK"
+)
+
+// ScreeningConditionDTO - условие правила.
+type ScreeningConditionDTO struct {
+	Attribute string   `json:"attribute"`
+	Operator  string   `json:"operator"`
+	Value     string   `json:"value,omitempty"`
+	Values    []string `json:"values,omitempty"`
+}
+
+// ScreeningRuleDTO - правило скрининга в нормализованном виде.
+type ScreeningRuleDTO struct {
+	ID          string                  `json:"id"`
+	Description string                  `json:"description,omitempty"`
+	Action      string                  `json:"action"`
+	Conditions  []ScreeningConditionDTO `json:"conditions"`
+}
+
+// ScreeningRuleListDTO - активные правила в порядке применения.
+type ScreeningRuleListDTO struct {
+	Enabled    bool               `json:"enabled"` // false - скрининг выключен в конфигурации
+	Rules      []ScreeningRuleDTO `json:"rules"`
+	TotalCount int                `json:"total_count"`
+}
+
+// ScreeningDryRunDTO - решение по гипотетической транзакции.
+type ScreeningDryRunDTO struct {
+	Decision  string   `json:"decision"` // ALLOW, FLAG или BLOCK
+	BlockedBy string   `json:"blocked_by,omitempty"`
+	Flags     []string `json:"flags"`
+	Matched   []string `json:"matched"` // Все сработавшие правила в порядке применения
+}
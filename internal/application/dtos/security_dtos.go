@@ -0,0 +1,43 @@
+package dtos
+
+import "time"
+
+// ============================================
+// Queries
+// ============================================
+
+// ListSecurityEventsQuery - запрос журнала событий аутентификации (admin).
+type ListSecurityEventsQuery struct {
+	Principal *string `json:"principal,omitempty"`
+	Type      *string `json:"type,omitempty" validate:"omitempty,oneof=AUTH_FAILED AUTH_SUCCEEDED SCOPE_DENIED POLICY_DENIED"`
+	IP        *string `json:"ip,omitempty"`
+
+	// Диапазон occurred_at [from, to), UTC
+	OccurredFrom *time.Time `json:"occurred_from,omitempty"`
+	OccurredTo   *time.Time `json:"occurred_to,omitempty"`
+
+	Offset int `json:"offset" validate:"min=0"`
+	Limit  int `json:"limit" validate:"min=1,max=100"`
+}
+
+// ============================================
+// Results
+// ============================================
+
+// SecurityEventDTO - одна запись журнала безопасности.
+type SecurityEventDTO struct {
+	ID         string    `json:"id"`
+	Principal  string    `json:"principal,omitempty"` // user ID или key:<prefix>; пусто - без учётных данных
+	Type       string    `json:"type"`
+	IP         string    `json:"ip,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// SecurityEventListDTO - страница журнала, новые события первыми.
+type SecurityEventListDTO struct {
+	Events     []SecurityEventDTO `json:"events"`
+	TotalCount int                `json:"total_count"`
+	Offset     int                `json:"offset"`
+	Limit      int                `json:"limit"`
+}
@@ -0,0 +1,139 @@
+package dtos
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SnapshotFormatVersion - версия формата bundle. Импорт отклоняет другие версии.
+const SnapshotFormatVersion = 1
+
+// ============================================
+// Queries / Commands
+// ============================================
+
+// ExportSnapshotQuery - выгрузка пользователей с кошельками и транзакциями.
+type ExportSnapshotQuery struct {
+	UserIDs []string `json:"user_ids" validate:"required,min=1,dive,uuid"`
+
+	// Since - глубина истории транзакций; 0 - вся история
+	Since time.Duration `json:"since"`
+}
+
+// ImportSnapshotCommand - загрузка bundle в непроизводственное окружение.
+type ImportSnapshotCommand struct {
+	Bundle *SnapshotBundle `json:"bundle" validate:"required"`
+}
+
+// ============================================
+// Bundle
+// ============================================
+
+// SnapshotBundle - самодостаточная выгрузка: все кошельки, на которые
+// ссылаются транзакции, и все пользователи этих кошельков лежат в bundle.
+// Суммы - в минимальных единицах валюты (как в хранилище).
+type SnapshotBundle struct {
+	FormatVersion int        `json:"format_version"`
+	ExportedAt    time.Time  `json:"exported_at"`
+	Since         *time.Time `json:"since,omitempty"` // Транзакции с created_at >= since; раньше - свёрнуты в opening balance
+
+	Users        []SnapshotUser        `json:"users"`
+	Wallets      []SnapshotWallet      `json:"wallets"`
+	Transactions []SnapshotTransaction `json:"transactions"`
+}
+
+// SnapshotUser - пользователь с замаскированными PII (email, имя, Telegram ID).
+type SnapshotUser struct {
+	ID           string    `json:"id"`
+	Email        string    `json:"email"`
+	FullName     string    `json:"full_name"`
+	KYCStatus    string    `json:"kyc_status"`
+	Jurisdiction string    `json:"jurisdiction,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Принятая версия ToS; пустые поля у бандлов, выгруженных до её учёта
+	TermsVersion       int        `json:"tos_version,omitempty"`
+	TermsAcceptedAt    *time.Time `json:"tos_accepted_at,omitempty"`
+	TermsGrandfathered bool       `json:"tos_grandfathered,omitempty"`
+}
+
+// SnapshotWallet - кошелёк без баланса: при импорте баланс пересчитывается по ledger.
+type SnapshotWallet struct {
+	ID                string    `json:"id"`
+	UserID            string    `json:"user_id"`
+	CurrencyCode      string    `json:"currency_code"`
+	WalletType        string    `json:"wallet_type"`
+	Status            string    `json:"status"`
+	DailyLimitCents   int64     `json:"daily_limit_cents"`
+	MonthlyLimitCents int64     `json:"monthly_limit_cents"`
+	Jurisdiction      string    `json:"jurisdiction,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+
+	// MaxPendingTransactions - override лимита незавершённых транзакций
+	MaxPendingTransactions *int `json:"max_pending_transactions,omitempty"`
+
+	// ExportedBalanceCents - баланс на момент выгрузки, только для сверки человеком
+	ExportedBalanceCents int64 `json:"exported_balance_cents"`
+}
+
+// SnapshotTransaction - транзакция в исходном виде (кроме переписанных
+// переводов через границу bundle, см. usecases/snapshot).
+type SnapshotTransaction struct {
+	ID                    string          `json:"id"`
+	WalletID              string          `json:"wallet_id"`
+	IdempotencyKey        string          `json:"idempotency_key"`
+	Type                  string          `json:"type"`
+	Status                string          `json:"status"`
+	CurrencyCode          string          `json:"currency_code"`
+	AmountCents           int64           `json:"amount_cents"`
+	FeeCents              int64           `json:"fee_cents"`
+	NetCents              int64           `json:"net_cents"`
+	DestinationWalletID   *string         `json:"destination_wallet_id,omitempty"`
+	ExternalReference     string          `json:"external_reference,omitempty"`
+	ExternalReferenceHash string          `json:"external_reference_hash,omitempty"`
+	Description           string          `json:"description,omitempty"`
+	Metadata              json.RawMessage `json:"metadata,omitempty"`
+	FailureReason         string          `json:"failure_reason,omitempty"`
+	FailureCategory       string          `json:"failure_category,omitempty"`
+	RetryCount            int             `json:"retry_count"`
+	NextRetryAt           *time.Time      `json:"next_retry_at,omitempty"`
+	Jurisdiction          string          `json:"jurisdiction,omitempty"`
+	CreatedByVersion      string          `json:"created_by_version,omitempty"`
+	CreatedAt             time.Time       `json:"created_at"`
+	UpdatedAt             time.Time       `json:"updated_at"`
+	ProcessedAt           *time.Time      `json:"processed_at,omitempty"`
+	CompletedAt           *time.Time      `json:"completed_at,omitempty"`
+}
+
+// ============================================
+// Results
+// ============================================
+
+// SnapshotImportCounts - сколько записей загружено и сколько пропущено
+// (уже существовали или касаются пропущенного кошелька).
+type SnapshotImportCounts struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+}
+
+// SnapshotRename - значение, изменённое из-за конфликта с данными окружения.
+type SnapshotRename struct {
+	ID   string `json:"id"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// SnapshotImportSummaryDTO - отчёт об импорте.
+type SnapshotImportSummaryDTO struct {
+	Users        SnapshotImportCounts `json:"users"`
+	Wallets      SnapshotImportCounts `json:"wallets"`
+	Transactions SnapshotImportCounts `json:"transactions"`
+
+	RenamedEmails          []SnapshotRename `json:"renamed_emails,omitempty"`
+	RenamedIdempotencyKeys []SnapshotRename `json:"renamed_idempotency_keys,omitempty"`
+
+	// Balances - пересчитанные по ledger балансы загруженных кошельков (wallet ID -> сумма)
+	Balances map[string]string `json:"balances"`
+}
@@ -1,7 +1,10 @@
 // Package dtos - Transaction DTOs для передачи данных о транзакциях.
 package dtos
 
-import "time"
+import (
+	"iter"
+	"time"
+)
 
 // ============================================
 // Commands (Write операции)
@@ -35,11 +38,25 @@ type ProcessTransactionCommand struct {
 	TransactionID string `json:"transaction_id" validate:"required,uuid"`
 	Success       bool   `json:"success"`                  // Результат обработки (mock для примера)
 	FailureReason string `json:"failure_reason,omitempty"` // Причина провала
+
+	// ProviderErrorCode - код ошибки провайдера (TIMEOUT, INVALID_ACCOUNT, ...).
+	// Провал должен нести FailureReason или ProviderErrorCode; без обоих
+	// причина станет UNSPECIFIED с предупреждением в логе
+	ProviderErrorCode string `json:"provider_error_code,omitempty"`
+	Provider          string `json:"provider,omitempty"` // Имя провайдера, если известно
+	Source            string `json:"source,omitempty"`   // Откуда пришёл результат: callback, recovery, ...
+
+	// FailureCategory - кто виноват в провале (CLIENT, PROVIDER, SYSTEM, FRAUD).
+	// Пусто - по коду причины провайдера, иначе PROVIDER
+	FailureCategory string `json:"failure_category,omitempty"`
 }
 
 // RetryTransactionCommand - команда для повтора failed транзакции.
 type RetryTransactionCommand struct {
 	TransactionID string `json:"transaction_id" validate:"required,uuid"`
+
+	// IgnoreSchedule - ручной повтор администратором: next_retry_at не проверяется
+	IgnoreSchedule bool `json:"ignore_schedule,omitempty"`
 }
 
 // CancelTransactionCommand - команда для отмены транзакции.
@@ -62,14 +79,38 @@ type GetTransactionByIdempotencyKeyQuery struct {
 	IdempotencyKey string `json:"idempotency_key" validate:"required"`
 }
 
+// GetReceiptQuery - запрос чека по номеру, как его ввёл человек.
+type GetReceiptQuery struct {
+	ReceiptNumber string `json:"receipt_number" validate:"required"`
+}
+
 // ListTransactionsQuery - запрос списка транзакций с фильтрацией.
 type ListTransactionsQuery struct {
 	WalletID *string `json:"wallet_id,omitempty" validate:"omitempty,uuid"`
 	UserID   *string `json:"user_id,omitempty" validate:"omitempty,uuid"`
-	Type     *string `json:"type,omitempty" validate:"omitempty,oneof=DEPOSIT WITHDRAW PAYOUT TRANSFER FEE REFUND ADJUSTMENT"`
-	Status   *string `json:"status,omitempty" validate:"omitempty,oneof=PENDING PROCESSING COMPLETED FAILED CANCELLED"`
-	Offset   int     `json:"offset" validate:"min=0"`
-	Limit    int     `json:"limit" validate:"min=1,max=100"`
+	Type     *string `json:"type,omitempty" validate:"omitempty,enum_term"`
+	Status   *string `json:"status,omitempty" validate:"omitempty,enum_term"`
+
+	// Диапазон created_at [from, to), UTC
+	CreatedFrom *time.Time `json:"created_from,omitempty"`
+	CreatedTo   *time.Time `json:"created_to,omitempty"`
+
+	Offset int `json:"offset" validate:"min=0"`
+	Limit  int `json:"limit" validate:"min=1,max=100"`
+}
+
+// ExportTransactionsQuery - выгрузка всех транзакций по фильтрам
+// ListTransactionsQuery (CSV), без пагинации. Предел строк задаёт
+// конфигурация (transactions.export_max_rows).
+type ExportTransactionsQuery struct {
+	WalletID *string `json:"wallet_id,omitempty" validate:"omitempty,uuid"`
+	UserID   *string `json:"user_id,omitempty" validate:"omitempty,uuid"`
+	Type     *string `json:"type,omitempty" validate:"omitempty,enum_term"`
+	Status   *string `json:"status,omitempty" validate:"omitempty,enum_term"`
+
+	// Диапазон created_at [from, to), UTC
+	CreatedFrom *time.Time `json:"created_from,omitempty"`
+	CreatedTo   *time.Time `json:"created_to,omitempty"`
 }
 
 // ============================================
@@ -83,18 +124,63 @@ type TransactionDTO struct {
 	IdempotencyKey      string            `json:"idempotency_key"`
 	Type                string            `json:"type"`
 	Status              string            `json:"status"`
-	Amount              string            `json:"amount"`
+	Amount              string            `json:"amount"` // = gross_amount, оставлено для совместимости
+	GrossAmount         string            `json:"gross_amount"`
+	FeeAmount           string            `json:"fee_amount"`
+	NetAmount           string            `json:"net_amount"`
+	FeeMode             string            `json:"fee_mode,omitempty"` // SENDER_PAYS | DEDUCTED, пусто без комиссии
 	CurrencyCode        string            `json:"currency_code"`
 	DestinationWalletID *string           `json:"destination_wallet_id,omitempty"`
 	ExternalReference   string            `json:"external_reference,omitempty"`
 	Description         string            `json:"description"`
 	Metadata            map[string]string `json:"metadata,omitempty"`
 	FailureReason       string            `json:"failure_reason,omitempty"`
+	FailureMessageKey   string            `json:"failure_message_key,omitempty"` // Ключ локализации для кода причины; пусто для свободного текста
+	FailureCategory     string            `json:"failure_category,omitempty"`    // CLIENT, PROVIDER, SYSTEM или FRAUD; только для FAILED
 	RetryCount          int               `json:"retry_count"`
+	MaxRetries          int               `json:"max_retries"`
+	IsRetryable         bool              `json:"is_retryable"`            // FAILED, попытки не исчерпаны, причина не окончательная
+	NextRetryAt         *time.Time        `json:"next_retry_at,omitempty"` // Не раньше этого времени; только при is_retryable
+	Jurisdiction        string            `json:"jurisdiction,omitempty"`  // Тег хранения, задаётся при создании
+	CreatedByVersion    *string           `json:"created_by_version"`      // Версия сборки (version+commit); null для старых транзакций
 	CreatedAt           time.Time         `json:"created_at"`
 	UpdatedAt           time.Time         `json:"updated_at"`
 	ProcessedAt         *time.Time        `json:"processed_at,omitempty"`
 	CompletedAt         *time.Time        `json:"completed_at,omitempty"`
+	ReceiptNumber       string            `json:"receipt_number,omitempty"` // Только для COMPLETED, см. GET /receipts/{number}
+
+	// IdempotentReplay выставляется только в ответе на создание транзакции,
+	// если она уже существовала с тем же idempotency_key.
+	IdempotentReplay bool `json:"idempotent_replay,omitempty"`
+
+	// MetadataQuotaWarning выставляется только в ответе на создание
+	// транзакции, пока объём metadata кошелька выше мягкой квоты.
+	MetadataQuotaWarning *MetadataQuotaWarningDTO `json:"metadata_quota_warning,omitempty"`
+}
+
+// MetadataQuotaWarningDTO - объём metadata кошелька выше мягкой квоты.
+type MetadataQuotaWarningDTO struct {
+	UsedBytes      int64 `json:"used_bytes"`
+	SoftLimitBytes int64 `json:"soft_limit_bytes"`
+	HardLimitBytes int64 `json:"hard_limit_bytes,omitempty"` // 0 - жёсткой квоты нет
+}
+
+// ReceiptDTO - чек завершённой транзакции.
+// VerificationHash пересчитывается из сохранённой транзакции: совпадение
+// с напечатанным значением подтверждает, что чек не изменён.
+type ReceiptDTO struct {
+	ReceiptNumber       string    `json:"receipt_number"`
+	TransactionID       string    `json:"transaction_id"`
+	Type                string    `json:"type"`
+	WalletID            string    `json:"wallet_id"`
+	DestinationWalletID *string   `json:"destination_wallet_id,omitempty"`
+	GrossAmount         string    `json:"gross_amount"`
+	FeeAmount           string    `json:"fee_amount"`
+	NetAmount           string    `json:"net_amount"`
+	CurrencyCode        string    `json:"currency_code"`
+	Description         string    `json:"description"`
+	CompletedAt         time.Time `json:"completed_at"`
+	VerificationHash    string    `json:"verification_hash"`
 }
 
 // TransactionListDTO - результат для списка транзакций.
@@ -105,8 +191,60 @@ type TransactionListDTO struct {
 	Limit        int              `json:"limit"`
 }
 
+// Направление движения средств в выгрузке транзакций.
+const (
+	TransactionDirectionIn  = "IN"
+	TransactionDirectionOut = "OUT"
+)
+
+// TransactionExportRowDTO - строка выгрузки: транзакция в том же виде, что в
+// списке, и направление относительно кошелька фильтра (без фильтра - для
+// кошелька-источника). Для типа, неизвестного этой версии, направление
+// пустое.
+type TransactionExportRowDTO struct {
+	TransactionDTO
+	Direction string `json:"direction"`
+}
+
+// TransactionExportDTO - выгрузка транзакций.
+//
+// Rows читает хранилище страницами по мере обхода (keyset), поэтому память
+// не зависит от размера выборки; обход прерывается первой ошибкой. Truncated
+// известен до обхода: по фильтру больше MaxRows строк, Rows отдаёт первые
+// MaxRows.
+type TransactionExportDTO struct {
+	MaxRows   int                                        `json:"max_rows"`
+	Truncated bool                                       `json:"truncated"`
+	Rows      iter.Seq2[*TransactionExportRowDTO, error] `json:"-"`
+}
+
 // TransactionCreatedDTO - результат создания транзакции.
 type TransactionCreatedDTO struct {
 	Transaction TransactionDTO `json:"transaction"`
 	Message     string         `json:"message"`
 }
+
+// GetTransactionFailureStatsQuery - запрос статистики провалов транзакций (admin).
+type GetTransactionFailureStatsQuery struct {
+	// Окно completed_at [from, to), UTC. По умолчанию - последние 24 часа
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+// TransactionFailureStatsDTO - доля провалов за период с разбивкой по категориям.
+type TransactionFailureStatsDTO struct {
+	From        time.Time                 `json:"from"`
+	To          time.Time                 `json:"to"`
+	Finished    int                       `json:"finished"` // COMPLETED + FAILED
+	Completed   int                       `json:"completed"`
+	Failed      int                       `json:"failed"`
+	FailureRate float64                   `json:"failure_rate"` // failed / finished, 0 без транзакций
+	ByCategory  []FailureCategoryStatsDTO `json:"by_category"`  // Все категории, в том числе с нулём
+}
+
+// FailureCategoryStatsDTO - провалы одной категории.
+type FailureCategoryStatsDTO struct {
+	Category string  `json:"category"` // CLIENT, PROVIDER, SYSTEM, FRAUD; UNCATEGORIZED - провалы без категории
+	Count    int     `json:"count"`
+	Rate     float64 `json:"rate"` // count / finished
+}
@@ -26,8 +26,9 @@ import "time"
 // - Содержит все параметры для выполнения операции
 // - Используется для write-операций
 type CreateUserCommand struct {
-	Email    string `json:"email" validate:"required,email"`
-	FullName string `json:"full_name" validate:"required,min=2,max=100"`
+	Email        string `json:"email" validate:"required,email"`
+	FullName     string `json:"full_name" validate:"required,min=2,max=100"`
+	Jurisdiction string `json:"jurisdiction,omitempty" validate:"omitempty,len=2"` // ISO 3166-1 alpha-2, пусто = fallback
 }
 
 // StartKYCVerificationCommand - команда для запуска KYC верификации.
@@ -35,11 +36,25 @@ type StartKYCVerificationCommand struct {
 	UserID string `json:"user_id" validate:"required,uuid"`
 }
 
-// ApproveKYCCommand - команда для одобрения KYC.
+// ApproveKYCCommand - команда для решения по KYC (одобрить или отклонить).
+// Инициатор решения (actor) берётся из context, а не из команды.
 type ApproveKYCCommand struct {
 	UserID   string `json:"user_id" validate:"required,uuid"`
 	Verified bool   `json:"verified"`
-	Reason   string `json:"reason,omitempty"` // Причина (если rejected)
+	Reason   string `json:"reason,omitempty"` // Причина (обязательна если rejected, минимум 10 символов)
+}
+
+// RevokeKYCCommand - отзыв KYC после повторной проверки compliance.
+// Инициатор (admin) берётся из context, а не из команды.
+type RevokeKYCCommand struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+	Reason string `json:"reason" validate:"required"` // Минимум 10 символов
+}
+
+// AcceptTermsCommand - принятие пользователем версии условий обслуживания.
+type AcceptTermsCommand struct {
+	UserID  string `json:"user_id" validate:"required,uuid"`
+	Version int    `json:"version" validate:"required,min=1"`
 }
 
 // UpdateUserCommand - команда для обновления данных пользователя.
@@ -47,6 +62,9 @@ type UpdateUserCommand struct {
 	UserID   string  `json:"user_id" validate:"required,uuid"`
 	Email    *string `json:"email,omitempty" validate:"omitempty,email"`     // nil = не изменять
 	FullName *string `json:"full_name,omitempty" validate:"omitempty,min=2"` // nil = не изменять
+	// Jurisdiction - новая юрисдикция (nil = не изменять).
+	// Уже созданные кошельки и транзакции сохраняют прежний тег.
+	Jurisdiction *string `json:"jurisdiction,omitempty" validate:"omitempty,len=2"`
 }
 
 // ============================================
@@ -63,6 +81,20 @@ type GetUserQuery struct {
 	UserID string `json:"user_id" validate:"required,uuid"`
 }
 
+// GetAdminUserQuery - запрос пользователя для admin консоли.
+type GetAdminUserQuery struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+
+	// IncludeSummary добавляет в ответ AdminUserDTO.Summary (агрегация по
+	// кошелькам и транзакциям пользователя)
+	IncludeSummary bool `json:"include_summary,omitempty"`
+}
+
+// GetKYCHistoryQuery - запрос истории KYC решений пользователя.
+type GetKYCHistoryQuery struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+}
+
 // ListUsersQuery - запрос для получения списка пользователей.
 type ListUsersQuery struct {
 	Offset int `json:"offset" validate:"min=0"`
@@ -81,12 +113,36 @@ type ListUsersQuery struct {
 // - Может содержать вычисляемые поля
 // - Не раскрывает внутренние детали
 type UserDTO struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	FullName  string    `json:"full_name"`
-	KYCStatus string    `json:"kyc_status"` // "UNVERIFIED", "PENDING", "VERIFIED", "REJECTED"
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           string    `json:"id"`
+	Email        string    `json:"email"`
+	FullName     string    `json:"full_name"`
+	KYCStatus    string    `json:"kyc_status"`             // "UNVERIFIED", "PENDING", "VERIFIED", "REJECTED"
+	Jurisdiction string    `json:"jurisdiction,omitempty"` // ISO 3166-1 alpha-2
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// LastKYCRejectionReason - причина последнего отказа KYC (сохраняется и после одобрения)
+	LastKYCRejectionReason string `json:"last_kyc_rejection_reason,omitempty"`
+
+	// Принятая версия ToS: 0 - не принималась; grandfathered - пользователь
+	// существовал до учёта ToS и считается принявшим версию из конфигурации
+	TermsVersion       int        `json:"tos_version"`
+	TermsAcceptedAt    *time.Time `json:"tos_accepted_at,omitempty"`
+	TermsGrandfathered bool       `json:"tos_grandfathered,omitempty"`
+}
+
+// AdminUserDTO - пользователь для admin консоли: публичное представление
+// и, по include_summary=true, сводка активности на одном уровне с ним.
+type AdminUserDTO struct {
+	UserDTO
+	*UserActivitySummaryDTO
+}
+
+// UserActivitySummaryDTO - сводка активности пользователя по всем его кошелькам.
+type UserActivitySummaryDTO struct {
+	WalletCount       int        `json:"wallet_count"`
+	TotalTransactions int64      `json:"total_transactions"`
+	LastActivityAt    *time.Time `json:"last_activity_at"` // null - транзакций не было
 }
 
 // UserListDTO - результат для списка пользователей.
@@ -97,6 +153,22 @@ type UserListDTO struct {
 	Limit      int       `json:"limit"`
 }
 
+// KYCTransitionDTO - одна запись истории KYC.
+type KYCTransitionDTO struct {
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason,omitempty"`
+	ActorID    string    `json:"actor_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// KYCHistoryDTO - история KYC пользователя в хронологическом порядке.
+type KYCHistoryDTO struct {
+	UserID      string             `json:"user_id"`
+	KYCStatus   string             `json:"kyc_status"` // Текущий статус
+	Transitions []KYCTransitionDTO `json:"transitions"`
+}
+
 // UserCreatedDTO - результат создания пользователя.
 // Может содержать дополнительные поля, специфичные для операции создания.
 type UserCreatedDTO struct {
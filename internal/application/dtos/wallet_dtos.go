@@ -16,7 +16,8 @@ type CreateWalletCommand struct {
 // CreditWalletCommand - команда для пополнения кошелька.
 type CreditWalletCommand struct {
 	WalletID          string `json:"wallet_id" validate:"required,uuid"`
-	Amount            string `json:"amount" validate:"required"` // Decimal string: "100.50"
+	Amount            string `json:"amount,omitempty"`       // Decimal string: "100.50"
+	AmountMinor       *int64 `json:"amount_minor,omitempty"` // Либо минорные единицы: 10050
 	IdempotencyKey    string `json:"idempotency_key" validate:"required,uuid"`
 	Description       string `json:"description" validate:"required"`
 	ExternalReference string `json:"external_reference,omitempty"` // Например, Stripe payment_intent_id
@@ -25,7 +26,8 @@ type CreditWalletCommand struct {
 // DebitWalletCommand - команда для списания с кошелька.
 type DebitWalletCommand struct {
 	WalletID          string `json:"wallet_id" validate:"required,uuid"`
-	Amount            string `json:"amount" validate:"required"`
+	Amount            string `json:"amount,omitempty"`
+	AmountMinor       *int64 `json:"amount_minor,omitempty"`
 	IdempotencyKey    string `json:"idempotency_key" validate:"required,uuid"`
 	Description       string `json:"description" validate:"required"`
 	ExternalReference string `json:"external_reference,omitempty"`
@@ -35,16 +37,20 @@ type DebitWalletCommand struct {
 type TransferFundsCommand struct {
 	SourceWalletID      string `json:"source_wallet_id" validate:"required,uuid"`
 	DestinationWalletID string `json:"destination_wallet_id" validate:"required,uuid"`
-	Amount              string `json:"amount" validate:"required"`
+	Amount              string `json:"amount,omitempty"`
+	AmountMinor         *int64 `json:"amount_minor,omitempty"`
 	IdempotencyKey      string `json:"idempotency_key" validate:"required,uuid"`
 	Description         string `json:"description" validate:"required"`
+	ExternalReference   string `json:"external_reference,omitempty"` // подставляется в шаблон описания получателя
+	ConfirmNewPayee     bool   `json:"confirm_new_payee"`            // подтверждение первого перевода новому получателю
 }
 
 // ExchangeCurrencyCommand - команда для обмена валюты между своими кошельками.
 type ExchangeCurrencyCommand struct {
 	SourceWalletID      string `json:"source_wallet_id" validate:"required,uuid"`
 	DestinationWalletID string `json:"destination_wallet_id" validate:"required,uuid"`
-	Amount              string `json:"amount" validate:"required"`
+	Amount              string `json:"amount,omitempty"`
+	AmountMinor         *int64 `json:"amount_minor,omitempty"`
 	IdempotencyKey      string `json:"idempotency_key" validate:"required,uuid"`
 }
 
@@ -56,6 +62,7 @@ type ExchangeResultDTO struct {
 	SourceAmount      string    `json:"source_amount"`
 	DestinationAmount string    `json:"destination_amount"`
 	ExchangeRate      string    `json:"exchange_rate"`
+	RateSnapshotID    string    `json:"rate_snapshot_id,omitempty"` // Снимок курса провайдера (fx_rate_snapshots)
 	Spread            string    `json:"spread"`
 	SourceCurrency    string    `json:"source_currency"`
 	DestCurrency      string    `json:"dest_currency"`
@@ -76,6 +83,27 @@ type UpdateWalletLimitsCommand struct {
 	MonthlyLimit string `json:"monthly_limit" validate:"required"`
 }
 
+// CloseWalletCommand - команда для закрытия кошелька с переводом остатка.
+type CloseWalletCommand struct {
+	WalletID        string `json:"wallet_id" validate:"required,uuid"`
+	SweepToWalletID string `json:"sweep_to_wallet_id" validate:"required,uuid"` // Кошелёк владельца в той же валюте
+	ConfirmNewPayee bool   `json:"confirm_new_payee"`                           // подтверждение первого перевода новому получателю
+}
+
+// EnsureWalletCommand - команда "кошелёк должен существовать с этими лимитами".
+// Пустой лимит не управляется: при создании остаётся значение по умолчанию,
+// у существующего кошелька не меняется.
+type EnsureWalletCommand struct {
+	UserID       string `json:"user_id" validate:"required,uuid"`
+	CurrencyCode string `json:"currency_code" validate:"required,len=3"`
+	DailyLimit   string `json:"daily_limit,omitempty"`
+	MonthlyLimit string `json:"monthly_limit,omitempty"`
+
+	// MaxPendingTransactions - override лимита незавершённых транзакций
+	// (только админ): nil - не управляется, 0 - вернуть лимит по умолчанию.
+	MaxPendingTransactions *int `json:"max_pending_transactions,omitempty"`
+}
+
 // ============================================
 // Queries (Read операции)
 // ============================================
@@ -83,6 +111,14 @@ type UpdateWalletLimitsCommand struct {
 // GetWalletQuery - запрос для получения кошелька по ID.
 type GetWalletQuery struct {
 	WalletID string `json:"wallet_id" validate:"required,uuid"`
+
+	// IncludeUsage добавляет в ответ WalletDTO.Usage (отдельный COUNT запрос)
+	IncludeUsage bool `json:"include_usage,omitempty"`
+}
+
+// GetWalletBalanceQuery - запрос только балансов кошелька (быстрый путь GetWallet).
+type GetWalletBalanceQuery struct {
+	WalletID string `json:"wallet_id" validate:"required,uuid"`
 }
 
 // GetWalletByUserAndCurrencyQuery - запрос кошелька пользователя по валюте.
@@ -95,7 +131,7 @@ type GetWalletByUserAndCurrencyQuery struct {
 type ListWalletsQuery struct {
 	UserID       *string `json:"user_id,omitempty" validate:"omitempty,uuid"`
 	CurrencyCode *string `json:"currency_code,omitempty" validate:"omitempty,len=3"`
-	Status       *string `json:"status,omitempty" validate:"omitempty,oneof=ACTIVE SUSPENDED LOCKED CLOSED"`
+	Status       *string `json:"status,omitempty" validate:"omitempty,enum_term"`
 	Offset       int     `json:"offset" validate:"min=0"`
 	Limit        int     `json:"limit" validate:"min=1,max=100"`
 }
@@ -116,30 +152,169 @@ type WalletDTO struct {
 	TotalBalance     string    `json:"total_balance"`
 	DailyLimit       string    `json:"daily_limit"`
 	MonthlyLimit     string    `json:"monthly_limit"`
+	BalanceVersion   int64     `json:"balance_version"`        // Растёт при каждом изменении баланса
+	Jurisdiction     string    `json:"jurisdiction,omitempty"` // Тег хранения, задаётся при создании
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
+
+	// MaxPendingTransactions - override лимита незавершённых транзакций, nil - по умолчанию
+	MaxPendingTransactions *int `json:"max_pending_transactions,omitempty"`
+
+	// Usage - текущая загрузка кошелька, только с include_usage
+	Usage *WalletUsageDTO `json:"usage,omitempty"`
+
+	// Converted - доступный баланс в валюте отображения кошелька
+	Converted *ConvertedBalanceDTO `json:"converted,omitempty"`
+}
+
+// WalletBalanceDTO - балансы кошелька без остальных полей WalletDTO.
+// Форматы значений совпадают с WalletDTO.
+type WalletBalanceDTO struct {
+	WalletID         string `json:"wallet_id"`
+	CurrencyCode     string `json:"currency_code"`
+	AvailableBalance string `json:"available_balance"`
+	PendingBalance   string `json:"pending_balance"`
+	BalanceVersion   int64  `json:"balance_version"` // Растёт при каждом изменении баланса
+
+	// Converted - доступный баланс в валюте отображения кошелька
+	Converted *ConvertedBalanceDTO `json:"converted,omitempty"`
+
+	// UserID - владелец для проверки доступа в handler'е, клиенту не отдаётся
+	UserID string `json:"-"`
 }
 
-// WalletListDTO - результат для списка кошельков.
-type WalletListDTO struct {
-	Wallets    []WalletDTO `json:"wallets"`
-	TotalCount int         `json:"total_count"`
-	Offset     int         `json:"offset"`
-	Limit      int         `json:"limit"`
+// ConvertedBalanceDTO - приблизительный пересчёт доступного баланса в
+// валюту отображения (wallet settings display_currency). Только для показа:
+// курс не фиксируется, деньги по нему не двигаются. Без курса блок
+// отсутствует.
+type ConvertedBalanceDTO struct {
+	Currency      string    `json:"currency"`
+	Amount        string    `json:"amount"` // "43210.55 USD"
+	Rate          string    `json:"rate"`   // 1 единица валюты кошелька в currency
+	RateFetchedAt time.Time `json:"rate_fetched_at"`
+	Approximate   bool      `json:"approximate"` // всегда true
 }
 
+// WalletUsageDTO - загрузка кошелька относительно лимита незавершённых транзакций.
+type WalletUsageDTO struct {
+	PendingTransactions    int `json:"pending_transactions"`     // PENDING + PROCESSING
+	MaxPendingTransactions int `json:"max_pending_transactions"` // действующий лимит, 0 - без лимита
+
+	// Metadata - объём metadata транзакций относительно квот
+	Metadata *WalletMetadataUsageDTO `json:"metadata,omitempty"`
+}
+
+// WalletMetadataUsageDTO - суммарный объём metadata транзакций кошелька.
+type WalletMetadataUsageDTO struct {
+	UsedBytes      int64 `json:"used_bytes"`
+	SoftLimitBytes int64 `json:"soft_limit_bytes"` // 0 - без предупреждений
+	HardLimitBytes int64 `json:"hard_limit_bytes"` // 0 - без отказа
+}
+
+// WalletListDTO - страница списка кошельков.
+type WalletListDTO = Page[WalletDTO]
+
 // WalletOperationDTO - результат операции с кошельком (credit/debit).
+//
+// IdempotentReplay = true означает, что запрос с этим idempotency_key
+// уже был обработан раньше: деньги сейчас не двигались, а CreatedAt -
+// время исходной транзакции.
+//
+// Transaction - проведённая транзакция в том виде, в каком её вернёт
+// GET /transactions/:id (COMPLETED для синхронных операций); TransactionID
+// дублирует Transaction.ID для старых клиентов.
 type WalletOperationDTO struct {
-	Wallet        WalletDTO `json:"wallet"`
-	TransactionID string    `json:"transaction_id"`
-	Message       string    `json:"message"` // Например: "Wallet credited successfully"
+	Wallet           WalletDTO      `json:"wallet"`
+	Transaction      TransactionDTO `json:"transaction"`
+	TransactionID    string         `json:"transaction_id"`
+	Message          string         `json:"message,omitempty"` // Deprecated: используйте transaction
+	CreatedAt        time.Time      `json:"created_at"`        // Время создания транзакции
+	IdempotentReplay bool           `json:"idempotent_replay"`
 }
 
 // TransferResultDTO - результат перевода между кошельками.
+// Семантика IdempotentReplay, CreatedAt и Transaction - как в WalletOperationDTO;
+// Transaction - TRANSFER транзакция (FEE доступна по FeeTransactionID).
 type TransferResultDTO struct {
-	SourceWallet      WalletDTO `json:"source_wallet"`
-	DestinationWallet WalletDTO `json:"destination_wallet"`
-	TransactionID     string    `json:"transaction_id"`
-	Amount            string    `json:"amount"`
-	Status            string    `json:"status"`
+	SourceWallet      WalletDTO      `json:"source_wallet"`
+	DestinationWallet WalletDTO      `json:"destination_wallet"`
+	Transaction       TransactionDTO `json:"transaction"`
+	TransactionID     string         `json:"transaction_id"`
+	Amount            string         `json:"amount"` // = gross_amount
+	GrossAmount       string         `json:"gross_amount"`
+	FeeAmount         string         `json:"fee_amount"`
+	NetAmount         string         `json:"net_amount"` // Получено destination wallet
+	FeeMode           string         `json:"fee_mode,omitempty"`
+	FeeTransactionID  string         `json:"fee_transaction_id,omitempty"`
+	Status            string         `json:"status"`
+	CreatedAt         time.Time      `json:"created_at"` // Время создания транзакции
+	IdempotentReplay  bool           `json:"idempotent_replay"`
+}
+
+// CloseWalletResultDTO - результат закрытия кошелька.
+type CloseWalletResultDTO struct {
+	Wallet            WalletDTO `json:"wallet"`             // Закрытый кошелёк
+	DestinationWallet WalletDTO `json:"destination_wallet"` // Кошелёк, получивший остаток
+	SweptAmount       string    `json:"swept_amount"`
+	TransactionID     string    `json:"transaction_id,omitempty"` // Пусто, если остаток был нулевым
+}
+
+// Поля кошелька, которые может изменить EnsureWallet.
+const (
+	EnsureWalletFieldDailyLimit   = "daily_limit"
+	EnsureWalletFieldMonthlyLimit = "monthly_limit"
+
+	EnsureWalletFieldMaxPendingTransactions = "max_pending_transactions"
+)
+
+// EnsureWalletResultDTO - результат EnsureWallet.
+//
+// Created = true - кошелёк создан этим вызовом. Changed = true - кошелёк
+// создан или изменён; ChangedFields перечисляет изменённые поля
+// существующего кошелька (для созданного - пусто).
+type EnsureWalletResultDTO struct {
+	Wallet        WalletDTO `json:"wallet"`
+	Created       bool      `json:"created"`
+	Changed       bool      `json:"changed"`
+	ChangedFields []string  `json:"changed_fields"`
+}
+
+// ============================================
+// Activity heatmap
+// ============================================
+
+// GetActivityHeatmapQuery - запрос активности по дням для heatmap дашборда.
+// Задаётся ровно одно из WalletID (один кошелёк) и UserID (все кошельки
+// пользователя).
+type GetActivityHeatmapQuery struct {
+	WalletID string `json:"wallet_id,omitempty" validate:"omitempty,uuid"`
+	UserID   string `json:"user_id,omitempty" validate:"omitempty,uuid"`
+	Days     int    `json:"days" validate:"min=0,max=730"` // 0 - по умолчанию (365)
+	Timezone string `json:"timezone,omitempty"`            // IANA, по умолчанию UTC
+}
+
+// ActivityHeatmapDTO - активность за дни [From, To] в зоне Timezone.
+// Days содержит каждый день диапазона по возрастанию, в том числе без
+// транзакций.
+type ActivityHeatmapDTO struct {
+	WalletID string           `json:"wallet_id,omitempty"`
+	UserID   string           `json:"user_id,omitempty"`
+	Timezone string           `json:"timezone"`
+	From     string           `json:"from"` // YYYY-MM-DD
+	To       string           `json:"to"`   // YYYY-MM-DD, включительно
+	Days     []ActivityDayDTO `json:"days"`
+}
+
+// ActivityDayDTO - активность за один день.
+type ActivityDayDTO struct {
+	Date  string           `json:"date"` // YYYY-MM-DD
+	Count int              `json:"count"`
+	Sums  []ActivitySumDTO `json:"sums"` // По валюте кошельков, в том числе нулевые
+}
+
+// ActivitySumDTO - суммы дня в одной валюте.
+type ActivitySumDTO struct {
+	CurrencyCode string `json:"currency_code"`
+	CreditSum    string `json:"credit_sum"`
+	DebitSum     string `json:"debit_sum"`
 }
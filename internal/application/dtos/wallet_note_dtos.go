@@ -0,0 +1,63 @@
+package dtos
+
+import "time"
+
+// Заметки поддержки видны только администраторам: WalletNoteDTO не входит
+// в WalletDTO и в snapshot экспорт.
+
+// ============================================
+// Commands
+// ============================================
+
+// CreateWalletNoteCommand - добавить заметку к кошельку (admin).
+// Автор берётся из context (ports.ActorFromContext).
+type CreateWalletNoteCommand struct {
+	WalletID string `json:"wallet_id" validate:"required,uuid"`
+	Body     string `json:"body" validate:"required,max=2000"`
+}
+
+// SetWalletNotePinnedCommand - закрепить или открепить заметку (admin).
+type SetWalletNotePinnedCommand struct {
+	WalletID string `json:"wallet_id" validate:"required,uuid"`
+	NoteID   string `json:"note_id" validate:"required,uuid"`
+	Pinned   bool   `json:"pinned"`
+}
+
+// DeleteWalletNoteCommand - мягко удалить заметку (автор или superadmin).
+type DeleteWalletNoteCommand struct {
+	WalletID string `json:"wallet_id" validate:"required,uuid"`
+	NoteID   string `json:"note_id" validate:"required,uuid"`
+}
+
+// ============================================
+// Queries
+// ============================================
+
+// ListWalletNotesQuery - заметки кошелька (admin).
+type ListWalletNotesQuery struct {
+	WalletID string `json:"wallet_id" validate:"required,uuid"`
+	Offset   int    `json:"offset" validate:"min=0"`
+	Limit    int    `json:"limit" validate:"min=1,max=100"`
+}
+
+// ============================================
+// Results
+// ============================================
+
+// WalletNoteDTO - заметка поддержки.
+type WalletNoteDTO struct {
+	ID        string    `json:"id"`
+	WalletID  string    `json:"wallet_id"`
+	AuthorID  string    `json:"author_id"`
+	Body      string    `json:"body"`
+	Pinned    bool      `json:"pinned"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WalletNoteListDTO - страница заметок: закреплённые первыми, затем новые.
+type WalletNoteListDTO struct {
+	Notes      []WalletNoteDTO `json:"notes"`
+	TotalCount int             `json:"total_count"`
+	Offset     int             `json:"offset"`
+	Limit      int             `json:"limit"`
+}
@@ -0,0 +1,84 @@
+package dtos
+
+import (
+	"time"
+)
+
+// ============================================
+// Queries
+// ============================================
+
+// ReplayWalletQuery - воспроизведение истории кошелька (admin API, CLI).
+type ReplayWalletQuery struct {
+	WalletID string
+}
+
+// ============================================
+// Results
+// ============================================
+
+// WalletReplayDTO - кошелёк, заново построенный по истории транзакций,
+// и его расхождения с сохранённым.
+type WalletReplayDTO struct {
+	WalletID     string `json:"wallet_id"`
+	CurrencyCode string `json:"currency_code"`
+	Outcome      string `json:"outcome"` // CONSISTENT, DIVERGED, UNREPLAYABLE
+
+	// Complete - история воспроизведена до конца. false - остановлена на
+	// first_divergence или blocker: replayed - состояние перед этой строкой
+	Complete bool `json:"complete"`
+
+	TransactionsRead    int `json:"transactions_read"`    // Строк истории прочитано
+	TransactionsApplied int `json:"transactions_applied"` // Строк, изменивших баланс кошелька
+
+	Stored   WalletReplayStateDTO `json:"stored"`
+	Replayed WalletReplayStateDTO `json:"replayed"`
+
+	Differences  []WalletReplayDifferenceDTO  `json:"differences"`
+	StatusEvents []WalletReplayStatusEventDTO `json:"status_events"`
+
+	// FirstDivergence - строка, которую живая система провела, а replay
+	// провести не может (история не сходится начиная с неё)
+	FirstDivergence *WalletReplayRowDTO `json:"first_divergence,omitempty"`
+	// Blocker - строка, которую replay не умеет воспроизвести
+	Blocker *WalletReplayRowDTO `json:"blocker,omitempty"`
+}
+
+// WalletReplayStateDTO - состояние кошелька.
+type WalletReplayStateDTO struct {
+	AvailableBalance string `json:"available_balance"`
+	PendingBalance   string `json:"pending_balance"`
+	BalanceVersion   int64  `json:"balance_version"`
+	Status           string `json:"status"`
+}
+
+// WalletReplayDifferenceDTO - поле, в котором сохранённый кошелёк
+// расходится с воспроизведённым.
+type WalletReplayDifferenceDTO struct {
+	Field    string `json:"field"` // available_balance, pending_balance, balance_version
+	Stored   string `json:"stored"`
+	Replayed string `json:"replayed"`
+}
+
+// WalletReplayStatusEventDTO - смена статуса, которую история и сохранённый
+// кошелёк видят по-разному.
+type WalletReplayStatusEventDTO struct {
+	Event string `json:"event"` // WALLET_CLOSED
+	// Kind: missing - история закрыла кошелёк, а он не закрыт;
+	// extra - кошелёк закрыт с остатком без sweep-транзакции
+	Kind          string `json:"kind"`
+	TransactionID string `json:"transaction_id,omitempty"` // Sweep-транзакция для missing
+}
+
+// WalletReplayRowDTO - строка истории, на которой остановился replay.
+type WalletReplayRowDTO struct {
+	Position         int       `json:"position"` // Номер строки в истории кошелька, с 1
+	TransactionID    string    `json:"transaction_id"`
+	Type             string    `json:"type"`
+	Status           string    `json:"status"`
+	CreatedAt        time.Time `json:"created_at"`
+	CreatedByVersion string    `json:"created_by_version,omitempty"`
+	Reason           string    `json:"reason"`
+	Detail           string    `json:"detail"`
+	AvailableBefore  string    `json:"available_before"` // Воспроизведённый баланс перед строкой
+}
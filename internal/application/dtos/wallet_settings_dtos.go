@@ -0,0 +1,26 @@
+package dtos
+
+import "time"
+
+// Настройки представления кошелька управляются его владельцем.
+// Ownership проверяется в HTTP handler до отправки команды.
+
+// UpdateWalletSettingsCommand - заменить настройки кошелька целиком.
+// Пустой шаблон, пустой список тегов и пустая валюта отображения выключают
+// соответствующую настройку.
+// Инициатор берётся из context (ports.ActorFromContext).
+type UpdateWalletSettingsCommand struct {
+	WalletID                    string   `json:"wallet_id" validate:"required,uuid"`
+	IncomingDescriptionTemplate string   `json:"incoming_description_template" validate:"max=200"`
+	AutoTags                    []string `json:"auto_tags" validate:"max=10"`
+	DisplayCurrency             string   `json:"display_currency" validate:"omitempty,max=10"`
+}
+
+// WalletSettingsDTO - настройки представления кошелька.
+type WalletSettingsDTO struct {
+	WalletID                    string    `json:"wallet_id"`
+	IncomingDescriptionTemplate string    `json:"incoming_description_template"`
+	AutoTags                    []string  `json:"auto_tags"`
+	DisplayCurrency             string    `json:"display_currency"` // пусто - без пересчёта
+	UpdatedAt                   time.Time `json:"updated_at"`
+}
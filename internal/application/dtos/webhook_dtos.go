@@ -0,0 +1,110 @@
+package dtos
+
+import "time"
+
+// Webhook подписки принадлежат пользователю: все операции выполняются от
+// имени владельца (ports.ActorFromContext). Секрет подписи возвращается
+// только при создании.
+
+// ============================================
+// Commands
+// ============================================
+
+// CreateWebhookCommand - создать webhook подписку. Пустой EventTypes -
+// все типы из ports.WebhookEventTypes.
+type CreateWebhookCommand struct {
+	URL        string   `json:"url" validate:"required,max=2048"`
+	EventTypes []string `json:"event_types,omitempty" validate:"max=20"`
+}
+
+// UpdateWebhookCommand - изменить подписку (PATCH). nil поля не меняются.
+//
+// Active=true включает отключённую подписку: очередь доставок продолжается
+// с места остановки, а с SkipBacklog=true накопленные доставки пропускаются.
+type UpdateWebhookCommand struct {
+	SubscriptionID string    `json:"subscription_id" validate:"required,uuid"`
+	URL            *string   `json:"url,omitempty" validate:"omitempty,max=2048"`
+	EventTypes     *[]string `json:"event_types,omitempty" validate:"omitempty,max=20"`
+	Active         *bool     `json:"active,omitempty"`
+	SkipBacklog    bool      `json:"skip_backlog,omitempty"`
+}
+
+// DeleteWebhookCommand - мягко удалить подписку. История доставок хранится
+// до очистки по webhooks.delivery_retention.
+type DeleteWebhookCommand struct {
+	SubscriptionID string `json:"subscription_id" validate:"required,uuid"`
+}
+
+// ============================================
+// Queries
+// ============================================
+
+// ListWebhooksQuery - подписки текущего пользователя.
+type ListWebhooksQuery struct{}
+
+// GetWebhookQuery - подписка владельца.
+type GetWebhookQuery struct {
+	SubscriptionID string `json:"subscription_id" validate:"required,uuid"`
+}
+
+// ListWebhookDeliveriesQuery - история доставок подписки, новые первыми.
+type ListWebhookDeliveriesQuery struct {
+	SubscriptionID string `json:"subscription_id" validate:"required,uuid"`
+	Offset         int    `json:"offset" validate:"min=0"`
+	Limit          int    `json:"limit" validate:"min=1,max=100"`
+}
+
+// ============================================
+// Results
+// ============================================
+
+// WebhookSubscriptionDTO - webhook подписка без секрета.
+//
+// Status: ACTIVE, DISABLED_MANUAL (отключена владельцем) или DISABLED_AUTO
+// (отключена после серии неудачных доставок, причина в LastFailureReason).
+type WebhookSubscriptionDTO struct {
+	ID                  string     `json:"id"`
+	URL                 string     `json:"url"`
+	EventTypes          []string   `json:"event_types"`
+	Status              string     `json:"status"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	FailingSince        *time.Time `json:"failing_since,omitempty"`
+	LastFailureReason   string     `json:"last_failure_reason,omitempty"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// WebhookSubscriptionCreatedDTO - созданная подписка с секретом подписи
+// (X-PayBridge-Signature: sha256=HMAC(secret, body)).
+type WebhookSubscriptionCreatedDTO struct {
+	WebhookSubscriptionDTO
+	Secret string `json:"secret"`
+}
+
+// WebhookSubscriptionListDTO - подписки владельца, новые первыми.
+type WebhookSubscriptionListDTO struct {
+	Subscriptions []WebhookSubscriptionDTO `json:"subscriptions"`
+}
+
+// WebhookDeliveryDTO - доставка события подписке.
+type WebhookDeliveryDTO struct {
+	ID             string     `json:"id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"` // только PENDING
+	CreatedAt      time.Time  `json:"created_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+}
+
+// WebhookDeliveryListDTO - страница истории доставок.
+type WebhookDeliveryListDTO struct {
+	Deliveries []WebhookDeliveryDTO `json:"deliveries"`
+	TotalCount int                  `json:"total_count"`
+	Offset     int                  `json:"offset"`
+	Limit      int                  `json:"limit"`
+}
@@ -0,0 +1,37 @@
+package dtos
+
+import "time"
+
+// ============================================
+// Queries
+// ============================================
+
+// ListWorkersQuery - heartbeat фоновых компонентов (admin).
+type ListWorkersQuery struct{}
+
+// ============================================
+// Results
+// ============================================
+
+// WorkerDTO - последний heartbeat фонового компонента на экземпляре.
+type WorkerDTO struct {
+	Name              string     `json:"name"`
+	Instance          string     `json:"instance"`
+	Local             bool       `json:"local"` // экземпляр, ответивший на запрос
+	State             string     `json:"state"` // RUNNING, IDLE, STOPPED
+	Stale             bool       `json:"stale"`
+	LastHeartbeatAt   time.Time  `json:"last_heartbeat_at"`
+	LastRunAt         *time.Time `json:"last_run_at,omitempty"`
+	LastSuccessAt     *time.Time `json:"last_success_at,omitempty"`
+	ItemsProcessed    int        `json:"items_processed"` // в последнем завершённом прогоне
+	LastError         string     `json:"last_error,omitempty"`
+	StaleAfterSeconds int64      `json:"stale_after_seconds"` // 0 - не устаревает
+}
+
+// WorkerListDTO - фоновые компоненты экземпляра или всего кластера.
+type WorkerListDTO struct {
+	Instance   string      `json:"instance"`
+	Workers    []WorkerDTO `json:"workers"`
+	StaleCount int         `json:"stale_count"`
+	TotalCount int         `json:"total_count"`
+}
@@ -0,0 +1,151 @@
+// Package kyc - проверка KYC владельца кошелька перед выводом средств.
+//
+// KYC может быть отозван после повторной проверки compliance, а кошельки,
+// открытые при верифицированном владельце, продолжают работать. Gate
+// перечитывает статус владельца при списаниях, выводах, выплатах и
+// исходящих переводах:
+//   - strict: операция отклоняется с USER_KYC_NOT_VERIFIED
+//   - lenient (по умолчанию): операция проходит, транзакция помечается
+//     в metadata и публикуется TransactionFlagged
+//
+// Пополнения не проверяются, чтобы входящие средства не застревали.
+//
+// Статусы кэшируются в процессе на CacheTTL и обновляются событиями
+// UserKYCApproved / UserKYCRejected / UserKYCRevoked из шины. Шина
+// доставляет события только своего экземпляра, поэтому на других
+// экземплярах отзыв вступает в силу не позже чем через CacheTTL.
+package kyc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/pkg/ttlcache"
+)
+
+// RuleNotVerified - правило, которым отклоняется (strict) или помечается
+// (lenient) вывод средств с кошелька неверифицированного владельца.
+const RuleNotVerified = "USER_KYC_NOT_VERIFIED"
+
+// DefaultCacheTTL - время жизни закэшированного статуса по умолчанию.
+const DefaultCacheTTL = 30 * time.Second
+
+// maxCachedStatuses - сколько статусов держит кэш (см. ttlcache).
+const maxCachedStatuses = 100_000
+
+// Mode - режим проверки (config kyc.enforcement).
+type Mode string
+
+// Режимы проверки.
+const (
+	ModeLenient Mode = "lenient"
+	ModeStrict  Mode = "strict"
+)
+
+// ParseMode разбирает режим; пустая строка - ModeLenient.
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case "":
+		return ModeLenient, nil
+	case ModeLenient, ModeStrict:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown KYC enforcement mode %q (want lenient or strict)", s)
+	}
+}
+
+// Policy - настройки проверки.
+type Policy struct {
+	Mode     Mode
+	CacheTTL time.Duration // 0 - статус читается при каждой операции
+}
+
+// Gate реализует ports.KYCGate.
+type Gate struct {
+	policy   Policy
+	users    ports.UserRepository
+	statuses *ttlcache.Cache[uuid.UUID, entities.KYCStatus]
+}
+
+// Compile-time check
+var _ ports.KYCGate = (*Gate)(nil)
+
+// NewGate проверяет политику и создаёт Gate.
+func NewGate(policy Policy, users ports.UserRepository) (*Gate, error) {
+	if policy.Mode != ModeLenient && policy.Mode != ModeStrict {
+		return nil, fmt.Errorf("unknown KYC enforcement mode %q", policy.Mode)
+	}
+	if policy.CacheTTL < 0 {
+		return nil, fmt.Errorf("KYC status cache TTL must not be negative: %s", policy.CacheTTL)
+	}
+	return &Gate{
+		policy:   policy,
+		users:    users,
+		statuses: ttlcache.New[uuid.UUID, entities.KYCStatus](policy.CacheTTL, maxCachedStatuses),
+	}, nil
+}
+
+// CheckOwner применяет режим к KYC статусу владельца кошелька.
+func (g *Gate) CheckOwner(ctx context.Context, tx *entities.Transaction, wallet *entities.Wallet) ([]events.DomainEvent, error) {
+	status, err := g.status(ctx, wallet.UserID())
+	if err != nil {
+		return nil, err
+	}
+	if status == entities.KYCStatusVerified {
+		return nil, nil
+	}
+
+	if g.policy.Mode == ModeStrict {
+		return nil, errors.NewBusinessRuleViolation(
+			RuleNotVerified,
+			"wallet owner is not KYC verified",
+			map[string]interface{}{
+				"userId":    wallet.UserID().String(),
+				"kycStatus": string(status),
+			},
+		)
+	}
+
+	if err := tx.AddMetadata(ports.KYCUnverifiedOwnerMetadataKey, string(status)); err != nil {
+		return nil, err
+	}
+	return []events.DomainEvent{
+		events.NewTransactionFlagged(tx.ID(), tx.WalletID(), string(tx.Type()), tx.Amount(), []string{RuleNotVerified}),
+	}, nil
+}
+
+// HandleEvent обновляет кэш по KYC событиям; остальные события игнорируются.
+// Подписывается на шину событий (ports.EventHandler).
+func (g *Gate) HandleEvent(_ context.Context, event events.DomainEvent) error {
+	switch e := event.(type) {
+	case *events.UserKYCApproved:
+		g.statuses.Set(e.UserID, entities.KYCStatusVerified)
+	case *events.UserKYCRejected:
+		g.statuses.Set(e.UserID, entities.KYCStatusRejected)
+	case *events.UserKYCRevoked:
+		g.statuses.Set(e.UserID, entities.KYCStatusRejected)
+	}
+	return nil
+}
+
+// status возвращает статус из кэша или из хранилища.
+func (g *Gate) status(ctx context.Context, userID uuid.UUID) (entities.KYCStatus, error) {
+	if status, ok := g.statuses.Get(userID); ok {
+		return status, nil
+	}
+
+	user, err := g.users.FindByID(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load wallet owner for KYC check: %w", err)
+	}
+	g.statuses.Set(userID, user.KYCStatus())
+	return user.KYCStatus(), nil
+}
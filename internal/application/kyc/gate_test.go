@@ -0,0 +1,224 @@
+package kyc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/kyc"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// gateFixture - верифицированный владелец кошелька и транзакция вывода.
+type gateFixture struct {
+	users  *memory.UserRepository
+	owner  *entities.User
+	wallet *entities.Wallet
+}
+
+func newGateFixture(t *testing.T) *gateFixture {
+	t.Helper()
+
+	users := memory.NewUserRepository(memory.NewStore())
+	owner, err := entities.NewUser("kyc-"+uuid.NewString()+"@example.com", "KYC Owner")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := users.Save(context.Background(), owner); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+	wallet, err := entities.NewWallet(owner.ID(), valueobjects.USD)
+	if err != nil {
+		t.Fatalf("NewWallet() error = %v", err)
+	}
+	return &gateFixture{users: users, owner: owner, wallet: wallet}
+}
+
+// revoke отзывает KYC владельца в хранилище, минуя события.
+func (f *gateFixture) revoke(t *testing.T) {
+	t.Helper()
+	if err := f.owner.RevokeKYC("Sanctions list match"); err != nil {
+		t.Fatalf("RevokeKYC() error = %v", err)
+	}
+	if err := f.users.Save(context.Background(), f.owner); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+}
+
+// withdrawal создаёт транзакцию вывода с кошелька.
+func (f *gateFixture) withdrawal(t *testing.T) *entities.Transaction {
+	t.Helper()
+	amount, _ := valueobjects.NewMoney("10", valueobjects.USD)
+	tx, err := entities.NewTransaction(f.wallet.ID(), uuid.NewString(), entities.TransactionTypeWithdraw, amount, "Withdrawal")
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	return tx
+}
+
+func newGate(t *testing.T, mode kyc.Mode, ttl time.Duration, users ports.UserRepository) *kyc.Gate {
+	t.Helper()
+	gate, err := kyc.NewGate(kyc.Policy{Mode: mode, CacheTTL: ttl}, users)
+	if err != nil {
+		t.Fatalf("NewGate() error = %v", err)
+	}
+	return gate
+}
+
+func TestGate_Strict(t *testing.T) {
+	f := newGateFixture(t)
+	gate := newGate(t, kyc.ModeStrict, 0, f.users)
+
+	evts, err := gate.CheckOwner(context.Background(), f.withdrawal(t), f.wallet)
+	if err != nil || len(evts) != 0 {
+		t.Fatalf("verified owner: events = %d, error = %v", len(evts), err)
+	}
+
+	f.revoke(t)
+	tx := f.withdrawal(t)
+	_, err = gate.CheckOwner(context.Background(), tx, f.wallet)
+	var violation *domainErrors.BusinessRuleViolation
+	if !errors.As(err, &violation) || violation.Rule != kyc.RuleNotVerified {
+		t.Fatalf("Expected %s, got %v", kyc.RuleNotVerified, err)
+	}
+	if violation.Context["kycStatus"] != "REJECTED" {
+		t.Errorf("violation context = %v", violation.Context)
+	}
+	if _, ok := tx.Metadata()[ports.KYCUnverifiedOwnerMetadataKey]; ok {
+		t.Error("strict mode must not flag the transaction")
+	}
+}
+
+func TestGate_Lenient(t *testing.T) {
+	f := newGateFixture(t)
+	gate := newGate(t, kyc.ModeLenient, 0, f.users)
+	f.revoke(t)
+
+	tx := f.withdrawal(t)
+	evts, err := gate.CheckOwner(context.Background(), tx, f.wallet)
+	if err != nil {
+		t.Fatalf("CheckOwner() error = %v", err)
+	}
+	if tx.Metadata()[ports.KYCUnverifiedOwnerMetadataKey] != "REJECTED" {
+		t.Errorf("metadata = %v, want %s=REJECTED", tx.Metadata(), ports.KYCUnverifiedOwnerMetadataKey)
+	}
+	if len(evts) != 1 {
+		t.Fatalf("events = %d, want 1", len(evts))
+	}
+	flagged, ok := evts[0].(*events.TransactionFlagged)
+	if !ok {
+		t.Fatalf("event = %T, want *events.TransactionFlagged", evts[0])
+	}
+	if flagged.TransactionID != tx.ID() || len(flagged.RuleIDs) != 1 || flagged.RuleIDs[0] != kyc.RuleNotVerified {
+		t.Errorf("TransactionFlagged = %+v", flagged)
+	}
+}
+
+// TestGate_CacheRefreshedByEvents тестирует, что закэшированный статус
+// обновляют KYC события, не дожидаясь истечения TTL.
+func TestGate_CacheRefreshedByEvents(t *testing.T) {
+	f := newGateFixture(t)
+	gate := newGate(t, kyc.ModeStrict, time.Hour, f.users)
+	ctx := context.Background()
+
+	// Статус VERIFIED закэширован первой проверкой
+	if _, err := gate.CheckOwner(ctx, f.withdrawal(t), f.wallet); err != nil {
+		t.Fatalf("CheckOwner() error = %v", err)
+	}
+
+	// Отзыв без события: до истечения TTL действует кэш
+	f.revoke(t)
+	if _, err := gate.CheckOwner(ctx, f.withdrawal(t), f.wallet); err != nil {
+		t.Fatalf("cached status: CheckOwner() error = %v", err)
+	}
+
+	// Событие отзыва обновляет кэш сразу
+	if err := gate.HandleEvent(ctx, events.NewUserKYCRevoked(f.owner.ID(), "Sanctions list match", uuid.New())); err != nil {
+		t.Fatalf("HandleEvent() error = %v", err)
+	}
+	if _, err := gate.CheckOwner(ctx, f.withdrawal(t), f.wallet); err == nil {
+		t.Fatal("Expected rejection after UserKYCRevoked")
+	}
+
+	// Повторное одобрение: кэш снова VERIFIED
+	if err := gate.HandleEvent(ctx, events.NewUserKYCApproved(f.owner.ID(), uuid.New())); err != nil {
+		t.Fatalf("HandleEvent() error = %v", err)
+	}
+	if _, err := gate.CheckOwner(ctx, f.withdrawal(t), f.wallet); err != nil {
+		t.Fatalf("after UserKYCApproved: CheckOwner() error = %v", err)
+	}
+
+	// Отказ при повторной проверке тоже обновляет кэш
+	if err := gate.HandleEvent(ctx, events.NewUserKYCRejected(f.owner.ID(), "Document expired", uuid.New())); err != nil {
+		t.Fatalf("HandleEvent() error = %v", err)
+	}
+	if _, err := gate.CheckOwner(ctx, f.withdrawal(t), f.wallet); err == nil {
+		t.Fatal("Expected rejection after UserKYCRejected")
+	}
+
+	// Прочие события не влияют на кэш
+	if err := gate.HandleEvent(ctx, events.NewUserCreated(f.owner.ID(), "x@example.com", "X")); err != nil {
+		t.Fatalf("HandleEvent() error = %v", err)
+	}
+}
+
+// TestGate_CacheExpires тестирует, что без события отзыв вступает в силу
+// после истечения TTL (другие экземпляры не получают события шины).
+func TestGate_CacheExpires(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC))
+	defer clock.SetClock(fake)()
+
+	f := newGateFixture(t)
+	gate := newGate(t, kyc.ModeStrict, time.Minute, f.users)
+	ctx := context.Background()
+
+	if _, err := gate.CheckOwner(ctx, f.withdrawal(t), f.wallet); err != nil {
+		t.Fatalf("CheckOwner() error = %v", err)
+	}
+	f.revoke(t)
+
+	fake.Advance(59 * time.Second)
+	if _, err := gate.CheckOwner(ctx, f.withdrawal(t), f.wallet); err != nil {
+		t.Fatalf("before TTL: CheckOwner() error = %v", err)
+	}
+
+	fake.Advance(time.Second)
+	if _, err := gate.CheckOwner(ctx, f.withdrawal(t), f.wallet); err == nil {
+		t.Fatal("Expected rejection after TTL")
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    kyc.Mode
+		wantErr bool
+	}{
+		{"", kyc.ModeLenient, false},
+		{"lenient", kyc.ModeLenient, false},
+		{" STRICT ", kyc.ModeStrict, false},
+		{"off", "", true},
+	}
+	for _, tt := range tests {
+		got, err := kyc.ParseMode(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMode(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+
+	if _, err := kyc.NewGate(kyc.Policy{Mode: "off"}, nil); err == nil {
+		t.Error("NewGate() expected error for unknown mode")
+	}
+	if _, err := kyc.NewGate(kyc.Policy{Mode: kyc.ModeStrict, CacheTTL: -time.Second}, nil); err == nil {
+		t.Error("NewGate() expected error for negative TTL")
+	}
+}
@@ -0,0 +1,132 @@
+// Package operations - аварийные выключатели денежных операций.
+//
+// Во время инцидента администратор выключает вид операций (DEPOSIT,
+// WITHDRAW, TRANSFER, PAYOUT) с причиной; use cases этого вида отклоняют
+// запросы с OPERATION_TEMPORARILY_DISABLED (503), остальные работают.
+// Состояние хранится в system_settings и кэшируется каждым экземпляром на
+// RefreshInterval: выключение на одном экземпляре доходит до остальных не
+// позже чем через интервал, на самом экземпляре - сразу (Invalidate).
+package operations
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// DefaultRefreshInterval - как часто перечитываются выключатели по умолчанию.
+const DefaultRefreshInterval = 5 * time.Second
+
+// Gate реализует ports.OperationGate поверх OperationSwitchRepository
+// с кэшем в памяти.
+//
+// Кэш обновляется при проверке, если он старше refresh. Ошибка чтения
+// не роняет операции: остаётся последнее известное состояние и следующая
+// попытка - через refresh. Только если состояние ещё ни разу не читалось,
+// RequireEnabled возвращает ошибку чтения.
+type Gate struct {
+	repo    ports.OperationSwitchRepository
+	refresh time.Duration
+	now     func() time.Time
+
+	mu       sync.Mutex
+	switches map[entities.TransactionType]*entities.OperationSwitch
+	loaded   bool
+	loadedAt time.Time
+}
+
+// Compile-time check
+var _ ports.OperationGate = (*Gate)(nil)
+
+// NewGate создаёт Gate. refresh <= 0 - DefaultRefreshInterval.
+func NewGate(repo ports.OperationSwitchRepository, refresh time.Duration) *Gate {
+	if refresh <= 0 {
+		refresh = DefaultRefreshInterval
+	}
+	return &Gate{
+		repo:    repo,
+		refresh: refresh,
+		now:     time.Now,
+	}
+}
+
+// RequireEnabled проверяет выключатели операции txType.
+func (g *Gate) RequireEnabled(ctx context.Context, txType entities.TransactionType) error {
+	required := entities.OperationSwitchesFor(txType)
+	if len(required) == 0 {
+		return nil
+	}
+
+	switches, err := g.current(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, operation := range required {
+		if sw, ok := switches[operation]; ok && !sw.Enabled() {
+			return &errors.OperationDisabledError{Operation: string(operation), Reason: sw.Reason()}
+		}
+	}
+	return nil
+}
+
+// Disabled возвращает выключенные операции в порядке entities.SwitchableOperations.
+// Если состояние прочитать не удалось, возвращает nil.
+func (g *Gate) Disabled(ctx context.Context) []*entities.OperationSwitch {
+	switches, err := g.current(ctx)
+	if err != nil {
+		return nil
+	}
+
+	var disabled []*entities.OperationSwitch
+	for _, operation := range entities.SwitchableOperations {
+		if sw, ok := switches[operation]; ok && !sw.Enabled() {
+			disabled = append(disabled, sw)
+		}
+	}
+	return disabled
+}
+
+// Invalidate сбрасывает кэш.
+func (g *Gate) Invalidate() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.loadedAt = time.Time{}
+}
+
+// current возвращает кэш, перечитывая его, если он устарел. Чтение идёт
+// под мьютексом: одновременные запросы не читают хранилище повторно.
+func (g *Gate) current(ctx context.Context) (map[entities.TransactionType]*entities.OperationSwitch, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	if g.loaded && now.Sub(g.loadedAt) < g.refresh {
+		return g.switches, nil
+	}
+
+	list, err := g.repo.FindAll(ctx)
+	if err != nil {
+		if !g.loaded {
+			return nil, fmt.Errorf("failed to load operation switches: %w", err)
+		}
+		// Последнее известное состояние; повтор - через refresh
+		g.loadedAt = now
+		return g.switches, nil
+	}
+
+	switches := make(map[entities.TransactionType]*entities.OperationSwitch, len(list))
+	for _, sw := range list {
+		switches[sw.Operation()] = sw
+	}
+	g.switches = switches
+	g.loaded = true
+	g.loadedAt = now
+	return switches, nil
+}
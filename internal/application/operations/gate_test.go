@@ -0,0 +1,115 @@
+package operations
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// newTestGate - gate с управляемыми часами поверх памяти.
+func newTestGate(repo ports.OperationSwitchRepository) (*Gate, *time.Time) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	gate := NewGate(repo, time.Second)
+	gate.now = func() time.Time { return now }
+	return gate, &now
+}
+
+func setSwitch(t *testing.T, repo ports.OperationSwitchRepository, operation entities.TransactionType, enabled bool, reason string) {
+	t.Helper()
+	sw, err := entities.NewOperationSwitch(operation, enabled, reason, uuid.New())
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(context.Background(), sw))
+}
+
+func TestGate_RequireEnabled(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewOperationSwitchRepository(memory.NewStore())
+	gate, _ := newTestGate(repo)
+
+	for _, txType := range []entities.TransactionType{entities.TransactionTypeDeposit, entities.TransactionTypeWithdraw, entities.TransactionTypePayout} {
+		assert.NoError(t, gate.RequireEnabled(ctx, txType), "no switches means %s enabled", txType)
+	}
+
+	setSwitch(t, repo, entities.TransactionTypeWithdraw, false, "provider incident")
+	gate.Invalidate()
+
+	for _, txType := range []entities.TransactionType{entities.TransactionTypeWithdraw, entities.TransactionTypePayout} {
+		err := gate.RequireEnabled(ctx, txType)
+		require.True(t, errors.IsOperationDisabled(err), "%s: expected OperationDisabledError, got %v", txType, err)
+
+		var disabled *errors.OperationDisabledError
+		require.True(t, stdErrors.As(err, &disabled))
+		assert.Equal(t, "WITHDRAW", disabled.Operation)
+		assert.Equal(t, "provider incident", disabled.Reason)
+	}
+	assert.NoError(t, gate.RequireEnabled(ctx, entities.TransactionTypeDeposit))
+	assert.NoError(t, gate.RequireEnabled(ctx, entities.TransactionTypeTransfer))
+
+	disabled := gate.Disabled(ctx)
+	require.Len(t, disabled, 1)
+	assert.Equal(t, entities.TransactionTypeWithdraw, disabled[0].Operation())
+
+	setSwitch(t, repo, entities.TransactionTypeWithdraw, true, "")
+	gate.Invalidate()
+
+	assert.NoError(t, gate.RequireEnabled(ctx, entities.TransactionTypeWithdraw))
+	assert.NoError(t, gate.RequireEnabled(ctx, entities.TransactionTypePayout))
+	assert.Empty(t, gate.Disabled(ctx))
+}
+
+func TestGate_PicksUpChangesWithinRefreshInterval(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewOperationSwitchRepository(memory.NewStore())
+	gate, now := newTestGate(repo)
+
+	require.NoError(t, gate.RequireEnabled(ctx, entities.TransactionTypeDeposit))
+
+	// Изменение с другого экземпляра: кэш ещё свежий
+	setSwitch(t, repo, entities.TransactionTypeDeposit, false, "bank maintenance")
+	assert.NoError(t, gate.RequireEnabled(ctx, entities.TransactionTypeDeposit))
+
+	*now = now.Add(time.Second)
+	assert.True(t, errors.IsOperationDisabled(gate.RequireEnabled(ctx, entities.TransactionTypeDeposit)))
+}
+
+// flakyRepository отказывает в чтении, пока fail = true.
+type flakyRepository struct {
+	ports.OperationSwitchRepository
+	fail bool
+}
+
+func (r *flakyRepository) FindAll(ctx context.Context) ([]*entities.OperationSwitch, error) {
+	if r.fail {
+		return nil, stdErrors.New("database unavailable")
+	}
+	return r.OperationSwitchRepository.FindAll(ctx)
+}
+
+func TestGate_ReadFailure(t *testing.T) {
+	ctx := context.Background()
+	repo := &flakyRepository{OperationSwitchRepository: memory.NewOperationSwitchRepository(memory.NewStore()), fail: true}
+	gate, now := newTestGate(repo)
+
+	// Состояние ни разу не читалось - ошибка
+	require.Error(t, gate.RequireEnabled(ctx, entities.TransactionTypeTransfer))
+
+	repo.fail = false
+	setSwitch(t, repo, entities.TransactionTypeTransfer, false, "ledger migration")
+	require.True(t, errors.IsOperationDisabled(gate.RequireEnabled(ctx, entities.TransactionTypeTransfer)))
+
+	// Хранилище недоступно - остаётся последнее известное состояние
+	repo.fail = true
+	*now = now.Add(time.Minute)
+	assert.True(t, errors.IsOperationDisabled(gate.RequireEnabled(ctx, entities.TransactionTypeTransfer)))
+	assert.Len(t, gate.Disabled(ctx), 1)
+}
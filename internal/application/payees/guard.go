@@ -0,0 +1,181 @@
+// Package payees - ограничения переводов на кошельки, на которые источник
+// ещё не переводил.
+//
+// Первый перевод новому получателю может требовать явного подтверждения
+// (confirm_new_payee), а переводы новому получателю ограничиваются суммой
+// в эквиваленте LimitCurrency: лимит действует на все переводы ему в окне.
+// Завершённый перевод запоминает получателя в wallet_payees; получатель
+// остаётся новым ещё Window после первого перевода, потом переводы ему
+// проходят без ограничений.
+package payees
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// Правила, которыми отклоняются переводы новому получателю.
+const (
+	RuleConfirmationRequired = "NEW_PAYEE_CONFIRMATION_REQUIRED"
+	RuleLimitExceeded        = "NEW_PAYEE_LIMIT_EXCEEDED"
+)
+
+// Policy - настройки проверки новых получателей.
+type Policy struct {
+	// Enabled включает проверку. Выключенная проверка всё равно запоминает
+	// получателей, чтобы после включения знакомые кошельки не считались новыми.
+	Enabled bool
+
+	// RequireConfirmation - первый перевод требует confirm_new_payee=true.
+	RequireConfirmation bool
+
+	// MaxAmount - максимум в LimitCurrency для всех переводов новому
+	// получателю в окне (ожидающие и завершённые плюс текущий); "" - без
+	// лимита. Суммы в других валютах конвертируются по текущему курсу.
+	MaxAmount     string
+	LimitCurrency string
+
+	// Window - сколько после первого перевода получатель считается новым:
+	// подтверждение и лимит действуют до first_transfer_at + Window.
+	// 0 - ограничения снимаются первым завершённым переводом.
+	Window time.Duration
+}
+
+// Guard реализует ports.NewPayeeGuard.
+type Guard struct {
+	policy    Policy
+	maxAmount *big.Rat // nil - без лимита
+	payees    ports.PayeeRepository
+	rates     ports.ExchangeRateProvider // nil - лимит только для LimitCurrency
+}
+
+// Compile-time check
+var _ ports.NewPayeeGuard = (*Guard)(nil)
+
+// NewGuard проверяет политику и создаёт Guard.
+func NewGuard(policy Policy, payees ports.PayeeRepository, rates ports.ExchangeRateProvider) (*Guard, error) {
+	g := &Guard{policy: policy, payees: payees, rates: rates}
+
+	if policy.Window < 0 {
+		return nil, fmt.Errorf("new payee window must not be negative, got %s", policy.Window)
+	}
+	if policy.MaxAmount == "" {
+		return g, nil
+	}
+
+	maxAmount, ok := new(big.Rat).SetString(policy.MaxAmount)
+	if !ok || maxAmount.Sign() <= 0 {
+		return nil, fmt.Errorf("new payee max amount must be a positive decimal, got %q", policy.MaxAmount)
+	}
+	if _, err := valueobjects.NewCurrency(policy.LimitCurrency); err != nil {
+		return nil, fmt.Errorf("new payee limit currency: %w", err)
+	}
+	g.maxAmount = maxAmount
+
+	return g, nil
+}
+
+// Check пропускает переводы знакомым получателям и применяет политику к новым,
+// в том числе в окне после первого перевода.
+func (g *Guard) Check(ctx context.Context, req *ports.NewPayeeRequest) error {
+	if !g.policy.Enabled {
+		return nil
+	}
+
+	tx := req.Transaction
+	destinationID := tx.DestinationWalletID()
+	if destinationID == nil {
+		return nil
+	}
+
+	firstTransferAt, known, err := g.payees.FirstTransferAt(ctx, tx.WalletID(), *destinationID)
+	if err != nil {
+		return fmt.Errorf("failed to check payee: %w", err)
+	}
+	// Маленький первый перевод не снимает ограничения сразу
+	if known && !clock.Now().Before(firstTransferAt.Add(g.policy.Window)) {
+		return nil
+	}
+
+	if g.policy.RequireConfirmation && !req.Confirmed {
+		return errors.NewBusinessRuleViolation(
+			RuleConfirmationRequired,
+			"transfer to a new payee requires confirm_new_payee",
+			map[string]interface{}{"destination_wallet_id": destinationID.String()},
+		)
+	}
+
+	if g.maxAmount == nil {
+		return nil
+	}
+
+	// Несколько переводов под лимитом не должны обходить его в сумме. Нижняя
+	// граница не нужна: до записи получателя завершённых переводов ему нет,
+	// а первый перевод создан раньше first_transfer_at и тоже в окне.
+	transferred, err := g.payees.TransferredTo(ctx, tx.WalletID(), *destinationID, tx.Amount().Currency())
+	if err != nil {
+		return fmt.Errorf("failed to sum transfers to payee: %w", err)
+	}
+	total, err := transferred.Add(tx.Amount())
+	if err != nil {
+		return err
+	}
+
+	amount, err := g.inLimitCurrency(ctx, total)
+	if err != nil {
+		return err
+	}
+	if amount.Cmp(g.maxAmount) > 0 {
+		limit := g.maxAmount.FloatString(2)
+		return errors.NewBusinessRuleViolation(
+			RuleLimitExceeded,
+			fmt.Sprintf("transfers to a new payee are limited to %s %s in total", limit, g.policy.LimitCurrency),
+			map[string]interface{}{
+				"destination_wallet_id": destinationID.String(),
+				"limit":                 limit,
+				"limit_currency":        g.policy.LimitCurrency,
+			},
+		)
+	}
+
+	return nil
+}
+
+// RecordTransfer запоминает получателя завершённого перевода.
+func (g *Guard) RecordTransfer(ctx context.Context, tx *entities.Transaction) error {
+	destinationID := tx.DestinationWalletID()
+	if destinationID == nil {
+		return nil
+	}
+
+	if err := g.payees.RecordPayee(ctx, tx.WalletID(), *destinationID, tx.ID()); err != nil {
+		return fmt.Errorf("failed to record payee: %w", err)
+	}
+	return nil
+}
+
+// inLimitCurrency переводит сумму в LimitCurrency.
+func (g *Guard) inLimitCurrency(ctx context.Context, amount valueobjects.Money) (*big.Rat, error) {
+	from := amount.Currency().Code()
+	if from == g.policy.LimitCurrency {
+		return amount.Amount(), nil
+	}
+
+	if g.rates == nil {
+		return nil, fmt.Errorf("no exchange rate provider to convert %s to %s for new payee limit", from, g.policy.LimitCurrency)
+	}
+	rate, err := g.rates.GetRate(ctx, from, g.policy.LimitCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s/%s rate for new payee limit: %w", from, g.policy.LimitCurrency, err)
+	}
+
+	return new(big.Rat).Mul(amount.Amount(), rate), nil
+}
@@ -0,0 +1,218 @@
+package payees
+
+import (
+	"context"
+	stdErrors "errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// fixedRates отдаёт один курс для любой пары валют.
+type fixedRates struct {
+	rate *big.Rat
+}
+
+func (r fixedRates) GetRate(_ context.Context, _, _ string) (*big.Rat, error) {
+	return r.rate, nil
+}
+
+func (r fixedRates) Quote(_ context.Context, _, _ string) (*ports.RateQuote, error) {
+	return &ports.RateQuote{Rate: r.rate, Source: "fixed", FetchedAt: time.Now()}, nil
+}
+
+func newTransfer(t *testing.T, source, destination uuid.UUID, amount, currency string) *entities.Transaction {
+	t.Helper()
+
+	money, err := valueobjects.NewMoney(amount, valueobjects.MustNewCurrency(currency))
+	require.NoError(t, err)
+	tx, err := entities.NewTransaction(source, uuid.NewString(), entities.TransactionTypeTransfer, money, "payee")
+	require.NoError(t, err)
+	require.NoError(t, tx.SetDestinationWallet(destination))
+	return tx
+}
+
+func assertViolation(t *testing.T, err error, rule string) {
+	t.Helper()
+
+	var violation *errors.BusinessRuleViolation
+	require.True(t, stdErrors.As(err, &violation), "expected BusinessRuleViolation, got %v", err)
+	assert.Equal(t, rule, violation.Rule)
+}
+
+func TestGuard_Disabled_SkipsCheckButRecords(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPayeeRepository(memory.NewStore())
+	guard, err := NewGuard(Policy{RequireConfirmation: true, MaxAmount: "10", LimitCurrency: "USD"}, repo, nil)
+	require.NoError(t, err)
+
+	tx := newTransfer(t, uuid.New(), uuid.New(), "1000", "USD")
+	require.NoError(t, guard.Check(ctx, &ports.NewPayeeRequest{Transaction: tx}))
+
+	require.NoError(t, guard.RecordTransfer(ctx, tx))
+	known, err := repo.HasPriorCompletedTransfer(ctx, tx.WalletID(), *tx.DestinationWalletID())
+	require.NoError(t, err)
+	assert.True(t, known)
+}
+
+func TestGuard_RequireConfirmation(t *testing.T) {
+	ctx := context.Background()
+	guard, err := NewGuard(Policy{Enabled: true, RequireConfirmation: true},
+		memory.NewPayeeRepository(memory.NewStore()), nil)
+	require.NoError(t, err)
+
+	tx := newTransfer(t, uuid.New(), uuid.New(), "5", "USD")
+	assertViolation(t, guard.Check(ctx, &ports.NewPayeeRequest{Transaction: tx}), RuleConfirmationRequired)
+	assert.NoError(t, guard.Check(ctx, &ports.NewPayeeRequest{Transaction: tx, Confirmed: true}))
+
+	// Знакомому получателю подтверждение не нужно
+	require.NoError(t, guard.RecordTransfer(ctx, tx))
+	next := newTransfer(t, tx.WalletID(), *tx.DestinationWalletID(), "5", "USD")
+	assert.NoError(t, guard.Check(ctx, &ports.NewPayeeRequest{Transaction: next}))
+}
+
+func TestGuard_MaxAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency string
+		rates    ports.ExchangeRateProvider
+		wantRule string
+	}{
+		{name: "UnderLimit", amount: "500", currency: "USD"},
+		{name: "OverLimit", amount: "500.01", currency: "USD", wantRule: RuleLimitExceeded},
+		{name: "ConvertedUnderLimit", amount: "400", currency: "EUR", rates: fixedRates{big.NewRat(5, 4)}},
+		{name: "ConvertedOverLimit", amount: "401", currency: "EUR", rates: fixedRates{big.NewRat(5, 4)}, wantRule: RuleLimitExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard, err := NewGuard(Policy{Enabled: true, MaxAmount: "500", LimitCurrency: "USD"},
+				memory.NewPayeeRepository(memory.NewStore()), tt.rates)
+			require.NoError(t, err)
+
+			tx := newTransfer(t, uuid.New(), uuid.New(), tt.amount, tt.currency)
+			err = guard.Check(context.Background(), &ports.NewPayeeRequest{Transaction: tx, Confirmed: true})
+			if tt.wantRule == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assertViolation(t, err, tt.wantRule)
+
+			var violation *errors.BusinessRuleViolation
+			require.True(t, stdErrors.As(err, &violation))
+			assert.Equal(t, "500.00", violation.Context["limit"])
+			assert.Equal(t, "USD", violation.Context["limit_currency"])
+		})
+	}
+}
+
+func TestGuard_Window(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	defer clock.SetClock(fake)()
+
+	ctx := context.Background()
+	guard, err := NewGuard(Policy{
+		Enabled:             true,
+		RequireConfirmation: true,
+		MaxAmount:           "500",
+		LimitCurrency:       "USD",
+		Window:              24 * time.Hour,
+	}, memory.NewPayeeRepository(memory.NewStore()), nil)
+	require.NoError(t, err)
+
+	// Маленький первый перевод записывает получателя
+	first := newTransfer(t, uuid.New(), uuid.New(), "1", "USD")
+	require.NoError(t, guard.Check(ctx, &ports.NewPayeeRequest{Transaction: first, Confirmed: true}))
+	require.NoError(t, guard.RecordTransfer(ctx, first))
+
+	large := func() *ports.NewPayeeRequest {
+		return &ports.NewPayeeRequest{
+			Transaction: newTransfer(t, first.WalletID(), *first.DestinationWalletID(), "1000", "USD"),
+			Confirmed:   true,
+		}
+	}
+
+	fake.Advance(23 * time.Hour)
+	assertViolation(t, guard.Check(ctx, large()), RuleLimitExceeded)
+	small := newTransfer(t, first.WalletID(), *first.DestinationWalletID(), "5", "USD")
+	assertViolation(t, guard.Check(ctx, &ports.NewPayeeRequest{Transaction: small}), RuleConfirmationRequired)
+
+	fake.Advance(time.Hour)
+	assert.NoError(t, guard.Check(ctx, large()), "window is over")
+	assert.NoError(t, guard.Check(ctx, &ports.NewPayeeRequest{Transaction: small}))
+}
+
+func TestGuard_MaxAmount_Cumulative(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	guard, err := NewGuard(Policy{Enabled: true, MaxAmount: "500", LimitCurrency: "USD", Window: 24 * time.Hour},
+		memory.NewPayeeRepository(store), nil)
+	require.NoError(t, err)
+
+	newWallet := func(email string) uuid.UUID {
+		user, err := entities.NewUser(email, "Payee Owner")
+		require.NoError(t, err)
+		require.NoError(t, memory.NewUserRepository(store).Save(ctx, user))
+		wallet, err := entities.NewWallet(user.ID(), valueobjects.MustNewCurrency("USD"))
+		require.NoError(t, err)
+		require.NoError(t, memory.NewWalletRepository(store).Save(ctx, wallet))
+		return wallet.ID()
+	}
+	source, destination := newWallet("source@example.com"), newWallet("destination@example.com")
+
+	// Каждый перевод под лимитом, но вместе - больше
+	first := newTransfer(t, source, destination, "300", "USD")
+	require.NoError(t, guard.Check(ctx, &ports.NewPayeeRequest{Transaction: first}))
+	require.NoError(t, memory.NewTransactionRepository(store).Save(ctx, first))
+
+	second := newTransfer(t, source, destination, "300", "USD")
+	assertViolation(t, guard.Check(ctx, &ports.NewPayeeRequest{Transaction: second}), RuleLimitExceeded)
+
+	rest := newTransfer(t, source, destination, "200", "USD")
+	assert.NoError(t, guard.Check(ctx, &ports.NewPayeeRequest{Transaction: rest}))
+
+	// Завершённый первый перевод тоже считается в окне
+	require.NoError(t, first.StartProcessing())
+	require.NoError(t, first.MarkCompleted())
+	require.NoError(t, memory.NewTransactionRepository(store).Save(ctx, first))
+	require.NoError(t, guard.RecordTransfer(ctx, first))
+	assertViolation(t, guard.Check(ctx, &ports.NewPayeeRequest{Transaction: second}), RuleLimitExceeded)
+}
+
+func TestGuard_MaxAmount_NoRatesForForeignCurrency(t *testing.T) {
+	guard, err := NewGuard(Policy{Enabled: true, MaxAmount: "500", LimitCurrency: "USD"},
+		memory.NewPayeeRepository(memory.NewStore()), nil)
+	require.NoError(t, err)
+
+	tx := newTransfer(t, uuid.New(), uuid.New(), "1", "EUR")
+	err = guard.Check(context.Background(), &ports.NewPayeeRequest{Transaction: tx})
+	require.Error(t, err)
+	assert.False(t, errors.IsBusinessRuleViolation(err))
+}
+
+func TestNewGuard_InvalidPolicy(t *testing.T) {
+	repo := memory.NewPayeeRepository(memory.NewStore())
+
+	for _, policy := range []Policy{
+		{MaxAmount: "abc", LimitCurrency: "USD"},
+		{MaxAmount: "0", LimitCurrency: "USD"},
+		{MaxAmount: "-5", LimitCurrency: "USD"},
+		{MaxAmount: "100", LimitCurrency: "XXX1"},
+		{Window: -time.Hour},
+	} {
+		_, err := NewGuard(policy, repo, nil)
+		assert.Error(t, err, "policy %+v", policy)
+	}
+}
@@ -0,0 +1,46 @@
+// Package ports - Actor: кто выполняет операцию.
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Actor - инициатор операции (авторизованный пользователь или администратор).
+//
+// Adapter (HTTP auth middleware) кладёт Actor в context, use cases
+// читают его через ActorFromContext, не зная о способе аутентификации.
+type Actor struct {
+	ID   uuid.UUID
+	Role string
+}
+
+// IsAdmin возвращает true для администратора (superadmin - тоже администратор).
+func (a Actor) IsAdmin() bool {
+	return a.Role == "admin" || a.IsSuperAdmin()
+}
+
+// IsSuperAdmin возвращает true для superadmin: администратора с правом
+// на операции над чужими записями (например, удаление чужих заметок).
+func (a Actor) IsSuperAdmin() bool {
+	return a.Role == "superadmin"
+}
+
+// actorKey - ключ Actor в context.
+type actorKey struct{}
+
+// WithActor возвращает context с Actor.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext извлекает Actor из context.
+// false - Actor не задан или без ID.
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	if !ok || actor.ID == uuid.Nil {
+		return Actor{}, false
+	}
+	return actor, true
+}
@@ -0,0 +1,27 @@
+// Package ports - BuildInfo: версия сборки, которая создаёт записи.
+package ports
+
+// unknownGitCommit - значение gitCommit в main.go, если сборка без ldflags.
+const unknownGitCommit = "unknown"
+
+// BuildInfo - версия бинарника (ldflags в cmd/api/main.go).
+//
+// Use cases штампуют ею создаваемые транзакции, outbox - события. Значение
+// берётся только из сборки и никогда из запроса, поэтому по нему можно
+// восстановить, какой код создал запись.
+type BuildInfo struct {
+	Version   string
+	GitCommit string
+}
+
+// ProducerVersion возвращает версию в формате "version+commit"
+// ("version" без известного коммита, "" без версии - запись без штампа).
+func (b BuildInfo) ProducerVersion() string {
+	if b.Version == "" {
+		return ""
+	}
+	if b.GitCommit == "" || b.GitCommit == unknownGitCommit {
+		return b.Version
+	}
+	return b.Version + "+" + b.GitCommit
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildInfo_ProducerVersion(t *testing.T) {
+	assert.Equal(t, "1.4.0+abc1234", BuildInfo{Version: "1.4.0", GitCommit: "abc1234"}.ProducerVersion())
+	assert.Equal(t, "1.4.0", BuildInfo{Version: "1.4.0", GitCommit: "unknown"}.ProducerVersion())
+	assert.Equal(t, "1.4.0", BuildInfo{Version: "1.4.0"}.ProducerVersion())
+	assert.Empty(t, BuildInfo{GitCommit: "abc1234"}.ProducerVersion())
+}
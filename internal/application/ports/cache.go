@@ -26,3 +26,14 @@ type DistributedLock interface {
 	// to prevent releasing a lock held by another caller.
 	Release(ctx context.Context, key string, token string) error
 }
+
+// ConfirmationStore keeps one-time confirmation tokens for destructive
+// admin operations. A token is bound to the fingerprint of the request it
+// was issued for (method, path, body hash) and expires after ttl.
+type ConfirmationStore interface {
+	// Issue stores token for the given request fingerprint.
+	Issue(ctx context.Context, token, fingerprint string, ttl time.Duration) error
+	// Consume atomically removes token and returns its fingerprint.
+	// found is false if the token is unknown, expired or already used.
+	Consume(ctx context.Context, token string) (fingerprint string, found bool, err error)
+}
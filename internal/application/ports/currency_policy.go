@@ -0,0 +1,65 @@
+// Package ports - CurrencyPolicy: валюты новых кошельков и лимиты сумм.
+package ports
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// CurrencyPolicy - в каких поддерживаемых валютах можно открыть кошелёк
+// (config currencies.wallet_currencies) и лимиты суммы одной транзакции
+// по валютам (config currencies.limits).
+//
+// Клиенты получают политику вместе с реестром валют из
+// GET /api/v1/meta/currencies, чтобы проверять ввод суммы у себя.
+type CurrencyPolicy struct {
+	WalletCurrencies []string                // пусто - все поддерживаемые валюты
+	Limits           map[string]AmountLimits // по коду валюты; нет записи - без лимитов
+}
+
+// AmountLimits - лимиты суммы одной транзакции в валюте ("" - без лимита).
+type AmountLimits struct {
+	Min string
+	Max string
+}
+
+// AllowsWallet сообщает, можно ли сейчас открыть кошелёк в валюте.
+func (p CurrencyPolicy) AllowsWallet(currency valueobjects.Currency) bool {
+	if len(p.WalletCurrencies) == 0 {
+		return true
+	}
+	for _, code := range p.WalletCurrencies {
+		if strings.EqualFold(code, currency.Code()) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckWallet возвращает CURRENCY_NOT_ALLOWED (422), если валюта
+// закрыта для новых кошельков. Существующие кошельки не затрагиваются.
+func (p CurrencyPolicy) CheckWallet(currency valueobjects.Currency) error {
+	if p.AllowsWallet(currency) {
+		return nil
+	}
+	return errors.NewBusinessRuleViolation(
+		"CURRENCY_NOT_ALLOWED",
+		fmt.Sprintf("new wallets in %s are not allowed", currency.Code()),
+		map[string]interface{}{"currency": currency.Code()},
+	)
+}
+
+// Current реализует CurrencyPolicySource для неизменной политики.
+func (p CurrencyPolicy) Current() CurrencyPolicy {
+	return p
+}
+
+// CurrencyPolicySource отдаёт текущую политику валют. Читается на каждый
+// запрос, поэтому источник, перечитывающий конфигурацию на лету, меняет
+// ответы и проверки без перезапуска.
+type CurrencyPolicySource interface {
+	Current() CurrencyPolicy
+}
@@ -0,0 +1,33 @@
+// Package ports - DisplayCurrency: пересчёт балансов в валюту отображения.
+package ports
+
+import "context"
+
+// CurrencyPair - направление пересчёта: 1 From = rate To.
+type CurrencyPair struct {
+	From string
+	To   string
+}
+
+// DisplayRates отдаёт курсы для пересчёта балансов в валюту отображения
+// кошелька (WalletSettings.DisplayCurrency). Курсы только для показа: по
+// ним не проводятся операции и не сохраняются снимки.
+type DisplayRates interface {
+	// Quotes возвращает курсы пар. Пары без курса (провайдер недоступен,
+	// пара не поддерживается) в map отсутствуют: ответ отдаётся без
+	// пересчёта, а не с ошибкой.
+	Quotes(ctx context.Context, pairs []CurrencyPair) map[CurrencyPair]*RateQuote
+}
+
+// DisplayCurrency - зависимости пересчёта балансов в валюту отображения для
+// GetWallet, ListWallets и GetWalletBalance. Нулевое значение - пересчёт
+// выключен.
+type DisplayCurrency struct {
+	Settings WalletSettingsRepository
+	Rates    DisplayRates
+}
+
+// Enabled возвращает true, если пересчёт настроен.
+func (d DisplayCurrency) Enabled() bool {
+	return d.Settings != nil && d.Rates != nil
+}
@@ -0,0 +1,34 @@
+// Package ports - DedupStore: учёт событий, обработанных потребителями.
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DedupStore - журнал обработанных событий (consumer, event_id).
+//
+// Доставка событий at-least-once: outbox переотправляет событие, если не
+// успел пометить его опубликованным. Внутренние потребители (проекции,
+// webhook/notification dispatcher) подключают дедупликацию через
+// eventbus.WithDedup вместо собственной реализации.
+//
+// Контракт (проверяется porttest.RunDedupStoreTests):
+//   - MarkProcessed атомарен: из параллельных вызовов для одной пары
+//     true получает ровно один
+//   - Потребители независимы: одно событие отмечается отдельно для каждого
+//   - Внутри UnitOfWork запись участвует в транзакции ctx и откатывается с ней
+type DedupStore interface {
+	// MarkProcessed отмечает событие обработанным потребителем.
+	// false - событие уже было отмечено, обрабатывать его не нужно.
+	MarkProcessed(ctx context.Context, consumer string, eventID uuid.UUID) (bool, error)
+
+	// Unmark снимает отметку, чтобы повторная доставка обработала событие
+	// (обработчик вне транзакции завершился ошибкой).
+	Unmark(ctx context.Context, consumer string, eventID uuid.UUID) error
+
+	// DeleteBefore удаляет отметки старше cutoff и возвращает число удалённых.
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
@@ -10,6 +10,10 @@ package ports
 
 import (
 	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
 
 	"github.com/Haleralex/wallethub/internal/domain/events"
 )
@@ -30,15 +34,23 @@ type EventPublisher interface {
 	// - Consumers должны быть идемпотентными!
 	//
 	// Example:
-	//   event := events.NewWalletCredited(walletID, amount, txID, balance)
+	//   event := events.NewWalletCredited(walletID, amount, txID, balance, version)
 	//   err := publisher.Publish(ctx, event)
 	Publish(ctx context.Context, event events.DomainEvent) error
 
 	// PublishBatch публикует несколько событий за один вызов.
-	// Более эффективно для множественных событий.
+	// Use cases с несколькими событиями публикуют их одним PublishBatch.
 	//
-	// Важно: Если один event не удаётся опубликовать, вся batch должна провалиться
-	// (атомарность на уровне batch).
+	// Контракт:
+	// - Порядок: события одного aggregate доставляются в порядке слайса
+	// - Дубликаты: повтор EventID внутри batch публикуется один раз
+	//   (первое вхождение, см. DedupeEvents)
+	// - Атомарность: если backend умеет (outbox - один INSERT), batch
+	//   публикуется целиком или не публикуется вовсе
+	// - Без атомарности backend публикует по порядку, останавливается на первой
+	//   ошибке и возвращает *PartialPublishError с опубликованными и
+	//   неопубликованными событиями
+	// - Пустой batch - no-op
 	//
 	// Example:
 	//   events := []events.DomainEvent{
@@ -49,6 +61,85 @@ type EventPublisher interface {
 	PublishBatch(ctx context.Context, events []events.DomainEvent) error
 }
 
+// DedupeEvents убирает повторы EventID, сохраняя порядок первых вхождений.
+// Возвращает исходный слайс, если дубликатов нет.
+func DedupeEvents(evts []events.DomainEvent) []events.DomainEvent {
+	seen := make(map[uuid.UUID]struct{}, len(evts))
+	for i, event := range evts {
+		if _, dup := seen[event.EventID()]; dup {
+			// Дубликат найден - копируем уникальные события в новый слайс
+			unique := append(make([]events.DomainEvent, 0, len(evts)-1), evts[:i]...)
+			for _, rest := range evts[i+1:] {
+				if _, dup := seen[rest.EventID()]; !dup {
+					seen[rest.EventID()] = struct{}{}
+					unique = append(unique, rest)
+				}
+			}
+			return unique
+		}
+		seen[event.EventID()] = struct{}{}
+	}
+	return evts
+}
+
+// FailedEvent - событие, которое не удалось опубликовать.
+type FailedEvent struct {
+	EventID   uuid.UUID
+	EventType string
+	Err       error // nil - не публиковалось после более ранней ошибки
+}
+
+// PartialPublishError возвращается PublishBatch backend'а без атомарности,
+// когда часть batch уже опубликована.
+//
+// Published - опубликованные события в порядке batch. Failed - первое
+// упавшее событие (с Err) и все следующие за ним (Err == nil, не отправлялись,
+// чтобы не нарушить порядок). Повтор Failed событий безопасен: consumers
+// идемпотентны по EventID.
+type PartialPublishError struct {
+	Published []uuid.UUID
+	Failed    []FailedEvent
+}
+
+// NewPartialPublishError собирает ошибку для batch, упавшего на событии failedAt.
+func NewPartialPublishError(batch []events.DomainEvent, failedAt int, err error) *PartialPublishError {
+	partial := &PartialPublishError{}
+	for _, event := range batch[:failedAt] {
+		partial.Published = append(partial.Published, event.EventID())
+	}
+	for i, event := range batch[failedAt:] {
+		failed := FailedEvent{EventID: event.EventID(), EventType: event.EventType()}
+		if i == 0 {
+			failed.Err = err
+		}
+		partial.Failed = append(partial.Failed, failed)
+	}
+	return partial
+}
+
+func (e *PartialPublishError) Error() string {
+	if len(e.Failed) == 0 {
+		return "partial publish: no failed events"
+	}
+	first := e.Failed[0]
+	return fmt.Sprintf("partial publish: %d of %d events failed, first %s (%s): %v",
+		len(e.Failed), len(e.Published)+len(e.Failed), first.EventType, first.EventID, first.Err)
+}
+
+// Unwrap возвращает причину сбоя (ошибку первого упавшего события).
+func (e *PartialPublishError) Unwrap() error {
+	if len(e.Failed) == 0 {
+		return nil
+	}
+	return e.Failed[0].Err
+}
+
+// IsPartialPublishError проверяет, является ли ошибка PartialPublishError.
+func IsPartialPublishError(err error) bool {
+	var partial *PartialPublishError
+	return errors.As(err, &partial)
+}
+
 // EventSubscriber определяет контракт для подписки на события (consumers).
 // Будет использоваться в Phase 6 для обработчиков событий.
 //
@@ -98,6 +189,14 @@ type OutboxRepository interface {
 	// Используется poller'ом для публикации.
 	FindUnpublished(ctx context.Context, limit int) ([]events.DomainEvent, error)
 
+	// FindUnpublishedByPriority возвращает PENDING события полосы priority
+	// вместе со всеми более ранними PENDING событиями тех же агрегатов:
+	// порядок внутри агрегата важнее приоритета. Результат - в порядке записи.
+	FindUnpublishedByPriority(ctx context.Context, priority OutboxPriority, limit int) ([]events.DomainEvent, error)
+
+	// CountPending возвращает число PENDING событий по полосам (для метрик).
+	CountPending(ctx context.Context) (map[OutboxPriority]int, error)
+
 	// MarkPublished помечает событие как опубликованное.
 	// После этого poller не будет пытаться публиковать его снова.
 	MarkPublished(ctx context.Context, eventID string) error
@@ -0,0 +1,55 @@
+package ports
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+func newEvent() events.DomainEvent {
+	id := uuid.New()
+	return events.NewUserCreated(id, id.String()+"@example.com", "Test User")
+}
+
+func TestDedupeEvents(t *testing.T) {
+	a, b, c := newEvent(), newEvent(), newEvent()
+
+	t.Run("NoDuplicates", func(t *testing.T) {
+		batch := []events.DomainEvent{a, b, c}
+		assert.Equal(t, batch, DedupeEvents(batch))
+	})
+
+	t.Run("KeepsFirstOccurrence", func(t *testing.T) {
+		batch := []events.DomainEvent{a, b, a, c, b}
+		assert.Equal(t, []events.DomainEvent{a, b, c}, DedupeEvents(batch))
+		assert.Equal(t, []events.DomainEvent{a, b, a, c, b}, batch, "input must not be modified")
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		assert.Empty(t, DedupeEvents(nil))
+	})
+}
+
+func TestPartialPublishError(t *testing.T) {
+	a, b, c := newEvent(), newEvent(), newEvent()
+	cause := errors.New("broker unavailable")
+
+	err := NewPartialPublishError([]events.DomainEvent{a, b, c}, 1, cause)
+
+	assert.Equal(t, []uuid.UUID{a.EventID()}, err.Published)
+	require.Len(t, err.Failed, 2)
+	assert.Equal(t, b.EventID(), err.Failed[0].EventID)
+	assert.Equal(t, cause, err.Failed[0].Err)
+	assert.Equal(t, c.EventID(), err.Failed[1].EventID)
+	assert.Nil(t, err.Failed[1].Err, "events after the failure are not attempted")
+
+	var wrapped error = err
+	assert.True(t, IsPartialPublishError(wrapped))
+	assert.ErrorIs(t, wrapped, cause)
+	assert.Contains(t, err.Error(), "2 of 3 events failed")
+}
@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 	"math/big"
+	"time"
 )
 
 // ExchangeRateProvider provides currency exchange rates.
@@ -11,4 +12,16 @@ type ExchangeRateProvider interface {
 	// The rate represents how much of 'to' currency you get for 1 unit of 'from' currency.
 	// Example: GetRate(ctx, "USD", "EUR") might return 0.92 (1 USD = 0.92 EUR).
 	GetRate(ctx context.Context, from, to string) (*big.Rat, error)
+
+	// Quote returns the same rate as GetRate together with where it came from.
+	// Mutating operations use Quote and record the result as an
+	// entities.FXRateSnapshot.
+	Quote(ctx context.Context, from, to string) (*RateQuote, error)
+}
+
+// RateQuote is an exchange rate with its source metadata.
+type RateQuote struct {
+	Rate      *big.Rat
+	Source    string    // Provider name, e.g. "exchangerate-api.com"
+	FetchedAt time.Time // Provider's timestamp of the rate (fetch time if it has none)
 }
@@ -0,0 +1,37 @@
+// Package ports - FailedRequestRecorder: образцы неудачных денежных операций.
+package ports
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FailedRequestCapture - запрос и ответ неудачной операции, как их видел
+// HTTP middleware. Тела ещё не очищены от чувствительных данных и обрезаны
+// по лимиту размера (Truncated).
+type FailedRequestCapture struct {
+	Method       string
+	Route        string // Шаблон маршрута: /api/v1/wallets/{id}/debit
+	StatusCode   int
+	RequestID    string
+	ActorID      uuid.UUID // uuid.Nil - запрос без аутентификации
+	RequestBody  []byte
+	ResponseBody []byte
+	Truncated    bool
+	OccurredAt   time.Time
+}
+
+// FailedRequestRecorder сохраняет образцы неудачных денежных операций
+// для поддержки.
+//
+// Контракт:
+//   - Record никогда не блокирует запрос: образец ставится в очередь,
+//     а при переполненной очереди отбрасывается (с метрикой)
+//   - Очистка тел от чувствительных данных и запись выполняются вне
+//     запроса; ошибка записи не возвращается вызывающему
+//   - Буферы тел переходят во владение recorder'а: вызывающий их больше
+//     не изменяет
+type FailedRequestRecorder interface {
+	Record(capture FailedRequestCapture)
+}
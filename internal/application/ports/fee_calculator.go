@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// FeeQuoteRequest contains details of an outgoing transaction to price.
+type FeeQuoteRequest struct {
+	UserID              string
+	WalletID            string
+	DestinationWalletID string // Empty unless TRANSFER
+	TransactionType     entities.TransactionType
+	Amount              valueobjects.Money // Gross amount requested
+}
+
+// FeeQuote is the fee policy decision for one transaction.
+type FeeQuote struct {
+	Amount valueobjects.Money // Zero means no fee
+	Mode   entities.FeeMode   // Who bears the fee
+}
+
+// FeeCalculator prices outgoing transactions (TRANSFER, WITHDRAW, PAYOUT).
+// Use cases treat a nil calculator as "no fees".
+type FeeCalculator interface {
+	Quote(ctx context.Context, req *FeeQuoteRequest) (*FeeQuote, error)
+}
@@ -0,0 +1,82 @@
+// Package ports - фоновые задачи по расписанию: блокировки и журнал прогонов.
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobRunStatus - состояние прогона фоновой задачи.
+type JobRunStatus string
+
+const (
+	JobRunRunning   JobRunStatus = "RUNNING"
+	JobRunSucceeded JobRunStatus = "SUCCEEDED"
+	JobRunFailed    JobRunStatus = "FAILED"
+)
+
+// JobTrigger - что запустило прогон.
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "SCHEDULE"
+	JobTriggerManual   JobTrigger = "MANUAL"
+)
+
+// JobRun - запись журнала прогонов (таблица job_runs).
+type JobRun struct {
+	ID             uuid.UUID
+	Job            string
+	Trigger        JobTrigger
+	Instance       string // экземпляр приложения, выполнявший прогон
+	Status         JobRunStatus
+	Error          string
+	ItemsProcessed int
+	StartedAt      time.Time
+	FinishedAt     *time.Time
+}
+
+// JobRepository хранит блокировки задач и журнал прогонов.
+//
+// Блокировка - lease с истечением: экземпляр, упавший посреди прогона,
+// не держит задачу дольше lease. Владелец блокировки - ID прогона, поэтому
+// Unlock чужого (или уже перехваченного после истечения) lease - no-op.
+type JobRepository interface {
+	// TryLock берёт блокировку задачи на lease. false - блокировку держит
+	// другой прогон, и её lease ещё не истёк.
+	TryLock(ctx context.Context, job string, owner uuid.UUID, lease time.Duration) (bool, error)
+
+	// Unlock снимает блокировку, если её держит owner.
+	Unlock(ctx context.Context, job string, owner uuid.UUID) error
+
+	// StartRun записывает начало прогона (статус RUNNING).
+	StartRun(ctx context.Context, run *JobRun) error
+
+	// FinishRun записывает итог прогона: статус, ошибку, число элементов и FinishedAt.
+	FinishRun(ctx context.Context, run *JobRun) error
+
+	// ListRuns возвращает последние прогоны задачи, новые первыми (started_at DESC).
+	ListRuns(ctx context.Context, job string, limit int) ([]*JobRun, error)
+}
+
+// JobInfo - зарегистрированная задача и её последние прогоны.
+type JobInfo struct {
+	Name       string
+	Schedule   string
+	Timeout    time.Duration
+	NextRunAt  *time.Time // nil - планировщик не запущен
+	RecentRuns []*JobRun
+}
+
+// JobScheduler - admin-доступ к планировщику фоновых задач.
+type JobScheduler interface {
+	// Jobs возвращает зарегистрированные задачи в порядке регистрации.
+	Jobs(ctx context.Context) ([]JobInfo, error)
+
+	// Trigger запускает задачу вне расписания и возвращает начатый прогон,
+	// не дожидаясь его завершения. Задача, уже выполняемая любым
+	// экземпляром, не запускается повторно (BusinessRuleViolation JOB_RUNNING).
+	Trigger(ctx context.Context, name string) (*JobRun, error)
+}
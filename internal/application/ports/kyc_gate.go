@@ -0,0 +1,37 @@
+// Package ports - KYCGate: KYC владельца кошелька перед выводом средств.
+package ports
+
+import (
+	"context"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// KYCUnverifiedOwnerMetadataKey - ключ metadata транзакции, проведённой
+// в мягком режиме при неверифицированном владельце (значение - KYC статус).
+const KYCUnverifiedOwnerMetadataKey = "kyc_unverified_owner"
+
+// KYCGate проверяет, что владелец кошелька всё ещё прошёл KYC, перед
+// списаниями, выводами, выплатами и исходящими переводами. Пополнения не
+// проверяются: входящие средства не должны застревать.
+// Use cases считают nil gate отсутствием проверки.
+type KYCGate interface {
+	// CheckOwner проверяет KYC владельца wallet для транзакции tx.
+	//
+	//   - strict: BusinessRuleViolation USER_KYC_NOT_VERIFIED
+	//   - lenient: статус пишется в metadata kyc_unverified_owner,
+	//     возвращается событие TransactionFlagged для публикации вместе
+	//     с остальными
+	//
+	// Вызывается внутри UnitOfWork после создания транзакции и до движения средств.
+	CheckOwner(ctx context.Context, tx *entities.Transaction, wallet *entities.Wallet) ([]events.DomainEvent, error)
+}
+
+// CheckOwnerKYC вызывает gate, если он задан.
+func CheckOwnerKYC(ctx context.Context, gate KYCGate, tx *entities.Transaction, wallet *entities.Wallet) ([]events.DomainEvent, error) {
+	if gate == nil {
+		return nil, nil
+	}
+	return gate.CheckOwner(ctx, tx, wallet)
+}
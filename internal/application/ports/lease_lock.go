@@ -0,0 +1,66 @@
+// Package ports - LeaseLock: блокировка singleton-работы на кластер.
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrLeaseLost - lease потерян: блокировка истекла, перехвачена или её
+// соединение оборвалось. Работа, начатая под lease, не должна коммитить
+// результат.
+var ErrLeaseLost = errors.New("lease lost")
+
+// LeaseLock выдаёт эксклюзивный lease на ключ: одну задачу планировщика
+// или роль лидера (outbox relay) в каждый момент держит один экземпляр.
+//
+// В отличие от DistributedLock (короткая блокировка ключа идемпотентности)
+// lease живёт всё время работы: реализация продлевает его сама, пока он не
+// отпущен, и сообщает о потере через Lost. Каждый взятый lease получает
+// fencing token, строго больший токенов всех прежних lease этого ключа.
+type LeaseLock interface {
+	// TryAcquire берёт lease на ключ. nil, nil - lease держит другой
+	// владелец. ttl - сколько lease переживает владельца, переставшего его
+	// продлевать (упавший экземпляр).
+	TryAcquire(ctx context.Context, key string, ttl time.Duration) (Lease, error)
+}
+
+// Lease - взятая блокировка.
+type Lease interface {
+	// Token - fencing token lease.
+	Token() int64
+
+	// Lost закрывается, когда lease потерян (не закрывается при Release).
+	Lost() <-chan struct{}
+
+	// Check подтверждает, что lease всё ещё принадлежит владельцу
+	// (ErrLeaseLost - нет). Шаги долгой работы вызывают его перед коммитом.
+	Check(ctx context.Context) error
+
+	// Release отпускает lease и останавливает продление. Повторный вызов
+	// и Release потерянного lease - no-op.
+	Release(ctx context.Context) error
+}
+
+type leaseContextKey struct{}
+
+// ContextWithLease сохраняет lease, под которым выполняется работа.
+func ContextWithLease(ctx context.Context, lease Lease) context.Context {
+	return context.WithValue(ctx, leaseContextKey{}, lease)
+}
+
+// LeaseFromContext возвращает lease работы (nil - работа выполняется без lease).
+func LeaseFromContext(ctx context.Context) Lease {
+	lease, _ := ctx.Value(leaseContextKey{}).(Lease)
+	return lease
+}
+
+// CheckLease проверяет lease из ctx перед коммитом шага работы. Без lease
+// (блокировка задач в job_locks, один экземпляр) проверять нечего - nil.
+func CheckLease(ctx context.Context) error {
+	if lease := LeaseFromContext(ctx); lease != nil {
+		return lease.Check(ctx)
+	}
+	return nil
+}
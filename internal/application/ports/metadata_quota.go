@@ -0,0 +1,80 @@
+// Package ports - MetadataQuota: квота на суммарный объём metadata кошелька.
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// MetadataQuota - мягкая и жёсткая квоты на суммарный объём metadata
+// транзакций кошелька (config transactions.metadata_soft_limit_bytes и
+// transactions.metadata_hard_limit_bytes).
+//
+// Учитывается только metadata клиента в том виде, в каком она сохранена
+// (после маскирования); системные ключи (комиссии, скрининг) не считаются.
+// Мягкая квота лишь предупреждает, жёсткая отклоняет непустую metadata -
+// та же транзакция без metadata проходит.
+type MetadataQuota struct {
+	Usage          MetadataUsageRepository // nil - объём не учитывается
+	SoftLimitBytes int64                   // 0 - без предупреждений
+	HardLimitBytes int64                   // 0 - без отказа
+}
+
+// MetadataQuotaStatus - объём metadata кошелька после учёта транзакции.
+type MetadataQuotaStatus struct {
+	UsedBytes         int64
+	SoftLimitExceeded bool // объём выше мягкой квоты
+	SoftLimitCrossed  bool // именно эта транзакция вывела объём за мягкую квоту
+}
+
+// Charge учитывает metadata новой транзакции в счётчике кошелька.
+//
+// Вызывается внутри UnitOfWork создания транзакции: METADATA_QUOTA_EXCEEDED
+// (422) откатывает и транзакцию, и счётчик. Пустая metadata не учитывается.
+func (q MetadataQuota) Charge(ctx context.Context, walletID, transactionID uuid.UUID, bytes int64) (MetadataQuotaStatus, error) {
+	if q.Usage == nil || bytes == 0 {
+		return MetadataQuotaStatus{}, nil
+	}
+
+	used, err := q.Usage.AddTransactionMetadata(ctx, walletID, transactionID, bytes)
+	if err != nil {
+		return MetadataQuotaStatus{}, fmt.Errorf("failed to record metadata usage: %w", err)
+	}
+
+	if q.HardLimitBytes > 0 && used > q.HardLimitBytes {
+		return MetadataQuotaStatus{}, errors.NewBusinessRuleViolation(
+			"METADATA_QUOTA_EXCEEDED",
+			fmt.Sprintf("wallet metadata quota of %d bytes is exhausted; retry without metadata", q.HardLimitBytes),
+			map[string]interface{}{
+				"walletId":       walletID.String(),
+				"usedBytes":      used - bytes,
+				"metadataBytes":  bytes,
+				"hardLimitBytes": q.HardLimitBytes,
+			},
+		)
+	}
+
+	status := MetadataQuotaStatus{UsedBytes: used}
+	if q.SoftLimitBytes > 0 && used > q.SoftLimitBytes {
+		status.SoftLimitExceeded = true
+		status.SoftLimitCrossed = used-bytes <= q.SoftLimitBytes
+	}
+	return status, nil
+}
+
+// MetadataSize возвращает объём metadata в байтах JSON (0 для пустой).
+func MetadataSize(metadata map[string]interface{}) int64 {
+	if len(metadata) == 0 {
+		return 0
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
@@ -0,0 +1,54 @@
+// Package ports - NewPayeeGuard: ограничения первых переводов новому получателю.
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// PayeeRepository - получатели, на которые кошелёк уже переводил (таблица wallet_payees).
+type PayeeRepository interface {
+	// HasPriorCompletedTransfer сообщает, был ли завершённый перевод
+	// с sourceWalletID на destinationWalletID.
+	HasPriorCompletedTransfer(ctx context.Context, sourceWalletID, destinationWalletID uuid.UUID) (bool, error)
+
+	// FirstTransferAt возвращает время первого завершённого перевода
+	// с sourceWalletID на destinationWalletID; false - пара не записана.
+	FirstTransferAt(ctx context.Context, sourceWalletID, destinationWalletID uuid.UUID) (time.Time, bool, error)
+
+	// TransferredTo суммирует переводы (TRANSFER) с sourceWalletID на
+	// destinationWalletID в статусах PENDING, PROCESSING и COMPLETED в валюте
+	// кошелька-источника currency. Без переводов - ноль.
+	TransferredTo(ctx context.Context, sourceWalletID, destinationWalletID uuid.UUID, currency valueobjects.Currency) (valueobjects.Money, error)
+
+	// RecordPayee запоминает получателя после завершённого перевода transactionID
+	// со временем clock.Now(). Повторная запись той же пары - no-op: первый
+	// перевод не перезаписывается.
+	RecordPayee(ctx context.Context, sourceWalletID, destinationWalletID, transactionID uuid.UUID) error
+}
+
+// NewPayeeRequest - перевод перед движением средств.
+type NewPayeeRequest struct {
+	Transaction *entities.Transaction // TRANSFER с destination wallet
+	Confirmed   bool                  // confirm_new_payee из запроса
+}
+
+// NewPayeeGuard ограничивает переводы на кошельки, на которые источник
+// ещё не переводил: скомпрометированный аккаунт обычно сразу уводит
+// средства на незнакомый кошелёк. Use cases считают nil guard отсутствием проверки.
+type NewPayeeGuard interface {
+	// Check возвращает BusinessRuleViolation NEW_PAYEE_CONFIRMATION_REQUIRED
+	// или NEW_PAYEE_LIMIT_EXCEEDED, если политика не пропускает перевод.
+	// Вызывается внутри UnitOfWork до движения средств.
+	Check(ctx context.Context, req *NewPayeeRequest) error
+
+	// RecordTransfer запоминает получателя завершённого перевода в той же
+	// UnitOfWork: переводы ему перестают ограничиваться, когда истечёт окно
+	// нового получателя.
+	RecordTransfer(ctx context.Context, tx *entities.Transaction) error
+}
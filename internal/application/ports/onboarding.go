@@ -0,0 +1,42 @@
+// Package ports - OnboardingRepository: результаты onboarding для повторов.
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OnboardingRecord - результат onboarding, сохранённый под idempotency_key
+// запроса партнёра.
+//
+// RequestHash - отпечаток нормализованной команды: повтор ключа с другим
+// телом отклоняется, а не возвращает чужой результат. WalletIDs - в порядке
+// валют запроса, TransactionIDs - начальные зачисления в том же порядке
+// (кошельки без зачисления пропускаются).
+type OnboardingRecord struct {
+	IdempotencyKey  uuid.UUID
+	PartnerID       uuid.UUID
+	RequestHash     string
+	UserID          uuid.UUID
+	WalletIDs       []uuid.UUID
+	TransactionIDs  []uuid.UUID
+	KYCAutoApproved bool
+	CreatedAt       time.Time
+}
+
+// OnboardingRepository - журнал onboarding запросов (таблица onboardings).
+//
+// Контракт (проверяется porttest.RunOnboardingRepositoryTests):
+//   - Save вызывается в UnitOfWork onboarding: откат не оставляет записи
+//   - Повторный Save того же ключа - ErrEntityAlreadyExists
+//   - FindByIdempotencyKey отсутствующего ключа - ErrEntityNotFound
+//   - Порядок WalletIDs и TransactionIDs сохраняется
+type OnboardingRepository interface {
+	// Save сохраняет результат onboarding.
+	Save(ctx context.Context, record *OnboardingRecord) error
+
+	// FindByIdempotencyKey загружает результат по ключу запроса.
+	FindByIdempotencyKey(ctx context.Context, key uuid.UUID) (*OnboardingRecord, error)
+}
@@ -0,0 +1,33 @@
+// Package ports - OperationGate: аварийные выключатели денежных операций.
+package ports
+
+import (
+	"context"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// OperationGate останавливает отдельные виды движения средств во время
+// инцидента (например, только выводы при сбое провайдера выплат).
+// Use cases считают nil gate отсутствием выключателей.
+type OperationGate interface {
+	// RequireEnabled возвращает *errors.OperationDisabledError с причиной,
+	// если выключен выключатель операции txType (см.
+	// entities.OperationSwitchesFor). Вызывается в начале use case до
+	// UnitOfWork; типы без выключателя всегда разрешены.
+	RequireEnabled(ctx context.Context, txType entities.TransactionType) error
+
+	// Disabled возвращает выключенные сейчас операции (для health).
+	Disabled(ctx context.Context) []*entities.OperationSwitch
+
+	// Invalidate сбрасывает кэш: следующая проверка перечитает хранилище.
+	Invalidate()
+}
+
+// RequireOperationEnabled проверяет выключатели txType; nil gate разрешает всё.
+func RequireOperationEnabled(ctx context.Context, gate OperationGate, txType entities.TransactionType) error {
+	if gate == nil {
+		return nil
+	}
+	return gate.RequireEnabled(ctx, txType)
+}
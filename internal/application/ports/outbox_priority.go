@@ -0,0 +1,74 @@
+// Package ports - OutboxPriority: полосы доставки событий из outbox.
+package ports
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// OutboxPriority - полоса доставки события. Назначается при записи в outbox.
+type OutboxPriority string
+
+const (
+	OutboxPriorityHigh   OutboxPriority = "HIGH"
+	OutboxPriorityNormal OutboxPriority = "NORMAL"
+	OutboxPriorityLow    OutboxPriority = "LOW"
+)
+
+// OutboxPriorityLanes - все полосы, от высшей к низшей.
+var OutboxPriorityLanes = []OutboxPriority{
+	OutboxPriorityHigh,
+	OutboxPriorityNormal,
+	OutboxPriorityLow,
+}
+
+// IsValid проверяет, что приоритет - одна из полос.
+func (p OutboxPriority) IsValid() bool {
+	switch p {
+	case OutboxPriorityHigh, OutboxPriorityNormal, OutboxPriorityLow:
+		return true
+	}
+	return false
+}
+
+// OutboxPriorities - приоритет по типу события.
+// Типы, которых нет в маппинге, получают NORMAL.
+type OutboxPriorities map[string]OutboxPriority
+
+// DefaultOutboxPriorities возвращает маппинг по умолчанию:
+// события, которых ждёт пользователь (KYC, завершение транзакции) - HIGH,
+// промежуточные, нужные только проекциям и аналитике - LOW.
+func DefaultOutboxPriorities() OutboxPriorities {
+	return OutboxPriorities{
+		events.EventTypeUserKYCApproved:      OutboxPriorityHigh,
+		events.EventTypeUserKYCRejected:      OutboxPriorityHigh,
+		events.EventTypeUserKYCRevoked:       OutboxPriorityHigh,
+		events.EventTypeTransactionCompleted: OutboxPriorityHigh,
+		events.EventTypeCurrencyExchanged:    OutboxPriorityHigh,
+		events.EventTypeTransactionCreated:   OutboxPriorityLow,
+	}
+}
+
+// NewOutboxPriorities накладывает overrides (event type -> "HIGH"/"NORMAL"/"LOW")
+// на DefaultOutboxPriorities. Регистр значения не важен.
+func NewOutboxPriorities(overrides map[string]string) (OutboxPriorities, error) {
+	priorities := DefaultOutboxPriorities()
+	for eventType, value := range overrides {
+		priority := OutboxPriority(strings.ToUpper(strings.TrimSpace(value)))
+		if !priority.IsValid() {
+			return nil, fmt.Errorf("invalid outbox priority %q for event type %s", value, eventType)
+		}
+		priorities[eventType] = priority
+	}
+	return priorities, nil
+}
+
+// For возвращает приоритет типа события.
+func (p OutboxPriorities) For(eventType string) OutboxPriority {
+	if priority, ok := p[eventType]; ok {
+		return priority
+	}
+	return OutboxPriorityNormal
+}
@@ -0,0 +1,37 @@
+package ports
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+func TestOutboxPriorities(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		p := DefaultOutboxPriorities()
+		assert.Equal(t, OutboxPriorityHigh, p.For(events.EventTypeUserKYCApproved))
+		assert.Equal(t, OutboxPriorityHigh, p.For(events.EventTypeTransactionCompleted))
+		assert.Equal(t, OutboxPriorityLow, p.For(events.EventTypeTransactionCreated))
+		assert.Equal(t, OutboxPriorityNormal, p.For(events.EventTypeWalletCredited))
+		assert.Equal(t, OutboxPriorityNormal, p.For("unknown.event"))
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		p, err := NewOutboxPriorities(map[string]string{
+			events.EventTypeWalletCredited:       "high",
+			events.EventTypeTransactionCompleted: " NORMAL ",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, OutboxPriorityHigh, p.For(events.EventTypeWalletCredited))
+		assert.Equal(t, OutboxPriorityNormal, p.For(events.EventTypeTransactionCompleted))
+		assert.Equal(t, OutboxPriorityHigh, p.For(events.EventTypeUserKYCApproved), "defaults are kept")
+	})
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		_, err := NewOutboxPriorities(map[string]string{events.EventTypeWalletCredited: "urgent"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,49 @@
+// Package ports - PendingTransactionsPolicy: лимит незавершённых транзакций кошелька.
+package ports
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// PendingTransactionsPolicy - лимит одновременно PENDING/PROCESSING
+// транзакций на кошелёк (config transactions.max_pending_per_wallet).
+//
+// Каждая незавершённая выплата держит резерв, поэтому тысячи зависших
+// транзакций фактически замораживают баланс. Админ может переопределить
+// лимит для кошелька (Wallet.MaxPendingTransactions) - для мерчантов
+// с большим потоком. Место освобождается, как только транзакция
+// завершается, отменяется или истекает.
+type PendingTransactionsPolicy struct {
+	MaxPerWallet int // 0 - без лимита, если у кошелька нет override
+}
+
+// Check возвращает TOO_MANY_PENDING_TRANSACTIONS (422), если у кошелька
+// уже столько незавершённых транзакций, сколько позволяет лимит.
+// Без лимита не обращается к хранилищу.
+func (p PendingTransactionsPolicy) Check(ctx context.Context, transactions TransactionRepository, wallet *entities.Wallet) error {
+	limit := wallet.PendingTransactionsCap(p.MaxPerWallet)
+	if limit <= 0 {
+		return nil
+	}
+
+	pending, err := transactions.CountPendingByWallet(ctx, wallet.ID())
+	if err != nil {
+		return fmt.Errorf("failed to count pending transactions: %w", err)
+	}
+	if pending >= limit {
+		return errors.NewBusinessRuleViolation(
+			"TOO_MANY_PENDING_TRANSACTIONS",
+			fmt.Sprintf("wallet already has %d pending transactions (limit %d)", pending, limit),
+			map[string]interface{}{
+				"walletId": wallet.ID().String(),
+				"pending":  pending,
+				"limit":    limit,
+			},
+		)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package porttest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// DedupHarness - DedupStore и UnitOfWork над тем же хранилищем.
+type DedupHarness struct {
+	Store      ports.DedupStore
+	UnitOfWork ports.UnitOfWork
+}
+
+// DedupStoreFactory создаёт harness над ПУСТЫМ хранилищем.
+type DedupStoreFactory func(t *testing.T) DedupHarness
+
+// RunDedupStoreTests проверяет реализацию ports.DedupStore.
+func RunDedupStoreTests(t *testing.T, factory DedupStoreFactory) {
+	t.Run("MarkOncePerConsumer", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		eventID := uuid.New()
+
+		first, err := h.Store.MarkProcessed(ctx, "projection", eventID)
+		require.NoError(t, err)
+		assert.True(t, first)
+
+		again, err := h.Store.MarkProcessed(ctx, "projection", eventID)
+		require.NoError(t, err)
+		assert.False(t, again, "second mark of the same event")
+
+		// Другой потребитель обрабатывает событие независимо
+		other, err := h.Store.MarkProcessed(ctx, "webhooks", eventID)
+		require.NoError(t, err)
+		assert.True(t, other)
+	})
+
+	t.Run("UnmarkAllowsReprocessing", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		eventID := uuid.New()
+
+		_, err := h.Store.MarkProcessed(ctx, "projection", eventID)
+		require.NoError(t, err)
+		require.NoError(t, h.Store.Unmark(ctx, "projection", eventID))
+
+		marked, err := h.Store.MarkProcessed(ctx, "projection", eventID)
+		require.NoError(t, err)
+		assert.True(t, marked)
+
+		// Снятие отсутствующей отметки - не ошибка
+		require.NoError(t, h.Store.Unmark(ctx, "projection", uuid.New()))
+	})
+
+	t.Run("ConcurrentMarkHasOneWinner", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		eventID := uuid.New()
+
+		const workers = 8
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			winners int
+		)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				marked, err := h.Store.MarkProcessed(ctx, "projection", eventID)
+				assert.NoError(t, err)
+				if marked {
+					mu.Lock()
+					winners++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, winners)
+	})
+
+	t.Run("RolledBackWithUnitOfWork", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		eventID := uuid.New()
+		errHandler := errors.New("handler failed")
+
+		err := h.UnitOfWork.Execute(ctx, func(txCtx context.Context) error {
+			marked, err := h.Store.MarkProcessed(txCtx, "projection", eventID)
+			require.NoError(t, err)
+			require.True(t, marked)
+			return errHandler
+		})
+		require.ErrorIs(t, err, errHandler)
+
+		marked, err := h.Store.MarkProcessed(ctx, "projection", eventID)
+		require.NoError(t, err)
+		assert.True(t, marked, "rolled back mark must not survive")
+	})
+
+	t.Run("DeleteBefore", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		eventID := uuid.New()
+
+		_, err := h.Store.MarkProcessed(ctx, "projection", eventID)
+		require.NoError(t, err)
+
+		deleted, err := h.Store.DeleteBefore(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, deleted, "fresh marks are kept")
+
+		deleted, err = h.Store.DeleteBefore(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		marked, err := h.Store.MarkProcessed(ctx, "projection", eventID)
+		require.NoError(t, err)
+		assert.True(t, marked, "purged event is processed again")
+	})
+}
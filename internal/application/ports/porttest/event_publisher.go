@@ -0,0 +1,93 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// EventPublisherHarness - publisher и чтение того, что он сохранил/отправил.
+type EventPublisherHarness struct {
+	Publisher ports.EventPublisher
+
+	// Delivered возвращает опубликованные события в порядке доставки
+	// (для outbox - в порядке, в котором их заберёт poller).
+	Delivered func(t *testing.T) []events.DomainEvent
+}
+
+// EventPublisherFactory создаёт publisher над ПУСТЫМ хранилищем.
+type EventPublisherFactory func(t *testing.T) EventPublisherHarness
+
+// RunEventPublisherTests проверяет реализацию ports.EventPublisher на соответствие контракту PublishBatch.
+func RunEventPublisherTests(t *testing.T, factory EventPublisherFactory) {
+	t.Run("BatchKeepsSliceOrder", func(t *testing.T) {
+		h := factory(t)
+		aggregateID := uuid.New()
+
+		// События созданы в одном порядке, а публикуются в обратном:
+		// порядок доставки задаёт слайс, а не OccurredAt
+		created := make([]events.DomainEvent, 5)
+		for i := range created {
+			created[i] = newTestEvent(aggregateID)
+		}
+		batch := make([]events.DomainEvent, 0, len(created))
+		for i := len(created) - 1; i >= 0; i-- {
+			batch = append(batch, created[i])
+		}
+
+		require.NoError(t, h.Publisher.PublishBatch(context.Background(), batch))
+
+		assert.Equal(t, eventIDs(batch), eventIDs(h.Delivered(t)))
+	})
+
+	t.Run("BatchAfterPublishKeepsOrder", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		aggregateID := uuid.New()
+		first, second, third := newTestEvent(aggregateID), newTestEvent(aggregateID), newTestEvent(aggregateID)
+
+		require.NoError(t, h.Publisher.Publish(ctx, first))
+		require.NoError(t, h.Publisher.PublishBatch(ctx, []events.DomainEvent{second, third}))
+
+		assert.Equal(t, eventIDs([]events.DomainEvent{first, second, third}), eventIDs(h.Delivered(t)))
+	})
+
+	t.Run("DuplicatesWithinBatchPublishedOnce", func(t *testing.T) {
+		h := factory(t)
+		aggregateID := uuid.New()
+		a, b, c := newTestEvent(aggregateID), newTestEvent(aggregateID), newTestEvent(aggregateID)
+
+		require.NoError(t, h.Publisher.PublishBatch(context.Background(), []events.DomainEvent{a, b, a, c, b}))
+
+		assert.Equal(t, eventIDs([]events.DomainEvent{a, b, c}), eventIDs(h.Delivered(t)))
+	})
+
+	t.Run("EmptyBatchIsNoop", func(t *testing.T) {
+		h := factory(t)
+
+		require.NoError(t, h.Publisher.PublishBatch(context.Background(), nil))
+		require.NoError(t, h.Publisher.PublishBatch(context.Background(), []events.DomainEvent{}))
+
+		assert.Empty(t, h.Delivered(t))
+	})
+}
+
+// newTestEvent создаёт событие aggregate, которое сериализуют все реализации.
+func newTestEvent(aggregateID uuid.UUID) events.DomainEvent {
+	return events.NewUserCreated(aggregateID, aggregateID.String()+"@example.com", "Conformance User")
+}
+
+// eventIDs - EventID событий в порядке слайса.
+func eventIDs(evts []events.DomainEvent) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(evts))
+	for _, event := range evts {
+		ids = append(ids, event.EventID())
+	}
+	return ids
+}
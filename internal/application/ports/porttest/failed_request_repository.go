@@ -0,0 +1,126 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// FailedRequestRepositoryFactory создаёт репозиторий над ПУСТЫМ хранилищем.
+type FailedRequestRepositoryFactory func(t *testing.T) ports.FailedRequestRepository
+
+// RunFailedRequestRepositoryTests проверяет реализацию ports.FailedRequestRepository.
+func RunFailedRequestRepositoryTests(t *testing.T, factory FailedRequestRepositoryFactory) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+
+	failedRequest := func(actorID uuid.UUID, route string, occurredAt time.Time) *entities.FailedRequest {
+		return entities.ReconstructFailedRequest(uuid.New(), "POST", route, 422, "req-1", actorID,
+			`{"amount":"10.00"}`, `{"success":false}`, false, occurredAt)
+	}
+
+	t.Run("ListNewestFirstWithFilters", func(t *testing.T) {
+		repo := factory(t)
+		ctx := context.Background()
+		userID := uuid.New()
+		const debit = "/api/v1/wallets/{id}/debit"
+		const credit = "/api/v1/wallets/{id}/credit"
+
+		oldest := failedRequest(userID, debit, at(0))
+		otherRoute := failedRequest(userID, credit, at(1))
+		otherUser := failedRequest(uuid.New(), debit, at(2))
+		anonymous := failedRequest(uuid.Nil, debit, at(3))
+		for _, r := range []*entities.FailedRequest{oldest, otherRoute, otherUser, anonymous} {
+			require.NoError(t, repo.Append(ctx, r))
+		}
+
+		all, err := repo.List(ctx, ports.FailedRequestFilter{}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, failedRequestIDs(anonymous, otherUser, otherRoute, oldest), failedRequestIDs(all...))
+
+		byUser, err := repo.List(ctx, ports.FailedRequestFilter{ActorID: &userID}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, failedRequestIDs(otherRoute, oldest), failedRequestIDs(byUser...))
+
+		route := debit
+		from, to := at(1), at(3)
+		ranged, err := repo.List(ctx, ports.FailedRequestFilter{Route: &route, OccurredFrom: &from, OccurredTo: &to}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, failedRequestIDs(otherUser), failedRequestIDs(ranged...))
+
+		page, err := repo.List(ctx, ports.FailedRequestFilter{}, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, failedRequestIDs(otherUser, otherRoute), failedRequestIDs(page...))
+	})
+
+	t.Run("ReadsBackAllFields", func(t *testing.T) {
+		repo := factory(t)
+		ctx := context.Background()
+		actorID := uuid.New()
+
+		stored := entities.ReconstructFailedRequest(uuid.New(), "DELETE", "/api/v1/transactions/{id}", 409, "req-42",
+			actorID, `{"reason":"x"}`, `{"success":false,"error":{"code":"CONFLICT"}}`, true, at(0))
+		require.NoError(t, repo.Append(ctx, stored))
+
+		got, err := repo.List(ctx, ports.FailedRequestFilter{}, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, stored.ID(), got[0].ID())
+		assert.Equal(t, "DELETE", got[0].Method())
+		assert.Equal(t, "/api/v1/transactions/{id}", got[0].Route())
+		assert.Equal(t, 409, got[0].StatusCode())
+		assert.Equal(t, "req-42", got[0].RequestID())
+		assert.Equal(t, actorID, got[0].ActorID())
+		assert.Equal(t, `{"reason":"x"}`, got[0].RequestBody())
+		assert.Equal(t, `{"success":false,"error":{"code":"CONFLICT"}}`, got[0].ResponseBody())
+		assert.True(t, got[0].Truncated())
+		assert.True(t, stored.OccurredAt().Equal(got[0].OccurredAt()))
+		assert.Equal(t, time.UTC, got[0].OccurredAt().Location())
+	})
+
+	t.Run("AnonymousActorReadsBackNil", func(t *testing.T) {
+		repo := factory(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.Append(ctx, failedRequest(uuid.Nil, "/api/v1/wallets/{id}/debit", at(0))))
+
+		got, err := repo.List(ctx, ports.FailedRequestFilter{}, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, uuid.Nil, got[0].ActorID())
+	})
+
+	t.Run("DeleteBeforeKeepsCutoff", func(t *testing.T) {
+		repo := factory(t)
+		ctx := context.Background()
+
+		expired := failedRequest(uuid.Nil, "/api/v1/wallets/{id}/debit", at(0))
+		atCutoff := failedRequest(uuid.Nil, "/api/v1/wallets/{id}/debit", at(10))
+		for _, r := range []*entities.FailedRequest{expired, atCutoff} {
+			require.NoError(t, repo.Append(ctx, r))
+		}
+
+		deleted, err := repo.DeleteBefore(ctx, at(10))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		left, err := repo.List(ctx, ports.FailedRequestFilter{}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, failedRequestIDs(atCutoff), failedRequestIDs(left...))
+	})
+}
+
+func failedRequestIDs(list ...*entities.FailedRequest) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(list))
+	for _, r := range list {
+		ids = append(ids, r.ID())
+	}
+	return ids
+}
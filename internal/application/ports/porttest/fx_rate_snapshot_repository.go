@@ -0,0 +1,119 @@
+package porttest
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// FXRateSnapshotHarness - FXRateSnapshotRepository и UnitOfWork над тем же хранилищем.
+type FXRateSnapshotHarness struct {
+	Repository ports.FXRateSnapshotRepository
+	UnitOfWork ports.UnitOfWork
+}
+
+// FXRateSnapshotRepositoryFactory создаёт harness над ПУСТЫМ хранилищем.
+type FXRateSnapshotRepositoryFactory func(t *testing.T) FXRateSnapshotHarness
+
+// RunFXRateSnapshotRepositoryTests проверяет реализацию ports.FXRateSnapshotRepository.
+func RunFXRateSnapshotRepositoryTests(t *testing.T, factory FXRateSnapshotRepositoryFactory) {
+	fetchedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	snapshot := func(t *testing.T, rate string, fetchedAt time.Time) *entities.FXRateSnapshot {
+		t.Helper()
+		r, ok := new(big.Rat).SetString(rate)
+		require.True(t, ok)
+		s, err := entities.NewFXRateSnapshot("USD", "EUR", r, "test-provider", fetchedAt)
+		require.NoError(t, err)
+		return s
+	}
+
+	t.Run("ReadsBackAllFields", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+
+		stored := snapshot(t, "0.923456789012", fetchedAt)
+		require.NoError(t, h.Repository.Append(ctx, stored))
+
+		got, err := h.Repository.FindByID(ctx, stored.ID())
+		require.NoError(t, err)
+		assert.Equal(t, stored.ID(), got.ID())
+		assert.Equal(t, "USD", got.Base())
+		assert.Equal(t, "EUR", got.Quote())
+		assert.Equal(t, 0, stored.Rate().Cmp(got.Rate()), "rate %s != %s", stored.Rate().FloatString(12), got.Rate().FloatString(12))
+		assert.Equal(t, "test-provider", got.Source())
+		assert.True(t, fetchedAt.Equal(got.FetchedAt()))
+		assert.Equal(t, time.UTC, got.FetchedAt().Location())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		h := factory(t)
+
+		_, err := h.Repository.FindByID(context.Background(), uuid.New())
+		assert.ErrorIs(t, err, domainErrors.ErrEntityNotFound)
+	})
+
+	t.Run("AppendOnly", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+
+		stored := snapshot(t, "0.92", fetchedAt)
+		require.NoError(t, h.Repository.Append(ctx, stored))
+
+		changed := entities.ReconstructFXRateSnapshot(stored.ID(), "USD", "EUR", big.NewRat(1, 1), "other", fetchedAt)
+		err := h.Repository.Append(ctx, changed)
+		require.Error(t, err)
+		var domainErr *domainErrors.DomainError
+		require.True(t, errors.As(err, &domainErr), "got %v", err)
+		assert.Equal(t, "FX_RATE_SNAPSHOT_EXISTS", domainErr.Code)
+
+		got, err := h.Repository.FindByID(ctx, stored.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "test-provider", got.Source(), "stored snapshot must not change")
+	})
+
+	t.Run("RolledBackWithUnitOfWork", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		errOperation := errors.New("operation failed")
+
+		stored := snapshot(t, "0.92", fetchedAt)
+		err := h.UnitOfWork.Execute(ctx, func(txCtx context.Context) error {
+			require.NoError(t, h.Repository.Append(txCtx, stored))
+			return errOperation
+		})
+		require.ErrorIs(t, err, errOperation)
+
+		_, err = h.Repository.FindByID(ctx, stored.ID())
+		assert.ErrorIs(t, err, domainErrors.ErrEntityNotFound, "rolled back snapshot must not survive")
+	})
+
+	t.Run("DeleteBefore", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+
+		old := snapshot(t, "0.91", fetchedAt.Add(-48*time.Hour))
+		fresh := snapshot(t, "0.92", fetchedAt)
+		require.NoError(t, h.Repository.Append(ctx, old))
+		require.NoError(t, h.Repository.Append(ctx, fresh))
+
+		deleted, err := h.Repository.DeleteBefore(ctx, fetchedAt.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		_, err = h.Repository.FindByID(ctx, old.ID())
+		assert.ErrorIs(t, err, domainErrors.ErrEntityNotFound)
+		_, err = h.Repository.FindByID(ctx, fresh.ID())
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,91 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// IncidentRepositoryFactory создаёт репозиторий над ПУСТЫМ хранилищем.
+type IncidentRepositoryFactory func(t *testing.T) ports.IncidentRepository
+
+// RunIncidentRepositoryTests проверяет реализацию ports.IncidentRepository.
+func RunIncidentRepositoryTests(t *testing.T, factory IncidentRepositoryFactory) {
+	startedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	incident := func(title string, started time.Time, resolvedAt *time.Time) *entities.Incident {
+		var resolvedBy *uuid.UUID
+		if resolvedAt != nil {
+			actor := uuid.New()
+			resolvedBy = &actor
+		}
+		return entities.ReconstructIncident(
+			uuid.New(), title, entities.IncidentSeverityMajor,
+			[]entities.StatusComponent{entities.StatusComponentPayouts, entities.StatusComponentAPI},
+			started, resolvedAt, uuid.New(), resolvedBy, started,
+		)
+	}
+
+	t.Run("FindByIDNotFound", func(t *testing.T) {
+		repo := factory(t)
+
+		_, err := repo.FindByID(context.Background(), uuid.New())
+		assert.ErrorIs(t, err, domainErrors.ErrEntityNotFound)
+	})
+
+	t.Run("SaveRoundTripAndResolve", func(t *testing.T) {
+		repo := factory(t)
+		ctx := context.Background()
+
+		saved := incident("Payout delays", startedAt, nil)
+		require.NoError(t, repo.Save(ctx, saved))
+
+		loaded, err := repo.FindByID(ctx, saved.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "Payout delays", loaded.Title())
+		assert.Equal(t, entities.IncidentSeverityMajor, loaded.Severity())
+		assert.Equal(t, []entities.StatusComponent{entities.StatusComponentPayouts, entities.StatusComponentAPI}, loaded.Components())
+		assert.True(t, loaded.StartedAt().Equal(startedAt))
+		assert.Equal(t, saved.CreatedBy(), loaded.CreatedBy())
+		assert.False(t, loaded.IsResolved())
+
+		actor := uuid.New()
+		require.NoError(t, loaded.Resolve(actor))
+		require.NoError(t, repo.Save(ctx, loaded))
+
+		resolved, err := repo.FindByID(ctx, saved.ID())
+		require.NoError(t, err)
+		require.True(t, resolved.IsResolved())
+		assert.Equal(t, actor, *resolved.ResolvedBy())
+		assert.Equal(t, time.UTC, resolved.ResolvedAt().Location())
+	})
+
+	t.Run("ListVisibleSkipsLongResolved", func(t *testing.T) {
+		repo := factory(t)
+		ctx := context.Background()
+		cutoff := startedAt.Add(-entities.IncidentResolvedVisibility)
+
+		active := incident("Active", startedAt.Add(-time.Hour), nil)
+		recentAt := cutoff.Add(time.Minute)
+		recent := incident("Recently resolved", startedAt.Add(-2*time.Hour), &recentAt)
+		oldAt := cutoff.Add(-time.Minute)
+		old := incident("Resolved long ago", startedAt.Add(-48*time.Hour), &oldAt)
+		for _, i := range []*entities.Incident{old, recent, active} {
+			require.NoError(t, repo.Save(ctx, i))
+		}
+
+		visible, err := repo.ListVisible(ctx, cutoff)
+		require.NoError(t, err)
+		require.Len(t, visible, 2)
+		assert.Equal(t, active.ID(), visible[0].ID(), "started_at DESC")
+		assert.Equal(t, recent.ID(), visible[1].ID())
+	})
+}
@@ -0,0 +1,119 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// JobRepositoryFactory создаёт репозиторий над ПУСТЫМ хранилищем.
+type JobRepositoryFactory func(t *testing.T) ports.JobRepository
+
+// RunJobRepositoryTests проверяет реализацию ports.JobRepository.
+func RunJobRepositoryTests(t *testing.T, factory JobRepositoryFactory) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+
+	t.Run("LockIsExclusiveUntilUnlocked", func(t *testing.T) {
+		repo := factory(t)
+		ctx := context.Background()
+		first, second := uuid.New(), uuid.New()
+
+		locked, err := repo.TryLock(ctx, "porttest-job", first, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, locked)
+
+		locked, err = repo.TryLock(ctx, "porttest-job", second, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, locked, "lock must be held by the first owner")
+
+		// Другая задача блокируется независимо
+		locked, err = repo.TryLock(ctx, "porttest-other", second, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, locked)
+
+		// Чужой Unlock - no-op
+		require.NoError(t, repo.Unlock(ctx, "porttest-job", second))
+		locked, err = repo.TryLock(ctx, "porttest-job", second, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, locked)
+
+		require.NoError(t, repo.Unlock(ctx, "porttest-job", first))
+		locked, err = repo.TryLock(ctx, "porttest-job", second, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, locked)
+	})
+
+	t.Run("ExpiredLeaseCanBeTakenOver", func(t *testing.T) {
+		repo := factory(t)
+		ctx := context.Background()
+		crashed, next := uuid.New(), uuid.New()
+
+		locked, err := repo.TryLock(ctx, "porttest-job", crashed, 50*time.Millisecond)
+		require.NoError(t, err)
+		require.True(t, locked)
+
+		time.Sleep(100 * time.Millisecond)
+
+		locked, err = repo.TryLock(ctx, "porttest-job", next, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, locked, "expired lease must be taken over")
+
+		// Упавший владелец не снимает перехваченную блокировку
+		require.NoError(t, repo.Unlock(ctx, "porttest-job", crashed))
+		locked, err = repo.TryLock(ctx, "porttest-job", crashed, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, locked)
+	})
+
+	t.Run("RunsReadBackNewestFirst", func(t *testing.T) {
+		repo := factory(t)
+		ctx := context.Background()
+
+		older := &ports.JobRun{ID: uuid.New(), Job: "porttest-job", Trigger: ports.JobTriggerSchedule,
+			Instance: "a", Status: ports.JobRunRunning, StartedAt: at(0)}
+		newer := &ports.JobRun{ID: uuid.New(), Job: "porttest-job", Trigger: ports.JobTriggerManual,
+			Instance: "b", Status: ports.JobRunRunning, StartedAt: at(5)}
+		other := &ports.JobRun{ID: uuid.New(), Job: "porttest-other", Trigger: ports.JobTriggerSchedule,
+			Instance: "a", Status: ports.JobRunRunning, StartedAt: at(10)}
+		for _, run := range []*ports.JobRun{older, newer, other} {
+			require.NoError(t, repo.StartRun(ctx, run))
+		}
+
+		finishedAt := at(1)
+		older.Status = ports.JobRunFailed
+		older.Error = "boom"
+		older.ItemsProcessed = 7
+		older.FinishedAt = &finishedAt
+		require.NoError(t, repo.FinishRun(ctx, older))
+
+		runs, err := repo.ListRuns(ctx, "porttest-job", 10)
+		require.NoError(t, err)
+		require.Len(t, runs, 2)
+		assert.Equal(t, newer.ID, runs[0].ID)
+		assert.Equal(t, ports.JobRunRunning, runs[0].Status)
+		assert.Nil(t, runs[0].FinishedAt)
+		assert.Equal(t, ports.JobTriggerManual, runs[0].Trigger)
+		assert.Equal(t, "b", runs[0].Instance)
+
+		assert.Equal(t, older.ID, runs[1].ID)
+		assert.Equal(t, ports.JobRunFailed, runs[1].Status)
+		assert.Equal(t, "boom", runs[1].Error)
+		assert.Equal(t, 7, runs[1].ItemsProcessed)
+		require.NotNil(t, runs[1].FinishedAt)
+		assert.True(t, finishedAt.Equal(*runs[1].FinishedAt))
+		assert.True(t, at(0).Equal(runs[1].StartedAt))
+		assert.Equal(t, time.UTC, runs[1].StartedAt.Location())
+
+		limited, err := repo.ListRuns(ctx, "porttest-job", 1)
+		require.NoError(t, err)
+		require.Len(t, limited, 1)
+		assert.Equal(t, newer.ID, limited[0].ID)
+	})
+}
@@ -0,0 +1,132 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// LeaseLockHarness - два экземпляра LeaseLock над одним хранилищем.
+type LeaseLockHarness struct {
+	Lock  ports.LeaseLock
+	Other ports.LeaseLock // как второй экземпляр приложения
+
+	// Break отнимает lease ключа в обход владельца, как истечение lease
+	// или обрыв соединения.
+	Break func(t *testing.T, key string)
+}
+
+// LeaseLockFactory создаёт harness над ПУСТЫМ хранилищем.
+type LeaseLockFactory func(t *testing.T) LeaseLockHarness
+
+// RunLeaseLockTests проверяет реализацию ports.LeaseLock.
+func RunLeaseLockTests(t *testing.T, factory LeaseLockFactory) {
+	// ttl короткий: watchdog продлевает lease каждые ttl/3
+	const ttl = 300 * time.Millisecond
+
+	acquire := func(t *testing.T, lock ports.LeaseLock, key string) ports.Lease {
+		t.Helper()
+		lease, err := lock.TryAcquire(context.Background(), key, ttl)
+		require.NoError(t, err)
+		require.NotNil(t, lease, "lease %s must be free", key)
+		t.Cleanup(func() { _ = lease.Release(context.Background()) })
+		return lease
+	}
+	assertHeld := func(t *testing.T, lock ports.LeaseLock, key string) {
+		t.Helper()
+		lease, err := lock.TryAcquire(context.Background(), key, ttl)
+		require.NoError(t, err)
+		assert.Nil(t, lease, "lease %s must be held by its owner", key)
+	}
+
+	t.Run("ExclusiveUntilReleased", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+
+		first := acquire(t, h.Lock, "porttest-lease")
+		assertHeld(t, h.Other, "porttest-lease")
+		assertHeld(t, h.Lock, "porttest-lease")
+
+		// Другой ключ блокируется независимо
+		acquire(t, h.Other, "porttest-other")
+
+		require.NoError(t, first.Release(ctx))
+		acquire(t, h.Other, "porttest-lease")
+	})
+
+	t.Run("FencingTokensIncrease", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+
+		var previous int64
+		for i, lock := range []ports.LeaseLock{h.Lock, h.Other, h.Lock} {
+			lease := acquire(t, lock, "porttest-lease")
+			assert.Greater(t, lease.Token(), previous, "lease %d", i)
+			previous = lease.Token()
+			require.NoError(t, lease.Release(ctx))
+		}
+	})
+
+	t.Run("WatchdogKeepsLeaseBeyondTTL", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+
+		lease := acquire(t, h.Lock, "porttest-lease")
+		time.Sleep(3 * ttl)
+
+		assertHeld(t, h.Other, "porttest-lease")
+		assert.NoError(t, lease.Check(ctx))
+		select {
+		case <-lease.Lost():
+			t.Fatal("held lease must not be reported lost")
+		default:
+		}
+	})
+
+	t.Run("ReleaseIsIdempotent", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+
+		lease := acquire(t, h.Lock, "porttest-lease")
+		require.NoError(t, lease.Release(ctx))
+		require.NoError(t, lease.Release(ctx))
+
+		next := acquire(t, h.Other, "porttest-lease")
+		require.NoError(t, lease.Release(ctx))
+		assertHeld(t, h.Lock, "porttest-lease")
+		assert.NoError(t, next.Check(ctx))
+
+		select {
+		case <-lease.Lost():
+			t.Fatal("released lease is not lost")
+		default:
+		}
+	})
+
+	t.Run("LostLease", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+
+		stale := acquire(t, h.Lock, "porttest-lease")
+		h.Break(t, "porttest-lease")
+
+		select {
+		case <-stale.Lost():
+		case <-time.After(5 * time.Second):
+			t.Fatal("watchdog must report the lost lease")
+		}
+		assert.ErrorIs(t, stale.Check(ctx), ports.ErrLeaseLost, "stale holder must not commit")
+
+		// Новый владелец получает больший token, потерянный lease его не снимает
+		next := acquire(t, h.Other, "porttest-lease")
+		assert.Greater(t, next.Token(), stale.Token())
+		require.NoError(t, stale.Release(ctx))
+		assertHeld(t, h.Lock, "porttest-lease")
+		assert.NoError(t, next.Check(ctx))
+	})
+}
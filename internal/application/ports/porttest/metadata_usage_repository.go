@@ -0,0 +1,129 @@
+package porttest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// MetadataUsageHarness - MetadataUsageRepository, UnitOfWork и репозитории
+// над тем же хранилищем (счётчики ссылаются на wallets).
+type MetadataUsageHarness struct {
+	Repositories
+	Usage      ports.MetadataUsageRepository
+	UnitOfWork ports.UnitOfWork
+}
+
+// MetadataUsageRepositoryFactory создаёт harness над ПУСТЫМ хранилищем.
+type MetadataUsageRepositoryFactory func(t *testing.T) MetadataUsageHarness
+
+// RunMetadataUsageRepositoryTests проверяет реализацию ports.MetadataUsageRepository.
+func RunMetadataUsageRepositoryTests(t *testing.T, factory MetadataUsageRepositoryFactory) {
+	usage := func(t *testing.T, h MetadataUsageHarness, walletID uuid.UUID) int64 {
+		t.Helper()
+		used, err := h.Usage.MetadataUsage(context.Background(), walletID)
+		require.NoError(t, err)
+		return used
+	}
+
+	t.Run("AddAccumulatesPerWallet", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		user := newUser(t, h.Repositories)
+		first := newWallet(t, h.Repositories, user.ID(), "USD")
+		second := newWallet(t, h.Repositories, user.ID(), "EUR")
+
+		assert.Zero(t, usage(t, h, first.ID()))
+
+		used, err := h.Usage.AddTransactionMetadata(ctx, first.ID(), uuid.New(), 100)
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), used)
+
+		used, err = h.Usage.AddTransactionMetadata(ctx, first.ID(), uuid.New(), 50)
+		require.NoError(t, err)
+		assert.Equal(t, int64(150), used)
+
+		assert.Equal(t, int64(150), usage(t, h, first.ID()))
+		assert.Zero(t, usage(t, h, second.ID()))
+	})
+
+	t.Run("SameTransactionCountedOnce", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		transactionID := uuid.New()
+
+		_, err := h.Usage.AddTransactionMetadata(ctx, wallet.ID(), transactionID, 100)
+		require.NoError(t, err)
+		_, err = h.Usage.AddTransactionMetadata(ctx, wallet.ID(), transactionID, 100)
+		assert.Error(t, err)
+
+		assert.Equal(t, int64(100), usage(t, h, wallet.ID()))
+	})
+
+	t.Run("UnknownWallet", func(t *testing.T) {
+		h := factory(t)
+
+		_, err := h.Usage.AddTransactionMetadata(context.Background(), uuid.New(), uuid.New(), 100)
+		assert.Error(t, err)
+	})
+
+	t.Run("RolledBackWithUnitOfWork", func(t *testing.T) {
+		h := factory(t)
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		_, err := h.Usage.AddTransactionMetadata(context.Background(), wallet.ID(), uuid.New(), 100)
+		require.NoError(t, err)
+
+		errAbort := errors.New("abort")
+		err = h.UnitOfWork.Execute(context.Background(), func(txCtx context.Context) error {
+			used, err := h.Usage.AddTransactionMetadata(txCtx, wallet.ID(), uuid.New(), 70)
+			require.NoError(t, err)
+			assert.Equal(t, int64(170), used)
+			return errAbort
+		})
+		require.ErrorIs(t, err, errAbort)
+
+		assert.Equal(t, int64(100), usage(t, h, wallet.ID()))
+	})
+
+	t.Run("ReleaseSubtractsArchivedTransactions", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		user := newUser(t, h.Repositories)
+		first := newWallet(t, h.Repositories, user.ID(), "USD")
+		second := newWallet(t, h.Repositories, user.ID(), "EUR")
+
+		archived, kept, other := uuid.New(), uuid.New(), uuid.New()
+		for walletID, added := range map[uuid.UUID]map[uuid.UUID]int64{
+			first.ID():  {archived: 100, kept: 50},
+			second.ID(): {other: 30},
+		} {
+			for transactionID, bytes := range added {
+				_, err := h.Usage.AddTransactionMetadata(ctx, walletID, transactionID, bytes)
+				require.NoError(t, err)
+			}
+		}
+
+		// Неучтённая транзакция пропускается
+		require.NoError(t, h.Usage.ReleaseTransactionMetadata(ctx, []uuid.UUID{archived, other, uuid.New()}))
+		assert.Equal(t, int64(50), usage(t, h, first.ID()))
+		assert.Zero(t, usage(t, h, second.ID()))
+
+		// Повторная архивация ничего не вычитает
+		require.NoError(t, h.Usage.ReleaseTransactionMetadata(ctx, []uuid.UUID{archived, other}))
+		assert.Equal(t, int64(50), usage(t, h, first.ID()))
+
+		// Освободившееся место снова учитывается
+		used, err := h.Usage.AddTransactionMetadata(ctx, first.ID(), uuid.New(), 20)
+		require.NoError(t, err)
+		assert.Equal(t, int64(70), used)
+
+		require.NoError(t, h.Usage.ReleaseTransactionMetadata(ctx, nil))
+	})
+}
@@ -0,0 +1,107 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// OnboardingHarness - OnboardingRepository и репозитории над тем же
+// хранилищем (onboardings ссылается на users).
+type OnboardingHarness struct {
+	Repositories
+	Onboardings ports.OnboardingRepository
+}
+
+// OnboardingRepositoryFactory создаёт harness над ПУСТЫМ хранилищем.
+type OnboardingRepositoryFactory func(t *testing.T) OnboardingHarness
+
+// RunOnboardingRepositoryTests проверяет реализацию ports.OnboardingRepository.
+func RunOnboardingRepositoryTests(t *testing.T, factory OnboardingRepositoryFactory) {
+	t.Run("SaveAndFind", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		user := newUser(t, h.Repositories)
+		usd := newWallet(t, h.Repositories, user.ID(), "USD")
+		eur := newWallet(t, h.Repositories, user.ID(), "EUR")
+
+		record := &ports.OnboardingRecord{
+			IdempotencyKey:  uuid.New(),
+			PartnerID:       uuid.New(),
+			RequestHash:     "hash",
+			UserID:          user.ID(),
+			WalletIDs:       []uuid.UUID{usd.ID(), eur.ID()},
+			TransactionIDs:  []uuid.UUID{uuid.New()},
+			KYCAutoApproved: true,
+			CreatedAt:       time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC),
+		}
+		require.NoError(t, h.Onboardings.Save(ctx, record))
+
+		found, err := h.Onboardings.FindByIdempotencyKey(ctx, record.IdempotencyKey)
+		require.NoError(t, err)
+		assert.Equal(t, record.PartnerID, found.PartnerID)
+		assert.Equal(t, record.RequestHash, found.RequestHash)
+		assert.Equal(t, record.UserID, found.UserID)
+		assert.Equal(t, record.WalletIDs, found.WalletIDs)
+		assert.Equal(t, record.TransactionIDs, found.TransactionIDs)
+		assert.True(t, found.KYCAutoApproved)
+		assert.True(t, record.CreatedAt.Equal(found.CreatedAt))
+	})
+
+	t.Run("WithoutWallets", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		record := &ports.OnboardingRecord{
+			IdempotencyKey: uuid.New(),
+			PartnerID:      uuid.New(),
+			RequestHash:    "hash",
+			UserID:         newUser(t, h.Repositories).ID(),
+			CreatedAt:      time.Now(),
+		}
+		require.NoError(t, h.Onboardings.Save(ctx, record))
+
+		found, err := h.Onboardings.FindByIdempotencyKey(ctx, record.IdempotencyKey)
+		require.NoError(t, err)
+		assert.Empty(t, found.WalletIDs)
+		assert.Empty(t, found.TransactionIDs)
+		assert.False(t, found.KYCAutoApproved)
+	})
+
+	t.Run("DuplicateKey", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		user := newUser(t, h.Repositories)
+		record := &ports.OnboardingRecord{
+			IdempotencyKey: uuid.New(),
+			PartnerID:      uuid.New(),
+			RequestHash:    "first",
+			UserID:         user.ID(),
+			CreatedAt:      time.Now(),
+		}
+		require.NoError(t, h.Onboardings.Save(ctx, record))
+
+		duplicate := *record
+		duplicate.RequestHash = "second"
+		err := h.Onboardings.Save(ctx, &duplicate)
+		assert.ErrorIs(t, err, domainErrors.ErrEntityAlreadyExists)
+
+		// Первая запись не перезаписана
+		found, err := h.Onboardings.FindByIdempotencyKey(ctx, record.IdempotencyKey)
+		require.NoError(t, err)
+		assert.Equal(t, "first", found.RequestHash)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		h := factory(t)
+
+		_, err := h.Onboardings.FindByIdempotencyKey(context.Background(), uuid.New())
+		assert.True(t, domainErrors.IsNotFound(err), "error = %v", err)
+	})
+}
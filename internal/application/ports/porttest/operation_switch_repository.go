@@ -0,0 +1,58 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// OperationSwitchRepositoryFactory создаёт репозиторий над ПУСТЫМ хранилищем.
+type OperationSwitchRepositoryFactory func(t *testing.T) ports.OperationSwitchRepository
+
+// RunOperationSwitchRepositoryTests проверяет реализацию ports.OperationSwitchRepository.
+func RunOperationSwitchRepositoryTests(t *testing.T, factory OperationSwitchRepositoryFactory) {
+	updatedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("EmptyMeansAllEnabled", func(t *testing.T) {
+		repo := factory(t)
+
+		switches, err := repo.FindAll(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, switches)
+	})
+
+	t.Run("SaveUpsertsByOperation", func(t *testing.T) {
+		repo := factory(t)
+		ctx := context.Background()
+		actorID := uuid.New()
+
+		withdraw := entities.ReconstructOperationSwitch(entities.TransactionTypeWithdraw, false, "provider outage", actorID, updatedAt)
+		deposit := entities.ReconstructOperationSwitch(entities.TransactionTypeDeposit, false, "bank maintenance", actorID, updatedAt)
+		require.NoError(t, repo.Save(ctx, withdraw))
+		require.NoError(t, repo.Save(ctx, deposit))
+
+		reenabled := entities.ReconstructOperationSwitch(entities.TransactionTypeWithdraw, true, "", actorID, updatedAt.Add(time.Minute))
+		require.NoError(t, repo.Save(ctx, reenabled))
+
+		switches, err := repo.FindAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, switches, 2)
+
+		assert.Equal(t, entities.TransactionTypeDeposit, switches[0].Operation())
+		assert.False(t, switches[0].Enabled())
+		assert.Equal(t, "bank maintenance", switches[0].Reason())
+
+		assert.Equal(t, entities.TransactionTypeWithdraw, switches[1].Operation())
+		assert.True(t, switches[1].Enabled())
+		assert.Empty(t, switches[1].Reason())
+		assert.Equal(t, actorID, switches[1].UpdatedBy())
+		assert.True(t, switches[1].UpdatedAt().Equal(updatedAt.Add(time.Minute)))
+	})
+}
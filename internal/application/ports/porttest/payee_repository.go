@@ -0,0 +1,127 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// PayeeHarness - PayeeRepository и репозитории над тем же хранилищем
+// (wallet_payees ссылается на wallets).
+type PayeeHarness struct {
+	Repositories
+	Payees ports.PayeeRepository
+}
+
+// PayeeRepositoryFactory создаёт harness над ПУСТЫМ хранилищем.
+type PayeeRepositoryFactory func(t *testing.T) PayeeHarness
+
+// RunPayeeRepositoryTests проверяет реализацию ports.PayeeRepository.
+func RunPayeeRepositoryTests(t *testing.T, factory PayeeRepositoryFactory) {
+	t.Run("RecordedPairIsKnownInOneDirection", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		user := newUser(t, h.Repositories)
+		source := newWallet(t, h.Repositories, user.ID(), "USD")
+		destination := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		known, err := h.Payees.HasPriorCompletedTransfer(ctx, source.ID(), destination.ID())
+		require.NoError(t, err)
+		assert.False(t, known)
+
+		require.NoError(t, h.Payees.RecordPayee(ctx, source.ID(), destination.ID(), uuid.New()))
+
+		known, err = h.Payees.HasPriorCompletedTransfer(ctx, source.ID(), destination.ID())
+		require.NoError(t, err)
+		assert.True(t, known)
+
+		// Обратное направление - другой получатель
+		known, err = h.Payees.HasPriorCompletedTransfer(ctx, destination.ID(), source.ID())
+		require.NoError(t, err)
+		assert.False(t, known)
+	})
+
+	t.Run("FirstTransferAtKeepsFirstRecord", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		user := newUser(t, h.Repositories)
+		source := newWallet(t, h.Repositories, user.ID(), "USD")
+		destination := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		_, known, err := h.Payees.FirstTransferAt(ctx, source.ID(), destination.ID())
+		require.NoError(t, err)
+		assert.False(t, known)
+
+		fake := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+		defer clock.SetClock(fake)()
+
+		require.NoError(t, h.Payees.RecordPayee(ctx, source.ID(), destination.ID(), uuid.New()))
+		fake.Advance(time.Hour)
+		require.NoError(t, h.Payees.RecordPayee(ctx, source.ID(), destination.ID(), uuid.New()))
+
+		at, known, err := h.Payees.FirstTransferAt(ctx, source.ID(), destination.ID())
+		require.NoError(t, err)
+		assert.True(t, known)
+		assert.True(t, at.Equal(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)), "first_transfer_at = %s", at)
+	})
+
+	t.Run("RecordIsIdempotent", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		user := newUser(t, h.Repositories)
+		source := newWallet(t, h.Repositories, user.ID(), "USD")
+		destination := newWallet(t, h.Repositories, user.ID(), "EUR")
+
+		require.NoError(t, h.Payees.RecordPayee(ctx, source.ID(), destination.ID(), uuid.New()))
+		require.NoError(t, h.Payees.RecordPayee(ctx, source.ID(), destination.ID(), uuid.New()))
+
+		known, err := h.Payees.HasPriorCompletedTransfer(ctx, source.ID(), destination.ID())
+		require.NoError(t, err)
+		assert.True(t, known)
+	})
+
+	t.Run("TransferredToSumsActiveTransfers", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		source := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		destination := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		other := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		transfer := func(to uuid.UUID, amount string) *entities.Transaction {
+			tx, err := entities.NewTransaction(source.ID(), uuid.NewString(), entities.TransactionTypeTransfer, money(t, amount, "USD"), "payee")
+			require.NoError(t, err)
+			require.NoError(t, tx.SetDestinationWallet(to))
+			return tx
+		}
+
+		pending := transfer(destination.ID(), "100.25")
+		require.NoError(t, h.Transactions.Save(ctx, pending))
+
+		completed := transfer(destination.ID(), "200")
+		require.NoError(t, completed.StartProcessing())
+		require.NoError(t, completed.MarkCompleted())
+		require.NoError(t, h.Transactions.Save(ctx, completed))
+
+		// Отменённые переводы и переводы другому получателю не считаются
+		cancelled := transfer(destination.ID(), "1000")
+		require.NoError(t, cancelled.Cancel())
+		require.NoError(t, h.Transactions.Save(ctx, cancelled))
+		require.NoError(t, h.Transactions.Save(ctx, transfer(other.ID(), "1000")))
+
+		total, err := h.Payees.TransferredTo(ctx, source.ID(), destination.ID(), currency(t, "USD"))
+		require.NoError(t, err)
+		assert.True(t, total.Equals(money(t, "300.25", "USD")), "total = %s", total)
+
+		total, err = h.Payees.TransferredTo(ctx, destination.ID(), source.ID(), currency(t, "USD"))
+		require.NoError(t, err)
+		assert.True(t, total.IsZero(), "reverse direction total = %s", total)
+	})
+}
@@ -0,0 +1,107 @@
+// Package porttest - conformance test suite для реализаций портов.
+//
+// Каждая реализация репозиториев (PostgreSQL, in-memory, ...) обязана вести
+// себя одинаково: одинаковые типы ошибок, одинаковая сортировка и пагинация,
+// одинаковая реакция на конфликт версий. Suite фиксирует этот контракт,
+// а адаптеры подключают его в своих тестах:
+//
+//	func TestWalletRepository_Conformance(t *testing.T) {
+//		porttest.RunWalletRepositoryTests(t, func(t *testing.T) porttest.Repositories {
+//			store := memory.NewStore()
+//			return porttest.Repositories{...}
+//		})
+//	}
+//
+// Решения по спорным случаям (см. также doc-комментарии в ports):
+//   - Find* методы, возвращающие одну entity, отдают ErrEntityNotFound
+//   - FindByIdempotencyKey тоже возвращает ErrEntityNotFound, а не nil, nil
+//   - Save устаревшей версии кошелька - ConcurrencyError
+//   - Пустой результат списка - пустой слайс или nil, оба допустимы
+//   - Timestamps читаются в UTC независимо от TZ процесса и сессии БД
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// Repositories - набор репозиториев над одним хранилищем.
+//
+// Репозитории должны разделять данные: wallet ссылается на user,
+// transaction - на wallet (foreign keys в PostgreSQL).
+type Repositories struct {
+	Users        ports.UserRepository
+	Wallets      ports.WalletRepository
+	Transactions ports.TransactionRepository
+}
+
+// Factory создаёт репозитории над ПУСТЫМ хранилищем.
+//
+// Вызывается перед каждым subtest, поэтому реализация должна либо
+// создавать новое хранилище, либо очищать существующее.
+type Factory func(t *testing.T) Repositories
+
+// ============================================
+// Fixtures
+// ============================================
+
+// newUser сохраняет нового пользователя и возвращает его.
+func newUser(t *testing.T, repos Repositories) *entities.User {
+	t.Helper()
+
+	user, err := entities.NewUser(uuid.NewString()+"@example.com", "Conformance User")
+	require.NoError(t, err)
+	require.NoError(t, repos.Users.Save(context.Background(), user))
+
+	return user
+}
+
+// newWallet сохраняет новый кошелёк пользователя в указанной валюте.
+func newWallet(t *testing.T, repos Repositories, userID uuid.UUID, code string) *entities.Wallet {
+	t.Helper()
+
+	wallet, err := entities.NewWallet(userID, currency(t, code))
+	require.NoError(t, err)
+	require.NoError(t, repos.Wallets.Save(context.Background(), wallet))
+
+	return wallet
+}
+
+// currency создаёт валюту или валит тест.
+func currency(t *testing.T, code string) valueobjects.Currency {
+	t.Helper()
+
+	c, err := valueobjects.NewCurrency(code)
+	require.NoError(t, err)
+
+	return c
+}
+
+// money создаёт сумму или валит тест.
+func money(t *testing.T, amount, code string) valueobjects.Money {
+	t.Helper()
+
+	m, err := valueobjects.NewMoney(amount, currency(t, code))
+	require.NoError(t, err)
+
+	return m
+}
+
+// setLocalTimeZone подменяет зону процесса (TZ и time.Local) до конца теста.
+// Используется фиксированное смещение, чтобы не зависеть от tzdata.
+func setLocalTimeZone(t *testing.T, name string, offsetHours int) {
+	t.Helper()
+
+	previous := time.Local
+	t.Setenv("TZ", name)
+	time.Local = time.FixedZone(name, offsetHours*60*60)
+	t.Cleanup(func() { time.Local = previous })
+}
@@ -0,0 +1,178 @@
+package porttest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// ProcessingRecoveryHarness - репозитории над ОДНИМ хранилищем.
+type ProcessingRecoveryHarness struct {
+	Repositories // Transactions - без экземпляра (транзакции до учёта владельцев)
+
+	// Owner сохраняет транзакции владельцем OwnerInstance
+	Owner         ports.TransactionRepository
+	OwnerInstance string
+
+	Recovery  ports.ProcessingRecoveryRepository
+	Instances ports.InstanceRepository
+}
+
+// ProcessingRecoveryFactory создаёт harness над ПУСТЫМ хранилищем.
+type ProcessingRecoveryFactory func(t *testing.T) ProcessingRecoveryHarness
+
+// RunProcessingRecoveryTests проверяет реализацию ports.ProcessingRecoveryRepository.
+func RunProcessingRecoveryTests(t *testing.T, factory ProcessingRecoveryFactory) {
+	// Все транзакции теста начаты раньше startedBefore
+	startedBefore := func() time.Time { return time.Now().UTC().Add(time.Minute) }
+	const aliveWindow = time.Minute
+
+	processing := func(t *testing.T, h ProcessingRecoveryHarness, repo ports.TransactionRepository) *entities.Transaction {
+		t.Helper()
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		tx := keyedTransaction(t, wallet, uuid.NewString(), "10.00")
+		require.NoError(t, tx.StartProcessing())
+		require.NoError(t, repo.Save(context.Background(), tx))
+		return tx
+	}
+	heartbeat := func(t *testing.T, h ProcessingRecoveryHarness, instance string, age time.Duration) {
+		t.Helper()
+		require.NoError(t, h.Instances.Heartbeat(context.Background(), instance, time.Now().UTC().Add(-age)))
+	}
+	claim := func(t *testing.T, h ProcessingRecoveryHarness, instance string) []uuid.UUID {
+		t.Helper()
+		heartbeat(t, h, instance, 0)
+		ids, err := h.Recovery.ClaimOrphaned(context.Background(), instance,
+			startedBefore(), time.Now().UTC().Add(-aliveWindow), 100)
+		require.NoError(t, err)
+		return ids
+	}
+
+	t.Run("AliveOwnerNeverClaimed", func(t *testing.T) {
+		h := factory(t)
+		tx := processing(t, h, h.Owner)
+
+		heartbeat(t, h, h.OwnerInstance, 0)
+		assert.Empty(t, claim(t, h, "sweeper"))
+
+		// Владелец перестал подавать heartbeat - транзакция осиротела
+		heartbeat(t, h, h.OwnerInstance, time.Hour)
+		assert.Equal(t, []uuid.UUID{tx.ID()}, claim(t, h, "sweeper"))
+	})
+
+	t.Run("OwnerWithoutHeartbeatIsOrphaned", func(t *testing.T) {
+		h := factory(t)
+		tx := processing(t, h, h.Owner)
+
+		assert.Equal(t, []uuid.UUID{tx.ID()}, claim(t, h, "sweeper"))
+	})
+
+	t.Run("UnownedIsOrphaned", func(t *testing.T) {
+		h := factory(t)
+		tx := processing(t, h, h.Transactions)
+
+		assert.Equal(t, []uuid.UUID{tx.ID()}, claim(t, h, "sweeper"))
+	})
+
+	t.Run("RecentNotClaimed", func(t *testing.T) {
+		h := factory(t)
+		processing(t, h, h.Owner)
+
+		ids, err := h.Recovery.ClaimOrphaned(context.Background(), "sweeper",
+			time.Now().UTC().Add(-time.Hour), time.Now().UTC().Add(-aliveWindow), 100)
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+	})
+
+	t.Run("OnlyProcessingClaimed", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		pending := keyedTransaction(t, wallet, uuid.NewString(), "10.00")
+		require.NoError(t, h.Owner.Save(ctx, pending))
+		completed := keyedTransaction(t, wallet, uuid.NewString(), "10.00")
+		require.NoError(t, completed.StartProcessing())
+		require.NoError(t, h.Owner.Save(ctx, completed))
+		require.NoError(t, completed.MarkCompleted())
+		require.NoError(t, h.Owner.Save(ctx, completed))
+
+		assert.Empty(t, claim(t, h, "sweeper"))
+	})
+
+	t.Run("OldestFirstWithinLimit", func(t *testing.T) {
+		h := factory(t)
+		first := processing(t, h, h.Owner)
+		time.Sleep(5 * time.Millisecond)
+		processing(t, h, h.Owner)
+
+		heartbeat(t, h, "sweeper", 0)
+		ids, err := h.Recovery.ClaimOrphaned(context.Background(), "sweeper",
+			startedBefore(), time.Now().UTC().Add(-aliveWindow), 1)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{first.ID()}, ids)
+	})
+
+	t.Run("ConcurrentSweepersClaimOnce", func(t *testing.T) {
+		h := factory(t)
+		orphans := make(map[uuid.UUID]bool)
+		for range 10 {
+			orphans[processing(t, h, h.Owner).ID()] = true
+		}
+
+		var (
+			mu      sync.Mutex
+			claimed = make(map[uuid.UUID]int)
+			wg      sync.WaitGroup
+		)
+		for _, sweeper := range []string{"sweeper-a", "sweeper-b", "sweeper-c"} {
+			heartbeat(t, h, sweeper, 0)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ids, err := h.Recovery.ClaimOrphaned(context.Background(), sweeper,
+					startedBefore(), time.Now().UTC().Add(-aliveWindow), 100)
+				assert.NoError(t, err)
+
+				mu.Lock()
+				defer mu.Unlock()
+				for _, id := range ids {
+					claimed[id]++
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, claimed, len(orphans))
+		for id, n := range claimed {
+			assert.True(t, orphans[id], "claimed foreign transaction %s", id)
+			assert.Equal(t, 1, n, "transaction %s claimed %d times", id, n)
+		}
+
+		// Новые владельцы живы - повторно не выдаются
+		assert.Empty(t, claim(t, h, "sweeper-d"))
+	})
+
+	t.Run("ReleaseReturnsToOrphans", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		tx := processing(t, h, h.Owner)
+
+		require.Equal(t, []uuid.UUID{tx.ID()}, claim(t, h, "sweeper-a"))
+
+		// Чужой Release не снимает владельца
+		require.NoError(t, h.Recovery.Release(ctx, tx.ID(), "sweeper-b"))
+		assert.Empty(t, claim(t, h, "sweeper-b"))
+
+		require.NoError(t, h.Recovery.Release(ctx, tx.ID(), "sweeper-a"))
+		assert.Equal(t, []uuid.UUID{tx.ID()}, claim(t, h, "sweeper-b"))
+	})
+}
@@ -0,0 +1,106 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// SecurityEventRepositoryFactory создаёт репозиторий над ПУСТЫМ хранилищем.
+type SecurityEventRepositoryFactory func(t *testing.T) ports.SecurityEventRepository
+
+// RunSecurityEventRepositoryTests проверяет реализацию ports.SecurityEventRepository.
+func RunSecurityEventRepositoryTests(t *testing.T, factory SecurityEventRepositoryFactory) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+
+	securityEvent := func(principal string, eventType entities.SecurityEventType, ip string, occurredAt time.Time) *entities.SecurityEvent {
+		return entities.ReconstructSecurityEvent(uuid.New(), principal, eventType, ip, "porttest/1.0", occurredAt)
+	}
+
+	t.Run("ListNewestFirstWithFilters", func(t *testing.T) {
+		repo := factory(t)
+		ctx := context.Background()
+		userID := uuid.NewString()
+
+		oldest := securityEvent(userID, entities.SecurityEventAuthFailed, "10.0.0.1", at(0))
+		succeeded := securityEvent(userID, entities.SecurityEventAuthSucceeded, "10.0.0.1", at(1))
+		otherIP := securityEvent(userID, entities.SecurityEventAuthFailed, "10.0.0.2", at(2))
+		anonymous := securityEvent("", entities.SecurityEventAuthFailed, "10.0.0.3", at(3))
+		for _, e := range []*entities.SecurityEvent{oldest, succeeded, otherIP, anonymous} {
+			require.NoError(t, repo.Append(ctx, e))
+		}
+
+		all, err := repo.List(ctx, ports.SecurityEventFilter{}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, securityEventIDs(anonymous, otherIP, succeeded, oldest), securityEventIDs(all...))
+
+		failed := entities.SecurityEventAuthFailed
+		byUser, err := repo.List(ctx, ports.SecurityEventFilter{Principal: &userID, Type: &failed}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, securityEventIDs(otherIP, oldest), securityEventIDs(byUser...))
+
+		ip := "10.0.0.1"
+		from, to := at(1), at(3)
+		ranged, err := repo.List(ctx, ports.SecurityEventFilter{IP: &ip, OccurredFrom: &from, OccurredTo: &to}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, securityEventIDs(succeeded), securityEventIDs(ranged...))
+
+		page, err := repo.List(ctx, ports.SecurityEventFilter{}, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, securityEventIDs(otherIP, succeeded), securityEventIDs(page...))
+	})
+
+	t.Run("ReadsBackAllFields", func(t *testing.T) {
+		repo := factory(t)
+		ctx := context.Background()
+
+		stored := securityEvent("key:abcd1234", entities.SecurityEventScopeDenied, "192.0.2.7", at(0))
+		require.NoError(t, repo.Append(ctx, stored))
+
+		got, err := repo.List(ctx, ports.SecurityEventFilter{}, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, stored.ID(), got[0].ID())
+		assert.Equal(t, "key:abcd1234", got[0].Principal())
+		assert.Equal(t, entities.SecurityEventScopeDenied, got[0].Type())
+		assert.Equal(t, "192.0.2.7", got[0].IP())
+		assert.Equal(t, "porttest/1.0", got[0].UserAgent())
+		assert.True(t, stored.OccurredAt().Equal(got[0].OccurredAt()))
+		assert.Equal(t, time.UTC, got[0].OccurredAt().Location())
+	})
+
+	t.Run("DeleteBeforeKeepsCutoff", func(t *testing.T) {
+		repo := factory(t)
+		ctx := context.Background()
+
+		expired := securityEvent("", entities.SecurityEventAuthFailed, "10.0.0.1", at(0))
+		atCutoff := securityEvent("", entities.SecurityEventAuthFailed, "10.0.0.1", at(10))
+		for _, e := range []*entities.SecurityEvent{expired, atCutoff} {
+			require.NoError(t, repo.Append(ctx, e))
+		}
+
+		deleted, err := repo.DeleteBefore(ctx, at(10))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		left, err := repo.List(ctx, ports.SecurityEventFilter{}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, securityEventIDs(atCutoff), securityEventIDs(left...))
+	})
+}
+
+func securityEventIDs(list ...*entities.SecurityEvent) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(list))
+	for _, e := range list {
+		ids = append(ids, e.ID())
+	}
+	return ids
+}
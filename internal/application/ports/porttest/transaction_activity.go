@@ -0,0 +1,253 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// TransactionActivityHarness - репозитории над ОДНИМ хранилищем.
+type TransactionActivityHarness struct {
+	Repositories
+	Activity ports.TransactionActivityRepository
+}
+
+// TransactionActivityFactory создаёт harness над ПУСТЫМ хранилищем.
+type TransactionActivityFactory func(t *testing.T) TransactionActivityHarness
+
+// RunTransactionActivityTests проверяет контракт
+// ports.TransactionActivityRepository: границы дней в зоне пользователя
+// (включая переходы на летнее время), направление сумм и учёт переводов.
+func RunTransactionActivityTests(t *testing.T, factory TransactionActivityFactory) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// 2024 год по Нью-Йорку
+	yearFilter := func(walletIDs ...uuid.UUID) ports.ActivityFilter {
+		return ports.ActivityFilter{
+			WalletIDs: walletIDs,
+			From:      time.Date(2024, time.January, 1, 0, 0, 0, 0, newYork),
+			To:        time.Date(2025, time.January, 1, 0, 0, 0, 0, newYork),
+			Location:  newYork,
+		}
+	}
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("DaysFollowDaylightSavingTime", func(t *testing.T) {
+		h := factory(t)
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		for _, createdAt := range []string{
+			// 10 марта - 23 часа: 00:00 EST = 05:00Z, 24:00 EDT = 04:00Z
+			"2024-03-10T04:30:00Z", // 9 марта, 23:30 EST
+			"2024-03-10T05:30:00Z", // 10 марта, 00:30 EST
+			"2024-03-11T03:30:00Z", // 10 марта, 23:30 EDT
+			"2024-03-11T04:30:00Z", // 11 марта, 00:30 EDT
+			// 3 ноября - 25 часов: 00:00 EDT = 04:00Z, 24:00 EST = 05:00Z
+			"2024-11-03T03:30:00Z", // 2 ноября, 23:30 EDT
+			"2024-11-03T04:30:00Z", // 3 ноября, 00:30 EDT
+			"2024-11-04T04:30:00Z", // 3 ноября, 23:30 EST
+			"2024-11-04T05:30:00Z", // 4 ноября, 00:30 EST
+		} {
+			saveActivityTransaction(t, h, wallet, entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, "1.00", nil, mustParseTime(t, createdAt))
+		}
+
+		activity, err := h.Activity.DailyActivity(context.Background(), yearFilter(wallet.ID()))
+		require.NoError(t, err)
+
+		counts := make(map[time.Time]int)
+		for _, a := range activity {
+			assert.Equal(t, time.UTC, a.Date.Location())
+			counts[a.Date] = a.Count
+		}
+		assert.Equal(t, map[time.Time]int{
+			day(time.March, 9): 1, day(time.March, 10): 2, day(time.March, 11): 1,
+			day(time.November, 2): 1, day(time.November, 3): 2, day(time.November, 4): 1,
+		}, counts)
+	})
+
+	t.Run("RangeIsHalfOpen", func(t *testing.T) {
+		h := factory(t)
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		filter := yearFilter(wallet.ID())
+
+		saveActivityTransaction(t, h, wallet, entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, "1.00", nil, filter.From.Add(-time.Second))
+		saveActivityTransaction(t, h, wallet, entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, "2.00", nil, filter.From)
+		saveActivityTransaction(t, h, wallet, entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, "4.00", nil, filter.To)
+
+		activity, err := h.Activity.DailyActivity(context.Background(), filter)
+		require.NoError(t, err)
+		require.Len(t, activity, 1)
+		assert.Equal(t, day(time.January, 1), activity[0].Date)
+		assert.Equal(t, "2.00 USD", activity[0].Credit.String())
+	})
+
+	t.Run("SumsByDirectionAndCurrency", func(t *testing.T) {
+		h := factory(t)
+		user := newUser(t, h.Repositories)
+		usd := newWallet(t, h.Repositories, user.ID(), "USD")
+		eur := newWallet(t, h.Repositories, user.ID(), "EUR")
+		at := time.Date(2024, time.June, 1, 12, 0, 0, 0, newYork)
+
+		saveActivityTransaction(t, h, usd, entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, "100.00", nil, at)
+		saveActivityTransaction(t, h, usd, entities.TransactionTypeRefund, entities.TransactionStatusCompleted, "5.00", nil, at)
+		saveActivityTransaction(t, h, usd, entities.TransactionTypeWithdraw, entities.TransactionStatusCompleted, "30.00", nil, at)
+		saveActivityTransaction(t, h, usd, entities.TransactionTypeFee, entities.TransactionStatusCompleted, "0.50", nil, at)
+		saveActivityTransaction(t, h, eur, entities.TransactionTypePayout, entities.TransactionStatusCompleted, "7.25", nil, at)
+		// Незавершённые не учитываются
+		saveActivityTransaction(t, h, usd, entities.TransactionTypeDeposit, entities.TransactionStatusPending, "1000.00", nil, at)
+		saveActivityTransaction(t, h, usd, entities.TransactionTypeWithdraw, entities.TransactionStatusFailed, "1000.00", nil, at)
+
+		activity, err := h.Activity.DailyActivity(context.Background(), yearFilter(usd.ID(), eur.ID()))
+		require.NoError(t, err)
+		assert.Equal(t, []activitySums{
+			{Date: day(time.June, 1), CurrencyCode: "EUR", Count: 1, Credit: "0.00 EUR", Debit: "7.25 EUR"},
+			{Date: day(time.June, 1), CurrencyCode: "USD", Count: 4, Credit: "105.00 USD", Debit: "30.50 USD"},
+		}, sumsOf(activity))
+	})
+
+	t.Run("Transfers", func(t *testing.T) {
+		h := factory(t)
+		source := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		destination := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		destinationID := destination.ID()
+		at := time.Date(2024, time.June, 1, 12, 0, 0, 0, newYork)
+
+		saveActivityTransaction(t, h, source, entities.TransactionTypeTransfer, entities.TransactionStatusCompleted, "10.00", &destinationID, at)
+
+		ctx := context.Background()
+		outgoing, err := h.Activity.DailyActivity(ctx, yearFilter(source.ID()))
+		require.NoError(t, err)
+		assert.Equal(t, []activitySums{
+			{Date: day(time.June, 1), CurrencyCode: "USD", Count: 1, Credit: "0.00 USD", Debit: "10.00 USD"},
+		}, sumsOf(outgoing))
+
+		// Входящий перевод - зачисление суммы за вычетом комиссии
+		incoming, err := h.Activity.DailyActivity(ctx, yearFilter(destination.ID()))
+		require.NoError(t, err)
+		assert.Equal(t, []activitySums{
+			{Date: day(time.June, 1), CurrencyCode: "USD", Count: 1, Credit: "9.90 USD", Debit: "0.00 USD"},
+		}, sumsOf(incoming))
+
+		// Перевод между кошельками фильтра - одна транзакция
+		both, err := h.Activity.DailyActivity(ctx, yearFilter(source.ID(), destination.ID()))
+		require.NoError(t, err)
+		assert.Equal(t, []activitySums{
+			{Date: day(time.June, 1), CurrencyCode: "USD", Count: 1, Credit: "9.90 USD", Debit: "10.00 USD"},
+		}, sumsOf(both))
+	})
+
+	t.Run("SumsBeyondInt64", func(t *testing.T) {
+		h := factory(t)
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		at := time.Date(2024, time.June, 1, 12, 0, 0, 0, newYork)
+
+		// Каждая сумма помещается в BIGINT, их сумма - нет
+		for range 2 {
+			saveActivityTransaction(t, h, wallet, entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, "60000000000000000.00", nil, at)
+		}
+
+		activity, err := h.Activity.DailyActivity(context.Background(), yearFilter(wallet.ID()))
+		require.NoError(t, err)
+		assert.Equal(t, []activitySums{
+			{Date: day(time.June, 1), CurrencyCode: "USD", Count: 2, Credit: "120000000000000000.00 USD", Debit: "0.00 USD"},
+		}, sumsOf(activity))
+	})
+
+	t.Run("OtherWalletsAndEmptyFilter", func(t *testing.T) {
+		h := factory(t)
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		other := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		saveActivityTransaction(t, h, other, entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, "1.00", nil, time.Date(2024, time.June, 1, 12, 0, 0, 0, newYork))
+
+		activity, err := h.Activity.DailyActivity(context.Background(), yearFilter(wallet.ID()))
+		require.NoError(t, err)
+		assert.Empty(t, activity)
+
+		activity, err = h.Activity.DailyActivity(context.Background(), yearFilter())
+		require.NoError(t, err)
+		assert.Empty(t, activity)
+	})
+}
+
+// activitySums - ports.DailyActivity с суммами строками: Money с big.Rat
+// внутри не сравнивается через assert.Equal.
+type activitySums struct {
+	Date          time.Time
+	CurrencyCode  string
+	Count         int
+	Credit, Debit string
+}
+
+func sumsOf(activity []ports.DailyActivity) []activitySums {
+	sums := make([]activitySums, len(activity))
+	for i, a := range activity {
+		sums[i] = activitySums{
+			Date:         a.Date,
+			CurrencyCode: a.CurrencyCode,
+			Count:        a.Count,
+			Credit:       a.Credit.String(),
+			Debit:        a.Debit.String(),
+		}
+	}
+	return sums
+}
+
+// saveActivityTransaction сохраняет транзакцию с заданным created_at.
+// У переводов (destination != nil) комиссия - 1% суммы.
+func saveActivityTransaction(
+	t *testing.T, h TransactionActivityHarness, wallet *entities.Wallet,
+	txType entities.TransactionType, status entities.TransactionStatus,
+	amount string, destination *uuid.UUID, createdAt time.Time,
+) *entities.Transaction {
+	t.Helper()
+
+	gross := money(t, amount, wallet.Currency().Code())
+	fee := valueobjects.Zero(gross.Currency())
+	if destination != nil {
+		var err error
+		fee, err = valueobjects.NewMoneyFromCents(gross.Cents()/100, gross.Currency())
+		require.NoError(t, err)
+	}
+	net, err := gross.Subtract(fee)
+	require.NoError(t, err)
+
+	var processedAt, completedAt *time.Time
+	if status != entities.TransactionStatusPending {
+		processedAt = &createdAt
+	}
+	if status == entities.TransactionStatusCompleted {
+		completedAt = &createdAt
+	}
+	tx, err := entities.ReconstructTransaction(
+		uuid.New(), wallet.ID(), uuid.NewString(),
+		txType, status,
+		gross, fee, net,
+		destination, "", "", "conformance", nil, "", "", 0, nil, "", "",
+		createdAt, createdAt, processedAt, completedAt,
+		"",
+	)
+	require.NoError(t, err)
+	require.NoError(t, h.Transactions.Save(context.Background(), tx))
+
+	return tx
+}
+
+// mustParseTime разбирает RFC 3339 или валит тест.
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+	return parsed
+}
@@ -0,0 +1,137 @@
+package porttest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// RunTransactionBackfillRepositoryTests проверяет реализацию
+// ports.TransactionBackfillRepository. Factory должна отдавать Transactions,
+// реализующий и этот порт.
+func RunTransactionBackfillRepositoryTests(t *testing.T, factory Factory) {
+	backfill := func(t *testing.T, repos Repositories) ports.TransactionBackfillRepository {
+		t.Helper()
+		repo, ok := repos.Transactions.(ports.TransactionBackfillRepository)
+		require.True(t, ok, "transaction repository does not implement TransactionBackfillRepository")
+		return repo
+	}
+
+	t.Run("InsertBatchKeepsRecordAsIs", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		source := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+		dest := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+
+		destID := dest.ID()
+		createdAt := time.Date(2019, 3, 4, 10, 0, 0, 0, time.UTC)
+		completedAt := createdAt.Add(time.Minute)
+		transfer := importedTransaction(t, entities.TransactionImport{
+			ID: uuid.New(), WalletID: source.ID(), DestinationWalletID: &destID,
+			Type: entities.TransactionTypeTransfer, Status: entities.TransactionStatusCompleted,
+			CurrencyCode: "USD", AmountCents: 1000, FeeCents: 50,
+			CreatedAt: createdAt, CompletedAt: &completedAt,
+		})
+		failed := importedTransaction(t, entities.TransactionImport{
+			ID: uuid.New(), WalletID: source.ID(),
+			Type: entities.TransactionTypeWithdraw, Status: entities.TransactionStatusFailed,
+			CurrencyCode: "USD", AmountCents: 300, FailureReason: "TIMEOUT",
+			CreatedAt: createdAt,
+		})
+
+		require.NoError(t, backfill(t, repos).InsertBatch(ctx, []*entities.Transaction{transfer, failed}))
+
+		loaded, err := repos.Transactions.FindByID(ctx, transfer.ID())
+		require.NoError(t, err)
+		assert.Equal(t, entities.TransactionStatusCompleted, loaded.Status())
+		assert.Equal(t, "10.00 USD", loaded.Amount().String())
+		assert.Equal(t, "0.50 USD", loaded.FeeAmount().String())
+		assert.Equal(t, "9.50 USD", loaded.NetAmount().String())
+		assert.Equal(t, entities.SourceLegacy, loaded.Metadata()[entities.MetadataSource])
+		assertSameUTC(t, createdAt, loaded.CreatedAt())
+		assertSameUTC(t, completedAt, loaded.UpdatedAt())
+		require.NotNil(t, loaded.CompletedAt())
+		assertSameUTC(t, completedAt, *loaded.CompletedAt())
+
+		loaded, err = repos.Transactions.FindByID(ctx, failed.ID())
+		require.NoError(t, err)
+		assert.Equal(t, entities.TransactionStatusFailed, loaded.Status())
+		assert.Equal(t, entities.FailureCategoryProvider, loaded.FailureCategory())
+		assert.Nil(t, loaded.CompletedAt())
+
+		// Балансы не изменяются
+		wallet, err := repos.Wallets.FindByID(ctx, source.ID())
+		require.NoError(t, err)
+		assert.True(t, wallet.AvailableBalance().IsZero())
+	})
+
+	t.Run("ExistingIDs", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+		stored := saveTransactionAt(t, repos, wallet, time.Now().Add(-time.Hour))
+
+		existing, err := backfill(t, repos).ExistingIDs(ctx, []uuid.UUID{stored.ID(), uuid.New()})
+		require.NoError(t, err)
+		assert.Equal(t, map[uuid.UUID]struct{}{stored.ID(): {}}, existing)
+	})
+
+	t.Run("DuplicateRejectsWholeBatch", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+		stored := saveTransactionAt(t, repos, wallet, time.Now().Add(-time.Hour))
+
+		fresh := importedDeposit(t, wallet.ID())
+		duplicate := importedTransaction(t, entities.TransactionImport{
+			ID: stored.ID(), WalletID: wallet.ID(),
+			Type: entities.TransactionTypeDeposit, Status: entities.TransactionStatusCancelled,
+			CurrencyCode: "USD", AmountCents: 100, CreatedAt: time.Now().Add(-time.Hour),
+		})
+
+		err := backfill(t, repos).InsertBatch(ctx, []*entities.Transaction{fresh, duplicate})
+		assert.True(t, errors.Is(err, domainErrors.ErrDuplicateTransaction), "expected ErrDuplicateTransaction, got %v", err)
+
+		_, err = repos.Transactions.FindByID(ctx, fresh.ID())
+		assert.True(t, domainErrors.IsNotFound(err), "batch must not be partially written")
+	})
+
+	t.Run("UnknownWallet", func(t *testing.T) {
+		repos := factory(t)
+
+		err := backfill(t, repos).InsertBatch(context.Background(), []*entities.Transaction{importedDeposit(t, uuid.New())})
+		require.Error(t, err)
+		assertDomainErrorCode(t, err, "WALLET_NOT_FOUND")
+	})
+}
+
+// importedTransaction собирает импортируемую транзакцию или валит тест.
+func importedTransaction(t *testing.T, record entities.TransactionImport) *entities.Transaction {
+	t.Helper()
+
+	tx, err := entities.ReconstructForImport(record)
+	require.NoError(t, err)
+
+	return tx
+}
+
+// importedDeposit - COMPLETED депозит 1.00 USD из legacy системы.
+func importedDeposit(t *testing.T, walletID uuid.UUID) *entities.Transaction {
+	t.Helper()
+
+	createdAt := time.Now().Add(-24 * time.Hour)
+	return importedTransaction(t, entities.TransactionImport{
+		ID: uuid.New(), WalletID: walletID,
+		Type: entities.TransactionTypeDeposit, Status: entities.TransactionStatusCompleted,
+		CurrencyCode: "USD", AmountCents: 100, CreatedAt: createdAt, CompletedAt: &createdAt,
+	})
+}
@@ -0,0 +1,120 @@
+package porttest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// TransactionEnvironmentHarness - репозитории транзакций разных окружений
+// над ОДНИМ хранилищем (sandbox и production в общей базе).
+type TransactionEnvironmentHarness struct {
+	Repositories // Transactions - без окружения (транзакции до его учёта)
+	Sandbox      ports.TransactionRepository
+	Production   ports.TransactionRepository
+}
+
+// TransactionEnvironmentFactory создаёт harness над ПУСТЫМ хранилищем.
+type TransactionEnvironmentFactory func(t *testing.T) TransactionEnvironmentHarness
+
+// RunTransactionEnvironmentTests проверяет, что ключи идемпотентности
+// уникальны в пределах окружения, а не всего хранилища.
+func RunTransactionEnvironmentTests(t *testing.T, factory TransactionEnvironmentFactory) {
+	t.Run("SameKeyOncePerEnvironment", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		key := uuid.NewString()
+
+		sandboxTx := keyedTransaction(t, wallet, key, "10.00")
+		require.NoError(t, h.Sandbox.Save(ctx, sandboxTx))
+
+		_, err := h.Production.FindByIdempotencyKey(ctx, key)
+		assert.True(t, domainErrors.IsNotFound(err), "sandbox key visible in production: %v", err)
+
+		productionTx := keyedTransaction(t, wallet, key, "20.00")
+		require.NoError(t, h.Production.Save(ctx, productionTx))
+
+		found, err := h.Sandbox.FindByIdempotencyKey(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, sandboxTx.ID(), found.ID())
+		found, err = h.Production.FindByIdempotencyKey(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, productionTx.ID(), found.ID())
+
+		err = h.Sandbox.Save(ctx, keyedTransaction(t, wallet, key, "30.00"))
+		assert.True(t, errors.Is(err, domainErrors.ErrDuplicateTransaction), "expected ErrDuplicateTransaction, got %v", err)
+		err = h.Production.Save(ctx, keyedTransaction(t, wallet, key, "30.00"))
+		assert.True(t, errors.Is(err, domainErrors.ErrDuplicateTransaction), "expected ErrDuplicateTransaction, got %v", err)
+	})
+
+	t.Run("UpdateKeepsEnvironment", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		tx := keyedTransaction(t, wallet, uuid.NewString(), "10.00")
+		require.NoError(t, h.Sandbox.Save(ctx, tx))
+		require.NoError(t, tx.StartProcessing())
+		require.NoError(t, h.Production.Save(ctx, tx))
+
+		found, err := h.Sandbox.FindByIdempotencyKey(ctx, tx.IdempotencyKey())
+		require.NoError(t, err)
+		assert.Equal(t, entities.TransactionStatusProcessing, found.Status())
+		_, err = h.Production.FindByIdempotencyKey(ctx, tx.IdempotencyKey())
+		assert.True(t, domainErrors.IsNotFound(err), "expected ErrEntityNotFound, got %v", err)
+	})
+
+	t.Run("UntaggedVisibleFromEveryEnvironment", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		legacy := keyedTransaction(t, wallet, uuid.NewString(), "10.00")
+		require.NoError(t, h.Transactions.Save(ctx, legacy))
+
+		for name, repo := range map[string]ports.TransactionRepository{"sandbox": h.Sandbox, "production": h.Production} {
+			found, err := repo.FindByIdempotencyKey(ctx, legacy.IdempotencyKey())
+			require.NoError(t, err, name)
+			assert.Equal(t, legacy.ID(), found.ID(), name)
+		}
+	})
+
+	t.Run("OwnEnvironmentPreferred", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		key := uuid.NewString()
+
+		legacy := keyedTransaction(t, wallet, key, "10.00")
+		require.NoError(t, h.Transactions.Save(ctx, legacy))
+		production := keyedTransaction(t, wallet, key, "20.00")
+		require.NoError(t, h.Production.Save(ctx, production))
+
+		found, err := h.Production.FindByIdempotencyKey(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, production.ID(), found.ID())
+		found, err = h.Sandbox.FindByIdempotencyKey(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, legacy.ID(), found.ID())
+	})
+}
+
+// keyedTransaction создаёт (не сохраняя) DEPOSIT с заданным ключом идемпотентности.
+func keyedTransaction(t *testing.T, wallet *entities.Wallet, key, amount string) *entities.Transaction {
+	t.Helper()
+
+	tx, err := entities.NewTransaction(
+		wallet.ID(), key, entities.TransactionTypeDeposit, money(t, amount, wallet.Currency().Code()), "conformance",
+	)
+	require.NoError(t, err)
+	return tx
+}
@@ -0,0 +1,124 @@
+package porttest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// TransactionReceiptHarness - репозитории над ОДНИМ хранилищем.
+type TransactionReceiptHarness struct {
+	Repositories
+	Receipts ports.TransactionReceiptRepository
+}
+
+// TransactionReceiptFactory создаёт harness над ПУСТЫМ хранилищем.
+type TransactionReceiptFactory func(t *testing.T) TransactionReceiptHarness
+
+// RunTransactionReceiptTests проверяет, что Save присваивает номер чека
+// завершённым транзакциям и что номер уникален и находится по
+// ports.TransactionReceiptRepository.
+func RunTransactionReceiptTests(t *testing.T, factory TransactionReceiptFactory) {
+	complete := func(t *testing.T, h TransactionReceiptHarness, wallet *entities.Wallet) *entities.Transaction {
+		t.Helper()
+		tx := newTransaction(t, h.Repositories, wallet, entities.TransactionTypeDeposit, "10.00")
+		require.NoError(t, tx.StartProcessing())
+		require.NoError(t, tx.MarkCompleted())
+		require.NoError(t, h.Transactions.Save(context.Background(), tx))
+		return tx
+	}
+
+	t.Run("CompletedTransactionGetsReceipt", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		tx := complete(t, h, wallet)
+		number := tx.ReceiptNumber()
+		parsed, err := valueobjects.ParseReceiptNumber(number)
+		require.NoError(t, err)
+		assert.Equal(t, number, parsed)
+
+		found, err := h.Receipts.FindByReceiptNumber(ctx, number)
+		require.NoError(t, err)
+		assert.Equal(t, tx.ID(), found.ID())
+		assert.Equal(t, number, found.ReceiptNumber())
+
+		// Повторный Save не меняет номер
+		require.NoError(t, h.Transactions.Save(ctx, found))
+		reloaded, err := h.Transactions.FindByID(ctx, tx.ID())
+		require.NoError(t, err)
+		assert.Equal(t, number, reloaded.ReceiptNumber())
+	})
+
+	t.Run("UnfinishedAndFailedHaveNoReceipt", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		pending := newTransaction(t, h.Repositories, wallet, entities.TransactionTypeDeposit, "10.00")
+		failed := newTransaction(t, h.Repositories, wallet, entities.TransactionTypeDeposit, "10.00")
+		require.NoError(t, failed.StartProcessing())
+		require.NoError(t, failed.MarkFailed("declined", entities.FailureCategoryClient))
+		require.NoError(t, h.Transactions.Save(ctx, failed))
+
+		for _, tx := range []*entities.Transaction{pending, failed} {
+			loaded, err := h.Transactions.FindByID(ctx, tx.ID())
+			require.NoError(t, err)
+			assert.Empty(t, loaded.ReceiptNumber(), "status %s", loaded.Status())
+		}
+	})
+
+	t.Run("UniqueUnderParallelCompletions", func(t *testing.T) {
+		h := factory(t)
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		const n = 20
+		transactions := make([]*entities.Transaction, n)
+		for i := range transactions {
+			transactions[i] = newTransaction(t, h.Repositories, wallet, entities.TransactionTypeDeposit, "1.00")
+			require.NoError(t, transactions[i].StartProcessing())
+			require.NoError(t, transactions[i].MarkCompleted())
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, n)
+		for i, tx := range transactions {
+			wg.Add(1)
+			go func(i int, tx *entities.Transaction) {
+				defer wg.Done()
+				errs[i] = h.Transactions.Save(context.Background(), tx)
+			}(i, tx)
+		}
+		wg.Wait()
+
+		seen := make(map[string]bool, n)
+		for i, tx := range transactions {
+			require.NoError(t, errs[i])
+			require.NotEmpty(t, tx.ReceiptNumber())
+			assert.False(t, seen[tx.ReceiptNumber()], "duplicate receipt %s", tx.ReceiptNumber())
+			seen[tx.ReceiptNumber()] = true
+		}
+	})
+
+	t.Run("UnknownNumberNotFound", func(t *testing.T) {
+		h := factory(t)
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		tx := complete(t, h, wallet)
+
+		number, err := valueobjects.NewReceiptNumber(valueobjects.MaxReceiptSequence)
+		require.NoError(t, err)
+		require.NotEqual(t, tx.ReceiptNumber(), number)
+
+		_, err = h.Receipts.FindByReceiptNumber(context.Background(), number)
+		assert.True(t, domainErrors.IsNotFound(err), "expected not found, got %v", err)
+	})
+}
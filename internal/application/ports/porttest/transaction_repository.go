@@ -0,0 +1,615 @@
+package porttest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// RunTransactionRepositoryTests проверяет реализацию ports.TransactionRepository на соответствие контракту.
+func RunTransactionRepositoryTests(t *testing.T, factory Factory) {
+	t.Run("SaveAndFindByID", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+
+		tx := newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "50.00")
+		require.NoError(t, tx.AddMetadata("source", "card"))
+		require.NoError(t, repos.Transactions.Save(ctx, tx))
+
+		loaded, err := repos.Transactions.FindByID(ctx, tx.ID())
+		require.NoError(t, err)
+		assert.Equal(t, tx.ID(), loaded.ID())
+		assert.Equal(t, wallet.ID(), loaded.WalletID())
+		assert.Equal(t, tx.IdempotencyKey(), loaded.IdempotencyKey())
+		assert.Equal(t, entities.TransactionTypeDeposit, loaded.Type())
+		assert.Equal(t, entities.TransactionStatusPending, loaded.Status())
+		assert.Equal(t, "50.00 USD", loaded.Amount().String())
+		assert.Equal(t, "card", loaded.Metadata()["source"])
+		assert.Nil(t, loaded.DestinationWalletID())
+		assert.Nil(t, loaded.CompletedAt())
+	})
+
+	t.Run("SaveUpdatesExisting", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+		tx := newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "10.00")
+
+		require.NoError(t, tx.StartProcessing())
+		require.NoError(t, tx.MarkCompleted())
+		require.NoError(t, repos.Transactions.Save(ctx, tx))
+
+		loaded, err := repos.Transactions.FindByID(ctx, tx.ID())
+		require.NoError(t, err)
+		assert.Equal(t, entities.TransactionStatusCompleted, loaded.Status())
+		assert.NotNil(t, loaded.ProcessedAt())
+		assert.NotNil(t, loaded.CompletedAt())
+	})
+
+	t.Run("FailureCategory", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+		tx := newTransaction(t, repos, wallet, entities.TransactionTypeWithdraw, "10.00")
+
+		require.NoError(t, tx.MarkFailed("INSUFFICIENT_BALANCE", entities.FailureCategoryClient))
+		require.NoError(t, repos.Transactions.Save(ctx, tx))
+
+		loaded, err := repos.Transactions.FindByID(ctx, tx.ID())
+		require.NoError(t, err)
+		assert.Equal(t, entities.FailureCategoryClient, loaded.FailureCategory())
+		assert.False(t, loaded.IsRetryable())
+
+		// Повтор очищает категорию вместе с причиной
+		require.NoError(t, loaded.Retry(entities.DefaultMaxRetries))
+		require.NoError(t, repos.Transactions.Save(ctx, loaded))
+
+		reloaded, err := repos.Transactions.FindByID(ctx, tx.ID())
+		require.NoError(t, err)
+		assert.Empty(t, reloaded.FailureCategory())
+	})
+
+	t.Run("CountOutcomes", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+		from := time.Now().Add(-time.Minute)
+
+		completed := newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "10.00")
+		require.NoError(t, completed.StartProcessing())
+		require.NoError(t, completed.MarkCompleted())
+		require.NoError(t, repos.Transactions.Save(ctx, completed))
+
+		for _, category := range []entities.FailureCategory{
+			entities.FailureCategoryClient, entities.FailureCategoryClient, entities.FailureCategorySystem,
+		} {
+			failed := newTransaction(t, repos, wallet, entities.TransactionTypeWithdraw, "1.00")
+			require.NoError(t, failed.MarkFailed("declined", category))
+			require.NoError(t, repos.Transactions.Save(ctx, failed))
+		}
+
+		// PENDING и CANCELLED не считаются
+		newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "1.00")
+		cancelled := newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "1.00")
+		require.NoError(t, cancelled.Cancel())
+		require.NoError(t, repos.Transactions.Save(ctx, cancelled))
+
+		counts, err := repos.Transactions.CountOutcomes(ctx, from, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 1, counts.Completed)
+		assert.Equal(t, map[entities.FailureCategory]int{
+			entities.FailureCategoryClient: 2,
+			entities.FailureCategorySystem: 1,
+		}, counts.Failed)
+
+		// Вне окна
+		counts, err = repos.Transactions.CountOutcomes(ctx, from.Add(-time.Hour), from)
+		require.NoError(t, err)
+		assert.Zero(t, counts.Completed)
+		assert.Empty(t, counts.Failed)
+	})
+
+	t.Run("CreatedByVersion", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+
+		// Транзакции, созданные до записи версии, хранятся без неё (NULL)
+		legacy := newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "1.00")
+
+		stamped, err := entities.NewTransaction(
+			wallet.ID(), uuid.NewString(), entities.TransactionTypeDeposit, money(t, "2.00", "USD"), "",
+		)
+		require.NoError(t, err)
+		require.NoError(t, stamped.StampCreatedByVersion("1.4.0+abc1234"))
+		require.NoError(t, repos.Transactions.Save(ctx, stamped))
+
+		loaded, err := repos.Transactions.FindByID(ctx, legacy.ID())
+		require.NoError(t, err)
+		assert.Empty(t, loaded.CreatedByVersion())
+
+		loaded, err = repos.Transactions.FindByIdempotencyKey(ctx, stamped.IdempotencyKey())
+		require.NoError(t, err)
+		assert.Equal(t, "1.4.0+abc1234", loaded.CreatedByVersion())
+
+		// Версия не меняется при обновлении статуса
+		require.NoError(t, loaded.StartProcessing())
+		require.NoError(t, repos.Transactions.Save(ctx, loaded))
+		listed, err := repos.Transactions.FindByWalletID(ctx, wallet.ID(), 0, 10)
+		require.NoError(t, err)
+		for _, tx := range listed {
+			if tx.ID() == stamped.ID() {
+				assert.Equal(t, "1.4.0+abc1234", tx.CreatedByVersion())
+			}
+		}
+	})
+
+	t.Run("FindByIdempotencyKey", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+		tx := newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "25.00")
+
+		found, err := repos.Transactions.FindByIdempotencyKey(ctx, tx.IdempotencyKey())
+		require.NoError(t, err)
+		assert.Equal(t, tx.ID(), found.ID())
+	})
+
+	t.Run("FindByExternalReference", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+
+		// Ссылка с номером карты хранится замаскированной, поиск идёт по хэшу оригинала
+		const reference = "pi_4111111111111111"
+		first := newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "10.00")
+		require.NoError(t, first.SetExternalReference(reference))
+		require.NoError(t, repos.Transactions.Save(ctx, first))
+		second := newTransaction(t, repos, wallet, entities.TransactionTypeWithdraw, "5.00")
+		require.NoError(t, second.SetExternalReference(reference))
+		require.NoError(t, repos.Transactions.Save(ctx, second))
+		other := newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "1.00")
+		require.NoError(t, other.SetExternalReference("pi_other"))
+		require.NoError(t, repos.Transactions.Save(ctx, other))
+
+		found, err := repos.Transactions.FindByExternalReference(ctx, reference)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{first.ID(), second.ID()}, transactionIDs(found))
+		assert.Equal(t, "pi_411111******1111", found[0].ExternalReference())
+		assert.Equal(t, valueobjects.HashExternalReference(reference), found[0].ExternalReferenceHash())
+
+		// Замаскированное значение - не та ссылка, которую прислал провайдер
+		found, err = repos.Transactions.FindByExternalReference(ctx, "pi_411111******1111")
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+
+		found, err := repos.Transactions.FindByID(ctx, uuid.New())
+		assert.True(t, domainErrors.IsNotFound(err), "FindByID: expected ErrEntityNotFound, got %v", err)
+		assert.Nil(t, found)
+
+		// Решение контракта: не nil, nil, а типизированная ошибка
+		found, err = repos.Transactions.FindByIdempotencyKey(ctx, uuid.NewString())
+		assert.True(t, domainErrors.IsNotFound(err), "FindByIdempotencyKey: expected ErrEntityNotFound, got %v", err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("DuplicateIdempotencyKey", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+		original := newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "10.00")
+
+		duplicate, err := entities.NewTransaction(
+			wallet.ID(), original.IdempotencyKey(), entities.TransactionTypeDeposit, money(t, "99.00", "USD"), "",
+		)
+		require.NoError(t, err)
+
+		err = repos.Transactions.Save(ctx, duplicate)
+		assert.True(t, errors.Is(err, domainErrors.ErrDuplicateTransaction), "expected ErrDuplicateTransaction, got %v", err)
+
+		found, err := repos.Transactions.FindByIdempotencyKey(ctx, original.IdempotencyKey())
+		require.NoError(t, err)
+		assert.Equal(t, original.ID(), found.ID())
+	})
+
+	t.Run("UnknownWallet", func(t *testing.T) {
+		repos := factory(t)
+
+		tx, err := entities.NewTransaction(
+			uuid.New(), uuid.NewString(), entities.TransactionTypeDeposit, money(t, "1.00", "USD"), "",
+		)
+		require.NoError(t, err)
+
+		err = repos.Transactions.Save(context.Background(), tx)
+		require.Error(t, err)
+		assertDomainErrorCode(t, err, "WALLET_NOT_FOUND")
+	})
+
+	t.Run("FindByWalletIDPagination", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		user := newUser(t, repos)
+		wallet := newWallet(t, repos, user.ID(), "USD")
+		other := newWallet(t, repos, user.ID(), "EUR")
+
+		var created []uuid.UUID
+		for i := 0; i < 3; i++ {
+			created = append(created, newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "1.00").ID())
+		}
+		newTransaction(t, repos, other, entities.TransactionTypeDeposit, "1.00")
+
+		// Порядок - created_at DESC
+		expected := []uuid.UUID{created[2], created[1], created[0]}
+
+		firstPage, err := repos.Transactions.FindByWalletID(ctx, wallet.ID(), 0, 2)
+		require.NoError(t, err)
+		secondPage, err := repos.Transactions.FindByWalletID(ctx, wallet.ID(), 2, 2)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected[:2], transactionIDs(firstPage))
+		assert.Equal(t, expected[2:], transactionIDs(secondPage))
+	})
+
+	t.Run("FindPendingByWallet", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+
+		first := newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "1.00")
+		completed := newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "2.00")
+		second := newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "3.00")
+
+		require.NoError(t, completed.StartProcessing())
+		require.NoError(t, completed.MarkCompleted())
+		require.NoError(t, repos.Transactions.Save(ctx, completed))
+
+		// Порядок - created_at ASC (очередь)
+		pending, err := repos.Transactions.FindPendingByWallet(ctx, wallet.ID())
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{first.ID(), second.ID()}, transactionIDs(pending))
+	})
+
+	t.Run("CountPendingByWallet", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+		other := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+
+		newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "1.00")
+		processing := newTransaction(t, repos, wallet, entities.TransactionTypeWithdraw, "2.00")
+		require.NoError(t, processing.StartProcessing())
+		require.NoError(t, repos.Transactions.Save(ctx, processing))
+		cancelled := newTransaction(t, repos, wallet, entities.TransactionTypeWithdraw, "3.00")
+		require.NoError(t, cancelled.Cancel())
+		require.NoError(t, repos.Transactions.Save(ctx, cancelled))
+		newTransaction(t, repos, other, entities.TransactionTypeDeposit, "4.00")
+
+		// PENDING и PROCESSING; отменённые и чужие не считаются
+		count, err := repos.Transactions.CountPendingByWallet(ctx, wallet.ID())
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		require.NoError(t, processing.MarkCompleted())
+		require.NoError(t, repos.Transactions.Save(ctx, processing))
+
+		count, err = repos.Transactions.CountPendingByWallet(ctx, wallet.ID())
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		count, err = repos.Transactions.CountPendingByWallet(ctx, uuid.New())
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("FindFailedRetryable", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+
+		fresh := newTransaction(t, repos, wallet, entities.TransactionTypeWithdraw, "1.00")
+		require.NoError(t, fresh.MarkFailed("TIMEOUT", entities.FailureCategoryProvider))
+		require.NoError(t, repos.Transactions.Save(ctx, fresh))
+
+		exhausted := newTransaction(t, repos, wallet, entities.TransactionTypeWithdraw, "2.00")
+		require.NoError(t, exhausted.MarkFailed("TIMEOUT", entities.FailureCategoryProvider))
+		require.NoError(t, exhausted.Retry(1))
+		require.NoError(t, exhausted.MarkFailed("TIMEOUT", entities.FailureCategoryProvider))
+		require.NoError(t, repos.Transactions.Save(ctx, exhausted))
+
+		newTransaction(t, repos, wallet, entities.TransactionTypeWithdraw, "3.00")
+
+		retryable, err := repos.Transactions.FindFailedRetryable(ctx, 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fresh.ID()}, transactionIDs(retryable))
+
+		retryable, err = repos.Transactions.FindFailedRetryable(ctx, 5, 1)
+		require.NoError(t, err)
+		assert.Len(t, retryable, 1)
+	})
+
+	t.Run("FindFailedRetryableSkipsScheduled", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+
+		// Повтор не удался: следующий не раньше next_retry_at (через минуту)
+		scheduled := newTransaction(t, repos, wallet, entities.TransactionTypeWithdraw, "1.00")
+		require.NoError(t, scheduled.MarkFailed("TIMEOUT", entities.FailureCategoryProvider))
+		require.NoError(t, scheduled.Retry(5))
+		require.NoError(t, scheduled.MarkFailed("TIMEOUT", entities.FailureCategoryProvider))
+		require.NoError(t, repos.Transactions.Save(ctx, scheduled))
+
+		due := saveFailedTransaction(t, repos, wallet, time.Now().Add(-time.Second))
+
+		retryable, err := repos.Transactions.FindFailedRetryable(ctx, 5, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{due.ID()}, transactionIDs(retryable))
+
+		found, err := repos.Transactions.FindByID(ctx, scheduled.ID())
+		require.NoError(t, err)
+		require.NotNil(t, found.NextRetryAt())
+		assertSameUTC(t, *scheduled.NextRetryAt(), *found.NextRetryAt())
+	})
+
+	t.Run("ListFilters", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		alice := newUser(t, repos)
+		bob := newUser(t, repos)
+		aliceWallet := newWallet(t, repos, alice.ID(), "USD")
+		bobWallet := newWallet(t, repos, bob.ID(), "USD")
+
+		deposit := newTransaction(t, repos, aliceWallet, entities.TransactionTypeDeposit, "10.00")
+
+		transfer, err := entities.NewTransaction(
+			aliceWallet.ID(), uuid.NewString(), entities.TransactionTypeTransfer, money(t, "5.00", "USD"), "",
+		)
+		require.NoError(t, err)
+		require.NoError(t, transfer.SetDestinationWallet(bobWallet.ID()))
+		require.NoError(t, transfer.StartProcessing())
+		require.NoError(t, transfer.MarkCompleted())
+		require.NoError(t, repos.Transactions.Save(ctx, transfer))
+
+		bobDeposit := newTransaction(t, repos, bobWallet, entities.TransactionTypeDeposit, "1.00")
+
+		// Фильтр по кошельку включает входящие переводы
+		bobWalletID := bobWallet.ID()
+		list, err := repos.Transactions.List(ctx, ports.TransactionFilter{WalletID: &bobWalletID}, 0, 10)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{transfer.ID(), bobDeposit.ID()}, transactionIDs(list))
+
+		aliceID := alice.ID()
+		list, err = repos.Transactions.List(ctx, ports.TransactionFilter{UserID: &aliceID}, 0, 10)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{deposit.ID(), transfer.ID()}, transactionIDs(list))
+
+		txType := entities.TransactionTypeDeposit
+		list, err = repos.Transactions.List(ctx, ports.TransactionFilter{Type: &txType}, 0, 10)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{deposit.ID(), bobDeposit.ID()}, transactionIDs(list))
+
+		status := entities.TransactionStatusCompleted
+		list, err = repos.Transactions.List(ctx, ports.TransactionFilter{Status: &status}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{transfer.ID()}, transactionIDs(list))
+
+		// Порядок - created_at DESC, пагинация через offset/limit
+		list, err = repos.Transactions.List(ctx, ports.TransactionFilter{}, 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{transfer.ID()}, transactionIDs(list))
+	})
+
+	t.Run("TimestampsStableAcrossTimeZoneChange", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+
+		// Запись в одной зоне процесса...
+		setLocalTimeZone(t, "America/New_York", -5)
+		tx := newTransaction(t, repos, wallet, entities.TransactionTypeDeposit, "12.00")
+		require.NoError(t, tx.StartProcessing())
+		require.NoError(t, tx.MarkCompleted())
+		require.NoError(t, repos.Transactions.Save(ctx, tx))
+
+		// ...чтение в другой: момент времени и представление не меняются
+		setLocalTimeZone(t, "Asia/Tokyo", 9)
+		loaded, err := repos.Transactions.FindByID(ctx, tx.ID())
+		require.NoError(t, err)
+
+		assertSameUTC(t, tx.CreatedAt(), loaded.CreatedAt())
+		assertSameUTC(t, tx.UpdatedAt(), loaded.UpdatedAt())
+		require.NotNil(t, loaded.CompletedAt())
+		assertSameUTC(t, *tx.CompletedAt(), *loaded.CompletedAt())
+
+		// Граница фильтра в локальной зоне сравнивается как момент времени
+		from := tx.CreatedAt().Add(-time.Second).In(time.Local)
+		list, err := repos.Transactions.List(ctx, ports.TransactionFilter{CreatedFrom: &from}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{tx.ID()}, transactionIDs(list))
+	})
+
+	t.Run("ListByCreatedRange", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+
+		base := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+		before := saveTransactionAt(t, repos, wallet, base.Add(-time.Hour))
+		atFrom := saveTransactionAt(t, repos, wallet, base)
+		inside := saveTransactionAt(t, repos, wallet, base.Add(12*time.Hour))
+		atTo := saveTransactionAt(t, repos, wallet, base.Add(24*time.Hour))
+
+		// [from, to): нижняя граница включена, верхняя - нет
+		from, to := base, base.Add(24*time.Hour)
+		list, err := repos.Transactions.List(ctx, ports.TransactionFilter{CreatedFrom: &from, CreatedTo: &to}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{inside.ID(), atFrom.ID()}, transactionIDs(list))
+
+		list, err = repos.Transactions.List(ctx, ports.TransactionFilter{CreatedTo: &from}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{before.ID()}, transactionIDs(list))
+
+		list, err = repos.Transactions.List(ctx, ports.TransactionFilter{CreatedFrom: &to}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{atTo.ID()}, transactionIDs(list))
+	})
+
+	t.Run("ListBeforeWalksListOrder", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+
+		// Одинаковый created_at: порядок и курсор различают строки по id
+		base := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+		for i := 0; i < 5; i++ {
+			saveTransactionAt(t, repos, wallet, base.Add(time.Duration(i/2)*time.Hour))
+		}
+		walletID := wallet.ID()
+		filter := ports.TransactionFilter{WalletID: &walletID}
+
+		all, err := repos.Transactions.List(ctx, filter, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, all, 5)
+
+		var walked []*entities.Transaction
+		var cursor *ports.TransactionCursor
+		for {
+			page, err := repos.Transactions.ListBefore(ctx, filter, cursor, 2)
+			require.NoError(t, err)
+			walked = append(walked, page...)
+			if len(page) < 2 {
+				break
+			}
+			cursor = ports.CursorOf(page[len(page)-1])
+		}
+		assert.Equal(t, transactionIDs(all), transactionIDs(walked))
+	})
+
+	t.Run("ListAfterWalksChronologically", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+
+		base := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+		for i := 0; i < 5; i++ {
+			saveTransactionAt(t, repos, wallet, base.Add(time.Duration(i/2)*time.Hour))
+		}
+		walletID := wallet.ID()
+		filter := ports.TransactionFilter{WalletID: &walletID}
+
+		all, err := repos.Transactions.List(ctx, filter, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, all, 5)
+
+		var walked []*entities.Transaction
+		var cursor *ports.TransactionCursor
+		for {
+			page, err := repos.Transactions.ListAfter(ctx, filter, cursor, 2)
+			require.NoError(t, err)
+			walked = append(walked, page...)
+			if len(page) < 2 {
+				break
+			}
+			cursor = ports.CursorOf(page[len(page)-1])
+		}
+
+		// Обратный порядок List, в том числе внутри одного created_at
+		want := transactionIDs(all)
+		slices.Reverse(want)
+		assert.Equal(t, want, transactionIDs(walked))
+	})
+}
+
+// ============================================
+// Helpers
+// ============================================
+
+// newTransaction сохраняет новую PENDING транзакцию кошелька.
+func newTransaction(t *testing.T, repos Repositories, wallet *entities.Wallet, txType entities.TransactionType, amount string) *entities.Transaction {
+	t.Helper()
+
+	tx, err := entities.NewTransaction(
+		wallet.ID(), uuid.NewString(), txType, money(t, amount, wallet.Currency().Code()), "conformance",
+	)
+	require.NoError(t, err)
+	require.NoError(t, repos.Transactions.Save(context.Background(), tx))
+
+	return tx
+}
+
+// transactionIDs возвращает ID транзакций в исходном порядке.
+func transactionIDs(transactions []*entities.Transaction) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(transactions))
+	for _, tx := range transactions {
+		ids = append(ids, tx.ID())
+	}
+	return ids
+}
+
+// saveTransactionAt сохраняет COMPLETED депозит с заданным created_at.
+func saveTransactionAt(t *testing.T, repos Repositories, wallet *entities.Wallet, createdAt time.Time) *entities.Transaction {
+	t.Helper()
+
+	amount := money(t, "1.00", wallet.Currency().Code())
+	tx, err := entities.ReconstructTransaction(
+		uuid.New(), wallet.ID(), uuid.NewString(),
+		entities.TransactionTypeDeposit, entities.TransactionStatusCompleted,
+		amount, valueobjects.Zero(amount.Currency()), amount,
+		nil, "", "", "conformance", nil, "", "", 0, nil, "", "",
+		createdAt, createdAt, &createdAt, &createdAt,
+		"",
+	)
+	require.NoError(t, err)
+	require.NoError(t, repos.Transactions.Save(context.Background(), tx))
+
+	return tx
+}
+
+// saveFailedTransaction сохраняет FAILED вывод с одним повтором и заданным next_retry_at.
+func saveFailedTransaction(t *testing.T, repos Repositories, wallet *entities.Wallet, nextRetryAt time.Time) *entities.Transaction {
+	t.Helper()
+
+	amount := money(t, "1.00", wallet.Currency().Code())
+	createdAt := time.Now().Add(-time.Hour)
+	tx, err := entities.ReconstructTransaction(
+		uuid.New(), wallet.ID(), uuid.NewString(),
+		entities.TransactionTypeWithdraw, entities.TransactionStatusFailed,
+		amount, valueobjects.Zero(amount.Currency()), amount,
+		nil, "", "", "conformance", nil, "TIMEOUT", entities.FailureCategoryProvider, 1, &nextRetryAt, "", "",
+		createdAt, createdAt, &createdAt, &createdAt,
+		"",
+	)
+	require.NoError(t, err)
+	require.NoError(t, repos.Transactions.Save(context.Background(), tx))
+
+	return tx
+}
+
+// assertSameUTC проверяет, что прочитанное время - тот же момент в UTC.
+// PostgreSQL хранит микросекунды, поэтому наносекунды отбрасываются.
+func assertSameUTC(t *testing.T, expected, actual time.Time) {
+	t.Helper()
+
+	assert.Equal(t, time.UTC, actual.Location())
+	assert.Equal(t,
+		expected.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+		actual.Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	)
+}
@@ -0,0 +1,119 @@
+package porttest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// RunUserRepositoryTests проверяет реализацию ports.UserRepository на соответствие контракту.
+//
+// Репозитории фабрики должны использовать valueobjects.DefaultEmailPolicy.
+func RunUserRepositoryTests(t *testing.T, factory Factory) {
+	t.Run("FindByEmailUsesNormalizedForm", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		id := uuid.NewString()[:8]
+
+		user := saveUserWithEmail(t, repos, "Conf."+id+"+News@Gmail.com")
+		assert.Equal(t, "conf."+id+"+news@gmail.com", user.Email(), "address is stored as entered, lowercased")
+
+		// Для gmail плюс-тег и точки в local part не значимы
+		loaded, err := repos.Users.FindByEmail(ctx, "c.o.n.f"+id+"@gmail.com")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID(), loaded.ID())
+		assert.Equal(t, user.Email(), loaded.Email())
+
+		exists, err := repos.Users.ExistsByEmail(ctx, "CONF"+id+"+other@gmail.com")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("OtherProvidersKeepTagsAndDots", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		local := "conf" + uuid.NewString()[:8]
+
+		saveUserWithEmail(t, repos, local+"+tag@example.com")
+
+		exists, err := repos.Users.ExistsByEmail(ctx, local+"@example.com")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		exists, err = repos.Users.ExistsByEmail(ctx, local+"+TAG@Example.com")
+		require.NoError(t, err)
+		assert.True(t, exists, "comparison is case-insensitive")
+
+		saveUserWithEmail(t, repos, local+"@example.com")
+	})
+
+	t.Run("DuplicateNormalizedEmailRejected", func(t *testing.T) {
+		repos := factory(t)
+		local := "conf" + uuid.NewString()[:8]
+
+		saveUserWithEmail(t, repos, local+"@gmail.com")
+
+		duplicate, err := entities.NewUser(local[:2]+"."+local[2:]+"+alt@gmail.com", "Duplicate User")
+		require.NoError(t, err)
+		err = repos.Users.Save(context.Background(), duplicate)
+
+		var violation *domainErrors.BusinessRuleViolation
+		require.True(t, errors.As(err, &violation), "expected BusinessRuleViolation, got %v", err)
+		assert.Equal(t, "EMAIL_ALREADY_EXISTS", violation.Rule)
+	})
+
+	t.Run("ActivitySummaryCountsAllWallets", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+
+		owner := newUser(t, repos)
+		usd := newWallet(t, repos, owner.ID(), "USD")
+		eur := newWallet(t, repos, owner.ID(), "EUR")
+		newWallet(t, repos, owner.ID(), "GBP") // без транзакций
+
+		latest := time.Now().UTC().Truncate(time.Microsecond)
+		saveTransactionAt(t, repos, usd, latest.Add(-48*time.Hour))
+		saveTransactionAt(t, repos, usd, latest.Add(-time.Hour))
+		saveTransactionAt(t, repos, eur, latest)
+
+		// Транзакции чужих кошельков не учитываются
+		other := newUser(t, repos)
+		saveTransactionAt(t, repos, newWallet(t, repos, other.ID(), "USD"), latest.Add(time.Hour))
+
+		activity, err := repos.Users.UserActivitySummary(ctx, owner.ID())
+		require.NoError(t, err)
+		assert.Equal(t, 3, activity.WalletCount)
+		assert.Equal(t, int64(3), activity.TotalTransactions)
+		require.NotNil(t, activity.LastActivityAt)
+		assert.True(t, activity.LastActivityAt.Equal(latest), "last activity %v, want %v", activity.LastActivityAt, latest)
+		assert.Equal(t, time.UTC, activity.LastActivityAt.Location())
+	})
+
+	t.Run("ActivitySummaryWithoutWalletsIsEmpty", func(t *testing.T) {
+		repos := factory(t)
+
+		activity, err := repos.Users.UserActivitySummary(context.Background(), newUser(t, repos).ID())
+		require.NoError(t, err)
+		assert.Equal(t, ports.UserActivity{}, activity)
+	})
+}
+
+// saveUserWithEmail сохраняет нового пользователя с указанным email.
+func saveUserWithEmail(t *testing.T, repos Repositories, email string) *entities.User {
+	t.Helper()
+
+	user, err := entities.NewUser(email, "Conformance User")
+	require.NoError(t, err)
+	require.NoError(t, repos.Users.Save(context.Background(), user))
+
+	return user
+}
@@ -0,0 +1,126 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// WalletNoteHarness - WalletNoteRepository и репозитории над тем же хранилищем
+// (wallet_notes ссылается на wallets).
+type WalletNoteHarness struct {
+	Repositories
+	Notes ports.WalletNoteRepository
+}
+
+// WalletNoteRepositoryFactory создаёт harness над ПУСТЫМ хранилищем.
+type WalletNoteRepositoryFactory func(t *testing.T) WalletNoteHarness
+
+// RunWalletNoteRepositoryTests проверяет реализацию ports.WalletNoteRepository.
+func RunWalletNoteRepositoryTests(t *testing.T, factory WalletNoteRepositoryFactory) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	note := func(walletID uuid.UUID, pinned bool, minutes int) *entities.WalletNote {
+		return entities.ReconstructWalletNote(uuid.New(), walletID, uuid.New(), "porttest note", pinned,
+			base.Add(time.Duration(minutes)*time.Minute), nil, nil)
+	}
+
+	t.Run("ListPinnedFirstNewestFirst", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		other := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		oldest := note(wallet.ID(), false, 0)
+		pinnedOld := note(wallet.ID(), true, 1)
+		middle := note(wallet.ID(), false, 2)
+		pinnedNew := note(wallet.ID(), true, 3)
+		newest := note(wallet.ID(), false, 4)
+		foreign := note(other.ID(), true, 5)
+		for _, n := range []*entities.WalletNote{oldest, pinnedOld, middle, pinnedNew, newest, foreign} {
+			require.NoError(t, h.Notes.Save(ctx, n))
+		}
+
+		all, err := h.Notes.ListByWallet(ctx, wallet.ID(), 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, walletNoteIDs(pinnedNew, pinnedOld, newest, middle, oldest), walletNoteIDs(all...))
+
+		page, err := h.Notes.ListByWallet(ctx, wallet.ID(), 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, walletNoteIDs(pinnedOld, newest), walletNoteIDs(page...))
+	})
+
+	t.Run("SaveUpdatesPinnedAndRoundTrips", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		n, err := entities.NewWalletNote(wallet.ID(), uuid.New(), "  customer called about failed payout  ")
+		require.NoError(t, err)
+		require.NoError(t, h.Notes.Save(ctx, n))
+
+		require.NoError(t, n.SetPinned(true))
+		require.NoError(t, h.Notes.Save(ctx, n))
+
+		got, err := h.Notes.FindByID(ctx, n.ID())
+		require.NoError(t, err)
+		assert.Equal(t, n.WalletID(), got.WalletID())
+		assert.Equal(t, n.AuthorID(), got.AuthorID())
+		assert.Equal(t, "customer called about failed payout", got.Body())
+		assert.True(t, got.Pinned())
+		assert.Equal(t, time.UTC, got.CreatedAt().Location())
+		assert.WithinDuration(t, n.CreatedAt(), got.CreatedAt(), time.Millisecond)
+	})
+
+	t.Run("SoftDeletedNoteIsHidden", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		kept := note(wallet.ID(), false, 0)
+		deleted := note(wallet.ID(), true, 1)
+		require.NoError(t, h.Notes.Save(ctx, kept))
+		require.NoError(t, h.Notes.Save(ctx, deleted))
+
+		deleted.Delete(uuid.New())
+		require.NoError(t, h.Notes.Save(ctx, deleted))
+
+		_, err := h.Notes.FindByID(ctx, deleted.ID())
+		assert.True(t, domainErrors.IsNotFound(err), "FindByID: expected ErrEntityNotFound, got %v", err)
+
+		list, err := h.Notes.ListByWallet(ctx, wallet.ID(), 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, walletNoteIDs(kept), walletNoteIDs(list...))
+	})
+
+	t.Run("NotFoundAndUnknownWallet", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+
+		_, err := h.Notes.FindByID(ctx, uuid.New())
+		assert.True(t, domainErrors.IsNotFound(err), "FindByID: expected ErrEntityNotFound, got %v", err)
+
+		err = h.Notes.Save(ctx, note(uuid.New(), false, 0))
+		assertDomainErrorCode(t, err, "WALLET_NOT_FOUND")
+
+		list, err := h.Notes.ListByWallet(ctx, uuid.New(), 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, list)
+	})
+}
+
+func walletNoteIDs(list ...*entities.WalletNote) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(list))
+	for _, n := range list {
+		ids = append(ids, n.ID())
+	}
+	return ids
+}
@@ -0,0 +1,421 @@
+package porttest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// RunWalletRepositoryTests проверяет реализацию ports.WalletRepository на соответствие контракту.
+func RunWalletRepositoryTests(t *testing.T, factory Factory) {
+	t.Run("SaveAndFindByID", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		user := newUser(t, repos)
+
+		wallet := newWallet(t, repos, user.ID(), "USD")
+
+		loaded, err := repos.Wallets.FindByID(ctx, wallet.ID())
+		require.NoError(t, err)
+		assert.Equal(t, wallet.ID(), loaded.ID())
+		assert.Equal(t, user.ID(), loaded.UserID())
+		assert.Equal(t, "USD", loaded.Currency().Code())
+		assert.Equal(t, wallet.WalletType(), loaded.WalletType())
+		assert.Equal(t, wallet.Status(), loaded.Status())
+		assert.True(t, loaded.AvailableBalance().IsZero())
+		assert.True(t, loaded.PendingBalance().IsZero())
+		assert.Equal(t, int64(0), loaded.BalanceVersion())
+		assert.True(t, wallet.DailyLimit().Equals(loaded.DailyLimit()))
+		assert.True(t, wallet.MonthlyLimit().Equals(loaded.MonthlyLimit()))
+	})
+
+	t.Run("UpdatePersistsBalance", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		user := newUser(t, repos)
+		wallet := newWallet(t, repos, user.ID(), "EUR")
+
+		require.NoError(t, wallet.Credit(money(t, "100.50", "EUR")))
+		require.NoError(t, repos.Wallets.Save(ctx, wallet))
+
+		loaded, err := repos.Wallets.FindByID(ctx, wallet.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "100.50 EUR", loaded.AvailableBalance().String())
+		assert.Equal(t, int64(1), loaded.BalanceVersion())
+	})
+
+	t.Run("FindBalanceByIDMatchesFindByID", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		user := newUser(t, repos)
+		wallet := newWallet(t, repos, user.ID(), "USD")
+
+		require.NoError(t, wallet.Credit(money(t, "120.50", "USD")))
+		require.NoError(t, repos.Wallets.Save(ctx, wallet))
+		require.NoError(t, wallet.Reserve(money(t, "20", "USD")))
+		require.NoError(t, repos.Wallets.Save(ctx, wallet))
+
+		loaded, err := repos.Wallets.FindByID(ctx, wallet.ID())
+		require.NoError(t, err)
+		balance, err := repos.Wallets.FindBalanceByID(ctx, wallet.ID())
+		require.NoError(t, err)
+
+		assert.Equal(t, ports.WalletBalance{
+			WalletID:       wallet.ID(),
+			UserID:         user.ID(),
+			CurrencyCode:   "USD",
+			AvailableCents: loaded.AvailableBalance().Cents(),
+			PendingCents:   loaded.PendingBalance().Cents(),
+			BalanceVersion: loaded.BalanceVersion(),
+		}, *balance)
+		assert.Equal(t, int64(10050), balance.AvailableCents)
+		assert.Equal(t, int64(2000), balance.PendingCents)
+	})
+
+	t.Run("UpdatePersistsPendingCapOverride", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, repos, newUser(t, repos).ID(), "USD")
+
+		override := 500
+		require.NoError(t, wallet.SetMaxPendingTransactions(&override))
+		require.NoError(t, repos.Wallets.Save(ctx, wallet))
+
+		loaded, err := repos.Wallets.FindByID(ctx, wallet.ID())
+		require.NoError(t, err)
+		require.NotNil(t, loaded.MaxPendingTransactions())
+		assert.Equal(t, 500, *loaded.MaxPendingTransactions())
+
+		// nil снимает override
+		require.NoError(t, loaded.SetMaxPendingTransactions(nil))
+		require.NoError(t, repos.Wallets.Save(ctx, loaded))
+
+		loaded, err = repos.Wallets.FindByID(ctx, wallet.ID())
+		require.NoError(t, err)
+		assert.Nil(t, loaded.MaxPendingTransactions())
+	})
+
+	t.Run("ReturnedEntityIsDetached", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		user := newUser(t, repos)
+		wallet := newWallet(t, repos, user.ID(), "USD")
+
+		// Изменения entity без Save не должны попадать в хранилище
+		require.NoError(t, wallet.Credit(money(t, "10.00", "USD")))
+
+		loaded, err := repos.Wallets.FindByID(ctx, wallet.ID())
+		require.NoError(t, err)
+		assert.True(t, loaded.AvailableBalance().IsZero())
+	})
+
+	t.Run("OptimisticLockingConflict", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		user := newUser(t, repos)
+		wallet := newWallet(t, repos, user.ID(), "BTC")
+
+		first, err := repos.Wallets.FindByID(ctx, wallet.ID())
+		require.NoError(t, err)
+		second, err := repos.Wallets.FindByID(ctx, wallet.ID())
+		require.NoError(t, err)
+
+		require.NoError(t, first.Credit(money(t, "1.0", "BTC")))
+		require.NoError(t, repos.Wallets.Save(ctx, first))
+
+		require.NoError(t, second.Credit(money(t, "2.0", "BTC")))
+		err = repos.Wallets.Save(ctx, second)
+		require.Error(t, err)
+		assert.True(t, domainErrors.IsConcurrencyError(err), "expected ConcurrencyError, got %v", err)
+
+		loaded, err := repos.Wallets.FindByID(ctx, wallet.ID())
+		require.NoError(t, err)
+		assert.True(t, loaded.AvailableBalance().Equals(money(t, "1.0", "BTC")))
+	})
+
+	t.Run("UpdateOfMissingWalletIsConflict", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		user := newUser(t, repos)
+
+		wallet, err := entities.NewWallet(user.ID(), currency(t, "USD"))
+		require.NoError(t, err)
+		require.NoError(t, wallet.Credit(money(t, "5.00", "USD")))
+
+		err = repos.Wallets.Save(ctx, wallet)
+		require.Error(t, err)
+		assert.True(t, domainErrors.IsConcurrencyError(err), "expected ConcurrencyError, got %v", err)
+	})
+
+	t.Run("UniqueUserCurrency", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		user := newUser(t, repos)
+		newWallet(t, repos, user.ID(), "USD")
+
+		duplicate, err := entities.NewWallet(user.ID(), currency(t, "USD"))
+		require.NoError(t, err)
+
+		err = repos.Wallets.Save(ctx, duplicate)
+		require.Error(t, err)
+		assertRuleViolation(t, err, "WALLET_ALREADY_EXISTS")
+
+		// Другая валюта того же пользователя - можно
+		newWallet(t, repos, user.ID(), "EUR")
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		repos := factory(t)
+
+		wallet, err := entities.NewWallet(uuid.New(), currency(t, "USD"))
+		require.NoError(t, err)
+
+		err = repos.Wallets.Save(context.Background(), wallet)
+		require.Error(t, err)
+		assertDomainErrorCode(t, err, "USER_NOT_FOUND")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		user := newUser(t, repos)
+
+		_, err := repos.Wallets.FindByID(ctx, uuid.New())
+		assert.True(t, domainErrors.IsNotFound(err), "FindByID: expected ErrEntityNotFound, got %v", err)
+
+		_, err = repos.Wallets.FindBalanceByID(ctx, uuid.New())
+		assert.True(t, domainErrors.IsNotFound(err), "FindBalanceByID: expected ErrEntityNotFound, got %v", err)
+
+		_, err = repos.Wallets.FindByUserAndCurrency(ctx, user.ID(), currency(t, "EUR"))
+		assert.True(t, domainErrors.IsNotFound(err), "FindByUserAndCurrency: expected ErrEntityNotFound, got %v", err)
+
+		wallets, err := repos.Wallets.FindByUserID(ctx, user.ID())
+		require.NoError(t, err)
+		assert.Empty(t, wallets)
+	})
+
+	t.Run("FindByUserAndCurrency", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		user := newUser(t, repos)
+		usd := newWallet(t, repos, user.ID(), "USD")
+		newWallet(t, repos, user.ID(), "EUR")
+
+		found, err := repos.Wallets.FindByUserAndCurrency(ctx, user.ID(), currency(t, "USD"))
+		require.NoError(t, err)
+		assert.Equal(t, usd.ID(), found.ID())
+
+		exists, err := repos.Wallets.ExistsByUserAndCurrency(ctx, user.ID(), currency(t, "USD"))
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		exists, err = repos.Wallets.ExistsByUserAndCurrency(ctx, user.ID(), currency(t, "BTC"))
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("FindByUserIDOrderedByCreation", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		user := newUser(t, repos)
+		other := newUser(t, repos)
+
+		var expected []uuid.UUID
+		for _, code := range []string{"USD", "EUR", "BTC"} {
+			expected = append(expected, newWallet(t, repos, user.ID(), code).ID())
+		}
+		newWallet(t, repos, other.ID(), "USD")
+
+		wallets, err := repos.Wallets.FindByUserID(ctx, user.ID())
+		require.NoError(t, err)
+		assert.Equal(t, expected, walletIDs(wallets))
+	})
+
+	t.Run("ListFilters", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		alice := newUser(t, repos)
+		bob := newUser(t, repos)
+
+		aliceUSD := newWallet(t, repos, alice.ID(), "USD")
+		aliceEUR := newWallet(t, repos, alice.ID(), "EUR")
+		bobUSD := newWallet(t, repos, bob.ID(), "USD")
+
+		// Статус меняем после Credit: смена статуса сама по себе не двигает версию
+		require.NoError(t, aliceEUR.Credit(money(t, "1.00", "EUR")))
+		require.NoError(t, aliceEUR.Suspend())
+		require.NoError(t, repos.Wallets.Save(ctx, aliceEUR))
+
+		userID := alice.ID()
+		wallets, err := repos.Wallets.List(ctx, ports.WalletFilter{UserID: &userID}, 0, 10)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{aliceUSD.ID(), aliceEUR.ID()}, walletIDs(wallets))
+
+		usd := currency(t, "USD")
+		wallets, err = repos.Wallets.List(ctx, ports.WalletFilter{Currency: &usd}, 0, 10)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{aliceUSD.ID(), bobUSD.ID()}, walletIDs(wallets))
+
+		suspended := entities.WalletStatusSuspended
+		wallets, err = repos.Wallets.List(ctx, ports.WalletFilter{Status: &suspended}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{aliceEUR.ID()}, walletIDs(wallets))
+
+		wallets, err = repos.Wallets.List(ctx, ports.WalletFilter{UserID: &userID, Currency: &usd}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{aliceUSD.ID()}, walletIDs(wallets))
+	})
+
+	t.Run("ListPagination", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		user := newUser(t, repos)
+
+		var created []uuid.UUID
+		for _, code := range []string{"USD", "EUR", "BTC"} {
+			created = append(created, newWallet(t, repos, user.ID(), code).ID())
+		}
+
+		// Порядок - created_at DESC
+		expected := []uuid.UUID{created[2], created[1], created[0]}
+
+		firstPage, err := repos.Wallets.List(ctx, ports.WalletFilter{}, 0, 2)
+		require.NoError(t, err)
+		secondPage, err := repos.Wallets.List(ctx, ports.WalletFilter{}, 2, 2)
+		require.NoError(t, err)
+		emptyPage, err := repos.Wallets.List(ctx, ports.WalletFilter{}, 3, 2)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected[:2], walletIDs(firstPage))
+		assert.Equal(t, expected[2:], walletIDs(secondPage))
+		assert.Empty(t, emptyPage)
+	})
+
+	t.Run("ConcurrentUpdates", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		user := newUser(t, repos)
+		wallet := newWallet(t, repos, user.ID(), "USD")
+
+		const workers = 8
+		copies := make([]*entities.Wallet, workers)
+		for i := range copies {
+			loaded, err := repos.Wallets.FindByID(ctx, wallet.ID())
+			require.NoError(t, err)
+			require.NoError(t, loaded.Credit(money(t, "10.00", "USD")))
+			copies[i] = loaded
+		}
+
+		errs := runConcurrently(workers, func(i int) error {
+			return repos.Wallets.Save(ctx, copies[i])
+		})
+
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.True(t, domainErrors.IsConcurrencyError(err), "expected ConcurrencyError, got %v", err)
+		}
+		assert.Equal(t, 1, succeeded, "exactly one writer of the same version must win")
+
+		loaded, err := repos.Wallets.FindByID(ctx, wallet.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "10.00 USD", loaded.AvailableBalance().String())
+		assert.Equal(t, int64(1), loaded.BalanceVersion())
+	})
+
+	t.Run("ConcurrentInserts", func(t *testing.T) {
+		repos := factory(t)
+		ctx := context.Background()
+		user := newUser(t, repos)
+
+		eur := currency(t, "EUR")
+
+		const workers = 8
+		errs := runConcurrently(workers, func(int) error {
+			wallet, err := entities.NewWallet(user.ID(), eur)
+			if err != nil {
+				return err
+			}
+			return repos.Wallets.Save(ctx, wallet)
+		})
+
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assertRuleViolation(t, err, "WALLET_ALREADY_EXISTS")
+		}
+		assert.Equal(t, 1, succeeded, "exactly one wallet per user and currency")
+
+		wallets, err := repos.Wallets.FindByUserID(ctx, user.ID())
+		require.NoError(t, err)
+		assert.Len(t, wallets, 1)
+	})
+}
+
+// ============================================
+// Helpers
+// ============================================
+
+// walletIDs возвращает ID кошельков в исходном порядке.
+func walletIDs(wallets []*entities.Wallet) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(wallets))
+	for _, w := range wallets {
+		ids = append(ids, w.ID())
+	}
+	return ids
+}
+
+// runConcurrently запускает fn в n горутинах одновременно и собирает ошибки.
+func runConcurrently(n int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = fn(i)
+		}(i)
+	}
+
+	close(start)
+	wg.Wait()
+
+	return errs
+}
+
+// assertRuleViolation проверяет, что err - BusinessRuleViolation с указанным правилом.
+func assertRuleViolation(t *testing.T, err error, rule string) {
+	t.Helper()
+
+	var brv *domainErrors.BusinessRuleViolation
+	if assert.ErrorAs(t, err, &brv) {
+		assert.Equal(t, rule, brv.Rule)
+	}
+}
+
+// assertDomainErrorCode проверяет, что err - DomainError с указанным кодом.
+func assertDomainErrorCode(t *testing.T, err error, code string) {
+	t.Helper()
+
+	var de *domainErrors.DomainError
+	if assert.ErrorAs(t, err, &de) {
+		assert.Equal(t, code, de.Code)
+	}
+}
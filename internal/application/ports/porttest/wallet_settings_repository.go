@@ -0,0 +1,118 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// WalletSettingsHarness - WalletSettingsRepository и репозитории над тем же
+// хранилищем (wallet_settings ссылается на wallets, представления - ещё и на
+// transactions).
+type WalletSettingsHarness struct {
+	Repositories
+	Settings ports.WalletSettingsRepository
+}
+
+// WalletSettingsRepositoryFactory создаёт harness над ПУСТЫМ хранилищем.
+type WalletSettingsRepositoryFactory func(t *testing.T) WalletSettingsHarness
+
+// RunWalletSettingsRepositoryTests проверяет реализацию ports.WalletSettingsRepository.
+func RunWalletSettingsRepositoryTests(t *testing.T, factory WalletSettingsRepositoryFactory) {
+	t.Run("SaveReplacesAndRoundTrips", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		wallet := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		_, err := h.Settings.FindByWalletID(ctx, wallet.ID())
+		assert.True(t, domainErrors.IsNotFound(err), "FindByWalletID: expected ErrEntityNotFound, got %v", err)
+
+		first, err := entities.NewWalletSettings(wallet.ID(), "order {external_reference}", []string{"shop", "eu"}, "", wallet.UserID())
+		require.NoError(t, err)
+		require.NoError(t, h.Settings.Save(ctx, first))
+
+		second, err := entities.NewWalletSettings(wallet.ID(), "", []string{"shop:42"}, "USD", wallet.UserID())
+		require.NoError(t, err)
+		require.NoError(t, h.Settings.Save(ctx, second))
+
+		got, err := h.Settings.FindByWalletID(ctx, wallet.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "", got.IncomingDescriptionTemplate())
+		assert.Equal(t, []string{"shop:42"}, got.AutoTags())
+		assert.Equal(t, "USD", got.DisplayCurrency())
+		assert.Equal(t, wallet.UserID(), got.UpdatedBy())
+		assert.Equal(t, time.UTC, got.UpdatedAt().Location())
+	})
+
+	t.Run("FindByWalletIDs", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		user := newUser(t, h.Repositories)
+		btc := newWallet(t, h.Repositories, user.ID(), "BTC")
+		eth := newWallet(t, h.Repositories, user.ID(), "ETH")
+		plain := newWallet(t, h.Repositories, user.ID(), "USD")
+
+		for walletID, display := range map[uuid.UUID]string{btc.ID(): "USD", eth.ID(): "EUR"} {
+			settings, err := entities.NewWalletSettings(walletID, "", nil, display, user.ID())
+			require.NoError(t, err)
+			require.NoError(t, h.Settings.Save(ctx, settings))
+		}
+
+		// Кошельки без настроек и неизвестные ID в результат не попадают
+		found, err := h.Settings.FindByWalletIDs(ctx, []uuid.UUID{btc.ID(), eth.ID(), plain.ID(), uuid.New()})
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		assert.Equal(t, "USD", found[btc.ID()].DisplayCurrency())
+		assert.Equal(t, "EUR", found[eth.ID()].DisplayCurrency())
+
+		found, err = h.Settings.FindByWalletIDs(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+
+	t.Run("IncomingViewRoundTrip", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		source := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+		destination := newWallet(t, h.Repositories, newUser(t, h.Repositories).ID(), "USD")
+
+		tx, err := entities.NewTransaction(source.ID(), uuid.NewString(), entities.TransactionTypeTransfer,
+			money(t, "10", "USD"), "sender text")
+		require.NoError(t, err)
+		require.NoError(t, tx.SetDestinationWallet(destination.ID()))
+		require.NoError(t, tx.SetExternalReference("INV-7"))
+		require.NoError(t, h.Transactions.Save(ctx, tx))
+
+		_, err = h.Settings.FindIncomingView(ctx, tx.ID())
+		assert.True(t, domainErrors.IsNotFound(err), "FindIncomingView: expected ErrEntityNotFound, got %v", err)
+
+		settings, err := entities.NewWalletSettings(destination.ID(), "order {external_reference}", []string{"shop"}, "", destination.UserID())
+		require.NoError(t, err)
+		view, err := entities.NewIncomingTransferView(tx, settings)
+		require.NoError(t, err)
+		require.NoError(t, h.Settings.SaveIncomingView(ctx, view))
+
+		got, err := h.Settings.FindIncomingView(ctx, tx.ID())
+		require.NoError(t, err)
+		assert.Equal(t, destination.ID(), got.WalletID())
+		assert.Equal(t, "order INV-7", got.Description())
+		assert.Equal(t, []string{"shop"}, got.Tags())
+		assert.Equal(t, time.UTC, got.CreatedAt().Location())
+	})
+
+	t.Run("UnknownWallet", func(t *testing.T) {
+		h := factory(t)
+
+		settings, err := entities.NewWalletSettings(uuid.New(), "", []string{"shop"}, "", uuid.New())
+		require.NoError(t, err)
+		assertDomainErrorCode(t, h.Settings.Save(context.Background(), settings), "WALLET_NOT_FOUND")
+	})
+}
@@ -0,0 +1,349 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// WebhookHarness - репозитории подписок и доставок и репозитории над тем же
+// хранилищем (подписки ссылаются на users, доставки - на подписки).
+type WebhookHarness struct {
+	Repositories
+	Subscriptions ports.WebhookSubscriptionRepository
+	Deliveries    ports.WebhookDeliveryRepository
+}
+
+// WebhookRepositoryFactory создаёт harness над ПУСТЫМ хранилищем.
+type WebhookRepositoryFactory func(t *testing.T) WebhookHarness
+
+// RunWebhookRepositoryTests проверяет реализации ports.WebhookSubscriptionRepository
+// и ports.WebhookDeliveryRepository.
+func RunWebhookRepositoryTests(t *testing.T, factory WebhookRepositoryFactory) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	subscription := func(t *testing.T, h WebhookHarness, userID uuid.UUID) *entities.WebhookSubscription {
+		t.Helper()
+		s, err := entities.NewWebhookSubscription(userID, "https://example.com/hooks", []string{"transaction.completed"})
+		require.NoError(t, err)
+		require.NoError(t, h.Subscriptions.Save(context.Background(), s))
+		return s
+	}
+	reload := func(t *testing.T, h WebhookHarness, id uuid.UUID) *entities.WebhookSubscription {
+		t.Helper()
+		s, err := h.Subscriptions.FindByID(context.Background(), id)
+		require.NoError(t, err)
+		return s
+	}
+	delivery := func(subscriptionID uuid.UUID, minutes int) *entities.WebhookDelivery {
+		at := base.Add(time.Duration(minutes) * time.Minute)
+		return entities.ReconstructWebhookDelivery(uuid.New(), subscriptionID, uuid.New(), "transaction.completed",
+			[]byte(`{"type": "transaction.completed"}`), entities.WebhookDeliveryPending, 0, "", 0, at, at, nil)
+	}
+	enqueue := func(t *testing.T, h WebhookHarness, deliveries ...*entities.WebhookDelivery) {
+		t.Helper()
+		for _, d := range deliveries {
+			require.NoError(t, h.Deliveries.Enqueue(context.Background(), d))
+		}
+	}
+	history := func(t *testing.T, h WebhookHarness, subscriptionID uuid.UUID) map[uuid.UUID]*entities.WebhookDelivery {
+		t.Helper()
+		list, err := h.Deliveries.ListBySubscription(context.Background(), subscriptionID, 0, 100)
+		require.NoError(t, err)
+		result := make(map[uuid.UUID]*entities.WebhookDelivery, len(list))
+		for _, d := range list {
+			result[d.ID()] = d
+		}
+		return result
+	}
+
+	t.Run("SaveRoundTrip", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		user := newUser(t, h.Repositories)
+		s := subscription(t, h, user.ID())
+
+		got := reload(t, h, s.ID())
+		assert.Equal(t, user.ID(), got.UserID())
+		assert.Equal(t, "https://example.com/hooks", got.URL())
+		assert.Equal(t, s.Secret(), got.Secret())
+		assert.Equal(t, []string{"transaction.completed"}, got.EventTypes())
+		assert.Equal(t, entities.WebhookStatusActive, got.Status())
+		assert.Equal(t, int64(1), got.Version())
+		assert.Equal(t, time.UTC, got.CreatedAt().Location())
+
+		got.RecordDeliveryFailure("HTTP 404", entities.WebhookHealthPolicy{MaxConsecutiveFailures: 1})
+		require.NoError(t, h.Subscriptions.Save(ctx, got))
+
+		got = reload(t, h, s.ID())
+		assert.Equal(t, entities.WebhookStatusDisabledAuto, got.Status())
+		assert.Equal(t, 1, got.ConsecutiveFailures())
+		assert.Equal(t, "HTTP 404", got.LastFailureReason())
+		require.NotNil(t, got.FailingSince())
+		require.NotNil(t, got.DisabledAt())
+		assert.Equal(t, int64(2), got.Version())
+	})
+
+	t.Run("OptimisticLocking", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		s := subscription(t, h, newUser(t, h.Repositories).ID())
+
+		// Повторное создание той же подписки
+		err := h.Subscriptions.Save(ctx, s)
+		assert.True(t, domainErrors.IsConcurrencyError(err), "expected ConcurrencyError, got %v", err)
+
+		owner, worker := reload(t, h, s.ID()), reload(t, h, s.ID())
+		require.NoError(t, owner.Disable())
+		require.NoError(t, h.Subscriptions.Save(ctx, owner))
+
+		worker.RecordDeliveryFailure("HTTP 500", entities.DefaultWebhookHealthPolicy)
+		err = h.Subscriptions.Save(ctx, worker)
+		assert.True(t, domainErrors.IsConcurrencyError(err), "expected ConcurrencyError, got %v", err)
+
+		got := reload(t, h, s.ID())
+		assert.Equal(t, entities.WebhookStatusDisabledManual, got.Status())
+		assert.Zero(t, got.ConsecutiveFailures())
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		h := factory(t)
+
+		s, err := entities.NewWebhookSubscription(uuid.New(), "https://example.com/hooks", nil)
+		require.NoError(t, err)
+		assertDomainErrorCode(t, h.Subscriptions.Save(context.Background(), s), "USER_NOT_FOUND")
+
+		_, err = h.Subscriptions.FindByID(context.Background(), s.ID())
+		assert.True(t, domainErrors.IsNotFound(err), "FindByID: expected ErrEntityNotFound, got %v", err)
+	})
+
+	t.Run("ListByUserNewestFirst", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		user := newUser(t, h.Repositories)
+
+		var ids []uuid.UUID
+		for i := 0; i < 3; i++ {
+			s := entities.ReconstructWebhookSubscription(uuid.New(), user.ID(), "https://example.com/hooks", "secret",
+				nil, entities.WebhookStatusActive, 0, nil, "", nil, 1,
+				base.Add(time.Duration(i)*time.Minute), base.Add(time.Duration(i)*time.Minute), nil)
+			require.NoError(t, h.Subscriptions.Save(ctx, s))
+			ids = append([]uuid.UUID{s.ID()}, ids...)
+		}
+		subscription(t, h, newUser(t, h.Repositories).ID())
+
+		list, err := h.Subscriptions.ListByUserID(ctx, user.ID())
+		require.NoError(t, err)
+		assert.Equal(t, ids, webhookSubscriptionIDs(list...))
+	})
+
+	t.Run("SoftDeleteKeepsHistoryUntilPurge", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		user := newUser(t, h.Repositories)
+		kept := subscription(t, h, user.ID())
+		deleted := subscription(t, h, user.ID())
+		enqueue(t, h, delivery(deleted.ID(), 0), delivery(kept.ID(), 0))
+
+		s := reload(t, h, deleted.ID())
+		s.Delete()
+		require.NoError(t, h.Subscriptions.Save(ctx, s))
+
+		_, err := h.Subscriptions.FindByID(ctx, deleted.ID())
+		assert.True(t, domainErrors.IsNotFound(err), "FindByID: expected ErrEntityNotFound, got %v", err)
+		list, err := h.Subscriptions.ListByUserID(ctx, user.ID())
+		require.NoError(t, err)
+		assert.Equal(t, webhookSubscriptionIDs(kept), webhookSubscriptionIDs(list...))
+		assert.Len(t, history(t, h, deleted.ID()), 1, "history of a deleted subscription is kept")
+
+		purged, err := h.Subscriptions.PurgeDeleted(ctx, s.DeletedAt().Add(-time.Second))
+		require.NoError(t, err)
+		assert.Zero(t, purged)
+
+		purged, err = h.Subscriptions.PurgeDeleted(ctx, s.DeletedAt().Add(time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+		assert.Empty(t, history(t, h, deleted.ID()))
+		assert.Len(t, history(t, h, kept.ID()), 1)
+	})
+
+	t.Run("EnqueueIsIdempotent", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		s := subscription(t, h, newUser(t, h.Repositories).ID())
+
+		first := delivery(s.ID(), 0)
+		enqueue(t, h, first)
+		again := entities.NewWebhookDelivery(s.ID(), first.EventID(), first.EventType(), first.Payload())
+		require.NoError(t, h.Deliveries.Enqueue(ctx, again))
+
+		list := history(t, h, s.ID())
+		assert.Len(t, list, 1)
+		assert.Contains(t, list, first.ID())
+	})
+
+	t.Run("FindDueOnlyActiveInQueueOrder", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		user := newUser(t, h.Repositories)
+		active := subscription(t, h, user.ID())
+		disabled := subscription(t, h, user.ID())
+		deleted := subscription(t, h, user.ID())
+
+		second, first, future := delivery(active.ID(), 2), delivery(active.ID(), 1), delivery(active.ID(), 10)
+		enqueue(t, h, second, first, future, delivery(disabled.ID(), 0), delivery(deleted.ID(), 0))
+
+		s := reload(t, h, disabled.ID())
+		require.NoError(t, s.Disable())
+		require.NoError(t, h.Subscriptions.Save(ctx, s))
+		s = reload(t, h, deleted.ID())
+		s.Delete()
+		require.NoError(t, h.Subscriptions.Save(ctx, s))
+
+		due, err := h.Deliveries.FindDue(ctx, base.Add(5*time.Minute), 10)
+		require.NoError(t, err)
+		assert.Equal(t, webhookDeliveryIDs(first, second), webhookDeliveryIDs(due...))
+
+		due, err = h.Deliveries.FindDue(ctx, base.Add(5*time.Minute), 1)
+		require.NoError(t, err)
+		assert.Equal(t, webhookDeliveryIDs(first), webhookDeliveryIDs(due...))
+	})
+
+	t.Run("SaveAttemptResult", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		s := subscription(t, h, newUser(t, h.Repositories).ID())
+		d := delivery(s.ID(), 0)
+		enqueue(t, h, d)
+
+		next := base.Add(time.Hour)
+		d.MarkAttemptFailed(503, "HTTP 503", next, 10)
+		require.NoError(t, h.Deliveries.Save(ctx, d))
+
+		got := history(t, h, s.ID())[d.ID()]
+		require.NotNil(t, got)
+		assert.Equal(t, entities.WebhookDeliveryPending, got.Status())
+		assert.Equal(t, 1, got.Attempts())
+		assert.Equal(t, 503, got.LastStatusCode())
+		assert.Equal(t, "HTTP 503", got.LastError())
+		assert.True(t, got.NextAttemptAt().Equal(next))
+		assert.Equal(t, `{"type": "transaction.completed"}`, string(got.Payload()), "payload is stored verbatim")
+
+		due, err := h.Deliveries.FindDue(ctx, base.Add(time.Minute), 10)
+		require.NoError(t, err)
+		assert.Empty(t, due)
+
+		d.MarkDelivered(200)
+		require.NoError(t, h.Deliveries.Save(ctx, d))
+		got = history(t, h, s.ID())[d.ID()]
+		assert.Equal(t, entities.WebhookDeliveryDelivered, got.Status())
+		assert.NotNil(t, got.FinishedAt())
+	})
+
+	t.Run("SkipRescheduleExpire", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		user := newUser(t, h.Repositories)
+		skipped := subscription(t, h, user.ID())
+		rescheduled := subscription(t, h, user.ID())
+		expired := subscription(t, h, user.ID())
+
+		done := delivery(skipped.ID(), 0)
+		done.MarkDelivered(200)
+		pendingSkipped := delivery(skipped.ID(), 1)
+		pendingRescheduled := delivery(rescheduled.ID(), 1)
+		pendingRescheduled.MarkAttemptFailed(500, "HTTP 500", base.Add(24*time.Hour), 10)
+		pendingExpired := delivery(expired.ID(), 1)
+		enqueue(t, h, done, pendingSkipped, pendingRescheduled, pendingExpired)
+
+		count, err := h.Deliveries.SkipPending(ctx, skipped.ID())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		list := history(t, h, skipped.ID())
+		assert.Equal(t, entities.WebhookDeliveryDelivered, list[done.ID()].Status())
+		assert.Equal(t, entities.WebhookDeliverySkipped, list[pendingSkipped.ID()].Status())
+		assert.NotNil(t, list[pendingSkipped.ID()].FinishedAt())
+
+		count, err = h.Deliveries.ReschedulePending(ctx, rescheduled.ID(), base)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		got := history(t, h, rescheduled.ID())[pendingRescheduled.ID()]
+		assert.True(t, got.NextAttemptAt().Equal(base), "next attempt = %v", got.NextAttemptAt())
+
+		// Активная подписка не истекает; отключённая раньше cutoff - да
+		s := reload(t, h, expired.ID())
+		require.NoError(t, s.Disable())
+		require.NoError(t, h.Subscriptions.Save(ctx, s))
+
+		count, err = h.Deliveries.ExpireBacklog(ctx, s.DisabledAt().Add(-time.Second))
+		require.NoError(t, err)
+		assert.Zero(t, count, "disabled within the grace period")
+
+		count, err = h.Deliveries.ExpireBacklog(ctx, s.DisabledAt().Add(time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		assert.Equal(t, entities.WebhookDeliveryExpired, history(t, h, expired.ID())[pendingExpired.ID()].Status())
+		assert.Equal(t, entities.WebhookDeliveryPending, history(t, h, rescheduled.ID())[pendingRescheduled.ID()].Status())
+	})
+
+	t.Run("DeleteFinishedKeepsPending", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		s := subscription(t, h, newUser(t, h.Repositories).ID())
+
+		old, pending, fresh := delivery(s.ID(), 0), delivery(s.ID(), 1), delivery(s.ID(), 60)
+		old.MarkDelivered(200)
+		fresh.MarkDelivered(200)
+		enqueue(t, h, old, pending, fresh)
+
+		deleted, err := h.Deliveries.DeleteFinishedBefore(ctx, base.Add(30*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		list := history(t, h, s.ID())
+		assert.NotContains(t, list, old.ID())
+		assert.Contains(t, list, pending.ID())
+		assert.Contains(t, list, fresh.ID())
+	})
+
+	t.Run("HistoryNewestFirst", func(t *testing.T) {
+		h := factory(t)
+		ctx := context.Background()
+		s := subscription(t, h, newUser(t, h.Repositories).ID())
+
+		a, b, c := delivery(s.ID(), 0), delivery(s.ID(), 1), delivery(s.ID(), 2)
+		enqueue(t, h, b, a, c)
+
+		list, err := h.Deliveries.ListBySubscription(ctx, s.ID(), 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, webhookDeliveryIDs(c, b, a), webhookDeliveryIDs(list...))
+
+		page, err := h.Deliveries.ListBySubscription(ctx, s.ID(), 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, webhookDeliveryIDs(b), webhookDeliveryIDs(page...))
+	})
+}
+
+func webhookSubscriptionIDs(list ...*entities.WebhookSubscription) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(list))
+	for _, s := range list {
+		ids = append(ids, s.ID())
+	}
+	return ids
+}
+
+func webhookDeliveryIDs(list ...*entities.WebhookDelivery) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(list))
+	for _, d := range list {
+		ids = append(ids, d.ID())
+	}
+	return ids
+}
@@ -0,0 +1,54 @@
+// Package ports - ReadSessions: snapshot-сессии чтения для сверки.
+package ports
+
+import (
+	"context"
+	"time"
+
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Коды ошибок сессий чтения.
+const (
+	// ReadSessionNotFoundCode - токен неизвестен, истёк или выдан другому клиенту.
+	ReadSessionNotFoundCode = "READ_SESSION_NOT_FOUND"
+	// ReadSessionLimitCode - у клиента (или у инстанса) открыто максимум сессий.
+	ReadSessionLimitCode = "READ_SESSION_LIMIT_EXCEEDED"
+	// ReadSessionReadOnlyCode - мутирующий запрос внутри сессии чтения.
+	ReadSessionReadOnlyCode = "READ_SESSION_READ_ONLY"
+)
+
+// ErrReadSessionNotFound - токен сессии неизвестен, истёк или выдан другому
+// клиенту. Различать эти случаи клиенту незачем: нужна новая сессия.
+var ErrReadSessionNotFound = domainErrors.NewDomainError(ReadSessionNotFoundCode,
+	"Read session not found or expired, open a new one", nil)
+
+// ReadSession - открытая сессия чтения.
+type ReadSession struct {
+	Token     string
+	ExpiresAt time.Time
+	// Source - откуда читает сессия: "replica" или "primary"
+	Source string
+}
+
+// ReadSessions - короткоживущие сессии чтения с общим snapshot БД.
+//
+// Внешняя сверка выгружает кошельки и транзакции постранично за несколько
+// секунд; без общего snapshot балансы и списки транзакций относятся к разным
+// моментам и не сходятся. Сессия держит открытой транзакцию REPEATABLE READ
+// READ ONLY: все чтения, выполненные через Attach, видят одно состояние БД.
+//
+// Сессия занимает соединение пула, поэтому число сессий ограничено на
+// клиента и на инстанс, а сессия закрывается сама по истечении TTL.
+// Токен действует только на открывшем его инстансе.
+type ReadSessions interface {
+	// Open открывает сессию клиента. Превышение лимита - DomainError
+	// с кодом ReadSessionLimitCode.
+	Open(ctx context.Context, clientID string) (*ReadSession, error)
+
+	// Attach возвращает context, чтения репозиториев в котором идут через
+	// snapshot сессии, и release, который обязательно вызвать после запроса.
+	// Запросы одной сессии выполняются по очереди. Чужой или истёкший
+	// токен - ErrReadSessionNotFound.
+	Attach(ctx context.Context, clientID, token string) (context.Context, func(), error)
+}
@@ -11,6 +11,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/Haleralex/wallethub/internal/domain/entities"
 	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
@@ -34,7 +35,8 @@ type UserRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
 
 	// FindByEmail загружает пользователя по email.
-	// Email уникален в системе (UNIQUE constraint).
+	// Email уникален по нормализованному ключу (valueobjects.EmailPolicy):
+	// User+tag@gmail.com и user@gmail.com - один пользователь.
 	FindByEmail(ctx context.Context, email string) (*entities.User, error)
 
 	// ExistsByEmail проверяет существование без загрузки всей entity.
@@ -49,20 +51,324 @@ type UserRepository interface {
 	// offset: пропустить N записей
 	// limit: вернуть максимум N записей
 	List(ctx context.Context, offset, limit int) ([]*entities.User, error)
+
+	// UserActivitySummary возвращает число кошельков и транзакций пользователя
+	// одной агрегацией. Пользователь без кошельков - нули и LastActivityAt nil.
+	// Выполняется на каждой загрузке admin страницы пользователя, поэтому
+	// запрос должен обходиться индексами (wallets.user_id,
+	// transactions(wallet_id, created_at)).
+	UserActivitySummary(ctx context.Context, userID uuid.UUID) (UserActivity, error)
+}
+
+// UserActivity - сводка активности пользователя по всем его кошелькам.
+type UserActivity struct {
+	WalletCount       int
+	TotalTransactions int64
+	LastActivityAt    *time.Time // created_at последней транзакции; nil - транзакций нет
+}
+
+// KYCHistoryRepository определяет контракт для истории KYC решений.
+//
+// История append-only: записи не изменяются и не удаляются.
+// Append вызывается внутри того же UnitOfWork, что и сохранение User,
+// поэтому статус пользователя и история не расходятся.
+type KYCHistoryRepository interface {
+	// Append добавляет запись о смене KYC статуса.
+	Append(ctx context.Context, transition *entities.KYCTransition) error
+
+	// ListByUserID возвращает историю пользователя в хронологическом порядке
+	// (occurred_at ASC). Пустой slice, если решений не было.
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.KYCTransition, error)
+}
+
+// SecurityEventRepository определяет контракт для журнала событий аутентификации.
+//
+// Журнал append-only; записи удаляются только по retention (DeleteBefore).
+// Пишется асинхронно вне UnitOfWork запроса (см. SecurityEventRecorder).
+type SecurityEventRepository interface {
+	// Append добавляет событие в журнал.
+	Append(ctx context.Context, event *entities.SecurityEvent) error
+
+	// List возвращает события с фильтрацией и пагинацией, новые первыми
+	// (occurred_at DESC).
+	List(ctx context.Context, filter SecurityEventFilter, offset, limit int) ([]*entities.SecurityEvent, error)
+
+	// DeleteBefore удаляет события старше cutoff и возвращает число удалённых.
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// SecurityEventFilter определяет критерии фильтрации журнала безопасности.
+type SecurityEventFilter struct {
+	Principal *string                     // Точное совпадение principal
+	Type      *entities.SecurityEventType // Фильтр по типу
+	IP        *string                     // Фильтр по IP клиента
+
+	OccurredFrom *time.Time // occurred_at >= OccurredFrom
+	OccurredTo   *time.Time // occurred_at < OccurredTo
+}
+
+// FailedRequestRepository определяет контракт для образцов неудачных
+// денежных операций (таблица failed_requests).
+//
+// Хранилище append-only; записи удаляются только по retention (DeleteBefore).
+// Пишется асинхронно вне UnitOfWork запроса (см. FailedRequestRecorder).
+type FailedRequestRepository interface {
+	// Append добавляет образец.
+	Append(ctx context.Context, sample *entities.FailedRequest) error
+
+	// List возвращает образцы с фильтрацией и пагинацией, новые первыми
+	// (occurred_at DESC).
+	List(ctx context.Context, filter FailedRequestFilter, offset, limit int) ([]*entities.FailedRequest, error)
+
+	// DeleteBefore удаляет образцы старше cutoff и возвращает число удалённых.
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// FailedRequestFilter определяет критерии фильтрации образцов неудачных запросов.
+type FailedRequestFilter struct {
+	ActorID *uuid.UUID // Пользователь, отправивший запрос
+	Route   *string    // Точное совпадение шаблона маршрута
+
+	OccurredFrom *time.Time // occurred_at >= OccurredFrom
+	OccurredTo   *time.Time // occurred_at < OccurredTo
+}
+
+// FXRateSnapshotRepository определяет контракт для снимков курсов валют,
+// по которым выполнены денежные операции (таблица fx_rate_snapshots).
+//
+// Контракт (проверяется porttest.RunFXRateSnapshotRepositoryTests):
+//   - Хранилище append-only: повторный Append того же ID -
+//     DomainError FX_RATE_SNAPSHOT_EXISTS, изменение снимка невозможно
+//   - Append внутри UnitOfWork откатывается вместе с операцией
+//   - FindByID отдаёт ErrEntityNotFound, если снимка нет
+//   - Курс читается обратно без потери точности (FXRateScale знаков)
+type FXRateSnapshotRepository interface {
+	// Append сохраняет снимок.
+	Append(ctx context.Context, snapshot *entities.FXRateSnapshot) error
+
+	// FindByID загружает снимок по ID.
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.FXRateSnapshot, error)
+
+	// DeleteBefore удаляет снимки с fetched_at раньше cutoff и возвращает
+	// число удалённых.
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// OperationSwitchRepository определяет контракт для аварийных выключателей
+// операций (строки operation_switch.* таблицы system_settings).
+//
+// Контракт (проверяется porttest.RunOperationSwitchRepositoryTests):
+//   - Save создаёт или заменяет состояние выключателя его операции
+//   - FindAll возвращает только сохранённые выключатели; операция без
+//     записи считается включённой
+//   - Изменения видны вызывающему после коммита UnitOfWork
+type OperationSwitchRepository interface {
+	// Save сохраняет состояние выключателя (upsert по операции).
+	Save(ctx context.Context, sw *entities.OperationSwitch) error
+
+	// FindAll возвращает все сохранённые выключатели, упорядоченные по операции.
+	FindAll(ctx context.Context) ([]*entities.OperationSwitch, error)
+}
+
+// IncidentRepository определяет контракт для отметок инцидентов страницы
+// статуса (таблица incidents).
+//
+// Контракт (проверяется porttest.RunIncidentRepositoryTests):
+//   - Save создаёт или заменяет инцидент по ID
+//   - FindByID отдаёт ErrEntityNotFound, если инцидента нет
+//   - ListVisible возвращает активные инциденты и решённые не раньше
+//     resolvedSince, по started_at DESC
+type IncidentRepository interface {
+	// Save сохраняет инцидент (create or update по ID).
+	Save(ctx context.Context, incident *entities.Incident) error
+
+	// FindByID загружает инцидент по ID.
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.Incident, error)
+
+	// ListVisible возвращает инциденты для страницы статуса.
+	ListVisible(ctx context.Context, resolvedSince time.Time) ([]*entities.Incident, error)
+}
+
+// WalletNoteRepository определяет контракт для заметок поддержки на кошельках.
+//
+// Контракт (проверяется porttest.RunWalletNoteRepositoryTests):
+//   - Удаление мягкое: Save удалённой заметки сохраняет deleted_at/deleted_by,
+//     строка остаётся в хранилище, но FindByID и ListByWallet её не видят
+//   - FindByID отдаёт ErrEntityNotFound для отсутствующей или удалённой заметки
+//   - Заметка несуществующего кошелька - DomainError WALLET_NOT_FOUND
+//   - ListByWallet: закреплённые первыми, внутри групп created_at DESC
+type WalletNoteRepository interface {
+	// Save сохраняет заметку (create or update по ID).
+	Save(ctx context.Context, note *entities.WalletNote) error
+
+	// FindByID загружает неудалённую заметку по ID.
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.WalletNote, error)
+
+	// ListByWallet возвращает неудалённые заметки кошелька с пагинацией.
+	ListByWallet(ctx context.Context, walletID uuid.UUID, offset, limit int) ([]*entities.WalletNote, error)
+}
+
+// WebhookSubscriptionRepository определяет контракт для webhook подписок
+// пользователей (таблица webhook_subscriptions).
+//
+// Контракт (проверяется porttest.RunWebhookRepositoryTests):
+//   - Save создаёт подписку с Version() == 1 или обновляет сохранённую с
+//     версией Version()-1; иначе (изменена параллельно или уже создана) -
+//     ConcurrencyError
+//   - Подписка несуществующего пользователя - DomainError USER_NOT_FOUND
+//   - Удаление мягкое: удалённая подписка остаётся в хранилище вместе с
+//     историей доставок, но FindByID и ListByUserID её не видят
+//   - PurgeDeleted удаляет подписки, удалённые раньше cutoff, вместе с их
+//     доставками (retention)
+type WebhookSubscriptionRepository interface {
+	// Save сохраняет подписку (create or update с optimistic locking).
+	Save(ctx context.Context, subscription *entities.WebhookSubscription) error
+
+	// FindByID загружает неудалённую подписку по ID.
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.WebhookSubscription, error)
+
+	// ListByUserID возвращает неудалённые подписки пользователя, новые первыми.
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.WebhookSubscription, error)
+
+	// PurgeDeleted окончательно удаляет подписки, удалённые раньше cutoff,
+	// и возвращает их число.
+	PurgeDeleted(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// WebhookDeliveryRepository определяет контракт для очереди и истории
+// webhook доставок (таблица webhook_deliveries).
+//
+// Контракт (проверяется porttest.RunWebhookRepositoryTests):
+//   - Enqueue идемпотентен: повтор события для той же подписки игнорируется
+//   - FindDue возвращает PENDING доставки со сроком не позже now только
+//     активных неудалённых подписок, в порядке постановки в очередь
+//   - SkipPending/ReschedulePending/ExpireBacklog меняют только PENDING доставки
+//   - DeleteFinishedBefore не трогает PENDING доставки
+//
+// FindDue не блокирует строки: доставку выполняет одна задача планировщика
+// под lease (см. scheduler), параллельных исполнителей нет.
+type WebhookDeliveryRepository interface {
+	// Enqueue ставит доставку в очередь.
+	Enqueue(ctx context.Context, delivery *entities.WebhookDelivery) error
+
+	// Save сохраняет результат попытки доставки.
+	Save(ctx context.Context, delivery *entities.WebhookDelivery) error
+
+	// FindDue возвращает до limit доставок, срок которых наступил.
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*entities.WebhookDelivery, error)
+
+	// ListBySubscription возвращает историю доставок подписки, новые первыми.
+	ListBySubscription(ctx context.Context, subscriptionID uuid.UUID, offset, limit int) ([]*entities.WebhookDelivery, error)
+
+	// SkipPending отмечает очередь подписки SKIPPED (возобновление без
+	// накопленного, удаление подписки) и возвращает число доставок.
+	SkipPending(ctx context.Context, subscriptionID uuid.UUID) (int64, error)
+
+	// ReschedulePending делает очередь подписки доступной с момента at
+	// (возобновление с накопленным) и возвращает число доставок.
+	ReschedulePending(ctx context.Context, subscriptionID uuid.UUID, at time.Time) (int64, error)
+
+	// ExpireBacklog отмечает EXPIRED очередь подписок, отключённых раньше
+	// disabledBefore, и возвращает число доставок.
+	ExpireBacklog(ctx context.Context, disabledBefore time.Time) (int64, error)
+
+	// DeleteFinishedBefore удаляет завершённые доставки, поставленные в
+	// очередь раньше cutoff, и возвращает их число (retention).
+	DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// WalletSettingsRepository определяет контракт для настроек представления
+// кошелька (таблица wallet_settings) и отрисованных по ним представлений
+// входящих переводов (таблица incoming_transfer_views).
+//
+// Контракт (проверяется porttest.RunWalletSettingsRepositoryTests):
+//   - Save создаёт или заменяет настройки кошелька
+//   - FindByWalletID отдаёт ErrEntityNotFound, если настройки не сохранялись
+//   - Настройки или представление несуществующего кошелька - DomainError
+//     WALLET_NOT_FOUND
+//   - FindIncomingView отдаёт ErrEntityNotFound, если представления нет
+type WalletSettingsRepository interface {
+	// Save сохраняет настройки (create or update по ID кошелька).
+	Save(ctx context.Context, settings *entities.WalletSettings) error
+
+	// FindByWalletID загружает настройки кошелька.
+	FindByWalletID(ctx context.Context, walletID uuid.UUID) (*entities.WalletSettings, error)
+
+	// FindByWalletIDs загружает настройки нескольких кошельков одним
+	// запросом (списки кошельков). Кошельков без настроек в map нет.
+	FindByWalletIDs(ctx context.Context, walletIDs []uuid.UUID) (map[uuid.UUID]*entities.WalletSettings, error)
+
+	// SaveIncomingView сохраняет представление входящего перевода для получателя.
+	SaveIncomingView(ctx context.Context, view *entities.IncomingTransferView) error
+
+	// FindIncomingView загружает представление перевода для получателя.
+	FindIncomingView(ctx context.Context, transactionID uuid.UUID) (*entities.IncomingTransferView, error)
+}
+
+// MetadataUsageRepository определяет контракт для счётчиков объёма
+// metadata транзакций по кошелькам (таблицы wallet_metadata_usage и
+// transaction_metadata_usage).
+//
+// Счётчик кошелька - ровно сумма учтённых транзакций: учёт идёт в той же
+// UnitOfWork, что и создание транзакции, и откатывается вместе с ней.
+//
+// Контракт (проверяется porttest.RunMetadataUsageRepositoryTests):
+//   - AddTransactionMetadata атомарно увеличивает счётчик кошелька и
+//     возвращает новое значение; повтор для той же транзакции - ошибка
+//   - ReleaseTransactionMetadata вычитает учтённое для транзакций
+//     (архивация); неучтённые и уже вычтенные транзакции пропускаются
+//   - MetadataUsage возвращает 0 для кошелька без учтённых транзакций
+//   - Счётчики удаляются вместе с кошельком
+type MetadataUsageRepository interface {
+	// AddTransactionMetadata учитывает metadata новой транзакции и
+	// возвращает объём metadata кошелька с её учётом.
+	AddTransactionMetadata(ctx context.Context, walletID, transactionID uuid.UUID, bytes int64) (int64, error)
+
+	// ReleaseTransactionMetadata вычитает metadata архивируемых транзакций.
+	ReleaseTransactionMetadata(ctx context.Context, transactionIDs []uuid.UUID) error
+
+	// MetadataUsage возвращает текущий объём metadata кошелька в байтах.
+	MetadataUsage(ctx context.Context, walletID uuid.UUID) (int64, error)
 }
 
 // WalletRepository определяет контракт для хранения кошельков.
 //
 // Важно: Wallet - это Aggregate Root.
 // Repository сохраняет весь Aggregate (включая Balance) атомарно.
+//
+// Use cases, которые только читают, принимают WalletReader: так запись
+// из read-only use case не скомпилируется, а reader можно привязать
+// к другому пулу соединений, не трогая use cases.
+//
+// Контракт (проверяется porttest.RunWalletRepositoryTests):
+//   - Find* методы, возвращающие одну entity, отдают ErrEntityNotFound если её нет
+//   - Второй кошелёк с той же парой user+currency - BusinessRuleViolation WALLET_ALREADY_EXISTS
+//   - Кошелёк несуществующего пользователя - DomainError USER_NOT_FOUND
+//   - List сортирует по created_at DESC, FindByUserID - по created_at ASC
 type WalletRepository interface {
+	WalletReader
+	WalletWriter
+}
+
+// WalletWriter - запись кошельков.
+type WalletWriter interface {
 	// Save сохраняет кошелёк с проверкой версии (optimistic locking).
-	// Если version не совпадает, возвращает ConcurrencyError.
+	// Новый кошелёк (version = 0) вставляется, остальные обновляются,
+	// если в хранилище лежит version - 1. Иначе возвращает ConcurrencyError.
 	Save(ctx context.Context, wallet *entities.Wallet) error
+}
 
+// WalletReader - чтение кошельков.
+type WalletReader interface {
 	// FindByID загружает кошелёк по ID со всеми вложенными данными.
+	// Возвращает ErrEntityNotFound если кошелёк не найден.
 	FindByID(ctx context.Context, id uuid.UUID) (*entities.Wallet, error)
 
+	// FindBalanceByID читает только балансы, версию и владельца кошелька,
+	// без восстановления entity (быстрый путь чтения баланса).
+	// Возвращает ErrEntityNotFound если кошелёк не найден.
+	FindBalanceByID(ctx context.Context, id uuid.UUID) (*WalletBalance, error)
+
 	// FindByUserAndCurrency находит кошелёк пользователя для конкретной валюты.
 	// У пользователя может быть только один кошелёк на валюту.
 	FindByUserAndCurrency(ctx context.Context, userID uuid.UUID, currency valueobjects.Currency) (*entities.Wallet, error)
@@ -77,6 +383,17 @@ type WalletRepository interface {
 	List(ctx context.Context, filter WalletFilter, offset, limit int) ([]*entities.Wallet, error)
 }
 
+// WalletBalance - балансы кошелька в минимальных единицах валюты
+// (результат FindBalanceByID). Значения те же, что отдаёт FindByID.
+type WalletBalance struct {
+	WalletID       uuid.UUID
+	UserID         uuid.UUID // Владелец - для проверки доступа
+	CurrencyCode   string
+	AvailableCents int64
+	PendingCents   int64
+	BalanceVersion int64
+}
+
 // WalletFilter определяет критерии фильтрации для кошельков.
 type WalletFilter struct {
 	UserID   *uuid.UUID             // Фильтр по пользователю
@@ -85,17 +402,45 @@ type WalletFilter struct {
 }
 
 // TransactionRepository определяет контракт для хранения транзакций.
+//
+// Контракт (проверяется porttest.RunTransactionRepositoryTests):
+//   - Find* методы, возвращающие одну entity, отдают ErrEntityNotFound если её нет
+//     (включая FindByIdempotencyKey - nil, nil не возвращается никогда)
+//   - Idempotency key уникален в пределах окружения (app.environment), которое
+//     задаётся реализации при создании, а не в запросе: чужой ключ своего
+//     окружения при Save - ErrDuplicateTransaction, ключ другого окружения
+//     не виден FindByIdempotencyKey и не мешает Save
+//   - Транзакция несуществующего кошелька - DomainError WALLET_NOT_FOUND
+//
+// Read-only use cases принимают TransactionReader (см. WalletRepository).
 type TransactionRepository interface {
-	// Save сохраняет транзакцию.
+	TransactionReader
+	TransactionWriter
+}
+
+// TransactionWriter - запись транзакций.
+type TransactionWriter interface {
+	// Save сохраняет транзакцию (upsert по ID).
 	Save(ctx context.Context, tx *entities.Transaction) error
+}
 
+// TransactionReader - чтение транзакций.
+type TransactionReader interface {
 	// FindByID загружает транзакцию по ID.
+	// Возвращает ErrEntityNotFound если транзакция не найдена.
 	FindByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error)
 
-	// FindByIdempotencyKey находит транзакцию по ключу идемпотентности.
-	// Критично для предотвращения дубликатов!
+	// FindByIdempotencyKey находит транзакцию по ключу идемпотентности
+	// в окружении репозитория. Критично для предотвращения дубликатов!
+	// Возвращает ErrEntityNotFound если ключ ещё не использовался.
 	FindByIdempotencyKey(ctx context.Context, key string) (*entities.Transaction, error)
 
+	// FindByExternalReference находит транзакции по внешней ссылке в том виде,
+	// в каком её прислал провайдер. Сравнение идёт по SHA-256 хэшу
+	// (external_reference_hash), поэтому работает и для замаскированных ссылок.
+	// Порядок - по created_at ASC; ничего не найдено - пустой результат без ошибки.
+	FindByExternalReference(ctx context.Context, reference string) ([]*entities.Transaction, error)
+
 	// FindByWalletID возвращает транзакции кошелька.
 	FindByWalletID(ctx context.Context, walletID uuid.UUID, offset, limit int) ([]*entities.Transaction, error)
 
@@ -103,12 +448,111 @@ type TransactionRepository interface {
 	// Используется для обработки очереди.
 	FindPendingByWallet(ctx context.Context, walletID uuid.UUID) ([]*entities.Transaction, error)
 
-	// FindFailedRetryable возвращает failed транзакции, которые можно повторить.
+	// CountPendingByWallet возвращает число незавершённых (PENDING и PROCESSING)
+	// транзакций кошелька. Дешёвый COUNT для проверки лимита без загрузки строк.
+	CountPendingByWallet(ctx context.Context, walletID uuid.UUID) (int, error)
+
+	// FindFailedRetryable возвращает failed транзакции, которые можно повторить:
+	// retry_count < maxRetries и next_retry_at уже наступил (или не задан).
 	// Для фоновой обработки retry logic.
 	FindFailedRetryable(ctx context.Context, maxRetries int, limit int) ([]*entities.Transaction, error)
 
-	// List возвращает транзакции с фильтрацией и пагинацией.
+	// List возвращает транзакции с фильтрацией и пагинацией
+	// (created_at DESC, id DESC).
 	List(ctx context.Context, filter TransactionFilter, offset, limit int) ([]*entities.Transaction, error)
+
+	// ListBefore - keyset вариант List для обхода больших выборок: до limit
+	// транзакций строго после курсора в порядке List (nil - с начала).
+	// Страницы не пересекаются и не пропускают строки при вставках в голову
+	// списка, в отличие от OFFSET.
+	ListBefore(ctx context.Context, filter TransactionFilter, before *TransactionCursor, limit int) ([]*entities.Transaction, error)
+
+	// ListAfter - хронологический keyset обход (created_at ASC, id ASC):
+	// до limit транзакций строго после курсора (nil - с самой ранней).
+	// Для воспроизведения истории кошелька в порядке проведения.
+	ListAfter(ctx context.Context, filter TransactionFilter, after *TransactionCursor, limit int) ([]*entities.Transaction, error)
+
+	// CountOutcomes считает COMPLETED и FAILED транзакции, завершённые
+	// (completed_at) в [from, to). Для admin статистики провалов.
+	CountOutcomes(ctx context.Context, from, to time.Time) (TransactionOutcomeCounts, error)
+}
+
+// TransactionOutcomeCounts - исходы транзакций за период.
+type TransactionOutcomeCounts struct {
+	Completed int
+	Failed    map[entities.FailureCategory]int // Ключ "" - провалы без категории
+}
+
+// ProcessingRecoveryRepository находит транзакции, оставленные в PROCESSING
+// упавшим экземпляром. Владелец транзакции - экземпляр, сохранивший её в
+// PROCESSING (задаётся реализации TransactionRepository при создании, как
+// окружение); время начала обработки - ProcessedAt.
+//
+// Контракт (проверяется porttest.RunProcessingRecoveryTests):
+//   - Транзакция, владелец которой подал heartbeat (InstanceRepository) не
+//     раньше aliveSince, не выдаётся никогда
+//   - Транзакция без владельца (сохранена до его учёта) считается осиротевшей
+//   - ClaimOrphaned делает вызывающий экземпляр владельцем выданных
+//     транзакций: конкурирующие вызовы получают непересекающиеся наборы
+//   - Release снимает владельца, только если транзакция всё ещё PROCESSING
+//     у instance - следующий ClaimOrphaned выдаст её снова
+type ProcessingRecoveryRepository interface {
+	// ClaimOrphaned забирает до limit транзакций PROCESSING, начатых раньше
+	// startedBefore, чей владелец не жив с aliveSince (старые первыми), и
+	// возвращает их ID.
+	ClaimOrphaned(ctx context.Context, instance string, startedBefore, aliveSince time.Time, limit int) ([]uuid.UUID, error)
+
+	// Release отказывается от транзакции, забранной ClaimOrphaned.
+	Release(ctx context.Context, id uuid.UUID, instance string) error
+}
+
+// TransactionReceiptRepository находит транзакции по номеру чека.
+//
+// Контракт (проверяется porttest.RunTransactionReceiptTests):
+//   - TransactionRepository.Save выдаёт номер чека (valueobjects.NewReceiptNumber
+//     от следующего значения последовательности) каждой COMPLETED транзакции
+//     без номера; номера уникальны и при параллельных Save
+//   - Незавершённые транзакции номера не получают, выданный номер не меняется
+//   - FindByReceiptNumber принимает канонический номер (valueobjects.ParseReceiptNumber)
+//     и отдаёт ErrEntityNotFound если его нет
+type TransactionReceiptRepository interface {
+	FindByReceiptNumber(ctx context.Context, number string) (*entities.Transaction, error)
+}
+
+// TransactionActivityRepository агрегирует активность кошельков по дням
+// (heatmap дашборда).
+//
+// Контракт (проверяется porttest.RunTransactionActivityTests):
+//   - Учитываются только COMPLETED транзакции с created_at в [From, To)
+//   - День - календарная дата created_at в Location (с учётом перехода
+//     на летнее время)
+//   - Транзакция кошелька фильтра - зачисление amount для DEPOSIT, REFUND,
+//     ADJUSTMENT и списание amount для остальных известных типов (как
+//     направление в выгрузке); входящий TRANSFER (destination_wallet_id) -
+//     зачисление net_amount. Тип, неизвестный версии, только считается
+//   - Транзакция между двумя кошельками фильтра считается один раз
+//   - Дни без транзакций не возвращаются; порядок - по дате, затем валюте
+//   - Запрос идёт по индексам (wallet_id, created_at) и
+//     (destination_wallet_id, created_at), без полного сканирования
+type TransactionActivityRepository interface {
+	DailyActivity(ctx context.Context, filter ActivityFilter) ([]DailyActivity, error)
+}
+
+// ActivityFilter - кошельки и период агрегации активности.
+type ActivityFilter struct {
+	WalletIDs []uuid.UUID
+	From      time.Time      // created_at >= From
+	To        time.Time      // created_at < To
+	Location  *time.Location // Зона, в которой считаются дни
+}
+
+// DailyActivity - активность кошельков фильтра за день в одной валюте.
+type DailyActivity struct {
+	Date         time.Time // Календарный день: полночь UTC с датой дня в Location
+	CurrencyCode string
+	Count        int
+	Credit       valueobjects.Money // Зачисления в CurrencyCode
+	Debit        valueobjects.Money // Списания в CurrencyCode
 }
 
 // TransactionFilter определяет критерии фильтрации для транзакций.
@@ -117,4 +561,55 @@ type TransactionFilter struct {
 	UserID   *uuid.UUID                  // Фильтр по пользователю (join через wallet)
 	Type     *entities.TransactionType   // Фильтр по типу
 	Status   *entities.TransactionStatus // Фильтр по статусу
+
+	CreatedFrom *time.Time // created_at >= CreatedFrom
+	CreatedTo   *time.Time // created_at < CreatedTo
+}
+
+// TransactionCursor - позиция в списке транзакций (created_at, id):
+// последняя прочитанная строка. ListBefore идёт от неё к более ранним,
+// ListAfter - к более поздним.
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CursorOf возвращает курсор, указывающий на транзакцию tx.
+func CursorOf(tx *entities.Transaction) *TransactionCursor {
+	return &TransactionCursor{CreatedAt: tx.CreatedAt(), ID: tx.ID()}
+}
+
+// TransactionBackfillRepository - массовая запись исторических транзакций
+// (перенос из legacy системы).
+//
+// Контракт (проверяется porttest.RunTransactionBackfillRepositoryTests):
+//   - ExistingIDs возвращает только ID, уже сохранённые в хранилище
+//   - InsertBatch пишет пачку как есть, без upsert и событий; пачка
+//     атомарна: дубликат ID или idempotency key - ErrDuplicateTransaction,
+//     кошелёк не существует - DomainError WALLET_NOT_FOUND, и ничего не записано
+//   - Балансы кошельков не изменяются
+type TransactionBackfillRepository interface {
+	// ExistingIDs возвращает множество ID из ids, уже сохранённых в хранилище.
+	ExistingIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]struct{}, error)
+
+	// InsertBatch записывает новые транзакции одной пачкой (в PostgreSQL - COPY).
+	InsertBatch(ctx context.Context, transactions []*entities.Transaction) error
 }
+
+// SandboxRepository удаляет данные tenant'а в sandbox окружении.
+//
+// Контракт:
+//   - PurgeUserData удаляет транзакции кошельков пользователя, затем сами
+//     кошельки (порядок FK), webhook подписки пользователя с их доставками
+//     и возвращает число удалённых строк по таблицам
+//   - Пользователь и его KYC история не удаляются - tenant продолжает работу
+//   - Чужие строки не изменяются: если транзакция другого пользователя ссылается
+//     на кошелёк пользователя (входящий перевод), возвращается BusinessRuleViolation
+//     CROSS_TENANT_REFERENCES и ничего не удаляется
+//   - Вызывается внутри UnitOfWork
+type SandboxRepository interface {
+	PurgeUserData(ctx context.Context, userID uuid.UUID) (SandboxPurgeCounts, error)
+}
+
+// SandboxPurgeCounts - число удалённых строк по таблицам.
+type SandboxPurgeCounts map[string]int64
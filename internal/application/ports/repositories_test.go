@@ -0,0 +1,72 @@
+package ports
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Compile-time проверка: интерфейс с методом Save другой сигнатуры
+// перестанет компилироваться (duplicate method Save), если Save появится
+// в reader.
+type (
+	walletReaderWithoutSave interface {
+		WalletReader
+		Save()
+	}
+	transactionReaderWithoutSave interface {
+		TransactionReader
+		Save()
+	}
+)
+
+var (
+	_ walletReaderWithoutSave      = nil
+	_ transactionReaderWithoutSave = nil
+)
+
+// TestReaders_HaveOnlyReadMethods проверяет, что reader интерфейсы
+// содержат только методы чтения, а Repository - это reader + writer.
+func TestReaders_HaveOnlyReadMethods(t *testing.T) {
+	readPrefixes := []string{"Find", "Exists", "List", "Count"}
+
+	readers := map[string]reflect.Type{
+		"WalletReader":      reflect.TypeOf((*WalletReader)(nil)).Elem(),
+		"TransactionReader": reflect.TypeOf((*TransactionReader)(nil)).Elem(),
+	}
+	for name, reader := range readers {
+		for i := 0; i < reader.NumMethod(); i++ {
+			method := reader.Method(i).Name
+			isRead := false
+			for _, prefix := range readPrefixes {
+				if strings.HasPrefix(method, prefix) {
+					isRead = true
+					break
+				}
+			}
+			if !isRead {
+				t.Errorf("%s.%s is not a read method", name, method)
+			}
+		}
+	}
+
+	repositories := []struct {
+		repository, reader, writer reflect.Type
+	}{
+		{
+			reflect.TypeOf((*WalletRepository)(nil)).Elem(),
+			readers["WalletReader"],
+			reflect.TypeOf((*WalletWriter)(nil)).Elem(),
+		},
+		{
+			reflect.TypeOf((*TransactionRepository)(nil)).Elem(),
+			readers["TransactionReader"],
+			reflect.TypeOf((*TransactionWriter)(nil)).Elem(),
+		},
+	}
+	for _, r := range repositories {
+		if got, want := r.repository.NumMethod(), r.reader.NumMethod()+r.writer.NumMethod(); got != want {
+			t.Errorf("%s has %d methods, want reader + writer = %d", r.repository.Name(), got, want)
+		}
+	}
+}
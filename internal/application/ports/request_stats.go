@@ -0,0 +1,84 @@
+// Package ports - RequestStats: стоимость запроса для debug заголовков.
+package ports
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// requestStatsKey - ключ RequestStats в context.
+type requestStatsKey struct{}
+
+// RequestStats - счётчики стоимости одного запроса (debug).
+//
+// Кладётся в context только по запросу (X-Debug-Stats), поэтому без него
+// репозитории и publisher получают nil и ничего не считают. Методы безопасны
+// для nil receiver и конкурентных вызовов.
+type RequestStats struct {
+	dbQueries       atomic.Int64
+	dbTime          atomic.Int64 // nanoseconds
+	lockRetries     atomic.Int64
+	eventsPublished atomic.Int64
+	publishTime     atomic.Int64 // nanoseconds
+}
+
+// RequestStatsSnapshot - значения счётчиков на момент чтения.
+type RequestStatsSnapshot struct {
+	DBQueries       int64
+	DBTime          time.Duration
+	LockRetries     int64
+	EventsPublished int64
+	PublishTime     time.Duration // Включает запись в outbox (она же учтена в DBTime)
+}
+
+// WithRequestStats возвращает context с RequestStats.
+func WithRequestStats(ctx context.Context, stats *RequestStats) context.Context {
+	return context.WithValue(ctx, requestStatsKey{}, stats)
+}
+
+// RequestStatsFromContext извлекает RequestStats из context (nil если нет).
+func RequestStatsFromContext(ctx context.Context) *RequestStats {
+	stats, _ := ctx.Value(requestStatsKey{}).(*RequestStats)
+	return stats
+}
+
+// RecordQuery учитывает один запрос к хранилищу.
+func (s *RequestStats) RecordQuery(elapsed time.Duration) {
+	if s == nil {
+		return
+	}
+	s.dbQueries.Add(1)
+	s.dbTime.Add(int64(elapsed))
+}
+
+// RecordLockRetry учитывает повтор операции после конфликта optimistic locking.
+func (s *RequestStats) RecordLockRetry() {
+	if s == nil {
+		return
+	}
+	s.lockRetries.Add(1)
+}
+
+// RecordPublish учитывает публикацию count событий.
+func (s *RequestStats) RecordPublish(count int, elapsed time.Duration) {
+	if s == nil {
+		return
+	}
+	s.eventsPublished.Add(int64(count))
+	s.publishTime.Add(int64(elapsed))
+}
+
+// Snapshot возвращает текущие значения счётчиков.
+func (s *RequestStats) Snapshot() RequestStatsSnapshot {
+	if s == nil {
+		return RequestStatsSnapshot{}
+	}
+	return RequestStatsSnapshot{
+		DBQueries:       s.dbQueries.Load(),
+		DBTime:          time.Duration(s.dbTime.Load()),
+		LockRetries:     s.lockRetries.Load(),
+		EventsPublished: s.eventsPublished.Load(),
+		PublishTime:     time.Duration(s.publishTime.Load()),
+	}
+}
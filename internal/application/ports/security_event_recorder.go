@@ -0,0 +1,16 @@
+// Package ports - SecurityEventRecorder: журнал событий аутентификации.
+package ports
+
+import "github.com/Haleralex/wallethub/internal/domain/entities"
+
+// SecurityEventRecorder принимает события аутентификации и авторизации
+// от HTTP middleware.
+//
+// Контракт:
+//   - Record никогда не блокирует запрос: событие ставится в очередь,
+//     а при переполненной очереди отбрасывается (с метрикой)
+//   - Ошибка записи в хранилище не возвращается вызывающему - запрос
+//     не должен падать из-за журнала безопасности
+type SecurityEventRecorder interface {
+	Record(event *entities.SecurityEvent)
+}
@@ -0,0 +1,49 @@
+// Package ports - SensitiveDataPolicy: что делать с PAN/IBAN во входящих данных.
+package ports
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// SensitiveDataPolicy - режим обработки номеров карт и IBAN в external
+// reference и metadata (config sensitive_data.strict).
+//
+// По умолчанию Transaction сама маскирует такие значения при записи.
+// В строгом режиме use cases отклоняют запрос с кодом SENSITIVE_DATA,
+// и значение не попадает в хранилище даже в замаскированном виде.
+type SensitiveDataPolicy struct {
+	Strict bool
+}
+
+// Check добавляет в invalid ошибку для каждого значения с PAN/IBAN.
+// value - строка либо metadata (map/slice проверяются рекурсивно,
+// пути полей как у запроса: "metadata[card]", "metadata[items][0]").
+// Без строгого режима ничего не делает.
+func (p SensitiveDataPolicy) Check(invalid *errors.ValidationErrors, field string, value interface{}) {
+	if !p.Strict {
+		return
+	}
+
+	switch v := value.(type) {
+	case string:
+		if kinds := valueobjects.SensitiveDataKinds(v); len(kinds) > 0 {
+			invalid.AddCode(field, errors.ValidationCodeSensitiveData,
+				fmt.Sprintf("must not contain %s", strings.Join(kinds, " or ")))
+		}
+	case map[string]interface{}:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			p.Check(invalid, field+"["+key+"]", v[key])
+		}
+	case []interface{}:
+		for i, item := range v {
+			p.Check(invalid, field+"["+strconv.Itoa(i)+"]", item)
+		}
+	}
+}
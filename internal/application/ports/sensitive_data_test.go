@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+func TestSensitiveDataPolicy_Check(t *testing.T) {
+	metadata := map[string]interface{}{
+		"order": "1234567812345678", // Luhn не проходит - обычный ID
+		"payer": map[string]interface{}{
+			"accounts": []interface{}{"ok", "DE89370400440532013000"},
+		},
+		"card": "4111111111111111",
+	}
+
+	var invalid errors.ValidationErrors
+	SensitiveDataPolicy{Strict: true}.Check(&invalid, "metadata", metadata)
+	SensitiveDataPolicy{Strict: true}.Check(&invalid, "external_reference", "pi_123")
+
+	if assert.Len(t, invalid, 2) {
+		assert.Equal(t, "metadata[card]", invalid[0].Field)
+		assert.Equal(t, "must not contain PAN", invalid[0].Message)
+		assert.Equal(t, "metadata[payer][accounts][1]", invalid[1].Field)
+		assert.Equal(t, errors.ValidationCodeSensitiveData, invalid[1].Code)
+	}
+
+	invalid = nil
+	SensitiveDataPolicy{}.Check(&invalid, "metadata", metadata)
+	assert.Empty(t, invalid, "without strict mode values are masked by the entity, not rejected")
+}
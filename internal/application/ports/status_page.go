@@ -0,0 +1,62 @@
+// Package ports - StatusPage: данные публичной страницы статуса.
+package ports
+
+import (
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// ServiceStatus - грубый статус компонента для клиентов.
+type ServiceStatus string
+
+const (
+	ServiceOperational ServiceStatus = "operational"
+	ServiceDegraded    ServiceStatus = "degraded"
+	ServiceDown        ServiceStatus = "down"
+)
+
+// Worse возвращает худший из двух статусов.
+func (s ServiceStatus) Worse(other ServiceStatus) ServiceStatus {
+	rank := func(status ServiceStatus) int {
+		switch status {
+		case ServiceDown:
+			return 2
+		case ServiceDegraded:
+			return 1
+		default:
+			return 0
+		}
+	}
+	if rank(other) > rank(s) {
+		return other
+	}
+	return s
+}
+
+// ComponentStatus - статус одного компонента страницы статуса.
+type ComponentStatus struct {
+	Component entities.StatusComponent
+	Status    ServiceStatus
+}
+
+// StatusSnapshot - состояние страницы статуса на момент последнего обновления.
+type StatusSnapshot struct {
+	Status     ServiceStatus // худший из статусов компонентов
+	Components []ComponentStatus
+	Incidents  []*entities.Incident
+
+	// Stale - последнее обновление не смогло прочитать хранилище:
+	// выключатели и инциденты - последние известные
+	Stale bool
+
+	// UpdatedAt - последнее успешное чтение хранилища (zero - ни одного)
+	UpdatedAt time.Time
+}
+
+// StatusPage отдаёт состояние страницы статуса из памяти, не обращаясь к
+// хранилищу: ответ должен переживать недоступность БД.
+type StatusPage interface {
+	// Snapshot возвращает последнее вычисленное состояние.
+	Snapshot() StatusSnapshot
+}
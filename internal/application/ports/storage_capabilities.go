@@ -0,0 +1,42 @@
+// Package ports - StorageCapabilities: чем хранилище отличается от PostgreSQL.
+package ports
+
+// StorageCapabilities описывает возможности хранилища репозиториев, на
+// которые опираются фоновые компоненты. Компонент, которому возможности
+// не хватает, переходит на работу в пределах процесса (и пишет об этом в
+// лог при старте), а не падает.
+//
+// PostgreSQL поддерживает всё. Standalone хранилище (storage.backend:
+// memory) - ничего: данные живут в памяти одного процесса.
+type StorageCapabilities struct {
+	// Durable - данные переживают перезапуск процесса.
+	Durable bool
+	// SkipLocked - выборка работы через FOR UPDATE SKIP LOCKED: несколько
+	// экземпляров делят outbox и processing-recovery без повторов.
+	SkipLocked bool
+	// AdvisoryLocks - блокировки между процессами (job_locks, advisory
+	// lease). Без них задачи планировщика и lease сериализуются
+	// in-process мьютексами.
+	AdvisoryLocks bool
+}
+
+// PostgresCapabilities - возможности PostgreSQL.
+var PostgresCapabilities = StorageCapabilities{Durable: true, SkipLocked: true, AdvisoryLocks: true}
+
+// MemoryCapabilities - возможности in-memory хранилища.
+var MemoryCapabilities = StorageCapabilities{}
+
+// Degraded перечисляет отсутствующие возможности (для лога при старте).
+func (c StorageCapabilities) Degraded() []string {
+	var missing []string
+	if !c.Durable {
+		missing = append(missing, "durable")
+	}
+	if !c.SkipLocked {
+		missing = append(missing, "skip_locked")
+	}
+	if !c.AdvisoryLocks {
+		missing = append(missing, "advisory_locks")
+	}
+	return missing
+}
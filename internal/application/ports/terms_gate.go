@@ -0,0 +1,18 @@
+// Package ports - TermsGate: принятие актуальной версии ToS перед движением средств.
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TermsGate не пропускает списания, переводы и выплаты пользователя,
+// не принявшего актуальную версию условий обслуживания.
+// Use cases считают nil gate отсутствием проверки.
+type TermsGate interface {
+	// RequireAccepted возвращает BusinessRuleViolation TERMS_ACCEPTANCE_REQUIRED
+	// (требуемая версия в context), если версия пользователя userID устарела.
+	// Вызывается внутри UnitOfWork до движения средств.
+	RequireAccepted(ctx context.Context, userID uuid.UUID) error
+}
@@ -0,0 +1,96 @@
+// Package ports - TransactionScreener: проверка транзакций правилами compliance.
+package ports
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// ScreeningFlagsMetadataKey - ключ metadata транзакции со списком сработавших
+// FLAG правил (ID через запятую).
+const ScreeningFlagsMetadataKey = "screening_flags"
+
+// ScreeningRule - правило скрининга в виде конфигурации / строки screening_rules.
+//
+// Условия объединяются через AND. Правило без условий не загружается.
+type ScreeningRule struct {
+	ID          string               `json:"id" mapstructure:"id"`
+	Description string               `json:"description" mapstructure:"description"`
+	Action      string               `json:"action" mapstructure:"action"` // BLOCK | FLAG
+	Conditions  []ScreeningCondition `json:"conditions" mapstructure:"conditions"`
+}
+
+// ScreeningCondition - одно условие правила: <attribute> <operator> <value>.
+//
+// Атрибуты: type, amount, wallet_age, kyc_status, currency, country.
+// Для in/not_in используется Values, для остальных операторов - Value.
+type ScreeningCondition struct {
+	Attribute string   `json:"attribute" mapstructure:"attribute"`
+	Operator  string   `json:"operator" mapstructure:"operator"`
+	Value     string   `json:"value,omitempty" mapstructure:"value"`
+	Values    []string `json:"values,omitempty" mapstructure:"values"`
+}
+
+// ScreeningRuleRepository - правила из таблицы screening_rules.
+type ScreeningRuleRepository interface {
+	// ListEnabled возвращает включённые правила в порядке ID.
+	ListEnabled(ctx context.Context) ([]ScreeningRule, error)
+}
+
+// ScreeningRequest - транзакция перед коммитом.
+type ScreeningRequest struct {
+	Transaction *entities.Transaction
+	Wallet      *entities.Wallet // Кошелёк транзакции (для TRANSFER - источник)
+}
+
+// ScreeningResult - решение по транзакции.
+type ScreeningResult struct {
+	BlockedBy string   // ID первого сработавшего BLOCK правила, "" - не заблокирована
+	Flags     []string // ID сработавших FLAG правил в порядке загрузки
+}
+
+// TransactionScreener проверяет транзакции правилами compliance до коммита.
+// Use cases считают nil screener отсутствием правил.
+type TransactionScreener interface {
+	Screen(ctx context.Context, req *ScreeningRequest) (*ScreeningResult, error)
+}
+
+// ScreenTransaction проверяет транзакцию и применяет решение.
+//
+//   - BLOCK: BusinessRuleViolation TRANSACTION_SCREENED с rule_id в контексте
+//   - FLAG: ID правил пишутся в metadata screening_flags, возвращается
+//     событие TransactionFlagged для публикации вместе с остальными
+//
+// Вызывается внутри UnitOfWork после создания транзакции и до движения средств.
+func ScreenTransaction(ctx context.Context, screener TransactionScreener, tx *entities.Transaction, wallet *entities.Wallet) ([]events.DomainEvent, error) {
+	if screener == nil {
+		return nil, nil
+	}
+
+	result, err := screener.Screen(ctx, &ScreeningRequest{Transaction: tx, Wallet: wallet})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.BlockedBy != "" {
+		return nil, errors.NewBusinessRuleViolation(
+			"TRANSACTION_SCREENED",
+			"transaction blocked by screening rule "+result.BlockedBy,
+			map[string]interface{}{"rule_id": result.BlockedBy},
+		)
+	}
+
+	if len(result.Flags) == 0 {
+		return nil, nil
+	}
+	if err := tx.AddMetadata(ScreeningFlagsMetadataKey, strings.Join(result.Flags, ",")); err != nil {
+		return nil, err
+	}
+	return []events.DomainEvent{
+		events.NewTransactionFlagged(tx.ID(), tx.WalletID(), string(tx.Type()), tx.Amount(), result.Flags),
+	}, nil
+}
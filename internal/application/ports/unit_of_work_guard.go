@@ -0,0 +1,100 @@
+// Package ports - UnitOfWorkGuard: детектор ошибок использования UnitOfWork.
+package ports
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// ErrNestedUnitOfWork - Execute вызван во время активного UnitOfWork того же
+// запроса с context без его транзакции (обычно захваченный внешний ctx).
+// Вложенные транзакции (savepoints) не поддерживаются: вместо второго
+// соединения Execute возвращает эту ошибку. Вызов с txCtx по-прежнему
+// выполняется в текущей транзакции.
+var ErrNestedUnitOfWork = errors.New("nested unit of work: Execute called with a context outside the active transaction")
+
+// unitOfWorkGuardKey - ключ UnitOfWorkGuard в context.
+type unitOfWorkGuardKey struct{}
+
+// UnitOfWorkGuard отслеживает активный UnitOfWork одного запроса.
+//
+// UnitOfWork отмечает в нём начало и конец транзакции, а хранилище
+// сообщает о каждом запросе (CheckQuery). Запрос без маркера транзакции
+// в context во время активного UnitOfWork - ошибка вызывающего кода: он
+// выполнится мимо транзакции и не откатится вместе с ней. В обычном режиме
+// такой запрос логируется со стеком, в strict режиме (storage.strict_uow,
+// CI) - паника, чтобы тест упал как под race detector.
+//
+// Кладётся в context на время обработки команды или запроса (см.
+// cqrs.UnitOfWorkGuardMiddleware); без него проверки не выполняются.
+// Методы безопасны для nil receiver. Guard не рассчитан на параллельные
+// UnitOfWork внутри одного запроса: каждой горутине нужен свой.
+type UnitOfWorkGuard struct {
+	active     atomic.Bool
+	violations atomic.Int64
+	strict     bool
+	logger     *slog.Logger
+}
+
+// NewUnitOfWorkGuard создаёт guard. logger nil - slog.Default().
+func NewUnitOfWorkGuard(logger *slog.Logger, strict bool) *UnitOfWorkGuard {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &UnitOfWorkGuard{strict: strict, logger: logger}
+}
+
+// WithUnitOfWorkGuard возвращает context с UnitOfWorkGuard.
+func WithUnitOfWorkGuard(ctx context.Context, guard *UnitOfWorkGuard) context.Context {
+	return context.WithValue(ctx, unitOfWorkGuardKey{}, guard)
+}
+
+// UnitOfWorkGuardFromContext извлекает UnitOfWorkGuard из context (nil если нет).
+func UnitOfWorkGuardFromContext(ctx context.Context) *UnitOfWorkGuard {
+	guard, _ := ctx.Value(unitOfWorkGuardKey{}).(*UnitOfWorkGuard)
+	return guard
+}
+
+// Enter отмечает начало UnitOfWork. false - UnitOfWork уже активен:
+// вызывающий должен вернуть ErrNestedUnitOfWork.
+func (g *UnitOfWorkGuard) Enter() bool {
+	if g == nil {
+		return true
+	}
+	return g.active.CompareAndSwap(false, true)
+}
+
+// Leave отмечает конец UnitOfWork (COMMIT или ROLLBACK).
+func (g *UnitOfWorkGuard) Leave() {
+	if g == nil {
+		return
+	}
+	g.active.Store(false)
+}
+
+// CheckQuery вызывается хранилищем на каждый запрос. inTx - в context есть
+// маркер транзакции UnitOfWork.
+func (g *UnitOfWorkGuard) CheckQuery(ctx context.Context, inTx bool) {
+	if g == nil || inTx || !g.active.Load() {
+		return
+	}
+
+	g.violations.Add(1)
+	if g.strict {
+		panic("unit of work: repository called with a context outside the active transaction")
+	}
+	g.logger.ErrorContext(ctx, "repository called with a context outside the active unit of work",
+		slog.String("stack", string(debug.Stack())),
+	)
+}
+
+// Violations возвращает число запросов мимо активной транзакции.
+func (g *UnitOfWorkGuard) Violations() int64 {
+	if g == nil {
+		return 0
+	}
+	return g.violations.Load()
+}
@@ -0,0 +1,49 @@
+// Package ports - WalletLimiter: ограничение параллельных мутаций кошелька.
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// WalletLimiter ограничивает число одновременных мутаций одного кошелька
+// в пределах процесса.
+//
+// Optimistic locking всё равно остаётся гарантией корректности: limiter лишь
+// не пускает в UnitOfWork запросы, которые почти наверняка проиграют конфликт
+// версий, и экономит им retry и round trips к БД.
+//
+// Контракт:
+//   - Acquire захватывает слоты всех переданных кошельков в порядке UUID
+//     (встречные переводы A→B и B→A не дают deadlock); дубликаты игнорируются
+//   - Если слот не освободился за время ожидания, возвращается DomainError
+//     WALLET_BUSY (HTTP 429) и ничего не остаётся захваченным
+//   - release освобождает все слоты; вызывается ровно один раз
+//   - Вызывается ДО UnitOfWork, чтобы ожидание не держало транзакцию БД
+type WalletLimiter interface {
+	Acquire(ctx context.Context, walletIDs ...uuid.UUID) (release func(), err error)
+}
+
+// AcquireWallets захватывает слоты limiter для кошельков из команды.
+//
+// nil limiter ничего не ограничивает. Невалидные UUID пропускаются - их
+// отклонит валидация внутри use case. Возвращаемый release никогда не nil.
+func AcquireWallets(ctx context.Context, limiter WalletLimiter, walletIDs ...string) (func(), error) {
+	noop := func() {}
+	if limiter == nil {
+		return noop, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(walletIDs))
+	for _, raw := range walletIDs {
+		if id, err := uuid.Parse(raw); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return noop, nil
+	}
+
+	return limiter.Acquire(ctx, ids...)
+}
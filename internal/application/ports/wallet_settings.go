@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// LoadWalletSettings возвращает настройки представления кошелька.
+// nil repo или кошелёк без настроек - nil без ошибки: методы
+// entities.WalletSettings на nil ничего не меняют.
+func LoadWalletSettings(ctx context.Context, repo WalletSettingsRepository, walletID uuid.UUID) (*entities.WalletSettings, error) {
+	if repo == nil {
+		return nil, nil
+	}
+
+	settings, err := repo.FindByWalletID(ctx, walletID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load wallet settings: %w", err)
+	}
+	return settings, nil
+}
+
+// CheckReservedMetadata отклоняет ключи metadata, которые заполняет сервис.
+func CheckReservedMetadata(invalid *errors.ValidationErrors, metadata map[string]interface{}) {
+	if _, ok := metadata[entities.WalletTagsMetadataKey]; ok {
+		invalid.AddCode("metadata."+entities.WalletTagsMetadataKey, errors.ValidationCodeInvalidValue, "key is reserved for wallet auto tags")
+	}
+}
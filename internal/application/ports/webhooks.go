@@ -0,0 +1,26 @@
+// Package ports - WebhookEventTypes: события, доступные для webhook подписок.
+package ports
+
+import (
+	"slices"
+
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// WebhookEventTypes - типы событий, которые доставляются webhook подписчикам.
+// Только события с известным владельцем: подписка получает события своего
+// пользователя. Подписка без типов получает все перечисленные.
+var WebhookEventTypes = []string{
+	events.EventTypeTransactionCreated,
+	events.EventTypeTransactionCompleted,
+	events.EventTypeTransactionFailed,
+	events.EventTypeWalletCreated,
+	events.EventTypeUserKYCApproved,
+	events.EventTypeUserKYCRejected,
+	events.EventTypeUserKYCRevoked,
+}
+
+// IsWebhookEventType проверяет, что события типа eventType доставляются webhooks.
+func IsWebhookEventType(eventType string) bool {
+	return slices.Contains(WebhookEventTypes, eventType)
+}
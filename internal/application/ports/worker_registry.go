@@ -0,0 +1,98 @@
+// Package ports - heartbeat фоновых компонентов (relay, сверки, задачи планировщика).
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// WorkerState - текущее состояние фонового компонента.
+type WorkerState string
+
+const (
+	WorkerRunning WorkerState = "RUNNING" // выполняется прогон
+	WorkerIdle    WorkerState = "IDLE"    // ждёт следующего прогона
+	WorkerStopped WorkerState = "STOPPED" // остановлен, heartbeat не ожидается
+)
+
+// WorkerStatus - последний heartbeat фонового компонента на экземпляре.
+type WorkerStatus struct {
+	Worker   string
+	Instance string
+	State    WorkerState
+
+	LastRunAt      *time.Time // начало последнего прогона
+	LastSuccessAt  *time.Time // окончание последнего успешного прогона
+	ItemsProcessed int        // элементов в последнем завершённом прогоне
+	LastError      string     // ошибка последнего прогона, пусто - успешен
+
+	LastHeartbeatAt time.Time
+	StaleAfter      time.Duration // 0 - не устаревает
+}
+
+// Stale - heartbeat не обновлялся дольше StaleAfter.
+// Остановленный компонент не считается устаревшим.
+func (s WorkerStatus) Stale(now time.Time) bool {
+	if s.StaleAfter <= 0 || s.State == WorkerStopped {
+		return false
+	}
+	return now.Sub(s.LastHeartbeatAt) > s.StaleAfter
+}
+
+// WorkerHeartbeat - отчёт фонового компонента о своих прогонах.
+//
+// Вызывается из цикла компонента, поэтому реализация не блокирует и не
+// обращается к БД.
+type WorkerHeartbeat interface {
+	// RunStarted - начат прогон (состояние RUNNING).
+	RunStarted()
+
+	// RunFinished - прогон завершён (состояние IDLE).
+	RunFinished(itemsProcessed int, err error)
+
+	// Beat - компонент жив, но прогон не выполнялся (например, задачу
+	// выполняет другой экземпляр).
+	Beat()
+
+	// Stopped - компонент остановлен (состояние STOPPED).
+	Stopped()
+}
+
+// WorkerHeartbeatRepository хранит heartbeat всех экземпляров
+// (таблица workers_heartbeat) для вида кластера.
+type WorkerHeartbeatRepository interface {
+	// Save записывает heartbeat экземпляра (UPSERT по worker + instance).
+	Save(ctx context.Context, statuses []WorkerStatus) error
+
+	// ListSince возвращает heartbeat, обновлённые не раньше since.
+	ListSince(ctx context.Context, since time.Time) ([]WorkerStatus, error)
+}
+
+// InstanceRepository - liveness экземпляров приложения (таблица instances).
+// Транзакции PROCESSING экземпляра без свежего heartbeat считаются
+// осиротевшими (см. ProcessingRecoveryRepository).
+type InstanceRepository interface {
+	// Heartbeat отмечает, что экземпляр жив на момент at (UPSERT).
+	Heartbeat(ctx context.Context, instance string, at time.Time) error
+}
+
+// WorkerMonitor - чтение heartbeat фоновых компонентов.
+type WorkerMonitor interface {
+	// Instance - идентификатор текущего экземпляра.
+	Instance() string
+
+	// Local возвращает heartbeat компонентов этого экземпляра.
+	Local() []WorkerStatus
+
+	// Workers возвращает heartbeat всех экземпляров: текущего - из памяти,
+	// остальных - из хранилища (если оно подключено).
+	Workers(ctx context.Context) ([]WorkerStatus, error)
+}
+
+// NoopWorkerHeartbeat - WorkerHeartbeat для компонента без реестра.
+type NoopWorkerHeartbeat struct{}
+
+func (NoopWorkerHeartbeat) RunStarted()            {}
+func (NoopWorkerHeartbeat) RunFinished(int, error) {}
+func (NoopWorkerHeartbeat) Beat()                  {}
+func (NoopWorkerHeartbeat) Stopped()               {}
@@ -0,0 +1,123 @@
+package screening
+
+import (
+	"math/big"
+	"slices"
+	"time"
+)
+
+// Facts - атрибуты проверяемой транзакции.
+type Facts struct {
+	Type      string        // entities.TransactionType
+	Amount    *big.Rat      // В валюте кошелька; nil - условия amount не выполняются
+	Currency  string        // Валюта кошелька
+	WalletAge time.Duration // Время с создания кошелька
+	KYCStatus string        // entities.KYCStatus владельца кошелька
+	Country   string        // Юрисдикция владельца, "" - неизвестна
+}
+
+// Decision - результат проверки.
+type Decision struct {
+	BlockedBy string   // Первое сработавшее BLOCK правило, "" - не заблокирована
+	Flags     []string // Сработавшие FLAG правила
+	Matched   []string // Все сработавшие правила в порядке загрузки
+}
+
+// Blocked сообщает, заблокирована ли транзакция.
+func (d Decision) Blocked() bool { return d.BlockedBy != "" }
+
+// Evaluate проверяет факты всеми правилами.
+//
+// Правила проверяются все и в порядке загрузки: блокирует первое
+// сработавшее BLOCK правило, FLAG правила собираются в Flags.
+// Чистая функция: без I/O и без зависимости от текущего времени.
+func (s *RuleSet) Evaluate(facts Facts) Decision {
+	var decision Decision
+	if s == nil {
+		return decision
+	}
+
+	for _, rule := range s.rules {
+		if !rule.matches(facts) {
+			continue
+		}
+		decision.Matched = append(decision.Matched, rule.ID())
+		switch rule.action {
+		case ActionBlock:
+			if decision.BlockedBy == "" {
+				decision.BlockedBy = rule.ID()
+			}
+		case ActionFlag:
+			decision.Flags = append(decision.Flags, rule.ID())
+		}
+	}
+	return decision
+}
+
+// matches - все условия правила выполняются (AND).
+func (r *Rule) matches(facts Facts) bool {
+	for i := range r.conditions {
+		if !r.conditions[i].matches(facts) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *condition) matches(facts Facts) bool {
+	switch c.attribute {
+	case AttributeAmount:
+		if facts.Amount == nil {
+			return false
+		}
+		return compare(c.operator, facts.Amount.Cmp(c.amount))
+	case AttributeWalletAge:
+		return compare(c.operator, cmpDuration(facts.WalletAge, c.age))
+	case AttributeType:
+		return c.matchEnum(facts.Type)
+	case AttributeKYCStatus:
+		return c.matchEnum(facts.KYCStatus)
+	case AttributeCurrency:
+		return c.matchEnum(facts.Currency)
+	case AttributeCountry:
+		return c.matchEnum(facts.Country)
+	}
+	return false
+}
+
+func (c *condition) matchEnum(value string) bool {
+	switch c.operator {
+	case OperatorEq, OperatorIn:
+		return slices.Contains(c.enum, value)
+	case OperatorNe, OperatorNotIn:
+		return !slices.Contains(c.enum, value)
+	}
+	return false
+}
+
+// compare применяет числовой оператор к результату Cmp (-1, 0, 1).
+func compare(operator Operator, cmp int) bool {
+	switch operator {
+	case OperatorEq:
+		return cmp == 0
+	case OperatorGt:
+		return cmp > 0
+	case OperatorGte:
+		return cmp >= 0
+	case OperatorLt:
+		return cmp < 0
+	case OperatorLte:
+		return cmp <= 0
+	}
+	return false
+}
+
+func cmpDuration(a, b time.Duration) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,274 @@
+// Package screening - правила compliance для транзакций (block / flag).
+//
+// Правила задаются данными (конфигурация и таблица screening_rules) и
+// компилируются при старте: неизвестный атрибут, оператор или значение -
+// ошибка запуска, а не тихо не срабатывающее правило.
+//
+// RuleSet.Evaluate - чистая функция от Facts без I/O; Service собирает Facts
+// из транзакции, кошелька и пользователя.
+package screening
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// Attribute - атрибут транзакции, доступный в условиях.
+type Attribute string
+
+const (
+	AttributeType      Attribute = "type"       // DEPOSIT, WITHDRAW, TRANSFER, ...
+	AttributeAmount    Attribute = "amount"     // Сумма в валюте кошелька (без конвертации)
+	AttributeWalletAge Attribute = "wallet_age" // Время с создания кошелька: "24h", "30m"
+	AttributeKYCStatus Attribute = "kyc_status" // UNVERIFIED, PENDING, VERIFIED, REJECTED
+	AttributeCurrency  Attribute = "currency"   // Валюта кошелька (ISO 4217)
+	AttributeCountry   Attribute = "country"    // Юрисдикция пользователя (ISO 3166-1 alpha-2)
+)
+
+// Operator - оператор сравнения.
+type Operator string
+
+const (
+	OperatorEq    Operator = "eq"
+	OperatorNe    Operator = "ne"
+	OperatorIn    Operator = "in"
+	OperatorNotIn Operator = "not_in"
+	OperatorGt    Operator = "gt"
+	OperatorGte   Operator = "gte"
+	OperatorLt    Operator = "lt"
+	OperatorLte   Operator = "lte"
+)
+
+// Action - что делать с транзакцией, подходящей под правило.
+type Action string
+
+const (
+	ActionBlock Action = "BLOCK" // Отклонить: TRANSACTION_SCREENED
+	ActionFlag  Action = "FLAG"  // Провести, пометить metadata и опубликовать TransactionFlagged
+)
+
+var (
+	enumOperators    = []Operator{OperatorEq, OperatorNe, OperatorIn, OperatorNotIn}
+	numericOperators = []Operator{OperatorEq, OperatorGt, OperatorGte, OperatorLt, OperatorLte}
+
+	// operators - допустимые операторы по атрибутам
+	operators = map[Attribute][]Operator{
+		AttributeType:      enumOperators,
+		AttributeKYCStatus: enumOperators,
+		AttributeCurrency:  enumOperators,
+		AttributeCountry:   enumOperators,
+		AttributeAmount:    numericOperators,
+		AttributeWalletAge: numericOperators,
+	}
+
+	ruleIDRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+)
+
+// ============================================
+// Compiled rules
+// ============================================
+
+// condition - скомпилированное условие. Заполнено одно из полей значения
+// в зависимости от атрибута.
+type condition struct {
+	attribute Attribute
+	operator  Operator
+	enum      []string      // type, kyc_status, currency, country (нормализованы)
+	amount    *big.Rat      // amount
+	age       time.Duration // wallet_age
+}
+
+// Rule - скомпилированное правило.
+type Rule struct {
+	definition ports.ScreeningRule // Нормализованное определение (для admin API)
+	action     Action
+	conditions []condition
+}
+
+// ID возвращает ID правила.
+func (r *Rule) ID() string { return r.definition.ID }
+
+// Action возвращает действие правила.
+func (r *Rule) Action() Action { return r.action }
+
+// RuleSet - набор правил в порядке загрузки. Thread-safe: не изменяется.
+type RuleSet struct {
+	rules []*Rule
+}
+
+// NewRuleSet валидирует и компилирует правила.
+// Ошибка указывает ID правила и номер условия.
+func NewRuleSet(definitions []ports.ScreeningRule) (*RuleSet, error) {
+	set := &RuleSet{rules: make([]*Rule, 0, len(definitions))}
+	seen := make(map[string]struct{}, len(definitions))
+
+	for i, def := range definitions {
+		rule, err := compileRule(def)
+		if err != nil {
+			if def.ID == "" {
+				return nil, fmt.Errorf("screening rule #%d: %w", i+1, err)
+			}
+			return nil, fmt.Errorf("screening rule %q: %w", def.ID, err)
+		}
+		if _, dup := seen[rule.ID()]; dup {
+			return nil, fmt.Errorf("screening rule %q: duplicate id", rule.ID())
+		}
+		seen[rule.ID()] = struct{}{}
+		set.rules = append(set.rules, rule)
+	}
+	return set, nil
+}
+
+// Len возвращает число правил.
+func (s *RuleSet) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.rules)
+}
+
+// Definitions возвращает нормализованные определения правил в порядке загрузки.
+func (s *RuleSet) Definitions() []ports.ScreeningRule {
+	if s == nil {
+		return nil
+	}
+	result := make([]ports.ScreeningRule, len(s.rules))
+	for i, rule := range s.rules {
+		result[i] = rule.definition
+	}
+	return result
+}
+
+func compileRule(def ports.ScreeningRule) (*Rule, error) {
+	id := strings.TrimSpace(def.ID)
+	if !ruleIDRegex.MatchString(id) {
+		return nil, fmt.Errorf("id must be 1-64 characters of letters, digits, '_', '.', '-'")
+	}
+
+	action := Action(strings.ToUpper(strings.TrimSpace(def.Action)))
+	if action != ActionBlock && action != ActionFlag {
+		return nil, fmt.Errorf("unknown action %q (want BLOCK or FLAG)", def.Action)
+	}
+
+	if len(def.Conditions) == 0 {
+		return nil, fmt.Errorf("at least one condition is required")
+	}
+
+	rule := &Rule{
+		definition: ports.ScreeningRule{
+			ID:          id,
+			Description: strings.TrimSpace(def.Description),
+			Action:      string(action),
+		},
+		action: action,
+	}
+	for i, raw := range def.Conditions {
+		cond, normalized, err := compileCondition(raw)
+		if err != nil {
+			return nil, fmt.Errorf("condition #%d: %w", i+1, err)
+		}
+		rule.conditions = append(rule.conditions, cond)
+		rule.definition.Conditions = append(rule.definition.Conditions, normalized)
+	}
+	return rule, nil
+}
+
+// compileCondition проверяет условие и возвращает его скомпилированную
+// и нормализованную формы.
+func compileCondition(raw ports.ScreeningCondition) (condition, ports.ScreeningCondition, error) {
+	attribute := Attribute(strings.ToLower(strings.TrimSpace(raw.Attribute)))
+	operator := Operator(strings.ToLower(strings.TrimSpace(raw.Operator)))
+
+	allowed, ok := operators[attribute]
+	if !ok {
+		return condition{}, raw, fmt.Errorf("unknown attribute %q", raw.Attribute)
+	}
+	if !slices.Contains(allowed, operator) {
+		return condition{}, raw, fmt.Errorf("operator %q is not supported for %s", raw.Operator, attribute)
+	}
+
+	cond := condition{attribute: attribute, operator: operator}
+	normalized := ports.ScreeningCondition{Attribute: string(attribute), Operator: string(operator)}
+
+	// Списки - только для in/not_in, одно значение - для остальных
+	values := raw.Values
+	if operator == OperatorIn || operator == OperatorNotIn {
+		if len(values) == 0 || raw.Value != "" {
+			return condition{}, raw, fmt.Errorf("operator %s requires values (a list)", operator)
+		}
+	} else {
+		if len(values) != 0 || strings.TrimSpace(raw.Value) == "" {
+			return condition{}, raw, fmt.Errorf("operator %s requires a single value", operator)
+		}
+		values = []string{raw.Value}
+	}
+
+	switch attribute {
+	case AttributeAmount:
+		amount, ok := new(big.Rat).SetString(strings.TrimSpace(values[0]))
+		if !ok || amount.Sign() < 0 {
+			return condition{}, raw, fmt.Errorf("amount must be a non-negative decimal, got %q", values[0])
+		}
+		cond.amount = amount
+		normalized.Value = strings.TrimSpace(values[0])
+
+	case AttributeWalletAge:
+		age, err := time.ParseDuration(strings.TrimSpace(values[0]))
+		if err != nil || age < 0 {
+			return condition{}, raw, fmt.Errorf("wallet_age must be a non-negative duration like 24h, got %q", values[0])
+		}
+		cond.age = age
+		normalized.Value = age.String()
+
+	default:
+		for _, v := range values {
+			value, err := normalizeEnum(attribute, v)
+			if err != nil {
+				return condition{}, raw, err
+			}
+			cond.enum = append(cond.enum, value)
+		}
+		if operator == OperatorIn || operator == OperatorNotIn {
+			normalized.Values = cond.enum
+		} else {
+			normalized.Value = cond.enum[0]
+		}
+	}
+	return cond, normalized, nil
+}
+
+// normalizeEnum проверяет значение перечислимого атрибута и приводит его к
+// каноническому виду (верхний регистр).
+func normalizeEnum(attribute Attribute, raw string) (string, error) {
+	value := strings.ToUpper(strings.TrimSpace(raw))
+
+	switch attribute {
+	case AttributeType:
+		if !entities.TransactionType(value).IsValid() {
+			return "", fmt.Errorf("unknown transaction type %q", raw)
+		}
+	case AttributeKYCStatus:
+		if !entities.KYCStatus(value).IsValid() {
+			return "", fmt.Errorf("unknown kyc status %q", raw)
+		}
+	case AttributeCurrency:
+		if _, err := valueobjects.NewCurrency(value); err != nil {
+			return "", fmt.Errorf("unknown currency %q", raw)
+		}
+	case AttributeCountry:
+		code, err := entities.NormalizeJurisdiction(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid country %q", raw)
+		}
+		value = code
+	}
+	return value, nil
+}
@@ -0,0 +1,205 @@
+package screening
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+func cond(attribute, operator, value string, values ...string) ports.ScreeningCondition {
+	return ports.ScreeningCondition{Attribute: attribute, Operator: operator, Value: value, Values: values}
+}
+
+func rule(id, action string, conditions ...ports.ScreeningCondition) ports.ScreeningRule {
+	return ports.ScreeningRule{ID: id, Action: action, Conditions: conditions}
+}
+
+func TestNewRuleSet_Validation(t *testing.T) {
+	tests := []struct {
+		name            string
+		rules           []ports.ScreeningRule
+		wantErrContains string
+	}{
+		{"EmptyID", []ports.ScreeningRule{rule("", "BLOCK", cond("amount", "gt", "1"))}, "screening rule #1: id must be"},
+		{"BadID", []ports.ScreeningRule{rule("big transfer", "BLOCK", cond("amount", "gt", "1"))}, "id must be"},
+		{"DuplicateID", []ports.ScreeningRule{
+			rule("r1", "BLOCK", cond("amount", "gt", "1")),
+			rule("r1", "FLAG", cond("amount", "gt", "2")),
+		}, "duplicate id"},
+		{"UnknownAction", []ports.ScreeningRule{rule("r1", "REVIEW", cond("amount", "gt", "1"))}, "unknown action"},
+		{"NoConditions", []ports.ScreeningRule{rule("r1", "FLAG")}, "at least one condition"},
+		{"UnknownAttribute", []ports.ScreeningRule{rule("r1", "FLAG", cond("ip", "eq", "1.2.3.4"))}, `unknown attribute "ip"`},
+		{"UnknownOperator", []ports.ScreeningRule{rule("r1", "FLAG", cond("amount", "between", "1"))}, `operator "between" is not supported`},
+		{"OrderingOnEnum", []ports.ScreeningRule{rule("r1", "FLAG", cond("currency", "gt", "USD"))}, "not supported for currency"},
+		{"InOnAmount", []ports.ScreeningRule{rule("r1", "FLAG", cond("amount", "in", "", "1", "2"))}, "not supported for amount"},
+		{"InWithoutValues", []ports.ScreeningRule{rule("r1", "FLAG", cond("country", "in", "RU"))}, "requires values"},
+		{"EqWithValues", []ports.ScreeningRule{rule("r1", "FLAG", cond("country", "eq", "", "RU"))}, "requires a single value"},
+		{"BadAmount", []ports.ScreeningRule{rule("r1", "FLAG", cond("amount", "gt", "ten"))}, "non-negative decimal"},
+		{"NegativeAmount", []ports.ScreeningRule{rule("r1", "FLAG", cond("amount", "gt", "-1"))}, "non-negative decimal"},
+		{"BadWalletAge", []ports.ScreeningRule{rule("r1", "FLAG", cond("wallet_age", "lt", "1 day"))}, "wallet_age must be"},
+		{"BadType", []ports.ScreeningRule{rule("r1", "FLAG", cond("type", "eq", "CHARGEBACK"))}, "unknown transaction type"},
+		{"BadKYC", []ports.ScreeningRule{rule("r1", "FLAG", cond("kyc_status", "eq", "MAYBE"))}, "unknown kyc status"},
+		{"BadCurrency", []ports.ScreeningRule{rule("r1", "FLAG", cond("currency", "eq", "XXX"))}, "unknown currency"},
+		{"BadCountry", []ports.ScreeningRule{rule("r1", "FLAG", cond("country", "not_in", "", "DE", "GER"))}, "invalid country"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRuleSet(tt.rules)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErrContains)
+		})
+	}
+}
+
+func TestNewRuleSet_Normalizes(t *testing.T) {
+	set, err := NewRuleSet([]ports.ScreeningRule{{
+		ID:          " new-wallet-large ",
+		Description: " Large withdrawals from fresh wallets ",
+		Action:      "block",
+		Conditions: []ports.ScreeningCondition{
+			cond(" Type ", "EQ", "withdraw"),
+			cond("wallet_age", "lt", "1440m"),
+			cond("country", "in", "", "de", " fr"),
+		},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, 1, set.Len())
+
+	assert.Equal(t, []ports.ScreeningRule{{
+		ID:          "new-wallet-large",
+		Description: "Large withdrawals from fresh wallets",
+		Action:      "BLOCK",
+		Conditions: []ports.ScreeningCondition{
+			{Attribute: "type", Operator: "eq", Value: "WITHDRAW"},
+			{Attribute: "wallet_age", Operator: "lt", Value: "24h0m0s"},
+			{Attribute: "country", Operator: "in", Values: []string{"DE", "FR"}},
+		},
+	}}, set.Definitions())
+}
+
+func TestRuleSet_Evaluate(t *testing.T) {
+	set, err := NewRuleSet([]ports.ScreeningRule{
+		rule("fresh-wallet-withdraw", "BLOCK",
+			cond("type", "eq", "WITHDRAW"),
+			cond("wallet_age", "lt", "24h"),
+			cond("amount", "gte", "500"),
+		),
+		rule("sanctioned-country", "BLOCK", cond("country", "in", "", "KP", "IR")),
+		rule("unverified-large", "FLAG",
+			cond("kyc_status", "ne", "VERIFIED"),
+			cond("amount", "gt", "1000"),
+		),
+		rule("large-transfer", "FLAG",
+			cond("type", "eq", "TRANSFER"),
+			cond("amount", "gt", "10000.50"),
+		),
+		rule("non-major-currency", "FLAG", cond("currency", "not_in", "", "USD", "EUR")),
+	})
+	require.NoError(t, err)
+
+	base := Facts{
+		Type:      "WITHDRAW",
+		Amount:    big.NewRat(100, 1),
+		Currency:  "USD",
+		WalletAge: 30 * 24 * time.Hour,
+		KYCStatus: "VERIFIED",
+		Country:   "DE",
+	}
+	with := func(change func(f *Facts)) Facts {
+		facts := base
+		change(&facts)
+		return facts
+	}
+
+	tests := []struct {
+		name      string
+		facts     Facts
+		blockedBy string
+		flags     []string
+	}{
+		{"Clean", base, "", nil},
+		{"FreshWalletLargeWithdraw", with(func(f *Facts) {
+			f.WalletAge = time.Hour
+			f.Amount = big.NewRat(500, 1)
+		}), "fresh-wallet-withdraw", nil},
+		{"FreshWalletSmallWithdraw", with(func(f *Facts) {
+			f.WalletAge = time.Hour
+			f.Amount = big.NewRat(49999, 100)
+		}), "", nil},
+		{"FreshWalletLargeDeposit", with(func(f *Facts) {
+			f.Type = "DEPOSIT"
+			f.WalletAge = time.Hour
+			f.Amount = big.NewRat(500, 1)
+		}), "", nil},
+		{"OldWalletLargeWithdraw", with(func(f *Facts) {
+			f.WalletAge = 24 * time.Hour
+			f.Amount = big.NewRat(500, 1)
+		}), "", nil},
+		{"SanctionedCountry", with(func(f *Facts) { f.Country = "IR" }), "sanctioned-country", nil},
+		{"UnknownCountryIsNotIn", with(func(f *Facts) { f.Country = "" }), "", nil},
+		{"UnverifiedLarge", with(func(f *Facts) {
+			f.KYCStatus = "PENDING"
+			f.Amount = big.NewRat(1001, 1)
+		}), "", []string{"unverified-large"}},
+		{"LargeTransferDecimalBoundary", with(func(f *Facts) {
+			f.Type = "TRANSFER"
+			f.Amount = big.NewRat(1000050, 100)
+		}), "", nil},
+		{"LargeTransferAboveBoundary", with(func(f *Facts) {
+			f.Type = "TRANSFER"
+			f.Amount = big.NewRat(1000051, 100)
+		}), "", []string{"large-transfer"}},
+		{"MultipleFlags", with(func(f *Facts) {
+			f.Type = "TRANSFER"
+			f.Amount = big.NewRat(20000, 1)
+			f.KYCStatus = "UNVERIFIED"
+			f.Currency = "GBP"
+		}), "", []string{"unverified-large", "large-transfer", "non-major-currency"}},
+		{"BlockAndFlag", with(func(f *Facts) {
+			f.Country = "KP"
+			f.Currency = "GBP"
+		}), "sanctioned-country", []string{"non-major-currency"}},
+		{"FirstBlockWins", with(func(f *Facts) {
+			f.Country = "KP"
+			f.WalletAge = time.Minute
+			f.Amount = big.NewRat(600, 1)
+		}), "fresh-wallet-withdraw", nil},
+		{"NilAmountNeverMatchesAmount", with(func(f *Facts) {
+			f.WalletAge = time.Hour
+			f.Amount = nil
+		}), "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := set.Evaluate(tt.facts)
+			assert.Equal(t, tt.blockedBy, decision.BlockedBy)
+			assert.Equal(t, tt.blockedBy != "", decision.Blocked())
+			assert.Equal(t, tt.flags, decision.Flags)
+		})
+	}
+}
+
+func TestRuleSet_EvaluateMatchedKeepsLoadOrder(t *testing.T) {
+	set, err := NewRuleSet([]ports.ScreeningRule{
+		rule("b-flag", "FLAG", cond("currency", "eq", "USD")),
+		rule("a-block", "BLOCK", cond("amount", "gt", "0")),
+	})
+	require.NoError(t, err)
+
+	decision := set.Evaluate(Facts{Currency: "USD", Amount: big.NewRat(1, 1)})
+	assert.Equal(t, []string{"b-flag", "a-block"}, decision.Matched)
+}
+
+func TestRuleSet_NilIsEmpty(t *testing.T) {
+	var set *RuleSet
+	assert.Equal(t, 0, set.Len())
+	assert.Nil(t, set.Definitions())
+	assert.False(t, set.Evaluate(Facts{}).Blocked())
+}
@@ -0,0 +1,73 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Service реализует ports.TransactionScreener поверх RuleSet.
+//
+// KYC статус и страна берутся из владельца кошелька; если у пользователя
+// не задана юрисдикция, используется юрисдикция кошелька.
+type Service struct {
+	rules    *RuleSet
+	userRepo ports.UserRepository
+	now      func() time.Time
+}
+
+// Compile-time check
+var _ ports.TransactionScreener = (*Service)(nil)
+
+// NewService создаёт Service.
+func NewService(rules *RuleSet, userRepo ports.UserRepository) *Service {
+	return &Service{
+		rules:    rules,
+		userRepo: userRepo,
+		now:      time.Now,
+	}
+}
+
+// Rules возвращает активный набор правил.
+func (s *Service) Rules() *RuleSet {
+	return s.rules
+}
+
+// Screen собирает Facts и проверяет транзакцию.
+func (s *Service) Screen(ctx context.Context, req *ports.ScreeningRequest) (*ports.ScreeningResult, error) {
+	if s.rules.Len() == 0 {
+		return &ports.ScreeningResult{}, nil
+	}
+
+	facts, err := s.facts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := s.rules.Evaluate(facts)
+	return &ports.ScreeningResult{BlockedBy: decision.BlockedBy, Flags: decision.Flags}, nil
+}
+
+func (s *Service) facts(ctx context.Context, req *ports.ScreeningRequest) (Facts, error) {
+	tx, wallet := req.Transaction, req.Wallet
+
+	facts := Facts{
+		Type:      string(tx.Type()),
+		Amount:    tx.Amount().Amount(),
+		Currency:  wallet.Currency().Code(),
+		WalletAge: s.now().Sub(wallet.CreatedAt()),
+		Country:   wallet.Jurisdiction(),
+	}
+
+	user, err := s.userRepo.FindByID(ctx, wallet.UserID())
+	if err != nil {
+		return Facts{}, fmt.Errorf("failed to load wallet owner for screening: %w", err)
+	}
+	facts.KYCStatus = string(user.KYCStatus())
+	if user.Jurisdiction() != "" {
+		facts.Country = user.Jurisdiction()
+	}
+	return facts, nil
+}
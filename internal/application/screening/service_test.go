@@ -0,0 +1,111 @@
+package screening
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+func newScreeningFixture(t *testing.T, rules ...ports.ScreeningRule) (*Service, *entities.User, *entities.Wallet) {
+	t.Helper()
+
+	set, err := NewRuleSet(rules)
+	require.NoError(t, err)
+
+	users := memory.NewUserRepository(memory.NewStore())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	user := entities.ReconstructUser(uuid.New(), "screened@example.com", "Screened User",
+		entities.KYCStatusPending, nil, "FR", "", entities.TermsAcceptance{}, now, now)
+	require.NoError(t, users.Save(context.Background(), user))
+
+	usd, err := valueobjects.NewCurrency("USD")
+	require.NoError(t, err)
+	zero, _ := valueobjects.NewMoneyFromInt(0, usd)
+	limit, _ := valueobjects.NewMoneyFromInt(10000, usd)
+	wallet := entities.ReconstructWallet(uuid.New(), user.ID(), usd, entities.WalletTypeFiat,
+		entities.WalletStatusActive, zero, zero, 0, limit, limit, "DE", nil, now.Add(-2*time.Hour), now)
+
+	service := NewService(set, users)
+	service.now = func() time.Time { return now }
+	return service, user, wallet
+}
+
+func newScreenedTransaction(t *testing.T, wallet *entities.Wallet, txType entities.TransactionType, amount string) *entities.Transaction {
+	t.Helper()
+
+	money, err := valueobjects.NewMoney(amount, wallet.Currency())
+	require.NoError(t, err)
+	tx, err := entities.NewTransaction(wallet.ID(), uuid.NewString(), txType, money, "screening")
+	require.NoError(t, err)
+	return tx
+}
+
+func TestService_Screen_UsesWalletAndOwnerFacts(t *testing.T) {
+	service, _, wallet := newScreeningFixture(t,
+		rule("fresh-wallet", "FLAG", cond("wallet_age", "lt", "3h")),
+		rule("owner-country", "FLAG", cond("country", "eq", "FR")),
+		rule("owner-kyc", "FLAG", cond("kyc_status", "eq", "PENDING")),
+		rule("big-withdraw", "BLOCK", cond("type", "eq", "WITHDRAW"), cond("amount", "gt", "100")),
+	)
+
+	result, err := service.Screen(context.Background(), &ports.ScreeningRequest{
+		Transaction: newScreenedTransaction(t, wallet, entities.TransactionTypeDeposit, "500.00"),
+		Wallet:      wallet,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.BlockedBy)
+	assert.Equal(t, []string{"fresh-wallet", "owner-country", "owner-kyc"}, result.Flags)
+
+	result, err = service.Screen(context.Background(), &ports.ScreeningRequest{
+		Transaction: newScreenedTransaction(t, wallet, entities.TransactionTypeWithdraw, "100.01"),
+		Wallet:      wallet,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "big-withdraw", result.BlockedBy)
+}
+
+func TestService_Screen_FallsBackToWalletJurisdiction(t *testing.T) {
+	service, user, wallet := newScreeningFixture(t, rule("wallet-country", "FLAG", cond("country", "eq", "DE")))
+	owner := entities.ReconstructUser(user.ID(), user.Email(), user.FullName(), user.KYCStatus(),
+		nil, "", "", entities.TermsAcceptance{}, user.CreatedAt(), user.UpdatedAt())
+	require.NoError(t, service.userRepo.Save(context.Background(), owner))
+
+	result, err := service.Screen(context.Background(), &ports.ScreeningRequest{
+		Transaction: newScreenedTransaction(t, wallet, entities.TransactionTypeDeposit, "1.00"),
+		Wallet:      wallet,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"wallet-country"}, result.Flags)
+}
+
+func TestService_Screen_UnknownOwner(t *testing.T) {
+	service, _, wallet := newScreeningFixture(t, rule("any", "FLAG", cond("amount", "gt", "0")))
+	usd := wallet.Currency()
+	zero, _ := valueobjects.NewMoneyFromInt(0, usd)
+	orphan := entities.ReconstructWallet(uuid.New(), uuid.New(), usd, entities.WalletTypeFiat,
+		entities.WalletStatusActive, zero, zero, 0, zero, zero, "", nil, wallet.CreatedAt(), wallet.CreatedAt())
+
+	_, err := service.Screen(context.Background(), &ports.ScreeningRequest{
+		Transaction: newScreenedTransaction(t, orphan, entities.TransactionTypeDeposit, "1.00"),
+		Wallet:      orphan,
+	})
+	assert.ErrorContains(t, err, "failed to load wallet owner for screening")
+}
+
+func TestService_Screen_NoRulesSkipsLookup(t *testing.T) {
+	service := NewService(nil, nil)
+
+	result, err := service.Screen(context.Background(), &ports.ScreeningRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, result.BlockedBy)
+	assert.Empty(t, result.Flags)
+}
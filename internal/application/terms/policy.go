@@ -0,0 +1,100 @@
+// Package terms - принятие пользователем актуальной версии условий
+// обслуживания (ToS) перед движением средств.
+//
+// Версии ToS - положительные целые, растущие с каждой редакцией. Пока
+// принятая пользователем версия старше требуемой, списания, переводы и
+// выплаты отклоняются с TERMS_ACCEPTANCE_REQUIRED; пополнения и чтение не
+// затрагиваются. Пользователи, существовавшие до учёта ToS (grandfathered),
+// считаются принявшими GrandfatheredVersion, пока не примут ToS явно.
+package terms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// RuleAcceptanceRequired - правило, которым отклоняется движение средств.
+const RuleAcceptanceRequired = "TERMS_ACCEPTANCE_REQUIRED"
+
+// Policy - требуемая версия ToS (config terms.*).
+type Policy struct {
+	RequiredVersion      int // 0 - проверка выключена
+	GrandfatheredVersion int // Засчитывается пользователям, существовавшим до учёта ToS
+}
+
+// Validate проверяет согласованность версий.
+func (p Policy) Validate() error {
+	if p.RequiredVersion < 0 || p.GrandfatheredVersion < 0 {
+		return fmt.Errorf("terms versions must not be negative (required %d, grandfathered %d)", p.RequiredVersion, p.GrandfatheredVersion)
+	}
+	if p.GrandfatheredVersion > p.RequiredVersion {
+		return fmt.Errorf("grandfathered terms version %d is newer than required version %d", p.GrandfatheredVersion, p.RequiredVersion)
+	}
+	return nil
+}
+
+// AcceptedVersion возвращает версию, которую политика засчитывает пользователю.
+func (p Policy) AcceptedVersion(acceptance entities.TermsAcceptance) int {
+	if acceptance.Grandfathered && acceptance.Version < p.GrandfatheredVersion {
+		return p.GrandfatheredVersion
+	}
+	return acceptance.Version
+}
+
+// Check возвращает TERMS_ACCEPTANCE_REQUIRED (422) с требуемой версией
+// в context ошибки, если пользователь не принял актуальную версию.
+func (p Policy) Check(user *entities.User) error {
+	if p.RequiredVersion <= 0 {
+		return nil
+	}
+
+	accepted := p.AcceptedVersion(user.TermsAcceptance())
+	if accepted >= p.RequiredVersion {
+		return nil
+	}
+	return errors.NewBusinessRuleViolation(
+		RuleAcceptanceRequired,
+		fmt.Sprintf("terms of service version %d must be accepted", p.RequiredVersion),
+		map[string]interface{}{
+			"requiredVersion": p.RequiredVersion,
+			"acceptedVersion": accepted,
+		},
+	)
+}
+
+// Gate реализует ports.TermsGate.
+type Gate struct {
+	policy Policy
+	users  ports.UserRepository
+}
+
+// Compile-time check
+var _ ports.TermsGate = (*Gate)(nil)
+
+// NewGate проверяет политику и создаёт Gate.
+func NewGate(policy Policy, users ports.UserRepository) (*Gate, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &Gate{policy: policy, users: users}, nil
+}
+
+// RequireAccepted загружает пользователя и применяет Policy.Check.
+// С выключенной проверкой не обращается к хранилищу.
+func (g *Gate) RequireAccepted(ctx context.Context, userID uuid.UUID) error {
+	if g.policy.RequiredVersion <= 0 {
+		return nil
+	}
+
+	user, err := g.users.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to load user for terms check: %w", err)
+	}
+	return g.policy.Check(user)
+}
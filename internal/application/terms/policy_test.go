@@ -0,0 +1,75 @@
+package terms
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+func newUser(t *testing.T, users *memory.UserRepository, acceptance entities.TermsAcceptance) uuid.UUID {
+	t.Helper()
+
+	user := entities.ReconstructUser(uuid.New(), uuid.NewString()+"@example.com", "Terms User",
+		entities.KYCStatusVerified, nil, "", "", acceptance, time.Now(), time.Now())
+	require.NoError(t, users.Save(context.Background(), user))
+	return user.ID()
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	assert.NoError(t, Policy{}.Validate())
+	assert.NoError(t, Policy{RequiredVersion: 3, GrandfatheredVersion: 3}.Validate())
+	assert.Error(t, Policy{RequiredVersion: -1}.Validate())
+	assert.Error(t, Policy{RequiredVersion: 2, GrandfatheredVersion: 3}.Validate())
+}
+
+func TestGate_RequireAccepted(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository(memory.NewStore())
+
+	now := time.Now()
+	never := newUser(t, users, entities.TermsAcceptance{})
+	current := newUser(t, users, entities.TermsAcceptance{Version: 2, AcceptedAt: &now})
+	outdated := newUser(t, users, entities.TermsAcceptance{Version: 1, AcceptedAt: &now})
+	legacy := newUser(t, users, entities.TermsAcceptance{Grandfathered: true})
+
+	gate, err := NewGate(Policy{RequiredVersion: 2, GrandfatheredVersion: 2}, users)
+	require.NoError(t, err)
+
+	assert.NoError(t, gate.RequireAccepted(ctx, current))
+	assert.NoError(t, gate.RequireAccepted(ctx, legacy), "grandfathered user counts as accepting version 2")
+
+	for name, userID := range map[string]uuid.UUID{"never": never, "outdated": outdated} {
+		err := gate.RequireAccepted(ctx, userID)
+
+		var violation *errors.BusinessRuleViolation
+		require.True(t, stdErrors.As(err, &violation), "%s: expected BusinessRuleViolation, got %v", name, err)
+		assert.Equal(t, RuleAcceptanceRequired, violation.Rule)
+		assert.Equal(t, 2, violation.Context["requiredVersion"])
+	}
+
+	// Новая редакция: grandfathered версия больше не актуальна
+	gate, err = NewGate(Policy{RequiredVersion: 3, GrandfatheredVersion: 2}, users)
+	require.NoError(t, err)
+	assert.Error(t, gate.RequireAccepted(ctx, legacy))
+}
+
+func TestGate_Disabled_SkipsRepository(t *testing.T) {
+	gate, err := NewGate(Policy{}, nil)
+	require.NoError(t, err)
+
+	assert.NoError(t, gate.RequireAccepted(context.Background(), uuid.New()))
+}
+
+func TestNewGate_InvalidPolicy(t *testing.T) {
+	_, err := NewGate(Policy{RequiredVersion: 1, GrandfatheredVersion: 2}, nil)
+	assert.Error(t, err)
+}
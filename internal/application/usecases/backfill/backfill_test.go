@@ -0,0 +1,221 @@
+package backfill_test
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/usecases/backfill"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+type env struct {
+	store        *memory.Store
+	wallets      *memory.WalletRepository
+	transactions *memory.TransactionRepository
+	uc           *backfill.BackfillTransactionsUseCase
+}
+
+func newEnv() *env {
+	store := memory.NewStore()
+	transactions := memory.NewTransactionRepository(store)
+	return &env{
+		store:        store,
+		wallets:      memory.NewWalletRepository(store),
+		transactions: transactions,
+		uc:           backfill.NewBackfillTransactionsUseCase(transactions),
+	}
+}
+
+// saveWallet сохраняет USD кошелёк нового пользователя (баланс переносится отдельно, здесь 0).
+func (e *env) saveWallet(t *testing.T) *entities.Wallet {
+	t.Helper()
+	user, err := entities.NewUser(uuid.NewString()+"@example.com", "Legacy User")
+	require.NoError(t, err)
+	require.NoError(t, memory.NewUserRepository(e.store).Save(context.Background(), user))
+
+	usd, _ := valueobjects.NewCurrency("USD")
+	wallet, err := entities.NewWallet(user.ID(), usd)
+	require.NoError(t, err)
+	require.NoError(t, e.wallets.Save(context.Background(), wallet))
+	return wallet
+}
+
+var legacyTime = time.Date(2018, 6, 1, 9, 30, 0, 0, time.UTC)
+
+func record(txType entities.TransactionType, status entities.TransactionStatus, wallet *entities.Wallet, cents int64) dtos.BackfillTransactionRecord {
+	completedAt := legacyTime.Add(time.Minute)
+	r := dtos.BackfillTransactionRecord{
+		ID:           uuid.NewString(),
+		WalletID:     wallet.ID().String(),
+		Type:         string(txType),
+		Status:       string(status),
+		CurrencyCode: "USD",
+		AmountCents:  cents,
+		CreatedAt:    legacyTime,
+	}
+	if status == entities.TransactionStatusCompleted {
+		r.CompletedAt = &completedAt
+	}
+	return r
+}
+
+func transfer(from, to *entities.Wallet, cents, feeCents int64) dtos.BackfillTransactionRecord {
+	r := record(entities.TransactionTypeTransfer, entities.TransactionStatusCompleted, from, cents)
+	dest := to.ID().String()
+	r.DestinationWalletID = &dest
+	r.FeeCents = feeCents
+	return r
+}
+
+func TestBackfillTransactions_ImportsAsIs(t *testing.T) {
+	e := newEnv()
+	ctx := context.Background()
+	alice, bob := e.saveWallet(t), e.saveWallet(t)
+
+	deposit := record(entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, alice, 10000)
+	deposit.Metadata = map[string]interface{}{"legacy_batch": "B-17"}
+	failed := record(entities.TransactionTypeWithdraw, entities.TransactionStatusFailed, alice, 500)
+	failed.FailureReason = "INSUFFICIENT_BALANCE"
+	records := []dtos.BackfillTransactionRecord{deposit, transfer(alice, bob, 3000, 100), failed}
+
+	result, err := e.uc.Execute(ctx, dtos.BackfillTransactionsCommand{Records: records})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Imported)
+	assert.Equal(t, 0, result.Skipped)
+	assert.Equal(t, map[string]int64{
+		alice.ID().String(): 10000 - 3000,
+		bob.ID().String():   2900,
+	}, result.LedgerCents)
+
+	tx, err := e.transactions.FindByID(ctx, uuid.MustParse(deposit.ID))
+	require.NoError(t, err)
+	assert.Equal(t, entities.TransactionStatusCompleted, tx.Status())
+	assert.True(t, legacyTime.Equal(tx.CreatedAt()))
+	assert.Equal(t, "legacy-"+deposit.ID, tx.IdempotencyKey())
+	assert.Equal(t, entities.SourceLegacy, tx.Metadata()[entities.MetadataSource])
+	assert.Equal(t, "B-17", tx.Metadata()["legacy_batch"])
+
+	tx, err = e.transactions.FindByID(ctx, uuid.MustParse(failed.ID))
+	require.NoError(t, err)
+	assert.Equal(t, entities.FailureCategoryClient, tx.FailureCategory())
+
+	// Балансы переносятся отдельно: импорт их не трогает
+	wallet, err := e.wallets.FindByID(ctx, alice.ID())
+	require.NoError(t, err)
+	assert.True(t, wallet.AvailableBalance().IsZero())
+}
+
+func TestBackfillTransactions_RerunSkipsImported(t *testing.T) {
+	e := newEnv()
+	ctx := context.Background()
+	wallet := e.saveWallet(t)
+
+	first := []dtos.BackfillTransactionRecord{
+		record(entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, wallet, 700),
+		record(entities.TransactionTypeDeposit, entities.TransactionStatusCancelled, wallet, 300),
+	}
+	_, err := e.uc.Execute(ctx, dtos.BackfillTransactionsCommand{Records: first})
+	require.NoError(t, err)
+
+	// Прерванный запуск перезапущен с тем же файлом и продолжением
+	rerun := append(first, record(entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, wallet, 200))
+	result, err := e.uc.Execute(ctx, dtos.BackfillTransactionsCommand{Records: rerun})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 2, result.Skipped)
+	assert.Equal(t, map[string]int64{wallet.ID().String(): 900}, result.LedgerCents)
+}
+
+func TestBackfillTransactions_InvalidRecordRejectsBatch(t *testing.T) {
+	wallet := newEnv().saveWallet(t)
+
+	pending := record(entities.TransactionTypeDeposit, entities.TransactionStatusPending, wallet, 100)
+	unknownCurrency := record(entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, wallet, 100)
+	unknownCurrency.CurrencyCode = "XYZ"
+	negative := record(entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, wallet, -100)
+	exchange := record(entities.TransactionTypeExchange, entities.TransactionStatusCompleted, wallet, 100)
+	dest := uuid.NewString()
+	exchange.DestinationWalletID = &dest // без dest_amount ledger не посчитать
+	duplicate := record(entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, wallet, 100)
+
+	tests := map[string][]dtos.BackfillTransactionRecord{
+		"NotFinalStatus":  {pending},
+		"UnknownCurrency": {unknownCurrency},
+		"NegativeAmount":  {negative},
+		"ExchangeNoRate":  {exchange},
+		"DuplicateID":     {duplicate, duplicate},
+	}
+	for name, records := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := newEnv()
+			valid := record(entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, e.saveWallet(t), 100)
+
+			_, err := e.uc.Execute(context.Background(), dtos.BackfillTransactionsCommand{
+				Records: append([]dtos.BackfillTransactionRecord{valid}, records...),
+			})
+			require.Error(t, err)
+
+			_, err = e.transactions.FindByID(context.Background(), uuid.MustParse(valid.ID))
+			assert.True(t, domainErrors.IsNotFound(err), "batch must not be partially written")
+		})
+	}
+}
+
+func TestBackfillTransactions_UnknownWallet(t *testing.T) {
+	e := newEnv()
+	ghost := e.saveWallet(t)
+	e2 := newEnv()
+
+	_, err := e2.uc.Execute(context.Background(), dtos.BackfillTransactionsCommand{
+		Records: []dtos.BackfillTransactionRecord{record(entities.TransactionTypeDeposit, entities.TransactionStatusCompleted, ghost, 100)},
+	})
+	var domainErr *domainErrors.DomainError
+	require.True(t, stderrors.As(err, &domainErr), "expected DomainError, got %v", err)
+	assert.Equal(t, "WALLET_NOT_FOUND", domainErr.Code)
+}
+
+func TestReconciliation(t *testing.T) {
+	alice, bob, carol := uuid.NewString(), uuid.NewString(), uuid.NewString()
+
+	r := backfill.NewReconciliation()
+	r.Add(&dtos.BackfillBatchResultDTO{LedgerCents: map[string]int64{alice: 500, bob: 100}})
+	r.Add(&dtos.BackfillBatchResultDTO{LedgerCents: map[string]int64{alice: -200}})
+
+	t.Run("Matches", func(t *testing.T) {
+		report, err := r.Check([]dtos.BackfillExpectedBalance{
+			{WalletID: alice, BalanceCents: 300},
+			{WalletID: bob, BalanceCents: 100},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.Wallets)
+		assert.Empty(t, report.Mismatches)
+	})
+
+	t.Run("Mismatches", func(t *testing.T) {
+		report, err := r.Check([]dtos.BackfillExpectedBalance{
+			{WalletID: alice, BalanceCents: 300},
+			{WalletID: carol, BalanceCents: 50},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, report.Wallets)
+		assert.ElementsMatch(t, []dtos.BackfillMismatchDTO{
+			{WalletID: bob, ExpectedCents: 0, ImportedCents: 100},
+			{WalletID: carol, ExpectedCents: 50, ImportedCents: 0},
+		}, report.Mismatches)
+	})
+
+	t.Run("InvalidWalletID", func(t *testing.T) {
+		_, err := r.Check([]dtos.BackfillExpectedBalance{{WalletID: "nope"}})
+		assert.True(t, domainErrors.IsValidationError(err))
+	})
+}
@@ -0,0 +1,156 @@
+// Package backfill - перенос исторических транзакций из legacy системы.
+//
+// Транзакции загружаются в финальном статусе с исходными ID и timestamps,
+// минуя state machine (entities.ReconstructForImport): балансы кошельков
+// переносятся отдельно, поэтому импорт их не изменяет и событий не
+// публикует. Импортированные строки помечены metadata source=legacy.
+//
+// Импорт идёт пачками; повторный запуск пропускает уже загруженные ID,
+// поэтому прерванный перенос можно просто перезапустить. Итог сверяется
+// с ожидаемыми балансами (Reconciliation).
+package backfill
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/snapshot"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// BackfillTransactionsUseCase - импорт пачки исторических транзакций.
+//
+// Сценарий:
+// 1. Все записи пачки проверяются до записи (ReconstructForImport)
+// 2. ID, уже сохранённые прошлым запуском, пропускаются
+// 3. Остальные пишутся одной пачкой (InsertBatch, в PostgreSQL - COPY)
+// 4. Возвращается изменение ledger кошельков от всех записей пачки
+//
+// Пачка атомарна: невалидная запись, конфликт idempotency key или
+// несуществующий кошелёк - ошибка, и из пачки не записано ничего.
+type BackfillTransactionsUseCase struct {
+	transactionRepo ports.TransactionBackfillRepository
+}
+
+// NewBackfillTransactionsUseCase создаёт новый use case.
+func NewBackfillTransactionsUseCase(transactionRepo ports.TransactionBackfillRepository) *BackfillTransactionsUseCase {
+	return &BackfillTransactionsUseCase{transactionRepo: transactionRepo}
+}
+
+// Execute импортирует пачку записей.
+//
+// Errors:
+//   - ValidationError: Невалидная запись или ID повторяется в пачке
+//   - ErrDuplicateTransaction: Idempotency key уже занят другой транзакцией
+//   - WALLET_NOT_FOUND: Кошелёк записи не существует
+func (uc *BackfillTransactionsUseCase) Execute(ctx context.Context, cmd dtos.BackfillTransactionsCommand) (*dtos.BackfillBatchResultDTO, error) {
+	result := &dtos.BackfillBatchResultDTO{LedgerCents: map[string]int64{}}
+	if len(cmd.Records) == 0 {
+		return result, nil
+	}
+
+	transactions := make([]*entities.Transaction, 0, len(cmd.Records))
+	ids := make([]uuid.UUID, 0, len(cmd.Records))
+	seen := make(map[uuid.UUID]struct{}, len(cmd.Records))
+	for i, record := range cmd.Records {
+		tx, err := reconstructRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("records[%d]: %w", i, err)
+		}
+		if _, ok := seen[tx.ID()]; ok {
+			return nil, errors.ValidationError{
+				Field:   fmt.Sprintf("records[%d].id", i),
+				Message: fmt.Sprintf("transaction %s appears twice in the batch", tx.ID()),
+			}
+		}
+		seen[tx.ID()] = struct{}{}
+
+		deltas, err := snapshot.LedgerDeltas(tx)
+		if err != nil {
+			return nil, fmt.Errorf("records[%d]: %w", i, err)
+		}
+		for walletID, cents := range deltas {
+			result.LedgerCents[walletID.String()] += cents
+		}
+
+		transactions = append(transactions, tx)
+		ids = append(ids, tx.ID())
+	}
+
+	existing, err := uc.transactionRepo.ExistingIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check imported transactions: %w", err)
+	}
+
+	fresh := transactions[:0]
+	for _, tx := range transactions {
+		if _, ok := existing[tx.ID()]; ok {
+			result.Skipped++
+			continue
+		}
+		fresh = append(fresh, tx)
+	}
+
+	if len(fresh) > 0 {
+		if err := uc.transactionRepo.InsertBatch(ctx, fresh); err != nil {
+			return nil, fmt.Errorf("failed to import batch: %w", err)
+		}
+	}
+	result.Imported = len(fresh)
+
+	return result, nil
+}
+
+// reconstructRecord разбирает запись выгрузки в транзакцию.
+func reconstructRecord(record dtos.BackfillTransactionRecord) (*entities.Transaction, error) {
+	id, err := uuid.Parse(record.ID)
+	if err != nil {
+		return nil, invalidFormat("id", record.ID)
+	}
+	walletID, err := uuid.Parse(record.WalletID)
+	if err != nil {
+		return nil, invalidFormat("wallet_id", record.WalletID)
+	}
+	var destination *uuid.UUID
+	if record.DestinationWalletID != nil {
+		dest, err := uuid.Parse(*record.DestinationWalletID)
+		if err != nil {
+			return nil, invalidFormat("destination_wallet_id", *record.DestinationWalletID)
+		}
+		destination = &dest
+	}
+
+	return entities.ReconstructForImport(entities.TransactionImport{
+		ID:                  id,
+		WalletID:            walletID,
+		IdempotencyKey:      record.IdempotencyKey,
+		Type:                entities.TransactionType(record.Type),
+		Status:              entities.TransactionStatus(record.Status),
+		CurrencyCode:        record.CurrencyCode,
+		AmountCents:         record.AmountCents,
+		FeeCents:            record.FeeCents,
+		NetCents:            record.NetCents,
+		DestinationWalletID: destination,
+		ExternalReference:   record.ExternalReference,
+		Description:         record.Description,
+		Metadata:            record.Metadata,
+		FailureReason:       record.FailureReason,
+		Jurisdiction:        record.Jurisdiction,
+		CreatedAt:           record.CreatedAt,
+		UpdatedAt:           record.UpdatedAt,
+		CompletedAt:         record.CompletedAt,
+	})
+}
+
+func invalidFormat(field, value string) error {
+	return errors.ValidationError{
+		Field:   field,
+		Code:    errors.ValidationCodeInvalidFormat,
+		Message: fmt.Sprintf("invalid value %q", value),
+	}
+}
@@ -0,0 +1,85 @@
+package backfill
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Reconciliation накапливает ledger импортированных пачек и сверяет его
+// с итоговыми балансами legacy системы.
+//
+// Сверяется объединение кошельков: кошелёк без ожидаемого баланса ожидается
+// с нулём, кошелёк без импортированных транзакций - с нулевым ledger.
+// Записи, повторяющиеся в разных пачках одного запуска, учитываются
+// дважды и проявятся как расхождение.
+//
+// Не потокобезопасна: рассчитана на последовательный импорт.
+type Reconciliation struct {
+	ledger map[uuid.UUID]int64
+}
+
+// NewReconciliation создаёт пустую сверку.
+func NewReconciliation() *Reconciliation {
+	return &Reconciliation{ledger: make(map[uuid.UUID]int64)}
+}
+
+// Add учитывает результат импорта пачки.
+func (r *Reconciliation) Add(batch *dtos.BackfillBatchResultDTO) {
+	for walletID, cents := range batch.LedgerCents {
+		r.ledger[uuid.MustParse(walletID)] += cents
+	}
+}
+
+// Check сравнивает накопленный ledger с ожидаемыми балансами.
+// Расхождения отсортированы по wallet ID.
+//
+// Errors:
+//   - ValidationError: Невалидный или повторяющийся wallet ID в expected
+func (r *Reconciliation) Check(expected []dtos.BackfillExpectedBalance) (*dtos.BackfillReconciliationDTO, error) {
+	balances := make(map[uuid.UUID]int64, len(expected))
+	for i, balance := range expected {
+		id, err := uuid.Parse(balance.WalletID)
+		if err != nil {
+			return nil, errors.ValidationError{
+				Field:   fmt.Sprintf("expected[%d].wallet_id", i),
+				Code:    errors.ValidationCodeInvalidFormat,
+				Message: fmt.Sprintf("invalid value %q", balance.WalletID),
+			}
+		}
+		if _, ok := balances[id]; ok {
+			return nil, errors.ValidationError{
+				Field:   fmt.Sprintf("expected[%d].wallet_id", i),
+				Message: fmt.Sprintf("wallet %s appears twice", id),
+			}
+		}
+		balances[id] = balance.BalanceCents
+	}
+
+	wallets := make(map[uuid.UUID]struct{}, len(balances)+len(r.ledger))
+	for id := range balances {
+		wallets[id] = struct{}{}
+	}
+	for id := range r.ledger {
+		wallets[id] = struct{}{}
+	}
+
+	report := &dtos.BackfillReconciliationDTO{Wallets: len(wallets)}
+	for id := range wallets {
+		if balances[id] != r.ledger[id] {
+			report.Mismatches = append(report.Mismatches, dtos.BackfillMismatchDTO{
+				WalletID:      id.String(),
+				ExpectedCents: balances[id],
+				ImportedCents: r.ledger[id],
+			})
+		}
+	}
+	sort.Slice(report.Mismatches, func(i, j int) bool {
+		return report.Mismatches[i].WalletID < report.Mismatches[j].WalletID
+	})
+	return report, nil
+}
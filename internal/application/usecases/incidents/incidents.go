@@ -0,0 +1,132 @@
+// Package incidents - admin use cases отметок инцидентов публичной
+// страницы статуса: открытие и решение.
+package incidents
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Доступ только для admin/superadmin - проверяется в роутере (группа /admin).
+// Страница статуса перечитывает инциденты в фоне: изменение появляется на
+// GET /status в пределах интервала обновления (status_page.refresh_interval).
+
+// ============================================
+// Create
+// ============================================
+
+// CreateIncidentUseCase открывает инцидент.
+type CreateIncidentUseCase struct {
+	repo ports.IncidentRepository
+}
+
+// NewCreateIncidentUseCase создаёт новый use case.
+func NewCreateIncidentUseCase(repo ports.IncidentRepository) *CreateIncidentUseCase {
+	return &CreateIncidentUseCase{repo: repo}
+}
+
+// Execute сохраняет новый активный инцидент.
+//
+// Errors:
+//   - ACTOR_REQUIRED: В context нет инициатора
+//   - ValidationError: Пустой или длинный заголовок, неизвестная severity
+//     или компонент, начало в будущем
+func (uc *CreateIncidentUseCase) Execute(ctx context.Context, cmd dtos.CreateIncidentCommand) (*dtos.IncidentDTO, error) {
+	actor, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "incident requires an authenticated actor", nil)
+	}
+
+	components := make([]entities.StatusComponent, len(cmd.Components))
+	for i, component := range cmd.Components {
+		components[i] = entities.StatusComponent(component)
+	}
+
+	var startedAt time.Time
+	if cmd.StartedAt != nil {
+		startedAt = *cmd.StartedAt
+	}
+
+	incident, err := entities.NewIncident(cmd.Title, entities.IncidentSeverity(cmd.Severity), components, startedAt, actor.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Save(ctx, incident); err != nil {
+		return nil, fmt.Errorf("failed to save incident: %w", err)
+	}
+
+	dto := dtos.ToIncidentDTO(incident)
+	return &dto, nil
+}
+
+// ============================================
+// Resolve
+// ============================================
+
+// ResolveIncidentUseCase отмечает инцидент решённым. Решённый инцидент
+// остаётся на странице статуса entities.IncidentResolvedVisibility.
+type ResolveIncidentUseCase struct {
+	repo ports.IncidentRepository
+	uow  ports.UnitOfWork
+}
+
+// NewResolveIncidentUseCase создаёт новый use case.
+func NewResolveIncidentUseCase(repo ports.IncidentRepository, uow ports.UnitOfWork) *ResolveIncidentUseCase {
+	return &ResolveIncidentUseCase{repo: repo, uow: uow}
+}
+
+// Execute решает инцидент и возвращает его новое состояние.
+//
+// Errors:
+//   - ACTOR_REQUIRED: В context нет инициатора
+//   - INCIDENT_NOT_FOUND: Инцидента нет
+//   - INCIDENT_ALREADY_RESOLVED: Инцидент уже решён
+func (uc *ResolveIncidentUseCase) Execute(ctx context.Context, cmd dtos.ResolveIncidentCommand) (*dtos.IncidentDTO, error) {
+	actor, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "incident requires an authenticated actor", nil)
+	}
+
+	incidentID, err := uuid.Parse(cmd.IncidentID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "incident_id", Message: "invalid UUID"}
+	}
+
+	var result *dtos.IncidentDTO
+
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		incident, err := uc.repo.FindByID(txCtx, incidentID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return errors.NewDomainError("INCIDENT_NOT_FOUND", "incident not found", err)
+			}
+			return fmt.Errorf("failed to load incident: %w", err)
+		}
+
+		if err := incident.Resolve(actor.ID); err != nil {
+			return err
+		}
+
+		if err := uc.repo.Save(txCtx, incident); err != nil {
+			return fmt.Errorf("failed to save incident: %w", err)
+		}
+
+		dto := dtos.ToIncidentDTO(incident)
+		result = &dto
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,92 @@
+package incidents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/incidents"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+func TestIncidentUseCases(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewIncidentRepository(store)
+	create := incidents.NewCreateIncidentUseCase(repo)
+	resolve := incidents.NewResolveIncidentUseCase(repo, memory.NewUnitOfWork(store))
+
+	actor := ports.Actor{ID: uuid.New(), Role: "admin"}
+	ctx := ports.WithActor(context.Background(), actor)
+
+	t.Run("CreateAndResolve", func(t *testing.T) {
+		startedAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+		created, err := create.Execute(ctx, dtos.CreateIncidentCommand{
+			Title:      "  Delayed payouts  ",
+			Severity:   "MAJOR",
+			Components: []string{"payouts", "payouts", "api"},
+			StartedAt:  &startedAt,
+		})
+		if err != nil {
+			t.Fatalf("Create error = %v", err)
+		}
+		if created.Title != "Delayed payouts" || created.CreatedBy != actor.ID.String() || !created.StartedAt.Equal(startedAt) {
+			t.Errorf("Unexpected incident: %+v", created)
+		}
+		if len(created.Components) != 2 {
+			t.Errorf("Expected duplicate component dropped, got %v", created.Components)
+		}
+
+		resolved, err := resolve.Execute(ctx, dtos.ResolveIncidentCommand{IncidentID: created.ID})
+		if err != nil {
+			t.Fatalf("Resolve error = %v", err)
+		}
+		if resolved.ResolvedAt == nil || resolved.ResolvedBy != actor.ID.String() {
+			t.Errorf("Expected resolved incident, got %+v", resolved)
+		}
+
+		_, err = resolve.Execute(ctx, dtos.ResolveIncidentCommand{IncidentID: created.ID})
+		var violation *domainErrors.BusinessRuleViolation
+		if !errors.As(err, &violation) || violation.Rule != "INCIDENT_ALREADY_RESOLVED" {
+			t.Errorf("Expected INCIDENT_ALREADY_RESOLVED, got %v", err)
+		}
+	})
+
+	t.Run("UnknownComponent", func(t *testing.T) {
+		_, err := create.Execute(ctx, dtos.CreateIncidentCommand{Title: "x", Severity: "MINOR", Components: []string{"ledger"}})
+		var validation domainErrors.ValidationError
+		if !errors.As(err, &validation) || validation.Field != "components" {
+			t.Errorf("Expected components ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("FutureStart", func(t *testing.T) {
+		future := time.Now().Add(time.Hour)
+		_, err := create.Execute(ctx, dtos.CreateIncidentCommand{Title: "x", Severity: "MINOR", Components: []string{"api"}, StartedAt: &future})
+		var validation domainErrors.ValidationError
+		if !errors.As(err, &validation) || validation.Field != "started_at" {
+			t.Errorf("Expected started_at ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("ResolveNotFound", func(t *testing.T) {
+		_, err := resolve.Execute(ctx, dtos.ResolveIncidentCommand{IncidentID: uuid.NewString()})
+		var domainErr *domainErrors.DomainError
+		if !errors.As(err, &domainErr) || domainErr.Code != "INCIDENT_NOT_FOUND" {
+			t.Errorf("Expected INCIDENT_NOT_FOUND, got %v", err)
+		}
+	})
+
+	t.Run("ActorRequired", func(t *testing.T) {
+		_, err := create.Execute(context.Background(), dtos.CreateIncidentCommand{Title: "x", Severity: "MINOR", Components: []string{"api"}})
+		var domainErr *domainErrors.DomainError
+		if !errors.As(err, &domainErr) || domainErr.Code != "ACTOR_REQUIRED" {
+			t.Errorf("Expected ACTOR_REQUIRED, got %v", err)
+		}
+	})
+}
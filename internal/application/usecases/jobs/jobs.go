@@ -0,0 +1,120 @@
+// Package jobs - admin use cases фоновых задач: список с историей прогонов
+// и ручной запуск.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// ============================================
+// List
+// ============================================
+
+// ListJobsUseCase возвращает зарегистрированные задачи и их последние прогоны.
+//
+// Доступ только для admin - проверяется в роутере (группа /admin).
+type ListJobsUseCase struct {
+	scheduler ports.JobScheduler // nil - планировщик выключен
+}
+
+// NewListJobsUseCase создаёт новый use case.
+func NewListJobsUseCase(scheduler ports.JobScheduler) *ListJobsUseCase {
+	return &ListJobsUseCase{scheduler: scheduler}
+}
+
+// Execute возвращает задачи в порядке регистрации.
+func (uc *ListJobsUseCase) Execute(ctx context.Context, _ dtos.ListJobsQuery) (*dtos.JobListDTO, error) {
+	result := &dtos.JobListDTO{Jobs: []dtos.JobDTO{}}
+	if uc.scheduler == nil {
+		return result, nil
+	}
+
+	infos, err := uc.scheduler.Jobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	result.Enabled = true
+	result.Jobs = make([]dtos.JobDTO, len(infos))
+	result.TotalCount = len(infos)
+	for i, info := range infos {
+		job := dtos.JobDTO{
+			Name:           info.Name,
+			Schedule:       info.Schedule,
+			TimeoutSeconds: int64(info.Timeout.Seconds()),
+			RecentRuns:     make([]dtos.JobRunDTO, len(info.RecentRuns)),
+		}
+		if info.NextRunAt != nil {
+			next := info.NextRunAt.UTC()
+			job.NextRunAt = &next
+		}
+		for j, run := range info.RecentRuns {
+			job.RecentRuns[j] = toJobRunDTO(run)
+		}
+		result.Jobs[i] = job
+	}
+
+	return result, nil
+}
+
+// ============================================
+// Run
+// ============================================
+
+// RunJobUseCase запускает задачу вне расписания.
+//
+// Прогон идёт в фоне: результат возвращается сразу со статусом RUNNING,
+// итог виден в списке задач. Задача, уже выполняемая любым экземпляром,
+// не запускается (BusinessRuleViolation JOB_RUNNING).
+type RunJobUseCase struct {
+	scheduler ports.JobScheduler // nil - планировщик выключен
+}
+
+// NewRunJobUseCase создаёт новый use case.
+func NewRunJobUseCase(scheduler ports.JobScheduler) *RunJobUseCase {
+	return &RunJobUseCase{scheduler: scheduler}
+}
+
+// Execute запускает задачу и возвращает начатый прогон.
+func (uc *RunJobUseCase) Execute(ctx context.Context, cmd dtos.RunJobCommand) (*dtos.JobRunDTO, error) {
+	name := strings.TrimSpace(cmd.Name)
+	if name == "" {
+		return nil, errors.ValidationError{Field: "name", Message: "job name is required"}
+	}
+	if uc.scheduler == nil {
+		return nil, fmt.Errorf("%w: job %s", errors.ErrEntityNotFound, name)
+	}
+
+	run, err := uc.scheduler.Trigger(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := toJobRunDTO(run)
+	return &result, nil
+}
+
+// toJobRunDTO конвертирует запись журнала прогонов в DTO.
+func toJobRunDTO(run *ports.JobRun) dtos.JobRunDTO {
+	dto := dtos.JobRunDTO{
+		ID:             run.ID.String(),
+		Job:            run.Job,
+		Trigger:        string(run.Trigger),
+		Instance:       run.Instance,
+		Status:         string(run.Status),
+		Error:          run.Error,
+		ItemsProcessed: run.ItemsProcessed,
+		StartedAt:      run.StartedAt.UTC(),
+	}
+	if run.FinishedAt != nil {
+		finishedAt := run.FinishedAt.UTC()
+		dto.FinishedAt = &finishedAt
+	}
+	return dto
+}
@@ -0,0 +1,91 @@
+package jobs_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/usecases/jobs"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/Haleralex/wallethub/internal/infrastructure/scheduler"
+)
+
+func newScheduler(t *testing.T, handler scheduler.Handler) *scheduler.Scheduler {
+	t.Helper()
+
+	s := scheduler.New(slog.New(slog.NewTextHandler(io.Discard, nil)), memory.NewJobRepository(memory.NewStore()), scheduler.Config{Instance: "test"})
+	if err := s.Register(scheduler.Job{Name: "outbox-cleanup", Schedule: "0 4 * * *", Timeout: time.Minute, Handler: handler}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	t.Cleanup(func() { _ = s.Stop(context.Background()) })
+	return s
+}
+
+func TestRunJobUseCase_RunsAndListsHistory(t *testing.T) {
+	ctx := context.Background()
+	s := newScheduler(t, func(ctx context.Context) (int, error) { return 42, nil })
+
+	run, err := jobs.NewRunJobUseCase(s).Execute(ctx, dtos.RunJobCommand{Name: "outbox-cleanup"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if run.Status != "RUNNING" || run.Trigger != "MANUAL" || run.Job != "outbox-cleanup" {
+		t.Errorf("Unexpected run: %+v", run)
+	}
+
+	var listed *dtos.JobListDTO
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		listed, err = jobs.NewListJobsUseCase(s).Execute(ctx, dtos.ListJobsQuery{})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if runs := listed.Jobs[0].RecentRuns; len(runs) == 1 && runs[0].Status != "RUNNING" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("job run did not finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if !listed.Enabled || listed.TotalCount != 1 {
+		t.Fatalf("Unexpected list: %+v", listed)
+	}
+	job := listed.Jobs[0]
+	if job.Schedule != "0 4 * * *" || job.TimeoutSeconds != 60 {
+		t.Errorf("Unexpected job: %+v", job)
+	}
+	got := job.RecentRuns[0]
+	if got.ID != run.ID || got.Status != "SUCCEEDED" || got.ItemsProcessed != 42 || got.FinishedAt == nil {
+		t.Errorf("Unexpected run: %+v", got)
+	}
+}
+
+func TestRunJobUseCase_UnknownJob(t *testing.T) {
+	s := newScheduler(t, func(ctx context.Context) (int, error) { return 0, nil })
+
+	_, err := jobs.NewRunJobUseCase(s).Execute(context.Background(), dtos.RunJobCommand{Name: "archival"})
+	if !domainErrors.IsNotFound(err) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+
+	_, err = jobs.NewRunJobUseCase(nil).Execute(context.Background(), dtos.RunJobCommand{Name: "archival"})
+	if !domainErrors.IsNotFound(err) {
+		t.Errorf("Expected not found with scheduler disabled, got %v", err)
+	}
+}
+
+func TestListJobsUseCase_Disabled(t *testing.T) {
+	result, err := jobs.NewListJobsUseCase(nil).Execute(context.Background(), dtos.ListJobsQuery{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Enabled || len(result.Jobs) != 0 {
+		t.Errorf("Expected empty disabled list, got %+v", result)
+	}
+}
@@ -0,0 +1,443 @@
+// Package onboarding - OnboardUser use case: готовый аккаунт для партнёра
+// одним запросом (пользователь, кошельки, начальные зачисления).
+package onboarding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/user"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+const (
+	// MaxWallets - максимум кошельков в одном onboarding.
+	MaxWallets = 10
+
+	// KYCAutoApprovalReason - причина в истории KYC при одобрении партнёром.
+	KYCAutoApprovalReason = "auto-approved at onboarding by trusted partner"
+
+	// initialCreditDescription - описание начального зачисления.
+	initialCreditDescription = "Initial credit (onboarding)"
+)
+
+// Policy - доверенные партнёры (config onboarding.trusted_partners).
+type Policy struct {
+	TrustedPartners []uuid.UUID // ID партнёров (subject токена); пусто - одобрение KYC недоступно
+}
+
+// isTrusted сообщает, может ли партнёр одобрять KYC при onboarding.
+func (p Policy) isTrusted(partnerID uuid.UUID) bool {
+	return slices.Contains(p.TrustedPartners, partnerID)
+}
+
+// InitialCreditKey возвращает idempotency_key начального зачисления:
+// UUIDv5 от валюты в пространстве ключа onboarding. Партнёр может найти
+// зачисление через GET /api/v1/transactions/by-key/:key, не сохраняя ответ.
+func InitialCreditKey(onboardingKey uuid.UUID, currencyCode string) uuid.UUID {
+	return uuid.NewSHA1(onboardingKey, []byte("initial-credit:"+currencyCode))
+}
+
+// OnboardUserUseCase - use case для onboarding пользователя партнёром.
+//
+// Сценарий (всё в одной UnitOfWork):
+// 1. Найти результат по idempotency_key - повтор возвращает его
+// 2. Создать пользователя (CreateUserUseCase)
+// 3. Одобрить KYC, если запрошено доверенным партнёром и пользователь
+// ещё не верифицирован
+// 4. Для каждой валюты создать кошелёк (CreateWalletUseCase) и провести
+// начальное зачисление (CreditWalletUseCase) с ключом InitialCreditKey
+// 5. Сохранить результат для повторов
+//
+// Вложенные use cases присоединяются к транзакции onboarding, поэтому
+// ошибка на любом шаге (например, закрытая валюта третьего кошелька)
+// откатывает пользователя, кошельки, зачисления и события.
+//
+// Бизнес-правила:
+// - Одобрение KYC требует и capability токена (TrustedPartnerScope), и
+// партнёра в Policy.TrustedPartners; переходы KYC пишутся в историю с
+// партнёром как инициатором и причиной KYCAutoApprovalReason
+// - Кошельки открываются только верифицированным пользователям: если новый
+// пользователь не верифицирован и KYC не одобрен, onboarding с кошельками
+// откатывается с USER_NOT_VERIFIED. Пока entities.NewUser создаёт
+// пользователей верифицированными, одобрение не требуется и не применяется
+// (kyc_auto_approved = false)
+// - Повтор ключа с другим запросом (другой партнёр или тело) - IDEMPOTENCY_KEY_REUSED
+type OnboardUserUseCase struct {
+	userRepo        ports.UserRepository
+	walletRepo      ports.WalletReader
+	transactionRepo ports.TransactionRepository
+	historyRepo     ports.KYCHistoryRepository
+	onboardingRepo  ports.OnboardingRepository
+	eventPublisher  ports.EventPublisher
+	uow             ports.UnitOfWork
+	createUser      *user.CreateUserUseCase
+	createWallet    *wallet.CreateWalletUseCase
+	creditWallet    *wallet.CreditWalletUseCase
+	policy          Policy
+}
+
+// NewOnboardUserUseCase создаёт новый use case.
+func NewOnboardUserUseCase(
+	userRepo ports.UserRepository,
+	walletRepo ports.WalletReader,
+	transactionRepo ports.TransactionRepository,
+	historyRepo ports.KYCHistoryRepository,
+	onboardingRepo ports.OnboardingRepository,
+	eventPublisher ports.EventPublisher,
+	uow ports.UnitOfWork,
+	createUser *user.CreateUserUseCase,
+	createWallet *wallet.CreateWalletUseCase,
+	creditWallet *wallet.CreditWalletUseCase,
+	policy Policy,
+) *OnboardUserUseCase {
+	return &OnboardUserUseCase{
+		userRepo:        userRepo,
+		walletRepo:      walletRepo,
+		transactionRepo: transactionRepo,
+		historyRepo:     historyRepo,
+		onboardingRepo:  onboardingRepo,
+		eventPublisher:  eventPublisher,
+		uow:             uow,
+		createUser:      createUser,
+		createWallet:    createWallet,
+		creditWallet:    creditWallet,
+		policy:          policy,
+	}
+}
+
+// Execute выполняет onboarding.
+//
+// Errors:
+//   - ACTOR_REQUIRED: В context нет партнёра
+//   - ValidationErrors: Ключ, число кошельков, валюты или суммы
+//   - KYC_AUTO_APPROVAL_FORBIDDEN: Одобрение KYC без доверенного партнёра
+//   - IDEMPOTENCY_KEY_REUSED: Ключ уже использован другим запросом
+//   - Ошибки вложенных use cases (EMAIL_ALREADY_EXISTS, CURRENCY_NOT_ALLOWED, ...)
+func (uc *OnboardUserUseCase) Execute(ctx context.Context, cmd dtos.OnboardUserCommand) (*dtos.OnboardingResultDTO, error) {
+	partner, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "onboarding requires an authenticated partner", nil)
+	}
+
+	// 1. Синхронные проверки команды: все ошибки сразу
+	key, currencies, err := uc.validate(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.AutoApproveKYC && !(cmd.TrustedPartnerScope && uc.policy.isTrusted(partner.ID)) {
+		return nil, errors.NewDomainError("KYC_AUTO_APPROVAL_FORBIDDEN", "KYC auto-approval requires the trusted partner scope", nil)
+	}
+
+	hash, err := requestHash(partner.ID, cmd, currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *dtos.OnboardingResultDTO
+
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		// 2. Проверка идемпотентности
+		existing, err := uc.onboardingRepo.FindByIdempotencyKey(txCtx, key)
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to check onboarding idempotency key: %w", err)
+		}
+		if existing != nil {
+			result, err = uc.replay(txCtx, existing, hash)
+			return err
+		}
+
+		result, err = uc.onboard(txCtx, partner.ID, key, hash, cmd, currencies)
+		return err
+	})
+
+	if err != nil {
+		// Гонка: параллельный запрос с тем же ключом закоммитился между
+		// проверкой идемпотентности и записью - отвечаем как на повтор
+		if existing, findErr := uc.onboardingRepo.FindByIdempotencyKey(ctx, key); findErr == nil {
+			return uc.replay(ctx, existing, hash)
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// validate проверяет команду и возвращает ключ и валюты кошельков.
+func (uc *OnboardUserUseCase) validate(cmd dtos.OnboardUserCommand) (uuid.UUID, []valueobjects.Currency, error) {
+	var invalid errors.ValidationErrors
+
+	key, err := uuid.Parse(cmd.IdempotencyKey)
+	if err != nil {
+		invalid.AddCode("idempotency_key", errors.ValidationCodeInvalidFormat, "invalid UUID format")
+	}
+
+	if len(cmd.Wallets) > MaxWallets {
+		invalid.AddCode("wallets", errors.ValidationCodeOutOfRange, fmt.Sprintf("at most %d wallets per onboarding", MaxWallets))
+	}
+
+	currencies := make([]valueobjects.Currency, 0, len(cmd.Wallets))
+	seen := make(map[string]bool, len(cmd.Wallets))
+	for i, item := range cmd.Wallets {
+		field := fmt.Sprintf("wallets[%d]", i)
+		currency, err := valueobjects.NewCurrency(item.CurrencyCode)
+		if err != nil {
+			invalid.AddCode(field+".currency_code", errors.ValidationCodeInvalidValue, fmt.Sprintf("invalid currency: %v", err))
+			continue
+		}
+		if seen[currency.Code()] {
+			invalid.AddCode(field+".currency_code", errors.ValidationCodeInvalidValue, "duplicate currency")
+		}
+		seen[currency.Code()] = true
+		currencies = append(currencies, currency)
+
+		if item.InitialCredit != "" {
+			amount, err := valueobjects.NewMoney(item.InitialCredit, currency)
+			if err != nil {
+				invalid.AddCode(field+".initial_credit", errors.ValidationCodeInvalidFormat, fmt.Sprintf("invalid amount: %v", err))
+			} else if !amount.IsPositive() {
+				invalid.AddCode(field+".initial_credit", errors.ValidationCodeOutOfRange, "must be positive")
+			}
+		}
+	}
+
+	if err := invalid.Err(); err != nil {
+		return uuid.Nil, nil, err
+	}
+	return key, currencies, nil
+}
+
+// onboard создаёт пользователя, кошельки и зачисления в транзакции txCtx.
+func (uc *OnboardUserUseCase) onboard(
+	txCtx context.Context,
+	partnerID, key uuid.UUID,
+	hash string,
+	cmd dtos.OnboardUserCommand,
+	currencies []valueobjects.Currency,
+) (*dtos.OnboardingResultDTO, error) {
+	// 3. Пользователь
+	created, err := uc.createUser.Execute(txCtx, dtos.CreateUserCommand{
+		Email:        cmd.Email,
+		FullName:     cmd.FullName,
+		Jurisdiction: cmd.Jurisdiction,
+	})
+	if err != nil {
+		return nil, err
+	}
+	userID, err := uuid.Parse(created.User.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid created user id: %w", err)
+	}
+
+	// 4. KYC
+	approved := false
+	if cmd.AutoApproveKYC {
+		if approved, err = uc.approveKYC(txCtx, userID, partnerID); err != nil {
+			return nil, err
+		}
+	}
+
+	record := &ports.OnboardingRecord{
+		IdempotencyKey:  key,
+		PartnerID:       partnerID,
+		RequestHash:     hash,
+		UserID:          userID,
+		KYCAutoApproved: approved,
+		CreatedAt:       clock.Now(),
+	}
+	result := &dtos.OnboardingResultDTO{
+		Wallets:         make([]dtos.WalletDTO, 0, len(currencies)),
+		Transactions:    make([]dtos.TransactionDTO, 0, len(currencies)),
+		KYCAutoApproved: approved,
+		CreatedAt:       record.CreatedAt,
+	}
+
+	// 5. Кошельки и начальные зачисления
+	for i, currency := range currencies {
+		w, err := uc.createWallet.Execute(txCtx, dtos.CreateWalletCommand{
+			UserID:       userID.String(),
+			CurrencyCode: currency.Code(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("wallets[%d]: %w", i, err)
+		}
+		walletID, err := uuid.Parse(w.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid created wallet id: %w", err)
+		}
+		record.WalletIDs = append(record.WalletIDs, walletID)
+
+		if cmd.Wallets[i].InitialCredit == "" {
+			result.Wallets = append(result.Wallets, *w)
+			continue
+		}
+
+		credited, err := uc.creditWallet.Execute(txCtx, dtos.CreditWalletCommand{
+			WalletID:       w.ID,
+			Amount:         cmd.Wallets[i].InitialCredit,
+			IdempotencyKey: InitialCreditKey(key, currency.Code()).String(),
+			Description:    initialCreditDescription,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("wallets[%d]: %w", i, err)
+		}
+		transactionID, err := uuid.Parse(credited.TransactionID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid initial credit transaction id: %w", err)
+		}
+		record.TransactionIDs = append(record.TransactionIDs, transactionID)
+		result.Wallets = append(result.Wallets, credited.Wallet)
+		result.Transactions = append(result.Transactions, credited.Transaction)
+	}
+
+	// 6. Результат для повторов
+	if err := uc.onboardingRepo.Save(txCtx, record); err != nil {
+		return nil, fmt.Errorf("failed to save onboarding: %w", err)
+	}
+
+	// Пользователь после KYC
+	u, err := uc.userRepo.FindByID(txCtx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	result.User = dtos.ToUserDTO(u)
+
+	return result, nil
+}
+
+// approveKYC одобряет KYC нового пользователя от имени партнёра.
+// false - пользователь уже верифицирован, одобрять нечего.
+//
+// Одобрить можно только PENDING, поэтому UNVERIFIED пользователь сначала
+// переводится в PENDING; каждый переход пишется в историю с партнёром
+// как инициатором и причиной KYCAutoApprovalReason.
+func (uc *OnboardUserUseCase) approveKYC(txCtx context.Context, userID, partnerID uuid.UUID) (bool, error) {
+	u, err := uc.userRepo.FindByID(txCtx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to load user: %w", err)
+	}
+	if u.IsVerified() {
+		return false, nil
+	}
+
+	var transitions []*entities.KYCTransition
+	if u.KYCStatus() != entities.KYCStatusPending {
+		from := u.KYCStatus()
+		if err := u.StartKYCVerification(); err != nil {
+			return false, err
+		}
+		transitions = append(transitions, entities.NewKYCTransition(userID, from, u.KYCStatus(), KYCAutoApprovalReason, partnerID))
+	}
+	from := u.KYCStatus()
+	if err := u.ApproveKYC(); err != nil {
+		return false, err
+	}
+	transitions = append(transitions, entities.NewKYCTransition(userID, from, u.KYCStatus(), KYCAutoApprovalReason, partnerID))
+
+	if err := uc.userRepo.Save(txCtx, u); err != nil {
+		return false, fmt.Errorf("failed to save user: %w", err)
+	}
+
+	for _, transition := range transitions {
+		if err := uc.historyRepo.Append(txCtx, transition); err != nil {
+			return false, fmt.Errorf("failed to append KYC history: %w", err)
+		}
+	}
+
+	event := events.NewUserKYCApproved(userID, partnerID)
+	if err := uc.eventPublisher.Publish(txCtx, event); err != nil {
+		return false, fmt.Errorf("failed to publish %s event: %w", event.EventType(), err)
+	}
+	return true, nil
+}
+
+// replay строит результат для уже обработанного ключа.
+func (uc *OnboardUserUseCase) replay(ctx context.Context, record *ports.OnboardingRecord, hash string) (*dtos.OnboardingResultDTO, error) {
+	if record.RequestHash != hash {
+		return nil, errors.NewBusinessRuleViolation(
+			"IDEMPOTENCY_KEY_REUSED",
+			"idempotency_key was already used for a different onboarding request",
+			map[string]interface{}{"idempotency_key": record.IdempotencyKey.String()},
+		)
+	}
+
+	u, err := uc.userRepo.FindByID(ctx, record.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	result := &dtos.OnboardingResultDTO{
+		User:             dtos.ToUserDTO(u),
+		Wallets:          make([]dtos.WalletDTO, 0, len(record.WalletIDs)),
+		Transactions:     make([]dtos.TransactionDTO, 0, len(record.TransactionIDs)),
+		KYCAutoApproved:  record.KYCAutoApproved,
+		CreatedAt:        record.CreatedAt,
+		IdempotentReplay: true,
+	}
+	for _, walletID := range record.WalletIDs {
+		w, err := uc.walletRepo.FindByID(ctx, walletID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load wallet: %w", err)
+		}
+		result.Wallets = append(result.Wallets, dtos.ToWalletDTO(w))
+	}
+	for _, transactionID := range record.TransactionIDs {
+		tx, err := uc.transactionRepo.FindByID(ctx, transactionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load transaction: %w", err)
+		}
+		result.Transactions = append(result.Transactions, dtos.ToTransactionDTO(tx))
+	}
+
+	return result, nil
+}
+
+// onboardingFingerprint - нормализованная команда для RequestHash.
+type onboardingFingerprint struct {
+	PartnerID      uuid.UUID                   `json:"partner_id"`
+	Email          string                      `json:"email"`
+	FullName       string                      `json:"full_name"`
+	Jurisdiction   string                      `json:"jurisdiction"`
+	AutoApproveKYC bool                        `json:"auto_approve_kyc"`
+	Wallets        []dtos.OnboardingWalletItem `json:"wallets"`
+}
+
+// requestHash возвращает SHA-256 нормализованной команды.
+func requestHash(partnerID uuid.UUID, cmd dtos.OnboardUserCommand, currencies []valueobjects.Currency) (string, error) {
+	fingerprint := onboardingFingerprint{
+		PartnerID:      partnerID,
+		Email:          strings.ToLower(strings.TrimSpace(cmd.Email)),
+		FullName:       strings.TrimSpace(cmd.FullName),
+		Jurisdiction:   strings.ToUpper(cmd.Jurisdiction),
+		AutoApproveKYC: cmd.AutoApproveKYC,
+		Wallets:        make([]dtos.OnboardingWalletItem, 0, len(currencies)),
+	}
+	for i, currency := range currencies {
+		fingerprint.Wallets = append(fingerprint.Wallets, dtos.OnboardingWalletItem{
+			CurrencyCode:  currency.Code(),
+			InitialCredit: cmd.Wallets[i].InitialCredit,
+		})
+	}
+
+	data, err := json.Marshal(fingerprint)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode onboarding request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
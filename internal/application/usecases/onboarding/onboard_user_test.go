@@ -0,0 +1,338 @@
+package onboarding_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/onboarding"
+	"github.com/Haleralex/wallethub/internal/application/usecases/user"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// unverifiedSignups сохраняет новых пользователей UNVERIFIED - как в
+// развёртывании, где регистрация не верифицирует автоматически.
+type unverifiedSignups struct {
+	*memory.UserRepository
+}
+
+func (r unverifiedSignups) Save(ctx context.Context, u *entities.User) error {
+	if _, err := r.FindByID(ctx, u.ID()); domainErrors.IsNotFound(err) && u.IsVerified() {
+		u = entities.ReconstructUser(u.ID(), u.Email(), u.FullName(), entities.KYCStatusUnverified,
+			u.TelegramID(), u.Jurisdiction(), u.LastKYCRejectionReason(), u.TermsAcceptance(), u.CreatedAt(), u.UpdatedAt())
+	}
+	return r.UserRepository.Save(ctx, u)
+}
+
+// onboardingFixture - onboarding поверх memory store с доверенным партнёром.
+type onboardingFixture struct {
+	users        ports.UserRepository
+	wallets      *memory.WalletRepository
+	transactions *memory.TransactionRepository
+	history      *memory.KYCHistoryRepository
+	onboardings  *memory.OnboardingRepository
+	publisher    *memory.EventPublisher
+	useCase      *onboarding.OnboardUserUseCase
+	partnerID    uuid.UUID
+}
+
+func newOnboardingFixture(t *testing.T, unverified bool, currencies ports.CurrencyPolicySource) *onboardingFixture {
+	t.Helper()
+
+	store := memory.NewStore()
+	f := &onboardingFixture{
+		users:        memory.NewUserRepository(store),
+		wallets:      memory.NewWalletRepository(store),
+		transactions: memory.NewTransactionRepository(store),
+		history:      memory.NewKYCHistoryRepository(store),
+		onboardings:  memory.NewOnboardingRepository(store),
+		publisher:    memory.NewEventPublisher(store),
+		partnerID:    uuid.New(),
+	}
+	if unverified {
+		f.users = unverifiedSignups{memory.NewUserRepository(store)}
+	}
+	uow := memory.NewUnitOfWork(store)
+
+	f.useCase = onboarding.NewOnboardUserUseCase(
+		f.users, f.wallets, f.transactions, f.history, f.onboardings, f.publisher, uow,
+		user.NewCreateUserUseCase(f.users, f.publisher, uow, nil),
+		wallet.NewCreateWalletUseCase(f.users, f.wallets, f.publisher, uow, currencies),
+		wallet.NewCreditWalletUseCase(f.wallets, f.transactions, f.publisher, uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil),
+		onboarding.Policy{TrustedPartners: []uuid.UUID{f.partnerID}},
+	)
+	return f
+}
+
+// partnerContext - context с партнёром как инициатором.
+func (f *onboardingFixture) partnerContext() context.Context {
+	return ports.WithActor(context.Background(), ports.Actor{ID: f.partnerID, Role: "user"})
+}
+
+func (f *onboardingFixture) command(key uuid.UUID, wallets ...dtos.OnboardingWalletItem) dtos.OnboardUserCommand {
+	return dtos.OnboardUserCommand{
+		IdempotencyKey:      key.String(),
+		Email:               "onboarded@example.com",
+		FullName:            "Onboarded User",
+		AutoApproveKYC:      true,
+		Wallets:             wallets,
+		TrustedPartnerScope: true,
+	}
+}
+
+// assertNothingPersisted проверяет, что onboarding не оставил следов.
+func (f *onboardingFixture) assertNothingPersisted(t *testing.T, key uuid.UUID, currencies ...string) {
+	t.Helper()
+	ctx := context.Background()
+
+	exists, err := f.users.ExistsByEmail(ctx, "onboarded@example.com")
+	require.NoError(t, err)
+	assert.False(t, exists, "user must be rolled back")
+
+	for _, currency := range currencies {
+		_, err := f.transactions.FindByIdempotencyKey(ctx, onboarding.InitialCreditKey(key, currency).String())
+		assert.True(t, domainErrors.IsNotFound(err), "%s initial credit must be rolled back: %v", currency, err)
+	}
+
+	_, err = f.onboardings.FindByIdempotencyKey(ctx, key)
+	assert.True(t, domainErrors.IsNotFound(err), "onboarding record must be rolled back: %v", err)
+	assert.Empty(t, f.publisher.Events(), "events must be rolled back")
+}
+
+func TestOnboardUserUseCase_FullPath(t *testing.T) {
+	f := newOnboardingFixture(t, true, nil)
+	key := uuid.New()
+
+	result, err := f.useCase.Execute(f.partnerContext(), f.command(key,
+		dtos.OnboardingWalletItem{CurrencyCode: "USD", InitialCredit: "100.00"},
+		dtos.OnboardingWalletItem{CurrencyCode: "eur"},
+		dtos.OnboardingWalletItem{CurrencyCode: "GBP", InitialCredit: "5.50"},
+	))
+	require.NoError(t, err)
+
+	assert.False(t, result.IdempotentReplay)
+	assert.True(t, result.KYCAutoApproved)
+	assert.Equal(t, string(entities.KYCStatusVerified), result.User.KYCStatus)
+
+	// Кошельки - в порядке запроса, с балансом после зачисления
+	require.Len(t, result.Wallets, 3)
+	assert.Equal(t, []string{"USD", "EUR", "GBP"}, []string{
+		result.Wallets[0].CurrencyCode, result.Wallets[1].CurrencyCode, result.Wallets[2].CurrencyCode,
+	})
+	assert.Equal(t, "100.00 USD", result.Wallets[0].AvailableBalance)
+	assert.Equal(t, "0.00 EUR", result.Wallets[1].AvailableBalance)
+	assert.Equal(t, "5.50 GBP", result.Wallets[2].AvailableBalance)
+	for _, w := range result.Wallets {
+		assert.Equal(t, result.User.ID, w.UserID)
+	}
+
+	// Зачисления с ключами, выведенными из ключа onboarding
+	require.Len(t, result.Transactions, 2)
+	assert.Equal(t, onboarding.InitialCreditKey(key, "USD").String(), result.Transactions[0].IdempotencyKey)
+	assert.Equal(t, onboarding.InitialCreditKey(key, "GBP").String(), result.Transactions[1].IdempotencyKey)
+	for i, tx := range result.Transactions {
+		assert.Equal(t, string(entities.TransactionTypeDeposit), tx.Type)
+		assert.Equal(t, string(entities.TransactionStatusCompleted), tx.Status)
+		assert.Equal(t, result.Wallets[i*2].ID, tx.WalletID)
+	}
+
+	// Одобрение KYC записано в историю от имени партнёра
+	userID := uuid.MustParse(result.User.ID)
+	history, err := f.history.ListByUserID(context.Background(), userID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, entities.KYCStatusUnverified, history[0].FromStatus())
+	assert.Equal(t, entities.KYCStatusPending, history[0].ToStatus())
+	assert.Equal(t, entities.KYCStatusPending, history[1].FromStatus())
+	assert.Equal(t, entities.KYCStatusVerified, history[1].ToStatus())
+	for _, transition := range history {
+		assert.Equal(t, f.partnerID, transition.ActorID())
+		assert.Equal(t, onboarding.KYCAutoApprovalReason, transition.Reason())
+	}
+
+	var approved *events.UserKYCApproved
+	for _, e := range f.publisher.Events() {
+		if e, ok := e.(*events.UserKYCApproved); ok {
+			approved = e
+		}
+	}
+	require.NotNil(t, approved)
+	assert.Equal(t, userID, approved.UserID)
+	assert.Equal(t, f.partnerID, approved.ActorID)
+}
+
+func TestOnboardUserUseCase_RollbackOnBadCurrencyMidList(t *testing.T) {
+	// EUR закрыт для новых кошельков: ошибка на втором кошельке из трёх
+	f := newOnboardingFixture(t, true, ports.CurrencyPolicy{WalletCurrencies: []string{"USD", "GBP"}})
+	key := uuid.New()
+
+	_, err := f.useCase.Execute(f.partnerContext(), f.command(key,
+		dtos.OnboardingWalletItem{CurrencyCode: "USD", InitialCredit: "100.00"},
+		dtos.OnboardingWalletItem{CurrencyCode: "EUR", InitialCredit: "10.00"},
+		dtos.OnboardingWalletItem{CurrencyCode: "GBP"},
+	))
+	var violation *domainErrors.BusinessRuleViolation
+	require.True(t, errors.As(err, &violation), "error = %v", err)
+	assert.Equal(t, "CURRENCY_NOT_ALLOWED", violation.Rule)
+
+	f.assertNothingPersisted(t, key, "USD", "EUR")
+
+	// Ключ не занят: исправленный запрос выполняется
+	result, err := f.useCase.Execute(f.partnerContext(), f.command(key,
+		dtos.OnboardingWalletItem{CurrencyCode: "USD", InitialCredit: "100.00"},
+		dtos.OnboardingWalletItem{CurrencyCode: "GBP"},
+	))
+	require.NoError(t, err)
+	assert.False(t, result.IdempotentReplay)
+	assert.Len(t, result.Wallets, 2)
+}
+
+func TestOnboardUserUseCase_Replay(t *testing.T) {
+	// Пользователи верифицируются при создании: одобрять KYC нечего
+	f := newOnboardingFixture(t, false, nil)
+	key := uuid.New()
+	cmd := f.command(key,
+		dtos.OnboardingWalletItem{CurrencyCode: "USD", InitialCredit: "25.00"},
+		dtos.OnboardingWalletItem{CurrencyCode: "EUR"},
+	)
+	cmd.AutoApproveKYC = false
+
+	first, err := f.useCase.Execute(f.partnerContext(), cmd)
+	require.NoError(t, err)
+	assert.False(t, first.KYCAutoApproved)
+	eventCount := len(f.publisher.Events())
+
+	// Повтор отдаёт исходный результат и ничего не создаёт
+	replay, err := f.useCase.Execute(f.partnerContext(), cmd)
+	require.NoError(t, err)
+	assert.True(t, replay.IdempotentReplay)
+	assert.Equal(t, first.User.ID, replay.User.ID)
+	require.Len(t, replay.Wallets, 2)
+	for i := range first.Wallets {
+		assert.Equal(t, first.Wallets[i].ID, replay.Wallets[i].ID)
+		assert.Equal(t, first.Wallets[i].AvailableBalance, replay.Wallets[i].AvailableBalance)
+	}
+	require.Len(t, replay.Transactions, 1)
+	assert.Equal(t, first.Transactions[0].ID, replay.Transactions[0].ID)
+	assert.True(t, first.CreatedAt.Equal(replay.CreatedAt))
+	assert.Len(t, f.publisher.Events(), eventCount)
+
+	// Тот же ключ с другим запросом или от другого партнёра отклоняется
+	changed := cmd
+	changed.Wallets = []dtos.OnboardingWalletItem{{CurrencyCode: "USD", InitialCredit: "2500.00"}}
+	otherPartner := ports.WithActor(context.Background(), ports.Actor{ID: uuid.New(), Role: "user"})
+	for name, call := range map[string]func() error{
+		"OtherBody": func() error {
+			_, err := f.useCase.Execute(f.partnerContext(), changed)
+			return err
+		},
+		"OtherPartner": func() error {
+			_, err := f.useCase.Execute(otherPartner, cmd)
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var violation *domainErrors.BusinessRuleViolation
+			err := call()
+			require.True(t, errors.As(err, &violation), "error = %v", err)
+			assert.Equal(t, "IDEMPOTENCY_KEY_REUSED", violation.Rule)
+		})
+	}
+	assert.Len(t, f.publisher.Events(), eventCount)
+}
+
+func TestOnboardUserUseCase_KYCAutoApprovalRequiresTrustedPartner(t *testing.T) {
+	f := newOnboardingFixture(t, true, nil)
+	stranger := ports.WithActor(context.Background(), ports.Actor{ID: uuid.New(), Role: "user"})
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		scope bool
+	}{
+		{"ListedPartnerWithoutScope", f.partnerContext(), false},
+		{"ScopeWithoutListedPartner", stranger, true},
+		{"Neither", stranger, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := uuid.New()
+			cmd := f.command(key, dtos.OnboardingWalletItem{CurrencyCode: "USD", InitialCredit: "1.00"})
+			cmd.TrustedPartnerScope = tt.scope
+
+			_, err := f.useCase.Execute(tt.ctx, cmd)
+			var domainErr *domainErrors.DomainError
+			require.True(t, errors.As(err, &domainErr), "error = %v", err)
+			assert.Equal(t, "KYC_AUTO_APPROVAL_FORBIDDEN", domainErr.Code)
+			f.assertNothingPersisted(t, key, "USD")
+		})
+	}
+
+	t.Run("UnverifiedUserWithoutApprovalCannotOpenWallets", func(t *testing.T) {
+		key := uuid.New()
+		cmd := f.command(key, dtos.OnboardingWalletItem{CurrencyCode: "USD"})
+		cmd.AutoApproveKYC = false
+
+		_, err := f.useCase.Execute(f.partnerContext(), cmd)
+		assert.ErrorIs(t, err, domainErrors.ErrUserNotVerified)
+		f.assertNothingPersisted(t, key)
+	})
+
+	t.Run("ActorRequired", func(t *testing.T) {
+		_, err := f.useCase.Execute(context.Background(), f.command(uuid.New()))
+		var domainErr *domainErrors.DomainError
+		require.True(t, errors.As(err, &domainErr), "error = %v", err)
+		assert.Equal(t, "ACTOR_REQUIRED", domainErr.Code)
+	})
+}
+
+func TestOnboardUserUseCase_Validation(t *testing.T) {
+	f := newOnboardingFixture(t, false, nil)
+
+	tooMany := make([]dtos.OnboardingWalletItem, onboarding.MaxWallets+1)
+	for i := range tooMany {
+		tooMany[i] = dtos.OnboardingWalletItem{CurrencyCode: "USD"}
+	}
+	tests := []struct {
+		name    string
+		key     string
+		wallets []dtos.OnboardingWalletItem
+		field   string
+	}{
+		{"InvalidKey", "not-a-uuid", nil, "idempotency_key"},
+		{"TooManyWallets", uuid.NewString(), tooMany, "wallets"},
+		{"UnknownCurrency", uuid.NewString(), []dtos.OnboardingWalletItem{{CurrencyCode: "USD"}, {CurrencyCode: "XYZ"}}, "wallets[1].currency_code"},
+		{"DuplicateCurrency", uuid.NewString(), []dtos.OnboardingWalletItem{{CurrencyCode: "USD"}, {CurrencyCode: "usd"}}, "wallets[1].currency_code"},
+		{"InvalidCredit", uuid.NewString(), []dtos.OnboardingWalletItem{{CurrencyCode: "USD", InitialCredit: "ten"}}, "wallets[0].initial_credit"},
+		{"NegativeCredit", uuid.NewString(), []dtos.OnboardingWalletItem{{CurrencyCode: "USD", InitialCredit: "-1.00"}}, "wallets[0].initial_credit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := dtos.OnboardUserCommand{
+				IdempotencyKey: tt.key,
+				Email:          "validation@example.com",
+				FullName:       "Validation User",
+				Wallets:        tt.wallets,
+			}
+			_, err := f.useCase.Execute(f.partnerContext(), cmd)
+
+			var invalid domainErrors.ValidationErrors
+			require.True(t, errors.As(err, &invalid), "error = %v", err)
+			fields := make([]string, 0, len(invalid))
+			for _, v := range invalid {
+				fields = append(fields, v.Field)
+			}
+			assert.Contains(t, fields, tt.field)
+		})
+	}
+}
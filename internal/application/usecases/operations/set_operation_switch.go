@@ -0,0 +1,81 @@
+// Package operations - admin use case аварийных выключателей операций.
+package operations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// SetOperationSwitchUseCase включает или выключает вид операций.
+//
+// Доступ только для admin/superadmin - проверяется в роутере (группа /admin).
+// Выключатель и событие OperationSwitchChanged (запись аудита) сохраняются
+// в одном UnitOfWork. После коммита кэш gate этого экземпляра сбрасывается,
+// остальные экземпляры увидят изменение в пределах своего интервала обновления.
+type SetOperationSwitchUseCase struct {
+	repo           ports.OperationSwitchRepository
+	gate           ports.OperationGate
+	eventPublisher ports.EventPublisher
+	uow            ports.UnitOfWork
+}
+
+// NewSetOperationSwitchUseCase создаёт новый use case.
+func NewSetOperationSwitchUseCase(
+	repo ports.OperationSwitchRepository,
+	gate ports.OperationGate,
+	eventPublisher ports.EventPublisher,
+	uow ports.UnitOfWork,
+) *SetOperationSwitchUseCase {
+	return &SetOperationSwitchUseCase{
+		repo:           repo,
+		gate:           gate,
+		eventPublisher: eventPublisher,
+		uow:            uow,
+	}
+}
+
+// Execute сохраняет состояние выключателя.
+//
+// Errors:
+//   - ACTOR_REQUIRED: В context нет инициатора
+//   - ValidationError: Неизвестный вид операций, выключение без причины
+//     или причина длиннее entities.MaxOperationSwitchReasonLength
+func (uc *SetOperationSwitchUseCase) Execute(ctx context.Context, cmd dtos.SetOperationSwitchCommand) (*dtos.OperationSwitchDTO, error) {
+	actor, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "operation switch requires an authenticated actor", nil)
+	}
+
+	sw, err := entities.NewOperationSwitch(entities.TransactionType(cmd.Type), cmd.Enabled, cmd.Reason, actor.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.Save(txCtx, sw); err != nil {
+			return fmt.Errorf("failed to save operation switch: %w", err)
+		}
+
+		event := events.NewOperationSwitchChanged(string(sw.Operation()), sw.Enabled(), sw.Reason(), actor.ID)
+		if err := uc.eventPublisher.Publish(txCtx, event); err != nil {
+			return fmt.Errorf("failed to publish OperationSwitchChanged event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.gate != nil {
+		uc.gate.Invalidate()
+	}
+
+	dto := dtos.ToOperationSwitchDTO(sw)
+	return &dto, nil
+}
@@ -0,0 +1,74 @@
+package operations_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/operations"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+func TestSetOperationSwitchUseCase(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewOperationSwitchRepository(store)
+	publisher := memory.NewEventPublisher(store)
+	useCase := operations.NewSetOperationSwitchUseCase(repo, nil, publisher, memory.NewUnitOfWork(store))
+
+	actor := ports.Actor{ID: uuid.New(), Role: "admin"}
+	ctx := ports.WithActor(context.Background(), actor)
+
+	t.Run("DisablePublishesAuditEvent", func(t *testing.T) {
+		result, err := useCase.Execute(ctx, dtos.SetOperationSwitchCommand{Type: "PAYOUT", Reason: "provider incident"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.Type != "PAYOUT" || result.Enabled || result.Reason != "provider incident" || result.UpdatedBy != actor.ID.String() {
+			t.Errorf("Unexpected switch: %+v", result)
+		}
+
+		stored, err := repo.FindAll(context.Background())
+		if err != nil || len(stored) != 1 || stored[0].Enabled() {
+			t.Fatalf("Expected one disabled switch, got %v (err %v)", stored, err)
+		}
+
+		published := publisher.Events()
+		if len(published) != 1 {
+			t.Fatalf("Expected 1 event, got %d", len(published))
+		}
+		changed, ok := published[0].(*events.OperationSwitchChanged)
+		if !ok || changed.Operation != "PAYOUT" || changed.Enabled || changed.Reason != "provider incident" || changed.ActorID != actor.ID {
+			t.Errorf("Unexpected event: %+v", published[0])
+		}
+	})
+
+	t.Run("DisableRequiresReason", func(t *testing.T) {
+		_, err := useCase.Execute(ctx, dtos.SetOperationSwitchCommand{Type: "DEPOSIT"})
+		var validation domainErrors.ValidationError
+		if !errors.As(err, &validation) || validation.Field != "reason" {
+			t.Errorf("Expected reason ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("UnknownOperation", func(t *testing.T) {
+		_, err := useCase.Execute(ctx, dtos.SetOperationSwitchCommand{Type: "FEE", Enabled: true})
+		var validation domainErrors.ValidationError
+		if !errors.As(err, &validation) || validation.Field != "type" {
+			t.Errorf("Expected type ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("ActorRequired", func(t *testing.T) {
+		_, err := useCase.Execute(context.Background(), dtos.SetOperationSwitchCommand{Type: "DEPOSIT", Enabled: true})
+		var domainErr *domainErrors.DomainError
+		if !errors.As(err, &domainErr) || domainErr.Code != "ACTOR_REQUIRED" {
+			t.Errorf("Expected ACTOR_REQUIRED, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+// Package readsession - use cases snapshot-сессий чтения.
+package readsession
+
+import (
+	"context"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// isolationRepeatableRead - уровень изоляции транзакции сессии.
+const isolationRepeatableRead = "REPEATABLE READ"
+
+// OpenReadSessionUseCase - use case открытия сессии чтения.
+//
+// Сессия принадлежит пользователю, который её открыл: токен не действует
+// в запросах другого пользователя. Лимиты числа сессий и TTL задаёт
+// реализация ports.ReadSessions.
+type OpenReadSessionUseCase struct {
+	sessions ports.ReadSessions
+
+	// now - источник времени (подменяется в тестах)
+	now func() time.Time
+}
+
+// NewOpenReadSessionUseCase создаёт новый use case.
+func NewOpenReadSessionUseCase(sessions ports.ReadSessions) *OpenReadSessionUseCase {
+	return &OpenReadSessionUseCase{sessions: sessions, now: time.Now}
+}
+
+// Execute открывает сессию чтения текущего пользователя.
+func (uc *OpenReadSessionUseCase) Execute(ctx context.Context, _ dtos.OpenReadSessionCommand) (*dtos.ReadSessionDTO, error) {
+	actor, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "read session requires an authenticated actor", nil)
+	}
+
+	// Клиент сессии - ID пользователя: по нему middleware сверяет владельца токена
+	session, err := uc.sessions.Open(ctx, actor.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.ReadSessionDTO{
+		Token:      session.Token,
+		ExpiresAt:  session.ExpiresAt,
+		TTLSeconds: int(session.ExpiresAt.Sub(uc.now()).Round(time.Second).Seconds()),
+		Isolation:  isolationRepeatableRead,
+		Source:     session.Source,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package readsession
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// fakeReadSessions запоминает клиентов открытых сессий.
+type fakeReadSessions struct {
+	clients []string
+	err     error
+	now     time.Time
+}
+
+func (f *fakeReadSessions) Open(_ context.Context, clientID string) (*ports.ReadSession, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.clients = append(f.clients, clientID)
+	return &ports.ReadSession{Token: "token", ExpiresAt: f.now.Add(time.Minute), Source: "replica"}, nil
+}
+
+func (f *fakeReadSessions) Attach(ctx context.Context, _, _ string) (context.Context, func(), error) {
+	return ctx, func() {}, nil
+}
+
+func TestOpenReadSessionUseCase(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	sessions := &fakeReadSessions{now: now}
+	uc := NewOpenReadSessionUseCase(sessions)
+	uc.now = func() time.Time { return now }
+
+	actorID := uuid.New()
+	ctx := ports.WithActor(context.Background(), ports.Actor{ID: actorID})
+
+	result, err := uc.Execute(ctx, dtos.OpenReadSessionCommand{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Token != "token" || result.TTLSeconds != 60 || result.Source != "replica" || result.Isolation != "REPEATABLE READ" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if len(sessions.clients) != 1 || sessions.clients[0] != actorID.String() {
+		t.Errorf("Session client = %v, want %s", sessions.clients, actorID)
+	}
+
+	// Без actor сессия не открывается
+	if _, err := uc.Execute(context.Background(), dtos.OpenReadSessionCommand{}); err == nil {
+		t.Error("Expected ACTOR_REQUIRED error")
+	}
+
+	// Ошибка лимита передаётся как есть
+	sessions.err = domainErrors.NewDomainError(ports.ReadSessionLimitCode, "limit", nil)
+	_, err = uc.Execute(ctx, dtos.OpenReadSessionCommand{})
+	var domainErr *domainErrors.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != ports.ReadSessionLimitCode {
+		t.Errorf("Expected %s, got %v", ports.ReadSessionLimitCode, err)
+	}
+}
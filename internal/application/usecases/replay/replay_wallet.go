@@ -0,0 +1,432 @@
+// Package replay - ReplayWallet use case: воспроизведение истории кошелька.
+//
+// В отличие от сверки (snapshot.ReconcileWallet), которая сравнивает сумму
+// ledger с балансом, replay строит кошелёк заново теми же доменными методами,
+// что и живая система (Credit, Debit, Lock/SweepAvailable/Close), и находит
+// строку истории, с которой она перестала сходиться. Только чтение.
+//
+// Холдов (резервов средств) в модели транзакций нет: Reserve/Release не
+// оставляют строк истории, поэтому pending воспроизводится нулём.
+package replay
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/snapshot"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// DefaultPageSize - сколько транзакций читается за один запрос к хранилищу.
+// В памяти держится только текущая страница.
+const DefaultPageSize = 1000
+
+// Итог воспроизведения (WalletReplayDTO.Outcome).
+const (
+	OutcomeConsistent   = "CONSISTENT"   // Сохранённый кошелёк совпал с историей
+	OutcomeDiverged     = "DIVERGED"     // Баланс или закрытие не сходятся с историей
+	OutcomeUnreplayable = "UNREPLAYABLE" // Историю не воспроизвести (см. Blocker)
+)
+
+// Причины остановки на строке (WalletReplayRowDTO.Reason).
+const (
+	// First divergence
+	ReasonOperationRejected = "OPERATION_REJECTED"    // Доменный метод отклонил операцию
+	ReasonSweepMismatch     = "SWEEP_AMOUNT_MISMATCH" // Sweep списал не весь воспроизведённый остаток
+
+	// Blocker
+	ReasonUnknownType         = "UNKNOWN_TRANSACTION_TYPE"   // Тип из более новой версии
+	ReasonUnknownStatus       = "UNKNOWN_TRANSACTION_STATUS" // Статус из более новой версии
+	ReasonMissingCounterparty = "MISSING_COUNTERPARTY_DATA"  // Нет получателя или зачисленной суммы обмена
+)
+
+// Поля расхождений (WalletReplayDifferenceDTO.Field).
+const (
+	FieldAvailableBalance = "available_balance"
+	FieldPendingBalance   = "pending_balance"
+	FieldBalanceVersion   = "balance_version"
+)
+
+// Смены статуса (WalletReplayStatusEventDTO).
+const (
+	EventWalletClosed = "WALLET_CLOSED"
+
+	StatusEventMissing = "missing"
+	StatusEventExtra   = "extra"
+)
+
+// ReplayWalletUseCase - use case для воспроизведения истории одного кошелька.
+//
+// Транзакции читаются хронологическими keyset-страницами (ListAfter), поэтому
+// память не зависит от длины истории. Применяются только COMPLETED строки,
+// по тем же правилам, что в use cases transaction/wallet:
+//   - DEPOSIT, REFUND, ADJUSTMENT: Credit(amount)
+//   - WITHDRAW, PAYOUT, FEE без родителя: Debit(net + fee)
+//   - FEE родительской транзакции: ничего (списан вместе с родителем)
+//   - TRANSFER: Debit(net + fee) источнику, Credit(net) получателю;
+//     sweep-транзакция: Lock, SweepAvailable, Close источника
+//   - EXCHANGE: Debit(amount) источнику, Credit(dest_amount) получателю
+//
+// balance_version показывается справочно и на итог не влияет: живая система
+// увеличивает её и при откатах провалившихся операций, которых нет среди
+// COMPLETED строк. Приостановка и блокировка кошелька в истории транзакций не
+// видны, поэтому из статусов сверяется только закрытие.
+//
+// Доступ только для admin - проверяется в роутере (группа /admin).
+type ReplayWalletUseCase struct {
+	walletRepo      ports.WalletReader
+	transactionRepo ports.TransactionReader
+	pageSize        int
+}
+
+// NewReplayWalletUseCase создаёт новый use case.
+func NewReplayWalletUseCase(walletRepo ports.WalletReader, transactionRepo ports.TransactionReader) *ReplayWalletUseCase {
+	return &ReplayWalletUseCase{
+		walletRepo:      walletRepo,
+		transactionRepo: transactionRepo,
+		pageSize:        DefaultPageSize,
+	}
+}
+
+// WithPageSize задаёт размер страницы чтения истории (<= 0 - DefaultPageSize).
+func (uc *ReplayWalletUseCase) WithPageSize(size int) *ReplayWalletUseCase {
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	uc.pageSize = size
+	return uc
+}
+
+// Execute воспроизводит историю кошелька и сравнивает результат с сохранённым.
+//
+// Errors:
+//   - ValidationError: Невалидный wallet ID
+//   - ErrEntityNotFound: Кошелёк не найден
+//   - ConcurrencyError: Кошелёк изменился во время воспроизведения
+func (uc *ReplayWalletUseCase) Execute(ctx context.Context, query dtos.ReplayWalletQuery) (*dtos.WalletReplayDTO, error) {
+	walletID, err := uuid.Parse(query.WalletID)
+	if err != nil {
+		return nil, errors.ValidationError{
+			Field:   "wallet_id",
+			Code:    errors.ValidationCodeInvalidFormat,
+			Message: "must be a valid UUID",
+		}
+	}
+
+	stored, err := uc.walletRepo.FindByID(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wallet %s: %w", walletID, err)
+	}
+
+	r := newReplayer(stored)
+	filter := ports.TransactionFilter{WalletID: &walletID}
+	var cursor *ports.TransactionCursor
+	for !r.stopped() {
+		page, err := uc.transactionRepo.ListAfter(ctx, filter, cursor, uc.pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list transactions of wallet %s: %w", walletID, err)
+		}
+		for _, tx := range page {
+			if r.apply(tx); r.stopped() {
+				break
+			}
+		}
+		if len(page) < uc.pageSize {
+			break
+		}
+		cursor = ports.CursorOf(page[len(page)-1])
+	}
+
+	// Операция, проведённая во время чтения, попала бы в историю, но не
+	// в загруженный кошелёк - такой отчёт показал бы ложное расхождение
+	current, err := uc.walletRepo.FindByID(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload wallet %s: %w", walletID, err)
+	}
+	if current.BalanceVersion() != stored.BalanceVersion() || current.Status() != stored.Status() {
+		return nil, errors.NewConcurrencyError(
+			"Wallet",
+			walletID.String(),
+			"wallet was modified during replay, run it again",
+		)
+	}
+
+	return r.report(stored), nil
+}
+
+// ============================================
+// Replayer
+// ============================================
+
+// replayer применяет строки истории к заново построенному кошельку.
+type replayer struct {
+	walletID uuid.UUID
+	wallet   *entities.Wallet
+
+	read    int
+	applied int
+	sweep   *entities.Transaction // Sweep-транзакция, закрывшая кошелёк
+
+	divergence *dtos.WalletReplayRowDTO
+	blocker    *dtos.WalletReplayRowDTO
+}
+
+// rowIssue - почему строку не удалось применить.
+type rowIssue struct {
+	reason   string
+	detail   string
+	blocking bool // Строку не умеем воспроизводить (blocker), а не расхождение
+}
+
+// newReplayer создаёт пустой ACTIVE кошелёк с identity и настройками сохранённого.
+func newReplayer(stored *entities.Wallet) *replayer {
+	zero := valueobjects.Zero(stored.Currency())
+	fresh := entities.ReconstructWallet(
+		stored.ID(), stored.UserID(), stored.Currency(), stored.WalletType(), entities.WalletStatusActive,
+		zero, zero, 0,
+		stored.DailyLimit(), stored.MonthlyLimit(), stored.Jurisdiction(), nil,
+		stored.CreatedAt(), stored.CreatedAt(),
+	)
+	return &replayer{walletID: stored.ID(), wallet: fresh}
+}
+
+// stopped - дальше воспроизводить нельзя.
+func (r *replayer) stopped() bool {
+	return r.divergence != nil || r.blocker != nil
+}
+
+// apply применяет очередную строку истории.
+func (r *replayer) apply(tx *entities.Transaction) {
+	r.read++
+	availableBefore := r.wallet.AvailableBalance()
+
+	issue := r.check(tx)
+	if issue == nil && tx.IsCompleted() {
+		var changed bool
+		changed, issue = r.applyCompleted(tx)
+		if changed && issue == nil {
+			r.applied++
+		}
+	}
+	if issue == nil {
+		return
+	}
+
+	row := &dtos.WalletReplayRowDTO{
+		Position:         r.read,
+		TransactionID:    tx.ID().String(),
+		Type:             string(tx.Type()),
+		Status:           string(tx.Status()),
+		CreatedAt:        tx.CreatedAt(),
+		CreatedByVersion: tx.CreatedByVersion(),
+		Reason:           issue.reason,
+		Detail:           issue.detail,
+		AvailableBefore:  availableBefore.String(),
+	}
+	if issue.blocking {
+		r.blocker = row
+	} else {
+		r.divergence = row
+	}
+}
+
+// check отклоняет строки, которые эта версия не умеет воспроизводить.
+func (r *replayer) check(tx *entities.Transaction) *rowIssue {
+	if !tx.Type().IsKnown() {
+		return &rowIssue{
+			reason:   ReasonUnknownType,
+			detail:   fmt.Sprintf("transaction type %s is not known to this build", tx.Type()),
+			blocking: true,
+		}
+	}
+	if !tx.Status().IsKnown() {
+		return &rowIssue{
+			reason:   ReasonUnknownStatus,
+			detail:   fmt.Sprintf("transaction status %s is not known to this build", tx.Status()),
+			blocking: true,
+		}
+	}
+	return nil
+}
+
+// applyCompleted применяет COMPLETED строку; changed - строка изменила кошелёк.
+func (r *replayer) applyCompleted(tx *entities.Transaction) (changed bool, issue *rowIssue) {
+	if tx.WalletID() == r.walletID {
+		if changed, issue = r.applyOutgoing(tx); issue != nil {
+			return changed, issue
+		}
+	}
+	if dest := tx.DestinationWalletID(); dest != nil && *dest == r.walletID {
+		issue = r.applyIncoming(tx)
+		changed = true
+	}
+	return changed, issue
+}
+
+// applyOutgoing применяет строку, проведённую по кошельку как по источнику.
+func (r *replayer) applyOutgoing(tx *entities.Transaction) (bool, *rowIssue) {
+	switch tx.Type() {
+	case entities.TransactionTypeDeposit, entities.TransactionTypeRefund, entities.TransactionTypeAdjustment:
+		return true, rejected(r.wallet.Credit(tx.Amount()))
+
+	case entities.TransactionTypeWithdraw, entities.TransactionTypePayout:
+		return true, r.debitPayer(tx)
+
+	case entities.TransactionTypeFee:
+		if _, ok := tx.ParentTransactionID(); ok {
+			return false, nil
+		}
+		return true, r.debitPayer(tx)
+
+	case entities.TransactionTypeTransfer:
+		if tx.DestinationWalletID() == nil {
+			return false, missingCounterparty("transfer without destination wallet")
+		}
+		if swept, _ := tx.Metadata()[wallet.MetadataSweep].(bool); swept {
+			return true, r.closeWithSweep(tx)
+		}
+		return true, r.debitPayer(tx)
+
+	case entities.TransactionTypeExchange:
+		if tx.DestinationWalletID() == nil {
+			return false, missingCounterparty("exchange without destination wallet")
+		}
+		return true, rejected(r.wallet.Debit(tx.Amount()))
+	}
+	return false, nil
+}
+
+// applyIncoming зачисляет входящий перевод или обмен.
+func (r *replayer) applyIncoming(tx *entities.Transaction) *rowIssue {
+	if tx.Type() == entities.TransactionTypeExchange {
+		credited, err := snapshot.ExchangeCredit(tx)
+		if err != nil {
+			return missingCounterparty(err.Error())
+		}
+		return rejected(r.wallet.Credit(credited))
+	}
+	return rejected(r.wallet.Credit(tx.NetAmount()))
+}
+
+// debitPayer списывает net + fee, как payerDebit в use cases транзакций.
+func (r *replayer) debitPayer(tx *entities.Transaction) *rowIssue {
+	total, err := tx.NetAmount().Add(tx.FeeAmount())
+	if err != nil {
+		return rejected(err)
+	}
+	return rejected(r.wallet.Debit(total))
+}
+
+// closeWithSweep повторяет CloseWalletWithSweep: sweep списывает весь
+// доступный остаток, поэтому сумма транзакции должна с ним совпасть.
+func (r *replayer) closeWithSweep(tx *entities.Transaction) *rowIssue {
+	if err := r.wallet.Lock(); err != nil {
+		return rejected(err)
+	}
+	swept, err := r.wallet.SweepAvailable()
+	if err != nil {
+		return rejected(err)
+	}
+	if !swept.Equals(tx.Amount()) {
+		return &rowIssue{
+			reason: ReasonSweepMismatch,
+			detail: fmt.Sprintf("sweep moved %s, replayed balance was %s", tx.Amount(), swept),
+		}
+	}
+	if err := r.wallet.Close(); err != nil {
+		return rejected(err)
+	}
+	r.sweep = tx
+	return nil
+}
+
+// report сравнивает воспроизведённый кошелёк с сохранённым.
+func (r *replayer) report(stored *entities.Wallet) *dtos.WalletReplayDTO {
+	result := &dtos.WalletReplayDTO{
+		WalletID:            r.walletID.String(),
+		CurrencyCode:        stored.Currency().Code(),
+		Complete:            !r.stopped(),
+		TransactionsRead:    r.read,
+		TransactionsApplied: r.applied,
+		Stored:              walletState(stored),
+		Replayed:            walletState(r.wallet),
+		Differences:         []dtos.WalletReplayDifferenceDTO{},
+		StatusEvents:        []dtos.WalletReplayStatusEventDTO{},
+		FirstDivergence:     r.divergence,
+		Blocker:             r.blocker,
+	}
+
+	diverged := r.divergence != nil
+	addDifference := func(field, storedValue, replayedValue string) {
+		if storedValue != replayedValue {
+			result.Differences = append(result.Differences, dtos.WalletReplayDifferenceDTO{
+				Field:    field,
+				Stored:   storedValue,
+				Replayed: replayedValue,
+			})
+			diverged = diverged || field != FieldBalanceVersion
+		}
+	}
+	addDifference(FieldAvailableBalance, result.Stored.AvailableBalance, result.Replayed.AvailableBalance)
+	addDifference(FieldPendingBalance, result.Stored.PendingBalance, result.Replayed.PendingBalance)
+	addDifference(FieldBalanceVersion,
+		strconv.FormatInt(result.Stored.BalanceVersion, 10), strconv.FormatInt(result.Replayed.BalanceVersion, 10))
+
+	// Закрытие без остатка sweep-транзакции не оставляет - это не расхождение
+	storedClosed := stored.Status() == entities.WalletStatusClosed
+	switch {
+	case r.sweep != nil && !storedClosed:
+		result.StatusEvents = append(result.StatusEvents, dtos.WalletReplayStatusEventDTO{
+			Event:         EventWalletClosed,
+			Kind:          StatusEventMissing,
+			TransactionID: r.sweep.ID().String(),
+		})
+	case r.sweep == nil && storedClosed && !r.wallet.AvailableBalance().IsZero():
+		result.StatusEvents = append(result.StatusEvents, dtos.WalletReplayStatusEventDTO{
+			Event: EventWalletClosed,
+			Kind:  StatusEventExtra,
+		})
+	}
+	diverged = diverged || len(result.StatusEvents) > 0
+
+	switch {
+	case r.blocker != nil:
+		result.Outcome = OutcomeUnreplayable
+	case diverged:
+		result.Outcome = OutcomeDiverged
+	default:
+		result.Outcome = OutcomeConsistent
+	}
+	return result
+}
+
+// walletState - сравниваемое состояние кошелька.
+func walletState(w *entities.Wallet) dtos.WalletReplayStateDTO {
+	return dtos.WalletReplayStateDTO{
+		AvailableBalance: w.AvailableBalance().String(),
+		PendingBalance:   w.PendingBalance().String(),
+		BalanceVersion:   w.BalanceVersion(),
+		Status:           string(w.Status()),
+	}
+}
+
+// rejected - расхождение, если доменный метод отклонил операцию.
+func rejected(err error) *rowIssue {
+	if err == nil {
+		return nil
+	}
+	return &rowIssue{reason: ReasonOperationRejected, detail: err.Error()}
+}
+
+// missingCounterparty - строка без данных второй стороны.
+func missingCounterparty(detail string) *rowIssue {
+	return &rowIssue{reason: ReasonMissingCounterparty, detail: detail, blocking: true}
+}
@@ -0,0 +1,375 @@
+package replay_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/replay"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+var (
+	usd = valueobjects.MustNewCurrency("USD")
+	eur = valueobjects.MustNewCurrency("EUR")
+)
+
+// storedWallets - сохранённые кошельки: WalletReader по таблице,
+// состояние (в том числе версия) задаётся тестом как есть.
+type storedWallets struct {
+	ports.WalletReader
+	wallets map[uuid.UUID]*entities.Wallet
+}
+
+func (r *storedWallets) FindByID(_ context.Context, id uuid.UUID) (*entities.Wallet, error) {
+	w, ok := r.wallets[id]
+	if !ok {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+	return reconstruct(w, w.BalanceVersion()), nil
+}
+
+// reconstruct - копия кошелька с версией баланса version.
+func reconstruct(w *entities.Wallet, version int64) *entities.Wallet {
+	return entities.ReconstructWallet(w.ID(), w.UserID(), w.Currency(), w.WalletType(), w.Status(),
+		w.AvailableBalance(), w.PendingBalance(), version, w.DailyLimit(), w.MonthlyLimit(),
+		w.Jurisdiction(), w.MaxPendingTransactions(), w.CreatedAt(), w.UpdatedAt())
+}
+
+// env - сохранённые кошельки и их история; строки идут с шагом в минуту.
+// In-memory хранилище держит кошельки только для ссылок из транзакций.
+type env struct {
+	store        *memory.Store
+	wallets      *storedWallets
+	transactions *memory.TransactionRepository
+	next         time.Time
+}
+
+func newEnv(t *testing.T) *env {
+	t.Helper()
+	store := memory.NewStore()
+	return &env{
+		store:        store,
+		wallets:      &storedWallets{wallets: make(map[uuid.UUID]*entities.Wallet)},
+		transactions: memory.NewTransactionRepository(store),
+		next:         time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func (e *env) useCase() *replay.ReplayWalletUseCase {
+	// Маленькая страница: история читается в несколько запросов
+	return replay.NewReplayWalletUseCase(e.wallets, e.transactions).WithPageSize(3)
+}
+
+// saveWallet сохраняет кошелёк в заданном (сохранённом) состоянии.
+func (e *env) saveWallet(t *testing.T, currency valueobjects.Currency, status entities.WalletStatus, availableCents, version int64) *entities.Wallet {
+	t.Helper()
+	available, err := valueobjects.NewMoneyFromCents(availableCents, currency)
+	require.NoError(t, err)
+	limit, err := valueobjects.NewMoneyFromInt(10000, currency)
+	require.NoError(t, err)
+
+	owner, err := entities.NewUser(uuid.NewString()+"@example.com", "Replay Owner")
+	require.NoError(t, err)
+	require.NoError(t, memory.NewUserRepository(e.store).Save(context.Background(), owner))
+
+	w := entities.ReconstructWallet(uuid.New(), owner.ID(), currency, entities.WalletTypeFiat, status,
+		available, valueobjects.Zero(currency), version, limit, limit, "", nil, e.next, e.next)
+	require.NoError(t, memory.NewWalletRepository(e.store).Save(context.Background(), reconstruct(w, 0)))
+	e.wallets.wallets[w.ID()] = w
+	return w
+}
+
+// add сохраняет строку истории; по умолчанию COMPLETED в валюте USD.
+func (e *env) add(t *testing.T, record entities.TransactionImport) *entities.Transaction {
+	t.Helper()
+	e.next = e.next.Add(time.Minute)
+
+	record.ID = uuid.New()
+	record.CreatedAt = e.next
+	if record.Status == "" {
+		record.Status = entities.TransactionStatusCompleted
+	}
+	if record.Status == entities.TransactionStatusCompleted {
+		completedAt := e.next
+		record.CompletedAt = &completedAt
+	}
+	if record.CurrencyCode == "" {
+		record.CurrencyCode = "USD"
+	}
+
+	tx, err := entities.ReconstructForImport(record)
+	require.NoError(t, err)
+	require.NoError(t, e.transactions.Save(context.Background(), tx))
+	return tx
+}
+
+// addUnknownType сохраняет COMPLETED строку типа, неизвестного этой версии.
+func (e *env) addUnknownType(t *testing.T, walletID uuid.UUID, txType, version string) *entities.Transaction {
+	t.Helper()
+	e.next = e.next.Add(time.Minute)
+	amount, err := valueobjects.NewMoneyFromCents(100, usd)
+	require.NoError(t, err)
+
+	tx, err := entities.ReconstructTransaction(uuid.New(), walletID, uuid.NewString(), entities.TransactionType(txType),
+		entities.TransactionStatusCompleted, amount, valueobjects.Zero(usd), amount, nil,
+		"", "", "", nil, "", "", 0, nil, "", version, e.next, e.next, &e.next, &e.next, "")
+	require.NoError(t, err)
+	require.NoError(t, e.transactions.Save(context.Background(), tx))
+	return tx
+}
+
+// everyType записывает историю со всеми типами транзакций:
+//
+//	+100 депозит, +5 корректировка, -20 вывод (комиссия 1 и её FEE строка),
+//	-10 выплата, -2 отдельная комиссия, -15 перевод (комиссия 0.50),
+//	+7 входящий перевод, +3 возврат, -8 обмен в EUR, +10 обмен из EUR,
+//	проваленный депозит 50 не учитывается
+//
+// Итог: 70.00 USD за 10 изменений баланса.
+func (e *env) everyType(t *testing.T, w, peer, eurWallet *entities.Wallet) {
+	t.Helper()
+	id, peerID, eurID := w.ID(), peer.ID(), eurWallet.ID()
+
+	e.add(t, entities.TransactionImport{WalletID: id, Type: entities.TransactionTypeDeposit, AmountCents: 10000})
+	e.add(t, entities.TransactionImport{WalletID: id, Type: entities.TransactionTypeAdjustment, AmountCents: 500})
+	withdraw := e.add(t, entities.TransactionImport{WalletID: id, Type: entities.TransactionTypeWithdraw, AmountCents: 2000, FeeCents: 100})
+	e.add(t, entities.TransactionImport{WalletID: id, Type: entities.TransactionTypeFee, AmountCents: 100,
+		Metadata: map[string]interface{}{entities.MetadataParentTransactionID: withdraw.ID().String()}})
+	e.add(t, entities.TransactionImport{WalletID: id, Type: entities.TransactionTypePayout, AmountCents: 1000})
+	e.add(t, entities.TransactionImport{WalletID: id, Type: entities.TransactionTypeFee, AmountCents: 200})
+	e.add(t, entities.TransactionImport{WalletID: id, Type: entities.TransactionTypeTransfer, AmountCents: 1500, FeeCents: 50,
+		DestinationWalletID: &peerID})
+	e.add(t, entities.TransactionImport{WalletID: peerID, Type: entities.TransactionTypeTransfer, AmountCents: 700,
+		DestinationWalletID: &id})
+	e.add(t, entities.TransactionImport{WalletID: id, Type: entities.TransactionTypeRefund, AmountCents: 300})
+	e.add(t, entities.TransactionImport{WalletID: id, Type: entities.TransactionTypeExchange, AmountCents: 800,
+		DestinationWalletID: &eurID, Metadata: map[string]interface{}{"dest_amount": "7.20 EUR"}})
+	e.add(t, entities.TransactionImport{WalletID: eurID, Type: entities.TransactionTypeExchange, AmountCents: 900, CurrencyCode: "EUR",
+		DestinationWalletID: &id, Metadata: map[string]interface{}{"dest_amount": "10.00 USD"}})
+	e.add(t, entities.TransactionImport{WalletID: id, Type: entities.TransactionTypeDeposit, AmountCents: 5000,
+		Status: entities.TransactionStatusFailed, FailureReason: "card declined"})
+}
+
+func TestReplayWallet_EveryTransactionType(t *testing.T) {
+	e := newEnv(t)
+	w := e.saveWallet(t, usd, entities.WalletStatusActive, 7000, 10)
+	peer := e.saveWallet(t, usd, entities.WalletStatusActive, 0, 0)
+	eurWallet := e.saveWallet(t, eur, entities.WalletStatusActive, 0, 0)
+	e.everyType(t, w, peer, eurWallet)
+
+	result, err := e.useCase().Execute(context.Background(), dtos.ReplayWalletQuery{WalletID: w.ID().String()})
+
+	require.NoError(t, err)
+	assert.Equal(t, replay.OutcomeConsistent, result.Outcome)
+	assert.True(t, result.Complete)
+	assert.Equal(t, 12, result.TransactionsRead)
+	assert.Equal(t, 10, result.TransactionsApplied)
+	assert.Equal(t, dtos.WalletReplayStateDTO{
+		AvailableBalance: "70.00 USD",
+		PendingBalance:   "0.00 USD",
+		BalanceVersion:   10,
+		Status:           "ACTIVE",
+	}, result.Replayed)
+	assert.Equal(t, result.Stored, result.Replayed)
+	assert.Empty(t, result.Differences)
+	assert.Empty(t, result.StatusEvents)
+	assert.Nil(t, result.FirstDivergence)
+	assert.Nil(t, result.Blocker)
+}
+
+func TestReplayWallet_CorruptedBalance(t *testing.T) {
+	t.Run("PinpointsOverdraftRow", func(t *testing.T) {
+		e := newEnv(t)
+		// Баланс завышен на 50.00: живая система провела вывод 120.00, на
+		// который у истории нет денег, и осталась с нулём
+		w := e.saveWallet(t, usd, entities.WalletStatusActive, 0, 11)
+		peer := e.saveWallet(t, usd, entities.WalletStatusActive, 0, 0)
+		eurWallet := e.saveWallet(t, eur, entities.WalletStatusActive, 0, 0)
+		e.everyType(t, w, peer, eurWallet)
+		overdraft := e.add(t, entities.TransactionImport{WalletID: w.ID(), Type: entities.TransactionTypeWithdraw, AmountCents: 12000})
+		e.add(t, entities.TransactionImport{WalletID: w.ID(), Type: entities.TransactionTypeDeposit, AmountCents: 100})
+
+		result, err := e.useCase().Execute(context.Background(), dtos.ReplayWalletQuery{WalletID: w.ID().String()})
+
+		require.NoError(t, err)
+		assert.Equal(t, replay.OutcomeDiverged, result.Outcome)
+		assert.False(t, result.Complete, "replay stops at the diverging row")
+		require.NotNil(t, result.FirstDivergence)
+		assert.Equal(t, overdraft.ID().String(), result.FirstDivergence.TransactionID)
+		assert.Equal(t, 13, result.FirstDivergence.Position)
+		assert.Equal(t, "WITHDRAW", result.FirstDivergence.Type)
+		assert.Equal(t, replay.ReasonOperationRejected, result.FirstDivergence.Reason)
+		assert.Contains(t, result.FirstDivergence.Detail, "insufficient balance")
+		assert.Equal(t, "70.00 USD", result.FirstDivergence.AvailableBefore)
+		assert.Equal(t, 13, result.TransactionsRead, "rows after the divergence are not read")
+		assert.Nil(t, result.Blocker)
+	})
+
+	t.Run("FinalBalanceOnly", func(t *testing.T) {
+		e := newEnv(t)
+		w := e.saveWallet(t, usd, entities.WalletStatusActive, 6950, 10)
+		peer := e.saveWallet(t, usd, entities.WalletStatusActive, 0, 0)
+		eurWallet := e.saveWallet(t, eur, entities.WalletStatusActive, 0, 0)
+		e.everyType(t, w, peer, eurWallet)
+
+		result, err := e.useCase().Execute(context.Background(), dtos.ReplayWalletQuery{WalletID: w.ID().String()})
+
+		require.NoError(t, err)
+		assert.Equal(t, replay.OutcomeDiverged, result.Outcome)
+		assert.True(t, result.Complete)
+		assert.Nil(t, result.FirstDivergence, "history itself is consistent")
+		assert.Equal(t, []dtos.WalletReplayDifferenceDTO{
+			{Field: replay.FieldAvailableBalance, Stored: "69.50 USD", Replayed: "70.00 USD"},
+		}, result.Differences)
+	})
+
+	t.Run("VersionOnlyIsInformational", func(t *testing.T) {
+		e := newEnv(t)
+		w := e.saveWallet(t, usd, entities.WalletStatusActive, 700, 3)
+		e.add(t, entities.TransactionImport{WalletID: w.ID(), Type: entities.TransactionTypeDeposit, AmountCents: 700})
+
+		result, err := e.useCase().Execute(context.Background(), dtos.ReplayWalletQuery{WalletID: w.ID().String()})
+
+		require.NoError(t, err)
+		assert.Equal(t, replay.OutcomeConsistent, result.Outcome)
+		assert.Equal(t, []dtos.WalletReplayDifferenceDTO{
+			{Field: replay.FieldBalanceVersion, Stored: "3", Replayed: "1"},
+		}, result.Differences)
+	})
+}
+
+func TestReplayWallet_Sweep(t *testing.T) {
+	setup := func(t *testing.T, status entities.WalletStatus, sweptCents int64) (*env, *entities.Wallet, *entities.Transaction) {
+		e := newEnv(t)
+		w := e.saveWallet(t, usd, status, 0, 2)
+		peerID := e.saveWallet(t, usd, entities.WalletStatusActive, 0, 0).ID()
+		e.add(t, entities.TransactionImport{WalletID: w.ID(), Type: entities.TransactionTypeDeposit, AmountCents: 3000})
+		sweep := e.add(t, entities.TransactionImport{WalletID: w.ID(), Type: entities.TransactionTypeTransfer, AmountCents: sweptCents,
+			DestinationWalletID: &peerID, Metadata: map[string]interface{}{wallet.MetadataSweep: true}})
+		return e, w, sweep
+	}
+
+	t.Run("ClosedBySweep", func(t *testing.T) {
+		e, w, _ := setup(t, entities.WalletStatusClosed, 3000)
+
+		result, err := e.useCase().Execute(context.Background(), dtos.ReplayWalletQuery{WalletID: w.ID().String()})
+
+		require.NoError(t, err)
+		assert.Equal(t, replay.OutcomeConsistent, result.Outcome)
+		assert.Equal(t, "CLOSED", result.Replayed.Status)
+	})
+
+	t.Run("MissingClose", func(t *testing.T) {
+		e, w, sweep := setup(t, entities.WalletStatusActive, 3000)
+
+		result, err := e.useCase().Execute(context.Background(), dtos.ReplayWalletQuery{WalletID: w.ID().String()})
+
+		require.NoError(t, err)
+		assert.Equal(t, replay.OutcomeDiverged, result.Outcome)
+		assert.Equal(t, []dtos.WalletReplayStatusEventDTO{
+			{Event: replay.EventWalletClosed, Kind: replay.StatusEventMissing, TransactionID: sweep.ID().String()},
+		}, result.StatusEvents)
+	})
+
+	t.Run("SweptAmountMismatch", func(t *testing.T) {
+		e, w, sweep := setup(t, entities.WalletStatusClosed, 2500)
+
+		result, err := e.useCase().Execute(context.Background(), dtos.ReplayWalletQuery{WalletID: w.ID().String()})
+
+		require.NoError(t, err)
+		require.NotNil(t, result.FirstDivergence)
+		assert.Equal(t, sweep.ID().String(), result.FirstDivergence.TransactionID)
+		assert.Equal(t, replay.ReasonSweepMismatch, result.FirstDivergence.Reason)
+	})
+}
+
+func TestReplayWallet_Unreplayable(t *testing.T) {
+	t.Run("UnknownTransactionType", func(t *testing.T) {
+		e := newEnv(t)
+		w := e.saveWallet(t, usd, entities.WalletStatusActive, 1000, 1)
+		e.add(t, entities.TransactionImport{WalletID: w.ID(), Type: entities.TransactionTypeDeposit, AmountCents: 1000})
+		chargeback := e.addUnknownType(t, w.ID(), "CHARGEBACK", "v9.1.0")
+
+		result, err := e.useCase().Execute(context.Background(), dtos.ReplayWalletQuery{WalletID: w.ID().String()})
+
+		require.NoError(t, err)
+		assert.Equal(t, replay.OutcomeUnreplayable, result.Outcome)
+		assert.False(t, result.Complete)
+		require.NotNil(t, result.Blocker)
+		assert.Equal(t, chargeback.ID().String(), result.Blocker.TransactionID)
+		assert.Equal(t, 2, result.Blocker.Position)
+		assert.Equal(t, replay.ReasonUnknownType, result.Blocker.Reason)
+		assert.Equal(t, "v9.1.0", result.Blocker.CreatedByVersion)
+	})
+
+	t.Run("MissingCounterpartyData", func(t *testing.T) {
+		e := newEnv(t)
+		w := e.saveWallet(t, usd, entities.WalletStatusActive, 1000, 1)
+		eurID := e.saveWallet(t, eur, entities.WalletStatusActive, 0, 0).ID()
+		id := w.ID()
+		exchange := e.add(t, entities.TransactionImport{WalletID: eurID, Type: entities.TransactionTypeExchange, AmountCents: 900,
+			CurrencyCode: "EUR", DestinationWalletID: &id})
+
+		result, err := e.useCase().Execute(context.Background(), dtos.ReplayWalletQuery{WalletID: w.ID().String()})
+
+		require.NoError(t, err)
+		assert.Equal(t, replay.OutcomeUnreplayable, result.Outcome)
+		require.NotNil(t, result.Blocker)
+		assert.Equal(t, exchange.ID().String(), result.Blocker.TransactionID)
+		assert.Equal(t, replay.ReasonMissingCounterparty, result.Blocker.Reason)
+		assert.Contains(t, result.Blocker.Detail, "dest_amount")
+	})
+}
+
+// changingWallets - WalletReader, у которого кошелёк меняется между чтениями.
+type changingWallets struct {
+	ports.WalletReader
+	reads int
+}
+
+func (r *changingWallets) FindByID(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+	w, err := r.WalletReader.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.reads++
+	if r.reads > 1 {
+		amount, _ := valueobjects.NewMoneyFromCents(100, w.Currency())
+		_ = w.Credit(amount)
+	}
+	return w, nil
+}
+
+func TestReplayWallet_Errors(t *testing.T) {
+	e := newEnv(t)
+	ctx := context.Background()
+
+	t.Run("InvalidID", func(t *testing.T) {
+		_, err := e.useCase().Execute(ctx, dtos.ReplayWalletQuery{WalletID: "not-a-uuid"})
+		assert.True(t, domainErrors.IsValidation(err))
+	})
+
+	t.Run("UnknownWallet", func(t *testing.T) {
+		_, err := e.useCase().Execute(ctx, dtos.ReplayWalletQuery{WalletID: uuid.NewString()})
+		assert.True(t, domainErrors.IsNotFound(err))
+	})
+
+	t.Run("ModifiedDuringReplay", func(t *testing.T) {
+		w := e.saveWallet(t, usd, entities.WalletStatusActive, 0, 0)
+		uc := replay.NewReplayWalletUseCase(&changingWallets{WalletReader: e.wallets}, e.transactions)
+
+		_, err := uc.Execute(ctx, dtos.ReplayWalletQuery{WalletID: w.ID().String()})
+		assert.True(t, domainErrors.IsConcurrencyError(err))
+	})
+}
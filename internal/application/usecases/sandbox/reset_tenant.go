@@ -0,0 +1,218 @@
+// Package sandbox - use cases sandbox окружения.
+package sandbox
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/google/uuid"
+)
+
+const (
+	// ConfirmationTokenTTL - время жизни токена подтверждения сброса.
+	ConfirmationTokenTTL = 5 * time.Minute
+
+	// ResetCooldown - минимальный интервал между сбросами одного tenant'а.
+	ResetCooldown = 10 * time.Minute
+)
+
+// pendingReset - выданный, но ещё не использованный токен подтверждения.
+type pendingReset struct {
+	token     string
+	expiresAt time.Time
+}
+
+// ResetTenantUseCase - use case сброса sandbox данных tenant'а.
+//
+// Сценарий:
+// 1. Вызов без токена: проверить cooldown, выдать токен (TTL 5 минут),
+// опубликовать SandboxResetRequested
+// 2. Вызов с токеном: сверить и погасить токен, проверить cooldown,
+// в одном UnitOfWork удалить данные и опубликовать SandboxResetCompleted
+//
+// Токен одноразовый, поэтому повтор запроса (retry) не сбрасывает данные дважды.
+// Токены и время последнего сброса хранятся в памяти процесса - при нескольких
+// инстансах подтверждение должно попасть на тот же инстанс.
+type ResetTenantUseCase struct {
+	sandboxRepo    ports.SandboxRepository
+	eventPublisher ports.EventPublisher
+	uow            ports.UnitOfWork
+
+	// now - источник времени (подменяется в тестах)
+	now func() time.Time
+
+	mu        sync.Mutex
+	pending   map[uuid.UUID]pendingReset
+	lastReset map[uuid.UUID]time.Time
+}
+
+// NewResetTenantUseCase создаёт новый use case.
+func NewResetTenantUseCase(
+	sandboxRepo ports.SandboxRepository,
+	eventPublisher ports.EventPublisher,
+	uow ports.UnitOfWork,
+) *ResetTenantUseCase {
+	return &ResetTenantUseCase{
+		sandboxRepo:    sandboxRepo,
+		eventPublisher: eventPublisher,
+		uow:            uow,
+		now:            func() time.Time { return time.Now().UTC() },
+		pending:        make(map[uuid.UUID]pendingReset),
+		lastReset:      make(map[uuid.UUID]time.Time),
+	}
+}
+
+// Execute выполняет шаг сброса sandbox.
+//
+// Errors:
+//   - ACTOR_REQUIRED: В context нет инициатора
+//   - RATE_LIMITED: Сброс уже выполнялся менее ResetCooldown назад
+//   - INVALID_CONFIRMATION_TOKEN: Токен не выдавался, истёк или уже использован
+//   - CROSS_TENANT_REFERENCES: На кошельки tenant'а ссылаются чужие транзакции
+func (uc *ResetTenantUseCase) Execute(ctx context.Context, cmd dtos.ResetSandboxCommand) (*dtos.SandboxResetDTO, error) {
+	actor, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "sandbox reset requires an authenticated actor", nil)
+	}
+	tenantID := actor.ID
+
+	if cmd.ConfirmationToken == "" {
+		return uc.requestReset(ctx, tenantID, actor.ID)
+	}
+
+	if err := uc.consumeToken(tenantID, cmd.ConfirmationToken); err != nil {
+		return nil, err
+	}
+	return uc.reset(ctx, tenantID, actor.ID)
+}
+
+// requestReset выдаёт токен подтверждения. Новый запрос заменяет прежний токен.
+func (uc *ResetTenantUseCase) requestReset(ctx context.Context, tenantID, actorID uuid.UUID) (*dtos.SandboxResetDTO, error) {
+	token, err := newConfirmationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	now := uc.now()
+	if err := uc.checkCooldown(tenantID, now); err != nil {
+		uc.mu.Unlock()
+		return nil, err
+	}
+	expiresAt := now.Add(ConfirmationTokenTTL)
+	uc.pending[tenantID] = pendingReset{token: token, expiresAt: expiresAt}
+	uc.mu.Unlock()
+
+	event := events.NewSandboxResetRequested(tenantID, actorID, expiresAt)
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		return uc.eventPublisher.Publish(txCtx, event)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish %s event: %w", event.EventType(), err)
+	}
+
+	return &dtos.SandboxResetDTO{
+		Status:            dtos.SandboxResetConfirmationRequired,
+		ConfirmationToken: token,
+		ExpiresAt:         &expiresAt,
+	}, nil
+}
+
+// consumeToken сверяет токен и гасит его (в том числе истёкший).
+func (uc *ResetTenantUseCase) consumeToken(tenantID uuid.UUID, token string) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	pending, ok := uc.pending[tenantID]
+	if !ok || subtle.ConstantTimeCompare([]byte(pending.token), []byte(token)) != 1 {
+		return errors.NewDomainError("INVALID_CONFIRMATION_TOKEN", "confirmation token is invalid or already used", nil)
+	}
+	delete(uc.pending, tenantID)
+
+	if !uc.now().Before(pending.expiresAt) {
+		return errors.NewDomainError("INVALID_CONFIRMATION_TOKEN", "confirmation token has expired", nil)
+	}
+	return nil
+}
+
+// reset удаляет данные tenant'а и пишет audit событие в одной транзакции.
+func (uc *ResetTenantUseCase) reset(ctx context.Context, tenantID, actorID uuid.UUID) (*dtos.SandboxResetDTO, error) {
+	// Слот cooldown занимается до удаления: параллельные подтверждения не проходят
+	uc.mu.Lock()
+	now := uc.now()
+	if err := uc.checkCooldown(tenantID, now); err != nil {
+		uc.mu.Unlock()
+		return nil, err
+	}
+	previous, hadPrevious := uc.lastReset[tenantID]
+	uc.lastReset[tenantID] = now
+	uc.mu.Unlock()
+
+	var deleted ports.SandboxPurgeCounts
+	err := uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		var err error
+		deleted, err = uc.sandboxRepo.PurgeUserData(txCtx, tenantID)
+		if err != nil {
+			return err
+		}
+
+		event := events.NewSandboxResetCompleted(tenantID, actorID, deleted)
+		if err := uc.eventPublisher.Publish(txCtx, event); err != nil {
+			return fmt.Errorf("failed to publish %s event: %w", event.EventType(), err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		// Сброс не состоялся - освобождаем слот cooldown
+		uc.mu.Lock()
+		if hadPrevious {
+			uc.lastReset[tenantID] = previous
+		} else {
+			delete(uc.lastReset, tenantID)
+		}
+		uc.mu.Unlock()
+		return nil, err
+	}
+
+	return &dtos.SandboxResetDTO{
+		Status:  dtos.SandboxResetCompleted,
+		Deleted: deleted,
+		ResetAt: &now,
+	}, nil
+}
+
+// checkCooldown возвращает RATE_LIMITED, если tenant сбрасывался недавно.
+// Вызывается под uc.mu.
+func (uc *ResetTenantUseCase) checkCooldown(tenantID uuid.UUID, now time.Time) error {
+	last, ok := uc.lastReset[tenantID]
+	if !ok {
+		return nil
+	}
+	if retryAt := last.Add(ResetCooldown); now.Before(retryAt) {
+		return errors.NewDomainError(
+			"RATE_LIMITED",
+			fmt.Sprintf("sandbox can be reset once per %s, retry after %s", ResetCooldown, retryAt.UTC().Format(time.RFC3339)),
+			nil,
+		)
+	}
+	return nil
+}
+
+// newConfirmationToken генерирует случайный токен подтверждения.
+func newConfirmationToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,353 @@
+package sandbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// resetFixture - два tenant'а с кошельками и транзакциями поверх memory store.
+type resetFixture struct {
+	users        *memory.UserRepository
+	wallets      *memory.WalletRepository
+	transactions *memory.TransactionRepository
+	webhooks     *memory.WebhookSubscriptionRepository
+	deliveries   *memory.WebhookDeliveryRepository
+	publisher    *memory.EventPublisher
+	useCase      *ResetTenantUseCase
+
+	clock time.Time
+}
+
+func newResetFixture(t *testing.T) *resetFixture {
+	t.Helper()
+
+	store := memory.NewStore()
+	f := &resetFixture{
+		users:        memory.NewUserRepository(store),
+		wallets:      memory.NewWalletRepository(store),
+		transactions: memory.NewTransactionRepository(store),
+		webhooks:     memory.NewWebhookSubscriptionRepository(store),
+		deliveries:   memory.NewWebhookDeliveryRepository(store),
+		publisher:    memory.NewEventPublisher(store),
+		clock:        time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	f.useCase = NewResetTenantUseCase(memory.NewSandboxRepository(store), f.publisher, memory.NewUnitOfWork(store))
+	f.useCase.now = func() time.Time { return f.clock }
+	return f
+}
+
+// seedTenant создаёт пользователя с USD кошельком и одним пополнением.
+func (f *resetFixture) seedTenant(t *testing.T) (*entities.User, *entities.Wallet) {
+	t.Helper()
+	ctx := context.Background()
+
+	user, err := entities.NewUser(uuid.NewString()+"@example.com", "Sandbox Tenant")
+	if err != nil {
+		t.Fatalf("NewUser error = %v", err)
+	}
+	if err := f.users.Save(ctx, user); err != nil {
+		t.Fatalf("Save user error = %v", err)
+	}
+
+	wallet, err := entities.NewWallet(user.ID(), valueobjects.MustNewCurrency("USD"))
+	if err != nil {
+		t.Fatalf("NewWallet error = %v", err)
+	}
+	if err := f.wallets.Save(ctx, wallet); err != nil {
+		t.Fatalf("Save wallet error = %v", err)
+	}
+
+	f.seedTransaction(t, wallet.ID(), nil)
+	return user, wallet
+}
+
+// seedTransaction сохраняет транзакцию кошелька (перевод, если задан dest).
+func (f *resetFixture) seedTransaction(t *testing.T, walletID uuid.UUID, dest *uuid.UUID) *entities.Transaction {
+	t.Helper()
+
+	amount, _ := valueobjects.NewMoney("10.00", valueobjects.USD)
+	txType := entities.TransactionTypeDeposit
+	if dest != nil {
+		txType = entities.TransactionTypeTransfer
+	}
+	tx, err := entities.NewTransaction(walletID, uuid.NewString(), txType, amount, "seed")
+	if err != nil {
+		t.Fatalf("NewTransaction error = %v", err)
+	}
+	if dest != nil {
+		if err := tx.SetDestinationWallet(*dest); err != nil {
+			t.Fatalf("SetDestinationWallet error = %v", err)
+		}
+	}
+	if err := f.transactions.Save(context.Background(), tx); err != nil {
+		t.Fatalf("Save transaction error = %v", err)
+	}
+	return tx
+}
+
+// seedWebhook создаёт подписку пользователя с одной доставкой в очереди.
+func (f *resetFixture) seedWebhook(t *testing.T, userID uuid.UUID) *entities.WebhookSubscription {
+	t.Helper()
+	ctx := context.Background()
+
+	subscription, err := entities.NewWebhookSubscription(userID, "https://hooks.example.com/"+userID.String(), nil)
+	if err != nil {
+		t.Fatalf("NewWebhookSubscription error = %v", err)
+	}
+	if err := f.webhooks.Save(ctx, subscription); err != nil {
+		t.Fatalf("Save webhook error = %v", err)
+	}
+	delivery := entities.NewWebhookDelivery(subscription.ID(), uuid.New(), "WalletCredited", []byte(`{}`))
+	if err := f.deliveries.Enqueue(ctx, delivery); err != nil {
+		t.Fatalf("Enqueue delivery error = %v", err)
+	}
+	return subscription
+}
+
+// confirm выполняет оба шага сброса от имени пользователя.
+func (f *resetFixture) confirm(t *testing.T, ctx context.Context) (*dtos.SandboxResetDTO, error) {
+	t.Helper()
+
+	requested, err := f.useCase.Execute(ctx, dtos.ResetSandboxCommand{})
+	if err != nil {
+		t.Fatalf("request step error = %v", err)
+	}
+	return f.useCase.Execute(ctx, dtos.ResetSandboxCommand{ConfirmationToken: requested.ConfirmationToken})
+}
+
+func actorContext(userID uuid.UUID) context.Context {
+	return ports.WithActor(context.Background(), ports.Actor{ID: userID, Role: "user"})
+}
+
+func domainErrorCode(err error) string {
+	if de, ok := err.(*domainErrors.DomainError); ok {
+		return de.Code
+	}
+	return ""
+}
+
+// TestResetTenantUseCase_DeletesOnlyCallerData проверяет, что сброс удаляет
+// ровно данные вызывающего tenant'а и не трогает строки другого.
+func TestResetTenantUseCase_DeletesOnlyCallerData(t *testing.T) {
+	f := newResetFixture(t)
+	caller, callerWallet := f.seedTenant(t)
+	other, otherWallet := f.seedTenant(t)
+
+	// Исходящий перевод caller -> other принадлежит caller и удаляется вместе с ним
+	f.seedTransaction(t, callerWallet.ID(), ptr(otherWallet.ID()))
+	otherTx := f.seedTransaction(t, otherWallet.ID(), nil)
+	callerWebhook := f.seedWebhook(t, caller.ID())
+	otherWebhook := f.seedWebhook(t, other.ID())
+
+	ctx := actorContext(caller.ID())
+
+	requested, err := f.useCase.Execute(ctx, dtos.ResetSandboxCommand{})
+	if err != nil {
+		t.Fatalf("request step error = %v", err)
+	}
+	if requested.Status != dtos.SandboxResetConfirmationRequired || requested.ConfirmationToken == "" {
+		t.Fatalf("expected confirmation token, got %+v", requested)
+	}
+	if got := requested.ExpiresAt.Sub(f.clock); got != ConfirmationTokenTTL {
+		t.Errorf("token TTL = %s, want %s", got, ConfirmationTokenTTL)
+	}
+
+	// Первый шаг ничего не удаляет
+	if _, err := f.wallets.FindByID(context.Background(), callerWallet.ID()); err != nil {
+		t.Fatalf("wallet deleted before confirmation: %v", err)
+	}
+
+	result, err := f.useCase.Execute(ctx, dtos.ResetSandboxCommand{ConfirmationToken: requested.ConfirmationToken})
+	if err != nil {
+		t.Fatalf("confirm step error = %v", err)
+	}
+	if result.Status != dtos.SandboxResetCompleted {
+		t.Errorf("Status = %s, want completed", result.Status)
+	}
+	if result.Deleted["transactions"] != 2 || result.Deleted["wallets"] != 1 {
+		t.Errorf("Deleted = %v, want 2 transactions and 1 wallet", result.Deleted)
+	}
+	if result.Deleted["webhook_subscriptions"] != 1 || result.Deleted["webhook_deliveries"] != 1 {
+		t.Errorf("Deleted = %v, want 1 webhook subscription and 1 delivery", result.Deleted)
+	}
+	if _, err := f.webhooks.FindByID(context.Background(), callerWebhook.ID()); !domainErrors.IsNotFound(err) {
+		t.Errorf("caller webhook subscription still exists, err = %v", err)
+	}
+
+	// Данные caller удалены, сам пользователь остаётся
+	if _, err := f.wallets.FindByID(context.Background(), callerWallet.ID()); !domainErrors.IsNotFound(err) {
+		t.Errorf("caller wallet still exists, err = %v", err)
+	}
+	if txs, _ := f.transactions.FindByWalletID(context.Background(), callerWallet.ID(), 0, 100); len(txs) != 0 {
+		t.Errorf("caller has %d transactions left", len(txs))
+	}
+	if _, err := f.users.FindByID(context.Background(), caller.ID()); err != nil {
+		t.Errorf("caller user must be kept, err = %v", err)
+	}
+
+	// Данные другого tenant'а не тронуты
+	if _, err := f.users.FindByID(context.Background(), other.ID()); err != nil {
+		t.Errorf("other user deleted: %v", err)
+	}
+	if _, err := f.wallets.FindByID(context.Background(), otherWallet.ID()); err != nil {
+		t.Errorf("other wallet deleted: %v", err)
+	}
+	if txs, _ := f.transactions.FindByWalletID(context.Background(), otherWallet.ID(), 0, 100); len(txs) != 2 {
+		t.Errorf("other tenant has %d transactions, want 2", len(txs))
+	}
+	if _, err := f.transactions.FindByID(context.Background(), otherTx.ID()); err != nil {
+		t.Errorf("other transaction deleted: %v", err)
+	}
+	if _, err := f.webhooks.FindByID(context.Background(), otherWebhook.ID()); err != nil {
+		t.Errorf("other webhook subscription deleted: %v", err)
+	}
+	if deliveries, _ := f.deliveries.ListBySubscription(context.Background(), otherWebhook.ID(), 0, 10); len(deliveries) != 1 {
+		t.Errorf("other webhook has %d deliveries, want 1", len(deliveries))
+	}
+
+	// Audit: запрос и завершение сброса
+	var requestedEvents, completedEvents int
+	for _, event := range f.publisher.Events() {
+		switch e := event.(type) {
+		case *events.SandboxResetRequested:
+			requestedEvents++
+		case *events.SandboxResetCompleted:
+			completedEvents++
+			if e.TenantID != caller.ID() || e.ActorID != caller.ID() || e.Deleted["wallets"] != 1 {
+				t.Errorf("unexpected completed event %+v", e)
+			}
+		}
+	}
+	if requestedEvents != 1 || completedEvents != 1 {
+		t.Errorf("audit events requested/completed = %d/%d, want 1/1", requestedEvents, completedEvents)
+	}
+}
+
+// TestResetTenantUseCase_ConfirmationToken проверяет одноразовость и срок жизни токена.
+func TestResetTenantUseCase_ConfirmationToken(t *testing.T) {
+	t.Run("Token reuse", func(t *testing.T) {
+		f := newResetFixture(t)
+		caller, _ := f.seedTenant(t)
+		ctx := actorContext(caller.ID())
+
+		requested, _ := f.useCase.Execute(ctx, dtos.ResetSandboxCommand{})
+		cmd := dtos.ResetSandboxCommand{ConfirmationToken: requested.ConfirmationToken}
+		if _, err := f.useCase.Execute(ctx, cmd); err != nil {
+			t.Fatalf("confirm error = %v", err)
+		}
+
+		// Повтор того же запроса (retry) не сбрасывает данные второй раз
+		f.clock = f.clock.Add(ResetCooldown)
+		if _, err := f.useCase.Execute(ctx, cmd); domainErrorCode(err) != "INVALID_CONFIRMATION_TOKEN" {
+			t.Errorf("expected INVALID_CONFIRMATION_TOKEN, got %v", err)
+		}
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		f := newResetFixture(t)
+		caller, wallet := f.seedTenant(t)
+		ctx := actorContext(caller.ID())
+
+		requested, _ := f.useCase.Execute(ctx, dtos.ResetSandboxCommand{})
+		f.clock = f.clock.Add(ConfirmationTokenTTL)
+
+		_, err := f.useCase.Execute(ctx, dtos.ResetSandboxCommand{ConfirmationToken: requested.ConfirmationToken})
+		if domainErrorCode(err) != "INVALID_CONFIRMATION_TOKEN" {
+			t.Errorf("expected INVALID_CONFIRMATION_TOKEN, got %v", err)
+		}
+		if _, err := f.wallets.FindByID(context.Background(), wallet.ID()); err != nil {
+			t.Errorf("wallet deleted with expired token: %v", err)
+		}
+	})
+
+	t.Run("Token of another tenant", func(t *testing.T) {
+		f := newResetFixture(t)
+		caller, _ := f.seedTenant(t)
+		other, otherWallet := f.seedTenant(t)
+
+		requested, _ := f.useCase.Execute(actorContext(caller.ID()), dtos.ResetSandboxCommand{})
+		_, err := f.useCase.Execute(actorContext(other.ID()), dtos.ResetSandboxCommand{ConfirmationToken: requested.ConfirmationToken})
+		if domainErrorCode(err) != "INVALID_CONFIRMATION_TOKEN" {
+			t.Errorf("expected INVALID_CONFIRMATION_TOKEN, got %v", err)
+		}
+		if _, err := f.wallets.FindByID(context.Background(), otherWallet.ID()); err != nil {
+			t.Errorf("other wallet deleted: %v", err)
+		}
+	})
+
+	t.Run("Actor required", func(t *testing.T) {
+		f := newResetFixture(t)
+		if _, err := f.useCase.Execute(context.Background(), dtos.ResetSandboxCommand{}); domainErrorCode(err) != "ACTOR_REQUIRED" {
+			t.Errorf("expected ACTOR_REQUIRED, got %v", err)
+		}
+	})
+}
+
+// TestResetTenantUseCase_Cooldown проверяет ограничение "один сброс в 10 минут".
+func TestResetTenantUseCase_Cooldown(t *testing.T) {
+	f := newResetFixture(t)
+	caller, _ := f.seedTenant(t)
+	other, _ := f.seedTenant(t)
+	ctx := actorContext(caller.ID())
+
+	if _, err := f.confirm(t, ctx); err != nil {
+		t.Fatalf("first reset error = %v", err)
+	}
+
+	f.clock = f.clock.Add(ResetCooldown - time.Second)
+	if _, err := f.useCase.Execute(ctx, dtos.ResetSandboxCommand{}); domainErrorCode(err) != "RATE_LIMITED" {
+		t.Errorf("expected RATE_LIMITED, got %v", err)
+	}
+
+	// Cooldown считается по tenant'у
+	if _, err := f.confirm(t, actorContext(other.ID())); err != nil {
+		t.Errorf("other tenant reset error = %v", err)
+	}
+
+	f.clock = f.clock.Add(time.Second)
+	if _, err := f.confirm(t, ctx); err != nil {
+		t.Errorf("reset after cooldown error = %v", err)
+	}
+}
+
+// TestResetTenantUseCase_CrossTenantReferences проверяет, что входящие переводы
+// другого tenant'а блокируют сброс целиком и не занимают слот cooldown.
+func TestResetTenantUseCase_CrossTenantReferences(t *testing.T) {
+	f := newResetFixture(t)
+	caller, callerWallet := f.seedTenant(t)
+	_, otherWallet := f.seedTenant(t)
+	incoming := f.seedTransaction(t, otherWallet.ID(), ptr(callerWallet.ID()))
+	ctx := actorContext(caller.ID())
+
+	_, err := f.confirm(t, ctx)
+	if !domainErrors.IsBusinessRuleViolation(err) {
+		t.Fatalf("expected CROSS_TENANT_REFERENCES violation, got %v", err)
+	}
+
+	if _, err := f.wallets.FindByID(context.Background(), callerWallet.ID()); err != nil {
+		t.Errorf("caller wallet deleted despite failed reset: %v", err)
+	}
+	if _, err := f.transactions.FindByID(context.Background(), incoming.ID()); err != nil {
+		t.Errorf("other tenant transaction deleted: %v", err)
+	}
+
+	// Неудачный сброс не считается для cooldown
+	if _, err := f.useCase.Execute(ctx, dtos.ResetSandboxCommand{}); err != nil {
+		t.Errorf("expected new token after failed reset, got %v", err)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
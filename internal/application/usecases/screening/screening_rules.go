@@ -0,0 +1,159 @@
+// Package screening - admin use cases правил скрининга транзакций:
+// список активных правил и dry-run гипотетической транзакции.
+package screening
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	engine "github.com/Haleralex/wallethub/internal/application/screening"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// ============================================
+// List
+// ============================================
+
+// ListScreeningRulesUseCase возвращает правила, загруженные при старте.
+//
+// Доступ только для admin - проверяется в роутере (группа /admin).
+type ListScreeningRulesUseCase struct {
+	rules *engine.RuleSet // nil - скрининг выключен
+}
+
+// NewListScreeningRulesUseCase создаёт новый use case.
+func NewListScreeningRulesUseCase(rules *engine.RuleSet) *ListScreeningRulesUseCase {
+	return &ListScreeningRulesUseCase{rules: rules}
+}
+
+// Execute возвращает активные правила в порядке применения.
+func (uc *ListScreeningRulesUseCase) Execute(_ context.Context, _ dtos.ListScreeningRulesQuery) (*dtos.ScreeningRuleListDTO, error) {
+	definitions := uc.rules.Definitions()
+
+	result := &dtos.ScreeningRuleListDTO{
+		Enabled:    uc.rules != nil,
+		Rules:      make([]dtos.ScreeningRuleDTO, len(definitions)),
+		TotalCount: len(definitions),
+	}
+	for i, def := range definitions {
+		rule := dtos.ScreeningRuleDTO{
+			ID:          def.ID,
+			Description: def.Description,
+			Action:      def.Action,
+			Conditions:  make([]dtos.ScreeningConditionDTO, len(def.Conditions)),
+		}
+		for j, cond := range def.Conditions {
+			rule.Conditions[j] = dtos.ScreeningConditionDTO{
+				Attribute: cond.Attribute,
+				Operator:  cond.Operator,
+				Value:     cond.Value,
+				Values:    cond.Values,
+			}
+		}
+		result.Rules[i] = rule
+	}
+
+	return result, nil
+}
+
+// ============================================
+// Dry run
+// ============================================
+
+// DryRunScreeningUseCase проверяет гипотетическую транзакцию активными
+// правилами. Ничего не сохраняет и не публикует.
+type DryRunScreeningUseCase struct {
+	rules *engine.RuleSet // nil - скрининг выключен, всегда ALLOW
+}
+
+// NewDryRunScreeningUseCase создаёт новый use case.
+func NewDryRunScreeningUseCase(rules *engine.RuleSet) *DryRunScreeningUseCase {
+	return &DryRunScreeningUseCase{rules: rules}
+}
+
+// Execute возвращает решение, которое получила бы такая транзакция.
+func (uc *DryRunScreeningUseCase) Execute(_ context.Context, query dtos.DryRunScreeningQuery) (*dtos.ScreeningDryRunDTO, error) {
+	facts, err := dryRunFacts(query)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := uc.rules.Evaluate(facts)
+
+	result := &dtos.ScreeningDryRunDTO{
+		Decision:  dtos.ScreeningDecisionAllow,
+		BlockedBy: decision.BlockedBy,
+		Flags:     nonNil(decision.Flags),
+		Matched:   nonNil(decision.Matched),
+	}
+	switch {
+	case decision.Blocked():
+		result.Decision = dtos.ScreeningDecisionBlock
+	case len(decision.Flags) > 0:
+		result.Decision = dtos.ScreeningDecisionFlag
+	}
+	return result, nil
+}
+
+// dryRunFacts валидирует запрос и приводит его к engine.Facts так же,
+// как Service делает это для настоящей транзакции.
+func dryRunFacts(query dtos.DryRunScreeningQuery) (engine.Facts, error) {
+	var facts engine.Facts
+
+	txType := entities.TransactionType(strings.ToUpper(strings.TrimSpace(query.Type)))
+	if !txType.IsValid() {
+		return facts, errors.ValidationError{Field: "type", Message: "unknown transaction type"}
+	}
+	facts.Type = string(txType)
+
+	currency, err := valueobjects.NewCurrency(strings.ToUpper(strings.TrimSpace(query.Currency)))
+	if err != nil {
+		return facts, errors.ValidationError{Field: "currency", Message: "unsupported currency"}
+	}
+	facts.Currency = currency.Code()
+
+	amount, ok := new(big.Rat).SetString(strings.TrimSpace(query.Amount))
+	if !ok || amount.Sign() <= 0 {
+		return facts, errors.ValidationError{Field: "amount", Message: "amount must be a positive decimal"}
+	}
+	facts.Amount = amount
+
+	if query.WalletAge != "" {
+		age, err := time.ParseDuration(query.WalletAge)
+		if err != nil || age < 0 {
+			return facts, errors.ValidationError{Field: "wallet_age", Message: "wallet_age must be a non-negative duration like 36h"}
+		}
+		facts.WalletAge = age
+	}
+
+	if query.KYCStatus != "" {
+		status := entities.KYCStatus(strings.ToUpper(strings.TrimSpace(query.KYCStatus)))
+		if !status.IsValid() {
+			return facts, errors.ValidationError{Field: "kyc_status", Message: "unknown kyc status"}
+		}
+		facts.KYCStatus = string(status)
+	}
+
+	if query.Country != "" {
+		country, err := entities.NormalizeJurisdiction(query.Country)
+		if err != nil {
+			return facts, errors.ValidationError{Field: "country", Message: fmt.Sprintf("invalid country: %v", err)}
+		}
+		facts.Country = country
+	}
+
+	return facts, nil
+}
+
+func nonNil(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
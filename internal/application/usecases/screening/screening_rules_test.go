@@ -0,0 +1,156 @@
+package screening_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	engine "github.com/Haleralex/wallethub/internal/application/screening"
+	"github.com/Haleralex/wallethub/internal/application/usecases/screening"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+func newTestRuleSet(t *testing.T) *engine.RuleSet {
+	t.Helper()
+
+	rules, err := engine.NewRuleSet([]ports.ScreeningRule{
+		{
+			ID:     "fresh-wallet-withdraw",
+			Action: "block",
+			Conditions: []ports.ScreeningCondition{
+				{Attribute: "type", Operator: "eq", Value: "withdraw"},
+				{Attribute: "wallet_age", Operator: "lt", Value: "24h"},
+			},
+		},
+		{
+			ID:          "unverified-large",
+			Description: "Large amounts from unverified users",
+			Action:      "FLAG",
+			Conditions: []ports.ScreeningCondition{
+				{Attribute: "kyc_status", Operator: "ne", Value: "VERIFIED"},
+				{Attribute: "amount", Operator: "gt", Value: "1000"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRuleSet() error = %v", err)
+	}
+	return rules
+}
+
+func TestListScreeningRulesUseCase_Execute(t *testing.T) {
+	result, err := screening.NewListScreeningRulesUseCase(newTestRuleSet(t)).Execute(context.Background(), dtos.ListScreeningRulesQuery{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if !result.Enabled || result.TotalCount != 2 {
+		t.Fatalf("Expected 2 enabled rules, got enabled=%v total=%d", result.Enabled, result.TotalCount)
+	}
+	first := result.Rules[0]
+	if first.ID != "fresh-wallet-withdraw" || first.Action != "BLOCK" {
+		t.Errorf("Unexpected first rule %+v", first)
+	}
+	want := dtos.ScreeningConditionDTO{Attribute: "type", Operator: "eq", Value: "WITHDRAW"}
+	if !reflect.DeepEqual(first.Conditions[0], want) {
+		t.Errorf("Condition = %+v, want normalized %+v", first.Conditions[0], want)
+	}
+}
+
+func TestListScreeningRulesUseCase_Disabled(t *testing.T) {
+	result, err := screening.NewListScreeningRulesUseCase(nil).Execute(context.Background(), dtos.ListScreeningRulesQuery{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Enabled || result.TotalCount != 0 || result.Rules == nil {
+		t.Errorf("Expected disabled empty list, got %+v", result)
+	}
+}
+
+func TestDryRunScreeningUseCase_Execute(t *testing.T) {
+	uc := screening.NewDryRunScreeningUseCase(newTestRuleSet(t))
+
+	tests := []struct {
+		name      string
+		query     dtos.DryRunScreeningQuery
+		decision  string
+		blockedBy string
+		flags     []string
+	}{
+		{
+			name:     "Allow",
+			query:    dtos.DryRunScreeningQuery{Type: "DEPOSIT", Amount: "50", Currency: "USD", KYCStatus: "VERIFIED"},
+			decision: dtos.ScreeningDecisionAllow,
+			flags:    []string{},
+		},
+		{
+			name:      "BlockFreshWallet",
+			query:     dtos.DryRunScreeningQuery{Type: "withdraw", Amount: "10.00", Currency: "usd", WalletAge: "2h", KYCStatus: "VERIFIED"},
+			decision:  dtos.ScreeningDecisionBlock,
+			blockedBy: "fresh-wallet-withdraw",
+			flags:     []string{},
+		},
+		{
+			name:     "FlagUnverified",
+			query:    dtos.DryRunScreeningQuery{Type: "DEPOSIT", Amount: "1000.01", Currency: "EUR", KYCStatus: "pending", Country: "de"},
+			decision: dtos.ScreeningDecisionFlag,
+			flags:    []string{"unverified-large"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := uc.Execute(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Decision != tt.decision {
+				t.Errorf("Decision = %s, want %s", result.Decision, tt.decision)
+			}
+			if result.BlockedBy != tt.blockedBy {
+				t.Errorf("BlockedBy = %q, want %q", result.BlockedBy, tt.blockedBy)
+			}
+			if !reflect.DeepEqual(result.Flags, tt.flags) {
+				t.Errorf("Flags = %v, want %v", result.Flags, tt.flags)
+			}
+		})
+	}
+}
+
+func TestDryRunScreeningUseCase_Validation(t *testing.T) {
+	uc := screening.NewDryRunScreeningUseCase(nil)
+	valid := dtos.DryRunScreeningQuery{Type: "DEPOSIT", Amount: "1", Currency: "USD"}
+
+	tests := []struct {
+		field  string
+		mutate func(q *dtos.DryRunScreeningQuery)
+	}{
+		{"type", func(q *dtos.DryRunScreeningQuery) { q.Type = "CHARGEBACK" }},
+		{"currency", func(q *dtos.DryRunScreeningQuery) { q.Currency = "XXX" }},
+		{"amount", func(q *dtos.DryRunScreeningQuery) { q.Amount = "0" }},
+		{"wallet_age", func(q *dtos.DryRunScreeningQuery) { q.WalletAge = "two days" }},
+		{"kyc_status", func(q *dtos.DryRunScreeningQuery) { q.KYCStatus = "MAYBE" }},
+		{"country", func(q *dtos.DryRunScreeningQuery) { q.Country = "GER" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			query := valid
+			tt.mutate(&query)
+
+			_, err := uc.Execute(context.Background(), query)
+			var validationErr domainErrors.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("Expected validation error on %s, got %v", tt.field, err)
+			}
+		})
+	}
+
+	result, err := uc.Execute(context.Background(), valid)
+	if err != nil || result.Decision != dtos.ScreeningDecisionAllow {
+		t.Errorf("Disabled screening must allow, got %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,67 @@
+// Package security - ListSecurityEvents use case для просмотра журнала аутентификации.
+package security
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// ListSecurityEventsUseCase - use case для получения журнала безопасности.
+//
+// Доступ только для admin - проверяется в роутере (группа /admin).
+type ListSecurityEventsUseCase struct {
+	eventRepo ports.SecurityEventRepository
+}
+
+// NewListSecurityEventsUseCase создаёт новый use case.
+func NewListSecurityEventsUseCase(eventRepo ports.SecurityEventRepository) *ListSecurityEventsUseCase {
+	return &ListSecurityEventsUseCase{eventRepo: eventRepo}
+}
+
+// Execute возвращает события с фильтрацией и пагинацией, новые первыми.
+func (uc *ListSecurityEventsUseCase) Execute(ctx context.Context, query dtos.ListSecurityEventsQuery) (*dtos.SecurityEventListDTO, error) {
+	filter := ports.SecurityEventFilter{
+		Principal: query.Principal,
+		IP:        query.IP,
+	}
+
+	if query.Type != nil {
+		eventType := entities.SecurityEventType(*query.Type)
+		if !eventType.IsValid() {
+			return nil, errors.ValidationError{Field: "type", Message: "unknown security event type"}
+		}
+		filter.Type = &eventType
+	}
+
+	if query.OccurredFrom != nil {
+		from := query.OccurredFrom.UTC()
+		filter.OccurredFrom = &from
+	}
+
+	if query.OccurredTo != nil {
+		to := query.OccurredTo.UTC()
+		filter.OccurredTo = &to
+	}
+
+	securityEvents, err := uc.eventRepo.List(ctx, filter, query.Offset, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list security events: %w", err)
+	}
+
+	result := &dtos.SecurityEventListDTO{
+		Events:     make([]dtos.SecurityEventDTO, len(securityEvents)),
+		TotalCount: len(securityEvents),
+		Offset:     query.Offset,
+		Limit:      query.Limit,
+	}
+	for i, event := range securityEvents {
+		result.Events[i] = dtos.ToSecurityEventDTO(event)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package security_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/usecases/security"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+func TestListSecurityEventsUseCase_Filters(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewSecurityEventRepository(memory.NewStore())
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	principal := uuid.NewString()
+
+	for i, eventType := range []entities.SecurityEventType{
+		entities.SecurityEventAuthFailed,
+		entities.SecurityEventAuthSucceeded,
+		entities.SecurityEventAuthFailed,
+	} {
+		event := entities.ReconstructSecurityEvent(uuid.New(), principal, eventType, "10.0.0.1", "test", base.Add(time.Duration(i)*time.Minute))
+		if err := repo.Append(ctx, event); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	eventType := "AUTH_FAILED"
+	// Смещение +03:00 приводится к UTC: from = base+1m
+	from := base.Add(time.Minute).In(time.FixedZone("MSK", 3*60*60))
+	result, err := security.NewListSecurityEventsUseCase(repo).Execute(ctx, dtos.ListSecurityEventsQuery{
+		Principal:    &principal,
+		Type:         &eventType,
+		OccurredFrom: &from,
+		Limit:        20,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(result.Events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(result.Events))
+	}
+	got := result.Events[0]
+	if got.Type != "AUTH_FAILED" || !got.OccurredAt.Equal(base.Add(2*time.Minute)) {
+		t.Errorf("Unexpected event: %+v", got)
+	}
+	if got.OccurredAt.Location() != time.UTC {
+		t.Errorf("Expected UTC occurred_at, got %s", got.OccurredAt.Location())
+	}
+}
+
+func TestListSecurityEventsUseCase_UnknownType(t *testing.T) {
+	eventType := "LOGIN"
+	_, err := security.NewListSecurityEventsUseCase(memory.NewSecurityEventRepository(memory.NewStore())).
+		Execute(context.Background(), dtos.ListSecurityEventsQuery{Type: &eventType, Limit: 20})
+
+	if _, ok := err.(domainErrors.ValidationError); !ok {
+		t.Errorf("Expected ValidationError, got %v", err)
+	}
+}
@@ -0,0 +1,367 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/google/uuid"
+)
+
+// Метки metadata, которые выгрузка добавляет к синтетическим и переписанным транзакциям.
+const (
+	MetadataOpeningBalance      = "snapshot_opening_balance"
+	MetadataOriginalType        = "snapshot_original_type"
+	MetadataCounterpartyWallet  = "snapshot_counterparty_wallet_id"
+	openingIdempotencyNamespace = "snapshot-opening"
+)
+
+// maskedEmailDomain - домен замаскированных email.
+const maskedEmailDomain = "example.com"
+
+// ExportSnapshotUseCase - выгрузка пользователей в bundle.
+//
+// Bundle самодостаточен:
+// 1. Транзакции до Since сворачиваются в одну ADJUSTMENT "opening balance"
+// на кошелёк (сумма их ledger, а не сохранённый баланс)
+// 2. Переводы с кошельками вне bundle переписываются в WITHDRAW (исходящий)
+// или DEPOSIT на наш кошелёк (входящий) с теми же ID; исходный тип и
+// чужой кошелёк остаются в metadata
+//
+// Так сумма ledger каждого кошелька в bundle равна его балансу.
+type ExportSnapshotUseCase struct {
+	userRepo        ports.UserRepository
+	walletRepo      ports.WalletReader
+	transactionRepo ports.TransactionReader
+
+	// now - источник времени (подменяется в тестах)
+	now func() time.Time
+}
+
+// NewExportSnapshotUseCase создаёт новый use case.
+func NewExportSnapshotUseCase(
+	userRepo ports.UserRepository,
+	walletRepo ports.WalletReader,
+	transactionRepo ports.TransactionReader,
+) *ExportSnapshotUseCase {
+	return &ExportSnapshotUseCase{
+		userRepo:        userRepo,
+		walletRepo:      walletRepo,
+		transactionRepo: transactionRepo,
+		now:             func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Execute выгружает пользователей, их кошельки и транзакции.
+//
+// Errors:
+//   - ValidationError: Пустой или невалидный список пользователей
+//   - ErrEntityNotFound: Пользователь не найден
+//   - LEDGER_INCONSISTENT: История кошелька до Since уходит в минус
+func (uc *ExportSnapshotUseCase) Execute(ctx context.Context, query dtos.ExportSnapshotQuery) (*dtos.SnapshotBundle, error) {
+	userIDs, err := parseUserIDs(query.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &dtos.SnapshotBundle{
+		FormatVersion: dtos.SnapshotFormatVersion,
+		ExportedAt:    uc.now(),
+		Users:         []dtos.SnapshotUser{},
+		Wallets:       []dtos.SnapshotWallet{},
+		Transactions:  []dtos.SnapshotTransaction{},
+	}
+
+	var since time.Time
+	if query.Since > 0 {
+		since = bundle.ExportedAt.Add(-query.Since)
+		bundle.Since = &since
+	}
+
+	// 1. Пользователи и кошельки
+	wallets := make(map[uuid.UUID]*entities.Wallet)
+	var walletOrder []*entities.Wallet
+	for _, userID := range userIDs {
+		user, err := uc.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load user %s: %w", userID, err)
+		}
+		bundle.Users = append(bundle.Users, maskUser(user))
+
+		userWallets, err := uc.walletRepo.FindByUserID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load wallets of user %s: %w", userID, err)
+		}
+		for _, w := range userWallets {
+			wallets[w.ID()] = w
+			walletOrder = append(walletOrder, w)
+			bundle.Wallets = append(bundle.Wallets, toSnapshotWallet(w))
+		}
+	}
+
+	// 2. История кошельков (перевод между кошельками bundle встречается дважды)
+	seen := make(map[uuid.UUID]bool)
+	var history []*entities.Transaction
+	for _, w := range walletOrder {
+		txs, err := walletHistory(ctx, uc.transactionRepo, w.ID())
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range txs {
+			if !seen[tx.ID()] {
+				seen[tx.ID()] = true
+				history = append(history, tx)
+			}
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		if !history[i].CreatedAt().Equal(history[j].CreatedAt()) {
+			return history[i].CreatedAt().Before(history[j].CreatedAt())
+		}
+		return history[i].ID().String() < history[j].ID().String()
+	})
+
+	// 3. Транзакции до since - в opening balance, остальные - в bundle
+	opening := make(map[uuid.UUID]int64)
+	var records []dtos.SnapshotTransaction
+	for _, tx := range history {
+		if tx.CreatedAt().Before(since) {
+			entries, err := ledgerEntries(tx)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				if _, ok := wallets[entry.walletID]; ok {
+					opening[entry.walletID] += entry.cents
+				}
+			}
+			continue
+		}
+
+		record, err := toBundleTransaction(tx, wallets)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	for _, w := range walletOrder {
+		cents := opening[w.ID()]
+		if cents < 0 {
+			return nil, errors.NewBusinessRuleViolation(
+				"LEDGER_INCONSISTENT",
+				"wallet history before the snapshot window has a negative balance",
+				map[string]interface{}{"walletId": w.ID().String(), "ledgerCents": cents},
+			)
+		}
+		if cents > 0 {
+			bundle.Transactions = append(bundle.Transactions, openingBalance(w, cents, since))
+		}
+	}
+	bundle.Transactions = append(bundle.Transactions, records...)
+
+	return bundle, nil
+}
+
+// toBundleTransaction переводит транзакцию в запись bundle, переписывая
+// переводы через границу bundle.
+func toBundleTransaction(tx *entities.Transaction, wallets map[uuid.UUID]*entities.Wallet) (dtos.SnapshotTransaction, error) {
+	record, err := toSnapshotTransaction(tx)
+	if err != nil {
+		return dtos.SnapshotTransaction{}, err
+	}
+
+	dest := tx.DestinationWalletID()
+	_, sourceInBundle := wallets[tx.WalletID()]
+	if dest == nil {
+		return record, nil
+	}
+	destWallet, destInBundle := wallets[*dest]
+
+	switch {
+	case sourceInBundle && destInBundle:
+		return record, nil
+
+	case sourceInBundle:
+		// Исходящий перевод: списание с нашего кошелька
+		record.Type = string(entities.TransactionTypeWithdraw)
+		record.DestinationWalletID = nil
+		record.Metadata, err = withMetadata(tx, map[string]interface{}{
+			MetadataOriginalType:       string(tx.Type()),
+			MetadataCounterpartyWallet: dest.String(),
+		})
+		return record, err
+
+	default:
+		// Входящий перевод: зачисление на наш кошелёк под тем же ID
+		credited := tx.NetAmount()
+		if tx.Type() == entities.TransactionTypeExchange {
+			if credited, err = ExchangeCredit(tx); err != nil {
+				return dtos.SnapshotTransaction{}, err
+			}
+		}
+		record.WalletID = dest.String()
+		record.Type = string(entities.TransactionTypeDeposit)
+		record.CurrencyCode = destWallet.Currency().Code()
+		record.AmountCents = credited.Cents()
+		record.FeeCents = 0
+		record.NetCents = credited.Cents()
+		record.DestinationWalletID = nil
+		record.Metadata, err = withMetadata(tx, map[string]interface{}{
+			MetadataOriginalType:       string(tx.Type()),
+			MetadataCounterpartyWallet: tx.WalletID().String(),
+		})
+		return record, err
+	}
+}
+
+// ============================================
+// Mapping
+// ============================================
+
+func parseUserIDs(raw []string) ([]uuid.UUID, error) {
+	if len(raw) == 0 {
+		return nil, errors.ValidationError{Field: "user_ids", Message: "at least one user is required"}
+	}
+
+	seen := make(map[uuid.UUID]bool, len(raw))
+	ids := make([]uuid.UUID, 0, len(raw))
+	for _, s := range raw {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, errors.ValidationError{Field: "user_ids", Message: fmt.Sprintf("invalid user id %q", s)}
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// maskUser заменяет PII детерминированными значениями от ID:
+// повторная выгрузка того же пользователя даёт тот же email.
+func maskUser(user *entities.User) dtos.SnapshotUser {
+	short := user.ID().String()[:8]
+	terms := user.TermsAcceptance()
+	return dtos.SnapshotUser{
+		ID:           user.ID().String(),
+		Email:        "user-" + short + "@" + maskedEmailDomain,
+		FullName:     "Snapshot User " + short,
+		KYCStatus:    string(user.KYCStatus()),
+		Jurisdiction: user.Jurisdiction(),
+		CreatedAt:    user.CreatedAt().UTC(),
+		UpdatedAt:    user.UpdatedAt().UTC(),
+
+		TermsVersion:       terms.Version,
+		TermsAcceptedAt:    terms.AcceptedAt,
+		TermsGrandfathered: terms.Grandfathered,
+	}
+}
+
+func toSnapshotWallet(w *entities.Wallet) dtos.SnapshotWallet {
+	return dtos.SnapshotWallet{
+		ID:                     w.ID().String(),
+		UserID:                 w.UserID().String(),
+		CurrencyCode:           w.Currency().Code(),
+		WalletType:             string(w.WalletType()),
+		Status:                 string(w.Status()),
+		DailyLimitCents:        w.DailyLimit().Cents(),
+		MonthlyLimitCents:      w.MonthlyLimit().Cents(),
+		Jurisdiction:           w.Jurisdiction(),
+		CreatedAt:              w.CreatedAt().UTC(),
+		MaxPendingTransactions: w.MaxPendingTransactions(),
+		UpdatedAt:              w.UpdatedAt().UTC(),
+		ExportedBalanceCents:   w.AvailableBalance().Cents() + w.PendingBalance().Cents(),
+	}
+}
+
+func toSnapshotTransaction(tx *entities.Transaction) (dtos.SnapshotTransaction, error) {
+	metadata, err := withMetadata(tx, nil)
+	if err != nil {
+		return dtos.SnapshotTransaction{}, err
+	}
+
+	record := dtos.SnapshotTransaction{
+		ID:                    tx.ID().String(),
+		WalletID:              tx.WalletID().String(),
+		IdempotencyKey:        tx.IdempotencyKey(),
+		Type:                  string(tx.Type()),
+		Status:                string(tx.Status()),
+		CurrencyCode:          tx.Amount().Currency().Code(),
+		AmountCents:           tx.Amount().Cents(),
+		FeeCents:              tx.FeeAmount().Cents(),
+		NetCents:              tx.NetAmount().Cents(),
+		ExternalReference:     tx.ExternalReference(),
+		ExternalReferenceHash: tx.ExternalReferenceHash(),
+		Description:           tx.Description(),
+		Metadata:              metadata,
+		FailureReason:         tx.FailureReason(),
+		FailureCategory:       string(tx.FailureCategory()),
+		RetryCount:            tx.RetryCount(),
+		NextRetryAt:           tx.NextRetryAt(),
+		Jurisdiction:          tx.Jurisdiction(),
+		CreatedByVersion:      tx.CreatedByVersion(),
+		CreatedAt:             tx.CreatedAt().UTC(),
+		UpdatedAt:             tx.UpdatedAt().UTC(),
+		ProcessedAt:           tx.ProcessedAt(),
+		CompletedAt:           tx.CompletedAt(),
+	}
+	if dest := tx.DestinationWalletID(); dest != nil {
+		s := dest.String()
+		record.DestinationWalletID = &s
+	}
+	return record, nil
+}
+
+// withMetadata сериализует metadata транзакции, дополненную extra.
+func withMetadata(tx *entities.Transaction, extra map[string]interface{}) (json.RawMessage, error) {
+	if len(tx.Metadata()) == 0 && len(extra) == 0 {
+		return nil, nil
+	}
+
+	merged := make(map[string]interface{}, len(tx.Metadata())+len(extra))
+	for k, v := range tx.Metadata() {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s: failed to encode metadata: %w", tx.ID(), err)
+	}
+	return raw, nil
+}
+
+// openingBalance - синтетическая ADJUSTMENT на сумму истории до since.
+// ID и ключ детерминированы: повторная выгрузка с тем же since даёт ту же запись.
+func openingBalance(w *entities.Wallet, cents int64, since time.Time) dtos.SnapshotTransaction {
+	stamp := since.UTC().Format(time.RFC3339Nano)
+	at := since.UTC()
+	return dtos.SnapshotTransaction{
+		ID:             uuid.NewSHA1(w.ID(), []byte(openingIdempotencyNamespace+":"+stamp)).String(),
+		WalletID:       w.ID().String(),
+		IdempotencyKey: valueobjects.DerivedIdempotencyKey(openingIdempotencyNamespace, w.ID().String(), stamp),
+		Type:           string(entities.TransactionTypeAdjustment),
+		Status:         string(entities.TransactionStatusCompleted),
+		CurrencyCode:   w.Currency().Code(),
+		AmountCents:    cents,
+		NetCents:       cents,
+		Description:    "Opening balance carried into snapshot",
+		Metadata:       json.RawMessage(`{"` + MetadataOpeningBalance + `":true}`),
+		Jurisdiction:   w.Jurisdiction(),
+		CreatedAt:      at,
+		UpdatedAt:      at,
+		ProcessedAt:    &at,
+		CompletedAt:    &at,
+	}
+}
@@ -0,0 +1,464 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/google/uuid"
+)
+
+// importKeySuffix добавляется к idempotency key, уже занятому в окружении.
+const importKeySuffix = "-snapshot"
+
+// ImportSnapshotUseCase - загрузка bundle в непроизводственное окружение.
+//
+// Сценарий (один UnitOfWork - bundle загружается целиком или никак):
+// 1. Пользователи: существующий ID пропускается; занятый email получает суффикс
+// 2. Кошельки: существующий ID пропускается вместе со всеми транзакциями,
+// которые его касаются - его баланс и ledger принадлежат окружению
+// 3. Балансы новых кошельков считаются по загружаемым транзакциям,
+// экспортированный баланс не используется
+// 4. Транзакции: занятый idempotency key получает суффикс
+// 5. Каждый новый кошелёк сверяется с ledger (ReconcileWallet)
+//
+// Повторный импорт того же bundle ничего не меняет. События не публикуются:
+// это перенос данных, а не бизнес-операции.
+type ImportSnapshotUseCase struct {
+	userRepo        ports.UserRepository
+	walletRepo      ports.WalletRepository
+	transactionRepo ports.TransactionRepository
+	uow             ports.UnitOfWork
+
+	// production - импорт запрещён
+	production bool
+}
+
+// NewImportSnapshotUseCase создаёт новый use case.
+// production = true (APP_ENVIRONMENT=production) отключает импорт.
+func NewImportSnapshotUseCase(
+	userRepo ports.UserRepository,
+	walletRepo ports.WalletRepository,
+	transactionRepo ports.TransactionRepository,
+	uow ports.UnitOfWork,
+	production bool,
+) *ImportSnapshotUseCase {
+	return &ImportSnapshotUseCase{
+		userRepo:        userRepo,
+		walletRepo:      walletRepo,
+		transactionRepo: transactionRepo,
+		uow:             uow,
+		production:      production,
+	}
+}
+
+// parsedBundle - bundle, разобранный в entities до начала транзакции.
+type parsedBundle struct {
+	users        []*entities.User
+	wallets      []dtos.SnapshotWallet
+	transactions []*entities.Transaction
+}
+
+// Execute загружает bundle и возвращает отчёт.
+//
+// Errors:
+//   - PRODUCTION_IMPORT_FORBIDDEN: Окружение - production
+//   - ValidationError: Bundle другой версии или с невалидными полями
+//   - LEDGER_INCONSISTENT: Загружаемый ledger кошелька уходит в минус
+//   - LEDGER_MISMATCH: Баланс после загрузки не сходится с ledger
+func (uc *ImportSnapshotUseCase) Execute(ctx context.Context, cmd dtos.ImportSnapshotCommand) (*dtos.SnapshotImportSummaryDTO, error) {
+	if uc.production {
+		return nil, errors.NewDomainError("PRODUCTION_IMPORT_FORBIDDEN", "snapshot import is disabled in production", nil)
+	}
+
+	parsed, err := parseBundle(cmd.Bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	var summary *dtos.SnapshotImportSummaryDTO
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		summary = &dtos.SnapshotImportSummaryDTO{Balances: map[string]string{}}
+
+		if err := uc.importUsers(txCtx, parsed.users, summary); err != nil {
+			return err
+		}
+
+		// Новые кошельки (пока без баланса)
+		newWallets := make(map[uuid.UUID]dtos.SnapshotWallet)
+		for _, w := range parsed.wallets {
+			id := uuid.MustParse(w.ID)
+			exists, err := found(uc.walletRepo.FindByID(txCtx, id))
+			if err != nil {
+				return fmt.Errorf("failed to check wallet %s: %w", id, err)
+			}
+			if exists {
+				summary.Wallets.Skipped++
+				continue
+			}
+			newWallets[id] = w
+		}
+
+		// Транзакции, касающиеся только новых кошельков
+		var importable []*entities.Transaction
+		balances := make(map[uuid.UUID]int64, len(newWallets))
+		for _, tx := range parsed.transactions {
+			if !touchesOnly(tx, newWallets) {
+				summary.Transactions.Skipped++
+				continue
+			}
+			exists, err := found(uc.transactionRepo.FindByID(txCtx, tx.ID()))
+			if err != nil {
+				return fmt.Errorf("failed to check transaction %s: %w", tx.ID(), err)
+			}
+			if exists {
+				summary.Transactions.Skipped++
+				continue
+			}
+
+			entries, err := ledgerEntries(tx)
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				balances[entry.walletID] += entry.cents
+			}
+			importable = append(importable, tx)
+		}
+
+		// Кошельки сохраняются до транзакций (FK), уже с пересчитанным балансом
+		for _, w := range parsed.wallets {
+			id := uuid.MustParse(w.ID)
+			if _, ok := newWallets[id]; !ok {
+				continue
+			}
+			wallet, err := reconstructWallet(w, balances[id])
+			if err != nil {
+				return err
+			}
+			if err := uc.walletRepo.Save(txCtx, wallet); err != nil {
+				return fmt.Errorf("failed to save wallet %s: %w", id, err)
+			}
+			summary.Wallets.Imported++
+			summary.Balances[w.ID] = wallet.AvailableBalance().String()
+		}
+
+		for _, tx := range importable {
+			if err := uc.saveTransaction(txCtx, tx, summary); err != nil {
+				return err
+			}
+			summary.Transactions.Imported++
+		}
+
+		for id := range newWallets {
+			if err := ReconcileWallet(txCtx, uc.walletRepo, uc.transactionRepo, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// importUsers сохраняет новых пользователей, переименовывая занятые email.
+func (uc *ImportSnapshotUseCase) importUsers(ctx context.Context, users []*entities.User, summary *dtos.SnapshotImportSummaryDTO) error {
+	for _, user := range users {
+		exists, err := found(uc.userRepo.FindByID(ctx, user.ID()))
+		if err != nil {
+			return fmt.Errorf("failed to check user %s: %w", user.ID(), err)
+		}
+		if exists {
+			summary.Users.Skipped++
+			continue
+		}
+
+		email, err := uc.freeEmail(ctx, user.Email())
+		if err != nil {
+			return err
+		}
+		if email != user.Email() {
+			summary.RenamedEmails = append(summary.RenamedEmails, dtos.SnapshotRename{
+				ID: user.ID().String(), From: user.Email(), To: email,
+			})
+			user = entities.ReconstructUser(user.ID(), email, user.FullName(), user.KYCStatus(), nil,
+				user.Jurisdiction(), "", user.TermsAcceptance(), user.CreatedAt(), user.UpdatedAt())
+		}
+
+		if err := uc.userRepo.Save(ctx, user); err != nil {
+			return fmt.Errorf("failed to save user %s: %w", user.ID(), err)
+		}
+		summary.Users.Imported++
+	}
+	return nil
+}
+
+// freeEmail возвращает email или первый свободный вариант local-N@domain.
+// Не local+N: для gmail плюс-тег отбрасывается при проверке уникальности.
+func (uc *ImportSnapshotUseCase) freeEmail(ctx context.Context, email string) (string, error) {
+	local, domain, _ := strings.Cut(email, "@")
+	candidate := email
+	for n := 1; ; n++ {
+		taken, err := uc.userRepo.ExistsByEmail(ctx, candidate)
+		if err != nil {
+			return "", fmt.Errorf("failed to check email: %w", err)
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d@%s", local, n, domain)
+	}
+}
+
+// saveTransaction сохраняет транзакцию, переименовывая занятый idempotency key.
+func (uc *ImportSnapshotUseCase) saveTransaction(ctx context.Context, tx *entities.Transaction, summary *dtos.SnapshotImportSummaryDTO) error {
+	key := tx.IdempotencyKey()
+	for n := 0; ; n++ {
+		candidate := key
+		if n > 0 {
+			candidate = fmt.Sprintf("%s%s-%d", key, importKeySuffix, n)
+		}
+		taken, err := found(uc.transactionRepo.FindByIdempotencyKey(ctx, candidate))
+		if err != nil {
+			return fmt.Errorf("failed to check idempotency key: %w", err)
+		}
+		if taken {
+			continue
+		}
+
+		if candidate != key {
+			summary.RenamedIdempotencyKeys = append(summary.RenamedIdempotencyKeys, dtos.SnapshotRename{
+				ID: tx.ID().String(), From: key, To: candidate,
+			})
+			if tx, err = withIdempotencyKey(tx, candidate); err != nil {
+				return err
+			}
+		}
+		if err := uc.transactionRepo.Save(ctx, tx); err != nil {
+			return fmt.Errorf("failed to save transaction %s: %w", tx.ID(), err)
+		}
+		return nil
+	}
+}
+
+// found превращает результат Find* в признак существования.
+func found[T any](_ T, err error) (bool, error) {
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.IsNotFound(err):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// touchesOnly - все кошельки транзакции входят в wallets.
+func touchesOnly(tx *entities.Transaction, wallets map[uuid.UUID]dtos.SnapshotWallet) bool {
+	if _, ok := wallets[tx.WalletID()]; !ok {
+		return false
+	}
+	if dest := tx.DestinationWalletID(); dest != nil {
+		if _, ok := wallets[*dest]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ============================================
+// Parsing
+// ============================================
+
+// parseBundle проверяет bundle и разбирает его в entities.
+func parseBundle(bundle *dtos.SnapshotBundle) (*parsedBundle, error) {
+	if bundle == nil {
+		return nil, errors.ValidationError{Field: "bundle", Message: "bundle is required"}
+	}
+	if bundle.FormatVersion != dtos.SnapshotFormatVersion {
+		return nil, errors.ValidationError{
+			Field:   "format_version",
+			Message: fmt.Sprintf("unsupported format version %d (expected %d)", bundle.FormatVersion, dtos.SnapshotFormatVersion),
+		}
+	}
+
+	parsed := &parsedBundle{wallets: bundle.Wallets}
+
+	for i, u := range bundle.Users {
+		id, err := uuid.Parse(u.ID)
+		if err != nil {
+			return nil, invalidField("users", i, "id", u.ID)
+		}
+		status := entities.KYCStatus(u.KYCStatus)
+		if !status.IsValid() {
+			return nil, invalidField("users", i, "kyc_status", u.KYCStatus)
+		}
+		terms := entities.TermsAcceptance{
+			Version:       u.TermsVersion,
+			AcceptedAt:    u.TermsAcceptedAt,
+			Grandfathered: u.TermsGrandfathered,
+		}
+		parsed.users = append(parsed.users, entities.ReconstructUser(
+			id, u.Email, u.FullName, status, nil, u.Jurisdiction, "", terms, u.CreatedAt, u.UpdatedAt,
+		))
+	}
+
+	for i, w := range bundle.Wallets {
+		if _, err := reconstructWallet(w, 0); err != nil {
+			return nil, fmt.Errorf("wallets[%d]: %w", i, err)
+		}
+	}
+
+	for i, t := range bundle.Transactions {
+		tx, err := reconstructTransaction(t)
+		if err != nil {
+			return nil, fmt.Errorf("transactions[%d]: %w", i, err)
+		}
+		parsed.transactions = append(parsed.transactions, tx)
+	}
+
+	return parsed, nil
+}
+
+func invalidField(collection string, index int, field, value string) error {
+	return errors.ValidationError{
+		Field:   fmt.Sprintf("%s[%d].%s", collection, index, field),
+		Message: fmt.Sprintf("invalid value %q", value),
+	}
+}
+
+// reconstructWallet собирает кошелёк с балансом balanceCents (pending = 0).
+func reconstructWallet(w dtos.SnapshotWallet, balanceCents int64) (*entities.Wallet, error) {
+	id, err := uuid.Parse(w.ID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "wallet.id", Message: fmt.Sprintf("invalid value %q", w.ID)}
+	}
+	userID, err := uuid.Parse(w.UserID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "wallet.user_id", Message: fmt.Sprintf("invalid value %q", w.UserID)}
+	}
+	currency, err := valueobjects.NewCurrency(w.CurrencyCode)
+	if err != nil {
+		return nil, fmt.Errorf("wallet %s: %w", id, err)
+	}
+	walletType, status := entities.WalletType(w.WalletType), entities.WalletStatus(w.Status)
+	if !walletType.IsValid() || !status.IsValid() {
+		return nil, errors.ValidationError{Field: "wallet.status", Message: fmt.Sprintf("wallet %s: invalid type or status", id)}
+	}
+
+	if balanceCents < 0 {
+		return nil, errors.NewBusinessRuleViolation(
+			"LEDGER_INCONSISTENT",
+			"imported ledger of the wallet has a negative balance",
+			map[string]interface{}{"walletId": id.String(), "ledgerCents": balanceCents},
+		)
+	}
+	balance, err := valueobjects.NewMoneyFromCents(balanceCents, currency)
+	if err != nil {
+		return nil, fmt.Errorf("wallet %s: %w", id, err)
+	}
+	dailyLimit, err := valueobjects.NewMoneyFromCents(w.DailyLimitCents, currency)
+	if err != nil {
+		return nil, fmt.Errorf("wallet %s: daily limit: %w", id, err)
+	}
+	monthlyLimit, err := valueobjects.NewMoneyFromCents(w.MonthlyLimitCents, currency)
+	if err != nil {
+		return nil, fmt.Errorf("wallet %s: monthly limit: %w", id, err)
+	}
+
+	return entities.ReconstructWallet(
+		id, userID, currency, walletType, status,
+		balance, valueobjects.Zero(currency), 0,
+		dailyLimit, monthlyLimit, w.Jurisdiction,
+		w.MaxPendingTransactions,
+		w.CreatedAt, w.UpdatedAt,
+	), nil
+}
+
+func reconstructTransaction(t dtos.SnapshotTransaction) (*entities.Transaction, error) {
+	id, err := uuid.Parse(t.ID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "id", Message: fmt.Sprintf("invalid value %q", t.ID)}
+	}
+	walletID, err := uuid.Parse(t.WalletID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "wallet_id", Message: fmt.Sprintf("invalid value %q", t.WalletID)}
+	}
+	var destination *uuid.UUID
+	if t.DestinationWalletID != nil {
+		dest, err := uuid.Parse(*t.DestinationWalletID)
+		if err != nil {
+			return nil, errors.ValidationError{Field: "destination_wallet_id", Message: fmt.Sprintf("invalid value %q", *t.DestinationWalletID)}
+		}
+		destination = &dest
+	}
+
+	txType, status := entities.TransactionType(t.Type), entities.TransactionStatus(t.Status)
+	if !txType.IsValid() || !status.IsValid() {
+		return nil, errors.ValidationError{Field: "type", Message: fmt.Sprintf("transaction %s: invalid type or status", id)}
+	}
+
+	// Снимки до появления failure_category: категория по коду причины,
+	// как в backfill миграции 000026
+	failureCategory := entities.FailureCategory(t.FailureCategory)
+	if failureCategory == "" && status == entities.TransactionStatusFailed {
+		failureCategory = entities.FailureCategoryForReason(t.FailureReason, entities.FailureCategoryProvider)
+	}
+	if failureCategory != "" && !failureCategory.IsValid() {
+		return nil, errors.ValidationError{Field: "failure_category", Message: fmt.Sprintf("transaction %s: invalid value %q", id, t.FailureCategory)}
+	}
+
+	currency, err := valueobjects.NewCurrency(t.CurrencyCode)
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s: %w", id, err)
+	}
+	amount, err := valueobjects.NewMoneyFromCents(t.AmountCents, currency)
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s: amount: %w", id, err)
+	}
+	fee, err := valueobjects.NewMoneyFromCents(t.FeeCents, currency)
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s: fee: %w", id, err)
+	}
+	net, err := valueobjects.NewMoneyFromCents(t.NetCents, currency)
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s: net: %w", id, err)
+	}
+
+	// Снимки до появления external_reference_hash содержат ссылку как есть
+	extRef, extRefHash := t.ExternalReference, t.ExternalReferenceHash
+	if extRef != "" && extRefHash == "" {
+		extRefHash = valueobjects.HashExternalReference(extRef)
+		extRef, _ = valueobjects.RedactSensitiveData(extRef)
+	}
+
+	tx, err := entities.ReconstructTransaction(
+		id, walletID, t.IdempotencyKey, txType, status,
+		amount, fee, net, destination,
+		extRef, extRefHash, t.Description, t.Metadata,
+		t.FailureReason, failureCategory, t.RetryCount, t.NextRetryAt, t.Jurisdiction, t.CreatedByVersion,
+		t.CreatedAt, t.UpdatedAt, t.ProcessedAt, t.CompletedAt,
+		"",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s: invalid metadata: %w", id, err)
+	}
+	return tx, nil
+}
+
+// withIdempotencyKey - копия транзакции с другим idempotency key.
+func withIdempotencyKey(tx *entities.Transaction, key string) (*entities.Transaction, error) {
+	record, err := toSnapshotTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+	record.IdempotencyKey = key
+	return reconstructTransaction(record)
+}
@@ -0,0 +1,181 @@
+// Package snapshot - выгрузка и загрузка данных пользователей для клонирования окружений.
+//
+// Export собирает пользователей, их кошельки и транзакции за период в
+// самодостаточный bundle; Import загружает его в непроизводственное окружение
+// и пересчитывает балансы по загруженному ledger.
+//
+// Холдов (резервов средств) в модели нет: pending баланс не используется
+// use case'ами, поэтому bundle их не содержит, а pending после импорта - 0.
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/google/uuid"
+)
+
+// pageSize - размер страницы при чтении истории кошелька.
+const pageSize = 500
+
+// ledgerEntry - изменение доступного баланса одного кошелька (в минимальных единицах).
+type ledgerEntry struct {
+	walletID uuid.UUID
+	cents    int64
+}
+
+// ledgerEntries возвращает изменения балансов от транзакции - так же, как
+// их применяют use cases transaction/wallet. Учитываются только COMPLETED.
+//
+//   - DEPOSIT, REFUND, ADJUSTMENT: +amount
+//   - WITHDRAW, PAYOUT, FEE без родителя: -(net + fee)
+//   - FEE родительской транзакции: 0 (списан вместе с родителем)
+//   - TRANSFER: -(net + fee) источнику, +net получателю
+//   - EXCHANGE: -amount источнику, +dest_amount (metadata) получателю
+func ledgerEntries(tx *entities.Transaction) ([]ledgerEntry, error) {
+	if !tx.IsCompleted() {
+		return nil, nil
+	}
+
+	payerDebit := -(tx.NetAmount().Cents() + tx.FeeAmount().Cents())
+
+	switch tx.Type() {
+	case entities.TransactionTypeDeposit, entities.TransactionTypeRefund, entities.TransactionTypeAdjustment:
+		return []ledgerEntry{{walletID: tx.WalletID(), cents: tx.Amount().Cents()}}, nil
+
+	case entities.TransactionTypeWithdraw, entities.TransactionTypePayout:
+		return []ledgerEntry{{walletID: tx.WalletID(), cents: payerDebit}}, nil
+
+	case entities.TransactionTypeFee:
+		if _, ok := tx.ParentTransactionID(); ok {
+			return nil, nil
+		}
+		return []ledgerEntry{{walletID: tx.WalletID(), cents: payerDebit}}, nil
+
+	case entities.TransactionTypeTransfer:
+		entries := []ledgerEntry{{walletID: tx.WalletID(), cents: payerDebit}}
+		if dest := tx.DestinationWalletID(); dest != nil {
+			entries = append(entries, ledgerEntry{walletID: *dest, cents: tx.NetAmount().Cents()})
+		}
+		return entries, nil
+
+	case entities.TransactionTypeExchange:
+		entries := []ledgerEntry{{walletID: tx.WalletID(), cents: -tx.Amount().Cents()}}
+		if dest := tx.DestinationWalletID(); dest != nil {
+			credited, err := ExchangeCredit(tx)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, ledgerEntry{walletID: *dest, cents: credited.Cents()})
+		}
+		return entries, nil
+
+	default:
+		return nil, fmt.Errorf("transaction %s: unsupported type %s", tx.ID(), tx.Type())
+	}
+}
+
+// LedgerDeltas возвращает изменение баланса каждого кошелька от транзакции
+// по тем же правилам, что и сверка ReconcileWallet (см. ledgerEntries).
+func LedgerDeltas(tx *entities.Transaction) (map[uuid.UUID]int64, error) {
+	entries, err := ledgerEntries(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	deltas := make(map[uuid.UUID]int64, len(entries))
+	for _, entry := range entries {
+		deltas[entry.walletID] += entry.cents
+	}
+	return deltas, nil
+}
+
+// ExchangeCredit читает зачисленную получателю сумму обмена из metadata
+// ("12.34 EUR"). Без неё зачисление по транзакции не восстановить.
+func ExchangeCredit(tx *entities.Transaction) (valueobjects.Money, error) {
+	raw, _ := tx.Metadata()["dest_amount"].(string)
+	amount, code, ok := strings.Cut(raw, " ")
+	if !ok {
+		return valueobjects.Money{}, fmt.Errorf("transaction %s: exchange without dest_amount metadata", tx.ID())
+	}
+
+	currency, err := valueobjects.NewCurrency(code)
+	if err != nil {
+		return valueobjects.Money{}, fmt.Errorf("transaction %s: invalid dest_amount currency: %w", tx.ID(), err)
+	}
+	money, err := valueobjects.NewMoney(amount, currency)
+	if err != nil {
+		return valueobjects.Money{}, fmt.Errorf("transaction %s: invalid dest_amount: %w", tx.ID(), err)
+	}
+	return money, nil
+}
+
+// walletHistory загружает все транзакции кошелька, включая входящие переводы.
+func walletHistory(ctx context.Context, transactionRepo ports.TransactionReader, walletID uuid.UUID) ([]*entities.Transaction, error) {
+	filter := ports.TransactionFilter{WalletID: &walletID}
+
+	var history []*entities.Transaction
+	for offset := 0; ; offset += pageSize {
+		page, err := transactionRepo.List(ctx, filter, offset, pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list transactions of wallet %s: %w", walletID, err)
+		}
+		history = append(history, page...)
+		if len(page) < pageSize {
+			return history, nil
+		}
+	}
+}
+
+// ReconcileWallet сверяет сохранённый баланс кошелька с суммой его ledger.
+//
+// Errors:
+//   - LEDGER_MISMATCH: Баланс не равен сумме COMPLETED транзакций
+func ReconcileWallet(
+	ctx context.Context,
+	walletRepo ports.WalletReader,
+	transactionRepo ports.TransactionReader,
+	walletID uuid.UUID,
+) error {
+	wallet, err := walletRepo.FindByID(ctx, walletID)
+	if err != nil {
+		return fmt.Errorf("failed to load wallet %s: %w", walletID, err)
+	}
+
+	history, err := walletHistory(ctx, transactionRepo, walletID)
+	if err != nil {
+		return err
+	}
+
+	var ledger int64
+	for _, tx := range history {
+		entries, err := ledgerEntries(tx)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.walletID == walletID {
+				ledger += entry.cents
+			}
+		}
+	}
+
+	balance := wallet.AvailableBalance().Cents() + wallet.PendingBalance().Cents()
+	if balance != ledger {
+		return errors.NewBusinessRuleViolation(
+			"LEDGER_MISMATCH",
+			"wallet balance does not match its ledger",
+			map[string]interface{}{
+				"walletId":     walletID.String(),
+				"balanceCents": balance,
+				"ledgerCents":  ledger,
+			},
+		)
+	}
+	return nil
+}
@@ -0,0 +1,348 @@
+package snapshot_test
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/usecases/snapshot"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/google/uuid"
+)
+
+// env - одно окружение (хранилище и репозитории).
+type env struct {
+	store        *memory.Store
+	users        *memory.UserRepository
+	wallets      *memory.WalletRepository
+	transactions *memory.TransactionRepository
+}
+
+func newEnv() *env {
+	store := memory.NewStore()
+	return &env{
+		store:        store,
+		users:        memory.NewUserRepository(store),
+		wallets:      memory.NewWalletRepository(store),
+		transactions: memory.NewTransactionRepository(store),
+	}
+}
+
+func (e *env) exporter() *snapshot.ExportSnapshotUseCase {
+	return snapshot.NewExportSnapshotUseCase(e.users, e.wallets, e.transactions)
+}
+
+func (e *env) importer(production bool) *snapshot.ImportSnapshotUseCase {
+	return snapshot.NewImportSnapshotUseCase(e.users, e.wallets, e.transactions, memory.NewUnitOfWork(e.store), production)
+}
+
+func (e *env) saveUser(t *testing.T, email string) *entities.User {
+	t.Helper()
+	user, err := entities.NewUser(email, "Real Name")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := e.users.Save(context.Background(), user); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+	return user
+}
+
+// saveWallet сохраняет кошелёк с готовым балансом (сумма ledger из сценария).
+func (e *env) saveWallet(t *testing.T, owner *entities.User, currency valueobjects.Currency, balanceCents int64) *entities.Wallet {
+	t.Helper()
+	balance, _ := valueobjects.NewMoneyFromCents(balanceCents, currency)
+	limit, _ := valueobjects.NewMoneyFromInt(10000, currency)
+	now := time.Now().UTC()
+	w := entities.ReconstructWallet(uuid.New(), owner.ID(), currency, entities.WalletTypeFiat, entities.WalletStatusActive,
+		balance, valueobjects.Zero(currency), 0, limit, limit, "", nil, now.AddDate(-1, 0, 0), now)
+	if err := e.wallets.Save(context.Background(), w); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+	return w
+}
+
+// saveTx сохраняет COMPLETED транзакцию, созданную daysAgo дней назад.
+func (e *env) saveTx(t *testing.T, wallet *entities.Wallet, txType entities.TransactionType, cents int64, daysAgo int, dest *entities.Wallet, metadata string) *entities.Transaction {
+	t.Helper()
+	amount, _ := valueobjects.NewMoneyFromCents(cents, wallet.Currency())
+	createdAt := time.Now().UTC().AddDate(0, 0, -daysAgo)
+	var destID *uuid.UUID
+	if dest != nil {
+		id := dest.ID()
+		destID = &id
+	}
+	var rawMetadata []byte
+	if metadata != "" {
+		rawMetadata = []byte(metadata)
+	}
+
+	tx, err := entities.ReconstructTransaction(uuid.New(), wallet.ID(), "key-"+uuid.NewString(), txType,
+		entities.TransactionStatusCompleted, amount, valueobjects.Zero(wallet.Currency()), amount, destID,
+		"", "", "", rawMetadata, "", "", 0, nil, "", "", createdAt, createdAt, &createdAt, &createdAt, "")
+	if err != nil {
+		t.Fatalf("ReconstructTransaction() error = %v", err)
+	}
+	if err := e.transactions.Save(context.Background(), tx); err != nil {
+		t.Fatalf("save transaction error = %v", err)
+	}
+	return tx
+}
+
+func (e *env) balance(t *testing.T, walletID uuid.UUID) string {
+	t.Helper()
+	w, err := e.wallets.FindByID(context.Background(), walletID)
+	if err != nil {
+		t.Fatalf("FindByID(%s) error = %v", walletID, err)
+	}
+	return w.AvailableBalance().String()
+}
+
+// fixture - alice (USD + EUR) и bob (USD) в исходном окружении.
+//
+// alice USD: +100 (200 дней назад, до окна), +50, -30 bob'у, +20 от bob'а,
+// -10 обмен в EUR (+9 EUR), -5 вывод = 125.00 USD; alice EUR = 9.00 EUR.
+type fixture struct {
+	source   *env
+	alice    *entities.User
+	aliceUSD *entities.Wallet
+	aliceEUR *entities.Wallet
+	exchange *entities.Transaction
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	usd, eur := valueobjects.MustNewCurrency("USD"), valueobjects.MustNewCurrency("EUR")
+
+	source := newEnv()
+	alice := source.saveUser(t, "alice@example.com")
+	bob := source.saveUser(t, "bob@example.com")
+	aliceUSD := source.saveWallet(t, alice, usd, 12500)
+	aliceEUR := source.saveWallet(t, alice, eur, 900)
+	bobUSD := source.saveWallet(t, bob, usd, 1000)
+
+	source.saveTx(t, aliceUSD, entities.TransactionTypeDeposit, 10000, 200, nil, "")
+	source.saveTx(t, aliceUSD, entities.TransactionTypeDeposit, 5000, 10, nil, "")
+	source.saveTx(t, aliceUSD, entities.TransactionTypeTransfer, 3000, 5, bobUSD, "")
+	source.saveTx(t, bobUSD, entities.TransactionTypeTransfer, 2000, 3, aliceUSD, "")
+	exchange := source.saveTx(t, aliceUSD, entities.TransactionTypeExchange, 1000, 2, aliceEUR, `{"dest_amount":"9.00 EUR"}`)
+	source.saveTx(t, aliceUSD, entities.TransactionTypeWithdraw, 500, 1, nil, "")
+
+	return &fixture{source: source, alice: alice, aliceUSD: aliceUSD, aliceEUR: aliceEUR, exchange: exchange}
+}
+
+// export выгружает alice за 90 дней и прогоняет bundle через JSON.
+func (f *fixture) export(t *testing.T) *dtos.SnapshotBundle {
+	t.Helper()
+	bundle, err := f.source.exporter().Execute(context.Background(), dtos.ExportSnapshotQuery{
+		UserIDs: []string{f.alice.ID().String()},
+		Since:   90 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("export error = %v", err)
+	}
+
+	raw, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("marshal bundle error = %v", err)
+	}
+	var decoded dtos.SnapshotBundle
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("unmarshal bundle error = %v", err)
+	}
+	return &decoded
+}
+
+func importBundle(t *testing.T, target *env, bundle *dtos.SnapshotBundle) *dtos.SnapshotImportSummaryDTO {
+	t.Helper()
+	summary, err := target.importer(false).Execute(context.Background(), dtos.ImportSnapshotCommand{Bundle: bundle})
+	if err != nil {
+		t.Fatalf("import error = %v", err)
+	}
+	return summary
+}
+
+// ============================================
+// Round trip
+// ============================================
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	f := newFixture(t)
+	bundle := f.export(t)
+
+	if len(bundle.Users) != 1 || bundle.Users[0].Email == "alice@example.com" || bundle.Users[0].FullName == "Real Name" {
+		t.Fatalf("bundle users = %+v, want one masked user", bundle.Users)
+	}
+	// opening balance + 5 транзакций окна; переводы с bob'ом переписаны
+	if len(bundle.Transactions) != 6 {
+		t.Fatalf("bundle transactions = %d, want 6", len(bundle.Transactions))
+	}
+	for _, tx := range bundle.Transactions {
+		if tx.DestinationWalletID != nil && *tx.DestinationWalletID != f.aliceEUR.ID().String() {
+			t.Errorf("transaction %s references wallet %s outside the bundle", tx.ID, *tx.DestinationWalletID)
+		}
+	}
+
+	target := newEnv()
+	summary := importBundle(t, target, bundle)
+
+	if summary.Users.Imported != 1 || summary.Wallets.Imported != 2 || summary.Transactions.Imported != 6 {
+		t.Errorf("summary = %+v, want 1 user, 2 wallets, 6 transactions", summary)
+	}
+
+	// Балансы пересчитаны по ledger и совпадают с источником
+	if got := target.balance(t, f.aliceUSD.ID()); got != "125.00 USD" {
+		t.Errorf("USD balance = %s, want 125.00 USD", got)
+	}
+	if got := target.balance(t, f.aliceEUR.ID()); got != "9.00 EUR" {
+		t.Errorf("EUR balance = %s, want 9.00 EUR", got)
+	}
+	if summary.Balances[f.aliceUSD.ID().String()] != "125.00 USD" {
+		t.Errorf("summary balances = %v", summary.Balances)
+	}
+
+	// Reconciliation audit
+	for _, w := range []*entities.Wallet{f.aliceUSD, f.aliceEUR} {
+		if err := snapshot.ReconcileWallet(context.Background(), target.wallets, target.transactions, w.ID()); err != nil {
+			t.Errorf("ReconcileWallet(%s) error = %v", w.Currency().Code(), err)
+		}
+	}
+
+	// Внутренние UUID сохранены
+	imported, err := target.transactions.FindByID(context.Background(), f.exchange.ID())
+	if err != nil {
+		t.Fatalf("exchange transaction not imported under its ID: %v", err)
+	}
+	if imported.Type() != entities.TransactionTypeExchange {
+		t.Errorf("exchange type = %s", imported.Type())
+	}
+}
+
+func TestSnapshot_ExportIgnoresStoredBalance(t *testing.T) {
+	f := newFixture(t)
+
+	// Баланс в источнике разошёлся с ledger - в bundle попадает только ledger
+	drifted := entities.ReconstructWallet(f.aliceUSD.ID(), f.alice.ID(), f.aliceUSD.Currency(), entities.WalletTypeFiat,
+		entities.WalletStatusActive, mustMoney(t, 99999, "USD"), valueobjects.Zero(f.aliceUSD.Currency()), 1,
+		f.aliceUSD.DailyLimit(), f.aliceUSD.MonthlyLimit(), "", nil, f.aliceUSD.CreatedAt(), time.Now())
+	if err := f.source.wallets.Save(context.Background(), drifted); err != nil {
+		t.Fatalf("save drifted wallet error = %v", err)
+	}
+
+	target := newEnv()
+	importBundle(t, target, f.export(t))
+
+	if got := target.balance(t, f.aliceUSD.ID()); got != "125.00 USD" {
+		t.Errorf("USD balance = %s, want 125.00 USD from ledger", got)
+	}
+}
+
+// ============================================
+// Re-import and conflicts
+// ============================================
+
+func TestSnapshot_ReimportIsIdempotent(t *testing.T) {
+	f := newFixture(t)
+	bundle := f.export(t)
+	target := newEnv()
+	importBundle(t, target, bundle)
+
+	summary := importBundle(t, target, bundle)
+
+	if summary.Users != (dtos.SnapshotImportCounts{Skipped: 1}) ||
+		summary.Wallets != (dtos.SnapshotImportCounts{Skipped: 2}) ||
+		summary.Transactions != (dtos.SnapshotImportCounts{Skipped: 6}) {
+		t.Errorf("second import summary = %+v, want everything skipped", summary)
+	}
+	if got := target.balance(t, f.aliceUSD.ID()); got != "125.00 USD" {
+		t.Errorf("USD balance after re-import = %s, want 125.00 USD", got)
+	}
+}
+
+func TestSnapshot_ConflictingEmailAndKeyGetSuffix(t *testing.T) {
+	f := newFixture(t)
+	bundle := f.export(t)
+
+	// В целевом окружении уже есть другой пользователь с тем же замаскированным
+	// email и транзакция с тем же idempotency key
+	target := newEnv()
+	masked := bundle.Users[0].Email
+	other := target.saveUser(t, masked)
+	otherWallet := target.saveWallet(t, other, valueobjects.MustNewCurrency("USD"), 100)
+	clash := target.saveTx(t, otherWallet, entities.TransactionTypeDeposit, 100, 1, nil, "")
+	bundle.Transactions[1].IdempotencyKey = clash.IdempotencyKey()
+
+	summary := importBundle(t, target, bundle)
+
+	if len(summary.RenamedEmails) != 1 || summary.RenamedEmails[0].To != suffixed(masked, 1) {
+		t.Errorf("renamed emails = %+v, want %s", summary.RenamedEmails, suffixed(masked, 1))
+	}
+	user, err := target.users.FindByID(context.Background(), f.alice.ID())
+	if err != nil || user.Email() != suffixed(masked, 1) {
+		t.Errorf("imported user email = %v (err %v)", user, err)
+	}
+
+	if len(summary.RenamedIdempotencyKeys) != 1 || summary.RenamedIdempotencyKeys[0].To != clash.IdempotencyKey()+"-snapshot-1" {
+		t.Errorf("renamed keys = %+v", summary.RenamedIdempotencyKeys)
+	}
+	if summary.Transactions.Imported != 6 {
+		t.Errorf("imported transactions = %d, want 6", summary.Transactions.Imported)
+	}
+}
+
+func TestSnapshot_ImportRefusedInProduction(t *testing.T) {
+	f := newFixture(t)
+	target := newEnv()
+
+	_, err := target.importer(true).Execute(context.Background(), dtos.ImportSnapshotCommand{Bundle: f.export(t)})
+
+	var domainErr *domainErrors.DomainError
+	if !stderrors.As(err, &domainErr) || domainErr.Code != "PRODUCTION_IMPORT_FORBIDDEN" {
+		t.Fatalf("error = %v, want PRODUCTION_IMPORT_FORBIDDEN", err)
+	}
+	if _, err := target.users.FindByID(context.Background(), f.alice.ID()); !domainErrors.IsNotFound(err) {
+		t.Errorf("user written despite refusal: %v", err)
+	}
+}
+
+func TestSnapshot_RejectsUnknownFormatVersion(t *testing.T) {
+	f := newFixture(t)
+	bundle := f.export(t)
+	bundle.FormatVersion = dtos.SnapshotFormatVersion + 1
+
+	_, err := newEnv().importer(false).Execute(context.Background(), dtos.ImportSnapshotCommand{Bundle: bundle})
+
+	var validationErr domainErrors.ValidationError
+	if !stderrors.As(err, &validationErr) || validationErr.Field != "format_version" {
+		t.Fatalf("error = %v, want format_version ValidationError", err)
+	}
+}
+
+// ============================================
+// Helpers
+// ============================================
+
+func mustMoney(t *testing.T, cents int64, code string) valueobjects.Money {
+	t.Helper()
+	m, err := valueobjects.NewMoneyFromCents(cents, valueobjects.MustNewCurrency(code))
+	if err != nil {
+		t.Fatalf("NewMoneyFromCents() error = %v", err)
+	}
+	return m
+}
+
+func suffixed(email string, n int) string {
+	for i := range email {
+		if email[i] == '@' {
+			return fmt.Sprintf("%s-%d%s", email[:i], n, email[i:])
+		}
+	}
+	return email
+}
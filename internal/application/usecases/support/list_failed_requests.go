@@ -0,0 +1,62 @@
+// Package support - ListFailedRequests use case для разбора обращений поддержкой.
+package support
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// ListFailedRequestsUseCase - use case для получения образцов неудачных
+// денежных операций.
+//
+// Доступ только для admin - проверяется в роутере (группа /admin).
+type ListFailedRequestsUseCase struct {
+	failedRequestRepo ports.FailedRequestRepository
+}
+
+// NewListFailedRequestsUseCase создаёт новый use case.
+func NewListFailedRequestsUseCase(failedRequestRepo ports.FailedRequestRepository) *ListFailedRequestsUseCase {
+	return &ListFailedRequestsUseCase{failedRequestRepo: failedRequestRepo}
+}
+
+// Execute возвращает образцы с фильтрацией и пагинацией, новые первыми.
+func (uc *ListFailedRequestsUseCase) Execute(ctx context.Context, query dtos.ListFailedRequestsQuery) (*dtos.FailedRequestListDTO, error) {
+	filter := ports.FailedRequestFilter{Route: query.Route}
+
+	if query.UserID != nil {
+		userID, err := uuid.Parse(*query.UserID)
+		if err != nil {
+			return nil, errors.ValidationError{Field: "user_id", Message: "must be a valid UUID"}
+		}
+		filter.ActorID = &userID
+	}
+
+	if query.OccurredFrom != nil {
+		from := query.OccurredFrom.UTC()
+		filter.OccurredFrom = &from
+	}
+
+	if query.OccurredTo != nil {
+		to := query.OccurredTo.UTC()
+		filter.OccurredTo = &to
+	}
+
+	// Лишняя строка определяет has_more
+	samples, err := uc.failedRequestRepo.List(ctx, filter, query.Offset, dtos.FetchLimit(query.Limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list failed requests: %w", err)
+	}
+
+	rows := make([]dtos.FailedRequestDTO, len(samples))
+	for i, sample := range samples {
+		rows[i] = dtos.ToFailedRequestDTO(sample)
+	}
+
+	return dtos.NewOffsetPage(rows, query.Offset, query.Limit), nil
+}
@@ -0,0 +1,57 @@
+package support_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/usecases/support"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+func TestListFailedRequestsUseCase(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewFailedRequestRepository(memory.NewStore())
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	for i, actorID := range []uuid.UUID{userID, uuid.New(), userID, userID} {
+		sample := entities.ReconstructFailedRequest(uuid.New(), "POST", "/api/v1/wallets/{id}/debit", 422, "req",
+			actorID, `{"amount":"10.00"}`, `{"amount":"10.0`, false, base.Add(time.Duration(i)*time.Minute))
+		if err := repo.Append(ctx, sample); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	useCase := support.NewListFailedRequestsUseCase(repo)
+	user := userID.String()
+	page, err := useCase.Execute(ctx, dtos.ListFailedRequestsQuery{UserID: &user, Limit: 2})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(page.Data) != 2 || !page.Pagination.HasMore {
+		t.Fatalf("Expected 2 samples and has_more, got %d (has_more=%v)", len(page.Data), page.Pagination.HasMore)
+	}
+	got := page.Data[0]
+	if !got.OccurredAt.Equal(base.Add(3*time.Minute)) || got.UserID != user {
+		t.Errorf("Expected newest sample of %s first, got %+v", user, got)
+	}
+	if string(got.RequestBody) != `{"amount":"10.00"}` {
+		t.Errorf("Expected JSON request body as is, got %s", got.RequestBody)
+	}
+	if string(got.ResponseBody) != `"{\"amount\":\"10.0"` {
+		t.Errorf("Expected cut JSON response body as string, got %s", got.ResponseBody)
+	}
+
+	invalid := "not-a-uuid"
+	_, err = useCase.Execute(ctx, dtos.ListFailedRequestsQuery{UserID: &invalid, Limit: 2})
+	if _, ok := err.(domainErrors.ValidationError); !ok {
+		t.Errorf("Expected ValidationError, got %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+// Package transaction - BulkTransfer use case для пакета переводов с одного кошелька.
+package transaction
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/google/uuid"
+)
+
+// bulkTransferMaxAttempts - сколько раз повторяем перевод после конфликта версий.
+const bulkTransferMaxAttempts = 3
+
+// BulkTransferPolicy - ограничения пакета переводов (config transactions.bulk_*).
+type BulkTransferPolicy struct {
+	MaxItems  int // Максимум переводов в пакете
+	GroupSize int // Переводов в одной UnitOfWork
+}
+
+// BulkTransferUseCase - use case для пакета переводов (выплата зарплат).
+//
+// Сценарий:
+// 1. Проверить команду целиком: ошибки формата отклоняют пакет (400)
+// 2. Выполнять переводы по порядку группами по GroupSize, каждая группа
+// в одной UnitOfWork через TransferBetweenWalletsUseCase
+// 3. Если перевод группы завершился ошибкой, группа откатывается и её
+// переводы выполняются по одному: ошибка одного перевода не откатывает
+// другие, конфликт версий кошелька повторяется
+// 4. Собрать отчёт: статус каждого перевода и агрегаты
+//
+// Бизнес-правила:
+// - Переводы выполняются по порядку команды
+// - После INSUFFICIENT_BALANCE оставшиеся переводы не выполняются и
+// получают тот же код
+// - Повтор пакета пропускает переводы с уже использованным idempotency_key
+// (idempotent_replay)
+// - Неожиданная ошибка (БД недоступна) прерывает пакет; выполненные
+// переводы остаются, повтор пакета продолжит с места остановки
+type BulkTransferUseCase struct {
+	walletRepo ports.WalletReader
+	uow        ports.UnitOfWork
+	transfer   *TransferBetweenWalletsUseCase
+	policy     BulkTransferPolicy
+}
+
+// NewBulkTransferUseCase создаёт новый use case.
+func NewBulkTransferUseCase(
+	walletRepo ports.WalletReader,
+	uow ports.UnitOfWork,
+	transfer *TransferBetweenWalletsUseCase,
+	policy BulkTransferPolicy,
+) *BulkTransferUseCase {
+	return &BulkTransferUseCase{
+		walletRepo: walletRepo,
+		uow:        uow,
+		transfer:   transfer,
+		policy:     policy,
+	}
+}
+
+// Execute выполняет пакет переводов.
+//
+// Errors:
+//   - ValidationErrors: Пакет пуст, больше MaxItems, ошибки формата переводов
+//     или повтор idempotency_key внутри пакета
+//   - WALLET_NOT_FOUND: Кошелёк-источник не найден
+func (uc *BulkTransferUseCase) Execute(ctx context.Context, cmd dtos.BulkTransferCommand) (*dtos.BulkTransferResultDTO, error) {
+	// 1. Синхронные проверки команды: все ошибки сразу
+	sourceWalletID, err := uc.validate(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Выключенные переводы отклоняют весь пакет, а не каждый элемент
+	if err := ports.RequireOperationEnabled(ctx, uc.transfer.operations, entities.TransactionTypeTransfer); err != nil {
+		return nil, err
+	}
+
+	source, err := uc.walletRepo.FindByID(ctx, sourceWalletID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NewDomainError("WALLET_NOT_FOUND", "source wallet not found", err)
+		}
+		return nil, fmt.Errorf("failed to load source wallet: %w", err)
+	}
+
+	// 2. Группы переводов
+	items := make([]dtos.BulkTransferItemResultDTO, len(cmd.Transfers))
+	insufficient := false
+	groupSize := max(uc.policy.GroupSize, 1)
+
+	for start := 0; start < len(cmd.Transfers); start += groupSize {
+		end := min(start+groupSize, len(cmd.Transfers))
+
+		if !insufficient {
+			results, err := uc.executeGroup(ctx, cmd, start, end)
+			if err == nil {
+				for i, result := range results {
+					items[start+i] = completedItem(start+i, cmd.Transfers[start+i], result)
+				}
+				continue
+			}
+		}
+
+		// 3. Группа откатилась - переводы по одному
+		for i := start; i < end; i++ {
+			item := cmd.Transfers[i]
+			if insufficient {
+				items[i] = failedItem(i, item, "INSUFFICIENT_BALANCE", "not attempted: source wallet balance was exhausted by previous transfers")
+				continue
+			}
+
+			result, err := uc.executeOne(ctx, cmd.SourceWalletID, item)
+			if err != nil {
+				code, message, ok := bulkItemError(err)
+				if !ok {
+					return nil, fmt.Errorf("bulk transfer item %d: %w", i, err)
+				}
+				items[i] = failedItem(i, item, code, message)
+				insufficient = code == "INSUFFICIENT_BALANCE"
+				continue
+			}
+			items[i] = completedItem(i, item, result)
+		}
+	}
+
+	// 4. Отчёт
+	return uc.buildResult(ctx, source.ID(), source.Currency(), cmd, items)
+}
+
+// validate проверяет пакет целиком. Пути полей: "transfers[3].amount".
+func (uc *BulkTransferUseCase) validate(cmd dtos.BulkTransferCommand) (uuid.UUID, error) {
+	var invalid errors.ValidationErrors
+
+	sourceWalletID, err := uuid.Parse(cmd.SourceWalletID)
+	if err != nil {
+		invalid.AddCode("source_wallet_id", errors.ValidationCodeInvalidFormat, "invalid source wallet ID format")
+	}
+
+	switch {
+	case len(cmd.Transfers) == 0:
+		invalid.AddCode("transfers", errors.ValidationCodeRequired, "at least one transfer is required")
+	case uc.policy.MaxItems > 0 && len(cmd.Transfers) > uc.policy.MaxItems:
+		invalid.AddCode("transfers", errors.ValidationCodeOutOfRange,
+			fmt.Sprintf("too many transfers: %d (max %d)", len(cmd.Transfers), uc.policy.MaxItems))
+	}
+
+	keys := make(map[string]int, len(cmd.Transfers))
+	for i, item := range cmd.Transfers {
+		prefix := fmt.Sprintf("transfers[%d].", i)
+
+		if _, err := uuid.Parse(item.DestinationWalletID); err != nil {
+			invalid.AddCode(prefix+"destination_wallet_id", errors.ValidationCodeInvalidFormat, "invalid destination wallet ID format")
+		}
+
+		var amountErrs errors.ValidationErrors
+		dtos.CheckCommandAmount(&amountErrs, item.Amount, item.AmountMinor)
+		for _, e := range amountErrs {
+			invalid.AddCode(prefix+e.Field, e.Code, e.Message)
+		}
+
+		if item.IdempotencyKey == "" {
+			invalid.AddCode(prefix+"idempotency_key", errors.ValidationCodeRequired, "idempotency key is required")
+		} else if first, ok := keys[item.IdempotencyKey]; ok {
+			invalid.AddCode(prefix+"idempotency_key", errors.ValidationCodeInvalidValue,
+				fmt.Sprintf("duplicate idempotency key (same as transfers[%d])", first))
+		} else {
+			keys[item.IdempotencyKey] = i
+		}
+	}
+
+	return sourceWalletID, invalid.Err()
+}
+
+// executeGroup выполняет переводы [start, end) в одной UnitOfWork.
+// Любая ошибка откатывает всю группу.
+func (uc *BulkTransferUseCase) executeGroup(ctx context.Context, cmd dtos.BulkTransferCommand, start, end int) ([]*dtos.TransferResultDTO, error) {
+	results := make([]*dtos.TransferResultDTO, 0, end-start)
+
+	err := uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		results = results[:0]
+		for _, item := range cmd.Transfers[start:end] {
+			result, err := uc.transfer.Execute(txCtx, bulkItemCommand(cmd.SourceWalletID, item))
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// executeOne выполняет один перевод в собственной UnitOfWork,
+// повторяя его после конфликта версий кошелька.
+func (uc *BulkTransferUseCase) executeOne(ctx context.Context, sourceWalletID string, item dtos.BulkTransferItem) (*dtos.TransferResultDTO, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := uc.transfer.Execute(ctx, bulkItemCommand(sourceWalletID, item))
+		if err == nil || !errors.IsConcurrencyError(err) || attempt == bulkTransferMaxAttempts {
+			return result, err
+		}
+		ports.RequestStatsFromContext(ctx).RecordLockRetry()
+	}
+}
+
+func (uc *BulkTransferUseCase) buildResult(
+	ctx context.Context,
+	sourceWalletID uuid.UUID,
+	currency valueobjects.Currency,
+	cmd dtos.BulkTransferCommand,
+	items []dtos.BulkTransferItemResultDTO,
+) (*dtos.BulkTransferResultDTO, error) {
+	source, err := uc.walletRepo.FindByID(ctx, sourceWalletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load source wallet: %w", err)
+	}
+
+	totals := dtos.BulkTransferTotalsDTO{Requested: len(items)}
+	completedAmount := valueobjects.Zero(currency)
+	for i, item := range items {
+		if item.Status != dtos.BulkTransferItemCompleted {
+			totals.Failed++
+			continue
+		}
+		totals.Completed++
+
+		amount, err := dtos.ParseCommandAmount(cmd.Transfers[i].Amount, cmd.Transfers[i].AmountMinor, currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sum transfer %d: %w", i, err)
+		}
+		if completedAmount, err = completedAmount.Add(amount); err != nil {
+			return nil, fmt.Errorf("failed to sum transfer %d: %w", i, err)
+		}
+	}
+	totals.CompletedAmount = completedAmount.String()
+
+	status := dtos.BulkTransferStatusPartial
+	switch totals.Completed {
+	case totals.Requested:
+		status = dtos.BulkTransferStatusCompleted
+	case 0:
+		status = dtos.BulkTransferStatusFailed
+	}
+
+	return &dtos.BulkTransferResultDTO{
+		SourceWallet: dtos.ToWalletDTO(source),
+		Status:       status,
+		Totals:       totals,
+		Items:        items,
+	}, nil
+}
+
+func bulkItemCommand(sourceWalletID string, item dtos.BulkTransferItem) dtos.TransferFundsCommand {
+	return dtos.TransferFundsCommand{
+		SourceWalletID:      sourceWalletID,
+		DestinationWalletID: item.DestinationWalletID,
+		Amount:              item.Amount,
+		AmountMinor:         item.AmountMinor,
+		IdempotencyKey:      item.IdempotencyKey,
+		Description:         item.Reference,
+		ConfirmNewPayee:     item.ConfirmNewPayee,
+	}
+}
+
+func completedItem(index int, item dtos.BulkTransferItem, result *dtos.TransferResultDTO) dtos.BulkTransferItemResultDTO {
+	return dtos.BulkTransferItemResultDTO{
+		Index:               index,
+		DestinationWalletID: item.DestinationWalletID,
+		IdempotencyKey:      item.IdempotencyKey,
+		Status:              dtos.BulkTransferItemCompleted,
+		TransactionID:       result.TransactionID,
+		Amount:              result.GrossAmount,
+		FeeAmount:           result.FeeAmount,
+		IdempotentReplay:    result.IdempotentReplay,
+	}
+}
+
+func failedItem(index int, item dtos.BulkTransferItem, code, message string) dtos.BulkTransferItemResultDTO {
+	return dtos.BulkTransferItemResultDTO{
+		Index:               index,
+		DestinationWalletID: item.DestinationWalletID,
+		IdempotencyKey:      item.IdempotencyKey,
+		Status:              dtos.BulkTransferItemFailed,
+		ErrorCode:           code,
+		ErrorMessage:        message,
+	}
+}
+
+// bulkItemError возвращает код ошибки API для перевода пакета.
+// false - ошибка не доменная (инфраструктура), пакет прерывается.
+func bulkItemError(err error) (code, message string, ok bool) {
+	var violation *errors.BusinessRuleViolation
+	var domainErr *errors.DomainError
+
+	switch {
+	case stderrors.Is(err, errors.ErrInsufficientBalance):
+		return "INSUFFICIENT_BALANCE", "insufficient balance", true
+	case stderrors.Is(err, errors.ErrWalletNotActive):
+		return "WALLET_NOT_ACTIVE", "source wallet is not active", true
+	case stderrors.As(err, &violation):
+		return violation.Rule, violation.Message, true
+	case errors.IsValidationError(err):
+		return "VALIDATION_ERROR", err.Error(), true
+	case errors.IsConcurrencyError(err):
+		return "CONCURRENCY_ERROR", "source wallet was modified concurrently, retry the batch", true
+	case errors.IsNotFound(err):
+		return "WALLET_NOT_FOUND", "destination wallet not found", true
+	case stderrors.As(err, &domainErr):
+		return domainErr.Code, domainErr.Message, true
+	}
+	return "", "", false
+}
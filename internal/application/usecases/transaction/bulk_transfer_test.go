@@ -0,0 +1,228 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// newBulkHarness - in-memory окружение пакетных переводов.
+func newBulkHarness(t *testing.T, policy BulkTransferPolicy) (*crashHarness, *BulkTransferUseCase) {
+	t.Helper()
+
+	store := memory.NewStore()
+	h := &crashHarness{
+		wallets:      memory.NewWalletRepository(store),
+		transactions: memory.NewTransactionRepository(store),
+		events:       memory.NewEventPublisher(store),
+		users:        memory.NewUserRepository(store),
+	}
+	h.walletRepo = h.wallets
+	h.transactionRepo = h.transactions
+	h.eventPublisher = h.events
+	h.uow = memory.NewUnitOfWork(store)
+
+	transfer := NewTransferBetweenWalletsUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
+	return h, NewBulkTransferUseCase(h.walletRepo, h.uow, transfer, policy)
+}
+
+func bulkItem(destination uuid.UUID, amount string) dtos.BulkTransferItem {
+	return dtos.BulkTransferItem{
+		DestinationWalletID: destination.String(),
+		Amount:              amount,
+		IdempotencyKey:      uuid.NewString(),
+		Reference:           "salary",
+	}
+}
+
+func assertItemStatuses(t *testing.T, result *dtos.BulkTransferResultDTO, expected ...string) {
+	t.Helper()
+
+	if len(result.Items) != len(expected) {
+		t.Fatalf("Expected %d items, got %d", len(expected), len(result.Items))
+	}
+	for i, item := range result.Items {
+		got := item.Status
+		if item.ErrorCode != "" {
+			got += ":" + item.ErrorCode
+		}
+		if got != expected[i] {
+			t.Errorf("Item %d: expected %s, got %s (%s)", i, expected[i], got, item.ErrorMessage)
+		}
+	}
+}
+
+// TestBulkTransferUseCase_PartialBatch проверяет, что ошибка одного перевода
+// откатывает только его: остальные переводы группы выполняются.
+func TestBulkTransferUseCase_PartialBatch(t *testing.T) {
+	ctx := context.Background()
+	h, useCase := newBulkHarness(t, BulkTransferPolicy{MaxItems: 10, GroupSize: 5})
+	source := h.seedWallet(t, "1000.00")
+	first := h.seedWallet(t, "0.00")
+	second := h.seedWallet(t, "0.00")
+
+	result, err := useCase.Execute(ctx, dtos.BulkTransferCommand{
+		SourceWalletID: source.ID().String(),
+		Transfers: []dtos.BulkTransferItem{
+			bulkItem(first.ID(), "100.00"),
+			bulkItem(uuid.New(), "50.00"),
+			bulkItem(second.ID(), "200.00"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	assertItemStatuses(t, result, "COMPLETED", "FAILED:DESTINATION_WALLET_NOT_FOUND", "COMPLETED")
+	if result.Status != dtos.BulkTransferStatusPartial {
+		t.Errorf("Expected status PARTIAL, got %s", result.Status)
+	}
+	if result.Totals.Completed != 2 || result.Totals.Failed != 1 {
+		t.Errorf("Expected totals 2/1, got %d/%d", result.Totals.Completed, result.Totals.Failed)
+	}
+	if result.Totals.CompletedAmount != "300.00 USD" {
+		t.Errorf("Expected completed amount 300.00 USD, got %s", result.Totals.CompletedAmount)
+	}
+	h.assertBalance(t, source.ID(), "700.00 USD")
+	h.assertBalance(t, first.ID(), "100.00 USD")
+	h.assertBalance(t, second.ID(), "200.00 USD")
+}
+
+// TestBulkTransferUseCase_InsufficientBalance проверяет, что после
+// INSUFFICIENT_BALANCE оставшиеся переводы не выполняются.
+func TestBulkTransferUseCase_InsufficientBalance(t *testing.T) {
+	ctx := context.Background()
+	h, useCase := newBulkHarness(t, BulkTransferPolicy{MaxItems: 10, GroupSize: 2})
+	source := h.seedWallet(t, "250.00")
+	destination := h.seedWallet(t, "0.00")
+
+	result, err := useCase.Execute(ctx, dtos.BulkTransferCommand{
+		SourceWalletID: source.ID().String(),
+		Transfers: []dtos.BulkTransferItem{
+			bulkItem(destination.ID(), "100.00"),
+			bulkItem(destination.ID(), "100.00"),
+			bulkItem(destination.ID(), "100.00"),
+			bulkItem(destination.ID(), "10.00"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	assertItemStatuses(t, result, "COMPLETED", "COMPLETED", "FAILED:INSUFFICIENT_BALANCE", "FAILED:INSUFFICIENT_BALANCE")
+	h.assertBalance(t, source.ID(), "50.00 USD")
+	h.assertNotCommittedKey(t, result.Items[3].IdempotencyKey)
+}
+
+// TestBulkTransferUseCase_Resubmit проверяет, что повтор пакета не выполняет
+// переводы повторно, а отмечает их idempotent_replay.
+func TestBulkTransferUseCase_Resubmit(t *testing.T) {
+	ctx := context.Background()
+	h, useCase := newBulkHarness(t, BulkTransferPolicy{MaxItems: 10, GroupSize: 5})
+	source := h.seedWallet(t, "1000.00")
+	destination := h.seedWallet(t, "0.00")
+
+	cmd := dtos.BulkTransferCommand{
+		SourceWalletID: source.ID().String(),
+		Transfers: []dtos.BulkTransferItem{
+			bulkItem(destination.ID(), "100.00"),
+			bulkItem(destination.ID(), "200.00"),
+		},
+	}
+	first, err := useCase.Execute(ctx, cmd)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	second, err := useCase.Execute(ctx, cmd)
+	if err != nil {
+		t.Fatalf("Resubmit failed: %v", err)
+	}
+
+	assertItemStatuses(t, second, "COMPLETED", "COMPLETED")
+	for i, item := range second.Items {
+		if !item.IdempotentReplay {
+			t.Errorf("Item %d: expected idempotent_replay", i)
+		}
+		if item.TransactionID != first.Items[i].TransactionID {
+			t.Errorf("Item %d: expected transaction %s, got %s", i, first.Items[i].TransactionID, item.TransactionID)
+		}
+	}
+	h.assertBalance(t, source.ID(), "700.00 USD")
+	h.assertBalance(t, destination.ID(), "300.00 USD")
+}
+
+// TestBulkTransferUseCase_Validation проверяет, что ошибки формата
+// отклоняют пакет целиком без переводов.
+func TestBulkTransferUseCase_Validation(t *testing.T) {
+	ctx := context.Background()
+	h, useCase := newBulkHarness(t, BulkTransferPolicy{MaxItems: 2, GroupSize: 5})
+	source := h.seedWallet(t, "1000.00")
+	destination := h.seedWallet(t, "0.00")
+
+	t.Run("DuplicateIdempotencyKey", func(t *testing.T) {
+		duplicate := bulkItem(destination.ID(), "10.00")
+		_, err := useCase.Execute(ctx, dtos.BulkTransferCommand{
+			SourceWalletID: source.ID().String(),
+			Transfers:      []dtos.BulkTransferItem{duplicate, duplicate},
+		})
+		assertValidationField(t, err, "transfers[1].idempotency_key")
+	})
+
+	t.Run("TooManyTransfers", func(t *testing.T) {
+		_, err := useCase.Execute(ctx, dtos.BulkTransferCommand{
+			SourceWalletID: source.ID().String(),
+			Transfers: []dtos.BulkTransferItem{
+				bulkItem(destination.ID(), "1.00"),
+				bulkItem(destination.ID(), "1.00"),
+				bulkItem(destination.ID(), "1.00"),
+			},
+		})
+		assertValidationField(t, err, "transfers")
+	})
+
+	t.Run("InvalidAmount", func(t *testing.T) {
+		_, err := useCase.Execute(ctx, dtos.BulkTransferCommand{
+			SourceWalletID: source.ID().String(),
+			Transfers: []dtos.BulkTransferItem{
+				bulkItem(destination.ID(), "10.00"),
+				bulkItem(destination.ID(), "-5"),
+			},
+		})
+		assertValidationField(t, err, "transfers[1].amount")
+	})
+
+	h.assertBalance(t, source.ID(), "1000.00 USD")
+}
+
+func assertValidationField(t *testing.T, err error, field string) {
+	t.Helper()
+
+	var invalid domainErrors.ValidationErrors
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+	for _, e := range invalid {
+		if e.Field == field {
+			return
+		}
+	}
+	t.Errorf("Expected validation error for %s, got %v", field, invalid)
+}
+
+// assertNotCommittedKey проверяет, что по ключу нет транзакции.
+func (h *crashHarness) assertNotCommittedKey(t *testing.T, idempotencyKey string) {
+	t.Helper()
+
+	_, err := h.transactions.FindByIdempotencyKey(context.Background(), idempotencyKey)
+	if !domainErrors.IsNotFound(err) {
+		t.Errorf("Expected no committed transaction, got err = %v", err)
+	}
+}
@@ -147,13 +147,19 @@ func (uc *CancelTransactionUseCase) Execute(ctx context.Context, cmd dtos.Cancel
 		}
 
 		// 7. Публикуем события
+		ownerID, err := walletOwnerID(txCtx, uc.walletRepo, transaction.WalletID())
+		if err != nil {
+			return err
+		}
 		eventList := []events.DomainEvent{
 			events.NewTransactionFailed(
 				transaction.ID(),
 				transaction.WalletID(),
+				ownerID,
 				string(transaction.Type()),
 				transaction.Amount(),
 				"transaction cancelled by user",
+				string(entities.FailureCategoryClient),
 				false, // not retryable
 			),
 		}
@@ -0,0 +1,355 @@
+// Package transaction - crash-consistency тесты денежных операций.
+//
+// Use cases работают поверх in-memory репозиториев с настоящим откатом
+// (memory.UnitOfWork), а faultinject роняет операцию в каждой точке:
+// ошибкой или panic (имитация падения процесса). После сбоя проверяются
+// инварианты:
+//   - нет изменения баланса без закоммиченной транзакции
+//   - нет событий без коммита
+//   - idempotency_key не "сгорает": повтор запроса проходит ровно один раз
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/faultinject"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+var errInjected = errors.New("injected fault")
+
+// crashHarness - in-memory окружение с точками внедрения сбоев.
+type crashHarness struct {
+	faults *faultinject.Injector
+
+	// Обёрнутые зависимости для use case
+	walletRepo      ports.WalletRepository
+	transactionRepo ports.TransactionRepository
+	eventPublisher  ports.EventPublisher
+	uow             ports.UnitOfWork
+
+	// Прямой доступ к хранилищу для проверки инвариантов
+	wallets      *memory.WalletRepository
+	transactions *memory.TransactionRepository
+	events       *memory.EventPublisher
+	users        *memory.UserRepository
+	metadata     *memory.MetadataUsageRepository
+}
+
+func newCrashHarness() *crashHarness {
+	store := memory.NewStore()
+	faults := faultinject.NewInjector()
+	h := &crashHarness{
+		faults:       faults,
+		wallets:      memory.NewWalletRepository(store),
+		transactions: memory.NewTransactionRepository(store),
+		events:       memory.NewEventPublisher(store),
+		users:        memory.NewUserRepository(store),
+		metadata:     memory.NewMetadataUsageRepository(store),
+	}
+
+	h.walletRepo = faultinject.WrapWalletRepository(h.wallets, faults)
+	h.transactionRepo = faultinject.WrapTransactionRepository(h.transactions, faults)
+	h.eventPublisher = faultinject.WrapEventPublisher(h.events, faults)
+	h.uow = faultinject.WrapUnitOfWork(memory.NewUnitOfWork(store), faults)
+
+	return h
+}
+
+// seedWallet создаёт USD кошелёк с начальным балансом в обход точек внедрения.
+func (h *crashHarness) seedWallet(t *testing.T, balance string) *entities.Wallet {
+	t.Helper()
+	ctx := context.Background()
+
+	user, err := entities.NewUser(uuid.NewString()+"@example.com", "Crash Test")
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if err := h.users.Save(ctx, user); err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+
+	currency := valueobjects.MustNewCurrency("USD")
+	wallet, err := entities.NewWallet(user.ID(), currency)
+	if err != nil {
+		t.Fatalf("failed to create wallet: %v", err)
+	}
+	if err := h.wallets.Save(ctx, wallet); err != nil {
+		t.Fatalf("failed to save wallet: %v", err)
+	}
+
+	amount, _ := valueobjects.NewMoney(balance, currency)
+	if err := wallet.Credit(amount); err != nil {
+		t.Fatalf("failed to fund wallet: %v", err)
+	}
+	if err := h.wallets.Save(ctx, wallet); err != nil {
+		t.Fatalf("failed to save funded wallet: %v", err)
+	}
+
+	return wallet
+}
+
+// assertBalance проверяет сохранённый баланс кошелька.
+func (h *crashHarness) assertBalance(t *testing.T, walletID uuid.UUID, expected string) {
+	t.Helper()
+
+	wallet, err := h.wallets.FindByID(context.Background(), walletID)
+	if err != nil {
+		t.Fatalf("failed to load wallet: %v", err)
+	}
+	if got := wallet.AvailableBalance().String(); got != expected {
+		t.Errorf("Expected balance = %s, got %s", expected, got)
+	}
+}
+
+// assertNotCommitted проверяет, что по ключу нет ни транзакции, ни событий.
+func (h *crashHarness) assertNotCommitted(t *testing.T, idempotencyKey string) {
+	t.Helper()
+
+	_, err := h.transactions.FindByIdempotencyKey(context.Background(), idempotencyKey)
+	if !domainErrors.IsNotFound(err) {
+		t.Errorf("Expected no committed transaction, got err = %v", err)
+	}
+	if n := len(h.events.Events()); n != 0 {
+		t.Errorf("Expected no events without commit, got %d", n)
+	}
+}
+
+// faultAction - способ уронить операцию в точке.
+type faultAction struct {
+	name   string
+	inject func(faults *faultinject.Injector, point faultinject.Point, nth int)
+}
+
+var faultActions = []faultAction{
+	{"Error", func(f *faultinject.Injector, p faultinject.Point, nth int) { f.FailOn(p, nth, errInjected) }},
+	{"Panic", func(f *faultinject.Injector, p faultinject.Point, nth int) { f.PanicOn(p, nth, "process crashed") }},
+}
+
+// runCrashing выполняет fn, превращая panic в ошибку.
+func runCrashing(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn()
+}
+
+// ============================================
+// CreateTransaction
+// ============================================
+
+func TestCrashConsistency_CreateTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	newCommand := func(walletID uuid.UUID) dtos.CreateTransactionCommand {
+		return dtos.CreateTransactionCommand{
+			WalletID:       walletID.String(),
+			IdempotencyKey: uuid.NewString(),
+			Type:           "DEPOSIT",
+			Amount:         "25.00",
+		}
+	}
+
+	// Сбой до коммита: ничего не сохранено, повтор проходит как новая операция
+	for _, point := range faultinject.Points() {
+		if point == faultinject.PointAfterCommit {
+			continue
+		}
+		for _, action := range faultActions {
+			t.Run(fmt.Sprintf("%s/%s", point, action.name), func(t *testing.T) {
+				h := newCrashHarness()
+				wallet := h.seedWallet(t, "100.00")
+				useCase := NewCreateTransactionUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
+				cmd := newCommand(wallet.ID())
+
+				action.inject(h.faults, point, 1)
+
+				err := runCrashing(func() error {
+					_, err := useCase.Execute(ctx, cmd)
+					return err
+				})
+				if err == nil {
+					t.Fatal("Expected injected failure, got nil")
+				}
+
+				h.assertBalance(t, wallet.ID(), "100.00 USD")
+				h.assertNotCommitted(t, cmd.IdempotencyKey)
+
+				// Повтор после сбоя проводит операцию ровно один раз
+				h.faults.Reset()
+				result, err := useCase.Execute(ctx, cmd)
+				if err != nil {
+					t.Fatalf("Expected retry to succeed, got: %v", err)
+				}
+				if result.IdempotentReplay {
+					t.Error("Expected retry after rollback to be a new transaction")
+				}
+				h.assertBalance(t, wallet.ID(), "125.00 USD")
+			})
+		}
+	}
+
+	// Сбой после коммита: операция сохранена целиком вместе с событиями,
+	// повтор клиента получает replay без повторного зачисления
+	for _, action := range faultActions {
+		t.Run(fmt.Sprintf("%s/%s", faultinject.PointAfterCommit, action.name), func(t *testing.T) {
+			h := newCrashHarness()
+			wallet := h.seedWallet(t, "100.00")
+			useCase := NewCreateTransactionUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
+			cmd := newCommand(wallet.ID())
+
+			action.inject(h.faults, faultinject.PointAfterCommit, 1)
+
+			err := runCrashing(func() error {
+				_, err := useCase.Execute(ctx, cmd)
+				return err
+			})
+			if err == nil {
+				t.Fatal("Expected injected failure, got nil")
+			}
+
+			h.assertBalance(t, wallet.ID(), "125.00 USD")
+			committed, err := h.transactions.FindByIdempotencyKey(ctx, cmd.IdempotencyKey)
+			if err != nil {
+				t.Fatalf("Expected committed transaction, got: %v", err)
+			}
+			if committed.Status() != entities.TransactionStatusCompleted {
+				t.Errorf("Expected status = %s, got %s", entities.TransactionStatusCompleted, committed.Status())
+			}
+			eventCount := len(h.events.Events())
+			if eventCount == 0 {
+				t.Error("Expected events to be committed with the transaction")
+			}
+
+			result, err := useCase.Execute(ctx, cmd)
+			if err != nil {
+				t.Fatalf("Expected replay to succeed, got: %v", err)
+			}
+			if !result.IdempotentReplay {
+				t.Error("Expected IdempotentReplay = true after committed crash")
+			}
+			h.assertBalance(t, wallet.ID(), "125.00 USD")
+			if n := len(h.events.Events()); n != eventCount {
+				t.Errorf("Expected no new events on replay, got %d (was %d)", n, eventCount)
+			}
+		})
+	}
+}
+
+// ============================================
+// TransferBetweenWallets
+// ============================================
+
+func TestCrashConsistency_Transfer(t *testing.T) {
+	ctx := context.Background()
+
+	// Перевод сохраняет два кошелька: nth = 2 - сбой на destination,
+	// когда source уже записан в рамках той же транзакции
+	cases := []struct {
+		point faultinject.Point
+		nth   int
+	}{
+		{faultinject.PointBeforeTransactionSave, 1},
+		{faultinject.PointAfterTransactionSave, 1},
+		{faultinject.PointBeforeWalletSave, 1},
+		{faultinject.PointAfterWalletSave, 1},
+		{faultinject.PointBeforeWalletSave, 2},
+		{faultinject.PointAfterWalletSave, 2},
+		{faultinject.PointBeforeEventPublish, 1},
+		{faultinject.PointBeforeCommit, 1},
+	}
+
+	for _, tc := range cases {
+		for _, action := range faultActions {
+			t.Run(fmt.Sprintf("%s#%d/%s", tc.point, tc.nth, action.name), func(t *testing.T) {
+				h := newCrashHarness()
+				source := h.seedWallet(t, "100.00")
+				destination := h.seedWallet(t, "10.00")
+				useCase := NewTransferBetweenWalletsUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
+				cmd := dtos.TransferFundsCommand{
+					SourceWalletID:      source.ID().String(),
+					DestinationWalletID: destination.ID().String(),
+					Amount:              "40.00",
+					IdempotencyKey:      uuid.NewString(),
+				}
+
+				action.inject(h.faults, tc.point, tc.nth)
+
+				err := runCrashing(func() error {
+					_, err := useCase.Execute(ctx, cmd)
+					return err
+				})
+				if err == nil {
+					t.Fatal("Expected injected failure, got nil")
+				}
+
+				// Атомарность: ни один из кошельков не изменился
+				h.assertBalance(t, source.ID(), "100.00 USD")
+				h.assertBalance(t, destination.ID(), "10.00 USD")
+				h.assertNotCommitted(t, cmd.IdempotencyKey)
+
+				h.faults.Reset()
+				if _, err := useCase.Execute(ctx, cmd); err != nil {
+					t.Fatalf("Expected retry to succeed, got: %v", err)
+				}
+				h.assertBalance(t, source.ID(), "60.00 USD")
+				h.assertBalance(t, destination.ID(), "50.00 USD")
+			})
+		}
+	}
+
+	t.Run("AfterCommit", func(t *testing.T) {
+		h := newCrashHarness()
+		source := h.seedWallet(t, "100.00")
+		destination := h.seedWallet(t, "10.00")
+		useCase := NewTransferBetweenWalletsUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
+		cmd := dtos.TransferFundsCommand{
+			SourceWalletID:      source.ID().String(),
+			DestinationWalletID: destination.ID().String(),
+			Amount:              "40.00",
+			IdempotencyKey:      uuid.NewString(),
+		}
+
+		h.faults.PanicOn(faultinject.PointAfterCommit, 1, "process crashed")
+
+		if err := runCrashing(func() error {
+			_, err := useCase.Execute(ctx, cmd)
+			return err
+		}); err == nil {
+			t.Fatal("Expected injected failure, got nil")
+		}
+
+		// Коммит прошёл: оба кошелька изменены, события сохранены
+		h.assertBalance(t, source.ID(), "60.00 USD")
+		h.assertBalance(t, destination.ID(), "50.00 USD")
+		eventCount := len(h.events.Events())
+		if eventCount == 0 {
+			t.Error("Expected events to be committed with the transfer")
+		}
+
+		result, err := useCase.Execute(ctx, cmd)
+		if err != nil {
+			t.Fatalf("Expected replay to succeed, got: %v", err)
+		}
+		if !result.IdempotentReplay {
+			t.Error("Expected IdempotentReplay = true after committed crash")
+		}
+		h.assertBalance(t, source.ID(), "60.00 USD")
+		h.assertBalance(t, destination.ID(), "50.00 USD")
+		if n := len(h.events.Events()); n != eventCount {
+			t.Errorf("Expected no new events on replay, got %d (was %d)", n, eventCount)
+		}
+	})
+}
@@ -28,8 +28,12 @@ import (
 // Бизнес-правила:
 // - Idempotency: повторный запрос с тем же ключом возвращает существующую транзакцию
 // - Кошелёк должен существовать и быть активным
-// - Для WITHDRAW/PAYOUT достаточно средств
+// - Для WITHDRAW/PAYOUT достаточно средств (с учётом комиссии)
+// - Комиссия только для WITHDRAW/PAYOUT: кошелёк теряет net + fee (см. TransferBetweenWallets)
 // - Для DEPOSIT/REFUND лимиты не превышены
+// - У кошелька не больше PENDING/PROCESSING транзакций, чем позволяет лимит
+// - Для WITHDRAW/PAYOUT владелец кошелька принял актуальную версию ToS
+// - Metadata клиента учитывается в квоте кошелька (см. ports.MetadataQuota)
 type CreateTransactionUseCase struct {
 	walletRepo      ports.WalletRepository
 	transactionRepo ports.TransactionRepository
@@ -38,6 +42,15 @@ type CreateTransactionUseCase struct {
 	// distributedLock prevents idempotency race conditions across multiple instances.
 	// May be nil — in that case idempotency is still checked via DB, but without a lock.
 	distributedLock ports.DistributedLock
+	feeCalculator   ports.FeeCalculator             // nil - без комиссий
+	screener        ports.TransactionScreener       // nil - без правил скрининга
+	buildInfo       ports.BuildInfo                 // версия сборки для created_by_version
+	sensitiveData   ports.SensitiveDataPolicy       // PAN/IBAN в external reference: маскировать или отклонять
+	pending         ports.PendingTransactionsPolicy // лимит незавершённых транзакций кошелька
+	terms           ports.TermsGate                 // nil - без проверки принятия ToS
+	kyc             ports.KYCGate                   // nil - без проверки KYC владельца
+	operations      ports.OperationGate             // nil - без аварийных выключателей
+	metadataQuota   ports.MetadataQuota             // Usage nil - metadata не учитывается
 }
 
 // NewCreateTransactionUseCase создаёт новый use case.
@@ -47,6 +60,15 @@ func NewCreateTransactionUseCase(
 	eventPublisher ports.EventPublisher,
 	uow ports.UnitOfWork,
 	lock ports.DistributedLock,
+	feeCalculator ports.FeeCalculator,
+	screener ports.TransactionScreener,
+	buildInfo ports.BuildInfo,
+	sensitiveData ports.SensitiveDataPolicy,
+	pending ports.PendingTransactionsPolicy,
+	terms ports.TermsGate,
+	kyc ports.KYCGate,
+	operations ports.OperationGate,
+	metadataQuota ports.MetadataQuota,
 ) *CreateTransactionUseCase {
 	return &CreateTransactionUseCase{
 		walletRepo:      walletRepo,
@@ -54,11 +76,24 @@ func NewCreateTransactionUseCase(
 		eventPublisher:  eventPublisher,
 		uow:             uow,
 		distributedLock: lock,
+		feeCalculator:   feeCalculator,
+		screener:        screener,
+		buildInfo:       buildInfo,
+		sensitiveData:   sensitiveData,
+		pending:         pending,
+		terms:           terms,
+		kyc:             kyc,
+		operations:      operations,
+		metadataQuota:   metadataQuota,
 	}
 }
 
 // Execute выполняет создание транзакции.
 func (uc *CreateTransactionUseCase) Execute(ctx context.Context, cmd dtos.CreateTransactionCommand) (*dtos.TransactionDTO, error) {
+	if err := ports.RequireOperationEnabled(ctx, uc.operations, entities.TransactionType(cmd.Type)); err != nil {
+		return nil, err
+	}
+
 	var result *dtos.TransactionDTO
 
 	// 0. Acquire distributed lock for idempotency key to prevent race conditions.
@@ -82,19 +117,37 @@ func (uc *CreateTransactionUseCase) Execute(ctx context.Context, cmd dtos.Create
 			}
 			if existingTx != nil {
 				// Идемпотентный запрос - возвращаем существующую транзакцию
-				result = dtos.MapTransactionToDTO(existingTx)
+				result = replayTransactionDTO(existingTx)
 				return nil
 			}
 		}
 
-		// 2. Парсим wallet ID
+		// 2. Синхронные проверки команды: все ошибки сразу, до обращения к БД
+		var invalid errors.ValidationErrors
 		walletID, err := uuid.Parse(cmd.WalletID)
 		if err != nil {
-			return errors.ValidationError{
-				Field:   "wallet_id",
-				Message: "invalid wallet ID format",
+			invalid.AddCode("wallet_id", errors.ValidationCodeInvalidFormat, "invalid wallet ID format")
+		}
+		var destinationWalletID *uuid.UUID
+		if cmd.DestinationWalletID != "" {
+			if destID, err := uuid.Parse(cmd.DestinationWalletID); err != nil {
+				invalid.AddCode("destination_wallet_id", errors.ValidationCodeInvalidFormat, "invalid destination wallet ID format")
+			} else {
+				destinationWalletID = &destID
 			}
 		}
+		if _, err := valueobjects.ParseAmount(cmd.Amount); err != nil {
+			invalid.AddCode("amount", errors.ValidationCodeInvalidFormat, fmt.Sprintf("invalid amount: %v", err))
+		}
+		if !entities.TransactionType(cmd.Type).IsValid() {
+			invalid.AddCode("type", errors.ValidationCodeInvalidValue, fmt.Sprintf("unsupported transaction type: %s", cmd.Type))
+		}
+		uc.sensitiveData.Check(&invalid, "external_reference", cmd.ExternalReference)
+		uc.sensitiveData.Check(&invalid, "metadata", cmd.Metadata)
+		ports.CheckReservedMetadata(&invalid, cmd.Metadata)
+		if err := invalid.Err(); err != nil {
+			return err
+		}
 
 		// 3. Загружаем кошелёк
 		wallet, err := uc.walletRepo.FindByID(txCtx, walletID)
@@ -105,12 +158,26 @@ func (uc *CreateTransactionUseCase) Execute(ctx context.Context, cmd dtos.Create
 			return fmt.Errorf("failed to load wallet: %w", err)
 		}
 
+		// Вывод средств требует принятой актуальной версии ToS
+		txType := entities.TransactionType(cmd.Type)
+		if uc.terms != nil && (txType == entities.TransactionTypeWithdraw || txType == entities.TransactionTypePayout) {
+			if err := uc.terms.RequireAccepted(txCtx, wallet.UserID()); err != nil {
+				return err
+			}
+		}
+
+		// Лимит незавершённых транзакций (в том числе PAYOUT с резервом)
+		if err := uc.pending.Check(txCtx, uc.transactionRepo, wallet); err != nil {
+			return err
+		}
+
 		// 4. Парсим сумму
 		amount, err := valueobjects.NewMoney(cmd.Amount, wallet.Currency())
 		if err != nil {
 			return errors.ValidationError{
 				Field:   "amount",
 				Message: fmt.Sprintf("invalid amount: %v", err),
+				Code:    errors.ValidationCodeInvalidFormat,
 			}
 		}
 
@@ -126,16 +193,19 @@ func (uc *CreateTransactionUseCase) Execute(ctx context.Context, cmd dtos.Create
 			return fmt.Errorf("failed to create transaction: %w", err)
 		}
 
+		// Тег хранения копируется с исходного кошелька и дальше не меняется
+		if err := transaction.AssignJurisdiction(wallet.Jurisdiction()); err != nil {
+			return fmt.Errorf("failed to assign jurisdiction: %w", err)
+		}
+
+		// Версия сборки - только из build info, никогда из запроса
+		if err := transaction.StampCreatedByVersion(uc.buildInfo.ProducerVersion()); err != nil {
+			return fmt.Errorf("failed to stamp build version: %w", err)
+		}
+
 		// Устанавливаем опциональные поля
-		if cmd.DestinationWalletID != "" {
-			destID, err := uuid.Parse(cmd.DestinationWalletID)
-			if err != nil {
-				return errors.ValidationError{
-					Field:   "destination_wallet_id",
-					Message: "invalid destination wallet ID format",
-				}
-			}
-			if err := transaction.SetDestinationWallet(destID); err != nil {
+		if destinationWalletID != nil {
+			if err := transaction.SetDestinationWallet(*destinationWalletID); err != nil {
 				return fmt.Errorf("failed to set destination wallet: %w", err)
 			}
 		}
@@ -154,6 +224,35 @@ func (uc *CreateTransactionUseCase) Execute(ctx context.Context, cmd dtos.Create
 			}
 		}
 
+		// Объём metadata клиента в том виде, в каком она будет сохранена
+		metadataBytes := clientMetadataSize(transaction, cmd.Metadata)
+
+		// Вывод средств требует KYC владельца; пометка lenient режима - после
+		// metadata клиента, как и screening_flags
+		var kycEvents []events.DomainEvent
+		if txType == entities.TransactionTypeWithdraw || txType == entities.TransactionTypePayout {
+			if kycEvents, err = ports.CheckOwnerKYC(txCtx, uc.kyc, transaction, wallet); err != nil {
+				return err
+			}
+		}
+
+		// Скрининг после metadata клиента: screening_flags не перезаписывается запросом
+		screeningEvents, err := ports.ScreenTransaction(txCtx, uc.screener, transaction, wallet)
+		if err != nil {
+			return err
+		}
+
+		if err := applyFeeQuote(txCtx, uc.feeCalculator, transaction, &ports.FeeQuoteRequest{
+			UserID:          wallet.UserID().String(),
+			WalletID:        walletID.String(),
+			TransactionType: transaction.Type(),
+			Amount:          amount,
+		}); err != nil {
+			return err
+		}
+		netAmount := transaction.NetAmount()
+		var feeTx *entities.Transaction
+
 		// 7. Применяем операцию к кошельку в зависимости от типа транзакции
 		switch entities.TransactionType(cmd.Type) {
 		case entities.TransactionTypeDeposit, entities.TransactionTypeRefund:
@@ -163,10 +262,17 @@ func (uc *CreateTransactionUseCase) Execute(ctx context.Context, cmd dtos.Create
 			}
 
 		case entities.TransactionTypeWithdraw, entities.TransactionTypePayout, entities.TransactionTypeFee:
-			// Списание с кошелька
-			if err := wallet.Debit(amount); err != nil {
+			// Списание с кошелька: net + fee, комиссия фиксируется FEE транзакцией
+			debitAmount, err := payerDebit(transaction)
+			if err != nil {
+				return err
+			}
+			if err := wallet.Debit(debitAmount); err != nil {
 				return fmt.Errorf("failed to debit wallet: %w", err)
 			}
+			if feeTx, err = chargeFee(transaction); err != nil {
+				return err
+			}
 
 		case entities.TransactionTypeTransfer:
 			// Для TRANSFER нужен отдельный use case (TransferBetweenWallets)
@@ -187,6 +293,7 @@ func (uc *CreateTransactionUseCase) Execute(ctx context.Context, cmd dtos.Create
 			return errors.ValidationError{
 				Field:   "type",
 				Message: fmt.Sprintf("unsupported transaction type: %s", cmd.Type),
+				Code:    errors.ValidationCodeInvalidValue,
 			}
 		}
 
@@ -204,6 +311,18 @@ func (uc *CreateTransactionUseCase) Execute(ctx context.Context, cmd dtos.Create
 			return fmt.Errorf("failed to save transaction: %w", err)
 		}
 
+		if feeTx != nil {
+			if err := uc.transactionRepo.Save(txCtx, feeTx); err != nil {
+				return fmt.Errorf("failed to save fee transaction: %w", err)
+			}
+		}
+
+		// Квота metadata: учёт в той же UoW, превышение жёсткой квоты откатывает всё
+		quota, err := uc.metadataQuota.Charge(txCtx, walletID, transaction.ID(), metadataBytes)
+		if err != nil {
+			return err
+		}
+
 		// 10. Сохраняем обновлённый кошелёк
 		if err := uc.walletRepo.Save(txCtx, wallet); err != nil {
 			return fmt.Errorf("failed to save wallet: %w", err)
@@ -214,16 +333,19 @@ func (uc *CreateTransactionUseCase) Execute(ctx context.Context, cmd dtos.Create
 			events.NewTransactionCreated(
 				transaction.ID(),
 				walletID,
+				wallet.UserID(),
 				string(transaction.Type()),
 				amount,
 				cmd.IdempotencyKey,
-			),
+			).WithAmountBreakdown(transaction.FeeAmount(), netAmount),
 			events.NewTransactionCompleted(
 				transaction.ID(),
 				walletID,
+				wallet.UserID(),
 				string(transaction.Type()),
 				amount,
-			),
+			).WithAmountBreakdown(transaction.FeeAmount(), netAmount).
+				WithReceiptNumber(transaction.ReceiptNumber()),
 		}
 
 		// Добавляем события в зависимости от типа транзакции
@@ -234,13 +356,27 @@ func (uc *CreateTransactionUseCase) Execute(ctx context.Context, cmd dtos.Create
 				amount,
 				transaction.ID(),
 				wallet.AvailableBalance(),
+				wallet.BalanceVersion(),
 			))
 		case entities.TransactionTypeWithdraw, entities.TransactionTypePayout, entities.TransactionTypeFee:
 			eventList = append(eventList, events.NewWalletDebited(
 				walletID,
-				amount,
+				netAmount,
 				transaction.ID(),
 				wallet.AvailableBalance(),
+				wallet.BalanceVersion(),
+			))
+		}
+		eventList = append(eventList, feeEvents(feeTx, wallet)...)
+		eventList = append(eventList, kycEvents...)
+		eventList = append(eventList, screeningEvents...)
+		if quota.SoftLimitCrossed {
+			eventList = append(eventList, events.NewWalletMetadataQuotaWarning(
+				walletID,
+				wallet.UserID(),
+				transaction.ID(),
+				quota.UsedBytes,
+				uc.metadataQuota.SoftLimitBytes,
 			))
 		}
 
@@ -249,12 +385,48 @@ func (uc *CreateTransactionUseCase) Execute(ctx context.Context, cmd dtos.Create
 		}
 
 		result = dtos.MapTransactionToDTO(transaction)
+		if quota.SoftLimitExceeded {
+			result.MetadataQuotaWarning = &dtos.MetadataQuotaWarningDTO{
+				UsedBytes:      quota.UsedBytes,
+				SoftLimitBytes: uc.metadataQuota.SoftLimitBytes,
+				HardLimitBytes: uc.metadataQuota.HardLimitBytes,
+			}
+		}
 		return nil
 	})
 
 	if err != nil {
+		// Гонка по idempotency_key - отвечаем как на повтор
+		if errors.IsDuplicateTransaction(err) && cmd.IdempotencyKey != "" {
+			existingTx, findErr := uc.transactionRepo.FindByIdempotencyKey(ctx, cmd.IdempotencyKey)
+			if findErr != nil {
+				return nil, err
+			}
+			return replayTransactionDTO(existingTx), nil
+		}
 		return nil, err
 	}
 
 	return result, nil
 }
+
+// clientMetadataSize - объём ключей клиента в metadata транзакции
+// (значения уже замаскированы AddMetadata).
+func clientMetadataSize(transaction *entities.Transaction, metadata map[string]interface{}) int64 {
+	if len(metadata) == 0 {
+		return 0
+	}
+	stored := transaction.Metadata()
+	client := make(map[string]interface{}, len(metadata))
+	for key := range metadata {
+		client[key] = stored[key]
+	}
+	return ports.MetadataSize(client)
+}
+
+// replayTransactionDTO строит DTO для уже обработанного idempotency_key.
+func replayTransactionDTO(existingTx *entities.Transaction) *dtos.TransactionDTO {
+	result := dtos.MapTransactionToDTO(existingTx)
+	result.IdempotentReplay = true
+	return result
+}
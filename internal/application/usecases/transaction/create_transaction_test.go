@@ -43,6 +43,10 @@ func (m *mockTransactionRepo) FindByIdempotencyKey(ctx context.Context, key stri
 	return nil, domainErrors.ErrEntityNotFound
 }
 
+func (m *mockTransactionRepo) FindByExternalReference(ctx context.Context, reference string) ([]*entities.Transaction, error) {
+	return nil, nil
+}
+
 func (m *mockTransactionRepo) FindByWalletID(ctx context.Context, walletID uuid.UUID, offset, limit int) ([]*entities.Transaction, error) {
 	return nil, nil
 }
@@ -51,6 +55,14 @@ func (m *mockTransactionRepo) FindPendingByWallet(ctx context.Context, walletID
 	return nil, nil
 }
 
+func (m *mockTransactionRepo) CountPendingByWallet(ctx context.Context, walletID uuid.UUID) (int, error) {
+	return 0, nil
+}
+
+func (m *mockTransactionRepo) CountOutcomes(ctx context.Context, from, to time.Time) (ports.TransactionOutcomeCounts, error) {
+	return ports.TransactionOutcomeCounts{}, nil
+}
+
 func (m *mockTransactionRepo) FindFailedRetryable(ctx context.Context, maxRetries int, limit int) ([]*entities.Transaction, error) {
 	return nil, nil
 }
@@ -59,6 +71,14 @@ func (m *mockTransactionRepo) List(ctx context.Context, filter ports.Transaction
 	return nil, nil
 }
 
+func (m *mockTransactionRepo) ListBefore(ctx context.Context, filter ports.TransactionFilter, before *ports.TransactionCursor, limit int) ([]*entities.Transaction, error) {
+	return nil, nil
+}
+
+func (m *mockTransactionRepo) ListAfter(ctx context.Context, filter ports.TransactionFilter, after *ports.TransactionCursor, limit int) ([]*entities.Transaction, error) {
+	return nil, nil
+}
+
 type mockWalletRepo struct {
 	findByIDFunc func(ctx context.Context, id uuid.UUID) (*entities.Wallet, error)
 	saveFunc     func(ctx context.Context, wallet *entities.Wallet) error
@@ -90,6 +110,10 @@ func (m *mockWalletRepo) ExistsByUserAndCurrency(ctx context.Context, userID uui
 	return false, nil
 }
 
+func (m *mockWalletRepo) FindBalanceByID(ctx context.Context, id uuid.UUID) (*ports.WalletBalance, error) {
+	return nil, nil
+}
+
 func (m *mockWalletRepo) List(ctx context.Context, filter ports.WalletFilter, offset, limit int) ([]*entities.Wallet, error) {
 	return nil, nil
 }
@@ -137,7 +161,7 @@ func createTestWallet(walletID, userID uuid.UUID, currency valueobjects.Currency
 	dailyLimit, _ := valueobjects.NewMoney("10000", currency)
 	monthlyLimit, _ := valueobjects.NewMoney("100000", currency)
 	return entities.ReconstructWallet(walletID, userID, currency, entities.WalletTypeFiat, entities.WalletStatusActive,
-		initialBalance, initialBalance, 0, dailyLimit, monthlyLimit, time.Now(), time.Now())
+		initialBalance, initialBalance, 0, dailyLimit, monthlyLimit, "DE", nil, time.Now(), time.Now())
 }
 
 // TestCreateTransactionUseCase_Deposit_Success тестирует успешное создание транзакции DEPOSIT
@@ -179,7 +203,7 @@ func TestCreateTransactionUseCase_Deposit_Success(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
 
 	cmd := dtos.CreateTransactionCommand{
 		WalletID:       walletID.String(),
@@ -209,6 +233,11 @@ func TestCreateTransactionUseCase_Deposit_Success(t *testing.T) {
 		t.Fatal("Expected transaction to be saved")
 	}
 
+	// Новая транзакция - не replay
+	if result.IdempotentReplay {
+		t.Error("Expected IdempotentReplay = false for a new transaction")
+	}
+
 	// Проверяем статус транзакции
 	if savedTransaction.Status() != entities.TransactionStatusCompleted {
 		t.Errorf("Expected transaction status = %s, got %s", entities.TransactionStatusCompleted, savedTransaction.Status())
@@ -219,6 +248,11 @@ func TestCreateTransactionUseCase_Deposit_Success(t *testing.T) {
 		t.Errorf("Expected transaction type = %s, got %s", entities.TransactionTypeDeposit, savedTransaction.Type())
 	}
 
+	// Тег юрисдикции копируется с кошелька
+	if savedTransaction.Jurisdiction() != "DE" {
+		t.Errorf("Expected transaction jurisdiction = DE, got %s", savedTransaction.Jurisdiction())
+	}
+
 	// Проверяем события (TransactionCreated, TransactionCompleted, WalletCredited)
 	if len(eventPublisher.publishedEvents) < 3 {
 		t.Errorf("Expected at least 3 events, got %d", len(eventPublisher.publishedEvents))
@@ -270,7 +304,7 @@ func TestCreateTransactionUseCase_Withdraw_Success(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
 
 	cmd := dtos.CreateTransactionCommand{
 		WalletID:       walletID.String(),
@@ -340,7 +374,7 @@ func TestCreateTransactionUseCase_Idempotency(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
 
 	cmd := dtos.CreateTransactionCommand{
 		WalletID:       walletID.String(),
@@ -362,6 +396,13 @@ func TestCreateTransactionUseCase_Idempotency(t *testing.T) {
 		t.Fatal("Expected result, got nil")
 	}
 
+	if !result.IdempotentReplay {
+		t.Error("Expected IdempotentReplay = true for a replayed key")
+	}
+	if !result.CreatedAt.Equal(existingTx.CreatedAt()) {
+		t.Errorf("Expected original CreatedAt = %v, got %v", existingTx.CreatedAt(), result.CreatedAt)
+	}
+
 	// Идемпотентность: не должны публиковаться новые события
 	if len(eventPublisher.publishedEvents) != 0 {
 		t.Errorf("Expected no new events (idempotent), got %d", len(eventPublisher.publishedEvents))
@@ -396,7 +437,7 @@ func TestCreateTransactionUseCase_InsufficientBalance(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
 
 	cmd := dtos.CreateTransactionCommand{
 		WalletID:       walletID.String(),
@@ -434,7 +475,7 @@ func TestCreateTransactionUseCase_InvalidWalletID(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
 
 	cmd := dtos.CreateTransactionCommand{
 		WalletID:       "invalid-uuid",
@@ -483,7 +524,7 @@ func TestCreateTransactionUseCase_WalletNotFound(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
 
 	cmd := dtos.CreateTransactionCommand{
 		WalletID:       walletID.String(),
@@ -533,7 +574,7 @@ func TestCreateTransactionUseCase_InvalidAmount(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
 
 	cmd := dtos.CreateTransactionCommand{
 		WalletID:       walletID.String(),
@@ -560,3 +601,54 @@ func TestCreateTransactionUseCase_InvalidAmount(t *testing.T) {
 		t.Errorf("Expected ValidationError, got: %v", err)
 	}
 }
+
+// TestCreateTransactionUseCase_AllValidationErrors проверяет, что синхронные
+// проверки команды возвращаются вместе и до загрузки кошелька.
+func TestCreateTransactionUseCase_AllValidationErrors(t *testing.T) {
+	ctx := context.Background()
+
+	walletRepo := &mockWalletRepo{
+		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+			t.Fatal("wallet must not be loaded for an invalid command")
+			return nil, nil
+		},
+	}
+	transactionRepo := &mockTransactionRepo{
+		findByIdempotencyKeyFunc: func(ctx context.Context, key string) (*entities.Transaction, error) {
+			return nil, domainErrors.ErrEntityNotFound
+		},
+	}
+
+	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, &mockEventPublisher{}, &mockUnitOfWork{}, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
+
+	cmd := dtos.CreateTransactionCommand{
+		WalletID:            "invalid-uuid",
+		IdempotencyKey:      uuid.New().String(),
+		Type:                "BONUS",
+		Amount:              "-1",
+		DestinationWalletID: "also-invalid",
+		Description:         "Test",
+	}
+
+	_, err := useCase.Execute(ctx, cmd)
+
+	var validationErrs domainErrors.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		t.Fatalf("Expected ValidationErrors, got: %v", err)
+	}
+
+	want := map[string]string{
+		"wallet_id":             domainErrors.ValidationCodeInvalidFormat,
+		"destination_wallet_id": domainErrors.ValidationCodeInvalidFormat,
+		"amount":                domainErrors.ValidationCodeInvalidFormat,
+		"type":                  domainErrors.ValidationCodeInvalidValue,
+	}
+	if len(validationErrs) != len(want) {
+		t.Fatalf("Expected %d errors, got %d: %v", len(want), len(validationErrs), validationErrs)
+	}
+	for _, ve := range validationErrs {
+		if want[ve.Field] != ve.Code {
+			t.Errorf("Field %s: expected code %q, got %q", ve.Field, want[ve.Field], ve.Code)
+		}
+	}
+}
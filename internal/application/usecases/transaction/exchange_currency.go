@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"strconv"
 
 	"github.com/Haleralex/wallethub/internal/application/dtos"
 	"github.com/Haleralex/wallethub/internal/application/ports"
@@ -19,10 +20,12 @@ type ExchangeCurrencyUseCase struct {
 	walletRepo     ports.WalletRepository
 	transactionRepo ports.TransactionRepository
 	rateProvider   ports.ExchangeRateProvider
+	rateSnapshots  ports.FXRateSnapshotRepository // nil - снимки курсов не сохраняются
 	eventPublisher ports.EventPublisher
 	uow            ports.UnitOfWork
 	spreadPercent  float64
 	fraudDetector  ports.FraudDetector
+	buildInfo      ports.BuildInfo // версия сборки для created_by_version
 }
 
 // NewExchangeCurrencyUseCase creates a new use case.
@@ -30,19 +33,23 @@ func NewExchangeCurrencyUseCase(
 	walletRepo ports.WalletRepository,
 	transactionRepo ports.TransactionRepository,
 	rateProvider ports.ExchangeRateProvider,
+	rateSnapshots ports.FXRateSnapshotRepository,
 	eventPublisher ports.EventPublisher,
 	uow ports.UnitOfWork,
 	spreadPercent float64,
 	fraudDetector ports.FraudDetector,
+	buildInfo ports.BuildInfo,
 ) *ExchangeCurrencyUseCase {
 	return &ExchangeCurrencyUseCase{
 		walletRepo:      walletRepo,
 		transactionRepo: transactionRepo,
 		rateProvider:    rateProvider,
+		rateSnapshots:   rateSnapshots,
 		eventPublisher:  eventPublisher,
 		uow:            uow,
 		spreadPercent:   spreadPercent,
 		fraudDetector:   fraudDetector,
+		buildInfo:       buildInfo,
 	}
 }
 
@@ -70,7 +77,10 @@ func (uc *ExchangeCurrencyUseCase) Execute(ctx context.Context, cmd dtos.Exchang
 				if err != nil {
 					return fmt.Errorf("failed to load destination wallet: %w", err)
 				}
-				result = uc.buildResult(sourceWallet, destWallet, existingTx, "", "", "")
+				// Повтор не обращается к провайдеру: курс - из снимка исполнения
+				rateStr, snapshotID, spreadStr := uc.replayRate(txCtx, existingTx)
+				result = uc.buildResult(sourceWallet, destWallet, existingTx, rateStr, spreadStr, metadataString(existingTx, "dest_amount"))
+				result.RateSnapshotID = snapshotID
 				return nil
 			}
 		}
@@ -115,16 +125,25 @@ func (uc *ExchangeCurrencyUseCase) Execute(ctx context.Context, cmd dtos.Exchang
 		}
 
 		// 6. Parse source amount
-		sourceAmount, err := valueobjects.NewMoney(cmd.Amount, sourceWallet.Currency())
+		sourceAmount, err := dtos.ParseCommandAmount(cmd.Amount, cmd.AmountMinor, sourceWallet.Currency())
 		if err != nil {
-			return errors.ValidationError{Field: "amount", Message: fmt.Sprintf("invalid amount: %v", err)}
+			return err
 		}
 
-		// 7. Get exchange rate
-		rate, err := uc.rateProvider.GetRate(txCtx, sourceWallet.Currency().Code(), destWallet.Currency().Code())
+		// 7. Get exchange rate. Курс фиксируется снимком (округлённым до
+		// entities.FXRateScale) и дальше используется только он
+		quote, err := uc.rateProvider.Quote(txCtx, sourceWallet.Currency().Code(), destWallet.Currency().Code())
 		if err != nil {
 			return fmt.Errorf("failed to get exchange rate: %w", err)
 		}
+		snapshot, err := entities.NewFXRateSnapshot(
+			sourceWallet.Currency().Code(), destWallet.Currency().Code(),
+			quote.Rate, quote.Source, quote.FetchedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("invalid exchange rate: %w", err)
+		}
+		rate := snapshot.Rate()
 
 		// 8. Apply spread: effectiveRate = rate * (1 - spread/100)
 		spreadFactor := new(big.Rat).SetFloat64(1.0 - uc.spreadPercent/100.0)
@@ -143,7 +162,7 @@ func (uc *ExchangeCurrencyUseCase) Execute(ctx context.Context, cmd dtos.Exchang
 				UserID:              sourceWallet.UserID().String(),
 				SourceWalletID:      sourceWalletID.String(),
 				DestinationWalletID: destWalletID.String(),
-				Amount:              cmd.Amount,
+				Amount:              sourceAmount.Amount().FloatString(sourceAmount.Currency().Decimals()),
 				Currency:            sourceWallet.Currency().Code(),
 				TransactionType:     "EXCHANGE",
 			})
@@ -170,6 +189,16 @@ func (uc *ExchangeCurrencyUseCase) Execute(ctx context.Context, cmd dtos.Exchang
 		if err != nil {
 			return fmt.Errorf("failed to create transaction: %w", err)
 		}
+
+		// Тег хранения копируется с исходного кошелька и дальше не меняется
+		if err := transaction.AssignJurisdiction(sourceWallet.Jurisdiction()); err != nil {
+			return fmt.Errorf("failed to assign jurisdiction: %w", err)
+		}
+
+		if err := transaction.StampCreatedByVersion(uc.buildInfo.ProducerVersion()); err != nil {
+			return fmt.Errorf("failed to stamp build version: %w", err)
+		}
+
 		if err := transaction.SetDestinationWallet(destWalletID); err != nil {
 			return fmt.Errorf("failed to set destination wallet: %w", err)
 		}
@@ -181,6 +210,9 @@ func (uc *ExchangeCurrencyUseCase) Execute(ctx context.Context, cmd dtos.Exchang
 		_ = transaction.AddMetadata("source_currency", sourceWallet.Currency().Code())
 		_ = transaction.AddMetadata("dest_currency", destWallet.Currency().Code())
 		_ = transaction.AddMetadata("dest_amount", destAmountMoney.String())
+		if uc.rateSnapshots != nil {
+			_ = transaction.AddMetadata(entities.MetadataFXRateSnapshotID, snapshot.ID().String())
+		}
 
 		// 11. Debit source wallet
 		if err := sourceWallet.Debit(sourceAmount); err != nil {
@@ -201,6 +233,11 @@ func (uc *ExchangeCurrencyUseCase) Execute(ctx context.Context, cmd dtos.Exchang
 		}
 
 		// 14. Save atomically
+		if uc.rateSnapshots != nil {
+			if err := uc.rateSnapshots.Append(txCtx, snapshot); err != nil {
+				return fmt.Errorf("failed to save rate snapshot: %w", err)
+			}
+		}
 		if err := uc.transactionRepo.Save(txCtx, transaction); err != nil {
 			return fmt.Errorf("failed to save transaction: %w", err)
 		}
@@ -216,8 +253,8 @@ func (uc *ExchangeCurrencyUseCase) Execute(ctx context.Context, cmd dtos.Exchang
 		spreadStr := fmt.Sprintf("%.2f%%", uc.spreadPercent)
 
 		eventList := []events.DomainEvent{
-			events.NewWalletDebited(sourceWalletID, sourceAmount, transaction.ID(), sourceWallet.AvailableBalance()),
-			events.NewWalletCredited(destWalletID, destAmountMoney, transaction.ID(), destWallet.AvailableBalance()),
+			events.NewWalletDebited(sourceWalletID, sourceAmount, transaction.ID(), sourceWallet.AvailableBalance(), sourceWallet.BalanceVersion()),
+			events.NewWalletCredited(destWalletID, destAmountMoney, transaction.ID(), destWallet.AvailableBalance(), destWallet.BalanceVersion()),
 			events.NewCurrencyExchanged(
 				transaction.ID(), sourceWalletID, destWalletID,
 				sourceAmount, destAmountMoney,
@@ -229,6 +266,9 @@ func (uc *ExchangeCurrencyUseCase) Execute(ctx context.Context, cmd dtos.Exchang
 		}
 
 		result = uc.buildResult(sourceWallet, destWallet, transaction, rateStr, spreadStr, destAmountMoney.String())
+		if uc.rateSnapshots != nil {
+			result.RateSnapshotID = snapshot.ID().String()
+		}
 		return nil
 	})
 
@@ -238,6 +278,40 @@ func (uc *ExchangeCurrencyUseCase) Execute(ctx context.Context, cmd dtos.Exchang
 	return result, nil
 }
 
+// replayRate восстанавливает эффективный курс исполненного обмена из его
+// снимка курса и сохранённого спреда. Обмен без снимка (до fx_rate_snapshots
+// или снимок удалён по retention) отдаёт курс из metadata.
+func (uc *ExchangeCurrencyUseCase) replayRate(ctx context.Context, tx *entities.Transaction) (rate, snapshotID, spread string) {
+	spreadPercent := metadataString(tx, "spread_percent")
+	if spreadPercent != "" {
+		spread = spreadPercent + "%"
+	}
+	rate = metadataString(tx, "effective_rate")
+
+	snapshotID = metadataString(tx, entities.MetadataFXRateSnapshotID)
+	id, err := uuid.Parse(snapshotID)
+	if err != nil || uc.rateSnapshots == nil {
+		return rate, "", spread
+	}
+	snapshot, err := uc.rateSnapshots.FindByID(ctx, id)
+	if err != nil {
+		return rate, "", spread
+	}
+	percent, err := strconv.ParseFloat(spreadPercent, 64)
+	if err != nil {
+		return rate, snapshotID, spread
+	}
+
+	spreadFactor := new(big.Rat).SetFloat64(1.0 - percent/100.0)
+	return new(big.Rat).Mul(snapshot.Rate(), spreadFactor).FloatString(8), snapshotID, spread
+}
+
+// metadataString возвращает строковое значение metadata транзакции ("" если нет).
+func metadataString(tx *entities.Transaction, key string) string {
+	value, _ := tx.Metadata()[key].(string)
+	return value
+}
+
 func (uc *ExchangeCurrencyUseCase) buildResult(
 	source, dest *entities.Wallet,
 	tx *entities.Transaction,
@@ -258,6 +332,8 @@ func (uc *ExchangeCurrencyUseCase) buildResult(
 			TotalBalance:     srcTotal.String(),
 			DailyLimit:       source.DailyLimit().String(),
 			MonthlyLimit:     source.MonthlyLimit().String(),
+			BalanceVersion:   source.BalanceVersion(),
+			Jurisdiction:     source.Jurisdiction(),
 			CreatedAt:        source.CreatedAt(),
 			UpdatedAt:        source.UpdatedAt(),
 		},
@@ -272,6 +348,8 @@ func (uc *ExchangeCurrencyUseCase) buildResult(
 			TotalBalance:     dstTotal.String(),
 			DailyLimit:       dest.DailyLimit().String(),
 			MonthlyLimit:     dest.MonthlyLimit().String(),
+			BalanceVersion:   dest.BalanceVersion(),
+			Jurisdiction:     dest.Jurisdiction(),
 			CreatedAt:        dest.CreatedAt(),
 			UpdatedAt:        dest.UpdatedAt(),
 		},
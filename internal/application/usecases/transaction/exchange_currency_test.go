@@ -0,0 +1,155 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// countingRates отдаёт заданный курс и считает обращения к провайдеру.
+type countingRates struct {
+	rate      *big.Rat
+	fetchedAt time.Time
+	err       error
+	calls     int
+}
+
+func (r *countingRates) GetRate(ctx context.Context, from, to string) (*big.Rat, error) {
+	quote, err := r.Quote(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return quote.Rate, nil
+}
+
+func (r *countingRates) Quote(_ context.Context, _, _ string) (*ports.RateQuote, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &ports.RateQuote{Rate: r.rate, Source: "test-provider", FetchedAt: r.fetchedAt}, nil
+}
+
+// newExchangeFixture создаёт USD кошелёк с 1000.00 и пустой EUR кошелёк того же пользователя.
+func newExchangeFixture(t *testing.T, h *crashHarness) (usd, eur *entities.Wallet) {
+	t.Helper()
+
+	usd = h.seedWallet(t, "1000.00")
+	eur, err := entities.NewWallet(usd.UserID(), valueobjects.MustNewCurrency("EUR"))
+	if err != nil {
+		t.Fatalf("failed to create EUR wallet: %v", err)
+	}
+	if err := h.wallets.Save(context.Background(), eur); err != nil {
+		t.Fatalf("failed to save EUR wallet: %v", err)
+	}
+	return usd, eur
+}
+
+// TestExchangeCurrencyUseCase_RecordsRateSnapshot тестирует, что обмен
+// сохраняет снимок курса провайдера и ссылается на него из metadata.
+func TestExchangeCurrencyUseCase_RecordsRateSnapshot(t *testing.T) {
+	ctx := context.Background()
+	h := newCrashHarness()
+	usd, eur := newExchangeFixture(t, h)
+
+	fetchedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	// Курс с лишними знаками: операция считает по округлённому снимку
+	rates := &countingRates{rate: big.NewRat(92345678901234567, 100000000000000000), fetchedAt: fetchedAt}
+	snapshots := memory.NewFXRateSnapshotRepository(memory.NewStore())
+	useCase := NewExchangeCurrencyUseCase(h.walletRepo, h.transactionRepo, rates, snapshots, h.eventPublisher, h.uow, 0, nil, ports.BuildInfo{})
+
+	result, err := useCase.Execute(ctx, dtos.ExchangeCurrencyCommand{
+		SourceWalletID:      usd.ID().String(),
+		DestinationWalletID: eur.ID().String(),
+		Amount:              "100.00",
+		IdempotencyKey:      uuid.NewString(),
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	tx, err := h.transactions.FindByID(ctx, uuid.MustParse(result.TransactionID))
+	if err != nil {
+		t.Fatalf("failed to load transaction: %v", err)
+	}
+	snapshotID, _ := tx.Metadata()[entities.MetadataFXRateSnapshotID].(string)
+	if snapshotID == "" || snapshotID != result.RateSnapshotID {
+		t.Fatalf("metadata %s = %q, result rate_snapshot_id = %q", entities.MetadataFXRateSnapshotID, snapshotID, result.RateSnapshotID)
+	}
+
+	snapshot, err := snapshots.FindByID(ctx, uuid.MustParse(snapshotID))
+	if err != nil {
+		t.Fatalf("snapshot %s not stored: %v", snapshotID, err)
+	}
+	if snapshot.Base() != "USD" || snapshot.Quote() != "EUR" {
+		t.Errorf("snapshot pair = %s/%s, want USD/EUR", snapshot.Base(), snapshot.Quote())
+	}
+	if got := snapshot.Rate().FloatString(entities.FXRateScale); got != "0.923456789012" {
+		t.Errorf("snapshot rate = %s, want 0.923456789012", got)
+	}
+	if snapshot.Source() != "test-provider" || !snapshot.FetchedAt().Equal(fetchedAt) {
+		t.Errorf("snapshot source = %q at %s, want test-provider at %s", snapshot.Source(), snapshot.FetchedAt(), fetchedAt)
+	}
+	if result.ExchangeRate != "0.92345679" {
+		t.Errorf("ExchangeRate = %s, want 0.92345679", result.ExchangeRate)
+	}
+	if result.DestinationAmount != "92.35 EUR" {
+		t.Errorf("DestinationAmount = %s, want 92.35 EUR", result.DestinationAmount)
+	}
+}
+
+// TestExchangeCurrencyUseCase_ReplayUsesStoredSnapshot тестирует, что
+// повтор обмена с тем же ключом отдаёт курс из снимка, не запрашивая провайдера.
+func TestExchangeCurrencyUseCase_ReplayUsesStoredSnapshot(t *testing.T) {
+	ctx := context.Background()
+	h := newCrashHarness()
+	usd, eur := newExchangeFixture(t, h)
+
+	rates := &countingRates{rate: big.NewRat(92, 100), fetchedAt: time.Now()}
+	snapshots := memory.NewFXRateSnapshotRepository(memory.NewStore())
+	useCase := NewExchangeCurrencyUseCase(h.walletRepo, h.transactionRepo, rates, snapshots, h.eventPublisher, h.uow, 0.5, nil, ports.BuildInfo{})
+
+	cmd := dtos.ExchangeCurrencyCommand{
+		SourceWalletID:      usd.ID().String(),
+		DestinationWalletID: eur.ID().String(),
+		Amount:              "100.00",
+		IdempotencyKey:      uuid.NewString(),
+	}
+	first, err := useCase.Execute(ctx, cmd)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// Живой курс изменился, а провайдер недоступен
+	rates.rate = big.NewRat(150, 100)
+	rates.err = errors.New("provider down")
+	rates.calls = 0
+
+	replay, err := useCase.Execute(ctx, cmd)
+	if err != nil {
+		t.Fatalf("replay Execute() error = %v", err)
+	}
+	if rates.calls != 0 {
+		t.Errorf("replay consulted the rate provider %d times", rates.calls)
+	}
+	if replay.TransactionID != first.TransactionID {
+		t.Errorf("replay TransactionID = %s, want %s", replay.TransactionID, first.TransactionID)
+	}
+	if replay.ExchangeRate != first.ExchangeRate || replay.ExchangeRate != "0.91540000" {
+		t.Errorf("replay ExchangeRate = %s, first = %s, want 0.91540000", replay.ExchangeRate, first.ExchangeRate)
+	}
+	if replay.RateSnapshotID != first.RateSnapshotID || replay.Spread != first.Spread || replay.DestinationAmount != first.DestinationAmount {
+		t.Errorf("replay = %+v, want snapshot/spread/amount of %+v", replay, first)
+	}
+	h.assertBalance(t, usd.ID(), "900.00 USD")
+}
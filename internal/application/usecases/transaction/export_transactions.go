@@ -0,0 +1,118 @@
+// Package transaction - ExportTransactions use case для выгрузки списка транзакций.
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+const (
+	// ExportPageSize - сколько транзакций выгрузка читает из хранилища за раз.
+	ExportPageSize = 500
+	// DefaultExportMaxRows - предел строк выгрузки по умолчанию.
+	DefaultExportMaxRows = 50000
+)
+
+// ExportTransactionsUseCase выгружает транзакции по фильтрам списка без
+// пагинации клиента: обход keyset-страницами (ListBefore) в порядке списка.
+type ExportTransactionsUseCase struct {
+	transactionRepo ports.TransactionReader
+	maxRows         int
+}
+
+// NewExportTransactionsUseCase создаёт новый use case. maxRows <= 0 -
+// DefaultExportMaxRows.
+func NewExportTransactionsUseCase(transactionRepo ports.TransactionReader, maxRows int) *ExportTransactionsUseCase {
+	if maxRows <= 0 {
+		maxRows = DefaultExportMaxRows
+	}
+	return &ExportTransactionsUseCase{
+		transactionRepo: transactionRepo,
+		maxRows:         maxRows,
+	}
+}
+
+// Execute проверяет, помещается ли выборка в maxRows, и возвращает ленивый
+// обход её строк. Строки, добавленные во время обхода в голову списка, в
+// выгрузку не попадают.
+func (uc *ExportTransactionsUseCase) Execute(ctx context.Context, query dtos.ExportTransactionsQuery) (*dtos.TransactionExportDTO, error) {
+	filter, err := transactionFilter(query.WalletID, query.UserID, query.Type, query.Status, query.CreatedFrom, query.CreatedTo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Строка за пределом maxRows означает усечение; узнаём до обхода,
+	// чтобы сообщить об этом в заголовке ответа
+	beyond, err := uc.transactionRepo.List(ctx, filter, uc.maxRows, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	// Первая страница читается сразу: ошибка хранилища возвращается до
+	// начала ответа, а не посреди выгрузки
+	first, err := uc.transactionRepo.ListBefore(ctx, filter, nil, min(ExportPageSize, uc.maxRows))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	return &dtos.TransactionExportDTO{
+		MaxRows:   uc.maxRows,
+		Truncated: len(beyond) > 0,
+		Rows:      uc.rows(ctx, filter, first, uc.maxRows),
+	}, nil
+}
+
+// rows обходит выборку страницами, начиная с уже прочитанной first.
+func (uc *ExportTransactionsUseCase) rows(ctx context.Context, filter ports.TransactionFilter, first []*entities.Transaction, maxRows int) iter.Seq2[*dtos.TransactionExportRowDTO, error] {
+	return func(yield func(*dtos.TransactionExportRowDTO, error) bool) {
+		page, emitted := first, 0
+		for {
+			for _, tx := range page {
+				row := &dtos.TransactionExportRowDTO{
+					TransactionDTO: dtos.ToTransactionDTO(tx),
+					Direction:      transactionDirection(tx, filter.WalletID),
+				}
+				if !yield(row, nil) {
+					return
+				}
+				emitted++
+			}
+
+			limit := min(ExportPageSize, maxRows-emitted)
+			if len(page) < ExportPageSize || limit <= 0 {
+				return
+			}
+
+			var err error
+			page, err = uc.transactionRepo.ListBefore(ctx, filter, ports.CursorOf(page[len(page)-1]), limit)
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to list transactions: %w", err))
+				return
+			}
+		}
+	}
+}
+
+// transactionDirection - направление движения средств для кошелька
+// walletID (nil - кошелёк-источник транзакции). Для типа из более нового
+// релиза направление неизвестно - пустая строка.
+func transactionDirection(tx *entities.Transaction, walletID *uuid.UUID) string {
+	if !tx.Type().IsKnown() {
+		return ""
+	}
+	if dest := tx.DestinationWalletID(); walletID != nil && dest != nil && *dest == *walletID && tx.WalletID() != *walletID {
+		return dtos.TransactionDirectionIn
+	}
+
+	if tx.Type().IsCredit() {
+		return dtos.TransactionDirectionIn
+	}
+	return dtos.TransactionDirectionOut
+}
@@ -0,0 +1,172 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// TestExportTransactions тестирует выгрузку: обход нескольких страниц в
+// порядке списка, усечение по maxRows и направление для кошелька фильтра.
+func TestExportTransactions(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	transactions := memory.NewTransactionRepository(store)
+
+	usd, err := valueobjects.NewCurrency("USD")
+	if err != nil {
+		t.Fatalf("NewCurrency() error = %v", err)
+	}
+	amount, err := valueobjects.NewMoney("1.00", usd)
+	if err != nil {
+		t.Fatalf("NewMoney() error = %v", err)
+	}
+	newWallet := func(email string) *entities.Wallet {
+		user, err := entities.NewUser(email, "Export Owner")
+		if err != nil {
+			t.Fatalf("NewUser() error = %v", err)
+		}
+		if err := memory.NewUserRepository(store).Save(ctx, user); err != nil {
+			t.Fatalf("Save user error = %v", err)
+		}
+		wallet, err := entities.NewWallet(user.ID(), usd)
+		if err != nil {
+			t.Fatalf("NewWallet() error = %v", err)
+		}
+		if err := memory.NewWalletRepository(store).Save(ctx, wallet); err != nil {
+			t.Fatalf("Save wallet error = %v", err)
+		}
+		return wallet
+	}
+	source, destination := newWallet("source@example.com"), newWallet("destination@example.com")
+
+	total := ExportPageSize + 20
+	for range total {
+		tx, err := entities.NewTransaction(source.ID(), uuid.NewString(), entities.TransactionTypeWithdraw, amount, "withdraw")
+		if err != nil {
+			t.Fatalf("NewTransaction() error = %v", err)
+		}
+		if err := transactions.Save(ctx, tx); err != nil {
+			t.Fatalf("Save transaction error = %v", err)
+		}
+	}
+
+	collect := func(t *testing.T, result *dtos.TransactionExportDTO) []*dtos.TransactionExportRowDTO {
+		t.Helper()
+		var rows []*dtos.TransactionExportRowDTO
+		for row, err := range result.Rows {
+			if err != nil {
+				t.Fatalf("Rows error = %v", err)
+			}
+			rows = append(rows, row)
+		}
+		return rows
+	}
+
+	t.Run("WalksAllPagesInListOrder", func(t *testing.T) {
+		walletID := source.ID().String()
+		result, err := NewExportTransactionsUseCase(transactions, 0).Execute(ctx, dtos.ExportTransactionsQuery{WalletID: &walletID})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.Truncated {
+			t.Error("Expected export to fit the default limit")
+		}
+
+		rows := collect(t, result)
+		if len(rows) != total {
+			t.Fatalf("Expected %d rows, got %d", total, len(rows))
+		}
+		listed, err := transactions.List(ctx, listFilter(t, walletID), 0, total)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		for i, tx := range listed {
+			if rows[i].ID != tx.ID().String() || rows[i].Direction != dtos.TransactionDirectionOut {
+				t.Fatalf("Row %d = %s/%s, want %s/OUT", i, rows[i].ID, rows[i].Direction, tx.ID())
+			}
+		}
+	})
+
+	t.Run("TruncatesAtMaxRows", func(t *testing.T) {
+		result, err := NewExportTransactionsUseCase(transactions, ExportPageSize+1).Execute(ctx, dtos.ExportTransactionsQuery{})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !result.Truncated || result.MaxRows != ExportPageSize+1 {
+			t.Errorf("Expected truncation at %d, got %+v", ExportPageSize+1, result)
+		}
+		if rows := collect(t, result); len(rows) != ExportPageSize+1 {
+			t.Errorf("Expected %d rows, got %d", ExportPageSize+1, len(rows))
+		}
+	})
+
+	t.Run("TransferIsIncomingForDestination", func(t *testing.T) {
+		transfer, err := entities.NewTransaction(source.ID(), uuid.NewString(), entities.TransactionTypeTransfer, amount, "transfer")
+		if err != nil {
+			t.Fatalf("NewTransaction() error = %v", err)
+		}
+		if err := transfer.SetDestinationWallet(destination.ID()); err != nil {
+			t.Fatalf("SetDestinationWallet() error = %v", err)
+		}
+		if err := transactions.Save(ctx, transfer); err != nil {
+			t.Fatalf("Save transaction error = %v", err)
+		}
+
+		walletID := destination.ID().String()
+		result, err := NewExportTransactionsUseCase(transactions, 0).Execute(ctx, dtos.ExportTransactionsQuery{WalletID: &walletID})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		rows := collect(t, result)
+		if len(rows) != 1 || rows[0].ID != transfer.ID().String() || rows[0].Direction != dtos.TransactionDirectionIn {
+			t.Errorf("Expected incoming transfer, got %+v", rows)
+		}
+	})
+
+	t.Run("UnknownTypeHasNoDirection", func(t *testing.T) {
+		// Транзакция типа из более нового релиза, как её прочитал бы репозиторий
+		now := time.Now()
+		chargeback, err := entities.ReconstructTransaction(
+			uuid.New(), destination.ID(), uuid.NewString(), "CHARGEBACK", entities.TransactionStatusPending,
+			amount, valueobjects.Zero(usd), amount,
+			nil, "", "", "chargeback", nil, "", "", 0, nil, "", "",
+			now, now, nil, nil, "",
+		)
+		if err != nil {
+			t.Fatalf("ReconstructTransaction() error = %v", err)
+		}
+		if err := transactions.Save(ctx, chargeback); err != nil {
+			t.Fatalf("Save transaction error = %v", err)
+		}
+
+		walletID, txType := destination.ID().String(), "CHARGEBACK"
+		result, err := NewExportTransactionsUseCase(transactions, 0).Execute(ctx, dtos.ExportTransactionsQuery{WalletID: &walletID, Type: &txType})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		rows := collect(t, result)
+		if len(rows) != 1 || rows[0].Type != "CHARGEBACK" || rows[0].Direction != "" {
+			t.Errorf("Expected chargeback without direction, got %+v", rows)
+		}
+	})
+}
+
+// listFilter - фильтр списка по кошельку или падение теста.
+func listFilter(t *testing.T, walletID string) ports.TransactionFilter {
+	t.Helper()
+
+	filter, err := transactionFilter(&walletID, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("transactionFilter() error = %v", err)
+	}
+	return filter
+}
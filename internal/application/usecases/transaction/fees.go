@@ -0,0 +1,103 @@
+// Package transaction - применение комиссий к исходящим транзакциям.
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// applyFeeQuote запрашивает комиссию у калькулятора и фиксирует разбивку в транзакции.
+//
+// Без калькулятора (nil) или при нулевой комиссии транзакция остаётся
+// с fee = 0 и net = gross.
+func applyFeeQuote(ctx context.Context, calc ports.FeeCalculator, tx *entities.Transaction, req *ports.FeeQuoteRequest) error {
+	if calc == nil || !tx.Type().IsFeeApplicable() {
+		return nil
+	}
+
+	quote, err := calc.Quote(ctx, req)
+	if err != nil {
+		return fmt.Errorf("fee quote failed: %w", err)
+	}
+	if quote == nil {
+		return nil
+	}
+
+	if err := tx.ApplyFee(quote.Amount, quote.Mode); err != nil {
+		return fmt.Errorf("failed to apply fee: %w", err)
+	}
+	return nil
+}
+
+// payerDebit - сумма, списываемая с кошелька плательщика: net + fee.
+// Это gross + fee при SENDER_PAYS и ровно gross при DEDUCTED.
+//
+// Списывается одним Debit: Save кошелька допускает одно изменение баланса
+// (optimistic locking по balance version).
+func payerDebit(tx *entities.Transaction) (valueobjects.Money, error) {
+	total, err := tx.NetAmount().Add(tx.FeeAmount())
+	if err != nil {
+		return valueobjects.Money{}, fmt.Errorf("failed to compute debit amount: %w", err)
+	}
+	return total, nil
+}
+
+// chargeFee создаёт связанную FEE транзакцию - запись о списанной комиссии.
+// Сама комиссия уже списана с кошелька вместе с net (см. payerDebit).
+// Возвращает nil, если комиссии нет.
+func chargeFee(parent *entities.Transaction) (*entities.Transaction, error) {
+	if !parent.HasFee() {
+		return nil, nil
+	}
+
+	feeTx, err := entities.NewFeeTransaction(parent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fee transaction: %w", err)
+	}
+
+	if err := feeTx.StartProcessing(); err != nil {
+		return nil, fmt.Errorf("failed to start processing fee transaction: %w", err)
+	}
+	if err := feeTx.MarkCompleted(); err != nil {
+		return nil, fmt.Errorf("failed to complete fee transaction: %w", err)
+	}
+
+	return feeTx, nil
+}
+
+// feeEvents - события FEE транзакции; пусто, если комиссии нет.
+func feeEvents(feeTx *entities.Transaction, payer *entities.Wallet) []events.DomainEvent {
+	if feeTx == nil {
+		return nil
+	}
+
+	return []events.DomainEvent{
+		events.NewTransactionCreated(
+			feeTx.ID(),
+			feeTx.WalletID(),
+			payer.UserID(),
+			string(feeTx.Type()),
+			feeTx.Amount(),
+			feeTx.IdempotencyKey(),
+		),
+		events.NewWalletDebited(
+			feeTx.WalletID(),
+			feeTx.Amount(),
+			feeTx.ID(),
+			payer.AvailableBalance(),
+			payer.BalanceVersion(),
+		),
+		events.NewTransactionCompleted(
+			feeTx.ID(),
+			feeTx.WalletID(),
+			payer.UserID(),
+			string(feeTx.Type()),
+			feeTx.Amount(),
+		).WithReceiptNumber(feeTx.ReceiptNumber()),
+	}
+}
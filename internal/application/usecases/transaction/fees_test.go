@@ -0,0 +1,246 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/google/uuid"
+)
+
+// stubFeeCalculator возвращает фиксированную комиссию.
+type stubFeeCalculator struct {
+	fee  string
+	mode entities.FeeMode
+}
+
+func (s *stubFeeCalculator) Quote(_ context.Context, req *ports.FeeQuoteRequest) (*ports.FeeQuote, error) {
+	fee, err := valueobjects.NewMoney(s.fee, req.Amount.Currency())
+	if err != nil {
+		return nil, err
+	}
+	return &ports.FeeQuote{Amount: fee, Mode: s.mode}, nil
+}
+
+// TestTransferBetweenWalletsUseCase_FeeModes проверяет разбивку gross/fee/net
+// и движение средств для обоих режимов комиссии и для нулевой комиссии.
+func TestTransferBetweenWalletsUseCase_FeeModes(t *testing.T) {
+	tests := []struct {
+		name          string
+		calc          ports.FeeCalculator
+		wantFee       string
+		wantNet       string
+		wantMode      string
+		wantSource    string
+		wantDest      string
+		wantFeeTx     bool
+		wantEventsLen int
+	}{
+		{
+			name:          "NoCalculator",
+			calc:          nil,
+			wantFee:       "0.00 USD",
+			wantNet:       "100.00 USD",
+			wantSource:    "900.00 USD",
+			wantDest:      "1100.00 USD",
+			wantEventsLen: 4,
+		},
+		{
+			name:          "ZeroFee",
+			calc:          &stubFeeCalculator{fee: "0", mode: entities.FeeModeDeducted},
+			wantFee:       "0.00 USD",
+			wantNet:       "100.00 USD",
+			wantSource:    "900.00 USD",
+			wantDest:      "1100.00 USD",
+			wantEventsLen: 4,
+		},
+		{
+			name:          "SenderPays",
+			calc:          &stubFeeCalculator{fee: "1.50", mode: entities.FeeModeSenderPays},
+			wantFee:       "1.50 USD",
+			wantNet:       "100.00 USD",
+			wantMode:      "SENDER_PAYS",
+			wantSource:    "898.50 USD",
+			wantDest:      "1100.00 USD",
+			wantFeeTx:     true,
+			wantEventsLen: 7,
+		},
+		{
+			name:          "Deducted",
+			calc:          &stubFeeCalculator{fee: "1.50", mode: entities.FeeModeDeducted},
+			wantFee:       "1.50 USD",
+			wantNet:       "98.50 USD",
+			wantMode:      "DEDUCTED",
+			wantSource:    "900.00 USD",
+			wantDest:      "1098.50 USD",
+			wantFeeTx:     true,
+			wantEventsLen: 7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			h := newCrashHarness()
+			source := h.seedWallet(t, "1000.00")
+			dest := h.seedWallet(t, "1000.00")
+
+			useCase := NewTransferBetweenWalletsUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, tt.calc, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
+			cmd := dtos.TransferFundsCommand{
+				SourceWalletID:      source.ID().String(),
+				DestinationWalletID: dest.ID().String(),
+				Amount:              "100.00",
+				IdempotencyKey:      uuid.NewString(),
+			}
+
+			result, err := useCase.Execute(ctx, cmd)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.GrossAmount != "100.00 USD" || result.Amount != "100.00 USD" {
+				t.Errorf("Expected gross = amount = 100.00, got gross=%s amount=%s", result.GrossAmount, result.Amount)
+			}
+			if result.FeeAmount != tt.wantFee {
+				t.Errorf("Expected fee = %s, got %s", tt.wantFee, result.FeeAmount)
+			}
+			if result.NetAmount != tt.wantNet {
+				t.Errorf("Expected net = %s, got %s", tt.wantNet, result.NetAmount)
+			}
+			if result.FeeMode != tt.wantMode {
+				t.Errorf("Expected fee mode = %q, got %q", tt.wantMode, result.FeeMode)
+			}
+
+			h.assertBalance(t, source.ID(), tt.wantSource)
+			h.assertBalance(t, dest.ID(), tt.wantDest)
+
+			// FEE транзакция связана с переводом и списывает ровно fee
+			feeTx, err := h.transactions.FindByIdempotencyKey(ctx, entities.FeeIdempotencyKey(cmd.IdempotencyKey))
+			if !tt.wantFeeTx {
+				if !domainErrors.IsNotFound(err) {
+					t.Errorf("Expected no fee transaction, got err = %v", err)
+				}
+				if result.FeeTransactionID != "" {
+					t.Errorf("Expected empty fee_transaction_id, got %s", result.FeeTransactionID)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Expected fee transaction, got err = %v", err)
+				}
+				if feeTx.Type() != entities.TransactionTypeFee || feeTx.Amount().String() != tt.wantFee {
+					t.Errorf("Expected FEE of %s, got %s of %s", tt.wantFee, feeTx.Type(), feeTx.Amount())
+				}
+				if parentID, ok := feeTx.ParentTransactionID(); !ok || parentID.String() != result.TransactionID {
+					t.Errorf("Expected fee parent = %s, got %s", result.TransactionID, parentID)
+				}
+				if result.FeeTransactionID != feeTx.ID().String() {
+					t.Errorf("Expected fee_transaction_id = %s, got %s", feeTx.ID(), result.FeeTransactionID)
+				}
+			}
+
+			// События перевода несут ту же разбивку
+			published := h.events.Events()
+			if len(published) != tt.wantEventsLen {
+				t.Errorf("Expected %d events, got %d", tt.wantEventsLen, len(published))
+			}
+			for _, event := range published {
+				completed, ok := event.(*events.TransactionCompleted)
+				if !ok || completed.TransactionID.String() != result.TransactionID {
+					continue
+				}
+				if completed.FeeAmount.String() != tt.wantFee || completed.NetAmount.String() != tt.wantNet {
+					t.Errorf("Expected event fee/net = %s/%s, got %s/%s",
+						tt.wantFee, tt.wantNet, completed.FeeAmount, completed.NetAmount)
+				}
+				// Владельцы обоих кошельков - без lookup'а у потребителей
+				if completed.UserID != source.UserID() || completed.CounterpartyUserID != dest.UserID() {
+					t.Errorf("Expected event users %s -> %s, got %s -> %s",
+						source.UserID(), dest.UserID(), completed.UserID, completed.CounterpartyUserID)
+				}
+				if completed.CounterpartyWalletID != dest.ID() {
+					t.Errorf("Expected counterparty wallet %s, got %s", dest.ID(), completed.CounterpartyWalletID)
+				}
+			}
+
+			// Повтор не списывает комиссию второй раз
+			replay, err := useCase.Execute(ctx, cmd)
+			if err != nil {
+				t.Fatalf("replay error = %v", err)
+			}
+			if !replay.IdempotentReplay || replay.FeeAmount != tt.wantFee || replay.FeeTransactionID != result.FeeTransactionID {
+				t.Errorf("Expected replay with the same breakdown, got %+v", replay)
+			}
+			h.assertBalance(t, source.ID(), tt.wantSource)
+		})
+	}
+}
+
+// TestTransferBetweenWalletsUseCase_FeeExceedsBalance проверяет, что
+// SENDER_PAYS комиссия учитывается при проверке баланса и откатывает перевод.
+func TestTransferBetweenWalletsUseCase_FeeExceedsBalance(t *testing.T) {
+	ctx := context.Background()
+	h := newCrashHarness()
+	source := h.seedWallet(t, "100.00")
+	dest := h.seedWallet(t, "0.00")
+
+	calc := &stubFeeCalculator{fee: "1.00", mode: entities.FeeModeSenderPays}
+	useCase := NewTransferBetweenWalletsUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, calc, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
+	cmd := dtos.TransferFundsCommand{
+		SourceWalletID:      source.ID().String(),
+		DestinationWalletID: dest.ID().String(),
+		Amount:              "100.00",
+		IdempotencyKey:      uuid.NewString(),
+	}
+
+	if _, err := useCase.Execute(ctx, cmd); err == nil {
+		t.Fatal("Expected error when balance does not cover amount + fee")
+	}
+
+	h.assertBalance(t, source.ID(), "100.00 USD")
+	h.assertBalance(t, dest.ID(), "0.00 USD")
+	h.assertNotCommitted(t, cmd.IdempotencyKey)
+}
+
+// TestCreateTransactionUseCase_WithdrawFee проверяет комиссию на вывод
+// и отсутствие комиссии на пополнение.
+func TestCreateTransactionUseCase_WithdrawFee(t *testing.T) {
+	ctx := context.Background()
+	h := newCrashHarness()
+	wallet := h.seedWallet(t, "1000.00")
+
+	calc := &stubFeeCalculator{fee: "2.00", mode: entities.FeeModeDeducted}
+	useCase := NewCreateTransactionUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, calc, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
+
+	withdraw, err := useCase.Execute(ctx, dtos.CreateTransactionCommand{
+		WalletID:       wallet.ID().String(),
+		Type:           string(entities.TransactionTypeWithdraw),
+		Amount:         "50.00",
+		IdempotencyKey: uuid.NewString(),
+	})
+	if err != nil {
+		t.Fatalf("withdraw error = %v", err)
+	}
+	if withdraw.GrossAmount != "50.00 USD" || withdraw.FeeAmount != "2.00 USD" || withdraw.NetAmount != "48.00 USD" {
+		t.Errorf("Expected 50.00/2.00/48.00, got %s/%s/%s", withdraw.GrossAmount, withdraw.FeeAmount, withdraw.NetAmount)
+	}
+	h.assertBalance(t, wallet.ID(), "950.00 USD")
+
+	deposit, err := useCase.Execute(ctx, dtos.CreateTransactionCommand{
+		WalletID:       wallet.ID().String(),
+		Type:           string(entities.TransactionTypeDeposit),
+		Amount:         "50.00",
+		IdempotencyKey: uuid.NewString(),
+	})
+	if err != nil {
+		t.Fatalf("deposit error = %v", err)
+	}
+	if deposit.FeeAmount != "0.00 USD" || deposit.NetAmount != deposit.GrossAmount {
+		t.Errorf("Expected no fee on deposit, got fee=%s net=%s", deposit.FeeAmount, deposit.NetAmount)
+	}
+	h.assertBalance(t, wallet.ID(), "1000.00 USD")
+}
@@ -12,11 +12,11 @@ import (
 
 // GetTransactionByIdempotencyKeyUseCase - use case для поиска транзакции по ключу идемпотентности.
 type GetTransactionByIdempotencyKeyUseCase struct {
-	transactionRepo ports.TransactionRepository
+	transactionRepo ports.TransactionReader
 }
 
 // NewGetTransactionByIdempotencyKeyUseCase создаёт новый use case.
-func NewGetTransactionByIdempotencyKeyUseCase(transactionRepo ports.TransactionRepository) *GetTransactionByIdempotencyKeyUseCase {
+func NewGetTransactionByIdempotencyKeyUseCase(transactionRepo ports.TransactionReader) *GetTransactionByIdempotencyKeyUseCase {
 	return &GetTransactionByIdempotencyKeyUseCase{
 		transactionRepo: transactionRepo,
 	}
@@ -0,0 +1,94 @@
+// Package transaction - GetFailureStats use case для admin статистики провалов.
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// failureStatsDefaultWindow - окно статистики без явного from.
+const failureStatsDefaultWindow = 24 * time.Hour
+
+// uncategorizedFailures - категория в отчёте для провалов без failure_category.
+const uncategorizedFailures = "UNCATEGORIZED"
+
+// GetFailureStatsUseCase - use case для доли провалов транзакций по категориям.
+//
+// Разделяет ожидаемые провалы по вине клиента (CLIENT) и провалы по вине
+// провайдера или системы. Доступ только для admin - проверяется в роутере.
+type GetFailureStatsUseCase struct {
+	transactionRepo ports.TransactionReader
+	now             func() time.Time
+}
+
+// NewGetFailureStatsUseCase создаёт новый use case.
+func NewGetFailureStatsUseCase(transactionRepo ports.TransactionReader) *GetFailureStatsUseCase {
+	return &GetFailureStatsUseCase{
+		transactionRepo: transactionRepo,
+		now:             time.Now,
+	}
+}
+
+// Execute считает исходы транзакций, завершённых в окне [from, to).
+func (uc *GetFailureStatsUseCase) Execute(ctx context.Context, query dtos.GetTransactionFailureStatsQuery) (*dtos.TransactionFailureStatsDTO, error) {
+	to := uc.now().UTC()
+	if query.To != nil {
+		to = query.To.UTC()
+	}
+	from := to.Add(-failureStatsDefaultWindow)
+	if query.From != nil {
+		from = query.From.UTC()
+	}
+	if !from.Before(to) {
+		return nil, errors.ValidationError{Field: "to", Message: "to must be after from"}
+	}
+
+	counts, err := uc.transactionRepo.CountOutcomes(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count transaction outcomes: %w", err)
+	}
+
+	result := &dtos.TransactionFailureStatsDTO{
+		From:       from,
+		To:         to,
+		Completed:  counts.Completed,
+		ByCategory: make([]dtos.FailureCategoryStatsDTO, 0, len(entities.FailureCategories)+1),
+	}
+	for _, count := range counts.Failed {
+		result.Failed += count
+	}
+	result.Finished = result.Completed + result.Failed
+	result.FailureRate = outcomeRate(result.Failed, result.Finished)
+
+	for _, category := range entities.FailureCategories {
+		count := counts.Failed[category]
+		result.ByCategory = append(result.ByCategory, dtos.FailureCategoryStatsDTO{
+			Category: string(category),
+			Count:    count,
+			Rate:     outcomeRate(count, result.Finished),
+		})
+	}
+	if count := counts.Failed[""]; count > 0 {
+		result.ByCategory = append(result.ByCategory, dtos.FailureCategoryStatsDTO{
+			Category: uncategorizedFailures,
+			Count:    count,
+			Rate:     outcomeRate(count, result.Finished),
+		})
+	}
+
+	return result, nil
+}
+
+// outcomeRate - доля part от total, 0 при пустом total.
+func outcomeRate(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
@@ -0,0 +1,82 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/google/uuid"
+)
+
+// TestGetFailureStatsUseCase проверяет долю провалов по категориям.
+func TestGetFailureStatsUseCase(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	h := &crashHarness{
+		wallets:      memory.NewWalletRepository(store),
+		transactions: memory.NewTransactionRepository(store),
+		users:        memory.NewUserRepository(store),
+	}
+	wallet := h.seedWallet(t, "0.00")
+
+	finish := func(category entities.FailureCategory) {
+		amount, _ := valueobjects.NewMoney("1.00", valueobjects.USD)
+		tx, _ := entities.NewTransaction(wallet.ID(), uuid.NewString(), entities.TransactionTypeWithdraw, amount, "stats")
+		_ = tx.StartProcessing()
+		if category == "" {
+			_ = tx.MarkCompleted()
+		} else if err := tx.MarkFailed("declined", category); err != nil {
+			t.Fatalf("MarkFailed() error = %v", err)
+		}
+		if err := h.transactions.Save(ctx, tx); err != nil {
+			t.Fatalf("failed to save transaction: %v", err)
+		}
+	}
+	for i := 0; i < 6; i++ {
+		finish("")
+	}
+	finish(entities.FailureCategoryClient)
+	finish(entities.FailureCategoryProvider)
+
+	useCase := NewGetFailureStatsUseCase(h.transactions)
+	stats, err := useCase.Execute(ctx, dtos.GetTransactionFailureStatsQuery{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if stats.Finished != 8 || stats.Completed != 6 || stats.Failed != 2 {
+		t.Errorf("Expected 8 finished (6/2), got %d (%d/%d)", stats.Finished, stats.Completed, stats.Failed)
+	}
+	if stats.FailureRate != 0.25 {
+		t.Errorf("Expected failure rate 0.25, got %v", stats.FailureRate)
+	}
+	expected := []dtos.FailureCategoryStatsDTO{
+		{Category: "CLIENT", Count: 1, Rate: 0.125},
+		{Category: "PROVIDER", Count: 1, Rate: 0.125},
+		{Category: "SYSTEM"},
+		{Category: "FRAUD"},
+	}
+	if len(stats.ByCategory) != len(expected) {
+		t.Fatalf("Expected %d categories, got %v", len(expected), stats.ByCategory)
+	}
+	for i, category := range expected {
+		if stats.ByCategory[i] != category {
+			t.Errorf("Category %d: expected %+v, got %+v", i, category, stats.ByCategory[i])
+		}
+	}
+
+	// Окно без транзакций
+	from := time.Now().Add(-48 * time.Hour)
+	to := from.Add(time.Hour)
+	stats, err = useCase.Execute(ctx, dtos.GetTransactionFailureStatsQuery{From: &from, To: &to})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if stats.Finished != 0 || stats.FailureRate != 0 {
+		t.Errorf("Expected empty window, got %d finished, rate %v", stats.Finished, stats.FailureRate)
+	}
+}
@@ -0,0 +1,101 @@
+// Package transaction - GetReceipt use case для поиска чека по номеру.
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// GetReceiptUseCase - use case для получения чека по номеру (PB-XXXX-XXXX).
+//
+// Номер вводит человек (из письма, со скриншота, по телефону), поэтому
+// он нормализуется и проверяется по контрольному символу до обращения
+// к репозиторию: опечатка даёт ValidationError, а не "не найдено".
+//
+// Чек видят владелец кошелька-источника, владелец кошелька-получателя
+// и admin. Для остальных чек "не найден" - существование чужого номера
+// не раскрывается.
+type GetReceiptUseCase struct {
+	receiptRepo ports.TransactionReceiptRepository
+	walletRepo  ports.WalletReader
+}
+
+// NewGetReceiptUseCase создаёт новый use case.
+func NewGetReceiptUseCase(receiptRepo ports.TransactionReceiptRepository, walletRepo ports.WalletReader) *GetReceiptUseCase {
+	return &GetReceiptUseCase{
+		receiptRepo: receiptRepo,
+		walletRepo:  walletRepo,
+	}
+}
+
+// Execute возвращает чек по номеру.
+//
+// Errors:
+//   - ACTOR_REQUIRED: В context нет инициатора
+//   - ValidationError: Номер некорректен или содержит опечатку
+//   - RECEIPT_NOT_FOUND: Чека нет или он принадлежит чужим кошелькам
+func (uc *GetReceiptUseCase) Execute(ctx context.Context, query dtos.GetReceiptQuery) (*dtos.ReceiptDTO, error) {
+	actor, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "receipt lookup requires an authenticated actor", nil)
+	}
+
+	number, err := valueobjects.ParseReceiptNumber(query.ReceiptNumber)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "number", Message: "invalid receipt number, check it for typos"}
+	}
+
+	tx, err := uc.receiptRepo.FindByReceiptNumber(ctx, number)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, receiptNotFound(err)
+		}
+		return nil, fmt.Errorf("failed to load receipt: %w", err)
+	}
+
+	if !actor.IsAdmin() {
+		walletIDs := []uuid.UUID{tx.WalletID()}
+		if destWalletID := tx.DestinationWalletID(); destWalletID != nil {
+			walletIDs = append(walletIDs, *destWalletID)
+		}
+		owned, err := uc.ownsAnyWallet(ctx, actor.ID, walletIDs)
+		if err != nil {
+			return nil, err
+		}
+		if !owned {
+			return nil, receiptNotFound(errors.ErrEntityNotFound)
+		}
+	}
+
+	result := dtos.ToReceiptDTO(tx)
+	return &result, nil
+}
+
+// ownsAnyWallet проверяет, владеет ли пользователь хотя бы одним из кошельков.
+// Удалённый кошелёк просто не даёт доступа.
+func (uc *GetReceiptUseCase) ownsAnyWallet(ctx context.Context, userID uuid.UUID, walletIDs []uuid.UUID) (bool, error) {
+	for _, walletID := range walletIDs {
+		balance, err := uc.walletRepo.FindBalanceByID(ctx, walletID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return false, fmt.Errorf("failed to load wallet owner: %w", err)
+		}
+		if balance.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func receiptNotFound(cause error) error {
+	return errors.NewDomainError("RECEIPT_NOT_FOUND", "receipt not found", cause)
+}
@@ -0,0 +1,101 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// TestGetReceipt тестирует поиск чека: номер из события завершения,
+// доступ только участникам перевода и admin, опечатка - ValidationError.
+func TestGetReceipt(t *testing.T) {
+	store := memory.NewStore()
+	h := &crashHarness{
+		wallets:      memory.NewWalletRepository(store),
+		transactions: memory.NewTransactionRepository(store),
+		events:       memory.NewEventPublisher(store),
+		users:        memory.NewUserRepository(store),
+	}
+	h.walletRepo = h.wallets
+	h.transactionRepo = h.transactions
+	h.eventPublisher = h.events
+	h.uow = memory.NewUnitOfWork(store)
+
+	transfer := NewTransferBetweenWalletsUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
+	useCase := NewGetReceiptUseCase(h.transactions, h.wallets)
+
+	source := h.seedWallet(t, "100.00")
+	destination := h.seedWallet(t, "0.00")
+	result, err := transfer.Execute(context.Background(), transferCommand(source.ID(), destination.ID(), "25", true))
+	if err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+
+	var number string
+	for _, event := range h.events.Events() {
+		if completed, ok := event.(*events.TransactionCompleted); ok && completed.TransactionID.String() == result.TransactionID {
+			number = completed.ReceiptNumber
+		}
+	}
+	if number == "" {
+		t.Fatal("Expected receipt number in TransactionCompleted event")
+	}
+
+	asUser := func(userID uuid.UUID, role string) context.Context {
+		return ports.WithActor(context.Background(), ports.Actor{ID: userID, Role: role})
+	}
+
+	t.Run("participants and admin", func(t *testing.T) {
+		typed := strings.ToLower(strings.ReplaceAll(number, "-", ""))
+		for name, ctx := range map[string]context.Context{
+			"source owner":      asUser(source.UserID(), "user"),
+			"destination owner": asUser(destination.UserID(), "user"),
+			"admin":             asUser(uuid.New(), "admin"),
+		} {
+			receipt, err := useCase.Execute(ctx, dtos.GetReceiptQuery{ReceiptNumber: typed})
+			if err != nil {
+				t.Fatalf("%s: Execute() error = %v", name, err)
+			}
+			if receipt.ReceiptNumber != number || receipt.TransactionID != result.TransactionID {
+				t.Errorf("%s: receipt = %s/%s, want %s/%s", name, receipt.ReceiptNumber, receipt.TransactionID, number, result.TransactionID)
+			}
+			if receipt.VerificationHash == "" || receipt.NetAmount != "25.00 USD" {
+				t.Errorf("%s: hash = %q, net = %q", name, receipt.VerificationHash, receipt.NetAmount)
+			}
+		}
+	})
+
+	t.Run("stranger gets not found", func(t *testing.T) {
+		_, err := useCase.Execute(asUser(uuid.New(), "user"), dtos.GetReceiptQuery{ReceiptNumber: number})
+		var domainErr *domainErrors.DomainError
+		if !errors.As(err, &domainErr) || domainErr.Code != "RECEIPT_NOT_FOUND" {
+			t.Errorf("Expected RECEIPT_NOT_FOUND, got %v", err)
+		}
+	})
+
+	t.Run("mistyped number", func(t *testing.T) {
+		typo := []byte(number)
+		typo[3] = map[bool]byte{true: 'X', false: 'Y'}[typo[3] != 'X']
+
+		_, err := useCase.Execute(asUser(source.UserID(), "user"), dtos.GetReceiptQuery{ReceiptNumber: string(typo)})
+		var validationErr domainErrors.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "number" {
+			t.Errorf("Expected ValidationError on number, got %v", err)
+		}
+	})
+
+	t.Run("actor required", func(t *testing.T) {
+		if _, err := useCase.Execute(context.Background(), dtos.GetReceiptQuery{ReceiptNumber: number}); err == nil {
+			t.Error("Expected ACTOR_REQUIRED without actor")
+		}
+	})
+}
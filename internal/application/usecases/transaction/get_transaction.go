@@ -13,11 +13,11 @@ import (
 
 // GetTransactionUseCase - use case для получения транзакции по ID.
 type GetTransactionUseCase struct {
-	transactionRepo ports.TransactionRepository
+	transactionRepo ports.TransactionReader
 }
 
 // NewGetTransactionUseCase создаёт новый use case.
-func NewGetTransactionUseCase(transactionRepo ports.TransactionRepository) *GetTransactionUseCase {
+func NewGetTransactionUseCase(transactionRepo ports.TransactionReader) *GetTransactionUseCase {
 	return &GetTransactionUseCase{
 		transactionRepo: transactionRepo,
 	}
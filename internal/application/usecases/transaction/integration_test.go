@@ -44,6 +44,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
 	"github.com/Haleralex/wallethub/internal/domain/entities"
 	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
 	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
@@ -76,6 +77,12 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+// strictContext включает strict UnitOfWorkGuard, как storage.strict_uow в CI:
+// запрос к репозиторию мимо транзакции use case роняет тест паникой.
+func strictContext(ctx context.Context) context.Context {
+	return ports.WithUnitOfWorkGuard(ctx, ports.NewUnitOfWorkGuard(nil, true))
+}
+
 // getTestConfig возвращает конфигурацию для тестовой БД
 func getTestConfig() postgres.Config {
 	cfg := postgres.DefaultConfig()
@@ -174,7 +181,7 @@ func createTestWalletIntegration(t *testing.T, ctx context.Context, userID uuid.
 // ============================================
 
 func TestCreateTransactionUseCase_Integration_Deposit_Success(t *testing.T) {
-	ctx := context.Background()
+	ctx := strictContext(context.Background())
 	cleanupDB(t, ctx)
 
 	// 1. Setup: создаём реальные repositories и use case
@@ -186,7 +193,7 @@ func TestCreateTransactionUseCase_Integration_Deposit_Success(t *testing.T) {
 	// или реальный in-memory publisher если нужно проверить события
 	eventPublisher := &mockEventPublisher{}
 
-	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
 
 	// 2. Подготовка тестовых данных в БД
 	user := createTestUser(t, ctx, "deposit@test.com", "Deposit Test")
@@ -251,7 +258,7 @@ func TestCreateTransactionUseCase_Integration_Deposit_Success(t *testing.T) {
 // ============================================
 
 func TestCreateTransactionUseCase_Integration_Idempotency(t *testing.T) {
-	ctx := context.Background()
+	ctx := strictContext(context.Background())
 	cleanupDB(t, ctx)
 
 	// Setup
@@ -260,7 +267,7 @@ func TestCreateTransactionUseCase_Integration_Idempotency(t *testing.T) {
 	uow := postgres.NewUnitOfWork(testPool)
 	eventPublisher := &mockEventPublisher{}
 
-	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
 
 	user := createTestUser(t, ctx, "idempotency@test.com", "Idempotency Test")
 	wallet := createTestWalletIntegration(t, ctx, user.ID(), "USD", "1000.00")
@@ -327,7 +334,7 @@ func TestCreateTransactionUseCase_Integration_Idempotency(t *testing.T) {
 // ПОДСКАЗКА: Копируй структуру из Deposit_Success теста выше
 // ПОДСКАЗКА: Меняй только Type: "WITHDRAW" и проверяй balance уменьшился
 func TestCreateTransactionUseCase_Integration_Withdraw_Success(t *testing.T) {
-	ctx := context.Background()
+	ctx := strictContext(context.Background())
 	cleanupDB(t, ctx)
 
 	// 1. Setup: создаём repositories и use case
@@ -336,7 +343,7 @@ func TestCreateTransactionUseCase_Integration_Withdraw_Success(t *testing.T) {
 	uow := postgres.NewUnitOfWork(testPool)
 	eventPublisher := &mockEventPublisher{}
 
-	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
 
 	// 2. Подготовка тестовых данных: СНАЧАЛА user, ПОТОМ wallet!
 	user := createTestUser(t, ctx, "withdraw@test.com", "Withdraw Test")
@@ -402,7 +409,7 @@ func TestCreateTransactionUseCase_Integration_Withdraw_Success(t *testing.T) {
 // ПОДСКАЗКА: Используй errors.Is(err, domainErrors.ErrInsufficientBalance)
 // ПОДСКАЗКА: Попробуй найти транзакцию по idempotency_key - её не должно быть
 func TestCreateTransactionUseCase_Integration_InsufficientBalance(t *testing.T) {
-	ctx := context.Background()
+	ctx := strictContext(context.Background())
 	cleanupDB(t, ctx)
 
 	// 1. Setup: создаём repositories и use case
@@ -411,7 +418,7 @@ func TestCreateTransactionUseCase_Integration_InsufficientBalance(t *testing.T)
 	uow := postgres.NewUnitOfWork(testPool)
 	eventPublisher := &mockEventPublisher{}
 
-	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
 
 	// 2. Подготовка тестовых данных: СНАЧАЛА user, ПОТОМ wallet!
 	user := createTestUser(t, ctx, "insufficient@test.com", "Insufficient Balance Test")
@@ -479,7 +486,7 @@ func TestCreateTransactionUseCase_Integration_InsufficientBalance(t *testing.T)
 // ПОДСКАЗКА: Нужен TransferBetweenWalletsUseCase
 // ПОДСКАЗКА: Создай 2 wallet через createTestWalletIntegration() с разными userID
 func TestTransferBetweenWalletsUseCase_Integration_Success(t *testing.T) {
-	ctx := context.Background()
+	ctx := strictContext(context.Background())
 	cleanupDB(t, ctx)
 
 	// 1. Setup: создаём repositories и use case (TRANSFER - это отдельный UseCase!)
@@ -489,7 +496,7 @@ func TestTransferBetweenWalletsUseCase_Integration_Success(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 
 	// ← ПРАВИЛЬНО: используем TransferBetweenWalletsUseCase, а не CreateTransactionUseCase!
-	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
 
 	// 2. Подготовка тестовых данных: СНАЧАЛА user, ПОТОМ wallet!
 	sourceUser := createTestUser(t, ctx, "sourceUser@test.com", "Money source user")
@@ -556,7 +563,7 @@ func TestTransferBetweenWalletsUseCase_Integration_Success(t *testing.T) {
 //
 // ПОДСКАЗКА: domainErrors.IsBusinessRuleViolation(err)
 func TestTransferBetweenWalletsUseCase_Integration_CurrencyMismatch(t *testing.T) {
-	ctx := context.Background()
+	ctx := strictContext(context.Background())
 	cleanupDB(t, ctx)
 
 	// 1. Setup: создаём repositories и use case
@@ -565,7 +572,7 @@ func TestTransferBetweenWalletsUseCase_Integration_CurrencyMismatch(t *testing.T
 	uow := postgres.NewUnitOfWork(testPool)
 	eventPublisher := &mockEventPublisher{}
 
-	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
 
 	// 2. Подготовка тестовых данных: разные валюты!
 	sourceUser := createTestUser(t, ctx, "currency-source@test.com", "Currency Source User")
@@ -610,6 +617,59 @@ func TestTransferBetweenWalletsUseCase_Integration_CurrencyMismatch(t *testing.T
 	}
 }
 
+// TestTransferBetweenWalletsUseCase_Integration_ClosedDestination проверяет,
+// что перевод на закрытый кошелёк отклоняется до списания: баланс источника
+// не меняется, транзакции и события не создаются.
+func TestTransferBetweenWalletsUseCase_Integration_ClosedDestination(t *testing.T) {
+	ctx := strictContext(context.Background())
+	cleanupDB(t, ctx)
+
+	walletRepo := postgres.NewWalletRepository(testPool)
+	transactionRepo := postgres.NewTransactionRepository(testPool)
+	uow := postgres.NewUnitOfWork(testPool)
+	eventPublisher := &mockEventPublisher{}
+
+	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
+
+	sourceUser := createTestUser(t, ctx, "closed-source@test.com", "Closed Source User")
+	sourceWallet := createTestWalletIntegration(t, ctx, sourceUser.ID(), "USD", "1000.00")
+
+	destinationUser := createTestUser(t, ctx, "closed-dest@test.com", "Closed Dest User")
+	destinationWallet := createTestWalletIntegration(t, ctx, destinationUser.ID(), "USD", "0")
+	if err := destinationWallet.Close(); err != nil {
+		t.Fatalf("Failed to close destination wallet: %v", err)
+	}
+	if err := walletRepo.Save(ctx, destinationWallet); err != nil {
+		t.Fatalf("Failed to save closed destination wallet: %v", err)
+	}
+
+	idempotencyKey := uuid.New().String()
+	result, err := useCase.Execute(ctx, dtos.TransferFundsCommand{
+		SourceWalletID:      sourceWallet.ID().String(),
+		DestinationWalletID: destinationWallet.ID().String(),
+		IdempotencyKey:      idempotencyKey,
+		Amount:              "250.00",
+		Description:         "Integration test closed destination",
+	})
+
+	var violation *domainErrors.BusinessRuleViolation
+	if !errors.As(err, &violation) || violation.Rule != domainErrors.DestinationWalletClosedRule {
+		t.Fatalf("Expected %s violation, got: %v", domainErrors.DestinationWalletClosedRule, err)
+	}
+	if result != nil {
+		t.Errorf("Expected nil result on error, got: %+v", result)
+	}
+
+	assertBalance(t, ctx, sourceWallet.ID(), "1000.00", "USD")
+
+	if _, err := transactionRepo.FindByIdempotencyKey(ctx, idempotencyKey); !domainErrors.IsNotFound(err) {
+		t.Errorf("Expected no transaction for rejected transfer, got: %v", err)
+	}
+	if len(eventPublisher.publishedEvents) > 0 {
+		t.Errorf("Expected no events published on error, got %d events", len(eventPublisher.publishedEvents))
+	}
+}
+
 // TODO 5: TestProcessTransactionUseCase_Integration_Success
 //
 // ЧТО ТЕСТИРОВАТЬ:
@@ -623,7 +683,7 @@ func TestTransferBetweenWalletsUseCase_Integration_CurrencyMismatch(t *testing.T
 // ПОДСКАЗКА: Сохрани в БД через transactionRepo.Save(ctx, transaction)
 // ПОДСКАЗКА: Потом вызови ProcessTransactionUseCase
 func TestProcessTransactionUseCase_Integration_Success(t *testing.T) {
-	ctx := context.Background()
+	ctx := strictContext(context.Background())
 	cleanupDB(t, ctx)
 
 	// 1. Setup: repositories и use case
@@ -632,7 +692,7 @@ func TestProcessTransactionUseCase_Integration_Success(t *testing.T) {
 	uow := postgres.NewUnitOfWork(testPool)
 	eventPublisher := &mockEventPublisher{}
 
-	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
 
 	// 2. Подготовка: создаём user, wallet, и transaction в статусе PENDING
 	user := createTestUser(t, ctx, "process@test.com", "Process Test User")
@@ -711,7 +771,7 @@ func TestProcessTransactionUseCase_Integration_Success(t *testing.T) {
 //
 // ПОДСКАЗКА: Похоже на ProcessTransaction тест
 func TestCancelTransactionUseCase_Integration_Success(t *testing.T) {
-	ctx := context.Background()
+	ctx := strictContext(context.Background())
 	cleanupDB(t, ctx)
 
 	// 1. Setup: repositories и use case
@@ -806,7 +866,7 @@ func TestCancelTransactionUseCase_Integration_Success(t *testing.T) {
 //	Проверяем что optimistic locking работает и balance не "потеряется"
 //	при concurrent updates!
 func TestCreateTransactionUseCase_Integration_Concurrent(t *testing.T) {
-	ctx := context.Background()
+	ctx := strictContext(context.Background())
 	cleanupDB(t, ctx)
 
 	// 1. Setup: создаём repositories и use case
@@ -815,7 +875,7 @@ func TestCreateTransactionUseCase_Integration_Concurrent(t *testing.T) {
 	uow := postgres.NewUnitOfWork(testPool)
 	eventPublisher := &mockEventPublisher{}
 
-	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewCreateTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, ports.MetadataQuota{})
 
 	// 2. Подготовка тестовых данных с балансом 1000 USD
 	user := createTestUser(t, ctx, "concurrent@test.com", "Concurrent Test User")
@@ -843,7 +903,8 @@ func TestCreateTransactionUseCase_Integration_Concurrent(t *testing.T) {
 			}
 
 			// Используем retry механизм для обработки concurrency conflicts
-			result, err := ExecuteWithRetry(ctx, useCase, cmd, retryConfig)
+			// Свой guard на горутину: guard рассчитан на один запрос
+			result, err := ExecuteWithRetry(strictContext(ctx), useCase, cmd, retryConfig)
 			if err != nil {
 				errors <- err
 			} else if result != nil {
@@ -0,0 +1,91 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/application/kyc"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// newKYCTransferHarness - in-memory окружение перевода с проверкой KYC владельца.
+func newKYCTransferHarness(t *testing.T, mode kyc.Mode) (*crashHarness, *TransferBetweenWalletsUseCase) {
+	t.Helper()
+
+	h := newCrashHarness()
+	gate, err := kyc.NewGate(kyc.Policy{Mode: mode}, h.users)
+	if err != nil {
+		t.Fatalf("failed to create kyc gate: %v", err)
+	}
+	useCase := NewTransferBetweenWalletsUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, gate, nil, nil, nil)
+	return h, useCase
+}
+
+// revokeOwnerKYC отзывает KYC владельца кошелька.
+func (h *crashHarness) revokeOwnerKYC(t *testing.T, wallet *entities.Wallet) {
+	t.Helper()
+	ctx := context.Background()
+
+	owner, err := h.users.FindByID(ctx, wallet.UserID())
+	if err != nil {
+		t.Fatalf("failed to load owner: %v", err)
+	}
+	if err := owner.RevokeKYC("Sanctions list match"); err != nil {
+		t.Fatalf("failed to revoke KYC: %v", err)
+	}
+	if err := h.users.Save(ctx, owner); err != nil {
+		t.Fatalf("failed to save owner: %v", err)
+	}
+}
+
+// TestTransferBetweenWalletsUseCase_KYCGateStrict проверяет, что перевод с
+// кошелька владельца без KYC отклоняется, а перевод на его кошелёк проходит.
+func TestTransferBetweenWalletsUseCase_KYCGateStrict(t *testing.T) {
+	ctx := context.Background()
+	h, useCase := newKYCTransferHarness(t, kyc.ModeStrict)
+	revoked := h.seedWallet(t, "1000.00")
+	verified := h.seedWallet(t, "1000.00")
+	h.revokeOwnerKYC(t, revoked)
+
+	_, err := useCase.Execute(ctx, transferCommand(revoked.ID(), verified.ID(), "100.00", false))
+	assertRuleViolation(t, err, kyc.RuleNotVerified)
+	h.assertBalance(t, revoked.ID(), "1000.00 USD")
+
+	// Входящие средства не блокируются
+	if _, err := useCase.Execute(ctx, transferCommand(verified.ID(), revoked.ID(), "100.00", false)); err != nil {
+		t.Fatalf("Expected transfer to unverified owner to succeed, got: %v", err)
+	}
+	h.assertBalance(t, revoked.ID(), "1100.00 USD")
+	h.assertBalance(t, verified.ID(), "900.00 USD")
+}
+
+// TestTransferBetweenWalletsUseCase_KYCGateLenient проверяет, что в мягком
+// режиме перевод проходит и помечается для ручной проверки.
+func TestTransferBetweenWalletsUseCase_KYCGateLenient(t *testing.T) {
+	ctx := context.Background()
+	h, useCase := newKYCTransferHarness(t, kyc.ModeLenient)
+	revoked := h.seedWallet(t, "1000.00")
+	verified := h.seedWallet(t, "0.00")
+	h.revokeOwnerKYC(t, revoked)
+
+	result, err := useCase.Execute(ctx, transferCommand(revoked.ID(), verified.ID(), "100.00", false))
+	if err != nil {
+		t.Fatalf("Expected flagged transfer to succeed, got: %v", err)
+	}
+	if result.Transaction.Metadata[ports.KYCUnverifiedOwnerMetadataKey] != "REJECTED" {
+		t.Errorf("Expected KYC flag in metadata, got %v", result.Transaction.Metadata)
+	}
+	h.assertBalance(t, revoked.ID(), "900.00 USD")
+
+	flagged := 0
+	for _, event := range h.events.Events() {
+		if e, ok := event.(*events.TransactionFlagged); ok && e.RuleIDs[0] == kyc.RuleNotVerified {
+			flagged++
+		}
+	}
+	if flagged != 1 {
+		t.Errorf("Expected 1 TransactionFlagged event, got %d", flagged)
+	}
+}
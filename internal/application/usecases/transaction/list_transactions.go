@@ -4,6 +4,7 @@ package transaction
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/Haleralex/wallethub/internal/application/dtos"
 	"github.com/Haleralex/wallethub/internal/application/ports"
@@ -13,11 +14,11 @@ import (
 
 // ListTransactionsUseCase - use case для получения списка транзакций с фильтрацией.
 type ListTransactionsUseCase struct {
-	transactionRepo ports.TransactionRepository
+	transactionRepo ports.TransactionReader
 }
 
 // NewListTransactionsUseCase создаёт новый use case.
-func NewListTransactionsUseCase(transactionRepo ports.TransactionRepository) *ListTransactionsUseCase {
+func NewListTransactionsUseCase(transactionRepo ports.TransactionReader) *ListTransactionsUseCase {
 	return &ListTransactionsUseCase{
 		transactionRepo: transactionRepo,
 	}
@@ -25,43 +26,63 @@ func NewListTransactionsUseCase(transactionRepo ports.TransactionRepository) *Li
 
 // Execute возвращает список транзакций с фильтрацией и пагинацией.
 func (uc *ListTransactionsUseCase) Execute(ctx context.Context, query dtos.ListTransactionsQuery) (*dtos.TransactionListDTO, error) {
+	filter, err := transactionFilter(query.WalletID, query.UserID, query.Type, query.Status, query.CreatedFrom, query.CreatedTo)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := uc.transactionRepo.List(ctx, filter, query.Offset, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	return &dtos.TransactionListDTO{
+		Transactions: dtos.ToTransactionDTOList(transactions),
+		TotalCount:   len(transactions),
+		Offset:       query.Offset,
+		Limit:        query.Limit,
+	}, nil
+}
+
+// transactionFilter собирает фильтр хранилища из фильтров запроса списка.
+func transactionFilter(walletID, userID, txType, status *string, createdFrom, createdTo *time.Time) (ports.TransactionFilter, error) {
 	filter := ports.TransactionFilter{}
 
-	if query.WalletID != nil {
-		walletID, err := uuid.Parse(*query.WalletID)
+	if walletID != nil {
+		id, err := uuid.Parse(*walletID)
 		if err != nil {
-			return nil, fmt.Errorf("invalid wallet_id: %w", err)
+			return filter, fmt.Errorf("invalid wallet_id: %w", err)
 		}
-		filter.WalletID = &walletID
+		filter.WalletID = &id
 	}
 
-	if query.UserID != nil {
-		userID, err := uuid.Parse(*query.UserID)
+	if userID != nil {
+		id, err := uuid.Parse(*userID)
 		if err != nil {
-			return nil, fmt.Errorf("invalid user_id: %w", err)
+			return filter, fmt.Errorf("invalid user_id: %w", err)
 		}
-		filter.UserID = &userID
+		filter.UserID = &id
 	}
 
-	if query.Type != nil {
-		txType := entities.TransactionType(*query.Type)
-		filter.Type = &txType
+	if txType != nil {
+		t := entities.TransactionType(*txType)
+		filter.Type = &t
 	}
 
-	if query.Status != nil {
-		txStatus := entities.TransactionStatus(*query.Status)
+	if status != nil {
+		txStatus := entities.TransactionStatus(*status)
 		filter.Status = &txStatus
 	}
 
-	transactions, err := uc.transactionRepo.List(ctx, filter, query.Offset, query.Limit)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	if createdFrom != nil {
+		from := createdFrom.UTC()
+		filter.CreatedFrom = &from
 	}
 
-	return &dtos.TransactionListDTO{
-		Transactions: dtos.ToTransactionDTOList(transactions),
-		TotalCount:   len(transactions),
-		Offset:       query.Offset,
-		Limit:        query.Limit,
-	}, nil
+	if createdTo != nil {
+		to := createdTo.UTC()
+		filter.CreatedTo = &to
+	}
+
+	return filter, nil
 }
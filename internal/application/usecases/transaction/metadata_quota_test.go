@@ -0,0 +1,151 @@
+package transaction
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// depositWithMetadata - пополнение с одним ключом metadata заданного размера.
+func depositWithMetadata(wallet *entities.Wallet, valueSize int) dtos.CreateTransactionCommand {
+	cmd := dtos.CreateTransactionCommand{
+		WalletID:       wallet.ID().String(),
+		IdempotencyKey: uuid.NewString(),
+		Type:           string(entities.TransactionTypeDeposit),
+		Amount:         "1.00",
+		Description:    "deposit",
+	}
+	if valueSize > 0 {
+		cmd.Metadata = map[string]interface{}{"order": strings.Repeat("x", valueSize)}
+	}
+	return cmd
+}
+
+// quotaWarnings - число событий WalletMetadataQuotaWarning кошелька.
+func (h *crashHarness) quotaWarnings(walletID uuid.UUID) int {
+	count := 0
+	for _, event := range h.events.Events() {
+		if warning, ok := event.(*events.WalletMetadataQuotaWarning); ok && warning.WalletID == walletID {
+			count++
+		}
+	}
+	return count
+}
+
+func (h *crashHarness) metadataUsage(t *testing.T, walletID uuid.UUID) int64 {
+	t.Helper()
+	used, err := h.metadata.MetadataUsage(context.Background(), walletID)
+	if err != nil {
+		t.Fatalf("MetadataUsage() error = %v", err)
+	}
+	return used
+}
+
+func TestCreateTransactionUseCase_MetadataQuota(t *testing.T) {
+	ctx := context.Background()
+
+	// {"order":"xxx..."}: 12 байт обвязки + значение
+	const entryBytes = 12 + 88
+
+	newUseCase := func(h *crashHarness, quota ports.MetadataQuota) *CreateTransactionUseCase {
+		quota.Usage = h.metadata
+		return NewCreateTransactionUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil,
+			ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, nil, quota)
+	}
+
+	t.Run("soft limit warns and emits event once", func(t *testing.T) {
+		h := newCrashHarness()
+		wallet := h.seedWallet(t, "0.00")
+		useCase := newUseCase(h, ports.MetadataQuota{SoftLimitBytes: 2 * entryBytes})
+
+		for i := 0; i < 2; i++ {
+			result, err := useCase.Execute(ctx, depositWithMetadata(wallet, 88))
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.MetadataQuotaWarning != nil {
+				t.Errorf("Expected no warning at %d bytes, got %+v", h.metadataUsage(t, wallet.ID()), result.MetadataQuotaWarning)
+			}
+		}
+		if h.quotaWarnings(wallet.ID()) != 0 {
+			t.Fatal("Expected no quota event at the soft limit")
+		}
+
+		for i := 3; i <= 4; i++ {
+			result, err := useCase.Execute(ctx, depositWithMetadata(wallet, 88))
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			warning := result.MetadataQuotaWarning
+			if warning == nil || warning.UsedBytes != int64(i*entryBytes) || warning.SoftLimitBytes != 2*entryBytes {
+				t.Errorf("Transaction %d: warning = %+v, want used %d", i, warning, i*entryBytes)
+			}
+		}
+		if got := h.quotaWarnings(wallet.ID()); got != 1 {
+			t.Errorf("Expected 1 WalletMetadataQuotaWarning event, got %d", got)
+		}
+	})
+
+	t.Run("hard limit rejects metadata but not the transaction", func(t *testing.T) {
+		h := newCrashHarness()
+		wallet := h.seedWallet(t, "0.00")
+		useCase := newUseCase(h, ports.MetadataQuota{HardLimitBytes: entryBytes + 50})
+
+		if _, err := useCase.Execute(ctx, depositWithMetadata(wallet, 88)); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		rejected := depositWithMetadata(wallet, 88)
+		_, err := useCase.Execute(ctx, rejected)
+		var brv *domainErrors.BusinessRuleViolation
+		if !stderrors.As(err, &brv) || brv.Rule != "METADATA_QUOTA_EXCEEDED" {
+			t.Fatalf("Expected METADATA_QUOTA_EXCEEDED, got: %v", err)
+		}
+		h.assertNotCommittedKey(t, rejected.IdempotencyKey)
+		h.assertBalance(t, wallet.ID(), "1.00 USD")
+		if used := h.metadataUsage(t, wallet.ID()); used != entryBytes {
+			t.Errorf("Usage after rejection = %d, want %d", used, entryBytes)
+		}
+
+		// Повтор без metadata проходит
+		retry := depositWithMetadata(wallet, 0)
+		retry.IdempotencyKey = rejected.IdempotencyKey
+		if _, err := useCase.Execute(ctx, retry); err != nil {
+			t.Fatalf("Expected retry without metadata to succeed, got: %v", err)
+		}
+		h.assertBalance(t, wallet.ID(), "2.00 USD")
+	})
+
+	t.Run("archival frees quota", func(t *testing.T) {
+		h := newCrashHarness()
+		wallet := h.seedWallet(t, "0.00")
+		useCase := newUseCase(h, ports.MetadataQuota{HardLimitBytes: entryBytes})
+
+		first, err := useCase.Execute(ctx, depositWithMetadata(wallet, 88))
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if _, err := useCase.Execute(ctx, depositWithMetadata(wallet, 88)); err == nil {
+			t.Fatal("Expected METADATA_QUOTA_EXCEEDED before archival")
+		}
+
+		if err := h.metadata.ReleaseTransactionMetadata(ctx, []uuid.UUID{uuid.MustParse(first.ID)}); err != nil {
+			t.Fatalf("ReleaseTransactionMetadata() error = %v", err)
+		}
+		if used := h.metadataUsage(t, wallet.ID()); used != 0 {
+			t.Errorf("Usage after archival = %d, want 0", used)
+		}
+		if _, err := useCase.Execute(ctx, depositWithMetadata(wallet, 88)); err != nil {
+			t.Fatalf("Expected metadata to fit after archival, got: %v", err)
+		}
+	})
+}
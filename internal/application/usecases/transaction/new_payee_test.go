@@ -0,0 +1,119 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/payees"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// newPayeeHarness - in-memory окружение перевода с проверкой новых получателей.
+// Получатели хранятся в том же Store, что и кошельки, поэтому откатываются
+// вместе с UnitOfWork.
+func newPayeeHarness(t *testing.T, policy payees.Policy) (*crashHarness, *TransferBetweenWalletsUseCase) {
+	t.Helper()
+
+	store := memory.NewStore()
+	h := &crashHarness{
+		wallets:      memory.NewWalletRepository(store),
+		transactions: memory.NewTransactionRepository(store),
+		events:       memory.NewEventPublisher(store),
+		users:        memory.NewUserRepository(store),
+	}
+	h.walletRepo = h.wallets
+	h.transactionRepo = h.transactions
+	h.eventPublisher = h.events
+	h.uow = memory.NewUnitOfWork(store)
+
+	guard, err := payees.NewGuard(policy, memory.NewPayeeRepository(store), nil)
+	if err != nil {
+		t.Fatalf("failed to create payee guard: %v", err)
+	}
+
+	useCase := NewTransferBetweenWalletsUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil, nil, guard, ports.BuildInfo{}, nil, nil, nil, nil, nil)
+	return h, useCase
+}
+
+func transferCommand(source, destination uuid.UUID, amount string, confirm bool) dtos.TransferFundsCommand {
+	return dtos.TransferFundsCommand{
+		SourceWalletID:      source.String(),
+		DestinationWalletID: destination.String(),
+		Amount:              amount,
+		IdempotencyKey:      uuid.NewString(),
+		ConfirmNewPayee:     confirm,
+	}
+}
+
+func assertRuleViolation(t *testing.T, err error, rule string) {
+	t.Helper()
+
+	var violation *domainErrors.BusinessRuleViolation
+	if !errors.As(err, &violation) {
+		t.Fatalf("Expected BusinessRuleViolation %s, got %v", rule, err)
+	}
+	if violation.Rule != rule {
+		t.Errorf("Expected rule %s, got %s", rule, violation.Rule)
+	}
+}
+
+// TestTransferBetweenWalletsUseCase_NewPayeeLimit проверяет лимит первого перевода
+// новому получателю: превышение отклоняется без движения средств, после
+// первого завершённого перевода получатель больше не ограничивается.
+func TestTransferBetweenWalletsUseCase_NewPayeeLimit(t *testing.T) {
+	ctx := context.Background()
+	h, useCase := newPayeeHarness(t, payees.Policy{Enabled: true, MaxAmount: "100", LimitCurrency: "USD"})
+	source := h.seedWallet(t, "1000.00")
+	destination := h.seedWallet(t, "0.00")
+
+	// Первый перевод выше лимита
+	_, err := useCase.Execute(ctx, transferCommand(source.ID(), destination.ID(), "150.00", false))
+	assertRuleViolation(t, err, payees.RuleLimitExceeded)
+	h.assertBalance(t, source.ID(), "1000.00 USD")
+	h.assertBalance(t, destination.ID(), "0.00 USD")
+
+	// Первый перевод в пределах лимита
+	if _, err := useCase.Execute(ctx, transferCommand(source.ID(), destination.ID(), "100.00", false)); err != nil {
+		t.Fatalf("Expected first transfer under limit to succeed, got: %v", err)
+	}
+
+	// Второй перевод тому же получателю не ограничен
+	if _, err := useCase.Execute(ctx, transferCommand(source.ID(), destination.ID(), "500.00", false)); err != nil {
+		t.Fatalf("Expected transfer to known payee to succeed, got: %v", err)
+	}
+	h.assertBalance(t, source.ID(), "400.00 USD")
+	h.assertBalance(t, destination.ID(), "600.00 USD")
+
+	// Обратное направление - новый получатель
+	_, err = useCase.Execute(ctx, transferCommand(destination.ID(), source.ID(), "150.00", false))
+	assertRuleViolation(t, err, payees.RuleLimitExceeded)
+}
+
+// TestTransferBetweenWalletsUseCase_NewPayeeConfirmation проверяет флаг confirm_new_payee.
+func TestTransferBetweenWalletsUseCase_NewPayeeConfirmation(t *testing.T) {
+	ctx := context.Background()
+	h, useCase := newPayeeHarness(t, payees.Policy{Enabled: true, RequireConfirmation: true})
+	source := h.seedWallet(t, "1000.00")
+	destination := h.seedWallet(t, "0.00")
+
+	_, err := useCase.Execute(ctx, transferCommand(source.ID(), destination.ID(), "10.00", false))
+	assertRuleViolation(t, err, payees.RuleConfirmationRequired)
+	h.assertBalance(t, source.ID(), "1000.00 USD")
+
+	if _, err := useCase.Execute(ctx, transferCommand(source.ID(), destination.ID(), "10.00", true)); err != nil {
+		t.Fatalf("Expected confirmed transfer to succeed, got: %v", err)
+	}
+
+	// Получатель запомнен: подтверждение больше не требуется
+	if _, err := useCase.Execute(ctx, transferCommand(source.ID(), destination.ID(), "10.00", false)); err != nil {
+		t.Fatalf("Expected transfer to known payee to succeed, got: %v", err)
+	}
+	h.assertBalance(t, source.ID(), "980.00 USD")
+	h.assertBalance(t, destination.ID(), "20.00 USD")
+}
@@ -0,0 +1,54 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/operations"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// TestCreateTransactionUseCase_OperationGate тестирует, что выключенный
+// WITHDRAW останавливает выплаты, а депозиты продолжают создаваться.
+func TestCreateTransactionUseCase_OperationGate(t *testing.T) {
+	ctx := context.Background()
+	h := newCrashHarness()
+	wallet := h.seedWallet(t, "1000.00")
+
+	switches := memory.NewOperationSwitchRepository(memory.NewStore())
+	gate := operations.NewGate(switches, 0)
+	useCase := NewCreateTransactionUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil,
+		ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{}, nil, nil, gate, ports.MetadataQuota{})
+
+	disabled, err := entities.NewOperationSwitch(entities.TransactionTypeWithdraw, false, "payout provider incident", uuid.New())
+	if err != nil {
+		t.Fatalf("NewOperationSwitch() error = %v", err)
+	}
+	if err := switches.Save(ctx, disabled); err != nil {
+		t.Fatalf("save switch error = %v", err)
+	}
+	gate.Invalidate()
+
+	cmd := payoutCommand(wallet)
+	_, err = useCase.Execute(ctx, cmd)
+	if !domainErrors.IsOperationDisabled(err) {
+		t.Fatalf("Expected OperationDisabledError, got %v", err)
+	}
+	h.assertNotCommitted(t, cmd.IdempotencyKey)
+
+	deposit := dtos.CreateTransactionCommand{
+		WalletID:       wallet.ID().String(),
+		IdempotencyKey: uuid.NewString(),
+		Type:           string(entities.TransactionTypeDeposit),
+		Amount:         "10.00",
+	}
+	if _, err := useCase.Execute(ctx, deposit); err != nil {
+		t.Errorf("Deposit while WITHDRAW disabled error = %v", err)
+	}
+}
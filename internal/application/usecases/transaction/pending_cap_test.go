@@ -0,0 +1,134 @@
+package transaction
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/google/uuid"
+)
+
+// seedPending сохраняет n PENDING транзакций кошелька (как зависшие выплаты интегратора).
+func (h *crashHarness) seedPending(t *testing.T, wallet *entities.Wallet, n int) []*entities.Transaction {
+	t.Helper()
+
+	amount, _ := valueobjects.NewMoney("1.00", wallet.Currency())
+	pending := make([]*entities.Transaction, 0, n)
+	for i := 0; i < n; i++ {
+		tx, err := entities.NewTransaction(wallet.ID(), uuid.NewString(), entities.TransactionTypeDeposit, amount, "pending")
+		if err != nil {
+			t.Fatalf("failed to create transaction: %v", err)
+		}
+		if err := h.transactions.Save(context.Background(), tx); err != nil {
+			t.Fatalf("failed to save transaction: %v", err)
+		}
+		pending = append(pending, tx)
+	}
+	return pending
+}
+
+func payoutCommand(wallet *entities.Wallet) dtos.CreateTransactionCommand {
+	return dtos.CreateTransactionCommand{
+		WalletID:       wallet.ID().String(),
+		IdempotencyKey: uuid.NewString(),
+		Type:           string(entities.TransactionTypePayout),
+		Amount:         "10.00",
+	}
+}
+
+// assertTooManyPending проверяет отказ по лимиту незавершённых транзакций.
+func assertTooManyPending(t *testing.T, err error) {
+	t.Helper()
+
+	var brv *domainErrors.BusinessRuleViolation
+	if !stderrors.As(err, &brv) || brv.Rule != "TOO_MANY_PENDING_TRANSACTIONS" {
+		t.Fatalf("Expected TOO_MANY_PENDING_TRANSACTIONS, got: %v", err)
+	}
+}
+
+func TestCreateTransactionUseCase_PendingCap(t *testing.T) {
+	ctx := context.Background()
+	h := newCrashHarness()
+	wallet := h.seedWallet(t, "1000.00")
+	h.seedPending(t, wallet, 2)
+
+	useCase := NewCreateTransactionUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil,
+		ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{MaxPerWallet: 2}, nil, nil, nil, ports.MetadataQuota{})
+
+	_, err := useCase.Execute(ctx, payoutCommand(wallet))
+	assertTooManyPending(t, err)
+	h.assertBalance(t, wallet.ID(), "1000.00 USD")
+
+	// Лимит действует на кошелёк: другой кошелёк не затронут
+	other := h.seedWallet(t, "1000.00")
+	if _, err := useCase.Execute(ctx, payoutCommand(other)); err != nil {
+		t.Fatalf("Expected payout from another wallet to succeed, got: %v", err)
+	}
+}
+
+func TestCreateTransactionUseCase_PendingCapAdminOverride(t *testing.T) {
+	ctx := context.Background()
+	h := newCrashHarness()
+	wallet := h.seedWallet(t, "1000.00")
+	h.seedPending(t, wallet, 2)
+
+	override := 3
+	if err := wallet.SetMaxPendingTransactions(&override); err != nil {
+		t.Fatalf("SetMaxPendingTransactions() error = %v", err)
+	}
+	if err := h.wallets.Save(ctx, wallet); err != nil {
+		t.Fatalf("failed to save wallet: %v", err)
+	}
+
+	useCase := NewCreateTransactionUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil,
+		ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{MaxPerWallet: 2}, nil, nil, nil, ports.MetadataQuota{})
+
+	if _, err := useCase.Execute(ctx, payoutCommand(wallet)); err != nil {
+		t.Fatalf("Expected override to allow the payout, got: %v", err)
+	}
+
+	// Override тоже лимит: третья незавершённая транзакция исчерпывает его
+	h.seedPending(t, wallet, 1)
+	_, err := useCase.Execute(ctx, payoutCommand(wallet))
+	assertTooManyPending(t, err)
+}
+
+func TestCreateTransactionUseCase_PendingCapFreedByCompletion(t *testing.T) {
+	ctx := context.Background()
+	h := newCrashHarness()
+	wallet := h.seedWallet(t, "1000.00")
+	pending := h.seedPending(t, wallet, 2)
+
+	useCase := NewCreateTransactionUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil,
+		ports.BuildInfo{}, ports.SensitiveDataPolicy{}, ports.PendingTransactionsPolicy{MaxPerWallet: 2}, nil, nil, nil, ports.MetadataQuota{})
+	processUC := NewProcessTransactionUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil)
+	cancelUC := NewCancelTransactionUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow)
+
+	_, err := useCase.Execute(ctx, payoutCommand(wallet))
+	assertTooManyPending(t, err)
+
+	// Отмена освобождает место
+	if _, err := cancelUC.Execute(ctx, dtos.CancelTransactionCommand{TransactionID: pending[0].ID().String(), Reason: "stale"}); err != nil {
+		t.Fatalf("cancel error = %v", err)
+	}
+	if _, err := useCase.Execute(ctx, payoutCommand(wallet)); err != nil {
+		t.Fatalf("Expected payout after cancel to succeed, got: %v", err)
+	}
+
+	// Завершение тоже
+	h.seedPending(t, wallet, 1)
+	_, err = useCase.Execute(ctx, payoutCommand(wallet))
+	assertTooManyPending(t, err)
+
+	if _, err := processUC.Execute(ctx, dtos.ProcessTransactionCommand{TransactionID: pending[1].ID().String(), Success: true}); err != nil {
+		t.Fatalf("process error = %v", err)
+	}
+	if _, err := useCase.Execute(ctx, payoutCommand(wallet)); err != nil {
+		t.Fatalf("Expected payout after completion to succeed, got: %v", err)
+	}
+}
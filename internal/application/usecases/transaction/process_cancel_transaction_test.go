@@ -1,12 +1,17 @@
 package transaction
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	stderrors "errors"
+	"log/slog"
 	"testing"
 
 	"github.com/Haleralex/wallethub/internal/application/dtos"
 	"github.com/Haleralex/wallethub/internal/domain/entities"
 	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
 	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
 	"github.com/google/uuid"
 )
@@ -17,6 +22,7 @@ func TestProcessTransactionUseCase_Success(t *testing.T) {
 	ctx := context.Background()
 	transactionID := uuid.New()
 	walletID := uuid.New()
+	userID := uuid.New()
 	currency := valueobjects.MustNewCurrency("USD")
 
 	// Создаём транзакцию в статусе PENDING
@@ -25,7 +31,7 @@ func TestProcessTransactionUseCase_Success(t *testing.T) {
 
 	var savedTransaction *entities.Transaction
 
-	walletRepo := &mockWalletRepo{}
+	walletRepo := ownerWalletRepo(userID)
 
 	transactionRepo := &mockTransactionRepo{
 		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
@@ -43,7 +49,7 @@ func TestProcessTransactionUseCase_Success(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.ProcessTransactionCommand{
 		TransactionID: transactionID.String(),
@@ -73,7 +79,15 @@ func TestProcessTransactionUseCase_Success(t *testing.T) {
 
 	// Проверяем событие TransactionCompleted опубликовано
 	if len(eventPublisher.publishedEvents) == 0 {
-		t.Error("Expected at least 1 event to be published")
+		t.Fatal("Expected at least 1 event to be published")
+	}
+	completed, ok := eventPublisher.publishedEvents[0].(*events.TransactionCompleted)
+	if !ok {
+		t.Fatalf("Expected TransactionCompleted event, got %T", eventPublisher.publishedEvents[0])
+	}
+	// Владелец кошелька на момент транзакции
+	if completed.UserID != userID {
+		t.Errorf("Expected event user_id = %s, got %s", userID, completed.UserID)
 	}
 }
 
@@ -127,7 +141,7 @@ func TestProcessTransactionUseCase_Failure(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.ProcessTransactionCommand{
 		TransactionID: transactionID.String(),
@@ -166,9 +180,125 @@ func TestProcessTransactionUseCase_Failure(t *testing.T) {
 		t.Error("Expected wallet to be saved after rollback")
 	}
 
+	// Без категории в команде - провайдер
+	if savedTransaction.FailureCategory() != entities.FailureCategoryProvider {
+		t.Errorf("Expected failure category = PROVIDER, got '%s'", savedTransaction.FailureCategory())
+	}
+
 	// Проверяем событие TransactionFailed опубликовано
 	if len(eventPublisher.publishedEvents) == 0 {
-		t.Error("Expected at least 1 event to be published")
+		t.Fatal("Expected at least 1 event to be published")
+	}
+	failed, ok := eventPublisher.publishedEvents[0].(*events.TransactionFailed)
+	if !ok {
+		t.Fatalf("Expected TransactionFailed event, got %T", eventPublisher.publishedEvents[0])
+	}
+	if failed.FailureCategory != "PROVIDER" || !failed.IsRetryable {
+		t.Errorf("Expected PROVIDER retryable failure event, got category=%s retryable=%v", failed.FailureCategory, failed.IsRetryable)
+	}
+	if failed.UserID != userID {
+		t.Errorf("Expected event user_id = %s, got %s", userID, failed.UserID)
+	}
+}
+
+// TestProcessTransactionUseCase_Failure_Category проверяет категорию провала:
+// явная категория callback'а, иначе - по коду причины провайдера.
+func TestProcessTransactionUseCase_Failure_Category(t *testing.T) {
+	tests := []struct {
+		name             string
+		reason           string
+		category         string
+		expectedCategory entities.FailureCategory
+		expectedRetry    bool
+	}{
+		{"KnownReasonCode", "INSUFFICIENT_BALANCE", "", entities.FailureCategoryClient, false},
+		{"FraudReasonCode", "FRAUD_DETECTED", "", entities.FailureCategoryFraud, false},
+		{"ExplicitSystem", "ledger timeout", "SYSTEM", entities.FailureCategorySystem, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			walletID := uuid.New()
+			amount, _ := valueobjects.NewMoney("100.00", valueobjects.USD)
+			transaction, _ := entities.NewTransaction(walletID, uuid.New().String(), entities.TransactionTypeWithdraw, amount, "Test")
+			wallet := createTestWallet(walletID, uuid.New(), valueobjects.USD)
+
+			walletRepo := &mockWalletRepo{
+				findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+					return wallet, nil
+				},
+			}
+			transactionRepo := &mockTransactionRepo{
+				findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
+					return transaction, nil
+				},
+			}
+			eventPublisher := &mockEventPublisher{}
+			useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, &mockUnitOfWork{}, nil)
+
+			result, err := useCase.Execute(ctx, dtos.ProcessTransactionCommand{
+				TransactionID:   transaction.ID().String(),
+				FailureReason:   tt.reason,
+				FailureCategory: tt.category,
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if result.FailureCategory != string(tt.expectedCategory) {
+				t.Errorf("Expected failure_category = %s, got %s", tt.expectedCategory, result.FailureCategory)
+			}
+			if result.IsRetryable != tt.expectedRetry {
+				t.Errorf("Expected is_retryable = %v, got %v", tt.expectedRetry, result.IsRetryable)
+			}
+			failed := eventPublisher.publishedEvents[0].(*events.TransactionFailed)
+			if failed.FailureCategory != string(tt.expectedCategory) || failed.IsRetryable != tt.expectedRetry {
+				t.Errorf("Expected event %s/%v, got %s/%v", tt.expectedCategory, tt.expectedRetry, failed.FailureCategory, failed.IsRetryable)
+			}
+		})
+	}
+}
+
+// TestProcessTransactionUseCase_Failure_CurrencyMismatch тестирует rollback суммы
+// в чужой валюте: ошибка - CURRENCY_MISMATCH, а не INSUFFICIENT_BALANCE,
+// даже если сумма больше баланса кошелька.
+func TestProcessTransactionUseCase_Failure_CurrencyMismatch(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	transactionID := uuid.New()
+	walletID := uuid.New()
+
+	// Транзакция в EUR на USD-кошельке (будущий мультивалютный путь)
+	eurAmount, _ := valueobjects.NewMoney("5000.00", valueobjects.EUR)
+	transaction, _ := entities.NewTransaction(walletID, uuid.New().String(), entities.TransactionTypeDeposit, eurAmount, "Test")
+	wallet := createTestWallet(walletID, uuid.New(), valueobjects.USD)
+
+	walletRepo := &mockWalletRepo{
+		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+			return wallet, nil
+		},
+	}
+	transactionRepo := &mockTransactionRepo{
+		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
+			return transaction, nil
+		},
+	}
+
+	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, &mockEventPublisher{}, &mockUnitOfWork{}, nil)
+
+	// Act
+	_, err := useCase.Execute(ctx, dtos.ProcessTransactionCommand{
+		TransactionID: transactionID.String(),
+		Success:       false,
+	})
+
+	// Assert
+	if !domainErrors.IsCurrencyMismatch(err) {
+		t.Fatalf("Expected CURRENCY_MISMATCH, got: %v", err)
+	}
+	if stderrors.Is(err, domainErrors.ErrInsufficientBalance) {
+		t.Errorf("Currency mismatch must not surface as insufficient balance: %v", err)
 	}
 }
 
@@ -182,7 +312,7 @@ func TestProcessTransactionUseCase_InvalidTransactionID(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.ProcessTransactionCommand{
 		TransactionID: "invalid-uuid",
@@ -224,7 +354,7 @@ func TestProcessTransactionUseCase_TransactionNotFound(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.ProcessTransactionCommand{
 		TransactionID: transactionID.String(),
@@ -431,7 +561,7 @@ func TestProcessTransactionUseCase_IdempotentCompleted(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.ProcessTransactionCommand{
 		TransactionID: transactionID.String(),
@@ -500,7 +630,7 @@ func TestProcessTransactionUseCase_WithdrawRollback(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.ProcessTransactionCommand{
 		TransactionID: transactionID.String(),
@@ -565,12 +695,16 @@ func TestProcessTransactionUseCase_FailureDefaultReason(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, logger)
 
 	cmd := dtos.ProcessTransactionCommand{
 		TransactionID: transactionID.String(),
 		Success:       false,
 		FailureReason: "", // Empty - should use default
+		Provider:      "acme-pay",
+		Source:        "callback",
 	}
 
 	// Act
@@ -589,10 +723,91 @@ func TestProcessTransactionUseCase_FailureDefaultReason(t *testing.T) {
 		t.Fatal("Expected transaction to be saved")
 	}
 
-	// Should use default failure reason
-	if savedTransaction.FailureReason() != "external service error" {
+	// Should use the structured default: reason code, message key, metadata
+	if savedTransaction.FailureReason() != entities.FailureReasonUnspecified {
 		t.Errorf("Expected default failure reason, got: %s", savedTransaction.FailureReason())
 	}
+	if result.FailureMessageKey != "transaction.failure.unspecified" {
+		t.Errorf("Expected failure_message_key transaction.failure.unspecified, got %q", result.FailureMessageKey)
+	}
+	if result.FailureCategory != string(entities.FailureCategoryProvider) {
+		t.Errorf("Expected PROVIDER category, got %s", result.FailureCategory)
+	}
+	metadata := savedTransaction.Metadata()
+	if metadata["failure_provider"] != "acme-pay" || metadata["failure_source"] != "callback" {
+		t.Errorf("Expected provider and source in metadata, got %v", metadata)
+	}
+
+	// Warning with the transaction ID to chase the integrator
+	var entry map[string]interface{}
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected one JSON log entry, got %q: %v", logs.String(), err)
+	}
+	if entry["level"] != "WARN" || entry["transaction_id"] != transaction.ID().String() ||
+		entry["provider"] != "acme-pay" || entry["source"] != "callback" {
+		t.Errorf("Unexpected warning log entry: %v", entry)
+	}
+}
+
+// TestProcessTransactionUseCase_FailureProviderErrorCode тестирует провал
+// только с кодом провайдера: код становится причиной и категорией,
+// предупреждения в логе нет.
+func TestProcessTransactionUseCase_FailureProviderErrorCode(t *testing.T) {
+	tests := []struct {
+		name             string
+		reason           string
+		expectedReason   string
+		expectedKey      string
+		expectedMetadata string
+	}{
+		{"CodeOnly", "", "INVALID_ACCOUNT", "transaction.failure.invalid_account", ""},
+		{"ReasonAndCode", "account closed at bank", "account closed at bank", "", "INVALID_ACCOUNT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			walletID := uuid.New()
+			amount, _ := valueobjects.NewMoney("100.00", valueobjects.USD)
+			transaction, _ := entities.NewTransaction(walletID, uuid.New().String(), entities.TransactionTypeWithdraw, amount, "Test")
+			wallet := createTestWallet(walletID, uuid.New(), valueobjects.USD)
+
+			walletRepo := &mockWalletRepo{
+				findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+					return wallet, nil
+				},
+			}
+			transactionRepo := &mockTransactionRepo{
+				findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
+					return transaction, nil
+				},
+			}
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, nil))
+			useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, &mockEventPublisher{}, &mockUnitOfWork{}, logger)
+
+			result, err := useCase.Execute(context.Background(), dtos.ProcessTransactionCommand{
+				TransactionID:     transaction.ID().String(),
+				FailureReason:     tt.reason,
+				ProviderErrorCode: "INVALID_ACCOUNT",
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if result.FailureReason != tt.expectedReason || result.FailureMessageKey != tt.expectedKey {
+				t.Errorf("Expected %q/%q, got %q/%q", tt.expectedReason, tt.expectedKey, result.FailureReason, result.FailureMessageKey)
+			}
+			if result.FailureCategory != string(entities.FailureCategoryClient) {
+				t.Errorf("Expected CLIENT category from provider code, got %s", result.FailureCategory)
+			}
+			if got, _ := transaction.Metadata()["provider_error_code"].(string); got != tt.expectedMetadata {
+				t.Errorf("Expected provider_error_code metadata %q, got %q", tt.expectedMetadata, got)
+			}
+			if logs.Len() != 0 {
+				t.Errorf("Expected no warning, got %s", logs.String())
+			}
+		})
+	}
 }
 
 // TestCancelTransactionUseCase_AlreadyCancelled tests idempotent cancel
@@ -648,7 +863,7 @@ func TestCancelTransactionUseCase_AlreadyFailed(t *testing.T) {
 	amountMoney, _ := valueobjects.NewMoney("100.00", currency)
 	transaction, _ := entities.NewTransaction(walletID, uuid.New().String(), entities.TransactionTypeDeposit, amountMoney, "Test")
 	_ = transaction.StartProcessing()
-	_ = transaction.MarkFailed("Some error")
+	_ = transaction.MarkFailed("Some error", entities.FailureCategoryProvider)
 
 	transactionRepo := &mockTransactionRepo{
 		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
@@ -730,7 +945,7 @@ func TestCancelTransactionUseCase_PendingDeposit(t *testing.T) {
 
 	var savedTransaction *entities.Transaction
 
-	walletRepo := &mockWalletRepo{}
+	walletRepo := ownerWalletRepo(uuid.New())
 
 	transactionRepo := &mockTransactionRepo{
 		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
@@ -787,7 +1002,7 @@ func TestCancelTransactionUseCase_PendingWithdraw(t *testing.T) {
 
 	var savedTransaction *entities.Transaction
 
-	walletRepo := &mockWalletRepo{}
+	walletRepo := ownerWalletRepo(uuid.New())
 
 	transactionRepo := &mockTransactionRepo{
 		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
@@ -842,7 +1057,7 @@ func TestCancelTransactionUseCase_TransferNotImplemented(t *testing.T) {
 	transaction, _ := entities.NewTransaction(walletID, uuid.New().String(), entities.TransactionTypeTransfer, amountMoney, "Transfer")
 	// PENDING transfer - cancel should succeed since no rollback needed for PENDING
 
-	walletRepo := &mockWalletRepo{}
+	walletRepo := ownerWalletRepo(uuid.New())
 
 	transactionRepo := &mockTransactionRepo{
 		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
@@ -898,7 +1113,7 @@ func TestProcessTransactionUseCase_InvalidStatus(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.ProcessTransactionCommand{
 		TransactionID: transactionID.String(),
@@ -942,11 +1157,11 @@ func TestProcessTransactionUseCase_AlreadyProcessing(t *testing.T) {
 		},
 	}
 
-	walletRepo := &mockWalletRepo{}
+	walletRepo := ownerWalletRepo(uuid.New())
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewProcessTransactionUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.ProcessTransactionCommand{
 		TransactionID: transactionID.String(),
@@ -973,3 +1188,12 @@ func TestProcessTransactionUseCase_AlreadyProcessing(t *testing.T) {
 		t.Errorf("Expected COMPLETED status, got %s", savedTransaction.Status())
 	}
 }
+
+// ownerWalletRepo - кошельки с владельцем userID (для user_id в событиях).
+func ownerWalletRepo(userID uuid.UUID) *mockWalletRepo {
+	return &mockWalletRepo{
+		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+			return createTestWallet(id, userID, valueobjects.USD), nil
+		},
+	}
+}
@@ -4,12 +4,14 @@ package transaction
 import (
 	"context"
 	"fmt"
+	"log/slog"
 
 	"github.com/Haleralex/wallethub/internal/application/dtos"
 	"github.com/Haleralex/wallethub/internal/application/ports"
 	"github.com/Haleralex/wallethub/internal/domain/entities"
 	"github.com/Haleralex/wallethub/internal/domain/errors"
 	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/pkg/logger"
 	"github.com/google/uuid"
 )
 
@@ -33,6 +35,7 @@ type ProcessTransactionUseCase struct {
 	transactionRepo ports.TransactionRepository
 	eventPublisher  ports.EventPublisher
 	uow             ports.UnitOfWork
+	logger          *slog.Logger // Предупреждения о провалах без причины
 	// В реальной системе здесь будет PaymentGatewayClient
 }
 
@@ -42,12 +45,17 @@ func NewProcessTransactionUseCase(
 	transactionRepo ports.TransactionRepository,
 	eventPublisher ports.EventPublisher,
 	uow ports.UnitOfWork,
+	logger *slog.Logger, // nil = без логов
 ) *ProcessTransactionUseCase {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
 	return &ProcessTransactionUseCase{
 		walletRepo:      walletRepo,
 		transactionRepo: transactionRepo,
 		eventPublisher:  eventPublisher,
 		uow:             uow,
+		logger:          logger,
 	}
 }
 
@@ -106,12 +114,22 @@ func (uc *ProcessTransactionUseCase) Execute(ctx context.Context, cmd dtos.Proce
 
 		if !success {
 			// Обработка провалилась
-			failureReason := cmd.FailureReason
-			if failureReason == "" {
-				failureReason = "external service error"
+			failureReason, err := uc.resolveFailureReason(txCtx, transaction, cmd)
+			if err != nil {
+				return err
+			}
+
+			// Категория из callback; без неё - по коду провайдера или причины
+			failureCategory := entities.FailureCategory(cmd.FailureCategory)
+			if failureCategory == "" {
+				code := cmd.ProviderErrorCode
+				if code == "" {
+					code = failureReason
+				}
+				failureCategory = entities.FailureCategoryForReason(code, entities.FailureCategoryProvider)
 			}
 
-			if err := transaction.MarkFailed(failureReason); err != nil {
+			if err := transaction.MarkFailed(failureReason, failureCategory); err != nil {
 				return fmt.Errorf("failed to mark transaction as failed: %w", err)
 			}
 
@@ -155,7 +173,11 @@ func (uc *ProcessTransactionUseCase) Execute(ctx context.Context, cmd dtos.Proce
 			return fmt.Errorf("failed to save transaction: %w", err)
 		}
 
-		// 7. Публикуем события
+		// 7. Публикуем события (с владельцем кошелька на момент транзакции)
+		ownerID, err := walletOwnerID(txCtx, uc.walletRepo, transaction.WalletID())
+		if err != nil {
+			return err
+		}
 		var eventList []events.DomainEvent
 
 		if transaction.IsCompleted() {
@@ -163,19 +185,22 @@ func (uc *ProcessTransactionUseCase) Execute(ctx context.Context, cmd dtos.Proce
 				events.NewTransactionCompleted(
 					transaction.ID(),
 					transaction.WalletID(),
+					ownerID,
 					string(transaction.Type()),
 					transaction.Amount(),
-				),
+				).WithReceiptNumber(transaction.ReceiptNumber()),
 			}
 		} else if transaction.IsFailed() {
 			eventList = []events.DomainEvent{
 				events.NewTransactionFailed(
 					transaction.ID(),
 					transaction.WalletID(),
+					ownerID,
 					string(transaction.Type()),
 					transaction.Amount(),
 					transaction.FailureReason(),
-					false, // isRetryable - можно настроить по логике
+					string(transaction.FailureCategory()),
+					transaction.CanRetry(entities.DefaultMaxRetries) && transaction.IsRetryable(),
 				),
 			}
 		}
@@ -196,3 +221,54 @@ func (uc *ProcessTransactionUseCase) Execute(ctx context.Context, cmd dtos.Proce
 
 	return result, nil
 }
+
+// resolveFailureReason возвращает причину провала: текст из команды,
+// иначе код ошибки провайдера. Без обоих - код UNSPECIFIED, а провайдер
+// и источник команды сохраняются в metadata, чтобы найти интегратора,
+// присылающего пустые причины.
+func (uc *ProcessTransactionUseCase) resolveFailureReason(
+	ctx context.Context,
+	transaction *entities.Transaction,
+	cmd dtos.ProcessTransactionCommand,
+) (string, error) {
+	if cmd.FailureReason != "" {
+		if cmd.ProviderErrorCode != "" {
+			if err := transaction.AddMetadata("provider_error_code", cmd.ProviderErrorCode); err != nil {
+				return "", fmt.Errorf("failed to record provider error code: %w", err)
+			}
+		}
+		return cmd.FailureReason, nil
+	}
+	if cmd.ProviderErrorCode != "" {
+		return cmd.ProviderErrorCode, nil
+	}
+
+	source := cmd.Source
+	if source == "" {
+		source = "unknown"
+	}
+	if cmd.Provider != "" {
+		if err := transaction.AddMetadata("failure_provider", cmd.Provider); err != nil {
+			return "", fmt.Errorf("failed to record failure provider: %w", err)
+		}
+	}
+	if err := transaction.AddMetadata("failure_source", source); err != nil {
+		return "", fmt.Errorf("failed to record failure source: %w", err)
+	}
+
+	uc.logger.WarnContext(ctx, "transaction failed without reason or provider error code",
+		logger.TransactionID(transaction.ID()),
+		slog.String("provider", cmd.Provider),
+		slog.String("source", source),
+	)
+	return entities.FailureReasonUnspecified, nil
+}
+
+// walletOwnerID возвращает владельца кошелька для событий транзакции.
+func walletOwnerID(ctx context.Context, walletRepo ports.WalletReader, walletID uuid.UUID) (uuid.UUID, error) {
+	wallet, err := walletRepo.FindByID(ctx, walletID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to load wallet owner: %w", err)
+	}
+	return wallet.UserID(), nil
+}
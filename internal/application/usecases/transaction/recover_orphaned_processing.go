@@ -0,0 +1,127 @@
+// Package transaction - RecoverOrphanedProcessing use case для транзакций,
+// оставленных в PROCESSING упавшим экземпляром.
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+// OrphanRecoveryConfig - пороги восстановления.
+type OrphanRecoveryConfig struct {
+	// Threshold - транзакция в PROCESSING дольше порога - кандидат
+	Threshold time.Duration
+
+	// InstanceTimeout - экземпляр без heartbeat дольше считается упавшим.
+	// Должен заметно превышать интервал heartbeat (workers.persist_interval)
+	InstanceTimeout time.Duration
+
+	// BatchSize - транзакций за один прогон
+	BatchSize int
+}
+
+// RecoverOrphanedProcessingUseCase - восстановление транзакций, оставленных
+// в PROCESSING упавшим экземпляром (задача планировщика processing-recovery).
+//
+// Сценарий:
+// 1. Отметить heartbeat своего экземпляра: забранные транзакции должны
+// принадлежать живому экземпляру
+// 2. Забрать осиротевшие транзакции (ClaimOrphaned): PROCESSING дольше
+// Threshold, владелец без heartbeat дольше InstanceTimeout
+// 3. Провести каждую через ProcessTransaction с провалом SYSTEM /
+// ORPHANED_PROCESSING: откат изменений кошелька, событие TransactionFailed,
+// дальше транзакцию подхватывает обычный retry
+//
+// Бизнес-правила:
+// - Транзакции живых экземпляров не трогаются никогда
+// - Транзакция разрешается ровно один раз, даже если прогон выполняется
+// на нескольких экземплярах одновременно (claim переназначает владельца)
+// - Неудачно проведённая транзакция возвращается в осиротевшие (Release)
+// и подхватывается следующим прогоном
+//
+// Статус у провайдера не запрашивается: PSP коннектора в системе нет,
+// результат внешнего вызова упавшего экземпляра неизвестен.
+type RecoverOrphanedProcessingUseCase struct {
+	recovery  ports.ProcessingRecoveryRepository
+	instances ports.InstanceRepository
+	process   *ProcessTransactionUseCase
+	instance  string
+	cfg       OrphanRecoveryConfig
+	now       func() time.Time
+}
+
+// NewRecoverOrphanedProcessingUseCase создаёт новый use case. instance -
+// идентификатор текущего экземпляра (тот же, что пишет heartbeat).
+func NewRecoverOrphanedProcessingUseCase(
+	recovery ports.ProcessingRecoveryRepository,
+	instances ports.InstanceRepository,
+	process *ProcessTransactionUseCase,
+	instance string,
+	cfg OrphanRecoveryConfig,
+) *RecoverOrphanedProcessingUseCase {
+	return &RecoverOrphanedProcessingUseCase{
+		recovery:  recovery,
+		instances: instances,
+		process:   process,
+		instance:  instance,
+		cfg:       cfg,
+		now:       func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Execute выполняет один прогон и возвращает число разрешённых транзакций.
+func (uc *RecoverOrphanedProcessingUseCase) Execute(ctx context.Context) (int, error) {
+	now := uc.now()
+
+	if err := uc.instances.Heartbeat(ctx, uc.instance, now); err != nil {
+		return 0, fmt.Errorf("failed to record instance heartbeat: %w", err)
+	}
+
+	ids, err := uc.recovery.ClaimOrphaned(ctx, uc.instance,
+		now.Add(-uc.cfg.Threshold), now.Add(-uc.cfg.InstanceTimeout), uc.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		resolved int
+		errs     []error
+	)
+	for i, id := range ids {
+		// Потерянный lease задачи: остальные транзакции отдаются новому владельцу
+		if err := ports.CheckLease(ctx); err != nil {
+			for _, rest := range ids[i:] {
+				errs = append(errs, uc.release(ctx, rest))
+			}
+			errs = append(errs, err)
+			break
+		}
+
+		_, err := uc.process.Execute(ctx, dtos.ProcessTransactionCommand{
+			TransactionID:   id.String(),
+			Success:         false,
+			FailureReason:   entities.FailureReasonOrphanedProcessing,
+			FailureCategory: string(entities.FailureCategorySystem),
+			Source:          "recovery",
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("transaction %s: %w", id, err), uc.release(ctx, id))
+			continue
+		}
+		resolved++
+	}
+
+	return resolved, errors.Join(errs...)
+}
+
+// release возвращает транзакцию в осиротевшие для следующего прогона.
+func (uc *RecoverOrphanedProcessingUseCase) release(ctx context.Context, id uuid.UUID) error {
+	return uc.recovery.Release(context.WithoutCancel(ctx), id, uc.instance)
+}
@@ -0,0 +1,165 @@
+package transaction
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// orphanHarness - in-memory хранилище, общее для "упавшего" экземпляра и sweeper'ов.
+type orphanHarness struct {
+	store     *memory.Store
+	wallets   *memory.WalletRepository
+	events    *memory.EventPublisher
+	instances *memory.InstanceRepository
+}
+
+func newOrphanHarness() *orphanHarness {
+	store := memory.NewStore()
+	return &orphanHarness{
+		store:     store,
+		wallets:   memory.NewWalletRepository(store),
+		events:    memory.NewEventPublisher(store),
+		instances: memory.NewInstanceRepository(store),
+	}
+}
+
+// sweeper создаёт use case восстановления на экземпляре instance.
+func (h *orphanHarness) sweeper(instance string) *RecoverOrphanedProcessingUseCase {
+	transactions := memory.NewTransactionRepository(h.store).WithInstance(instance)
+	process := NewProcessTransactionUseCase(h.wallets, transactions, h.events, memory.NewUnitOfWork(h.store), nil)
+	return NewRecoverOrphanedProcessingUseCase(transactions, h.instances, process, instance, OrphanRecoveryConfig{
+		Threshold:       time.Minute,
+		InstanceTimeout: time.Minute,
+		BatchSize:       10,
+	})
+}
+
+// orphan записывает кошелёк с зачисленным депозитом и транзакцию, которую
+// экземпляр owner оставил в PROCESSING startedAgo назад.
+func (h *orphanHarness) orphan(t *testing.T, owner string, startedAgo time.Duration) (*entities.Wallet, *entities.Transaction) {
+	t.Helper()
+	ctx := context.Background()
+
+	user, err := entities.NewUser(uuid.NewString()+"@example.com", "Orphan Owner")
+	require.NoError(t, err)
+	require.NoError(t, memory.NewUserRepository(h.store).Save(ctx, user))
+
+	usd := valueobjects.MustNewCurrency("USD")
+	wallet, err := entities.NewWallet(user.ID(), usd)
+	require.NoError(t, err)
+	require.NoError(t, h.wallets.Save(ctx, wallet))
+
+	amount, err := valueobjects.NewMoney("25.00", usd)
+	require.NoError(t, err)
+	tx, err := entities.NewTransaction(wallet.ID(), uuid.NewString(), entities.TransactionTypeDeposit, amount, "orphan")
+	require.NoError(t, err)
+	require.NoError(t, tx.StartProcessing())
+	require.NoError(t, wallet.Credit(amount))
+	require.NoError(t, h.wallets.Save(ctx, wallet))
+
+	// Строка в том виде, в каком её оставил экземпляр, упавший до финального Save
+	processedAt := time.Now().UTC().Add(-startedAgo)
+	stored, err := entities.ReconstructTransaction(
+		tx.ID(), tx.WalletID(), tx.IdempotencyKey(), tx.Type(), tx.Status(), tx.Amount(), tx.FeeAmount(), tx.NetAmount(),
+		nil, "", "", tx.Description(), nil, "", "", 0, nil, "", "",
+		tx.CreatedAt(), processedAt, &processedAt, nil,
+		"",
+	)
+	require.NoError(t, err)
+	require.NoError(t, memory.NewTransactionRepository(h.store).WithInstance(owner).Save(ctx, stored))
+
+	return wallet, stored
+}
+
+func TestRecoverOrphanedProcessing_TwoSweepersResolveOnce(t *testing.T) {
+	h := newOrphanHarness()
+	ctx := context.Background()
+	wallet, orphan := h.orphan(t, "crashed-instance", time.Hour)
+	require.NoError(t, h.instances.Heartbeat(ctx, "crashed-instance", time.Now().UTC().Add(-time.Hour)))
+
+	var (
+		mu       sync.Mutex
+		resolved int
+		wg       sync.WaitGroup
+	)
+	for _, sweeper := range []*RecoverOrphanedProcessingUseCase{h.sweeper("sweeper-a"), h.sweeper("sweeper-b")} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := sweeper.Execute(ctx)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			resolved += n
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, resolved)
+
+	stored, err := memory.NewTransactionRepository(h.store).FindByID(ctx, orphan.ID())
+	require.NoError(t, err)
+	assert.Equal(t, entities.TransactionStatusFailed, stored.Status())
+	assert.Equal(t, entities.FailureReasonOrphanedProcessing, stored.FailureReason())
+	assert.Equal(t, entities.FailureCategorySystem, stored.FailureCategory())
+	assert.True(t, stored.IsRetryable(), "SYSTEM failure is left to the retry machinery")
+
+	// Депозит откачен ровно один раз
+	reloaded, err := h.wallets.FindByID(ctx, wallet.ID())
+	require.NoError(t, err)
+	assert.True(t, reloaded.AvailableBalance().IsZero(), "balance = %s", reloaded.AvailableBalance())
+
+	failed := 0
+	for _, event := range h.events.Events() {
+		if event.EventType() == events.EventTypeTransactionFailed {
+			failed++
+		}
+	}
+	assert.Equal(t, 1, failed)
+
+	// Повторный прогон ничего не находит
+	n, err := h.sweeper("sweeper-a").Execute(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestRecoverOrphanedProcessing_AliveOwnerUntouched(t *testing.T) {
+	h := newOrphanHarness()
+	ctx := context.Background()
+	_, orphan := h.orphan(t, "busy-instance", time.Hour)
+	require.NoError(t, h.instances.Heartbeat(ctx, "busy-instance", time.Now().UTC()))
+
+	n, err := h.sweeper("sweeper-a").Execute(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	stored, err := memory.NewTransactionRepository(h.store).FindByID(ctx, orphan.ID())
+	require.NoError(t, err)
+	assert.Equal(t, entities.TransactionStatusProcessing, stored.Status())
+}
+
+func TestRecoverOrphanedProcessing_RecentUntouched(t *testing.T) {
+	h := newOrphanHarness()
+	ctx := context.Background()
+	_, orphan := h.orphan(t, "crashed-instance", time.Second)
+
+	n, err := h.sweeper("sweeper-a").Execute(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	stored, err := memory.NewTransactionRepository(h.store).FindByID(ctx, orphan.ID())
+	require.NoError(t, err)
+	assert.Equal(t, entities.TransactionStatusProcessing, stored.Status())
+}
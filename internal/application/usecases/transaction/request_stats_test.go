@@ -0,0 +1,63 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/google/uuid"
+)
+
+// TestTransferBetweenWalletsUseCase_RequestStats проверяет стоимость перевода
+// в ports.RequestStats (memory репозитории считают по запросу на вызов, как postgres).
+func TestTransferBetweenWalletsUseCase_RequestStats(t *testing.T) {
+	h := newCrashHarness()
+	source := h.seedWallet(t, "1000.00")
+	dest := h.seedWallet(t, "0.00")
+
+	useCase := NewTransferBetweenWalletsUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
+	cmd := dtos.TransferFundsCommand{
+		SourceWalletID:      source.ID().String(),
+		DestinationWalletID: dest.ID().String(),
+		Amount:              "100.00",
+		IdempotencyKey:      uuid.NewString(),
+	}
+
+	stats := &ports.RequestStats{}
+	ctx := ports.WithRequestStats(context.Background(), stats)
+	if _, err := useCase.Execute(ctx, cmd); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// idempotency lookup + 2 wallet loads + transaction insert + 2 wallet updates + outbox insert
+	got := stats.Snapshot()
+	if got.DBQueries != 7 {
+		t.Errorf("Expected 7 queries, got %d", got.DBQueries)
+	}
+	if got.EventsPublished != 4 {
+		t.Errorf("Expected 4 events published, got %d", got.EventsPublished)
+	}
+	if got.LockRetries != 0 {
+		t.Errorf("Expected no lock retries, got %d", got.LockRetries)
+	}
+
+	// Повтор: idempotency lookup + 2 wallet loads, без публикации
+	replayStats := &ports.RequestStats{}
+	if _, err := useCase.Execute(ports.WithRequestStats(context.Background(), replayStats), cmd); err != nil {
+		t.Fatalf("replay error = %v", err)
+	}
+	replay := replayStats.Snapshot()
+	if replay.DBQueries != 3 || replay.EventsPublished != 0 {
+		t.Errorf("Expected 3 queries and no events on replay, got %d/%d", replay.DBQueries, replay.EventsPublished)
+	}
+
+	// Без RequestStats в context ничего не считается и не падает
+	cmd.IdempotencyKey = uuid.NewString()
+	if _, err := useCase.Execute(context.Background(), cmd); err != nil {
+		t.Fatalf("Execute() without stats error = %v", err)
+	}
+	if after := stats.Snapshot(); after != got {
+		t.Errorf("Expected stats unchanged by a request without stats, got %+v", after)
+	}
+}
@@ -4,16 +4,16 @@ package transaction
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/Haleralex/wallethub/internal/application/dtos"
 	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
 	"github.com/Haleralex/wallethub/internal/domain/errors"
 	"github.com/Haleralex/wallethub/internal/domain/events"
 	"github.com/google/uuid"
 )
 
-const defaultMaxRetries = 3
-
 // RetryTransactionUseCase - use case для повтора failed транзакции.
 type RetryTransactionUseCase struct {
 	walletRepo      ports.WalletRepository
@@ -55,8 +55,18 @@ func (uc *RetryTransactionUseCase) Execute(ctx context.Context, cmd dtos.RetryTr
 			return fmt.Errorf("failed to load transaction: %w", err)
 		}
 
+		// Повтор до next_retry_at разрешён только администратору
+		if !cmd.IgnoreSchedule && transaction.IsFailed() && !transaction.IsRetryDue(time.Now()) {
+			return errors.NewBusinessRuleViolation(
+				"RETRY_NOT_DUE",
+				"transaction cannot be retried yet",
+				map[string]interface{}{"nextRetryAt": transaction.NextRetryAt().Format(time.RFC3339)},
+			)
+		}
+
 		// Domain entity проверяет бизнес-правила (статус FAILED, retryCount < maxRetries)
-		if err := transaction.Retry(defaultMaxRetries); err != nil {
+		// и откладывает следующий повтор (next_retry_at)
+		if err := transaction.Retry(entities.DefaultMaxRetries); err != nil {
 			return err
 		}
 
@@ -64,9 +74,15 @@ func (uc *RetryTransactionUseCase) Execute(ctx context.Context, cmd dtos.RetryTr
 			return fmt.Errorf("failed to save transaction: %w", err)
 		}
 
+		ownerID, err := walletOwnerID(txCtx, uc.walletRepo, transaction.WalletID())
+		if err != nil {
+			return err
+		}
+
 		event := events.NewTransactionCreated(
 			transaction.ID(),
 			transaction.WalletID(),
+			ownerID,
 			string(transaction.Type()),
 			transaction.Amount(),
 			transaction.IdempotencyKey(),
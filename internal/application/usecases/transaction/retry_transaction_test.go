@@ -0,0 +1,91 @@
+package transaction
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/google/uuid"
+)
+
+// newRetryUseCase создаёт RetryTransactionUseCase над failed транзакцией
+// с одним неудачным повтором и заданным nextRetryAt.
+func newRetryUseCase(t *testing.T, nextRetryAt time.Time) (*RetryTransactionUseCase, *entities.Transaction) {
+	t.Helper()
+
+	amount, _ := valueobjects.NewMoney("100.00", valueobjects.USD)
+	createdAt := time.Now().Add(-time.Hour)
+	transaction, err := entities.ReconstructTransaction(
+		uuid.New(), uuid.New(), uuid.NewString(),
+		entities.TransactionTypeWithdraw, entities.TransactionStatusFailed,
+		amount, valueobjects.Zero(valueobjects.USD), amount,
+		nil, "", "", "", nil, "TIMEOUT", entities.FailureCategoryProvider, 1, &nextRetryAt, "", "",
+		createdAt, createdAt, &createdAt, &createdAt,
+		"",
+	)
+	if err != nil {
+		t.Fatalf("ReconstructTransaction() error = %v", err)
+	}
+
+	transactionRepo := &mockTransactionRepo{
+		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
+			if id == transaction.ID() {
+				return transaction, nil
+			}
+			return nil, domainErrors.ErrEntityNotFound
+		},
+	}
+
+	return NewRetryTransactionUseCase(ownerWalletRepo(uuid.New()), transactionRepo, &mockEventPublisher{}, &mockUnitOfWork{}), transaction
+}
+
+// TestRetryTransactionUseCase_NotDue тестирует отказ до наступления next_retry_at
+func TestRetryTransactionUseCase_NotDue(t *testing.T) {
+	useCase, transaction := newRetryUseCase(t, time.Now().Add(5*time.Minute))
+
+	_, err := useCase.Execute(context.Background(), dtos.RetryTransactionCommand{TransactionID: transaction.ID().String()})
+
+	var violation *domainErrors.BusinessRuleViolation
+	if !stderrors.As(err, &violation) || violation.Rule != "RETRY_NOT_DUE" {
+		t.Fatalf("Expected RETRY_NOT_DUE, got: %v", err)
+	}
+	if transaction.Status() != entities.TransactionStatusFailed {
+		t.Errorf("Status = %v, want FAILED", transaction.Status())
+	}
+}
+
+// TestRetryTransactionUseCase_Due тестирует повтор после next_retry_at
+func TestRetryTransactionUseCase_Due(t *testing.T) {
+	useCase, transaction := newRetryUseCase(t, time.Now().Add(-time.Second))
+
+	result, err := useCase.Execute(context.Background(), dtos.RetryTransactionCommand{TransactionID: transaction.ID().String()})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if result.Status != string(entities.TransactionStatusPending) || result.RetryCount != 2 {
+		t.Errorf("Result = %s/%d, want PENDING/2", result.Status, result.RetryCount)
+	}
+	if next := transaction.NextRetryAt(); next == nil || time.Until(*next) < 4*time.Minute {
+		t.Errorf("NextRetryAt = %v, want ~5m after the second retry", next)
+	}
+}
+
+// TestRetryTransactionUseCase_IgnoreSchedule тестирует ручной повтор администратором
+func TestRetryTransactionUseCase_IgnoreSchedule(t *testing.T) {
+	useCase, transaction := newRetryUseCase(t, time.Now().Add(time.Hour))
+
+	cmd := dtos.RetryTransactionCommand{TransactionID: transaction.ID().String(), IgnoreSchedule: true}
+	if _, err := useCase.Execute(context.Background(), cmd); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if transaction.Status() != entities.TransactionStatusPending {
+		t.Errorf("Status = %v, want PENDING", transaction.Status())
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
 	"github.com/Haleralex/wallethub/internal/domain/events"
 )
 
@@ -44,8 +45,9 @@ func (m *EnhancedMockEventPublisher) Publish(ctx context.Context, event events.D
 	return nil
 }
 
+// PublishBatch публикует события по порядку без повторов EventID (контракт ports.EventPublisher).
 func (m *EnhancedMockEventPublisher) PublishBatch(ctx context.Context, evts []events.DomainEvent) error {
-	for _, event := range evts {
+	for _, event := range ports.DedupeEvents(evts) {
 		if err := m.Publish(ctx, event); err != nil {
 			return err
 		}
@@ -170,6 +172,7 @@ func ExecuteWithRetry(
 		}
 
 		// Ждём перед следующей попыткой (exponential backoff)
+		ports.RequestStatsFromContext(ctx).RecordLockRetry()
 		time.Sleep(backoff)
 		backoff = time.Duration(float64(backoff) * config.BackoffMultiple)
 		if backoff > config.MaxBackoff {
@@ -4,32 +4,45 @@ package transaction
 import (
 	"context"
 	"fmt"
+	"log/slog"
 
 	"github.com/Haleralex/wallethub/internal/application/dtos"
 	"github.com/Haleralex/wallethub/internal/application/ports"
 	"github.com/Haleralex/wallethub/internal/domain/entities"
 	"github.com/Haleralex/wallethub/internal/domain/errors"
 	"github.com/Haleralex/wallethub/internal/domain/events"
-	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/logger"
 	"github.com/google/uuid"
 )
 
+// transferLogSampleEvery - в debug лог попадает каждая N-я запись о
+// переводе: переводы - самый частый путь денег.
+const transferLogSampleEvery = 100
+
 // TransferBetweenWalletsUseCase - use case для перевода между кошельками.
 //
 // Сценарий:
 // 1. Проверить idempotency_key
 // 2. Загрузить оба кошелька
-// 3. Проверить валюты (должны совпадать)
-// 4. Создать транзакцию TRANSFER
-// 5. Debit с source wallet
-// 6. Credit на destination wallet
+// 3. Проверить получателя: существует, не закрыт, не ограничен, та же валюта
+// 4. Создать транзакцию TRANSFER и применить комиссию (если есть калькулятор)
+// 5. Debit net + fee с source wallet, комиссия фиксируется FEE транзакцией
+// 6. Credit net на destination wallet
 // 7. Сохранить всё атомарно
 // 8. Опубликовать события
 //
 // Бизнес-правила:
-// - Валюты должны совпадать
-// - Достаточно средств на source wallet
+// - Нельзя переводить на тот же кошелёк (SELF_TRANSFER_NOT_ALLOWED)
+// - Получатель существует (DESTINATION_WALLET_NOT_FOUND)
+// - Получатель не закрыт (DESTINATION_WALLET_CLOSED) и не заблокирован (DESTINATION_WALLET_RESTRICTED)
+// - Валюты должны совпадать (CURRENCY_MISMATCH)
+// - Достаточно средств на source wallet (с учётом комиссии)
+// - SENDER_PAYS: получатель получает gross, списывается gross + fee
+// - DEDUCTED: получатель получает gross - fee, списывается gross
 // - Оба кошелька должны быть активны
+// - Первый перевод новому получателю - по политике payeeGuard
+// - Владелец source wallet принял актуальную версию ToS
+// - Владелец source wallet прошёл KYC (strict; в lenient режиме - пометка)
 // - Атомарность: либо оба изменения, либо ничего
 type TransferBetweenWalletsUseCase struct {
 	walletRepo      ports.WalletRepository
@@ -37,6 +50,17 @@ type TransferBetweenWalletsUseCase struct {
 	eventPublisher  ports.EventPublisher
 	uow             ports.UnitOfWork
 	fraudDetector   ports.FraudDetector
+	feeCalculator   ports.FeeCalculator            // nil - без комиссий
+	walletLimiter   ports.WalletLimiter            // nil - без ограничения параллельности
+	screener        ports.TransactionScreener      // nil - без правил скрининга
+	payeeGuard      ports.NewPayeeGuard            // nil - без проверки новых получателей
+	buildInfo       ports.BuildInfo                // версия сборки для created_by_version
+	terms           ports.TermsGate                // nil - без проверки принятия ToS
+	kyc             ports.KYCGate                  // nil - без проверки KYC владельца
+	operations      ports.OperationGate            // nil - без аварийных выключателей
+	settings        ports.WalletSettingsRepository // nil - без настроек представления кошельков
+	logger          *slog.Logger                   // debug записи о переводах (log.components.transfer)
+	sampler         *logger.Sampler
 }
 
 // NewTransferBetweenWalletsUseCase создаёт новый use case.
@@ -46,21 +70,58 @@ func NewTransferBetweenWalletsUseCase(
 	eventPublisher ports.EventPublisher,
 	uow ports.UnitOfWork,
 	fraudDetector ports.FraudDetector,
+	feeCalculator ports.FeeCalculator,
+	walletLimiter ports.WalletLimiter,
+	screener ports.TransactionScreener,
+	payeeGuard ports.NewPayeeGuard,
+	buildInfo ports.BuildInfo,
+	terms ports.TermsGate,
+	kyc ports.KYCGate,
+	operations ports.OperationGate,
+	settings ports.WalletSettingsRepository,
+	log *slog.Logger, // nil = без логов
 ) *TransferBetweenWalletsUseCase {
+	if log == nil {
+		log = slog.New(slog.DiscardHandler)
+	}
 	return &TransferBetweenWalletsUseCase{
 		walletRepo:      walletRepo,
 		transactionRepo: transactionRepo,
 		eventPublisher:  eventPublisher,
 		uow:             uow,
 		fraudDetector:   fraudDetector,
+		feeCalculator:   feeCalculator,
+		walletLimiter:   walletLimiter,
+		screener:        screener,
+		payeeGuard:      payeeGuard,
+		buildInfo:       buildInfo,
+		terms:           terms,
+		kyc:             kyc,
+		operations:      operations,
+		settings:        settings,
+		logger:          log,
+		sampler:         logger.NewSampler(transferLogSampleEvery),
 	}
 }
 
 // Execute выполняет перевод между кошельками.
 func (uc *TransferBetweenWalletsUseCase) Execute(ctx context.Context, cmd dtos.TransferFundsCommand) (*dtos.TransferResultDTO, error) {
+	if err := ports.RequireOperationEnabled(ctx, uc.operations, entities.TransactionTypeTransfer); err != nil {
+		return nil, err
+	}
+
+	// Слоты обоих кошельков захватываются в порядке UUID - встречные
+	// переводы не взаимоблокируются
+	release, err := ports.AcquireWallets(ctx, uc.walletLimiter, cmd.SourceWalletID, cmd.DestinationWalletID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	var result *dtos.TransferResultDTO
+	var completed []slog.Attr // поля записи о проведённом переводе; nil - повтор
 
-	err := uc.uow.Execute(ctx, func(txCtx context.Context) error {
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
 		// 1. Проверка idempotency
 		if cmd.IdempotencyKey != "" {
 			existingTx, err := uc.transactionRepo.FindByIdempotencyKey(txCtx, cmd.IdempotencyKey)
@@ -68,46 +129,32 @@ func (uc *TransferBetweenWalletsUseCase) Execute(ctx context.Context, cmd dtos.T
 				return fmt.Errorf("failed to check idempotency: %w", err)
 			}
 			if existingTx != nil {
-				sourceWallet, err := uc.walletRepo.FindByID(txCtx, existingTx.WalletID())
-				if err != nil {
-					return fmt.Errorf("failed to load source wallet: %w", err)
-				}
-				destID := existingTx.DestinationWalletID()
-				if destID == nil {
-					return fmt.Errorf("existing transfer transaction has no destination wallet")
-				}
-				destWallet, err := uc.walletRepo.FindByID(txCtx, *destID)
-				if err != nil {
-					return fmt.Errorf("failed to load destination wallet: %w", err)
-				}
-				result = uc.buildTransferResult(sourceWallet, destWallet, existingTx)
-				return nil
+				result, err = uc.replay(txCtx, existingTx)
+				return err
 			}
 		}
 
-		// 2. Парсим IDs
+		// 2. Синхронные проверки команды: все ошибки сразу, до обращения к БД
+		var invalid errors.ValidationErrors
 		sourceWalletID, err := uuid.Parse(cmd.SourceWalletID)
 		if err != nil {
-			return errors.ValidationError{
-				Field:   "source_wallet_id",
-				Message: "invalid source wallet ID format",
-			}
+			invalid.AddCode("source_wallet_id", errors.ValidationCodeInvalidFormat, "invalid source wallet ID format")
 		}
-
 		destinationWalletID, err := uuid.Parse(cmd.DestinationWalletID)
 		if err != nil {
-			return errors.ValidationError{
-				Field:   "destination_wallet_id",
-				Message: "invalid destination wallet ID format",
-			}
+			invalid.AddCode("destination_wallet_id", errors.ValidationCodeInvalidFormat, "invalid destination wallet ID format")
+		}
+		dtos.CheckCommandAmount(&invalid, cmd.Amount, cmd.AmountMinor)
+		if err := invalid.Err(); err != nil {
+			return err
 		}
 
-		// Проверка: нельзя переводить самому себе
+		// Проверка: нельзя переводить самому себе (раздувает объёмы переводов)
 		if sourceWalletID == destinationWalletID {
 			return errors.NewBusinessRuleViolation(
-				"SelfTransfer",
+				errors.SelfTransferNotAllowedRule,
 				"cannot transfer to the same wallet",
-				nil,
+				map[string]interface{}{"walletID": sourceWalletID.String()},
 			)
 		}
 
@@ -123,31 +170,31 @@ func (uc *TransferBetweenWalletsUseCase) Execute(ctx context.Context, cmd dtos.T
 		destinationWallet, err := uc.walletRepo.FindByID(txCtx, destinationWalletID)
 		if err != nil {
 			if errors.IsNotFound(err) {
-				return fmt.Errorf("%w: destination wallet %s", errors.ErrEntityNotFound, cmd.DestinationWalletID)
+				return errors.NewBusinessRuleViolation(
+					errors.DestinationWalletNotFoundRule,
+					"destination wallet not found",
+					map[string]interface{}{"destinationWalletID": cmd.DestinationWalletID},
+				)
 			}
 			return fmt.Errorf("failed to load destination wallet: %w", err)
 		}
 
-		// 4. Проверка валют
-		if sourceWallet.Currency().Code() != destinationWallet.Currency().Code() {
-			return errors.NewBusinessRuleViolation(
-				"CurrencyMismatch",
-				fmt.Sprintf(
-					"currency mismatch: source=%s, destination=%s",
-					sourceWallet.Currency().Code(),
-					destinationWallet.Currency().Code(),
-				),
-				nil,
-			)
+		// 4. Получатель проверяется до любых списаний и событий
+		if err := ValidateTransferDestination(sourceWallet, destinationWallet); err != nil {
+			return err
+		}
+
+		// Отправитель должен принять актуальную версию ToS
+		if uc.terms != nil {
+			if err := uc.terms.RequireAccepted(txCtx, sourceWallet.UserID()); err != nil {
+				return err
+			}
 		}
 
 		// 5. Парсим сумму
-		amount, err := valueobjects.NewMoney(cmd.Amount, sourceWallet.Currency())
+		amount, err := dtos.ParseCommandAmount(cmd.Amount, cmd.AmountMinor, sourceWallet.Currency())
 		if err != nil {
-			return errors.ValidationError{
-				Field:   "amount",
-				Message: fmt.Sprintf("invalid amount: %v", err),
-			}
+			return err
 		}
 
 		// 6. Fraud check
@@ -156,7 +203,7 @@ func (uc *TransferBetweenWalletsUseCase) Execute(ctx context.Context, cmd dtos.T
 				UserID:              sourceWallet.UserID().String(),
 				SourceWalletID:      sourceWalletID.String(),
 				DestinationWalletID: destinationWalletID.String(),
-				Amount:              cmd.Amount,
+				Amount:              amount.Amount().FloatString(amount.Currency().Decimals()),
 				Currency:            sourceWallet.Currency().Code(),
 				TransactionType:     "TRANSFER",
 			})
@@ -184,18 +231,82 @@ func (uc *TransferBetweenWalletsUseCase) Execute(ctx context.Context, cmd dtos.T
 			return fmt.Errorf("failed to create transaction: %w", err)
 		}
 
+		// Тег хранения копируется с исходного кошелька и дальше не меняется
+		if err := transaction.AssignJurisdiction(sourceWallet.Jurisdiction()); err != nil {
+			return fmt.Errorf("failed to assign jurisdiction: %w", err)
+		}
+
+		if err := transaction.StampCreatedByVersion(uc.buildInfo.ProducerVersion()); err != nil {
+			return fmt.Errorf("failed to stamp build version: %w", err)
+		}
+
 		// Устанавливаем destination wallet
 		if err := transaction.SetDestinationWallet(destinationWalletID); err != nil {
 			return fmt.Errorf("failed to set destination wallet: %w", err)
 		}
 
-		// 7. Списываем с source wallet
-		if err := sourceWallet.Debit(amount); err != nil {
+		if cmd.ExternalReference != "" {
+			if err := transaction.SetExternalReference(cmd.ExternalReference); err != nil {
+				return fmt.Errorf("failed to set external reference: %w", err)
+			}
+		}
+
+		// Запись перевода принадлежит отправителю: на неё попадают только его
+		// автотеги. Описание и теги получателя - в его представлении перевода
+		incomingView, err := uc.tagTransfer(txCtx, transaction, sourceWalletID, destinationWalletID)
+		if err != nil {
+			return err
+		}
+
+		// KYC проверяется только у отправителя: входящие средства не блокируются
+		kycEvents, err := ports.CheckOwnerKYC(txCtx, uc.kyc, transaction, sourceWallet)
+		if err != nil {
+			return err
+		}
+
+		// Правила скрининга применяются к кошельку-источнику
+		screeningEvents, err := ports.ScreenTransaction(txCtx, uc.screener, transaction, sourceWallet)
+		if err != nil {
+			return err
+		}
+
+		if uc.payeeGuard != nil {
+			if err := uc.payeeGuard.Check(txCtx, &ports.NewPayeeRequest{
+				Transaction: transaction,
+				Confirmed:   cmd.ConfirmNewPayee,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// Комиссия фиксируется до движения средств: дальше работаем с net
+		if err := applyFeeQuote(txCtx, uc.feeCalculator, transaction, &ports.FeeQuoteRequest{
+			UserID:              sourceWallet.UserID().String(),
+			WalletID:            sourceWalletID.String(),
+			DestinationWalletID: destinationWalletID.String(),
+			TransactionType:     entities.TransactionTypeTransfer,
+			Amount:              amount,
+		}); err != nil {
+			return err
+		}
+		netAmount := transaction.NetAmount()
+
+		// 7. Списываем net + fee с source wallet, комиссия фиксируется FEE транзакцией
+		debitAmount, err := payerDebit(transaction)
+		if err != nil {
+			return err
+		}
+		if err := sourceWallet.Debit(debitAmount); err != nil {
 			return fmt.Errorf("failed to debit source wallet: %w", err)
 		}
 
-		// 8. Зачисляем на destination wallet
-		if err := destinationWallet.Credit(amount); err != nil {
+		feeTx, err := chargeFee(transaction)
+		if err != nil {
+			return err
+		}
+
+		// 8. Зачисляем net на destination wallet
+		if err := destinationWallet.Credit(netAmount); err != nil {
 			return fmt.Errorf("failed to credit destination wallet: %w", err)
 		}
 
@@ -213,6 +324,25 @@ func (uc *TransferBetweenWalletsUseCase) Execute(ctx context.Context, cmd dtos.T
 			return fmt.Errorf("failed to save transaction: %w", err)
 		}
 
+		if feeTx != nil {
+			if err := uc.transactionRepo.Save(txCtx, feeTx); err != nil {
+				return fmt.Errorf("failed to save fee transaction: %w", err)
+			}
+		}
+
+		if incomingView != nil {
+			if err := uc.settings.SaveIncomingView(txCtx, incomingView); err != nil {
+				return fmt.Errorf("failed to save incoming transfer view: %w", err)
+			}
+		}
+
+		// Получатель становится знакомым вместе с коммитом перевода
+		if uc.payeeGuard != nil {
+			if err := uc.payeeGuard.RecordTransfer(txCtx, transaction); err != nil {
+				return err
+			}
+		}
+
 		if err := uc.walletRepo.Save(txCtx, sourceWallet); err != nil {
 			return fmt.Errorf("failed to save source wallet: %w", err)
 		}
@@ -226,50 +356,176 @@ func (uc *TransferBetweenWalletsUseCase) Execute(ctx context.Context, cmd dtos.T
 			events.NewTransactionCreated(
 				transaction.ID(),
 				sourceWalletID,
+				sourceWallet.UserID(),
 				string(entities.TransactionTypeTransfer),
 				amount,
 				cmd.IdempotencyKey,
-			),
+			).WithAmountBreakdown(transaction.FeeAmount(), netAmount),
 			events.NewWalletDebited(
 				sourceWalletID,
-				amount,
+				netAmount,
 				transaction.ID(),
 				sourceWallet.AvailableBalance(),
+				sourceWallet.BalanceVersion(),
 			),
 			events.NewWalletCredited(
 				destinationWalletID,
-				amount,
+				netAmount,
 				transaction.ID(),
 				destinationWallet.AvailableBalance(),
+				destinationWallet.BalanceVersion(),
 			),
 			events.NewTransactionCompleted(
 				transaction.ID(),
 				sourceWalletID,
+				sourceWallet.UserID(),
 				string(entities.TransactionTypeTransfer),
 				amount,
-			),
+			).WithAmountBreakdown(transaction.FeeAmount(), netAmount).
+				WithCounterparty(destinationWalletID, destinationWallet.UserID()).
+				WithReceiptNumber(transaction.ReceiptNumber()),
 		}
+		eventList = append(eventList, feeEvents(feeTx, sourceWallet)...)
+		eventList = append(eventList, kycEvents...)
+		eventList = append(eventList, screeningEvents...)
 
 		if err := uc.eventPublisher.PublishBatch(txCtx, eventList); err != nil {
 			return fmt.Errorf("failed to publish events: %w", err)
 		}
 
-		result = uc.buildTransferResult(sourceWallet, destinationWallet, transaction)
+		result = uc.buildTransferResult(sourceWallet, destinationWallet, transaction, feeTx)
+		completed = []slog.Attr{
+			logger.TransactionID(transaction.ID()),
+			logger.WalletID(sourceWalletID),
+			logger.DestinationWalletID(destinationWalletID),
+			logger.Amount(amount),
+		}
 		return nil
 	})
 
+	if err != nil {
+		// Гонка по idempotency_key - отвечаем как на повтор
+		if errors.IsDuplicateTransaction(err) && cmd.IdempotencyKey != "" {
+			existingTx, findErr := uc.transactionRepo.FindByIdempotencyKey(ctx, cmd.IdempotencyKey)
+			if findErr != nil {
+				return nil, err
+			}
+			return uc.replay(ctx, existingTx)
+		}
+		uc.sampler.Log(ctx, uc.logger, slog.LevelDebug, "transfer failed",
+			slog.String(logger.FieldWalletID, cmd.SourceWalletID),
+			slog.String(logger.FieldDestinationWalletID, cmd.DestinationWalletID),
+			logger.Err(err),
+		)
+		return nil, err
+	}
+
+	if completed != nil {
+		// Запись - после коммита: откаченный перевод не должен выглядеть проведённым
+		uc.sampler.Log(ctx, uc.logger, slog.LevelDebug, "transfer completed", completed...)
+	}
+	return result, nil
+}
+
+// tagTransfer добавляет автотеги отправителя в запись перевода и отрисовывает
+// представление перевода для получателя (nil, если настройки получателя
+// ничего не меняют). Каноническая транзакция сохраняет описание отправителя.
+func (uc *TransferBetweenWalletsUseCase) tagTransfer(ctx context.Context, transaction *entities.Transaction, sourceWalletID, destinationWalletID uuid.UUID) (*entities.IncomingTransferView, error) {
+	sourceSettings, err := ports.LoadWalletSettings(ctx, uc.settings, sourceWalletID)
+	if err != nil {
+		return nil, err
+	}
+	if err := sourceSettings.TagTransaction(transaction); err != nil {
+		return nil, fmt.Errorf("failed to tag transaction: %w", err)
+	}
+
+	destinationSettings, err := ports.LoadWalletSettings(ctx, uc.settings, destinationWalletID)
 	if err != nil {
 		return nil, err
 	}
+	return entities.NewIncomingTransferView(transaction, destinationSettings)
+}
+
+// ValidateTransferDestination проверяет кошелёк-получатель перевода.
+// Каждое нарушение - отдельное правило (422), кошельки не изменяются.
+// Используется и sweep-переводом при закрытии кошелька.
+//
+// Блокирующее ограничение входящих переводов - статус LOCKED
+// (блокировка по требованию безопасности или комплаенса).
+func ValidateTransferDestination(source, destination *entities.Wallet) error {
+	switch destination.Status() {
+	case entities.WalletStatusClosed:
+		return errors.NewBusinessRuleViolation(
+			errors.DestinationWalletClosedRule,
+			"destination wallet is closed",
+			map[string]interface{}{"destinationWalletID": destination.ID().String()},
+		)
+	case entities.WalletStatusLocked:
+		return errors.NewBusinessRuleViolation(
+			errors.DestinationWalletRestrictedRule,
+			"destination wallet does not accept incoming transfers",
+			map[string]interface{}{
+				"destinationWalletID": destination.ID().String(),
+				"status":              string(destination.Status()),
+			},
+		)
+	}
+
+	if source.Currency().Code() != destination.Currency().Code() {
+		return errors.NewBusinessRuleViolation(
+			errors.CurrencyMismatchRule,
+			fmt.Sprintf(
+				"currency mismatch: source=%s, destination=%s",
+				source.Currency().Code(),
+				destination.Currency().Code(),
+			),
+			map[string]interface{}{
+				"sourceCurrency":      source.Currency().Code(),
+				"destinationCurrency": destination.Currency().Code(),
+			},
+		)
+	}
+	return nil
+}
 
+// replay строит результат для уже обработанного idempotency_key.
+func (uc *TransferBetweenWalletsUseCase) replay(ctx context.Context, existingTx *entities.Transaction) (*dtos.TransferResultDTO, error) {
+	sourceWallet, err := uc.walletRepo.FindByID(ctx, existingTx.WalletID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load source wallet: %w", err)
+	}
+	destID := existingTx.DestinationWalletID()
+	if destID == nil {
+		return nil, fmt.Errorf("existing transfer transaction has no destination wallet")
+	}
+	destWallet, err := uc.walletRepo.FindByID(ctx, *destID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load destination wallet: %w", err)
+	}
+
+	// FEE транзакция ищется по производному ключу; комиссии, проведённые
+	// до производных ключей, - по старому суффиксу ":fee"
+	var feeTx *entities.Transaction
+	if existingTx.HasFee() {
+		feeTx, err = uc.transactionRepo.FindByIdempotencyKey(ctx, entities.FeeIdempotencyKey(existingTx.IdempotencyKey()))
+		if errors.IsNotFound(err) {
+			feeTx, err = uc.transactionRepo.FindByIdempotencyKey(ctx, existingTx.IdempotencyKey()+entities.LegacyFeeIdempotencySuffix)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fee transaction: %w", err)
+		}
+	}
+
+	result := uc.buildTransferResult(sourceWallet, destWallet, existingTx, feeTx)
+	result.IdempotentReplay = true
 	return result, nil
 }
 
-func (uc *TransferBetweenWalletsUseCase) buildTransferResult(source, dest *entities.Wallet, tx *entities.Transaction) *dtos.TransferResultDTO {
+func (uc *TransferBetweenWalletsUseCase) buildTransferResult(source, dest *entities.Wallet, tx, feeTx *entities.Transaction) *dtos.TransferResultDTO {
 	srcTotal, _ := source.TotalBalance()
 	dstTotal, _ := dest.TotalBalance()
 
-	return &dtos.TransferResultDTO{
+	result := &dtos.TransferResultDTO{
 		SourceWallet: dtos.WalletDTO{
 			ID:               source.ID().String(),
 			UserID:           source.UserID().String(),
@@ -281,6 +537,8 @@ func (uc *TransferBetweenWalletsUseCase) buildTransferResult(source, dest *entit
 			TotalBalance:     srcTotal.String(),
 			DailyLimit:       source.DailyLimit().String(),
 			MonthlyLimit:     source.MonthlyLimit().String(),
+			BalanceVersion:   source.BalanceVersion(),
+			Jurisdiction:     source.Jurisdiction(),
 			CreatedAt:        source.CreatedAt(),
 			UpdatedAt:        source.UpdatedAt(),
 		},
@@ -295,11 +553,23 @@ func (uc *TransferBetweenWalletsUseCase) buildTransferResult(source, dest *entit
 			TotalBalance:     dstTotal.String(),
 			DailyLimit:       dest.DailyLimit().String(),
 			MonthlyLimit:     dest.MonthlyLimit().String(),
+			BalanceVersion:   dest.BalanceVersion(),
+			Jurisdiction:     dest.Jurisdiction(),
 			CreatedAt:        dest.CreatedAt(),
 			UpdatedAt:        dest.UpdatedAt(),
 		},
+		Transaction:   dtos.ToTransactionDTO(tx),
 		TransactionID: tx.ID().String(),
 		Amount:        tx.Amount().String(),
+		GrossAmount:   tx.GrossAmount().String(),
+		FeeAmount:     tx.FeeAmount().String(),
+		NetAmount:     tx.NetAmount().String(),
+		FeeMode:       string(tx.FeeMode()),
 		Status:        string(tx.Status()),
+		CreatedAt:     tx.CreatedAt(),
+	}
+	if feeTx != nil {
+		result.FeeTransactionID = feeTx.ID().String()
 	}
+	return result
 }
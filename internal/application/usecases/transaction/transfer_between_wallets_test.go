@@ -1,14 +1,21 @@
 package transaction
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"log/slog"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
 	"github.com/Haleralex/wallethub/internal/domain/entities"
 	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
 	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/logger"
 	"github.com/google/uuid"
 )
 
@@ -61,7 +68,7 @@ func TestTransferBetweenWalletsUseCase_Success(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
 
 	cmd := dtos.TransferFundsCommand{
 		SourceWalletID:      sourceWalletID.String(),
@@ -87,6 +94,22 @@ func TestTransferBetweenWalletsUseCase_Success(t *testing.T) {
 		t.Fatal("Expected transaction to be saved")
 	}
 
+	// Новый перевод - не replay
+	if result.IdempotentReplay {
+		t.Error("Expected IdempotentReplay = false for a new transfer")
+	}
+	if !result.CreatedAt.Equal(savedTransaction.CreatedAt()) {
+		t.Errorf("Expected CreatedAt = %v, got %v", savedTransaction.CreatedAt(), result.CreatedAt)
+	}
+
+	// Транзакция в ответе - та же TRANSFER в состоянии после коммита
+	if result.Transaction.ID != savedTransaction.ID().String() || result.Transaction.ID != result.TransactionID {
+		t.Errorf("Expected embedded transaction %s, got %s", savedTransaction.ID(), result.Transaction.ID)
+	}
+	if result.Transaction.Type != string(entities.TransactionTypeTransfer) || result.Transaction.Status != string(entities.TransactionStatusCompleted) {
+		t.Errorf("Expected COMPLETED TRANSFER, got %s %s", result.Transaction.Status, result.Transaction.Type)
+	}
+
 	// Проверяем тип транзакции
 	if savedTransaction.Type() != entities.TransactionTypeTransfer {
 		t.Errorf("Expected transaction type = %s, got %s", entities.TransactionTypeTransfer, savedTransaction.Type())
@@ -148,7 +171,7 @@ func TestTransferBetweenWalletsUseCase_CurrencyMismatch(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
 
 	cmd := dtos.TransferFundsCommand{
 		SourceWalletID:      sourceWalletID.String(),
@@ -210,7 +233,7 @@ func TestTransferBetweenWalletsUseCase_InsufficientBalance(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
 
 	cmd := dtos.TransferFundsCommand{
 		SourceWalletID:      sourceWalletID.String(),
@@ -267,7 +290,7 @@ func TestTransferBetweenWalletsUseCase_SameWallet(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
 
 	cmd := dtos.TransferFundsCommand{
 		SourceWalletID:      walletID.String(),
@@ -337,7 +360,7 @@ func TestTransferBetweenWalletsUseCase_Idempotency(t *testing.T) {
 	eventPublisher := &mockEventPublisher{}
 	uow := &mockUnitOfWork{}
 
-	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil)
+	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
 
 	cmd := dtos.TransferFundsCommand{
 		SourceWalletID:      sourceWalletID.String(),
@@ -359,8 +382,293 @@ func TestTransferBetweenWalletsUseCase_Idempotency(t *testing.T) {
 		t.Fatal("Expected result, got nil")
 	}
 
+	if !result.IdempotentReplay {
+		t.Error("Expected IdempotentReplay = true for a replayed key")
+	}
+	if !result.CreatedAt.Equal(existingTx.CreatedAt()) {
+		t.Errorf("Expected original CreatedAt = %v, got %v", existingTx.CreatedAt(), result.CreatedAt)
+	}
+
 	// Идемпотентность: не должны публиковаться новые события
 	if len(eventPublisher.publishedEvents) != 0 {
 		t.Errorf("Expected no new events (idempotent), got %d", len(eventPublisher.publishedEvents))
 	}
 }
+
+// stubScreener возвращает заданное решение и запоминает запрос.
+type stubScreener struct {
+	result *ports.ScreeningResult
+	got    *ports.ScreeningRequest
+}
+
+func (s *stubScreener) Screen(_ context.Context, req *ports.ScreeningRequest) (*ports.ScreeningResult, error) {
+	s.got = req
+	return s.result, nil
+}
+
+// TestTransferBetweenWalletsUseCase_ScreeningBlock - правило BLOCK проверяется
+// по кошельку-источнику и отклоняет перевод до движения средств
+func TestTransferBetweenWalletsUseCase_ScreeningBlock(t *testing.T) {
+	ctx := context.Background()
+	sourceWalletID := uuid.New()
+	destinationWalletID := uuid.New()
+	currency := valueobjects.MustNewCurrency("USD")
+
+	sourceWallet := createTestWallet(sourceWalletID, uuid.New(), currency) // 1000 USD
+	destinationWallet := createTestWallet(destinationWalletID, uuid.New(), currency)
+
+	saved := false
+	walletRepo := &mockWalletRepo{
+		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+			if id == sourceWalletID {
+				return sourceWallet, nil
+			}
+			return destinationWallet, nil
+		},
+		saveFunc: func(ctx context.Context, wallet *entities.Wallet) error {
+			saved = true
+			return nil
+		},
+	}
+	transactionRepo := &mockTransactionRepo{
+		findByIdempotencyKeyFunc: func(ctx context.Context, key string) (*entities.Transaction, error) {
+			return nil, domainErrors.ErrEntityNotFound
+		},
+	}
+	screener := &stubScreener{result: &ports.ScreeningResult{BlockedBy: "sanctioned-country"}}
+
+	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, &mockEventPublisher{}, &mockUnitOfWork{}, nil, nil, nil, screener, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
+
+	_, err := useCase.Execute(ctx, dtos.TransferFundsCommand{
+		SourceWalletID:      sourceWalletID.String(),
+		DestinationWalletID: destinationWalletID.String(),
+		Amount:              "10.00",
+		IdempotencyKey:      uuid.NewString(),
+		Description:         "Test transfer",
+	})
+
+	var violation *domainErrors.BusinessRuleViolation
+	if !errors.As(err, &violation) || violation.Rule != "TRANSACTION_SCREENED" {
+		t.Fatalf("Expected TRANSACTION_SCREENED violation, got: %v", err)
+	}
+	if violation.Context["rule_id"] != "sanctioned-country" {
+		t.Errorf("rule_id = %v, want sanctioned-country", violation.Context["rule_id"])
+	}
+	if screener.got == nil || screener.got.Wallet != sourceWallet {
+		t.Error("Expected screening against the source wallet")
+	}
+	if screener.got != nil && screener.got.Transaction.Type() != entities.TransactionTypeTransfer {
+		t.Errorf("Screened type = %s, want TRANSFER", screener.got.Transaction.Type())
+	}
+	if saved {
+		t.Error("Blocked transfer must not save wallets")
+	}
+	if sourceWallet.AvailableBalance().String() != "1000.00 USD" {
+		t.Errorf("Source balance = %s, want 1000.00 USD", sourceWallet.AvailableBalance())
+	}
+}
+
+// TestTransferBetweenWalletsUseCase_AllValidationErrors проверяет, что ошибки
+// IDs и суммы возвращаются одним ValidationErrors
+func TestTransferBetweenWalletsUseCase_AllValidationErrors(t *testing.T) {
+	transactionRepo := &mockTransactionRepo{
+		findByIdempotencyKeyFunc: func(ctx context.Context, key string) (*entities.Transaction, error) {
+			return nil, domainErrors.ErrEntityNotFound
+		},
+	}
+	useCase := NewTransferBetweenWalletsUseCase(&mockWalletRepo{}, transactionRepo, &mockEventPublisher{}, &mockUnitOfWork{}, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
+
+	_, err := useCase.Execute(context.Background(), dtos.TransferFundsCommand{
+		SourceWalletID:      "bad-source",
+		DestinationWalletID: "bad-destination",
+		Amount:              "abc",
+		IdempotencyKey:      uuid.New().String(),
+	})
+
+	var validationErrs domainErrors.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		t.Fatalf("Expected ValidationErrors, got: %v", err)
+	}
+	fields := make([]string, 0, len(validationErrs))
+	for _, ve := range validationErrs {
+		fields = append(fields, ve.Field)
+	}
+	if len(fields) != 3 || fields[0] != "source_wallet_id" || fields[1] != "destination_wallet_id" || fields[2] != "amount" {
+		t.Errorf("Expected source_wallet_id, destination_wallet_id, amount; got %v", fields)
+	}
+}
+
+// TestTransferBetweenWalletsUseCase_DestinationRejected проверяет, что каждое
+// нарушение правил получателя возвращает своё правило и ничего не списывается.
+func TestTransferBetweenWalletsUseCase_DestinationRejected(t *testing.T) {
+	usd := valueobjects.MustNewCurrency("USD")
+
+	withStatus := func(status entities.WalletStatus, currency valueobjects.Currency) func(id uuid.UUID) *entities.Wallet {
+		return func(id uuid.UUID) *entities.Wallet {
+			balance, _ := valueobjects.NewMoney("50", currency)
+			zero := valueobjects.Zero(currency)
+			limit, _ := valueobjects.NewMoney("10000", currency)
+			return entities.ReconstructWallet(id, uuid.New(), currency, entities.WalletTypeFiat, status,
+				balance, zero, 0, limit, limit, "DE", nil, time.Now(), time.Now())
+		}
+	}
+
+	tests := []struct {
+		name         string
+		destination  func(id uuid.UUID) *entities.Wallet // nil - кошелёк не найден
+		selfTransfer bool
+		wantRule     string
+	}{
+		{name: "not found", wantRule: domainErrors.DestinationWalletNotFoundRule},
+		{name: "closed", destination: withStatus(entities.WalletStatusClosed, usd), wantRule: domainErrors.DestinationWalletClosedRule},
+		{name: "locked", destination: withStatus(entities.WalletStatusLocked, usd), wantRule: domainErrors.DestinationWalletRestrictedRule},
+		{name: "currency mismatch", destination: withStatus(entities.WalletStatusActive, valueobjects.MustNewCurrency("EUR")), wantRule: domainErrors.CurrencyMismatchRule},
+		{name: "self transfer", selfTransfer: true, wantRule: domainErrors.SelfTransferNotAllowedRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			sourceWalletID := uuid.New()
+			destinationWalletID := uuid.New()
+			if tt.selfTransfer {
+				destinationWalletID = sourceWalletID
+			}
+
+			sourceWallet := createTestWallet(sourceWalletID, uuid.New(), usd)
+			var destinationWallet *entities.Wallet
+			if tt.destination != nil {
+				destinationWallet = tt.destination(destinationWalletID)
+			}
+
+			walletSaves, txSaves := 0, 0
+			walletRepo := &mockWalletRepo{
+				findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+					if id == sourceWalletID {
+						return sourceWallet, nil
+					}
+					if id == destinationWalletID && destinationWallet != nil {
+						return destinationWallet, nil
+					}
+					return nil, domainErrors.ErrEntityNotFound
+				},
+				saveFunc: func(ctx context.Context, wallet *entities.Wallet) error {
+					walletSaves++
+					return nil
+				},
+			}
+			transactionRepo := &mockTransactionRepo{
+				findByIdempotencyKeyFunc: func(ctx context.Context, key string) (*entities.Transaction, error) {
+					return nil, domainErrors.ErrEntityNotFound
+				},
+				saveFunc: func(ctx context.Context, tx *entities.Transaction) error {
+					txSaves++
+					return nil
+				},
+			}
+			eventPublisher := &mockEventPublisher{}
+
+			useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, eventPublisher, &mockUnitOfWork{}, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
+
+			result, err := useCase.Execute(ctx, dtos.TransferFundsCommand{
+				SourceWalletID:      sourceWalletID.String(),
+				DestinationWalletID: destinationWalletID.String(),
+				Amount:              "100.00",
+				IdempotencyKey:      uuid.New().String(),
+			})
+
+			var violation *domainErrors.BusinessRuleViolation
+			if !errors.As(err, &violation) {
+				t.Fatalf("Expected BusinessRuleViolation, got: %v", err)
+			}
+			if violation.Rule != tt.wantRule {
+				t.Errorf("Expected rule %s, got %s", tt.wantRule, violation.Rule)
+			}
+			if result != nil {
+				t.Errorf("Expected no result on error, got: %v", result)
+			}
+			if got := sourceWallet.AvailableBalance().String(); got != "1000.00 USD" {
+				t.Errorf("Expected source balance untouched, got %s", got)
+			}
+			if walletSaves != 0 || txSaves != 0 {
+				t.Errorf("Expected nothing saved, got %d wallet and %d transaction saves", walletSaves, txSaves)
+			}
+			if len(eventPublisher.publishedEvents) != 0 {
+				t.Errorf("Expected no events, got %d", len(eventPublisher.publishedEvents))
+			}
+		})
+	}
+}
+
+// TestTransferBetweenWalletsUseCase_SampledLog тестирует debug запись о
+// переводе: стабильные поля и выборку каждой transferLogSampleEvery-й.
+func TestTransferBetweenWalletsUseCase_SampledLog(t *testing.T) {
+	ctx := context.Background()
+	sourceWalletID := uuid.New()
+	destinationWalletID := uuid.New()
+	currency := valueobjects.MustNewCurrency("USD")
+
+	sourceWallet := createTestWallet(sourceWalletID, uuid.New(), currency)
+	destinationWallet := createTestWallet(destinationWalletID, uuid.New(), currency)
+
+	walletRepo := &mockWalletRepo{
+		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+			switch id {
+			case sourceWalletID:
+				return sourceWallet, nil
+			case destinationWalletID:
+				return destinationWallet, nil
+			}
+			return nil, domainErrors.ErrEntityNotFound
+		},
+		saveFunc: func(ctx context.Context, w *entities.Wallet) error { return nil },
+	}
+	var lastTransaction *entities.Transaction
+	transactionRepo := &mockTransactionRepo{
+		findByIdempotencyKeyFunc: func(ctx context.Context, key string) (*entities.Transaction, error) {
+			return nil, domainErrors.ErrEntityNotFound
+		},
+		saveFunc: func(ctx context.Context, tx *entities.Transaction) error {
+			lastTransaction = tx
+			return nil
+		},
+	}
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	useCase := NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, &mockEventPublisher{}, &mockUnitOfWork{}, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, log)
+
+	for range transferLogSampleEvery + 1 {
+		if _, err := useCase.Execute(ctx, dtos.TransferFundsCommand{
+			SourceWalletID:      sourceWalletID.String(),
+			DestinationWalletID: destinationWalletID.String(),
+			Amount:              "1.00",
+			IdempotencyKey:      uuid.NewString(),
+		}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 sampled records, got %d: %s", len(lines), buf.String())
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+		t.Fatalf("Failed to decode record: %v", err)
+	}
+	want := map[string]any{
+		"msg":                           "transfer completed",
+		logger.FieldTransactionID:       lastTransaction.ID().String(),
+		logger.FieldWalletID:            sourceWalletID.String(),
+		logger.FieldDestinationWalletID: destinationWalletID.String(),
+		logger.FieldAmount:              "1.00 USD",
+		logger.FieldSuppressed:          float64(transferLogSampleEvery - 1),
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("Expected %s = %v, got %v", key, value, record[key])
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// TestTransferBetweenWallets_WalletSettings тестирует, что настройки
+// получателя меняют только его представление перевода: каноническая запись
+// сохраняет описание отправителя и несёт только теги отправителя.
+func TestTransferBetweenWallets_WalletSettings(t *testing.T) {
+	store := memory.NewStore()
+	h := &crashHarness{
+		wallets:      memory.NewWalletRepository(store),
+		transactions: memory.NewTransactionRepository(store),
+		events:       memory.NewEventPublisher(store),
+		users:        memory.NewUserRepository(store),
+	}
+	h.walletRepo = h.wallets
+	h.transactionRepo = h.transactions
+	h.eventPublisher = h.events
+	h.uow = memory.NewUnitOfWork(store)
+	settings := memory.NewWalletSettingsRepository(store)
+
+	useCase := NewTransferBetweenWalletsUseCase(h.walletRepo, h.transactionRepo, h.eventPublisher, h.uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, settings, nil)
+
+	source := h.seedWallet(t, "100.00")
+	destination := h.seedWallet(t, "0.00")
+	saveSettings := func(walletID uuid.UUID, template string, tags ...string) {
+		t.Helper()
+		s, err := entities.NewWalletSettings(walletID, template, tags, "", uuid.New())
+		if err != nil {
+			t.Fatalf("NewWalletSettings() error = %v", err)
+		}
+		if err := settings.Save(context.Background(), s); err != nil {
+			t.Fatalf("save settings error = %v", err)
+		}
+	}
+	saveSettings(source.ID(), "", "outgoing")
+	saveSettings(destination.ID(), "Invoice {external_reference}", "incoming")
+
+	tests := []struct {
+		name      string
+		reference string
+		wantView  string
+	}{
+		{name: "with external reference", reference: "INV-42", wantView: "Invoice INV-42"},
+		{name: "without external reference", wantView: "Invoice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := transferCommand(source.ID(), destination.ID(), "5", false)
+			cmd.Description = "sender text"
+			cmd.ExternalReference = tt.reference
+
+			result, err := useCase.Execute(context.Background(), cmd)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			txID := uuid.MustParse(result.TransactionID)
+
+			tx, err := h.transactions.FindByID(context.Background(), txID)
+			if err != nil {
+				t.Fatalf("FindByID() error = %v", err)
+			}
+			if tx.Description() != "sender text" {
+				t.Errorf("canonical description = %q, want sender text", tx.Description())
+			}
+			if got := tx.Metadata()[entities.WalletTagsMetadataKey]; got != "outgoing" {
+				t.Errorf("canonical %s = %v, want outgoing", entities.WalletTagsMetadataKey, got)
+			}
+
+			view, err := settings.FindIncomingView(context.Background(), txID)
+			if err != nil {
+				t.Fatalf("FindIncomingView() error = %v", err)
+			}
+			if view.Description() != tt.wantView {
+				t.Errorf("incoming description = %q, want %q", view.Description(), tt.wantView)
+			}
+			if tags := view.Tags(); len(tags) != 1 || tags[0] != "incoming" {
+				t.Errorf("incoming tags = %v, want [incoming]", tags)
+			}
+		})
+	}
+
+	cmd := transferCommand(source.ID(), destination.ID(), "5", false)
+	cmd.Description = "no settings on this destination"
+	other := h.seedWallet(t, "0.00")
+	cmd.DestinationWalletID = other.ID().String()
+	result, err := useCase.Execute(context.Background(), cmd)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if _, err := settings.FindIncomingView(context.Background(), uuid.MustParse(result.TransactionID)); !domainErrors.IsNotFound(err) {
+		t.Errorf("Expected no incoming view without destination settings, got %v", err)
+	}
+}
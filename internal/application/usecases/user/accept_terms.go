@@ -0,0 +1,106 @@
+// Package user - AcceptTerms use case для принятия условий обслуживания.
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/terms"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/google/uuid"
+)
+
+// AcceptTermsUseCase - use case для принятия пользователем версии ToS.
+//
+// Сценарий (всё в одном UnitOfWork):
+// 1. Загрузить пользователя
+// 2. Принять версию (entity проверяет монотонность версий)
+// 3. Сохранить пользователя и опубликовать UserAcceptedTerms
+//
+// Повторное принятие той же версии ничего не меняет и событие не публикует.
+type AcceptTermsUseCase struct {
+	userRepo       ports.UserRepository
+	eventPublisher ports.EventPublisher
+	uow            ports.UnitOfWork
+	policy         terms.Policy
+}
+
+// NewAcceptTermsUseCase создаёт новый use case.
+func NewAcceptTermsUseCase(
+	userRepo ports.UserRepository,
+	eventPublisher ports.EventPublisher,
+	uow ports.UnitOfWork,
+	policy terms.Policy,
+) *AcceptTermsUseCase {
+	return &AcceptTermsUseCase{
+		userRepo:       userRepo,
+		eventPublisher: eventPublisher,
+		uow:            uow,
+		policy:         policy,
+	}
+}
+
+// Execute принимает версию ToS.
+//
+// Errors:
+//   - USER_NOT_FOUND: Пользователь не найден
+//   - ValidationError: Версия не положительна или новее требуемой (такой редакции нет)
+//   - TERMS_VERSION_DOWNGRADE: Версия старше уже принятой
+func (uc *AcceptTermsUseCase) Execute(ctx context.Context, cmd dtos.AcceptTermsCommand) (*dtos.UserDTO, error) {
+	userID, err := uuid.Parse(cmd.UserID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "user_id", Message: "invalid UUID"}
+	}
+	if uc.policy.RequiredVersion > 0 && cmd.Version > uc.policy.RequiredVersion {
+		return nil, errors.ValidationError{
+			Field:   "version",
+			Message: fmt.Sprintf("unknown terms version %d (current version is %d)", cmd.Version, uc.policy.RequiredVersion),
+			Code:    errors.ValidationCodeOutOfRange,
+		}
+	}
+
+	var result *dtos.UserDTO
+
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		// 1. Загружаем пользователя
+		user, err := uc.userRepo.FindByID(txCtx, userID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return errors.NewDomainError("USER_NOT_FOUND", "user not found", err)
+			}
+			return fmt.Errorf("failed to load user: %w", err)
+		}
+
+		// 2. Принимаем версию
+		recorded, err := user.AcceptTerms(cmd.Version)
+		if err != nil {
+			return err
+		}
+
+		// 3. Сохраняем и публикуем
+		if recorded {
+			if err := uc.userRepo.Save(txCtx, user); err != nil {
+				return fmt.Errorf("failed to save user: %w", err)
+			}
+
+			acceptance := user.TermsAcceptance()
+			event := events.NewUserAcceptedTerms(user.ID(), acceptance.Version, *acceptance.AcceptedAt)
+			if err := uc.eventPublisher.Publish(txCtx, event); err != nil {
+				return fmt.Errorf("failed to publish %s event: %w", event.EventType(), err)
+			}
+		}
+
+		dto := dtos.ToUserDTO(user)
+		result = &dto
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
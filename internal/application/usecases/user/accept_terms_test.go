@@ -0,0 +1,103 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/terms"
+	"github.com/Haleralex/wallethub/internal/application/usecases/user"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+func newAcceptTermsFixture(t *testing.T, acceptance entities.TermsAcceptance) (*user.AcceptTermsUseCase, *memory.EventPublisher, uuid.UUID) {
+	t.Helper()
+
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	publisher := memory.NewEventPublisher(store)
+
+	existing := entities.ReconstructUser(uuid.New(), "terms@example.com", "Terms User",
+		entities.KYCStatusVerified, nil, "", "", acceptance, time.Now(), time.Now())
+	if err := users.Save(context.Background(), existing); err != nil {
+		t.Fatalf("Save user error = %v", err)
+	}
+
+	policy := terms.Policy{RequiredVersion: 3, GrandfatheredVersion: 2}
+	uc := user.NewAcceptTermsUseCase(users, publisher, memory.NewUnitOfWork(store), policy)
+	return uc, publisher, existing.ID()
+}
+
+// TestAcceptTermsUseCase_Execute тестирует принятие версии и событие UserAcceptedTerms.
+// Повторное принятие той же версии не публикует событие.
+func TestAcceptTermsUseCase_Execute(t *testing.T) {
+	uc, publisher, userID := newAcceptTermsFixture(t, entities.TermsAcceptance{Grandfathered: true})
+	cmd := dtos.AcceptTermsCommand{UserID: userID.String(), Version: 3}
+
+	result, err := uc.Execute(context.Background(), cmd)
+	if err != nil {
+		t.Fatalf("Execute error = %v", err)
+	}
+	if result.TermsVersion != 3 || result.TermsAcceptedAt == nil || result.TermsGrandfathered {
+		t.Errorf("result terms = {%d, %v, %v}, want version 3 accepted explicitly",
+			result.TermsVersion, result.TermsAcceptedAt, result.TermsGrandfathered)
+	}
+
+	if _, err := uc.Execute(context.Background(), cmd); err != nil {
+		t.Fatalf("repeated Execute error = %v", err)
+	}
+
+	published := publisher.Events()
+	if len(published) != 1 {
+		t.Fatalf("published events = %d, want 1", len(published))
+	}
+	accepted, ok := published[0].(*events.UserAcceptedTerms)
+	if !ok {
+		t.Fatalf("event = %T, want *events.UserAcceptedTerms", published[0])
+	}
+	if accepted.UserID != userID || accepted.Version != 3 {
+		t.Errorf("UserAcceptedTerms = {%s, %d}, want {%s, 3}", accepted.UserID, accepted.Version, userID)
+	}
+}
+
+// TestAcceptTermsUseCase_Errors тестирует отклонение неизвестной и устаревшей версии.
+func TestAcceptTermsUseCase_Errors(t *testing.T) {
+	now := time.Now()
+	uc, publisher, userID := newAcceptTermsFixture(t, entities.TermsAcceptance{Version: 2, AcceptedAt: &now})
+
+	t.Run("UnknownVersion", func(t *testing.T) {
+		_, err := uc.Execute(context.Background(), dtos.AcceptTermsCommand{UserID: userID.String(), Version: 4})
+
+		var validationErr domainErrors.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "version" {
+			t.Errorf("Expected version ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("Downgrade", func(t *testing.T) {
+		_, err := uc.Execute(context.Background(), dtos.AcceptTermsCommand{UserID: userID.String(), Version: 1})
+
+		var violation *domainErrors.BusinessRuleViolation
+		if !errors.As(err, &violation) || violation.Rule != "TERMS_VERSION_DOWNGRADE" {
+			t.Errorf("Expected TERMS_VERSION_DOWNGRADE, got %v", err)
+		}
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		_, err := uc.Execute(context.Background(), dtos.AcceptTermsCommand{UserID: uuid.NewString(), Version: 3})
+		if !domainErrors.IsNotFound(err) {
+			t.Errorf("Expected not found error, got %v", err)
+		}
+	})
+
+	if len(publisher.Events()) != 0 {
+		t.Errorf("published events = %d, want 0", len(publisher.Events()))
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/Haleralex/wallethub/internal/application/compliance"
 	"github.com/Haleralex/wallethub/internal/application/dtos"
 	"github.com/Haleralex/wallethub/internal/application/ports"
 	"github.com/Haleralex/wallethub/internal/domain/entities"
@@ -26,7 +27,7 @@ import (
 //
 // Сценарий:
 // 1. Проверить уникальность email
-// 2. Создать domain entity User
+// 2. Создать domain entity User и назначить юрисдикцию
 // 3. Сохранить в БД
 // 4. Опубликовать событие UserCreated
 // 5. Вернуть DTO
@@ -36,6 +37,7 @@ type CreateUserUseCase struct {
 	userRepo       ports.UserRepository
 	eventPublisher ports.EventPublisher
 	uow            ports.UnitOfWork
+	compliance     *compliance.Policy // nil = юрисдикция не назначается
 }
 
 // NewCreateUserUseCase создаёт новый use case.
@@ -44,11 +46,13 @@ func NewCreateUserUseCase(
 	userRepo ports.UserRepository,
 	eventPublisher ports.EventPublisher,
 	uow ports.UnitOfWork,
+	compliancePolicy *compliance.Policy,
 ) *CreateUserUseCase {
 	return &CreateUserUseCase{
 		userRepo:       userRepo,
 		eventPublisher: eventPublisher,
 		uow:            uow,
+		compliance:     compliancePolicy,
 	}
 }
 
@@ -64,7 +68,7 @@ func NewCreateUserUseCase(
 //
 // Errors:
 //   - ErrEntityAlreadyExists: Email уже используется
-//   - ValidationError: Невалидные данные
+//   - ValidationError: Невалидные данные или неразрешённая юрисдикция
 //   - InfrastructureError: Проблемы с БД/сетью
 func (uc *CreateUserUseCase) Execute(ctx context.Context, cmd dtos.CreateUserCommand) (*dtos.UserCreatedDTO, error) {
 	var result *dtos.UserCreatedDTO
@@ -90,6 +94,17 @@ func (uc *CreateUserUseCase) Execute(ctx context.Context, cmd dtos.CreateUserCom
 			return fmt.Errorf("failed to create user entity: %w", err)
 		}
 
+		// Юрисдикция проверяется по списку разрешённых, пусто = fallback
+		if uc.compliance != nil {
+			jurisdiction, err := uc.compliance.ResolveJurisdiction(cmd.Jurisdiction)
+			if err != nil {
+				return err
+			}
+			if err := user.ChangeJurisdiction(jurisdiction); err != nil {
+				return err
+			}
+		}
+
 		// 3. Сохраняем в repository
 		if err := uc.userRepo.Save(txCtx, user); err != nil {
 			return fmt.Errorf("failed to save user: %w", err)
@@ -106,12 +121,13 @@ func (uc *CreateUserUseCase) Execute(ctx context.Context, cmd dtos.CreateUserCom
 		// 6. Конвертируем domain entity в DTO
 		result = &dtos.UserCreatedDTO{
 			User: dtos.UserDTO{
-				ID:        user.ID().String(),
-				Email:     user.Email(),
-				FullName:  user.FullName(),
-				KYCStatus: string(user.KYCStatus()),
-				CreatedAt: user.CreatedAt(),
-				UpdatedAt: user.UpdatedAt(),
+				ID:           user.ID().String(),
+				Email:        user.Email(),
+				FullName:     user.FullName(),
+				KYCStatus:    string(user.KYCStatus()),
+				Jurisdiction: user.Jurisdiction(),
+				CreatedAt:    user.CreatedAt(),
+				UpdatedAt:    user.UpdatedAt(),
 			},
 			Message: "User created successfully. Please complete KYC verification.",
 		}
@@ -14,7 +14,9 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/Haleralex/wallethub/internal/application/compliance"
 	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
 	"github.com/Haleralex/wallethub/internal/application/usecases/user"
 	"github.com/Haleralex/wallethub/internal/domain/entities"
 	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
@@ -33,6 +35,7 @@ type MockUserRepository struct {
 	FindByEmailFunc   func(ctx context.Context, email string) (*entities.User, error)
 	ExistsByEmailFunc func(ctx context.Context, email string) (bool, error)
 	ListFunc          func(ctx context.Context, offset, limit int) ([]*entities.User, error)
+	ActivityFunc      func(ctx context.Context, userID uuid.UUID) (ports.UserActivity, error)
 }
 
 func (m *MockUserRepository) Save(ctx context.Context, user *entities.User) error {
@@ -74,6 +77,13 @@ func (m *MockUserRepository) List(ctx context.Context, offset, limit int) ([]*en
 	return nil, nil
 }
 
+func (m *MockUserRepository) UserActivitySummary(ctx context.Context, userID uuid.UUID) (ports.UserActivity, error) {
+	if m.ActivityFunc != nil {
+		return m.ActivityFunc(ctx, userID)
+	}
+	return ports.UserActivity{}, nil
+}
+
 // MockEventPublisher - mock для event publisher.
 type MockEventPublisher struct {
 	PublishFunc      func(ctx context.Context, event events.DomainEvent) error
@@ -135,7 +145,7 @@ func TestCreateUserUseCase_Success(t *testing.T) {
 	uow := &MockUnitOfWork{}
 
 	// Создаём use case
-	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow)
+	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateUserCommand{
 		Email:    "test@example.com",
@@ -190,7 +200,7 @@ func TestCreateUserUseCase_EmailAlreadyExists(t *testing.T) {
 	eventPublisher := &MockEventPublisher{}
 	uow := &MockUnitOfWork{}
 
-	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow)
+	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateUserCommand{
 		Email:    "existing@example.com",
@@ -234,7 +244,7 @@ func TestCreateUserUseCase_RepositoryError(t *testing.T) {
 	eventPublisher := &MockEventPublisher{}
 	uow := &MockUnitOfWork{}
 
-	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow)
+	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateUserCommand{
 		Email:    "test@example.com",
@@ -271,7 +281,7 @@ func TestCreateUserUseCase_InvalidEmail(t *testing.T) {
 	eventPublisher := &MockEventPublisher{}
 	uow := &MockUnitOfWork{}
 
-	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow)
+	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow, nil)
 
 	// Invalid email
 	cmd := dtos.CreateUserCommand{
@@ -331,7 +341,7 @@ func TestCreateUserUseCase_TransactionRollback(t *testing.T) {
 		},
 	}
 
-	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow)
+	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateUserCommand{
 		Email:    "test@example.com",
@@ -373,7 +383,7 @@ func BenchmarkCreateUserUseCase_Success(b *testing.B) {
 	eventPublisher := &MockEventPublisher{}
 	uow := &MockUnitOfWork{}
 
-	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow)
+	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateUserCommand{
 		Email:    "test@example.com",
@@ -402,7 +412,7 @@ func TestCreateUserUseCase_SaveError(t *testing.T) {
 	eventPublisher := &MockEventPublisher{}
 	uow := &MockUnitOfWork{}
 
-	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow)
+	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateUserCommand{
 		Email:    "test@example.com",
@@ -436,7 +446,7 @@ func TestCreateUserUseCase_EmptyFullName(t *testing.T) {
 	eventPublisher := &MockEventPublisher{}
 	uow := &MockUnitOfWork{}
 
-	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow)
+	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateUserCommand{
 		Email:    "test@example.com",
@@ -471,7 +481,7 @@ func TestCreateUserUseCase_EmailNormalization(t *testing.T) {
 	eventPublisher := &MockEventPublisher{}
 	uow := &MockUnitOfWork{}
 
-	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow)
+	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateUserCommand{
 		Email:    "Test@EXAMPLE.COM", // Mixed case
@@ -507,7 +517,7 @@ func TestCreateUserUseCase_ResultContainsCorrectMessage(t *testing.T) {
 	eventPublisher := &MockEventPublisher{}
 	uow := &MockUnitOfWork{}
 
-	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow)
+	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateUserCommand{
 		Email:    "test@example.com",
@@ -544,7 +554,7 @@ func TestCreateUserUseCase_UserIDIsGenerated(t *testing.T) {
 	eventPublisher := &MockEventPublisher{}
 	uow := &MockUnitOfWork{}
 
-	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow)
+	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateUserCommand{
 		Email:    "test@example.com",
@@ -582,7 +592,7 @@ func TestCreateUserUseCase_TimestampsAreSet(t *testing.T) {
 	eventPublisher := &MockEventPublisher{}
 	uow := &MockUnitOfWork{}
 
-	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow)
+	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateUserCommand{
 		Email:    "test@example.com",
@@ -618,7 +628,7 @@ func TestCreateUserUseCase_PublishedEventContainsCorrectData(t *testing.T) {
 	eventPublisher := &MockEventPublisher{}
 	uow := &MockUnitOfWork{}
 
-	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow)
+	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateUserCommand{
 		Email:    "test@example.com",
@@ -671,7 +681,7 @@ func TestCreateUserUseCase_ContextCancellation(t *testing.T) {
 	eventPublisher := &MockEventPublisher{}
 	uow := &MockUnitOfWork{}
 
-	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow)
+	useCase := user.NewCreateUserUseCase(userRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateUserCommand{
 		Email:    "test@example.com",
@@ -692,3 +702,63 @@ func TestCreateUserUseCase_ContextCancellation(t *testing.T) {
 		t.Errorf("Expected nil result, got %v", result)
 	}
 }
+
+// TestCreateUserUseCase_Jurisdiction тестирует назначение юрисдикции по политике.
+func TestCreateUserUseCase_Jurisdiction(t *testing.T) {
+	policy, err := compliance.NewPolicy([]string{"DE", "FR"}, "DE", nil, 60)
+	if err != nil {
+		t.Fatalf("NewPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		jurisdiction string
+		want         string
+		wantErr      bool
+	}{
+		{"Explicit", "fr", "FR", false},
+		{"EmptyUsesDefault", "", "DE", false},
+		{"NotAllowed", "US", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *entities.User
+			userRepo := &MockUserRepository{
+				SaveFunc: func(ctx context.Context, u *entities.User) error {
+					saved = u
+					return nil
+				},
+			}
+
+			useCase := user.NewCreateUserUseCase(userRepo, &MockEventPublisher{}, &MockUnitOfWork{}, policy)
+
+			result, err := useCase.Execute(context.Background(), dtos.CreateUserCommand{
+				Email:        "test@example.com",
+				FullName:     "John Doe",
+				Jurisdiction: tt.jurisdiction,
+			})
+
+			if tt.wantErr {
+				var validationErr domainErrors.ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("Expected ValidationError, got %v", err)
+				}
+				if saved != nil {
+					t.Error("User must not be saved with a rejected jurisdiction")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if result.User.Jurisdiction != tt.want {
+				t.Errorf("DTO jurisdiction = %s, want %s", result.User.Jurisdiction, tt.want)
+			}
+			if saved.Jurisdiction() != tt.want {
+				t.Errorf("Saved jurisdiction = %s, want %s", saved.Jurisdiction(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Package user - GetAdminUser use case для страницы пользователя в admin консоли.
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/google/uuid"
+)
+
+// GetAdminUserUseCase - use case для получения пользователя администратором.
+//
+// Со сводкой (include_summary) страница пользователя рендерится одним
+// запросом вместо трёх (пользователь, кошельки, число транзакций).
+type GetAdminUserUseCase struct {
+	userRepo ports.UserRepository
+}
+
+// NewGetAdminUserUseCase создаёт новый use case.
+func NewGetAdminUserUseCase(userRepo ports.UserRepository) *GetAdminUserUseCase {
+	return &GetAdminUserUseCase{
+		userRepo: userRepo,
+	}
+}
+
+// Execute возвращает пользователя и, если запрошено, сводку его активности.
+func (uc *GetAdminUserUseCase) Execute(ctx context.Context, query dtos.GetAdminUserQuery) (*dtos.AdminUserDTO, error) {
+	userID, err := uuid.Parse(query.UserID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "user_id", Message: "invalid UUID"}
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NewDomainError("USER_NOT_FOUND", "user not found", err)
+		}
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	result := &dtos.AdminUserDTO{UserDTO: dtos.ToUserDTO(user)}
+
+	if query.IncludeSummary {
+		activity, err := uc.userRepo.UserActivitySummary(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load user activity summary: %w", err)
+		}
+		result.UserActivitySummaryDTO = &dtos.UserActivitySummaryDTO{
+			WalletCount:       activity.WalletCount,
+			TotalTransactions: activity.TotalTransactions,
+			LastActivityAt:    activity.LastActivityAt,
+		}
+	}
+
+	return result, nil
+}
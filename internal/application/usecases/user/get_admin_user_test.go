@@ -0,0 +1,165 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/user"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// TestGetAdminUserUseCase_Summary проверяет точные значения сводки
+// для пользователя с несколькими кошельками и транзакциями.
+func TestGetAdminUserUseCase_Summary(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	wallets := memory.NewWalletRepository(store)
+	transactions := memory.NewTransactionRepository(store)
+
+	owner := mustSaveUser(t, users, "owner@example.com")
+	other := mustSaveUser(t, users, "other@example.com")
+
+	usd := mustSaveWallet(t, wallets, owner.ID(), "USD")
+	eur := mustSaveWallet(t, wallets, owner.ID(), "EUR")
+	mustSaveWallet(t, wallets, owner.ID(), "GBP") // без транзакций
+	foreign := mustSaveWallet(t, wallets, other.ID(), "USD")
+
+	latest := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mustSaveTransaction(t, transactions, usd, latest.Add(-48*time.Hour))
+	mustSaveTransaction(t, transactions, usd, latest.Add(-24*time.Hour))
+	mustSaveTransaction(t, transactions, eur, latest)
+	mustSaveTransaction(t, transactions, eur, latest.Add(-time.Hour))
+	mustSaveTransaction(t, transactions, foreign, latest.Add(time.Hour)) // чужой кошелёк
+
+	uc := user.NewGetAdminUserUseCase(users)
+
+	result, err := uc.Execute(ctx, dtos.GetAdminUserQuery{UserID: owner.ID().String(), IncludeSummary: true})
+	if err != nil {
+		t.Fatalf("Execute error = %v", err)
+	}
+	if result.ID != owner.ID().String() {
+		t.Errorf("ID = %s, want %s", result.ID, owner.ID())
+	}
+	if result.UserActivitySummaryDTO == nil {
+		t.Fatal("Expected summary with include_summary=true")
+	}
+	if result.WalletCount != 3 {
+		t.Errorf("WalletCount = %d, want 3", result.WalletCount)
+	}
+	if result.TotalTransactions != 4 {
+		t.Errorf("TotalTransactions = %d, want 4", result.TotalTransactions)
+	}
+	if result.LastActivityAt == nil || !result.LastActivityAt.Equal(latest) {
+		t.Errorf("LastActivityAt = %v, want %v", result.LastActivityAt, latest)
+	}
+
+	// Без include_summary сводка не считается
+	plain, err := uc.Execute(ctx, dtos.GetAdminUserQuery{UserID: owner.ID().String()})
+	if err != nil {
+		t.Fatalf("Execute error = %v", err)
+	}
+	if plain.UserActivitySummaryDTO != nil {
+		t.Errorf("Expected no summary without include_summary, got %+v", plain.UserActivitySummaryDTO)
+	}
+}
+
+// TestGetAdminUserUseCase_SummaryWithoutWallets проверяет нули и null
+// last_activity_at для пользователя без кошельков.
+func TestGetAdminUserUseCase_SummaryWithoutWallets(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	newcomer := mustSaveUser(t, users, "newcomer@example.com")
+
+	result, err := user.NewGetAdminUserUseCase(users).
+		Execute(ctx, dtos.GetAdminUserQuery{UserID: newcomer.ID().String(), IncludeSummary: true})
+	if err != nil {
+		t.Fatalf("Execute error = %v", err)
+	}
+	if result.UserActivitySummaryDTO == nil {
+		t.Fatal("Expected summary with include_summary=true")
+	}
+	if result.WalletCount != 0 || result.TotalTransactions != 0 || result.LastActivityAt != nil {
+		t.Errorf("Expected empty summary, got %+v", *result.UserActivitySummaryDTO)
+	}
+}
+
+// TestGetAdminUserUseCase_Errors тестирует ошибки загрузки пользователя и сводки.
+func TestGetAdminUserUseCase_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := user.NewGetAdminUserUseCase(&MockUserRepository{}).
+		Execute(ctx, dtos.GetAdminUserQuery{UserID: uuid.NewString()})
+	var domainErr *domainErrors.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != "USER_NOT_FOUND" {
+		t.Errorf("Expected USER_NOT_FOUND, got %v", err)
+	}
+
+	existing, _ := entities.NewUser("summary@example.com", "Summary User")
+	failing := &MockUserRepository{
+		FindByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.User, error) { return existing, nil },
+		ActivityFunc: func(ctx context.Context, userID uuid.UUID) (ports.UserActivity, error) {
+			return ports.UserActivity{}, errors.New("database unavailable")
+		},
+	}
+	if _, err := user.NewGetAdminUserUseCase(failing).
+		Execute(ctx, dtos.GetAdminUserQuery{UserID: existing.ID().String(), IncludeSummary: true}); err == nil {
+		t.Error("Expected summary error to be returned")
+	}
+}
+
+func mustSaveUser(t *testing.T, users *memory.UserRepository, email string) *entities.User {
+	t.Helper()
+
+	u, err := entities.NewUser(email, "Summary User")
+	if err != nil {
+		t.Fatalf("NewUser error = %v", err)
+	}
+	if err := users.Save(context.Background(), u); err != nil {
+		t.Fatalf("Save user error = %v", err)
+	}
+	return u
+}
+
+func mustSaveWallet(t *testing.T, wallets *memory.WalletRepository, userID uuid.UUID, code string) *entities.Wallet {
+	t.Helper()
+
+	w, err := entities.NewWallet(userID, valueobjects.MustNewCurrency(code))
+	if err != nil {
+		t.Fatalf("NewWallet error = %v", err)
+	}
+	if err := wallets.Save(context.Background(), w); err != nil {
+		t.Fatalf("Save wallet error = %v", err)
+	}
+	return w
+}
+
+func mustSaveTransaction(t *testing.T, transactions *memory.TransactionRepository, wallet *entities.Wallet, createdAt time.Time) {
+	t.Helper()
+
+	amount, _ := valueobjects.NewMoney("1.00", wallet.Currency())
+	tx, err := entities.ReconstructTransaction(
+		uuid.New(), wallet.ID(), uuid.NewString(),
+		entities.TransactionTypeDeposit, entities.TransactionStatusCompleted,
+		amount, valueobjects.Zero(amount.Currency()), amount,
+		nil, "", "", "summary", nil, "", "", 0, nil, "", "",
+		createdAt, createdAt, &createdAt, &createdAt,
+		"",
+	)
+	if err != nil {
+		t.Fatalf("ReconstructTransaction error = %v", err)
+	}
+	if err := transactions.Save(context.Background(), tx); err != nil {
+		t.Fatalf("Save transaction error = %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+// Package user - GetKYCHistory use case для получения истории KYC решений.
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/google/uuid"
+)
+
+// GetKYCHistoryUseCase - use case для получения истории KYC пользователя.
+//
+// Проверка доступа (владелец или admin) выполняется в HTTP handler.
+type GetKYCHistoryUseCase struct {
+	userRepo    ports.UserRepository
+	historyRepo ports.KYCHistoryRepository
+}
+
+// NewGetKYCHistoryUseCase создаёт новый use case.
+func NewGetKYCHistoryUseCase(userRepo ports.UserRepository, historyRepo ports.KYCHistoryRepository) *GetKYCHistoryUseCase {
+	return &GetKYCHistoryUseCase{
+		userRepo:    userRepo,
+		historyRepo: historyRepo,
+	}
+}
+
+// Execute возвращает историю KYC в хронологическом порядке.
+func (uc *GetKYCHistoryUseCase) Execute(ctx context.Context, query dtos.GetKYCHistoryQuery) (*dtos.KYCHistoryDTO, error) {
+	userID, err := uuid.Parse(query.UserID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "user_id", Message: "invalid UUID"}
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NewDomainError("USER_NOT_FOUND", "user not found", err)
+		}
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	transitions, err := uc.historyRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load KYC history: %w", err)
+	}
+
+	result := &dtos.KYCHistoryDTO{
+		UserID:      user.ID().String(),
+		KYCStatus:   string(user.KYCStatus()),
+		Transitions: make([]dtos.KYCTransitionDTO, len(transitions)),
+	}
+	for i, transition := range transitions {
+		result.Transitions[i] = dtos.ToKYCTransitionDTO(transition)
+	}
+
+	return result, nil
+}
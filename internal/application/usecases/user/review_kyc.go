@@ -0,0 +1,124 @@
+// Package user - ReviewKYC use case для одобрения/отклонения KYC.
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/google/uuid"
+)
+
+// ReviewKYCUseCase - use case для решения по KYC верификации.
+//
+// Сценарий (всё в одном UnitOfWork):
+// 1. Загрузить пользователя
+// 2. Одобрить или отклонить KYC (entity проверяет статус и причину)
+// 3. Сохранить пользователя
+// 4. Добавить запись в историю KYC
+// 5. Опубликовать UserKYCApproved / UserKYCRejected
+//
+// Инициатор решения обязателен и берётся из context (ports.ActorFromContext).
+type ReviewKYCUseCase struct {
+	userRepo       ports.UserRepository
+	historyRepo    ports.KYCHistoryRepository
+	eventPublisher ports.EventPublisher
+	uow            ports.UnitOfWork
+}
+
+// NewReviewKYCUseCase создаёт новый use case.
+func NewReviewKYCUseCase(
+	userRepo ports.UserRepository,
+	historyRepo ports.KYCHistoryRepository,
+	eventPublisher ports.EventPublisher,
+	uow ports.UnitOfWork,
+) *ReviewKYCUseCase {
+	return &ReviewKYCUseCase{
+		userRepo:       userRepo,
+		historyRepo:    historyRepo,
+		eventPublisher: eventPublisher,
+		uow:            uow,
+	}
+}
+
+// Execute применяет решение по KYC.
+//
+// Errors:
+//   - ACTOR_REQUIRED: В context нет инициатора
+//   - USER_NOT_FOUND: Пользователь не найден
+//   - KYC_NOT_PENDING: KYC не ожидает решения
+//   - ValidationError: Причина отказа короче entities.MinKYCRejectionReasonLength
+func (uc *ReviewKYCUseCase) Execute(ctx context.Context, cmd dtos.ApproveKYCCommand) (*dtos.UserDTO, error) {
+	actor, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "KYC decision requires an authenticated actor", nil)
+	}
+
+	userID, err := uuid.Parse(cmd.UserID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "user_id", Message: "invalid UUID"}
+	}
+
+	var result *dtos.UserDTO
+
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		// 1. Загружаем пользователя
+		user, err := uc.userRepo.FindByID(txCtx, userID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return errors.NewDomainError("USER_NOT_FOUND", "user not found", err)
+			}
+			return fmt.Errorf("failed to load user: %w", err)
+		}
+
+		// 2. Применяем решение
+		fromStatus := user.KYCStatus()
+		var event events.DomainEvent
+		if cmd.Verified {
+			if err := user.ApproveKYC(); err != nil {
+				return err
+			}
+			event = events.NewUserKYCApproved(user.ID(), actor.ID)
+		} else {
+			if err := user.RejectKYC(cmd.Reason); err != nil {
+				return err
+			}
+			// Причина берётся из entity (уже нормализована) - та же, что в истории
+			event = events.NewUserKYCRejected(user.ID(), user.LastKYCRejectionReason(), actor.ID)
+		}
+
+		// 3. Сохраняем пользователя
+		if err := uc.userRepo.Save(txCtx, user); err != nil {
+			return fmt.Errorf("failed to save user: %w", err)
+		}
+
+		// 4. Пишем историю
+		reason := ""
+		if !cmd.Verified {
+			reason = user.LastKYCRejectionReason()
+		}
+		transition := entities.NewKYCTransition(user.ID(), fromStatus, user.KYCStatus(), reason, actor.ID)
+		if err := uc.historyRepo.Append(txCtx, transition); err != nil {
+			return fmt.Errorf("failed to append KYC history: %w", err)
+		}
+
+		// 5. Публикуем событие
+		if err := uc.eventPublisher.Publish(txCtx, event); err != nil {
+			return fmt.Errorf("failed to publish %s event: %w", event.EventType(), err)
+		}
+
+		dto := dtos.ToUserDTO(user)
+		result = &dto
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,168 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/user"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// kycFixture - пользователь в статусе PENDING и use cases поверх memory store.
+type kycFixture struct {
+	userID     uuid.UUID
+	publisher  *memory.EventPublisher
+	review     *user.ReviewKYCUseCase
+	getHistory *user.GetKYCHistoryUseCase
+}
+
+func newKYCFixture(t *testing.T) *kycFixture {
+	t.Helper()
+
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	history := memory.NewKYCHistoryRepository(store)
+	publisher := memory.NewEventPublisher(store)
+	uow := memory.NewUnitOfWork(store)
+
+	pending := entities.ReconstructUser(uuid.New(), "kyc@example.com", "KYC User",
+		entities.KYCStatusPending, nil, "", "", entities.TermsAcceptance{}, time.Now(), time.Now())
+	if err := users.Save(context.Background(), pending); err != nil {
+		t.Fatalf("Save user error = %v", err)
+	}
+
+	return &kycFixture{
+		userID:     pending.ID(),
+		publisher:  publisher,
+		review:     user.NewReviewKYCUseCase(users, history, publisher, uow),
+		getHistory: user.NewGetKYCHistoryUseCase(users, history),
+	}
+}
+
+// TestReviewKYCUseCase_RejectRejectApprove тестирует историю KYC:
+// два отказа и одобрение дают три записи с корректными переходами.
+func TestReviewKYCUseCase_RejectRejectApprove(t *testing.T) {
+	f := newKYCFixture(t)
+	reviewerID := uuid.New()
+	ctx := ports.WithActor(context.Background(), ports.Actor{ID: reviewerID, Role: "admin"})
+
+	decisions := []dtos.ApproveKYCCommand{
+		{UserID: f.userID.String(), Verified: false, Reason: "Blurry passport photo"},
+		{UserID: f.userID.String(), Verified: false, Reason: "Blurry passport photo again"},
+		{UserID: f.userID.String(), Verified: true},
+	}
+
+	var result *dtos.UserDTO
+	for i, cmd := range decisions {
+		var err error
+		result, err = f.review.Execute(ctx, cmd)
+		if err != nil {
+			t.Fatalf("decision %d: Execute error = %v", i+1, err)
+		}
+	}
+
+	// DTO: финальный статус + последняя причина отказа
+	if result.KYCStatus != string(entities.KYCStatusVerified) {
+		t.Errorf("KYCStatus = %s, want VERIFIED", result.KYCStatus)
+	}
+	if result.LastKYCRejectionReason != "Blurry passport photo again" {
+		t.Errorf("LastKYCRejectionReason = %q, want last rejection reason", result.LastKYCRejectionReason)
+	}
+
+	history, err := f.getHistory.Execute(context.Background(), dtos.GetKYCHistoryQuery{UserID: f.userID.String()})
+	if err != nil {
+		t.Fatalf("GetKYCHistory error = %v", err)
+	}
+
+	want := []struct {
+		from, to, reason string
+	}{
+		{"PENDING", "REJECTED", "Blurry passport photo"},
+		{"REJECTED", "REJECTED", "Blurry passport photo again"},
+		{"REJECTED", "VERIFIED", ""},
+	}
+	if len(history.Transitions) != len(want) {
+		t.Fatalf("history length = %d, want %d", len(history.Transitions), len(want))
+	}
+	for i, w := range want {
+		got := history.Transitions[i]
+		if got.FromStatus != w.from || got.ToStatus != w.to || got.Reason != w.reason {
+			t.Errorf("transition %d = %s->%s (%q), want %s->%s (%q)",
+				i+1, got.FromStatus, got.ToStatus, got.Reason, w.from, w.to, w.reason)
+		}
+		if got.ActorID != reviewerID.String() {
+			t.Errorf("transition %d actor = %s, want %s", i+1, got.ActorID, reviewerID)
+		}
+	}
+	if history.KYCStatus != "VERIFIED" {
+		t.Errorf("history KYCStatus = %s, want VERIFIED", history.KYCStatus)
+	}
+
+	// События несут причину и reviewer'а
+	published := f.publisher.Events()
+	if len(published) != 3 {
+		t.Fatalf("published events = %d, want 3", len(published))
+	}
+	rejected, ok := published[1].(*events.UserKYCRejected)
+	if !ok {
+		t.Fatalf("event 2 = %T, want *events.UserKYCRejected", published[1])
+	}
+	if rejected.Reason != "Blurry passport photo again" || rejected.ActorID != reviewerID {
+		t.Errorf("UserKYCRejected = {%q, %s}, want reason and reviewer", rejected.Reason, rejected.ActorID)
+	}
+	if _, ok := published[2].(*events.UserKYCApproved); !ok {
+		t.Errorf("event 3 = %T, want *events.UserKYCApproved", published[2])
+	}
+}
+
+// TestReviewKYCUseCase_Errors тестирует ошибки решения по KYC.
+// Ни одна ошибка не должна оставлять запись в истории.
+func TestReviewKYCUseCase_Errors(t *testing.T) {
+	f := newKYCFixture(t)
+	actorCtx := ports.WithActor(context.Background(), ports.Actor{ID: uuid.New(), Role: "admin"})
+
+	t.Run("ActorRequired", func(t *testing.T) {
+		_, err := f.review.Execute(context.Background(), dtos.ApproveKYCCommand{UserID: f.userID.String(), Verified: true})
+
+		var domainErr *domainErrors.DomainError
+		if !errors.As(err, &domainErr) || domainErr.Code != "ACTOR_REQUIRED" {
+			t.Errorf("Expected ACTOR_REQUIRED, got %v", err)
+		}
+	})
+
+	t.Run("ShortReason", func(t *testing.T) {
+		_, err := f.review.Execute(actorCtx, dtos.ApproveKYCCommand{UserID: f.userID.String(), Reason: "blurry"})
+
+		var validationErr domainErrors.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "reason" {
+			t.Errorf("Expected reason ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		_, err := f.review.Execute(actorCtx, dtos.ApproveKYCCommand{UserID: uuid.NewString(), Verified: true})
+		if !domainErrors.IsNotFound(err) {
+			t.Errorf("Expected not found error, got %v", err)
+		}
+	})
+
+	history, err := f.getHistory.Execute(context.Background(), dtos.GetKYCHistoryQuery{UserID: f.userID.String()})
+	if err != nil {
+		t.Fatalf("GetKYCHistory error = %v", err)
+	}
+	if len(history.Transitions) != 0 {
+		t.Errorf("history length = %d, want 0", len(history.Transitions))
+	}
+	if history.KYCStatus != "PENDING" {
+		t.Errorf("KYCStatus = %s, want PENDING", history.KYCStatus)
+	}
+}
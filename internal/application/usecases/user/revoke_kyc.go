@@ -0,0 +1,115 @@
+// Package user - RevokeKYC use case для отзыва KYC верификации.
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/google/uuid"
+)
+
+// RevokeKYCUseCase - use case для отзыва KYC после повторной проверки compliance.
+//
+// Сценарий (всё в одном UnitOfWork):
+// 1. Загрузить пользователя
+// 2. Отозвать KYC (VERIFIED -> REJECTED, entity проверяет статус и причину)
+// 3. Сохранить пользователя
+// 4. Добавить запись в историю KYC
+// 5. Опубликовать UserKYCRevoked
+//
+// Кошельки пользователя не закрываются: вывод средств с них ограничивает
+// проверка KYC владельца (kyc.enforcement), кэш статусов которой
+// обновляется по UserKYCRevoked. Повторная верификация - через ReviewKYC.
+//
+// Инициатор обязателен и берётся из context (ports.ActorFromContext).
+type RevokeKYCUseCase struct {
+	userRepo       ports.UserRepository
+	historyRepo    ports.KYCHistoryRepository
+	eventPublisher ports.EventPublisher
+	uow            ports.UnitOfWork
+}
+
+// NewRevokeKYCUseCase создаёт новый use case.
+func NewRevokeKYCUseCase(
+	userRepo ports.UserRepository,
+	historyRepo ports.KYCHistoryRepository,
+	eventPublisher ports.EventPublisher,
+	uow ports.UnitOfWork,
+) *RevokeKYCUseCase {
+	return &RevokeKYCUseCase{
+		userRepo:       userRepo,
+		historyRepo:    historyRepo,
+		eventPublisher: eventPublisher,
+		uow:            uow,
+	}
+}
+
+// Execute отзывает KYC пользователя.
+//
+// Errors:
+//   - ACTOR_REQUIRED: В context нет инициатора
+//   - USER_NOT_FOUND: Пользователь не найден
+//   - KYC_NOT_VERIFIED: KYC пользователя не подтверждён
+//   - ValidationError: Причина короче entities.MinKYCRejectionReasonLength
+func (uc *RevokeKYCUseCase) Execute(ctx context.Context, cmd dtos.RevokeKYCCommand) (*dtos.UserDTO, error) {
+	actor, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "KYC revocation requires an authenticated actor", nil)
+	}
+
+	userID, err := uuid.Parse(cmd.UserID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "user_id", Message: "invalid UUID"}
+	}
+
+	var result *dtos.UserDTO
+
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		// 1. Загружаем пользователя
+		user, err := uc.userRepo.FindByID(txCtx, userID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return errors.NewDomainError("USER_NOT_FOUND", "user not found", err)
+			}
+			return fmt.Errorf("failed to load user: %w", err)
+		}
+
+		// 2. Отзываем верификацию
+		fromStatus := user.KYCStatus()
+		if err := user.RevokeKYC(cmd.Reason); err != nil {
+			return err
+		}
+
+		// 3. Сохраняем пользователя
+		if err := uc.userRepo.Save(txCtx, user); err != nil {
+			return fmt.Errorf("failed to save user: %w", err)
+		}
+
+		// 4. Пишем историю (причина из entity - уже нормализована)
+		reason := user.LastKYCRejectionReason()
+		transition := entities.NewKYCTransition(user.ID(), fromStatus, user.KYCStatus(), reason, actor.ID)
+		if err := uc.historyRepo.Append(txCtx, transition); err != nil {
+			return fmt.Errorf("failed to append KYC history: %w", err)
+		}
+
+		// 5. Публикуем событие
+		if err := uc.eventPublisher.Publish(txCtx, events.NewUserKYCRevoked(user.ID(), reason, actor.ID)); err != nil {
+			return fmt.Errorf("failed to publish %s event: %w", events.EventTypeUserKYCRevoked, err)
+		}
+
+		dto := dtos.ToUserDTO(user)
+		result = &dto
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,108 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/user"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// TestRevokeKYCUseCase тестирует отзыв KYC: переход в истории, событие
+// и повторную верификацию через ReviewKYC.
+func TestRevokeKYCUseCase(t *testing.T) {
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	history := memory.NewKYCHistoryRepository(store)
+	publisher := memory.NewEventPublisher(store)
+	uow := memory.NewUnitOfWork(store)
+
+	verified := entities.ReconstructUser(uuid.New(), "revoke@example.com", "Revoke User",
+		entities.KYCStatusVerified, nil, "", "", entities.TermsAcceptance{}, time.Now(), time.Now())
+	if err := users.Save(context.Background(), verified); err != nil {
+		t.Fatalf("Save user error = %v", err)
+	}
+
+	revoke := user.NewRevokeKYCUseCase(users, history, publisher, uow)
+	adminID := uuid.New()
+	ctx := ports.WithActor(context.Background(), ports.Actor{ID: adminID, Role: "admin"})
+
+	t.Run("ActorRequired", func(t *testing.T) {
+		_, err := revoke.Execute(context.Background(), dtos.RevokeKYCCommand{UserID: verified.ID().String(), Reason: "Sanctions list match"})
+
+		var domainErr *domainErrors.DomainError
+		if !errors.As(err, &domainErr) || domainErr.Code != "ACTOR_REQUIRED" {
+			t.Errorf("Expected ACTOR_REQUIRED, got %v", err)
+		}
+	})
+
+	t.Run("ShortReason", func(t *testing.T) {
+		_, err := revoke.Execute(ctx, dtos.RevokeKYCCommand{UserID: verified.ID().String(), Reason: "sanctions"})
+
+		var validationErr domainErrors.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "reason" {
+			t.Errorf("Expected reason ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		_, err := revoke.Execute(ctx, dtos.RevokeKYCCommand{UserID: uuid.NewString(), Reason: "Sanctions list match"})
+		if !domainErrors.IsNotFound(err) {
+			t.Errorf("Expected not found error, got %v", err)
+		}
+	})
+
+	result, err := revoke.Execute(ctx, dtos.RevokeKYCCommand{UserID: verified.ID().String(), Reason: " Sanctions list match "})
+	if err != nil {
+		t.Fatalf("Execute error = %v", err)
+	}
+	if result.KYCStatus != "REJECTED" || result.LastKYCRejectionReason != "Sanctions list match" {
+		t.Errorf("result = {%s, %q}, want REJECTED with reason", result.KYCStatus, result.LastKYCRejectionReason)
+	}
+
+	// Повторный отзыв невозможен: KYC уже не подтверждён
+	_, err = revoke.Execute(ctx, dtos.RevokeKYCCommand{UserID: verified.ID().String(), Reason: "Sanctions list match"})
+	var violation *domainErrors.BusinessRuleViolation
+	if !errors.As(err, &violation) || violation.Rule != "KYC_NOT_VERIFIED" {
+		t.Errorf("Expected KYC_NOT_VERIFIED, got %v", err)
+	}
+
+	transitions, err := history.ListByUserID(context.Background(), verified.ID())
+	if err != nil {
+		t.Fatalf("ListByUserID error = %v", err)
+	}
+	if len(transitions) != 1 {
+		t.Fatalf("history length = %d, want 1", len(transitions))
+	}
+	if got := transitions[0]; got.FromStatus() != entities.KYCStatusVerified || got.ToStatus() != entities.KYCStatusRejected ||
+		got.Reason() != "Sanctions list match" || got.ActorID() != adminID {
+		t.Errorf("transition = %s->%s (%q) by %s", got.FromStatus(), got.ToStatus(), got.Reason(), got.ActorID())
+	}
+
+	all := publisher.Events()
+	if len(all) != 1 {
+		t.Fatalf("published events = %d, want 1", len(all))
+	}
+	published, ok := all[0].(*events.UserKYCRevoked)
+	if !ok {
+		t.Fatalf("event = %T, want *events.UserKYCRevoked", all[0])
+	}
+	if published.UserID != verified.ID() || published.Reason != "Sanctions list match" || published.ActorID != adminID {
+		t.Errorf("UserKYCRevoked = %+v", published)
+	}
+
+	// Повторная верификация - обычным решением по KYC
+	review := user.NewReviewKYCUseCase(users, history, publisher, uow)
+	if _, err := review.Execute(ctx, dtos.ApproveKYCCommand{UserID: verified.ID().String(), Verified: true}); err != nil {
+		t.Errorf("re-approval error = %v", err)
+	}
+}
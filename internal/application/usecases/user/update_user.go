@@ -0,0 +1,118 @@
+// Package user - UpdateUser use case для изменения профиля пользователя.
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/compliance"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/google/uuid"
+)
+
+// UpdateUserUseCase - use case для обновления профиля пользователя.
+//
+// Сценарий:
+// 1. Загрузить пользователя
+// 2. Применить изменения (только переданные поля)
+// 3. Сохранить в БД
+//
+// Смена юрисдикции влияет только на новые кошельки и транзакции:
+// уже созданные записи хранят тег, полученный при создании.
+type UpdateUserUseCase struct {
+	userRepo   ports.UserRepository
+	uow        ports.UnitOfWork
+	compliance *compliance.Policy // nil = юрисдикция проверяется только по формату
+}
+
+// NewUpdateUserUseCase создаёт новый use case.
+func NewUpdateUserUseCase(
+	userRepo ports.UserRepository,
+	uow ports.UnitOfWork,
+	compliancePolicy *compliance.Policy,
+) *UpdateUserUseCase {
+	return &UpdateUserUseCase{
+		userRepo:   userRepo,
+		uow:        uow,
+		compliance: compliancePolicy,
+	}
+}
+
+// Execute выполняет обновление профиля.
+//
+// Errors:
+//   - USER_NOT_FOUND: Пользователь не найден
+//   - EMAIL_ALREADY_EXISTS: Email занят другим пользователем
+//   - ValidationError: Невалидные данные или неразрешённая юрисдикция
+func (uc *UpdateUserUseCase) Execute(ctx context.Context, cmd dtos.UpdateUserCommand) (*dtos.UserDTO, error) {
+	userID, err := uuid.Parse(cmd.UserID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "user_id", Message: "invalid UUID"}
+	}
+
+	var result *dtos.UserDTO
+
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		// 1. Загружаем пользователя
+		user, err := uc.userRepo.FindByID(txCtx, userID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return errors.NewDomainError("USER_NOT_FOUND", "user not found", err)
+			}
+			return fmt.Errorf("failed to load user: %w", err)
+		}
+
+		// 2. Применяем изменения
+		if cmd.Email != nil && *cmd.Email != user.Email() {
+			exists, err := uc.userRepo.ExistsByEmail(txCtx, *cmd.Email)
+			if err != nil {
+				return fmt.Errorf("failed to check email uniqueness: %w", err)
+			}
+			if exists {
+				return errors.NewBusinessRuleViolation(
+					"EMAIL_ALREADY_EXISTS",
+					fmt.Sprintf("user with email %s already exists", *cmd.Email),
+					map[string]interface{}{"email": *cmd.Email},
+				)
+			}
+			if err := user.UpdateEmail(*cmd.Email); err != nil {
+				return err
+			}
+		}
+
+		if cmd.FullName != nil {
+			if err := user.UpdateFullName(*cmd.FullName); err != nil {
+				return err
+			}
+		}
+
+		if cmd.Jurisdiction != nil {
+			jurisdiction := *cmd.Jurisdiction
+			if uc.compliance != nil {
+				if jurisdiction, err = uc.compliance.ValidateJurisdiction(jurisdiction); err != nil {
+					return err
+				}
+			}
+			if err := user.ChangeJurisdiction(jurisdiction); err != nil {
+				return err
+			}
+		}
+
+		// 3. Сохраняем
+		if err := uc.userRepo.Save(txCtx, user); err != nil {
+			return fmt.Errorf("failed to save user: %w", err)
+		}
+
+		dto := dtos.ToUserDTO(user)
+		result = &dto
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
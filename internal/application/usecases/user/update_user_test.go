@@ -0,0 +1,215 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/compliance"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/user"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// TestUpdateUserUseCase_UpdatesProfile тестирует частичное обновление профиля.
+func TestUpdateUserUseCase_UpdatesProfile(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	uow := memory.NewUnitOfWork(store)
+
+	created, err := user.NewCreateUserUseCase(users, memory.NewEventPublisher(store), uow, nil).
+		Execute(ctx, dtos.CreateUserCommand{Email: "john@example.com", FullName: "John Doe"})
+	if err != nil {
+		t.Fatalf("CreateUser error = %v", err)
+	}
+
+	newName := "John Smith"
+	result, err := user.NewUpdateUserUseCase(users, uow, nil).Execute(ctx, dtos.UpdateUserCommand{
+		UserID:   created.User.ID,
+		FullName: &newName,
+	})
+	if err != nil {
+		t.Fatalf("UpdateUser error = %v", err)
+	}
+
+	if result.FullName != newName {
+		t.Errorf("FullName = %s, want %s", result.FullName, newName)
+	}
+	if result.Email != "john@example.com" {
+		t.Errorf("Email should not change, got %s", result.Email)
+	}
+}
+
+// TestUpdateUserUseCase_Errors тестирует ошибки обновления профиля.
+func TestUpdateUserUseCase_Errors(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	uow := memory.NewUnitOfWork(store)
+	publisher := memory.NewEventPublisher(store)
+
+	policy, err := compliance.NewPolicy([]string{"DE", "FR"}, "DE", nil, 60)
+	if err != nil {
+		t.Fatalf("NewPolicy() error = %v", err)
+	}
+
+	createUser := user.NewCreateUserUseCase(users, publisher, uow, policy)
+	first, err := createUser.Execute(ctx, dtos.CreateUserCommand{Email: "first@example.com", FullName: "First User"})
+	if err != nil {
+		t.Fatalf("CreateUser error = %v", err)
+	}
+	if _, err := createUser.Execute(ctx, dtos.CreateUserCommand{Email: "second@example.com", FullName: "Second User"}); err != nil {
+		t.Fatalf("CreateUser error = %v", err)
+	}
+
+	useCase := user.NewUpdateUserUseCase(users, uow, policy)
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := useCase.Execute(ctx, dtos.UpdateUserCommand{UserID: uuid.NewString()})
+		if !domainErrors.IsNotFound(err) {
+			t.Errorf("Expected not found error, got %v", err)
+		}
+	})
+
+	t.Run("EmailTaken", func(t *testing.T) {
+		email := "second@example.com"
+		_, err := useCase.Execute(ctx, dtos.UpdateUserCommand{UserID: first.User.ID, Email: &email})
+		if !domainErrors.IsBusinessRuleViolation(err) {
+			t.Errorf("Expected business rule violation, got %v", err)
+		}
+	})
+
+	t.Run("JurisdictionNotAllowed", func(t *testing.T) {
+		jurisdiction := "US"
+		_, err := useCase.Execute(ctx, dtos.UpdateUserCommand{UserID: first.User.ID, Jurisdiction: &jurisdiction})
+
+		var validationErr domainErrors.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected ValidationError, got %v", err)
+		}
+
+		stored, _ := users.FindByID(ctx, uuid.MustParse(first.User.ID))
+		if stored.Jurisdiction() != "DE" {
+			t.Errorf("Jurisdiction = %s, want unchanged DE", stored.Jurisdiction())
+		}
+	})
+}
+
+// TestUpdateUserUseCase_MovedUserKeepsRecordTags тестирует переезд пользователя:
+// старые кошельки и транзакции сохраняют тег и срок хранения своей юрисдикции,
+// новые записи получают новую юрисдикцию.
+func TestUpdateUserUseCase_MovedUserKeepsRecordTags(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	wallets := memory.NewWalletRepository(store)
+	transactions := memory.NewTransactionRepository(store)
+	publisher := memory.NewEventPublisher(store)
+	uow := memory.NewUnitOfWork(store)
+
+	policy, err := compliance.NewPolicy([]string{"DE", "FR"}, "DE", map[string]int{"DE": 120, "FR": 72}, 60)
+	if err != nil {
+		t.Fatalf("NewPolicy() error = %v", err)
+	}
+
+	createWallet := wallet.NewCreateWalletUseCase(users, wallets, publisher, uow, nil)
+	creditWallet := wallet.NewCreditWalletUseCase(wallets, transactions, publisher, uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil)
+
+	// 1. Пользователь живёт в Германии
+	created, err := user.NewCreateUserUseCase(users, publisher, uow, policy).Execute(ctx, dtos.CreateUserCommand{
+		Email:        "mover@example.com",
+		FullName:     "Moving User",
+		Jurisdiction: "DE",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser error = %v", err)
+	}
+
+	usdWallet, err := createWallet.Execute(ctx, dtos.CreateWalletCommand{UserID: created.User.ID, CurrencyCode: "USD"})
+	if err != nil {
+		t.Fatalf("CreateWallet error = %v", err)
+	}
+	if usdWallet.Jurisdiction != "DE" {
+		t.Errorf("Wallet jurisdiction = %s, want DE", usdWallet.Jurisdiction)
+	}
+
+	deposit, err := creditWallet.Execute(ctx, dtos.CreditWalletCommand{
+		WalletID:       usdWallet.ID,
+		Amount:         "100.00",
+		IdempotencyKey: uuid.NewString(),
+		Description:    "Salary",
+	})
+	if err != nil {
+		t.Fatalf("CreditWallet error = %v", err)
+	}
+
+	// 2. Переезд во Францию
+	france := "fr"
+	moved, err := user.NewUpdateUserUseCase(users, uow, policy).Execute(ctx, dtos.UpdateUserCommand{
+		UserID:       created.User.ID,
+		Jurisdiction: &france,
+	})
+	if err != nil {
+		t.Fatalf("UpdateUser error = %v", err)
+	}
+	if moved.Jurisdiction != "FR" {
+		t.Errorf("User jurisdiction = %s, want FR", moved.Jurisdiction)
+	}
+
+	// 3. Новые записи - уже французские
+	eurWallet, err := createWallet.Execute(ctx, dtos.CreateWalletCommand{UserID: created.User.ID, CurrencyCode: "EUR"})
+	if err != nil {
+		t.Fatalf("CreateWallet error = %v", err)
+	}
+	if eurWallet.Jurisdiction != "FR" {
+		t.Errorf("New wallet jurisdiction = %s, want FR", eurWallet.Jurisdiction)
+	}
+
+	// 4. Старые записи сохраняют исходный тег
+	oldWallet, err := wallets.FindByID(ctx, uuid.MustParse(usdWallet.ID))
+	if err != nil {
+		t.Fatalf("FindByID error = %v", err)
+	}
+	if oldWallet.Jurisdiction() != "DE" {
+		t.Errorf("Old wallet jurisdiction = %s, want DE", oldWallet.Jurisdiction())
+	}
+
+	oldTx, err := transactions.FindByID(ctx, uuid.MustParse(deposit.TransactionID))
+	if err != nil {
+		t.Fatalf("FindByID error = %v", err)
+	}
+	if oldTx.Jurisdiction() != "DE" {
+		t.Errorf("Old transaction jurisdiction = %s, want DE", oldTx.Jurisdiction())
+	}
+
+	// Пополнение старого кошелька после переезда всё ещё тегируется по кошельку
+	later, err := creditWallet.Execute(ctx, dtos.CreditWalletCommand{
+		WalletID:       usdWallet.ID,
+		Amount:         "5.00",
+		IdempotencyKey: uuid.NewString(),
+		Description:    "Refund",
+	})
+	if err != nil {
+		t.Fatalf("CreditWallet error = %v", err)
+	}
+	if later.Wallet.Jurisdiction != "DE" {
+		t.Errorf("Wallet jurisdiction after move = %s, want DE", later.Wallet.Jurisdiction)
+	}
+
+	// 5. Срок хранения считается по тегу записи, а не по текущей юрисдикции пользователя
+	now := time.Now()
+	sevenYearsAgo := now.AddDate(-7, 0, 0)
+	if policy.IsExpired(oldTx.Jurisdiction(), sevenYearsAgo, now) {
+		t.Error("DE record (120 months) must not expire after 7 years")
+	}
+	if !policy.IsExpired(moved.Jurisdiction, sevenYearsAgo, now) {
+		t.Error("FR record (72 months) must expire after 7 years")
+	}
+}
@@ -0,0 +1,207 @@
+// Package wallet - GetActivityHeatmap use case: активность кошельков по дням
+// для heatmap дашборда.
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// Параметры heatmap.
+const (
+	DefaultHeatmapDays = 365
+	MaxHeatmapDays     = 730
+
+	// HeatmapQueryTimeout - бюджет агрегирующего запроса: дашборд лучше
+	// покажет ошибку, чем будет держать соединение пула
+	HeatmapQueryTimeout = 2 * time.Second
+)
+
+// heatmapDateLayout - формат дат heatmap.
+const heatmapDateLayout = time.DateOnly
+
+// GetActivityHeatmapUseCase возвращает число транзакций и суммы зачислений
+// и списаний по дням за последние Days дней, включая сегодняшний.
+//
+// Дни считаются в зоне запроса (IANA, по умолчанию UTC): граница дня -
+// местная полночь, поэтому дни перехода на летнее время длятся 23 или 25
+// часов. Ответ содержит каждый день диапазона и каждую валюту кошельков -
+// дни без транзакций с нулями, чтобы клиенту не нужно было их достраивать.
+//
+// Агрегат строится запросом к transactions (ports.TransactionActivityRepository);
+// проекции активности пока нет. Доступ к кошельку проверяет handler.
+type GetActivityHeatmapUseCase struct {
+	activityRepo ports.TransactionActivityRepository
+	walletRepo   ports.WalletReader
+}
+
+// NewGetActivityHeatmapUseCase создаёт новый use case.
+func NewGetActivityHeatmapUseCase(activityRepo ports.TransactionActivityRepository, walletRepo ports.WalletReader) *GetActivityHeatmapUseCase {
+	return &GetActivityHeatmapUseCase{
+		activityRepo: activityRepo,
+		walletRepo:   walletRepo,
+	}
+}
+
+// Execute возвращает heatmap кошелька (WalletID) или всех кошельков
+// пользователя (UserID).
+//
+// Errors:
+//   - ValidationError: Не задан кошелёк или пользователь, некорректные days или timezone
+//   - ErrEntityNotFound: Кошелёк не найден
+func (uc *GetActivityHeatmapUseCase) Execute(ctx context.Context, query dtos.GetActivityHeatmapQuery) (*dtos.ActivityHeatmapDTO, error) {
+	days := query.Days
+	if days == 0 {
+		days = DefaultHeatmapDays
+	}
+	if days < 1 || days > MaxHeatmapDays {
+		return nil, errors.ValidationError{Field: "days", Message: fmt.Sprintf("must be between 1 and %d", MaxHeatmapDays)}
+	}
+
+	timezone := query.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "Local" {
+		return nil, errors.ValidationError{Field: "timezone", Message: "unknown IANA time zone"}
+	}
+
+	wallets, err := uc.loadWallets(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	now := clock.Now().In(location)
+	from := time.Date(now.Year(), now.Month(), now.Day()-(days-1), 0, 0, 0, 0, location)
+	to := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, location)
+
+	walletIDs := make([]uuid.UUID, 0, len(wallets))
+	currencies := make(map[string]valueobjects.Currency, len(wallets))
+	for _, w := range wallets {
+		walletIDs = append(walletIDs, w.ID())
+		currencies[w.Currency().Code()] = w.Currency()
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, HeatmapQueryTimeout)
+	defer cancel()
+
+	activity, err := uc.activityRepo.DailyActivity(queryCtx, ports.ActivityFilter{
+		WalletIDs: walletIDs,
+		From:      from,
+		To:        to,
+		Location:  location,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate wallet activity: %w", err)
+	}
+
+	return buildHeatmap(query, timezone, from, days, currencies, activity)
+}
+
+// loadWallets возвращает кошельки, по которым строится heatmap.
+func (uc *GetActivityHeatmapUseCase) loadWallets(ctx context.Context, query dtos.GetActivityHeatmapQuery) ([]*entities.Wallet, error) {
+	switch {
+	case query.WalletID != "" && query.UserID == "":
+		walletID, err := uuid.Parse(query.WalletID)
+		if err != nil {
+			return nil, errors.ValidationError{Field: "wallet_id", Message: "invalid UUID"}
+		}
+		w, err := uc.walletRepo.FindByID(ctx, walletID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, fmt.Errorf("%w: wallet %s", errors.ErrEntityNotFound, query.WalletID)
+			}
+			return nil, fmt.Errorf("failed to load wallet: %w", err)
+		}
+		return []*entities.Wallet{w}, nil
+
+	case query.UserID != "" && query.WalletID == "":
+		userID, err := uuid.Parse(query.UserID)
+		if err != nil {
+			return nil, errors.ValidationError{Field: "user_id", Message: "invalid UUID"}
+		}
+		wallets, err := uc.walletRepo.FindByUserID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load user wallets: %w", err)
+		}
+		return wallets, nil
+
+	default:
+		return nil, errors.ValidationError{Field: "wallet_id", Message: "exactly one of wallet_id and user_id is required"}
+	}
+}
+
+// buildHeatmap раскладывает агрегаты по всем дням диапазона и валютам.
+func buildHeatmap(
+	query dtos.GetActivityHeatmapQuery,
+	timezone string,
+	from time.Time,
+	days int,
+	currencies map[string]valueobjects.Currency,
+	activity []ports.DailyActivity,
+) (*dtos.ActivityHeatmapDTO, error) {
+	type dayCurrency struct {
+		date     string
+		currency string
+	}
+	byDay := make(map[dayCurrency]ports.DailyActivity, len(activity))
+	for _, a := range activity {
+		// Валюта входящего перевода может не совпадать с валютами кошельков
+		if _, ok := currencies[a.CurrencyCode]; !ok {
+			currency, err := valueobjects.NewCurrency(a.CurrencyCode)
+			if err != nil {
+				return nil, fmt.Errorf("invalid activity currency: %w", err)
+			}
+			currencies[a.CurrencyCode] = currency
+		}
+		byDay[dayCurrency{a.Date.Format(heatmapDateLayout), a.CurrencyCode}] = a
+	}
+
+	codes := make([]string, 0, len(currencies))
+	for code := range currencies {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+
+	// Календарные даты перебираются в UTC: в нём нет переходов времени
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	result := &dtos.ActivityHeatmapDTO{
+		WalletID: query.WalletID,
+		UserID:   query.UserID,
+		Timezone: timezone,
+		From:     start.Format(heatmapDateLayout),
+		To:       start.AddDate(0, 0, days-1).Format(heatmapDateLayout),
+		Days:     make([]dtos.ActivityDayDTO, 0, days),
+	}
+	for i := range days {
+		date := start.AddDate(0, 0, i).Format(heatmapDateLayout)
+		day := dtos.ActivityDayDTO{Date: date, Sums: make([]dtos.ActivitySumDTO, 0, len(codes))}
+		for _, code := range codes {
+			a, ok := byDay[dayCurrency{date, code}]
+			if !ok {
+				zero := valueobjects.Zero(currencies[code])
+				a = ports.DailyActivity{Credit: zero, Debit: zero}
+			}
+			day.Count += a.Count
+			day.Sums = append(day.Sums, dtos.ActivitySumDTO{
+				CurrencyCode: code,
+				CreditSum:    a.Credit.String(),
+				DebitSum:     a.Debit.String(),
+			})
+		}
+		result.Days = append(result.Days, day)
+	}
+	return result, nil
+}
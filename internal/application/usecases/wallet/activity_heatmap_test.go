@@ -0,0 +1,206 @@
+package wallet_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// heatmapFixture - кошельки USD и EUR одного пользователя в памяти.
+type heatmapFixture struct {
+	transactions *memory.TransactionRepository
+	useCase      *wallet.GetActivityHeatmapUseCase
+	user         *entities.User
+	usd, eur     *entities.Wallet
+}
+
+func newHeatmapFixture(t *testing.T) *heatmapFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	wallets := memory.NewWalletRepository(store)
+	transactions := memory.NewTransactionRepository(store)
+
+	user, err := entities.NewUser("heatmap-"+uuid.NewString()+"@example.com", "Heatmap User")
+	require.NoError(t, err)
+	require.NoError(t, users.Save(ctx, user))
+
+	f := &heatmapFixture{
+		transactions: transactions,
+		useCase:      wallet.NewGetActivityHeatmapUseCase(transactions, wallets),
+		user:         user,
+	}
+	for _, w := range []**entities.Wallet{&f.usd, &f.eur} {
+		currency := valueobjects.USD
+		if w == &f.eur {
+			currency = valueobjects.EUR
+		}
+		*w, err = entities.NewWallet(user.ID(), currency)
+		require.NoError(t, err)
+		require.NoError(t, wallets.Save(ctx, *w))
+	}
+	return f
+}
+
+// complete сохраняет COMPLETED транзакцию с заданным created_at.
+func (f *heatmapFixture) complete(t *testing.T, w *entities.Wallet, txType entities.TransactionType, amount string, createdAt time.Time) {
+	t.Helper()
+
+	m, err := valueobjects.NewMoney(amount, w.Currency())
+	require.NoError(t, err)
+	tx, err := entities.ReconstructTransaction(
+		uuid.New(), w.ID(), uuid.NewString(),
+		txType, entities.TransactionStatusCompleted,
+		m, valueobjects.Zero(w.Currency()), m,
+		nil, "", "", "heatmap", nil, "", "", 0, nil, "", "",
+		createdAt, createdAt, &createdAt, &createdAt,
+		"",
+	)
+	require.NoError(t, err)
+	require.NoError(t, f.transactions.Save(context.Background(), tx))
+}
+
+func TestGetActivityHeatmapUseCase_SeededYear(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// 31 декабря 2024, 20:00 по Нью-Йорку - уже 1 января по UTC
+	defer clock.SetClock(clock.NewFake(time.Date(2024, time.December, 31, 20, 0, 0, 0, newYork)))()
+
+	f := newHeatmapFixture(t)
+
+	// Год данных: депозит каждый седьмой день в местный полдень
+	first := time.Date(2024, time.January, 2, 12, 0, 0, 0, newYork)
+	seeded := make(map[string]bool)
+	for day := first; day.Year() == 2024; day = day.AddDate(0, 0, 7) {
+		f.complete(t, f.usd, entities.TransactionTypeDeposit, "1.00", day)
+		seeded[day.Format(time.DateOnly)] = true
+	}
+	// До начала диапазона (1 января - 366-й день назад)
+	f.complete(t, f.usd, entities.TransactionTypeDeposit, "1000.00", time.Date(2024, time.January, 1, 23, 59, 0, 0, newYork))
+	// Вокруг переходов на летнее время: 9 марта 23:30 EST и 3 ноября 23:30 EST
+	f.complete(t, f.usd, entities.TransactionTypeWithdraw, "2.50", time.Date(2024, time.March, 10, 4, 30, 0, 0, time.UTC))
+	f.complete(t, f.eur, entities.TransactionTypePayout, "3.00", time.Date(2024, time.November, 4, 4, 30, 0, 0, time.UTC))
+
+	heatmap, err := f.useCase.Execute(context.Background(), dtos.GetActivityHeatmapQuery{
+		UserID:   f.user.ID().String(),
+		Timezone: "America/New_York",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "America/New_York", heatmap.Timezone)
+	assert.Equal(t, "2024-01-02", heatmap.From)
+	assert.Equal(t, "2024-12-31", heatmap.To)
+	require.Len(t, heatmap.Days, wallet.DefaultHeatmapDays)
+
+	byDate := make(map[string]dtos.ActivityDayDTO, len(heatmap.Days))
+	expected := time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)
+	for _, day := range heatmap.Days {
+		// Каждый день диапазона по порядку, в каждом - обе валюты
+		require.Equal(t, expected.Format(time.DateOnly), day.Date)
+		require.Len(t, day.Sums, 2)
+		assert.Equal(t, "EUR", day.Sums[0].CurrencyCode)
+		assert.Equal(t, "USD", day.Sums[1].CurrencyCode)
+		byDate[day.Date] = day
+		expected = expected.AddDate(0, 0, 1)
+	}
+
+	for _, day := range heatmap.Days {
+		switch {
+		case day.Date == "2024-03-09":
+			assert.Equal(t, 1, day.Count)
+			assert.Equal(t, "2.50 USD", day.Sums[1].DebitSum)
+		case day.Date == "2024-11-03":
+			assert.Equal(t, 1, day.Count)
+			assert.Equal(t, "3.00 EUR", day.Sums[0].DebitSum)
+		case seeded[day.Date]:
+			assert.Equal(t, 1, day.Count, day.Date)
+			assert.Equal(t, "1.00 USD", day.Sums[1].CreditSum, day.Date)
+		default:
+			assert.Equal(t, 0, day.Count, day.Date)
+			assert.Equal(t, []dtos.ActivitySumDTO{
+				{CurrencyCode: "EUR", CreditSum: "0.00 EUR", DebitSum: "0.00 EUR"},
+				{CurrencyCode: "USD", CreditSum: "0.00 USD", DebitSum: "0.00 USD"},
+			}, day.Sums, day.Date)
+		}
+	}
+
+	// Переходы не сдвигают соседние дни
+	for _, date := range []string{"2024-03-10", "2024-03-11", "2024-11-02", "2024-11-04"} {
+		if !seeded[date] {
+			assert.Zero(t, byDate[date].Count, date)
+		}
+	}
+}
+
+func TestGetActivityHeatmapUseCase_WalletInUTC(t *testing.T) {
+	defer clock.SetClock(clock.NewFake(time.Date(2024, time.June, 10, 1, 0, 0, 0, time.UTC)))()
+
+	f := newHeatmapFixture(t)
+	f.complete(t, f.usd, entities.TransactionTypeDeposit, "10.00", time.Date(2024, time.June, 9, 23, 0, 0, 0, time.UTC))
+	f.complete(t, f.usd, entities.TransactionTypeFee, "0.25", time.Date(2024, time.June, 10, 0, 30, 0, 0, time.UTC))
+	f.complete(t, f.eur, entities.TransactionTypeDeposit, "99.00", time.Date(2024, time.June, 10, 0, 30, 0, 0, time.UTC))
+
+	heatmap, err := f.useCase.Execute(context.Background(), dtos.GetActivityHeatmapQuery{
+		WalletID: f.usd.ID().String(),
+		Days:     2,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &dtos.ActivityHeatmapDTO{
+		WalletID: f.usd.ID().String(),
+		Timezone: "UTC",
+		From:     "2024-06-09",
+		To:       "2024-06-10",
+		Days: []dtos.ActivityDayDTO{
+			{Date: "2024-06-09", Count: 1, Sums: []dtos.ActivitySumDTO{{CurrencyCode: "USD", CreditSum: "10.00 USD", DebitSum: "0.00 USD"}}},
+			{Date: "2024-06-10", Count: 1, Sums: []dtos.ActivitySumDTO{{CurrencyCode: "USD", CreditSum: "0.00 USD", DebitSum: "0.25 USD"}}},
+		},
+	}, heatmap)
+}
+
+func TestGetActivityHeatmapUseCase_Validation(t *testing.T) {
+	f := newHeatmapFixture(t)
+	walletID := f.usd.ID().String()
+
+	tests := []struct {
+		name  string
+		query dtos.GetActivityHeatmapQuery
+		field string
+	}{
+		{"TooManyDays", dtos.GetActivityHeatmapQuery{WalletID: walletID, Days: wallet.MaxHeatmapDays + 1}, "days"},
+		{"NegativeDays", dtos.GetActivityHeatmapQuery{WalletID: walletID, Days: -1}, "days"},
+		{"UnknownTimezone", dtos.GetActivityHeatmapQuery{WalletID: walletID, Timezone: "Mars/Olympus_Mons"}, "timezone"},
+		{"LocalTimezone", dtos.GetActivityHeatmapQuery{WalletID: walletID, Timezone: "Local"}, "timezone"},
+		{"NoScope", dtos.GetActivityHeatmapQuery{}, "wallet_id"},
+		{"BothScopes", dtos.GetActivityHeatmapQuery{WalletID: walletID, UserID: f.user.ID().String()}, "wallet_id"},
+		{"InvalidWalletID", dtos.GetActivityHeatmapQuery{WalletID: "not-a-uuid"}, "wallet_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.useCase.Execute(context.Background(), tt.query)
+			var validationErr domainErrors.ValidationError
+			require.True(t, errors.As(err, &validationErr), "error = %v", err)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+
+	t.Run("WalletNotFound", func(t *testing.T) {
+		_, err := f.useCase.Execute(context.Background(), dtos.GetActivityHeatmapQuery{WalletID: uuid.NewString()})
+		assert.True(t, domainErrors.IsNotFound(err), "error = %v", err)
+	})
+}
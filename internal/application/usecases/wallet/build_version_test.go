@@ -0,0 +1,90 @@
+package wallet_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/google/uuid"
+)
+
+// creditAndReadBack пополняет новый кошелёк и возвращает created_by_version
+// сохранённой транзакции в том виде, в каком его отдаёт API.
+func creditAndReadBack(t *testing.T, buildInfo ports.BuildInfo) interface{} {
+	t.Helper()
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	wallets := memory.NewWalletRepository(store)
+	transactions := memory.NewTransactionRepository(store)
+
+	owner, err := entities.NewUser(fmt.Sprintf("build-%s@example.com", uuid.NewString()), "Build Version")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := users.Save(ctx, owner); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+	target, err := entities.NewWallet(owner.ID(), valueobjects.USD)
+	if err != nil {
+		t.Fatalf("NewWallet() error = %v", err)
+	}
+	if err := wallets.Save(ctx, target); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+
+	credit := wallet.NewCreditWalletUseCase(wallets, transactions, memory.NewEventPublisher(store),
+		memory.NewUnitOfWork(store), nil, nil, buildInfo, ports.SensitiveDataPolicy{}, nil, nil)
+
+	key := uuid.NewString()
+	if _, err := credit.Execute(ctx, dtos.CreditWalletCommand{
+		WalletID:       target.ID().String(),
+		Amount:         "10.00",
+		IdempotencyKey: key,
+		Description:    "Versioned credit",
+	}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	stored, err := transactions.FindByIdempotencyKey(ctx, key)
+	if err != nil {
+		t.Fatalf("FindByIdempotencyKey() error = %v", err)
+	}
+
+	data, err := json.Marshal(dtos.ToTransactionDTO(stored))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var wire map[string]interface{}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	value, ok := wire["created_by_version"]
+	if !ok {
+		t.Fatalf("Expected created_by_version in transaction JSON, got %s", data)
+	}
+	return value
+}
+
+func TestCreditWalletUseCase_StampsBuildVersion(t *testing.T) {
+	got := creditAndReadBack(t, ports.BuildInfo{Version: "1.4.0", GitCommit: "abc1234"})
+	if got != "1.4.0+abc1234" {
+		t.Errorf("Expected created_by_version 1.4.0+abc1234, got %v", got)
+	}
+}
+
+func TestCreditWalletUseCase_WithoutBuildVersion(t *testing.T) {
+	// Сборка без версии не штампует - как строки, созданные до миграции
+	if got := creditAndReadBack(t, ports.BuildInfo{}); got != nil {
+		t.Errorf("Expected null created_by_version, got %v", got)
+	}
+}
@@ -0,0 +1,379 @@
+// Package wallet - CloseWalletWithSweep use case для закрытия кошелька с переводом остатка.
+package wallet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/transaction"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/google/uuid"
+)
+
+// closeSweepMaxAttempts - сколько раз повторяем закрытие после конфликта версий.
+const closeSweepMaxAttempts = 3
+
+// SweepIdempotencyNamespace - namespace производного ключа sweep-транзакции:
+// ключ зависит только от ID кошелька, поэтому sweep на кошелёк один.
+const SweepIdempotencyNamespace = "sweep"
+
+// MetadataSweep - metadata sweep-транзакции (значение true).
+const MetadataSweep = "sweep"
+
+// SweepDestinationNotOwnedRule - остаток уходит только на кошелёк владельца
+// закрываемого кошелька.
+const SweepDestinationNotOwnedRule = "SWEEP_DESTINATION_NOT_OWNED"
+
+// CloseWalletWithSweepUseCase - use case для закрытия кошелька с автоматическим
+// переводом остатка на другой кошелёк.
+//
+// Сценарий (одна UnitOfWork):
+// 1. Загрузить оба кошелька, проверить владельца и получателя
+// 2. Перевести закрываемый кошелёк в LOCKED - списания больше невозможны
+// 3. Списать весь available balance (pending блокирует закрытие)
+// 4. Провести остаток через проверки обычного перевода
+// 5. Зачислить остаток на destination транзакцией TRANSFER с metadata sweep=true
+// 6. Закрыть кошелёк, сохранить всё и опубликовать события
+//
+// Кредит, пришедший после загрузки кошелька, ломает сохранение на optimistic
+// lock - тогда сценарий повторяется целиком и переводит уже новый остаток.
+//
+// Бизнес-правила:
+// - destination принадлежит владельцу кошелька (SWEEP_DESTINATION_NOT_OWNED)
+// - destination проходит проверки получателя перевода (закрыт, ограничен, валюта)
+// - Ненулевой остаток - это TRANSFER: выключатель TRANSFER, ToS, fraud check,
+// KYC владельца, скрининг и политика новых получателей - как у перевода
+type CloseWalletWithSweepUseCase struct {
+	walletRepo      ports.WalletRepository
+	transactionRepo ports.TransactionRepository
+	eventPublisher  ports.EventPublisher
+	uow             ports.UnitOfWork
+	fraudDetector   ports.FraudDetector
+	walletLimiter   ports.WalletLimiter       // nil - без ограничения параллельности
+	screener        ports.TransactionScreener // nil - без правил скрининга
+	payeeGuard      ports.NewPayeeGuard       // nil - без проверки новых получателей
+	buildInfo       ports.BuildInfo           // версия сборки для created_by_version
+	terms           ports.TermsGate           // nil - без проверки принятия ToS
+	kyc             ports.KYCGate             // nil - без проверки KYC владельца
+	operations      ports.OperationGate       // nil - без аварийных выключателей
+}
+
+// NewCloseWalletWithSweepUseCase создаёт новый use case.
+func NewCloseWalletWithSweepUseCase(
+	walletRepo ports.WalletRepository,
+	transactionRepo ports.TransactionRepository,
+	eventPublisher ports.EventPublisher,
+	uow ports.UnitOfWork,
+	fraudDetector ports.FraudDetector,
+	walletLimiter ports.WalletLimiter,
+	screener ports.TransactionScreener,
+	payeeGuard ports.NewPayeeGuard,
+	buildInfo ports.BuildInfo,
+	terms ports.TermsGate,
+	kyc ports.KYCGate,
+	operations ports.OperationGate,
+) *CloseWalletWithSweepUseCase {
+	return &CloseWalletWithSweepUseCase{
+		walletRepo:      walletRepo,
+		transactionRepo: transactionRepo,
+		eventPublisher:  eventPublisher,
+		uow:             uow,
+		fraudDetector:   fraudDetector,
+		walletLimiter:   walletLimiter,
+		screener:        screener,
+		payeeGuard:      payeeGuard,
+		buildInfo:       buildInfo,
+		terms:           terms,
+		kyc:             kyc,
+		operations:      operations,
+	}
+}
+
+// Execute закрывает кошелёк, переводя остаток на cmd.SweepToWalletID.
+func (uc *CloseWalletWithSweepUseCase) Execute(ctx context.Context, cmd dtos.CloseWalletCommand) (*dtos.CloseWalletResultDTO, error) {
+	walletID, err := uuid.Parse(cmd.WalletID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "wallet_id", Message: "invalid UUID"}
+	}
+	destinationID, err := uuid.Parse(cmd.SweepToWalletID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "sweep_to", Message: "invalid UUID"}
+	}
+	if walletID == destinationID {
+		return nil, errors.NewBusinessRuleViolation(
+			"SelfTransfer",
+			"cannot sweep a wallet into itself",
+			nil,
+		)
+	}
+
+	release, err := ports.AcquireWallets(ctx, uc.walletLimiter, cmd.WalletID, cmd.SweepToWalletID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	for attempt := 1; ; attempt++ {
+		result, err := uc.closeOnce(ctx, walletID, destinationID, cmd.ConfirmNewPayee)
+		if err == nil || !errors.IsConcurrencyError(err) || attempt == closeSweepMaxAttempts {
+			return result, err
+		}
+		ports.RequestStatsFromContext(ctx).RecordLockRetry()
+	}
+}
+
+// closeOnce - одна попытка закрытия в отдельной UnitOfWork.
+func (uc *CloseWalletWithSweepUseCase) closeOnce(ctx context.Context, walletID, destinationID uuid.UUID, confirmNewPayee bool) (*dtos.CloseWalletResultDTO, error) {
+	var result *dtos.CloseWalletResultDTO
+
+	err := uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		// 1. Загружаем оба кошелька
+		wallet, err := uc.walletRepo.FindByID(txCtx, walletID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return errors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", err)
+			}
+			return fmt.Errorf("failed to load wallet: %w", err)
+		}
+
+		destination, err := uc.walletRepo.FindByID(txCtx, destinationID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return errors.NewDomainError("WALLET_NOT_FOUND", "destination wallet not found", err)
+			}
+			return fmt.Errorf("failed to load destination wallet: %w", err)
+		}
+
+		if wallet.Status() == entities.WalletStatusClosed {
+			return errors.NewBusinessRuleViolation(
+				"WALLET_CLOSED",
+				"wallet is already closed",
+				map[string]interface{}{"walletID": walletID},
+			)
+		}
+
+		// Остаток уходит только владельцу закрываемого кошелька
+		if destination.UserID() != wallet.UserID() {
+			return errors.NewBusinessRuleViolation(
+				SweepDestinationNotOwnedRule,
+				"sweep destination must belong to the wallet owner",
+				map[string]interface{}{"destinationWalletID": destinationID.String()},
+			)
+		}
+		if err := transaction.ValidateTransferDestination(wallet, destination); err != nil {
+			return err
+		}
+
+		// 2. LOCKED до списания: дальше с кошелька ничего не спишется
+		if err := wallet.Lock(); err != nil {
+			return fmt.Errorf("failed to lock wallet: %w", err)
+		}
+
+		// 3. Списываем весь остаток
+		swept, err := wallet.SweepAvailable()
+		if err != nil {
+			return err
+		}
+
+		// 4. Ненулевой остаток - перевод со всеми проверками перевода
+		var sweepTx *entities.Transaction
+		var gateEvents []events.DomainEvent
+		if swept.IsPositive() {
+			sweepTx, err = uc.sweepTransaction(wallet, destinationID, swept)
+			if err != nil {
+				return err
+			}
+			gateEvents, err = uc.checkSweep(txCtx, wallet, sweepTx, confirmNewPayee)
+			if err != nil {
+				return err
+			}
+
+			// 5. Зачисляем остаток на destination
+			if err := destination.Credit(swept); err != nil {
+				return fmt.Errorf("failed to credit destination wallet: %w", err)
+			}
+			if err := sweepTx.StartProcessing(); err != nil {
+				return fmt.Errorf("failed to start processing transaction: %w", err)
+			}
+			if err := sweepTx.MarkCompleted(); err != nil {
+				return fmt.Errorf("failed to complete transaction: %w", err)
+			}
+		}
+
+		// 6. Закрываем и сохраняем; кошелёк первым - конфликт версий ничего не запишет
+		if err := wallet.Close(); err != nil {
+			return fmt.Errorf("failed to close wallet: %w", err)
+		}
+
+		if err := uc.walletRepo.Save(txCtx, wallet); err != nil {
+			if errors.IsConcurrencyError(err) {
+				return errors.NewConcurrencyError(
+					"Wallet",
+					walletID.String(),
+					"wallet was modified by another transaction",
+				)
+			}
+			return fmt.Errorf("failed to save wallet: %w", err)
+		}
+
+		transactionID := uuid.Nil
+		eventList := []events.DomainEvent{}
+		if sweepTx != nil {
+			if err := uc.walletRepo.Save(txCtx, destination); err != nil {
+				return fmt.Errorf("failed to save destination wallet: %w", err)
+			}
+			if err := uc.transactionRepo.Save(txCtx, sweepTx); err != nil {
+				return fmt.Errorf("failed to save sweep transaction: %w", err)
+			}
+			if uc.payeeGuard != nil {
+				if err := uc.payeeGuard.RecordTransfer(txCtx, sweepTx); err != nil {
+					return err
+				}
+			}
+
+			transactionID = sweepTx.ID()
+			eventList = append(eventList,
+				events.NewTransactionCreated(
+					transactionID,
+					walletID,
+					wallet.UserID(),
+					string(entities.TransactionTypeTransfer),
+					swept,
+					sweepTx.IdempotencyKey(),
+				),
+				events.NewWalletDebited(walletID, swept, transactionID, wallet.AvailableBalance(), wallet.BalanceVersion()),
+				events.NewWalletCredited(destinationID, swept, transactionID, destination.AvailableBalance(), destination.BalanceVersion()),
+				events.NewTransactionCompleted(
+					transactionID,
+					walletID,
+					wallet.UserID(),
+					string(entities.TransactionTypeTransfer),
+					swept,
+				).WithCounterparty(destinationID, destination.UserID()).
+					WithReceiptNumber(sweepTx.ReceiptNumber()),
+			)
+			eventList = append(eventList, gateEvents...)
+		}
+
+		// 7. Публикуем события
+		eventList = append(eventList, events.NewWalletClosed(walletID, destinationID, swept, transactionID))
+		if err := uc.eventPublisher.PublishBatch(txCtx, eventList); err != nil {
+			return fmt.Errorf("failed to publish events: %w", err)
+		}
+
+		result = &dtos.CloseWalletResultDTO{
+			Wallet:            dtos.ToWalletDTO(wallet),
+			DestinationWallet: dtos.ToWalletDTO(destination),
+			SweptAmount:       swept.String(),
+		}
+		if sweepTx != nil {
+			result.TransactionID = transactionID.String()
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkSweep применяет к sweep-переводу проверки TransferBetweenWallets и
+// возвращает события KYC и скрининга.
+func (uc *CloseWalletWithSweepUseCase) checkSweep(
+	ctx context.Context,
+	wallet *entities.Wallet,
+	sweepTx *entities.Transaction,
+	confirmNewPayee bool,
+) ([]events.DomainEvent, error) {
+	if err := ports.RequireOperationEnabled(ctx, uc.operations, entities.TransactionTypeTransfer); err != nil {
+		return nil, err
+	}
+
+	if uc.terms != nil {
+		if err := uc.terms.RequireAccepted(ctx, wallet.UserID()); err != nil {
+			return nil, err
+		}
+	}
+
+	if uc.fraudDetector != nil {
+		amount := sweepTx.Amount()
+		fraudResult, err := uc.fraudDetector.Check(ctx, &ports.FraudCheckRequest{
+			UserID:              wallet.UserID().String(),
+			SourceWalletID:      wallet.ID().String(),
+			DestinationWalletID: sweepTx.DestinationWalletID().String(),
+			Amount:              amount.Amount().FloatString(amount.Currency().Decimals()),
+			Currency:            wallet.Currency().Code(),
+			TransactionType:     "TRANSFER",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fraud check failed: %w", err)
+		}
+		if !fraudResult.Approved {
+			return nil, errors.NewBusinessRuleViolation(
+				"FraudDetected",
+				fmt.Sprintf("transaction blocked: %s (risk score: %.2f)", fraudResult.Reason, fraudResult.RiskScore),
+				nil,
+			)
+		}
+	}
+
+	// Закрытие выводит деньги владельца: KYC отозван - остаток не уходит
+	kycEvents, err := ports.CheckOwnerKYC(ctx, uc.kyc, sweepTx, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	screeningEvents, err := ports.ScreenTransaction(ctx, uc.screener, sweepTx, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.payeeGuard != nil {
+		if err := uc.payeeGuard.Check(ctx, &ports.NewPayeeRequest{
+			Transaction: sweepTx,
+			Confirmed:   confirmNewPayee,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return append(kycEvents, screeningEvents...), nil
+}
+
+// sweepTransaction создаёт TRANSFER транзакцию остатка. Завершается она
+// после проверок: скрининг дописывает metadata.
+func (uc *CloseWalletWithSweepUseCase) sweepTransaction(
+	wallet *entities.Wallet,
+	destinationID uuid.UUID,
+	swept valueobjects.Money,
+) (*entities.Transaction, error) {
+	transaction, err := entities.NewTransaction(
+		wallet.ID(),
+		valueobjects.DerivedIdempotencyKey(SweepIdempotencyNamespace, wallet.ID().String()),
+		entities.TransactionTypeTransfer,
+		swept,
+		"Balance sweep on wallet close",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sweep transaction: %w", err)
+	}
+
+	if err := transaction.AssignJurisdiction(wallet.Jurisdiction()); err != nil {
+		return nil, fmt.Errorf("failed to assign jurisdiction: %w", err)
+	}
+	if err := transaction.StampCreatedByVersion(uc.buildInfo.ProducerVersion()); err != nil {
+		return nil, fmt.Errorf("failed to stamp build version: %w", err)
+	}
+	if err := transaction.SetDestinationWallet(destinationID); err != nil {
+		return nil, fmt.Errorf("failed to set destination wallet: %w", err)
+	}
+	if err := transaction.AddMetadata(MetadataSweep, true); err != nil {
+		return nil, fmt.Errorf("failed to tag sweep transaction: %w", err)
+	}
+	return transaction, nil
+}
@@ -0,0 +1,386 @@
+package wallet_test
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/google/uuid"
+)
+
+// sweepFixture - хранилище с двумя кошельками для тестов закрытия.
+type sweepFixture struct {
+	store        *memory.Store
+	users        *memory.UserRepository
+	wallets      *memory.WalletRepository
+	transactions *memory.TransactionRepository
+	publisher    *memory.EventPublisher
+	source       *entities.Wallet
+	destination  *entities.Wallet
+}
+
+func newSweepFixture(t *testing.T, sourceBalance string, destinationCurrency valueobjects.Currency) *sweepFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	f := &sweepFixture{
+		store:        store,
+		wallets:      memory.NewWalletRepository(store),
+		transactions: memory.NewTransactionRepository(store),
+		publisher:    memory.NewEventPublisher(store),
+	}
+	f.users = memory.NewUserRepository(store)
+
+	newWallet := func(currency valueobjects.Currency) *entities.Wallet {
+		owner, err := entities.NewUser(fmt.Sprintf("sweep-%s@example.com", uuid.NewString()), "Sweep Test")
+		if err != nil {
+			t.Fatalf("NewUser() error = %v", err)
+		}
+		if err := f.users.Save(ctx, owner); err != nil {
+			t.Fatalf("save user error = %v", err)
+		}
+		w, err := entities.NewWallet(owner.ID(), currency)
+		if err != nil {
+			t.Fatalf("NewWallet() error = %v", err)
+		}
+		if err := f.wallets.Save(ctx, w); err != nil {
+			t.Fatalf("save wallet error = %v", err)
+		}
+		return w
+	}
+
+	f.source = newWallet(valueobjects.USD)
+	f.destination = newWallet(destinationCurrency)
+
+	if sourceBalance != "" {
+		f.creditDirect(t, f.source.ID(), sourceBalance)
+	}
+	return f
+}
+
+// creditDirect пополняет кошелёк напрямую через репозиторий, в обход use cases.
+func (f *sweepFixture) creditDirect(t *testing.T, walletID uuid.UUID, amount string) {
+	t.Helper()
+	ctx := context.Background()
+
+	w, err := f.wallets.FindByID(ctx, walletID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if err := w.Credit(mustMoney(t, amount, w.Currency())); err != nil {
+		t.Fatalf("Credit() error = %v", err)
+	}
+	if err := f.wallets.Save(ctx, w); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+}
+
+// useCase закрывает source с destination, принадлежащим владельцу source.
+func (f *sweepFixture) useCase(walletRepo ports.WalletRepository, uow ports.UnitOfWork) *wallet.CloseWalletWithSweepUseCase {
+	return f.useCaseWithGates(walletRepo, uow, sweepGates{})
+}
+
+// sweepGates - проверки перевода, включённые в тесте; nil - выключена.
+type sweepGates struct {
+	operations ports.OperationGate
+	kyc        ports.KYCGate
+}
+
+func (f *sweepFixture) useCaseWithGates(walletRepo ports.WalletRepository, uow ports.UnitOfWork, gates sweepGates) *wallet.CloseWalletWithSweepUseCase {
+	owned := &ownedDestination{WalletRepository: walletRepo, destination: f.destination.ID(), owner: f.source.UserID()}
+	return f.rawUseCase(owned, uow, gates)
+}
+
+func (f *sweepFixture) rawUseCase(walletRepo ports.WalletRepository, uow ports.UnitOfWork, gates sweepGates) *wallet.CloseWalletWithSweepUseCase {
+	return wallet.NewCloseWalletWithSweepUseCase(walletRepo, f.transactions, f.publisher, uow, nil, nil, nil, nil,
+		ports.BuildInfo{}, nil, gates.kyc, gates.operations)
+}
+
+func (f *sweepFixture) command() dtos.CloseWalletCommand {
+	return dtos.CloseWalletCommand{
+		WalletID:        f.source.ID().String(),
+		SweepToWalletID: f.destination.ID().String(),
+	}
+}
+
+func (f *sweepFixture) reload(t *testing.T, id uuid.UUID) *entities.Wallet {
+	t.Helper()
+	w, err := f.wallets.FindByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	return w
+}
+
+func mustMoney(t *testing.T, amount string, currency valueobjects.Currency) valueobjects.Money {
+	t.Helper()
+	m, err := valueobjects.NewMoney(amount, currency)
+	if err != nil {
+		t.Fatalf("NewMoney() error = %v", err)
+	}
+	return m
+}
+
+// ownedDestination выдаёт destination за кошелёк владельца source:
+// wallets_user_currency_unique не даёт создать пользователю второй кошелёк
+// в той же валюте.
+type ownedDestination struct {
+	ports.WalletRepository
+	destination uuid.UUID
+	owner       uuid.UUID
+}
+
+func (r *ownedDestination) FindByID(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+	w, err := r.WalletRepository.FindByID(ctx, id)
+	if err != nil || id != r.destination {
+		return w, err
+	}
+	return entities.ReconstructWallet(w.ID(), r.owner, w.Currency(), w.WalletType(), w.Status(),
+		w.AvailableBalance(), w.PendingBalance(), w.BalanceVersion(), w.DailyLimit(), w.MonthlyLimit(),
+		w.Jurisdiction(), w.MaxPendingTransactions(), w.CreatedAt(), w.UpdatedAt()), nil
+}
+
+// transferSwitchedOff - аварийный выключатель TRANSFER выключен.
+type transferSwitchedOff struct {
+	ports.OperationGate
+}
+
+func (transferSwitchedOff) RequireEnabled(_ context.Context, txType entities.TransactionType) error {
+	if txType == entities.TransactionTypeTransfer {
+		return &domainErrors.OperationDisabledError{Operation: "TRANSFER", Reason: "incident"}
+	}
+	return nil
+}
+
+// racingWalletRepository зачисляет кредит на закрываемый кошелёк сразу после
+// первой загрузки - use case держит устаревшую версию.
+type racingWalletRepository struct {
+	*memory.WalletRepository
+	fixture  *sweepFixture
+	t        *testing.T
+	target   uuid.UUID
+	credit   string
+	raced    bool
+	attempts int
+}
+
+func (r *racingWalletRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+	w, err := r.WalletRepository.FindByID(ctx, id)
+	if err != nil || id != r.target {
+		return w, err
+	}
+
+	r.attempts++
+	if !r.raced {
+		r.raced = true
+		r.fixture.creditDirect(r.t, id, r.credit)
+	}
+	return w, nil
+}
+
+func TestCloseWalletWithSweepUseCase_Success(t *testing.T) {
+	f := newSweepFixture(t, "125.50", valueobjects.USD)
+	f.creditDirect(t, f.destination.ID(), "10.00")
+
+	result, err := f.useCase(f.wallets, memory.NewUnitOfWork(f.store)).Execute(context.Background(), f.command())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.SweptAmount != "125.50 USD" {
+		t.Errorf("Expected swept amount 125.50 USD, got %s", result.SweptAmount)
+	}
+
+	source := f.reload(t, f.source.ID())
+	if source.Status() != entities.WalletStatusClosed {
+		t.Errorf("Expected CLOSED wallet, got %s", source.Status())
+	}
+	if !source.AvailableBalance().IsZero() {
+		t.Errorf("Expected zero balance on closed wallet, got %s", source.AvailableBalance().String())
+	}
+	if got := f.reload(t, f.destination.ID()).AvailableBalance().String(); got != "135.50 USD" {
+		t.Errorf("Expected destination balance 135.50 USD, got %s", got)
+	}
+
+	// Sweep - обычный TRANSFER, помеченный metadata sweep=true
+	sweepTx, err := f.transactions.FindByIdempotencyKey(context.Background(),
+		valueobjects.DerivedIdempotencyKey(wallet.SweepIdempotencyNamespace, f.source.ID().String()))
+	if err != nil {
+		t.Fatalf("sweep transaction not found: %v", err)
+	}
+	if sweepTx.ID().String() != result.TransactionID {
+		t.Errorf("Expected transaction_id %s, got %s", sweepTx.ID(), result.TransactionID)
+	}
+	if sweepTx.Type() != entities.TransactionTypeTransfer || !sweepTx.IsCompleted() {
+		t.Errorf("Expected completed TRANSFER, got %s %s", sweepTx.Type(), sweepTx.Status())
+	}
+	if sweepTx.Metadata()["sweep"] != true {
+		t.Errorf("Expected sweep=true metadata, got %v", sweepTx.Metadata())
+	}
+
+	var closed *events.WalletClosed
+	for _, e := range f.publisher.Events() {
+		if c, ok := e.(*events.WalletClosed); ok {
+			closed = c
+		}
+	}
+	if closed == nil {
+		t.Fatal("Expected WalletClosed event")
+	}
+	if closed.TransactionID != sweepTx.ID() || closed.DestinationWalletID != f.destination.ID() {
+		t.Errorf("WalletClosed event does not reference the sweep: %+v", closed)
+	}
+}
+
+func TestCloseWalletWithSweepUseCase_ZeroBalance(t *testing.T) {
+	f := newSweepFixture(t, "", valueobjects.USD)
+
+	result, err := f.useCase(f.wallets, memory.NewUnitOfWork(f.store)).Execute(context.Background(), f.command())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.TransactionID != "" {
+		t.Errorf("Expected no sweep transaction, got %s", result.TransactionID)
+	}
+	if status := f.reload(t, f.source.ID()).Status(); status != entities.WalletStatusClosed {
+		t.Errorf("Expected CLOSED wallet, got %s", status)
+	}
+}
+
+func TestCloseWalletWithSweepUseCase_PendingBalance(t *testing.T) {
+	f := newSweepFixture(t, "100.00", valueobjects.USD)
+
+	source := f.reload(t, f.source.ID())
+	if err := source.Reserve(mustMoney(t, "40.00", valueobjects.USD)); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if err := f.wallets.Save(context.Background(), source); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+
+	_, err := f.useCase(f.wallets, memory.NewUnitOfWork(f.store)).Execute(context.Background(), f.command())
+
+	var violation *domainErrors.BusinessRuleViolation
+	if !stderrors.As(err, &violation) || violation.Rule != "PENDING_BALANCE_ON_CLOSE" {
+		t.Fatalf("Expected PENDING_BALANCE_ON_CLOSE, got %v", err)
+	}
+
+	source = f.reload(t, f.source.ID())
+	if source.Status() != entities.WalletStatusActive {
+		t.Errorf("Expected rejected close to leave wallet ACTIVE, got %s", source.Status())
+	}
+	if got := source.AvailableBalance().String(); got != "60.00 USD" {
+		t.Errorf("Expected available balance untouched, got %s", got)
+	}
+}
+
+func TestCloseWalletWithSweepUseCase_CurrencyMismatch(t *testing.T) {
+	f := newSweepFixture(t, "50.00", valueobjects.EUR)
+
+	_, err := f.useCase(f.wallets, memory.NewUnitOfWork(f.store)).Execute(context.Background(), f.command())
+
+	var violation *domainErrors.BusinessRuleViolation
+	if !stderrors.As(err, &violation) || violation.Rule != domainErrors.CurrencyMismatchRule {
+		t.Fatalf("Expected %s, got %v", domainErrors.CurrencyMismatchRule, err)
+	}
+	if status := f.reload(t, f.source.ID()).Status(); status != entities.WalletStatusActive {
+		t.Errorf("Expected wallet to stay ACTIVE, got %s", status)
+	}
+}
+
+func TestCloseWalletWithSweepUseCase_RacingCreditResweeps(t *testing.T) {
+	f := newSweepFixture(t, "100.00", valueobjects.USD)
+
+	// optimisticUoW без сериализации: кредит попадает между загрузкой и сохранением
+	racing := &racingWalletRepository{
+		WalletRepository: f.wallets,
+		fixture:          f,
+		t:                t,
+		target:           f.source.ID(),
+		credit:           "25.00",
+	}
+
+	ctx := ports.WithRequestStats(context.Background(), &ports.RequestStats{})
+	result, err := f.useCase(racing, &optimisticUoW{}).Execute(ctx, f.command())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if racing.attempts != 2 {
+		t.Errorf("Expected one retry after the racing credit, got %d attempts", racing.attempts)
+	}
+	if retries := ports.RequestStatsFromContext(ctx).Snapshot().LockRetries; retries != 1 {
+		t.Errorf("Expected 1 lock retry recorded, got %d", retries)
+	}
+
+	// Повтор переводит и пришедший кредит - на закрытом кошельке ничего не остаётся
+	if result.SweptAmount != "125.00 USD" {
+		t.Errorf("Expected swept amount 125.00 USD, got %s", result.SweptAmount)
+	}
+	source := f.reload(t, f.source.ID())
+	if source.Status() != entities.WalletStatusClosed || !source.AvailableBalance().IsZero() {
+		t.Errorf("Expected closed empty wallet, got %s %s", source.Status(), source.AvailableBalance().String())
+	}
+	if got := f.reload(t, f.destination.ID()).AvailableBalance().String(); got != "125.00 USD" {
+		t.Errorf("Expected destination balance 125.00 USD, got %s", got)
+	}
+}
+
+func TestCloseWalletWithSweepUseCase_ForeignDestination(t *testing.T) {
+	f := newSweepFixture(t, "50.00", valueobjects.USD)
+
+	// Кошелёк другого пользователя: закрытие не должно работать как перевод
+	_, err := f.rawUseCase(f.wallets, memory.NewUnitOfWork(f.store), sweepGates{}).Execute(context.Background(), f.command())
+
+	var violation *domainErrors.BusinessRuleViolation
+	if !stderrors.As(err, &violation) || violation.Rule != wallet.SweepDestinationNotOwnedRule {
+		t.Fatalf("Expected %s, got %v", wallet.SweepDestinationNotOwnedRule, err)
+	}
+	source := f.reload(t, f.source.ID())
+	if source.Status() != entities.WalletStatusActive || source.AvailableBalance().String() != "50.00 USD" {
+		t.Errorf("Expected wallet untouched, got %s %s", source.Status(), source.AvailableBalance().String())
+	}
+	if got := f.reload(t, f.destination.ID()).AvailableBalance(); !got.IsZero() {
+		t.Errorf("Expected nothing credited to foreign wallet, got %s", got.String())
+	}
+}
+
+func TestCloseWalletWithSweepUseCase_TransferSwitchedOff(t *testing.T) {
+	t.Run("SweepRejected", func(t *testing.T) {
+		f := newSweepFixture(t, "50.00", valueobjects.USD)
+
+		_, err := f.useCaseWithGates(f.wallets, memory.NewUnitOfWork(f.store), sweepGates{operations: transferSwitchedOff{}}).
+			Execute(context.Background(), f.command())
+
+		if !domainErrors.IsOperationDisabled(err) {
+			t.Fatalf("Expected OperationDisabledError, got %v", err)
+		}
+		if status := f.reload(t, f.source.ID()).Status(); status != entities.WalletStatusActive {
+			t.Errorf("Expected wallet to stay ACTIVE, got %s", status)
+		}
+	})
+
+	t.Run("ZeroBalanceCloses", func(t *testing.T) {
+		f := newSweepFixture(t, "", valueobjects.USD)
+
+		// Без остатка перевода нет - выключатель TRANSFER не мешает закрытию
+		if _, err := f.useCaseWithGates(f.wallets, memory.NewUnitOfWork(f.store), sweepGates{operations: transferSwitchedOff{}}).
+			Execute(context.Background(), f.command()); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if status := f.reload(t, f.source.ID()).Status(); status != entities.WalletStatusClosed {
+			t.Errorf("Expected CLOSED wallet, got %s", status)
+		}
+	})
+}
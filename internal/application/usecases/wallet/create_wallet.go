@@ -26,11 +26,13 @@ import (
 // Бизнес-правила:
 // - Только верифицированные пользователи могут создавать кошельки (domain rule)
 // - У пользователя может быть только один кошелёк на валюту
+// - Валюта должна быть открыта для новых кошельков (CurrencyPolicy)
 type CreateWalletUseCase struct {
 	userRepo       ports.UserRepository
 	walletRepo     ports.WalletRepository
 	eventPublisher ports.EventPublisher
 	uow            ports.UnitOfWork
+	currencyPolicy ports.CurrencyPolicySource
 }
 
 // NewCreateWalletUseCase создаёт новый use case.
@@ -39,12 +41,14 @@ func NewCreateWalletUseCase(
 	walletRepo ports.WalletRepository,
 	eventPublisher ports.EventPublisher,
 	uow ports.UnitOfWork,
+	currencyPolicy ports.CurrencyPolicySource, // nil - все поддерживаемые валюты
 ) *CreateWalletUseCase {
 	return &CreateWalletUseCase{
 		userRepo:       userRepo,
 		walletRepo:     walletRepo,
 		eventPublisher: eventPublisher,
 		uow:            uow,
+		currencyPolicy: currencyPolicy,
 	}
 }
 
@@ -53,20 +57,22 @@ func (uc *CreateWalletUseCase) Execute(ctx context.Context, cmd dtos.CreateWalle
 	var result *dtos.WalletDTO
 
 	err := uc.uow.Execute(ctx, func(txCtx context.Context) error {
-		// 1. Парсим входные параметры
+		// 1. Парсим входные параметры: все ошибки сразу
+		var invalid errors.ValidationErrors
 		userID, err := uuid.Parse(cmd.UserID)
 		if err != nil {
-			return errors.ValidationError{
-				Field:   "user_id",
-				Message: "invalid UUID format",
-			}
+			invalid.AddCode("user_id", errors.ValidationCodeInvalidFormat, "invalid UUID format")
 		}
-
 		currency, err := valueobjects.NewCurrency(cmd.CurrencyCode)
 		if err != nil {
-			return errors.ValidationError{
-				Field:   "currency_code",
-				Message: fmt.Sprintf("invalid currency: %v", err),
+			invalid.AddCode("currency_code", errors.ValidationCodeInvalidValue, fmt.Sprintf("invalid currency: %v", err))
+		}
+		if err := invalid.Err(); err != nil {
+			return err
+		}
+		if uc.currencyPolicy != nil {
+			if err := uc.currencyPolicy.Current().CheckWallet(currency); err != nil {
+				return err
 			}
 		}
 
@@ -106,6 +112,12 @@ func (uc *CreateWalletUseCase) Execute(ctx context.Context, cmd dtos.CreateWalle
 			return fmt.Errorf("failed to create wallet entity: %w", err)
 		}
 
+		// Тег хранения - юрисдикция владельца на момент создания.
+		// Последующий переезд пользователя этот кошелёк не затрагивает.
+		if err := wallet.AssignJurisdiction(user.Jurisdiction()); err != nil {
+			return fmt.Errorf("failed to assign jurisdiction: %w", err)
+		}
+
 		// 6. Сохраняем в repository
 		if err := uc.walletRepo.Save(txCtx, wallet); err != nil {
 			return fmt.Errorf("failed to save wallet: %w", err)
@@ -132,6 +144,8 @@ func (uc *CreateWalletUseCase) Execute(ctx context.Context, cmd dtos.CreateWalle
 			TotalBalance:     totalBalance.String(),
 			DailyLimit:       wallet.DailyLimit().String(),
 			MonthlyLimit:     wallet.MonthlyLimit().String(),
+			BalanceVersion:   wallet.BalanceVersion(),
+			Jurisdiction:     wallet.Jurisdiction(),
 			CreatedAt:        wallet.CreatedAt(),
 			UpdatedAt:        wallet.UpdatedAt(),
 		}
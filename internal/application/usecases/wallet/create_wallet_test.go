@@ -47,6 +47,10 @@ func (m *mockUserRepoForWallet) List(ctx context.Context, offset, limit int) ([]
 	return nil, nil
 }
 
+func (m *mockUserRepoForWallet) UserActivitySummary(ctx context.Context, userID uuid.UUID) (ports.UserActivity, error) {
+	return ports.UserActivity{}, nil
+}
+
 type mockWalletRepoForCreate struct {
 	saveFunc                    func(ctx context.Context, wallet *entities.Wallet) error
 	existsByUserAndCurrencyFunc func(ctx context.Context, userID uuid.UUID, currency valueobjects.Currency) (bool, error)
@@ -82,6 +86,10 @@ func (m *mockWalletRepoForCreate) ExistsByUserAndCurrency(ctx context.Context, u
 	return false, nil
 }
 
+func (m *mockWalletRepoForCreate) FindBalanceByID(ctx context.Context, id uuid.UUID) (*ports.WalletBalance, error) {
+	return nil, nil
+}
+
 func (m *mockWalletRepoForCreate) List(ctx context.Context, filter ports.WalletFilter, offset, limit int) ([]*entities.Wallet, error) {
 	return nil, nil
 }
@@ -128,7 +136,7 @@ func TestCreateWalletUseCase_Success(t *testing.T) {
 
 	// Создаем верифицированного пользователя
 	user, _ := entities.NewUser("test@example.com", "Test User")
-	user = entities.ReconstructUser(userID, user.Email(), user.FullName(), entities.KYCStatusUnverified, nil, time.Now(), time.Now())
+	user = entities.ReconstructUser(userID, user.Email(), user.FullName(), entities.KYCStatusUnverified, nil, "DE", "", entities.TermsAcceptance{}, time.Now(), time.Now())
 	_ = user.StartKYCVerification()
 	_ = user.ApproveKYC() // Verified пользователь
 
@@ -156,7 +164,7 @@ func TestCreateWalletUseCase_Success(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow)
+	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateWalletCommand{
 		UserID:       userID.String(),
@@ -191,6 +199,11 @@ func TestCreateWalletUseCase_Success(t *testing.T) {
 		t.Fatal("Expected wallet to be saved")
 	}
 
+	// Юрисдикция владельца копируется на кошелёк
+	if savedWallet.Jurisdiction() != "DE" || result.Jurisdiction != "DE" {
+		t.Errorf("Expected jurisdiction DE, got entity %q, DTO %q", savedWallet.Jurisdiction(), result.Jurisdiction)
+	}
+
 	// Проверяем событие WalletCreated
 	if len(eventPublisher.publishedEvents) != 1 {
 		t.Fatalf("Expected 1 event, got %d", len(eventPublisher.publishedEvents))
@@ -208,7 +221,7 @@ func TestCreateWalletUseCase_CryptoWallet(t *testing.T) {
 	userID := uuid.New()
 
 	user, _ := entities.NewUser("test@example.com", "Test User")
-	user = entities.ReconstructUser(userID, user.Email(), user.FullName(), entities.KYCStatusVerified, nil, time.Now(), time.Now())
+	user = entities.ReconstructUser(userID, user.Email(), user.FullName(), entities.KYCStatusVerified, nil, "", "", entities.TermsAcceptance{}, time.Now(), time.Now())
 
 	userRepo := &mockUserRepoForWallet{
 		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.User, error) {
@@ -225,7 +238,7 @@ func TestCreateWalletUseCase_CryptoWallet(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow)
+	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateWalletCommand{
 		UserID:       userID.String(),
@@ -259,7 +272,7 @@ func TestCreateWalletUseCase_InvalidUserUUID(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow)
+	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateWalletCommand{
 		UserID:       "invalid-uuid",
@@ -294,7 +307,7 @@ func TestCreateWalletUseCase_InvalidCurrency(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow)
+	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateWalletCommand{
 		UserID:       userID.String(),
@@ -318,6 +331,31 @@ func TestCreateWalletUseCase_InvalidCurrency(t *testing.T) {
 	}
 }
 
+// TestCreateWalletUseCase_AllValidationErrors проверяет, что ошибки user_id
+// и currency_code возвращаются вместе
+func TestCreateWalletUseCase_AllValidationErrors(t *testing.T) {
+	useCase := NewCreateWalletUseCase(&mockUserRepoForWallet{}, &mockWalletRepoForCreate{}, &mockEventPublisherForWallet{}, &mockUoWForWallet{}, nil)
+
+	_, err := useCase.Execute(context.Background(), dtos.CreateWalletCommand{
+		UserID:       "not-a-uuid",
+		CurrencyCode: "XYZ",
+	})
+
+	var validationErrs domainErrors.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+	}
+	if len(validationErrs) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", validationErrs)
+	}
+	if validationErrs[0].Field != "user_id" || validationErrs[1].Field != "currency_code" {
+		t.Errorf("Unexpected fields: %v", validationErrs)
+	}
+	if validationErrs[1].Code != domainErrors.ValidationCodeInvalidValue {
+		t.Errorf("Expected currency_code code %s, got %s", domainErrors.ValidationCodeInvalidValue, validationErrs[1].Code)
+	}
+}
+
 // TestCreateWalletUseCase_UserNotFound тестирует случай, когда пользователь не найден
 func TestCreateWalletUseCase_UserNotFound(t *testing.T) {
 	// Arrange
@@ -334,7 +372,7 @@ func TestCreateWalletUseCase_UserNotFound(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow)
+	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateWalletCommand{
 		UserID:       userID.String(),
@@ -385,7 +423,7 @@ func TestCreateWalletUseCase_UserNotVerified(t *testing.T) {
 			userID := uuid.New()
 
 			user, _ := entities.NewUser("test@example.com", "Test User")
-			user = entities.ReconstructUser(userID, user.Email(), user.FullName(), tt.kycStatus, nil, time.Now(), time.Now())
+			user = entities.ReconstructUser(userID, user.Email(), user.FullName(), tt.kycStatus, nil, "", "", entities.TermsAcceptance{}, time.Now(), time.Now())
 
 			userRepo := &mockUserRepoForWallet{
 				findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.User, error) {
@@ -397,7 +435,7 @@ func TestCreateWalletUseCase_UserNotVerified(t *testing.T) {
 			eventPublisher := &mockEventPublisherForWallet{}
 			uow := &mockUoWForWallet{}
 
-			useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow)
+			useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow, nil)
 
 			cmd := dtos.CreateWalletCommand{
 				UserID:       userID.String(),
@@ -431,7 +469,7 @@ func TestCreateWalletUseCase_WalletAlreadyExists(t *testing.T) {
 	userID := uuid.New()
 
 	user, _ := entities.NewUser("test@example.com", "Test User")
-	user = entities.ReconstructUser(userID, user.Email(), user.FullName(), entities.KYCStatusVerified, nil, time.Now(), time.Now())
+	user = entities.ReconstructUser(userID, user.Email(), user.FullName(), entities.KYCStatusVerified, nil, "", "", entities.TermsAcceptance{}, time.Now(), time.Now())
 
 	userRepo := &mockUserRepoForWallet{
 		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.User, error) {
@@ -449,7 +487,7 @@ func TestCreateWalletUseCase_WalletAlreadyExists(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow)
+	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateWalletCommand{
 		UserID:       userID.String(),
@@ -480,7 +518,7 @@ func TestCreateWalletUseCase_ExistsCheckError(t *testing.T) {
 	userID := uuid.New()
 
 	user, _ := entities.NewUser("test@example.com", "Test User")
-	user = entities.ReconstructUser(userID, user.Email(), user.FullName(), entities.KYCStatusVerified, nil, time.Now(), time.Now())
+	user = entities.ReconstructUser(userID, user.Email(), user.FullName(), entities.KYCStatusVerified, nil, "", "", entities.TermsAcceptance{}, time.Now(), time.Now())
 
 	userRepo := &mockUserRepoForWallet{
 		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.User, error) {
@@ -497,7 +535,7 @@ func TestCreateWalletUseCase_ExistsCheckError(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow)
+	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateWalletCommand{
 		UserID:       userID.String(),
@@ -524,7 +562,7 @@ func TestCreateWalletUseCase_SaveError(t *testing.T) {
 	userID := uuid.New()
 
 	user, _ := entities.NewUser("test@example.com", "Test User")
-	user = entities.ReconstructUser(userID, user.Email(), user.FullName(), entities.KYCStatusVerified, nil, time.Now(), time.Now())
+	user = entities.ReconstructUser(userID, user.Email(), user.FullName(), entities.KYCStatusVerified, nil, "", "", entities.TermsAcceptance{}, time.Now(), time.Now())
 
 	userRepo := &mockUserRepoForWallet{
 		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.User, error) {
@@ -544,7 +582,7 @@ func TestCreateWalletUseCase_SaveError(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow)
+	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateWalletCommand{
 		UserID:       userID.String(),
@@ -571,7 +609,7 @@ func TestCreateWalletUseCase_EventPublishError(t *testing.T) {
 	userID := uuid.New()
 
 	user, _ := entities.NewUser("test@example.com", "Test User")
-	user = entities.ReconstructUser(userID, user.Email(), user.FullName(), entities.KYCStatusVerified, nil, time.Now(), time.Now())
+	user = entities.ReconstructUser(userID, user.Email(), user.FullName(), entities.KYCStatusVerified, nil, "", "", entities.TermsAcceptance{}, time.Now(), time.Now())
 
 	userRepo := &mockUserRepoForWallet{
 		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.User, error) {
@@ -593,7 +631,7 @@ func TestCreateWalletUseCase_EventPublishError(t *testing.T) {
 
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow)
+	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateWalletCommand{
 		UserID:       userID.String(),
@@ -620,7 +658,7 @@ func TestCreateWalletUseCase_InitialBalanceIsZero(t *testing.T) {
 	userID := uuid.New()
 
 	user, _ := entities.NewUser("test@example.com", "Test User")
-	user = entities.ReconstructUser(userID, user.Email(), user.FullName(), entities.KYCStatusVerified, nil, time.Now(), time.Now())
+	user = entities.ReconstructUser(userID, user.Email(), user.FullName(), entities.KYCStatusVerified, nil, "", "", entities.TermsAcceptance{}, time.Now(), time.Now())
 
 	userRepo := &mockUserRepoForWallet{
 		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.User, error) {
@@ -637,7 +675,7 @@ func TestCreateWalletUseCase_InitialBalanceIsZero(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow)
+	useCase := NewCreateWalletUseCase(userRepo, walletRepo, eventPublisher, uow, nil)
 
 	cmd := dtos.CreateWalletCommand{
 		UserID:       userID.String(),
@@ -666,3 +704,23 @@ func TestCreateWalletUseCase_InitialBalanceIsZero(t *testing.T) {
 		t.Error("Expected TotalBalance to be set")
 	}
 }
+
+// TestCreateWalletUseCase_CurrencyNotAllowed тестирует, что валюта,
+// закрытая политикой, отклоняется до обращения к хранилищу.
+func TestCreateWalletUseCase_CurrencyNotAllowed(t *testing.T) {
+	walletRepo := &mockWalletRepoForCreate{
+		saveFunc: func(ctx context.Context, wallet *entities.Wallet) error {
+			t.Fatal("Expected no wallet to be saved")
+			return nil
+		},
+	}
+	policy := ports.CurrencyPolicy{WalletCurrencies: []string{"USD"}}
+	useCase := NewCreateWalletUseCase(&mockUserRepoForWallet{}, walletRepo, &mockEventPublisherForWallet{}, &mockUoWForWallet{}, policy)
+
+	_, err := useCase.Execute(context.Background(), dtos.CreateWalletCommand{UserID: uuid.New().String(), CurrencyCode: "EUR"})
+
+	var violation *domainErrors.BusinessRuleViolation
+	if !errors.As(err, &violation) || violation.Rule != "CURRENCY_NOT_ALLOWED" {
+		t.Errorf("Expected CURRENCY_NOT_ALLOWED, got %v", err)
+	}
+}
@@ -17,7 +17,6 @@ import (
 	"github.com/Haleralex/wallethub/internal/domain/entities"
 	"github.com/Haleralex/wallethub/internal/domain/errors"
 	"github.com/Haleralex/wallethub/internal/domain/events"
-	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
 	"github.com/google/uuid"
 )
 
@@ -39,6 +38,12 @@ type CreditWalletUseCase struct {
 	transactionRepo ports.TransactionRepository
 	eventPublisher  ports.EventPublisher
 	uow             ports.UnitOfWork
+	walletLimiter   ports.WalletLimiter            // nil - без ограничения параллельности
+	screener        ports.TransactionScreener      // nil - без правил скрининга
+	buildInfo       ports.BuildInfo                // версия сборки для created_by_version
+	sensitiveData   ports.SensitiveDataPolicy      // PAN/IBAN в external reference: маскировать или отклонять
+	operations      ports.OperationGate            // nil - без аварийных выключателей
+	settings        ports.WalletSettingsRepository // nil - без автотегов кошелька
 }
 
 // NewCreditWalletUseCase создаёт новый use case.
@@ -47,20 +52,43 @@ func NewCreditWalletUseCase(
 	transactionRepo ports.TransactionRepository,
 	eventPublisher ports.EventPublisher,
 	uow ports.UnitOfWork,
+	walletLimiter ports.WalletLimiter,
+	screener ports.TransactionScreener,
+	buildInfo ports.BuildInfo,
+	sensitiveData ports.SensitiveDataPolicy,
+	operations ports.OperationGate,
+	settings ports.WalletSettingsRepository,
 ) *CreditWalletUseCase {
 	return &CreditWalletUseCase{
 		walletRepo:      walletRepo,
 		transactionRepo: transactionRepo,
 		eventPublisher:  eventPublisher,
 		uow:             uow,
+		walletLimiter:   walletLimiter,
+		screener:        screener,
+		buildInfo:       buildInfo,
+		sensitiveData:   sensitiveData,
+		operations:      operations,
+		settings:        settings,
 	}
 }
 
 // Execute выполняет пополнение кошелька.
 func (uc *CreditWalletUseCase) Execute(ctx context.Context, cmd dtos.CreditWalletCommand) (*dtos.WalletOperationDTO, error) {
+	if err := ports.RequireOperationEnabled(ctx, uc.operations, entities.TransactionTypeDeposit); err != nil {
+		return nil, err
+	}
+
+	// Ограничиваем параллельные мутации кошелька до входа в UnitOfWork
+	release, err := ports.AcquireWallets(ctx, uc.walletLimiter, cmd.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	var result *dtos.WalletOperationDTO
 
-	err := uc.uow.Execute(ctx, func(txCtx context.Context) error {
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
 		// 1. Проверка идемпотентности
 		// Если транзакция с таким ключом уже существует, возвращаем её
 		existingTx, err := uc.transactionRepo.FindByIdempotencyKey(txCtx, cmd.IdempotencyKey)
@@ -71,13 +99,8 @@ func (uc *CreditWalletUseCase) Execute(ctx context.Context, cmd dtos.CreditWalle
 		if existingTx != nil {
 			// 🔑 Идемпотентность: Транзакция уже существует
 			// Загружаем кошелёк и возвращаем текущее состояние
-			wallet, err := uc.walletRepo.FindByID(txCtx, existingTx.WalletID())
-			if err != nil {
-				return fmt.Errorf("failed to load wallet: %w", err)
-			}
-
-			result = uc.buildResult(wallet, existingTx)
-			return nil // Успешно, но без изменений (idempotent)
+			result, err = uc.replay(txCtx, existingTx)
+			return err // Успешно, но без изменений (idempotent)
 		}
 
 		// 2. Парсим входные параметры
@@ -86,6 +109,13 @@ func (uc *CreditWalletUseCase) Execute(ctx context.Context, cmd dtos.CreditWalle
 			return errors.ValidationError{Field: "wallet_id", Message: "invalid UUID"}
 		}
 
+		// PAN/IBAN в ссылке: в строгом режиме запрос отклоняется до записи
+		var invalid errors.ValidationErrors
+		uc.sensitiveData.Check(&invalid, "external_reference", cmd.ExternalReference)
+		if err := invalid.Err(); err != nil {
+			return err
+		}
+
 		// 3. Загружаем кошелёк
 		wallet, err := uc.walletRepo.FindByID(txCtx, walletID)
 		if err != nil {
@@ -96,9 +126,9 @@ func (uc *CreditWalletUseCase) Execute(ctx context.Context, cmd dtos.CreditWalle
 		}
 
 		// 4. Создаём Money с правильной валютой из кошелька
-		amountMoney, err := valueobjects.NewMoney(cmd.Amount, wallet.Currency())
+		amountMoney, err := dtos.ParseCommandAmount(cmd.Amount, cmd.AmountMinor, wallet.Currency())
 		if err != nil {
-			return errors.ValidationError{Field: "amount", Message: fmt.Sprintf("invalid amount: %v", err)}
+			return err
 		}
 
 		// 5. Создаём Transaction entity
@@ -113,6 +143,15 @@ func (uc *CreditWalletUseCase) Execute(ctx context.Context, cmd dtos.CreditWalle
 			return fmt.Errorf("failed to create transaction entity: %w", err)
 		}
 
+		// Тег хранения копируется с исходного кошелька и дальше не меняется
+		if err := transaction.AssignJurisdiction(wallet.Jurisdiction()); err != nil {
+			return fmt.Errorf("failed to assign jurisdiction: %w", err)
+		}
+
+		if err := transaction.StampCreatedByVersion(uc.buildInfo.ProducerVersion()); err != nil {
+			return fmt.Errorf("failed to stamp build version: %w", err)
+		}
+
 		// Устанавливаем external reference если есть
 		if cmd.ExternalReference != "" {
 			if err := transaction.SetExternalReference(cmd.ExternalReference); err != nil {
@@ -120,6 +159,21 @@ func (uc *CreditWalletUseCase) Execute(ctx context.Context, cmd dtos.CreditWalle
 			}
 		}
 
+		// Автотеги кошелька до скрининга: правила видят итоговую metadata
+		walletSettings, err := ports.LoadWalletSettings(txCtx, uc.settings, walletID)
+		if err != nil {
+			return err
+		}
+		if err := walletSettings.TagTransaction(transaction); err != nil {
+			return fmt.Errorf("failed to tag transaction: %w", err)
+		}
+
+		// Скрининг до движения средств: BLOCK откатывает UnitOfWork
+		screeningEvents, err := ports.ScreenTransaction(txCtx, uc.screener, transaction, wallet)
+		if err != nil {
+			return err
+		}
+
 		// 6. Применяем бизнес-операцию Credit к кошельку
 		// Domain entity Wallet выполнит валидацию и обновит баланс
 		if err := wallet.Credit(amountMoney); err != nil {
@@ -158,6 +212,7 @@ func (uc *CreditWalletUseCase) Execute(ctx context.Context, cmd dtos.CreditWalle
 			events.NewTransactionCreated(
 				transaction.ID(),
 				walletID,
+				wallet.UserID(),
 				string(entities.TransactionTypeDeposit),
 				amountMoney,
 				cmd.IdempotencyKey,
@@ -167,14 +222,17 @@ func (uc *CreditWalletUseCase) Execute(ctx context.Context, cmd dtos.CreditWalle
 				amountMoney,
 				transaction.ID(),
 				wallet.AvailableBalance(),
+				wallet.BalanceVersion(),
 			),
 			events.NewTransactionCompleted(
 				transaction.ID(),
 				walletID,
+				wallet.UserID(),
 				string(entities.TransactionTypeDeposit),
 				amountMoney,
-			),
+			).WithReceiptNumber(transaction.ReceiptNumber()),
 		}
+		eventList = append(eventList, screeningEvents...)
 
 		if err := uc.eventPublisher.PublishBatch(txCtx, eventList); err != nil {
 			return fmt.Errorf("failed to publish events: %w", err)
@@ -187,6 +245,16 @@ func (uc *CreditWalletUseCase) Execute(ctx context.Context, cmd dtos.CreditWalle
 	})
 
 	if err != nil {
+		// Гонка: параллельный запрос с тем же ключом закоммитился между
+		// проверкой идемпотентности и INSERT - отвечаем как на повтор
+		if errors.IsDuplicateTransaction(err) {
+			existingTx, findErr := uc.transactionRepo.FindByIdempotencyKey(ctx, cmd.IdempotencyKey)
+			if findErr != nil {
+				return nil, err
+			}
+			return uc.replay(ctx, existingTx)
+		}
+
 		// Если ошибка - concurrency error, может потребоваться retry
 		// Обработка retry будет в HTTP layer или middleware
 		return nil, err
@@ -195,6 +263,18 @@ func (uc *CreditWalletUseCase) Execute(ctx context.Context, cmd dtos.CreditWalle
 	return result, nil
 }
 
+// replay строит результат для уже обработанного idempotency_key.
+func (uc *CreditWalletUseCase) replay(ctx context.Context, existingTx *entities.Transaction) (*dtos.WalletOperationDTO, error) {
+	wallet, err := uc.walletRepo.FindByID(ctx, existingTx.WalletID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wallet: %w", err)
+	}
+
+	result := uc.buildResult(wallet, existingTx)
+	result.IdempotentReplay = true
+	return result, nil
+}
+
 // buildResult - вспомогательный метод для построения DTO
 func (uc *CreditWalletUseCase) buildResult(wallet *entities.Wallet, tx *entities.Transaction) *dtos.WalletOperationDTO {
 	totalBalance, _ := wallet.TotalBalance()
@@ -211,10 +291,14 @@ func (uc *CreditWalletUseCase) buildResult(wallet *entities.Wallet, tx *entities
 			TotalBalance:     totalBalance.String(),
 			DailyLimit:       wallet.DailyLimit().String(),
 			MonthlyLimit:     wallet.MonthlyLimit().String(),
+			BalanceVersion:   wallet.BalanceVersion(),
+			Jurisdiction:     wallet.Jurisdiction(),
 			CreatedAt:        wallet.CreatedAt(),
 			UpdatedAt:        wallet.UpdatedAt(),
 		},
+		Transaction:   dtos.ToTransactionDTO(tx),
 		TransactionID: tx.ID().String(),
 		Message:       fmt.Sprintf("Wallet credited with %s successfully", tx.Amount().String()),
+		CreatedAt:     tx.CreatedAt(),
 	}
 }
@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"context"
+	stderrors "errors"
 	"testing"
 	"time"
 
@@ -37,6 +38,10 @@ func (m *mockTransactionRepoForCredit) FindByIdempotencyKey(ctx context.Context,
 	return nil, domainErrors.ErrEntityNotFound
 }
 
+func (m *mockTransactionRepoForCredit) FindByExternalReference(ctx context.Context, reference string) ([]*entities.Transaction, error) {
+	return nil, nil
+}
+
 func (m *mockTransactionRepoForCredit) FindByWalletID(ctx context.Context, walletID uuid.UUID, offset, limit int) ([]*entities.Transaction, error) {
 	return nil, nil
 }
@@ -45,6 +50,14 @@ func (m *mockTransactionRepoForCredit) FindPendingByWallet(ctx context.Context,
 	return nil, nil
 }
 
+func (m *mockTransactionRepoForCredit) CountPendingByWallet(ctx context.Context, walletID uuid.UUID) (int, error) {
+	return 0, nil
+}
+
+func (m *mockTransactionRepoForCredit) CountOutcomes(ctx context.Context, from, to time.Time) (ports.TransactionOutcomeCounts, error) {
+	return ports.TransactionOutcomeCounts{}, nil
+}
+
 func (m *mockTransactionRepoForCredit) FindFailedRetryable(ctx context.Context, maxRetries int, limit int) ([]*entities.Transaction, error) {
 	return nil, nil
 }
@@ -53,6 +66,14 @@ func (m *mockTransactionRepoForCredit) List(ctx context.Context, filter ports.Tr
 	return nil, nil
 }
 
+func (m *mockTransactionRepoForCredit) ListBefore(ctx context.Context, filter ports.TransactionFilter, before *ports.TransactionCursor, limit int) ([]*entities.Transaction, error) {
+	return nil, nil
+}
+
+func (m *mockTransactionRepoForCredit) ListAfter(ctx context.Context, filter ports.TransactionFilter, after *ports.TransactionCursor, limit int) ([]*entities.Transaction, error) {
+	return nil, nil
+}
+
 type mockWalletRepoForCredit struct {
 	findByIDFunc func(ctx context.Context, id uuid.UUID) (*entities.Wallet, error)
 	saveFunc     func(ctx context.Context, wallet *entities.Wallet) error
@@ -84,6 +105,10 @@ func (m *mockWalletRepoForCredit) ExistsByUserAndCurrency(ctx context.Context, u
 	return false, nil
 }
 
+func (m *mockWalletRepoForCredit) FindBalanceByID(ctx context.Context, id uuid.UUID) (*ports.WalletBalance, error) {
+	return nil, nil
+}
+
 func (m *mockWalletRepoForCredit) List(ctx context.Context, filter ports.WalletFilter, offset, limit int) ([]*entities.Wallet, error) {
 	return nil, nil
 }
@@ -94,7 +119,7 @@ func createTestWallet(walletID, userID uuid.UUID, currency valueobjects.Currency
 	dailyLimit, _ := valueobjects.NewMoney("10000", currency)
 	monthlyLimit, _ := valueobjects.NewMoney("100000", currency)
 	return entities.ReconstructWallet(walletID, userID, currency, entities.WalletTypeFiat, entities.WalletStatusActive,
-		initialBalance, initialBalance, 0, dailyLimit, monthlyLimit, time.Now(), time.Now())
+		initialBalance, initialBalance, 0, dailyLimit, monthlyLimit, "", nil, time.Now(), time.Now())
 }
 
 // TestCreditWalletUseCase_Success тестирует успешное пополнение кошелька
@@ -136,7 +161,7 @@ func TestCreditWalletUseCase_Success(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil)
 
 	cmd := dtos.CreditWalletCommand{
 		WalletID:       walletID.String(),
@@ -170,6 +195,25 @@ func TestCreditWalletUseCase_Success(t *testing.T) {
 		t.Errorf("Expected transaction status = %s, got %s", entities.TransactionStatusCompleted, savedTransaction.Status())
 	}
 
+	// Новая операция - не replay
+	if result.IdempotentReplay {
+		t.Error("Expected IdempotentReplay = false for a new transaction")
+	}
+	if !result.CreatedAt.Equal(savedTransaction.CreatedAt()) {
+		t.Errorf("Expected CreatedAt = %v, got %v", savedTransaction.CreatedAt(), result.CreatedAt)
+	}
+
+	// Транзакция в ответе - в состоянии после коммита
+	if result.Transaction.ID != result.TransactionID || result.TransactionID != savedTransaction.ID().String() {
+		t.Errorf("Expected embedded transaction %s, got %s (transaction_id %s)", savedTransaction.ID(), result.Transaction.ID, result.TransactionID)
+	}
+	if result.Transaction.Status != string(entities.TransactionStatusCompleted) {
+		t.Errorf("Expected embedded transaction status = %s, got %s", entities.TransactionStatusCompleted, result.Transaction.Status)
+	}
+	if result.Transaction.Type != string(entities.TransactionTypeDeposit) || result.Transaction.CompletedAt == nil {
+		t.Errorf("Expected completed DEPOSIT, got %s (completed_at %v)", result.Transaction.Type, result.Transaction.CompletedAt)
+	}
+
 	// Проверяем события (3: TransactionCreated, WalletCredited, TransactionCompleted)
 	if len(eventPublisher.publishedEvents) < 3 {
 		t.Errorf("Expected at least 3 events, got %d", len(eventPublisher.publishedEvents))
@@ -191,7 +235,7 @@ func TestCreditWalletUseCase_Idempotency(t *testing.T) {
 	dailyLimit, _ := valueobjects.NewMoney("10000", currency)
 	monthlyLimit, _ := valueobjects.NewMoney("100000", currency)
 	wallet := entities.ReconstructWallet(walletID, userID, currency, entities.WalletTypeFiat, entities.WalletStatusActive,
-		creditedBalance, zeroBalance, 0, dailyLimit, monthlyLimit, time.Now(), time.Now())
+		creditedBalance, zeroBalance, 0, dailyLimit, monthlyLimit, "", nil, time.Now(), time.Now())
 
 	// Существующая транзакция
 	amountMoney, _ := valueobjects.NewMoney("100.50", currency)
@@ -215,7 +259,7 @@ func TestCreditWalletUseCase_Idempotency(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil)
 
 	cmd := dtos.CreditWalletCommand{
 		WalletID:       walletID.String(),
@@ -236,12 +280,76 @@ func TestCreditWalletUseCase_Idempotency(t *testing.T) {
 		t.Fatal("Expected result, got nil")
 	}
 
+	if !result.IdempotentReplay {
+		t.Error("Expected IdempotentReplay = true for a replayed key")
+	}
+	if !result.CreatedAt.Equal(existingTx.CreatedAt()) {
+		t.Errorf("Expected original CreatedAt = %v, got %v", existingTx.CreatedAt(), result.CreatedAt)
+	}
+	if result.TransactionID != existingTx.ID().String() {
+		t.Errorf("Expected TransactionID = %s, got %s", existingTx.ID(), result.TransactionID)
+	}
+
 	// Идемпотентность: не должны публиковаться новые события
 	if len(eventPublisher.publishedEvents) != 0 {
 		t.Errorf("Expected no new events (idempotent), got %d", len(eventPublisher.publishedEvents))
 	}
 }
 
+// TestCreditWalletUseCase_DuplicateKeyRace тестирует гонку двух запросов с одним ключом:
+// INSERT падает на unique constraint, ответ строится как replay.
+func TestCreditWalletUseCase_DuplicateKeyRace(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	walletID := uuid.New()
+	idempotencyKey := uuid.New().String()
+	currency := valueobjects.MustNewCurrency("USD")
+
+	amountMoney, _ := valueobjects.NewMoney("100.50", currency)
+	winnerTx, _ := entities.NewTransaction(walletID, idempotencyKey, entities.TransactionTypeDeposit, amountMoney, "Test deposit")
+
+	walletRepo := &mockWalletRepoForCredit{
+		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+			return createTestWallet(walletID, uuid.New(), currency), nil
+		},
+	}
+
+	// Первая проверка ключа ничего не находит, конкурент успевает закоммитить раньше нас
+	committed := false
+	transactionRepo := &mockTransactionRepoForCredit{
+		findByIdempotencyKeyFunc: func(ctx context.Context, key string) (*entities.Transaction, error) {
+			if committed {
+				return winnerTx, nil
+			}
+			return nil, domainErrors.ErrEntityNotFound
+		},
+		saveFunc: func(ctx context.Context, tx *entities.Transaction) error {
+			committed = true
+			return domainErrors.ErrDuplicateTransaction
+		},
+	}
+
+	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, &mockEventPublisherForWallet{}, &mockUoWForWallet{}, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil)
+
+	// Act
+	result, err := useCase.Execute(ctx, dtos.CreditWalletCommand{
+		WalletID:       walletID.String(),
+		Amount:         "100.50",
+		IdempotencyKey: idempotencyKey,
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !result.IdempotentReplay {
+		t.Error("Expected IdempotentReplay = true after unique violation")
+	}
+	if result.TransactionID != winnerTx.ID().String() {
+		t.Errorf("Expected TransactionID = %s, got %s", winnerTx.ID(), result.TransactionID)
+	}
+}
+
 // TestCreditWalletUseCase_InvalidWalletUUID тестирует валидацию UUID
 func TestCreditWalletUseCase_InvalidWalletUUID(t *testing.T) {
 	// Arrange
@@ -252,7 +360,7 @@ func TestCreditWalletUseCase_InvalidWalletUUID(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil)
 
 	cmd := dtos.CreditWalletCommand{
 		WalletID:       "invalid-uuid",
@@ -299,7 +407,7 @@ func TestCreditWalletUseCase_WalletNotFound(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil)
 
 	cmd := dtos.CreditWalletCommand{
 		WalletID:       walletID.String(),
@@ -337,7 +445,7 @@ func TestCreditWalletUseCase_ClosedWallet(t *testing.T) {
 	dailyLimit, _ := valueobjects.NewMoney("10000", currency)
 	monthlyLimit, _ := valueobjects.NewMoney("100000", currency)
 	wallet := entities.ReconstructWallet(walletID, userID, currency, entities.WalletTypeFiat, entities.WalletStatusClosed,
-		zeroBalance, zeroBalance, 0, dailyLimit, monthlyLimit, time.Now(), time.Now())
+		zeroBalance, zeroBalance, 0, dailyLimit, monthlyLimit, "", nil, time.Now(), time.Now())
 
 	walletRepo := &mockWalletRepoForCredit{
 		findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
@@ -354,7 +462,7 @@ func TestCreditWalletUseCase_ClosedWallet(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil)
 
 	cmd := dtos.CreditWalletCommand{
 		WalletID:       walletID.String(),
@@ -403,7 +511,7 @@ func TestCreditWalletUseCase_ConcurrencyError(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil)
 
 	cmd := dtos.CreditWalletCommand{
 		WalletID:       walletID.String(),
@@ -459,7 +567,7 @@ func TestCreditWalletUseCase_ExternalReference(t *testing.T) {
 	eventPublisher := &mockEventPublisherForWallet{}
 	uow := &mockUoWForWallet{}
 
-	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, eventPublisher, uow)
+	useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, eventPublisher, uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil)
 
 	cmd := dtos.CreditWalletCommand{
 		WalletID:          walletID.String(),
@@ -489,3 +597,123 @@ func TestCreditWalletUseCase_ExternalReference(t *testing.T) {
 		t.Errorf("Expected ExternalReference = stripe_pi_123456, got %s", savedTransaction.ExternalReference())
 	}
 }
+
+// TestCreditWalletUseCase_SensitiveExternalReference тестирует номер карты в external reference
+func TestCreditWalletUseCase_SensitiveExternalReference(t *testing.T) {
+	walletID := uuid.New()
+	wallet := createTestWallet(walletID, uuid.New(), valueobjects.MustNewCurrency("USD"))
+
+	run := func(t *testing.T, policy ports.SensitiveDataPolicy) (*entities.Transaction, error) {
+		var savedTransaction *entities.Transaction
+		walletRepo := &mockWalletRepoForCredit{
+			findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+				return wallet, nil
+			},
+		}
+		transactionRepo := &mockTransactionRepoForCredit{
+			saveFunc: func(ctx context.Context, tx *entities.Transaction) error {
+				savedTransaction = tx
+				return nil
+			},
+		}
+
+		useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, &mockEventPublisherForWallet{}, &mockUoWForWallet{}, nil, nil, ports.BuildInfo{}, policy, nil, nil)
+		_, err := useCase.Execute(context.Background(), dtos.CreditWalletCommand{
+			WalletID:          walletID.String(),
+			Amount:            "10.00",
+			IdempotencyKey:    uuid.New().String(),
+			Description:       "Test",
+			ExternalReference: "card 4111 1111 1111 1111",
+		})
+		return savedTransaction, err
+	}
+
+	t.Run("Masked by default", func(t *testing.T) {
+		saved, err := run(t, ports.SensitiveDataPolicy{})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if saved.ExternalReference() != "card 411111******1111" {
+			t.Errorf("Expected masked reference, got %s", saved.ExternalReference())
+		}
+		if saved.ExternalReferenceHash() != valueobjects.HashExternalReference("card 4111 1111 1111 1111") {
+			t.Errorf("Expected hash of original reference, got %s", saved.ExternalReferenceHash())
+		}
+	})
+
+	t.Run("Rejected in strict mode", func(t *testing.T) {
+		saved, err := run(t, ports.SensitiveDataPolicy{Strict: true})
+
+		var errs domainErrors.ValidationErrors
+		if !stderrors.As(err, &errs) || len(errs) != 1 {
+			t.Fatalf("Expected one validation error, got %v", err)
+		}
+		if errs[0].Field != "external_reference" || errs[0].Code != domainErrors.ValidationCodeSensitiveData {
+			t.Errorf("Expected SENSITIVE_DATA for external_reference, got %+v", errs[0])
+		}
+		if saved != nil {
+			t.Error("Expected no transaction to be saved")
+		}
+	})
+}
+
+// TestCreditWalletUseCase_AmountMinor тестирует сумму в минорных единицах валюты кошелька
+func TestCreditWalletUseCase_AmountMinor(t *testing.T) {
+	run := func(t *testing.T, currency valueobjects.Currency, cmd dtos.CreditWalletCommand) (*entities.Transaction, error) {
+		walletID := uuid.New()
+		wallet := createTestWallet(walletID, uuid.New(), currency)
+		var savedTransaction *entities.Transaction
+		walletRepo := &mockWalletRepoForCredit{
+			findByIDFunc: func(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+				return wallet, nil
+			},
+		}
+		transactionRepo := &mockTransactionRepoForCredit{
+			saveFunc: func(ctx context.Context, tx *entities.Transaction) error {
+				savedTransaction = tx
+				return nil
+			},
+		}
+
+		useCase := NewCreditWalletUseCase(walletRepo, transactionRepo, &mockEventPublisherForWallet{}, &mockUoWForWallet{}, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil)
+		cmd.WalletID = walletID.String()
+		cmd.IdempotencyKey = uuid.New().String()
+		cmd.Description = "Test"
+		_, err := useCase.Execute(context.Background(), cmd)
+		return savedTransaction, err
+	}
+	minor := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name     string
+		currency valueobjects.Currency
+		minor    int64
+		want     string
+	}{
+		{"USD cents", valueobjects.USD, 10050, "100.50 USD"},
+		{"BTC satoshis", valueobjects.BTC, 150000000, "1.50000000 BTC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved, err := run(t, tt.currency, dtos.CreditWalletCommand{AmountMinor: minor(tt.minor)})
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if saved.Amount().String() != tt.want {
+				t.Errorf("Expected amount %s, got %s", tt.want, saved.Amount())
+			}
+		})
+	}
+
+	t.Run("Both amount and amount_minor", func(t *testing.T) {
+		saved, err := run(t, valueobjects.USD, dtos.CreditWalletCommand{Amount: "100.50", AmountMinor: minor(10050)})
+
+		var ve domainErrors.ValidationError
+		if !stderrors.As(err, &ve) || ve.Field != "amount_minor" || ve.Code != domainErrors.ValidationCodeInvalidValue {
+			t.Fatalf("Expected INVALID_VALUE for amount_minor, got %v", err)
+		}
+		if saved != nil {
+			t.Error("Expected no transaction to be saved")
+		}
+	})
+}
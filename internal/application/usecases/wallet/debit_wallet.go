@@ -10,7 +10,6 @@ import (
 	"github.com/Haleralex/wallethub/internal/domain/entities"
 	"github.com/Haleralex/wallethub/internal/domain/errors"
 	"github.com/Haleralex/wallethub/internal/domain/events"
-	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
 	"github.com/google/uuid"
 )
 
@@ -28,6 +27,14 @@ type DebitWalletUseCase struct {
 	transactionRepo ports.TransactionRepository
 	eventPublisher  ports.EventPublisher
 	uow             ports.UnitOfWork
+	walletLimiter   ports.WalletLimiter            // nil - без ограничения параллельности
+	screener        ports.TransactionScreener      // nil - без правил скрининга
+	buildInfo       ports.BuildInfo                // версия сборки для created_by_version
+	sensitiveData   ports.SensitiveDataPolicy      // PAN/IBAN в external reference: маскировать или отклонять
+	terms           ports.TermsGate                // nil - без проверки принятия ToS
+	kyc             ports.KYCGate                  // nil - без проверки KYC владельца
+	operations      ports.OperationGate            // nil - без аварийных выключателей
+	settings        ports.WalletSettingsRepository // nil - без автотегов кошелька
 }
 
 // NewDebitWalletUseCase создаёт новый use case.
@@ -36,20 +43,47 @@ func NewDebitWalletUseCase(
 	transactionRepo ports.TransactionRepository,
 	eventPublisher ports.EventPublisher,
 	uow ports.UnitOfWork,
+	walletLimiter ports.WalletLimiter,
+	screener ports.TransactionScreener,
+	buildInfo ports.BuildInfo,
+	sensitiveData ports.SensitiveDataPolicy,
+	terms ports.TermsGate,
+	kyc ports.KYCGate,
+	operations ports.OperationGate,
+	settings ports.WalletSettingsRepository,
 ) *DebitWalletUseCase {
 	return &DebitWalletUseCase{
 		walletRepo:      walletRepo,
 		transactionRepo: transactionRepo,
 		eventPublisher:  eventPublisher,
 		uow:             uow,
+		walletLimiter:   walletLimiter,
+		screener:        screener,
+		buildInfo:       buildInfo,
+		sensitiveData:   sensitiveData,
+		terms:           terms,
+		kyc:             kyc,
+		operations:      operations,
+		settings:        settings,
 	}
 }
 
 // Execute выполняет списание с кошелька.
 func (uc *DebitWalletUseCase) Execute(ctx context.Context, cmd dtos.DebitWalletCommand) (*dtos.WalletOperationDTO, error) {
+	if err := ports.RequireOperationEnabled(ctx, uc.operations, entities.TransactionTypeWithdraw); err != nil {
+		return nil, err
+	}
+
+	// Ограничиваем параллельные мутации кошелька до входа в UnitOfWork
+	release, err := ports.AcquireWallets(ctx, uc.walletLimiter, cmd.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	var result *dtos.WalletOperationDTO
 
-	err := uc.uow.Execute(ctx, func(txCtx context.Context) error {
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
 		// 1. Проверка идемпотентности
 		existingTx, err := uc.transactionRepo.FindByIdempotencyKey(txCtx, cmd.IdempotencyKey)
 		if err != nil && !errors.IsNotFound(err) {
@@ -57,12 +91,8 @@ func (uc *DebitWalletUseCase) Execute(ctx context.Context, cmd dtos.DebitWalletC
 		}
 
 		if existingTx != nil {
-			wallet, err := uc.walletRepo.FindByID(txCtx, existingTx.WalletID())
-			if err != nil {
-				return fmt.Errorf("failed to load wallet: %w", err)
-			}
-			result = uc.buildResult(wallet, existingTx)
-			return nil
+			result, err = uc.replay(txCtx, existingTx)
+			return err
 		}
 
 		// 2. Парсим wallet ID
@@ -71,6 +101,13 @@ func (uc *DebitWalletUseCase) Execute(ctx context.Context, cmd dtos.DebitWalletC
 			return errors.ValidationError{Field: "wallet_id", Message: "invalid UUID"}
 		}
 
+		// PAN/IBAN в ссылке: в строгом режиме запрос отклоняется до записи
+		var invalid errors.ValidationErrors
+		uc.sensitiveData.Check(&invalid, "external_reference", cmd.ExternalReference)
+		if err := invalid.Err(); err != nil {
+			return err
+		}
+
 		// 3. Загружаем кошелёк
 		wallet, err := uc.walletRepo.FindByID(txCtx, walletID)
 		if err != nil {
@@ -80,10 +117,17 @@ func (uc *DebitWalletUseCase) Execute(ctx context.Context, cmd dtos.DebitWalletC
 			return fmt.Errorf("failed to load wallet: %w", err)
 		}
 
+		// Владелец должен принять актуальную версию ToS
+		if uc.terms != nil {
+			if err := uc.terms.RequireAccepted(txCtx, wallet.UserID()); err != nil {
+				return err
+			}
+		}
+
 		// 4. Создаём Money
-		amountMoney, err := valueobjects.NewMoney(cmd.Amount, wallet.Currency())
+		amountMoney, err := dtos.ParseCommandAmount(cmd.Amount, cmd.AmountMinor, wallet.Currency())
 		if err != nil {
-			return errors.ValidationError{Field: "amount", Message: fmt.Sprintf("invalid amount: %v", err)}
+			return err
 		}
 
 		// 5. Создаём Transaction entity
@@ -98,12 +142,42 @@ func (uc *DebitWalletUseCase) Execute(ctx context.Context, cmd dtos.DebitWalletC
 			return fmt.Errorf("failed to create transaction entity: %w", err)
 		}
 
+		// Тег хранения копируется с исходного кошелька и дальше не меняется
+		if err := transaction.AssignJurisdiction(wallet.Jurisdiction()); err != nil {
+			return fmt.Errorf("failed to assign jurisdiction: %w", err)
+		}
+
+		if err := transaction.StampCreatedByVersion(uc.buildInfo.ProducerVersion()); err != nil {
+			return fmt.Errorf("failed to stamp build version: %w", err)
+		}
+
 		if cmd.ExternalReference != "" {
 			if err := transaction.SetExternalReference(cmd.ExternalReference); err != nil {
 				return fmt.Errorf("failed to set external reference: %w", err)
 			}
 		}
 
+		// Автотеги кошелька до скрининга: правила видят итоговую metadata
+		walletSettings, err := ports.LoadWalletSettings(txCtx, uc.settings, walletID)
+		if err != nil {
+			return err
+		}
+		if err := walletSettings.TagTransaction(transaction); err != nil {
+			return fmt.Errorf("failed to tag transaction: %w", err)
+		}
+
+		// KYC владельца мог быть отозван после открытия кошелька
+		kycEvents, err := ports.CheckOwnerKYC(txCtx, uc.kyc, transaction, wallet)
+		if err != nil {
+			return err
+		}
+
+		// Скрининг до списания: BLOCK откатывает UnitOfWork
+		screeningEvents, err := ports.ScreenTransaction(txCtx, uc.screener, transaction, wallet)
+		if err != nil {
+			return err
+		}
+
 		// 6. Применяем Debit к кошельку
 		if err := wallet.Debit(amountMoney); err != nil {
 			return fmt.Errorf("failed to debit wallet: %w", err)
@@ -138,6 +212,7 @@ func (uc *DebitWalletUseCase) Execute(ctx context.Context, cmd dtos.DebitWalletC
 			events.NewTransactionCreated(
 				transaction.ID(),
 				walletID,
+				wallet.UserID(),
 				string(entities.TransactionTypeWithdraw),
 				amountMoney,
 				cmd.IdempotencyKey,
@@ -147,14 +222,18 @@ func (uc *DebitWalletUseCase) Execute(ctx context.Context, cmd dtos.DebitWalletC
 				amountMoney,
 				transaction.ID(),
 				wallet.AvailableBalance(),
+				wallet.BalanceVersion(),
 			),
 			events.NewTransactionCompleted(
 				transaction.ID(),
 				walletID,
+				wallet.UserID(),
 				string(entities.TransactionTypeWithdraw),
 				amountMoney,
-			),
+			).WithReceiptNumber(transaction.ReceiptNumber()),
 		}
+		eventList = append(eventList, kycEvents...)
+		eventList = append(eventList, screeningEvents...)
 
 		if err := uc.eventPublisher.PublishBatch(txCtx, eventList); err != nil {
 			return fmt.Errorf("failed to publish events: %w", err)
@@ -165,12 +244,32 @@ func (uc *DebitWalletUseCase) Execute(ctx context.Context, cmd dtos.DebitWalletC
 	})
 
 	if err != nil {
+		// Гонка по idempotency_key - отвечаем как на повтор
+		if errors.IsDuplicateTransaction(err) {
+			existingTx, findErr := uc.transactionRepo.FindByIdempotencyKey(ctx, cmd.IdempotencyKey)
+			if findErr != nil {
+				return nil, err
+			}
+			return uc.replay(ctx, existingTx)
+		}
 		return nil, err
 	}
 
 	return result, nil
 }
 
+// replay строит результат для уже обработанного idempotency_key.
+func (uc *DebitWalletUseCase) replay(ctx context.Context, existingTx *entities.Transaction) (*dtos.WalletOperationDTO, error) {
+	wallet, err := uc.walletRepo.FindByID(ctx, existingTx.WalletID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wallet: %w", err)
+	}
+
+	result := uc.buildResult(wallet, existingTx)
+	result.IdempotentReplay = true
+	return result, nil
+}
+
 func (uc *DebitWalletUseCase) buildResult(wallet *entities.Wallet, tx *entities.Transaction) *dtos.WalletOperationDTO {
 	totalBalance, _ := wallet.TotalBalance()
 
@@ -186,10 +285,14 @@ func (uc *DebitWalletUseCase) buildResult(wallet *entities.Wallet, tx *entities.
 			TotalBalance:     totalBalance.String(),
 			DailyLimit:       wallet.DailyLimit().String(),
 			MonthlyLimit:     wallet.MonthlyLimit().String(),
+			BalanceVersion:   wallet.BalanceVersion(),
+			Jurisdiction:     wallet.Jurisdiction(),
 			CreatedAt:        wallet.CreatedAt(),
 			UpdatedAt:        wallet.UpdatedAt(),
 		},
+		Transaction:   dtos.ToTransactionDTO(tx),
 		TransactionID: tx.ID().String(),
 		Message:       fmt.Sprintf("Wallet debited with %s successfully", tx.Amount().String()),
+		CreatedAt:     tx.CreatedAt(),
 	}
 }
@@ -0,0 +1,87 @@
+// Package wallet - пересчёт балансов в валюту отображения кошелька.
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// displayBalance - баланс кошелька и куда положить его пересчёт.
+type displayBalance struct {
+	walletID  uuid.UUID
+	balance   valueobjects.Money
+	converted **dtos.ConvertedBalanceDTO
+}
+
+// attachConvertedBalances пересчитывает балансы в валюты отображения
+// кошельков. Настройки всех кошельков читаются одним запросом, курс каждой
+// пары валют запрашивается один раз. Кошелёк без валюты отображения, с
+// валютой, совпадающей со своей, или без курса остаётся без пересчёта.
+func attachConvertedBalances(ctx context.Context, display ports.DisplayCurrency, balances []displayBalance) error {
+	if !display.Enabled() || len(balances) == 0 {
+		return nil
+	}
+
+	walletIDs := make([]uuid.UUID, len(balances))
+	for i, b := range balances {
+		walletIDs[i] = b.walletID
+	}
+	settings, err := display.Settings.FindByWalletIDs(ctx, walletIDs)
+	if err != nil {
+		return fmt.Errorf("failed to load wallet settings: %w", err)
+	}
+
+	pairs := make([]ports.CurrencyPair, len(balances))
+	wanted := make([]ports.CurrencyPair, 0, len(balances))
+	for i, b := range balances {
+		target := settings[b.walletID].DisplayCurrency()
+		if target == "" || target == b.balance.Currency().Code() {
+			continue
+		}
+		pairs[i] = ports.CurrencyPair{From: b.balance.Currency().Code(), To: target}
+		wanted = append(wanted, pairs[i])
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	quotes := display.Rates.Quotes(ctx, wanted)
+	for i, b := range balances {
+		quote, ok := quotes[pairs[i]]
+		if !ok {
+			continue
+		}
+		*b.converted = convertForDisplay(b.balance, pairs[i].To, quote)
+	}
+	return nil
+}
+
+// convertForDisplay пересчитывает баланс по курсу с округлением до
+// минимальной единицы валюты отображения. nil - пересчёт невозможен.
+func convertForDisplay(balance valueobjects.Money, target string, quote *ports.RateQuote) *dtos.ConvertedBalanceDTO {
+	currency, err := valueobjects.NewCurrency(target)
+	if err != nil {
+		return nil
+	}
+
+	amount := new(big.Rat).Mul(balance.Amount(), quote.Rate)
+	converted, err := valueobjects.NewMoney(amount.FloatString(currency.Decimals()), currency)
+	if err != nil {
+		return nil
+	}
+
+	return &dtos.ConvertedBalanceDTO{
+		Currency:      currency.Code(),
+		Amount:        converted.String(),
+		Rate:          quote.Rate.FloatString(8),
+		RateFetchedAt: quote.FetchedAt.UTC(),
+		Approximate:   true,
+	}
+}
@@ -0,0 +1,281 @@
+package wallet_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/displayfx"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+var rateFetchedAt = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+// countingRateProvider отдаёт фиксированные курсы и считает обращения по парам.
+type countingRateProvider struct {
+	mu    sync.Mutex
+	rates map[ports.CurrencyPair]*big.Rat
+	calls map[ports.CurrencyPair]int
+}
+
+func newCountingRateProvider(rates map[ports.CurrencyPair]*big.Rat) *countingRateProvider {
+	return &countingRateProvider{rates: rates, calls: make(map[ports.CurrencyPair]int)}
+}
+
+func (p *countingRateProvider) GetRate(ctx context.Context, from, to string) (*big.Rat, error) {
+	quote, err := p.Quote(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return quote.Rate, nil
+}
+
+func (p *countingRateProvider) Quote(_ context.Context, from, to string) (*ports.RateQuote, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pair := ports.CurrencyPair{From: from, To: to}
+	p.calls[pair]++
+	rate, ok := p.rates[pair]
+	if !ok {
+		return nil, errors.New("rate unavailable")
+	}
+	return &ports.RateQuote{Rate: rate, Source: "test", FetchedAt: rateFetchedAt}, nil
+}
+
+func (p *countingRateProvider) callsFor(from, to string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls[ports.CurrencyPair{From: from, To: to}]
+}
+
+// displayFixture - кошельки в memory store и настройки их валют отображения.
+type displayFixture struct {
+	store    *memory.Store
+	wallets  *memory.WalletRepository
+	settings *memory.WalletSettingsRepository
+	provider *countingRateProvider
+	display  ports.DisplayCurrency
+}
+
+func newDisplayFixture(t *testing.T) *displayFixture {
+	t.Helper()
+
+	store := memory.NewStore()
+	provider := newCountingRateProvider(map[ports.CurrencyPair]*big.Rat{
+		{From: "BTC", To: "USD"}: big.NewRat(40000, 1),
+		{From: "ETH", To: "USD"}: big.NewRat(2000, 1),
+		{From: "USD", To: "EUR"}: big.NewRat(92, 100),
+	})
+	f := &displayFixture{
+		store:    store,
+		wallets:  memory.NewWalletRepository(store),
+		settings: memory.NewWalletSettingsRepository(store),
+		provider: provider,
+	}
+	f.display = ports.DisplayCurrency{
+		Settings: f.settings,
+		Rates:    displayfx.NewRates(provider, displayfx.Config{CacheTTL: time.Minute}),
+	}
+	return f
+}
+
+func (f *displayFixture) user(t *testing.T) *entities.User {
+	t.Helper()
+	owner, err := entities.NewUser("display-"+uuid.NewString()+"@example.com", "Display Test")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := memory.NewUserRepository(f.store).Save(context.Background(), owner); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+	return owner
+}
+
+// wallet создаёт кошелёк с балансом amount и валютой отображения display ("" - без неё).
+func (f *displayFixture) wallet(t *testing.T, owner *entities.User, currency, amount, display string) *entities.Wallet {
+	t.Helper()
+	ctx := context.Background()
+
+	w, err := entities.NewWallet(owner.ID(), valueobjects.MustNewCurrency(currency))
+	if err != nil {
+		t.Fatalf("NewWallet() error = %v", err)
+	}
+	if err := f.wallets.Save(ctx, w); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+	money, err := valueobjects.NewMoney(amount, w.Currency())
+	if err != nil {
+		t.Fatalf("NewMoney() error = %v", err)
+	}
+	if err := w.Credit(money); err != nil {
+		t.Fatalf("Credit() error = %v", err)
+	}
+	if err := f.wallets.Save(ctx, w); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+
+	if display != "" {
+		settings, err := entities.NewWalletSettings(w.ID(), "", nil, display, owner.ID())
+		if err != nil {
+			t.Fatalf("NewWalletSettings() error = %v", err)
+		}
+		if err := f.settings.Save(ctx, settings); err != nil {
+			t.Fatalf("save settings error = %v", err)
+		}
+	}
+	return w
+}
+
+func TestDisplayCurrency_GetWallet(t *testing.T) {
+	f := newDisplayFixture(t)
+	owner := f.user(t)
+	btc := f.wallet(t, owner, "BTC", "0.5", "USD")
+
+	got, err := wallet.NewGetWalletUseCase(f.wallets, nil, ports.PendingTransactionsPolicy{}, ports.MetadataQuota{}, f.display).
+		Execute(context.Background(), dtos.GetWalletQuery{WalletID: btc.ID().String()})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := dtos.ConvertedBalanceDTO{
+		Currency:      "USD",
+		Amount:        "20000.00 USD",
+		Rate:          "40000.00000000",
+		RateFetchedAt: rateFetchedAt,
+		Approximate:   true,
+	}
+	if got.Converted == nil || *got.Converted != want {
+		t.Fatalf("Converted = %+v, want %+v", got.Converted, want)
+	}
+}
+
+func TestDisplayCurrency_GetWalletBalance(t *testing.T) {
+	f := newDisplayFixture(t)
+	owner := f.user(t)
+	usd := f.wallet(t, owner, "USD", "100.50", "EUR")
+
+	got, err := wallet.NewGetWalletBalanceUseCase(f.wallets, f.display).
+		Execute(context.Background(), dtos.GetWalletBalanceQuery{WalletID: usd.ID().String()})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// 100.50 * 0.92 = 92.46
+	if got.Converted == nil || got.Converted.Amount != "92.46 EUR" {
+		t.Fatalf("Converted = %+v, want 92.46 EUR", got.Converted)
+	}
+}
+
+func TestDisplayCurrency_ListWalletsMixedPreferences(t *testing.T) {
+	f := newDisplayFixture(t)
+	ctx := context.Background()
+
+	first, second := f.user(t), f.user(t)
+	btc1 := f.wallet(t, first, "BTC", "1", "USD")
+	eth := f.wallet(t, first, "ETH", "2", "USD")
+	plain := f.wallet(t, first, "EUR", "10", "")
+	same := f.wallet(t, first, "USD", "10", "USD")
+	noRate := f.wallet(t, first, "USDT", "10", "GBP")
+	btc2 := f.wallet(t, second, "BTC", "0.25", "USD")
+
+	list := wallet.NewListWalletsUseCase(f.wallets, f.display)
+	page, err := list.Execute(ctx, dtos.ListWalletsQuery{Limit: 100})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	converted := make(map[string]*dtos.ConvertedBalanceDTO, len(page.Data))
+	for _, w := range page.Data {
+		converted[w.ID] = w.Converted
+	}
+	if len(converted) != 6 {
+		t.Fatalf("listed %d wallets, want 6", len(converted))
+	}
+
+	for id, wantAmount := range map[uuid.UUID]string{
+		btc1.ID(): "40000.00 USD",
+		eth.ID():  "4000.00 USD",
+		btc2.ID(): "10000.00 USD",
+	} {
+		if c := converted[id.String()]; c == nil || c.Amount != wantAmount {
+			t.Errorf("wallet %s: Converted = %+v, want %s", id, c, wantAmount)
+		}
+	}
+	// Без валюты отображения, со своей валютой и без курса - блока нет
+	for _, id := range []uuid.UUID{plain.ID(), same.ID(), noRate.ID()} {
+		if c := converted[id.String()]; c != nil {
+			t.Errorf("wallet %s: Converted = %+v, want omitted", id, c)
+		}
+	}
+
+	// Два BTC кошелька - один запрос курса
+	if n := f.provider.callsFor("BTC", "USD"); n != 1 {
+		t.Errorf("BTC/USD provider calls = %d, want 1", n)
+	}
+	if n := f.provider.callsFor("USD", "USD"); n != 0 {
+		t.Errorf("USD/USD provider calls = %d, want 0", n)
+	}
+
+	// Повторный список берёт курсы из кэша; пара без курса не кэшируется
+	if _, err := list.Execute(ctx, dtos.ListWalletsQuery{Limit: 100}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if n := f.provider.callsFor("BTC", "USD"); n != 1 {
+		t.Errorf("BTC/USD provider calls after cache hit = %d, want 1", n)
+	}
+	if n := f.provider.callsFor("ETH", "USD"); n != 1 {
+		t.Errorf("ETH/USD provider calls after cache hit = %d, want 1", n)
+	}
+	if n := f.provider.callsFor("USDT", "GBP"); n != 2 {
+		t.Errorf("USDT/GBP provider calls = %d, want 2 (failures are not cached)", n)
+	}
+}
+
+func TestDisplayCurrency_Disabled(t *testing.T) {
+	f := newDisplayFixture(t)
+	btc := f.wallet(t, f.user(t), "BTC", "1", "USD")
+
+	got, err := wallet.NewGetWalletUseCase(f.wallets, nil, ports.PendingTransactionsPolicy{}, ports.MetadataQuota{}, ports.DisplayCurrency{}).
+		Execute(context.Background(), dtos.GetWalletQuery{WalletID: btc.ID().String()})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got.Converted != nil {
+		t.Errorf("Converted = %+v, want nil without DisplayCurrency", got.Converted)
+	}
+}
+
+func TestUpdateWalletSettings_DisplayCurrency(t *testing.T) {
+	f := newDisplayFixture(t)
+	owner := f.user(t)
+	btc := f.wallet(t, owner, "BTC", "1", "")
+
+	update := wallet.NewUpdateWalletSettingsUseCase(f.wallets, f.settings, memory.NewUnitOfWork(f.store))
+	ctx := ports.WithActor(context.Background(), ports.Actor{ID: owner.ID(), Role: "user"})
+
+	_, err := update.Execute(ctx, dtos.UpdateWalletSettingsCommand{WalletID: btc.ID().String(), DisplayCurrency: "XYZ"})
+	var validation domainErrors.ValidationError
+	if !errors.As(err, &validation) || validation.Field != "display_currency" {
+		t.Fatalf("Execute() error = %v, want display_currency ValidationError", err)
+	}
+
+	result, err := update.Execute(ctx, dtos.UpdateWalletSettingsCommand{WalletID: btc.ID().String(), DisplayCurrency: "usd"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.DisplayCurrency != "USD" {
+		t.Errorf("DisplayCurrency = %q, want USD", result.DisplayCurrency)
+	}
+}
@@ -0,0 +1,317 @@
+// Package wallet - EnsureWallet use case: идемпотентное "кошелёк должен существовать".
+package wallet
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/google/uuid"
+)
+
+// PendingCapOverrideForbiddenCode - override лимита незавершённых транзакций
+// запрошен не администратором.
+const PendingCapOverrideForbiddenCode = "PENDING_CAP_OVERRIDE_FORBIDDEN"
+
+// ensureWalletMaxAttempts - сколько раз повторяем ensure после гонки
+// с параллельным вызовом (WALLET_ALREADY_EXISTS или конфликт версий).
+const ensureWalletMaxAttempts = 3
+
+// EnsureWalletUseCase - use case для PUT /users/:id/wallets/:currency.
+//
+// Приводит кошелёк пользователя в валюте к желаемому состоянию:
+// - кошелька нет - создаёт (те же KYC правила, что у CreateWallet)
+// - лимиты отличаются от запрошенных - обновляет их
+// - override лимита незавершённых транзакций отличается - обновляет его
+// - всё совпадает - ничего не делает
+//
+// Override лимита незавершённых транзакций защищает баланс от интегратора,
+// поэтому задать его может только администратор (ports.ActorFromContext).
+//
+// Существующий кошелёк ошибкой не считается. Чтение и запись идут в одной
+// UnitOfWork под optimistic lock; проигравший гонку параллельный вызов
+// (unique user+currency или устаревшая версия) повторяется целиком и
+// видит уже созданный кошелёк, поэтому одновременные ensure сходятся
+// к одному кошельку. События публикуются только при реальных изменениях.
+type EnsureWalletUseCase struct {
+	userRepo       ports.UserRepository
+	walletRepo     ports.WalletRepository
+	eventPublisher ports.EventPublisher
+	uow            ports.UnitOfWork
+	currencyPolicy ports.CurrencyPolicySource
+}
+
+// NewEnsureWalletUseCase создаёт новый use case.
+func NewEnsureWalletUseCase(
+	userRepo ports.UserRepository,
+	walletRepo ports.WalletRepository,
+	eventPublisher ports.EventPublisher,
+	uow ports.UnitOfWork,
+	currencyPolicy ports.CurrencyPolicySource, // nil - все поддерживаемые валюты
+) *EnsureWalletUseCase {
+	return &EnsureWalletUseCase{
+		userRepo:       userRepo,
+		walletRepo:     walletRepo,
+		eventPublisher: eventPublisher,
+		uow:            uow,
+		currencyPolicy: currencyPolicy,
+	}
+}
+
+// ensureWalletSpec - разобранная команда.
+type ensureWalletSpec struct {
+	userID       uuid.UUID
+	currency     valueobjects.Currency
+	dailyLimit   *valueobjects.Money // nil - лимит не управляется
+	monthlyLimit *valueobjects.Money
+
+	managesPendingCap bool // override лимита незавершённых транзакций задан
+	maxPending        *int // nil - снять override
+}
+
+// Execute выполняет ensure кошелька.
+func (uc *EnsureWalletUseCase) Execute(ctx context.Context, cmd dtos.EnsureWalletCommand) (*dtos.EnsureWalletResultDTO, error) {
+	spec, err := parseEnsureWalletCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if spec.managesPendingCap {
+		if actor, ok := ports.ActorFromContext(ctx); !ok || !actor.IsAdmin() {
+			return nil, errors.NewDomainError(PendingCapOverrideForbiddenCode, "only admins can override max_pending_transactions", nil)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		result, err := uc.ensureOnce(ctx, spec)
+		if err == nil || !isEnsureRace(err) || attempt == ensureWalletMaxAttempts {
+			return result, err
+		}
+		ports.RequestStatsFromContext(ctx).RecordLockRetry()
+	}
+}
+
+// parseEnsureWalletCommand проверяет команду до обращения к хранилищу: все ошибки сразу.
+func parseEnsureWalletCommand(cmd dtos.EnsureWalletCommand) (ensureWalletSpec, error) {
+	var (
+		spec    ensureWalletSpec
+		invalid errors.ValidationErrors
+		err     error
+	)
+
+	spec.userID, err = uuid.Parse(cmd.UserID)
+	if err != nil {
+		invalid.AddCode("user_id", errors.ValidationCodeInvalidFormat, "invalid UUID format")
+	}
+	spec.currency, err = valueobjects.NewCurrency(cmd.CurrencyCode)
+	if err != nil {
+		invalid.AddCode("currency_code", errors.ValidationCodeInvalidValue, fmt.Sprintf("invalid currency: %v", err))
+	}
+
+	parseLimit := func(field, value string) *valueobjects.Money {
+		if value == "" {
+			return nil
+		}
+		limit, err := valueobjects.NewMoney(value, spec.currency)
+		if err != nil {
+			invalid.AddCode(field, errors.ValidationCodeInvalidFormat, fmt.Sprintf("invalid amount: %v", err))
+			return nil
+		}
+		if !limit.IsPositive() {
+			invalid.AddCode(field, errors.ValidationCodeOutOfRange, "limit must be positive")
+			return nil
+		}
+		return &limit
+	}
+	spec.dailyLimit = parseLimit("daily_limit", cmd.DailyLimit)
+	spec.monthlyLimit = parseLimit("monthly_limit", cmd.MonthlyLimit)
+
+	if max := cmd.MaxPendingTransactions; max != nil {
+		spec.managesPendingCap = true
+		switch {
+		case *max < 0:
+			invalid.AddCode("max_pending_transactions", errors.ValidationCodeOutOfRange, "must not be negative")
+		case *max > 0:
+			value := *max
+			spec.maxPending = &value
+		}
+	}
+
+	return spec, invalid.Err()
+}
+
+// ensureOnce - одна попытка ensure в отдельной UnitOfWork.
+func (uc *EnsureWalletUseCase) ensureOnce(ctx context.Context, spec ensureWalletSpec) (*dtos.EnsureWalletResultDTO, error) {
+	var result *dtos.EnsureWalletResultDTO
+
+	err := uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		wallet, err := uc.walletRepo.FindByUserAndCurrency(txCtx, spec.userID, spec.currency)
+		switch {
+		case err == nil:
+			result, err = uc.update(txCtx, wallet, spec)
+		case errors.IsNotFound(err):
+			result, err = uc.create(txCtx, spec)
+		default:
+			err = fmt.Errorf("failed to load wallet: %w", err)
+		}
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// create создаёт кошелёк с запрошенными лимитами. Закрытая для новых
+// кошельков валюта не мешает ensure уже существующего.
+func (uc *EnsureWalletUseCase) create(ctx context.Context, spec ensureWalletSpec) (*dtos.EnsureWalletResultDTO, error) {
+	if uc.currencyPolicy != nil {
+		if err := uc.currencyPolicy.Current().CheckWallet(spec.currency); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, spec.userID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NewDomainError("USER_NOT_FOUND", "user not found", err)
+		}
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	if err := user.CanCreateWallet(); err != nil {
+		return nil, err
+	}
+
+	wallet, err := entities.NewWallet(spec.userID, spec.currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wallet entity: %w", err)
+	}
+	if err := wallet.AssignJurisdiction(user.Jurisdiction()); err != nil {
+		return nil, fmt.Errorf("failed to assign jurisdiction: %w", err)
+	}
+
+	// Save с версией 0 - INSERT, поэтому кошелёк сначала сохраняется
+	// с лимитами по умолчанию, а запрошенные применяются обычным UPDATE
+	if err := uc.walletRepo.Save(ctx, wallet); err != nil {
+		return nil, fmt.Errorf("failed to save wallet: %w", err)
+	}
+	if err := uc.eventPublisher.Publish(ctx, events.NewWalletCreated(wallet.ID(), spec.userID, spec.currency)); err != nil {
+		return nil, fmt.Errorf("failed to publish WalletCreated event: %w", err)
+	}
+
+	if _, err := uc.applyLimits(ctx, wallet, spec); err != nil {
+		return nil, err
+	}
+	if _, err := uc.applyPendingCap(ctx, wallet, spec); err != nil {
+		return nil, err
+	}
+
+	return &dtos.EnsureWalletResultDTO{
+		Wallet:        dtos.ToWalletDTO(wallet),
+		Created:       true,
+		Changed:       true,
+		ChangedFields: []string{},
+	}, nil
+}
+
+// update приводит лимиты существующего кошелька к запрошенным.
+func (uc *EnsureWalletUseCase) update(ctx context.Context, wallet *entities.Wallet, spec ensureWalletSpec) (*dtos.EnsureWalletResultDTO, error) {
+	if wallet.Status() == entities.WalletStatusClosed {
+		return nil, errors.NewBusinessRuleViolation(
+			"WALLET_CLOSED",
+			"wallet is closed",
+			map[string]interface{}{"wallet_id": wallet.ID().String()},
+		)
+	}
+
+	changed, err := uc.applyLimits(ctx, wallet, spec)
+	if err != nil {
+		return nil, err
+	}
+	capChanged, err := uc.applyPendingCap(ctx, wallet, spec)
+	if err != nil {
+		return nil, err
+	}
+	if capChanged {
+		changed = append(changed, dtos.EnsureWalletFieldMaxPendingTransactions)
+	}
+
+	return &dtos.EnsureWalletResultDTO{
+		Wallet:        dtos.ToWalletDTO(wallet),
+		Changed:       len(changed) > 0,
+		ChangedFields: changed,
+	}, nil
+}
+
+// applyLimits меняет и сохраняет отличающиеся лимиты, публикуя WalletLimitsUpdated.
+// Возвращает изменённые поля; совпадающие лимиты кошелёк не трогают.
+func (uc *EnsureWalletUseCase) applyLimits(ctx context.Context, wallet *entities.Wallet, spec ensureWalletSpec) ([]string, error) {
+	changed := []string{}
+	daily, monthly := wallet.DailyLimit(), wallet.MonthlyLimit()
+
+	if spec.dailyLimit != nil && !spec.dailyLimit.Equals(daily) {
+		daily = *spec.dailyLimit
+		changed = append(changed, dtos.EnsureWalletFieldDailyLimit)
+	}
+	if spec.monthlyLimit != nil && !spec.monthlyLimit.Equals(monthly) {
+		monthly = *spec.monthlyLimit
+		changed = append(changed, dtos.EnsureWalletFieldMonthlyLimit)
+	}
+	if len(changed) == 0 {
+		return changed, nil
+	}
+
+	if err := wallet.UpdateLimits(daily, monthly); err != nil {
+		return nil, err
+	}
+	if err := uc.walletRepo.Save(ctx, wallet); err != nil {
+		return nil, fmt.Errorf("failed to save wallet: %w", err)
+	}
+	if err := uc.eventPublisher.Publish(ctx, events.NewWalletLimitsUpdated(wallet.ID(), daily, monthly)); err != nil {
+		return nil, fmt.Errorf("failed to publish WalletLimitsUpdated event: %w", err)
+	}
+
+	return changed, nil
+}
+
+// applyPendingCap меняет и сохраняет override лимита незавершённых транзакций.
+// Сохраняется отдельно от лимитов: каждое изменение кошелька увеличивает
+// версию на один, а Save ожидает ровно одно изменение.
+func (uc *EnsureWalletUseCase) applyPendingCap(ctx context.Context, wallet *entities.Wallet, spec ensureWalletSpec) (bool, error) {
+	if !spec.managesPendingCap || equalIntPtr(wallet.MaxPendingTransactions(), spec.maxPending) {
+		return false, nil
+	}
+
+	if err := wallet.SetMaxPendingTransactions(spec.maxPending); err != nil {
+		return false, err
+	}
+	if err := uc.walletRepo.Save(ctx, wallet); err != nil {
+		return false, fmt.Errorf("failed to save wallet: %w", err)
+	}
+	return true, nil
+}
+
+// equalIntPtr сравнивает необязательные значения.
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// isEnsureRace сообщает, что попытку опередил параллельный ensure.
+func isEnsureRace(err error) bool {
+	if errors.IsConcurrencyError(err) {
+		return true
+	}
+	var brv *errors.BusinessRuleViolation
+	return stderrors.As(err, &brv) && brv.Rule == "WALLET_ALREADY_EXISTS"
+}
@@ -0,0 +1,359 @@
+package wallet_test
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/google/uuid"
+)
+
+// ensureFixture - хранилище с пользователем без кошельков (NewUser создаёт verified).
+type ensureFixture struct {
+	store     *memory.Store
+	users     *memory.UserRepository
+	wallets   *memory.WalletRepository
+	publisher *memory.EventPublisher
+	user      *entities.User
+}
+
+func newEnsureFixture(t *testing.T) *ensureFixture {
+	t.Helper()
+
+	store := memory.NewStore()
+	f := &ensureFixture{
+		store:     store,
+		users:     memory.NewUserRepository(store),
+		wallets:   memory.NewWalletRepository(store),
+		publisher: memory.NewEventPublisher(store),
+	}
+
+	user, err := entities.NewUser("ensure-"+uuid.NewString()+"@example.com", "Ensure Test")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := f.users.Save(context.Background(), user); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+	f.user = user
+
+	return f
+}
+
+func (f *ensureFixture) useCase(walletRepo ports.WalletRepository, uow ports.UnitOfWork) *wallet.EnsureWalletUseCase {
+	return wallet.NewEnsureWalletUseCase(f.users, walletRepo, f.publisher, uow, nil)
+}
+
+func (f *ensureFixture) command(daily, monthly string) dtos.EnsureWalletCommand {
+	return dtos.EnsureWalletCommand{
+		UserID:       f.user.ID().String(),
+		CurrencyCode: "USD",
+		DailyLimit:   daily,
+		MonthlyLimit: monthly,
+	}
+}
+
+func (f *ensureFixture) eventTypes() []string {
+	var types []string
+	for _, e := range f.publisher.Events() {
+		types = append(types, e.EventType())
+	}
+	return types
+}
+
+func (f *ensureFixture) walletCount(t *testing.T) int {
+	t.Helper()
+	wallets, err := f.wallets.FindByUserID(context.Background(), f.user.ID())
+	if err != nil {
+		t.Fatalf("FindByUserID() error = %v", err)
+	}
+	return len(wallets)
+}
+
+func TestEnsureWalletUseCase_Create(t *testing.T) {
+	f := newEnsureFixture(t)
+
+	result, err := f.useCase(f.wallets, memory.NewUnitOfWork(f.store)).Execute(context.Background(), f.command("500", ""))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if !result.Created || !result.Changed {
+		t.Errorf("Expected created and changed, got created=%v changed=%v", result.Created, result.Changed)
+	}
+	if result.Wallet.DailyLimit != "500.00 USD" {
+		t.Errorf("Expected requested daily limit, got %s", result.Wallet.DailyLimit)
+	}
+	if result.Wallet.MonthlyLimit != "10000.00 USD" {
+		t.Errorf("Expected default monthly limit, got %s", result.Wallet.MonthlyLimit)
+	}
+
+	stored, err := f.wallets.FindByUserAndCurrency(context.Background(), f.user.ID(), valueobjects.USD)
+	if err != nil {
+		t.Fatalf("FindByUserAndCurrency() error = %v", err)
+	}
+	if stored.ID().String() != result.Wallet.ID || stored.DailyLimit().String() != "500.00 USD" {
+		t.Errorf("Stored wallet does not match result: %s %s", stored.ID(), stored.DailyLimit().String())
+	}
+
+	types := f.eventTypes()
+	if len(types) != 2 || types[0] != events.EventTypeWalletCreated || types[1] != events.EventTypeWalletLimitsUpdated {
+		t.Errorf("Expected WalletCreated and WalletLimitsUpdated events, got %v", types)
+	}
+}
+
+func TestEnsureWalletUseCase_UnverifiedUser(t *testing.T) {
+	f := newEnsureFixture(t)
+
+	user := entities.ReconstructUser(
+		uuid.New(), "ensure-unverified@example.com", "Unverified", entities.KYCStatusUnverified,
+		nil, "", "", entities.TermsAcceptance{}, time.Now(), time.Now(),
+	)
+	if err := f.users.Save(context.Background(), user); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+
+	cmd := f.command("", "")
+	cmd.UserID = user.ID().String()
+	if _, err := f.useCase(f.wallets, memory.NewUnitOfWork(f.store)).Execute(context.Background(), cmd); err == nil {
+		t.Fatal("Expected KYC error for unverified user")
+	}
+	if types := f.eventTypes(); len(types) != 0 {
+		t.Errorf("Expected no events, got %v", types)
+	}
+}
+
+func TestEnsureWalletUseCase_NoOp(t *testing.T) {
+	f := newEnsureFixture(t)
+	uc := f.useCase(f.wallets, memory.NewUnitOfWork(f.store))
+
+	first, err := uc.Execute(context.Background(), f.command("500.00", "5000.00"))
+	if err != nil {
+		t.Fatalf("first Execute() error = %v", err)
+	}
+	eventsBefore := len(f.publisher.Events())
+
+	// Те же лимиты в другой записи - изменений нет
+	result, err := uc.Execute(context.Background(), f.command("500", "5000.0"))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.Created || result.Changed || len(result.ChangedFields) != 0 {
+		t.Errorf("Expected no-op, got created=%v changed=%v fields=%v", result.Created, result.Changed, result.ChangedFields)
+	}
+	if result.Wallet.ID != first.Wallet.ID {
+		t.Errorf("Expected existing wallet %s, got %s", first.Wallet.ID, result.Wallet.ID)
+	}
+	if result.Wallet.BalanceVersion != first.Wallet.BalanceVersion {
+		t.Errorf("Expected untouched version %d, got %d", first.Wallet.BalanceVersion, result.Wallet.BalanceVersion)
+	}
+	if got := len(f.publisher.Events()); got != eventsBefore {
+		t.Errorf("Expected no new events, got %d", got-eventsBefore)
+	}
+}
+
+func TestEnsureWalletUseCase_UpdateLimits(t *testing.T) {
+	f := newEnsureFixture(t)
+	uc := f.useCase(f.wallets, memory.NewUnitOfWork(f.store))
+
+	if _, err := uc.Execute(context.Background(), f.command("", "")); err != nil {
+		t.Fatalf("first Execute() error = %v", err)
+	}
+
+	result, err := uc.Execute(context.Background(), f.command("10000", "2500.50"))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.Created || !result.Changed {
+		t.Errorf("Expected update, got created=%v changed=%v", result.Created, result.Changed)
+	}
+	if len(result.ChangedFields) != 1 || result.ChangedFields[0] != dtos.EnsureWalletFieldMonthlyLimit {
+		t.Errorf("Expected only monthly_limit changed, got %v", result.ChangedFields)
+	}
+	if result.Wallet.MonthlyLimit != "2500.50 USD" {
+		t.Errorf("Expected monthly limit 2500.50 USD, got %s", result.Wallet.MonthlyLimit)
+	}
+
+	var updated *events.WalletLimitsUpdated
+	for _, e := range f.publisher.Events() {
+		if u, ok := e.(*events.WalletLimitsUpdated); ok {
+			updated = u
+		}
+	}
+	if updated == nil || updated.MonthlyLimit.String() != "2500.50 USD" {
+		t.Errorf("Expected WalletLimitsUpdated with new monthly limit, got %+v", updated)
+	}
+}
+
+func TestEnsureWalletUseCase_MaxPendingTransactions(t *testing.T) {
+	f := newEnsureFixture(t)
+	uc := f.useCase(f.wallets, memory.NewUnitOfWork(f.store))
+	withCap := func(max int) dtos.EnsureWalletCommand {
+		cmd := f.command("", "")
+		cmd.MaxPendingTransactions = &max
+		return cmd
+	}
+	admin := ports.WithActor(context.Background(), ports.Actor{ID: uuid.New(), Role: "admin"})
+
+	// Новый кошелёк сразу получает override
+	created, err := uc.Execute(admin, withCap(500))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if created.Wallet.MaxPendingTransactions == nil || *created.Wallet.MaxPendingTransactions != 500 {
+		t.Fatalf("Expected override 500, got %v", created.Wallet.MaxPendingTransactions)
+	}
+
+	// Тот же override - no-op, лимиты без override не трогают его
+	for _, cmd := range []dtos.EnsureWalletCommand{withCap(500), f.command("", "")} {
+		result, err := uc.Execute(admin, cmd)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.Changed {
+			t.Errorf("Expected no-op, got fields=%v", result.ChangedFields)
+		}
+	}
+
+	// Лимиты и override вместе: два сохранения в одной UnitOfWork
+	cmd := withCap(0)
+	cmd.DailyLimit = "250"
+	result, err := uc.Execute(admin, cmd)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(result.ChangedFields) != 2 || result.ChangedFields[1] != dtos.EnsureWalletFieldMaxPendingTransactions {
+		t.Errorf("Expected daily_limit and max_pending_transactions changed, got %v", result.ChangedFields)
+	}
+
+	stored, err := f.wallets.FindByUserAndCurrency(context.Background(), f.user.ID(), valueobjects.USD)
+	if err != nil {
+		t.Fatalf("FindByUserAndCurrency() error = %v", err)
+	}
+	if stored.MaxPendingTransactions() != nil || stored.DailyLimit().String() != "250.00 USD" {
+		t.Errorf("Expected override removed and daily limit stored, got %v %s", stored.MaxPendingTransactions(), stored.DailyLimit())
+	}
+
+	if _, err := uc.Execute(admin, withCap(-1)); err == nil {
+		t.Error("Expected validation error for negative override")
+	}
+
+	// Владелец не может задать override себе сам
+	owner := ports.WithActor(context.Background(), ports.Actor{ID: f.user.ID(), Role: "user"})
+	for _, ctx := range []context.Context{owner, context.Background()} {
+		_, err := uc.Execute(ctx, withCap(10))
+		var domainErr *domainErrors.DomainError
+		if !stderrors.As(err, &domainErr) || domainErr.Code != wallet.PendingCapOverrideForbiddenCode {
+			t.Errorf("Expected %s, got %v", wallet.PendingCapOverrideForbiddenCode, err)
+		}
+	}
+	if _, err := uc.Execute(owner, f.command("", "")); err != nil {
+		t.Errorf("Expected owner ensure without override to pass, got %v", err)
+	}
+}
+
+func TestEnsureWalletUseCase_ValidationErrors(t *testing.T) {
+	f := newEnsureFixture(t)
+
+	cmd := dtos.EnsureWalletCommand{UserID: "not-a-uuid", CurrencyCode: "XXX", DailyLimit: "abc", MonthlyLimit: "-5"}
+	_, err := f.useCase(f.wallets, memory.NewUnitOfWork(f.store)).Execute(context.Background(), cmd)
+
+	var errs domainErrors.ValidationErrors
+	if !stderrors.As(err, &errs) {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+	fields := map[string]bool{}
+	for _, e := range errs {
+		fields[e.Field] = true
+	}
+	for _, field := range []string{"user_id", "currency_code", "daily_limit", "monthly_limit"} {
+		if !fields[field] {
+			t.Errorf("Expected error for %s, got %v", field, errs)
+		}
+	}
+}
+
+// barrierWalletRepository задерживает первый поиск каждого вызова, пока
+// все участники гонки не убедятся, что кошелька ещё нет.
+type barrierWalletRepository struct {
+	*memory.WalletRepository
+	barrier *sync.WaitGroup
+	once    sync.Map // goroutine key -> struct{}
+}
+
+type racerKey struct{}
+
+func (r *barrierWalletRepository) FindByUserAndCurrency(ctx context.Context, userID uuid.UUID, currency valueobjects.Currency) (*entities.Wallet, error) {
+	w, err := r.WalletRepository.FindByUserAndCurrency(ctx, userID, currency)
+	if _, loaded := r.once.LoadOrStore(ctx.Value(racerKey{}), struct{}{}); !loaded {
+		r.barrier.Done()
+		r.barrier.Wait()
+	}
+	return w, err
+}
+
+func TestEnsureWalletUseCase_ConcurrentEnsuresConverge(t *testing.T) {
+	f := newEnsureFixture(t)
+
+	const racers = 2
+	barrier := &sync.WaitGroup{}
+	barrier.Add(racers)
+	repo := &barrierWalletRepository{WalletRepository: f.wallets, barrier: barrier}
+
+	// optimisticUoW без сериализации: оба вызова видят, что кошелька нет
+	uc := f.useCase(repo, &optimisticUoW{})
+
+	results := make([]*dtos.EnsureWalletResultDTO, racers)
+	errs := make([]error, racers)
+	stats := make([]*ports.RequestStats, racers)
+	var wg sync.WaitGroup
+	for i := range racers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stats[i] = &ports.RequestStats{}
+			ctx := ports.WithRequestStats(context.WithValue(context.Background(), racerKey{}, i), stats[i])
+			results[i], errs[i] = uc.Execute(ctx, f.command("", ""))
+		}()
+	}
+	wg.Wait()
+
+	created, retries := 0, int64(0)
+	for i := range racers {
+		if errs[i] != nil {
+			t.Fatalf("Execute() #%d error = %v", i, errs[i])
+		}
+		if results[i].Created {
+			created++
+		}
+		retries += stats[i].Snapshot().LockRetries
+	}
+	if created != 1 {
+		t.Errorf("Expected exactly one ensure to create the wallet, got %d", created)
+	}
+	// Проигравший гонку получает WALLET_ALREADY_EXISTS и повторяет ensure
+	if retries != 1 {
+		t.Errorf("Expected 1 retry by the losing ensure, got %d", retries)
+	}
+	if results[0].Wallet.ID != results[1].Wallet.ID {
+		t.Errorf("Expected both ensures to return the same wallet, got %s and %s", results[0].Wallet.ID, results[1].Wallet.ID)
+	}
+	if n := f.walletCount(t); n != 1 {
+		t.Errorf("Expected one wallet, got %d", n)
+	}
+	if types := f.eventTypes(); len(types) != 1 || types[0] != events.EventTypeWalletCreated {
+		t.Errorf("Expected a single WalletCreated event, got %v", types)
+	}
+}
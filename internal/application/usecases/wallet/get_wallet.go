@@ -13,13 +13,27 @@ import (
 
 // GetWalletUseCase - use case для получения кошелька по ID.
 type GetWalletUseCase struct {
-	walletRepo ports.WalletRepository
+	walletRepo      ports.WalletReader
+	transactionRepo ports.TransactionReader         // только для include_usage
+	pending         ports.PendingTransactionsPolicy // лимит по умолчанию для Usage
+	metadataQuota   ports.MetadataQuota             // объём metadata для Usage; Usage nil - не показывается
+	display         ports.DisplayCurrency           // пересчёт в валюту отображения; нулевое - выключен
 }
 
 // NewGetWalletUseCase создаёт новый use case.
-func NewGetWalletUseCase(walletRepo ports.WalletRepository) *GetWalletUseCase {
+func NewGetWalletUseCase(
+	walletRepo ports.WalletReader,
+	transactionRepo ports.TransactionReader,
+	pending ports.PendingTransactionsPolicy,
+	metadataQuota ports.MetadataQuota,
+	display ports.DisplayCurrency,
+) *GetWalletUseCase {
 	return &GetWalletUseCase{
-		walletRepo: walletRepo,
+		walletRepo:      walletRepo,
+		transactionRepo: transactionRepo,
+		pending:         pending,
+		metadataQuota:   metadataQuota,
+		display:         display,
 	}
 }
 
@@ -39,5 +53,36 @@ func (uc *GetWalletUseCase) Execute(ctx context.Context, query dtos.GetWalletQue
 	}
 
 	dto := dtos.ToWalletDTO(wallet)
+
+	err = attachConvertedBalances(ctx, uc.display, []displayBalance{
+		{walletID: walletID, balance: wallet.AvailableBalance(), converted: &dto.Converted},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if query.IncludeUsage {
+		count, err := uc.transactionRepo.CountPendingByWallet(ctx, walletID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count pending transactions: %w", err)
+		}
+		dto.Usage = &dtos.WalletUsageDTO{
+			PendingTransactions:    count,
+			MaxPendingTransactions: max(wallet.PendingTransactionsCap(uc.pending.MaxPerWallet), 0),
+		}
+
+		if uc.metadataQuota.Usage != nil {
+			metadataBytes, err := uc.metadataQuota.Usage.MetadataUsage(ctx, walletID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load metadata usage: %w", err)
+			}
+			dto.Usage.Metadata = &dtos.WalletMetadataUsageDTO{
+				UsedBytes:      metadataBytes,
+				SoftLimitBytes: uc.metadataQuota.SoftLimitBytes,
+				HardLimitBytes: uc.metadataQuota.HardLimitBytes,
+			}
+		}
+	}
+
 	return &dto, nil
 }
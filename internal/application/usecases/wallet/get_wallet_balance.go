@@ -0,0 +1,70 @@
+// Package wallet - GetWalletBalance use case: быстрый путь чтения баланса.
+package wallet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/google/uuid"
+)
+
+// GetWalletBalanceUseCase - use case для чтения только балансов кошелька.
+// В отличие от GetWalletUseCase не восстанавливает entity и не читает
+// лимиты/статус: клиенты, которые опрашивают баланс, получают только числа.
+type GetWalletBalanceUseCase struct {
+	walletRepo ports.WalletReader
+	display    ports.DisplayCurrency // пересчёт в валюту отображения; нулевое - выключен
+}
+
+// NewGetWalletBalanceUseCase создаёт новый use case.
+func NewGetWalletBalanceUseCase(walletRepo ports.WalletReader, display ports.DisplayCurrency) *GetWalletBalanceUseCase {
+	return &GetWalletBalanceUseCase{walletRepo: walletRepo, display: display}
+}
+
+// Execute возвращает балансы кошелька по ID.
+func (uc *GetWalletBalanceUseCase) Execute(ctx context.Context, query dtos.GetWalletBalanceQuery) (*dtos.WalletBalanceDTO, error) {
+	walletID, err := uuid.Parse(query.WalletID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "wallet_id", Message: "invalid UUID"}
+	}
+
+	balance, err := uc.walletRepo.FindBalanceByID(ctx, walletID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: wallet %s", errors.ErrEntityNotFound, query.WalletID)
+		}
+		return nil, fmt.Errorf("failed to load wallet balance: %w", err)
+	}
+
+	currency, err := valueobjects.NewCurrency(balance.CurrencyCode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wallet currency: %w", err)
+	}
+
+	dto := &dtos.WalletBalanceDTO{
+		WalletID:         balance.WalletID.String(),
+		CurrencyCode:     currency.Code(),
+		AvailableBalance: valueobjects.FormatMinorUnits(balance.AvailableCents, currency),
+		PendingBalance:   valueobjects.FormatMinorUnits(balance.PendingCents, currency),
+		BalanceVersion:   balance.BalanceVersion,
+		UserID:           balance.UserID.String(),
+	}
+
+	if uc.display.Enabled() {
+		available, err := valueobjects.NewMoneyFromMinorUnits(balance.AvailableCents, currency)
+		if err == nil {
+			err = attachConvertedBalances(ctx, uc.display, []displayBalance{
+				{walletID: walletID, balance: available, converted: &dto.Converted},
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return dto, nil
+}
@@ -0,0 +1,136 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/google/uuid"
+)
+
+// newBalanceFixture сохраняет кошелёк с available 100.50 USD и pending 20.00 USD.
+func newBalanceFixture(tb testing.TB) (*memory.WalletRepository, *entities.Wallet) {
+	tb.Helper()
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	wallets := memory.NewWalletRepository(store)
+
+	owner, err := entities.NewUser("balance-"+uuid.NewString()+"@example.com", "Balance Test")
+	if err != nil {
+		tb.Fatalf("NewUser() error = %v", err)
+	}
+	if err := users.Save(ctx, owner); err != nil {
+		tb.Fatalf("save user error = %v", err)
+	}
+	w, err := entities.NewWallet(owner.ID(), valueobjects.USD)
+	if err != nil {
+		tb.Fatalf("NewWallet() error = %v", err)
+	}
+	if err := wallets.Save(ctx, w); err != nil {
+		tb.Fatalf("save wallet error = %v", err)
+	}
+
+	amount, _ := valueobjects.NewMoney("120.50", valueobjects.USD)
+	hold, _ := valueobjects.NewMoney("20", valueobjects.USD)
+	if err := w.Credit(amount); err != nil {
+		tb.Fatalf("Credit() error = %v", err)
+	}
+	if err := wallets.Save(ctx, w); err != nil {
+		tb.Fatalf("save wallet error = %v", err)
+	}
+	if err := w.Reserve(hold); err != nil {
+		tb.Fatalf("Reserve() error = %v", err)
+	}
+	if err := wallets.Save(ctx, w); err != nil {
+		tb.Fatalf("save wallet error = %v", err)
+	}
+	return wallets, w
+}
+
+func TestGetWalletBalanceUseCase_MatchesGetWallet(t *testing.T) {
+	ctx := context.Background()
+	wallets, w := newBalanceFixture(t)
+
+	balance, err := wallet.NewGetWalletBalanceUseCase(wallets, ports.DisplayCurrency{}).
+		Execute(ctx, dtos.GetWalletBalanceQuery{WalletID: w.ID().String()})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	full, err := wallet.NewGetWalletUseCase(wallets, nil, ports.PendingTransactionsPolicy{}, ports.MetadataQuota{}, ports.DisplayCurrency{}).
+		Execute(ctx, dtos.GetWalletQuery{WalletID: w.ID().String()})
+	if err != nil {
+		t.Fatalf("GetWallet error = %v", err)
+	}
+
+	want := dtos.WalletBalanceDTO{
+		WalletID:         full.ID,
+		CurrencyCode:     full.CurrencyCode,
+		AvailableBalance: full.AvailableBalance,
+		PendingBalance:   full.PendingBalance,
+		BalanceVersion:   full.BalanceVersion,
+		UserID:           full.UserID,
+	}
+	if *balance != want {
+		t.Errorf("balance = %+v, want %+v", *balance, want)
+	}
+	if balance.AvailableBalance != "100.50 USD" || balance.PendingBalance != "20.00 USD" {
+		t.Errorf("balances = %s / %s, want 100.50 USD / 20.00 USD", balance.AvailableBalance, balance.PendingBalance)
+	}
+}
+
+func TestGetWalletBalanceUseCase_Errors(t *testing.T) {
+	ctx := context.Background()
+	wallets, _ := newBalanceFixture(t)
+	uc := wallet.NewGetWalletBalanceUseCase(wallets, ports.DisplayCurrency{})
+
+	_, err := uc.Execute(ctx, dtos.GetWalletBalanceQuery{WalletID: uuid.NewString()})
+	if !domainErrors.IsNotFound(err) {
+		t.Errorf("unknown wallet: error = %v, want ErrEntityNotFound", err)
+	}
+
+	_, err = uc.Execute(ctx, dtos.GetWalletBalanceQuery{WalletID: "not-a-uuid"})
+	if _, ok := err.(domainErrors.ValidationError); !ok {
+		t.Errorf("invalid id: error = %v, want ValidationError", err)
+	}
+}
+
+// Быстрый путь должен быть заметно дешевле полного GetWallet:
+//
+//	go test ./internal/application/usecases/wallet -run '^$' -bench 'GetWallet' -benchmem
+func BenchmarkGetWalletUseCase(b *testing.B) {
+	wallets, w := newBalanceFixture(b)
+	uc := wallet.NewGetWalletUseCase(wallets, nil, ports.PendingTransactionsPolicy{}, ports.MetadataQuota{}, ports.DisplayCurrency{})
+	query := dtos.GetWalletQuery{WalletID: w.ID().String()}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := uc.Execute(ctx, query); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetWalletBalanceUseCase(b *testing.B) {
+	wallets, w := newBalanceFixture(b)
+	uc := wallet.NewGetWalletBalanceUseCase(wallets, ports.DisplayCurrency{})
+	query := dtos.GetWalletBalanceQuery{WalletID: w.ID().String()}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := uc.Execute(ctx, query); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package wallet_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/kyc"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/google/uuid"
+)
+
+// kycGateFixture - кошелёк с балансом 100 USD, владельцу которого отозван KYC.
+type kycGateFixture struct {
+	store     *memory.Store
+	users     *memory.UserRepository
+	wallets   *memory.WalletRepository
+	publisher *memory.EventPublisher
+	target    *entities.Wallet
+}
+
+func newKYCGateFixture(t *testing.T) *kycGateFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	f := &kycGateFixture{
+		store:     store,
+		users:     memory.NewUserRepository(store),
+		wallets:   memory.NewWalletRepository(store),
+		publisher: memory.NewEventPublisher(store),
+	}
+
+	owner, err := entities.NewUser("kyc-"+uuid.NewString()+"@example.com", "KYC Test")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := owner.RevokeKYC("Sanctions list match"); err != nil {
+		t.Fatalf("RevokeKYC() error = %v", err)
+	}
+	if err := f.users.Save(ctx, owner); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+
+	f.target, err = entities.NewWallet(owner.ID(), valueobjects.USD)
+	if err != nil {
+		t.Fatalf("NewWallet() error = %v", err)
+	}
+	if err := f.wallets.Save(ctx, f.target); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+	opening, _ := valueobjects.NewMoney("100", valueobjects.USD)
+	if err := f.target.Credit(opening); err != nil {
+		t.Fatalf("Credit() error = %v", err)
+	}
+	if err := f.wallets.Save(ctx, f.target); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+	return f
+}
+
+func (f *kycGateFixture) debit(t *testing.T, mode kyc.Mode) *wallet.DebitWalletUseCase {
+	t.Helper()
+	gate, err := kyc.NewGate(kyc.Policy{Mode: mode}, f.users)
+	if err != nil {
+		t.Fatalf("NewGate() error = %v", err)
+	}
+	return wallet.NewDebitWalletUseCase(f.wallets, memory.NewTransactionRepository(f.store), f.publisher,
+		memory.NewUnitOfWork(f.store), nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, gate, nil, nil)
+}
+
+func (f *kycGateFixture) debitCommand() dtos.DebitWalletCommand {
+	return dtos.DebitWalletCommand{
+		WalletID:       f.target.ID().String(),
+		Amount:         "10",
+		IdempotencyKey: uuid.NewString(),
+		Description:    "Payout",
+	}
+}
+
+// TestDebitWalletUseCase_KYCGateStrict тестирует, что в строгом режиме
+// списание с кошелька владельца без KYC отклоняется, а зачисление проходит.
+func TestDebitWalletUseCase_KYCGateStrict(t *testing.T) {
+	ctx := context.Background()
+	f := newKYCGateFixture(t)
+
+	_, err := f.debit(t, kyc.ModeStrict).Execute(ctx, f.debitCommand())
+	var violation *domainErrors.BusinessRuleViolation
+	if !errors.As(err, &violation) {
+		t.Fatalf("Expected BusinessRuleViolation, got %v", err)
+	}
+	if violation.Rule != kyc.RuleNotVerified {
+		t.Errorf("Rule = %s, want %s", violation.Rule, kyc.RuleNotVerified)
+	}
+
+	credit := wallet.NewCreditWalletUseCase(f.wallets, memory.NewTransactionRepository(f.store), f.publisher,
+		memory.NewUnitOfWork(f.store), nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil)
+	result, err := credit.Execute(ctx, dtos.CreditWalletCommand{
+		WalletID:       f.target.ID().String(),
+		Amount:         "5",
+		IdempotencyKey: uuid.NewString(),
+		Description:    "Refund",
+	})
+	if err != nil {
+		t.Fatalf("Credit for unverified owner error = %v", err)
+	}
+	if result.Wallet.AvailableBalance != "105.00 USD" {
+		t.Errorf("Balance = %s, want 105.00 USD", result.Wallet.AvailableBalance)
+	}
+}
+
+// TestDebitWalletUseCase_KYCGateLenient тестирует, что в мягком режиме
+// списание проходит, но транзакция помечается для ручной проверки.
+func TestDebitWalletUseCase_KYCGateLenient(t *testing.T) {
+	ctx := context.Background()
+	f := newKYCGateFixture(t)
+
+	result, err := f.debit(t, kyc.ModeLenient).Execute(ctx, f.debitCommand())
+	if err != nil {
+		t.Fatalf("Debit error = %v", err)
+	}
+	if result.Wallet.AvailableBalance != "90.00 USD" {
+		t.Errorf("Balance = %s, want 90.00 USD", result.Wallet.AvailableBalance)
+	}
+	if result.Transaction.Metadata[ports.KYCUnverifiedOwnerMetadataKey] != "REJECTED" {
+		t.Errorf("Metadata = %v", result.Transaction.Metadata)
+	}
+
+	flagged := 0
+	for _, event := range f.publisher.Events() {
+		if e, ok := event.(*events.TransactionFlagged); ok && e.RuleIDs[0] == kyc.RuleNotVerified {
+			flagged++
+		}
+	}
+	if flagged != 1 {
+		t.Errorf("TransactionFlagged events = %d, want 1", flagged)
+	}
+}
+
+// TestCloseWalletWithSweepUseCase_KYCGateStrict тестирует, что владелец с
+// отозванным KYC не выводит остаток закрытием кошелька.
+func TestCloseWalletWithSweepUseCase_KYCGateStrict(t *testing.T) {
+	ctx := context.Background()
+	f := newSweepFixture(t, "100.00", valueobjects.USD)
+
+	owner, err := f.users.FindByID(ctx, f.source.UserID())
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if err := owner.RevokeKYC("Sanctions list match"); err != nil {
+		t.Fatalf("RevokeKYC() error = %v", err)
+	}
+	if err := f.users.Save(ctx, owner); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+	gate, err := kyc.NewGate(kyc.Policy{Mode: kyc.ModeStrict}, f.users)
+	if err != nil {
+		t.Fatalf("NewGate() error = %v", err)
+	}
+
+	_, err = f.useCaseWithGates(f.wallets, memory.NewUnitOfWork(f.store), sweepGates{kyc: gate}).Execute(ctx, f.command())
+
+	var violation *domainErrors.BusinessRuleViolation
+	if !errors.As(err, &violation) || violation.Rule != kyc.RuleNotVerified {
+		t.Fatalf("Expected %s, got %v", kyc.RuleNotVerified, err)
+	}
+	source := f.reload(t, f.source.ID())
+	if source.Status() != entities.WalletStatusActive || source.AvailableBalance().String() != "100.00 USD" {
+		t.Errorf("Expected wallet untouched, got %s %s", source.Status(), source.AvailableBalance().String())
+	}
+	if got := f.reload(t, f.destination.ID()).AvailableBalance(); !got.IsZero() {
+		t.Errorf("Expected nothing swept, got %s", got.String())
+	}
+}
@@ -14,13 +14,15 @@ import (
 
 // ListWalletsUseCase - use case для получения списка кошельков с фильтрацией.
 type ListWalletsUseCase struct {
-	walletRepo ports.WalletRepository
+	walletRepo ports.WalletReader
+	display    ports.DisplayCurrency // пересчёт в валюту отображения; нулевое - выключен
 }
 
 // NewListWalletsUseCase создаёт новый use case.
-func NewListWalletsUseCase(walletRepo ports.WalletRepository) *ListWalletsUseCase {
+func NewListWalletsUseCase(walletRepo ports.WalletReader, display ports.DisplayCurrency) *ListWalletsUseCase {
 	return &ListWalletsUseCase{
 		walletRepo: walletRepo,
+		display:    display,
 	}
 }
 
@@ -49,15 +51,26 @@ func (uc *ListWalletsUseCase) Execute(ctx context.Context, query dtos.ListWallet
 		filter.Status = &status
 	}
 
-	wallets, err := uc.walletRepo.List(ctx, filter, query.Offset, query.Limit)
+	// Лишняя строка определяет has_more
+	wallets, err := uc.walletRepo.List(ctx, filter, query.Offset, dtos.FetchLimit(query.Limit))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list wallets: %w", err)
 	}
 
-	return &dtos.WalletListDTO{
-		Wallets:    dtos.ToWalletDTOList(wallets),
-		TotalCount: len(wallets),
-		Offset:     query.Offset,
-		Limit:      query.Limit,
-	}, nil
+	page := dtos.NewOffsetPage(dtos.ToWalletDTOList(wallets), query.Offset, query.Limit)
+
+	// Настройки и курсы - одним проходом на страницу, а не на кошелёк
+	balances := make([]displayBalance, len(page.Data))
+	for i := range page.Data {
+		balances[i] = displayBalance{
+			walletID:  wallets[i].ID(),
+			balance:   wallets[i].AvailableBalance(),
+			converted: &page.Data[i].Converted,
+		}
+	}
+	if err := attachConvertedBalances(ctx, uc.display, balances); err != nil {
+		return nil, err
+	}
+
+	return page, nil
 }
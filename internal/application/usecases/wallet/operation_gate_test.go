@@ -0,0 +1,83 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/operations"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	operationsuc "github.com/Haleralex/wallethub/internal/application/usecases/operations"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// TestOperationGate_WithdrawSwitch тестирует, что выключенный WITHDRAW
+// останавливает списания, но не пополнения, и что включение их возвращает.
+func TestOperationGate_WithdrawSwitch(t *testing.T) {
+	store := memory.NewStore()
+	wallets := memory.NewWalletRepository(store)
+	transactions := memory.NewTransactionRepository(store)
+	publisher := memory.NewEventPublisher(store)
+	uow := memory.NewUnitOfWork(store)
+	switches := memory.NewOperationSwitchRepository(store)
+
+	owner, err := entities.NewUser("switch-"+uuid.NewString()+"@example.com", "Switch Test")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := memory.NewUserRepository(store).Save(context.Background(), owner); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+	target, err := entities.NewWallet(owner.ID(), valueobjects.USD)
+	if err != nil {
+		t.Fatalf("NewWallet() error = %v", err)
+	}
+	if err := wallets.Save(context.Background(), target); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+
+	gate := operations.NewGate(switches, 0)
+	credit := wallet.NewCreditWalletUseCase(wallets, transactions, publisher, uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, gate, nil)
+	debit := wallet.NewDebitWalletUseCase(wallets, transactions, publisher, uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil, gate, nil)
+	setSwitch := operationsuc.NewSetOperationSwitchUseCase(switches, gate, publisher, uow)
+
+	ctx := ports.WithActor(context.Background(), ports.Actor{ID: uuid.New(), Role: "admin"})
+	creditCmd := func() dtos.CreditWalletCommand {
+		return dtos.CreditWalletCommand{WalletID: target.ID().String(), Amount: "100", IdempotencyKey: uuid.NewString()}
+	}
+	debitCmd := func() dtos.DebitWalletCommand {
+		return dtos.DebitWalletCommand{WalletID: target.ID().String(), Amount: "10", IdempotencyKey: uuid.NewString()}
+	}
+
+	if _, err := credit.Execute(ctx, creditCmd()); err != nil {
+		t.Fatalf("Credit() error = %v", err)
+	}
+	if _, err := setSwitch.Execute(ctx, dtos.SetOperationSwitchCommand{Type: "WITHDRAW", Enabled: false, Reason: "payout provider incident"}); err != nil {
+		t.Fatalf("Disable WITHDRAW error = %v", err)
+	}
+
+	_, err = debit.Execute(ctx, debitCmd())
+	if !domainErrors.IsOperationDisabled(err) {
+		t.Fatalf("Expected OperationDisabledError, got %v", err)
+	}
+	if _, err := credit.Execute(ctx, creditCmd()); err != nil {
+		t.Errorf("Credit while WITHDRAW disabled error = %v", err)
+	}
+
+	if _, err := setSwitch.Execute(ctx, dtos.SetOperationSwitchCommand{Type: "WITHDRAW", Enabled: true}); err != nil {
+		t.Fatalf("Enable WITHDRAW error = %v", err)
+	}
+	result, err := debit.Execute(ctx, debitCmd())
+	if err != nil {
+		t.Fatalf("Debit after re-enabling error = %v", err)
+	}
+	if result.Wallet.AvailableBalance != "190.00 USD" {
+		t.Errorf("Balance = %s, want 190.00 USD", result.Wallet.AvailableBalance)
+	}
+}
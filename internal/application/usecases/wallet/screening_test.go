@@ -0,0 +1,176 @@
+package wallet_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/screening"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/google/uuid"
+)
+
+// screeningFixture - кошелёк с балансом 100 USD и use cases с правилами:
+// списание больше 50 блокируется, пополнение больше 1000 помечается.
+type screeningFixture struct {
+	target       *entities.Wallet
+	wallets      *memory.WalletRepository
+	transactions *memory.TransactionRepository
+	publisher    *memory.EventPublisher
+	credit       *wallet.CreditWalletUseCase
+	debit        *wallet.DebitWalletUseCase
+}
+
+func newScreeningFixture(t *testing.T) *screeningFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	f := &screeningFixture{
+		wallets:      memory.NewWalletRepository(store),
+		transactions: memory.NewTransactionRepository(store),
+		publisher:    memory.NewEventPublisher(store),
+	}
+	uow := memory.NewUnitOfWork(store)
+
+	owner, err := entities.NewUser("screening-"+uuid.NewString()+"@example.com", "Screening Test")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := users.Save(ctx, owner); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+
+	f.target, err = entities.NewWallet(owner.ID(), valueobjects.USD)
+	if err != nil {
+		t.Fatalf("NewWallet() error = %v", err)
+	}
+	if err := f.wallets.Save(ctx, f.target); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+	opening, _ := valueobjects.NewMoney("100", valueobjects.USD)
+	if err := f.target.Credit(opening); err != nil {
+		t.Fatalf("Credit() error = %v", err)
+	}
+	if err := f.wallets.Save(ctx, f.target); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+
+	rules, err := screening.NewRuleSet([]ports.ScreeningRule{
+		{
+			ID:     "large-withdraw",
+			Action: "BLOCK",
+			Conditions: []ports.ScreeningCondition{
+				{Attribute: "type", Operator: "eq", Value: "WITHDRAW"},
+				{Attribute: "amount", Operator: "gt", Value: "50"},
+			},
+		},
+		{
+			ID:         "large-deposit",
+			Action:     "FLAG",
+			Conditions: []ports.ScreeningCondition{{Attribute: "amount", Operator: "gt", Value: "1000"}},
+		},
+		{
+			ID:         "usd",
+			Action:     "FLAG",
+			Conditions: []ports.ScreeningCondition{{Attribute: "currency", Operator: "eq", Value: "USD"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRuleSet() error = %v", err)
+	}
+	screener := screening.NewService(rules, users)
+
+	f.credit = wallet.NewCreditWalletUseCase(f.wallets, f.transactions, f.publisher, uow, nil, screener, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil)
+	f.debit = wallet.NewDebitWalletUseCase(f.wallets, f.transactions, f.publisher, uow, nil, screener, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil, nil, nil)
+	return f
+}
+
+func TestDebitWalletUseCase_ScreeningBlock(t *testing.T) {
+	ctx := context.Background()
+	f := newScreeningFixture(t)
+	published := len(f.publisher.Events())
+
+	_, err := f.debit.Execute(ctx, dtos.DebitWalletCommand{
+		WalletID:       f.target.ID().String(),
+		Amount:         "50.01",
+		IdempotencyKey: uuid.NewString(),
+		Description:    "Payout",
+	})
+
+	var violation *domainErrors.BusinessRuleViolation
+	if !errors.As(err, &violation) {
+		t.Fatalf("Expected BusinessRuleViolation, got %v", err)
+	}
+	if violation.Rule != "TRANSACTION_SCREENED" || violation.Context["rule_id"] != "large-withdraw" {
+		t.Errorf("Unexpected violation %+v", violation)
+	}
+
+	stored, err := f.wallets.FindByID(ctx, f.target.ID())
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if stored.AvailableBalance().String() != "100.00 USD" {
+		t.Errorf("Balance = %s, blocked debit must not move funds", stored.AvailableBalance())
+	}
+	if got := len(f.publisher.Events()); got != published {
+		t.Errorf("Published %d events for a blocked debit", got-published)
+	}
+
+	// Сумма ниже порога проходит
+	if _, err := f.debit.Execute(ctx, dtos.DebitWalletCommand{
+		WalletID:       f.target.ID().String(),
+		Amount:         "50.00",
+		IdempotencyKey: uuid.NewString(),
+		Description:    "Payout",
+	}); err != nil {
+		t.Errorf("Debit below threshold error = %v", err)
+	}
+}
+
+func TestCreditWalletUseCase_ScreeningFlag(t *testing.T) {
+	ctx := context.Background()
+	f := newScreeningFixture(t)
+
+	result, err := f.credit.Execute(ctx, dtos.CreditWalletCommand{
+		WalletID:       f.target.ID().String(),
+		Amount:         "1500",
+		IdempotencyKey: uuid.NewString(),
+		Description:    "Top up",
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Wallet.AvailableBalance != "1600.00 USD" {
+		t.Errorf("Balance = %s, flagged credit must go through", result.Wallet.AvailableBalance)
+	}
+
+	tx, err := f.transactions.FindByID(ctx, uuid.MustParse(result.TransactionID))
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if got := tx.Metadata()[ports.ScreeningFlagsMetadataKey]; got != "large-deposit,usd" {
+		t.Errorf("screening_flags = %v, want large-deposit,usd", got)
+	}
+
+	var flagged *events.TransactionFlagged
+	for _, event := range f.publisher.Events() {
+		if e, ok := event.(*events.TransactionFlagged); ok {
+			flagged = e
+		}
+	}
+	if flagged == nil {
+		t.Fatal("Expected TransactionFlagged event")
+	}
+	if flagged.TransactionID != tx.ID() || len(flagged.RuleIDs) != 2 || flagged.RuleIDs[0] != "large-deposit" {
+		t.Errorf("Unexpected TransactionFlagged %+v", flagged)
+	}
+}
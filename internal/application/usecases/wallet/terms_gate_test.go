@@ -0,0 +1,91 @@
+package wallet_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/terms"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/google/uuid"
+)
+
+// TestDebitWalletUseCase_TermsGate тестирует, что списание отклоняется,
+// пока владелец кошелька не примет требуемую версию ToS.
+func TestDebitWalletUseCase_TermsGate(t *testing.T) {
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	wallets := memory.NewWalletRepository(store)
+	uow := memory.NewUnitOfWork(store)
+
+	owner, err := entities.NewUser("terms-"+uuid.NewString()+"@example.com", "Terms Test")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := users.Save(ctx, owner); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+
+	target, err := entities.NewWallet(owner.ID(), valueobjects.USD)
+	if err != nil {
+		t.Fatalf("NewWallet() error = %v", err)
+	}
+	if err := wallets.Save(ctx, target); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+	opening, _ := valueobjects.NewMoney("100", valueobjects.USD)
+	if err := target.Credit(opening); err != nil {
+		t.Fatalf("Credit() error = %v", err)
+	}
+	if err := wallets.Save(ctx, target); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+
+	gate, err := terms.NewGate(terms.Policy{RequiredVersion: 2}, users)
+	if err != nil {
+		t.Fatalf("NewGate() error = %v", err)
+	}
+	debit := wallet.NewDebitWalletUseCase(wallets, memory.NewTransactionRepository(store), memory.NewEventPublisher(store),
+		uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, gate, nil, nil, nil)
+
+	cmd := func() dtos.DebitWalletCommand {
+		return dtos.DebitWalletCommand{
+			WalletID:       target.ID().String(),
+			Amount:         "10",
+			IdempotencyKey: uuid.NewString(),
+			Description:    "Payout",
+		}
+	}
+
+	_, err = debit.Execute(ctx, cmd())
+	var violation *domainErrors.BusinessRuleViolation
+	if !errors.As(err, &violation) {
+		t.Fatalf("Expected BusinessRuleViolation, got %v", err)
+	}
+	if violation.Rule != terms.RuleAcceptanceRequired || violation.Context["requiredVersion"] != 2 {
+		t.Errorf("Unexpected violation %+v", violation)
+	}
+
+	if _, err := owner.AcceptTerms(2); err != nil {
+		t.Fatalf("AcceptTerms() error = %v", err)
+	}
+	if err := users.Save(ctx, owner); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+
+	result, err := debit.Execute(ctx, cmd())
+	if err != nil {
+		t.Fatalf("Debit after acceptance error = %v", err)
+	}
+	if result.Wallet.AvailableBalance != "90.00 USD" {
+		t.Errorf("Balance = %s, want 90.00 USD", result.Wallet.AvailableBalance)
+	}
+}
@@ -0,0 +1,180 @@
+package wallet_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/application/walletlimit"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/google/uuid"
+)
+
+// optimisticUoW не сериализует транзакции (в отличие от memory.UnitOfWork):
+// конкурентные списания конфликтуют на версии кошелька, как в postgres.
+// Отката нет - debit сохраняет кошелёк первым, конфликт ничего не записывает.
+type optimisticUoW struct{}
+
+func (u *optimisticUoW) Execute(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+func (u *optimisticUoW) ExecuteWithResult(ctx context.Context, fn func(context.Context) (interface{}, error)) (interface{}, error) {
+	return fn(ctx)
+}
+
+func (u *optimisticUoW) New() ports.UnitOfWork {
+	return u
+}
+
+// slowWalletRepository добавляет round trip к БД на чтение и запись:
+// между загрузкой и сохранением кошелька конкурент успевает его изменить.
+type slowWalletRepository struct {
+	*memory.WalletRepository
+	latency time.Duration
+}
+
+func (r *slowWalletRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+	time.Sleep(r.latency)
+	return r.WalletRepository.FindByID(ctx, id)
+}
+
+func (r *slowWalletRepository) Save(ctx context.Context, wallet *entities.Wallet) error {
+	time.Sleep(r.latency)
+	return r.WalletRepository.Save(ctx, wallet)
+}
+
+// concurrentDebits выполняет n одновременных списаний с одного кошелька,
+// повторяя каждое после конфликта версий. Возвращает число повторов.
+func concurrentDebits(t *testing.T, limiter ports.WalletLimiter, n int) int64 {
+	t.Helper()
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	wallets := memory.NewWalletRepository(store)
+	transactions := memory.NewTransactionRepository(store)
+	publisher := memory.NewEventPublisher(store)
+
+	owner, err := entities.NewUser(fmt.Sprintf("load-%s@example.com", uuid.NewString()), "Load Test")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := users.Save(ctx, owner); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+
+	target, err := entities.NewWallet(owner.ID(), valueobjects.USD)
+	if err != nil {
+		t.Fatalf("NewWallet() error = %v", err)
+	}
+	if err := wallets.Save(ctx, target); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+
+	initial := fmt.Sprintf("%d.00", n)
+	credit := wallet.NewCreditWalletUseCase(wallets, transactions, publisher, memory.NewUnitOfWork(store), nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil)
+	if _, err := credit.Execute(ctx, dtos.CreditWalletCommand{
+		WalletID:       target.ID().String(),
+		Amount:         initial,
+		IdempotencyKey: uuid.NewString(),
+		Description:    "Initial funding",
+	}); err != nil {
+		t.Fatalf("credit error = %v", err)
+	}
+
+	debit := wallet.NewDebitWalletUseCase(
+		&slowWalletRepository{WalletRepository: wallets, latency: time.Millisecond},
+		transactions,
+		publisher,
+		&optimisticUoW{},
+		limiter,
+		nil,
+		ports.BuildInfo{},
+		ports.SensitiveDataPolicy{},
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	stats := &ports.RequestStats{}
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			cmd := dtos.DebitWalletCommand{
+				WalletID:       target.ID().String(),
+				Amount:         "1.00",
+				IdempotencyKey: uuid.NewString(),
+				Description:    fmt.Sprintf("Concurrent debit #%d", idx),
+			}
+
+			for attempt := 0; attempt < 10*n; attempt++ {
+				_, err := debit.Execute(ctx, cmd)
+				if err == nil {
+					return
+				}
+				if !domainErrors.IsConcurrencyError(err) {
+					errs <- err
+					return
+				}
+				stats.RecordLockRetry()
+				time.Sleep(time.Millisecond)
+			}
+			errs <- fmt.Errorf("debit #%d: retries exhausted", idx)
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("debit error = %v", err)
+	}
+
+	stored, err := wallets.FindByID(ctx, target.ID())
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if !stored.AvailableBalance().IsZero() {
+		t.Errorf("Expected zero balance after %d debits, got %s", n, stored.AvailableBalance().String())
+	}
+
+	return stats.Snapshot().LockRetries
+}
+
+// TestDebitWalletUseCase_WalletLimiterReducesConflictRetries - 200 одновременных
+// списаний с одного кошелька: с limiter конфликтов версий в разы меньше.
+func TestDebitWalletUseCase_WalletLimiterReducesConflictRetries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("load test")
+	}
+
+	const debits = 200
+
+	unthrottled := concurrentDebits(t, nil, debits)
+	throttled := concurrentDebits(t, walletlimit.NewLimiter(walletlimit.Config{
+		MaxInFlight: walletlimit.DefaultMaxInFlight,
+		MaxWait:     time.Minute,
+	}), debits)
+
+	t.Logf("conflict retries for %d debits: unthrottled=%d, limited=%d", debits, unthrottled, throttled)
+
+	if unthrottled < debits {
+		t.Fatalf("Expected the unthrottled path to conflict heavily, got %d retries", unthrottled)
+	}
+	if throttled*5 > unthrottled {
+		t.Errorf("Expected limiter to cut retries at least 5x, got %d vs %d", throttled, unthrottled)
+	}
+}
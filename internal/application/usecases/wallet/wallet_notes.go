@@ -0,0 +1,299 @@
+// Package wallet - admin use cases заметок поддержки на кошельке:
+// создание, список, закрепление и мягкое удаление.
+package wallet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// Доступ ко всем use cases заметок только для admin/superadmin - проверяется
+// в роутере (группа /admin). Заметки не попадают в WalletDTO и snapshot.
+
+// ============================================
+// Create
+// ============================================
+
+// CreateWalletNoteUseCase добавляет заметку к кошельку.
+//
+// Заметка и событие WalletNoteAdded (запись аудита) сохраняются в одном
+// UnitOfWork. Автор берётся из context (ports.ActorFromContext).
+type CreateWalletNoteUseCase struct {
+	walletRepo     ports.WalletReader
+	noteRepo       ports.WalletNoteRepository
+	eventPublisher ports.EventPublisher
+	uow            ports.UnitOfWork
+}
+
+// NewCreateWalletNoteUseCase создаёт новый use case.
+func NewCreateWalletNoteUseCase(
+	walletRepo ports.WalletReader,
+	noteRepo ports.WalletNoteRepository,
+	eventPublisher ports.EventPublisher,
+	uow ports.UnitOfWork,
+) *CreateWalletNoteUseCase {
+	return &CreateWalletNoteUseCase{
+		walletRepo:     walletRepo,
+		noteRepo:       noteRepo,
+		eventPublisher: eventPublisher,
+		uow:            uow,
+	}
+}
+
+// Execute создаёт заметку.
+//
+// Errors:
+//   - ACTOR_REQUIRED: В context нет инициатора
+//   - WALLET_NOT_FOUND: Кошелёк не найден
+//   - ValidationError: Пустой текст или длиннее entities.MaxWalletNoteLength
+func (uc *CreateWalletNoteUseCase) Execute(ctx context.Context, cmd dtos.CreateWalletNoteCommand) (*dtos.WalletNoteDTO, error) {
+	actor, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "wallet note requires an authenticated actor", nil)
+	}
+
+	walletID, err := uuid.Parse(cmd.WalletID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "wallet_id", Message: "invalid UUID"}
+	}
+
+	note, err := entities.NewWalletNote(walletID, actor.ID, cmd.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		if err := ensureWalletExists(txCtx, uc.walletRepo, walletID); err != nil {
+			return err
+		}
+
+		if err := uc.noteRepo.Save(txCtx, note); err != nil {
+			return fmt.Errorf("failed to save wallet note: %w", err)
+		}
+
+		event := events.NewWalletNoteAdded(walletID, note.ID(), actor.ID)
+		if err := uc.eventPublisher.Publish(txCtx, event); err != nil {
+			return fmt.Errorf("failed to publish WalletNoteAdded event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dto := dtos.ToWalletNoteDTO(note)
+	return &dto, nil
+}
+
+// ============================================
+// List
+// ============================================
+
+// ListWalletNotesUseCase возвращает заметки кошелька.
+type ListWalletNotesUseCase struct {
+	walletRepo ports.WalletReader
+	noteRepo   ports.WalletNoteRepository
+}
+
+// NewListWalletNotesUseCase создаёт новый use case.
+func NewListWalletNotesUseCase(walletRepo ports.WalletReader, noteRepo ports.WalletNoteRepository) *ListWalletNotesUseCase {
+	return &ListWalletNotesUseCase{walletRepo: walletRepo, noteRepo: noteRepo}
+}
+
+// Execute возвращает страницу заметок: закреплённые первыми, затем новые.
+// Удалённые заметки не возвращаются.
+func (uc *ListWalletNotesUseCase) Execute(ctx context.Context, query dtos.ListWalletNotesQuery) (*dtos.WalletNoteListDTO, error) {
+	walletID, err := uuid.Parse(query.WalletID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "wallet_id", Message: "invalid UUID"}
+	}
+
+	if err := ensureWalletExists(ctx, uc.walletRepo, walletID); err != nil {
+		return nil, err
+	}
+
+	notes, err := uc.noteRepo.ListByWallet(ctx, walletID, query.Offset, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallet notes: %w", err)
+	}
+
+	result := &dtos.WalletNoteListDTO{
+		Notes:      make([]dtos.WalletNoteDTO, len(notes)),
+		TotalCount: len(notes),
+		Offset:     query.Offset,
+		Limit:      query.Limit,
+	}
+	for i, note := range notes {
+		result.Notes[i] = dtos.ToWalletNoteDTO(note)
+	}
+
+	return result, nil
+}
+
+// ============================================
+// Pin / unpin
+// ============================================
+
+// SetWalletNotePinnedUseCase закрепляет или открепляет заметку.
+// Закреплять может любой администратор, не только автор.
+type SetWalletNotePinnedUseCase struct {
+	noteRepo ports.WalletNoteRepository
+	uow      ports.UnitOfWork
+}
+
+// NewSetWalletNotePinnedUseCase создаёт новый use case.
+func NewSetWalletNotePinnedUseCase(noteRepo ports.WalletNoteRepository, uow ports.UnitOfWork) *SetWalletNotePinnedUseCase {
+	return &SetWalletNotePinnedUseCase{noteRepo: noteRepo, uow: uow}
+}
+
+// Execute меняет признак pinned.
+//
+// Errors:
+//   - WALLET_NOTE_NOT_FOUND: Заметки нет, она удалена или относится к другому кошельку
+func (uc *SetWalletNotePinnedUseCase) Execute(ctx context.Context, cmd dtos.SetWalletNotePinnedCommand) (*dtos.WalletNoteDTO, error) {
+	walletID, noteID, err := parseWalletNoteIDs(cmd.WalletID, cmd.NoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *dtos.WalletNoteDTO
+
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		note, err := findWalletNote(txCtx, uc.noteRepo, walletID, noteID)
+		if err != nil {
+			return err
+		}
+
+		if err := note.SetPinned(cmd.Pinned); err != nil {
+			return err
+		}
+
+		if err := uc.noteRepo.Save(txCtx, note); err != nil {
+			return fmt.Errorf("failed to save wallet note: %w", err)
+		}
+
+		dto := dtos.ToWalletNoteDTO(note)
+		result = &dto
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ============================================
+// Delete
+// ============================================
+
+// DeleteWalletNoteUseCase мягко удаляет заметку: строка остаётся в хранилище
+// с deleted_at/deleted_by и больше не показывается в списке.
+//
+// Удалить заметку может её автор или superadmin.
+type DeleteWalletNoteUseCase struct {
+	noteRepo ports.WalletNoteRepository
+	uow      ports.UnitOfWork
+}
+
+// NewDeleteWalletNoteUseCase создаёт новый use case.
+func NewDeleteWalletNoteUseCase(noteRepo ports.WalletNoteRepository, uow ports.UnitOfWork) *DeleteWalletNoteUseCase {
+	return &DeleteWalletNoteUseCase{noteRepo: noteRepo, uow: uow}
+}
+
+// Execute удаляет заметку и возвращает её последнее состояние.
+//
+// Errors:
+//   - ACTOR_REQUIRED: В context нет инициатора
+//   - WALLET_NOTE_NOT_FOUND: Заметки нет, она уже удалена или относится к другому кошельку
+//   - WALLET_NOTE_DELETE_FORBIDDEN: Инициатор не автор и не superadmin
+func (uc *DeleteWalletNoteUseCase) Execute(ctx context.Context, cmd dtos.DeleteWalletNoteCommand) (*dtos.WalletNoteDTO, error) {
+	actor, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "wallet note deletion requires an authenticated actor", nil)
+	}
+
+	walletID, noteID, err := parseWalletNoteIDs(cmd.WalletID, cmd.NoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *dtos.WalletNoteDTO
+
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		note, err := findWalletNote(txCtx, uc.noteRepo, walletID, noteID)
+		if err != nil {
+			return err
+		}
+
+		if note.AuthorID() != actor.ID && !actor.IsSuperAdmin() {
+			return errors.NewDomainError("WALLET_NOTE_DELETE_FORBIDDEN", "only the author or a superadmin can delete a note", nil)
+		}
+
+		note.Delete(actor.ID)
+		if err := uc.noteRepo.Save(txCtx, note); err != nil {
+			return fmt.Errorf("failed to save wallet note: %w", err)
+		}
+
+		dto := dtos.ToWalletNoteDTO(note)
+		result = &dto
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ============================================
+// Helpers
+// ============================================
+
+// ensureWalletExists возвращает WALLET_NOT_FOUND для неизвестного кошелька.
+func ensureWalletExists(ctx context.Context, walletRepo ports.WalletReader, walletID uuid.UUID) error {
+	if _, err := walletRepo.FindByID(ctx, walletID); err != nil {
+		if errors.IsNotFound(err) {
+			return errors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", err)
+		}
+		return fmt.Errorf("failed to load wallet: %w", err)
+	}
+	return nil
+}
+
+// parseWalletNoteIDs разбирает ID кошелька и заметки из пути.
+func parseWalletNoteIDs(walletIDStr, noteIDStr string) (uuid.UUID, uuid.UUID, error) {
+	walletID, err := uuid.Parse(walletIDStr)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.ValidationError{Field: "wallet_id", Message: "invalid UUID"}
+	}
+	noteID, err := uuid.Parse(noteIDStr)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.ValidationError{Field: "note_id", Message: "invalid UUID"}
+	}
+	return walletID, noteID, nil
+}
+
+// findWalletNote загружает неудалённую заметку кошелька. Заметка другого
+// кошелька считается отсутствующей, чтобы путь не раскрывал чужие ID.
+func findWalletNote(ctx context.Context, noteRepo ports.WalletNoteRepository, walletID, noteID uuid.UUID) (*entities.WalletNote, error) {
+	note, err := noteRepo.FindByID(ctx, noteID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NewDomainError("WALLET_NOTE_NOT_FOUND", "wallet note not found", err)
+		}
+		return nil, fmt.Errorf("failed to load wallet note: %w", err)
+	}
+	if note.WalletID() != walletID {
+		return nil, errors.NewDomainError("WALLET_NOTE_NOT_FOUND", "wallet note not found", nil)
+	}
+	return note, nil
+}
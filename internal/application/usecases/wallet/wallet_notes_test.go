@@ -0,0 +1,204 @@
+package wallet_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// notesFixture - хранилище с одним кошельком.
+type notesFixture struct {
+	store     *memory.Store
+	wallets   *memory.WalletRepository
+	notes     *memory.WalletNoteRepository
+	publisher *memory.EventPublisher
+	uow       *memory.UnitOfWork
+	walletID  uuid.UUID
+}
+
+func newNotesFixture(t *testing.T) *notesFixture {
+	t.Helper()
+
+	store := memory.NewStore()
+	f := &notesFixture{
+		store:     store,
+		wallets:   memory.NewWalletRepository(store),
+		notes:     memory.NewWalletNoteRepository(store),
+		publisher: memory.NewEventPublisher(store),
+		uow:       memory.NewUnitOfWork(store),
+	}
+
+	user, err := entities.NewUser("notes-"+uuid.NewString()+"@example.com", "Notes Test")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := memory.NewUserRepository(store).Save(context.Background(), user); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+	w, err := entities.NewWallet(user.ID(), valueobjects.USD)
+	if err != nil {
+		t.Fatalf("NewWallet() error = %v", err)
+	}
+	if err := f.wallets.Save(context.Background(), w); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+	f.walletID = w.ID()
+
+	return f
+}
+
+func (f *notesFixture) create(t *testing.T, author ports.Actor, body string) *dtos.WalletNoteDTO {
+	t.Helper()
+
+	ctx := ports.WithActor(context.Background(), author)
+	note, err := wallet.NewCreateWalletNoteUseCase(f.wallets, f.notes, f.publisher, f.uow).
+		Execute(ctx, dtos.CreateWalletNoteCommand{WalletID: f.walletID.String(), Body: body})
+	if err != nil {
+		t.Fatalf("create note error = %v", err)
+	}
+	return note
+}
+
+func (f *notesFixture) delete(author ports.Actor, noteID string) error {
+	ctx := ports.WithActor(context.Background(), author)
+	_, err := wallet.NewDeleteWalletNoteUseCase(f.notes, f.uow).
+		Execute(ctx, dtos.DeleteWalletNoteCommand{WalletID: f.walletID.String(), NoteID: noteID})
+	return err
+}
+
+func TestCreateWalletNoteUseCase_PublishesAuditEvent(t *testing.T) {
+	f := newNotesFixture(t)
+	author := ports.Actor{ID: uuid.New(), Role: "admin"}
+
+	note := f.create(t, author, "  customer called about failed payout, advised to retry \n")
+
+	if note.Body != "customer called about failed payout, advised to retry" || note.AuthorID != author.ID.String() {
+		t.Errorf("Unexpected note: %+v", note)
+	}
+
+	published := f.publisher.Events()
+	if len(published) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(published))
+	}
+	added, ok := published[0].(*events.WalletNoteAdded)
+	if !ok || added.NoteID.String() != note.ID || added.AuthorID != author.ID || added.WalletID != f.walletID {
+		t.Errorf("Unexpected event: %+v", published[0])
+	}
+}
+
+func TestCreateWalletNoteUseCase_Validation(t *testing.T) {
+	f := newNotesFixture(t)
+	uc := wallet.NewCreateWalletNoteUseCase(f.wallets, f.notes, f.publisher, f.uow)
+	ctx := ports.WithActor(context.Background(), ports.Actor{ID: uuid.New(), Role: "admin"})
+
+	_, err := uc.Execute(context.Background(), dtos.CreateWalletNoteCommand{WalletID: f.walletID.String(), Body: "note"})
+	assertNoteErrorCode(t, err, "ACTOR_REQUIRED")
+
+	_, err = uc.Execute(ctx, dtos.CreateWalletNoteCommand{WalletID: uuid.NewString(), Body: "note"})
+	assertNoteErrorCode(t, err, "WALLET_NOT_FOUND")
+
+	for _, body := range []string{"   ", strings.Repeat("ж", entities.MaxWalletNoteLength+1)} {
+		_, err = uc.Execute(ctx, dtos.CreateWalletNoteCommand{WalletID: f.walletID.String(), Body: body})
+		if _, ok := err.(domainErrors.ValidationError); !ok {
+			t.Errorf("Body of %d runes: expected ValidationError, got %v", len([]rune(body)), err)
+		}
+	}
+
+	// Ровно MaxWalletNoteLength рун (не байт) допустимо
+	_, err = uc.Execute(ctx, dtos.CreateWalletNoteCommand{WalletID: f.walletID.String(), Body: strings.Repeat("ж", entities.MaxWalletNoteLength)})
+	if err != nil {
+		t.Errorf("Expected max-length body to be accepted, got %v", err)
+	}
+
+	if len(f.publisher.Events()) != 1 {
+		t.Errorf("Expected audit event only for the created note, got %d", len(f.publisher.Events()))
+	}
+}
+
+func TestDeleteWalletNoteUseCase_AuthorOrSuperadmin(t *testing.T) {
+	f := newNotesFixture(t)
+	author := ports.Actor{ID: uuid.New(), Role: "admin"}
+	otherAdmin := ports.Actor{ID: uuid.New(), Role: "admin"}
+	superadmin := ports.Actor{ID: uuid.New(), Role: "superadmin"}
+
+	byAuthor := f.create(t, author, "deleted by author")
+	bySuperadmin := f.create(t, author, "deleted by superadmin")
+
+	assertNoteErrorCode(t, f.delete(otherAdmin, byAuthor.ID), "WALLET_NOTE_DELETE_FORBIDDEN")
+
+	if err := f.delete(author, byAuthor.ID); err != nil {
+		t.Fatalf("author delete error = %v", err)
+	}
+	if err := f.delete(superadmin, bySuperadmin.ID); err != nil {
+		t.Fatalf("superadmin delete error = %v", err)
+	}
+
+	// Повторное удаление - заметки уже нет
+	assertNoteErrorCode(t, f.delete(author, byAuthor.ID), "WALLET_NOTE_NOT_FOUND")
+
+	list, err := wallet.NewListWalletNotesUseCase(f.wallets, f.notes).
+		Execute(context.Background(), dtos.ListWalletNotesQuery{WalletID: f.walletID.String(), Limit: 20})
+	if err != nil {
+		t.Fatalf("list error = %v", err)
+	}
+	if len(list.Notes) != 0 {
+		t.Errorf("Expected deleted notes to be hidden, got %d", len(list.Notes))
+	}
+}
+
+func TestSetWalletNotePinnedUseCase_PinnedFirst(t *testing.T) {
+	f := newNotesFixture(t)
+	author := ports.Actor{ID: uuid.New(), Role: "admin"}
+
+	first := f.create(t, author, "first")
+	second := f.create(t, author, "second")
+
+	pinned, err := wallet.NewSetWalletNotePinnedUseCase(f.notes, f.uow).Execute(context.Background(), dtos.SetWalletNotePinnedCommand{
+		WalletID: f.walletID.String(),
+		NoteID:   first.ID,
+		Pinned:   true,
+	})
+	if err != nil {
+		t.Fatalf("pin error = %v", err)
+	}
+	if !pinned.Pinned {
+		t.Error("Expected pinned note")
+	}
+
+	list, err := wallet.NewListWalletNotesUseCase(f.wallets, f.notes).
+		Execute(context.Background(), dtos.ListWalletNotesQuery{WalletID: f.walletID.String(), Limit: 20})
+	if err != nil {
+		t.Fatalf("list error = %v", err)
+	}
+	if len(list.Notes) != 2 || list.Notes[0].ID != first.ID || list.Notes[1].ID != second.ID {
+		t.Errorf("Expected pinned note first, got %+v", list.Notes)
+	}
+
+	// Заметка другого кошелька по этому пути не находится
+	_, err = wallet.NewSetWalletNotePinnedUseCase(f.notes, f.uow).Execute(context.Background(), dtos.SetWalletNotePinnedCommand{
+		WalletID: uuid.NewString(),
+		NoteID:   first.ID,
+	})
+	assertNoteErrorCode(t, err, "WALLET_NOTE_NOT_FOUND")
+}
+
+// assertNoteErrorCode проверяет, что err - DomainError с указанным кодом.
+func assertNoteErrorCode(t *testing.T, err error, code string) {
+	t.Helper()
+
+	domainErr, ok := err.(*domainErrors.DomainError)
+	if !ok || domainErr.Code != code {
+		t.Errorf("Expected DomainError %s, got %v", code, err)
+	}
+}
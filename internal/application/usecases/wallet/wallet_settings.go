@@ -0,0 +1,81 @@
+// Package wallet - UpdateWalletSettings use case: шаблон описания входящих
+// переводов, автотеги и валюта отображения кошелька.
+package wallet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// UpdateWalletSettingsUseCase заменяет настройки представления кошелька.
+//
+// Настройки применяются к операциям после сохранения: уже проведённые
+// транзакции и отрисованные представления переводов не меняются.
+// Ownership проверяется в HTTP handler.
+type UpdateWalletSettingsUseCase struct {
+	walletRepo   ports.WalletReader
+	settingsRepo ports.WalletSettingsRepository
+	uow          ports.UnitOfWork
+}
+
+// NewUpdateWalletSettingsUseCase создаёт новый use case.
+func NewUpdateWalletSettingsUseCase(
+	walletRepo ports.WalletReader,
+	settingsRepo ports.WalletSettingsRepository,
+	uow ports.UnitOfWork,
+) *UpdateWalletSettingsUseCase {
+	return &UpdateWalletSettingsUseCase{
+		walletRepo:   walletRepo,
+		settingsRepo: settingsRepo,
+		uow:          uow,
+	}
+}
+
+// Execute сохраняет настройки и возвращает их.
+//
+// Errors:
+//   - ACTOR_REQUIRED: В context нет инициатора
+//   - WALLET_NOT_FOUND: Кошелёк не найден
+//   - ValidationError: Шаблон длиннее entities.MaxIncomingDescriptionTemplateLength,
+//     больше entities.MaxWalletAutoTags тегов, недопустимый тег или валюта
+//     отображения вне реестра валют
+func (uc *UpdateWalletSettingsUseCase) Execute(ctx context.Context, cmd dtos.UpdateWalletSettingsCommand) (*dtos.WalletSettingsDTO, error) {
+	actor, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "wallet settings update requires an authenticated actor", nil)
+	}
+
+	walletID, err := uuid.Parse(cmd.WalletID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "wallet_id", Message: "invalid UUID"}
+	}
+
+	settings, err := entities.NewWalletSettings(walletID, cmd.IncomingDescriptionTemplate, cmd.AutoTags, cmd.DisplayCurrency, actor.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		if err := ensureWalletExists(txCtx, uc.walletRepo, walletID); err != nil {
+			return err
+		}
+
+		if err := uc.settingsRepo.Save(txCtx, settings); err != nil {
+			return fmt.Errorf("failed to save wallet settings: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dto := dtos.ToWalletSettingsDTO(settings)
+	return &dto, nil
+}
@@ -0,0 +1,78 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// TestWalletSettings_AutoTagsOnCreditAndDebit тестирует, что автотеги
+// кошелька попадают в metadata и пополнений, и списаний.
+func TestWalletSettings_AutoTagsOnCreditAndDebit(t *testing.T) {
+	store := memory.NewStore()
+	wallets := memory.NewWalletRepository(store)
+	transactions := memory.NewTransactionRepository(store)
+	publisher := memory.NewEventPublisher(store)
+	uow := memory.NewUnitOfWork(store)
+	settings := memory.NewWalletSettingsRepository(store)
+
+	owner, err := entities.NewUser("settings-"+uuid.NewString()+"@example.com", "Settings Test")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := memory.NewUserRepository(store).Save(context.Background(), owner); err != nil {
+		t.Fatalf("save user error = %v", err)
+	}
+	target, err := entities.NewWallet(owner.ID(), valueobjects.USD)
+	if err != nil {
+		t.Fatalf("NewWallet() error = %v", err)
+	}
+	if err := wallets.Save(context.Background(), target); err != nil {
+		t.Fatalf("save wallet error = %v", err)
+	}
+
+	update := wallet.NewUpdateWalletSettingsUseCase(wallets, settings, uow)
+	credit := wallet.NewCreditWalletUseCase(wallets, transactions, publisher, uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, settings)
+	debit := wallet.NewDebitWalletUseCase(wallets, transactions, publisher, uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil, nil, settings)
+
+	cmd := dtos.UpdateWalletSettingsCommand{WalletID: target.ID().String(), AutoTags: []string{"payroll", " team:ops ", "payroll"}}
+	if _, err := update.Execute(context.Background(), cmd); err == nil {
+		t.Fatal("Expected ACTOR_REQUIRED without actor")
+	}
+
+	ctx := ports.WithActor(context.Background(), ports.Actor{ID: owner.ID(), Role: "user"})
+	result, err := update.Execute(ctx, cmd)
+	if err != nil {
+		t.Fatalf("UpdateWalletSettings() error = %v", err)
+	}
+	if len(result.AutoTags) != 2 || result.AutoTags[1] != "team:ops" {
+		t.Errorf("AutoTags = %v, want [payroll team:ops]", result.AutoTags)
+	}
+
+	creditResult, err := credit.Execute(ctx, dtos.CreditWalletCommand{WalletID: target.ID().String(), Amount: "100", IdempotencyKey: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("Credit() error = %v", err)
+	}
+	debitResult, err := debit.Execute(ctx, dtos.DebitWalletCommand{WalletID: target.ID().String(), Amount: "10", IdempotencyKey: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("Debit() error = %v", err)
+	}
+
+	for name, txID := range map[string]string{"credit": creditResult.TransactionID, "debit": debitResult.TransactionID} {
+		tx, err := transactions.FindByID(context.Background(), uuid.MustParse(txID))
+		if err != nil {
+			t.Fatalf("FindByID(%s) error = %v", name, err)
+		}
+		if got := tx.Metadata()[entities.WalletTagsMetadataKey]; got != "payroll,team:ops" {
+			t.Errorf("%s %s = %v, want payroll,team:ops", name, entities.WalletTagsMetadataKey, got)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+// Package webhooks - use cases webhook подписок пользователя: создание,
+// просмотр, изменение (включая повторное включение), мягкое удаление и
+// история доставок.
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// Подписка доступна только владельцу: чужая подписка неотличима от
+// несуществующей (WEBHOOK_NOT_FOUND).
+
+// loadOwned загружает подписку владельца из context.
+func loadOwned(ctx context.Context, subs ports.WebhookSubscriptionRepository, rawID string) (*entities.WebhookSubscription, error) {
+	actor, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "webhook subscriptions require an authenticated actor", nil)
+	}
+
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "subscription_id", Message: "invalid UUID"}
+	}
+
+	subscription, err := subs.FindByID(ctx, id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NewDomainError("WEBHOOK_NOT_FOUND", "webhook subscription not found", err)
+		}
+		return nil, fmt.Errorf("failed to load webhook subscription: %w", err)
+	}
+	if subscription.UserID() != actor.ID {
+		return nil, errors.NewDomainError("WEBHOOK_NOT_FOUND", "webhook subscription not found", nil)
+	}
+
+	return subscription, nil
+}
+
+// validateEventTypes проверяет, что все типы доставляются webhooks.
+func validateEventTypes(eventTypes []string) error {
+	for _, eventType := range eventTypes {
+		if !ports.IsWebhookEventType(eventType) {
+			return errors.ValidationError{Field: "event_types", Message: fmt.Sprintf("unsupported event type %q", eventType)}
+		}
+	}
+	return nil
+}
+
+// ============================================
+// Create
+// ============================================
+
+// CreateWebhookUseCase создаёт подписку текущего пользователя.
+type CreateWebhookUseCase struct {
+	subs ports.WebhookSubscriptionRepository
+}
+
+// NewCreateWebhookUseCase создаёт новый use case.
+func NewCreateWebhookUseCase(subs ports.WebhookSubscriptionRepository) *CreateWebhookUseCase {
+	return &CreateWebhookUseCase{subs: subs}
+}
+
+// Execute создаёт активную подписку и возвращает её вместе с секретом.
+//
+// Errors:
+//   - ACTOR_REQUIRED: В context нет пользователя
+//   - ValidationError: Некорректный URL или неподдерживаемый тип события
+//   - USER_NOT_FOUND: Пользователя нет
+func (uc *CreateWebhookUseCase) Execute(ctx context.Context, cmd dtos.CreateWebhookCommand) (*dtos.WebhookSubscriptionCreatedDTO, error) {
+	actor, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "webhook subscriptions require an authenticated actor", nil)
+	}
+
+	if err := validateEventTypes(cmd.EventTypes); err != nil {
+		return nil, err
+	}
+
+	subscription, err := entities.NewWebhookSubscription(actor.ID, cmd.URL, cmd.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.subs.Save(ctx, subscription); err != nil {
+		if errors.IsConcurrencyError(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+
+	return &dtos.WebhookSubscriptionCreatedDTO{
+		WebhookSubscriptionDTO: dtos.ToWebhookSubscriptionDTO(subscription),
+		Secret:                 subscription.Secret(),
+	}, nil
+}
+
+// ============================================
+// List / Get
+// ============================================
+
+// ListWebhooksUseCase возвращает подписки текущего пользователя.
+type ListWebhooksUseCase struct {
+	subs ports.WebhookSubscriptionRepository
+}
+
+// NewListWebhooksUseCase создаёт новый use case.
+func NewListWebhooksUseCase(subs ports.WebhookSubscriptionRepository) *ListWebhooksUseCase {
+	return &ListWebhooksUseCase{subs: subs}
+}
+
+// Execute возвращает неудалённые подписки, новые первыми.
+func (uc *ListWebhooksUseCase) Execute(ctx context.Context, _ dtos.ListWebhooksQuery) (*dtos.WebhookSubscriptionListDTO, error) {
+	actor, ok := ports.ActorFromContext(ctx)
+	if !ok {
+		return nil, errors.NewDomainError("ACTOR_REQUIRED", "webhook subscriptions require an authenticated actor", nil)
+	}
+
+	subscriptions, err := uc.subs.ListByUserID(ctx, actor.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	result := &dtos.WebhookSubscriptionListDTO{Subscriptions: make([]dtos.WebhookSubscriptionDTO, len(subscriptions))}
+	for i, subscription := range subscriptions {
+		result.Subscriptions[i] = dtos.ToWebhookSubscriptionDTO(subscription)
+	}
+	return result, nil
+}
+
+// GetWebhookUseCase возвращает подписку текущего пользователя.
+type GetWebhookUseCase struct {
+	subs ports.WebhookSubscriptionRepository
+}
+
+// NewGetWebhookUseCase создаёт новый use case.
+func NewGetWebhookUseCase(subs ports.WebhookSubscriptionRepository) *GetWebhookUseCase {
+	return &GetWebhookUseCase{subs: subs}
+}
+
+// Execute возвращает подписку.
+//
+// Errors:
+//   - WEBHOOK_NOT_FOUND: Подписки нет, она удалена или чужая
+func (uc *GetWebhookUseCase) Execute(ctx context.Context, query dtos.GetWebhookQuery) (*dtos.WebhookSubscriptionDTO, error) {
+	subscription, err := loadOwned(ctx, uc.subs, query.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := dtos.ToWebhookSubscriptionDTO(subscription)
+	return &dto, nil
+}
+
+// ============================================
+// Update
+// ============================================
+
+// UpdateWebhookUseCase изменяет подписку: URL, типы событий, включение и
+// отключение владельцем.
+//
+// Отключённая подписка (вручную или автоматически) не получает доставок,
+// но события продолжают вставать в её очередь webhooks.backlog_grace после
+// отключения. Повторное включение продолжает очередь с места остановки;
+// с SkipBacklog накопленные доставки отмечаются SKIPPED.
+type UpdateWebhookUseCase struct {
+	subs       ports.WebhookSubscriptionRepository
+	deliveries ports.WebhookDeliveryRepository
+	uow        ports.UnitOfWork
+}
+
+// NewUpdateWebhookUseCase создаёт новый use case.
+func NewUpdateWebhookUseCase(subs ports.WebhookSubscriptionRepository, deliveries ports.WebhookDeliveryRepository, uow ports.UnitOfWork) *UpdateWebhookUseCase {
+	return &UpdateWebhookUseCase{subs: subs, deliveries: deliveries, uow: uow}
+}
+
+// Execute применяет изменения и возвращает новое состояние подписки.
+//
+// Errors:
+//   - WEBHOOK_NOT_FOUND: Подписки нет, она удалена или чужая
+//   - ValidationError: Некорректный URL или неподдерживаемый тип события
+//   - ConcurrencyError: Подписку одновременно изменил worker доставки
+func (uc *UpdateWebhookUseCase) Execute(ctx context.Context, cmd dtos.UpdateWebhookCommand) (*dtos.WebhookSubscriptionDTO, error) {
+	if cmd.EventTypes != nil {
+		if err := validateEventTypes(*cmd.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+
+	var result *dtos.WebhookSubscriptionDTO
+
+	err := uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		subscription, err := loadOwned(txCtx, uc.subs, cmd.SubscriptionID)
+		if err != nil {
+			return err
+		}
+
+		if cmd.URL != nil {
+			if err := subscription.ChangeURL(*cmd.URL); err != nil {
+				return err
+			}
+		}
+		if cmd.EventTypes != nil {
+			if err := subscription.SetEventTypes(*cmd.EventTypes); err != nil {
+				return err
+			}
+		}
+
+		resumed := false
+		if cmd.Active != nil {
+			if *cmd.Active {
+				resumed = !subscription.IsActive()
+				err = subscription.Enable()
+			} else {
+				err = subscription.Disable()
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := uc.subs.Save(txCtx, subscription); err != nil {
+			if errors.IsConcurrencyError(err) {
+				return err
+			}
+			return fmt.Errorf("failed to save webhook subscription: %w", err)
+		}
+
+		if resumed {
+			// Доставки, отложенные backoff до отключения, становятся
+			// доступны сразу: endpoint, вероятно, уже исправлен.
+			if cmd.SkipBacklog {
+				_, err = uc.deliveries.SkipPending(txCtx, subscription.ID())
+			} else {
+				_, err = uc.deliveries.ReschedulePending(txCtx, subscription.ID(), clock.Now())
+			}
+			if err != nil {
+				return fmt.Errorf("failed to resume webhook deliveries: %w", err)
+			}
+		}
+
+		dto := dtos.ToWebhookSubscriptionDTO(subscription)
+		result = &dto
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ============================================
+// Delete
+// ============================================
+
+// DeleteWebhookUseCase мягко удаляет подписку. Очередь доставок
+// пропускается, история остаётся до очистки по webhooks.delivery_retention,
+// после которой подписка удаляется окончательно.
+type DeleteWebhookUseCase struct {
+	subs       ports.WebhookSubscriptionRepository
+	deliveries ports.WebhookDeliveryRepository
+	uow        ports.UnitOfWork
+}
+
+// NewDeleteWebhookUseCase создаёт новый use case.
+func NewDeleteWebhookUseCase(subs ports.WebhookSubscriptionRepository, deliveries ports.WebhookDeliveryRepository, uow ports.UnitOfWork) *DeleteWebhookUseCase {
+	return &DeleteWebhookUseCase{subs: subs, deliveries: deliveries, uow: uow}
+}
+
+// Execute удаляет подписку и возвращает её последнее состояние.
+//
+// Errors:
+//   - WEBHOOK_NOT_FOUND: Подписки нет, она уже удалена или чужая
+func (uc *DeleteWebhookUseCase) Execute(ctx context.Context, cmd dtos.DeleteWebhookCommand) (*dtos.WebhookSubscriptionDTO, error) {
+	var result *dtos.WebhookSubscriptionDTO
+
+	err := uc.uow.Execute(ctx, func(txCtx context.Context) error {
+		subscription, err := loadOwned(txCtx, uc.subs, cmd.SubscriptionID)
+		if err != nil {
+			return err
+		}
+
+		subscription.Delete()
+		if err := uc.subs.Save(txCtx, subscription); err != nil {
+			if errors.IsConcurrencyError(err) {
+				return err
+			}
+			return fmt.Errorf("failed to save webhook subscription: %w", err)
+		}
+
+		if _, err := uc.deliveries.SkipPending(txCtx, subscription.ID()); err != nil {
+			return fmt.Errorf("failed to skip webhook deliveries: %w", err)
+		}
+
+		dto := dtos.ToWebhookSubscriptionDTO(subscription)
+		result = &dto
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ============================================
+// Deliveries
+// ============================================
+
+// ListWebhookDeliveriesUseCase возвращает историю доставок подписки.
+type ListWebhookDeliveriesUseCase struct {
+	subs       ports.WebhookSubscriptionRepository
+	deliveries ports.WebhookDeliveryRepository
+}
+
+// NewListWebhookDeliveriesUseCase создаёт новый use case.
+func NewListWebhookDeliveriesUseCase(subs ports.WebhookSubscriptionRepository, deliveries ports.WebhookDeliveryRepository) *ListWebhookDeliveriesUseCase {
+	return &ListWebhookDeliveriesUseCase{subs: subs, deliveries: deliveries}
+}
+
+// Execute возвращает страницу доставок, новые первыми.
+//
+// Errors:
+//   - WEBHOOK_NOT_FOUND: Подписки нет, она удалена или чужая
+func (uc *ListWebhookDeliveriesUseCase) Execute(ctx context.Context, query dtos.ListWebhookDeliveriesQuery) (*dtos.WebhookDeliveryListDTO, error) {
+	subscription, err := loadOwned(ctx, uc.subs, query.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := uc.deliveries.ListBySubscription(ctx, subscription.ID(), query.Offset, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	result := &dtos.WebhookDeliveryListDTO{
+		Deliveries: make([]dtos.WebhookDeliveryDTO, len(deliveries)),
+		TotalCount: len(deliveries),
+		Offset:     query.Offset,
+		Limit:      query.Limit,
+	}
+	for i, delivery := range deliveries {
+		result.Deliveries[i] = dtos.ToWebhookDeliveryDTO(delivery)
+	}
+	return result, nil
+}
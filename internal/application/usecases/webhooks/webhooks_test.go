@@ -0,0 +1,144 @@
+package webhooks_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/webhooks"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+func TestWebhookUseCases(t *testing.T) {
+	store := memory.NewStore()
+	subs := memory.NewWebhookSubscriptionRepository(store)
+	deliveries := memory.NewWebhookDeliveryRepository(store)
+	uow := memory.NewUnitOfWork(store)
+
+	create := webhooks.NewCreateWebhookUseCase(subs)
+	list := webhooks.NewListWebhooksUseCase(subs)
+	get := webhooks.NewGetWebhookUseCase(subs)
+	update := webhooks.NewUpdateWebhookUseCase(subs, deliveries, uow)
+	remove := webhooks.NewDeleteWebhookUseCase(subs, deliveries, uow)
+	history := webhooks.NewListWebhookDeliveriesUseCase(subs, deliveries)
+
+	newUserContext := func(t *testing.T, email string) context.Context {
+		t.Helper()
+		user, err := entities.NewUser(email, "Webhook Owner")
+		if err != nil {
+			t.Fatalf("NewUser() error = %v", err)
+		}
+		if err := memory.NewUserRepository(store).Save(context.Background(), user); err != nil {
+			t.Fatalf("Save user error = %v", err)
+		}
+		return ports.WithActor(context.Background(), ports.Actor{ID: user.ID(), Role: "user"})
+	}
+	owner := newUserContext(t, "owner@example.com")
+	stranger := newUserContext(t, "stranger@example.com")
+
+	expectCode := func(t *testing.T, err error, code string) {
+		t.Helper()
+		var domainErr *domainErrors.DomainError
+		if !errors.As(err, &domainErr) || domainErr.Code != code {
+			t.Errorf("Expected %s, got %v", code, err)
+		}
+	}
+
+	t.Run("CreateReturnsSecretOnce", func(t *testing.T) {
+		created, err := create.Execute(owner, dtos.CreateWebhookCommand{
+			URL:        "https://example.com/hooks",
+			EventTypes: []string{events.EventTypeTransactionCompleted},
+		})
+		if err != nil {
+			t.Fatalf("Create error = %v", err)
+		}
+		if created.Secret == "" || created.Status != string(entities.WebhookStatusActive) {
+			t.Errorf("Unexpected subscription: %+v", created)
+		}
+
+		listed, err := list.Execute(owner, dtos.ListWebhooksQuery{})
+		if err != nil {
+			t.Fatalf("List error = %v", err)
+		}
+		if len(listed.Subscriptions) != 1 || listed.Subscriptions[0].ID != created.ID {
+			t.Errorf("Unexpected list: %+v", listed)
+		}
+	})
+
+	t.Run("UnsupportedEventType", func(t *testing.T) {
+		_, err := create.Execute(owner, dtos.CreateWebhookCommand{URL: "https://example.com", EventTypes: []string{events.EventTypeWalletCredited}})
+		var validation domainErrors.ValidationError
+		if !errors.As(err, &validation) || validation.Field != "event_types" {
+			t.Errorf("Expected event_types ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("ForeignSubscriptionIsNotFound", func(t *testing.T) {
+		created, err := create.Execute(owner, dtos.CreateWebhookCommand{URL: "https://example.com/private"})
+		if err != nil {
+			t.Fatalf("Create error = %v", err)
+		}
+
+		_, err = get.Execute(stranger, dtos.GetWebhookQuery{SubscriptionID: created.ID})
+		expectCode(t, err, "WEBHOOK_NOT_FOUND")
+		active := false
+		_, err = update.Execute(stranger, dtos.UpdateWebhookCommand{SubscriptionID: created.ID, Active: &active})
+		expectCode(t, err, "WEBHOOK_NOT_FOUND")
+		_, err = remove.Execute(stranger, dtos.DeleteWebhookCommand{SubscriptionID: created.ID})
+		expectCode(t, err, "WEBHOOK_NOT_FOUND")
+	})
+
+	t.Run("ManualDisableAndUpdate", func(t *testing.T) {
+		created, err := create.Execute(owner, dtos.CreateWebhookCommand{URL: "https://example.com/manual"})
+		if err != nil {
+			t.Fatalf("Create error = %v", err)
+		}
+
+		active := false
+		newURL := "https://example.org/hooks"
+		updated, err := update.Execute(owner, dtos.UpdateWebhookCommand{SubscriptionID: created.ID, URL: &newURL, Active: &active})
+		if err != nil {
+			t.Fatalf("Update error = %v", err)
+		}
+		if updated.Status != string(entities.WebhookStatusDisabledManual) || updated.URL != newURL || updated.DisabledAt == nil {
+			t.Errorf("Unexpected subscription: %+v", updated)
+		}
+	})
+
+	t.Run("SoftDeleteKeepsHistory", func(t *testing.T) {
+		created, err := create.Execute(owner, dtos.CreateWebhookCommand{URL: "https://example.com/deleted"})
+		if err != nil {
+			t.Fatalf("Create error = %v", err)
+		}
+		subscriptionID := uuid.MustParse(created.ID)
+		delivery := entities.NewWebhookDelivery(subscriptionID, uuid.New(), events.EventTypeWalletCreated, []byte(`{}`))
+		if err := deliveries.Enqueue(context.Background(), delivery); err != nil {
+			t.Fatalf("Enqueue error = %v", err)
+		}
+
+		if _, err := remove.Execute(owner, dtos.DeleteWebhookCommand{SubscriptionID: created.ID}); err != nil {
+			t.Fatalf("Delete error = %v", err)
+		}
+		_, err = get.Execute(owner, dtos.GetWebhookQuery{SubscriptionID: created.ID})
+		expectCode(t, err, "WEBHOOK_NOT_FOUND")
+
+		kept, err := deliveries.ListBySubscription(context.Background(), subscriptionID, 0, 10)
+		if err != nil {
+			t.Fatalf("ListBySubscription error = %v", err)
+		}
+		if len(kept) != 1 || kept[0].Status() != entities.WebhookDeliverySkipped {
+			t.Errorf("Expected skipped delivery in history, got %d", len(kept))
+		}
+
+		// История удалённой подписки недоступна через API
+		_, err = history.Execute(owner, dtos.ListWebhookDeliveriesQuery{SubscriptionID: created.ID, Limit: 10})
+		expectCode(t, err, "WEBHOOK_NOT_FOUND")
+	})
+}
@@ -0,0 +1,78 @@
+// Package workers - admin use case heartbeat фоновых компонентов.
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// ListWorkersUseCase возвращает heartbeat фоновых компонентов всех экземпляров.
+//
+// Доступ только для admin - проверяется в роутере (группа /admin).
+type ListWorkersUseCase struct {
+	monitor ports.WorkerMonitor // nil - компоненты не регистрируются
+	now     func() time.Time
+}
+
+// NewListWorkersUseCase создаёт новый use case.
+func NewListWorkersUseCase(monitor ports.WorkerMonitor) *ListWorkersUseCase {
+	return &ListWorkersUseCase{
+		monitor: monitor,
+		now:     func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Execute возвращает компоненты с отметкой устаревших heartbeat.
+func (uc *ListWorkersUseCase) Execute(ctx context.Context, _ dtos.ListWorkersQuery) (*dtos.WorkerListDTO, error) {
+	result := &dtos.WorkerListDTO{Workers: []dtos.WorkerDTO{}}
+	if uc.monitor == nil {
+		return result, nil
+	}
+
+	statuses, err := uc.monitor.Workers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	now := uc.now()
+	result.Instance = uc.monitor.Instance()
+	result.Workers = make([]dtos.WorkerDTO, len(statuses))
+	result.TotalCount = len(statuses)
+	for i, s := range statuses {
+		worker := toWorkerDTO(s, now)
+		worker.Local = s.Instance == result.Instance
+		if worker.Stale {
+			result.StaleCount++
+		}
+		result.Workers[i] = worker
+	}
+
+	return result, nil
+}
+
+// toWorkerDTO конвертирует heartbeat в DTO.
+func toWorkerDTO(s ports.WorkerStatus, now time.Time) dtos.WorkerDTO {
+	dto := dtos.WorkerDTO{
+		Name:              s.Worker,
+		Instance:          s.Instance,
+		State:             string(s.State),
+		Stale:             s.Stale(now),
+		LastHeartbeatAt:   s.LastHeartbeatAt.UTC(),
+		ItemsProcessed:    s.ItemsProcessed,
+		LastError:         s.LastError,
+		StaleAfterSeconds: int64(s.StaleAfter.Seconds()),
+	}
+	if s.LastRunAt != nil {
+		t := s.LastRunAt.UTC()
+		dto.LastRunAt = &t
+	}
+	if s.LastSuccessAt != nil {
+		t := s.LastSuccessAt.UTC()
+		dto.LastSuccessAt = &t
+	}
+	return dto
+}
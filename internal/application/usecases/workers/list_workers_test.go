@@ -0,0 +1,90 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/workers"
+)
+
+// fakeMonitor - фиксированные heartbeat двух экземпляров.
+type fakeMonitor struct {
+	instance string
+	statuses []ports.WorkerStatus
+	err      error
+}
+
+func (m *fakeMonitor) Instance() string            { return m.instance }
+func (m *fakeMonitor) Local() []ports.WorkerStatus { return nil }
+func (m *fakeMonitor) Workers(context.Context) ([]ports.WorkerStatus, error) {
+	return m.statuses, m.err
+}
+
+func TestListWorkersUseCase_AggregatesInstances(t *testing.T) {
+	now := time.Now().UTC()
+	lastRun := now.Add(-2 * time.Second)
+	monitor := &fakeMonitor{
+		instance: "api-1",
+		statuses: []ports.WorkerStatus{
+			{
+				Worker: "balance-summary", Instance: "api-1", State: ports.WorkerIdle,
+				LastRunAt: &lastRun, LastSuccessAt: &lastRun, ItemsProcessed: 12,
+				LastHeartbeatAt: now.Add(-time.Second), StaleAfter: 15 * time.Second,
+			},
+			{
+				Worker: "outbox-relay", Instance: "notifier-1", State: ports.WorkerIdle,
+				LastRunAt: &lastRun, LastError: "nats: no responders",
+				LastHeartbeatAt: now.Add(-10 * time.Minute), StaleAfter: time.Minute,
+			},
+			{
+				Worker: "outbox-cleanup", Instance: "api-2", State: ports.WorkerStopped,
+				LastHeartbeatAt: now.Add(-time.Hour), StaleAfter: time.Minute,
+			},
+		},
+	}
+
+	result, err := workers.NewListWorkersUseCase(monitor).Execute(context.Background(), dtos.ListWorkersQuery{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.Instance != "api-1" || result.TotalCount != 3 || result.StaleCount != 1 {
+		t.Fatalf("Unexpected list: %+v", result)
+	}
+
+	summary, relay, cleanup := result.Workers[0], result.Workers[1], result.Workers[2]
+	if !summary.Local || summary.Stale || summary.ItemsProcessed != 12 || summary.StaleAfterSeconds != 15 {
+		t.Errorf("Unexpected local worker: %+v", summary)
+	}
+	if summary.LastSuccessAt == nil || !summary.LastSuccessAt.Equal(lastRun) {
+		t.Errorf("LastSuccessAt = %v, want %v", summary.LastSuccessAt, lastRun)
+	}
+	if relay.Local || !relay.Stale || relay.LastError != "nats: no responders" || relay.LastSuccessAt != nil {
+		t.Errorf("Unexpected remote worker: %+v", relay)
+	}
+	if cleanup.Stale || cleanup.State != "STOPPED" {
+		t.Errorf("Stopped worker must not be stale: %+v", cleanup)
+	}
+}
+
+func TestListWorkersUseCase_WithoutMonitor(t *testing.T) {
+	result, err := workers.NewListWorkersUseCase(nil).Execute(context.Background(), dtos.ListWorkersQuery{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.TotalCount != 0 || result.Workers == nil {
+		t.Errorf("Unexpected list: %+v", result)
+	}
+}
+
+func TestListWorkersUseCase_StoreError(t *testing.T) {
+	monitor := &fakeMonitor{instance: "api-1", err: errors.New("connection refused")}
+
+	if _, err := workers.NewListWorkersUseCase(monitor).Execute(context.Background(), dtos.ListWorkersQuery{}); err == nil {
+		t.Fatal("Execute() error = nil, want store error")
+	}
+}
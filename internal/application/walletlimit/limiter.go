@@ -0,0 +1,226 @@
+// Package walletlimit - in-process ограничение параллельных мутаций кошелька.
+//
+// Limiter реализует ports.WalletLimiter: на каждый кошелёк заводится
+// weighted semaphore ёмкостью MaxInFlight. Семафоры лежат в шардированной
+// map (шард выбирается по UUID), неиспользуемые удаляются по IdleTTL.
+//
+// Работает только в пределах одного инстанса - это дополнение к optimistic
+// locking, а не замена: между инстансами конфликты версий по-прежнему
+// разрешаются retry.
+package walletlimit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/google/uuid"
+	"golang.org/x/sync/semaphore"
+)
+
+// Значения по умолчанию.
+const (
+	DefaultMaxInFlight = 4
+	DefaultMaxWait     = 2 * time.Second
+	DefaultIdleTTL     = 5 * time.Minute
+
+	shardCount = 32
+)
+
+// Config - параметры Limiter.
+type Config struct {
+	MaxInFlight int           // Одновременных мутаций на кошелёк
+	MaxWait     time.Duration // Сколько ждать слот до WALLET_BUSY
+	IdleTTL     time.Duration // Через сколько удалять семафор неиспользуемого кошелька
+}
+
+// DefaultConfig возвращает конфигурацию по умолчанию.
+func DefaultConfig() Config {
+	return Config{
+		MaxInFlight: DefaultMaxInFlight,
+		MaxWait:     DefaultMaxWait,
+		IdleTTL:     DefaultIdleTTL,
+	}
+}
+
+// entry - семафор кошелька и число текущих пользователей записи.
+type entry struct {
+	sem      *semaphore.Weighted
+	refs     int
+	lastUsed time.Time
+}
+
+// shard - часть map семафоров со своим mutex.
+type shard struct {
+	mu        sync.Mutex
+	entries   map[uuid.UUID]*entry
+	lastSweep time.Time
+}
+
+// Limiter - per-wallet limiter на weighted semaphores.
+type Limiter struct {
+	config Config
+	shards [shardCount]*shard
+	now    func() time.Time
+}
+
+// Compile-time check
+var _ ports.WalletLimiter = (*Limiter)(nil)
+
+// NewLimiter создаёт Limiter. Нулевые поля config заменяются значениями по умолчанию.
+func NewLimiter(config Config) *Limiter {
+	if config.MaxInFlight <= 0 {
+		config.MaxInFlight = DefaultMaxInFlight
+	}
+	if config.MaxWait <= 0 {
+		config.MaxWait = DefaultMaxWait
+	}
+	if config.IdleTTL <= 0 {
+		config.IdleTTL = DefaultIdleTTL
+	}
+
+	l := &Limiter{config: config, now: time.Now}
+	for i := range l.shards {
+		l.shards[i] = &shard{entries: make(map[uuid.UUID]*entry)}
+	}
+	return l
+}
+
+// Acquire захватывает по слоту у каждого кошелька в порядке UUID.
+//
+// Ожидание ограничено MaxWait на весь вызов; по истечении уже захваченные
+// слоты освобождаются и возвращается WALLET_BUSY. Отмена ctx возвращает
+// ошибку ctx.
+func (l *Limiter) Acquire(ctx context.Context, walletIDs ...uuid.UUID) (func(), error) {
+	ids := sortedUnique(walletIDs)
+
+	waitCtx, cancel := context.WithTimeout(ctx, l.config.MaxWait)
+	defer cancel()
+
+	acquired := make([]uuid.UUID, 0, len(ids))
+	releaseAll := func() {
+		for i := len(acquired) - 1; i >= 0; i-- {
+			l.release(acquired[i])
+		}
+	}
+
+	for _, id := range ids {
+		sem := l.checkout(id)
+		if err := sem.Acquire(waitCtx, 1); err != nil {
+			l.checkin(id)
+			releaseAll()
+
+			// Отмена вызывающим - не перегрузка кошелька
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, errors.NewDomainError(
+				"WALLET_BUSY",
+				fmt.Sprintf("wallet %s has too many operations in flight, retry later", id),
+				err,
+			)
+		}
+		acquired = append(acquired, id)
+	}
+
+	var once sync.Once
+	return func() { once.Do(releaseAll) }, nil
+}
+
+// release возвращает слот кошелька.
+func (l *Limiter) release(id uuid.UUID) {
+	s := l.shardFor(id)
+	s.mu.Lock()
+	e := s.entries[id]
+	s.mu.Unlock()
+
+	e.sem.Release(1)
+	l.checkin(id)
+}
+
+// checkout возвращает семафор кошелька, создавая его при необходимости.
+// Запись не удаляется, пока refs > 0.
+func (l *Limiter) checkout(id uuid.UUID) *semaphore.Weighted {
+	s := l.shardFor(id)
+	now := l.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l.sweep(s, now)
+
+	e, ok := s.entries[id]
+	if !ok {
+		e = &entry{sem: semaphore.NewWeighted(int64(l.config.MaxInFlight))}
+		s.entries[id] = e
+	}
+	e.refs++
+	e.lastUsed = now
+	return e.sem
+}
+
+// checkin снимает ссылку на запись кошелька.
+func (l *Limiter) checkin(id uuid.UUID) {
+	s := l.shardFor(id)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[id]; ok {
+		e.refs--
+		e.lastUsed = l.now()
+	}
+}
+
+// sweep удаляет записи без пользователей, простаивающие дольше IdleTTL.
+// Выполняется не чаще раза в IdleTTL на шард; вызывается под s.mu.
+func (l *Limiter) sweep(s *shard, now time.Time) {
+	if now.Sub(s.lastSweep) < l.config.IdleTTL {
+		return
+	}
+	s.lastSweep = now
+
+	for id, e := range s.entries {
+		if e.refs == 0 && now.Sub(e.lastUsed) >= l.config.IdleTTL {
+			delete(s.entries, id)
+		}
+	}
+}
+
+// shardFor выбирает шард по UUID (v4 UUID равномерно распределены).
+func (l *Limiter) shardFor(id uuid.UUID) *shard {
+	return l.shards[int(id[len(id)-1])%shardCount]
+}
+
+// size возвращает число записей во всех шардах.
+func (l *Limiter) size() int {
+	total := 0
+	for _, s := range l.shards {
+		s.mu.Lock()
+		total += len(s.entries)
+		s.mu.Unlock()
+	}
+	return total
+}
+
+// sortedUnique возвращает UUID без дубликатов в порядке возрастания.
+func sortedUnique(ids []uuid.UUID) []uuid.UUID {
+	result := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i][:], result[j][:]) < 0
+	})
+	return result
+}
@@ -0,0 +1,236 @@
+package walletlimit
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+func requireWalletBusy(t *testing.T, err error) {
+	t.Helper()
+
+	var domainErr *errors.DomainError
+	require.True(t, stderrors.As(err, &domainErr), "expected DomainError, got %v", err)
+	assert.Equal(t, "WALLET_BUSY", domainErr.Code)
+}
+
+// sameShard возвращает другой UUID из того же шарда, что id.
+func sameShard(id uuid.UUID) uuid.UUID {
+	other := id
+	other[0]++
+	return other
+}
+
+func TestLimiter_Acquire(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("AllowsMaxInFlight", func(t *testing.T) {
+		limiter := NewLimiter(Config{MaxInFlight: 2, MaxWait: 20 * time.Millisecond})
+		wallet := uuid.New()
+
+		release1, err := limiter.Acquire(ctx, wallet)
+		require.NoError(t, err)
+		release2, err := limiter.Acquire(ctx, wallet)
+		require.NoError(t, err)
+
+		_, err = limiter.Acquire(ctx, wallet)
+		requireWalletBusy(t, err)
+
+		release1()
+		release2()
+	})
+
+	t.Run("FailsFastAfterMaxWait", func(t *testing.T) {
+		maxWait := 50 * time.Millisecond
+		limiter := NewLimiter(Config{MaxInFlight: 1, MaxWait: maxWait})
+		wallet := uuid.New()
+
+		release, err := limiter.Acquire(ctx, wallet)
+		require.NoError(t, err)
+		defer release()
+
+		start := time.Now()
+		_, err = limiter.Acquire(ctx, wallet)
+		elapsed := time.Since(start)
+
+		requireWalletBusy(t, err)
+		assert.GreaterOrEqual(t, elapsed, maxWait)
+		assert.Less(t, elapsed, time.Second, "request must not queue forever")
+	})
+
+	t.Run("WaitsForReleasedSlot", func(t *testing.T) {
+		limiter := NewLimiter(Config{MaxInFlight: 1, MaxWait: time.Second})
+		wallet := uuid.New()
+
+		release, err := limiter.Acquire(ctx, wallet)
+		require.NoError(t, err)
+
+		time.AfterFunc(20*time.Millisecond, release)
+
+		second, err := limiter.Acquire(ctx, wallet)
+		require.NoError(t, err)
+		second()
+	})
+
+	t.Run("ReleaseIsIdempotent", func(t *testing.T) {
+		limiter := NewLimiter(Config{MaxInFlight: 1, MaxWait: 20 * time.Millisecond})
+		wallet := uuid.New()
+
+		release, err := limiter.Acquire(ctx, wallet)
+		require.NoError(t, err)
+		release()
+		assert.NotPanics(t, release)
+
+		again, err := limiter.Acquire(ctx, wallet)
+		require.NoError(t, err)
+		again()
+	})
+
+	t.Run("DuplicateWalletsAcquiredOnce", func(t *testing.T) {
+		limiter := NewLimiter(Config{MaxInFlight: 1, MaxWait: 20 * time.Millisecond})
+		wallet := uuid.New()
+
+		release, err := limiter.Acquire(ctx, wallet, wallet)
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("PartialAcquireReleased", func(t *testing.T) {
+		limiter := NewLimiter(Config{MaxInFlight: 1, MaxWait: 20 * time.Millisecond})
+		source, destination := uuid.New(), uuid.New()
+
+		holdDestination, err := limiter.Acquire(ctx, destination)
+		require.NoError(t, err)
+
+		_, err = limiter.Acquire(ctx, source, destination)
+		requireWalletBusy(t, err)
+		holdDestination()
+
+		// Слот source не должен остаться занятым после неудачного перевода
+		release, err := limiter.Acquire(ctx, source)
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("OppositeTransfersDoNotDeadlock", func(t *testing.T) {
+		limiter := NewLimiter(Config{MaxInFlight: 1, MaxWait: 5 * time.Second})
+		a, b := uuid.New(), uuid.New()
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 100)
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(forward bool) {
+				defer wg.Done()
+
+				ids := []uuid.UUID{a, b}
+				if !forward {
+					ids = []uuid.UUID{b, a}
+				}
+				release, err := limiter.Acquire(ctx, ids...)
+				if err != nil {
+					errs <- err
+					return
+				}
+				time.Sleep(100 * time.Microsecond)
+				release()
+			}(i%2 == 0)
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			t.Errorf("Acquire() error = %v", err)
+		}
+	})
+
+	t.Run("CallerCancellation", func(t *testing.T) {
+		limiter := NewLimiter(Config{MaxInFlight: 1, MaxWait: time.Second})
+		wallet := uuid.New()
+
+		release, err := limiter.Acquire(ctx, wallet)
+		require.NoError(t, err)
+		defer release()
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err = limiter.Acquire(cancelled, wallet)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestLimiter_IdleCleanup(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Now()
+	limiter := NewLimiter(Config{MaxInFlight: 1, MaxWait: 20 * time.Millisecond, IdleTTL: time.Minute})
+	limiter.now = func() time.Time { return now }
+
+	idle, held := uuid.New(), uuid.New()
+
+	release, err := limiter.Acquire(ctx, idle)
+	require.NoError(t, err)
+	release()
+
+	holdHeld, err := limiter.Acquire(ctx, held)
+	require.NoError(t, err)
+	defer holdHeld()
+
+	assert.Equal(t, 2, limiter.size())
+
+	// Очистка шарда запускается следующим Acquire в этом шарде
+	now = now.Add(2 * time.Minute)
+	for _, id := range []uuid.UUID{sameShard(idle), sameShard(held)} {
+		release, err := limiter.Acquire(ctx, id)
+		require.NoError(t, err)
+		release()
+	}
+
+	// idle удалён, held занят и остаётся; плюс две новые записи
+	assert.Equal(t, 3, limiter.size())
+
+	_, err = limiter.Acquire(ctx, held)
+	requireWalletBusy(t, err)
+}
+
+func TestAcquireWallets(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("NilLimiter", func(t *testing.T) {
+		release, err := ports.AcquireWallets(ctx, nil, uuid.NewString())
+		require.NoError(t, err)
+		require.NotNil(t, release)
+		release()
+	})
+
+	t.Run("SkipsInvalidIDs", func(t *testing.T) {
+		limiter := NewLimiter(Config{MaxInFlight: 1, MaxWait: 20 * time.Millisecond})
+
+		release, err := ports.AcquireWallets(ctx, limiter, "not-a-uuid")
+		require.NoError(t, err)
+		release()
+		assert.Equal(t, 0, limiter.size())
+	})
+
+	t.Run("LimitsParsedIDs", func(t *testing.T) {
+		limiter := NewLimiter(Config{MaxInFlight: 1, MaxWait: 20 * time.Millisecond})
+		wallet := uuid.NewString()
+
+		release, err := ports.AcquireWallets(ctx, limiter, wallet, "not-a-uuid")
+		require.NoError(t, err)
+		defer release()
+
+		_, err = ports.AcquireWallets(ctx, limiter, wallet)
+		requireWalletBusy(t, err)
+	})
+}
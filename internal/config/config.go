@@ -13,10 +13,16 @@ package config
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/spf13/viper"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/logger"
 )
 
 // ============================================
@@ -28,6 +34,7 @@ type Config struct {
 	App      AppConfig      `mapstructure:"app"`
 	Server   ServerConfig   `mapstructure:"server"`
 	Database DatabaseConfig `mapstructure:"database"`
+	Storage  StorageConfig  `mapstructure:"storage"`
 	Auth     AuthConfig     `mapstructure:"auth"`
 	CORS     CORSConfig     `mapstructure:"cors"`
 	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
@@ -38,6 +45,31 @@ type Config struct {
 	Telemetry TelemetryConfig `mapstructure:"telemetry"`
 	Fraud     FraudConfig     `mapstructure:"fraud"`
 	Redis     RedisConfig     `mapstructure:"redis"`
+	Compliance ComplianceConfig `mapstructure:"compliance"`
+	Security   SecurityConfig   `mapstructure:"security"`
+	Screening  ScreeningConfig  `mapstructure:"screening"`
+	Outbox     OutboxConfig     `mapstructure:"outbox"`
+	Messaging  MessagingConfig  `mapstructure:"messaging"`
+	EmailPolicy EmailPolicyConfig `mapstructure:"email_policy"`
+	WalletMigration WalletMigrationConfig `mapstructure:"wallet_migration"`
+	Jobs            JobsConfig            `mapstructure:"jobs"`
+	Workers         WorkersConfig         `mapstructure:"workers"`
+	Locks           LocksConfig           `mapstructure:"locks"`
+	NewPayee        NewPayeeConfig        `mapstructure:"new_payee"`
+	SensitiveData   SensitiveDataConfig   `mapstructure:"sensitive_data"`
+	BalanceSummary  BalanceSummaryConfig  `mapstructure:"balance_summary"`
+	Transactions    TransactionsConfig    `mapstructure:"transactions"`
+	Currencies      CurrenciesConfig      `mapstructure:"currencies"`
+	Terms           TermsConfig           `mapstructure:"terms"`
+	RequestCapture  RequestCaptureConfig  `mapstructure:"request_capture"`
+	Operations      OperationsConfig      `mapstructure:"operations"`
+	Compression     CompressionConfig     `mapstructure:"compression"`
+	ConcurrencyLimit ConcurrencyLimitConfig `mapstructure:"concurrency_limit"`
+	StatusPage      StatusPageConfig      `mapstructure:"status_page"`
+	Webhooks        WebhooksConfig        `mapstructure:"webhooks"`
+	Onboarding      OnboardingConfig      `mapstructure:"onboarding"`
+	KYC             KYCConfig             `mapstructure:"kyc"`
+	ReadSessions    ReadSessionsConfig    `mapstructure:"read_sessions"`
 }
 
 // ============================================
@@ -52,6 +84,66 @@ type AppConfig struct {
 	Debug       bool   `mapstructure:"debug"`
 	BuildTime   string `mapstructure:"build_time"`
 	GitCommit   string `mapstructure:"git_commit"`
+
+	// SandboxEnabled включает sandbox-эндпоинты для интеграторов (например, сброс данных).
+	// В production игнорируется.
+	SandboxEnabled bool `mapstructure:"sandbox_enabled"`
+
+	// RouteManifestEnabled включает GET /api/v1/meta/routes в staging/production.
+	// В development и sandbox режиме manifest доступен всегда.
+	RouteManifestEnabled bool `mapstructure:"route_manifest_enabled"`
+
+	// LegacyWalletListShape возвращает списки кошельков в прежней форме
+	// {wallets, total_count, offset, limit} + meta вместо {data, pagination}.
+	// Переходный флаг на один релиз, затем будет удалён.
+	LegacyWalletListShape bool `mapstructure:"legacy_wallet_list_shape"`
+
+	// Startup - бюджеты времени на этапы Container.Initialize.
+	Startup StartupConfig `mapstructure:"startup"`
+}
+
+// Бюджеты этапов старта по умолчанию.
+const (
+	DefaultStartupDatabaseTimeout    = 30 * time.Second
+	DefaultStartupSchemaCheckTimeout = 5 * time.Second
+	DefaultStartupCacheTimeout       = 5 * time.Second
+	DefaultStartupMessagingTimeout   = 5 * time.Second
+	DefaultStartupComponentsTimeout  = 5 * time.Second
+)
+
+// StartupConfig - бюджеты времени на этапы старта. Каждый этап получает
+// свой контекст с бюджетом; этап, не уложившийся в бюджет, называется в
+// ошибке старта. Нулевой бюджет - значение по умолчанию.
+type StartupConfig struct {
+	// Timeout - общий дедлайн старта. 0 - сумма бюджетов этапов.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Database - подключение к primary и реплике.
+	Database time.Duration `mapstructure:"database_timeout"`
+	// SchemaCheck - проверка версии схемы (schema_migrations).
+	SchemaCheck time.Duration `mapstructure:"schema_check_timeout"`
+	// Cache - подключение к Redis.
+	Cache time.Duration `mapstructure:"cache_timeout"`
+	// Messaging - шина событий и её подписчики.
+	Messaging time.Duration `mapstructure:"messaging_timeout"`
+	// Components - каждый из остальных этапов (репозитории, фоновые
+	// задачи, политики, API).
+	Components time.Duration `mapstructure:"components_timeout"`
+}
+
+// WithDefaults возвращает копию с бюджетами по умолчанию вместо нулевых.
+func (s StartupConfig) WithDefaults() StartupConfig {
+	orDefault := func(d, def time.Duration) time.Duration {
+		if d <= 0 {
+			return def
+		}
+		return d
+	}
+	s.Database = orDefault(s.Database, DefaultStartupDatabaseTimeout)
+	s.SchemaCheck = orDefault(s.SchemaCheck, DefaultStartupSchemaCheckTimeout)
+	s.Cache = orDefault(s.Cache, DefaultStartupCacheTimeout)
+	s.Messaging = orDefault(s.Messaging, DefaultStartupMessagingTimeout)
+	s.Components = orDefault(s.Components, DefaultStartupComponentsTimeout)
+	return s
 }
 
 // IsDevelopment возвращает true если окружение development.
@@ -64,6 +156,16 @@ func (c *AppConfig) IsProduction() bool {
 	return c.Environment == "production"
 }
 
+// IsSandbox возвращает true если sandbox режим включён и окружение не production.
+func (c *AppConfig) IsSandbox() bool {
+	return c.SandboxEnabled && !c.IsProduction()
+}
+
+// IsRouteManifestEnabled возвращает true если route manifest должен быть зарегистрирован.
+func (c *AppConfig) IsRouteManifestEnabled() bool {
+	return c.IsDevelopment() || c.IsSandbox() || c.RouteManifestEnabled
+}
+
 // ============================================
 // Server Configuration
 // ============================================
@@ -99,6 +201,8 @@ type DatabaseConfig struct {
 	MinConnections  int32         `mapstructure:"min_connections"`
 	MaxConnLifetime time.Duration `mapstructure:"max_conn_lifetime"`
 	MaxConnIdleTime time.Duration `mapstructure:"max_conn_idle_time"`
+
+	Replica ReadReplicaConfig `mapstructure:"replica"`
 }
 
 // DSN возвращает строку подключения к PostgreSQL.
@@ -114,6 +218,93 @@ func (c *DatabaseConfig) DSN() string {
 	)
 }
 
+// ReplicaDSN возвращает строку подключения к реплике: те же учётные
+// данные и база, другой хост.
+func (c *DatabaseConfig) ReplicaDSN() string {
+	port := c.Replica.Port
+	if port == 0 {
+		port = c.Port
+	}
+	return fmt.Sprintf(
+		"postgres://%s:%s@%s:%d/%s?sslmode=%s",
+		c.User,
+		c.Password,
+		c.Replica.Host,
+		port,
+		c.Database,
+		c.SSLMode,
+	)
+}
+
+// ReadReplicaConfig - реплика для read-only запросов репозиториев.
+//
+// Пустой host - реплики нет, всё читается с primary.
+type ReadReplicaConfig struct {
+	Host           string `mapstructure:"host"`
+	Port           int    `mapstructure:"port"`            // 0 - порт primary
+	MaxConnections int32  `mapstructure:"max_connections"` // 0 - как у primary
+
+	// Hedging: если реплика не ответила за hedge_delay, запрос дублируется
+	// на primary и берётся первый ответ.
+	HedgeDelay         time.Duration   `mapstructure:"hedge_delay"`          // 0 - hedging выключен
+	HedgeMaxConcurrent int             `mapstructure:"hedge_max_concurrent"` // Лимит одновременных hedge-запросов на primary
+	HedgeGroups        map[string]bool `mapstructure:"hedge_groups"`         // wallet_reads, wallet_lists
+}
+
+// Enabled сообщает, настроена ли реплика.
+func (c ReadReplicaConfig) Enabled() bool {
+	return c.Host != ""
+}
+
+// validate проверяет группы и лимиты hedging.
+func (c ReadReplicaConfig) validate() error {
+	if c.HedgeDelay < 0 {
+		return fmt.Errorf("database.replica.hedge_delay must not be negative")
+	}
+	if c.HedgeDelay > 0 && c.HedgeMaxConcurrent <= 0 {
+		return fmt.Errorf("database.replica.hedge_max_concurrent must be positive when hedging is enabled")
+	}
+	for group := range c.HedgeGroups {
+		switch group {
+		case "wallet_reads", "wallet_lists":
+		default:
+			return fmt.Errorf("unknown database.replica.hedge_groups entry %q", group)
+		}
+	}
+	return nil
+}
+
+// ============================================
+// Storage Configuration
+// ============================================
+
+// Хранилища репозиториев (storage.backend).
+const (
+	StorageBackendPostgres = "postgres" // единственное хранилище для production
+	StorageBackendMemory   = "memory"   // standalone: без внешних зависимостей, данные живут до перезапуска
+)
+
+// StorageConfig - где живут данные репозиториев.
+//
+// memory - standalone профиль для локального запуска и smoke-тестов CI:
+// API поднимается без PostgreSQL, миграции не нужны. Воркеры работают в
+// пределах одного процесса (ports.StorageCapabilities), в production
+// профиль запрещён.
+//
+// StrictUoW - запрос к репозиторию мимо активной транзакции UnitOfWork
+// паникует вместо записи в лог (ports.UnitOfWorkGuard). Для CI и
+// интеграционных тестов; в production запрещён. Без него проверка
+// включена только в development.
+type StorageConfig struct {
+	Backend   string `mapstructure:"backend"` // postgres (по умолчанию) или memory
+	StrictUoW bool   `mapstructure:"strict_uow"`
+}
+
+// IsMemory сообщает, выбран ли standalone профиль.
+func (c StorageConfig) IsMemory() bool {
+	return c.Backend == StorageBackendMemory
+}
+
 // ============================================
 // Auth Configuration
 // ============================================
@@ -126,6 +317,29 @@ type AuthConfig struct {
 	TelegramBotToken  string        `mapstructure:"telegram_bot_token"` // Telegram bot token for Mini App auth
 }
 
+// ============================================
+// Email Policy Configuration
+// ============================================
+
+// EmailPolicyConfig - какие email считаются одним ящиком при проверке уникальности.
+//
+// Менять на живой базе вместе с пересчётом users.normalized_email:
+// миграция заполнила колонку по политике по умолчанию.
+type EmailPolicyConfig struct {
+	StripPlusTags bool     `mapstructure:"strip_plus_tags"`
+	StripDots     bool     `mapstructure:"strip_dots"`
+	Providers     []string `mapstructure:"providers"` // домены, для которых применяются правила выше
+}
+
+// Policy возвращает доменную политику нормализации email.
+func (c *EmailPolicyConfig) Policy() valueobjects.EmailPolicy {
+	return valueobjects.EmailPolicy{
+		StripPlusTags: c.StripPlusTags,
+		StripDots:     c.StripDots,
+		Providers:     c.Providers,
+	}
+}
+
 // ============================================
 // CORS Configuration
 // ============================================
@@ -151,6 +365,12 @@ type RateLimitConfig struct {
 	BurstSize            int           `mapstructure:"burst_size"`
 	FinancialOpsPerMin   int           `mapstructure:"financial_ops_per_min"`
 	CleanupInterval      time.Duration `mapstructure:"cleanup_interval"`
+
+	// Per-wallet limiter: сколько мутаций одного кошелька выполняется
+	// одновременно и сколько ждать свободный слот до WALLET_BUSY.
+	WalletMaxInFlight int           `mapstructure:"wallet_max_in_flight"`
+	WalletMaxWait     time.Duration `mapstructure:"wallet_max_wait"`
+	WalletIdleTTL     time.Duration `mapstructure:"wallet_idle_ttl"`
 }
 
 // ============================================
@@ -167,6 +387,11 @@ type LogConfig struct {
 	MaxBackups int    `mapstructure:"max_backups"` // количество файлов
 	MaxAge     int    `mapstructure:"max_age"`     // дней
 	Compress   bool   `mapstructure:"compress"`
+
+	// Components переопределяет уровень для отдельных компонентов
+	// (logger.WithComponent): имя -> debug, info, warn, error. Уровни
+	// перечитываются по SIGHUP без рестарта.
+	Components map[string]string `mapstructure:"components"`
 }
 
 // ============================================
@@ -189,6 +414,59 @@ type NotifierConfig struct {
 	PollInterval time.Duration `mapstructure:"poll_interval"`
 	BatchSize    int           `mapstructure:"batch_size"`
 	MaxRetries   int           `mapstructure:"max_retries"`
+
+	// Отдельный цикл для HIGH полосы outbox; 0 - без отдельного цикла
+	HighPriorityPollInterval time.Duration `mapstructure:"high_priority_poll_interval"`
+	HighPriorityBatchSize    int           `mapstructure:"high_priority_batch_size"`
+
+	MetricsPort int `mapstructure:"metrics_port"` // /metrics сервиса; 0 - не поднимать
+}
+
+// ============================================
+// Outbox Configuration
+// ============================================
+
+// OutboxConfig - полосы доставки событий outbox.
+//
+// EventPriorities переопределяет маппинг по умолчанию
+// (ports.DefaultOutboxPriorities): event type -> HIGH, NORMAL или LOW.
+type OutboxConfig struct {
+	EventPriorities map[string]string `mapstructure:"event_priorities"`
+}
+
+// ============================================
+// Messaging Configuration
+// ============================================
+
+// MessagingConfig - доставка domain events.
+//
+// Delivery:
+//   - outbox (по умолчанию): события пишутся в outbox в транзакции,
+//     notifier доставляет их асинхронно
+//   - sync: outbox не используется, закоммиченные события раздаются
+//     in-process подписчикам до ответа на запрос, не дольше SyncBudget
+//   - none: события отбрасываются (эфемерные окружения, не production)
+//
+// DedupRetention - сколько хранятся отметки обработанных событий
+// (processed_events). Повтор, доставленный позже, будет обработан снова.
+// Очистку выполняет задача processed-events-purge планировщика.
+//
+// Spool - журнал закоммиченных, но ещё не обработанных in-process
+// подписчиками событий на локальном диске (для инсталляций без outbox relay).
+type MessagingConfig struct {
+	Delivery       string           `mapstructure:"delivery"`
+	SyncBudget     time.Duration    `mapstructure:"sync_budget"`     // лимит обработки событий одного COMMIT в режиме sync
+	DedupRetention time.Duration    `mapstructure:"dedup_retention"` // 0 = хранить бессрочно
+	Spool          EventSpoolConfig `mapstructure:"spool"`
+}
+
+// EventSpoolConfig - журнал событий для повторной раздачи после рестарта.
+// Выключен по умолчанию.
+type EventSpoolConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	Dir        string `mapstructure:"dir"`
+	MaxEntries int    `mapstructure:"max_entries"` // при переполнении вытесняются самые старые записи
+	MaxReplays int    `mapstructure:"max_replays"` // запись, не обработанная за столько рестартов, удаляется
 }
 
 // ============================================
@@ -218,10 +496,551 @@ type FraudConfig struct {
 
 // ExchangeConfig - конфигурация сервиса обмена валют.
 type ExchangeConfig struct {
-	APIKey        string        `mapstructure:"api_key"`
-	APIURL        string        `mapstructure:"api_url"`
-	CacheTTL      time.Duration `mapstructure:"cache_ttl"`
-	SpreadPercent float64       `mapstructure:"spread_percent"`
+	APIKey            string        `mapstructure:"api_key"`
+	APIURL            string        `mapstructure:"api_url"`
+	CacheTTL          time.Duration `mapstructure:"cache_ttl"`
+	SpreadPercent     float64       `mapstructure:"spread_percent"`
+	// SnapshotRetention - срок хранения снимков курсов (fx_rate_snapshots),
+	// по которым выполнены обмены; 0 - хранить бессрочно
+	SnapshotRetention time.Duration `mapstructure:"snapshot_retention"`
+	// DisplayCacheTTL - время жизни курсов для пересчёта балансов в валюту
+	// отображения кошелька (только для показа)
+	DisplayCacheTTL time.Duration `mapstructure:"display_cache_ttl"`
+}
+
+// ============================================
+// Compliance Configuration
+// ============================================
+
+// ComplianceConfig - юрисдикции и сроки хранения данных.
+//
+// Коды юрисдикций - ISO 3166-1 alpha-2. Ключи retention_months
+// нормализуются в верхний регистр при создании политики.
+type ComplianceConfig struct {
+	AllowedJurisdictions   []string       `mapstructure:"allowed_jurisdictions"` // пусто = любые
+	DefaultJurisdiction    string         `mapstructure:"default_jurisdiction"`  // fallback для записей без тега
+	RetentionMonths        map[string]int `mapstructure:"retention_months"`      // юрисдикция -> месяцы
+	DefaultRetentionMonths int            `mapstructure:"default_retention_months"`
+}
+
+// ============================================
+// Security Configuration
+// ============================================
+
+// SecurityConfig - журнал событий аутентификации и алерт на подбор.
+//
+// SuspiciousAuthActivity публикуется, когда у одного principal больше
+// AuthFailureThreshold неудачных попыток за AuthFailureWindow.
+type SecurityConfig struct {
+	EventQueueSize       int           `mapstructure:"event_queue_size"` // очередь асинхронной записи
+	AuthFailureThreshold int           `mapstructure:"auth_failure_threshold"`
+	AuthFailureWindow    time.Duration `mapstructure:"auth_failure_window"`
+	EventRetention       time.Duration `mapstructure:"event_retention"` // 0 = хранить бессрочно
+}
+
+// ============================================
+// Screening Configuration
+// ============================================
+
+// ScreeningConfig - правила скрининга транзакций (block / flag).
+//
+// Правила из конфигурации и (при LoadFromDatabase) из таблицы
+// screening_rules объединяются и валидируются при старте: невалидное
+// правило - ошибка запуска.
+type ScreeningConfig struct {
+	Enabled          bool                  `mapstructure:"enabled"`
+	LoadFromDatabase bool                  `mapstructure:"load_from_database"` // дополнить правилами из screening_rules
+	Rules            []ports.ScreeningRule `mapstructure:"rules"`
+}
+
+// ============================================
+// Wallet Migration Configuration
+// ============================================
+
+// WalletMigrationConfig - перенос балансов кошельков в wallet_balances / wallet_holds.
+//
+// Phase проходится по порядку: off -> dual_write -> shadow_read_compare ->
+// new_read -> new_only. Пока пишутся обе схемы, выборка кошельков
+// периодически сверяется (CompareInterval, CompareSampleSize).
+type WalletMigrationConfig struct {
+	Phase             string        `mapstructure:"phase"`
+	NewReadPercent    int           `mapstructure:"new_read_percent"` // new_read: доля кошельков 0-100, читаемых из новой схемы
+	CompareInterval   time.Duration `mapstructure:"compare_interval"`
+	CompareSampleSize int           `mapstructure:"compare_sample_size"`
+}
+
+// ============================================
+// New Payee Configuration
+// ============================================
+
+// NewPayeeConfig - ограничения первого перевода на кошелёк, на который
+// источник ещё не переводил (защита от увода средств со взломанного аккаунта).
+type NewPayeeConfig struct {
+	Enabled             bool   `mapstructure:"enabled"`
+	RequireConfirmation bool   `mapstructure:"require_confirmation"` // нужен confirm_new_payee=true
+	MaxAmount           string `mapstructure:"max_amount"`           // на все переводы новому получателю, "" - без лимита
+	LimitCurrency       string `mapstructure:"limit_currency"`       // валюта max_amount, остальные конвертируются
+	// Window - получатель остаётся новым столько после первого перевода
+	Window time.Duration `mapstructure:"window"`
+}
+
+// ============================================
+// Sensitive Data Configuration
+// ============================================
+
+// SensitiveDataConfig - обработка номеров карт (PAN) и IBAN в external
+// reference и metadata транзакций.
+type SensitiveDataConfig struct {
+	// Strict отклоняет такие запросы (SENSITIVE_DATA) вместо маскирования
+	Strict bool `mapstructure:"strict"`
+}
+
+// ============================================
+// Transactions Configuration
+// ============================================
+
+// TransactionsConfig - ограничения на создание транзакций.
+type TransactionsConfig struct {
+	// MaxPendingPerWallet - сколько PENDING/PROCESSING транзакций может
+	// одновременно висеть на кошельке; 0 - без лимита. Админ может
+	// переопределить лимит для кошелька (wallets.max_pending_transactions).
+	MaxPendingPerWallet int `mapstructure:"max_pending_per_wallet"`
+
+	// BulkMaxItems - максимум переводов в POST /wallets/{id}/transfers/bulk.
+	// 0 - без ограничения.
+	BulkMaxItems int `mapstructure:"bulk_max_items"`
+
+	// BulkGroupSize - сколько переводов пакета выполняется в одной
+	// транзакции БД. Ошибка перевода откатывает только его группу, после
+	// чего переводы группы выполняются по одному. 0 - каждый перевод
+	// в своей транзакции.
+	BulkGroupSize int `mapstructure:"bulk_group_size"`
+
+	// MetadataSoftLimitBytes - суммарный объём metadata транзакций кошелька,
+	// выше которого ответ на создание транзакции несёт предупреждение, а при
+	// первом превышении публикуется WalletMetadataQuotaWarning. 0 - выключено.
+	MetadataSoftLimitBytes int64 `mapstructure:"metadata_soft_limit_bytes"`
+
+	// MetadataHardLimitBytes - объём, выше которого непустая metadata
+	// отклоняется (METADATA_QUOTA_EXCEEDED). 0 - выключено.
+	MetadataHardLimitBytes int64 `mapstructure:"metadata_hard_limit_bytes"`
+
+	// ExportMaxRows - предел строк CSV выгрузки списка транзакций
+	// (GET /api/v1/transactions с Accept: text/csv); дальше выгрузка
+	// усекается с X-Truncated: true.
+	ExportMaxRows int `mapstructure:"export_max_rows"`
+}
+
+// ============================================
+// Currencies Configuration
+// ============================================
+
+// CurrenciesConfig - валюты новых кошельков и лимиты суммы транзакции.
+// Вместе с реестром валют отдаётся клиентам в GET /api/v1/meta/currencies.
+//
+// Коды валют нормализуются в верхний регистр при создании политики.
+type CurrenciesConfig struct {
+	// WalletCurrencies - в каких валютах можно открыть кошелёк; пусто - во
+	// всех поддерживаемых. Существующие кошельки работают в любой валюте.
+	WalletCurrencies []string `mapstructure:"wallet_currencies"`
+
+	// Limits - минимальная и максимальная сумма одной транзакции по кодам валют
+	Limits map[string]CurrencyLimitConfig `mapstructure:"limits"`
+}
+
+// CurrencyLimitConfig - лимиты суммы одной транзакции ("" - без лимита).
+type CurrencyLimitConfig struct {
+	MinAmount string `mapstructure:"min_amount"`
+	MaxAmount string `mapstructure:"max_amount"`
+}
+
+// Policy создаёт ports.CurrencyPolicy из конфигурации.
+func (c CurrenciesConfig) Policy() ports.CurrencyPolicy {
+	policy := ports.CurrencyPolicy{Limits: make(map[string]ports.AmountLimits, len(c.Limits))}
+	for _, code := range c.WalletCurrencies {
+		policy.WalletCurrencies = append(policy.WalletCurrencies, strings.ToUpper(strings.TrimSpace(code)))
+	}
+	for code, limit := range c.Limits {
+		policy.Limits[strings.ToUpper(code)] = ports.AmountLimits{Min: limit.MinAmount, Max: limit.MaxAmount}
+	}
+	return policy
+}
+
+// ============================================
+// KYC Configuration
+// ============================================
+
+// KYCConfig - проверка KYC владельца при выводе средств с кошелька.
+//
+// Enforcement: lenient - операция проходит, транзакция помечается
+// (metadata kyc_unverified_owner, событие transaction.flagged); strict -
+// списания, выводы, выплаты и исходящие переводы отклоняются с
+// USER_KYC_NOT_VERIFIED. Пополнения не проверяются.
+type KYCConfig struct {
+	Enforcement    string        `mapstructure:"enforcement"`      // lenient | strict
+	StatusCacheTTL time.Duration `mapstructure:"status_cache_ttl"` // 0 - статус читается при каждой операции
+}
+
+// validate проверяет режим и TTL кэша статусов.
+func (c KYCConfig) validate() error {
+	switch strings.ToLower(strings.TrimSpace(c.Enforcement)) {
+	case "", "lenient", "strict":
+	default:
+		return fmt.Errorf("kyc.enforcement must be lenient or strict: %q", c.Enforcement)
+	}
+	if c.StatusCacheTTL < 0 {
+		return fmt.Errorf("kyc.status_cache_ttl must not be negative: %s", c.StatusCacheTTL)
+	}
+	return nil
+}
+
+// ============================================
+// Read Sessions Configuration
+// ============================================
+
+// maxReadSessionTTL - верхняя граница TTL сессии чтения: долгая транзакция
+// REPEATABLE READ задерживает VACUUM на primary и конфликтует с
+// воспроизведением WAL на реплике.
+const maxReadSessionTTL = 5 * time.Minute
+
+// ReadSessionsConfig - snapshot-сессии чтения (POST /api/v1/read-sessions).
+//
+// Каждая сессия держит соединение пула (реплики, если она настроена),
+// поэтому max_total должен оставлять соединения обычным запросам.
+type ReadSessionsConfig struct {
+	TTL          time.Duration `mapstructure:"ttl"`
+	MaxPerClient int           `mapstructure:"max_per_client"` // На пользователя
+	MaxTotal     int           `mapstructure:"max_total"`      // На инстанс
+}
+
+// validate проверяет лимиты; poolMax - размер пула, из которого берутся
+// соединения сессий. Нулевые значения - значения по умолчанию.
+func (c ReadSessionsConfig) validate(poolMax int32) error {
+	if c.TTL < 0 || c.TTL > maxReadSessionTTL {
+		return fmt.Errorf("read_sessions.ttl must be in [0, %s]: %s", maxReadSessionTTL, c.TTL)
+	}
+	if c.MaxPerClient < 0 || c.MaxTotal < 0 {
+		return fmt.Errorf("read_sessions.max_per_client and max_total must not be negative: %d, %d", c.MaxPerClient, c.MaxTotal)
+	}
+	if poolMax > 0 && int64(c.MaxTotal) >= int64(poolMax) {
+		return fmt.Errorf("read_sessions.max_total (%d) must be less than the connection pool size (%d)", c.MaxTotal, poolMax)
+	}
+	return nil
+}
+
+// ============================================
+// Onboarding Configuration
+// ============================================
+
+// OnboardingConfig - партнёрский onboarding (POST /api/v1/onboarding).
+type OnboardingConfig struct {
+	// TrustedPartners - ID партнёров, которым разрешено одобрять KYC при
+	// onboarding; дополнительно нужна capability trusted_partner в токене
+	TrustedPartners []string `mapstructure:"trusted_partners"`
+}
+
+// TrustedPartnerIDs возвращает ID доверенных партнёров (после Validate).
+func (c OnboardingConfig) TrustedPartnerIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(c.TrustedPartners))
+	for _, raw := range c.TrustedPartners {
+		if id, err := uuid.Parse(strings.TrimSpace(raw)); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// validate проверяет ID доверенных партнёров.
+func (c OnboardingConfig) validate() error {
+	for _, raw := range c.TrustedPartners {
+		if _, err := uuid.Parse(strings.TrimSpace(raw)); err != nil {
+			return fmt.Errorf("onboarding.trusted_partners: invalid partner ID %q", raw)
+		}
+	}
+	return nil
+}
+
+// validate проверяет коды валют и суммы лимитов.
+func (c CurrenciesConfig) validate() error {
+	for _, code := range c.WalletCurrencies {
+		if _, err := valueobjects.NewCurrency(code); err != nil {
+			return fmt.Errorf("currencies.wallet_currencies: unsupported currency %q", code)
+		}
+	}
+	for code, limit := range c.Limits {
+		currency, err := valueobjects.NewCurrency(code)
+		if err != nil {
+			return fmt.Errorf("currencies.limits: unsupported currency %q", code)
+		}
+		parse := func(field, value string) (*valueobjects.Money, error) {
+			if value == "" {
+				return nil, nil
+			}
+			amount, err := valueobjects.NewMoney(value, currency)
+			if err != nil || !amount.IsPositive() {
+				return nil, fmt.Errorf("currencies.limits.%s.%s must be a positive amount: %q", code, field, value)
+			}
+			return &amount, nil
+		}
+		min, err := parse("min_amount", limit.MinAmount)
+		if err != nil {
+			return err
+		}
+		max, err := parse("max_amount", limit.MaxAmount)
+		if err != nil {
+			return err
+		}
+		if min != nil && max != nil {
+			greater, err := min.GreaterThan(*max)
+			if err != nil {
+				return fmt.Errorf("currencies.limits.%s: %w", code, err)
+			}
+			if greater {
+				return fmt.Errorf("currencies.limits.%s: min_amount %s exceeds max_amount %s", code, limit.MinAmount, limit.MaxAmount)
+			}
+		}
+	}
+	return nil
+}
+
+// ============================================
+// Terms of Service Configuration
+// ============================================
+
+// TermsConfig - версия условий обслуживания, которую пользователь должен
+// принять до списаний, переводов и выплат (POST /users/{id}/accept-terms).
+type TermsConfig struct {
+	// RequiredVersion - актуальная версия ToS; 0 - проверка выключена.
+	// Повышается при публикации новой редакции.
+	RequiredVersion int `mapstructure:"required_version"`
+
+	// GrandfatheredVersion засчитывается пользователям, созданным до учёта
+	// ToS (users.tos_version IS NULL), пока они не примут ToS явно.
+	GrandfatheredVersion int `mapstructure:"grandfathered_version"`
+}
+
+// ============================================
+// Request Capture Configuration
+// ============================================
+
+// RequestCaptureConfig - образцы неудачных денежных операций для поддержки.
+//
+// Запрос и ответ каждой мутирующей операции с кошельками и транзакциями,
+// завершившейся 4xx/5xx (кроме 401/403/429), сохраняются в failed_requests
+// после очистки: значения SensitiveFields заменяются на [REDACTED], номера
+// карт и IBAN маскируются. Очистка и запись асинхронные.
+type RequestCaptureConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	Retention       time.Duration `mapstructure:"retention"`        // 0 = хранить бессрочно
+	MaxBodyBytes    int           `mapstructure:"max_body_bytes"`   // лимит каждого тела, длиннее - обрезается
+	QueueSize       int           `mapstructure:"queue_size"`       // очередь асинхронной записи
+	SensitiveFields []string      `mapstructure:"sensitive_fields"` // имена полей JSON без учёта регистра
+}
+
+// DefaultSensitiveFields - поля JSON, маскируемые в образцах по умолчанию.
+var DefaultSensitiveFields = []string{
+	"password", "secret", "token", "access_token", "refresh_token",
+	"pin", "cvv", "cvc", "card_number", "pan", "iban", "account_number",
+}
+
+// ============================================
+// Operation Switches Configuration
+// ============================================
+
+// OperationsConfig - аварийные выключатели видов операций
+// (PUT /api/v1/admin/switches/{type}). Состояние хранится в system_settings,
+// каждый экземпляр перечитывает его раз в RefreshInterval.
+type OperationsConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+}
+
+// ============================================
+// Status Page Configuration
+// ============================================
+
+// StatusPageConfig - публичная страница статуса (GET /status).
+// Состояние вычисляется в фоне раз в RefreshInterval и отдаётся из памяти,
+// поэтому страница отвечает и при недоступной БД. Инциденты задаются
+// через POST /api/v1/admin/incidents.
+type StatusPageConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	CacheMaxAge     time.Duration `mapstructure:"cache_max_age"` // Cache-Control max-age ответа, 0 - без заголовка
+}
+
+// ============================================
+// Webhooks Configuration
+// ============================================
+
+// WebhooksConfig - доставка событий на webhook подписки пользователей
+// (/api/v1/webhooks).
+//
+// Подписка отключается автоматически (DISABLED_AUTO) после
+// AutoDisableFailures неудач подряд или AutoDisableAfter непрерывных
+// неудач; 0 выключает критерий. Отключённая подписка копит очередь
+// BacklogGrace, затем старые доставки помечаются EXPIRED. Завершённые
+// доставки и удалённые подписки хранятся DeliveryRetention.
+type WebhooksConfig struct {
+	Enabled             bool          `mapstructure:"enabled"`
+	DeliveryInterval    time.Duration `mapstructure:"delivery_interval"`
+	BatchSize           int           `mapstructure:"batch_size"`
+	RequestTimeout      time.Duration `mapstructure:"request_timeout"`
+	MaxAttempts         int           `mapstructure:"max_attempts"` // попыток одной доставки до FAILED
+	AutoDisableFailures int           `mapstructure:"auto_disable_failures"`
+	AutoDisableAfter    time.Duration `mapstructure:"auto_disable_after"`
+	BacklogGrace        time.Duration `mapstructure:"backlog_grace"`
+	DeliveryRetention   time.Duration `mapstructure:"delivery_retention"` // 0 = хранить бессрочно
+}
+
+// ============================================
+// Compression Configuration
+// ============================================
+
+// CompressionConfig - сжатие HTTP ответов по Accept-Encoding.
+//
+// Ответы короче MinSize, уже сжатые форматы (изображения, архивы, PDF),
+// HEAD, 204 и 304 не сжимаются. zstd предлагается только при Zstd=true,
+// gzip - всегда.
+type CompressionConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	MinSize int  `mapstructure:"min_size"` // байт тела, с которых начинается сжатие
+	Zstd    bool `mapstructure:"zstd"`
+}
+
+// ============================================
+// Concurrency Limit Configuration
+// ============================================
+
+// ConcurrencyClasses - классы маршрутов concurrency limit
+// (routes.ConcurrencyRead, ConcurrencyMutation, ConcurrencyReport).
+var ConcurrencyClasses = []string{"read", "mutation", "report"}
+
+// ConcurrencyLimitConfig - ограничение одновременных запросов /api/v1.
+//
+// Каждый клиент (пользователь или IP) держит в обработке не больше
+// MaxPerClient запросов класса; запрос класса занимает Weight единиц
+// общего лимита инстанса GlobalMax. Сверх лимита запрос ждёт MaxWait и
+// получает 429 CONCURRENCY_LIMIT_EXCEEDED. В отличие от rate limit
+// ограничивается не частота, а число запросов в обработке.
+type ConcurrencyLimitConfig struct {
+	Enabled   bool                              `mapstructure:"enabled"`
+	GlobalMax int64                             `mapstructure:"global_max"` // суммарный вес запросов в обработке
+	MaxWait   time.Duration                     `mapstructure:"max_wait"`   // отрицательное - отказ без ожидания
+	IdleTTL   time.Duration                     `mapstructure:"idle_ttl"`   // удаление семафоров неактивных клиентов
+	Classes   map[string]ConcurrencyClassConfig `mapstructure:"classes"`
+}
+
+// ConcurrencyClassConfig - лимиты класса маршрутов.
+type ConcurrencyClassConfig struct {
+	MaxPerClient int64 `mapstructure:"max_per_client"`
+	Weight       int64 `mapstructure:"weight"`
+}
+
+// validate проверяет лимиты классов.
+func (c ConcurrencyLimitConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.GlobalMax <= 0 {
+		return fmt.Errorf("concurrency_limit.global_max must be positive: %d", c.GlobalMax)
+	}
+	for name, class := range c.Classes {
+		if !slices.Contains(ConcurrencyClasses, name) {
+			return fmt.Errorf("unknown concurrency_limit class %q (want one of %v)", name, ConcurrencyClasses)
+		}
+		if class.MaxPerClient <= 0 || class.Weight <= 0 {
+			return fmt.Errorf("concurrency_limit.classes.%s: max_per_client and weight must be positive", name)
+		}
+		if class.Weight > c.GlobalMax {
+			return fmt.Errorf("concurrency_limit.classes.%s: weight %d exceeds global_max %d", name, class.Weight, c.GlobalMax)
+		}
+	}
+	return nil
+}
+
+// ============================================
+// Balance Summary Configuration
+// ============================================
+
+// BalanceSummaryConfig - дебаунс изменений баланса: раз в Interval
+// публикуется WalletBalanceSummary по каждому изменившемуся кошельку.
+// Индивидуальные WalletCredited/WalletDebited публикуются как раньше.
+type BalanceSummaryConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Interval   time.Duration `mapstructure:"interval"`
+	MaxWallets int           `mapstructure:"max_wallets"` // кошельков в одном окне, остальные отбрасываются
+}
+
+// ============================================
+// Jobs Configuration
+// ============================================
+
+// JobsConfig - планировщик фоновых задач.
+//
+// Schedules переопределяет cron расписание задачи по имени
+// (wallet-balance-compare, security-events-purge, failed-requests-purge,
+// processed-events-purge, outbox-cleanup, processing-recovery).
+// Выключенный планировщик - задачи работают на собственных таймерах
+// каждого экземпляра, как раньше; outbox-cleanup и processed-events-purge
+// не выполняются.
+type JobsConfig struct {
+	Enabled         bool              `mapstructure:"enabled"`
+	Schedules       map[string]string `mapstructure:"schedules"`
+	HistorySize     int               `mapstructure:"history_size"`     // прогонов задачи в GET /admin/jobs
+	OutboxRetention time.Duration     `mapstructure:"outbox_retention"` // сколько хранить опубликованные события outbox
+
+	ProcessingRecovery ProcessingRecoveryConfig `mapstructure:"processing_recovery"`
+}
+
+// ProcessingRecoveryConfig - задача processing-recovery: транзакции,
+// оставленные в PROCESSING упавшим экземпляром, проводятся как провал
+// SYSTEM / ORPHANED_PROCESSING. Экземпляр считается упавшим, если его
+// heartbeat (пишется каждые workers.persist_interval) старше InstanceTimeout.
+type ProcessingRecoveryConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	Threshold       time.Duration `mapstructure:"threshold"`        // сколько транзакция должна провести в PROCESSING
+	InstanceTimeout time.Duration `mapstructure:"instance_timeout"` // сколько экземпляр может не подавать heartbeat
+	BatchSize       int           `mapstructure:"batch_size"`       // транзакций за прогон
+}
+
+// ============================================
+// Workers Configuration
+// ============================================
+
+// WorkersConfig - heartbeat фоновых компонентов (GET /api/v1/admin/workers).
+//
+// Компонент, не сообщавший о себе дольше порога, попадает в предупреждения
+// /ready, но не делает экземпляр неготовым. StaleAfter переопределяет порог
+// по имени компонента (outbox-relay, balance-summary, wallet-balance-compare,
+// имена задач планировщика); 0 - компонент не устаревает.
+type WorkersConfig struct {
+	Persist         bool                     `mapstructure:"persist"`          // писать heartbeat в workers_heartbeat (вид кластера)
+	PersistInterval time.Duration            `mapstructure:"persist_interval"` // как часто писать heartbeat
+	StaleAfter      map[string]time.Duration `mapstructure:"stale_after"`
+}
+
+// ============================================
+// Locks Configuration
+// ============================================
+
+// LocksConfig - lease-блокировки singleton-работы: задач планировщика и
+// роли лидера outbox relay (только лидер опрашивает основную полосу с
+// NORMAL и LOW событиями, HIGH полосу опрашивают все экземпляры).
+//
+// Backend:
+//   - "" (по умолчанию): задачи блокируются строками job_locks, outbox
+//     relay опрашивают все экземпляры
+//   - postgres: advisory lock; PostgresMode - session (прямое соединение,
+//     pgBouncer в режиме session), xact (pgBouncer в режиме transaction)
+//     или auto (определяется при старте)
+//   - redis: SET NX PX с продлением lease
+//
+// TTL - через сколько lease упавшего экземпляра перейдёт к другому;
+// живой владелец продлевает lease каждые TTL/3.
+type LocksConfig struct {
+	Backend      string        `mapstructure:"backend"`
+	PostgresMode string        `mapstructure:"postgres_mode"`
+	TTL          time.Duration `mapstructure:"ttl"`
 }
 
 // ============================================
@@ -324,6 +1143,15 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("app.version", "1.0.0")
 	v.SetDefault("app.environment", "development")
 	v.SetDefault("app.debug", true)
+	v.SetDefault("app.sandbox_enabled", false)
+	v.SetDefault("app.route_manifest_enabled", false)
+	v.SetDefault("app.legacy_wallet_list_shape", false)
+	v.SetDefault("app.startup.timeout", 0)
+	v.SetDefault("app.startup.database_timeout", DefaultStartupDatabaseTimeout)
+	v.SetDefault("app.startup.schema_check_timeout", DefaultStartupSchemaCheckTimeout)
+	v.SetDefault("app.startup.cache_timeout", DefaultStartupCacheTimeout)
+	v.SetDefault("app.startup.messaging_timeout", DefaultStartupMessagingTimeout)
+	v.SetDefault("app.startup.components_timeout", DefaultStartupComponentsTimeout)
 
 	// Server defaults
 	v.SetDefault("server.host", "0.0.0.0")
@@ -344,6 +1172,11 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.min_connections", 5)
 	v.SetDefault("database.max_conn_lifetime", "1h")
 	v.SetDefault("database.max_conn_idle_time", "30m")
+	v.SetDefault("database.replica.hedge_delay", "0s")
+	v.SetDefault("database.replica.hedge_max_concurrent", 10)
+
+	v.SetDefault("storage.backend", StorageBackendPostgres)
+	v.SetDefault("storage.strict_uow", false)
 
 	// Auth defaults
 	v.SetDefault("auth.jwt_secret", "change-me-in-production")
@@ -351,6 +1184,11 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("auth.access_token_expiry", "15m")
 	v.SetDefault("auth.telegram_bot_token", "")
 
+	// Email policy defaults (см. valueobjects.DefaultEmailPolicy)
+	v.SetDefault("email_policy.strip_plus_tags", true)
+	v.SetDefault("email_policy.strip_dots", true)
+	v.SetDefault("email_policy.providers", []string{"gmail.com", "googlemail.com"})
+
 	// CORS defaults
 	v.SetDefault("cors.allowed_origins", []string{"*"})
 	v.SetDefault("cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
@@ -365,6 +1203,9 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("rate_limit.burst_size", 20)
 	v.SetDefault("rate_limit.financial_ops_per_min", 30)
 	v.SetDefault("rate_limit.cleanup_interval", "1m")
+	v.SetDefault("rate_limit.wallet_max_in_flight", 4)
+	v.SetDefault("rate_limit.wallet_max_wait", "2s")
+	v.SetDefault("rate_limit.wallet_idle_ttl", "5m")
 
 	// NATS defaults
 	v.SetDefault("nats.url", "nats://localhost:4222")
@@ -375,6 +1216,12 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("notifier.poll_interval", "2s")
 	v.SetDefault("notifier.batch_size", 50)
 	v.SetDefault("notifier.max_retries", 5)
+	v.SetDefault("notifier.high_priority_poll_interval", "200ms")
+	v.SetDefault("notifier.high_priority_batch_size", 10)
+	v.SetDefault("notifier.metrics_port", 9091)
+
+	// Outbox defaults
+	v.SetDefault("outbox.event_priorities", map[string]string{})
 
 	// Fraud detection defaults
 	v.SetDefault("fraud.enabled", true)
@@ -390,6 +1237,141 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("exchange.api_key", "")
 	v.SetDefault("exchange.cache_ttl", "4h")
 	v.SetDefault("exchange.spread_percent", 0.5)
+	v.SetDefault("exchange.snapshot_retention", "0s")
+	v.SetDefault("exchange.display_cache_ttl", "1m")
+
+	// Compliance defaults
+	v.SetDefault("compliance.allowed_jurisdictions", []string{})
+	v.SetDefault("compliance.default_jurisdiction", "US")
+	v.SetDefault("compliance.retention_months", map[string]int{})
+	v.SetDefault("compliance.default_retention_months", 60) // 5 лет - минимум FATF для финансовых записей
+
+	// Security defaults
+	v.SetDefault("security.event_queue_size", 1024)
+	v.SetDefault("security.auth_failure_threshold", 10)
+	v.SetDefault("security.auth_failure_window", "5m")
+	v.SetDefault("security.event_retention", "2160h") // 90 дней
+
+	// Screening defaults
+	v.SetDefault("screening.enabled", false)
+	v.SetDefault("screening.load_from_database", true)
+
+	// Wallet migration defaults
+	v.SetDefault("wallet_migration.phase", "off")
+	v.SetDefault("wallet_migration.new_read_percent", 0)
+	v.SetDefault("wallet_migration.compare_interval", "10m")
+	v.SetDefault("wallet_migration.compare_sample_size", 500)
+
+	// New payee defaults
+	v.SetDefault("new_payee.enabled", false)
+	v.SetDefault("new_payee.require_confirmation", true)
+	v.SetDefault("new_payee.max_amount", "500")
+	v.SetDefault("new_payee.limit_currency", "USD")
+	v.SetDefault("new_payee.window", 24*time.Hour)
+
+	// Sensitive data defaults
+	v.SetDefault("sensitive_data.strict", false)
+
+	// Transactions defaults
+	v.SetDefault("transactions.max_pending_per_wallet", 100)
+	v.SetDefault("transactions.bulk_max_items", 500)
+	v.SetDefault("transactions.bulk_group_size", 20)
+	v.SetDefault("transactions.metadata_soft_limit_bytes", 100*1024*1024)
+	v.SetDefault("transactions.metadata_hard_limit_bytes", 0)
+	v.SetDefault("transactions.export_max_rows", 50000)
+
+	// Terms of service defaults
+	v.SetDefault("terms.required_version", 0)
+	v.SetDefault("terms.grandfathered_version", 0)
+
+	// Messaging defaults
+	v.SetDefault("messaging.delivery", "outbox")
+	v.SetDefault("messaging.sync_budget", "250ms")
+	v.SetDefault("messaging.dedup_retention", "168h")
+	v.SetDefault("messaging.spool.enabled", false)
+	v.SetDefault("messaging.spool.dir", "./var/event-spool")
+	v.SetDefault("messaging.spool.max_entries", 1024)
+	v.SetDefault("messaging.spool.max_replays", 5)
+
+	// Request capture defaults
+	v.SetDefault("request_capture.enabled", true)
+	v.SetDefault("request_capture.retention", "720h") // 30 дней
+	v.SetDefault("request_capture.max_body_bytes", 16384)
+	v.SetDefault("request_capture.queue_size", 256)
+	v.SetDefault("request_capture.sensitive_fields", DefaultSensitiveFields)
+
+	// Operation switches defaults
+	v.SetDefault("operations.refresh_interval", "5s")
+
+	// Status page defaults
+	v.SetDefault("status_page.enabled", true)
+	v.SetDefault("status_page.refresh_interval", "5s")
+	v.SetDefault("status_page.cache_max_age", "10s")
+
+	// Webhooks defaults
+	v.SetDefault("webhooks.enabled", false)
+	v.SetDefault("webhooks.delivery_interval", "10s")
+	v.SetDefault("webhooks.batch_size", 100)
+	v.SetDefault("webhooks.request_timeout", "10s")
+	v.SetDefault("webhooks.max_attempts", 10)
+	v.SetDefault("webhooks.auto_disable_failures", 50)
+	v.SetDefault("webhooks.auto_disable_after", "72h")
+	v.SetDefault("webhooks.backlog_grace", "168h")     // 7 дней
+	v.SetDefault("webhooks.delivery_retention", "720h") // 30 дней
+
+	// Onboarding
+	v.SetDefault("onboarding.trusted_partners", []string{})
+
+	// KYC enforcement
+	v.SetDefault("kyc.enforcement", "lenient")
+	v.SetDefault("kyc.status_cache_ttl", "30s")
+
+	// Read sessions
+	v.SetDefault("read_sessions.ttl", "60s")
+	v.SetDefault("read_sessions.max_per_client", 2)
+	v.SetDefault("read_sessions.max_total", 4)
+
+	// Compression defaults
+	v.SetDefault("compression.enabled", true)
+	v.SetDefault("compression.min_size", 1024)
+	v.SetDefault("compression.zstd", false)
+
+	// Concurrency limit defaults
+	v.SetDefault("concurrency_limit.enabled", true)
+	v.SetDefault("concurrency_limit.global_max", 256)
+	v.SetDefault("concurrency_limit.max_wait", "100ms")
+	v.SetDefault("concurrency_limit.idle_ttl", "5m")
+	v.SetDefault("concurrency_limit.classes.read.max_per_client", 32)
+	v.SetDefault("concurrency_limit.classes.read.weight", 1)
+	v.SetDefault("concurrency_limit.classes.mutation.max_per_client", 8)
+	v.SetDefault("concurrency_limit.classes.mutation.weight", 2)
+	v.SetDefault("concurrency_limit.classes.report.max_per_client", 2)
+	v.SetDefault("concurrency_limit.classes.report.weight", 8)
+
+	// Balance summary defaults
+	v.SetDefault("balance_summary.enabled", false)
+	v.SetDefault("balance_summary.interval", "5s")
+	v.SetDefault("balance_summary.max_wallets", 10000)
+
+	// Jobs defaults
+	v.SetDefault("jobs.enabled", false)
+	v.SetDefault("jobs.schedules", map[string]string{})
+	v.SetDefault("jobs.history_size", 10)
+	v.SetDefault("jobs.outbox_retention", "168h") // 7 дней
+	v.SetDefault("jobs.processing_recovery.enabled", false)
+	v.SetDefault("jobs.processing_recovery.threshold", "5m")
+	v.SetDefault("jobs.processing_recovery.instance_timeout", "1m")
+	v.SetDefault("jobs.processing_recovery.batch_size", 100)
+
+	// Workers defaults
+	v.SetDefault("workers.persist", false)
+	v.SetDefault("workers.persist_interval", "15s")
+	v.SetDefault("workers.stale_after", map[string]string{})
+
+	// Locks defaults
+	v.SetDefault("locks.backend", "")
+	v.SetDefault("locks.postgres_mode", "auto")
+	v.SetDefault("locks.ttl", "30s")
 
 	// Redis defaults
 	v.SetDefault("redis.host", "localhost")
@@ -401,6 +1383,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("log.level", "info")
 	v.SetDefault("log.format", "json")
 	v.SetDefault("log.output", "stdout")
+	v.SetDefault("log.components", map[string]string{})
 }
 
 // bindEnvVars привязывает переменные окружения.
@@ -412,6 +1395,10 @@ func bindEnvVars(v *viper.Viper) {
 	_ = v.BindEnv("database.password", "PAYBRIDGE_DATABASE_PASSWORD", "DB_PASSWORD")
 	_ = v.BindEnv("database.database", "PAYBRIDGE_DATABASE_DATABASE", "DB_NAME")
 	_ = v.BindEnv("database.ssl_mode", "PAYBRIDGE_DATABASE_SSL_MODE")
+	_ = v.BindEnv("database.replica.host", "PAYBRIDGE_DATABASE_REPLICA_HOST")
+	_ = v.BindEnv("database.replica.hedge_delay", "PAYBRIDGE_DATABASE_REPLICA_HEDGE_DELAY")
+	_ = v.BindEnv("storage.backend", "PAYBRIDGE_STORAGE_BACKEND")
+	_ = v.BindEnv("storage.strict_uow", "PAYBRIDGE_STORAGE_STRICT_UOW")
 
 	// Auth
 	_ = v.BindEnv("auth.jwt_secret", "PAYBRIDGE_AUTH_JWT_SECRET", "JWT_SECRET")
@@ -422,6 +1409,15 @@ func bindEnvVars(v *viper.Viper) {
 
 	// App
 	_ = v.BindEnv("app.environment", "PAYBRIDGE_APP_ENVIRONMENT", "ENVIRONMENT", "ENV")
+	_ = v.BindEnv("app.sandbox_enabled", "PAYBRIDGE_APP_SANDBOX_ENABLED")
+	_ = v.BindEnv("app.route_manifest_enabled", "PAYBRIDGE_APP_ROUTE_MANIFEST_ENABLED")
+	_ = v.BindEnv("app.legacy_wallet_list_shape", "PAYBRIDGE_APP_LEGACY_WALLET_LIST_SHAPE")
+	_ = v.BindEnv("app.startup.timeout", "PAYBRIDGE_APP_STARTUP_TIMEOUT")
+	_ = v.BindEnv("app.startup.database_timeout", "PAYBRIDGE_APP_STARTUP_DATABASE_TIMEOUT")
+	_ = v.BindEnv("app.startup.schema_check_timeout", "PAYBRIDGE_APP_STARTUP_SCHEMA_CHECK_TIMEOUT")
+	_ = v.BindEnv("app.startup.cache_timeout", "PAYBRIDGE_APP_STARTUP_CACHE_TIMEOUT")
+	_ = v.BindEnv("app.startup.messaging_timeout", "PAYBRIDGE_APP_STARTUP_MESSAGING_TIMEOUT")
+	_ = v.BindEnv("app.startup.components_timeout", "PAYBRIDGE_APP_STARTUP_COMPONENTS_TIMEOUT")
 
 	// NATS
 	_ = v.BindEnv("nats.url", "PAYBRIDGE_NATS_URL", "NATS_URL")
@@ -437,6 +1433,94 @@ func bindEnvVars(v *viper.Viper) {
 	// Exchange
 	_ = v.BindEnv("exchange.api_key", "PAYBRIDGE_EXCHANGE_API_KEY")
 	_ = v.BindEnv("exchange.spread_percent", "PAYBRIDGE_EXCHANGE_SPREAD_PERCENT")
+	_ = v.BindEnv("exchange.snapshot_retention", "PAYBRIDGE_EXCHANGE_SNAPSHOT_RETENTION")
+	_ = v.BindEnv("exchange.display_cache_ttl", "PAYBRIDGE_EXCHANGE_DISPLAY_CACHE_TTL")
+
+	// Compliance
+	_ = v.BindEnv("compliance.allowed_jurisdictions", "PAYBRIDGE_COMPLIANCE_ALLOWED_JURISDICTIONS")
+	_ = v.BindEnv("compliance.default_jurisdiction", "PAYBRIDGE_COMPLIANCE_DEFAULT_JURISDICTION")
+
+	// Screening
+	_ = v.BindEnv("screening.enabled", "PAYBRIDGE_SCREENING_ENABLED")
+
+	// Wallet migration
+	_ = v.BindEnv("wallet_migration.phase", "PAYBRIDGE_WALLET_MIGRATION_PHASE")
+	_ = v.BindEnv("wallet_migration.new_read_percent", "PAYBRIDGE_WALLET_MIGRATION_NEW_READ_PERCENT")
+
+	// Jobs
+	_ = v.BindEnv("jobs.enabled", "PAYBRIDGE_JOBS_ENABLED")
+	_ = v.BindEnv("jobs.processing_recovery.enabled", "PAYBRIDGE_JOBS_PROCESSING_RECOVERY_ENABLED")
+
+	// Workers
+	_ = v.BindEnv("workers.persist", "PAYBRIDGE_WORKERS_PERSIST")
+
+	// Locks
+	_ = v.BindEnv("locks.backend", "PAYBRIDGE_LOCKS_BACKEND")
+	_ = v.BindEnv("locks.postgres_mode", "PAYBRIDGE_LOCKS_POSTGRES_MODE")
+
+	// New payee
+	_ = v.BindEnv("new_payee.enabled", "PAYBRIDGE_NEW_PAYEE_ENABLED")
+	_ = v.BindEnv("new_payee.max_amount", "PAYBRIDGE_NEW_PAYEE_MAX_AMOUNT")
+
+	// Sensitive data
+	_ = v.BindEnv("sensitive_data.strict", "PAYBRIDGE_SENSITIVE_DATA_STRICT")
+
+	// Transactions
+	_ = v.BindEnv("transactions.max_pending_per_wallet", "PAYBRIDGE_TRANSACTIONS_MAX_PENDING_PER_WALLET")
+	_ = v.BindEnv("transactions.bulk_max_items", "PAYBRIDGE_TRANSACTIONS_BULK_MAX_ITEMS")
+	_ = v.BindEnv("transactions.export_max_rows", "PAYBRIDGE_TRANSACTIONS_EXPORT_MAX_ROWS")
+	_ = v.BindEnv("transactions.bulk_group_size", "PAYBRIDGE_TRANSACTIONS_BULK_GROUP_SIZE")
+	_ = v.BindEnv("transactions.metadata_soft_limit_bytes", "PAYBRIDGE_TRANSACTIONS_METADATA_SOFT_LIMIT_BYTES")
+	_ = v.BindEnv("transactions.metadata_hard_limit_bytes", "PAYBRIDGE_TRANSACTIONS_METADATA_HARD_LIMIT_BYTES")
+
+	// Currencies
+	_ = v.BindEnv("currencies.wallet_currencies", "PAYBRIDGE_CURRENCIES_WALLET_CURRENCIES")
+	_ = v.BindEnv("onboarding.trusted_partners", "PAYBRIDGE_ONBOARDING_TRUSTED_PARTNERS")
+	_ = v.BindEnv("kyc.enforcement", "PAYBRIDGE_KYC_ENFORCEMENT")
+	_ = v.BindEnv("kyc.status_cache_ttl", "PAYBRIDGE_KYC_STATUS_CACHE_TTL")
+	_ = v.BindEnv("read_sessions.ttl", "PAYBRIDGE_READ_SESSIONS_TTL")
+	_ = v.BindEnv("read_sessions.max_per_client", "PAYBRIDGE_READ_SESSIONS_MAX_PER_CLIENT")
+	_ = v.BindEnv("read_sessions.max_total", "PAYBRIDGE_READ_SESSIONS_MAX_TOTAL")
+
+	// Terms of service
+	_ = v.BindEnv("terms.required_version", "PAYBRIDGE_TERMS_REQUIRED_VERSION")
+	_ = v.BindEnv("terms.grandfathered_version", "PAYBRIDGE_TERMS_GRANDFATHERED_VERSION")
+
+	// Messaging
+	_ = v.BindEnv("messaging.delivery", "PAYBRIDGE_MESSAGING_DELIVERY")
+	_ = v.BindEnv("messaging.spool.enabled", "PAYBRIDGE_MESSAGING_SPOOL_ENABLED")
+	_ = v.BindEnv("messaging.spool.dir", "PAYBRIDGE_MESSAGING_SPOOL_DIR")
+	_ = v.BindEnv("messaging.spool.max_entries", "PAYBRIDGE_MESSAGING_SPOOL_MAX_ENTRIES")
+	_ = v.BindEnv("messaging.spool.max_replays", "PAYBRIDGE_MESSAGING_SPOOL_MAX_REPLAYS")
+
+	// Request capture
+	_ = v.BindEnv("request_capture.enabled", "PAYBRIDGE_REQUEST_CAPTURE_ENABLED")
+	_ = v.BindEnv("request_capture.retention", "PAYBRIDGE_REQUEST_CAPTURE_RETENTION")
+
+	// Operation switches
+	_ = v.BindEnv("operations.refresh_interval", "PAYBRIDGE_OPERATIONS_REFRESH_INTERVAL")
+	_ = v.BindEnv("status_page.enabled", "PAYBRIDGE_STATUS_PAGE_ENABLED")
+
+	// Webhooks
+	_ = v.BindEnv("webhooks.enabled", "PAYBRIDGE_WEBHOOKS_ENABLED")
+	_ = v.BindEnv("webhooks.auto_disable_failures", "PAYBRIDGE_WEBHOOKS_AUTO_DISABLE_FAILURES")
+	_ = v.BindEnv("webhooks.auto_disable_after", "PAYBRIDGE_WEBHOOKS_AUTO_DISABLE_AFTER")
+	_ = v.BindEnv("webhooks.backlog_grace", "PAYBRIDGE_WEBHOOKS_BACKLOG_GRACE")
+	_ = v.BindEnv("webhooks.delivery_retention", "PAYBRIDGE_WEBHOOKS_DELIVERY_RETENTION")
+
+	// Compression
+	_ = v.BindEnv("compression.enabled", "PAYBRIDGE_COMPRESSION_ENABLED")
+	_ = v.BindEnv("compression.min_size", "PAYBRIDGE_COMPRESSION_MIN_SIZE")
+	_ = v.BindEnv("compression.zstd", "PAYBRIDGE_COMPRESSION_ZSTD")
+
+	// Concurrency limit
+	_ = v.BindEnv("concurrency_limit.enabled", "PAYBRIDGE_CONCURRENCY_LIMIT_ENABLED")
+	_ = v.BindEnv("concurrency_limit.global_max", "PAYBRIDGE_CONCURRENCY_LIMIT_GLOBAL_MAX")
+	_ = v.BindEnv("concurrency_limit.max_wait", "PAYBRIDGE_CONCURRENCY_LIMIT_MAX_WAIT")
+
+	// Balance summary
+	_ = v.BindEnv("balance_summary.enabled", "PAYBRIDGE_BALANCE_SUMMARY_ENABLED")
+	_ = v.BindEnv("balance_summary.interval", "PAYBRIDGE_BALANCE_SUMMARY_INTERVAL")
 
 	// Redis
 	_ = v.BindEnv("redis.host", "PAYBRIDGE_REDIS_HOST", "REDIS_HOST")
@@ -460,21 +1544,204 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("JWT secret must be set in production")
 		}
 
+		if c.Messaging.Delivery == "none" {
+			return fmt.Errorf("event delivery mode none is not allowed in production")
+		}
+
+		if c.Storage.IsMemory() {
+			return fmt.Errorf("storage backend memory is not allowed in production")
+		}
+
 		if c.Database.SSLMode == "disable" {
 			// Warning, но не error
 			// В реальном приложении можно добавить логирование
 		}
 	}
 
+	startup := c.App.Startup
+	for name, d := range map[string]time.Duration{
+		"timeout":              startup.Timeout,
+		"database_timeout":     startup.Database,
+		"schema_check_timeout": startup.SchemaCheck,
+		"cache_timeout":        startup.Cache,
+		"messaging_timeout":    startup.Messaging,
+		"components_timeout":   startup.Components,
+	} {
+		if d < 0 {
+			return fmt.Errorf("app.startup.%s must not be negative: %s", name, d)
+		}
+	}
+
+	switch c.Storage.Backend {
+	case "", StorageBackendPostgres, StorageBackendMemory:
+	default:
+		return fmt.Errorf("invalid storage.backend: %q (want postgres or memory)", c.Storage.Backend)
+	}
+	if c.Storage.StrictUoW && c.App.IsProduction() {
+		return fmt.Errorf("storage.strict_uow is not allowed in production")
+	}
+	if _, err := logger.NewLevels(c.Log.Level, c.Log.Components); err != nil {
+		return fmt.Errorf("invalid log levels: %w", err)
+	}
+
 	// Проверяем обязательные поля
-	if c.Database.Host == "" {
+	if c.Database.Host == "" && !c.Storage.IsMemory() {
 		return fmt.Errorf("database host is required")
 	}
 
+	if err := c.Database.Replica.validate(); err != nil {
+		return err
+	}
+
 	if c.Server.Port <= 0 || c.Server.Port > 65535 {
 		return fmt.Errorf("invalid server port: %d", c.Server.Port)
 	}
 
+	switch c.Messaging.Delivery {
+	case "", "outbox", "sync", "none":
+	default:
+		return fmt.Errorf("invalid messaging delivery mode: %q (want outbox, sync or none)", c.Messaging.Delivery)
+	}
+
+	if c.Messaging.DedupRetention < 0 {
+		return fmt.Errorf("messaging.dedup_retention must not be negative: %s", c.Messaging.DedupRetention)
+	}
+
+	if spool := c.Messaging.Spool; spool.Enabled {
+		if spool.Dir == "" {
+			return fmt.Errorf("messaging.spool.dir is required when the spool is enabled")
+		}
+		if spool.MaxEntries <= 0 {
+			return fmt.Errorf("messaging.spool.max_entries must be positive: %d", spool.MaxEntries)
+		}
+		if spool.MaxReplays <= 0 {
+			return fmt.Errorf("messaging.spool.max_replays must be positive: %d", spool.MaxReplays)
+		}
+		if c.Messaging.Delivery == "none" {
+			return fmt.Errorf("messaging.spool requires event delivery (delivery mode is none)")
+		}
+	}
+
+	if c.Transactions.MaxPendingPerWallet < 0 {
+		return fmt.Errorf("transactions.max_pending_per_wallet must not be negative: %d", c.Transactions.MaxPendingPerWallet)
+	}
+	if c.Transactions.BulkMaxItems < 0 {
+		return fmt.Errorf("transactions.bulk_max_items must not be negative: %d", c.Transactions.BulkMaxItems)
+	}
+	if c.Transactions.BulkGroupSize < 0 {
+		return fmt.Errorf("transactions.bulk_group_size must not be negative: %d", c.Transactions.BulkGroupSize)
+	}
+	if c.Transactions.ExportMaxRows < 0 {
+		return fmt.Errorf("transactions.export_max_rows must not be negative: %d", c.Transactions.ExportMaxRows)
+	}
+	if c.Transactions.MetadataSoftLimitBytes < 0 || c.Transactions.MetadataHardLimitBytes < 0 {
+		return fmt.Errorf("transactions.metadata_soft_limit_bytes and metadata_hard_limit_bytes must not be negative")
+	}
+	if hard := c.Transactions.MetadataHardLimitBytes; hard > 0 && c.Transactions.MetadataSoftLimitBytes > hard {
+		return fmt.Errorf("transactions.metadata_soft_limit_bytes must not exceed metadata_hard_limit_bytes (%d)", hard)
+	}
+
+	if c.RequestCapture.Retention < 0 {
+		return fmt.Errorf("request_capture.retention must not be negative: %s", c.RequestCapture.Retention)
+	}
+	if c.RequestCapture.MaxBodyBytes < 0 {
+		return fmt.Errorf("request_capture.max_body_bytes must not be negative: %d", c.RequestCapture.MaxBodyBytes)
+	}
+	if c.RequestCapture.QueueSize < 0 {
+		return fmt.Errorf("request_capture.queue_size must not be negative: %d", c.RequestCapture.QueueSize)
+	}
+	if c.Exchange.SnapshotRetention < 0 {
+		return fmt.Errorf("exchange.snapshot_retention must not be negative: %s", c.Exchange.SnapshotRetention)
+	}
+	if c.Workers.PersistInterval < 0 {
+		return fmt.Errorf("workers.persist_interval must not be negative: %s", c.Workers.PersistInterval)
+	}
+	if recovery := c.Jobs.ProcessingRecovery; recovery.Enabled {
+		if recovery.Threshold <= 0 {
+			return fmt.Errorf("jobs.processing_recovery.threshold must be positive: %s", recovery.Threshold)
+		}
+		if recovery.BatchSize <= 0 {
+			return fmt.Errorf("jobs.processing_recovery.batch_size must be positive: %d", recovery.BatchSize)
+		}
+		// Живой экземпляр не должен выглядеть упавшим между двумя heartbeat
+		if recovery.InstanceTimeout <= c.Workers.PersistInterval {
+			return fmt.Errorf("jobs.processing_recovery.instance_timeout (%s) must exceed workers.persist_interval (%s)",
+				recovery.InstanceTimeout, c.Workers.PersistInterval)
+		}
+	}
+	for name, staleAfter := range c.Workers.StaleAfter {
+		if staleAfter < 0 {
+			return fmt.Errorf("workers.stale_after.%s must not be negative: %s", name, staleAfter)
+		}
+	}
+	switch c.Locks.Backend {
+	case "", "postgres", "redis":
+	default:
+		return fmt.Errorf("locks.backend must be empty, postgres or redis: %q", c.Locks.Backend)
+	}
+	switch c.Locks.PostgresMode {
+	case "", "auto", "session", "xact":
+	default:
+		return fmt.Errorf("locks.postgres_mode must be auto, session or xact: %q", c.Locks.PostgresMode)
+	}
+	if c.Locks.Backend != "" && c.Locks.TTL <= 0 {
+		return fmt.Errorf("locks.ttl must be positive: %s", c.Locks.TTL)
+	}
+	if c.Operations.RefreshInterval < 0 {
+		return fmt.Errorf("operations.refresh_interval must not be negative: %s", c.Operations.RefreshInterval)
+	}
+	if c.StatusPage.RefreshInterval < 0 {
+		return fmt.Errorf("status_page.refresh_interval must not be negative: %s", c.StatusPage.RefreshInterval)
+	}
+	if c.StatusPage.CacheMaxAge < 0 {
+		return fmt.Errorf("status_page.cache_max_age must not be negative: %s", c.StatusPage.CacheMaxAge)
+	}
+	if c.Webhooks.Enabled {
+		// Доставка выполняется задачей планировщика
+		if !c.Jobs.Enabled {
+			return fmt.Errorf("webhooks.enabled requires jobs.enabled")
+		}
+		if c.Webhooks.DeliveryInterval <= 0 {
+			return fmt.Errorf("webhooks.delivery_interval must be positive: %s", c.Webhooks.DeliveryInterval)
+		}
+		if c.Webhooks.AutoDisableFailures < 0 || c.Webhooks.AutoDisableAfter < 0 {
+			return fmt.Errorf("webhooks.auto_disable_failures and webhooks.auto_disable_after must not be negative")
+		}
+		if c.Webhooks.BacklogGrace < 0 || c.Webhooks.DeliveryRetention < 0 {
+			return fmt.Errorf("webhooks.backlog_grace and webhooks.delivery_retention must not be negative")
+		}
+	}
+	if c.Compression.MinSize < 0 {
+		return fmt.Errorf("compression.min_size must not be negative: %d", c.Compression.MinSize)
+	}
+	if err := c.ConcurrencyLimit.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Currencies.validate(); err != nil {
+		return err
+	}
+	if err := c.Onboarding.validate(); err != nil {
+		return err
+	}
+	if err := c.KYC.validate(); err != nil {
+		return err
+	}
+	sessionPool := c.Database.MaxConnections
+	if c.Database.Replica.Enabled() && c.Database.Replica.MaxConnections > 0 {
+		sessionPool = c.Database.Replica.MaxConnections
+	}
+	if err := c.ReadSessions.validate(sessionPool); err != nil {
+		return err
+	}
+
+	if c.Terms.RequiredVersion < 0 || c.Terms.GrandfatheredVersion < 0 {
+		return fmt.Errorf("terms versions must not be negative: required %d, grandfathered %d", c.Terms.RequiredVersion, c.Terms.GrandfatheredVersion)
+	}
+	if c.Terms.GrandfatheredVersion > c.Terms.RequiredVersion {
+		return fmt.Errorf("terms.grandfathered_version (%d) must not exceed terms.required_version (%d)", c.Terms.GrandfatheredVersion, c.Terms.RequiredVersion)
+	}
+
 	return nil
 }
 
@@ -516,6 +1783,11 @@ func Development() *Config {
 			JWTIssuer:         "paybridge-dev",
 			AccessTokenExpiry: 15 * time.Minute,
 		},
+		EmailPolicy: EmailPolicyConfig{
+			StripPlusTags: true,
+			StripDots:     true,
+			Providers:     []string{"gmail.com", "googlemail.com"},
+		},
 		CORS: CORSConfig{
 			AllowedOrigins:   []string{"*"},
 			AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
@@ -529,6 +1801,9 @@ func Development() *Config {
 			BurstSize:          20,
 			FinancialOpsPerMin: 30,
 			CleanupInterval:    time.Minute,
+			WalletMaxInFlight:  4,
+			WalletMaxWait:      2 * time.Second,
+			WalletIdleTTL:      5 * time.Minute,
 		},
 		Log: LogConfig{
 			Level:  "debug",
@@ -544,6 +1819,119 @@ func Development() *Config {
 			PollInterval: 2 * time.Second,
 			BatchSize:    50,
 			MaxRetries:   5,
+
+			HighPriorityPollInterval: 200 * time.Millisecond,
+			HighPriorityBatchSize:    10,
+			MetricsPort:              9091,
+		},
+		Compliance: ComplianceConfig{
+			DefaultJurisdiction:    "US",
+			DefaultRetentionMonths: 60,
+		},
+		Security: SecurityConfig{
+			EventQueueSize:       1024,
+			AuthFailureThreshold: 10,
+			AuthFailureWindow:    5 * time.Minute,
+			EventRetention:       90 * 24 * time.Hour,
+		},
+		Screening: ScreeningConfig{
+			LoadFromDatabase: true,
+		},
+		WalletMigration: WalletMigrationConfig{
+			Phase:             "off",
+			CompareInterval:   10 * time.Minute,
+			CompareSampleSize: 500,
+		},
+		Jobs: JobsConfig{
+			HistorySize:     10,
+			OutboxRetention: 7 * 24 * time.Hour,
+			ProcessingRecovery: ProcessingRecoveryConfig{
+				Threshold:       5 * time.Minute,
+				InstanceTimeout: time.Minute,
+				BatchSize:       100,
+			},
+		},
+		Workers: WorkersConfig{
+			PersistInterval: 15 * time.Second,
+		},
+		Locks: LocksConfig{
+			PostgresMode: "auto",
+			TTL:          30 * time.Second,
+		},
+		StatusPage: StatusPageConfig{
+			Enabled:         true,
+			RefreshInterval: 5 * time.Second,
+			CacheMaxAge:     10 * time.Second,
+		},
+		NewPayee: NewPayeeConfig{
+			RequireConfirmation: true,
+			MaxAmount:           "500",
+			LimitCurrency:       "USD",
+			Window:              24 * time.Hour,
+		},
+		BalanceSummary: BalanceSummaryConfig{
+			Interval:   5 * time.Second,
+			MaxWallets: 10000,
+		},
+		Messaging: MessagingConfig{
+			Delivery:       "outbox",
+			SyncBudget:     250 * time.Millisecond,
+			DedupRetention: 7 * 24 * time.Hour,
+			Spool: EventSpoolConfig{
+				Dir:        "./var/event-spool",
+				MaxEntries: 1024,
+				MaxReplays: 5,
+			},
+		},
+		Transactions: TransactionsConfig{
+			MaxPendingPerWallet: 100,
+			BulkMaxItems:        500,
+			BulkGroupSize:       20,
+			ExportMaxRows:       50000,
+
+			MetadataSoftLimitBytes: 100 * 1024 * 1024,
+		},
+		RequestCapture: RequestCaptureConfig{
+			Enabled:         true,
+			Retention:       30 * 24 * time.Hour,
+			MaxBodyBytes:    16384,
+			QueueSize:       256,
+			SensitiveFields: DefaultSensitiveFields,
+		},
+		Compression: CompressionConfig{
+			Enabled: true,
+			MinSize: 1024,
+		},
+		ConcurrencyLimit: ConcurrencyLimitConfig{
+			Enabled:   true,
+			GlobalMax: 256,
+			MaxWait:   100 * time.Millisecond,
+			IdleTTL:   5 * time.Minute,
+			Classes: map[string]ConcurrencyClassConfig{
+				"read":     {MaxPerClient: 32, Weight: 1},
+				"mutation": {MaxPerClient: 8, Weight: 2},
+				"report":   {MaxPerClient: 2, Weight: 8},
+			},
+		},
+		Webhooks: WebhooksConfig{
+			DeliveryInterval:    10 * time.Second,
+			BatchSize:           100,
+			RequestTimeout:      10 * time.Second,
+			MaxAttempts:         10,
+			AutoDisableFailures: 50,
+			AutoDisableAfter:    72 * time.Hour,
+			BacklogGrace:        7 * 24 * time.Hour,
+			DeliveryRetention:   30 * 24 * time.Hour,
+		},
+		Onboarding: OnboardingConfig{TrustedPartners: []string{}},
+		KYC: KYCConfig{
+			Enforcement:    "lenient",
+			StatusCacheTTL: 30 * time.Second,
+		},
+		ReadSessions: ReadSessionsConfig{
+			TTL:          60 * time.Second,
+			MaxPerClient: 2,
+			MaxTotal:     4,
 		},
 	}
 }
@@ -556,3 +1944,14 @@ func Test() *Config {
 	cfg.Log.Level = "error" // Меньше шума в тестах
 	return cfg
 }
+
+// Standalone возвращает конфигурацию без внешних зависимостей: данные в
+// памяти процесса, события раздаются внутренней шиной до ответа, фоновые
+// задачи работают в одном процессе. Для локального запуска и smoke-тестов.
+func Standalone() *Config {
+	cfg := Development()
+	cfg.Storage.Backend = StorageBackendMemory
+	cfg.Messaging.Delivery = "sync"
+	cfg.Jobs.Enabled = true
+	return cfg
+}
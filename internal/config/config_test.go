@@ -49,6 +49,51 @@ func TestAppConfig_IsProduction(t *testing.T) {
 	}
 }
 
+func TestAppConfig_IsSandbox(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		enabled     bool
+		expected    bool
+	}{
+		{"enabled in staging", "staging", true, true},
+		{"enabled in development", "development", true, true},
+		{"enabled in production is ignored", "production", true, false},
+		{"disabled", "staging", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &AppConfig{Environment: tt.environment, SandboxEnabled: tt.enabled}
+			assert.Equal(t, tt.expected, cfg.IsSandbox())
+		})
+	}
+}
+
+func TestAppConfig_IsRouteManifestEnabled(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		sandbox     bool
+		enabled     bool
+		expected    bool
+	}{
+		{"development", "development", false, false, true},
+		{"sandbox staging", "staging", true, false, true},
+		{"staging", "staging", false, false, false},
+		{"production", "production", false, false, false},
+		{"production opt-in", "production", false, true, true},
+		{"production sandbox flag is ignored", "production", true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &AppConfig{Environment: tt.environment, SandboxEnabled: tt.sandbox, RouteManifestEnabled: tt.enabled}
+			assert.Equal(t, tt.expected, cfg.IsRouteManifestEnabled())
+		})
+	}
+}
+
 func TestServerConfig_Address(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -180,6 +225,47 @@ func TestConfig_Validate_Production_Valid(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestConfig_Validate_MessagingDelivery(t *testing.T) {
+	cfg := Development()
+	cfg.Messaging.Delivery = "kafka"
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid messaging delivery mode")
+
+	cfg.Messaging.Delivery = "sync"
+	assert.NoError(t, cfg.Validate())
+
+	// Отбрасывать события в production нельзя
+	cfg.App.Environment = "production"
+	cfg.Auth.JWTSecret = "my-super-secure-production-secret"
+	cfg.Messaging.Delivery = "none"
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "delivery mode none")
+}
+
+func TestConfig_Validate_Locks(t *testing.T) {
+	cfg := Development()
+	cfg.Locks.Backend = "etcd"
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "locks.backend")
+
+	cfg.Locks.Backend = "postgres"
+	cfg.Locks.PostgresMode = "transaction"
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "locks.postgres_mode")
+
+	cfg.Locks.PostgresMode = "xact"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Locks.TTL = 0
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "locks.ttl")
+}
+
 func TestDevelopment(t *testing.T) {
 	cfg := Development()
 
@@ -293,3 +379,359 @@ func TestLogConfig(t *testing.T) {
 	assert.Equal(t, "text", cfg.Log.Format)
 	assert.Equal(t, "stdout", cfg.Log.Output)
 }
+
+func TestSecurityConfig_Defaults(t *testing.T) {
+	t.Setenv("PAYBRIDGE_SECURITY_AUTH_FAILURE_THRESHOLD", "3")
+
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1024, cfg.Security.EventQueueSize)
+	assert.Equal(t, 3, cfg.Security.AuthFailureThreshold)
+	assert.Equal(t, 5*time.Minute, cfg.Security.AuthFailureWindow)
+	assert.Equal(t, 90*24*time.Hour, cfg.Security.EventRetention)
+}
+
+func TestRequestCaptureConfig_Defaults(t *testing.T) {
+	t.Setenv("PAYBRIDGE_REQUEST_CAPTURE_RETENTION", "48h")
+
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+
+	assert.True(t, cfg.RequestCapture.Enabled)
+	assert.Equal(t, 48*time.Hour, cfg.RequestCapture.Retention)
+	assert.Equal(t, 16384, cfg.RequestCapture.MaxBodyBytes)
+	assert.Equal(t, 256, cfg.RequestCapture.QueueSize)
+	assert.Equal(t, DefaultSensitiveFields, cfg.RequestCapture.SensitiveFields)
+
+	cfg.RequestCapture.MaxBodyBytes = -1
+	assert.ErrorContains(t, cfg.Validate(), "request_capture.max_body_bytes")
+}
+
+func TestNotifierConfig_PriorityLaneDefaults(t *testing.T) {
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2*time.Second, cfg.Notifier.PollInterval)
+	assert.Equal(t, 200*time.Millisecond, cfg.Notifier.HighPriorityPollInterval)
+	assert.Equal(t, 10, cfg.Notifier.HighPriorityBatchSize)
+	assert.Empty(t, cfg.Outbox.EventPriorities)
+}
+
+func TestTransactionsConfig_MaxPendingPerWallet(t *testing.T) {
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+	assert.Equal(t, 100, cfg.Transactions.MaxPendingPerWallet)
+
+	t.Setenv("PAYBRIDGE_TRANSACTIONS_MAX_PENDING_PER_WALLET", "0")
+	cfg, err = Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+	assert.Zero(t, cfg.Transactions.MaxPendingPerWallet, "0 disables the cap")
+
+	cfg.Transactions.MaxPendingPerWallet = -1
+	assert.ErrorContains(t, cfg.Validate(), "max_pending_per_wallet")
+}
+
+func TestTransactionsConfig_MetadataQuota(t *testing.T) {
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+	assert.Equal(t, int64(100*1024*1024), cfg.Transactions.MetadataSoftLimitBytes)
+	assert.Zero(t, cfg.Transactions.MetadataHardLimitBytes, "hard limit is off by default")
+
+	cfg.Transactions.MetadataHardLimitBytes = 1024
+	assert.ErrorContains(t, cfg.Validate(), "must not exceed metadata_hard_limit_bytes")
+
+	cfg.Transactions.MetadataSoftLimitBytes = 512
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestCurrenciesConfig(t *testing.T) {
+	cfg := Development()
+	cfg.Currencies = CurrenciesConfig{
+		WalletCurrencies: []string{"usd", " EUR "},
+		Limits:           map[string]CurrencyLimitConfig{"btc": {MinAmount: "0.0001", MaxAmount: "2"}},
+	}
+	require.NoError(t, cfg.Validate())
+
+	policy := cfg.Currencies.Policy()
+	assert.Equal(t, []string{"USD", "EUR"}, policy.WalletCurrencies)
+	assert.Equal(t, "0.0001", policy.Limits["BTC"].Min)
+
+	cfg.Currencies.WalletCurrencies = []string{"XYZ"}
+	assert.ErrorContains(t, cfg.Validate(), "currencies.wallet_currencies")
+
+	cfg.Currencies.WalletCurrencies = nil
+	cfg.Currencies.Limits = map[string]CurrencyLimitConfig{"USD": {MinAmount: "10", MaxAmount: "5"}}
+	assert.ErrorContains(t, cfg.Validate(), "exceeds max_amount")
+
+	cfg.Currencies.Limits = map[string]CurrencyLimitConfig{"USD": {MaxAmount: "-1"}}
+	assert.ErrorContains(t, cfg.Validate(), "currencies.limits.USD.max_amount")
+}
+
+func TestReadReplicaConfig(t *testing.T) {
+	t.Setenv("PAYBRIDGE_DATABASE_REPLICA_HOST", "replica.internal")
+	t.Setenv("PAYBRIDGE_DATABASE_REPLICA_HEDGE_DELAY", "50ms")
+
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+	assert.True(t, cfg.Database.Replica.Enabled())
+	assert.Equal(t, 50*time.Millisecond, cfg.Database.Replica.HedgeDelay)
+	assert.Equal(t, 10, cfg.Database.Replica.HedgeMaxConcurrent)
+	assert.Contains(t, cfg.Database.ReplicaDSN(), "@replica.internal:5432/")
+
+	cfg.Database.Replica.HedgeGroups = map[string]bool{"wallet_reads": true, "transaction_lists": true}
+	assert.ErrorContains(t, cfg.Validate(), "transaction_lists")
+
+	cfg.Database.Replica.HedgeGroups = map[string]bool{"wallet_reads": true}
+	cfg.Database.Replica.HedgeMaxConcurrent = 0
+	assert.ErrorContains(t, cfg.Validate(), "hedge_max_concurrent")
+}
+
+func TestReadSessionsConfig(t *testing.T) {
+	t.Setenv("PAYBRIDGE_READ_SESSIONS_TTL", "2m")
+
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, cfg.ReadSessions.TTL)
+	assert.Equal(t, 2, cfg.ReadSessions.MaxPerClient)
+	assert.Equal(t, 4, cfg.ReadSessions.MaxTotal)
+	require.NoError(t, cfg.Validate())
+
+	cfg.ReadSessions.TTL = 10 * time.Minute
+	assert.ErrorContains(t, cfg.Validate(), "read_sessions.ttl")
+
+	// Сессии не должны занимать весь пул
+	cfg.ReadSessions.TTL = time.Minute
+	cfg.ReadSessions.MaxTotal = int(cfg.Database.MaxConnections)
+	assert.ErrorContains(t, cfg.Validate(), "read_sessions.max_total")
+
+	// С репликой сессии берут соединения из её пула
+	cfg.Database.Replica.Host = "replica.internal"
+	cfg.Database.Replica.MaxConnections = cfg.Database.MaxConnections * 2
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestStorageConfig(t *testing.T) {
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+	assert.Equal(t, StorageBackendPostgres, cfg.Storage.Backend)
+
+	t.Setenv("PAYBRIDGE_STORAGE_BACKEND", "memory")
+	cfg, err = Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+	assert.True(t, cfg.Storage.IsMemory())
+
+	// Standalone профиль не требует PostgreSQL
+	cfg.Database.Host = ""
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Storage.Backend = "sqlite"
+	assert.ErrorContains(t, cfg.Validate(), "storage.backend")
+}
+
+func TestStorageConfig_StrictUoW(t *testing.T) {
+	t.Setenv("PAYBRIDGE_STORAGE_STRICT_UOW", "true")
+
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+	assert.True(t, cfg.Storage.StrictUoW)
+
+	// Strict guard паникует - только для CI
+	cfg.App.Environment = "production"
+	cfg.Auth.JWTSecret = "my-super-secure-production-secret"
+	assert.ErrorContains(t, cfg.Validate(), "storage.strict_uow")
+}
+
+func TestConcurrencyLimitConfig(t *testing.T) {
+	t.Setenv("PAYBRIDGE_CONCURRENCY_LIMIT_MAX_WAIT", "250ms")
+
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+	assert.True(t, cfg.ConcurrencyLimit.Enabled)
+	assert.Equal(t, 250*time.Millisecond, cfg.ConcurrencyLimit.MaxWait)
+	assert.Equal(t, ConcurrencyClassConfig{MaxPerClient: 2, Weight: 8}, cfg.ConcurrencyLimit.Classes["report"])
+	assert.Len(t, cfg.ConcurrencyLimit.Classes, len(ConcurrencyClasses))
+
+	cfg.ConcurrencyLimit.Classes["report"] = ConcurrencyClassConfig{MaxPerClient: 2, Weight: 1000}
+	assert.ErrorContains(t, cfg.Validate(), "exceeds global_max")
+
+	cfg.ConcurrencyLimit.Classes = map[string]ConcurrencyClassConfig{"bulk": {MaxPerClient: 1, Weight: 1}}
+	assert.ErrorContains(t, cfg.Validate(), "unknown concurrency_limit class")
+
+	cfg.ConcurrencyLimit.Enabled = false
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLogConfig_Components(t *testing.T) {
+	cfg := Development()
+	cfg.Log.Components = map[string]string{"transfer": "debug", "fraud": "warn"}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Log.Components["transfer"] = "verbose"
+	assert.ErrorContains(t, cfg.Validate(), "component transfer")
+
+	cfg.Log.Components = nil
+	cfg.Log.Level = "loud"
+	assert.ErrorContains(t, cfg.Validate(), "invalid log levels")
+}
+
+func TestStartupConfig(t *testing.T) {
+	t.Setenv("PAYBRIDGE_APP_STARTUP_DATABASE_TIMEOUT", "2m")
+
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+
+	startup := cfg.App.Startup
+	assert.Zero(t, startup.Timeout, "overall deadline defaults to the sum of stage budgets")
+	assert.Equal(t, 2*time.Minute, startup.Database)
+	assert.Equal(t, DefaultStartupSchemaCheckTimeout, startup.SchemaCheck)
+	assert.Equal(t, DefaultStartupCacheTimeout, startup.Cache)
+	assert.Equal(t, DefaultStartupMessagingTimeout, startup.Messaging)
+	assert.Equal(t, DefaultStartupComponentsTimeout, startup.Components)
+
+	// Нулевые бюджеты (конфиг собран в коде) заменяются значениями по умолчанию
+	assert.Equal(t, DefaultStartupDatabaseTimeout, StartupConfig{}.WithDefaults().Database)
+	assert.Equal(t, time.Second, StartupConfig{Cache: time.Second}.WithDefaults().Cache)
+
+	cfg.App.Startup.Cache = -time.Second
+	assert.ErrorContains(t, cfg.Validate(), "app.startup.cache_timeout")
+}
+
+func TestMessagingConfig_DedupRetention(t *testing.T) {
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, cfg.Messaging.DedupRetention)
+
+	cfg.Messaging.DedupRetention = -time.Hour
+	assert.ErrorContains(t, cfg.Validate(), "dedup_retention")
+}
+
+func TestMessagingConfig_Spool(t *testing.T) {
+	t.Setenv("PAYBRIDGE_MESSAGING_SPOOL_ENABLED", "true")
+
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+	assert.True(t, cfg.Messaging.Spool.Enabled)
+	assert.Equal(t, "./var/event-spool", cfg.Messaging.Spool.Dir)
+	assert.Equal(t, 1024, cfg.Messaging.Spool.MaxEntries)
+	assert.Equal(t, 5, cfg.Messaging.Spool.MaxReplays)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Messaging.Spool.MaxEntries = 0
+	assert.ErrorContains(t, cfg.Validate(), "messaging.spool.max_entries")
+
+	cfg.Messaging.Spool.MaxEntries = 16
+	cfg.Messaging.Spool.MaxReplays = 0
+	assert.ErrorContains(t, cfg.Validate(), "messaging.spool.max_replays")
+
+	cfg.Messaging.Spool.MaxReplays = 5
+	cfg.Messaging.Delivery = "none"
+	assert.ErrorContains(t, cfg.Validate(), "delivery mode is none")
+
+	// Выключенный spool не проверяется
+	cfg.Messaging.Spool = EventSpoolConfig{}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestWalletMigrationConfig_Defaults(t *testing.T) {
+	t.Setenv("PAYBRIDGE_WALLET_MIGRATION_PHASE", "new_read")
+	t.Setenv("PAYBRIDGE_WALLET_MIGRATION_NEW_READ_PERCENT", "25")
+
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+
+	assert.Equal(t, "new_read", cfg.WalletMigration.Phase)
+	assert.Equal(t, 25, cfg.WalletMigration.NewReadPercent)
+	assert.Equal(t, 10*time.Minute, cfg.WalletMigration.CompareInterval)
+	assert.Equal(t, 500, cfg.WalletMigration.CompareSampleSize)
+}
+
+func TestJobsConfig_Defaults(t *testing.T) {
+	t.Setenv("PAYBRIDGE_JOBS_ENABLED", "true")
+
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Jobs.Enabled)
+	assert.Empty(t, cfg.Jobs.Schedules)
+	assert.Equal(t, 10, cfg.Jobs.HistorySize)
+	assert.Equal(t, 7*24*time.Hour, cfg.Jobs.OutboxRetention)
+}
+
+func TestProcessingRecoveryConfig_Validate(t *testing.T) {
+	t.Setenv("PAYBRIDGE_JOBS_PROCESSING_RECOVERY_ENABLED", "true")
+
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Jobs.ProcessingRecovery.Enabled)
+	assert.Equal(t, 5*time.Minute, cfg.Jobs.ProcessingRecovery.Threshold)
+	assert.Equal(t, time.Minute, cfg.Jobs.ProcessingRecovery.InstanceTimeout)
+	assert.Equal(t, 100, cfg.Jobs.ProcessingRecovery.BatchSize)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Jobs.ProcessingRecovery.InstanceTimeout = cfg.Workers.PersistInterval
+	assert.ErrorContains(t, cfg.Validate(), "instance_timeout")
+}
+
+func TestNewPayeeConfig_Defaults(t *testing.T) {
+	t.Setenv("PAYBRIDGE_NEW_PAYEE_ENABLED", "true")
+	t.Setenv("PAYBRIDGE_NEW_PAYEE_MAX_AMOUNT", "250.50")
+
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+
+	assert.True(t, cfg.NewPayee.Enabled)
+	assert.True(t, cfg.NewPayee.RequireConfirmation)
+	assert.Equal(t, "250.50", cfg.NewPayee.MaxAmount)
+	assert.Equal(t, "USD", cfg.NewPayee.LimitCurrency)
+	assert.Equal(t, 24*time.Hour, cfg.NewPayee.Window)
+}
+
+func TestScreeningConfig_LoadsRules(t *testing.T) {
+	dir := t.TempDir()
+	yaml := `
+screening:
+  enabled: true
+  load_from_database: false
+  rules:
+    - id: fresh-wallet-withdraw
+      description: Large withdrawals from new wallets
+      action: BLOCK
+      conditions:
+        - attribute: type
+          operator: eq
+          value: WITHDRAW
+        - attribute: wallet_age
+          operator: lt
+          value: 24h
+    - id: high-risk-country
+      action: FLAG
+      conditions:
+        - attribute: country
+          operator: in
+          values: [IR, KP]
+`
+	require.NoError(t, os.WriteFile(dir+"/screening.yaml", []byte(yaml), 0o600))
+
+	cfg, err := Load(dir, "screening")
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Screening.Enabled)
+	assert.False(t, cfg.Screening.LoadFromDatabase)
+	require.Len(t, cfg.Screening.Rules, 2)
+	assert.Equal(t, "fresh-wallet-withdraw", cfg.Screening.Rules[0].ID)
+	assert.Equal(t, "BLOCK", cfg.Screening.Rules[0].Action)
+	require.Len(t, cfg.Screening.Rules[0].Conditions, 2)
+	assert.Equal(t, "24h", cfg.Screening.Rules[0].Conditions[1].Value)
+	assert.Equal(t, []string{"IR", "KP"}, cfg.Screening.Rules[1].Conditions[0].Values)
+}
+
+func TestScreeningConfig_Defaults(t *testing.T) {
+	cfg, err := Load("/nonexistent/path", "nonexistent")
+	require.NoError(t, err)
+
+	assert.False(t, cfg.Screening.Enabled)
+	assert.True(t, cfg.Screening.LoadFromDatabase)
+	assert.Empty(t, cfg.Screening.Rules)
+}
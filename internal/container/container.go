@@ -21,17 +21,51 @@ import (
 	"github.com/Haleralex/wallethub/internal/adapters/http"
 	"github.com/Haleralex/wallethub/internal/adapters/http/middleware"
 	grpcadapter "github.com/Haleralex/wallethub/internal/adapters/grpc"
+	"github.com/Haleralex/wallethub/internal/application/compliance"
 	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/displayfx"
 	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/payees"
 	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/screening"
+	"github.com/Haleralex/wallethub/internal/application/kyc"
+	"github.com/Haleralex/wallethub/internal/application/terms"
+	"github.com/Haleralex/wallethub/internal/application/usecases/readsession"
+	"github.com/Haleralex/wallethub/internal/application/usecases/replay"
+	"github.com/Haleralex/wallethub/internal/application/usecases/sandbox"
+	"github.com/Haleralex/wallethub/internal/application/usecases/jobs"
+	"github.com/Haleralex/wallethub/internal/application/usecases/onboarding"
+	operationsuc "github.com/Haleralex/wallethub/internal/application/usecases/operations"
+	incidentsuc "github.com/Haleralex/wallethub/internal/application/usecases/incidents"
+	screeninguc "github.com/Haleralex/wallethub/internal/application/usecases/screening"
+	"github.com/Haleralex/wallethub/internal/application/usecases/security"
+	"github.com/Haleralex/wallethub/internal/application/usecases/support"
 	"github.com/Haleralex/wallethub/internal/application/usecases/transaction"
 	"github.com/Haleralex/wallethub/internal/application/usecases/user"
 	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	webhooksuc "github.com/Haleralex/wallethub/internal/application/usecases/webhooks"
+	workersuc "github.com/Haleralex/wallethub/internal/application/usecases/workers"
+	"github.com/Haleralex/wallethub/internal/application/walletlimit"
 	"github.com/Haleralex/wallethub/internal/config"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/balancesummary"
+	"github.com/Haleralex/wallethub/internal/infrastructure/statuspage"
 	"github.com/Haleralex/wallethub/internal/infrastructure/cache"
+	"github.com/Haleralex/wallethub/internal/infrastructure/eventbus"
 	"github.com/Haleralex/wallethub/internal/infrastructure/exchange"
+	"github.com/Haleralex/wallethub/internal/infrastructure/faultinject"
+	"github.com/Haleralex/wallethub/internal/infrastructure/leaselock"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
 	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/postgres"
+	"github.com/Haleralex/wallethub/internal/infrastructure/requestcapture"
+	"github.com/Haleralex/wallethub/internal/infrastructure/scheduler"
+	"github.com/Haleralex/wallethub/internal/infrastructure/securitylog"
 	"github.com/Haleralex/wallethub/internal/infrastructure/telemetry"
+	"github.com/Haleralex/wallethub/internal/infrastructure/walletmigration"
+	"github.com/Haleralex/wallethub/internal/infrastructure/webhooks"
+	"github.com/Haleralex/wallethub/internal/infrastructure/workers"
+	"github.com/Haleralex/wallethub/internal/pkg/logger"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
@@ -46,32 +80,141 @@ import (
 type Container struct {
 	config *config.Config
 	logger *slog.Logger
+	// logLevels - уровни логирования по компонентам (log.components),
+	// перечитываются ReloadLogLevels
+	logLevels *logger.Levels
 
 	// Infrastructure
 	pool           *pgxpool.Pool
+	replicaPool    *pgxpool.Pool // nil - реплики нет, всё читается с primary
+	readSessions   *postgres.ReadSessionManager // nil - storage.backend: memory
+	memStore       *memory.Store // standalone (storage.backend: memory); nil - PostgreSQL
+	storageCaps    ports.StorageCapabilities
 	tracerProvider *sdktrace.TracerProvider
 	meterProvider  *sdkmetric.MeterProvider
 	redisClient    *redis.Client
 
 	// Cache / Distributed primitives
-	tokenBlacklist  ports.TokenBlacklist
-	distributedLock ports.DistributedLock
+	tokenBlacklist    ports.TokenBlacklist
+	distributedLock   ports.DistributedLock
+	confirmationStore ports.ConfirmationStore
 
 	// Repositories
 	userRepo        ports.UserRepository
+	kycHistoryRepo  ports.KYCHistoryRepository
 	walletRepo      ports.WalletRepository
+	walletNoteRepo  ports.WalletNoteRepository
+	walletSettingsRepo ports.WalletSettingsRepository
 	transactionRepo ports.TransactionRepository
+	onboardingRepo  ports.OnboardingRepository
+	// Читающие use cases получают только reader: сейчас это те же
+	// репозитории, но reader можно привязать к отдельному пулу
+	walletReader      ports.WalletReader
+	transactionReader ports.TransactionReader
+	transactionReceiptRepo ports.TransactionReceiptRepository
+	transactionActivityRepo ports.TransactionActivityRepository
+	sandboxRepo     ports.SandboxRepository
 	outboxRepo      *postgres.OutboxRepository
 
+	securityEventRepo ports.SecurityEventRepository
+	failedRequestRepo ports.FailedRequestRepository
+
+	// Журнал обработанных событий внутренних потребителей (processed_events)
+	dedupStore ports.DedupStore
+
+	// PostgreSQL реализация walletRepo до обёрток (сверка схем балансов)
+	pgWalletRepo *postgres.WalletRepository
+
 	// Unit of Work
 	uow ports.UnitOfWork
 
 	// Event Publisher
 	eventPublisher ports.EventPublisher
 
+	// In-process event bus (после COMMIT)
+	eventBus *eventbus.Bus
+	// eventSpool - журнал недоставленных шиной событий (nil - выключен)
+	eventSpool *eventbus.Spool
+
+	// Дебаунс изменений балансов в WalletBalanceSummary (nil - выключен)
+	balanceSummary *balancesummary.Summarizer
+
+	// Журнал событий аутентификации (асинхронная запись + детектор подбора)
+	securityLog *securitylog.Log
+
+	// Образцы неудачных денежных операций (nil - не пишутся)
+	failedRequests *requestcapture.Recorder
+
+	// Сверка схем балансов кошельков (nil - обе схемы не пишутся)
+	walletCompareJob *walletmigration.CompareJob
+
+	// Планировщик фоновых задач (nil - выключен)
+	jobScheduler *scheduler.Scheduler
+
+	// Heartbeat фоновых компонентов (GET /admin/workers, предупреждения /ready)
+	workerRegistry *workers.Registry
+
+	// Данные публичной страницы статуса (nil - GET /status выключен)
+	statusPage *statuspage.Page
+
 	// Fraud Detector
 	fraudDetector ports.FraudDetector
 
+	// Fee Calculator (nil - комиссии не взимаются)
+	feeCalculator ports.FeeCalculator
+
+	// Per-wallet limiter параллельных мутаций
+	walletLimiter ports.WalletLimiter
+
+	// Версия сборки: штамп created_by_version / producer_version
+	buildInfo ports.BuildInfo
+
+	// PAN/IBAN в external reference и metadata: маскировать или отклонять
+	sensitiveDataPolicy ports.SensitiveDataPolicy
+
+	// Лимит незавершённых транзакций на кошелёк
+	pendingPolicy ports.PendingTransactionsPolicy
+
+	// Валюты новых кошельков и лимиты сумм (отдаются в /meta/currencies).
+	// Source читается на каждый запрос: перечитанная конфигурация
+	// подхватывается без перезапуска
+	currencyPolicy ports.CurrencyPolicySource
+
+	// Квоты суммарного объёма metadata транзакций кошелька
+	metadataQuota ports.MetadataQuota
+
+	// Compliance (jurisdictions / retention)
+	compliancePolicy *compliance.Policy
+
+	// Скрининг транзакций (nil - выключен)
+	screeningRules      *screening.RuleSet
+	transactionScreener ports.TransactionScreener
+
+	// Курсы валют (обмен и лимит первого перевода новому получателю)
+	exchangeProvider ports.ExchangeRateProvider
+
+	// Проверка первых переводов новым получателям
+	payeeGuard ports.NewPayeeGuard
+
+	// Terms of service
+	termsPolicy terms.Policy
+	termsGate   ports.TermsGate
+
+	// KYC владельца при выводе средств
+	kycGate ports.KYCGate
+
+	// Аварийные выключатели операций (system_settings)
+	operationSwitchRepo ports.OperationSwitchRepository
+	fxRateSnapshotRepo  ports.FXRateSnapshotRepository
+	operationGate       ports.OperationGate
+
+	// Инциденты страницы статуса
+	incidentRepo ports.IncidentRepository
+
+	// Webhook подписки пользователей и очередь их доставок
+	webhookSubscriptionRepo ports.WebhookSubscriptionRepository
+	webhookDeliveryRepo     ports.WebhookDeliveryRepository
+
 	// CQRS Buses
 	commandBus *cqrs.CommandBus
 	queryBus   *cqrs.QueryBus
@@ -79,20 +222,59 @@ type Container struct {
 	// Use Cases
 	createUserUC             *user.CreateUserUseCase
 	getUserUC                *user.GetUserUseCase
+	getAdminUserUC           *user.GetAdminUserUseCase
+	updateUserUC             *user.UpdateUserUseCase
+	reviewKYCUC              *user.ReviewKYCUseCase
+	revokeKYCUC              *user.RevokeKYCUseCase
+	acceptTermsUC            *user.AcceptTermsUseCase
+	getKYCHistoryUC          *user.GetKYCHistoryUseCase
+	onboardUserUC            *onboarding.OnboardUserUseCase
 	createWalletUC           *wallet.CreateWalletUseCase
 	creditWalletUC           *wallet.CreditWalletUseCase
 	debitWalletUC            *wallet.DebitWalletUseCase
+	closeWalletUC            *wallet.CloseWalletWithSweepUseCase
+	ensureWalletUC           *wallet.EnsureWalletUseCase
 	getWalletUC              *wallet.GetWalletUseCase
+	getWalletBalanceUC       *wallet.GetWalletBalanceUseCase
+	getActivityHeatmapUC     *wallet.GetActivityHeatmapUseCase
 	listWalletsUC            *wallet.ListWalletsUseCase
+	createWalletNoteUC       *wallet.CreateWalletNoteUseCase
+	listWalletNotesUC        *wallet.ListWalletNotesUseCase
+	replayWalletUC           *replay.ReplayWalletUseCase
+	setWalletNotePinnedUC    *wallet.SetWalletNotePinnedUseCase
+	deleteWalletNoteUC       *wallet.DeleteWalletNoteUseCase
+	updateWalletSettingsUC   *wallet.UpdateWalletSettingsUseCase
 	createTransactionUC      *transaction.CreateTransactionUseCase
 	processTransactionUC     *transaction.ProcessTransactionUseCase
 	cancelTransactionUC      *transaction.CancelTransactionUseCase
 	transferBetweenWalletsUC *transaction.TransferBetweenWalletsUseCase
+	bulkTransferUC           *transaction.BulkTransferUseCase
 	exchangeCurrencyUC      *transaction.ExchangeCurrencyUseCase
 	getByIdempotencyKeyUC   *transaction.GetTransactionByIdempotencyKeyUseCase
 	getTransactionUC        *transaction.GetTransactionUseCase
+	getReceiptUC            *transaction.GetReceiptUseCase
 	listTransactionsUC      *transaction.ListTransactionsUseCase
+	exportTransactionsUC    *transaction.ExportTransactionsUseCase
+	getFailureStatsUC       *transaction.GetFailureStatsUseCase
 	retryTransactionUC      *transaction.RetryTransactionUseCase
+	resetSandboxUC          *sandbox.ResetTenantUseCase
+	openReadSessionUC       *readsession.OpenReadSessionUseCase
+	listSecurityEventsUC    *security.ListSecurityEventsUseCase
+	listFailedRequestsUC    *support.ListFailedRequestsUseCase
+	listScreeningRulesUC    *screeninguc.ListScreeningRulesUseCase
+	dryRunScreeningUC       *screeninguc.DryRunScreeningUseCase
+	listJobsUC              *jobs.ListJobsUseCase
+	listWorkersUC           *workersuc.ListWorkersUseCase
+	runJobUC                *jobs.RunJobUseCase
+	setOperationSwitchUC    *operationsuc.SetOperationSwitchUseCase
+	createIncidentUC        *incidentsuc.CreateIncidentUseCase
+	resolveIncidentUC       *incidentsuc.ResolveIncidentUseCase
+	createWebhookUC         *webhooksuc.CreateWebhookUseCase
+	listWebhooksUC          *webhooksuc.ListWebhooksUseCase
+	getWebhookUC            *webhooksuc.GetWebhookUseCase
+	updateWebhookUC         *webhooksuc.UpdateWebhookUseCase
+	deleteWebhookUC         *webhooksuc.DeleteWebhookUseCase
+	listWebhookDeliveriesUC *webhooksuc.ListWebhookDeliveriesUseCase
 
 	// HTTP
 	httpServer *http.Server
@@ -100,8 +282,21 @@ type Container struct {
 
 // New создаёт новый контейнер с заданной конфигурацией.
 func New(cfg *config.Config) *Container {
+	storageCaps := ports.PostgresCapabilities
+	if cfg.Storage.IsMemory() {
+		storageCaps = ports.MemoryCapabilities
+	}
+
 	return &Container{
-		config: cfg,
+		config:      cfg,
+		storageCaps: storageCaps,
+		buildInfo: ports.BuildInfo{
+			Version:   cfg.App.Version,
+			GitCommit: cfg.App.GitCommit,
+		},
+		sensitiveDataPolicy: ports.SensitiveDataPolicy{Strict: cfg.SensitiveData.Strict},
+		pendingPolicy:       ports.PendingTransactionsPolicy{MaxPerWallet: cfg.Transactions.MaxPendingPerWallet},
+		currencyPolicy:      cfg.Currencies.Policy(),
 	}
 }
 
@@ -109,54 +304,32 @@ func New(cfg *config.Config) *Container {
 // Initialization
 // ============================================
 
-// Initialize инициализирует все зависимости.
+// Initialize инициализирует все зависимости по этапам (startupStages).
+// Каждый этап ограничен своим бюджетом app.startup, весь старт - общим
+// дедлайном; ошибка называет этап и время, которое он занял.
 func (c *Container) Initialize(ctx context.Context) error {
 	c.logger = c.initLogger()
-	c.logger.Info("Initializing application container...")
 
-	// 0. Telemetry (tracing)
-	if err := c.initTracing(ctx); err != nil {
-		c.logger.Warn("Failed to initialize tracing, continuing without it", slog.String("error", err.Error()))
-	}
-
-	// 0b. Telemetry (metrics push for Fly.io — locally Alloy scrapes /metrics instead)
-	if err := c.initMetrics(ctx); err != nil {
-		c.logger.Warn("Failed to initialize metrics provider, continuing without it",
-			slog.String("error", err.Error()))
-	}
+	stages := c.startupStages()
+	deadline := startupDeadline(c.config.App.Startup, stages)
+	c.logger.Info("Initializing application container...",
+		slog.Int("stages", len(stages)),
+		slog.Duration("deadline", deadline),
+	)
 
-	// 1. Database
-	if err := c.initDatabase(ctx); err != nil {
-		return fmt.Errorf("failed to initialize database: %w", err)
-	}
-	c.logger.Info("Database connected")
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
 
-	// 1b. Redis (optional — warn and continue if unavailable)
-	if err := c.initRedis(); err != nil {
-		c.logger.Warn("Redis unavailable, running without distributed cache",
-			slog.String("error", err.Error()))
+	start := time.Now()
+	if err := runStartupStages(ctx, c.logger, stages); err != nil {
+		return err
 	}
 
-	// 2. Repositories
-	c.initRepositories()
-	c.logger.Info("Repositories initialized")
-
-	// 3. Fraud Detector
-	c.initFraudDetector()
-
-	// 4. Use Cases
-	c.initUseCases()
-	c.logger.Info("Use cases initialized")
-
-	// 5. CQRS Buses
-	c.initCQRS()
-	c.logger.Info("CQRS buses initialized")
-
-	// 6. HTTP Server
-	c.initHTTPServer()
-	c.logger.Info("HTTP server initialized")
-
-	c.logger.Info("Container initialization complete")
+	c.logger.Info("Startup complete",
+		slog.Duration("duration", time.Since(start)),
+		slog.String("storage", c.config.Storage.Backend),
+		slog.Any("components", c.enabledComponents()),
+	)
 	return nil
 }
 
@@ -231,14 +404,15 @@ func (c *Container) initFraudDetector() {
 
 // initRedis инициализирует Redis клиент и зависимые компоненты.
 // Не возвращает fatal-ошибку — приложение продолжит работу без Redis.
-func (c *Container) initRedis() error {
-	rdb, err := cache.NewRedisClient(c.config.Redis)
+func (c *Container) initRedis(ctx context.Context) error {
+	rdb, err := cache.NewRedisClient(ctx, c.config.Redis)
 	if err != nil {
 		return err
 	}
 	c.redisClient = rdb
 	c.tokenBlacklist = cache.NewRedisTokenBlacklist(rdb)
 	c.distributedLock = cache.NewRedisDistributedLock(rdb)
+	c.confirmationStore = cache.NewRedisConfirmationStore(rdb)
 	c.logger.Info("Redis connected",
 		slog.String("addr", fmt.Sprintf("%s:%d", c.config.Redis.Host, c.config.Redis.Port)),
 	)
@@ -247,57 +421,96 @@ func (c *Container) initRedis() error {
 
 // initCQRS инициализирует Command Bus и Query Bus с middleware pipeline.
 func (c *Container) initCQRS() {
-	// Command Bus — middleware: Recovery → Tracing → Logging
-	c.commandBus = cqrs.NewCommandBus(
+	// Middleware: Recovery → Tracing → Logging [→ UnitOfWork guard]
+	middlewares := []cqrs.Middleware{
 		cqrs.RecoveryMiddleware(c.logger),
 		cqrs.TracingMiddleware(),
 		cqrs.LoggingMiddleware(c.logger),
-	)
+	}
+	// Guard ловит запросы мимо транзакции UnitOfWork: в development - в лог,
+	// со storage.strict_uow - паникой (Recovery превращает её в ошибку)
+	if c.config.App.IsDevelopment() || c.config.Storage.StrictUoW {
+		middlewares = append(middlewares, cqrs.UnitOfWorkGuardMiddleware(c.logger, c.config.Storage.StrictUoW))
+	}
 
-	// Query Bus — middleware: Recovery → Tracing → Logging
-	c.queryBus = cqrs.NewQueryBus(
-		cqrs.RecoveryMiddleware(c.logger),
-		cqrs.TracingMiddleware(),
-		cqrs.LoggingMiddleware(c.logger),
-	)
+	c.commandBus = cqrs.NewCommandBus(middlewares...)
+	c.queryBus = cqrs.NewQueryBus(middlewares...)
 
 	// Register Command Handlers
 	cqrs.RegisterCommandHandler[dtos.CreateUserCommand, *dtos.UserCreatedDTO](c.commandBus, c.createUserUC)
+	cqrs.RegisterCommandHandler[dtos.UpdateUserCommand, *dtos.UserDTO](c.commandBus, c.updateUserUC)
+	cqrs.RegisterCommandHandler[dtos.ApproveKYCCommand, *dtos.UserDTO](c.commandBus, c.reviewKYCUC)
+	cqrs.RegisterCommandHandler[dtos.RevokeKYCCommand, *dtos.UserDTO](c.commandBus, c.revokeKYCUC)
+	cqrs.RegisterCommandHandler[dtos.AcceptTermsCommand, *dtos.UserDTO](c.commandBus, c.acceptTermsUC)
+	cqrs.RegisterCommandHandler[dtos.OnboardUserCommand, *dtos.OnboardingResultDTO](c.commandBus, c.onboardUserUC)
 	cqrs.RegisterCommandHandler[dtos.CreateWalletCommand, *dtos.WalletDTO](c.commandBus, c.createWalletUC)
 	cqrs.RegisterCommandHandler[dtos.CreditWalletCommand, *dtos.WalletOperationDTO](c.commandBus, c.creditWalletUC)
 	cqrs.RegisterCommandHandler[dtos.DebitWalletCommand, *dtos.WalletOperationDTO](c.commandBus, c.debitWalletUC)
+	cqrs.RegisterCommandHandler[dtos.CloseWalletCommand, *dtos.CloseWalletResultDTO](c.commandBus, c.closeWalletUC)
+	cqrs.RegisterCommandHandler[dtos.EnsureWalletCommand, *dtos.EnsureWalletResultDTO](c.commandBus, c.ensureWalletUC)
 	cqrs.RegisterCommandHandler[dtos.TransferFundsCommand, *dtos.TransferResultDTO](c.commandBus, c.transferBetweenWalletsUC)
+	cqrs.RegisterCommandHandler[dtos.BulkTransferCommand, *dtos.BulkTransferResultDTO](c.commandBus, c.bulkTransferUC)
 	cqrs.RegisterCommandHandler[dtos.ExchangeCurrencyCommand, *dtos.ExchangeResultDTO](c.commandBus, c.exchangeCurrencyUC)
 	cqrs.RegisterCommandHandler[dtos.RetryTransactionCommand, *dtos.TransactionDTO](c.commandBus, c.retryTransactionUC)
 	cqrs.RegisterCommandHandler[dtos.CancelTransactionCommand, *dtos.TransactionDTO](c.commandBus, c.cancelTransactionUC)
+	cqrs.RegisterCommandHandler[dtos.ResetSandboxCommand, *dtos.SandboxResetDTO](c.commandBus, c.resetSandboxUC)
+	if c.openReadSessionUC != nil {
+		cqrs.RegisterCommandHandler[dtos.OpenReadSessionCommand, *dtos.ReadSessionDTO](c.commandBus, c.openReadSessionUC)
+	}
+	cqrs.RegisterCommandHandler[dtos.RunJobCommand, *dtos.JobRunDTO](c.commandBus, c.runJobUC)
+	cqrs.RegisterCommandHandler[dtos.CreateWalletNoteCommand, *dtos.WalletNoteDTO](c.commandBus, c.createWalletNoteUC)
+	cqrs.RegisterCommandHandler[dtos.SetWalletNotePinnedCommand, *dtos.WalletNoteDTO](c.commandBus, c.setWalletNotePinnedUC)
+	cqrs.RegisterCommandHandler[dtos.DeleteWalletNoteCommand, *dtos.WalletNoteDTO](c.commandBus, c.deleteWalletNoteUC)
+	cqrs.RegisterCommandHandler[dtos.UpdateWalletSettingsCommand, *dtos.WalletSettingsDTO](c.commandBus, c.updateWalletSettingsUC)
+	cqrs.RegisterCommandHandler[dtos.SetOperationSwitchCommand, *dtos.OperationSwitchDTO](c.commandBus, c.setOperationSwitchUC)
+	cqrs.RegisterCommandHandler[dtos.CreateIncidentCommand, *dtos.IncidentDTO](c.commandBus, c.createIncidentUC)
+	cqrs.RegisterCommandHandler[dtos.ResolveIncidentCommand, *dtos.IncidentDTO](c.commandBus, c.resolveIncidentUC)
+	cqrs.RegisterCommandHandler[dtos.CreateWebhookCommand, *dtos.WebhookSubscriptionCreatedDTO](c.commandBus, c.createWebhookUC)
+	cqrs.RegisterCommandHandler[dtos.UpdateWebhookCommand, *dtos.WebhookSubscriptionDTO](c.commandBus, c.updateWebhookUC)
+	cqrs.RegisterCommandHandler[dtos.DeleteWebhookCommand, *dtos.WebhookSubscriptionDTO](c.commandBus, c.deleteWebhookUC)
 
 	// Register Query Handlers
 	cqrs.RegisterQueryHandler[dtos.GetUserQuery, *dtos.UserDTO](c.queryBus, c.getUserUC)
+	cqrs.RegisterQueryHandler[dtos.GetAdminUserQuery, *dtos.AdminUserDTO](c.queryBus, c.getAdminUserUC)
+	cqrs.RegisterQueryHandler[dtos.GetKYCHistoryQuery, *dtos.KYCHistoryDTO](c.queryBus, c.getKYCHistoryUC)
 	cqrs.RegisterQueryHandler[dtos.GetWalletQuery, *dtos.WalletDTO](c.queryBus, c.getWalletUC)
+	cqrs.RegisterQueryHandler[dtos.GetWalletBalanceQuery, *dtos.WalletBalanceDTO](c.queryBus, c.getWalletBalanceUC)
+	cqrs.RegisterQueryHandler[dtos.GetActivityHeatmapQuery, *dtos.ActivityHeatmapDTO](c.queryBus, c.getActivityHeatmapUC)
 	cqrs.RegisterQueryHandler[dtos.ListWalletsQuery, *dtos.WalletListDTO](c.queryBus, c.listWalletsUC)
+	cqrs.RegisterQueryHandler[dtos.ListWalletNotesQuery, *dtos.WalletNoteListDTO](c.queryBus, c.listWalletNotesUC)
+	cqrs.RegisterQueryHandler[dtos.ReplayWalletQuery, *dtos.WalletReplayDTO](c.queryBus, c.replayWalletUC)
 	cqrs.RegisterQueryHandler[dtos.GetTransactionQuery, *dtos.TransactionDTO](c.queryBus, c.getTransactionUC)
+	cqrs.RegisterQueryHandler[dtos.GetReceiptQuery, *dtos.ReceiptDTO](c.queryBus, c.getReceiptUC)
 	cqrs.RegisterQueryHandler[dtos.ListTransactionsQuery, *dtos.TransactionListDTO](c.queryBus, c.listTransactionsUC)
+	cqrs.RegisterQueryHandler[dtos.ExportTransactionsQuery, *dtos.TransactionExportDTO](c.queryBus, c.exportTransactionsUC)
+	cqrs.RegisterQueryHandler[dtos.GetTransactionFailureStatsQuery, *dtos.TransactionFailureStatsDTO](c.queryBus, c.getFailureStatsUC)
 	cqrs.RegisterQueryHandler[dtos.GetTransactionByIdempotencyKeyQuery, *dtos.TransactionDTO](c.queryBus, c.getByIdempotencyKeyUC)
+	cqrs.RegisterQueryHandler[dtos.ListSecurityEventsQuery, *dtos.SecurityEventListDTO](c.queryBus, c.listSecurityEventsUC)
+	cqrs.RegisterQueryHandler[dtos.ListFailedRequestsQuery, *dtos.FailedRequestListDTO](c.queryBus, c.listFailedRequestsUC)
+	cqrs.RegisterQueryHandler[dtos.ListScreeningRulesQuery, *dtos.ScreeningRuleListDTO](c.queryBus, c.listScreeningRulesUC)
+	cqrs.RegisterQueryHandler[dtos.DryRunScreeningQuery, *dtos.ScreeningDryRunDTO](c.queryBus, c.dryRunScreeningUC)
+	cqrs.RegisterQueryHandler[dtos.ListJobsQuery, *dtos.JobListDTO](c.queryBus, c.listJobsUC)
+	cqrs.RegisterQueryHandler[dtos.ListWorkersQuery, *dtos.WorkerListDTO](c.queryBus, c.listWorkersUC)
+	cqrs.RegisterQueryHandler[dtos.ListWebhooksQuery, *dtos.WebhookSubscriptionListDTO](c.queryBus, c.listWebhooksUC)
+	cqrs.RegisterQueryHandler[dtos.GetWebhookQuery, *dtos.WebhookSubscriptionDTO](c.queryBus, c.getWebhookUC)
+	cqrs.RegisterQueryHandler[dtos.ListWebhookDeliveriesQuery, *dtos.WebhookDeliveryListDTO](c.queryBus, c.listWebhookDeliveriesUC)
 }
 
-// initLogger инициализирует логгер.
+// initLogger инициализирует логгер. Уровень фильтрует LeveledHandler:
+// у компонентов (logger.WithComponent) он свой и меняется без рестарта.
 func (c *Container) initLogger() *slog.Logger {
 	var handler slog.Handler
 
-	level := slog.LevelInfo
-	switch c.config.Log.Level {
-	case "debug":
-		level = slog.LevelDebug
-	case "info":
-		level = slog.LevelInfo
-	case "warn":
-		level = slog.LevelWarn
-	case "error":
-		level = slog.LevelError
+	// Config.Validate уже проверил уровни; здесь ошибка возможна только для
+	// конфигурации, собранной в обход Load - тогда берём info
+	levels, err := logger.NewLevels(c.config.Log.Level, c.config.Log.Components)
+	if err != nil {
+		levels, _ = logger.NewLevels("info", nil)
 	}
+	c.logLevels = levels
 
 	opts := &slog.HandlerOptions{
-		Level:     level,
+		Level:     slog.LevelDebug,
 		AddSource: c.config.App.Debug,
 	}
 
@@ -307,14 +520,32 @@ func (c *Container) initLogger() *slog.Logger {
 		handler = slog.NewTextHandler(os.Stdout, opts)
 	}
 
-	logger := slog.New(handler)
-	slog.SetDefault(logger)
+	l := slog.New(logger.NewLeveledHandler(handler, levels))
+	slog.SetDefault(l)
 
-	return logger
+	return l
 }
 
-// initDatabase инициализирует подключение к БД.
+// ReloadLogLevels применяет log.level и log.components без рестарта
+// (SIGHUP). При ошибке действуют прежние уровни. Логгер, переданный
+// через ContainerBuilder.WithLogger, уровнями контейнера не управляется.
+func (c *Container) ReloadLogLevels(cfg config.LogConfig) error {
+	if c.logLevels == nil {
+		return fmt.Errorf("log levels are not managed by the container")
+	}
+	return c.logLevels.Set(cfg.Level, cfg.Components)
+}
+
+// initDatabase инициализирует подключение к БД. В standalone профиле
+// вместо пула создаётся in-memory хранилище.
 func (c *Container) initDatabase(ctx context.Context) error {
+	if c.config.Storage.IsMemory() {
+		c.memStore = memory.NewStore()
+		c.logger.Warn("Standalone storage: data is kept in memory and lost on restart",
+			slog.Any("degraded", c.storageCaps.Degraded()))
+		return nil
+	}
+
 	poolConfig, err := pgxpool.ParseConfig(c.config.Database.DSN())
 	if err != nil {
 		return fmt.Errorf("failed to parse database URL: %w", err)
@@ -325,6 +556,9 @@ func (c *Container) initDatabase(ctx context.Context) error {
 	poolConfig.MaxConnLifetime = c.config.Database.MaxConnLifetime
 	poolConfig.MaxConnIdleTime = c.config.Database.MaxConnIdleTime
 
+	// Сессия в UTC: NOW() и timestamptz в текстовом виде не зависят от TZ сервера БД
+	poolConfig.ConnConfig.RuntimeParams["timezone"] = "UTC"
+
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return fmt.Errorf("failed to create connection pool: %w", err)
@@ -337,35 +571,854 @@ func (c *Container) initDatabase(ctx context.Context) error {
 	}
 
 	c.pool = pool
+	return c.initReplica(ctx)
+}
+
+// initReplica подключает реплику для read-only запросов (database.replica).
+// Без host ничего не делает.
+func (c *Container) initReplica(ctx context.Context) error {
+	replica := c.config.Database.Replica
+	if !replica.Enabled() {
+		return nil
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(c.config.Database.ReplicaDSN())
+	if err != nil {
+		return fmt.Errorf("failed to parse replica database URL: %w", err)
+	}
+
+	poolConfig.MaxConns = c.config.Database.MaxConnections
+	if replica.MaxConnections > 0 {
+		poolConfig.MaxConns = replica.MaxConnections
+	}
+	poolConfig.MaxConnLifetime = c.config.Database.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = c.config.Database.MaxConnIdleTime
+	poolConfig.ConnConfig.RuntimeParams["timezone"] = "UTC"
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create replica connection pool: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return fmt.Errorf("failed to ping replica database: %w", err)
+	}
+
+	c.replicaPool = pool
+	c.logger.Info("Read replica connected",
+		slog.String("host", replica.Host),
+		slog.Duration("hedge_delay", replica.HedgeDelay),
+	)
 	return nil
 }
 
+// readReplica собирает ReadReplica для репозиториев (nil без реплики).
+func (c *Container) readReplica() *postgres.ReadReplica {
+	if c.replicaPool == nil {
+		return nil
+	}
+
+	replica := c.config.Database.Replica
+	groups := make(map[postgres.ReadGroup]bool, len(replica.HedgeGroups))
+	for group, enabled := range replica.HedgeGroups {
+		groups[postgres.ReadGroup(group)] = enabled
+	}
+
+	return postgres.NewReadReplica(c.replicaPool, postgres.ReadHedging{
+		Delay:         replica.HedgeDelay,
+		MaxConcurrent: replica.HedgeMaxConcurrent,
+		Groups:        groups,
+		OnHedge: func(group postgres.ReadGroup, primaryWon bool) {
+			middleware.RecordReadHedge(string(group), primaryWon)
+		},
+	})
+}
+
+// initReadSessions создаёт snapshot-сессии чтения: на реплике, если она
+// подключена, иначе на primary.
+func (c *Container) initReadSessions() {
+	sessions := postgres.ReadSessionConfig{
+		TTL:          c.config.ReadSessions.TTL,
+		MaxPerClient: c.config.ReadSessions.MaxPerClient,
+		MaxTotal:     c.config.ReadSessions.MaxTotal,
+		Source:       "primary",
+	}
+	pool := c.pool
+	if c.replicaPool != nil {
+		pool = c.replicaPool
+		sessions.Source = "replica"
+	}
+	c.readSessions = postgres.NewReadSessionManager(pool, sessions, c.logger)
+}
+
 // initRepositories инициализирует репозитории.
-func (c *Container) initRepositories() {
-	c.userRepo = postgres.NewUserRepository(c.pool)
-	c.walletRepo = postgres.NewWalletRepository(c.pool)
-	c.transactionRepo = postgres.NewTransactionRepository(c.pool)
-	c.outboxRepo = postgres.NewOutboxRepository(c.pool)
+func (c *Container) initRepositories() error {
+	if c.memStore != nil {
+		c.initMemoryRepositories()
+		return nil
+	}
+
+	priorities, err := ports.NewOutboxPriorities(c.config.Outbox.EventPriorities)
+	if err != nil {
+		return err
+	}
+
+	migration, err := postgres.NewWalletMigration(
+		c.config.WalletMigration.Phase,
+		c.config.WalletMigration.NewReadPercent,
+	)
+	if err != nil {
+		return err
+	}
+	migration.OnShadowMismatch = middleware.RecordWalletShadowReadMismatch
+
+	c.userRepo = postgres.NewUserRepository(c.pool).WithEmailPolicy(c.config.EmailPolicy.Policy())
+	c.kycHistoryRepo = postgres.NewKYCHistoryRepository(c.pool)
+	c.pgWalletRepo = postgres.NewWalletRepository(c.pool).
+		WithMigration(migration).
+		WithReadReplica(c.readReplica())
+	c.walletRepo = c.pgWalletRepo
+	c.walletNoteRepo = postgres.NewWalletNoteRepository(c.pool)
+	c.onboardingRepo = postgres.NewOnboardingRepository(c.pool)
+	c.walletSettingsRepo = postgres.NewWalletSettingsRepository(c.pool)
+	c.metadataQuota = ports.MetadataQuota{
+		Usage:          postgres.NewMetadataUsageRepository(c.pool),
+		SoftLimitBytes: c.config.Transactions.MetadataSoftLimitBytes,
+		HardLimitBytes: c.config.Transactions.MetadataHardLimitBytes,
+	}
+	pgTransactionRepo := postgres.NewTransactionRepository(c.pool).
+		WithEnvironment(c.config.App.Environment).
+		WithInstance(c.workerRegistry.Instance())
+	c.transactionRepo = pgTransactionRepo
+	c.transactionReceiptRepo = pgTransactionRepo
+	c.transactionActivityRepo = pgTransactionRepo
+	c.walletReader = c.walletRepo
+	c.transactionReader = c.transactionRepo
+	c.sandboxRepo = postgres.NewSandboxRepository(c.pool)
+	c.securityEventRepo = postgres.NewSecurityEventRepository(c.pool)
+	c.failedRequestRepo = postgres.NewFailedRequestRepository(c.pool)
+	c.operationSwitchRepo = postgres.NewOperationSwitchRepository(c.pool)
+	c.fxRateSnapshotRepo = postgres.NewFXRateSnapshotRepository(c.pool)
+	c.incidentRepo = postgres.NewIncidentRepository(c.pool)
+	c.webhookSubscriptionRepo = postgres.NewWebhookSubscriptionRepository(c.pool)
+	c.webhookDeliveryRepo = postgres.NewWebhookDeliveryRepository(c.pool)
+	c.outboxRepo = postgres.NewOutboxRepository(c.pool, c.buildInfo.ProducerVersion(), priorities)
+	c.initReadSessions()
+
+	// Дедупликация потребителей событий: повторы после первого дубля
+	// отсекаются в Redis, без обращения к processed_events
+	c.dedupStore = postgres.NewDedupStore(c.pool)
+	if c.redisClient != nil {
+		ttl := c.config.Messaging.DedupRetention
+		if ttl <= 0 {
+			ttl = 24 * time.Hour
+		}
+		c.dedupStore = cache.NewRedisDedupStore(c.redisClient, c.dedupStore, ttl)
+	}
 
 	// Unit of Work
 	c.uow = postgres.NewUnitOfWork(c.pool)
 
 	// Event Publisher (OutboxRepository реализует интерфейс)
 	c.eventPublisher = c.outboxRepo
+	return nil
+}
+
+// initMemoryRepositories собирает репозитории standalone профиля поверх
+// одного memory.Store. UnitOfWork откатывает снимок хранилища, события
+// пишутся в то же хранилище (outbox и его relay не нужны).
+func (c *Container) initMemoryRepositories() {
+	store := c.memStore
+
+	c.userRepo = memory.NewUserRepository(store)
+	c.kycHistoryRepo = memory.NewKYCHistoryRepository(store)
+	c.walletRepo = memory.NewWalletRepository(store)
+	c.walletNoteRepo = memory.NewWalletNoteRepository(store)
+	c.onboardingRepo = memory.NewOnboardingRepository(store)
+	c.walletSettingsRepo = memory.NewWalletSettingsRepository(store)
+	c.metadataQuota = ports.MetadataQuota{
+		Usage:          memory.NewMetadataUsageRepository(store),
+		SoftLimitBytes: c.config.Transactions.MetadataSoftLimitBytes,
+		HardLimitBytes: c.config.Transactions.MetadataHardLimitBytes,
+	}
+	transactionRepo := memory.NewTransactionRepository(store).
+		WithEnvironment(c.config.App.Environment).
+		WithInstance(c.workerRegistry.Instance())
+	c.transactionRepo = transactionRepo
+	c.transactionReceiptRepo = transactionRepo
+	c.transactionActivityRepo = transactionRepo
+	c.walletReader = c.walletRepo
+	c.transactionReader = c.transactionRepo
+	c.sandboxRepo = memory.NewSandboxRepository(store)
+	c.securityEventRepo = memory.NewSecurityEventRepository(store)
+	c.failedRequestRepo = memory.NewFailedRequestRepository(store)
+	c.operationSwitchRepo = memory.NewOperationSwitchRepository(store)
+	c.fxRateSnapshotRepo = memory.NewFXRateSnapshotRepository(store)
+	c.incidentRepo = memory.NewIncidentRepository(store)
+	c.webhookSubscriptionRepo = memory.NewWebhookSubscriptionRepository(store)
+	c.webhookDeliveryRepo = memory.NewWebhookDeliveryRepository(store)
+	c.dedupStore = memory.NewDedupStore(store)
+
+	c.uow = memory.NewUnitOfWork(store)
+	c.eventPublisher = memory.NewEventPublisher(store)
+}
+
+// initEventBus подключает EventPublisher по режиму доставки (messaging.delivery)
+// и запускает внутреннюю шину:
+//   - outbox: события пишутся в outbox и после COMMIT дублируются в шину
+//   - sync: outbox не используется, шина раздаёт события до ответа на запрос
+//   - none: события отбрасываются, шина ничего не получает
+func (c *Container) initEventBus() error {
+	mode, err := eventbus.ParseDeliveryMode(c.config.Messaging.Delivery)
+	if err != nil {
+		return err
+	}
+
+	// Без доставки журналировать нечего
+	if spoolCfg := c.config.Messaging.Spool; spoolCfg.Enabled && mode != eventbus.DeliveryNone {
+		spool, err := eventbus.OpenSpool(logger.WithComponent(c.logger, "event_spool"), eventbus.SpoolConfig{
+			Dir:        spoolCfg.Dir,
+			MaxEntries: spoolCfg.MaxEntries,
+			MaxReplays: spoolCfg.MaxReplays,
+		})
+		if err != nil {
+			return err
+		}
+		c.eventSpool = spool
+	}
+
+	c.eventBus = eventbus.New(c.logger, eventbus.Config{
+		Sync:       mode == eventbus.DeliverySync,
+		SyncBudget: c.config.Messaging.SyncBudget,
+		Spool:      c.eventSpool,
+	})
+
+	switch mode {
+	case eventbus.DeliverySync:
+		c.eventPublisher = eventbus.WrapEventPublisher(eventbus.DiscardPublisher(), c.eventBus)
+		c.uow = eventbus.WrapUnitOfWork(c.uow, c.eventBus)
+	case eventbus.DeliveryNone:
+		c.eventPublisher = eventbus.DiscardPublisher()
+	default:
+		c.eventPublisher = eventbus.WrapEventPublisher(c.eventPublisher, c.eventBus)
+		c.uow = eventbus.WrapUnitOfWork(c.uow, c.eventBus)
+	}
+	c.eventBus.Start()
+
+	if mode != eventbus.DeliveryOutbox {
+		c.logger.Warn("Events bypass the outbox", slog.String("delivery", string(mode)))
+	}
+	return nil
+}
+
+// initBalanceSummary подписывает дебаунс изменений балансов на шину.
+// Summary публикуются через тот же outbox, что и доменные события.
+func (c *Container) initBalanceSummary() {
+	if !c.config.BalanceSummary.Enabled {
+		return
+	}
+
+	interval := c.config.BalanceSummary.Interval
+	if interval <= 0 {
+		interval = balancesummary.DefaultInterval
+	}
+
+	summarizer := balancesummary.New(logger.WithComponent(c.logger, "balance_summary"), c.walletRepo, c.eventPublisher, balancesummary.Config{
+		Interval:   interval,
+		MaxWallets: c.config.BalanceSummary.MaxWallets,
+		Heartbeat:  c.workerHeartbeat("balance-summary", 3*interval),
+	})
+	summarizer.Subscribe(c.eventBus, c.dedupStore)
+	summarizer.Start()
+
+	c.balanceSummary = summarizer
+	c.logger.Info("Wallet balance summary enabled",
+		slog.Duration("interval", c.config.BalanceSummary.Interval))
+}
+
+// initWebhooks подписывает постановку событий в очереди webhook подписок
+// на шину. Доставку выполняют задачи планировщика (initJobs).
+func (c *Container) initWebhooks() {
+	if !c.config.Webhooks.Enabled {
+		return
+	}
+
+	webhooks.NewFanout(c.webhookSubscriptionRepo, c.webhookDeliveryRepo, c.uow, c.config.Webhooks.BacklogGrace).
+		Subscribe(c.eventBus)
+	c.logger.Info("Webhooks enabled",
+		slog.Int("auto_disable_failures", c.config.Webhooks.AutoDisableFailures),
+		slog.Duration("auto_disable_after", c.config.Webhooks.AutoDisableAfter))
+}
+
+// initStatusPage запускает обновление данных публичной страницы статуса.
+// Запрос GET /status читает только память и переживает недоступность БД.
+func (c *Container) initStatusPage() {
+	if !c.config.StatusPage.Enabled {
+		return
+	}
+
+	cfg := statuspage.Config{
+		Switches:  c.operationSwitchRepo,
+		Incidents: c.incidentRepo,
+		Interval:  c.config.StatusPage.RefreshInterval,
+	}
+	if c.pool != nil {
+		cfg.Ping = c.pool.Ping
+	}
+	if c.workerRegistry != nil {
+		cfg.Workers = c.workerRegistry
+	}
+
+	c.statusPage = statuspage.New(c.logger, cfg)
+	c.statusPage.Start()
+	c.logger.Info("Status page enabled",
+		slog.Duration("refresh_interval", c.config.StatusPage.RefreshInterval))
+}
+
+// initSecurityLog запускает журнал событий аутентификации.
+// SuspiciousAuthActivity уходит через тот же outbox, что и доменные события.
+// При включённом планировщике очистку по retention выполняет задача
+// security-events-purge, а не каждый экземпляр.
+func (c *Container) initSecurityLog() {
+	retention := c.config.Security.EventRetention
+	if c.config.Jobs.Enabled {
+		retention = 0
+	}
+
+	c.securityLog = securitylog.New(c.logger, c.securityEventRepo, c.eventPublisher, securitylog.Config{
+		QueueSize:        c.config.Security.EventQueueSize,
+		FailureThreshold: c.config.Security.AuthFailureThreshold,
+		FailureWindow:    c.config.Security.AuthFailureWindow,
+		Retention:        retention,
+		OnDrop:           middleware.RecordSecurityEventDropped,
+	})
+	c.securityLog.Start()
+}
+
+// initFailedRequestCapture запускает запись образцов неудачных денежных
+// операций. При включённом планировщике очистку по retention выполняет
+// задача failed-requests-purge, а не каждый экземпляр.
+func (c *Container) initFailedRequestCapture() {
+	if !c.config.RequestCapture.Enabled {
+		return
+	}
+
+	retention := c.config.RequestCapture.Retention
+	if c.config.Jobs.Enabled {
+		retention = 0
+	}
+
+	c.failedRequests = requestcapture.New(c.logger, c.failedRequestRepo, requestcapture.Config{
+		QueueSize:       c.config.RequestCapture.QueueSize,
+		SensitiveFields: c.config.RequestCapture.SensitiveFields,
+		Retention:       retention,
+		OnDrop:          middleware.RecordFailedRequestDropped,
+	})
+	c.failedRequests.Start()
+}
+
+// initWalletMigration запускает сверку схем балансов, пока пишутся обе схемы.
+func (c *Container) initWalletMigration() {
+	phase, err := postgres.ParseWalletMigrationPhase(c.config.WalletMigration.Phase)
+	if err != nil || !phase.KeepsBothStores() || c.pgWalletRepo == nil {
+		return
+	}
+
+	cfg := walletmigration.Config{
+		Interval:   c.config.WalletMigration.CompareInterval,
+		SampleSize: c.config.WalletMigration.CompareSampleSize,
+		OnReport:   middleware.RecordWalletDivergenceSample,
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = walletmigration.DefaultInterval
+	}
+	// При включённом планировщике сверку запускает задача wallet-balance-compare
+	// (heartbeat сообщает планировщик)
+	if !c.config.Jobs.Enabled {
+		cfg.Heartbeat = c.workerHeartbeat("wallet-balance-compare", 3*cfg.Interval)
+	}
+
+	c.walletCompareJob = walletmigration.NewCompareJob(c.logger, c.pgWalletRepo, cfg)
+	if !c.config.Jobs.Enabled {
+		c.walletCompareJob.Start()
+	}
+	c.logger.Info("Wallet balance migration active", slog.String("phase", string(phase)))
+}
+
+// initJobs регистрирует фоновые задачи в планировщике и запускает его.
+// С locks.backend задачи блокируются lease вместо строк job_locks.
+//
+// Шаги задач проверяют lease (ports.CheckLease) перед записью: экземпляр,
+// потерявший lease посреди прогона, не удаляет данные за нового владельца.
+func (c *Container) initJobs(ctx context.Context) error {
+	if !c.config.Jobs.Enabled {
+		return nil
+	}
+
+	var (
+		lock    ports.LeaseLock
+		jobRepo ports.JobRepository
+		err     error
+	)
+	if c.storageCaps.AdvisoryLocks {
+		if lock, err = leaselock.New(ctx, c.config.Locks, c.pool, c.redisClient); err != nil {
+			return err
+		}
+		jobRepo = postgres.NewJobRepository(c.pool)
+	} else {
+		// Без межпроцессных блокировок задачи сериализуются внутри процесса
+		if c.config.Locks.Backend == "postgres" {
+			lock = leaselock.NewMemory()
+		} else if lock, err = leaselock.New(ctx, c.config.Locks, nil, c.redisClient); err != nil {
+			return err
+		}
+		jobRepo = memory.NewJobRepository(c.memStore)
+	}
+	if lock != nil {
+		c.logger.Info("Job leases enabled", slog.String("backend", c.config.Locks.Backend))
+	}
+
+	s := scheduler.New(c.logger, jobRepo, scheduler.Config{
+		Instance:    c.workerRegistry.Instance(),
+		Schedules:   c.config.Jobs.Schedules,
+		HistorySize: c.config.Jobs.HistorySize,
+		Heartbeat: func(job string, period time.Duration) ports.WorkerHeartbeat {
+			// Прогоны на других экземплярах тоже обновляют heartbeat (Beat)
+			return c.workerHeartbeat(job, 3*period)
+		},
+		Lock:     lock,
+		LeaseTTL: c.config.Locks.TTL,
+	})
+
+	if c.walletCompareJob != nil {
+		compareJob := c.walletCompareJob
+		if err := s.Register(scheduler.Job{
+			Name:     "wallet-balance-compare",
+			Schedule: "@every " + c.config.WalletMigration.CompareInterval.String(),
+			Timeout:  2 * time.Minute,
+			Handler: func(ctx context.Context) (int, error) {
+				report, err := compareJob.CompareOnce(ctx)
+				if err != nil {
+					return 0, err
+				}
+				return report.Sampled, nil
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if retention := c.config.Security.EventRetention; retention > 0 {
+		securityEventRepo := c.securityEventRepo
+		if err := s.Register(scheduler.Job{
+			Name:     "security-events-purge",
+			Schedule: "0 * * * *",
+			Handler: func(ctx context.Context) (int, error) {
+				if err := ports.CheckLease(ctx); err != nil {
+					return 0, err
+				}
+				deleted, err := securityEventRepo.DeleteBefore(ctx, time.Now().UTC().Add(-retention))
+				return int(deleted), err
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if retention := c.config.RequestCapture.Retention; c.config.RequestCapture.Enabled && retention > 0 {
+		failedRequestRepo := c.failedRequestRepo
+		if err := s.Register(scheduler.Job{
+			Name:     "failed-requests-purge",
+			Schedule: "45 * * * *",
+			Handler: func(ctx context.Context) (int, error) {
+				if err := ports.CheckLease(ctx); err != nil {
+					return 0, err
+				}
+				deleted, err := failedRequestRepo.DeleteBefore(ctx, time.Now().UTC().Add(-retention))
+				return int(deleted), err
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if retention := c.config.Exchange.SnapshotRetention; retention > 0 {
+		fxRateSnapshotRepo := c.fxRateSnapshotRepo
+		if err := s.Register(scheduler.Job{
+			Name:     "fx-rate-snapshots-purge",
+			Schedule: "50 4 * * *",
+			Handler: func(ctx context.Context) (int, error) {
+				if err := ports.CheckLease(ctx); err != nil {
+					return 0, err
+				}
+				deleted, err := fxRateSnapshotRepo.DeleteBefore(ctx, time.Now().UTC().Add(-retention))
+				return int(deleted), err
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if retention := c.config.Messaging.DedupRetention; retention > 0 {
+		dedupStore := c.dedupStore
+		if err := s.Register(scheduler.Job{
+			Name:     "processed-events-purge",
+			Schedule: "15 * * * *",
+			Handler: func(ctx context.Context) (int, error) {
+				if err := ports.CheckLease(ctx); err != nil {
+					return 0, err
+				}
+				deleted, err := dedupStore.DeleteBefore(ctx, time.Now().UTC().Add(-retention))
+				return int(deleted), err
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if cfg := c.config.Webhooks; cfg.Enabled {
+		if err := c.registerWebhookJobs(s, cfg); err != nil {
+			return err
+		}
+	}
+
+	// Standalone профиль пишет события без outbox - чистить нечего
+	if retention := c.config.Jobs.OutboxRetention; retention > 0 && c.outboxRepo != nil {
+		outboxRepo := c.outboxRepo
+		if err := s.Register(scheduler.Job{
+			Name:     "outbox-cleanup",
+			Schedule: "30 3 * * *",
+			Timeout:  15 * time.Minute,
+			Handler: func(ctx context.Context) (int, error) {
+				if err := ports.CheckLease(ctx); err != nil {
+					return 0, err
+				}
+				deleted, err := outboxRepo.CleanupPublished(ctx, retention)
+				return int(deleted), err
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if recovery := c.config.Jobs.ProcessingRecovery; recovery.Enabled {
+		instance := c.workerRegistry.Instance()
+		var orphans ports.ProcessingRecoveryRepository
+		if c.storageCaps.SkipLocked {
+			orphans = postgres.NewTransactionRepository(c.pool).WithInstance(instance)
+		} else {
+			// Без SKIP LOCKED восстановление рассчитано на один экземпляр
+			orphans = memory.NewTransactionRepository(c.memStore).WithInstance(instance)
+		}
+		recoverUC := transaction.NewRecoverOrphanedProcessingUseCase(
+			orphans,
+			c.instanceRepository(),
+			transaction.NewProcessTransactionUseCase(c.walletRepo, c.transactionRepo, c.eventPublisher, c.uow, logger.WithComponent(c.logger, "transaction")),
+			instance,
+			transaction.OrphanRecoveryConfig{
+				Threshold:       recovery.Threshold,
+				InstanceTimeout: recovery.InstanceTimeout,
+				BatchSize:       recovery.BatchSize,
+			},
+		)
+		if err := s.Register(scheduler.Job{
+			Name:     "processing-recovery",
+			Schedule: "@every 1m",
+			Handler:  recoverUC.Execute,
+		}); err != nil {
+			return err
+		}
+	}
+
+	s.Start()
+	c.jobScheduler = s
+	c.logger.Info("Job scheduler started")
+	return nil
+}
+
+// registerWebhookJobs регистрирует доставку webhook событий, истечение
+// очереди отключённых подписок и очистку истории доставок.
+func (c *Container) registerWebhookJobs(s *scheduler.Scheduler, cfg config.WebhooksConfig) error {
+	subs, deliveries := c.webhookSubscriptionRepo, c.webhookDeliveryRepo
+	dispatcher := webhooks.NewDispatcher(c.logger, subs, deliveries, c.uow, c.eventPublisher, nil, webhooks.Config{
+		BatchSize:      cfg.BatchSize,
+		RequestTimeout: cfg.RequestTimeout,
+		MaxAttempts:    cfg.MaxAttempts,
+		Health: entities.WebhookHealthPolicy{
+			MaxConsecutiveFailures: cfg.AutoDisableFailures,
+			MaxFailingDuration:     cfg.AutoDisableAfter,
+		},
+	})
+
+	if err := s.Register(scheduler.Job{
+		Name:     "webhook-deliveries",
+		Schedule: "@every " + cfg.DeliveryInterval.String(),
+		Timeout:  5 * time.Minute,
+		Handler: func(ctx context.Context) (int, error) {
+			// Одна доставка не должна уйти с двух экземпляров
+			if err := ports.CheckLease(ctx); err != nil {
+				return 0, err
+			}
+			return dispatcher.DeliverDue(ctx)
+		},
+	}); err != nil {
+		return err
+	}
+
+	grace := cfg.BacklogGrace
+	if grace <= 0 {
+		grace = webhooks.DefaultBacklogGrace
+	}
+	if err := s.Register(scheduler.Job{
+		Name:     "webhook-backlog-expire",
+		Schedule: "20 * * * *",
+		Handler: func(ctx context.Context) (int, error) {
+			if err := ports.CheckLease(ctx); err != nil {
+				return 0, err
+			}
+			expired, err := deliveries.ExpireBacklog(ctx, time.Now().UTC().Add(-grace))
+			return int(expired), err
+		},
+	}); err != nil {
+		return err
+	}
+
+	if retention := cfg.DeliveryRetention; retention > 0 {
+		if err := s.Register(scheduler.Job{
+			Name:     "webhook-deliveries-purge",
+			Schedule: "40 4 * * *",
+			Timeout:  15 * time.Minute,
+			Handler: func(ctx context.Context) (int, error) {
+				if err := ports.CheckLease(ctx); err != nil {
+					return 0, err
+				}
+				cutoff := time.Now().UTC().Add(-retention)
+				deleted, err := deliveries.DeleteFinishedBefore(ctx, cutoff)
+				if err != nil {
+					return int(deleted), err
+				}
+				purged, err := subs.PurgeDeleted(ctx, cutoff)
+				return int(deleted + purged), err
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initWorkers создаёт реестр heartbeat фоновых компонентов. С
+// workers.persist heartbeat пишутся в workers_heartbeat (вид кластера).
+// Heartbeat самого экземпляра (таблица instances) пишется всегда: по нему
+// processing-recovery отличает упавшие экземпляры от живых.
+func (c *Container) initWorkers() {
+	var repo ports.WorkerHeartbeatRepository
+	if c.config.Workers.Persist {
+		// Вид кластера нужен только экземплярам с общим хранилищем
+		if c.storageCaps.Durable {
+			repo = postgres.NewWorkerHeartbeatRepository(c.pool)
+		} else {
+			c.logger.Warn("workers.persist ignored: storage is local to this process")
+		}
+	}
+
+	c.workerRegistry = workers.NewRegistry(c.logger, repo, workers.Config{
+		PersistInterval: c.config.Workers.PersistInterval,
+		Instances:       c.instanceRepository(),
+	})
+	c.workerRegistry.Start()
+}
+
+// instanceRepository - liveness экземпляров для processing-recovery.
+func (c *Container) instanceRepository() ports.InstanceRepository {
+	if c.memStore != nil {
+		return memory.NewInstanceRepository(c.memStore)
+	}
+	return postgres.NewInstanceRepository(c.pool)
+}
+
+// workerHeartbeat регистрирует фоновый компонент. Порог устаревания из
+// workers.stale_after имеет приоритет над defaultStaleAfter.
+func (c *Container) workerHeartbeat(name string, defaultStaleAfter time.Duration) *workers.Worker {
+	staleAfter := defaultStaleAfter
+	if override, ok := c.config.Workers.StaleAfter[name]; ok {
+		staleAfter = override
+	}
+	return c.workerRegistry.Register(name, staleAfter)
+}
+
+// initCompliance создаёт политику юрисдикций и сроков хранения из конфигурации.
+func (c *Container) initCompliance() error {
+	policy, err := compliance.NewPolicy(
+		c.config.Compliance.AllowedJurisdictions,
+		c.config.Compliance.DefaultJurisdiction,
+		c.config.Compliance.RetentionMonths,
+		c.config.Compliance.DefaultRetentionMonths,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.compliancePolicy = policy
+	return nil
+}
+
+// initScreening загружает правила скрининга из конфигурации и таблицы
+// screening_rules. Невалидное правило - ошибка запуска.
+func (c *Container) initScreening(ctx context.Context) error {
+	if !c.config.Screening.Enabled {
+		return nil
+	}
+
+	definitions := append([]ports.ScreeningRule(nil), c.config.Screening.Rules...)
+	if c.config.Screening.LoadFromDatabase && c.memStore != nil {
+		c.logger.Warn("screening.load_from_database ignored: standalone storage has no stored rules")
+	} else if c.config.Screening.LoadFromDatabase {
+		stored, err := postgres.NewScreeningRuleRepository(c.pool).ListEnabled(ctx)
+		if err != nil {
+			return err
+		}
+		definitions = append(definitions, stored...)
+	}
+
+	rules, err := screening.NewRuleSet(definitions)
+	if err != nil {
+		return err
+	}
+
+	c.screeningRules = rules
+	c.transactionScreener = screening.NewService(rules, c.userRepo)
+	c.logger.Info("Screening rules loaded", slog.Int("rules", rules.Len()))
+	return nil
+}
+
+// initPayeeGuard создаёт проверку новых получателей. Получатели
+// запоминаются и при выключенной проверке (см. payees.Policy.Enabled).
+func (c *Container) initPayeeGuard() error {
+	c.exchangeProvider = exchange.NewProvider(
+		c.config.Exchange.APIKey,
+		c.config.Exchange.APIURL,
+		c.config.Exchange.CacheTTL,
+	)
+
+	cfg := c.config.NewPayee
+	guard, err := payees.NewGuard(payees.Policy{
+		Enabled:             cfg.Enabled,
+		RequireConfirmation: cfg.RequireConfirmation,
+		MaxAmount:           cfg.MaxAmount,
+		LimitCurrency:       cfg.LimitCurrency,
+		Window:              cfg.Window,
+	}, c.payeeRepository(), c.exchangeProvider)
+	if err != nil {
+		return err
+	}
+
+	c.payeeGuard = guard
+	return nil
+}
+
+// payeeRepository - известные получатели переводов.
+func (c *Container) payeeRepository() ports.PayeeRepository {
+	if c.memStore != nil {
+		return memory.NewPayeeRepository(c.memStore)
+	}
+	return postgres.NewPayeeRepository(c.pool)
+}
+
+// initTermsGate создаёт проверку принятия актуальной версии ToS.
+func (c *Container) initTermsGate() error {
+	c.termsPolicy = terms.Policy{
+		RequiredVersion:      c.config.Terms.RequiredVersion,
+		GrandfatheredVersion: c.config.Terms.GrandfatheredVersion,
+	}
+
+	gate, err := terms.NewGate(c.termsPolicy, c.userRepo)
+	if err != nil {
+		return err
+	}
+
+	c.termsGate = gate
+	return nil
+}
+
+// initKYCGate создаёт проверку KYC владельца при выводе средств и
+// подписывает кэш статусов на KYC события шины.
+func (c *Container) initKYCGate() error {
+	mode, err := kyc.ParseMode(c.config.KYC.Enforcement)
+	if err != nil {
+		return err
+	}
+
+	gate, err := kyc.NewGate(kyc.Policy{Mode: mode, CacheTTL: c.config.KYC.StatusCacheTTL}, c.userRepo)
+	if err != nil {
+		return err
+	}
+
+	eventbus.Subscribe(c.eventBus, "kyc-status-approved", func(ctx context.Context, e *events.UserKYCApproved) error {
+		return gate.HandleEvent(ctx, e)
+	})
+	eventbus.Subscribe(c.eventBus, "kyc-status-rejected", func(ctx context.Context, e *events.UserKYCRejected) error {
+		return gate.HandleEvent(ctx, e)
+	})
+	eventbus.Subscribe(c.eventBus, "kyc-status-revoked", func(ctx context.Context, e *events.UserKYCRevoked) error {
+		return gate.HandleEvent(ctx, e)
+	})
+
+	c.kycGate = gate
+	c.logger.Info("KYC enforcement configured", slog.String("mode", string(mode)))
+	return nil
 }
 
 // initUseCases инициализирует use cases.
 func (c *Container) initUseCases() {
 	// User Use Cases
-	c.createUserUC = user.NewCreateUserUseCase(c.userRepo, c.eventPublisher, c.uow)
+	c.createUserUC = user.NewCreateUserUseCase(c.userRepo, c.eventPublisher, c.uow, c.compliancePolicy)
 	c.getUserUC = user.NewGetUserUseCase(c.userRepo)
+	c.getAdminUserUC = user.NewGetAdminUserUseCase(c.userRepo)
+	c.updateUserUC = user.NewUpdateUserUseCase(c.userRepo, c.uow, c.compliancePolicy)
+	c.reviewKYCUC = user.NewReviewKYCUseCase(c.userRepo, c.kycHistoryRepo, c.eventPublisher, c.uow)
+	c.revokeKYCUC = user.NewRevokeKYCUseCase(c.userRepo, c.kycHistoryRepo, c.eventPublisher, c.uow)
+	c.acceptTermsUC = user.NewAcceptTermsUseCase(c.userRepo, c.eventPublisher, c.uow, c.termsPolicy)
+	c.getKYCHistoryUC = user.NewGetKYCHistoryUseCase(c.userRepo, c.kycHistoryRepo)
+
+	// Per-wallet limiter
+	c.walletLimiter = walletlimit.NewLimiter(walletlimit.Config{
+		MaxInFlight: c.config.RateLimit.WalletMaxInFlight,
+		MaxWait:     c.config.RateLimit.WalletMaxWait,
+		IdleTTL:     c.config.RateLimit.WalletIdleTTL,
+	})
 
 	// Wallet Use Cases
-	c.createWalletUC = wallet.NewCreateWalletUseCase(c.userRepo, c.walletRepo, c.eventPublisher, c.uow)
-	c.creditWalletUC = wallet.NewCreditWalletUseCase(c.walletRepo, c.transactionRepo, c.eventPublisher, c.uow)
-	c.debitWalletUC = wallet.NewDebitWalletUseCase(c.walletRepo, c.transactionRepo, c.eventPublisher, c.uow)
-	c.getWalletUC = wallet.NewGetWalletUseCase(c.walletRepo)
-	c.listWalletsUC = wallet.NewListWalletsUseCase(c.walletRepo)
+	c.createWalletUC = wallet.NewCreateWalletUseCase(c.userRepo, c.walletRepo, c.eventPublisher, c.uow, c.currencyPolicy)
+	c.creditWalletUC = wallet.NewCreditWalletUseCase(c.walletRepo, c.transactionRepo, c.eventPublisher, c.uow, c.walletLimiter, c.transactionScreener, c.buildInfo, c.sensitiveDataPolicy, c.operationGate, c.walletSettingsRepo)
+	c.debitWalletUC = wallet.NewDebitWalletUseCase(c.walletRepo, c.transactionRepo, c.eventPublisher, c.uow, c.walletLimiter, c.transactionScreener, c.buildInfo, c.sensitiveDataPolicy, c.termsGate, c.kycGate, c.operationGate, c.walletSettingsRepo)
+	c.closeWalletUC = wallet.NewCloseWalletWithSweepUseCase(c.walletRepo, c.transactionRepo, c.eventPublisher, c.uow, c.fraudDetector, c.walletLimiter, c.transactionScreener, c.payeeGuard, c.buildInfo, c.termsGate, c.kycGate, c.operationGate)
+	c.ensureWalletUC = wallet.NewEnsureWalletUseCase(c.userRepo, c.walletRepo, c.eventPublisher, c.uow, c.currencyPolicy)
+	// Onboarding партнёрами: переиспользует создание пользователя, кошелька и зачисление
+	c.onboardUserUC = onboarding.NewOnboardUserUseCase(
+		c.userRepo, c.walletRepo, c.transactionRepo, c.kycHistoryRepo, c.onboardingRepo,
+		c.eventPublisher, c.uow, c.createUserUC, c.createWalletUC, c.creditWalletUC,
+		onboarding.Policy{TrustedPartners: c.config.Onboarding.TrustedPartnerIDs()},
+	)
+	// Пересчёт балансов в валюту отображения кошелька (только для показа)
+	display := ports.DisplayCurrency{
+		Settings: c.walletSettingsRepo,
+		Rates:    displayfx.NewRates(c.exchangeProvider, displayfx.Config{CacheTTL: c.config.Exchange.DisplayCacheTTL}),
+	}
+	c.getWalletUC = wallet.NewGetWalletUseCase(c.walletReader, c.transactionReader, c.pendingPolicy, c.metadataQuota, display)
+	c.getWalletBalanceUC = wallet.NewGetWalletBalanceUseCase(c.walletReader, display)
+	c.getActivityHeatmapUC = wallet.NewGetActivityHeatmapUseCase(c.transactionActivityRepo, c.walletReader)
+	c.listWalletsUC = wallet.NewListWalletsUseCase(c.walletReader, display)
+
+	// Wallet Notes (admin)
+	c.createWalletNoteUC = wallet.NewCreateWalletNoteUseCase(c.walletRepo, c.walletNoteRepo, c.eventPublisher, c.uow)
+	c.listWalletNotesUC = wallet.NewListWalletNotesUseCase(c.walletReader, c.walletNoteRepo)
+	c.setWalletNotePinnedUC = wallet.NewSetWalletNotePinnedUseCase(c.walletNoteRepo, c.uow)
+	c.deleteWalletNoteUC = wallet.NewDeleteWalletNoteUseCase(c.walletNoteRepo, c.uow)
+	c.updateWalletSettingsUC = wallet.NewUpdateWalletSettingsUseCase(c.walletRepo, c.walletSettingsRepo, c.uow)
+
+	// Воспроизведение истории кошелька (admin)
+	c.replayWalletUC = replay.NewReplayWalletUseCase(c.walletReader, c.transactionReader)
 
 	// Transaction Use Cases
 	c.createTransactionUC = transaction.NewCreateTransactionUseCase(
@@ -374,12 +1427,22 @@ func (c *Container) initUseCases() {
 		c.eventPublisher,
 		c.uow,
 		c.distributedLock, // nil if Redis unavailable
+		c.feeCalculator,   // nil if no fee engine configured
+		c.transactionScreener,
+		c.buildInfo,
+		c.sensitiveDataPolicy,
+		c.pendingPolicy,
+		c.termsGate,
+		c.kycGate,
+		c.operationGate,
+		c.metadataQuota,
 	)
 	c.processTransactionUC = transaction.NewProcessTransactionUseCase(
 		c.walletRepo,
 		c.transactionRepo,
 		c.eventPublisher,
 		c.uow,
+		logger.WithComponent(c.logger, "transaction"),
 	)
 	c.cancelTransactionUC = transaction.NewCancelTransactionUseCase(
 		c.walletRepo,
@@ -393,26 +1456,88 @@ func (c *Container) initUseCases() {
 		c.eventPublisher,
 		c.uow,
 		c.fraudDetector,
+		c.feeCalculator,
+		c.walletLimiter,
+		c.transactionScreener,
+		c.payeeGuard,
+		c.buildInfo,
+		c.termsGate,
+		c.kycGate,
+		c.operationGate,
+		c.walletSettingsRepo,
+		logger.WithComponent(c.logger, "transfer"),
 	)
+	c.bulkTransferUC = transaction.NewBulkTransferUseCase(c.walletRepo, c.uow, c.transferBetweenWalletsUC, transaction.BulkTransferPolicy{
+		MaxItems:  c.config.Transactions.BulkMaxItems,
+		GroupSize: c.config.Transactions.BulkGroupSize,
+	})
+
+	// Sandbox Use Cases (endpoint регистрируется только в sandbox режиме)
+	c.resetSandboxUC = sandbox.NewResetTenantUseCase(c.sandboxRepo, c.eventPublisher, c.uow)
+
+	// Read Session Use Cases (только postgres)
+	if c.readSessions != nil {
+		c.openReadSessionUC = readsession.NewOpenReadSessionUseCase(c.readSessions)
+	}
+
+	// Security Use Cases
+	c.listSecurityEventsUC = security.NewListSecurityEventsUseCase(c.securityEventRepo)
+
+	// Support Use Cases (admin)
+	c.listFailedRequestsUC = support.NewListFailedRequestsUseCase(c.failedRequestRepo)
+
+	// Screening Use Cases (admin)
+	c.listScreeningRulesUC = screeninguc.NewListScreeningRulesUseCase(c.screeningRules)
+	c.dryRunScreeningUC = screeninguc.NewDryRunScreeningUseCase(c.screeningRules)
+
+	// Jobs (nil планировщик - пустой список, запуск вручную невозможен)
+	var jobScheduler ports.JobScheduler
+	if c.jobScheduler != nil {
+		jobScheduler = c.jobScheduler
+	}
+	c.listJobsUC = jobs.NewListJobsUseCase(jobScheduler)
+	c.runJobUC = jobs.NewRunJobUseCase(jobScheduler)
+
+	// Background worker heartbeats (admin)
+	var workerMonitor ports.WorkerMonitor
+	if c.workerRegistry != nil {
+		workerMonitor = c.workerRegistry
+	}
+	c.listWorkersUC = workersuc.NewListWorkersUseCase(workerMonitor)
+
+	// Operation kill switches (admin)
+	c.setOperationSwitchUC = operationsuc.NewSetOperationSwitchUseCase(c.operationSwitchRepo, c.operationGate, c.eventPublisher, c.uow)
+
+	// Status page incidents (admin)
+	c.createIncidentUC = incidentsuc.NewCreateIncidentUseCase(c.incidentRepo)
+	c.resolveIncidentUC = incidentsuc.NewResolveIncidentUseCase(c.incidentRepo, c.uow)
+
+	// Webhook Use Cases
+	c.createWebhookUC = webhooksuc.NewCreateWebhookUseCase(c.webhookSubscriptionRepo)
+	c.listWebhooksUC = webhooksuc.NewListWebhooksUseCase(c.webhookSubscriptionRepo)
+	c.getWebhookUC = webhooksuc.NewGetWebhookUseCase(c.webhookSubscriptionRepo)
+	c.updateWebhookUC = webhooksuc.NewUpdateWebhookUseCase(c.webhookSubscriptionRepo, c.webhookDeliveryRepo, c.uow)
+	c.deleteWebhookUC = webhooksuc.NewDeleteWebhookUseCase(c.webhookSubscriptionRepo, c.webhookDeliveryRepo, c.uow)
+	c.listWebhookDeliveriesUC = webhooksuc.NewListWebhookDeliveriesUseCase(c.webhookSubscriptionRepo, c.webhookDeliveryRepo)
 
 	// Exchange Currency
-	exchangeProvider := exchange.NewProvider(
-		c.config.Exchange.APIKey,
-		c.config.Exchange.APIURL,
-		c.config.Exchange.CacheTTL,
-	)
 	c.exchangeCurrencyUC = transaction.NewExchangeCurrencyUseCase(
 		c.walletRepo,
 		c.transactionRepo,
-		exchangeProvider,
+		c.exchangeProvider,
+		c.fxRateSnapshotRepo,
 		c.eventPublisher,
 		c.uow,
 		c.config.Exchange.SpreadPercent,
 		c.fraudDetector,
+		c.buildInfo,
 	)
-	c.getByIdempotencyKeyUC = transaction.NewGetTransactionByIdempotencyKeyUseCase(c.transactionRepo)
-	c.getTransactionUC = transaction.NewGetTransactionUseCase(c.transactionRepo)
-	c.listTransactionsUC = transaction.NewListTransactionsUseCase(c.transactionRepo)
+	c.getByIdempotencyKeyUC = transaction.NewGetTransactionByIdempotencyKeyUseCase(c.transactionReader)
+	c.getTransactionUC = transaction.NewGetTransactionUseCase(c.transactionReader)
+	c.getReceiptUC = transaction.NewGetReceiptUseCase(c.transactionReceiptRepo, c.walletReader)
+	c.listTransactionsUC = transaction.NewListTransactionsUseCase(c.transactionReader)
+	c.exportTransactionsUC = transaction.NewExportTransactionsUseCase(c.transactionReader, c.config.Transactions.ExportMaxRows)
+	c.getFailureStatsUC = transaction.NewGetFailureStatsUseCase(c.transactionReader)
 	c.retryTransactionUC = transaction.NewRetryTransactionUseCase(c.walletRepo, c.transactionRepo, c.eventPublisher, c.uow)
 }
 
@@ -442,6 +1567,47 @@ func (c *Container) initHTTPServer() {
 		JWTIssuer:          c.config.Auth.JWTIssuer,
 		RedisClient:        c.redisClient,        // nil if Redis unavailable
 		TokenBlacklist:     c.tokenBlacklist,     // nil if Redis unavailable
+		DefaultJurisdiction: c.compliancePolicy.DefaultJurisdiction(),
+		SandboxEnabled:     c.config.App.IsSandbox(),
+		RouteManifestEnabled: c.config.App.IsRouteManifestEnabled(),
+		SecurityLog:        c.securityLog,
+		LegacyWalletListShape: c.config.App.LegacyWalletListShape,
+		ConfirmationStore:  c.confirmationStore, // nil if Redis unavailable
+		FailedRequestMaxBodyBytes: c.config.RequestCapture.MaxBodyBytes,
+		Operations:         c.operationGate,
+	}
+	if c.failedRequests != nil {
+		routerConfig.FailedRequests = c.failedRequests
+	}
+	if c.workerRegistry != nil {
+		routerConfig.Workers = c.workerRegistry
+	}
+	routerConfig.Currencies = c.currencyPolicy
+	routerConfig.WebhooksEnabled = c.config.Webhooks.Enabled
+	if c.statusPage != nil {
+		routerConfig.StatusPage = c.statusPage
+		routerConfig.StatusPageMaxAge = c.config.StatusPage.CacheMaxAge
+	}
+	if c.readSessions != nil {
+		routerConfig.ReadSessions = c.readSessions
+	}
+	if c.config.Compression.Enabled {
+		routerConfig.Compression = &middleware.CompressionConfig{
+			MinSize: c.config.Compression.MinSize,
+			Zstd:    c.config.Compression.Zstd,
+		}
+	}
+	if limit := c.config.ConcurrencyLimit; limit.Enabled {
+		classes := make(map[string]middleware.ConcurrencyClass, len(limit.Classes))
+		for name, class := range limit.Classes {
+			classes[name] = middleware.ConcurrencyClass{MaxPerClient: class.MaxPerClient, Weight: class.Weight}
+		}
+		routerConfig.ConcurrencyLimit = &middleware.ConcurrencyLimitConfig{
+			Classes:   classes,
+			GlobalMax: limit.GlobalMax,
+			MaxWait:   limit.MaxWait,
+			IdleTTL:   limit.IdleTTL,
+		}
 	}
 
 	// Build Router (CQRS buses dispatch commands/queries through middleware pipeline)
@@ -515,6 +1681,11 @@ func (c *Container) UnitOfWork() ports.UnitOfWork {
 	return c.uow
 }
 
+// EventBus возвращает in-process шину событий для внутренних подписчиков.
+func (c *Container) EventBus() *eventbus.Bus {
+	return c.eventBus
+}
+
 // ============================================
 // Use Case Getters
 // ============================================
@@ -539,6 +1710,16 @@ func (c *Container) DebitWalletUseCase() *wallet.DebitWalletUseCase {
 	return c.debitWalletUC
 }
 
+// CloseWalletWithSweepUseCase возвращает use case закрытия кошелька с переводом остатка.
+func (c *Container) CloseWalletWithSweepUseCase() *wallet.CloseWalletWithSweepUseCase {
+	return c.closeWalletUC
+}
+
+// EnsureWalletUseCase возвращает use case идемпотентного создания кошелька.
+func (c *Container) EnsureWalletUseCase() *wallet.EnsureWalletUseCase {
+	return c.ensureWalletUC
+}
+
 // GetWalletUseCase возвращает use case получения кошелька.
 func (c *Container) GetWalletUseCase() *wallet.GetWalletUseCase {
 	return c.getWalletUC
@@ -571,6 +1752,63 @@ func (c *Container) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	// 1a. Security log (drain до остановки шины: алерты публикуются через outbox)
+	if c.securityLog != nil {
+		if err := c.securityLog.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("security log shutdown: %w", err))
+		}
+	}
+
+	// 1b. Event bus (drain очередей, пока БД ещё доступна подписчикам)
+	if c.eventBus != nil {
+		if err := c.eventBus.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("event bus shutdown: %w", err))
+		}
+	}
+
+	// 1c. Balance summary (flush окна после drain шины)
+	if c.balanceSummary != nil {
+		if err := c.balanceSummary.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("balance summary shutdown: %w", err))
+		}
+	}
+
+	// 1d. Job scheduler (прерываем прогоны и снимаем блокировки до закрытия пула)
+	if c.jobScheduler != nil {
+		if err := c.jobScheduler.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("job scheduler shutdown: %w", err))
+		}
+	}
+
+	// 1e. Wallet balance comparison (прерываем прогон до закрытия пула)
+	if c.walletCompareJob != nil {
+		if err := c.walletCompareJob.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("wallet compare job shutdown: %w", err))
+		}
+	}
+
+	// 1f. Failed request samples (drain принятых образцов до закрытия пула)
+	if c.failedRequests != nil {
+		if err := c.failedRequests.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed request recorder shutdown: %w", err))
+		}
+	}
+
+	// 1g. Status page (до закрытия пула)
+	if c.statusPage != nil {
+		if err := c.statusPage.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("status page shutdown: %w", err))
+		}
+	}
+
+	// 1h. Worker heartbeats (последнее состояние остановленных компонентов
+	// пишется до закрытия пула)
+	if c.workerRegistry != nil {
+		if err := c.workerRegistry.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("worker registry shutdown: %w", err))
+		}
+	}
+
 	// 2. Tracer Provider
 	if c.tracerProvider != nil {
 		if err := c.tracerProvider.Shutdown(ctx); err != nil {
@@ -592,6 +1830,12 @@ func (c *Container) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	// 2d. Read sessions (откатываем транзакции сессий: иначе закрытие пула
+	// ждёт их TTL)
+	if c.readSessions != nil {
+		c.readSessions.Close()
+	}
+
 	// 3. Database (даём время на завершение транзакций)
 	if c.pool != nil {
 		// Graceful close с таймаутом
@@ -608,6 +1852,9 @@ func (c *Container) Shutdown(ctx context.Context) error {
 			c.logger.Warn("Database close timeout")
 		}
 	}
+	if c.replicaPool != nil {
+		c.replicaPool.Close()
+	}
 
 	if len(errs) > 0 {
 		return fmt.Errorf("shutdown errors: %v", errs)
@@ -642,6 +1889,8 @@ type ContainerBuilder struct {
 	logger         *slog.Logger
 	pool           *pgxpool.Pool
 	eventPublisher ports.EventPublisher
+	feeCalculator  ports.FeeCalculator
+	faultInjector  faultinject.FaultInjector
 }
 
 // NewBuilder создаёт новый builder.
@@ -669,6 +1918,21 @@ func (b *ContainerBuilder) WithEventPublisher(ep ports.EventPublisher) *Containe
 	return b
 }
 
+// WithFeeCalculator подключает расчёт комиссий для переводов и выводов.
+// Без него комиссии нулевые (gross == net).
+func (b *ContainerBuilder) WithFeeCalculator(calc ports.FeeCalculator) *ContainerBuilder {
+	b.feeCalculator = calc
+	return b
+}
+
+// WithFaultInjector подключает fault injection к репозиториям, UnitOfWork
+// и event publisher. Только для тестов и development - в production
+// Build вернёт ошибку.
+func (b *ContainerBuilder) WithFaultInjector(faults faultinject.FaultInjector) *ContainerBuilder {
+	b.faultInjector = faults
+	return b
+}
+
 // Build создаёт контейнер.
 func (b *ContainerBuilder) Build(ctx context.Context) (*Container, error) {
 	c := New(b.cfg)
@@ -688,14 +1952,48 @@ func (b *ContainerBuilder) Build(ctx context.Context) (*Container, error) {
 		}
 	}
 
-	c.initRepositories()
+	c.initWorkers()
+	if err := c.initRepositories(); err != nil {
+		return nil, err
+	}
+
+	if err := c.initCompliance(); err != nil {
+		return nil, err
+	}
+
+	if err := c.initScreening(ctx); err != nil {
+		return nil, err
+	}
 
 	if b.eventPublisher != nil {
 		c.eventPublisher = b.eventPublisher
 	}
 
+	if err := c.initEventBus(); err != nil {
+		return nil, err
+	}
+	c.initBalanceSummary()
+	c.initWebhooks()
+	c.initSecurityLog()
+	c.initFailedRequestCapture()
+	c.initWalletMigration()
+	c.feeCalculator = b.feeCalculator
+
+	if b.faultInjector != nil {
+		if c.config.App.IsProduction() {
+			return nil, fmt.Errorf("fault injection is not allowed in production")
+		}
+		c.walletRepo = faultinject.WrapWalletRepository(c.walletRepo, b.faultInjector)
+		c.transactionRepo = faultinject.WrapTransactionRepository(c.transactionRepo, b.faultInjector)
+		c.walletReader = c.walletRepo
+		c.transactionReader = c.transactionRepo
+		c.eventPublisher = faultinject.WrapEventPublisher(c.eventPublisher, b.faultInjector)
+		c.uow = faultinject.WrapUnitOfWork(c.uow, b.faultInjector)
+	}
+
 	c.initUseCases()
 	c.initHTTPServer()
+	c.eventBus.ReplaySpool()
 
 	return c, nil
 }
@@ -721,7 +2019,9 @@ func (c *Container) Health(ctx context.Context) *HealthStatus {
 	}
 
 	// Database check
-	if err := c.pool.Ping(ctx); err != nil {
+	if c.pool == nil {
+		status.Checks["database"] = "ok: " + c.config.Storage.Backend
+	} else if err := c.pool.Ping(ctx); err != nil {
 		status.Status = "unhealthy"
 		status.Checks["database"] = "error: " + err.Error()
 	} else {
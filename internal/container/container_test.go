@@ -2,12 +2,16 @@ package container
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/Haleralex/wallethub/internal/config"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -280,6 +284,65 @@ func TestContainer_Shutdown_NilComponents(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestContainer_Shutdown_DrainsEventBus(t *testing.T) {
+	cfg := config.Development()
+	c := New(cfg)
+	c.logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+	require.NoError(t, c.initEventBus())
+
+	var delivered atomic.Int32
+	c.EventBus().SubscribeAll("test", func(ctx context.Context, event events.DomainEvent) error {
+		delivered.Add(1)
+		return nil
+	})
+	c.EventBus().Publish(events.NewWalletSuspended(uuid.New(), "test"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, c.Shutdown(ctx))
+	assert.Equal(t, int32(1), delivered.Load())
+}
+
+func TestContainer_InitEventBus_DeliveryModes(t *testing.T) {
+	for _, tt := range []struct {
+		delivery string
+		want     int32
+	}{
+		{delivery: "sync", want: 1},
+		{delivery: "none", want: 0},
+	} {
+		t.Run(tt.delivery, func(t *testing.T) {
+			cfg := config.Development()
+			cfg.Messaging.Delivery = tt.delivery
+			c := New(cfg)
+			c.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+			require.NoError(t, c.initEventBus())
+
+			var delivered atomic.Int32
+			c.EventBus().SubscribeAll("test", func(ctx context.Context, event events.DomainEvent) error {
+				delivered.Add(1)
+				return nil
+			})
+
+			// Outbox не участвует: publisher не требует БД
+			require.NoError(t, c.eventPublisher.Publish(context.Background(), events.NewWalletSuspended(uuid.New(), "test")))
+			assert.Equal(t, tt.want, delivered.Load(), "delivered before Publish returns")
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			require.NoError(t, c.Shutdown(ctx))
+			assert.Equal(t, tt.want, delivered.Load())
+		})
+	}
+
+	cfg := config.Development()
+	cfg.Messaging.Delivery = "kafka"
+	c := New(cfg)
+	c.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	assert.Error(t, c.initEventBus())
+}
+
 // Initialize Tests (with expected failures for no DB)
 
 func TestContainer_Initialize_NoDB(t *testing.T) {
@@ -0,0 +1,112 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/middleware"
+	"github.com/Haleralex/wallethub/internal/config"
+)
+
+// standaloneAPI - контейнер standalone профиля за httptest.Server.
+type standaloneAPI struct {
+	t      *testing.T
+	server *httptest.Server
+	token  string
+}
+
+func (a *standaloneAPI) do(method, path string, body any) (int, map[string]any) {
+	a.t.Helper()
+
+	var payload bytes.Buffer
+	if body != nil {
+		require.NoError(a.t, json.NewEncoder(&payload).Encode(body))
+	}
+	req, err := http.NewRequest(method, a.server.URL+path, &payload)
+	require.NoError(a.t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+
+	resp, err := a.server.Client().Do(req)
+	require.NoError(a.t, err)
+	defer resp.Body.Close()
+
+	var decoded map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&decoded)
+	return resp.StatusCode, decoded
+}
+
+func data(t *testing.T, response map[string]any) map[string]any {
+	t.Helper()
+	result, ok := response["data"].(map[string]any)
+	require.True(t, ok, "response without data: %v", response)
+	return result
+}
+
+// TestStandalone_Smoke поднимает полный контейнер без PostgreSQL, Redis и
+// NATS и проводит деньги через HTTP API (make test-standalone).
+func TestStandalone_Smoke(t *testing.T) {
+	cfg := config.Standalone()
+	cfg.Log.Level = "error"
+	cfg.Telemetry.Enabled = false
+
+	c := New(cfg)
+	require.NoError(t, c.Initialize(context.Background()))
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = c.Shutdown(ctx)
+	})
+
+	assert.Nil(t, c.Pool())
+	assert.NotNil(t, c.jobScheduler, "background jobs run against the memory store")
+	assert.Equal(t, "ok: memory", c.Health(context.Background()).Checks["database"])
+
+	server := httptest.NewServer(c.HTTPServer().Handler())
+	t.Cleanup(server.Close)
+	api := &standaloneAPI{t: t, server: server}
+
+	status, body := api.do(http.MethodPost, "/api/v1/users", map[string]any{
+		"email":     "standalone@example.com",
+		"full_name": "Standalone User",
+	})
+	require.Equal(t, http.StatusCreated, status, "create user: %v", body)
+	userID := data(t, body)["user"].(map[string]any)["id"].(string)
+
+	api.token, _ = middleware.GenerateJWT(cfg.Auth.JWTSecret, cfg.Auth.JWTIssuer, userID,
+		"standalone@example.com", "user", time.Hour)
+
+	status, body = api.do(http.MethodPost, "/api/v1/wallets", map[string]any{"currency_code": "USD"})
+	require.Equal(t, http.StatusCreated, status, "create wallet: %v", body)
+	walletID := data(t, body)["id"].(string)
+
+	status, body = api.do(http.MethodPost, "/api/v1/wallets/"+walletID+"/credit", map[string]any{
+		"amount":          "25.00",
+		"idempotency_key": uuid.NewString(),
+		"description":     "Standalone smoke",
+	})
+	require.Equal(t, http.StatusCreated, status, "credit: %v", body)
+
+	status, body = api.do(http.MethodGet, "/api/v1/wallets/"+walletID+"/balance", nil)
+	require.Equal(t, http.StatusOK, status, "balance: %v", body)
+	assert.Equal(t, "25.00 USD", data(t, body)["available_balance"])
+}
+
+func TestStandalone_RefusedInProduction(t *testing.T) {
+	cfg := config.Standalone()
+	cfg.App.Environment = "production"
+	cfg.Auth.JWTSecret = "production-secret"
+
+	assert.ErrorContains(t, cfg.Validate(), "storage backend memory is not allowed in production")
+}
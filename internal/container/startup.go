@@ -0,0 +1,251 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/operations"
+	"github.com/Haleralex/wallethub/internal/config"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/postgres"
+)
+
+// startupStage - этап Container.Initialize со своим бюджетом времени
+// (app.startup). Этап получает контекст с этим бюджетом: зависимость,
+// которая не ответила вовремя, называется в ошибке старта.
+type startupStage struct {
+	name   string
+	budget time.Duration
+	// optional - ошибка этапа не прерывает старт, приложение работает без
+	// компонента (например, без Redis).
+	optional bool
+	run      func(ctx context.Context) error
+}
+
+// startupStages - этапы старта в порядке выполнения.
+func (c *Container) startupStages() []startupStage {
+	budgets := c.config.App.Startup.WithDefaults()
+
+	return []startupStage{
+		{name: "telemetry", budget: budgets.Components, optional: true, run: c.initTelemetry},
+		{name: "database", budget: budgets.Database, run: c.connectDatabase},
+		{name: "schema_check", budget: budgets.SchemaCheck, run: c.checkSchema},
+		{name: "cache", budget: budgets.Cache, optional: true, run: c.initRedis},
+		{name: "repositories", budget: budgets.Components, run: c.initStorage},
+		{name: "messaging", budget: budgets.Messaging, run: c.initMessaging},
+		{name: "jobs", budget: budgets.Components, run: c.initBackground},
+		{name: "policies", budget: budgets.Components, run: c.initPolicies},
+		{name: "api", budget: budgets.Components, run: c.initAPI},
+	}
+}
+
+// startupDeadline - общий дедлайн старта: app.startup.timeout, а без него
+// сумма бюджетов этапов.
+func startupDeadline(cfg config.StartupConfig, stages []startupStage) time.Duration {
+	if cfg.Timeout > 0 {
+		return cfg.Timeout
+	}
+
+	var total time.Duration
+	for _, stage := range stages {
+		total += stage.budget
+	}
+	return total
+}
+
+// runStartupStages выполняет этапы по очереди. Ошибка обязательного этапа
+// прерывает старт: следующие этапы не запускаются.
+func runStartupStages(ctx context.Context, logger *slog.Logger, stages []startupStage) error {
+	for _, stage := range stages {
+		if err := runStartupStage(ctx, logger, stage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runStartupStage(ctx context.Context, logger *slog.Logger, stage startupStage) error {
+	logger.Info("Startup stage started",
+		slog.String("stage", stage.name),
+		slog.Duration("budget", stage.budget),
+	)
+
+	stageCtx, cancel := context.WithTimeout(ctx, stage.budget)
+	defer cancel()
+
+	start := time.Now()
+	err := stage.run(stageCtx)
+	elapsed := time.Since(start)
+
+	switch {
+	case err == nil && elapsed > stage.budget:
+		// Этап не следит за контекстом и закончил позже бюджета
+		logger.Warn("Startup stage finished over budget",
+			slog.String("stage", stage.name),
+			slog.Duration("duration", elapsed),
+			slog.Duration("budget", stage.budget),
+			slog.String("outcome", "ok"),
+		)
+	case err == nil:
+		logger.Info("Startup stage finished",
+			slog.String("stage", stage.name),
+			slog.Duration("duration", elapsed),
+			slog.String("outcome", "ok"),
+		)
+	case stage.optional:
+		logger.Warn("Startup stage failed, continuing without it",
+			slog.String("stage", stage.name),
+			slog.Duration("duration", elapsed),
+			slog.String("outcome", "degraded"),
+			slog.String("error", err.Error()),
+		)
+	default:
+		logger.Error("Startup stage failed",
+			slog.String("stage", stage.name),
+			slog.Duration("duration", elapsed),
+			slog.String("outcome", "failed"),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("startup stage %q failed after %s: %w", stage.name, elapsed.Round(time.Millisecond), err)
+	}
+	return nil
+}
+
+// enabledComponents перечисляет включённые необязательные компоненты (для
+// лога "Startup complete").
+func (c *Container) enabledComponents() []string {
+	var enabled []string
+	add := func(name string, on bool) {
+		if on {
+			enabled = append(enabled, name)
+		}
+	}
+
+	add("tracing", c.tracerProvider != nil)
+	add("metrics_push", c.meterProvider != nil)
+	add("redis", c.redisClient != nil)
+	add("read_replica", c.replicaPool != nil)
+	add("event_spool", c.eventSpool != nil)
+	add("balance_summary", c.balanceSummary != nil)
+	add("wallet_migration", c.walletCompareJob != nil)
+	add("jobs", c.jobScheduler != nil)
+	add("status_page", c.statusPage != nil)
+	add("webhooks", c.config.Webhooks.Enabled)
+	add("sandbox", c.config.App.IsSandbox())
+	return enabled
+}
+
+// ============================================
+// Startup stages
+// ============================================
+
+// initTelemetry инициализирует tracing и push метрик (на Fly.io; локально
+// /metrics скрейпит Alloy). Ошибка одного не мешает другому.
+func (c *Container) initTelemetry(ctx context.Context) error {
+	return errors.Join(c.initTracing(ctx), c.initMetrics(ctx))
+}
+
+// connectDatabase подключает primary и реплику (или in-memory хранилище).
+func (c *Container) connectDatabase(ctx context.Context) error {
+	if err := c.initDatabase(ctx); err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	return nil
+}
+
+// checkSchema проверяет состояние схемы до первых запросов к базе.
+// Незавершённая (dirty) миграция останавливает старт; неизвестная версия
+// (миграции не применялись через cmd/migrate) - только предупреждение.
+func (c *Container) checkSchema(ctx context.Context) error {
+	if c.pool == nil {
+		return nil
+	}
+
+	version, dirty, err := postgres.SchemaVersion(ctx, c.pool)
+	if errors.Is(err, postgres.ErrNoSchemaVersion) {
+		c.logger.Warn("Database schema version unknown", slog.String("error", err.Error()))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("schema migration %d is dirty: fix it and run cmd/migrate force", version)
+	}
+
+	c.logger.Info("Database schema checked", slog.Int64("version", version))
+	return nil
+}
+
+// initStorage создаёт репозитории (heartbeat воркеров первым: идентификатор
+// экземпляра нужен репозиториям).
+func (c *Container) initStorage(context.Context) error {
+	c.initWorkers()
+
+	if err := c.initRepositories(); err != nil {
+		return fmt.Errorf("failed to initialize repositories: %w", err)
+	}
+	return nil
+}
+
+// initMessaging поднимает in-process шину событий и её подписчиков.
+func (c *Container) initMessaging(context.Context) error {
+	if err := c.initEventBus(); err != nil {
+		return fmt.Errorf("failed to initialize event bus: %w", err)
+	}
+	c.initBalanceSummary()
+	c.initWebhooks()
+	c.initSecurityLog()
+	c.initFailedRequestCapture()
+	return nil
+}
+
+// initBackground запускает сверку балансов миграции и планировщик задач.
+func (c *Container) initBackground(ctx context.Context) error {
+	c.initWalletMigration()
+
+	if err := c.initJobs(ctx); err != nil {
+		return fmt.Errorf("failed to initialize job scheduler: %w", err)
+	}
+	return nil
+}
+
+// initPolicies собирает антифрод, compliance, screening, политику новых
+// получателей, условия использования, kill switches и status page.
+func (c *Container) initPolicies(ctx context.Context) error {
+	c.initFraudDetector()
+
+	if err := c.initCompliance(); err != nil {
+		return fmt.Errorf("failed to initialize compliance policy: %w", err)
+	}
+	if err := c.initScreening(ctx); err != nil {
+		return fmt.Errorf("failed to initialize screening rules: %w", err)
+	}
+	if err := c.initPayeeGuard(); err != nil {
+		return fmt.Errorf("failed to initialize new payee policy: %w", err)
+	}
+	if err := c.initTermsGate(); err != nil {
+		return fmt.Errorf("failed to initialize terms policy: %w", err)
+	}
+	if err := c.initKYCGate(); err != nil {
+		return fmt.Errorf("failed to initialize KYC enforcement: %w", err)
+	}
+
+	c.operationGate = operations.NewGate(c.operationSwitchRepo, c.config.Operations.RefreshInterval)
+	c.initStatusPage()
+	return nil
+}
+
+// initAPI собирает use cases, CQRS шины и HTTP сервер.
+func (c *Container) initAPI(context.Context) error {
+	c.initUseCases()
+	c.initCQRS()
+	c.initHTTPServer()
+
+	// События, не обработанные до падения прошлого запуска: после того как
+	// подписались все потребители
+	c.eventBus.ReplaySpool()
+	return nil
+}
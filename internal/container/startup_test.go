@@ -0,0 +1,121 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/config"
+)
+
+// slowDependency - зависимость, которая не отвечает, пока не истечёт контекст.
+func slowDependency(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestRunStartupStages_BlamesSlowStage(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	var attempted []string
+	stage := func(name string, budget time.Duration, run func(context.Context) error) startupStage {
+		return startupStage{name: name, budget: budget, run: func(ctx context.Context) error {
+			attempted = append(attempted, name)
+			return run(ctx)
+		}}
+	}
+	ok := func(context.Context) error { return nil }
+
+	err := runStartupStages(context.Background(), logger, []startupStage{
+		stage("database", time.Second, ok),
+		stage("messaging", 20*time.Millisecond, slowDependency),
+		stage("policies", time.Second, ok),
+		stage("api", time.Second, ok),
+	})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), `startup stage "messaging" failed after`)
+	assert.Equal(t, []string{"database", "messaging"}, attempted, "stages after the failure are not attempted")
+
+	assert.Contains(t, logs.String(), `"msg":"Startup stage failed","stage":"messaging"`)
+	assert.Contains(t, logs.String(), `"outcome":"failed"`)
+	assert.NotContains(t, logs.String(), `"stage":"policies"`)
+}
+
+func TestRunStartupStages_OptionalStageDegrades(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	reached := false
+	err := runStartupStages(context.Background(), logger, []startupStage{
+		{name: "cache", budget: 20 * time.Millisecond, optional: true, run: slowDependency},
+		{name: "api", budget: time.Second, run: func(context.Context) error {
+			reached = true
+			return nil
+		}},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, reached)
+	assert.Contains(t, logs.String(), `"outcome":"degraded"`)
+}
+
+func TestRunStartupStages_OverallDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := runStartupStages(ctx, slog.New(slog.DiscardHandler), []startupStage{
+		{name: "database", budget: time.Minute, run: slowDependency},
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), `startup stage "database"`)
+}
+
+func TestRunStartupStages_WrapsStageError(t *testing.T) {
+	errBroken := errors.New("schema migration 41 is dirty")
+
+	err := runStartupStages(context.Background(), slog.New(slog.DiscardHandler), []startupStage{
+		{name: "schema_check", budget: time.Second, run: func(context.Context) error { return errBroken }},
+	})
+
+	assert.ErrorIs(t, err, errBroken)
+	assert.Contains(t, err.Error(), `startup stage "schema_check" failed after`)
+}
+
+func TestStartupDeadline(t *testing.T) {
+	c := New(config.Development())
+	stages := c.startupStages()
+
+	// По умолчанию - сумма бюджетов этапов
+	var sum time.Duration
+	for _, stage := range stages {
+		sum += stage.budget
+	}
+	assert.Equal(t, sum, startupDeadline(c.config.App.Startup, stages))
+	assert.Equal(t, config.DefaultStartupDatabaseTimeout, stages[1].budget)
+
+	// Явный app.startup.timeout важнее суммы
+	assert.Equal(t, 30*time.Second, startupDeadline(config.StartupConfig{Timeout: 30 * time.Second}, stages))
+}
+
+func TestStartupStages_Order(t *testing.T) {
+	c := New(config.Development())
+
+	var names []string
+	for _, stage := range c.startupStages() {
+		names = append(names, stage.name)
+	}
+	assert.Equal(t, []string{
+		"telemetry", "database", "schema_check", "cache",
+		"repositories", "messaging", "jobs", "policies", "api",
+	}, names)
+}
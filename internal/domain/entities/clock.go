@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// touch returns the next UpdatedAt of an entity: the clock time, or
+// previous + 1ns when the clock has not moved past previous (same tick,
+// frozen test clock or a clock step back). UpdatedAt of one entity instance
+// therefore strictly increases with every change.
+//
+// Strictly increasing UpdatedAt is for display and caching only; the order
+// of changes is the balance version (wallets) or the status history.
+func touch(previous time.Time) time.Time {
+	now := clock.Now()
+	if !now.After(previous) {
+		return previous.Add(time.Nanosecond)
+	}
+	return now
+}
@@ -0,0 +1,28 @@
+// Package entities - forward-compatible handling of stored enum values.
+package entities
+
+// maxEnumLength bounds a well-formed enum value. Enum columns are
+// VARCHAR(20); the headroom leaves room for a later migration to widen them.
+const maxEnumLength = 32
+
+// IsWellFormedEnum reports whether raw has the shape of an enum value:
+// upper-case ASCII letters, digits and underscores, starting with a letter.
+//
+// During a rolling deploy older instances read rows written by newer ones,
+// so read paths accept well-formed values this build does not know (see
+// TransactionType.IsKnown) and carry them through as opaque strings. Only
+// malformed values - a sign of corrupted data - are rejected.
+func IsWellFormedEnum(raw string) bool {
+	if raw == "" || len(raw) > maxEnumLength {
+		return false
+	}
+	for i, c := range raw {
+		switch {
+		case c >= 'A' && c <= 'Z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '_'):
+		default:
+			return false
+		}
+	}
+	return true
+}
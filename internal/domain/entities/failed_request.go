@@ -0,0 +1,126 @@
+// Package entities - FailedRequest is an immutable sample of a failed money operation request.
+package entities
+
+import (
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+	"github.com/google/uuid"
+)
+
+// FailedRequest keeps what the client sent and what the API answered for a
+// money operation that failed, so support can see the exact payload behind a
+// complaint. Bodies are stored already redacted and may be cut at the size cap
+// (Truncated). ActorID is uuid.Nil when the request was not authenticated.
+// Samples are append-only and removed only by retention.
+type FailedRequest struct {
+	id           uuid.UUID
+	method       string
+	route        string
+	statusCode   int
+	requestID    string
+	actorID      uuid.UUID
+	requestBody  string
+	responseBody string
+	truncated    bool
+	occurredAt   time.Time
+}
+
+// FailedRequestSample describes a failed request for NewFailedRequest.
+type FailedRequestSample struct {
+	Method       string    // HTTP method
+	Route        string    // Route template, e.g. /api/v1/wallets/{id}/debit
+	StatusCode   int       // Response status
+	RequestID    string    // X-Request-ID of the request
+	ActorID      uuid.UUID // Authenticated user, uuid.Nil if none
+	RequestBody  string    // Redacted request body
+	ResponseBody string    // Redacted response body
+	Truncated    bool      // A body was cut at the size cap
+	OccurredAt   time.Time // When the request was handled
+}
+
+// NewFailedRequest creates a sample from an already redacted request.
+func NewFailedRequest(sample FailedRequestSample) *FailedRequest {
+	occurredAt := sample.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = clock.Now()
+	}
+	return ReconstructFailedRequest(uuid.New(), sample.Method, sample.Route, sample.StatusCode, sample.RequestID,
+		sample.ActorID, sample.RequestBody, sample.ResponseBody, sample.Truncated, occurredAt)
+}
+
+// ReconstructFailedRequest reconstructs a FailedRequest from stored data.
+// No validation - assumes data is already valid. Timestamps are normalized to UTC.
+func ReconstructFailedRequest(
+	id uuid.UUID,
+	method, route string,
+	statusCode int,
+	requestID string,
+	actorID uuid.UUID,
+	requestBody, responseBody string,
+	truncated bool,
+	occurredAt time.Time,
+) *FailedRequest {
+	return &FailedRequest{
+		id:           id,
+		method:       method,
+		route:        route,
+		statusCode:   statusCode,
+		requestID:    requestID,
+		actorID:      actorID,
+		requestBody:  requestBody,
+		responseBody: responseBody,
+		truncated:    truncated,
+		occurredAt:   occurredAt.UTC(),
+	}
+}
+
+// ID returns the sample identifier.
+func (r *FailedRequest) ID() uuid.UUID {
+	return r.id
+}
+
+// Method returns the HTTP method of the request.
+func (r *FailedRequest) Method() string {
+	return r.method
+}
+
+// Route returns the route template the request matched.
+func (r *FailedRequest) Route() string {
+	return r.route
+}
+
+// StatusCode returns the response status.
+func (r *FailedRequest) StatusCode() int {
+	return r.statusCode
+}
+
+// RequestID returns the X-Request-ID of the request.
+func (r *FailedRequest) RequestID() string {
+	return r.requestID
+}
+
+// ActorID returns the authenticated user, uuid.Nil for anonymous requests.
+func (r *FailedRequest) ActorID() uuid.UUID {
+	return r.actorID
+}
+
+// RequestBody returns the redacted request body.
+func (r *FailedRequest) RequestBody() string {
+	return r.requestBody
+}
+
+// ResponseBody returns the redacted response body.
+func (r *FailedRequest) ResponseBody() string {
+	return r.responseBody
+}
+
+// Truncated reports whether a body was cut at the size cap.
+func (r *FailedRequest) Truncated() bool {
+	return r.truncated
+}
+
+// OccurredAt returns when the request was handled.
+func (r *FailedRequest) OccurredAt() time.Time {
+	return r.occurredAt
+}
@@ -0,0 +1,183 @@
+// Package entities - fee breakdown for outgoing transactions.
+package entities
+
+import (
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/google/uuid"
+)
+
+// FeeMode defines who bears the fee of an outgoing transaction.
+type FeeMode string
+
+const (
+	// FeeModeSenderPays charges the fee on top: the counterparty receives the gross amount,
+	// the payer is debited gross + fee.
+	FeeModeSenderPays FeeMode = "SENDER_PAYS"
+	// FeeModeDeducted takes the fee out of the amount: the counterparty receives gross - fee,
+	// the payer is debited exactly gross.
+	FeeModeDeducted FeeMode = "DEDUCTED"
+)
+
+// IsValid checks if the fee mode is valid.
+func (m FeeMode) IsValid() bool {
+	return m == FeeModeSenderPays || m == FeeModeDeducted
+}
+
+// MetadataParentTransactionID links a FEE transaction to the transaction it was charged for.
+const MetadataParentTransactionID = "parent_transaction_id"
+
+// FeeIdempotencyNamespace is the namespace of derived FEE transaction keys.
+const FeeIdempotencyNamespace = "fee"
+
+// LegacyFeeIdempotencySuffix was appended to the parent key of FEE transactions
+// booked before keys were derived. Kept only to find those transactions on replay.
+const LegacyFeeIdempotencySuffix = ":fee"
+
+// FeeIdempotencyKey returns the idempotency key of the FEE transaction of a parent.
+func FeeIdempotencyKey(parentKey string) string {
+	return valueobjects.DerivedIdempotencyKey(FeeIdempotencyNamespace, parentKey)
+}
+
+// IsFeeApplicable reports whether fees can be charged on this transaction type.
+// Only outgoing money movements carry a fee.
+func (t TransactionType) IsFeeApplicable() bool {
+	switch t {
+	case TransactionTypeTransfer, TransactionTypeWithdraw, TransactionTypePayout:
+		return true
+	default:
+		return false
+	}
+}
+
+// ApplyFee sets the amount breakdown for the transaction.
+//
+// Business Rules:
+// - Only PENDING transactions of a fee-applicable type, and only once
+// - Fee must be non-negative and in the transaction currency
+// - Deducted fee must be less than the gross amount (net stays positive)
+// - A zero fee leaves gross == net and fee == 0 regardless of mode
+//
+// Invariant after ApplyFee: net + fee == gross (deducted) or net == gross (sender pays).
+// The payer wallet loses net + fee; the fee part is recorded by the linked FEE transaction.
+func (t *Transaction) ApplyFee(fee valueobjects.Money, mode FeeMode) error {
+	if !t.IsPending() {
+		return errors.ErrTransactionAlreadyProcessed
+	}
+
+	if !mode.IsValid() {
+		return errors.NewBusinessRuleViolation(
+			"INVALID_FEE_MODE",
+			"fee mode must be SENDER_PAYS or DEDUCTED",
+			map[string]interface{}{"mode": mode},
+		)
+	}
+
+	if !fee.Currency().Equals(t.amount.Currency()) {
+		return valueobjects.ErrCurrencyMismatch
+	}
+
+	if fee.Amount().Sign() < 0 {
+		return errors.NewBusinessRuleViolation(
+			"INVALID_FEE",
+			"fee must not be negative",
+			map[string]interface{}{"fee": fee.String()},
+		)
+	}
+
+	if fee.IsZero() {
+		return nil
+	}
+
+	if !t.transactionType.IsFeeApplicable() {
+		return errors.NewBusinessRuleViolation(
+			"FEE_NOT_APPLICABLE",
+			"fees only apply to outgoing transactions",
+			map[string]interface{}{"type": t.transactionType},
+		)
+	}
+
+	if !t.feeAmount.IsZero() {
+		return errors.NewBusinessRuleViolation(
+			"FEE_ALREADY_APPLIED",
+			"transaction fee has already been applied",
+			map[string]interface{}{"fee": t.feeAmount.String()},
+		)
+	}
+
+	net := t.amount
+	if mode == FeeModeDeducted {
+		var err error
+		net, err = t.amount.Subtract(fee)
+		if err != nil || !net.IsPositive() {
+			return errors.NewBusinessRuleViolation(
+				"FEE_EXCEEDS_AMOUNT",
+				"deducted fee must be less than the transaction amount",
+				map[string]interface{}{"amount": t.amount.String(), "fee": fee.String()},
+			)
+		}
+	}
+
+	t.feeAmount = fee
+	t.netAmount = net
+	return nil
+}
+
+// HasFee returns true if a non-zero fee was applied.
+func (t *Transaction) HasFee() bool {
+	return !t.feeAmount.IsZero()
+}
+
+// NewFeeTransaction creates the FEE transaction linked to a parent that has a fee applied.
+// It is booked against the same wallet, and its idempotency key is derived from the parent's,
+// so a replayed parent never charges the fee twice.
+func NewFeeTransaction(parent *Transaction) (*Transaction, error) {
+	if !parent.HasFee() {
+		return nil, errors.NewBusinessRuleViolation(
+			"NO_FEE",
+			"parent transaction has no fee",
+			map[string]interface{}{"transactionId": parent.id},
+		)
+	}
+
+	fee, err := NewTransaction(
+		parent.walletID,
+		FeeIdempotencyKey(parent.idempotencyKey),
+		TransactionTypeFee,
+		parent.feeAmount,
+		"Fee for transaction "+parent.id.String(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fee.metadata[MetadataParentTransactionID] = parent.id.String()
+	fee.jurisdiction = parent.jurisdiction
+	fee.createdByVersion = parent.createdByVersion
+	return fee, nil
+}
+
+// ParentTransactionID returns the transaction a FEE transaction was charged for, if any.
+func (t *Transaction) ParentTransactionID() (uuid.UUID, bool) {
+	raw, ok := t.metadata[MetadataParentTransactionID].(string)
+	if !ok {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// FeeMode derives who bore the fee from the stored breakdown.
+// Returns an empty mode when no fee was applied.
+func (t *Transaction) FeeMode() FeeMode {
+	if !t.HasFee() {
+		return ""
+	}
+	if t.netAmount.Equals(t.amount) {
+		return FeeModeSenderPays
+	}
+	return FeeModeDeducted
+}
@@ -0,0 +1,174 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/google/uuid"
+)
+
+func newTestTransfer(t *testing.T, amount string) *Transaction {
+	t.Helper()
+	money, _ := valueobjects.NewMoney(amount, valueobjects.USD)
+	tx, err := NewTransaction(uuid.New(), "key-"+uuid.NewString(), TransactionTypeTransfer, money, "transfer")
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	return tx
+}
+
+// TestNewTransaction_DefaultBreakdown tests that a new transaction has no fee and net == gross
+func TestNewTransaction_DefaultBreakdown(t *testing.T) {
+	tx := newTestTransfer(t, "100.00")
+
+	if !tx.GrossAmount().Equals(tx.Amount()) || !tx.NetAmount().Equals(tx.Amount()) {
+		t.Errorf("expected gross = net = amount, got gross=%s net=%s", tx.GrossAmount(), tx.NetAmount())
+	}
+	if !tx.FeeAmount().IsZero() || tx.HasFee() || tx.FeeMode() != "" {
+		t.Errorf("expected no fee, got %s (%q)", tx.FeeAmount(), tx.FeeMode())
+	}
+}
+
+// TestTransaction_ApplyFee tests the breakdown for both fee modes and a zero fee
+func TestTransaction_ApplyFee(t *testing.T) {
+	tests := []struct {
+		name     string
+		fee      string
+		mode     FeeMode
+		wantNet  string
+		wantMode FeeMode
+	}{
+		{"Sender pays", "1.50", FeeModeSenderPays, "100.00", FeeModeSenderPays},
+		{"Deducted", "1.50", FeeModeDeducted, "98.50", FeeModeDeducted},
+		{"Zero fee sender pays", "0", FeeModeSenderPays, "100.00", ""},
+		{"Zero fee deducted", "0", FeeModeDeducted, "100.00", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newTestTransfer(t, "100.00")
+			fee, _ := valueobjects.NewMoney(tt.fee, valueobjects.USD)
+
+			if err := tx.ApplyFee(fee, tt.mode); err != nil {
+				t.Fatalf("ApplyFee() error = %v", err)
+			}
+
+			wantNet, _ := valueobjects.NewMoney(tt.wantNet, valueobjects.USD)
+			if !tx.NetAmount().Equals(wantNet) {
+				t.Errorf("NetAmount() = %s, want %s", tx.NetAmount(), wantNet)
+			}
+			if !tx.FeeAmount().Equals(fee) {
+				t.Errorf("FeeAmount() = %s, want %s", tx.FeeAmount(), fee)
+			}
+			if !tx.GrossAmount().Equals(tx.Amount()) {
+				t.Errorf("GrossAmount() = %s, want %s", tx.GrossAmount(), tx.Amount())
+			}
+			if tx.FeeMode() != tt.wantMode {
+				t.Errorf("FeeMode() = %q, want %q", tx.FeeMode(), tt.wantMode)
+			}
+		})
+	}
+}
+
+// TestTransaction_ApplyFee_Errors tests fee validation rules
+func TestTransaction_ApplyFee_Errors(t *testing.T) {
+	fee, _ := valueobjects.NewMoney("1.00", valueobjects.USD)
+	eurFee, _ := valueobjects.NewMoney("1.00", valueobjects.EUR)
+	fullFee, _ := valueobjects.NewMoney("10.00", valueobjects.USD)
+
+	t.Run("Invalid mode", func(t *testing.T) {
+		tx := newTestTransfer(t, "10.00")
+		if err := tx.ApplyFee(fee, FeeMode("SPLIT")); !errors.IsBusinessRuleViolation(err) {
+			t.Errorf("expected business rule violation, got %v", err)
+		}
+	})
+
+	t.Run("Currency mismatch", func(t *testing.T) {
+		tx := newTestTransfer(t, "10.00")
+		if err := tx.ApplyFee(eurFee, FeeModeSenderPays); err != valueobjects.ErrCurrencyMismatch {
+			t.Errorf("expected ErrCurrencyMismatch, got %v", err)
+		}
+	})
+
+	t.Run("Deducted fee not less than amount", func(t *testing.T) {
+		tx := newTestTransfer(t, "10.00")
+		if err := tx.ApplyFee(fullFee, FeeModeDeducted); !errors.IsBusinessRuleViolation(err) {
+			t.Errorf("expected business rule violation, got %v", err)
+		}
+		if tx.HasFee() {
+			t.Error("failed ApplyFee must not change the breakdown")
+		}
+	})
+
+	t.Run("Applied twice", func(t *testing.T) {
+		tx := newTestTransfer(t, "10.00")
+		if err := tx.ApplyFee(fee, FeeModeSenderPays); err != nil {
+			t.Fatalf("ApplyFee() error = %v", err)
+		}
+		if err := tx.ApplyFee(fee, FeeModeSenderPays); !errors.IsBusinessRuleViolation(err) {
+			t.Errorf("expected business rule violation, got %v", err)
+		}
+	})
+
+	t.Run("Not pending", func(t *testing.T) {
+		tx := newTestTransfer(t, "10.00")
+		_ = tx.StartProcessing()
+		if err := tx.ApplyFee(fee, FeeModeSenderPays); err != errors.ErrTransactionAlreadyProcessed {
+			t.Errorf("expected ErrTransactionAlreadyProcessed, got %v", err)
+		}
+	})
+
+	t.Run("Incoming transaction", func(t *testing.T) {
+		amount, _ := valueobjects.NewMoney("10.00", valueobjects.USD)
+		tx, _ := NewTransaction(uuid.New(), "key-deposit", TransactionTypeDeposit, amount, "")
+		if err := tx.ApplyFee(fee, FeeModeDeducted); !errors.IsBusinessRuleViolation(err) {
+			t.Errorf("expected business rule violation, got %v", err)
+		}
+	})
+}
+
+// TestNewFeeTransaction tests the FEE transaction linked to its parent
+func TestNewFeeTransaction(t *testing.T) {
+	parent := newTestTransfer(t, "100.00")
+	if _, err := NewFeeTransaction(parent); err == nil {
+		t.Error("expected error for parent without fee")
+	}
+
+	fee, _ := valueobjects.NewMoney("1.50", valueobjects.USD)
+	_ = parent.AssignJurisdiction("DE")
+	_ = parent.StampCreatedByVersion("1.4.0+abc1234")
+	if err := parent.ApplyFee(fee, FeeModeDeducted); err != nil {
+		t.Fatalf("ApplyFee() error = %v", err)
+	}
+
+	feeTx, err := NewFeeTransaction(parent)
+	if err != nil {
+		t.Fatalf("NewFeeTransaction() error = %v", err)
+	}
+
+	if feeTx.Type() != TransactionTypeFee || !feeTx.Amount().Equals(fee) {
+		t.Errorf("expected FEE of %s, got %s of %s", fee, feeTx.Type(), feeTx.Amount())
+	}
+	if feeTx.WalletID() != parent.WalletID() {
+		t.Errorf("WalletID() = %s, want %s", feeTx.WalletID(), parent.WalletID())
+	}
+	if feeTx.IdempotencyKey() != FeeIdempotencyKey(parent.IdempotencyKey()) || !valueobjects.IsSystemIdempotencyKey(feeTx.IdempotencyKey()) {
+		t.Errorf("IdempotencyKey() = %s", feeTx.IdempotencyKey())
+	}
+	if parentID, ok := feeTx.ParentTransactionID(); !ok || parentID != parent.ID() {
+		t.Errorf("ParentTransactionID() = %s, %v; want %s", parentID, ok, parent.ID())
+	}
+	if feeTx.Jurisdiction() != "DE" {
+		t.Errorf("Jurisdiction() = %q, want DE", feeTx.Jurisdiction())
+	}
+	if feeTx.CreatedByVersion() != "1.4.0+abc1234" {
+		t.Errorf("CreatedByVersion() = %q, want 1.4.0+abc1234", feeTx.CreatedByVersion())
+	}
+
+	// Payer loses exactly gross in deducted mode: net + fee == gross
+	total, _ := parent.NetAmount().Add(feeTx.Amount())
+	if !total.Equals(parent.GrossAmount()) {
+		t.Errorf("net + fee = %s, want gross %s", total, parent.GrossAmount())
+	}
+}
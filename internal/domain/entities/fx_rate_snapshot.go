@@ -0,0 +1,112 @@
+// Package entities - FXRateSnapshot is an immutable record of an exchange rate used by a money operation.
+package entities
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// FXRateScale is the number of decimal places a snapshot keeps of a rate
+// (NUMERIC(24,12) in fx_rate_snapshots).
+const FXRateScale = 12
+
+// MetadataFXRateSnapshotID is the transaction metadata key referencing the
+// snapshot of the rate the transaction was priced at.
+const MetadataFXRateSnapshotID = "fx_rate_snapshot_id"
+
+// FXRateSnapshot records which rate a mutating operation used and where it
+// came from: how much Quote one unit of Base buys, the provider that
+// published it and the provider's own timestamp of the rate. The rate is
+// rounded to FXRateScale decimals when the snapshot is taken, and the
+// operation prices with the rounded value, so the stored rate reproduces the
+// operation exactly. Snapshots are append-only and removed only by retention.
+type FXRateSnapshot struct {
+	id        uuid.UUID
+	base      string
+	quote     string
+	rate      *big.Rat
+	source    string
+	fetchedAt time.Time
+}
+
+// NewFXRateSnapshot creates a snapshot of a rate returned by a provider.
+func NewFXRateSnapshot(base, quote string, rate *big.Rat, source string, fetchedAt time.Time) (*FXRateSnapshot, error) {
+	baseCurrency, err := valueobjects.NewCurrency(base)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "base", Code: errors.ValidationCodeInvalidValue, Message: fmt.Sprintf("unknown currency %q", base)}
+	}
+	quoteCurrency, err := valueobjects.NewCurrency(quote)
+	if err != nil {
+		return nil, errors.ValidationError{Field: "quote", Code: errors.ValidationCodeInvalidValue, Message: fmt.Sprintf("unknown currency %q", quote)}
+	}
+	if baseCurrency.Code() == quoteCurrency.Code() {
+		return nil, errors.ValidationError{Field: "quote", Code: errors.ValidationCodeInvalidValue, Message: "quote currency must differ from base"}
+	}
+	if rate == nil || rate.Sign() <= 0 {
+		return nil, errors.ValidationError{Field: "rate", Code: errors.ValidationCodeOutOfRange, Message: "rate must be positive"}
+	}
+	rounded, ok := new(big.Rat).SetString(rate.FloatString(FXRateScale))
+	if !ok || rounded.Sign() <= 0 {
+		return nil, errors.ValidationError{Field: "rate", Code: errors.ValidationCodeOutOfRange,
+			Message: fmt.Sprintf("rate is below %d decimal places", FXRateScale)}
+	}
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, errors.ValidationError{Field: "source", Code: errors.ValidationCodeRequired, Message: "source is required"}
+	}
+	if fetchedAt.IsZero() {
+		return nil, errors.ValidationError{Field: "fetched_at", Code: errors.ValidationCodeRequired, Message: "fetched_at is required"}
+	}
+
+	return ReconstructFXRateSnapshot(uuid.New(), baseCurrency.Code(), quoteCurrency.Code(), rounded, source, fetchedAt), nil
+}
+
+// ReconstructFXRateSnapshot reconstructs an FXRateSnapshot from stored data.
+// No validation - assumes data is already valid. Timestamps are normalized to UTC.
+func ReconstructFXRateSnapshot(id uuid.UUID, base, quote string, rate *big.Rat, source string, fetchedAt time.Time) *FXRateSnapshot {
+	return &FXRateSnapshot{
+		id:        id,
+		base:      base,
+		quote:     quote,
+		rate:      new(big.Rat).Set(rate),
+		source:    source,
+		fetchedAt: fetchedAt.UTC(),
+	}
+}
+
+// ID returns the snapshot identifier.
+func (s *FXRateSnapshot) ID() uuid.UUID {
+	return s.id
+}
+
+// Base returns the currency being converted from.
+func (s *FXRateSnapshot) Base() string {
+	return s.base
+}
+
+// Quote returns the currency being converted to.
+func (s *FXRateSnapshot) Quote() string {
+	return s.quote
+}
+
+// Rate returns a copy of the rate: units of Quote per one unit of Base.
+func (s *FXRateSnapshot) Rate() *big.Rat {
+	return new(big.Rat).Set(s.rate)
+}
+
+// Source returns the provider that published the rate.
+func (s *FXRateSnapshot) Source() string {
+	return s.source
+}
+
+// FetchedAt returns the provider's timestamp of the rate.
+func (s *FXRateSnapshot) FetchedAt() time.Time {
+	return s.fetchedAt
+}
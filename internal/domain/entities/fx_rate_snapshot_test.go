@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+func TestNewFXRateSnapshot(t *testing.T) {
+	fetchedAt := time.Date(2026, 3, 1, 15, 0, 0, 0, time.FixedZone("MSK", 3*3600))
+
+	snapshot, err := NewFXRateSnapshot("usd", "EUR", big.NewRat(2, 3), " provider ", fetchedAt)
+	if err != nil {
+		t.Fatalf("NewFXRateSnapshot() error = %v", err)
+	}
+	if snapshot.Base() != "USD" || snapshot.Quote() != "EUR" || snapshot.Source() != "provider" {
+		t.Errorf("snapshot = %s/%s from %q, want USD/EUR from provider", snapshot.Base(), snapshot.Quote(), snapshot.Source())
+	}
+	if got := snapshot.Rate().FloatString(15); got != "0.666666666667000" {
+		t.Errorf("Rate() = %s, want rounded to %d places", got, FXRateScale)
+	}
+	if !snapshot.FetchedAt().Equal(fetchedAt) || snapshot.FetchedAt().Location() != time.UTC {
+		t.Errorf("FetchedAt() = %s, want %s in UTC", snapshot.FetchedAt(), fetchedAt)
+	}
+
+	// Rate() отдаёт копию
+	snapshot.Rate().SetInt64(5)
+	if snapshot.Rate().Cmp(big.NewRat(5, 1)) == 0 {
+		t.Error("Rate() exposes the stored rate")
+	}
+}
+
+func TestNewFXRateSnapshot_Invalid(t *testing.T) {
+	fetchedAt := time.Now()
+	rate := big.NewRat(92, 100)
+
+	cases := []struct {
+		name  string
+		build func() (*FXRateSnapshot, error)
+		field string
+	}{
+		{"UnknownBase", func() (*FXRateSnapshot, error) { return NewFXRateSnapshot("XXX", "EUR", rate, "p", fetchedAt) }, "base"},
+		{"SameCurrency", func() (*FXRateSnapshot, error) { return NewFXRateSnapshot("EUR", "EUR", rate, "p", fetchedAt) }, "quote"},
+		{"ZeroRate", func() (*FXRateSnapshot, error) { return NewFXRateSnapshot("USD", "EUR", new(big.Rat), "p", fetchedAt) }, "rate"},
+		{"RateBelowScale", func() (*FXRateSnapshot, error) {
+			return NewFXRateSnapshot("USD", "EUR", big.NewRat(1, 1e13), "p", fetchedAt)
+		}, "rate"},
+		{"NoSource", func() (*FXRateSnapshot, error) { return NewFXRateSnapshot("USD", "EUR", rate, " ", fetchedAt) }, "source"},
+		{"NoFetchedAt", func() (*FXRateSnapshot, error) { return NewFXRateSnapshot("USD", "EUR", rate, "p", time.Time{}) }, "fetched_at"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.build()
+			valErr, ok := err.(errors.ValidationError)
+			if !ok || valErr.Field != tc.field {
+				t.Errorf("error = %v, want ValidationError on %s", err, tc.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+// Package entities - Incident is an incident marker shown on the public status page.
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// MaxIncidentTitleLength is the maximum length of an incident title in runes.
+const MaxIncidentTitleLength = 200
+
+// IncidentResolvedVisibility is how long a resolved incident stays on the status page.
+const IncidentResolvedVisibility = 24 * time.Hour
+
+// StatusComponent is a coarse-grained part of the service shown on the status page.
+type StatusComponent string
+
+const (
+	StatusComponentAPI      StatusComponent = "api"
+	StatusComponentDatabase StatusComponent = "database"
+	StatusComponentPayouts  StatusComponent = "payouts"
+	StatusComponentDeposits StatusComponent = "deposits"
+)
+
+// StatusComponents lists the status page components in display order.
+var StatusComponents = []StatusComponent{
+	StatusComponentAPI,
+	StatusComponentDatabase,
+	StatusComponentPayouts,
+	StatusComponentDeposits,
+}
+
+// IsStatusComponent reports whether the component is shown on the status page.
+func IsStatusComponent(component StatusComponent) bool {
+	for _, known := range StatusComponents {
+		if component == known {
+			return true
+		}
+	}
+	return false
+}
+
+// IncidentSeverity is the impact of an incident as announced to customers.
+type IncidentSeverity string
+
+const (
+	IncidentSeverityMinor    IncidentSeverity = "MINOR"
+	IncidentSeverityMajor    IncidentSeverity = "MAJOR"
+	IncidentSeverityCritical IncidentSeverity = "CRITICAL"
+)
+
+// IsValid reports whether the severity is known.
+func (s IncidentSeverity) IsValid() bool {
+	switch s {
+	case IncidentSeverityMinor, IncidentSeverityMajor, IncidentSeverityCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+// Incident is a customer-facing incident marker set by an admin. It stays
+// active until resolved and remains listed for IncidentResolvedVisibility
+// afterwards. CreatedBy and ResolvedBy are kept for audit and never shown
+// publicly.
+type Incident struct {
+	id         uuid.UUID
+	title      string
+	severity   IncidentSeverity
+	components []StatusComponent
+	startedAt  time.Time
+	resolvedAt *time.Time
+	createdBy  uuid.UUID
+	resolvedBy *uuid.UUID
+	createdAt  time.Time
+}
+
+// NewIncident creates an active incident with validation.
+// The title is trimmed and must be non-empty of at most MaxIncidentTitleLength
+// runes; components must be non-empty and known (duplicates are dropped).
+// A zero startedAt means now; a start in the future is rejected.
+func NewIncident(
+	title string,
+	severity IncidentSeverity,
+	components []StatusComponent,
+	startedAt time.Time,
+	createdBy uuid.UUID,
+) (*Incident, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, errors.ValidationError{Field: "title", Message: "incident title is required"}
+	}
+	if utf8.RuneCountInString(title) > MaxIncidentTitleLength {
+		return nil, errors.ValidationError{
+			Field:   "title",
+			Message: fmt.Sprintf("incident title must be at most %d characters", MaxIncidentTitleLength),
+		}
+	}
+
+	if !severity.IsValid() {
+		return nil, errors.ValidationError{Field: "severity", Message: "severity must be MINOR, MAJOR or CRITICAL"}
+	}
+
+	if len(components) == 0 {
+		return nil, errors.ValidationError{Field: "components", Message: "at least one affected component is required"}
+	}
+	unique := make([]StatusComponent, 0, len(components))
+	for _, component := range components {
+		if !IsStatusComponent(component) {
+			return nil, errors.ValidationError{
+				Field:   "components",
+				Message: fmt.Sprintf("unknown component %q", component),
+			}
+		}
+		if !containsComponent(unique, component) {
+			unique = append(unique, component)
+		}
+	}
+
+	now := clock.Now()
+	if startedAt.IsZero() {
+		startedAt = now
+	}
+	if startedAt.After(now) {
+		return nil, errors.ValidationError{Field: "started_at", Message: "incident cannot start in the future"}
+	}
+
+	return &Incident{
+		id:         uuid.New(),
+		title:      title,
+		severity:   severity,
+		components: unique,
+		startedAt:  startedAt.UTC(),
+		createdBy:  createdBy,
+		createdAt:  now,
+	}, nil
+}
+
+// ReconstructIncident reconstructs an Incident from stored data.
+// No validation - assumes data is already valid. Timestamps are normalized to UTC.
+func ReconstructIncident(
+	id uuid.UUID,
+	title string,
+	severity IncidentSeverity,
+	components []StatusComponent,
+	startedAt time.Time,
+	resolvedAt *time.Time,
+	createdBy uuid.UUID,
+	resolvedBy *uuid.UUID,
+	createdAt time.Time,
+) *Incident {
+	if resolvedAt != nil {
+		t := resolvedAt.UTC()
+		resolvedAt = &t
+	}
+
+	return &Incident{
+		id:         id,
+		title:      title,
+		severity:   severity,
+		components: append([]StatusComponent(nil), components...),
+		startedAt:  startedAt.UTC(),
+		resolvedAt: resolvedAt,
+		createdBy:  createdBy,
+		resolvedBy: resolvedBy,
+		createdAt:  createdAt.UTC(),
+	}
+}
+
+// containsComponent reports whether component is already in the list.
+func containsComponent(components []StatusComponent, component StatusComponent) bool {
+	for _, c := range components {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve marks the incident as resolved by actorID.
+// Resolving an already resolved incident is a business rule violation.
+func (i *Incident) Resolve(actorID uuid.UUID) error {
+	if i.IsResolved() {
+		return errors.NewBusinessRuleViolation(
+			"INCIDENT_ALREADY_RESOLVED",
+			"incident is already resolved",
+			map[string]interface{}{"incidentId": i.id},
+		)
+	}
+	now := clock.Now()
+	i.resolvedAt = &now
+	i.resolvedBy = &actorID
+	return nil
+}
+
+// ID returns the incident identifier.
+func (i *Incident) ID() uuid.UUID {
+	return i.id
+}
+
+// Title returns the customer-facing title.
+func (i *Incident) Title() string {
+	return i.title
+}
+
+// Severity returns the announced impact.
+func (i *Incident) Severity() IncidentSeverity {
+	return i.severity
+}
+
+// Components returns a copy of the affected components.
+func (i *Incident) Components() []StatusComponent {
+	return append([]StatusComponent(nil), i.components...)
+}
+
+// StartedAt returns when the incident started.
+func (i *Incident) StartedAt() time.Time {
+	return i.startedAt
+}
+
+// ResolvedAt returns when the incident was resolved, nil while active.
+func (i *Incident) ResolvedAt() *time.Time {
+	return i.resolvedAt
+}
+
+// CreatedBy returns the admin who opened the incident.
+func (i *Incident) CreatedBy() uuid.UUID {
+	return i.createdBy
+}
+
+// ResolvedBy returns the admin who resolved the incident, nil while active.
+func (i *Incident) ResolvedBy() *uuid.UUID {
+	return i.resolvedBy
+}
+
+// CreatedAt returns when the incident record was created.
+func (i *Incident) CreatedAt() time.Time {
+	return i.createdAt
+}
+
+// IsResolved reports whether the incident was resolved.
+func (i *Incident) IsResolved() bool {
+	return i.resolvedAt != nil
+}
+
+// VisibleAt reports whether the incident is listed on the status page at now:
+// active incidents always, resolved ones for IncidentResolvedVisibility.
+func (i *Incident) VisibleAt(now time.Time) bool {
+	return i.resolvedAt == nil || now.Sub(*i.resolvedAt) <= IncidentResolvedVisibility
+}
@@ -0,0 +1,84 @@
+// Package entities - IncomingTransferView is the destination's presentation of a transfer.
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// IncomingTransferView is how an incoming transfer is presented to the owner
+// of the destination wallet. It is derived from the destination's
+// WalletSettings at transfer time and stored next to the canonical
+// transaction, which keeps the sender's description and metadata.
+type IncomingTransferView struct {
+	transactionID uuid.UUID
+	walletID      uuid.UUID
+	description   string
+	tags          []string
+	createdAt     time.Time
+}
+
+// NewIncomingTransferView renders the destination's view of a transfer.
+// Returns nil when the settings neither change the description nor add tags.
+func NewIncomingTransferView(tx *Transaction, settings *WalletSettings) (*IncomingTransferView, error) {
+	destinationID := tx.DestinationWalletID()
+	if tx.Type() != TransactionTypeTransfer || destinationID == nil {
+		return nil, errors.NewBusinessRuleViolation(
+			"NOT_A_TRANSFER",
+			"incoming view requires a transfer with a destination wallet",
+			map[string]interface{}{"transactionId": tx.ID().String()},
+		)
+	}
+	if settings == nil || (settings.IncomingDescriptionTemplate() == "" && len(settings.autoTags) == 0) {
+		return nil, nil
+	}
+
+	return &IncomingTransferView{
+		transactionID: tx.ID(),
+		walletID:      *destinationID,
+		description:   settings.IncomingDescription(tx.Description(), tx.ExternalReference()),
+		tags:          settings.AutoTags(),
+		createdAt:     clock.Now(),
+	}, nil
+}
+
+// ReconstructIncomingTransferView reconstructs a view from stored data.
+// No validation - assumes data is already valid.
+func ReconstructIncomingTransferView(transactionID, walletID uuid.UUID, description string, tags []string, createdAt time.Time) *IncomingTransferView {
+	return &IncomingTransferView{
+		transactionID: transactionID,
+		walletID:      walletID,
+		description:   description,
+		tags:          append([]string(nil), tags...),
+		createdAt:     createdAt.UTC(),
+	}
+}
+
+// TransactionID returns the canonical transfer.
+func (v *IncomingTransferView) TransactionID() uuid.UUID {
+	return v.transactionID
+}
+
+// WalletID returns the destination wallet.
+func (v *IncomingTransferView) WalletID() uuid.UUID {
+	return v.walletID
+}
+
+// Description returns the description shown to the destination owner.
+func (v *IncomingTransferView) Description() string {
+	return v.description
+}
+
+// Tags returns a copy of the destination's auto tags.
+func (v *IncomingTransferView) Tags() []string {
+	return append(make([]string, 0, len(v.tags)), v.tags...)
+}
+
+// CreatedAt returns when the view was rendered.
+func (v *IncomingTransferView) CreatedAt() time.Time {
+	return v.createdAt
+}
@@ -0,0 +1,83 @@
+// Package entities - KYCTransition is an immutable record of a KYC status change.
+package entities
+
+import (
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+	"github.com/google/uuid"
+)
+
+// KYCTransition records one KYC decision: who moved the user from which status to which, and why.
+// Transitions are append-only; together they form the user's KYC history.
+type KYCTransition struct {
+	id         uuid.UUID
+	userID     uuid.UUID
+	fromStatus KYCStatus
+	toStatus   KYCStatus
+	reason     string // Required for rejections, optional otherwise
+	actorID    uuid.UUID
+	occurredAt time.Time
+}
+
+// NewKYCTransition creates a transition record for a status change that has just happened.
+func NewKYCTransition(userID uuid.UUID, fromStatus, toStatus KYCStatus, reason string, actorID uuid.UUID) *KYCTransition {
+	return &KYCTransition{
+		id:         uuid.New(),
+		userID:     userID,
+		fromStatus: fromStatus,
+		toStatus:   toStatus,
+		reason:     reason,
+		actorID:    actorID,
+		occurredAt: clock.Now(),
+	}
+}
+
+// ReconstructKYCTransition reconstructs a KYCTransition from stored data.
+// No validation - assumes data is already valid. Timestamps are normalized to UTC.
+func ReconstructKYCTransition(id, userID uuid.UUID, fromStatus, toStatus KYCStatus, reason string, actorID uuid.UUID, occurredAt time.Time) *KYCTransition {
+	return &KYCTransition{
+		id:         id,
+		userID:     userID,
+		fromStatus: fromStatus,
+		toStatus:   toStatus,
+		reason:     reason,
+		actorID:    actorID,
+		occurredAt: occurredAt.UTC(),
+	}
+}
+
+// ID returns the transition identifier.
+func (t *KYCTransition) ID() uuid.UUID {
+	return t.id
+}
+
+// UserID returns the user whose KYC status changed.
+func (t *KYCTransition) UserID() uuid.UUID {
+	return t.userID
+}
+
+// FromStatus returns the status before the change.
+func (t *KYCTransition) FromStatus() KYCStatus {
+	return t.fromStatus
+}
+
+// ToStatus returns the status after the change.
+func (t *KYCTransition) ToStatus() KYCStatus {
+	return t.toStatus
+}
+
+// Reason returns the reviewer's reason (empty for approvals without a comment).
+func (t *KYCTransition) Reason() string {
+	return t.reason
+}
+
+// ActorID returns who made the decision.
+func (t *KYCTransition) ActorID() uuid.UUID {
+	return t.actorID
+}
+
+// OccurredAt returns when the change happened.
+func (t *KYCTransition) OccurredAt() time.Time {
+	return t.occurredAt
+}
@@ -0,0 +1,138 @@
+// Package entities - OperationSwitch is an operational kill switch for one transaction type.
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// MaxOperationSwitchReasonLength is the maximum length of a switch reason in runes.
+const MaxOperationSwitchReasonLength = 500
+
+// SwitchableOperations lists the transaction types that have a kill switch.
+var SwitchableOperations = []TransactionType{
+	TransactionTypeDeposit,
+	TransactionTypeWithdraw,
+	TransactionTypeTransfer,
+	TransactionTypePayout,
+}
+
+// IsSwitchableOperation reports whether the transaction type has a kill switch.
+func IsSwitchableOperation(operation TransactionType) bool {
+	for _, switchable := range SwitchableOperations {
+		if operation == switchable {
+			return true
+		}
+	}
+	return false
+}
+
+// OperationSwitchesFor returns the switches that must be enabled for a
+// transaction of the given type. A payout leaves the system the same way a
+// withdrawal does, so the WITHDRAW switch stops payouts too; the PAYOUT switch
+// stops payouts only. Types without a switch return nil.
+func OperationSwitchesFor(txType TransactionType) []TransactionType {
+	switch txType {
+	case TransactionTypePayout:
+		return []TransactionType{TransactionTypeWithdraw, TransactionTypePayout}
+	case TransactionTypeDeposit, TransactionTypeWithdraw, TransactionTypeTransfer:
+		return []TransactionType{txType}
+	default:
+		return nil
+	}
+}
+
+// OperationSwitch enables or disables one kind of money movement during an
+// incident. An operation without a stored switch is enabled. A disabled switch
+// always carries the reason shown to clients; UpdatedBy is the admin who made
+// the last change.
+type OperationSwitch struct {
+	operation TransactionType
+	enabled   bool
+	reason    string
+	updatedBy uuid.UUID
+	updatedAt time.Time
+}
+
+// NewOperationSwitch creates a switch state with validation.
+// The operation must be switchable; disabling requires a non-empty reason of
+// at most MaxOperationSwitchReasonLength runes. Enabling clears the reason.
+func NewOperationSwitch(operation TransactionType, enabled bool, reason string, updatedBy uuid.UUID) (*OperationSwitch, error) {
+	if !IsSwitchableOperation(operation) {
+		return nil, errors.ValidationError{
+			Field:   "type",
+			Code:    errors.ValidationCodeInvalidValue,
+			Message: fmt.Sprintf("operation %s has no kill switch", operation),
+		}
+	}
+
+	reason = strings.TrimSpace(reason)
+	if enabled {
+		reason = ""
+	} else if reason == "" {
+		return nil, errors.ValidationError{
+			Field:   "reason",
+			Code:    errors.ValidationCodeRequired,
+			Message: "reason is required to disable an operation",
+		}
+	}
+	if utf8.RuneCountInString(reason) > MaxOperationSwitchReasonLength {
+		return nil, errors.ValidationError{
+			Field:   "reason",
+			Code:    errors.ValidationCodeOutOfRange,
+			Message: fmt.Sprintf("reason must be at most %d characters", MaxOperationSwitchReasonLength),
+		}
+	}
+
+	return &OperationSwitch{
+		operation: operation,
+		enabled:   enabled,
+		reason:    reason,
+		updatedBy: updatedBy,
+		updatedAt: clock.Now(),
+	}, nil
+}
+
+// ReconstructOperationSwitch reconstructs an OperationSwitch from stored data.
+// No validation - assumes data is already valid. Timestamps are normalized to UTC.
+func ReconstructOperationSwitch(operation TransactionType, enabled bool, reason string, updatedBy uuid.UUID, updatedAt time.Time) *OperationSwitch {
+	return &OperationSwitch{
+		operation: operation,
+		enabled:   enabled,
+		reason:    reason,
+		updatedBy: updatedBy,
+		updatedAt: updatedAt.UTC(),
+	}
+}
+
+// Operation returns the transaction type the switch controls.
+func (s *OperationSwitch) Operation() TransactionType {
+	return s.operation
+}
+
+// Enabled reports whether the operation is allowed.
+func (s *OperationSwitch) Enabled() bool {
+	return s.enabled
+}
+
+// Reason returns why the operation is disabled ("" when enabled).
+func (s *OperationSwitch) Reason() string {
+	return s.reason
+}
+
+// UpdatedBy returns the admin who made the last change.
+func (s *OperationSwitch) UpdatedBy() uuid.UUID {
+	return s.updatedBy
+}
+
+// UpdatedAt returns when the switch was last changed.
+func (s *OperationSwitch) UpdatedAt() time.Time {
+	return s.updatedAt
+}
@@ -0,0 +1,96 @@
+// Package entities - SecurityEvent is an immutable record of an authentication or authorization outcome.
+package entities
+
+import (
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+	"github.com/google/uuid"
+)
+
+// SecurityEventType classifies what happened to a request at the auth boundary.
+type SecurityEventType string
+
+const (
+	SecurityEventAuthFailed    SecurityEventType = "AUTH_FAILED"    // Credentials missing, malformed or rejected
+	SecurityEventAuthSucceeded SecurityEventType = "AUTH_SUCCEEDED" // Credentials accepted
+	SecurityEventScopeDenied   SecurityEventType = "SCOPE_DENIED"   // Authenticated, but the API key lacks the route's scope
+	SecurityEventPolicyDenied  SecurityEventType = "POLICY_DENIED"  // Authenticated, but the route's owner or role policy rejected the request
+)
+
+// IsValid reports whether the type is one of the known security event types.
+func (t SecurityEventType) IsValid() bool {
+	switch t {
+	case SecurityEventAuthFailed, SecurityEventAuthSucceeded, SecurityEventScopeDenied, SecurityEventPolicyDenied:
+		return true
+	}
+	return false
+}
+
+// SecurityEvent records one auth outcome for security monitoring.
+// The principal is the user ID when it is known, otherwise a short prefix of the
+// presented credential; it is empty when the request carried no credential at all.
+// Events are append-only and removed only by retention.
+type SecurityEvent struct {
+	id         uuid.UUID
+	principal  string
+	eventType  SecurityEventType
+	ip         string
+	userAgent  string
+	occurredAt time.Time
+}
+
+// NewSecurityEvent creates a record for an auth outcome that has just happened.
+func NewSecurityEvent(principal string, eventType SecurityEventType, ip, userAgent string) *SecurityEvent {
+	return &SecurityEvent{
+		id:         uuid.New(),
+		principal:  principal,
+		eventType:  eventType,
+		ip:         ip,
+		userAgent:  userAgent,
+		occurredAt: clock.Now(),
+	}
+}
+
+// ReconstructSecurityEvent reconstructs a SecurityEvent from stored data.
+// No validation - assumes data is already valid. Timestamps are normalized to UTC.
+func ReconstructSecurityEvent(id uuid.UUID, principal string, eventType SecurityEventType, ip, userAgent string, occurredAt time.Time) *SecurityEvent {
+	return &SecurityEvent{
+		id:         id,
+		principal:  principal,
+		eventType:  eventType,
+		ip:         ip,
+		userAgent:  userAgent,
+		occurredAt: occurredAt.UTC(),
+	}
+}
+
+// ID returns the event identifier.
+func (e *SecurityEvent) ID() uuid.UUID {
+	return e.id
+}
+
+// Principal returns the user ID or credential prefix the request was attributed to.
+func (e *SecurityEvent) Principal() string {
+	return e.principal
+}
+
+// Type returns what happened.
+func (e *SecurityEvent) Type() SecurityEventType {
+	return e.eventType
+}
+
+// IP returns the client address of the request.
+func (e *SecurityEvent) IP() string {
+	return e.ip
+}
+
+// UserAgent returns the User-Agent header of the request.
+func (e *SecurityEvent) UserAgent() string {
+	return e.userAgent
+}
+
+// OccurredAt returns when the request was handled.
+func (e *SecurityEvent) OccurredAt() time.Time {
+	return e.occurredAt
+}
@@ -0,0 +1,151 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/google/uuid"
+)
+
+var allTransactionStatuses = []TransactionStatus{
+	TransactionStatusPending,
+	TransactionStatusProcessing,
+	TransactionStatusCompleted,
+	TransactionStatusFailed,
+	TransactionStatusCancelled,
+}
+
+// TestTransactionTransitions_Exhaustive runs every status-changing method from
+// every status and checks that it succeeds exactly when the table allows it.
+func TestTransactionTransitions_Exhaustive(t *testing.T) {
+	operations := []struct {
+		name   string
+		target TransactionStatus
+		apply  func(tx *Transaction) error
+	}{
+		{"StartProcessing", TransactionStatusProcessing, func(tx *Transaction) error { return tx.StartProcessing() }},
+		{"MarkCompleted", TransactionStatusCompleted, func(tx *Transaction) error { return tx.MarkCompleted() }},
+		{"MarkFailed", TransactionStatusFailed, func(tx *Transaction) error { return tx.MarkFailed("timeout", FailureCategoryProvider) }},
+		{"Cancel", TransactionStatusCancelled, func(tx *Transaction) error { return tx.Cancel() }},
+		{"Retry", TransactionStatusPending, func(tx *Transaction) error { return tx.Retry(DefaultMaxRetries) }},
+	}
+
+	amount, _ := valueobjects.NewMoneyFromInt(100, valueobjects.USD)
+
+	for _, from := range allTransactionStatuses {
+		for _, op := range operations {
+			t.Run(string(from)+"/"+op.name, func(t *testing.T) {
+				tx, err := NewTransaction(uuid.New(), uuid.NewString(), TransactionTypeDeposit, amount, "test")
+				if err != nil {
+					t.Fatalf("NewTransaction() error = %v", err)
+				}
+				tx.status = from
+
+				allowed := TransactionTransitions.CanTransition(from, op.target)
+				err = op.apply(tx)
+
+				if allowed {
+					if err != nil {
+						t.Fatalf("%s from %s error = %v, want nil", op.name, from, err)
+					}
+					if tx.Status() != op.target {
+						t.Errorf("Status = %v, want %v", tx.Status(), op.target)
+					}
+					return
+				}
+				if err == nil {
+					t.Fatalf("%s from %s should return error", op.name, from)
+				}
+				if tx.Status() != from {
+					t.Errorf("Status = %v after rejected %s, want %v", tx.Status(), op.name, from)
+				}
+			})
+		}
+	}
+}
+
+// TestTransactionTransitions_CoverStatuses keeps the table in sync with the
+// status enum: every status has an entry and every target is a valid status.
+func TestTransactionTransitions_CoverStatuses(t *testing.T) {
+	if len(TransactionTransitions) != len(allTransactionStatuses) {
+		t.Errorf("table has %d statuses, want %d", len(TransactionTransitions), len(allTransactionStatuses))
+	}
+	for _, status := range allTransactionStatuses {
+		if _, ok := TransactionTransitions[status]; !ok {
+			t.Errorf("status %s missing from TransactionTransitions", status)
+		}
+	}
+	for _, transition := range TransactionTransitions.Transitions() {
+		if !transition.To.IsValid() {
+			t.Errorf("transition %s -> %s targets an invalid status", transition.From, transition.To)
+		}
+	}
+}
+
+var allWalletStatuses = []WalletStatus{
+	WalletStatusActive,
+	WalletStatusSuspended,
+	WalletStatusLocked,
+	WalletStatusClosed,
+}
+
+// TestWalletTransitions_Exhaustive runs every status-changing method from
+// every status and checks that it succeeds exactly when the table allows it.
+func TestWalletTransitions_Exhaustive(t *testing.T) {
+	operations := []struct {
+		name   string
+		target WalletStatus
+		apply  func(w *Wallet) error
+	}{
+		{"Activate", WalletStatusActive, func(w *Wallet) error { return w.Activate() }},
+		{"Suspend", WalletStatusSuspended, func(w *Wallet) error { return w.Suspend() }},
+		{"Lock", WalletStatusLocked, func(w *Wallet) error { return w.Lock() }},
+		{"Close", WalletStatusClosed, func(w *Wallet) error { return w.Close() }},
+	}
+
+	for _, from := range allWalletStatuses {
+		for _, op := range operations {
+			t.Run(string(from)+"/"+op.name, func(t *testing.T) {
+				wallet, err := NewWallet(uuid.New(), valueobjects.USD)
+				if err != nil {
+					t.Fatalf("NewWallet() error = %v", err)
+				}
+				wallet.status = from
+
+				allowed := WalletTransitions.CanTransition(from, op.target)
+				err = op.apply(wallet)
+
+				if allowed {
+					if err != nil {
+						t.Fatalf("%s from %s error = %v, want nil", op.name, from, err)
+					}
+					if wallet.Status() != op.target {
+						t.Errorf("Status = %v, want %v", wallet.Status(), op.target)
+					}
+					return
+				}
+				if err == nil {
+					t.Fatalf("%s from %s should return error", op.name, from)
+				}
+				if wallet.Status() != from {
+					t.Errorf("Status = %v after rejected %s, want %v", wallet.Status(), op.name, from)
+				}
+			})
+		}
+	}
+}
+
+// TestWalletTransitions_CoverStatuses keeps the table in sync with the status enum.
+func TestWalletTransitions_CoverStatuses(t *testing.T) {
+	if len(WalletTransitions) != len(allWalletStatuses) {
+		t.Errorf("table has %d statuses, want %d", len(WalletTransitions), len(allWalletStatuses))
+	}
+	for _, status := range allWalletStatuses {
+		if _, ok := WalletTransitions[status]; !ok {
+			t.Errorf("status %s missing from WalletTransitions", status)
+		}
+	}
+	if !WalletTransitions.IsFinal(WalletStatusClosed) {
+		t.Error("CLOSED must be final")
+	}
+}
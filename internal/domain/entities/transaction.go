@@ -4,10 +4,16 @@ package entities
 
 import (
 	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Haleralex/wallethub/internal/domain/errors"
 	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+	"github.com/Haleralex/wallethub/internal/pkg/ids"
+	"github.com/Haleralex/wallethub/internal/pkg/statemachine"
 	"github.com/google/uuid"
 )
 
@@ -25,16 +31,32 @@ const (
 	TransactionTypeExchange   TransactionType = "EXCHANGE"   // Currency exchange between own wallets
 )
 
-// IsValid checks if the transaction type is valid.
+// TransactionTypes lists the transaction types known to this build.
+var TransactionTypes = []TransactionType{
+	TransactionTypeDeposit, TransactionTypeWithdraw, TransactionTypePayout,
+	TransactionTypeTransfer, TransactionTypeFee, TransactionTypeRefund, TransactionTypeAdjustment,
+	TransactionTypeExchange,
+}
+
+// IsKnown reports whether this build knows the transaction type. Stored
+// rows may carry types added by a later release (see ReconstructTransaction):
+// such transactions can be read but not changed.
+func (t TransactionType) IsKnown() bool {
+	return slices.Contains(TransactionTypes, t)
+}
+
+// IsCredit reports whether the transaction credits its own wallet. Every
+// other known type debits it (a TRANSFER or EXCHANGE also credits its
+// destination wallet). ADJUSTMENT is always a credit for now (see
+// CreateTransactionUseCase); unknown types are neither.
+func (t TransactionType) IsCredit() bool {
+	return t == TransactionTypeDeposit || t == TransactionTypeRefund || t == TransactionTypeAdjustment
+}
+
+// IsValid checks if the transaction type is valid for new transactions:
+// only known types can be written.
 func (t TransactionType) IsValid() bool {
-	switch t {
-	case TransactionTypeDeposit, TransactionTypeWithdraw, TransactionTypePayout,
-		TransactionTypeTransfer, TransactionTypeFee, TransactionTypeRefund, TransactionTypeAdjustment,
-		TransactionTypeExchange:
-		return true
-	default:
-		return false
-	}
+	return t.IsKnown()
 }
 
 // TransactionStatus represents the current state of a transaction.
@@ -48,8 +70,9 @@ const (
 	TransactionStatusCancelled  TransactionStatus = "CANCELLED"  // Cancelled by user/system
 )
 
-// IsValid checks if the transaction status is valid.
-func (s TransactionStatus) IsValid() bool {
+// IsKnown reports whether this build knows the transaction status. Like
+// unknown types, unknown statuses are readable but have no transitions.
+func (s TransactionStatus) IsKnown() bool {
 	switch s {
 	case TransactionStatusPending, TransactionStatusProcessing, TransactionStatusCompleted,
 		TransactionStatusFailed, TransactionStatusCancelled:
@@ -59,11 +82,112 @@ func (s TransactionStatus) IsValid() bool {
 	}
 }
 
+// IsValid checks if the transaction status is valid for writing.
+func (s TransactionStatus) IsValid() bool {
+	return s.IsKnown()
+}
+
 // IsFinal returns true if the status is terminal (no further transitions).
 func (s TransactionStatus) IsFinal() bool {
 	return s == TransactionStatusCompleted || s == TransactionStatusFailed || s == TransactionStatusCancelled
 }
 
+// IsInFlight returns true for PENDING and PROCESSING - statuses that count
+// towards the per-wallet cap on unfinished transactions.
+func (s TransactionStatus) IsInFlight() bool {
+	return s == TransactionStatusPending || s == TransactionStatusProcessing
+}
+
+// TransactionTransitions is the transaction state machine: every status
+// change made by Transaction must be listed here. FAILED is final for
+// processing but may return to PENDING through Retry.
+var TransactionTransitions = statemachine.Table[TransactionStatus]{
+	TransactionStatusPending:    {TransactionStatusProcessing, TransactionStatusFailed, TransactionStatusCancelled},
+	TransactionStatusProcessing: {TransactionStatusCompleted, TransactionStatusFailed},
+	TransactionStatusFailed:     {TransactionStatusPending},
+	TransactionStatusCompleted:  {},
+	TransactionStatusCancelled:  {},
+}
+
+// FailureCategory classifies who caused a transaction failure.
+// Failure-rate reporting separates expected client-caused failures from
+// failures that are the fault of a provider or of the system itself.
+type FailureCategory string
+
+const (
+	FailureCategoryClient   FailureCategory = "CLIENT"   // Caused by the customer (insufficient funds, invalid account)
+	FailureCategoryProvider FailureCategory = "PROVIDER" // Caused by the external provider (outage, timeout, rejection)
+	FailureCategorySystem   FailureCategory = "SYSTEM"   // Caused by our own infrastructure (database, bugs)
+	FailureCategoryFraud    FailureCategory = "FRAUD"    // Blocked by fraud or compliance controls
+)
+
+// FailureCategories lists all categories in reporting order.
+var FailureCategories = []FailureCategory{
+	FailureCategoryClient,
+	FailureCategoryProvider,
+	FailureCategorySystem,
+	FailureCategoryFraud,
+}
+
+// IsValid checks if the failure category is valid.
+func (c FailureCategory) IsValid() bool {
+	switch c {
+	case FailureCategoryClient, FailureCategoryProvider, FailureCategorySystem, FailureCategoryFraud:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTransient returns true for categories whose failures may succeed on retry.
+func (c FailureCategory) IsTransient() bool {
+	return c == FailureCategoryProvider || c == FailureCategorySystem
+}
+
+// FailureReasonOrphanedProcessing marks a transaction left in PROCESSING by
+// an instance that died mid-processing and failed by the recovery sweep.
+const FailureReasonOrphanedProcessing = "ORPHANED_PROCESSING"
+
+// FailureReasonUnspecified is set when a failure arrives with neither a
+// reason nor a provider error code. It keeps the provider fallback category.
+const FailureReasonUnspecified = "UNSPECIFIED"
+
+// failureMessageKeyPrefix prefixes the localization keys of reason codes.
+const failureMessageKeyPrefix = "transaction.failure."
+
+// failureReasonCategories maps the provider reason codes we know about.
+var failureReasonCategories = map[string]FailureCategory{
+	"INVALID_ACCOUNT":      FailureCategoryClient,
+	"ACCOUNT_CLOSED":       FailureCategoryClient,
+	"INSUFFICIENT_BALANCE": FailureCategoryClient,
+	"FRAUD_DETECTED":       FailureCategoryFraud,
+	"BLACKLISTED":          FailureCategoryFraud,
+	"TIMEOUT":              FailureCategoryProvider,
+
+	FailureReasonOrphanedProcessing: FailureCategorySystem,
+}
+
+// FailureCategoryForReason returns the category of a known provider reason
+// code, or fallback for free-text and unknown reasons.
+func FailureCategoryForReason(reason string, fallback FailureCategory) FailureCategory {
+	if category, ok := failureReasonCategories[reason]; ok {
+		return category
+	}
+	return fallback
+}
+
+// FailureMessageKey returns the localization key of a reason code, e.g.
+// "transaction.failure.unspecified", or "" for free-text reasons, which
+// clients show as is.
+func FailureMessageKey(reason string) string {
+	if reason != FailureReasonUnspecified {
+		if _, ok := failureReasonCategories[reason]; !ok {
+			return ""
+		}
+	}
+	return failureMessageKeyPrefix + strings.ToLower(reason)
+}
+
 // Transaction represents a financial transaction in the system.
 // This is an Entity with complex state machine and business rules.
 //
@@ -87,23 +211,40 @@ type Transaction struct {
 	idempotencyKey  string    // Unique key for idempotency (client-provided)
 	transactionType TransactionType
 	status          TransactionStatus
-	amount          valueobjects.Money
+	amount          valueobjects.Money // Gross: what the initiator requested
+
+	// Amount breakdown (see ApplyFee). Without a fee: fee is zero, net equals gross.
+	feeAmount valueobjects.Money // Sum of linked FEE transactions
+	netAmount valueobjects.Money // What the counterparty receives
 
 	// Optional fields depending on transaction type
-	destinationWalletID *uuid.UUID // For transfers
-	externalReference   string     // External system reference (e.g., Stripe payment ID)
-	description         string
-	metadata            map[string]interface{} // Flexible metadata (JSON)
+	destinationWalletID   *uuid.UUID // For transfers
+	externalReference     string     // External system reference (e.g., Stripe payment ID), sensitive parts masked
+	externalReferenceHash string     // SHA-256 of the reference as received, for exact-match lookup
+	description           string
+	metadata              map[string]interface{} // Flexible metadata (JSON)
 
 	// Failure information
-	failureReason string
-	retryCount    int // Number of retry attempts
+	failureReason   string
+	failureCategory FailureCategory // Empty unless FAILED
+	retryCount      int             // Number of retry attempts
+	nextRetryAt     *time.Time      // Earliest start of the next retry (see Retry), nil = immediately
+
+	// Jurisdiction is copied from the source wallet at creation time
+	jurisdiction string
+
+	// Build version that created the transaction; empty for transactions
+	// created before versions were recorded
+	createdByVersion string
 
 	// Timestamps
 	createdAt   time.Time
 	updatedAt   time.Time
 	processedAt *time.Time // When processing started
 	completedAt *time.Time // When finalized (completed/failed/cancelled)
+
+	// Receipt number, assigned once the transaction is COMPLETED (see AssignReceiptNumber)
+	receiptNumber string
 }
 
 // NewTransaction creates a new transaction.
@@ -142,14 +283,16 @@ func NewTransaction(
 		)
 	}
 
-	now := time.Now()
+	now := clock.Now()
 	return &Transaction{
-		id:              uuid.New(),
+		id:              ids.NewTransactionID(),
 		walletID:        walletID,
 		idempotencyKey:  idempotencyKey,
 		transactionType: transactionType,
 		status:          TransactionStatusPending,
 		amount:          amount,
+		feeAmount:       valueobjects.Zero(amount.Currency()),
+		netAmount:       amount,
 		description:     description,
 		metadata:        make(map[string]interface{}),
 		retryCount:      0,
@@ -159,21 +302,45 @@ func NewTransaction(
 }
 
 // ReconstructTransaction reconstructs a Transaction from stored data.
+// Timestamps are normalized to UTC.
+//
+// Type and status written by a later release are accepted as long as they
+// are well-formed (IsWellFormedEnum): the transaction can be read, listed and
+// exported, while its state transitions are rejected (see IsKnown).
 func ReconstructTransaction(
 	id, walletID uuid.UUID,
 	idempotencyKey string,
 	transactionType TransactionType,
 	status TransactionStatus,
-	amount valueobjects.Money,
+	amount, feeAmount, netAmount valueobjects.Money,
 	destinationWalletID *uuid.UUID,
 	externalReference string,
+	externalReferenceHash string,
 	description string,
 	metadataJSON []byte,
 	failureReason string,
+	failureCategory FailureCategory,
 	retryCount int,
+	nextRetryAt *time.Time,
+	jurisdiction string,
+	createdByVersion string,
 	createdAt, updatedAt time.Time,
 	processedAt, completedAt *time.Time,
+	receiptNumber string,
 ) (*Transaction, error) {
+	if !IsWellFormedEnum(string(transactionType)) {
+		return nil, errors.ValidationError{
+			Field:   "type",
+			Message: "malformed transaction type: " + strconv.Quote(string(transactionType)),
+		}
+	}
+	if !IsWellFormedEnum(string(status)) {
+		return nil, errors.ValidationError{
+			Field:   "status",
+			Message: "malformed transaction status: " + strconv.Quote(string(status)),
+		}
+	}
+
 	var metadata map[string]interface{}
 	if len(metadataJSON) > 0 {
 		if err := json.Unmarshal(metadataJSON, &metadata); err != nil {
@@ -184,25 +351,42 @@ func ReconstructTransaction(
 	}
 
 	return &Transaction{
-		id:                  id,
-		walletID:            walletID,
-		idempotencyKey:      idempotencyKey,
-		transactionType:     transactionType,
-		status:              status,
-		amount:              amount,
-		destinationWalletID: destinationWalletID,
-		externalReference:   externalReference,
-		description:         description,
-		metadata:            metadata,
-		failureReason:       failureReason,
-		retryCount:          retryCount,
-		createdAt:           createdAt,
-		updatedAt:           updatedAt,
-		processedAt:         processedAt,
-		completedAt:         completedAt,
+		id:                    id,
+		walletID:              walletID,
+		idempotencyKey:        idempotencyKey,
+		transactionType:       transactionType,
+		status:                status,
+		amount:                amount,
+		feeAmount:             feeAmount,
+		netAmount:             netAmount,
+		destinationWalletID:   destinationWalletID,
+		externalReference:     externalReference,
+		externalReferenceHash: externalReferenceHash,
+		description:           description,
+		metadata:              metadata,
+		failureReason:         failureReason,
+		failureCategory:       failureCategory,
+		retryCount:            retryCount,
+		nextRetryAt:           utcPtr(nextRetryAt),
+		jurisdiction:          jurisdiction,
+		createdByVersion:      createdByVersion,
+		createdAt:             createdAt.UTC(),
+		updatedAt:             updatedAt.UTC(),
+		processedAt:           utcPtr(processedAt),
+		completedAt:           utcPtr(completedAt),
+		receiptNumber:         receiptNumber,
 	}, nil
 }
 
+// utcPtr converts an optional timestamp to UTC.
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
 // Getters
 
 func (t *Transaction) ID() uuid.UUID {
@@ -229,6 +413,19 @@ func (t *Transaction) Amount() valueobjects.Money {
 	return t.amount
 }
 
+// GrossAmount is the amount the initiator requested (same as Amount).
+func (t *Transaction) GrossAmount() valueobjects.Money {
+	return t.amount
+}
+
+func (t *Transaction) FeeAmount() valueobjects.Money {
+	return t.feeAmount
+}
+
+func (t *Transaction) NetAmount() valueobjects.Money {
+	return t.netAmount
+}
+
 func (t *Transaction) DestinationWalletID() *uuid.UUID {
 	return t.destinationWalletID
 }
@@ -237,6 +434,11 @@ func (t *Transaction) ExternalReference() string {
 	return t.externalReference
 }
 
+// ExternalReferenceHash returns the SHA-256 of the external reference as received ("" when unset).
+func (t *Transaction) ExternalReferenceHash() string {
+	return t.externalReferenceHash
+}
+
 func (t *Transaction) Description() string {
 	return t.description
 }
@@ -249,10 +451,28 @@ func (t *Transaction) FailureReason() string {
 	return t.failureReason
 }
 
+// FailureCategory returns who caused the failure (empty unless FAILED).
+func (t *Transaction) FailureCategory() FailureCategory {
+	return t.failureCategory
+}
+
 func (t *Transaction) RetryCount() int {
 	return t.retryCount
 }
 
+// NextRetryAt returns the earliest time the next retry may start (nil = immediately).
+func (t *Transaction) NextRetryAt() *time.Time {
+	return t.nextRetryAt
+}
+
+func (t *Transaction) Jurisdiction() string {
+	return t.jurisdiction
+}
+
+func (t *Transaction) CreatedByVersion() string {
+	return t.createdByVersion
+}
+
 func (t *Transaction) CreatedAt() time.Time {
 	return t.createdAt
 }
@@ -269,6 +489,40 @@ func (t *Transaction) CompletedAt() *time.Time {
 	return t.completedAt
 }
 
+// ReceiptNumber returns the receipt number; empty unless the transaction is COMPLETED.
+func (t *Transaction) ReceiptNumber() string {
+	return t.receiptNumber
+}
+
+// ReceiptVerificationHash returns the hash printed on the receipt (see
+// valueobjects.ReceiptVerificationHash); empty without a receipt number.
+// The completion time is taken to the second, so the hash survives storage.
+func (t *Transaction) ReceiptVerificationHash() string {
+	if t.receiptNumber == "" {
+		return ""
+	}
+
+	destination, completedAt := "", ""
+	if t.destinationWalletID != nil {
+		destination = t.destinationWalletID.String()
+	}
+	if t.completedAt != nil {
+		completedAt = t.completedAt.UTC().Truncate(time.Second).Format(time.RFC3339)
+	}
+
+	return valueobjects.ReceiptVerificationHash(
+		t.receiptNumber,
+		t.id.String(),
+		t.walletID.String(),
+		destination,
+		string(t.transactionType),
+		strconv.FormatInt(t.amount.Cents(), 10),
+		strconv.FormatInt(t.netAmount.Cents(), 10),
+		t.amount.Currency().Code(),
+		completedAt,
+	)
+}
+
 // Business Methods
 
 // IsPending returns true if the transaction is in pending state.
@@ -311,42 +565,164 @@ func (t *Transaction) SetDestinationWallet(walletID uuid.UUID) error {
 	}
 
 	t.destinationWalletID = &walletID
-	t.updatedAt = time.Now()
+	t.updatedAt = touch(t.updatedAt)
 	return nil
 }
 
 // SetExternalReference sets an external system reference.
+// Card numbers and IBANs inside the reference are masked before it is stored
+// (see valueobjects.RedactSensitiveData); the hash of the original value keeps
+// exact-match lookup working.
 func (t *Transaction) SetExternalReference(reference string) error {
 	if t.IsFinal() {
 		return errors.ErrTransactionAlreadyProcessed
 	}
 
-	t.externalReference = reference
-	t.updatedAt = time.Now()
+	t.externalReference, _ = valueobjects.RedactSensitiveData(reference)
+	t.externalReferenceHash = ""
+	if reference != "" {
+		t.externalReferenceHash = valueobjects.HashExternalReference(reference)
+	}
+	t.updatedAt = touch(t.updatedAt)
+	return nil
+}
+
+// AssignJurisdiction tags the transaction with the source wallet's jurisdiction.
+// Business rule: the tag is set once at creation and cannot be changed afterwards.
+func (t *Transaction) AssignJurisdiction(code string) error {
+	if code == "" {
+		return nil // untagged: resolved to the configured fallback at retention time
+	}
+
+	code, err := NormalizeJurisdiction(code)
+	if err != nil {
+		return err
+	}
+
+	if t.jurisdiction != "" && t.jurisdiction != code {
+		return errors.NewBusinessRuleViolation(
+			"JURISDICTION_ALREADY_ASSIGNED",
+			"transaction jurisdiction cannot be changed",
+			map[string]interface{}{"jurisdiction": t.jurisdiction},
+		)
+	}
+
+	t.jurisdiction = code
+	return nil
+}
+
+// StampCreatedByVersion records the build version that created the transaction.
+// Business rule: like the jurisdiction tag, it is set once at creation.
+func (t *Transaction) StampCreatedByVersion(version string) error {
+	if version == "" {
+		return nil // unknown build: stored as NULL
+	}
+
+	if t.createdByVersion != "" && t.createdByVersion != version {
+		return errors.NewBusinessRuleViolation(
+			"CREATED_BY_VERSION_ALREADY_SET",
+			"transaction build version cannot be changed",
+			map[string]interface{}{"created_by_version": t.createdByVersion},
+		)
+	}
+
+	t.createdByVersion = version
+	return nil
+}
+
+// AssignReceiptNumber records the receipt number of a completed transaction.
+// Business rule: only COMPLETED transactions have a receipt, and like the
+// build version it is set once; assigning the same number again is a no-op.
+func (t *Transaction) AssignReceiptNumber(number string) error {
+	if t.status != TransactionStatusCompleted {
+		return errors.NewBusinessRuleViolation(
+			"RECEIPT_REQUIRES_COMPLETED_TRANSACTION",
+			"only completed transactions have a receipt number",
+			map[string]interface{}{"currentStatus": t.status},
+		)
+	}
+
+	if t.receiptNumber != "" && t.receiptNumber != number {
+		return errors.NewBusinessRuleViolation(
+			"RECEIPT_NUMBER_ALREADY_SET",
+			"transaction receipt number cannot be changed",
+			map[string]interface{}{"receipt_number": t.receiptNumber},
+		)
+	}
+
+	t.receiptNumber = number
 	return nil
 }
 
 // AddMetadata adds custom metadata to the transaction.
+// Card numbers and IBANs in string values (including nested ones) are masked.
 func (t *Transaction) AddMetadata(key string, value interface{}) error {
 	if t.IsFinal() {
 		return errors.ErrTransactionAlreadyProcessed
 	}
 
-	t.metadata[key] = value
-	t.updatedAt = time.Now()
+	t.metadata[key] = redactMetadataValue(value)
+	t.updatedAt = touch(t.updatedAt)
 	return nil
 }
 
+// redactMetadataValue masks sensitive identifiers in string metadata values.
+func redactMetadataValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		redacted, _ := valueobjects.RedactSensitiveData(v)
+		return redacted
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = redactMetadataValue(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = redactMetadataValue(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
 // State Machine Transitions
+//
+// Each method checks TransactionTransitions before changing the status and
+// keeps its own domain error for a disallowed transition.
+
+// canTransition reports whether TransactionTransitions allows moving to status.
+func (t *Transaction) canTransition(status TransactionStatus) bool {
+	return TransactionTransitions.CanTransition(t.status, status)
+}
+
+// requireKnown rejects state transitions of a transaction whose type or
+// status this build does not know: the rules for it live in a later release.
+func (t *Transaction) requireKnown() error {
+	if t.transactionType.IsKnown() && t.status.IsKnown() {
+		return nil
+	}
+	return errors.NewBusinessRuleViolation(
+		"UNSUPPORTED_TRANSACTION",
+		"transaction type or status is not supported by this version",
+		map[string]interface{}{"type": t.transactionType, "status": t.status},
+	)
+}
 
 // StartProcessing transitions the transaction to PROCESSING status.
 // Business rule: Can only process PENDING transactions.
 func (t *Transaction) StartProcessing() error {
-	if !t.IsPending() {
+	if err := t.requireKnown(); err != nil {
+		return err
+	}
+	if !t.canTransition(TransactionStatusProcessing) {
 		return errors.ErrTransactionNotPending
 	}
 
-	now := time.Now()
+	now := touch(t.updatedAt)
 	t.status = TransactionStatusProcessing
 	t.processedAt = &now
 	t.updatedAt = now
@@ -356,7 +732,10 @@ func (t *Transaction) StartProcessing() error {
 // MarkCompleted transitions the transaction to COMPLETED status.
 // Business rule: Can only complete PROCESSING transactions.
 func (t *Transaction) MarkCompleted() error {
-	if !t.IsProcessing() {
+	if err := t.requireKnown(); err != nil {
+		return err
+	}
+	if !t.canTransition(TransactionStatusCompleted) {
 		return errors.NewBusinessRuleViolation(
 			"CANNOT_COMPLETE_NON_PROCESSING_TRANSACTION",
 			"only processing transactions can be completed",
@@ -364,23 +743,33 @@ func (t *Transaction) MarkCompleted() error {
 		)
 	}
 
-	now := time.Now()
+	now := touch(t.updatedAt)
 	t.status = TransactionStatusCompleted
 	t.completedAt = &now
 	t.updatedAt = now
 	return nil
 }
 
-// MarkFailed transitions the transaction to FAILED status with reason.
+// MarkFailed transitions the transaction to FAILED status with reason and category.
 // Business rule: Can fail from PENDING or PROCESSING states.
-func (t *Transaction) MarkFailed(reason string) error {
-	if t.IsFinal() {
+func (t *Transaction) MarkFailed(reason string, category FailureCategory) error {
+	if err := t.requireKnown(); err != nil {
+		return err
+	}
+	if !t.canTransition(TransactionStatusFailed) {
 		return errors.ErrTransactionAlreadyProcessed
 	}
+	if !category.IsValid() {
+		return errors.ValidationError{
+			Field:   "failureCategory",
+			Message: "invalid failure category: " + string(category),
+		}
+	}
 
-	now := time.Now()
+	now := touch(t.updatedAt)
 	t.status = TransactionStatusFailed
 	t.failureReason = reason
+	t.failureCategory = category
 	t.completedAt = &now
 	t.updatedAt = now
 	return nil
@@ -389,7 +778,10 @@ func (t *Transaction) MarkFailed(reason string) error {
 // Cancel transitions the transaction to CANCELLED status.
 // Business rule: Can only cancel PENDING transactions.
 func (t *Transaction) Cancel() error {
-	if !t.IsPending() {
+	if err := t.requireKnown(); err != nil {
+		return err
+	}
+	if !t.canTransition(TransactionStatusCancelled) {
 		return errors.NewBusinessRuleViolation(
 			"CANNOT_CANCEL_NON_PENDING_TRANSACTION",
 			"only pending transactions can be cancelled",
@@ -397,17 +789,48 @@ func (t *Transaction) Cancel() error {
 		)
 	}
 
-	now := time.Now()
+	now := touch(t.updatedAt)
 	t.status = TransactionStatusCancelled
 	t.completedAt = &now
 	t.updatedAt = now
 	return nil
 }
 
+// DefaultMaxRetries is the retry limit applied by the retry use case.
+const DefaultMaxRetries = 3
+
+// retryBackoff is the wait before the next retry, indexed by retryCount-1.
+// Attempts beyond the schedule wait for the last (capped) interval.
+var retryBackoff = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+}
+
+// RetryBackoff returns the wait before the retry that follows attempt number retryCount.
+func RetryBackoff(retryCount int) time.Duration {
+	if retryCount <= 0 {
+		return 0
+	}
+	if retryCount > len(retryBackoff) {
+		return retryBackoff[len(retryBackoff)-1]
+	}
+	return retryBackoff[retryCount-1]
+}
+
 // Retry attempts to retry a failed transaction.
 // Business rule: Only FAILED transactions can be retried, with max retry limit.
+//
+// The transaction returns to PENDING and nextRetryAt is pushed out by
+// RetryBackoff(retryCount): should this attempt fail as well, the next retry
+// is not due before then (see IsRetryDue). Retry itself does not check the
+// schedule, so a manual retry can always override it.
 func (t *Transaction) Retry(maxRetries int) error {
-	if !t.IsFailed() {
+	if err := t.requireKnown(); err != nil {
+		return err
+	}
+	if !t.canTransition(TransactionStatusPending) {
 		return errors.NewBusinessRuleViolation(
 			"CANNOT_RETRY_NON_FAILED_TRANSACTION",
 			"only failed transactions can be retried",
@@ -426,36 +849,36 @@ func (t *Transaction) Retry(maxRetries int) error {
 		)
 	}
 
+	now := touch(t.updatedAt)
 	t.status = TransactionStatusPending
 	t.retryCount++
+	nextRetryAt := now.Add(RetryBackoff(t.retryCount))
+	t.nextRetryAt = &nextRetryAt
 	t.failureReason = ""
+	t.failureCategory = ""
 	t.completedAt = nil
-	t.updatedAt = time.Now()
+	t.updatedAt = now
 	return nil
 }
 
+// IsRetryDue reports whether the backoff before the next retry has passed at now.
+func (t *Transaction) IsRetryDue(now time.Time) bool {
+	return t.nextRetryAt == nil || !now.Before(*t.nextRetryAt)
+}
+
 // CanRetry checks if the transaction can be retried.
 func (t *Transaction) CanRetry(maxRetries int) bool {
 	return t.IsFailed() && t.retryCount < maxRetries
 }
 
-// IsRetryable returns true if the failure reason indicates the transaction can be retried.
-// Business logic: Some errors are permanent (invalid account), others are transient (network timeout).
+// IsRetryable returns true if the failure is transient and may succeed on retry.
+// Business logic: CLIENT and FRAUD failures are permanent; PROVIDER and SYSTEM
+// failures are transient unless the reason is a known permanent code.
+// Transactions failed before categories were recorded fall back to the reason.
 func (t *Transaction) IsRetryable() bool {
-	// List of non-retryable failure reasons
-	nonRetryableReasons := []string{
-		"INVALID_ACCOUNT",
-		"ACCOUNT_CLOSED",
-		"INSUFFICIENT_BALANCE",
-		"FRAUD_DETECTED",
-		"BLACKLISTED",
-	}
-
-	for _, reason := range nonRetryableReasons {
-		if t.failureReason == reason {
-			return false
-		}
+	category := t.failureCategory
+	if category == "" {
+		category = FailureCategoryProvider
 	}
-
-	return true
+	return category.IsTransient() && FailureCategoryForReason(t.failureReason, category).IsTransient()
 }
@@ -0,0 +1,188 @@
+// Package entities - TransactionImport brings historical transactions from a legacy ledger.
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/google/uuid"
+)
+
+// Metadata of imported transactions.
+const (
+	MetadataSource = "source" // Where the transaction was booked
+	SourceLegacy   = "legacy" // Imported from the legacy ledger
+)
+
+// TransactionImport is a historical transaction exactly as the legacy ledger
+// booked it: explicit ID, final status and original timestamps.
+type TransactionImport struct {
+	ID                  uuid.UUID
+	WalletID            uuid.UUID
+	IdempotencyKey      string // Empty: "legacy-<id>"
+	Type                TransactionType
+	Status              TransactionStatus // COMPLETED, FAILED or CANCELLED
+	CurrencyCode        string
+	AmountCents         int64 // Gross amount
+	FeeCents            int64
+	NetCents            int64 // Zero: amount - fee
+	DestinationWalletID *uuid.UUID
+	ExternalReference   string
+	Description         string
+	Metadata            map[string]interface{}
+	FailureReason       string
+	Jurisdiction        string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time  // Zero: completed_at or created_at
+	CompletedAt         *time.Time // Required for COMPLETED
+}
+
+// ReconstructForImport builds a transaction from a legacy record without
+// going through the state machine: the record is already final and its
+// balance effect is part of the separately imported wallet balances.
+//
+// Unlike ReconstructTransaction, the record is validated:
+//   - type and status are known, status is final
+//   - currency is known, amount is positive, fee is not negative and
+//     net equals amount or amount - fee
+//   - TRANSFER and EXCHANGE have a destination other than the source wallet
+//   - created_at is set, COMPLETED has completed_at not before created_at
+//
+// The transaction is tagged with metadata source=legacy.
+func ReconstructForImport(record TransactionImport) (*Transaction, error) {
+	if record.ID == uuid.Nil {
+		return nil, importError(record, "id", errors.ValidationCodeRequired, "id is required")
+	}
+	if record.WalletID == uuid.Nil {
+		return nil, importError(record, "wallet_id", errors.ValidationCodeRequired, "wallet id is required")
+	}
+	if !record.Type.IsValid() {
+		return nil, importError(record, "type", errors.ValidationCodeInvalidValue, fmt.Sprintf("unknown type %q", record.Type))
+	}
+	if !record.Status.IsValid() || !record.Status.IsFinal() {
+		return nil, importError(record, "status", errors.ValidationCodeInvalidValue,
+			fmt.Sprintf("status %q is not a final status", record.Status))
+	}
+
+	currency, err := valueobjects.NewCurrency(record.CurrencyCode)
+	if err != nil {
+		return nil, importError(record, "currency_code", errors.ValidationCodeInvalidValue,
+			fmt.Sprintf("unknown currency %q", record.CurrencyCode))
+	}
+	amount, fee, net, err := importAmounts(record, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	needsDestination := record.Type == TransactionTypeTransfer || record.Type == TransactionTypeExchange
+	switch {
+	case needsDestination && record.DestinationWalletID == nil:
+		return nil, importError(record, "destination_wallet_id", errors.ValidationCodeRequired,
+			fmt.Sprintf("%s requires a destination wallet", record.Type))
+	case record.DestinationWalletID != nil && *record.DestinationWalletID == record.WalletID:
+		return nil, importError(record, "destination_wallet_id", errors.ValidationCodeInvalidValue,
+			"destination wallet must differ from the source wallet")
+	}
+
+	if record.CreatedAt.IsZero() {
+		return nil, importError(record, "created_at", errors.ValidationCodeRequired, "created_at is required")
+	}
+	completedAt := record.CompletedAt
+	if record.Status == TransactionStatusCompleted && completedAt == nil {
+		return nil, importError(record, "completed_at", errors.ValidationCodeRequired, "completed transaction requires completed_at")
+	}
+	if completedAt != nil && completedAt.Before(record.CreatedAt) {
+		return nil, importError(record, "completed_at", errors.ValidationCodeOutOfRange, "completed_at is before created_at")
+	}
+
+	updatedAt := record.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = record.CreatedAt
+		if completedAt != nil {
+			updatedAt = *completedAt
+		}
+	}
+
+	idempotencyKey := record.IdempotencyKey
+	if idempotencyKey == "" {
+		idempotencyKey = "legacy-" + record.ID.String()
+	}
+
+	var failureCategory FailureCategory
+	if record.Status == TransactionStatusFailed {
+		failureCategory = FailureCategoryForReason(record.FailureReason, FailureCategoryProvider)
+	}
+
+	extRef, extRefHash := record.ExternalReference, ""
+	if extRef != "" {
+		extRefHash = valueobjects.HashExternalReference(extRef)
+		extRef, _ = valueobjects.RedactSensitiveData(extRef)
+	}
+
+	metadata := make(map[string]interface{}, len(record.Metadata)+1)
+	for key, value := range record.Metadata {
+		metadata[key] = value
+	}
+	metadata[MetadataSource] = SourceLegacy
+
+	return &Transaction{
+		id:                    record.ID,
+		walletID:              record.WalletID,
+		idempotencyKey:        idempotencyKey,
+		transactionType:       record.Type,
+		status:                record.Status,
+		amount:                amount,
+		feeAmount:             fee,
+		netAmount:             net,
+		destinationWalletID:   record.DestinationWalletID,
+		externalReference:     extRef,
+		externalReferenceHash: extRefHash,
+		description:           record.Description,
+		metadata:              metadata,
+		failureReason:         record.FailureReason,
+		failureCategory:       failureCategory,
+		jurisdiction:          record.Jurisdiction,
+		createdAt:             record.CreatedAt.UTC(),
+		updatedAt:             updatedAt.UTC(),
+		processedAt:           utcPtr(completedAt),
+		completedAt:           utcPtr(completedAt),
+	}, nil
+}
+
+// importAmounts validates the gross/fee/net breakdown of a legacy record.
+func importAmounts(record TransactionImport, currency valueobjects.Currency) (amount, fee, net valueobjects.Money, err error) {
+	if record.AmountCents <= 0 {
+		return amount, fee, net, importError(record, "amount_cents", errors.ValidationCodeOutOfRange, "amount must be positive")
+	}
+	if record.FeeCents < 0 {
+		return amount, fee, net, importError(record, "fee_cents", errors.ValidationCodeOutOfRange, "fee must not be negative")
+	}
+	netCents := record.NetCents
+	if netCents == 0 {
+		netCents = record.AmountCents - record.FeeCents
+	}
+	if netCents <= 0 || (netCents != record.AmountCents && netCents+record.FeeCents != record.AmountCents) {
+		return amount, fee, net, importError(record, "net_cents", errors.ValidationCodeInvalidValue,
+			"net must equal amount or amount - fee")
+	}
+
+	if amount, err = valueobjects.NewMoneyFromCents(record.AmountCents, currency); err != nil {
+		return amount, fee, net, err
+	}
+	if fee, err = valueobjects.NewMoneyFromCents(record.FeeCents, currency); err != nil {
+		return amount, fee, net, err
+	}
+	net, err = valueobjects.NewMoneyFromCents(netCents, currency)
+	return amount, fee, net, err
+}
+
+// importError reports an invalid legacy record field.
+func importError(record TransactionImport, field, code, message string) error {
+	return errors.ValidationError{
+		Field:   field,
+		Code:    code,
+		Message: fmt.Sprintf("transaction %s: %s", record.ID, message),
+	}
+}
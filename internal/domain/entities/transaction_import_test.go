@@ -0,0 +1,118 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/google/uuid"
+)
+
+func newTestImport() TransactionImport {
+	createdAt := time.Date(2017, 11, 2, 8, 0, 0, 0, time.FixedZone("MSK", 3*3600))
+	completedAt := createdAt.Add(2 * time.Minute)
+	return TransactionImport{
+		ID:           uuid.New(),
+		WalletID:     uuid.New(),
+		Type:         TransactionTypeWithdraw,
+		Status:       TransactionStatusCompleted,
+		CurrencyCode: "USD",
+		AmountCents:  1000,
+		FeeCents:     30,
+		Metadata:     map[string]interface{}{"legacy_id": "W-1"},
+		CreatedAt:    createdAt,
+		CompletedAt:  &completedAt,
+	}
+}
+
+func TestReconstructForImport(t *testing.T) {
+	record := newTestImport()
+
+	tx, err := ReconstructForImport(record)
+	if err != nil {
+		t.Fatalf("ReconstructForImport() error = %v", err)
+	}
+
+	if tx.ID() != record.ID || tx.Status() != TransactionStatusCompleted {
+		t.Errorf("id/status = %s/%s, want %s/COMPLETED", tx.ID(), tx.Status(), record.ID)
+	}
+	if tx.IdempotencyKey() != "legacy-"+record.ID.String() {
+		t.Errorf("IdempotencyKey() = %q, want legacy-<id>", tx.IdempotencyKey())
+	}
+	if tx.NetAmount().Cents() != 970 || tx.FeeAmount().Cents() != 30 {
+		t.Errorf("net/fee = %d/%d, want 970/30", tx.NetAmount().Cents(), tx.FeeAmount().Cents())
+	}
+	if !tx.CreatedAt().Equal(record.CreatedAt) || tx.CreatedAt().Location() != time.UTC {
+		t.Errorf("CreatedAt() = %v, want %v in UTC", tx.CreatedAt(), record.CreatedAt)
+	}
+	if !tx.UpdatedAt().Equal(*record.CompletedAt) {
+		t.Errorf("UpdatedAt() = %v, want completed_at %v", tx.UpdatedAt(), *record.CompletedAt)
+	}
+	if tx.Metadata()[MetadataSource] != SourceLegacy || tx.Metadata()["legacy_id"] != "W-1" {
+		t.Errorf("Metadata() = %v, want source=legacy and the original keys", tx.Metadata())
+	}
+	if _, tagged := record.Metadata[MetadataSource]; tagged {
+		t.Error("record metadata must not be modified")
+	}
+}
+
+func TestReconstructForImport_FailedCategory(t *testing.T) {
+	record := newTestImport()
+	record.Status = TransactionStatusFailed
+	record.CompletedAt = nil
+	record.FailureReason = "FRAUD_DETECTED"
+
+	tx, err := ReconstructForImport(record)
+	if err != nil {
+		t.Fatalf("ReconstructForImport() error = %v", err)
+	}
+	if tx.FailureCategory() != FailureCategoryFraud {
+		t.Errorf("FailureCategory() = %s, want FRAUD", tx.FailureCategory())
+	}
+	if !tx.UpdatedAt().Equal(record.CreatedAt) {
+		t.Errorf("UpdatedAt() = %v, want created_at", tx.UpdatedAt())
+	}
+}
+
+func TestReconstructForImport_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		field  string
+		modify func(r *TransactionImport)
+	}{
+		{"Unknown type", "type", func(r *TransactionImport) { r.Type = "BONUS" }},
+		{"Pending status", "status", func(r *TransactionImport) { r.Status = TransactionStatusPending }},
+		{"Unknown currency", "currency_code", func(r *TransactionImport) { r.CurrencyCode = "XYZ" }},
+		{"Zero amount", "amount_cents", func(r *TransactionImport) { r.AmountCents = 0 }},
+		{"Negative fee", "fee_cents", func(r *TransactionImport) { r.FeeCents = -1 }},
+		{"Fee eats amount", "net_cents", func(r *TransactionImport) { r.FeeCents = 1000 }},
+		{"Broken breakdown", "net_cents", func(r *TransactionImport) { r.NetCents = 900 }},
+		{"Transfer without destination", "destination_wallet_id", func(r *TransactionImport) { r.Type = TransactionTypeTransfer }},
+		{"Transfer to itself", "destination_wallet_id", func(r *TransactionImport) {
+			r.Type = TransactionTypeTransfer
+			r.DestinationWalletID = &r.WalletID
+		}},
+		{"No created_at", "created_at", func(r *TransactionImport) { r.CreatedAt = time.Time{} }},
+		{"Completed without completed_at", "completed_at", func(r *TransactionImport) { r.CompletedAt = nil }},
+		{"Completed before created", "completed_at", func(r *TransactionImport) {
+			before := r.CreatedAt.Add(-time.Second)
+			r.CompletedAt = &before
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := newTestImport()
+			tt.modify(&record)
+
+			_, err := ReconstructForImport(record)
+			valErr, ok := err.(errors.ValidationError)
+			if !ok {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if valErr.Field != tt.field {
+				t.Errorf("Field = %q, want %q", valErr.Field, tt.field)
+			}
+		})
+	}
+}
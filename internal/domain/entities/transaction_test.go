@@ -7,6 +7,7 @@ import (
 
 	"github.com/Haleralex/wallethub/internal/domain/errors"
 	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
 	"github.com/google/uuid"
 )
 
@@ -102,6 +103,9 @@ func TestNewTransaction_Success(t *testing.T) {
 	if tx.ID() == uuid.Nil {
 		t.Error("Transaction ID should not be nil")
 	}
+	if tx.ID().Version() != 7 {
+		t.Errorf("Transaction ID version = %d, want time-ordered v7", tx.ID().Version())
+	}
 
 	if tx.WalletID() != walletID {
 		t.Errorf("WalletID = %v, want %v", tx.WalletID(), walletID)
@@ -194,15 +198,21 @@ func TestReconstructTransaction(t *testing.T) {
 		idempotencyKey,
 		TransactionTypeTransfer,
 		TransactionStatusCompleted,
-		amount,
+		amount, valueobjects.Zero(valueobjects.USD), amount,
 		&destWalletID,
 		"ext-ref-123",
+		"",
 		"Test transfer",
 		metadataJSON,
 		"",
+		"",
 		2,
+		nil,
+		"",
+		"1.4.0+abc1234",
 		now, now,
 		&processedAt, &completedAt,
+		"",
 	)
 
 	if err != nil {
@@ -228,6 +238,10 @@ func TestReconstructTransaction(t *testing.T) {
 	if tx.Metadata()["source"] != "app" {
 		t.Errorf("Metadata not reconstructed correctly")
 	}
+
+	if tx.CreatedByVersion() != "1.4.0+abc1234" {
+		t.Errorf("CreatedByVersion = %v, want 1.4.0+abc1234", tx.CreatedByVersion())
+	}
 }
 
 // TestReconstructTransaction_InvalidMetadata tests reconstruction with bad metadata
@@ -243,15 +257,21 @@ func TestReconstructTransaction_InvalidMetadata(t *testing.T) {
 		"key-123",
 		TransactionTypeDeposit,
 		TransactionStatusPending,
-		amount,
+		amount, valueobjects.Zero(valueobjects.USD), amount,
 		nil,
 		"",
+		"",
 		"Test",
 		invalidJSON,
 		"",
+		"",
 		0,
+		nil,
+		"",
+		"",
 		now, now,
 		nil, nil,
+		"",
 	)
 
 	if err == nil {
@@ -259,6 +279,109 @@ func TestReconstructTransaction_InvalidMetadata(t *testing.T) {
 	}
 }
 
+// reconstructWithEnums reconstructs a minimal transaction with the given
+// stored type and status.
+func reconstructWithEnums(txType TransactionType, status TransactionStatus) (*Transaction, error) {
+	amount, _ := valueobjects.NewMoneyFromInt(100, valueobjects.USD)
+	now := time.Now()
+	return ReconstructTransaction(
+		uuid.New(), uuid.New(),
+		"key-123",
+		txType,
+		status,
+		amount, valueobjects.Zero(valueobjects.USD), amount,
+		nil, "", "", "Test", nil, "", "", 0, nil, "", "",
+		now, now,
+		nil, nil,
+		"",
+	)
+}
+
+// TestReconstructTransaction_UnknownEnums tests that values written by a later
+// release are readable but cannot change state, while malformed ones are rejected.
+func TestReconstructTransaction_UnknownEnums(t *testing.T) {
+	transitions := map[string]func(*Transaction) error{
+		"StartProcessing": (*Transaction).StartProcessing,
+		"MarkCompleted":   (*Transaction).MarkCompleted,
+		"Cancel":          (*Transaction).Cancel,
+		"MarkFailed": func(tx *Transaction) error {
+			return tx.MarkFailed("INVALID_ACCOUNT", FailureCategoryClient)
+		},
+		"Retry": func(tx *Transaction) error { return tx.Retry(DefaultMaxRetries) },
+	}
+
+	for _, tt := range []struct {
+		name   string
+		txType TransactionType
+		status TransactionStatus
+	}{
+		{"UnknownType", "CHARGEBACK", TransactionStatusPending},
+		{"UnknownStatus", TransactionTypeDeposit, "DISPUTED"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, transition := range transitions {
+				tx, err := reconstructWithEnums(tt.txType, tt.status)
+				if err != nil {
+					t.Fatalf("ReconstructTransaction() error = %v", err)
+				}
+				if tx.Type() != tt.txType || tx.Status() != tt.status {
+					t.Fatalf("Type/Status = %s/%s, want %s/%s", tx.Type(), tx.Status(), tt.txType, tt.status)
+				}
+
+				err = transition(tx)
+				violation, ok := err.(*errors.BusinessRuleViolation)
+				if !ok || violation.Rule != "UNSUPPORTED_TRANSACTION" {
+					t.Errorf("%s() error = %v, want UNSUPPORTED_TRANSACTION", name, err)
+				}
+				if tx.Status() != tt.status {
+					t.Errorf("%s() changed status to %s", name, tx.Status())
+				}
+			}
+		})
+	}
+
+	t.Run("Malformed", func(t *testing.T) {
+		if _, err := reconstructWithEnums("charge back", TransactionStatusPending); err == nil {
+			t.Error("ReconstructTransaction() with malformed type should return error")
+		}
+		if _, err := reconstructWithEnums(TransactionTypeDeposit, ""); err == nil {
+			t.Error("ReconstructTransaction() with empty status should return error")
+		}
+	})
+
+	t.Run("NewTransactionStaysStrict", func(t *testing.T) {
+		amount, _ := valueobjects.NewMoneyFromInt(100, valueobjects.USD)
+		if _, err := NewTransaction(uuid.New(), "key-123", "CHARGEBACK", amount, "Test"); err != errors.ErrInvalidTransactionType {
+			t.Errorf("NewTransaction() error = %v, want ErrInvalidTransactionType", err)
+		}
+	})
+}
+
+// TestIsWellFormedEnum tests the shape check applied to stored enum values
+func TestIsWellFormedEnum(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected bool
+	}{
+		{"DEPOSIT", true},
+		{"CHARGEBACK", true},
+		{"CARD_PAYMENT_V2", true},
+		{"", false},
+		{"deposit", false},
+		{"_DEPOSIT", false},
+		{"2FA", false},
+		{"CHARGE BACK", false},
+		{"CHARGE-BACK", false},
+		{"A23456789012345678901234567890123", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsWellFormedEnum(tt.raw); got != tt.expected {
+			t.Errorf("IsWellFormedEnum(%q) = %v, want %v", tt.raw, got, tt.expected)
+		}
+	}
+}
+
 // TestReconstructTransaction_EmptyMetadata tests reconstruction with no metadata
 func TestReconstructTransaction_EmptyMetadata(t *testing.T) {
 	id := uuid.New()
@@ -271,15 +394,21 @@ func TestReconstructTransaction_EmptyMetadata(t *testing.T) {
 		"key-123",
 		TransactionTypeDeposit,
 		TransactionStatusPending,
-		amount,
+		amount, valueobjects.Zero(valueobjects.USD), amount,
 		nil,
 		"",
+		"",
 		"Test",
 		nil,
 		"",
+		"",
 		0,
+		nil,
+		"",
+		"",
 		now, now,
 		nil, nil,
+		"",
 	)
 
 	if err != nil {
@@ -400,6 +529,34 @@ func TestTransaction_SetExternalReference(t *testing.T) {
 		}
 	})
 
+	t.Run("Card number is masked and hashed", func(t *testing.T) {
+		tx, _ := NewTransaction(walletID, "key-123", TransactionTypeDeposit, amount, "Deposit")
+		reference := "pi_4111111111111111"
+
+		if err := tx.SetExternalReference(reference); err != nil {
+			t.Fatalf("SetExternalReference() error = %v", err)
+		}
+
+		if tx.ExternalReference() != "pi_411111******1111" {
+			t.Errorf("ExternalReference = %v, want masked PAN", tx.ExternalReference())
+		}
+		if tx.ExternalReferenceHash() != valueobjects.HashExternalReference(reference) {
+			t.Errorf("ExternalReferenceHash = %v, want hash of original reference", tx.ExternalReferenceHash())
+		}
+	})
+
+	t.Run("Clearing reference clears hash", func(t *testing.T) {
+		tx, _ := NewTransaction(walletID, "key-123", TransactionTypeDeposit, amount, "Deposit")
+		_ = tx.SetExternalReference("stripe_123")
+
+		if err := tx.SetExternalReference(""); err != nil {
+			t.Fatalf("SetExternalReference() error = %v", err)
+		}
+		if tx.ExternalReference() != "" || tx.ExternalReferenceHash() != "" {
+			t.Errorf("Expected empty reference and hash, got %q %q", tx.ExternalReference(), tx.ExternalReferenceHash())
+		}
+	})
+
 	t.Run("Cannot set reference on final transaction", func(t *testing.T) {
 		tx, _ := NewTransaction(walletID, "key-123", TransactionTypeDeposit, amount, "Deposit")
 		tx.status = TransactionStatusCompleted
@@ -441,6 +598,28 @@ func TestTransaction_AddMetadata(t *testing.T) {
 		}
 	})
 
+	t.Run("Sensitive values are masked", func(t *testing.T) {
+		tx, _ := NewTransaction(walletID, "key-123", TransactionTypeDeposit, amount, "Deposit")
+
+		_ = tx.AddMetadata("card", "5555 5555 5555 4444")
+		_ = tx.AddMetadata("payer", map[string]interface{}{
+			"iban":   "DE89370400440532013000",
+			"orders": []interface{}{"1234567812345670", 42},
+		})
+
+		if tx.Metadata()["card"] != "555555******4444" {
+			t.Errorf("card = %v, want masked PAN", tx.Metadata()["card"])
+		}
+		payer := tx.Metadata()["payer"].(map[string]interface{})
+		if payer["iban"] != "DE89**************3000" {
+			t.Errorf("payer.iban = %v, want masked IBAN", payer["iban"])
+		}
+		orders := payer["orders"].([]interface{})
+		if orders[0] != "123456******5670" || orders[1] != 42 {
+			t.Errorf("payer.orders = %v, want masked PAN and untouched number", orders)
+		}
+	})
+
 	t.Run("Cannot add metadata to final transaction", func(t *testing.T) {
 		tx, _ := NewTransaction(walletID, "key-123", TransactionTypeDeposit, amount, "Deposit")
 		tx.status = TransactionStatusCompleted
@@ -527,7 +706,7 @@ func TestTransaction_MarkFailed(t *testing.T) {
 		tx, _ := NewTransaction(walletID, "key-123", TransactionTypeDeposit, amount, "Deposit")
 		reason := "Network timeout"
 
-		err := tx.MarkFailed(reason)
+		err := tx.MarkFailed(reason, FailureCategoryProvider)
 		if err != nil {
 			t.Fatalf("MarkFailed() error = %v", err)
 		}
@@ -540,6 +719,10 @@ func TestTransaction_MarkFailed(t *testing.T) {
 			t.Errorf("FailureReason = %v, want %v", tx.FailureReason(), reason)
 		}
 
+		if tx.FailureCategory() != FailureCategoryProvider {
+			t.Errorf("FailureCategory = %v, want %v", tx.FailureCategory(), FailureCategoryProvider)
+		}
+
 		if tx.CompletedAt() == nil {
 			t.Error("CompletedAt should be set")
 		}
@@ -549,7 +732,7 @@ func TestTransaction_MarkFailed(t *testing.T) {
 		tx, _ := NewTransaction(walletID, "key-123", TransactionTypeDeposit, amount, "Deposit")
 		_ = tx.StartProcessing()
 
-		err := tx.MarkFailed("Error occurred")
+		err := tx.MarkFailed("Error occurred", FailureCategoryProvider)
 		if err != nil {
 			t.Fatalf("MarkFailed() error = %v", err)
 		}
@@ -564,11 +747,23 @@ func TestTransaction_MarkFailed(t *testing.T) {
 		_ = tx.StartProcessing()
 		_ = tx.MarkCompleted()
 
-		err := tx.MarkFailed("Reason")
+		err := tx.MarkFailed("Reason", FailureCategoryProvider)
 		if err == nil {
 			t.Fatal("MarkFailed() on completed should return error")
 		}
 	})
+
+	t.Run("Invalid category", func(t *testing.T) {
+		tx, _ := NewTransaction(walletID, "key-123", TransactionTypeDeposit, amount, "Deposit")
+
+		err := tx.MarkFailed("Reason", FailureCategory("OTHER"))
+		if err == nil {
+			t.Fatal("MarkFailed() with invalid category should return error")
+		}
+		if tx.Status() != TransactionStatusPending {
+			t.Errorf("Status = %v, want %v", tx.Status(), TransactionStatusPending)
+		}
+	})
 }
 
 // TestTransaction_Cancel tests canceling transaction
@@ -624,7 +819,7 @@ func TestTransaction_Retry(t *testing.T) {
 	t.Run("Retry failed transaction", func(t *testing.T) {
 		tx, _ := NewTransaction(walletID, "key-123", TransactionTypeDeposit, amount, "Deposit")
 		_ = tx.StartProcessing()
-		_ = tx.MarkFailed("Network error")
+		_ = tx.MarkFailed("Network error", FailureCategoryProvider)
 
 		err := tx.Retry(maxRetries)
 		if err != nil {
@@ -643,6 +838,10 @@ func TestTransaction_Retry(t *testing.T) {
 			t.Error("FailureReason should be cleared")
 		}
 
+		if tx.FailureCategory() != "" {
+			t.Error("FailureCategory should be cleared")
+		}
+
 		if tx.CompletedAt() != nil {
 			t.Error("CompletedAt should be cleared")
 		}
@@ -672,7 +871,7 @@ func TestTransaction_Retry(t *testing.T) {
 		tx, _ := NewTransaction(walletID, "key-123", TransactionTypeDeposit, amount, "Deposit")
 
 		_ = tx.StartProcessing()
-		_ = tx.MarkFailed("Error 1")
+		_ = tx.MarkFailed("Error 1", FailureCategoryProvider)
 		_ = tx.Retry(maxRetries)
 
 		if tx.RetryCount() != 1 {
@@ -680,7 +879,7 @@ func TestTransaction_Retry(t *testing.T) {
 		}
 
 		_ = tx.StartProcessing()
-		_ = tx.MarkFailed("Error 2")
+		_ = tx.MarkFailed("Error 2", FailureCategoryProvider)
 		_ = tx.Retry(maxRetries)
 
 		if tx.RetryCount() != 2 {
@@ -689,6 +888,62 @@ func TestTransaction_Retry(t *testing.T) {
 	})
 }
 
+// TestRetryBackoff tests the exponential backoff schedule between retries
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		retryCount int
+		want       time.Duration
+	}{
+		{0, 0},
+		{1, time.Minute},
+		{2, 5 * time.Minute},
+		{3, 30 * time.Minute},
+		{4, 2 * time.Hour},
+		{10, 2 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := RetryBackoff(tt.retryCount); got != tt.want {
+			t.Errorf("RetryBackoff(%d) = %v, want %v", tt.retryCount, got, tt.want)
+		}
+	}
+}
+
+// TestTransaction_Retry_SchedulesNextRetry tests that Retry pushes nextRetryAt out by the backoff
+func TestTransaction_Retry_SchedulesNextRetry(t *testing.T) {
+	amount, _ := valueobjects.NewMoneyFromInt(100, valueobjects.USD)
+	tx, _ := NewTransaction(uuid.New(), "key-123", TransactionTypeDeposit, amount, "Deposit")
+	_ = tx.MarkFailed("TIMEOUT", FailureCategoryProvider)
+
+	if tx.NextRetryAt() != nil || !tx.IsRetryDue(time.Now()) {
+		t.Fatal("First retry should be due immediately")
+	}
+
+	for attempt, backoff := range []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute} {
+		before := time.Now().UTC()
+		if err := tx.Retry(5); err != nil {
+			t.Fatalf("Retry() #%d error = %v", attempt+1, err)
+		}
+
+		next := tx.NextRetryAt()
+		if next == nil {
+			t.Fatalf("NextRetryAt should be set after retry #%d", attempt+1)
+		}
+		if next.Before(before.Add(backoff)) || next.After(time.Now().UTC().Add(backoff)) {
+			t.Errorf("NextRetryAt after retry #%d = %v, want now + %v", attempt+1, next, backoff)
+		}
+		if tx.IsRetryDue(next.Add(-time.Second)) {
+			t.Errorf("Retry #%d should not be due before NextRetryAt", attempt+2)
+		}
+		if !tx.IsRetryDue(*next) {
+			t.Errorf("Retry #%d should be due at NextRetryAt", attempt+2)
+		}
+
+		_ = tx.StartProcessing()
+		_ = tx.MarkFailed("TIMEOUT", FailureCategoryProvider)
+	}
+}
+
 // TestTransaction_CanRetry tests retry permission check
 func TestTransaction_CanRetry(t *testing.T) {
 	walletID := uuid.New()
@@ -765,6 +1020,80 @@ func TestTransaction_IsRetryable(t *testing.T) {
 	}
 }
 
+// TestTransaction_IsRetryable_Category tests that retryability follows the
+// failure category, with known permanent reasons overriding transient ones.
+func TestTransaction_IsRetryable_Category(t *testing.T) {
+	walletID := uuid.New()
+	amount, _ := valueobjects.NewMoneyFromInt(100, valueobjects.USD)
+
+	tests := []struct {
+		name     string
+		reason   string
+		category FailureCategory
+		expected bool
+	}{
+		{"Provider outage is retryable", "gateway unavailable", FailureCategoryProvider, true},
+		{"System error is retryable", "database timeout", FailureCategorySystem, true},
+		{"Client failure not retryable", "card declined", FailureCategoryClient, false},
+		{"Fraud block not retryable", "velocity rule", FailureCategoryFraud, false},
+		{"Permanent reason overrides provider category", "ACCOUNT_CLOSED", FailureCategoryProvider, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, _ := NewTransaction(walletID, "key-123", TransactionTypeDeposit, amount, "Deposit")
+			if err := tx.MarkFailed(tt.reason, tt.category); err != nil {
+				t.Fatalf("MarkFailed() error = %v", err)
+			}
+
+			if got := tx.IsRetryable(); got != tt.expected {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestFailureCategoryForReason tests mapping of known provider reason codes
+func TestFailureCategoryForReason(t *testing.T) {
+	tests := []struct {
+		reason   string
+		expected FailureCategory
+	}{
+		{"INSUFFICIENT_BALANCE", FailureCategoryClient},
+		{"INVALID_ACCOUNT", FailureCategoryClient},
+		{"FRAUD_DETECTED", FailureCategoryFraud},
+		{"BLACKLISTED", FailureCategoryFraud},
+		{"TIMEOUT", FailureCategoryProvider},
+		{"something unexpected", FailureCategorySystem},
+	}
+
+	for _, tt := range tests {
+		if got := FailureCategoryForReason(tt.reason, FailureCategorySystem); got != tt.expected {
+			t.Errorf("FailureCategoryForReason(%q) = %v, want %v", tt.reason, got, tt.expected)
+		}
+	}
+}
+
+// TestFailureMessageKey tests localization keys of reason codes
+func TestFailureMessageKey(t *testing.T) {
+	tests := []struct {
+		reason   string
+		expected string
+	}{
+		{FailureReasonUnspecified, "transaction.failure.unspecified"},
+		{"INVALID_ACCOUNT", "transaction.failure.invalid_account"},
+		{FailureReasonOrphanedProcessing, "transaction.failure.orphaned_processing"},
+		{"card declined by issuer", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := FailureMessageKey(tt.reason); got != tt.expected {
+			t.Errorf("FailureMessageKey(%q) = %q, want %q", tt.reason, got, tt.expected)
+		}
+	}
+}
+
 // TestTransaction_Getters tests all getter methods
 func TestTransaction_Getters(t *testing.T) {
 	id := uuid.New()
@@ -786,15 +1115,21 @@ func TestTransaction_Getters(t *testing.T) {
 		idempotencyKey,
 		TransactionTypeTransfer,
 		TransactionStatusFailed,
-		amount,
+		amount, valueobjects.Zero(valueobjects.USD), amount,
 		&destWalletID,
 		externalRef,
+		"",
 		description,
 		metadataJSON,
 		failureReason,
+		FailureCategoryProvider,
 		2,
+		nil,
+		"",
+		"",
 		now, now,
 		&processedAt, &completedAt,
+		"",
 	)
 
 	if tx.ID() != id {
@@ -827,6 +1162,9 @@ func TestTransaction_Getters(t *testing.T) {
 	if tx.FailureReason() != failureReason {
 		t.Errorf("FailureReason() = %v, want %v", tx.FailureReason(), failureReason)
 	}
+	if tx.FailureCategory() != FailureCategoryProvider {
+		t.Errorf("FailureCategory() = %v, want %v", tx.FailureCategory(), FailureCategoryProvider)
+	}
 	if tx.RetryCount() != 2 {
 		t.Errorf("RetryCount() = %v, want 2", tx.RetryCount())
 	}
@@ -849,18 +1187,26 @@ func TestTransaction_Getters(t *testing.T) {
 
 // TestTransaction_UpdatedAtChanges tests that UpdatedAt changes on operations
 func TestTransaction_UpdatedAtChanges(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer clock.SetClock(fake)()
+
 	walletID := uuid.New()
 	amount, _ := valueobjects.NewMoneyFromInt(100, valueobjects.USD)
 	tx, _ := NewTransaction(walletID, "key-123", TransactionTypeDeposit, amount, "Test")
 
 	initialUpdatedAt := tx.UpdatedAt()
-	time.Sleep(10 * time.Millisecond)
 
+	// Same clock tick: UpdatedAt still strictly increases
 	_ = tx.AddMetadata("test", "value")
-
 	if !tx.UpdatedAt().After(initialUpdatedAt) {
 		t.Error("UpdatedAt should change after metadata addition")
 	}
+
+	fake.Advance(time.Second)
+	_ = tx.StartProcessing()
+	if !tx.UpdatedAt().Equal(fake.Now()) || tx.ProcessedAt() == nil || !tx.ProcessedAt().Equal(fake.Now()) {
+		t.Errorf("UpdatedAt = %v, ProcessedAt = %v, want %v", tx.UpdatedAt(), tx.ProcessedAt(), fake.Now())
+	}
 }
 
 // TestTransaction_FullLifecycle tests complete transaction lifecycle
@@ -907,3 +1253,63 @@ func TestTransaction_FullLifecycle(t *testing.T) {
 		t.Error("Should not be able to add metadata to completed transaction")
 	}
 }
+
+// TestTransaction_AssignJurisdiction tests that the retention tag is set once at creation
+func TestTransaction_AssignJurisdiction(t *testing.T) {
+	amount, _ := valueobjects.NewMoneyFromInt(10, valueobjects.MustNewCurrency("USD"))
+	tx, _ := NewTransaction(uuid.New(), "key-1", TransactionTypeDeposit, amount, "deposit")
+
+	if err := tx.AssignJurisdiction(""); err != nil {
+		t.Fatalf("AssignJurisdiction(\"\") error = %v", err)
+	}
+	if tx.Jurisdiction() != "" {
+		t.Errorf("Empty code should leave transaction untagged, got %v", tx.Jurisdiction())
+	}
+
+	if err := tx.AssignJurisdiction("de"); err != nil {
+		t.Fatalf("AssignJurisdiction() error = %v", err)
+	}
+	if tx.Jurisdiction() != "DE" {
+		t.Errorf("Jurisdiction = %v, want DE", tx.Jurisdiction())
+	}
+
+	err := tx.AssignJurisdiction("FR")
+	if !errors.IsBusinessRuleViolation(err) {
+		t.Errorf("Expected business rule violation, got %v", err)
+	}
+	if tx.Jurisdiction() != "DE" {
+		t.Errorf("Jurisdiction changed to %v after rejected re-tag", tx.Jurisdiction())
+	}
+}
+
+// TestTransaction_StampCreatedByVersion tests that the build version is set once at creation
+func TestTransaction_StampCreatedByVersion(t *testing.T) {
+	amount, _ := valueobjects.NewMoneyFromInt(10, valueobjects.MustNewCurrency("USD"))
+	tx, _ := NewTransaction(uuid.New(), "key-1", TransactionTypeDeposit, amount, "deposit")
+
+	if tx.CreatedByVersion() != "" {
+		t.Errorf("New transaction should have no version, got %v", tx.CreatedByVersion())
+	}
+
+	if err := tx.StampCreatedByVersion(""); err != nil {
+		t.Fatalf("StampCreatedByVersion(\"\") error = %v", err)
+	}
+	if tx.CreatedByVersion() != "" {
+		t.Errorf("Empty version should leave transaction unstamped, got %v", tx.CreatedByVersion())
+	}
+
+	if err := tx.StampCreatedByVersion("1.4.0+abc1234"); err != nil {
+		t.Fatalf("StampCreatedByVersion() error = %v", err)
+	}
+	if tx.CreatedByVersion() != "1.4.0+abc1234" {
+		t.Errorf("CreatedByVersion = %v, want 1.4.0+abc1234", tx.CreatedByVersion())
+	}
+
+	err := tx.StampCreatedByVersion("1.5.0+def5678")
+	if !errors.IsBusinessRuleViolation(err) {
+		t.Errorf("Expected business rule violation, got %v", err)
+	}
+	if tx.CreatedByVersion() != "1.4.0+abc1234" {
+		t.Errorf("CreatedByVersion changed to %v after rejected re-stamp", tx.CreatedByVersion())
+	}
+}
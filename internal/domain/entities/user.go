@@ -12,8 +12,11 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
 	"github.com/google/uuid"
 )
 
@@ -52,12 +55,51 @@ type User struct {
 	fullName   string
 	kycStatus  KYCStatus
 	telegramID *int64 // Telegram user ID (optional, for Mini App auth)
-	createdAt  time.Time
-	updatedAt  time.Time
+	// Jurisdiction is an ISO 3166-1 alpha-2 country code that drives
+	// data retention rules. Empty means "not assigned yet".
+	jurisdiction string
+	// lastKYCRejectionReason is the reason of the most recent KYC rejection.
+	// It is kept after a later approval so support can see why earlier attempts failed.
+	lastKYCRejectionReason string
+	// terms is the terms-of-service version the user has accepted.
+	terms     TermsAcceptance
+	createdAt time.Time
+	updatedAt time.Time
 }
 
-// Email validation regex (simplified - real systems use more complex validation)
-var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+// TermsAcceptance is the terms-of-service version accepted by a user.
+//
+// Versions are positive integers that only grow: publishing new terms
+// bumps the version and users have to accept it again.
+type TermsAcceptance struct {
+	Version    int        // 0 - never accepted
+	AcceptedAt *time.Time // nil - never accepted
+	// Grandfathered marks users that existed before acceptance was tracked.
+	// The application treats them as having accepted the configured
+	// grandfathered version until they accept explicitly.
+	Grandfathered bool
+}
+
+// MinKYCRejectionReasonLength is the minimum length of a KYC rejection reason.
+// Short placeholders like "bad" give the user nothing to act on.
+const MinKYCRejectionReasonLength = 10
+
+// Jurisdiction validation regex (ISO 3166-1 alpha-2, after normalization)
+var jurisdictionRegex = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// NormalizeJurisdiction validates an ISO 3166-1 alpha-2 country code
+// and returns it in canonical upper-case form.
+// Whether the code is allowed for this deployment is decided by the application layer.
+func NormalizeJurisdiction(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !jurisdictionRegex.MatchString(code) {
+		return "", errors.ValidationError{
+			Field:   "jurisdiction",
+			Message: "jurisdiction must be an ISO 3166-1 alpha-2 country code",
+		}
+	}
+	return code, nil
+}
 
 // NewUser creates a new User with validation.
 // Factory function ensures all User instances satisfy business invariants.
@@ -78,10 +120,10 @@ func NewUser(email, fullName string) (*User, error) {
 	// Generate new identity
 	id := uuid.New()
 
-	// Validate email
-	email = strings.ToLower(strings.TrimSpace(email))
-	if !emailRegex.MatchString(email) {
-		return nil, errors.ErrInvalidEmail
+	// Validate email (see valueobjects.ParseEmail for the rules)
+	email, err := valueobjects.ParseEmail(email)
+	if err != nil {
+		return nil, err
 	}
 
 	// Validate full name
@@ -93,7 +135,7 @@ func NewUser(email, fullName string) (*User, error) {
 		}
 	}
 
-	now := time.Now()
+	now := clock.Now()
 	return &User{
 		id:        id,
 		email:     email,
@@ -114,7 +156,7 @@ func NewTelegramUser(telegramID int64, fullName string) (*User, error) {
 
 	email := fmt.Sprintf("tg_%d@telegram.local", telegramID)
 
-	now := time.Now()
+	now := clock.Now()
 	return &User{
 		id:         uuid.New(),
 		email:      email,
@@ -128,16 +170,24 @@ func NewTelegramUser(telegramID int64, fullName string) (*User, error) {
 
 // ReconstructUser reconstructs a User from stored data (e.g., from database).
 // Used by repository layer to hydrate entities.
-// No validation - assumes data is already valid.
-func ReconstructUser(id uuid.UUID, email, fullName string, kycStatus KYCStatus, telegramID *int64, createdAt, updatedAt time.Time) *User {
+// No validation - assumes data is already valid. Timestamps are normalized to UTC.
+func ReconstructUser(id uuid.UUID, email, fullName string, kycStatus KYCStatus, telegramID *int64, jurisdiction, lastKYCRejectionReason string, terms TermsAcceptance, createdAt, updatedAt time.Time) *User {
+	if terms.AcceptedAt != nil {
+		acceptedAt := terms.AcceptedAt.UTC()
+		terms.AcceptedAt = &acceptedAt
+	}
+
 	return &User{
-		id:         id,
-		email:      email,
-		fullName:   fullName,
-		kycStatus:  kycStatus,
-		telegramID: telegramID,
-		createdAt:  createdAt,
-		updatedAt:  updatedAt,
+		id:                     id,
+		email:                  email,
+		fullName:               fullName,
+		kycStatus:              kycStatus,
+		telegramID:             telegramID,
+		jurisdiction:           jurisdiction,
+		lastKYCRejectionReason: lastKYCRejectionReason,
+		terms:                  terms,
+		createdAt:              createdAt.UTC(),
+		updatedAt:              updatedAt.UTC(),
 	}
 }
 
@@ -177,16 +227,21 @@ func (u *User) TelegramID() *int64 {
 	return u.telegramID
 }
 
+// Jurisdiction returns the user's current jurisdiction (empty if not assigned).
+func (u *User) Jurisdiction() string {
+	return u.jurisdiction
+}
+
 // UpdateEmail changes the user's email with validation.
 // Business method that encapsulates the business rule.
 func (u *User) UpdateEmail(newEmail string) error {
-	newEmail = strings.ToLower(strings.TrimSpace(newEmail))
-	if !emailRegex.MatchString(newEmail) {
-		return errors.ErrInvalidEmail
+	newEmail, err := valueobjects.ParseEmail(newEmail)
+	if err != nil {
+		return err
 	}
 
 	u.email = newEmail
-	u.updatedAt = time.Now()
+	u.updatedAt = touch(u.updatedAt)
 	return nil
 }
 
@@ -201,7 +256,21 @@ func (u *User) UpdateFullName(newName string) error {
 	}
 
 	u.fullName = newName
-	u.updatedAt = time.Now()
+	u.updatedAt = touch(u.updatedAt)
+	return nil
+}
+
+// ChangeJurisdiction moves the user to another jurisdiction.
+// Wallets and transactions created earlier keep the jurisdiction
+// they were tagged with - only new records pick up the new value.
+func (u *User) ChangeJurisdiction(code string) error {
+	code, err := NormalizeJurisdiction(code)
+	if err != nil {
+		return err
+	}
+
+	u.jurisdiction = code
+	u.updatedAt = touch(u.updatedAt)
 	return nil
 }
 
@@ -217,41 +286,136 @@ func (u *User) StartKYCVerification() error {
 	}
 
 	u.kycStatus = KYCStatusPending
-	u.updatedAt = time.Now()
+	u.updatedAt = touch(u.updatedAt)
 	return nil
 }
 
 // ApproveKYC marks the user as verified.
-// Business rule: Can only approve if PENDING.
+// Business rule: Can only approve if PENDING or REJECTED (re-review of resubmitted documents).
+// The last rejection reason is kept for support history.
 func (u *User) ApproveKYC() error {
-	if u.kycStatus != KYCStatusPending {
+	if err := u.ensureKYCReviewable(); err != nil {
+		return err
+	}
+
+	u.kycStatus = KYCStatusVerified
+	u.updatedAt = touch(u.updatedAt)
+	return nil
+}
+
+// RejectKYC marks the KYC verification as rejected.
+// Business rules:
+// - Can only reject if PENDING or REJECTED (re-review of resubmitted documents)
+// - Reason is required and must be at least MinKYCRejectionReasonLength characters
+func (u *User) RejectKYC(reason string) error {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) < MinKYCRejectionReasonLength {
+		return errors.ValidationError{
+			Field:   "reason",
+			Message: fmt.Sprintf("rejection reason must be at least %d characters", MinKYCRejectionReasonLength),
+		}
+	}
+
+	if err := u.ensureKYCReviewable(); err != nil {
+		return err
+	}
+
+	u.kycStatus = KYCStatusRejected
+	u.lastKYCRejectionReason = reason
+	u.updatedAt = touch(u.updatedAt)
+	return nil
+}
+
+// RevokeKYC withdraws a previous verification after a compliance re-review.
+// The user goes back to REJECTED, so the usual re-review applies afterwards.
+// Business rules:
+// - Can only revoke if VERIFIED
+// - Reason is required and must be at least MinKYCRejectionReasonLength characters
+func (u *User) RevokeKYC(reason string) error {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) < MinKYCRejectionReasonLength {
+		return errors.ValidationError{
+			Field:   "reason",
+			Message: fmt.Sprintf("revocation reason must be at least %d characters", MinKYCRejectionReasonLength),
+		}
+	}
+
+	if u.kycStatus != KYCStatusVerified {
 		return errors.NewBusinessRuleViolation(
-			"KYC_NOT_PENDING",
-			"KYC verification is not in pending state",
+			"KYC_NOT_VERIFIED",
+			"only verified KYC can be revoked",
 			map[string]interface{}{"currentStatus": u.kycStatus},
 		)
 	}
 
-	u.kycStatus = KYCStatusVerified
-	u.updatedAt = time.Now()
+	u.kycStatus = KYCStatusRejected
+	u.lastKYCRejectionReason = reason
+	u.updatedAt = touch(u.updatedAt)
 	return nil
 }
 
-// RejectKYC marks the KYC verification as rejected.
-func (u *User) RejectKYC() error {
-	if u.kycStatus != KYCStatusPending {
+// ensureKYCReviewable checks that a reviewer decision can be applied.
+func (u *User) ensureKYCReviewable() error {
+	if u.kycStatus != KYCStatusPending && u.kycStatus != KYCStatusRejected {
 		return errors.NewBusinessRuleViolation(
 			"KYC_NOT_PENDING",
 			"KYC verification is not in pending state",
 			map[string]interface{}{"currentStatus": u.kycStatus},
 		)
 	}
-
-	u.kycStatus = KYCStatusRejected
-	u.updatedAt = time.Now()
 	return nil
 }
 
+// LastKYCRejectionReason returns the reason of the most recent KYC rejection.
+// Empty if KYC has never been rejected.
+func (u *User) LastKYCRejectionReason() string {
+	return u.lastKYCRejectionReason
+}
+
+// TermsAcceptance returns the terms-of-service version the user has accepted.
+func (u *User) TermsAcceptance() TermsAcceptance {
+	terms := u.terms
+	if terms.AcceptedAt != nil {
+		acceptedAt := *terms.AcceptedAt
+		terms.AcceptedAt = &acceptedAt
+	}
+	return terms
+}
+
+// AcceptTerms records acceptance of terms-of-service version.
+// Business rules:
+//   - Version must be positive
+//   - Versions are monotonic: accepting an older version than the one
+//     already accepted is rejected (TERMS_VERSION_DOWNGRADE)
+//   - Accepting the already accepted version again changes nothing and returns false
+//
+// Returns true if the acceptance was recorded.
+func (u *User) AcceptTerms(version int) (bool, error) {
+	if version <= 0 {
+		return false, errors.ValidationError{
+			Field:   "version",
+			Message: "terms version must be positive",
+			Code:    errors.ValidationCodeOutOfRange,
+		}
+	}
+
+	if version < u.terms.Version {
+		return false, errors.NewBusinessRuleViolation(
+			"TERMS_VERSION_DOWNGRADE",
+			fmt.Sprintf("terms version %d is older than accepted version %d", version, u.terms.Version),
+			map[string]interface{}{"acceptedVersion": u.terms.Version, "version": version},
+		)
+	}
+	if version == u.terms.Version && !u.terms.Grandfathered {
+		return false, nil
+	}
+
+	now := touch(u.updatedAt)
+	u.terms = TermsAcceptance{Version: version, AcceptedAt: &now}
+	u.updatedAt = now
+	return true, nil
+}
+
 // IsVerified returns true if the user has completed KYC verification.
 // Convenience method for business rules that require verification.
 func (u *User) IsVerified() bool {
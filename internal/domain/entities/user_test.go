@@ -4,8 +4,12 @@ package entities_test
 
 import (
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
 )
 
 // TestNewUser_Success tests successful user creation.
@@ -43,6 +47,10 @@ func TestNewUser_InvalidEmail(t *testing.T) {
 		"@example.com",
 		"user@",
 		"user space@example.com",
+		"a@b",
+		"a@b@example.com",
+		"John <john@example.com>",
+		"a@example.com, b@example.com",
 	}
 
 	for _, email := range invalidEmails {
@@ -143,6 +151,155 @@ func TestUser_UpdateFullName_Whitespace(t *testing.T) {
 	}
 }
 
+// TestUser_ChangeJurisdiction tests jurisdiction assignment and normalization.
+func TestUser_ChangeJurisdiction(t *testing.T) {
+	user, _ := entities.NewUser("test@example.com", "John Doe")
+
+	if user.Jurisdiction() != "" {
+		t.Errorf("New user jurisdiction = %v, want empty", user.Jurisdiction())
+	}
+
+	if err := user.ChangeJurisdiction(" de "); err != nil {
+		t.Fatalf("ChangeJurisdiction() error = %v", err)
+	}
+	if user.Jurisdiction() != "DE" {
+		t.Errorf("Jurisdiction = %v, want DE", user.Jurisdiction())
+	}
+
+	for _, invalid := range []string{"", "DEU", "D1", "Д"} {
+		if err := user.ChangeJurisdiction(invalid); err == nil {
+			t.Errorf("ChangeJurisdiction(%q) expected error", invalid)
+		}
+	}
+
+	if user.Jurisdiction() != "DE" {
+		t.Error("Jurisdiction should not change on validation error")
+	}
+}
+
+// TestUser_KYCReview tests reviewer decisions and the last rejection reason.
+func TestUser_KYCReview(t *testing.T) {
+	user := entities.ReconstructUser(uuid.New(), "test@example.com", "John Doe",
+		entities.KYCStatusPending, nil, "", "", entities.TermsAcceptance{}, time.Now(), time.Now())
+
+	if err := user.RejectKYC("too short"); err == nil {
+		t.Error("RejectKYC() expected error for short reason")
+	}
+	if user.KYCStatus() != entities.KYCStatusPending {
+		t.Errorf("KYCStatus = %v, want PENDING after failed rejection", user.KYCStatus())
+	}
+
+	if err := user.RejectKYC("  Blurry passport photo  "); err != nil {
+		t.Fatalf("RejectKYC() error = %v", err)
+	}
+	if user.LastKYCRejectionReason() != "Blurry passport photo" {
+		t.Errorf("LastKYCRejectionReason = %q, want trimmed reason", user.LastKYCRejectionReason())
+	}
+
+	// Rejected application can be re-reviewed after resubmission
+	if err := user.RejectKYC("Selfie does not match"); err != nil {
+		t.Fatalf("second RejectKYC() error = %v", err)
+	}
+	if err := user.ApproveKYC(); err != nil {
+		t.Fatalf("ApproveKYC() error = %v", err)
+	}
+	if user.KYCStatus() != entities.KYCStatusVerified {
+		t.Errorf("KYCStatus = %v, want VERIFIED", user.KYCStatus())
+	}
+	if user.LastKYCRejectionReason() != "Selfie does not match" {
+		t.Errorf("LastKYCRejectionReason = %q, want kept after approval", user.LastKYCRejectionReason())
+	}
+
+	// Verified user is not reviewable
+	if err := user.ApproveKYC(); err == nil {
+		t.Error("ApproveKYC() expected error for verified user")
+	}
+	if err := user.RejectKYC("Document expired yesterday"); err == nil {
+		t.Error("RejectKYC() expected error for verified user")
+	}
+}
+
+// TestUser_RevokeKYC tests revocation of a verified KYC.
+func TestUser_RevokeKYC(t *testing.T) {
+	user := entities.ReconstructUser(uuid.New(), "test@example.com", "John Doe",
+		entities.KYCStatusVerified, nil, "", "", entities.TermsAcceptance{}, time.Now(), time.Now())
+
+	if err := user.RevokeKYC("too short"); err == nil {
+		t.Error("RevokeKYC() expected error for short reason")
+	}
+	if user.KYCStatus() != entities.KYCStatusVerified {
+		t.Errorf("KYCStatus = %v, want VERIFIED after failed revocation", user.KYCStatus())
+	}
+
+	if err := user.RevokeKYC("  Sanctions list match  "); err != nil {
+		t.Fatalf("RevokeKYC() error = %v", err)
+	}
+	if user.KYCStatus() != entities.KYCStatusRejected || user.IsVerified() {
+		t.Errorf("KYCStatus = %v, want REJECTED", user.KYCStatus())
+	}
+	if user.LastKYCRejectionReason() != "Sanctions list match" {
+		t.Errorf("LastKYCRejectionReason = %q, want trimmed reason", user.LastKYCRejectionReason())
+	}
+
+	// Only a verified user can be revoked; re-review goes through ApproveKYC
+	if err := user.RevokeKYC("Sanctions list match"); err == nil {
+		t.Error("RevokeKYC() expected error for rejected user")
+	}
+	if err := user.ApproveKYC(); err != nil {
+		t.Fatalf("ApproveKYC() after revocation error = %v", err)
+	}
+}
+
+// TestUser_AcceptTerms tests terms acceptance monotonicity and grandfathered users.
+func TestUser_AcceptTerms(t *testing.T) {
+	user, _ := entities.NewUser("test@example.com", "John Doe")
+
+	if terms := user.TermsAcceptance(); terms.Version != 0 || terms.AcceptedAt != nil || terms.Grandfathered {
+		t.Errorf("New user terms = %+v, want never accepted", terms)
+	}
+
+	if _, err := user.AcceptTerms(0); err == nil {
+		t.Error("AcceptTerms(0) expected error")
+	}
+
+	recorded, err := user.AcceptTerms(2)
+	if err != nil || !recorded {
+		t.Fatalf("AcceptTerms(2) = %v, %v, want recorded", recorded, err)
+	}
+	acceptedAt := user.TermsAcceptance().AcceptedAt
+	if user.TermsAcceptance().Version != 2 || acceptedAt == nil {
+		t.Errorf("terms = %+v, want version 2 with acceptance time", user.TermsAcceptance())
+	}
+
+	// Same version again is a no-op
+	recorded, err = user.AcceptTerms(2)
+	if err != nil || recorded {
+		t.Errorf("repeated AcceptTerms(2) = %v, %v, want no-op", recorded, err)
+	}
+	if !user.TermsAcceptance().AcceptedAt.Equal(*acceptedAt) {
+		t.Error("repeated acceptance should keep the original acceptance time")
+	}
+
+	// Older version is rejected
+	if _, err := user.AcceptTerms(1); err == nil {
+		t.Error("AcceptTerms(1) expected downgrade error")
+	}
+	if user.TermsAcceptance().Version != 2 {
+		t.Errorf("Version = %d, want 2 after rejected downgrade", user.TermsAcceptance().Version)
+	}
+
+	// Grandfathered user records the explicit acceptance even for the same version
+	legacy := entities.ReconstructUser(uuid.New(), "legacy@example.com", "Legacy User",
+		entities.KYCStatusVerified, nil, "", "", entities.TermsAcceptance{Grandfathered: true}, time.Now(), time.Now())
+	recorded, err = legacy.AcceptTerms(1)
+	if err != nil || !recorded {
+		t.Fatalf("grandfathered AcceptTerms(1) = %v, %v, want recorded", recorded, err)
+	}
+	if legacy.TermsAcceptance().Grandfathered {
+		t.Error("explicit acceptance should clear Grandfathered")
+	}
+}
+
 // TestNewUser_EmailNormalization tests email is normalized (lowercase, trimmed).
 func TestNewUser_EmailNormalization(t *testing.T) {
 	tests := []struct {
@@ -178,23 +335,32 @@ func TestUser_CreatedAt(t *testing.T) {
 
 // TestUser_UpdatedAt tests updated timestamp changes on mutations.
 func TestUser_UpdatedAt(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer clock.SetClock(fake)()
+
 	user, _ := entities.NewUser("test@example.com", "John Doe")
 
 	initialUpdatedAt := user.UpdatedAt()
 
-	// Small sleep to ensure time difference
-	// time.Sleep(time.Millisecond)
-
-	// Note: In production, you might want to use a time provider interface
-	// For now, we just check that UpdatedAt is set and not zero
-
 	if initialUpdatedAt.IsZero() {
 		t.Error("UpdatedAt should be set initially")
 	}
 
 	// UpdatedAt should match CreatedAt for new user
 	if !user.UpdatedAt().Equal(user.CreatedAt()) {
-		t.Log("UpdatedAt and CreatedAt may differ slightly in fast execution")
+		t.Errorf("UpdatedAt = %v, want CreatedAt %v", user.UpdatedAt(), user.CreatedAt())
+	}
+
+	// Mutations within the same clock tick still move UpdatedAt forward
+	_ = user.UpdateFullName("Jane Doe")
+	if !user.UpdatedAt().After(initialUpdatedAt) {
+		t.Error("UpdatedAt should change after UpdateFullName")
+	}
+
+	fake.Advance(time.Minute)
+	_ = user.UpdateEmail("jane@example.com")
+	if !user.UpdatedAt().Equal(fake.Now()) {
+		t.Errorf("UpdatedAt = %v, want clock time %v", user.UpdatedAt(), fake.Now())
 	}
 }
 
@@ -208,6 +374,9 @@ func TestReconstructUser(t *testing.T) {
 		user.FullName(),
 		user.KYCStatus(),
 		nil,
+		"DE",
+		"Blurry passport photo",
+		entities.TermsAcceptance{},
 		user.CreatedAt(),
 		user.UpdatedAt(),
 	)
@@ -221,5 +390,11 @@ func TestReconstructUser(t *testing.T) {
 	if reconstructed.KYCStatus() != user.KYCStatus() {
 		t.Error("KYC status mismatch after reconstruction")
 	}
+	if reconstructed.Jurisdiction() != "DE" {
+		t.Errorf("Jurisdiction = %v, want DE", reconstructed.Jurisdiction())
+	}
+	if reconstructed.LastKYCRejectionReason() != "Blurry passport photo" {
+		t.Errorf("LastKYCRejectionReason = %v, want Blurry passport photo", reconstructed.LastKYCRejectionReason())
+	}
 }
 
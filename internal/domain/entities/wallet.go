@@ -7,6 +7,8 @@ import (
 
 	"github.com/Haleralex/wallethub/internal/domain/errors"
 	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+	"github.com/Haleralex/wallethub/internal/pkg/statemachine"
 	"github.com/google/uuid"
 )
 
@@ -18,11 +20,17 @@ const (
 	WalletTypeCrypto WalletType = "CRYPTO" // Cryptocurrency wallet (BTC, ETH, etc.)
 )
 
-// IsValid checks if the wallet type is valid.
-func (t WalletType) IsValid() bool {
+// IsKnown reports whether this build knows the wallet type. Stored wallets
+// may carry types added by a later release (see ReconstructWallet).
+func (t WalletType) IsKnown() bool {
 	return t == WalletTypeFiat || t == WalletTypeCrypto
 }
 
+// IsValid checks if the wallet type is valid for new wallets.
+func (t WalletType) IsValid() bool {
+	return t.IsKnown()
+}
+
 // WalletStatus represents the operational status of a wallet.
 type WalletStatus string
 
@@ -33,8 +41,9 @@ const (
 	WalletStatusClosed    WalletStatus = "CLOSED"    // Permanently closed
 )
 
-// IsValid checks if the wallet status is valid.
-func (s WalletStatus) IsValid() bool {
+// IsKnown reports whether this build knows the wallet status. Unknown
+// statuses are readable but have no transitions in WalletTransitions.
+func (s WalletStatus) IsKnown() bool {
 	switch s {
 	case WalletStatusActive, WalletStatusSuspended, WalletStatusLocked, WalletStatusClosed:
 		return true
@@ -43,6 +52,21 @@ func (s WalletStatus) IsValid() bool {
 	}
 }
 
+// IsValid checks if the wallet status is valid for writing.
+func (s WalletStatus) IsValid() bool {
+	return s.IsKnown()
+}
+
+// WalletTransitions is the wallet state machine. Any open status may move to
+// any status, including itself (suspending a suspended wallet is a no-op
+// change); CLOSED is final.
+var WalletTransitions = statemachine.Table[WalletStatus]{
+	WalletStatusActive:    {WalletStatusActive, WalletStatusSuspended, WalletStatusLocked, WalletStatusClosed},
+	WalletStatusSuspended: {WalletStatusActive, WalletStatusSuspended, WalletStatusLocked, WalletStatusClosed},
+	WalletStatusLocked:    {WalletStatusActive, WalletStatusSuspended, WalletStatusLocked, WalletStatusClosed},
+	WalletStatusClosed:    {},
+}
+
 // Wallet represents a user's wallet for a specific currency.
 // A user can have multiple wallets (one per currency).
 //
@@ -71,6 +95,14 @@ type Wallet struct {
 	dailyLimit   valueobjects.Money // Max daily transaction volume
 	monthlyLimit valueobjects.Money // Max monthly transaction volume
 
+	// Jurisdiction is copied from the owner at creation time and never follows
+	// later moves of the user - retention is evaluated per record.
+	jurisdiction string
+
+	// maxPendingTransactions overrides the configured cap on simultaneously
+	// PENDING/PROCESSING transactions; nil means the default applies.
+	maxPendingTransactions *int
+
 	createdAt time.Time
 	updatedAt time.Time
 }
@@ -113,7 +145,7 @@ func NewWallet(userID uuid.UUID, currency valueobjects.Currency) (*Wallet, error
 		defaultLimit, _ = valueobjects.NewMoneyFromInt(100, currency) // 100 crypto units
 	}
 
-	now := time.Now()
+	now := clock.Now()
 	wallet := &Wallet{
 		id:         uuid.New(),
 		userID:     userID,
@@ -136,6 +168,9 @@ func NewWallet(userID uuid.UUID, currency valueobjects.Currency) (*Wallet, error
 
 // ReconstructWallet reconstructs a Wallet from stored data.
 // Used by repository to hydrate entities from database.
+// Timestamps are normalized to UTC. Type and status are carried through
+// as stored, including values unknown to this build (see WalletType.IsKnown);
+// repositories reject malformed values with IsWellFormedEnum before calling it.
 func ReconstructWallet(
 	id, userID uuid.UUID,
 	currency valueobjects.Currency,
@@ -144,6 +179,8 @@ func ReconstructWallet(
 	available, pending valueobjects.Money,
 	balanceVersion int64,
 	dailyLimit, monthlyLimit valueobjects.Money,
+	jurisdiction string,
+	maxPendingTransactions *int,
 	createdAt, updatedAt time.Time,
 ) *Wallet {
 	return &Wallet{
@@ -159,8 +196,11 @@ func ReconstructWallet(
 		},
 		dailyLimit:   dailyLimit,
 		monthlyLimit: monthlyLimit,
-		createdAt:    createdAt,
-		updatedAt:    updatedAt,
+		jurisdiction: jurisdiction,
+		createdAt:    createdAt.UTC(),
+		updatedAt:    updatedAt.UTC(),
+
+		maxPendingTransactions: copyIntPtr(maxPendingTransactions),
 	}
 }
 
@@ -210,6 +250,25 @@ func (w *Wallet) MonthlyLimit() valueobjects.Money {
 	return w.monthlyLimit
 }
 
+func (w *Wallet) Jurisdiction() string {
+	return w.jurisdiction
+}
+
+// MaxPendingTransactions returns the per-wallet override of the pending
+// transactions cap, or nil when the configured default applies.
+func (w *Wallet) MaxPendingTransactions() *int {
+	return copyIntPtr(w.maxPendingTransactions)
+}
+
+// PendingTransactionsCap resolves the effective cap: the override if set,
+// otherwise defaultCap. A non-positive result means "no cap".
+func (w *Wallet) PendingTransactionsCap(defaultCap int) int {
+	if w.maxPendingTransactions != nil {
+		return *w.maxPendingTransactions
+	}
+	return defaultCap
+}
+
 func (w *Wallet) CreatedAt() time.Time {
 	return w.createdAt
 }
@@ -249,10 +308,22 @@ func (w *Wallet) CanCredit() error {
 
 // HasSufficientBalance checks if the wallet has enough available balance.
 // Business rule: Cannot spend more than available balance.
+// An amount in another currency is a CURRENCY_MISMATCH violation, not "insufficient".
 func (w *Wallet) HasSufficientBalance(amount valueobjects.Money) (bool, error) {
+	if err := w.ensureCurrency(amount); err != nil {
+		return false, err
+	}
 	return w.balance.available.GreaterThanOrEqual(amount)
 }
 
+// ensureCurrency rejects amounts that are not in the wallet currency.
+func (w *Wallet) ensureCurrency(amount valueobjects.Money) error {
+	if !w.currency.Equals(amount.Currency()) {
+		return errors.NewCurrencyMismatch(w.currency.Code(), amount.Currency().Code())
+	}
+	return nil
+}
+
 // Credit adds funds to the wallet.
 // This is a domain operation that enforces business rules.
 //
@@ -267,15 +338,8 @@ func (w *Wallet) Credit(amount valueobjects.Money) error {
 	}
 
 	// Validate currency match
-	if !w.currency.Equals(amount.Currency()) {
-		return errors.NewBusinessRuleViolation(
-			"CURRENCY_MISMATCH",
-			"amount currency doesn't match wallet currency",
-			map[string]interface{}{
-				"walletCurrency": w.currency.Code(),
-				"amountCurrency": amount.Currency().Code(),
-			},
-		)
+	if err := w.ensureCurrency(amount); err != nil {
+		return err
 	}
 
 	// Update balance
@@ -286,7 +350,7 @@ func (w *Wallet) Credit(amount valueobjects.Money) error {
 
 	w.balance.available = newBalance
 	w.balance.version++ // Increment version for optimistic locking
-	w.updatedAt = time.Now()
+	w.updatedAt = touch(w.updatedAt)
 
 	return nil
 }
@@ -303,15 +367,8 @@ func (w *Wallet) Debit(amount valueobjects.Money) error {
 	}
 
 	// Validate currency
-	if !w.currency.Equals(amount.Currency()) {
-		return errors.NewBusinessRuleViolation(
-			"CURRENCY_MISMATCH",
-			"amount currency doesn't match wallet currency",
-			map[string]interface{}{
-				"walletCurrency": w.currency.Code(),
-				"amountCurrency": amount.Currency().Code(),
-			},
-		)
+	if err := w.ensureCurrency(amount); err != nil {
+		return err
 	}
 
 	// Check sufficient balance
@@ -331,7 +388,7 @@ func (w *Wallet) Debit(amount valueobjects.Money) error {
 
 	w.balance.available = newBalance
 	w.balance.version++
-	w.updatedAt = time.Now()
+	w.updatedAt = touch(w.updatedAt)
 
 	return nil
 }
@@ -367,7 +424,7 @@ func (w *Wallet) Reserve(amount valueobjects.Money) error {
 	w.balance.available = newAvailable
 	w.balance.pending = newPending
 	w.balance.version++
-	w.updatedAt = time.Now()
+	w.updatedAt = touch(w.updatedAt)
 
 	return nil
 }
@@ -375,6 +432,10 @@ func (w *Wallet) Reserve(amount valueobjects.Money) error {
 // Release moves funds from pending back to available.
 // Used when a reserved transaction is cancelled.
 func (w *Wallet) Release(amount valueobjects.Money) error {
+	if err := w.ensureCurrency(amount); err != nil {
+		return err
+	}
+
 	// Check if enough pending balance
 	hasSufficient, err := w.balance.pending.GreaterThanOrEqual(amount)
 	if err != nil {
@@ -402,7 +463,7 @@ func (w *Wallet) Release(amount valueobjects.Money) error {
 	w.balance.available = newAvailable
 	w.balance.pending = newPending
 	w.balance.version++
-	w.updatedAt = time.Now()
+	w.updatedAt = touch(w.updatedAt)
 
 	return nil
 }
@@ -410,6 +471,10 @@ func (w *Wallet) Release(amount valueobjects.Money) error {
 // CompletePending completes a pending transaction by removing it from pending.
 // Used when a reserved transaction is finalized (e.g., payout completed).
 func (w *Wallet) CompletePending(amount valueobjects.Money) error {
+	if err := w.ensureCurrency(amount); err != nil {
+		return err
+	}
+
 	hasSufficient, err := w.balance.pending.GreaterThanOrEqual(amount)
 	if err != nil {
 		return err
@@ -429,16 +494,23 @@ func (w *Wallet) CompletePending(amount valueobjects.Money) error {
 
 	w.balance.pending = newPending
 	w.balance.version++
-	w.updatedAt = time.Now()
+	w.updatedAt = touch(w.updatedAt)
 
 	return nil
 }
 
 // Status Management
+//
+// Each method checks WalletTransitions before changing the status.
+
+// canTransition reports whether WalletTransitions allows moving to status.
+func (w *Wallet) canTransition(status WalletStatus) bool {
+	return WalletTransitions.CanTransition(w.status, status)
+}
 
 // Suspend temporarily disables the wallet.
 func (w *Wallet) Suspend() error {
-	if w.status == WalletStatusClosed {
+	if !w.canTransition(WalletStatusSuspended) {
 		return errors.NewBusinessRuleViolation(
 			"CANNOT_SUSPEND_CLOSED_WALLET",
 			"cannot suspend a closed wallet",
@@ -447,13 +519,13 @@ func (w *Wallet) Suspend() error {
 	}
 
 	w.status = WalletStatusSuspended
-	w.updatedAt = time.Now()
+	w.updatedAt = touch(w.updatedAt)
 	return nil
 }
 
 // Activate activates a suspended wallet.
 func (w *Wallet) Activate() error {
-	if w.status == WalletStatusClosed {
+	if !w.canTransition(WalletStatusActive) {
 		return errors.NewBusinessRuleViolation(
 			"CANNOT_ACTIVATE_CLOSED_WALLET",
 			"cannot activate a closed wallet",
@@ -462,20 +534,69 @@ func (w *Wallet) Activate() error {
 	}
 
 	w.status = WalletStatusActive
-	w.updatedAt = time.Now()
+	w.updatedAt = touch(w.updatedAt)
 	return nil
 }
 
 // Lock locks the wallet (security/compliance).
+// Business rule: A closed wallet cannot be locked.
 func (w *Wallet) Lock() error {
+	if !w.canTransition(WalletStatusLocked) {
+		return errors.NewBusinessRuleViolation(
+			"CANNOT_LOCK_CLOSED_WALLET",
+			"cannot lock a closed wallet",
+			nil,
+		)
+	}
+
 	w.status = WalletStatusLocked
-	w.updatedAt = time.Now()
+	w.updatedAt = touch(w.updatedAt)
 	return nil
 }
 
+// SweepAvailable empties the available balance of a locked wallet and returns
+// the swept amount, so the wallet can be closed right after.
+//
+// Business Rules:
+// - Wallet must be locked: no debit can run between the sweep and Close
+// - Pending (reserved) funds belong to in-flight operations and block the sweep
+// - Balance version is incremented even for a zero sweep (optimistic locking)
+func (w *Wallet) SweepAvailable() (valueobjects.Money, error) {
+	if w.status != WalletStatusLocked {
+		return valueobjects.Money{}, errors.NewBusinessRuleViolation(
+			"WALLET_NOT_LOCKED",
+			"wallet must be locked before sweep",
+			map[string]interface{}{"status": string(w.status)},
+		)
+	}
+
+	if !w.balance.pending.IsZero() {
+		return valueobjects.Money{}, errors.NewBusinessRuleViolation(
+			"PENDING_BALANCE_ON_CLOSE",
+			"cannot sweep wallet with pending balance",
+			map[string]interface{}{"pending": w.balance.pending.String()},
+		)
+	}
+
+	swept := w.balance.available
+	w.balance.available = valueobjects.Zero(w.currency)
+	w.balance.version++
+	w.updatedAt = touch(w.updatedAt)
+
+	return swept, nil
+}
+
 // Close permanently closes the wallet.
-// Business rule: Can only close if balance is zero.
+// Business rule: Can only close an open wallet with zero balance.
 func (w *Wallet) Close() error {
+	if !w.canTransition(WalletStatusClosed) {
+		return errors.NewBusinessRuleViolation(
+			"WALLET_CLOSED",
+			"wallet is already closed",
+			nil,
+		)
+	}
+
 	total, err := w.TotalBalance()
 	if err != nil {
 		return err
@@ -492,7 +613,7 @@ func (w *Wallet) Close() error {
 	}
 
 	w.status = WalletStatusClosed
-	w.updatedAt = time.Now()
+	w.updatedAt = touch(w.updatedAt)
 	return nil
 }
 
@@ -510,6 +631,59 @@ func (w *Wallet) UpdateLimits(dailyLimit, monthlyLimit valueobjects.Money) error
 
 	w.dailyLimit = dailyLimit
 	w.monthlyLimit = monthlyLimit
-	w.updatedAt = time.Now()
+	w.balance.version++ // limits share the optimistic lock with the balance
+	w.updatedAt = touch(w.updatedAt)
+	return nil
+}
+
+// SetMaxPendingTransactions overrides the pending transactions cap for this
+// wallet (high-volume merchants). nil removes the override.
+// Business rule: an override must be positive - a wallet that should not
+// accept new transactions is suspended instead.
+func (w *Wallet) SetMaxPendingTransactions(max *int) error {
+	if max != nil && *max <= 0 {
+		return errors.ValidationError{
+			Field:   "max_pending_transactions",
+			Message: "must be positive",
+		}
+	}
+
+	w.maxPendingTransactions = copyIntPtr(max)
+	w.balance.version++ // shares the optimistic lock with the balance, like limits
+	w.updatedAt = touch(w.updatedAt)
 	return nil
 }
+
+// AssignJurisdiction tags the wallet with its owner's jurisdiction.
+// Business rule: the tag is set once at creation and cannot be changed afterwards,
+// so a user moving country does not re-tag existing wallets.
+func (w *Wallet) AssignJurisdiction(code string) error {
+	if code == "" {
+		return nil // untagged: resolved to the configured fallback at retention time
+	}
+
+	code, err := NormalizeJurisdiction(code)
+	if err != nil {
+		return err
+	}
+
+	if w.jurisdiction != "" && w.jurisdiction != code {
+		return errors.NewBusinessRuleViolation(
+			"JURISDICTION_ALREADY_ASSIGNED",
+			"wallet jurisdiction cannot be changed",
+			map[string]interface{}{"jurisdiction": w.jurisdiction},
+		)
+	}
+
+	w.jurisdiction = code
+	return nil
+}
+
+// copyIntPtr keeps the wallet's override private from callers' pointers.
+func copyIntPtr(v *int) *int {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
@@ -0,0 +1,164 @@
+// Package entities - WalletNote is a free-text support annotation on a wallet.
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// MaxWalletNoteLength is the maximum length of a note body in runes.
+const MaxWalletNoteLength = 2000
+
+// WalletNote is an internal note left by a support agent on a wallet.
+// Notes are visible to other agents only and never to the wallet owner.
+// Deletion is soft: a deleted note keeps its body, author and the deleting
+// actor so the history stays available for audit.
+type WalletNote struct {
+	id        uuid.UUID
+	walletID  uuid.UUID
+	authorID  uuid.UUID
+	body      string
+	pinned    bool
+	createdAt time.Time
+	deletedAt *time.Time
+	deletedBy *uuid.UUID
+}
+
+// NewWalletNote creates a note with validation.
+// The body is trimmed and must be non-empty valid UTF-8 of at most MaxWalletNoteLength runes.
+func NewWalletNote(walletID, authorID uuid.UUID, body string) (*WalletNote, error) {
+	body, err := normalizeWalletNoteBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WalletNote{
+		id:        uuid.New(),
+		walletID:  walletID,
+		authorID:  authorID,
+		body:      body,
+		createdAt: clock.Now(),
+	}, nil
+}
+
+// ReconstructWalletNote reconstructs a WalletNote from stored data.
+// No validation - assumes data is already valid. Timestamps are normalized to UTC.
+func ReconstructWalletNote(
+	id, walletID, authorID uuid.UUID,
+	body string,
+	pinned bool,
+	createdAt time.Time,
+	deletedAt *time.Time,
+	deletedBy *uuid.UUID,
+) *WalletNote {
+	if deletedAt != nil {
+		t := deletedAt.UTC()
+		deletedAt = &t
+	}
+
+	return &WalletNote{
+		id:        id,
+		walletID:  walletID,
+		authorID:  authorID,
+		body:      body,
+		pinned:    pinned,
+		createdAt: createdAt.UTC(),
+		deletedAt: deletedAt,
+		deletedBy: deletedBy,
+	}
+}
+
+// normalizeWalletNoteBody applies the same rules as transaction descriptions:
+// surrounding whitespace is dropped, the rest is stored as typed.
+func normalizeWalletNoteBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", errors.ValidationError{Field: "body", Message: "note body is required"}
+	}
+	if !utf8.ValidString(body) {
+		return "", errors.ValidationError{Field: "body", Message: "note body must be valid UTF-8"}
+	}
+	if utf8.RuneCountInString(body) > MaxWalletNoteLength {
+		return "", errors.ValidationError{
+			Field:   "body",
+			Message: fmt.Sprintf("note body must be at most %d characters", MaxWalletNoteLength),
+		}
+	}
+	return body, nil
+}
+
+// SetPinned pins or unpins the note. Pinned notes are listed first.
+func (n *WalletNote) SetPinned(pinned bool) error {
+	if n.IsDeleted() {
+		return errors.NewBusinessRuleViolation(
+			"WALLET_NOTE_DELETED",
+			"deleted note cannot be changed",
+			map[string]interface{}{"noteId": n.id},
+		)
+	}
+	n.pinned = pinned
+	return nil
+}
+
+// Delete marks the note as deleted by actorID.
+// Deleting an already deleted note is a no-op and keeps the original deletion.
+func (n *WalletNote) Delete(actorID uuid.UUID) {
+	if n.IsDeleted() {
+		return
+	}
+	now := clock.Now()
+	n.deletedAt = &now
+	n.deletedBy = &actorID
+}
+
+// ID returns the note identifier.
+func (n *WalletNote) ID() uuid.UUID {
+	return n.id
+}
+
+// WalletID returns the annotated wallet.
+func (n *WalletNote) WalletID() uuid.UUID {
+	return n.walletID
+}
+
+// AuthorID returns the actor who wrote the note.
+func (n *WalletNote) AuthorID() uuid.UUID {
+	return n.authorID
+}
+
+// Body returns the note text.
+func (n *WalletNote) Body() string {
+	return n.body
+}
+
+// Pinned reports whether the note is pinned.
+func (n *WalletNote) Pinned() bool {
+	return n.pinned
+}
+
+// CreatedAt returns when the note was written.
+func (n *WalletNote) CreatedAt() time.Time {
+	return n.createdAt
+}
+
+// DeletedAt returns when the note was deleted, nil for live notes.
+func (n *WalletNote) DeletedAt() *time.Time {
+	return n.deletedAt
+}
+
+// DeletedBy returns the actor who deleted the note, nil for live notes.
+func (n *WalletNote) DeletedBy() *uuid.UUID {
+	return n.deletedBy
+}
+
+// IsDeleted reports whether the note was soft-deleted.
+func (n *WalletNote) IsDeleted() bool {
+	return n.deletedAt != nil
+}
@@ -0,0 +1,201 @@
+// Package entities - WalletSettings are owner-managed presentation settings of a wallet.
+package entities
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+const (
+	// MaxIncomingDescriptionTemplateLength is the maximum template length in runes.
+	MaxIncomingDescriptionTemplateLength = 200
+
+	// MaxWalletAutoTags is the maximum number of auto tags per wallet.
+	MaxWalletAutoTags = 10
+
+	// MaxWalletTagLength is the maximum length of a single tag.
+	MaxWalletTagLength = 32
+
+	// WalletTagsMetadataKey is the reserved metadata key holding the wallet's
+	// auto tags (comma-separated). Clients cannot set it directly.
+	WalletTagsMetadataKey = "wallet_tags"
+
+	// ExternalReferencePlaceholder is substituted with the transfer's external reference.
+	ExternalReferencePlaceholder = "{external_reference}"
+)
+
+// walletTagPattern: tags are identifiers, commas are reserved as the separator.
+var walletTagPattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]+$`)
+
+// WalletSettings holds how transactions touching a wallet are presented to its owner.
+//
+// The incoming description template replaces the sender-controlled description
+// on the destination's view of an incoming transfer; the canonical transaction
+// keeps the sender's description. Auto tags are copied into the metadata of
+// every transaction the wallet takes part in. The display currency makes
+// wallet reads carry an approximate converted balance; it never affects
+// how money moves.
+type WalletSettings struct {
+	walletID                    uuid.UUID
+	incomingDescriptionTemplate string
+	autoTags                    []string
+	displayCurrency             string
+	updatedBy                   uuid.UUID
+	updatedAt                   time.Time
+}
+
+// NewWalletSettings creates wallet settings with validation.
+// The template is trimmed; tags are trimmed and deduplicated in order.
+// The display currency must be in the currency registry; empty means none.
+func NewWalletSettings(walletID uuid.UUID, template string, tags []string, displayCurrency string, updatedBy uuid.UUID) (*WalletSettings, error) {
+	template = strings.TrimSpace(template)
+	if !utf8.ValidString(template) {
+		return nil, errors.ValidationError{Field: "incoming_description_template", Message: "must be valid UTF-8"}
+	}
+	if utf8.RuneCountInString(template) > MaxIncomingDescriptionTemplateLength {
+		return nil, errors.ValidationError{
+			Field:   "incoming_description_template",
+			Message: fmt.Sprintf("must be at most %d characters", MaxIncomingDescriptionTemplateLength),
+		}
+	}
+
+	normalized, err := normalizeWalletTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if displayCurrency = strings.TrimSpace(displayCurrency); displayCurrency != "" {
+		currency, err := valueobjects.NewCurrency(displayCurrency)
+		if err != nil {
+			return nil, errors.ValidationError{Field: "display_currency", Message: "unsupported currency"}
+		}
+		displayCurrency = currency.Code()
+	}
+
+	return &WalletSettings{
+		walletID:                    walletID,
+		incomingDescriptionTemplate: template,
+		autoTags:                    normalized,
+		displayCurrency:             displayCurrency,
+		updatedBy:                   updatedBy,
+		updatedAt:                   clock.Now(),
+	}, nil
+}
+
+// ReconstructWalletSettings reconstructs WalletSettings from stored data.
+// No validation - assumes data is already valid.
+func ReconstructWalletSettings(walletID uuid.UUID, template string, tags []string, displayCurrency string, updatedBy uuid.UUID, updatedAt time.Time) *WalletSettings {
+	return &WalletSettings{
+		walletID:                    walletID,
+		incomingDescriptionTemplate: template,
+		autoTags:                    append([]string(nil), tags...),
+		displayCurrency:             displayCurrency,
+		updatedBy:                   updatedBy,
+		updatedAt:                   updatedAt.UTC(),
+	}
+}
+
+// normalizeWalletTags trims, validates and deduplicates tags.
+func normalizeWalletTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || len(tag) > MaxWalletTagLength || !walletTagPattern.MatchString(tag) {
+			return nil, errors.ValidationError{
+				Field:   "auto_tags",
+				Message: fmt.Sprintf("tag %q must be 1-%d characters of letters, digits, '_', '.', ':' or '-'", tag, MaxWalletTagLength),
+			}
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	if len(result) > MaxWalletAutoTags {
+		return nil, errors.ValidationError{
+			Field:   "auto_tags",
+			Message: fmt.Sprintf("at most %d tags are allowed", MaxWalletAutoTags),
+		}
+	}
+	return result, nil
+}
+
+// WalletID returns the wallet the settings belong to.
+func (s *WalletSettings) WalletID() uuid.UUID {
+	return s.walletID
+}
+
+// IncomingDescriptionTemplate returns the template; empty means none.
+func (s *WalletSettings) IncomingDescriptionTemplate() string {
+	return s.incomingDescriptionTemplate
+}
+
+// AutoTags returns a copy of the auto tags.
+func (s *WalletSettings) AutoTags() []string {
+	return append(make([]string, 0, len(s.autoTags)), s.autoTags...)
+}
+
+// DisplayCurrency returns the currency code balances are converted to for
+// display; empty (or a nil receiver) means no conversion.
+func (s *WalletSettings) DisplayCurrency() string {
+	if s == nil {
+		return ""
+	}
+	return s.displayCurrency
+}
+
+// UpdatedBy returns the owner who last changed the settings.
+func (s *WalletSettings) UpdatedBy() uuid.UUID {
+	return s.updatedBy
+}
+
+// UpdatedAt returns when the settings were last changed.
+func (s *WalletSettings) UpdatedAt() time.Time {
+	return s.updatedAt
+}
+
+// IncomingDescription returns the description the wallet owner sees for an
+// incoming transfer. The placeholder is replaced with the external reference;
+// without a reference it is dropped and the surrounding whitespace collapsed.
+// Without a template, or if nothing is left after substitution, the sender's
+// description is returned unchanged.
+func (s *WalletSettings) IncomingDescription(description, externalReference string) string {
+	if s == nil || s.incomingDescriptionTemplate == "" {
+		return description
+	}
+
+	rendered := strings.ReplaceAll(s.incomingDescriptionTemplate, ExternalReferencePlaceholder, externalReference)
+	rendered = strings.Join(strings.Fields(rendered), " ")
+	if rendered == "" {
+		return description
+	}
+	return rendered
+}
+
+// TagsValue returns the auto tags as stored under WalletTagsMetadataKey.
+func (s *WalletSettings) TagsValue() string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join(s.autoTags, ",")
+}
+
+// TagTransaction copies the auto tags into the transaction metadata.
+// A nil receiver or empty tag list leaves the transaction untouched.
+func (s *WalletSettings) TagTransaction(tx *Transaction) error {
+	if s == nil || len(s.autoTags) == 0 {
+		return nil
+	}
+	return tx.AddMetadata(WalletTagsMetadataKey, s.TagsValue())
+}
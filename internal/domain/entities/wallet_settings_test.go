@@ -0,0 +1,108 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+func TestNewWalletSettings_Validation(t *testing.T) {
+	tooManyTags := make([]string, MaxWalletAutoTags+1)
+	for i := range tooManyTags {
+		tooManyTags[i] = "tag-" + string(rune('a'+i))
+	}
+
+	tests := []struct {
+		name      string
+		template  string
+		tags      []string
+		display   string
+		wantField string
+	}{
+		{name: "template at limit", template: strings.Repeat("x", MaxIncomingDescriptionTemplateLength)},
+		{name: "template too long", template: strings.Repeat("x", MaxIncomingDescriptionTemplateLength+1), wantField: "incoming_description_template"},
+		{name: "max tags", tags: tooManyTags[:MaxWalletAutoTags]},
+		{name: "too many tags", tags: tooManyTags, wantField: "auto_tags"},
+		{name: "duplicates do not count", tags: append(tooManyTags[:MaxWalletAutoTags:MaxWalletAutoTags], "tag-a")},
+		{name: "comma in tag", tags: []string{"a,b"}, wantField: "auto_tags"},
+		{name: "empty tag", tags: []string{" "}, wantField: "auto_tags"},
+		{name: "display currency", display: "usd"},
+		{name: "unsupported display currency", display: "XYZ", wantField: "display_currency"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWalletSettings(uuid.New(), tt.template, tt.tags, tt.display, uuid.New())
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("NewWalletSettings() error = %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(errors.ValidationError)
+			if !ok {
+				t.Fatalf("Expected ValidationError, got %v", err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("Field = %q, want %q", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestWalletSettings_IncomingDescription(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		reference string
+		want      string
+	}{
+		{name: "no template keeps sender description", reference: "INV-1", want: "sender text"},
+		{name: "reference substituted", template: "Payment {external_reference}", reference: "INV-1", want: "Payment INV-1"},
+		{name: "missing reference collapses", template: "Payment {external_reference} received", want: "Payment received"},
+		{name: "only placeholder without reference", template: "{external_reference}", want: "sender text"},
+		{name: "static template", template: "Rent", reference: "INV-1", want: "Rent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings, err := NewWalletSettings(uuid.New(), tt.template, nil, "", uuid.New())
+			if err != nil {
+				t.Fatalf("NewWalletSettings() error = %v", err)
+			}
+			if got := settings.IncomingDescription("sender text", tt.reference); got != tt.want {
+				t.Errorf("IncomingDescription() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewIncomingTransferView(t *testing.T) {
+	tx := newTestTransfer(t, "10.00")
+	if err := tx.SetDestinationWallet(uuid.New()); err != nil {
+		t.Fatalf("SetDestinationWallet() error = %v", err)
+	}
+
+	view, err := NewIncomingTransferView(tx, nil)
+	if err != nil || view != nil {
+		t.Fatalf("Expected no view without settings, got %v, %v", view, err)
+	}
+
+	settings, err := NewWalletSettings(*tx.DestinationWalletID(), "", []string{"payroll"}, "", uuid.New())
+	if err != nil {
+		t.Fatalf("NewWalletSettings() error = %v", err)
+	}
+	view, err = NewIncomingTransferView(tx, settings)
+	if err != nil {
+		t.Fatalf("NewIncomingTransferView() error = %v", err)
+	}
+	if view.Description() != "transfer" {
+		t.Errorf("Description = %q, want sender description", view.Description())
+	}
+	if tags := view.Tags(); len(tags) != 1 || tags[0] != "payroll" {
+		t.Errorf("Tags = %v, want [payroll]", tags)
+	}
+}
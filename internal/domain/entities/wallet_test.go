@@ -6,6 +6,7 @@ import (
 
 	"github.com/Haleralex/wallethub/internal/domain/errors"
 	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
 	"github.com/google/uuid"
 )
 
@@ -150,6 +151,8 @@ func TestReconstructWallet(t *testing.T) {
 		available, pending,
 		5,
 		dailyLimit, monthlyLimit,
+		"",
+		nil,
 		now, now,
 	)
 
@@ -287,6 +290,48 @@ func TestWallet_HasSufficientBalance(t *testing.T) {
 	}
 }
 
+// TestWallet_CurrencyMismatch_NotInsufficientBalance tests that a foreign-currency
+// amount surfaces as CURRENCY_MISMATCH from every balance operation.
+func TestWallet_CurrencyMismatch_NotInsufficientBalance(t *testing.T) {
+	usd, _ := valueobjects.NewMoneyFromInt(100, valueobjects.USD)
+	eur := mustMoney(valueobjects.NewMoneyFromInt(10, valueobjects.EUR))
+
+	newWallet := func() *Wallet {
+		return &Wallet{
+			currency: valueobjects.USD,
+			status:   WalletStatusActive,
+			balance: Balance{
+				available: usd,
+				pending:   usd,
+			},
+		}
+	}
+
+	operations := map[string]func(w *Wallet) error{
+		"HasSufficientBalance": func(w *Wallet) error {
+			ok, err := w.HasSufficientBalance(eur)
+			if ok {
+				t.Error("HasSufficientBalance() must not report true on mismatch")
+			}
+			return err
+		},
+		"Credit":          func(w *Wallet) error { return w.Credit(eur) },
+		"Debit":           func(w *Wallet) error { return w.Debit(eur) },
+		"Reserve":         func(w *Wallet) error { return w.Reserve(eur) },
+		"Release":         func(w *Wallet) error { return w.Release(eur) },
+		"CompletePending": func(w *Wallet) error { return w.CompletePending(eur) },
+	}
+
+	for name, op := range operations {
+		t.Run(name, func(t *testing.T) {
+			err := op(newWallet())
+			if !errors.IsCurrencyMismatch(err) {
+				t.Errorf("expected CURRENCY_MISMATCH, got %v", err)
+			}
+		})
+	}
+}
+
 // TestWallet_Credit tests crediting wallet
 func TestWallet_Credit(t *testing.T) {
 	userID := uuid.New()
@@ -834,7 +879,109 @@ func TestWallet_Close(t *testing.T) {
 	})
 }
 
+// TestWallet_SweepAvailable tests emptying a locked wallet before close
+func TestWallet_SweepAvailable(t *testing.T) {
+	userID := uuid.New()
+	currency := valueobjects.USD
+
+	t.Run("Sweep locked wallet", func(t *testing.T) {
+		wallet, _ := NewWallet(userID, currency)
+		_ = wallet.Credit(mustMoney(valueobjects.NewMoneyFromInt(75, currency)))
+		_ = wallet.Lock()
+		version := wallet.BalanceVersion()
+
+		swept, err := wallet.SweepAvailable()
+		if err != nil {
+			t.Fatalf("SweepAvailable() error = %v, want nil", err)
+		}
+		if swept.String() != "75.00 USD" {
+			t.Errorf("swept = %s, want 75.00 USD", swept.String())
+		}
+		if !wallet.AvailableBalance().IsZero() {
+			t.Errorf("AvailableBalance = %s, want zero", wallet.AvailableBalance().String())
+		}
+		if wallet.BalanceVersion() != version+1 {
+			t.Errorf("BalanceVersion = %d, want %d", wallet.BalanceVersion(), version+1)
+		}
+		if err := wallet.Close(); err != nil {
+			t.Errorf("Close() after sweep error = %v", err)
+		}
+	})
+
+	t.Run("Zero sweep still bumps version", func(t *testing.T) {
+		wallet, _ := NewWallet(userID, currency)
+		_ = wallet.Lock()
+
+		swept, err := wallet.SweepAvailable()
+		if err != nil {
+			t.Fatalf("SweepAvailable() error = %v, want nil", err)
+		}
+		if !swept.IsZero() {
+			t.Errorf("swept = %s, want zero", swept.String())
+		}
+		if wallet.BalanceVersion() != 1 {
+			t.Errorf("BalanceVersion = %d, want 1", wallet.BalanceVersion())
+		}
+	})
+
+	t.Run("Active wallet cannot be swept", func(t *testing.T) {
+		wallet, _ := NewWallet(userID, currency)
+		_ = wallet.Credit(mustMoney(valueobjects.NewMoneyFromInt(10, currency)))
+
+		if _, err := wallet.SweepAvailable(); err == nil {
+			t.Fatal("SweepAvailable() on active wallet should return error")
+		}
+		if wallet.AvailableBalance().IsZero() {
+			t.Error("failed sweep must not change balance")
+		}
+	})
+
+	t.Run("Pending balance blocks sweep", func(t *testing.T) {
+		wallet, _ := NewWallet(userID, currency)
+		_ = wallet.Credit(mustMoney(valueobjects.NewMoneyFromInt(100, currency)))
+		_ = wallet.Reserve(mustMoney(valueobjects.NewMoneyFromInt(30, currency)))
+		_ = wallet.Lock()
+
+		if _, err := wallet.SweepAvailable(); err == nil {
+			t.Fatal("SweepAvailable() with pending balance should return error")
+		}
+	})
+}
+
 // TestWallet_UpdateLimits tests updating transaction limits
+func TestWallet_SetMaxPendingTransactions(t *testing.T) {
+	wallet, _ := NewWallet(uuid.New(), valueobjects.USD)
+
+	if wallet.MaxPendingTransactions() != nil || wallet.PendingTransactionsCap(100) != 100 {
+		t.Fatalf("New wallet should use the default cap, got override %v", wallet.MaxPendingTransactions())
+	}
+
+	override := 5000
+	if err := wallet.SetMaxPendingTransactions(&override); err != nil {
+		t.Fatalf("SetMaxPendingTransactions() error = %v", err)
+	}
+	override = 1 // caller's pointer must not leak into the wallet
+	if got := wallet.PendingTransactionsCap(100); got != 5000 {
+		t.Errorf("PendingTransactionsCap = %d, want 5000", got)
+	}
+	if wallet.BalanceVersion() != 1 {
+		t.Errorf("BalanceVersion = %d, want 1", wallet.BalanceVersion())
+	}
+
+	for _, invalid := range []int{0, -1} {
+		if err := wallet.SetMaxPendingTransactions(&invalid); err == nil {
+			t.Errorf("SetMaxPendingTransactions(%d) should fail", invalid)
+		}
+	}
+
+	if err := wallet.SetMaxPendingTransactions(nil); err != nil {
+		t.Fatalf("SetMaxPendingTransactions(nil) error = %v", err)
+	}
+	if got := wallet.PendingTransactionsCap(100); got != 100 {
+		t.Errorf("PendingTransactionsCap after reset = %d, want 100", got)
+	}
+}
+
 func TestWallet_UpdateLimits(t *testing.T) {
 	userID := uuid.New()
 	currency := valueobjects.USD
@@ -856,6 +1003,10 @@ func TestWallet_UpdateLimits(t *testing.T) {
 		if !wallet.MonthlyLimit().Equals(newMonthly) {
 			t.Errorf("MonthlyLimit = %v, want %v", wallet.MonthlyLimit(), newMonthly)
 		}
+
+		if wallet.BalanceVersion() != 1 {
+			t.Errorf("BalanceVersion = %d, want 1", wallet.BalanceVersion())
+		}
 	})
 
 	t.Run("Update limits with wrong currency", func(t *testing.T) {
@@ -909,6 +1060,8 @@ func TestWallet_Getters(t *testing.T) {
 		available, pending,
 		5,
 		dailyLimit, monthlyLimit,
+		"",
+		nil,
 		now, now,
 	)
 
@@ -950,19 +1103,69 @@ func TestWallet_Getters(t *testing.T) {
 	}
 }
 
-// TestWallet_UpdatedAtChanges tests that UpdatedAt changes on operations
+// TestWallet_UpdatedAtChanges tests that UpdatedAt takes the clock time on operations
 func TestWallet_UpdatedAtChanges(t *testing.T) {
-	userID := uuid.New()
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer clock.SetClock(fake)()
+
 	currency := valueobjects.USD
-	wallet, _ := NewWallet(userID, currency)
+	wallet, _ := NewWallet(uuid.New(), currency)
+	if !wallet.UpdatedAt().Equal(fake.Now()) || !wallet.CreatedAt().Equal(fake.Now()) {
+		t.Errorf("CreatedAt/UpdatedAt = %v/%v, want clock time %v", wallet.CreatedAt(), wallet.UpdatedAt(), fake.Now())
+	}
 
-	initialUpdatedAt := wallet.UpdatedAt()
-	time.Sleep(10 * time.Millisecond)
+	fake.Advance(time.Minute)
+	_ = wallet.Credit(mustMoney(valueobjects.NewMoneyFromInt(100, currency)))
+
+	if !wallet.UpdatedAt().Equal(fake.Now()) {
+		t.Errorf("UpdatedAt = %v, want %v after Credit", wallet.UpdatedAt(), fake.Now())
+	}
+}
+
+// TestWallet_UpdatedAtMonotonic tests that UpdatedAt strictly increases even
+// when the clock does not move or steps back
+func TestWallet_UpdatedAtMonotonic(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer clock.SetClock(fake)()
+
+	currency := valueobjects.USD
+	wallet, _ := NewWallet(uuid.New(), currency)
+	created := wallet.UpdatedAt()
 
 	_ = wallet.Credit(mustMoney(valueobjects.NewMoneyFromInt(100, currency)))
+	if want := created.Add(time.Nanosecond); !wallet.UpdatedAt().Equal(want) {
+		t.Errorf("UpdatedAt in the same tick = %v, want %v", wallet.UpdatedAt(), want)
+	}
 
-	if !wallet.UpdatedAt().After(initialUpdatedAt) {
-		t.Error("UpdatedAt should change after Credit operation")
+	fake.Advance(-time.Hour)
+	_ = wallet.Debit(mustMoney(valueobjects.NewMoneyFromInt(10, currency)))
+	if want := created.Add(2 * time.Nanosecond); !wallet.UpdatedAt().Equal(want) {
+		t.Errorf("UpdatedAt after a clock step back = %v, want %v", wallet.UpdatedAt(), want)
+	}
+}
+
+// TestWallet_RapidCredits tests ordering under many operations within one clock tick
+func TestWallet_RapidCredits(t *testing.T) {
+	currency := valueobjects.USD
+	wallet, _ := NewWallet(uuid.New(), currency)
+	amount := mustMoney(valueobjects.NewMoneyFromInt(1, currency))
+
+	previousUpdatedAt, previousVersion := wallet.UpdatedAt(), wallet.BalanceVersion()
+	for i := 0; i < 1000; i++ {
+		if err := wallet.Credit(amount); err != nil {
+			t.Fatalf("Credit #%d: %v", i, err)
+		}
+		if wallet.UpdatedAt().Before(previousUpdatedAt) {
+			t.Fatalf("Credit #%d: UpdatedAt went back from %v to %v", i, previousUpdatedAt, wallet.UpdatedAt())
+		}
+		if wallet.BalanceVersion() <= previousVersion {
+			t.Fatalf("Credit #%d: version %d not after %d", i, wallet.BalanceVersion(), previousVersion)
+		}
+		previousUpdatedAt, previousVersion = wallet.UpdatedAt(), wallet.BalanceVersion()
+	}
+
+	if want := mustMoney(valueobjects.NewMoneyFromInt(1000, currency)); !wallet.AvailableBalance().Equals(want) {
+		t.Errorf("AvailableBalance() = %v, want %v", wallet.AvailableBalance(), want)
 	}
 }
 
@@ -973,3 +1176,39 @@ func mustMoney(m valueobjects.Money, err error) valueobjects.Money {
 	}
 	return m
 }
+
+// TestWallet_AssignJurisdiction tests that the retention tag is set once at creation
+func TestWallet_AssignJurisdiction(t *testing.T) {
+	wallet, _ := NewWallet(uuid.New(), valueobjects.MustNewCurrency("USD"))
+
+	if err := wallet.AssignJurisdiction(""); err != nil {
+		t.Fatalf("AssignJurisdiction(\"\") error = %v", err)
+	}
+	if wallet.Jurisdiction() != "" {
+		t.Errorf("Empty code should leave wallet untagged, got %v", wallet.Jurisdiction())
+	}
+
+	if err := wallet.AssignJurisdiction("fr"); err != nil {
+		t.Fatalf("AssignJurisdiction() error = %v", err)
+	}
+	if wallet.Jurisdiction() != "FR" {
+		t.Errorf("Jurisdiction = %v, want FR", wallet.Jurisdiction())
+	}
+
+	// Same value is idempotent
+	if err := wallet.AssignJurisdiction("FR"); err != nil {
+		t.Errorf("Re-assigning same jurisdiction should succeed, got %v", err)
+	}
+
+	err := wallet.AssignJurisdiction("DE")
+	if !errors.IsBusinessRuleViolation(err) {
+		t.Errorf("Expected business rule violation, got %v", err)
+	}
+	if wallet.Jurisdiction() != "FR" {
+		t.Errorf("Jurisdiction changed to %v after rejected re-tag", wallet.Jurisdiction())
+	}
+
+	if err := wallet.AssignJurisdiction("FRA"); err == nil {
+		t.Error("Expected validation error for alpha-3 code")
+	}
+}
@@ -0,0 +1,189 @@
+// Package entities - WebhookDelivery is one event queued for one webhook subscription.
+package entities
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// maxWebhookDeliveryErrorLength bounds the stored error of the last attempt.
+const maxWebhookDeliveryErrorLength = 500
+
+// WebhookDeliveryStatus is the state of a delivery.
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending - queued, attempted while the subscription is active.
+	WebhookDeliveryPending WebhookDeliveryStatus = "PENDING"
+	// WebhookDeliveryDelivered - the endpoint answered 2xx.
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "DELIVERED"
+	// WebhookDeliveryFailed - attempts ran out.
+	WebhookDeliveryFailed WebhookDeliveryStatus = "FAILED"
+	// WebhookDeliverySkipped - the owner re-enabled the subscription without
+	// the backlog, or deleted it.
+	WebhookDeliverySkipped WebhookDeliveryStatus = "SKIPPED"
+	// WebhookDeliveryExpired - the subscription stayed disabled past the
+	// backlog grace period.
+	WebhookDeliveryExpired WebhookDeliveryStatus = "EXPIRED"
+)
+
+// WebhookDelivery is an event queued for a subscription, and after it
+// finishes, the delivery history record. The payload is the exact request
+// body, so retries send the same bytes with the same signature.
+type WebhookDelivery struct {
+	id             uuid.UUID
+	subscriptionID uuid.UUID
+	eventID        uuid.UUID
+	eventType      string
+	payload        []byte
+	status         WebhookDeliveryStatus
+	attempts       int
+	lastError      string
+	lastStatusCode int
+	nextAttemptAt  time.Time
+	createdAt      time.Time
+	finishedAt     *time.Time
+}
+
+// NewWebhookDelivery queues an event for a subscription, due immediately.
+func NewWebhookDelivery(subscriptionID, eventID uuid.UUID, eventType string, payload []byte) *WebhookDelivery {
+	now := clock.Now()
+	return &WebhookDelivery{
+		id:             uuid.New(),
+		subscriptionID: subscriptionID,
+		eventID:        eventID,
+		eventType:      eventType,
+		payload:        append([]byte(nil), payload...),
+		status:         WebhookDeliveryPending,
+		nextAttemptAt:  now,
+		createdAt:      now,
+	}
+}
+
+// ReconstructWebhookDelivery reconstructs a WebhookDelivery from stored data.
+// No validation - assumes data is already valid. Timestamps are normalized to UTC.
+func ReconstructWebhookDelivery(
+	id, subscriptionID, eventID uuid.UUID,
+	eventType string,
+	payload []byte,
+	status WebhookDeliveryStatus,
+	attempts int,
+	lastError string,
+	lastStatusCode int,
+	nextAttemptAt, createdAt time.Time,
+	finishedAt *time.Time,
+) *WebhookDelivery {
+	return &WebhookDelivery{
+		id:             id,
+		subscriptionID: subscriptionID,
+		eventID:        eventID,
+		eventType:      eventType,
+		payload:        append([]byte(nil), payload...),
+		status:         status,
+		attempts:       attempts,
+		lastError:      lastError,
+		lastStatusCode: lastStatusCode,
+		nextAttemptAt:  nextAttemptAt.UTC(),
+		createdAt:      createdAt.UTC(),
+		finishedAt:     utcTimePtr(finishedAt),
+	}
+}
+
+// MarkDelivered records a successful attempt.
+func (d *WebhookDelivery) MarkDelivered(statusCode int) {
+	now := clock.Now()
+	d.attempts++
+	d.status = WebhookDeliveryDelivered
+	d.lastStatusCode = statusCode
+	d.lastError = ""
+	d.finishedAt = &now
+}
+
+// MarkAttemptFailed records a failed attempt. statusCode is 0 when the
+// endpoint did not answer. The delivery is retried at nextAttemptAt until
+// maxAttempts attempts were made, then it becomes FAILED.
+func (d *WebhookDelivery) MarkAttemptFailed(statusCode int, reason string, nextAttemptAt time.Time, maxAttempts int) {
+	d.attempts++
+	d.lastStatusCode = statusCode
+	if utf8.RuneCountInString(reason) > maxWebhookDeliveryErrorLength {
+		reason = string([]rune(reason)[:maxWebhookDeliveryErrorLength])
+	}
+	d.lastError = reason
+
+	if maxAttempts > 0 && d.attempts >= maxAttempts {
+		now := clock.Now()
+		d.status = WebhookDeliveryFailed
+		d.finishedAt = &now
+		return
+	}
+	d.nextAttemptAt = nextAttemptAt.UTC()
+}
+
+// ID returns the delivery identifier.
+func (d *WebhookDelivery) ID() uuid.UUID {
+	return d.id
+}
+
+// SubscriptionID returns the receiving subscription.
+func (d *WebhookDelivery) SubscriptionID() uuid.UUID {
+	return d.subscriptionID
+}
+
+// EventID returns the delivered event.
+func (d *WebhookDelivery) EventID() uuid.UUID {
+	return d.eventID
+}
+
+// EventType returns the type of the delivered event.
+func (d *WebhookDelivery) EventType() string {
+	return d.eventType
+}
+
+// Payload returns a copy of the request body.
+func (d *WebhookDelivery) Payload() []byte {
+	return append([]byte(nil), d.payload...)
+}
+
+// Status returns the delivery state.
+func (d *WebhookDelivery) Status() WebhookDeliveryStatus {
+	return d.status
+}
+
+// Attempts returns the number of attempts made.
+func (d *WebhookDelivery) Attempts() int {
+	return d.attempts
+}
+
+// LastError returns the error of the last failed attempt.
+func (d *WebhookDelivery) LastError() string {
+	return d.lastError
+}
+
+// LastStatusCode returns the HTTP status of the last attempt, 0 if none.
+func (d *WebhookDelivery) LastStatusCode() int {
+	return d.lastStatusCode
+}
+
+// NextAttemptAt returns when a pending delivery is due.
+func (d *WebhookDelivery) NextAttemptAt() time.Time {
+	return d.nextAttemptAt
+}
+
+// CreatedAt returns when the event was queued.
+func (d *WebhookDelivery) CreatedAt() time.Time {
+	return d.createdAt
+}
+
+// FinishedAt returns when the delivery left PENDING, nil while pending.
+func (d *WebhookDelivery) FinishedAt() *time.Time {
+	return d.finishedAt
+}
+
+// IsPending reports whether the delivery is still queued.
+func (d *WebhookDelivery) IsPending() bool {
+	return d.status == WebhookDeliveryPending
+}
@@ -0,0 +1,486 @@
+// Package entities - WebhookSubscription is a user's endpoint for event webhooks.
+package entities
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// MaxWebhookURLLength is the maximum length of a webhook endpoint URL.
+const MaxWebhookURLLength = 2048
+
+// MaxWebhookEventTypes is the maximum number of event types of one subscription.
+const MaxWebhookEventTypes = 20
+
+// maxWebhookFailureReasonLength bounds the stored reason of the last failure.
+const maxWebhookFailureReasonLength = 500
+
+// webhookSecretBytes is the size of the generated signing secret.
+const webhookSecretBytes = 32
+
+// WebhookSubscriptionStatus is the delivery state of a subscription.
+type WebhookSubscriptionStatus string
+
+const (
+	// WebhookStatusActive - deliveries are attempted.
+	WebhookStatusActive WebhookSubscriptionStatus = "ACTIVE"
+	// WebhookStatusDisabledManual - the owner turned deliveries off.
+	WebhookStatusDisabledManual WebhookSubscriptionStatus = "DISABLED_MANUAL"
+	// WebhookStatusDisabledAuto - deliveries were turned off after the
+	// endpoint kept failing (see WebhookHealthPolicy).
+	WebhookStatusDisabledAuto WebhookSubscriptionStatus = "DISABLED_AUTO"
+)
+
+// IsValid reports whether the status is known.
+func (s WebhookSubscriptionStatus) IsValid() bool {
+	switch s {
+	case WebhookStatusActive, WebhookStatusDisabledManual, WebhookStatusDisabledAuto:
+		return true
+	default:
+		return false
+	}
+}
+
+// Reasons of an automatic disable.
+const (
+	WebhookDisabledConsecutiveFailures = "consecutive_failures"
+	WebhookDisabledFailingDuration     = "failing_duration"
+)
+
+// WebhookHealthPolicy decides when a failing endpoint is disabled
+// automatically. A zero field turns its criterion off.
+type WebhookHealthPolicy struct {
+	// MaxConsecutiveFailures - failed attempts in a row without a success.
+	MaxConsecutiveFailures int
+	// MaxFailingDuration - how long the endpoint may keep failing since the
+	// first failure of the current run.
+	MaxFailingDuration time.Duration
+}
+
+// DefaultWebhookHealthPolicy disables an endpoint after 50 consecutive
+// failures or 3 days of failures, whichever comes first.
+var DefaultWebhookHealthPolicy = WebhookHealthPolicy{
+	MaxConsecutiveFailures: 50,
+	MaxFailingDuration:     72 * time.Hour,
+}
+
+// exceeded returns the disable reason, or "" while the endpoint is tolerated.
+func (p WebhookHealthPolicy) exceeded(failures int, failingSince time.Time, now time.Time) string {
+	if p.MaxConsecutiveFailures > 0 && failures >= p.MaxConsecutiveFailures {
+		return WebhookDisabledConsecutiveFailures
+	}
+	if p.MaxFailingDuration > 0 && now.Sub(failingSince) >= p.MaxFailingDuration {
+		return WebhookDisabledFailingDuration
+	}
+	return ""
+}
+
+// WebhookSubscription is an endpoint that receives the owner's events.
+//
+// The delivery worker and the owner change the same subscription (failure
+// counters vs. PATCH/DELETE), so it is versioned: every change set bumps
+// Version by one and the repository saves it only over Version()-1.
+// Reload the subscription after Save before changing it again.
+//
+// Deletion is soft: a deleted subscription receives nothing and cannot be
+// changed, but stays stored with its delivery history until the retention
+// purge removes both.
+type WebhookSubscription struct {
+	id                  uuid.UUID
+	userID              uuid.UUID
+	url                 string
+	secret              string
+	eventTypes          []string
+	status              WebhookSubscriptionStatus
+	consecutiveFailures int
+	failingSince        *time.Time
+	lastFailureReason   string
+	disabledAt          *time.Time
+	version             int64
+	changed             bool
+	createdAt           time.Time
+	updatedAt           time.Time
+	deletedAt           *time.Time
+}
+
+// NewWebhookSubscription creates an active subscription with a generated
+// signing secret. An empty eventTypes subscribes to all event types;
+// checking the types against the published ones is up to the caller.
+func NewWebhookSubscription(userID uuid.UUID, rawURL string, eventTypes []string) (*WebhookSubscription, error) {
+	endpoint, err := normalizeWebhookURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	types, err := normalizeWebhookEventTypes(eventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	now := clock.Now()
+	return &WebhookSubscription{
+		id:         uuid.New(),
+		userID:     userID,
+		url:        endpoint,
+		secret:     hex.EncodeToString(secret),
+		eventTypes: types,
+		status:     WebhookStatusActive,
+		version:    1,
+		createdAt:  now,
+		updatedAt:  now,
+	}, nil
+}
+
+// ReconstructWebhookSubscription reconstructs a WebhookSubscription from stored data.
+// No validation - assumes data is already valid. Timestamps are normalized to UTC.
+func ReconstructWebhookSubscription(
+	id, userID uuid.UUID,
+	endpoint, secret string,
+	eventTypes []string,
+	status WebhookSubscriptionStatus,
+	consecutiveFailures int,
+	failingSince *time.Time,
+	lastFailureReason string,
+	disabledAt *time.Time,
+	version int64,
+	createdAt, updatedAt time.Time,
+	deletedAt *time.Time,
+) *WebhookSubscription {
+	return &WebhookSubscription{
+		id:                  id,
+		userID:              userID,
+		url:                 endpoint,
+		secret:              secret,
+		eventTypes:          append([]string(nil), eventTypes...),
+		status:              status,
+		consecutiveFailures: consecutiveFailures,
+		failingSince:        utcTimePtr(failingSince),
+		lastFailureReason:   lastFailureReason,
+		disabledAt:          utcTimePtr(disabledAt),
+		version:             version,
+		createdAt:           createdAt.UTC(),
+		updatedAt:           updatedAt.UTC(),
+		deletedAt:           utcTimePtr(deletedAt),
+	}
+}
+
+// utcTimePtr returns a copy of t in UTC, nil for nil.
+func utcTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := t.UTC()
+	return &v
+}
+
+// normalizeWebhookURL trims the URL and requires an absolute http(s) URL.
+func normalizeWebhookURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", errors.ValidationError{Field: "url", Message: "webhook URL is required"}
+	}
+	if utf8.RuneCountInString(rawURL) > MaxWebhookURLLength {
+		return "", errors.ValidationError{
+			Field:   "url",
+			Message: fmt.Sprintf("webhook URL must be at most %d characters", MaxWebhookURLLength),
+		}
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return "", errors.ValidationError{Field: "url", Message: "webhook URL must be an absolute http or https URL"}
+	}
+	if parsed.User != nil {
+		return "", errors.ValidationError{Field: "url", Message: "webhook URL must not contain credentials"}
+	}
+	return rawURL, nil
+}
+
+// normalizeWebhookEventTypes trims the types and drops empty and duplicate entries.
+func normalizeWebhookEventTypes(eventTypes []string) ([]string, error) {
+	types := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if eventType == "" || containsString(types, eventType) {
+			continue
+		}
+		types = append(types, eventType)
+	}
+	if len(types) > MaxWebhookEventTypes {
+		return nil, errors.ValidationError{
+			Field:   "event_types",
+			Message: fmt.Sprintf("at most %d event types are allowed", MaxWebhookEventTypes),
+		}
+	}
+	return types, nil
+}
+
+// containsString reports whether value is in the list.
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// touch records a change: the first change since load bumps the version.
+func (s *WebhookSubscription) touch(now time.Time) {
+	if !s.changed {
+		s.version++
+		s.changed = true
+	}
+	s.updatedAt = now
+}
+
+// ensureNotDeleted rejects changes of a deleted subscription.
+func (s *WebhookSubscription) ensureNotDeleted() error {
+	if s.IsDeleted() {
+		return errors.NewBusinessRuleViolation(
+			"WEBHOOK_SUBSCRIPTION_DELETED",
+			"deleted webhook subscription cannot be changed",
+			map[string]interface{}{"subscriptionId": s.id},
+		)
+	}
+	return nil
+}
+
+// resetFailures forgets the current run of failures.
+func (s *WebhookSubscription) resetFailures() {
+	s.consecutiveFailures = 0
+	s.failingSince = nil
+	s.lastFailureReason = ""
+}
+
+// RecordDeliverySuccess ends the current run of failures. It reports
+// whether the subscription changed and has to be saved.
+func (s *WebhookSubscription) RecordDeliverySuccess() bool {
+	if s.consecutiveFailures == 0 && s.failingSince == nil {
+		return false
+	}
+	s.resetFailures()
+	s.touch(clock.Now())
+	return true
+}
+
+// RecordDeliveryFailure counts a failed delivery attempt. When the policy
+// is exceeded an active subscription becomes DISABLED_AUTO and the disable
+// reason (WebhookDisabled*) is returned; otherwise the result is "".
+func (s *WebhookSubscription) RecordDeliveryFailure(reason string, policy WebhookHealthPolicy) string {
+	now := clock.Now()
+	s.consecutiveFailures++
+	if s.failingSince == nil {
+		s.failingSince = &now
+	}
+	if utf8.RuneCountInString(reason) > maxWebhookFailureReasonLength {
+		reason = string([]rune(reason)[:maxWebhookFailureReasonLength])
+	}
+	s.lastFailureReason = reason
+	s.touch(now)
+
+	if s.status != WebhookStatusActive || s.IsDeleted() {
+		return ""
+	}
+	disabledReason := policy.exceeded(s.consecutiveFailures, *s.failingSince, now)
+	if disabledReason != "" {
+		s.status = WebhookStatusDisabledAuto
+		s.disabledAt = &now
+	}
+	return disabledReason
+}
+
+// Disable turns deliveries off at the owner's request. An automatically
+// disabled subscription becomes manually disabled but keeps its original
+// disable time, which starts the backlog grace period.
+func (s *WebhookSubscription) Disable() error {
+	if err := s.ensureNotDeleted(); err != nil {
+		return err
+	}
+	if s.status == WebhookStatusDisabledManual {
+		return nil
+	}
+
+	now := clock.Now()
+	if s.disabledAt == nil {
+		s.disabledAt = &now
+	}
+	s.status = WebhookStatusDisabledManual
+	s.touch(now)
+	return nil
+}
+
+// Enable turns deliveries back on and forgets past failures.
+// Enabling an active subscription is a no-op.
+func (s *WebhookSubscription) Enable() error {
+	if err := s.ensureNotDeleted(); err != nil {
+		return err
+	}
+	if s.status == WebhookStatusActive {
+		return nil
+	}
+
+	s.status = WebhookStatusActive
+	s.disabledAt = nil
+	s.resetFailures()
+	s.touch(clock.Now())
+	return nil
+}
+
+// ChangeURL points the subscription at another endpoint. Failures of the
+// previous endpoint are forgotten.
+func (s *WebhookSubscription) ChangeURL(rawURL string) error {
+	if err := s.ensureNotDeleted(); err != nil {
+		return err
+	}
+	endpoint, err := normalizeWebhookURL(rawURL)
+	if err != nil {
+		return err
+	}
+	if endpoint == s.url {
+		return nil
+	}
+
+	s.url = endpoint
+	s.resetFailures()
+	s.touch(clock.Now())
+	return nil
+}
+
+// SetEventTypes replaces the delivered event types (empty - all types).
+func (s *WebhookSubscription) SetEventTypes(eventTypes []string) error {
+	if err := s.ensureNotDeleted(); err != nil {
+		return err
+	}
+	types, err := normalizeWebhookEventTypes(eventTypes)
+	if err != nil {
+		return err
+	}
+
+	s.eventTypes = types
+	s.touch(clock.Now())
+	return nil
+}
+
+// Delete soft-deletes the subscription.
+// Deleting an already deleted subscription is a no-op.
+func (s *WebhookSubscription) Delete() {
+	if s.IsDeleted() {
+		return
+	}
+	now := clock.Now()
+	s.deletedAt = &now
+	s.touch(now)
+}
+
+// ID returns the subscription identifier.
+func (s *WebhookSubscription) ID() uuid.UUID {
+	return s.id
+}
+
+// UserID returns the owner.
+func (s *WebhookSubscription) UserID() uuid.UUID {
+	return s.userID
+}
+
+// URL returns the endpoint URL.
+func (s *WebhookSubscription) URL() string {
+	return s.url
+}
+
+// Secret returns the HMAC signing secret of the deliveries.
+func (s *WebhookSubscription) Secret() string {
+	return s.secret
+}
+
+// EventTypes returns a copy of the delivered event types (empty - all).
+func (s *WebhookSubscription) EventTypes() []string {
+	return append([]string(nil), s.eventTypes...)
+}
+
+// Status returns the delivery state.
+func (s *WebhookSubscription) Status() WebhookSubscriptionStatus {
+	return s.status
+}
+
+// ConsecutiveFailures returns the failed attempts since the last success.
+func (s *WebhookSubscription) ConsecutiveFailures() int {
+	return s.consecutiveFailures
+}
+
+// FailingSince returns the first failure of the current run, nil when healthy.
+func (s *WebhookSubscription) FailingSince() *time.Time {
+	return s.failingSince
+}
+
+// LastFailureReason returns the reason of the latest failure of the current run.
+func (s *WebhookSubscription) LastFailureReason() string {
+	return s.lastFailureReason
+}
+
+// DisabledAt returns when deliveries stopped, nil while active.
+func (s *WebhookSubscription) DisabledAt() *time.Time {
+	return s.disabledAt
+}
+
+// Version returns the optimistic locking version.
+func (s *WebhookSubscription) Version() int64 {
+	return s.version
+}
+
+// CreatedAt returns when the subscription was created.
+func (s *WebhookSubscription) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+// UpdatedAt returns when the subscription last changed.
+func (s *WebhookSubscription) UpdatedAt() time.Time {
+	return s.updatedAt
+}
+
+// DeletedAt returns when the subscription was deleted, nil for live ones.
+func (s *WebhookSubscription) DeletedAt() *time.Time {
+	return s.deletedAt
+}
+
+// IsDeleted reports whether the subscription was soft-deleted.
+func (s *WebhookSubscription) IsDeleted() bool {
+	return s.deletedAt != nil
+}
+
+// IsActive reports whether deliveries are attempted.
+func (s *WebhookSubscription) IsActive() bool {
+	return s.status == WebhookStatusActive && !s.IsDeleted()
+}
+
+// Subscribed reports whether events of eventType are delivered.
+func (s *WebhookSubscription) Subscribed(eventType string) bool {
+	return len(s.eventTypes) == 0 || containsString(s.eventTypes, eventType)
+}
+
+// QueuesAt reports whether new events are queued for the subscription at
+// now: always while active, and during the backlog grace period after a
+// disable so re-enabling resumes where deliveries stopped.
+func (s *WebhookSubscription) QueuesAt(now time.Time, backlogGrace time.Duration) bool {
+	if s.IsDeleted() {
+		return false
+	}
+	if s.status == WebhookStatusActive {
+		return true
+	}
+	return s.disabledAt != nil && now.Sub(*s.disabledAt) < backlogGrace
+}
@@ -78,15 +78,40 @@ func NewDomainError(code, message string, err error) *DomainError {
 	}
 }
 
+// OperationDisabledCode is the error code of OperationDisabledError.
+const OperationDisabledCode = "OPERATION_TEMPORARILY_DISABLED"
+
+// OperationDisabledError is returned when an operational kill switch stops a
+// kind of money movement. Reason is the operator's explanation for clients.
+type OperationDisabledError struct {
+	Operation string // Switch that is off (e.g., "WITHDRAW")
+	Reason    string
+}
+
+// Error implements the error interface.
+func (e *OperationDisabledError) Error() string {
+	return fmt.Sprintf("[%s] %s operations are temporarily disabled: %s", OperationDisabledCode, e.Operation, e.Reason)
+}
+
 // ValidationError represents validation failures with field-level details.
 // Useful for returning multiple validation errors at once.
 //
 // Pattern: Composite Error for Multiple Validations
 type ValidationError struct {
-	Field   string // Field name that failed validation
+	Field   string // Field path that failed validation (e.g., "amount", "items[3].idempotency_key")
 	Message string // What went wrong
+	Code    string // Machine-readable code (ValidationCode*); empty means ValidationCodeInvalidValue
 }
 
+// Machine-readable validation codes for ValidationError.Code.
+const (
+	ValidationCodeRequired      = "REQUIRED"       // Value is missing
+	ValidationCodeInvalidFormat = "INVALID_FORMAT" // Value cannot be parsed (UUID, amount, email...)
+	ValidationCodeInvalidValue  = "INVALID_VALUE"  // Value is well-formed but not allowed
+	ValidationCodeOutOfRange    = "OUT_OF_RANGE"   // Value or length is outside the allowed bounds
+	ValidationCodeSensitiveData = "SENSITIVE_DATA" // Value contains a card number or IBAN (strict mode)
+)
+
 // Error implements the error interface.
 func (e ValidationError) Error() string {
 	return fmt.Sprintf("validation failed for field '%s': %s", e.Field, e.Message)
@@ -108,6 +133,26 @@ func (e *ValidationErrors) Add(field, message string) {
 	*e = append(*e, ValidationError{Field: field, Message: message})
 }
 
+// AddCode appends a validation error with a machine-readable code.
+func (e *ValidationErrors) AddCode(field, code, message string) {
+	*e = append(*e, ValidationError{Field: field, Message: message, Code: code})
+}
+
+// Err returns the collection as an error, or nil if it is empty.
+// Lets callers accumulate every failure and return them at once:
+//
+//	var errs ValidationErrors
+//	...
+//	if err := errs.Err(); err != nil {
+//	    return err
+//	}
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
 // HasErrors returns true if there are any validation errors.
 func (e ValidationErrors) HasErrors() bool {
 	return len(e) > 0
@@ -137,6 +182,31 @@ func NewBusinessRuleViolation(rule, message string, context map[string]interface
 	}
 }
 
+// CurrencyMismatchRule - правило, нарушаемое при операции над суммами в разных валютах.
+const CurrencyMismatchRule = "CURRENCY_MISMATCH"
+
+// NewCurrencyMismatch creates the violation for an amount in the wrong currency.
+// Currency mismatches must surface with this rule, never as a balance error.
+func NewCurrencyMismatch(expected, actual string) *BusinessRuleViolation {
+	return NewBusinessRuleViolation(
+		CurrencyMismatchRule,
+		"amount currency doesn't match wallet currency",
+		map[string]interface{}{
+			"walletCurrency": expected,
+			"amountCurrency": actual,
+		},
+	)
+}
+
+// Rules of transfer destination pre-validation: a transfer that breaks one of
+// them is rejected before any debit or event.
+const (
+	DestinationWalletNotFoundRule   = "DESTINATION_WALLET_NOT_FOUND"
+	DestinationWalletClosedRule     = "DESTINATION_WALLET_CLOSED"
+	DestinationWalletRestrictedRule = "DESTINATION_WALLET_RESTRICTED"
+	SelfTransferNotAllowedRule      = "SELF_TRANSFER_NOT_ALLOWED"
+)
+
 // ConcurrencyError represents errors from concurrent access (optimistic locking).
 // This will be important when we implement balance updates with version checking.
 type ConcurrencyError struct {
@@ -166,6 +236,11 @@ func IsNotFound(err error) bool {
 	return errors.Is(err, ErrEntityNotFound)
 }
 
+// IsDuplicateTransaction checks if an error is an idempotency key collision.
+func IsDuplicateTransaction(err error) bool {
+	return errors.Is(err, ErrDuplicateTransaction)
+}
+
 // IsValidationError checks if an error is a validation error.
 func IsValidationError(err error) bool {
 	var valErr ValidationError
@@ -184,6 +259,18 @@ func IsBusinessRuleViolation(err error) bool {
 	return errors.As(err, &brv)
 }
 
+// IsCurrencyMismatch checks if an error is a CURRENCY_MISMATCH business rule violation.
+func IsCurrencyMismatch(err error) bool {
+	var brv *BusinessRuleViolation
+	return errors.As(err, &brv) && brv.Rule == CurrencyMismatchRule
+}
+
+// IsOperationDisabled checks if an error is an OperationDisabledError.
+func IsOperationDisabled(err error) bool {
+	var ode *OperationDisabledError
+	return errors.As(err, &ode)
+}
+
 // IsConcurrencyError checks if an error is a concurrency error.
 func IsConcurrencyError(err error) bool {
 	var ce *ConcurrencyError
@@ -233,6 +233,34 @@ func TestValidationErrors_HasErrors(t *testing.T) {
 	}
 }
 
+// TestValidationErrors_AddCodeAndErr tests accumulating coded errors
+func TestValidationErrors_AddCodeAndErr(t *testing.T) {
+	var errs ValidationErrors
+
+	if err := errs.Err(); err != nil {
+		t.Errorf("Err() on empty collection = %v, want nil", err)
+	}
+
+	errs.AddCode("amount", ValidationCodeInvalidFormat, "invalid amount")
+	errs.AddCode("items[3].idempotency_key", ValidationCodeRequired, "required")
+
+	err := errs.Err()
+	if err == nil {
+		t.Fatal("Err() = nil, want error")
+	}
+	if !IsValidationError(err) {
+		t.Error("IsValidationError() = false for ValidationErrors")
+	}
+
+	var got ValidationErrors
+	if !errors.As(err, &got) || len(got) != 2 {
+		t.Fatalf("errors.As() = %v, want 2 errors", got)
+	}
+	if got[1].Field != "items[3].idempotency_key" || got[1].Code != ValidationCodeRequired {
+		t.Errorf("Second error = %+v", got[1])
+	}
+}
+
 // TestBusinessRuleViolation_Error tests BusinessRuleViolation error message
 func TestBusinessRuleViolation_Error(t *testing.T) {
 	brv := BusinessRuleViolation{
@@ -16,6 +16,8 @@ import (
 	"time"
 
 	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+	"github.com/Haleralex/wallethub/internal/pkg/ids"
 	"github.com/google/uuid"
 )
 
@@ -44,9 +46,20 @@ type BaseEvent struct {
 
 func newBaseEvent(eventType string, aggregateID uuid.UUID) BaseEvent {
 	return BaseEvent{
-		eventID:     uuid.New(),
+		eventID:     ids.NewEventID(),
 		eventType:   eventType,
-		occurredAt:  time.Now(),
+		occurredAt:  clock.Now(),
+		aggregateID: aggregateID,
+	}
+}
+
+// RestoreBaseEvent rebuilds the metadata of an event read back from storage
+// (e.g. a post-commit spool). Use the New* constructors for new events.
+func RestoreBaseEvent(eventID uuid.UUID, eventType string, occurredAt time.Time, aggregateID uuid.UUID) BaseEvent {
+	return BaseEvent{
+		eventID:     eventID,
+		eventType:   eventType,
+		occurredAt:  occurredAt,
 		aggregateID: aggregateID,
 	}
 }
@@ -69,17 +82,30 @@ func (e BaseEvent) AggregateID() uuid.UUID {
 
 // Event Types (constants for type checking)
 const (
-	EventTypeUserCreated          = "user.created"
-	EventTypeUserKYCApproved      = "user.kyc.approved"
-	EventTypeUserKYCRejected      = "user.kyc.rejected"
-	EventTypeWalletCreated        = "wallet.created"
-	EventTypeWalletCredited       = "wallet.credited"
-	EventTypeWalletDebited        = "wallet.debited"
-	EventTypeWalletSuspended      = "wallet.suspended"
-	EventTypeTransactionCreated   = "transaction.created"
-	EventTypeTransactionCompleted = "transaction.completed"
-	EventTypeTransactionFailed    = "transaction.failed"
-	EventTypeCurrencyExchanged    = "transaction.exchange.completed"
+	EventTypeUserCreated           = "user.created"
+	EventTypeUserKYCApproved       = "user.kyc.approved"
+	EventTypeUserKYCRejected       = "user.kyc.rejected"
+	EventTypeUserKYCRevoked        = "user.kyc.revoked"
+	EventTypeUserAcceptedTerms     = "user.terms_accepted"
+	EventTypeWalletCreated         = "wallet.created"
+	EventTypeWalletCredited        = "wallet.credited"
+	EventTypeWalletDebited         = "wallet.debited"
+	EventTypeWalletSuspended       = "wallet.suspended"
+	EventTypeWalletClosed          = "wallet.closed"
+	EventTypeWalletLimitsUpdated   = "wallet.limits_updated"
+	EventTypeWalletBalanceSummary  = "wallet.balance_summary"
+	EventTypeWalletNoteAdded       = "wallet.note_added"
+	EventTypeWalletMetadataQuota   = "wallet.metadata_quota_warning"
+	EventTypeTransactionCreated    = "transaction.created"
+	EventTypeTransactionCompleted  = "transaction.completed"
+	EventTypeTransactionFailed     = "transaction.failed"
+	EventTypeTransactionFlagged    = "transaction.flagged"
+	EventTypeCurrencyExchanged     = "transaction.exchange.completed"
+	EventTypeSandboxResetRequested = "sandbox.reset_requested"
+	EventTypeSandboxResetCompleted = "sandbox.reset_completed"
+	EventTypeSuspiciousAuth        = "security.suspicious_auth"
+	EventTypeOperationSwitched     = "operations.switch_changed"
+	EventTypeWebhookDisabled       = "webhook.subscription_disabled"
 )
 
 // ===== User Events =====
@@ -103,28 +129,68 @@ func NewUserCreated(userID uuid.UUID, email, fullName string) *UserCreated {
 // This might trigger wallet creation or increased limits.
 type UserKYCApproved struct {
 	BaseEvent
-	UserID uuid.UUID
+	UserID  uuid.UUID
+	ActorID uuid.UUID // Reviewer who approved
 }
 
-func NewUserKYCApproved(userID uuid.UUID) *UserKYCApproved {
+func NewUserKYCApproved(userID, actorID uuid.UUID) *UserKYCApproved {
 	return &UserKYCApproved{
 		BaseEvent: newBaseEvent(EventTypeUserKYCApproved, userID),
 		UserID:    userID,
+		ActorID:   actorID,
 	}
 }
 
 // UserKYCRejected is raised when KYC verification is rejected.
+// Reason is the same text that is stored in the user's KYC history.
 type UserKYCRejected struct {
 	BaseEvent
-	UserID uuid.UUID
-	Reason string
+	UserID  uuid.UUID
+	Reason  string
+	ActorID uuid.UUID // Reviewer who rejected
 }
 
-func NewUserKYCRejected(userID uuid.UUID, reason string) *UserKYCRejected {
+func NewUserKYCRejected(userID uuid.UUID, reason string, actorID uuid.UUID) *UserKYCRejected {
 	return &UserKYCRejected{
 		BaseEvent: newBaseEvent(EventTypeUserKYCRejected, userID),
 		UserID:    userID,
 		Reason:    reason,
+		ActorID:   actorID,
+	}
+}
+
+// UserKYCRevoked is raised when a verified KYC is revoked after a compliance
+// re-review. The user is REJECTED afterwards; Reason is stored in the KYC history.
+type UserKYCRevoked struct {
+	BaseEvent
+	UserID  uuid.UUID
+	Reason  string
+	ActorID uuid.UUID // Admin who revoked
+}
+
+func NewUserKYCRevoked(userID uuid.UUID, reason string, actorID uuid.UUID) *UserKYCRevoked {
+	return &UserKYCRevoked{
+		BaseEvent: newBaseEvent(EventTypeUserKYCRevoked, userID),
+		UserID:    userID,
+		Reason:    reason,
+		ActorID:   actorID,
+	}
+}
+
+// UserAcceptedTerms is raised when a user accepts a terms-of-service version.
+type UserAcceptedTerms struct {
+	BaseEvent
+	UserID     uuid.UUID
+	Version    int
+	AcceptedAt time.Time
+}
+
+func NewUserAcceptedTerms(userID uuid.UUID, version int, acceptedAt time.Time) *UserAcceptedTerms {
+	return &UserAcceptedTerms{
+		BaseEvent:  newBaseEvent(EventTypeUserAcceptedTerms, userID),
+		UserID:     userID,
+		Version:    version,
+		AcceptedAt: acceptedAt,
 	}
 }
 
@@ -153,6 +219,11 @@ type WalletCredited struct {
 	Amount        valueobjects.Money
 	TransactionID uuid.UUID
 	BalanceAfter  valueobjects.Money
+	// BalanceVersion is the wallet balance version after the change - the
+	// per-wallet sequence number. Order balance events of one wallet by it,
+	// not by OccurredAt: changes within one clock tick share a timestamp.
+	// Events of one balance change (amount and its fee) share the version.
+	BalanceVersion int64
 }
 
 func NewWalletCredited(
@@ -160,13 +231,15 @@ func NewWalletCredited(
 	amount valueobjects.Money,
 	transactionID uuid.UUID,
 	balanceAfter valueobjects.Money,
+	balanceVersion int64,
 ) *WalletCredited {
 	return &WalletCredited{
-		BaseEvent:     newBaseEvent(EventTypeWalletCredited, walletID),
-		WalletID:      walletID,
-		Amount:        amount,
-		TransactionID: transactionID,
-		BalanceAfter:  balanceAfter,
+		BaseEvent:      newBaseEvent(EventTypeWalletCredited, walletID),
+		WalletID:       walletID,
+		Amount:         amount,
+		TransactionID:  transactionID,
+		BalanceAfter:   balanceAfter,
+		BalanceVersion: balanceVersion,
 	}
 }
 
@@ -177,6 +250,11 @@ type WalletDebited struct {
 	Amount        valueobjects.Money
 	TransactionID uuid.UUID
 	BalanceAfter  valueobjects.Money
+	// BalanceVersion is the wallet balance version after the change - the
+	// per-wallet sequence number. Order balance events of one wallet by it,
+	// not by OccurredAt: changes within one clock tick share a timestamp.
+	// Events of one balance change (amount and its fee) share the version.
+	BalanceVersion int64
 }
 
 func NewWalletDebited(
@@ -184,13 +262,15 @@ func NewWalletDebited(
 	amount valueobjects.Money,
 	transactionID uuid.UUID,
 	balanceAfter valueobjects.Money,
+	balanceVersion int64,
 ) *WalletDebited {
 	return &WalletDebited{
-		BaseEvent:     newBaseEvent(EventTypeWalletDebited, walletID),
-		WalletID:      walletID,
-		Amount:        amount,
-		TransactionID: transactionID,
-		BalanceAfter:  balanceAfter,
+		BaseEvent:      newBaseEvent(EventTypeWalletDebited, walletID),
+		WalletID:       walletID,
+		Amount:         amount,
+		TransactionID:  transactionID,
+		BalanceAfter:   balanceAfter,
+		BalanceVersion: balanceVersion,
 	}
 }
 
@@ -210,21 +290,143 @@ func NewWalletSuspended(walletID uuid.UUID, reason string) *WalletSuspended {
 	}
 }
 
+// WalletClosed is raised when a wallet is permanently closed.
+// The remaining balance is swept to another wallet of the same user first;
+// TransactionID is uuid.Nil when there was nothing to sweep.
+type WalletClosed struct {
+	BaseEvent
+	WalletID            uuid.UUID
+	DestinationWalletID uuid.UUID
+	SweptAmount         valueobjects.Money
+	TransactionID       uuid.UUID
+}
+
+func NewWalletClosed(
+	walletID, destinationWalletID uuid.UUID,
+	sweptAmount valueobjects.Money,
+	transactionID uuid.UUID,
+) *WalletClosed {
+	return &WalletClosed{
+		BaseEvent:           newBaseEvent(EventTypeWalletClosed, walletID),
+		WalletID:            walletID,
+		DestinationWalletID: destinationWalletID,
+		SweptAmount:         sweptAmount,
+		TransactionID:       transactionID,
+	}
+}
+
+// WalletLimitsUpdated is raised when the daily or monthly limit of a wallet changes.
+type WalletLimitsUpdated struct {
+	BaseEvent
+	WalletID     uuid.UUID
+	DailyLimit   valueobjects.Money
+	MonthlyLimit valueobjects.Money
+}
+
+func NewWalletLimitsUpdated(walletID uuid.UUID, dailyLimit, monthlyLimit valueobjects.Money) *WalletLimitsUpdated {
+	return &WalletLimitsUpdated{
+		BaseEvent:    newBaseEvent(EventTypeWalletLimitsUpdated, walletID),
+		WalletID:     walletID,
+		DailyLimit:   dailyLimit,
+		MonthlyLimit: monthlyLimit,
+	}
+}
+
+// WalletBalanceSummary is an additive digest of WalletCredited/WalletDebited:
+// one event per wallet whose balance changed during a debounce window.
+// Balances are the latest committed values; ChangeCount is the number of
+// balance events in the window and LastTransactionID the newest of them.
+type WalletBalanceSummary struct {
+	BaseEvent
+	WalletID          uuid.UUID
+	AvailableBalance  valueobjects.Money
+	PendingBalance    valueobjects.Money
+	ChangeCount       int
+	LastTransactionID uuid.UUID
+	WindowStart       time.Time
+	WindowEnd         time.Time
+}
+
+func NewWalletBalanceSummary(
+	walletID uuid.UUID,
+	available, pending valueobjects.Money,
+	changeCount int,
+	lastTransactionID uuid.UUID,
+	windowStart, windowEnd time.Time,
+) *WalletBalanceSummary {
+	return &WalletBalanceSummary{
+		BaseEvent:         newBaseEvent(EventTypeWalletBalanceSummary, walletID),
+		WalletID:          walletID,
+		AvailableBalance:  available,
+		PendingBalance:    pending,
+		ChangeCount:       changeCount,
+		LastTransactionID: lastTransactionID,
+		WindowStart:       windowStart.UTC(),
+		WindowEnd:         windowEnd.UTC(),
+	}
+}
+
+// WalletNoteAdded is raised when a support agent adds a note to a wallet.
+// It is the audit record of the note; the body itself stays in wallet_notes.
+type WalletNoteAdded struct {
+	BaseEvent
+	WalletID uuid.UUID
+	NoteID   uuid.UUID
+	AuthorID uuid.UUID
+}
+
+func NewWalletNoteAdded(walletID, noteID, authorID uuid.UUID) *WalletNoteAdded {
+	return &WalletNoteAdded{
+		BaseEvent: newBaseEvent(EventTypeWalletNoteAdded, walletID),
+		WalletID:  walletID,
+		NoteID:    noteID,
+		AuthorID:  authorID,
+	}
+}
+
+// WalletMetadataQuotaWarning is raised when the total metadata stored on a
+// wallet's transactions first exceeds the soft quota. It is not repeated for
+// later transactions while the wallet stays above the quota.
+type WalletMetadataQuotaWarning struct {
+	BaseEvent
+	WalletID       uuid.UUID
+	UserID         uuid.UUID
+	TransactionID  uuid.UUID // The transaction that crossed the quota
+	UsedBytes      int64
+	SoftLimitBytes int64
+}
+
+func NewWalletMetadataQuotaWarning(walletID, userID, transactionID uuid.UUID, usedBytes, softLimitBytes int64) *WalletMetadataQuotaWarning {
+	return &WalletMetadataQuotaWarning{
+		BaseEvent:      newBaseEvent(EventTypeWalletMetadataQuota, walletID),
+		WalletID:       walletID,
+		UserID:         userID,
+		TransactionID:  transactionID,
+		UsedBytes:      usedBytes,
+		SoftLimitBytes: softLimitBytes,
+	}
+}
+
 // ===== Transaction Events =====
 
 // TransactionCreated is raised when a new transaction is created.
 // Consumers might validate, apply risk checks, or start processing.
+// UserID is the wallet owner at the time of the transaction, so consumers
+// don't need a wallet lookup per event.
 type TransactionCreated struct {
 	BaseEvent
 	TransactionID   uuid.UUID
 	WalletID        uuid.UUID
+	UserID          uuid.UUID
 	TransactionType string
-	Amount          valueobjects.Money
+	Amount          valueobjects.Money // Gross amount
+	FeeAmount       valueobjects.Money
+	NetAmount       valueobjects.Money
 	IdempotencyKey  string
 }
 
 func NewTransactionCreated(
-	transactionID, walletID uuid.UUID,
+	transactionID, walletID, userID uuid.UUID,
 	transactionType string,
 	amount valueobjects.Money,
 	idempotencyKey string,
@@ -233,68 +435,142 @@ func NewTransactionCreated(
 		BaseEvent:       newBaseEvent(EventTypeTransactionCreated, transactionID),
 		TransactionID:   transactionID,
 		WalletID:        walletID,
+		UserID:          userID,
 		TransactionType: transactionType,
 		Amount:          amount,
+		FeeAmount:       valueobjects.Zero(amount.Currency()),
+		NetAmount:       amount,
 		IdempotencyKey:  idempotencyKey,
 	}
 }
 
+// WithAmountBreakdown sets the fee and net amounts (defaults: zero fee, net == gross).
+func (e *TransactionCreated) WithAmountBreakdown(fee, net valueobjects.Money) *TransactionCreated {
+	e.FeeAmount = fee
+	e.NetAmount = net
+	return e
+}
+
 // TransactionCompleted is raised when a transaction completes successfully.
 // This might trigger notifications, webhooks to merchants, analytics updates.
+// For transfers the counterparty fields identify the destination wallet and
+// its owner; they are uuid.Nil for other transaction types.
 type TransactionCompleted struct {
 	BaseEvent
-	TransactionID   uuid.UUID
-	WalletID        uuid.UUID
-	TransactionType string
-	Amount          valueobjects.Money
-	CompletedAt     time.Time
+	TransactionID        uuid.UUID
+	WalletID             uuid.UUID
+	UserID               uuid.UUID // Wallet owner at the time of the transaction
+	CounterpartyWalletID uuid.UUID
+	CounterpartyUserID   uuid.UUID
+	TransactionType      string
+	Amount               valueobjects.Money // Gross amount
+	FeeAmount            valueobjects.Money
+	NetAmount            valueobjects.Money
+	CompletedAt          time.Time
+	ReceiptNumber        string // Empty if the transaction was completed without a receipt
 }
 
 func NewTransactionCompleted(
-	transactionID, walletID uuid.UUID,
+	transactionID, walletID, userID uuid.UUID,
 	transactionType string,
 	amount valueobjects.Money,
 ) *TransactionCompleted {
+	base := newBaseEvent(EventTypeTransactionCompleted, transactionID)
 	return &TransactionCompleted{
-		BaseEvent:       newBaseEvent(EventTypeTransactionCompleted, transactionID),
+		BaseEvent:       base,
 		TransactionID:   transactionID,
 		WalletID:        walletID,
+		UserID:          userID,
 		TransactionType: transactionType,
 		Amount:          amount,
-		CompletedAt:     time.Now(),
+		FeeAmount:       valueobjects.Zero(amount.Currency()),
+		NetAmount:       amount,
+		CompletedAt:     base.OccurredAt(), // same process clock reading as OccurredAt
 	}
 }
 
+// WithAmountBreakdown sets the fee and net amounts (defaults: zero fee, net == gross).
+func (e *TransactionCompleted) WithAmountBreakdown(fee, net valueobjects.Money) *TransactionCompleted {
+	e.FeeAmount = fee
+	e.NetAmount = net
+	return e
+}
+
+// WithReceiptNumber sets the receipt number assigned when the transaction was saved.
+func (e *TransactionCompleted) WithReceiptNumber(number string) *TransactionCompleted {
+	e.ReceiptNumber = number
+	return e
+}
+
+// WithCounterparty sets the destination wallet and its owner of a transfer.
+func (e *TransactionCompleted) WithCounterparty(walletID, userID uuid.UUID) *TransactionCompleted {
+	e.CounterpartyWalletID = walletID
+	e.CounterpartyUserID = userID
+	return e
+}
+
 // TransactionFailed is raised when a transaction fails.
 // Consumers might retry, alert admins, or notify users.
 type TransactionFailed struct {
 	BaseEvent
 	TransactionID   uuid.UUID
 	WalletID        uuid.UUID
+	UserID          uuid.UUID // Wallet owner at the time of the transaction
 	TransactionType string
 	Amount          valueobjects.Money
 	FailureReason   string
+	FailureCategory string // CLIENT, PROVIDER, SYSTEM or FRAUD
 	IsRetryable     bool
 }
 
 func NewTransactionFailed(
-	transactionID, walletID uuid.UUID,
+	transactionID, walletID, userID uuid.UUID,
 	transactionType string,
 	amount valueobjects.Money,
 	failureReason string,
+	failureCategory string,
 	isRetryable bool,
 ) *TransactionFailed {
 	return &TransactionFailed{
 		BaseEvent:       newBaseEvent(EventTypeTransactionFailed, transactionID),
 		TransactionID:   transactionID,
 		WalletID:        walletID,
+		UserID:          userID,
 		TransactionType: transactionType,
 		Amount:          amount,
 		FailureReason:   failureReason,
+		FailureCategory: failureCategory,
 		IsRetryable:     isRetryable,
 	}
 }
 
+// TransactionFlagged is raised when screening rules with the FLAG action match
+// a transaction. The transaction still proceeds; compliance reviews it later.
+type TransactionFlagged struct {
+	BaseEvent
+	TransactionID   uuid.UUID
+	WalletID        uuid.UUID
+	TransactionType string
+	Amount          valueobjects.Money
+	RuleIDs         []string
+}
+
+func NewTransactionFlagged(
+	transactionID, walletID uuid.UUID,
+	transactionType string,
+	amount valueobjects.Money,
+	ruleIDs []string,
+) *TransactionFlagged {
+	return &TransactionFlagged{
+		BaseEvent:       newBaseEvent(EventTypeTransactionFlagged, transactionID),
+		TransactionID:   transactionID,
+		WalletID:        walletID,
+		TransactionType: transactionType,
+		Amount:          amount,
+		RuleIDs:         append([]string(nil), ruleIDs...),
+	}
+}
+
 // CurrencyExchanged is raised when a currency exchange completes.
 type CurrencyExchanged struct {
 	BaseEvent
@@ -326,6 +602,134 @@ func NewCurrencyExchanged(
 	}
 }
 
+// ===== Sandbox Events =====
+
+// SandboxResetRequested is raised when a tenant asks to reset its sandbox data
+// and receives a confirmation token. Audit trail only - nothing is deleted yet.
+type SandboxResetRequested struct {
+	BaseEvent
+	TenantID  uuid.UUID
+	ActorID   uuid.UUID
+	ExpiresAt time.Time // Confirmation token expiry
+}
+
+func NewSandboxResetRequested(tenantID, actorID uuid.UUID, expiresAt time.Time) *SandboxResetRequested {
+	return &SandboxResetRequested{
+		BaseEvent: newBaseEvent(EventTypeSandboxResetRequested, tenantID),
+		TenantID:  tenantID,
+		ActorID:   actorID,
+		ExpiresAt: expiresAt,
+	}
+}
+
+// SandboxResetCompleted is raised after a tenant's sandbox data was deleted.
+// Deleted holds the number of removed rows per table.
+type SandboxResetCompleted struct {
+	BaseEvent
+	TenantID uuid.UUID
+	ActorID  uuid.UUID
+	Deleted  map[string]int64
+}
+
+func NewSandboxResetCompleted(tenantID, actorID uuid.UUID, deleted map[string]int64) *SandboxResetCompleted {
+	return &SandboxResetCompleted{
+		BaseEvent: newBaseEvent(EventTypeSandboxResetCompleted, tenantID),
+		TenantID:  tenantID,
+		ActorID:   actorID,
+		Deleted:   deleted,
+	}
+}
+
+// ===== Security Events =====
+
+// SuspiciousAuthActivity is raised when a principal exceeds the failed
+// authentication threshold within one monitoring window. It is raised at most
+// once per principal per window.
+//
+// AggregateID is the user ID when the principal is one, otherwise uuid.Nil
+// (the principal is then a credential prefix).
+type SuspiciousAuthActivity struct {
+	BaseEvent
+	Principal   string
+	Failures    int
+	WindowStart time.Time
+	Window      time.Duration
+}
+
+func NewSuspiciousAuthActivity(principal string, failures int, windowStart time.Time, window time.Duration) *SuspiciousAuthActivity {
+	aggregateID, err := uuid.Parse(principal)
+	if err != nil {
+		aggregateID = uuid.Nil
+	}
+
+	return &SuspiciousAuthActivity{
+		BaseEvent:   newBaseEvent(EventTypeSuspiciousAuth, aggregateID),
+		Principal:   principal,
+		Failures:    failures,
+		WindowStart: windowStart.UTC(),
+		Window:      window,
+	}
+}
+
+// ===== Operations Events =====
+
+// OperationSwitchChanged is raised when an admin enables or disables a kind of
+// money movement. It is the audit record of the change. AggregateID is
+// uuid.Nil: switches are global, not tied to an entity.
+type OperationSwitchChanged struct {
+	BaseEvent
+	Operation string
+	Enabled   bool
+	Reason    string // Empty when enabled
+	ActorID   uuid.UUID
+}
+
+func NewOperationSwitchChanged(operation string, enabled bool, reason string, actorID uuid.UUID) *OperationSwitchChanged {
+	return &OperationSwitchChanged{
+		BaseEvent: newBaseEvent(EventTypeOperationSwitched, uuid.Nil),
+		Operation: operation,
+		Enabled:   enabled,
+		Reason:    reason,
+		ActorID:   actorID,
+	}
+}
+
+// ===== Webhook Events =====
+
+// WebhookSubscriptionDisabled is raised when deliveries to a webhook endpoint
+// are turned off automatically because it kept failing. Reason is one of
+// entities.WebhookDisabled*. Pending deliveries are kept for the backlog
+// grace period, so the owner can fix the endpoint and re-enable it.
+type WebhookSubscriptionDisabled struct {
+	BaseEvent
+	SubscriptionID      uuid.UUID
+	UserID              uuid.UUID
+	URL                 string
+	Reason              string
+	ConsecutiveFailures int
+	FailingSince        time.Time
+	LastFailureReason   string
+}
+
+func NewWebhookSubscriptionDisabled(
+	subscriptionID, userID uuid.UUID,
+	url, reason string,
+	consecutiveFailures int,
+	failingSince time.Time,
+	lastFailureReason string,
+) *WebhookSubscriptionDisabled {
+	return &WebhookSubscriptionDisabled{
+		BaseEvent:           newBaseEvent(EventTypeWebhookDisabled, subscriptionID),
+		SubscriptionID:      subscriptionID,
+		UserID:              userID,
+		URL:                 url,
+		Reason:              reason,
+		ConsecutiveFailures: consecutiveFailures,
+		FailingSince:        failingSince.UTC(),
+		LastFailureReason:   lastFailureReason,
+	}
+}
+
 // EventStore is a simple in-memory store for events during a transaction.
 // In Phase 6, we'll replace this with Kafka publishing.
 //
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
 	"github.com/google/uuid"
 )
 
@@ -34,6 +35,34 @@ func TestBaseEvent(t *testing.T) {
 	}
 }
 
+// TestNewBaseEvent_OccurredAtFromClock tests that OccurredAt comes from the process clock
+func TestNewBaseEvent_OccurredAtFromClock(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	defer clock.SetClock(clock.NewFake(at))()
+
+	first := newBaseEvent("test.event", uuid.New())
+	second := newBaseEvent("test.event", uuid.New())
+
+	// Events of one tick share OccurredAt; order comes from sequence numbers
+	if !first.OccurredAt().Equal(at) || !second.OccurredAt().Equal(at) {
+		t.Errorf("OccurredAt = %v, %v, want %v", first.OccurredAt(), second.OccurredAt(), at)
+	}
+}
+
+// TestNewTransactionCompleted_CompletedAtFromClock tests that CompletedAt
+// agrees with OccurredAt under a fixed clock
+func TestNewTransactionCompleted_CompletedAtFromClock(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	defer clock.SetClock(clock.NewFake(at))()
+
+	amount, _ := valueobjects.NewMoney("10", valueobjects.USD)
+	event := NewTransactionCompleted(uuid.New(), uuid.New(), uuid.New(), "DEPOSIT", amount)
+
+	if !event.CompletedAt.Equal(at) || !event.OccurredAt().Equal(at) {
+		t.Errorf("CompletedAt = %v, OccurredAt = %v, want %v", event.CompletedAt, event.OccurredAt(), at)
+	}
+}
+
 // TestNewUserCreated tests UserCreated event creation
 func TestNewUserCreated(t *testing.T) {
 	userID := uuid.New()
@@ -71,7 +100,7 @@ func TestNewUserCreated(t *testing.T) {
 func TestNewUserKYCApproved(t *testing.T) {
 	userID := uuid.New()
 
-	event := NewUserKYCApproved(userID)
+	event := NewUserKYCApproved(userID, uuid.New())
 
 	if event.EventType() != EventTypeUserKYCApproved {
 		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeUserKYCApproved)
@@ -89,9 +118,10 @@ func TestNewUserKYCApproved(t *testing.T) {
 // TestNewUserKYCRejected tests UserKYCRejected event creation
 func TestNewUserKYCRejected(t *testing.T) {
 	userID := uuid.New()
+	actorID := uuid.New()
 	reason := "Document expired"
 
-	event := NewUserKYCRejected(userID, reason)
+	event := NewUserKYCRejected(userID, reason, actorID)
 
 	if event.EventType() != EventTypeUserKYCRejected {
 		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeUserKYCRejected)
@@ -108,6 +138,30 @@ func TestNewUserKYCRejected(t *testing.T) {
 	if event.Reason != reason {
 		t.Errorf("Reason = %q, want %q", event.Reason, reason)
 	}
+
+	if event.ActorID != actorID {
+		t.Errorf("ActorID = %v, want %v", event.ActorID, actorID)
+	}
+}
+
+// TestNewUserKYCRevoked tests UserKYCRevoked event creation
+func TestNewUserKYCRevoked(t *testing.T) {
+	userID := uuid.New()
+	actorID := uuid.New()
+
+	event := NewUserKYCRevoked(userID, "Sanctions list match", actorID)
+
+	if event.EventType() != EventTypeUserKYCRevoked {
+		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeUserKYCRevoked)
+	}
+
+	if event.AggregateID() != userID || event.UserID != userID {
+		t.Errorf("AggregateID = %v, UserID = %v, want %v", event.AggregateID(), event.UserID, userID)
+	}
+
+	if event.Reason != "Sanctions list match" || event.ActorID != actorID {
+		t.Errorf("Reason = %q, ActorID = %v", event.Reason, event.ActorID)
+	}
 }
 
 // TestNewWalletCreated tests WalletCreated event creation
@@ -142,7 +196,7 @@ func TestNewWalletCredited(t *testing.T) {
 	amount, _ := valueobjects.NewMoneyFromInt(100, valueobjects.USD)
 	balanceAfter, _ := valueobjects.NewMoneyFromInt(150, valueobjects.USD)
 
-	event := NewWalletCredited(walletID, amount, transactionID, balanceAfter)
+	event := NewWalletCredited(walletID, amount, transactionID, balanceAfter, 3)
 
 	if event.EventType() != EventTypeWalletCredited {
 		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeWalletCredited)
@@ -167,6 +221,10 @@ func TestNewWalletCredited(t *testing.T) {
 	if !event.BalanceAfter.Equals(balanceAfter) {
 		t.Errorf("BalanceAfter = %v, want %v", event.BalanceAfter, balanceAfter)
 	}
+
+	if event.BalanceVersion != 3 {
+		t.Errorf("BalanceVersion = %d, want 3", event.BalanceVersion)
+	}
 }
 
 // TestNewWalletDebited tests WalletDebited event creation
@@ -176,7 +234,7 @@ func TestNewWalletDebited(t *testing.T) {
 	amount, _ := valueobjects.NewMoneyFromInt(50, valueobjects.USD)
 	balanceAfter, _ := valueobjects.NewMoneyFromInt(100, valueobjects.USD)
 
-	event := NewWalletDebited(walletID, amount, transactionID, balanceAfter)
+	event := NewWalletDebited(walletID, amount, transactionID, balanceAfter, 4)
 
 	if event.EventType() != EventTypeWalletDebited {
 		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeWalletDebited)
@@ -201,6 +259,10 @@ func TestNewWalletDebited(t *testing.T) {
 	if !event.BalanceAfter.Equals(balanceAfter) {
 		t.Errorf("BalanceAfter = %v, want %v", event.BalanceAfter, balanceAfter)
 	}
+
+	if event.BalanceVersion != 4 {
+		t.Errorf("BalanceVersion = %d, want 4", event.BalanceVersion)
+	}
 }
 
 // TestNewWalletSuspended tests WalletSuspended event creation
@@ -227,15 +289,182 @@ func TestNewWalletSuspended(t *testing.T) {
 	}
 }
 
+// TestNewWalletClosed tests WalletClosed event creation
+func TestNewWalletClosed(t *testing.T) {
+	walletID := uuid.New()
+	destinationID := uuid.New()
+	transactionID := uuid.New()
+	swept, _ := valueobjects.NewMoney("42.50", valueobjects.USD)
+
+	event := NewWalletClosed(walletID, destinationID, swept, transactionID)
+
+	if event.EventType() != EventTypeWalletClosed {
+		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeWalletClosed)
+	}
+
+	if event.AggregateID() != walletID {
+		t.Errorf("AggregateID = %v, want %v", event.AggregateID(), walletID)
+	}
+
+	if event.DestinationWalletID != destinationID {
+		t.Errorf("DestinationWalletID = %v, want %v", event.DestinationWalletID, destinationID)
+	}
+
+	if !event.SweptAmount.Equals(swept) {
+		t.Errorf("SweptAmount = %s, want %s", event.SweptAmount.String(), swept.String())
+	}
+
+	if event.TransactionID != transactionID {
+		t.Errorf("TransactionID = %v, want %v", event.TransactionID, transactionID)
+	}
+}
+
+// TestNewWalletLimitsUpdated tests WalletLimitsUpdated event creation
+func TestNewWalletLimitsUpdated(t *testing.T) {
+	walletID := uuid.New()
+	daily, _ := valueobjects.NewMoney("500.00", valueobjects.USD)
+	monthly, _ := valueobjects.NewMoney("5000.00", valueobjects.USD)
+
+	event := NewWalletLimitsUpdated(walletID, daily, monthly)
+
+	if event.EventType() != EventTypeWalletLimitsUpdated {
+		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeWalletLimitsUpdated)
+	}
+
+	if event.AggregateID() != walletID {
+		t.Errorf("AggregateID = %v, want %v", event.AggregateID(), walletID)
+	}
+
+	if !event.DailyLimit.Equals(daily) {
+		t.Errorf("DailyLimit = %s, want %s", event.DailyLimit.String(), daily.String())
+	}
+
+	if !event.MonthlyLimit.Equals(monthly) {
+		t.Errorf("MonthlyLimit = %s, want %s", event.MonthlyLimit.String(), monthly.String())
+	}
+}
+
+// TestNewWalletBalanceSummary tests WalletBalanceSummary event creation
+func TestNewWalletBalanceSummary(t *testing.T) {
+	walletID := uuid.New()
+	transactionID := uuid.New()
+	available, _ := valueobjects.NewMoney("120.00", valueobjects.USD)
+	pending, _ := valueobjects.NewMoney("5.00", valueobjects.USD)
+	windowStart := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*3600))
+
+	event := NewWalletBalanceSummary(walletID, available, pending, 7, transactionID, windowStart, windowStart.Add(5*time.Second))
+
+	if event.EventType() != EventTypeWalletBalanceSummary {
+		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeWalletBalanceSummary)
+	}
+	if event.AggregateID() != walletID {
+		t.Errorf("AggregateID = %v, want %v", event.AggregateID(), walletID)
+	}
+	if !event.AvailableBalance.Equals(available) || !event.PendingBalance.Equals(pending) {
+		t.Errorf("Balances = %s/%s, want %s/%s", event.AvailableBalance, event.PendingBalance, available, pending)
+	}
+	if event.ChangeCount != 7 || event.LastTransactionID != transactionID {
+		t.Errorf("ChangeCount/LastTransactionID = %d/%v, want 7/%v", event.ChangeCount, event.LastTransactionID, transactionID)
+	}
+	if event.WindowStart.Location() != time.UTC || !event.WindowEnd.Equal(windowStart.Add(5*time.Second)) {
+		t.Errorf("Window = %v..%v, want UTC window", event.WindowStart, event.WindowEnd)
+	}
+}
+
+// TestNewWalletNoteAdded tests WalletNoteAdded event creation
+func TestNewWalletNoteAdded(t *testing.T) {
+	walletID, noteID, authorID := uuid.New(), uuid.New(), uuid.New()
+
+	event := NewWalletNoteAdded(walletID, noteID, authorID)
+
+	if event.EventType() != EventTypeWalletNoteAdded {
+		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeWalletNoteAdded)
+	}
+	if event.AggregateID() != walletID {
+		t.Errorf("AggregateID = %v, want %v", event.AggregateID(), walletID)
+	}
+	if event.NoteID != noteID || event.AuthorID != authorID {
+		t.Errorf("NoteID/AuthorID = %v/%v, want %v/%v", event.NoteID, event.AuthorID, noteID, authorID)
+	}
+}
+
+// TestNewWalletMetadataQuotaWarning tests WalletMetadataQuotaWarning event creation
+func TestNewWalletMetadataQuotaWarning(t *testing.T) {
+	walletID, userID, transactionID := uuid.New(), uuid.New(), uuid.New()
+
+	event := NewWalletMetadataQuotaWarning(walletID, userID, transactionID, 1100, 1000)
+
+	if event.EventType() != EventTypeWalletMetadataQuota {
+		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeWalletMetadataQuota)
+	}
+	if event.AggregateID() != walletID || event.TransactionID != transactionID {
+		t.Errorf("AggregateID/TransactionID = %v/%v, want %v/%v", event.AggregateID(), event.TransactionID, walletID, transactionID)
+	}
+	if event.UsedBytes != 1100 || event.SoftLimitBytes != 1000 {
+		t.Errorf("UsedBytes/SoftLimitBytes = %d/%d, want 1100/1000", event.UsedBytes, event.SoftLimitBytes)
+	}
+}
+
+// TestNewWebhookSubscriptionDisabled tests WebhookSubscriptionDisabled event creation
+func TestNewWebhookSubscriptionDisabled(t *testing.T) {
+	subscriptionID, userID := uuid.New(), uuid.New()
+	failingSince := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+
+	event := NewWebhookSubscriptionDisabled(subscriptionID, userID, "https://example.com/hooks",
+		"consecutive_failures", 50, failingSince, "HTTP 404")
+
+	if event.EventType() != EventTypeWebhookDisabled {
+		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeWebhookDisabled)
+	}
+	if event.AggregateID() != subscriptionID || event.UserID != userID {
+		t.Errorf("AggregateID/UserID = %v/%v, want %v/%v", event.AggregateID(), event.UserID, subscriptionID, userID)
+	}
+	if event.ConsecutiveFailures != 50 || event.FailingSince.Location() != time.UTC || !event.FailingSince.Equal(failingSince) {
+		t.Errorf("ConsecutiveFailures/FailingSince = %d/%v", event.ConsecutiveFailures, event.FailingSince)
+	}
+}
+
+// TestNewSuspiciousAuthActivity tests SuspiciousAuthActivity event creation
+func TestNewSuspiciousAuthActivity(t *testing.T) {
+	windowStart := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("user principal", func(t *testing.T) {
+		userID := uuid.New()
+		event := NewSuspiciousAuthActivity(userID.String(), 6, windowStart, 5*time.Minute)
+
+		if event.EventType() != EventTypeSuspiciousAuth {
+			t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeSuspiciousAuth)
+		}
+		if event.AggregateID() != userID {
+			t.Errorf("AggregateID = %v, want %v", event.AggregateID(), userID)
+		}
+		if event.Failures != 6 || event.Window != 5*time.Minute || !event.WindowStart.Equal(windowStart) {
+			t.Errorf("unexpected payload: %+v", event)
+		}
+	})
+
+	t.Run("credential prefix principal", func(t *testing.T) {
+		event := NewSuspiciousAuthActivity("key:abcd1234", 6, windowStart, 5*time.Minute)
+
+		if event.AggregateID() != uuid.Nil {
+			t.Errorf("AggregateID = %v, want uuid.Nil", event.AggregateID())
+		}
+		if event.Principal != "key:abcd1234" {
+			t.Errorf("Principal = %q, want key:abcd1234", event.Principal)
+		}
+	})
+}
+
 // TestNewTransactionCreated tests TransactionCreated event creation
 func TestNewTransactionCreated(t *testing.T) {
 	transactionID := uuid.New()
 	walletID := uuid.New()
+	userID := uuid.New()
 	txType := "DEPOSIT"
 	amount, _ := valueobjects.NewMoneyFromInt(100, valueobjects.USD)
 	idempotencyKey := "key-123"
 
-	event := NewTransactionCreated(transactionID, walletID, txType, amount, idempotencyKey)
+	event := NewTransactionCreated(transactionID, walletID, userID, txType, amount, idempotencyKey)
 
 	if event.EventType() != EventTypeTransactionCreated {
 		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeTransactionCreated)
@@ -253,6 +482,10 @@ func TestNewTransactionCreated(t *testing.T) {
 		t.Errorf("WalletID = %v, want %v", event.WalletID, walletID)
 	}
 
+	if event.UserID != userID {
+		t.Errorf("UserID = %v, want %v", event.UserID, userID)
+	}
+
 	if event.TransactionType != txType {
 		t.Errorf("TransactionType = %q, want %q", event.TransactionType, txType)
 	}
@@ -270,10 +503,11 @@ func TestNewTransactionCreated(t *testing.T) {
 func TestNewTransactionCompleted(t *testing.T) {
 	transactionID := uuid.New()
 	walletID := uuid.New()
+	userID := uuid.New()
 	txType := "DEPOSIT"
 	amount, _ := valueobjects.NewMoneyFromInt(100, valueobjects.USD)
 
-	event := NewTransactionCompleted(transactionID, walletID, txType, amount)
+	event := NewTransactionCompleted(transactionID, walletID, userID, txType, amount)
 
 	if event.EventType() != EventTypeTransactionCompleted {
 		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeTransactionCompleted)
@@ -291,6 +525,10 @@ func TestNewTransactionCompleted(t *testing.T) {
 		t.Errorf("WalletID = %v, want %v", event.WalletID, walletID)
 	}
 
+	if event.UserID != userID {
+		t.Errorf("UserID = %v, want %v", event.UserID, userID)
+	}
+
 	if event.TransactionType != txType {
 		t.Errorf("TransactionType = %q, want %q", event.TransactionType, txType)
 	}
@@ -302,18 +540,36 @@ func TestNewTransactionCompleted(t *testing.T) {
 	if event.CompletedAt.IsZero() {
 		t.Error("CompletedAt should be set")
 	}
+
+	if event.CounterpartyWalletID != uuid.Nil || event.CounterpartyUserID != uuid.Nil {
+		t.Error("Counterparty should be empty for non-transfer transactions")
+	}
+
+	destinationWalletID := uuid.New()
+	destinationUserID := uuid.New()
+	event.WithCounterparty(destinationWalletID, destinationUserID)
+
+	if event.CounterpartyWalletID != destinationWalletID {
+		t.Errorf("CounterpartyWalletID = %v, want %v", event.CounterpartyWalletID, destinationWalletID)
+	}
+
+	if event.CounterpartyUserID != destinationUserID {
+		t.Errorf("CounterpartyUserID = %v, want %v", event.CounterpartyUserID, destinationUserID)
+	}
 }
 
 // TestNewTransactionFailed tests TransactionFailed event creation
 func TestNewTransactionFailed(t *testing.T) {
 	transactionID := uuid.New()
 	walletID := uuid.New()
+	userID := uuid.New()
 	txType := "DEPOSIT"
 	amount, _ := valueobjects.NewMoneyFromInt(100, valueobjects.USD)
 	failureReason := "Network timeout"
+	failureCategory := "PROVIDER"
 	isRetryable := true
 
-	event := NewTransactionFailed(transactionID, walletID, txType, amount, failureReason, isRetryable)
+	event := NewTransactionFailed(transactionID, walletID, userID, txType, amount, failureReason, failureCategory, isRetryable)
 
 	if event.EventType() != EventTypeTransactionFailed {
 		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeTransactionFailed)
@@ -331,6 +587,10 @@ func TestNewTransactionFailed(t *testing.T) {
 		t.Errorf("WalletID = %v, want %v", event.WalletID, walletID)
 	}
 
+	if event.UserID != userID {
+		t.Errorf("UserID = %v, want %v", event.UserID, userID)
+	}
+
 	if event.TransactionType != txType {
 		t.Errorf("TransactionType = %q, want %q", event.TransactionType, txType)
 	}
@@ -343,11 +603,43 @@ func TestNewTransactionFailed(t *testing.T) {
 		t.Errorf("FailureReason = %q, want %q", event.FailureReason, failureReason)
 	}
 
+	if event.FailureCategory != failureCategory {
+		t.Errorf("FailureCategory = %q, want %q", event.FailureCategory, failureCategory)
+	}
+
 	if event.IsRetryable != isRetryable {
 		t.Errorf("IsRetryable = %v, want %v", event.IsRetryable, isRetryable)
 	}
 }
 
+// TestNewTransactionFlagged tests TransactionFlagged event creation
+func TestNewTransactionFlagged(t *testing.T) {
+	transactionID := uuid.New()
+	walletID := uuid.New()
+	amount, _ := valueobjects.NewMoneyFromInt(6000, valueobjects.USD)
+	ruleIDs := []string{"new-wallet-withdraw", "unverified-large"}
+
+	event := NewTransactionFlagged(transactionID, walletID, "WITHDRAW", amount, ruleIDs)
+
+	if event.EventType() != EventTypeTransactionFlagged {
+		t.Errorf("EventType = %q, want %q", event.EventType(), EventTypeTransactionFlagged)
+	}
+
+	if event.AggregateID() != transactionID {
+		t.Errorf("AggregateID = %v, want %v", event.AggregateID(), transactionID)
+	}
+
+	if event.WalletID != walletID {
+		t.Errorf("WalletID = %v, want %v", event.WalletID, walletID)
+	}
+
+	// The event keeps its own copy of the rule IDs
+	ruleIDs[0] = "changed"
+	if len(event.RuleIDs) != 2 || event.RuleIDs[0] != "new-wallet-withdraw" {
+		t.Errorf("RuleIDs = %v, want [new-wallet-withdraw unverified-large]", event.RuleIDs)
+	}
+}
+
 // TestEventTypeConstants tests event type constants
 func TestEventTypeConstants(t *testing.T) {
 	constants := map[string]string{
@@ -358,9 +650,18 @@ func TestEventTypeConstants(t *testing.T) {
 		"EventTypeWalletCredited":       EventTypeWalletCredited,
 		"EventTypeWalletDebited":        EventTypeWalletDebited,
 		"EventTypeWalletSuspended":      EventTypeWalletSuspended,
+		"EventTypeWalletClosed":         EventTypeWalletClosed,
+		"EventTypeWalletLimitsUpdated":  EventTypeWalletLimitsUpdated,
+		"EventTypeWalletBalanceSummary": EventTypeWalletBalanceSummary,
+		"EventTypeWalletNoteAdded":      EventTypeWalletNoteAdded,
+		"EventTypeWalletMetadataQuota":  EventTypeWalletMetadataQuota,
 		"EventTypeTransactionCreated":   EventTypeTransactionCreated,
 		"EventTypeTransactionCompleted": EventTypeTransactionCompleted,
 		"EventTypeTransactionFailed":    EventTypeTransactionFailed,
+		"EventTypeTransactionFlagged":   EventTypeTransactionFlagged,
+		"EventTypeSuspiciousAuth":       EventTypeSuspiciousAuth,
+		"EventTypeOperationSwitched":    EventTypeOperationSwitched,
+		"EventTypeWebhookDisabled":      EventTypeWebhookDisabled,
 	}
 
 	for name, value := range constants {
@@ -393,7 +694,7 @@ func TestEventStore_Add(t *testing.T) {
 	userID := uuid.New()
 
 	event1 := NewUserCreated(userID, "test@example.com", "Test User")
-	event2 := NewUserKYCApproved(userID)
+	event2 := NewUserKYCApproved(userID, uuid.New())
 
 	store.Add(event1)
 
@@ -414,7 +715,7 @@ func TestEventStore_GetAll(t *testing.T) {
 	userID := uuid.New()
 
 	event1 := NewUserCreated(userID, "test@example.com", "Test User")
-	event2 := NewUserKYCApproved(userID)
+	event2 := NewUserKYCApproved(userID, uuid.New())
 
 	store.Add(event1)
 	store.Add(event2)
@@ -441,7 +742,7 @@ func TestEventStore_Clear(t *testing.T) {
 	userID := uuid.New()
 
 	store.Add(NewUserCreated(userID, "test@example.com", "Test User"))
-	store.Add(NewUserKYCApproved(userID))
+	store.Add(NewUserKYCApproved(userID, uuid.New()))
 
 	if store.Count() != 2 {
 		t.Fatalf("Setup failed: Count = %d, want 2", store.Count())
@@ -470,8 +771,8 @@ func TestEventStore_Count(t *testing.T) {
 	}{
 		{"Initial count", func() {}, 0},
 		{"After 1 add", func() { store.Add(NewUserCreated(userID, "test@example.com", "Test")) }, 1},
-		{"After 2 adds", func() { store.Add(NewUserKYCApproved(userID)) }, 2},
-		{"After 3 adds", func() { store.Add(NewUserKYCRejected(userID, "test")) }, 3},
+		{"After 2 adds", func() { store.Add(NewUserKYCApproved(userID, uuid.New())) }, 2},
+		{"After 3 adds", func() { store.Add(NewUserKYCRejected(userID, "test", uuid.New())) }, 3},
 		{"After clear", func() { store.Clear() }, 0},
 	}
 
@@ -496,7 +797,7 @@ func TestEventStore_MultipleEventTypes(t *testing.T) {
 	// Add different event types
 	store.Add(NewUserCreated(userID, "test@example.com", "Test User"))
 	store.Add(NewWalletCreated(walletID, userID, valueobjects.USD))
-	store.Add(NewTransactionCreated(transactionID, walletID, "DEPOSIT", amount, "key-123"))
+	store.Add(NewTransactionCreated(transactionID, walletID, uuid.New(), "DEPOSIT", amount, "key-123"))
 
 	events := store.GetAll()
 
@@ -529,15 +830,15 @@ func TestEventInterface_Compliance(t *testing.T) {
 
 	events := []DomainEvent{
 		NewUserCreated(userID, "test@example.com", "Test User"),
-		NewUserKYCApproved(userID),
-		NewUserKYCRejected(userID, "reason"),
+		NewUserKYCApproved(userID, uuid.New()),
+		NewUserKYCRejected(userID, "reason", uuid.New()),
 		NewWalletCreated(walletID, userID, valueobjects.USD),
-		NewWalletCredited(walletID, amount, transactionID, amount),
-		NewWalletDebited(walletID, amount, transactionID, amount),
+		NewWalletCredited(walletID, amount, transactionID, amount, 1),
+		NewWalletDebited(walletID, amount, transactionID, amount, 2),
 		NewWalletSuspended(walletID, "reason"),
-		NewTransactionCreated(transactionID, walletID, "DEPOSIT", amount, "key"),
-		NewTransactionCompleted(transactionID, walletID, "DEPOSIT", amount),
-		NewTransactionFailed(transactionID, walletID, "DEPOSIT", amount, "reason", true),
+		NewTransactionCreated(transactionID, walletID, userID, "DEPOSIT", amount, "key"),
+		NewTransactionCompleted(transactionID, walletID, userID, "DEPOSIT", amount),
+		NewTransactionFailed(transactionID, walletID, userID, "DEPOSIT", amount, "reason", "PROVIDER", true),
 	}
 
 	for i, event := range events {
@@ -9,6 +9,7 @@ package valueobjects
 
 import (
 	"errors"
+	"sort"
 	"strings"
 )
 
@@ -45,6 +46,17 @@ var supportedCurrencies = map[string]bool{
 	"USDC": true,
 }
 
+// currencySymbols are display symbols; a currency without one is shown by its code.
+var currencySymbols = map[string]string{
+	"USD":  "$",
+	"EUR":  "€",
+	"GBP":  "£",
+	"RUB":  "₽",
+	"BTC":  "₿",
+	"ETH":  "Ξ",
+	"USDT": "₮",
+}
+
 // ErrInvalidCurrency is returned when an invalid currency code is provided.
 // Using typed errors (instead of strings) allows callers to handle specific error cases.
 var ErrInvalidCurrency = errors.New("invalid currency code")
@@ -74,6 +86,17 @@ func NewCurrency(code string) (Currency, error) {
 	return Currency{code: code}, nil
 }
 
+// SupportedCurrencies returns the whole currency registry sorted by code.
+// Clients get it from GET /api/v1/meta/currencies instead of hardcoding it.
+func SupportedCurrencies() []Currency {
+	currencies := make([]Currency, 0, len(supportedCurrencies))
+	for code := range supportedCurrencies {
+		currencies = append(currencies, Currency{code: code})
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i].code < currencies[j].code })
+	return currencies
+}
+
 // MustNewCurrency is a convenience function that panics on invalid input.
 // Use only in initialization code where invalid input indicates a programming error.
 func MustNewCurrency(code string) Currency {
@@ -118,6 +141,23 @@ func (c Currency) IsFiat() bool {
 	return !c.IsCrypto()
 }
 
+// Decimals returns the number of digits after the decimal point in the
+// smallest unit of the currency: 8 for crypto (satoshi), 2 for fiat (cent).
+func (c Currency) Decimals() int {
+	if c.IsCrypto() {
+		return 8
+	}
+	return 2
+}
+
+// Symbol returns the display symbol of the currency, or its code if it has none.
+func (c Currency) Symbol() string {
+	if symbol, ok := currencySymbols[c.code]; ok {
+		return symbol
+	}
+	return c.code
+}
+
 // IsZero checks if this is an uninitialized currency.
 // Useful for optional currency fields.
 func (c Currency) IsZero() bool {
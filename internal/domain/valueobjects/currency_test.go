@@ -275,3 +275,29 @@ func TestCurrency_ImmutabilityThroughEquals(t *testing.T) {
 		t.Error("Currencies with different codes should not be equal")
 	}
 }
+
+// TestSupportedCurrencies tests that the registry is complete, sorted and valid.
+func TestSupportedCurrencies(t *testing.T) {
+	currencies := valueobjects.SupportedCurrencies()
+	if len(currencies) != 8 {
+		t.Fatalf("SupportedCurrencies() returned %d currencies, want 8", len(currencies))
+	}
+	for i, currency := range currencies {
+		if _, err := valueobjects.NewCurrency(currency.Code()); err != nil {
+			t.Errorf("SupportedCurrencies() returned unsupported %s", currency)
+		}
+		if i > 0 && currencies[i-1].Code() >= currency.Code() {
+			t.Errorf("SupportedCurrencies() not sorted at %s", currency)
+		}
+	}
+}
+
+// TestCurrency_Symbol tests display symbols with the code as fallback.
+func TestCurrency_Symbol(t *testing.T) {
+	if got := valueobjects.USD.Symbol(); got != "$" {
+		t.Errorf("USD.Symbol() = %q, want $", got)
+	}
+	if got := valueobjects.MustNewCurrency("USDC").Symbol(); got != "USDC" {
+		t.Errorf("USDC.Symbol() = %q, want USDC", got)
+	}
+}
@@ -0,0 +1,152 @@
+package valueobjects
+
+import (
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/idna"
+
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Email length limits (RFC 5321: 254 for the path, 64 for the local part).
+const (
+	MaxEmailLength     = 254
+	maxLocalPartLength = 64
+	maxDomainLabel     = 63
+)
+
+// ParseEmail validates a single bare address and returns its canonical form:
+// trimmed and lowercased, with an internationalized domain converted to punycode.
+//
+// Rules on top of net/mail.ParseAddress:
+//   - one address only, no display name or angle brackets
+//   - at most MaxEmailLength characters
+//   - the domain has at least one dot and valid DNS labels
+//
+// Errors wrap domain errors.ErrInvalidEmail.
+func ParseEmail(raw string) (string, error) {
+	email := strings.TrimSpace(raw)
+	if email == "" {
+		return "", invalidEmail("address is empty")
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return "", invalidEmail(err.Error())
+	}
+	// "Name <a@b.com>", "<a@b.com>" and comments parse fine but are not a bare address
+	if addr.Name != "" || strings.HasPrefix(email, "<") {
+		return "", invalidEmail("display names and angle brackets are not allowed")
+	}
+
+	// String() re-quotes the local part only where RFC 5322 requires it
+	spec := strings.TrimSuffix(strings.TrimPrefix(addr.String(), "<"), ">")
+	at := strings.LastIndex(spec, "@")
+	local, domain := spec[:at], spec[at+1:]
+	if len(local) > maxLocalPartLength {
+		return "", invalidEmail("local part is too long")
+	}
+
+	domain, err = normalizeEmailDomain(domain)
+	if err != nil {
+		return "", err
+	}
+
+	email = strings.ToLower(local) + "@" + domain
+	if len(email) > MaxEmailLength {
+		return "", invalidEmail("address is too long")
+	}
+	return email, nil
+}
+
+// normalizeEmailDomain converts the domain to lowercase ASCII (punycode) and checks its labels.
+func normalizeEmailDomain(domain string) (string, error) {
+	ascii, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return "", invalidEmail("invalid domain")
+	}
+	ascii = strings.ToLower(ascii)
+
+	labels := strings.Split(ascii, ".")
+	if len(labels) < 2 {
+		return "", invalidEmail("domain must contain a dot")
+	}
+	for _, label := range labels {
+		if !isDomainLabel(label) {
+			return "", invalidEmail("invalid domain")
+		}
+	}
+	return ascii, nil
+}
+
+// isDomainLabel reports whether s is a lowercase LDH label (letters, digits, inner hyphens).
+func isDomainLabel(s string) bool {
+	if s == "" || len(s) > maxDomainLabel || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+func invalidEmail(reason string) error {
+	return fmt.Errorf("%w: %s", domainErrors.ErrInvalidEmail, reason)
+}
+
+// EmailPolicy decides which addresses count as the same mailbox.
+//
+// The normalized form is used only for the uniqueness check; the stored
+// address keeps its plus-tag and dots. Lowercasing is always applied; tags
+// and dots in the local part are stripped only for the listed providers,
+// where delivery ignores them (gmail).
+type EmailPolicy struct {
+	StripPlusTags bool
+	StripDots     bool
+	Providers     []string // e.g. "gmail.com"
+}
+
+// DefaultEmailPolicy strips plus-tags and dots for gmail addresses.
+// The users.normalized_email backfill (migration 000015) applies the same rules.
+func DefaultEmailPolicy() EmailPolicy {
+	return EmailPolicy{
+		StripPlusTags: true,
+		StripDots:     true,
+		Providers:     []string{"gmail.com", "googlemail.com"},
+	}
+}
+
+// Normalize returns the uniqueness key of an address.
+// The address is expected to be parsed by ParseEmail; other input is only lowercased.
+func (p EmailPolicy) Normalize(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	local, domain := email[:at], email[at+1:]
+
+	isProvider := slices.ContainsFunc(p.Providers, func(d string) bool { return strings.EqualFold(d, domain) })
+
+	// Quoted local parts are compared verbatim
+	if strings.HasPrefix(local, `"`) || !isProvider {
+		return email
+	}
+
+	if p.StripPlusTags {
+		if tag := strings.Index(local, "+"); tag > 0 {
+			local = local[:tag]
+		}
+	}
+	if p.StripDots {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	return local + "@" + domain
+}
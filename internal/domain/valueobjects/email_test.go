@@ -0,0 +1,126 @@
+package valueobjects_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// TestParseEmail_Valid tests addresses that are unusual but valid.
+func TestParseEmail_Valid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Simple", input: "user@example.com", want: "user@example.com"},
+		{name: "MixedCase", input: "John.Doe@Example.COM", want: "john.doe@example.com"},
+		{name: "Whitespace", input: "  user@example.com\t", want: "user@example.com"},
+		{name: "PlusTag", input: "user+tag@example.com", want: "user+tag@example.com"},
+		{name: "Subdomain", input: "user@mail.eu.example.co.uk", want: "user@mail.eu.example.co.uk"},
+		{name: "HyphenDomain", input: "user@my-host.example.com", want: "user@my-host.example.com"},
+		{name: "NumericLocal", input: "1234567890@example.com", want: "1234567890@example.com"},
+		{name: "SingleCharLocal", input: "x@example.com", want: "x@example.com"},
+		{name: "SpecialChars", input: "a!#$%&'*+/=?^_`{|}~-b@example.com", want: "a!#$%&'*+/=?^_`{|}~-b@example.com"},
+		{name: "QuotedLocalWithSpace", input: `"john doe"@example.com`, want: `"john doe"@example.com`},
+		{name: "QuotedLocalWithAt", input: `"a@b"@example.com`, want: `"a@b"@example.com`},
+		{name: "UnnecessaryQuotes", input: `"john"@example.com`, want: "john@example.com"},
+		{name: "Punycode", input: "user@XN--80AK6AA92E.com", want: "user@xn--80ak6aa92e.com"},
+		{name: "UnicodeDomain", input: "user@пример.рф", want: "user@xn--e1afmkfd.xn--p1ai"},
+		{name: "NumericTLD", input: "user@example.123", want: "user@example.123"},
+		{name: "MaxLocalPart", input: strings.Repeat("a", 64) + "@example.com", want: strings.Repeat("a", 64) + "@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := valueobjects.ParseEmail(tt.input)
+			if err != nil {
+				t.Fatalf("ParseEmail(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseEmail(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseEmail_Invalid tests addresses that must be rejected.
+func TestParseEmail_Invalid(t *testing.T) {
+	longDomain := strings.Repeat("a", 63) + "." + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) + "." + strings.Repeat("d", 60) + ".com"
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Empty", input: ""},
+		{name: "Blank", input: "   "},
+		{name: "NoAt", input: "invalid-email"},
+		{name: "NoDotInDomain", input: "a@b"},
+		{name: "Localhost", input: "user@localhost"},
+		{name: "DoubleAt", input: "a@@b.com"},
+		{name: "TwoAts", input: "a@b@c.com"},
+		{name: "EmptyLocal", input: "@example.com"},
+		{name: "EmptyDomain", input: "user@"},
+		{name: "SpaceInLocal", input: "user name@example.com"},
+		{name: "LeadingDot", input: ".user@example.com"},
+		{name: "ConsecutiveDots", input: "us..er@example.com"},
+		{name: "TrailingDotDomain", input: "user@example.com."},
+		{name: "EmptyLabel", input: "user@example..com"},
+		{name: "HyphenLabelStart", input: "user@-example.com"},
+		{name: "HyphenLabelEnd", input: "user@example-.com"},
+		{name: "Underscore", input: "user@exa_mple.com"},
+		{name: "DisplayName", input: "John <john@example.com>"},
+		{name: "AngleBrackets", input: "<john@example.com>"},
+		{name: "Comment", input: "john@example.com (John)"},
+		{name: "MultipleAddresses", input: "a@example.com, b@example.com"},
+		{name: "IPLiteral", input: "user@[192.168.0.1]"},
+		{name: "LocalTooLong", input: strings.Repeat("a", 65) + "@example.com"},
+		{name: "LabelTooLong", input: "user@" + strings.Repeat("a", 64) + ".com"},
+		{name: "TooLong", input: "user@" + longDomain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := valueobjects.ParseEmail(tt.input)
+			if err == nil {
+				t.Fatalf("ParseEmail(%q) = %q, want error", tt.input, got)
+			}
+			if !errors.Is(err, domainErrors.ErrInvalidEmail) {
+				t.Errorf("ParseEmail(%q) error = %v, want ErrInvalidEmail", tt.input, err)
+			}
+		})
+	}
+}
+
+// TestEmailPolicy_Normalize tests the uniqueness key of addresses.
+func TestEmailPolicy_Normalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy valueobjects.EmailPolicy
+		input  string
+		want   string
+	}{
+		{name: "GmailPlusTag", policy: valueobjects.DefaultEmailPolicy(), input: "user+tag@gmail.com", want: "user@gmail.com"},
+		{name: "GmailDots", policy: valueobjects.DefaultEmailPolicy(), input: "u.s.e.r@gmail.com", want: "user@gmail.com"},
+		{name: "GmailBoth", policy: valueobjects.DefaultEmailPolicy(), input: "User.Name+news@GMAIL.com", want: "username@gmail.com"},
+		{name: "Googlemail", policy: valueobjects.DefaultEmailPolicy(), input: "user+x@googlemail.com", want: "user@googlemail.com"},
+		{name: "OtherProviderKeepsTag", policy: valueobjects.DefaultEmailPolicy(), input: "User+tag@Example.com", want: "user+tag@example.com"},
+		{name: "OtherProviderKeepsDots", policy: valueobjects.DefaultEmailPolicy(), input: "first.last@example.com", want: "first.last@example.com"},
+		{name: "LeadingPlusKept", policy: valueobjects.DefaultEmailPolicy(), input: "+tag@gmail.com", want: "+tag@gmail.com"},
+		{name: "QuotedLocalVerbatim", policy: valueobjects.DefaultEmailPolicy(), input: `"a.b+c"@gmail.com`, want: `"a.b+c"@gmail.com`},
+		{name: "LowercaseOnly", policy: valueobjects.EmailPolicy{}, input: "User.Name+tag@Gmail.com", want: "user.name+tag@gmail.com"},
+		{name: "TagsOnly", policy: valueobjects.EmailPolicy{StripPlusTags: true, Providers: []string{"gmail.com"}}, input: "user.name+tag@gmail.com", want: "user.name@gmail.com"},
+		{name: "ProviderCaseInsensitive", policy: valueobjects.EmailPolicy{StripPlusTags: true, Providers: []string{"Gmail.COM"}}, input: "user+tag@gmail.com", want: "user@gmail.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Normalize(tt.input); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package valueobjects
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"strings"
+)
+
+// Idempotency keys of transactions the system creates on its own behalf
+// (sweep on close, fee of a parent transaction, snapshot opening balance)
+// are derived from the inputs of the flow, so a retried flow produces the
+// same key and hits the unique constraint instead of booking twice.
+//
+// Derived keys live under the reserved "sys:" prefix. Client keys with this
+// prefix are rejected at the API boundary, so a client can never pre-claim
+// or replay a key of an internal transaction.
+const (
+	// SystemIdempotencyKeyPrefix marks keys derived by the system.
+	SystemIdempotencyKeyPrefix = "sys:"
+	// MaxIdempotencyKeyLength is the size of transactions.idempotency_key.
+	MaxIdempotencyKeyLength = 255
+)
+
+// DerivedIdempotencyKey returns "sys:<namespace>:<sha256 hex>" for the parts.
+//
+// The hash covers the namespace and every part with its length, so
+// ("ab", "c") and ("a", "bc") differ, and identical parts in different
+// namespaces never collide. An oversized namespace is truncated to keep the
+// key within MaxIdempotencyKeyLength; the hash is always kept whole.
+func DerivedIdempotencyKey(namespace string, parts ...string) string {
+	h := sha256.New()
+	writeKeyPart(h, namespace)
+	for _, part := range parts {
+		writeKeyPart(h, part)
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	room := MaxIdempotencyKeyLength - len(SystemIdempotencyKeyPrefix) - len(sum) - 1
+	if len(namespace) > room {
+		namespace = namespace[:room]
+	}
+	return SystemIdempotencyKeyPrefix + namespace + ":" + sum
+}
+
+// IsSystemIdempotencyKey reports whether key uses the reserved system prefix.
+// The check is case-insensitive: "SYS:" is reserved as well.
+func IsSystemIdempotencyKey(key string) bool {
+	return len(key) >= len(SystemIdempotencyKeyPrefix) &&
+		strings.EqualFold(key[:len(SystemIdempotencyKeyPrefix)], SystemIdempotencyKeyPrefix)
+}
+
+// writeKeyPart writes a length-prefixed part to the hash.
+func writeKeyPart(h hash.Hash, part string) {
+	var size [8]byte
+	binary.BigEndian.PutUint64(size[:], uint64(len(part)))
+	_, _ = h.Write(size[:])
+	_, _ = h.Write([]byte(part))
+}
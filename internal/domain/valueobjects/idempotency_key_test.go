@@ -0,0 +1,95 @@
+package valueobjects_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+func TestDerivedIdempotencyKey_Deterministic(t *testing.T) {
+	first := valueobjects.DerivedIdempotencyKey("sweep", "wallet-1")
+	second := valueobjects.DerivedIdempotencyKey("sweep", "wallet-1")
+
+	if first != second {
+		t.Errorf("Expected same key for same input, got %q and %q", first, second)
+	}
+	if !strings.HasPrefix(first, "sys:sweep:") || len(first) != len("sys:sweep:")+64 {
+		t.Errorf("Unexpected key format: %q", first)
+	}
+	if !valueobjects.IsSystemIdempotencyKey(first) {
+		t.Errorf("Expected derived key to be a system key: %q", first)
+	}
+}
+
+func TestDerivedIdempotencyKey_NoCollisions(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{
+			name: "SamePartsDifferentNamespaces",
+			a:    valueobjects.DerivedIdempotencyKey("sweep", "wallet-1"),
+			b:    valueobjects.DerivedIdempotencyKey("fee", "wallet-1"),
+		},
+		{
+			name: "PartBoundaries",
+			a:    valueobjects.DerivedIdempotencyKey("fee", "ab", "c"),
+			b:    valueobjects.DerivedIdempotencyKey("fee", "a", "bc"),
+		},
+		{
+			name: "NamespaceBoundary",
+			a:    valueobjects.DerivedIdempotencyKey("fee", "x"),
+			b:    valueobjects.DerivedIdempotencyKey("fe", "ex"),
+		},
+		{
+			name: "EmptyPart",
+			a:    valueobjects.DerivedIdempotencyKey("fee", "a"),
+			b:    valueobjects.DerivedIdempotencyKey("fee", "a", ""),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.a == tt.b {
+				t.Errorf("Expected different keys, both are %q", tt.a)
+			}
+		})
+	}
+}
+
+func TestDerivedIdempotencyKey_LongNamespace(t *testing.T) {
+	long := strings.Repeat("n", 300)
+
+	key := valueobjects.DerivedIdempotencyKey(long, "part")
+	if len(key) != valueobjects.MaxIdempotencyKeyLength {
+		t.Errorf("Expected key capped at %d, got %d", valueobjects.MaxIdempotencyKeyLength, len(key))
+	}
+
+	// Namespaces that share the truncated prefix still differ by hash
+	other := valueobjects.DerivedIdempotencyKey(long+"x", "part")
+	if key == other {
+		t.Error("Expected truncated namespaces to keep distinct keys")
+	}
+}
+
+func TestIsSystemIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"sys:fee:abc", true},
+		{"SYS:anything", true},
+		{"sys:", true},
+		{"sys", false},
+		{"system-key", false},
+		{"550e8400-e29b-41d4-a716-446655440000", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := valueobjects.IsSystemIdempotencyKey(tt.key); got != tt.want {
+			t.Errorf("IsSystemIdempotencyKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strconv"
 )
 
 // Money represents a monetary amount with its currency.
@@ -36,6 +37,7 @@ var (
 	ErrCurrencyMismatch   = errors.New("cannot operate on different currencies")
 	ErrInsufficientAmount = errors.New("insufficient amount")
 	ErrInvalidAmount      = errors.New("invalid amount format")
+	ErrAmountOverflow     = errors.New("amount exceeds the int64 range of minor units")
 )
 
 // NewMoney creates a Money instance from a string amount.
@@ -53,25 +55,38 @@ var (
 //
 //	money, err := NewMoney("100.50", USD)
 func NewMoney(amountStr string, currency Currency) (Money, error) {
+	amount, err := ParseAmount(amountStr)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{
+		amount:   amount,
+		currency: currency,
+	}, nil
+}
+
+// ParseAmount parses a decimal amount string without binding it to a currency.
+// Applies the same rules as NewMoney, so callers can validate an amount
+// before the currency is known (e.g., before the wallet is loaded).
+func ParseAmount(amountStr string) (*big.Rat, error) {
 	// Parse string to big.Rat
 	amount := new(big.Rat)
 	if _, ok := amount.SetString(amountStr); !ok {
-		return Money{}, fmt.Errorf("%w: %s", ErrInvalidAmount, amountStr)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAmount, amountStr)
 	}
 
 	// Business rule: Money cannot be negative (use different types for debits/credits)
 	if amount.Sign() < 0 {
-		return Money{}, ErrNegativeAmount
+		return nil, ErrNegativeAmount
 	}
 
-	return Money{
-		amount:   amount,
-		currency: currency,
-	}, nil
+	return amount, nil
 }
 
-// NewMoneyFromInt creates Money from an integer amount.
-// Useful for whole unit amounts.
+// NewMoneyFromInt creates Money from an integer amount of MAJOR units:
+// NewMoneyFromInt(100, USD) is $100.00, not $1.00. Integers that come from
+// clients or storage are minor units - use NewMoneyFromMinorUnits for them.
 func NewMoneyFromInt(amount int64, currency Currency) (Money, error) {
 	if amount < 0 {
 		return Money{}, ErrNegativeAmount
@@ -83,30 +98,36 @@ func NewMoneyFromInt(amount int64, currency Currency) (Money, error) {
 	}, nil
 }
 
-// NewMoneyFromCents creates Money from the smallest currency unit (cents, satoshis, wei).
-// This is the preferred way to store money in databases (as integer cents).
+// NewMoneyFromMinorUnits creates Money from the smallest currency unit
+// (cents, satoshis). The scale is taken from currency.Decimals().
 //
 // Example:
 //
-//	NewMoneyFromCents(10050, USD) // $100.50
-//	NewMoneyFromCents(100000000, BTC) // 1 BTC (100M satoshis)
-func NewMoneyFromCents(cents int64, currency Currency) (Money, error) {
-	if cents < 0 {
+//	NewMoneyFromMinorUnits(10050, USD)     // $100.50
+//	NewMoneyFromMinorUnits(100000000, BTC) // 1 BTC (100M satoshis)
+func NewMoneyFromMinorUnits(minor int64, currency Currency) (Money, error) {
+	if minor < 0 {
 		return Money{}, ErrNegativeAmount
 	}
 
-	// For crypto, use 8 decimals; for fiat, use 2
-	divisor := int64(100) // Default: 2 decimals
-	if currency.IsCrypto() {
-		divisor = 100000000 // 8 decimals for crypto
-	}
-
 	return Money{
-		amount:   big.NewRat(cents, divisor),
+		amount:   new(big.Rat).SetFrac(big.NewInt(minor), minorUnitScale(currency)),
 		currency: currency,
 	}, nil
 }
 
+// NewMoneyFromCents creates Money from the smallest currency unit (cents, satoshis, wei).
+// This is the preferred way to store money in databases (as integer cents).
+// Same as NewMoneyFromMinorUnits.
+//
+// Example:
+//
+//	NewMoneyFromCents(10050, USD) // $100.50
+//	NewMoneyFromCents(100000000, BTC) // 1 BTC (100M satoshis)
+func NewMoneyFromCents(cents int64, currency Currency) (Money, error) {
+	return NewMoneyFromMinorUnits(cents, currency)
+}
+
 // Zero creates a zero money amount for the given currency.
 func Zero(currency Currency) Money {
 	return Money{
@@ -132,6 +153,43 @@ func (m Money) String() string {
 	return fmt.Sprintf("%s %s", m.amount.FloatString(m.decimalPlaces()), m.currency.Code())
 }
 
+// FormatMinorUnits formats an amount in the smallest currency unit exactly
+// like NewMoneyFromMinorUnits(minor, currency).String(), without building a
+// big.Rat. Used on hot read paths that only display stored balances.
+//
+// Example:
+//
+//	FormatMinorUnits(10050, USD) // "100.50 USD"
+func FormatMinorUnits(minor int64, currency Currency) string {
+	decimals := currency.Decimals()
+	buf := make([]byte, 0, 24+len(currency.Code()))
+
+	var abs uint64
+	if minor < 0 {
+		buf = append(buf, '-')
+		abs = uint64(-(minor + 1)) + 1 // no overflow for math.MinInt64
+	} else {
+		abs = uint64(minor)
+	}
+
+	scale := uint64(1)
+	for i := 0; i < decimals; i++ {
+		scale *= 10
+	}
+	buf = strconv.AppendUint(buf, abs/scale, 10)
+	if decimals > 0 {
+		frac := strconv.FormatUint(abs%scale, 10)
+		buf = append(buf, '.')
+		for i := len(frac); i < decimals; i++ {
+			buf = append(buf, '0')
+		}
+		buf = append(buf, frac...)
+	}
+	buf = append(buf, ' ')
+	buf = append(buf, currency.Code()...)
+	return string(buf)
+}
+
 // Float64 returns the amount as float64.
 // WARNING: Use only for display purposes, not for calculations!
 func (m Money) Float64() float64 {
@@ -152,6 +210,24 @@ func (m Money) Cents() int64 {
 	return scaled.Num().Int64() / scaled.Denom().Int64()
 }
 
+// MinorUnits returns the amount in the smallest currency unit, like Cents,
+// but fails with ErrAmountOverflow instead of wrapping when the result does
+// not fit into int64 (about 92 billion BTC or 92 quadrillion USD).
+// Precision below the minor unit is truncated.
+func (m Money) MinorUnits() (int64, error) {
+	scaled := new(big.Rat).Mul(m.amount, new(big.Rat).SetInt(minorUnitScale(m.currency)))
+	minor := new(big.Int).Quo(scaled.Num(), scaled.Denom())
+	if !minor.IsInt64() {
+		return 0, ErrAmountOverflow
+	}
+	return minor.Int64(), nil
+}
+
+// minorUnitScale returns 10^currency.Decimals().
+func minorUnitScale(currency Currency) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(currency.Decimals())), nil)
+}
+
 // Add returns a new Money with the sum of two amounts.
 // IMMUTABLE: Returns new instance, doesn't modify receiver.
 //
@@ -197,28 +273,46 @@ func (m Money) IsPositive() bool {
 	return m.amount.Sign() > 0
 }
 
+// Compare returns -1, 0 or +1 when this money is less than, equal to or greater than another.
+//
+// Business rule: amounts in different currencies are not comparable.
+// A mismatch is always an error, never an ordering: 100 USD is neither
+// greater nor less than 100 EUR.
+func (m Money) Compare(other Money) (int, error) {
+	if !m.currency.Equals(other.currency) {
+		return 0, ErrCurrencyMismatch
+	}
+	return m.amount.Cmp(other.amount), nil
+}
+
 // GreaterThan checks if this money is greater than another.
+// Returns ErrCurrencyMismatch (and false) for different currencies.
 func (m Money) GreaterThan(other Money) (bool, error) {
-	if !m.currency.Equals(other.currency) {
-		return false, ErrCurrencyMismatch
+	cmp, err := m.Compare(other)
+	if err != nil {
+		return false, err
 	}
-	return m.amount.Cmp(other.amount) > 0, nil
+	return cmp > 0, nil
 }
 
 // GreaterThanOrEqual checks if this money is >= another.
+// Returns ErrCurrencyMismatch (and false) for different currencies.
 func (m Money) GreaterThanOrEqual(other Money) (bool, error) {
-	if !m.currency.Equals(other.currency) {
-		return false, ErrCurrencyMismatch
+	cmp, err := m.Compare(other)
+	if err != nil {
+		return false, err
 	}
-	return m.amount.Cmp(other.amount) >= 0, nil
+	return cmp >= 0, nil
 }
 
 // LessThan checks if this money is less than another.
+// Returns ErrCurrencyMismatch (and false) for different currencies.
 func (m Money) LessThan(other Money) (bool, error) {
-	if !m.currency.Equals(other.currency) {
-		return false, ErrCurrencyMismatch
+	cmp, err := m.Compare(other)
+	if err != nil {
+		return false, err
 	}
-	return m.amount.Cmp(other.amount) < 0, nil
+	return cmp < 0, nil
 }
 
 // Equals checks if two money values are equal (amount and currency).
@@ -228,8 +322,5 @@ func (m Money) Equals(other Money) bool {
 
 // decimalPlaces returns the number of decimal places for display.
 func (m Money) decimalPlaces() int {
-	if m.currency.IsCrypto() {
-		return 8
-	}
-	return 2
+	return m.currency.Decimals()
 }
@@ -9,6 +9,8 @@
 package valueobjects_test
 
 import (
+	"errors"
+	"math"
 	"math/big"
 	"testing"
 
@@ -80,6 +82,24 @@ func TestNewMoney_InvalidFormat(t *testing.T) {
 	}
 }
 
+// TestParseAmount tests currency-independent amount validation.
+func TestParseAmount(t *testing.T) {
+	amount, err := valueobjects.ParseAmount("100.50")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if amount.FloatString(2) != "100.50" {
+		t.Errorf("Expected 100.50, got %s", amount.FloatString(2))
+	}
+
+	if _, err := valueobjects.ParseAmount("abc"); !errors.Is(err, valueobjects.ErrInvalidAmount) {
+		t.Errorf("Expected ErrInvalidAmount, got %v", err)
+	}
+	if _, err := valueobjects.ParseAmount("-1"); !errors.Is(err, valueobjects.ErrNegativeAmount) {
+		t.Errorf("Expected ErrNegativeAmount, got %v", err)
+	}
+}
+
 // TestMoney_Add tests addition operation.
 // Business Rule: Can only add same currency.
 func TestMoney_Add(t *testing.T) {
@@ -109,6 +129,27 @@ func TestMoney_Add(t *testing.T) {
 	})
 }
 
+// TestMoney_Add_NoFloatDrift sums 0.10 ten thousand times: with float64 the
+// result drifts off 1000.00, with exact arithmetic it must not.
+func TestMoney_Add_NoFloatDrift(t *testing.T) {
+	dime, _ := valueobjects.NewMoney("0.1", valueobjects.USD)
+
+	sum := valueobjects.Zero(valueobjects.USD)
+	for i := 0; i < 10000; i++ {
+		var err error
+		if sum, err = sum.Add(dime); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	if got := sum.String(); got != "1000.00 USD" {
+		t.Errorf("sum = %s, want 1000.00 USD", got)
+	}
+	if cents, err := sum.MinorUnits(); err != nil || cents != 100000 {
+		t.Errorf("MinorUnits() = %d, %v, want 100000", cents, err)
+	}
+}
+
 // TestMoney_Subtract tests subtraction with insufficient balance check.
 func TestMoney_Subtract(t *testing.T) {
 	t.Run("Valid subtraction", func(t *testing.T) {
@@ -275,6 +316,121 @@ func TestNewMoneyFromCents(t *testing.T) {
 	}
 }
 
+// TestFormatMinorUnits checks the fast formatter against Money.String.
+func TestFormatMinorUnits(t *testing.T) {
+	tests := []struct {
+		minor    int64
+		currency valueobjects.Currency
+	}{
+		{0, valueobjects.USD},
+		{5, valueobjects.USD},
+		{10050, valueobjects.USD},
+		{100000000, valueobjects.BTC},
+		{123, valueobjects.BTC},
+		{math.MaxInt64, valueobjects.EUR},
+	}
+
+	for _, tt := range tests {
+		money, err := valueobjects.NewMoneyFromMinorUnits(tt.minor, tt.currency)
+		if err != nil {
+			t.Fatalf("NewMoneyFromMinorUnits(%d) error = %v", tt.minor, err)
+		}
+		if got := valueobjects.FormatMinorUnits(tt.minor, tt.currency); got != money.String() {
+			t.Errorf("FormatMinorUnits(%d, %s) = %q, want %q", tt.minor, tt.currency.Code(), got, money.String())
+		}
+	}
+
+	if got := valueobjects.FormatMinorUnits(-50, valueobjects.USD); got != "-0.50 USD" {
+		t.Errorf("FormatMinorUnits(-50, USD) = %q, want \"-0.50 USD\"", got)
+	}
+}
+
+// TestNewMoneyFromMinorUnits_RoundTrip tests minor units -> Money -> minor units.
+func TestNewMoneyFromMinorUnits_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		minor      int64
+		currency   valueobjects.Currency
+		wantString string
+	}{
+		{"USD zero", 0, valueobjects.USD, "0.00 USD"},
+		{"USD one cent", 1, valueobjects.USD, "0.01 USD"},
+		{"USD dollars and cents", 10050, valueobjects.USD, "100.50 USD"},
+		{"USD max int64", math.MaxInt64, valueobjects.USD, "92233720368547758.07 USD"},
+		{"BTC one satoshi", 1, valueobjects.BTC, "0.00000001 BTC"},
+		{"BTC one coin", 100000000, valueobjects.BTC, "1.00000000 BTC"},
+		{"BTC max int64", math.MaxInt64, valueobjects.BTC, "92233720368.54775807 BTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			money, err := valueobjects.NewMoneyFromMinorUnits(tt.minor, tt.currency)
+			if err != nil {
+				t.Fatalf("NewMoneyFromMinorUnits() error = %v", err)
+			}
+			if money.String() != tt.wantString {
+				t.Errorf("String() = %v, want %v", money.String(), tt.wantString)
+			}
+
+			minor, err := money.MinorUnits()
+			if err != nil {
+				t.Fatalf("MinorUnits() error = %v", err)
+			}
+			if minor != tt.minor {
+				t.Errorf("MinorUnits() = %v, want %v", minor, tt.minor)
+			}
+
+			// Decimal string of the same amount gives the same Money
+			parsed, err := valueobjects.NewMoney(money.Amount().FloatString(tt.currency.Decimals()), tt.currency)
+			if err != nil {
+				t.Fatalf("NewMoney() error = %v", err)
+			}
+			if !parsed.Equals(money) {
+				t.Errorf("Decimal round-trip mismatch: got %v, want %v", parsed, money)
+			}
+		})
+	}
+}
+
+func TestNewMoneyFromMinorUnits_Negative(t *testing.T) {
+	if _, err := valueobjects.NewMoneyFromMinorUnits(-1, valueobjects.BTC); !errors.Is(err, valueobjects.ErrNegativeAmount) {
+		t.Errorf("Expected ErrNegativeAmount, got %v", err)
+	}
+}
+
+// TestMoney_MinorUnitsOverflow tests amounts that do not fit into int64 minor units.
+func TestMoney_MinorUnitsOverflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency valueobjects.Currency
+		wantErr  bool
+	}{
+		{"BTC at the limit", "92233720368.54775807", valueobjects.BTC, false},
+		{"BTC one satoshi over", "92233720368.54775808", valueobjects.BTC, true},
+		{"BTC huge", "100000000000", valueobjects.BTC, true},
+		{"USD at the limit", "92233720368547758.07", valueobjects.USD, false},
+		{"USD one cent over", "92233720368547758.08", valueobjects.USD, true},
+		{"USD sub-cent is truncated", "0.019", valueobjects.USD, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			money, err := valueobjects.NewMoney(tt.amount, tt.currency)
+			if err != nil {
+				t.Fatalf("NewMoney() error = %v", err)
+			}
+			_, err = money.MinorUnits()
+			if tt.wantErr && !errors.Is(err, valueobjects.ErrAmountOverflow) {
+				t.Errorf("Expected ErrAmountOverflow, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
 // TestNewMoneyFromInt tests creating money from integer amounts.
 func TestNewMoneyFromInt(t *testing.T) {
 	tests := []struct {
@@ -577,6 +733,64 @@ func TestMoney_Comparison_DifferentCurrencies(t *testing.T) {
 	})
 }
 
+// TestMoney_Compare_Matrix tests Compare and the boolean helpers across amounts and currencies.
+func TestMoney_Compare_Matrix(t *testing.T) {
+	usd50, _ := valueobjects.NewMoney("50", valueobjects.USD)
+	usd100, _ := valueobjects.NewMoney("100", valueobjects.USD)
+	eur100, _ := valueobjects.NewMoney("100", valueobjects.EUR)
+	eur50, _ := valueobjects.NewMoney("50", valueobjects.EUR)
+
+	tests := []struct {
+		name      string
+		a, b      valueobjects.Money
+		wantCmp   int
+		wantGT    bool
+		wantGTE   bool
+		wantLT    bool
+		wantError bool
+	}{
+		{name: "Less", a: usd50, b: usd100, wantCmp: -1, wantLT: true},
+		{name: "Equal", a: usd100, b: usd100, wantCmp: 0, wantGTE: true},
+		{name: "Greater", a: usd100, b: usd50, wantCmp: 1, wantGT: true, wantGTE: true},
+		{name: "Equal amounts, different currencies", a: usd100, b: eur100, wantError: true},
+		{name: "Greater amount, different currencies", a: usd100, b: eur50, wantError: true},
+		{name: "Less amount, different currencies", a: eur50, b: usd100, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmp, err := tt.a.Compare(tt.b)
+			gt, gtErr := tt.a.GreaterThan(tt.b)
+			gte, gteErr := tt.a.GreaterThanOrEqual(tt.b)
+			lt, ltErr := tt.a.LessThan(tt.b)
+
+			if tt.wantError {
+				for _, e := range []error{err, gtErr, gteErr, ltErr} {
+					if e != valueobjects.ErrCurrencyMismatch {
+						t.Errorf("expected ErrCurrencyMismatch, got %v", e)
+					}
+				}
+				if cmp != 0 || gt || gte || lt {
+					t.Error("mismatched currencies must not yield an ordering")
+				}
+				return
+			}
+
+			for _, e := range []error{err, gtErr, gteErr, ltErr} {
+				if e != nil {
+					t.Fatalf("unexpected error: %v", e)
+				}
+			}
+			if cmp != tt.wantCmp {
+				t.Errorf("Compare() = %d, want %d", cmp, tt.wantCmp)
+			}
+			if gt != tt.wantGT || gte != tt.wantGTE || lt != tt.wantLT {
+				t.Errorf("GT/GTE/LT = %v/%v/%v, want %v/%v/%v", gt, gte, lt, tt.wantGT, tt.wantGTE, tt.wantLT)
+			}
+		})
+	}
+}
+
 // TestMoney_Equals_DifferentCurrencies tests equals with different currencies.
 func TestMoney_Equals_DifferentCurrencies(t *testing.T) {
 	mUSD, _ := valueobjects.NewMoney("100", valueobjects.USD)
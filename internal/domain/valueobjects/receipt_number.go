@@ -0,0 +1,135 @@
+package valueobjects
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Receipt numbers are short identifiers of completed transactions that a
+// customer can read out to support, e.g. "PB-7F3K-9Q2M".
+//
+// The number is derived from a database sequence, so it is unique without
+// random retries: the sequence value is scrambled by a bijection of the
+// 35-bit space (consecutive receipts do not look consecutive) and written
+// as 7 Crockford base32 characters plus a Luhn mod 32 check character. The
+// check character catches every single-character typo and most swaps of
+// adjacent characters.
+const (
+	// ReceiptNumberPrefix starts every receipt number.
+	ReceiptNumberPrefix = "PB"
+
+	// MaxReceiptSequence is the largest sequence value that fits a receipt number.
+	MaxReceiptSequence = receiptSpace - 1
+
+	receiptPayloadLength = 7
+	receiptSpace         = 1 << (5 * receiptPayloadLength)
+	receiptMask          = receiptSpace - 1
+
+	// Odd multiplier and XOR mask: both are bijections modulo 2^35.
+	receiptMultiplier = 0x5DEECE66D
+	receiptXOR        = 0x2B4D1F0A7
+)
+
+// crockfordAlphabet is Crockford's base32: no I, L, O or U.
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ErrInvalidReceiptNumber is returned for malformed or mistyped receipt numbers.
+var ErrInvalidReceiptNumber = errors.New("invalid receipt number")
+
+// NewReceiptNumber formats the receipt number of a sequence value.
+// Sequence values start at 1, as in a PostgreSQL sequence.
+func NewReceiptNumber(sequence int64) (string, error) {
+	if sequence < 1 || sequence > MaxReceiptSequence {
+		return "", fmt.Errorf("receipt sequence %d out of range [1, %d]", sequence, int64(MaxReceiptSequence))
+	}
+
+	value := (uint64(sequence)*receiptMultiplier)&receiptMask ^ receiptXOR
+
+	payload := make([]byte, receiptPayloadLength)
+	for i := receiptPayloadLength - 1; i >= 0; i-- {
+		payload[i] = crockfordAlphabet[value&31]
+		value >>= 5
+	}
+
+	return formatReceiptNumber(string(payload) + string(receiptCheckCharacter(string(payload)))), nil
+}
+
+// ParseReceiptNumber normalizes a receipt number as typed by a person and
+// verifies its check character. The prefix and dashes are optional, case is
+// ignored, and the look-alikes I, L and O are read as 1, 1 and 0.
+// Returns the canonical form ("PB-XXXX-XXXX") or ErrInvalidReceiptNumber.
+func ParseReceiptNumber(input string) (string, error) {
+	compact := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(input)))
+	if len(compact) == len(ReceiptNumberPrefix)+receiptPayloadLength+1 {
+		compact = strings.TrimPrefix(compact, ReceiptNumberPrefix)
+	}
+	if len(compact) != receiptPayloadLength+1 {
+		return "", ErrInvalidReceiptNumber
+	}
+
+	normalized := make([]byte, len(compact))
+	for i := 0; i < len(compact); i++ {
+		c := compact[i]
+		switch c {
+		case 'I', 'L':
+			c = '1'
+		case 'O':
+			c = '0'
+		}
+		if strings.IndexByte(crockfordAlphabet, c) < 0 {
+			return "", ErrInvalidReceiptNumber
+		}
+		normalized[i] = c
+	}
+
+	if !receiptChecksumValid(string(normalized)) {
+		return "", ErrInvalidReceiptNumber
+	}
+	return formatReceiptNumber(string(normalized)), nil
+}
+
+// ReceiptVerificationHash returns the hex SHA-256 over the receipt number and
+// the transaction facts printed on the receipt. Support recomputes it from
+// the stored transaction to tell a genuine printout from an edited one.
+func ReceiptVerificationHash(receiptNumber string, facts ...string) string {
+	h := sha256.New()
+	writeKeyPart(h, receiptNumber)
+	for _, fact := range facts {
+		writeKeyPart(h, fact)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// formatReceiptNumber splits 8 characters as "PB-XXXX-XXXX".
+func formatReceiptNumber(compact string) string {
+	return ReceiptNumberPrefix + "-" + compact[:4] + "-" + compact[4:]
+}
+
+// receiptCheckCharacter computes the Luhn mod 32 check character.
+func receiptCheckCharacter(payload string) byte {
+	const n = len(crockfordAlphabet)
+
+	factor, sum := 2, 0
+	for i := len(payload) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(crockfordAlphabet, payload[i])
+		factor = 3 - factor
+		sum += addend/n + addend%n
+	}
+	return crockfordAlphabet[(n-sum%n)%n]
+}
+
+// receiptChecksumValid verifies the Luhn mod 32 check character (the last one).
+func receiptChecksumValid(compact string) bool {
+	const n = len(crockfordAlphabet)
+
+	factor, sum := 1, 0
+	for i := len(compact) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(crockfordAlphabet, compact[i])
+		factor = 3 - factor
+		sum += addend/n + addend%n
+	}
+	return sum%n == 0
+}
@@ -0,0 +1,115 @@
+package valueobjects_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+func TestNewReceiptNumber_Format(t *testing.T) {
+	format := regexp.MustCompile(`^PB-[0-9A-HJKMNP-TV-Z]{4}-[0-9A-HJKMNP-TV-Z]{4}$`)
+
+	for _, sequence := range []int64{1, 2, 42, 1 << 20, valueobjects.MaxReceiptSequence} {
+		number, err := valueobjects.NewReceiptNumber(sequence)
+		if err != nil {
+			t.Fatalf("NewReceiptNumber(%d) error = %v", sequence, err)
+		}
+		if !format.MatchString(number) {
+			t.Errorf("NewReceiptNumber(%d) = %q, unexpected format", sequence, number)
+		}
+		parsed, err := valueobjects.ParseReceiptNumber(number)
+		if err != nil || parsed != number {
+			t.Errorf("ParseReceiptNumber(%q) = %q, %v", number, parsed, err)
+		}
+	}
+
+	for _, sequence := range []int64{0, -1, valueobjects.MaxReceiptSequence + 1} {
+		if _, err := valueobjects.NewReceiptNumber(sequence); err == nil {
+			t.Errorf("NewReceiptNumber(%d) expected out of range error", sequence)
+		}
+	}
+}
+
+func TestNewReceiptNumber_Unique(t *testing.T) {
+	seen := make(map[string]int64)
+	for sequence := int64(1); sequence <= 100000; sequence++ {
+		number, err := valueobjects.NewReceiptNumber(sequence)
+		if err != nil {
+			t.Fatalf("NewReceiptNumber(%d) error = %v", sequence, err)
+		}
+		if previous, ok := seen[number]; ok {
+			t.Fatalf("NewReceiptNumber(%d) = %q, same as sequence %d", sequence, number, previous)
+		}
+		seen[number] = sequence
+	}
+}
+
+func TestParseReceiptNumber_Normalizes(t *testing.T) {
+	number, err := valueobjects.NewReceiptNumber(12345)
+	if err != nil {
+		t.Fatalf("NewReceiptNumber() error = %v", err)
+	}
+	compact := strings.ReplaceAll(strings.TrimPrefix(number, "PB-"), "-", "")
+	lookalikes := strings.NewReplacer("0", "o", "1", "l").Replace(strings.ToLower(compact))
+
+	for _, input := range []string{
+		number,
+		strings.ToLower(number),
+		" " + compact + " ",
+		"PB" + compact,
+		compact[:4] + " " + compact[4:],
+		lookalikes,
+	} {
+		parsed, err := valueobjects.ParseReceiptNumber(input)
+		if err != nil || parsed != number {
+			t.Errorf("ParseReceiptNumber(%q) = %q, %v; want %q", input, parsed, err, number)
+		}
+	}
+}
+
+func TestParseReceiptNumber_RejectsSingleCharacterTypos(t *testing.T) {
+	for _, sequence := range []int64{1, 777, 123456789} {
+		number, err := valueobjects.NewReceiptNumber(sequence)
+		if err != nil {
+			t.Fatalf("NewReceiptNumber() error = %v", err)
+		}
+		compact := []byte(strings.ReplaceAll(strings.TrimPrefix(number, "PB-"), "-", ""))
+
+		for i := range compact {
+			original := compact[i]
+			for _, typo := range []byte(crockford) {
+				if typo == original {
+					continue
+				}
+				compact[i] = typo
+				if _, err := valueobjects.ParseReceiptNumber(string(compact)); !errors.Is(err, valueobjects.ErrInvalidReceiptNumber) {
+					t.Errorf("ParseReceiptNumber(%q) accepted a typo of %q", compact, number)
+				}
+			}
+			compact[i] = original
+		}
+	}
+}
+
+func TestParseReceiptNumber_RejectsMalformed(t *testing.T) {
+	for _, input := range []string{"", "PB-", "PB-1234-567", "PB-1234-56789", "PB-1234-567U", "XX-1234-5678"} {
+		if _, err := valueobjects.ParseReceiptNumber(input); !errors.Is(err, valueobjects.ErrInvalidReceiptNumber) {
+			t.Errorf("ParseReceiptNumber(%q) error = %v, want ErrInvalidReceiptNumber", input, err)
+		}
+	}
+}
+
+func TestReceiptVerificationHash(t *testing.T) {
+	a := valueobjects.ReceiptVerificationHash("PB-0000-0000", "10.00", "USD")
+	if a != valueobjects.ReceiptVerificationHash("PB-0000-0000", "10.00", "USD") {
+		t.Error("Expected the same hash for the same facts")
+	}
+	if a == valueobjects.ReceiptVerificationHash("PB-0000-0000", "100.0", "0USD") {
+		t.Error("Expected fact boundaries to change the hash")
+	}
+}
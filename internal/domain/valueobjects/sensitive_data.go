@@ -0,0 +1,203 @@
+package valueobjects
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Sensitive identifiers that must not be stored verbatim in free-form fields
+// (external references, metadata). Upstream providers sometimes embed card
+// numbers or IBANs in their reference strings, which would put every copy of
+// the value in PCI/GDPR scope.
+//
+// Detection is deliberately conservative to avoid masking ordinary numeric IDs:
+//   - PAN: 13-19 digits (contiguous or in printed groups of 3-6 separated by a space or dash) that pass the Luhn check
+//   - IBAN: country code, check digits and BBAN (compact or in groups of four) that pass ISO 7064 mod-97
+//
+// A random 13-19 digit ID still passes Luhn with ~10% probability; such IDs are masked too.
+const (
+	SensitiveKindPAN  = "PAN"
+	SensitiveKindIBAN = "IBAN"
+)
+
+// maskRune replaces the hidden part of a detected identifier.
+const maskRune = '*'
+
+var (
+	// panCandidateRegex matches digit runs with optional single space/dash separators.
+	panCandidateRegex = regexp.MustCompile(`\d(?:[ -]?\d)*`)
+	// digitRunRegex matches the digit groups of a candidate.
+	digitRunRegex = regexp.MustCompile(`\d+`)
+	// ibanRegex matches compact ("DE89370400440532013000") and printed
+	// ("DE89 3704 0044 0532 0130 00") IBANs.
+	ibanRegex = regexp.MustCompile(`\b[A-Z]{2}\d{2}(?:[A-Z0-9]{11,30}|(?: [A-Z0-9]{4}){2,7}(?: [A-Z0-9]{1,4})?)\b`)
+)
+
+// ContainsSensitiveData reports whether s contains a PAN or an IBAN.
+func ContainsSensitiveData(s string) bool {
+	_, found := RedactSensitiveData(s)
+	return found
+}
+
+// SensitiveDataKinds returns the kinds of sensitive identifiers found in s (PAN, IBAN).
+func SensitiveDataKinds(s string) []string {
+	var kinds []string
+	masked, found := redactIBANs(s)
+	if found {
+		kinds = append(kinds, SensitiveKindIBAN)
+	}
+	if _, found := redactPANs(masked); found {
+		kinds = append(kinds, SensitiveKindPAN)
+	}
+	return kinds
+}
+
+// RedactSensitiveData masks every PAN and IBAN in s and reports whether anything was masked.
+//
+// PANs keep the first 6 and last 4 digits ("411111******1111"), IBANs keep the
+// country code with check digits and the last 4 characters ("DE89**************3000").
+// Separators inside a masked identifier are dropped; the rest of s is unchanged.
+func RedactSensitiveData(s string) (string, bool) {
+	masked, ibanFound := redactIBANs(s)
+	masked, panFound := redactPANs(masked)
+	return masked, ibanFound || panFound
+}
+
+// HashExternalReference returns the hex SHA-256 of a reference as received.
+// The hash of the original value allows exact-match lookup after the stored
+// value has been redacted.
+func HashExternalReference(reference string) string {
+	sum := sha256.Sum256([]byte(reference))
+	return hex.EncodeToString(sum[:])
+}
+
+func redactIBANs(s string) (string, bool) {
+	found := false
+	masked := ibanRegex.ReplaceAllStringFunc(s, func(match string) string {
+		compact := strings.ReplaceAll(match, " ", "")
+		if !isValidIBAN(compact) {
+			return match
+		}
+		found = true
+		return maskMiddle(compact, 4, 4)
+	})
+	return masked, found
+}
+
+func redactPANs(s string) (string, bool) {
+	found := false
+	masked := panCandidateRegex.ReplaceAllStringFunc(s, func(match string) string {
+		return redactPANGroups(match, &found)
+	})
+	return masked, found
+}
+
+// redactPANGroups masks the longest sequences of digit groups in run that form a PAN.
+//
+// A PAN is either one contiguous group or several printed groups of 3-6 digits
+// ("4111 1111 1111 1111", "3782 822463 10005"). The group rule keeps UUIDs and
+// similar identifiers out ("a716-446655440000" is not a 15-digit candidate) and
+// still finds a PAN glued to neighbouring numbers ("order 12 4111 1111 1111 1111").
+func redactPANGroups(run string, found *bool) string {
+	groups := digitRunRegex.FindAllStringIndex(run, -1)
+
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(groups); i++ {
+		for j := len(groups) - 1; j >= i; j-- {
+			if j > i && !printedGroups(groups[i:j+1]) {
+				continue
+			}
+			digits := stripSeparators(run[groups[i][0]:groups[j][1]])
+			if !isPAN(digits) {
+				continue
+			}
+			*found = true
+			b.WriteString(run[last:groups[i][0]])
+			b.WriteString(maskMiddle(digits, 6, 4))
+			last = groups[j][1]
+			i = j
+			break
+		}
+	}
+	b.WriteString(run[last:])
+	return b.String()
+}
+
+// printedGroups reports whether every group is 3-6 digits long, as on a printed card.
+func printedGroups(groups [][]int) bool {
+	for _, g := range groups {
+		if n := g[1] - g[0]; n < 3 || n > 6 {
+			return false
+		}
+	}
+	return true
+}
+
+// isPAN reports whether digits look like a card number: 13-19 digits passing Luhn.
+func isPAN(digits string) bool {
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	return luhnValid(digits)
+}
+
+// luhnValid implements the Luhn (mod 10) checksum used by payment card numbers.
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// isValidIBAN checks the IBAN length and the ISO 7064 mod-97 checksum.
+func isValidIBAN(iban string) bool {
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	// Move the first four characters to the end and convert letters to numbers (A=10 ... Z=35)
+	rearranged := iban[4:] + iban[:4]
+	var numeric strings.Builder
+	for _, r := range rearranged {
+		switch {
+		case r >= '0' && r <= '9':
+			numeric.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			numeric.WriteString(big.NewInt(int64(r-'A') + 10).String())
+		default:
+			return false
+		}
+	}
+
+	n, ok := new(big.Int).SetString(numeric.String(), 10)
+	if !ok {
+		return false
+	}
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// maskMiddle keeps the first keepStart and last keepEnd characters of s.
+func maskMiddle(s string, keepStart, keepEnd int) string {
+	if len(s) <= keepStart+keepEnd {
+		return strings.Repeat(string(maskRune), len(s))
+	}
+	return s[:keepStart] + strings.Repeat(string(maskRune), len(s)-keepStart-keepEnd) + s[len(s)-keepEnd:]
+}
+
+func stripSeparators(s string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(s)
+}
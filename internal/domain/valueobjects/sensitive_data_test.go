@@ -0,0 +1,97 @@
+package valueobjects_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// TestRedactSensitiveData_Masks tests test-card PANs and IBANs in the formats providers send.
+func TestRedactSensitiveData_Masks(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		kinds []string
+	}{
+		{name: "Visa", input: "4111111111111111", want: "411111******1111", kinds: []string{"PAN"}},
+		{name: "Mastercard", input: "5555555555554444", want: "555555******4444", kinds: []string{"PAN"}},
+		{name: "Amex15", input: "378282246310005", want: "378282*****0005", kinds: []string{"PAN"}},
+		{name: "Embedded", input: "pi_4111111111111111_retry", want: "pi_411111******1111_retry", kinds: []string{"PAN"}},
+		{name: "SpaceGrouped", input: "card 4111 1111 1111 1111", want: "card 411111******1111", kinds: []string{"PAN"}},
+		{name: "DashGrouped", input: "6011-0009-9013-9424", want: "601100******9424", kinds: []string{"PAN"}},
+		{name: "AmexGrouped", input: "3782 822463 10005", want: "378282*****0005", kinds: []string{"PAN"}},
+		{name: "GluedToOrderNumber", input: "order 12 4111 1111 1111 1111", want: "order 12 411111******1111", kinds: []string{"PAN"}},
+		{name: "IBANCompact", input: "DE89370400440532013000", want: "DE89**************3000", kinds: []string{"IBAN"}},
+		{name: "IBANPrinted", input: "payout GB82 WEST 1234 5698 7654 32", want: "payout GB82**************5432", kinds: []string{"IBAN"}},
+		{
+			name:  "Both",
+			input: "NL91ABNA0417164300/4111111111111111",
+			want:  "NL91**********4300/411111******1111",
+			kinds: []string{"IBAN", "PAN"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := valueobjects.RedactSensitiveData(tt.input)
+			if !found || got != tt.want {
+				t.Errorf("RedactSensitiveData(%q) = %q, %v; want %q, true", tt.input, got, found, tt.want)
+			}
+			if kinds := valueobjects.SensitiveDataKinds(tt.input); !slices.Equal(kinds, tt.kinds) {
+				t.Errorf("SensitiveDataKinds(%q) = %v, want %v", tt.input, kinds, tt.kinds)
+			}
+			if !valueobjects.ContainsSensitiveData(tt.input) {
+				t.Errorf("ContainsSensitiveData(%q) = false", tt.input)
+			}
+		})
+	}
+}
+
+// TestRedactSensitiveData_Benign tests references that must be stored unchanged.
+func TestRedactSensitiveData_Benign(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Empty", input: ""},
+		{name: "StripeID", input: "ch_3MqLiJLkdIwHu7ix0sa3Xq5b"},
+		{name: "UUID", input: "550e8400-e29b-41d4-a716-446655440000"},
+		{name: "UUIDDigitTail", input: "00000000-0000-4000-8716-446655440000"},
+		{name: "LuhnFails16", input: "1234567812345678"},
+		{name: "LuhnFails16Prefixed", input: "order-4111111111111112"},
+		{name: "TooShort", input: "424242424242"},
+		{name: "LongNumericID", input: "12345678901234567890123"},
+		{name: "GroupedLuhnFails", input: "1234 5678 1234 5678"},
+		{name: "Date", input: "2024-01-15"},
+		{name: "IBANBadChecksum", input: "DE00370400440532013000"},
+		{name: "LowercaseCountry", input: "de89370400440532013000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := valueobjects.RedactSensitiveData(tt.input)
+			if found || got != tt.input {
+				t.Errorf("RedactSensitiveData(%q) = %q, %v; want unchanged", tt.input, got, found)
+			}
+			if valueobjects.ContainsSensitiveData(tt.input) {
+				t.Errorf("ContainsSensitiveData(%q) = true", tt.input)
+			}
+		})
+	}
+}
+
+// TestHashExternalReference tests that the hash is stable and depends on the original value.
+func TestHashExternalReference(t *testing.T) {
+	// sha256("4111111111111111")
+	const want = "9bbef19476623ca56c17da75fd57734dbf82530686043a6e491c6d71befe8f6e"
+	if got := valueobjects.HashExternalReference("4111111111111111"); got != want {
+		t.Errorf("HashExternalReference() = %s, want %s", got, want)
+	}
+
+	masked, _ := valueobjects.RedactSensitiveData("4111111111111111")
+	if valueobjects.HashExternalReference(masked) == want {
+		t.Error("Hash of masked value must differ from hash of original")
+	}
+}
@@ -0,0 +1,135 @@
+// Package e2e - in-process сервер PayBridge для end-to-end и contract тестов.
+//
+// Server собирает настоящий HTTP router (middleware, handlers, CQRS buses,
+// use cases) поверх in-memory репозиториев и поднимает его через
+// httptest.Server. PostgreSQL, Redis и NATS не нужны, поэтому тесты
+// клиентов (pkg/client) запускаются обычным go test.
+//
+// Аутентификация - middleware.MockTokenValidator: Bearer токен = user_id.
+//
+// UnitOfWork guard работает в strict режиме: use case, обратившийся к
+// репозиторию мимо транзакции, получает 500 вместо тихого успеха.
+//
+// События доставляются в режиме sync: к ответу на запрос подписчики
+// Server.Bus уже обработали его события, ждать и опрашивать не нужно.
+package e2e
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	grpcadapter "github.com/Haleralex/wallethub/internal/adapters/grpc"
+	httpadapter "github.com/Haleralex/wallethub/internal/adapters/http"
+	"github.com/Haleralex/wallethub/internal/adapters/http/middleware"
+	"github.com/Haleralex/wallethub/internal/application/cqrs"
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/usecases/transaction"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/infrastructure/eventbus"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// Server - запущенный in-process API.
+type Server struct {
+	URL string // Базовый URL без /api/v1
+
+	Store        *memory.Store
+	Users        *memory.UserRepository
+	Wallets      *memory.WalletRepository
+	Transactions *memory.TransactionRepository
+	Bus          *eventbus.Bus // синхронная шина закоммиченных событий
+}
+
+// NewServer поднимает сервер и останавливает его в t.Cleanup.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	wallets := memory.NewWalletRepository(store)
+	transactions := memory.NewTransactionRepository(store)
+	bus := eventbus.New(logger, eventbus.Config{Sync: true})
+	publisher := eventbus.WrapEventPublisher(eventbus.DiscardPublisher(), bus)
+	uow := eventbus.WrapUnitOfWork(memory.NewUnitOfWork(store), bus)
+	bus.Start()
+	t.Cleanup(func() { _ = bus.Stop(context.Background()) })
+	buildInfo := ports.BuildInfo{Version: "e2e"}
+
+	// Strict guard: запрос к репозиторию мимо транзакции UnitOfWork роняет
+	// запрос (500), как storage.strict_uow в CI
+	commandBus := cqrs.NewCommandBus(cqrs.RecoveryMiddleware(logger), cqrs.UnitOfWorkGuardMiddleware(logger, true))
+	queryBus := cqrs.NewQueryBus(cqrs.RecoveryMiddleware(logger), cqrs.UnitOfWorkGuardMiddleware(logger, true))
+
+	// Wallet
+	cqrs.RegisterCommandHandler[dtos.CreateWalletCommand, *dtos.WalletDTO](commandBus,
+		wallet.NewCreateWalletUseCase(users, wallets, publisher, uow, nil))
+	cqrs.RegisterCommandHandler[dtos.CreditWalletCommand, *dtos.WalletOperationDTO](commandBus,
+		wallet.NewCreditWalletUseCase(wallets, transactions, publisher, uow, nil, nil, buildInfo, ports.SensitiveDataPolicy{}, nil, nil))
+	cqrs.RegisterCommandHandler[dtos.DebitWalletCommand, *dtos.WalletOperationDTO](commandBus,
+		wallet.NewDebitWalletUseCase(wallets, transactions, publisher, uow, nil, nil, buildInfo, ports.SensitiveDataPolicy{}, nil, nil, nil, nil))
+	cqrs.RegisterCommandHandler[dtos.TransferFundsCommand, *dtos.TransferResultDTO](commandBus,
+		transaction.NewTransferBetweenWalletsUseCase(wallets, transactions, publisher, uow,
+			grpcadapter.NewNoOpFraudDetector(), nil, nil, nil, nil, buildInfo, nil, nil, nil, nil, nil))
+	cqrs.RegisterQueryHandler[dtos.GetWalletQuery, *dtos.WalletDTO](queryBus, wallet.NewGetWalletUseCase(wallets, transactions, ports.PendingTransactionsPolicy{}, ports.MetadataQuota{}, ports.DisplayCurrency{}))
+	cqrs.RegisterQueryHandler[dtos.GetWalletBalanceQuery, *dtos.WalletBalanceDTO](queryBus, wallet.NewGetWalletBalanceUseCase(wallets, ports.DisplayCurrency{}))
+	cqrs.RegisterQueryHandler[dtos.ListWalletsQuery, *dtos.WalletListDTO](queryBus, wallet.NewListWalletsUseCase(wallets, ports.DisplayCurrency{}))
+
+	// Transaction
+	cqrs.RegisterQueryHandler[dtos.GetTransactionQuery, *dtos.TransactionDTO](queryBus,
+		transaction.NewGetTransactionUseCase(transactions))
+	cqrs.RegisterQueryHandler[dtos.ListTransactionsQuery, *dtos.TransactionListDTO](queryBus,
+		transaction.NewListTransactionsUseCase(transactions))
+	cqrs.RegisterQueryHandler[dtos.ExportTransactionsQuery, *dtos.TransactionExportDTO](queryBus,
+		transaction.NewExportTransactionsUseCase(transactions, 0))
+	cqrs.RegisterQueryHandler[dtos.GetTransactionByIdempotencyKeyQuery, *dtos.TransactionDTO](queryBus,
+		transaction.NewGetTransactionByIdempotencyKeyUseCase(transactions))
+
+	router := httpadapter.NewRouterBuilder(&httpadapter.RouterConfig{
+		Logger:             logger,
+		Version:            buildInfo.Version,
+		BuildTime:          "unknown",
+		Environment:        "test",
+		AllowedOrigins:     []string{"*"},
+		AuthTokenValidator: middleware.MockTokenValidator,
+	}).
+		WithCQRS(commandBus, queryBus).
+		Build()
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return &Server{
+		URL:          server.URL,
+		Store:        store,
+		Users:        users,
+		Wallets:      wallets,
+		Transactions: transactions,
+		Bus:          bus,
+	}
+}
+
+// SeedUser создаёт пользователя и возвращает его ID - он же Bearer токен
+// для MockTokenValidator.
+func (s *Server) SeedUser(t testing.TB) string {
+	t.Helper()
+
+	user, err := entities.NewUser("user-"+uuid.NewString()[:8]+"@e2e.example.com", "E2E User")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := s.Users.Save(context.Background(), user); err != nil {
+		t.Fatalf("Save(user) error = %v", err)
+	}
+	return user.ID().String()
+}
@@ -0,0 +1,303 @@
+// Package balancesummary - дебаунс событий изменения баланса кошелька.
+//
+// Активные трейдеры порождают сотни WalletCredited/WalletDebited в минуту на
+// кошелёк. Потребителям, которым нужно только "баланс изменился, вот текущий",
+// Summarizer раз в Interval публикует по одному WalletBalanceSummary на каждый
+// изменившийся кошелёк: актуальные available/pending балансы, число изменений
+// за окно и последнюю транзакцию.
+//
+// Гарантии:
+//   - Индивидуальные события не меняются: summary публикуется дополнительно
+//   - Источник - внутренняя шина, то есть только закоммиченные события
+//   - Память ограничена: в окне отслеживается не больше MaxWallets кошельков,
+//     изменения остальных отбрасываются (WARN в лог при закрытии окна)
+//   - Stop публикует накопленное окно (flush)
+//
+// Summary best-effort, как и шина: для гарантированной доставки каждого
+// изменения по-прежнему используются индивидуальные события в outbox.
+// Повторно доставленное событие не учитывается дважды (см. Subscribe).
+package balancesummary
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/eventbus"
+	"github.com/Haleralex/wallethub/internal/pkg/logger"
+)
+
+// Значения по умолчанию для незаданных полей Config.
+const (
+	DefaultInterval   = 5 * time.Second
+	DefaultMaxWallets = 10000
+)
+
+// ConsumerName - имя потребителя в журнале обработанных событий.
+const ConsumerName = "balance-summary"
+
+// flushTimeout ограничивает загрузку кошельков и публикацию одного окна
+const flushTimeout = 30 * time.Second
+
+// WalletReader загружает актуальное состояние кошелька (часть ports.WalletReader).
+type WalletReader interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.Wallet, error)
+}
+
+// Config - настройки дебаунса.
+type Config struct {
+	// Interval - длина окна: summary публикуются раз в Interval.
+	Interval time.Duration
+
+	// MaxWallets - сколько кошельков отслеживается в одном окне.
+	MaxWallets int
+
+	// Heartbeat получает отчёт о каждом окне. nil - не сообщается.
+	Heartbeat ports.WorkerHeartbeat
+}
+
+// walletChanges - изменения баланса кошелька в текущем окне.
+type walletChanges struct {
+	count             int
+	lastTransactionID uuid.UUID
+	lastVersion       int64 // версия баланса после lastTransactionID
+}
+
+// Summarizer копит изменения балансов и публикует summary по окнам.
+type Summarizer struct {
+	logger     *slog.Logger
+	wallets    WalletReader
+	publisher  ports.EventPublisher
+	interval   time.Duration
+	maxWallets int
+	heartbeat  ports.WorkerHeartbeat
+	now        func() time.Time
+
+	mu             sync.Mutex
+	windowStart    time.Time
+	changes        map[uuid.UUID]*walletChanges
+	droppedChanges int // изменения кошельков сверх maxWallets в текущем окне
+
+	lifecycle sync.Mutex
+	started   bool
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// New создаёт Summarizer. Окна публикуются после Start.
+//
+// publisher получает WalletBalanceSummary вне UnitOfWork, поэтому должен
+// работать без транзакции в ctx.
+func New(logger *slog.Logger, wallets WalletReader, publisher ports.EventPublisher, cfg Config) *Summarizer {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.MaxWallets <= 0 {
+		cfg.MaxWallets = DefaultMaxWallets
+	}
+	if cfg.Heartbeat == nil {
+		cfg.Heartbeat = ports.NoopWorkerHeartbeat{}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Summarizer{
+		logger:      logger,
+		wallets:     wallets,
+		publisher:   publisher,
+		interval:    cfg.Interval,
+		maxWallets:  cfg.MaxWallets,
+		heartbeat:   cfg.Heartbeat,
+		now:         time.Now,
+		windowStart: time.Now().UTC(),
+		changes:     make(map[uuid.UUID]*walletChanges),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// ============================================
+// Observe
+// ============================================
+
+// Observe учитывает WalletCredited/WalletDebited; остальные события игнорируются.
+func (s *Summarizer) Observe(event events.DomainEvent) {
+	switch e := event.(type) {
+	case *events.WalletCredited:
+		s.record(e.WalletID, e.TransactionID, e.BalanceVersion)
+	case *events.WalletDebited:
+		s.record(e.WalletID, e.TransactionID, e.BalanceVersion)
+	}
+}
+
+// Handle - ports.EventHandler для подписки на шину.
+func (s *Summarizer) Handle(_ context.Context, event events.DomainEvent) error {
+	s.Observe(event)
+	return nil
+}
+
+// Subscribe подписывает Summarizer на WalletCredited/WalletDebited шины.
+// Событие, уже учтённое по журналу dedup, пропускается (nil - без дедупликации).
+func (s *Summarizer) Subscribe(bus *eventbus.Bus, dedup ports.DedupStore) {
+	handle := ports.EventHandler(s.Handle)
+	if dedup != nil {
+		handle = eventbus.WithDedup(ConsumerName, dedup, handle)
+	}
+
+	eventbus.Subscribe(bus, "balance-summary-credited", func(ctx context.Context, e *events.WalletCredited) error {
+		return handle(ctx, e)
+	})
+	eventbus.Subscribe(bus, "balance-summary-debited", func(ctx context.Context, e *events.WalletDebited) error {
+		return handle(ctx, e)
+	})
+}
+
+func (s *Summarizer) record(walletID, transactionID uuid.UUID, balanceVersion int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.changes[walletID]
+	if !ok {
+		if len(s.changes) >= s.maxWallets {
+			s.droppedChanges++
+			return
+		}
+		c = &walletChanges{}
+		s.changes[walletID] = c
+	}
+
+	c.count++
+	// Подписки на шину независимы: последней считается транзакция с самой
+	// старшей версией баланса. OccurredAt не годится - изменения в пределах
+	// одного тика часов получают одинаковое время
+	if balanceVersion >= c.lastVersion {
+		c.lastTransactionID = transactionID
+		c.lastVersion = balanceVersion
+	}
+}
+
+// ============================================
+// Flush
+// ============================================
+
+// Flush закрывает текущее окно и публикует summary по каждому изменившемуся
+// кошельку. Возвращает число опубликованных событий. Кошелёк, который не
+// удалось загрузить или опубликовать, пропускается (ERROR в лог).
+func (s *Summarizer) Flush(ctx context.Context) int {
+	s.mu.Lock()
+	changes, dropped := s.changes, s.droppedChanges
+	windowStart, windowEnd := s.windowStart, s.now().UTC()
+	s.changes = make(map[uuid.UUID]*walletChanges, len(changes))
+	s.droppedChanges = 0
+	s.windowStart = windowEnd
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		s.logger.Warn("Balance summary window overflow, changes of untracked wallets dropped",
+			slog.Int("tracked_wallets", len(changes)),
+			slog.Int("dropped_changes", dropped),
+		)
+	}
+
+	published := 0
+	for walletID, c := range changes {
+		wallet, err := s.wallets.FindByID(ctx, walletID)
+		if err != nil {
+			s.logger.Error("Failed to load wallet for balance summary",
+				logger.WalletID(walletID),
+				logger.Err(err),
+			)
+			continue
+		}
+
+		summary := events.NewWalletBalanceSummary(
+			walletID,
+			wallet.AvailableBalance(),
+			wallet.PendingBalance(),
+			c.count,
+			c.lastTransactionID,
+			windowStart,
+			windowEnd,
+		)
+		if err := s.publisher.Publish(ctx, summary); err != nil {
+			s.logger.Error("Failed to publish balance summary",
+				logger.WalletID(walletID),
+				logger.Err(err),
+			)
+			continue
+		}
+		published++
+	}
+
+	return published
+}
+
+// ============================================
+// Lifecycle
+// ============================================
+
+// Start запускает публикацию окон раз в Interval. Не блокирует; повторный вызов - no-op.
+func (s *Summarizer) Start() {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+
+	if s.started || s.ctx.Err() != nil {
+		return
+	}
+	s.started = true
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run()
+	}()
+}
+
+// Stop останавливает таймер и публикует накопленное окно.
+// Вызывается после остановки шины, чтобы в окно попали все доставленные события.
+func (s *Summarizer) Stop(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("balance summary stop interrupted: %w", ctx.Err())
+	}
+
+	s.Flush(ctx)
+	return nil
+}
+
+// run публикует окно раз в interval до Stop.
+func (s *Summarizer) run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			s.heartbeat.Stopped()
+			return
+		case <-ticker.C:
+			// Начатое окно публикуется до конца, даже если Stop уже вызван
+			s.heartbeat.RunStarted()
+			ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+			published := s.Flush(ctx)
+			cancel()
+			s.heartbeat.RunFinished(published, nil)
+		}
+	}
+}
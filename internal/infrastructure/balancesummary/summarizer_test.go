@@ -0,0 +1,267 @@
+package balancesummary
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/eventbus"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// Все тесты пакета проверяются на утечку горутин.
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+var windowStart = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// walletStub - кошельки с заданными балансами.
+type walletStub struct {
+	mu      sync.Mutex
+	wallets map[uuid.UUID]*entities.Wallet
+}
+
+func (s *walletStub) add(t *testing.T, available, pending string) uuid.UUID {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	availableMoney, err := valueobjects.NewMoney(available, valueobjects.USD)
+	require.NoError(t, err)
+	pendingMoney, err := valueobjects.NewMoney(pending, valueobjects.USD)
+	require.NoError(t, err)
+	limit, _ := valueobjects.NewMoney("10000", valueobjects.USD)
+
+	id := uuid.New()
+	if s.wallets == nil {
+		s.wallets = make(map[uuid.UUID]*entities.Wallet)
+	}
+	s.wallets[id] = entities.ReconstructWallet(id, uuid.New(), valueobjects.USD, entities.WalletTypeFiat, entities.WalletStatusActive,
+		availableMoney, pendingMoney, 1, limit, limit, "", nil, windowStart, windowStart)
+	return id
+}
+
+func (s *walletStub) FindByID(_ context.Context, id uuid.UUID) (*entities.Wallet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if w, ok := s.wallets[id]; ok {
+		return w, nil
+	}
+	return nil, domainErrors.ErrEntityNotFound
+}
+
+func newTestSummarizer(wallets WalletReader, cfg Config) (*Summarizer, *memory.EventPublisher) {
+	publisher := memory.NewEventPublisher(memory.NewStore())
+	s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), wallets, publisher, cfg)
+	s.now = func() time.Time { return windowStart.Add(5 * time.Second) }
+	s.windowStart = windowStart
+	return s, publisher
+}
+
+// balanceVersion - версии баланса событий в порядке создания, как у кошелька.
+var balanceVersion atomic.Int64
+
+func credited(walletID, transactionID uuid.UUID) *events.WalletCredited {
+	amount, _ := valueobjects.NewMoney("1.00", valueobjects.USD)
+	return events.NewWalletCredited(walletID, amount, transactionID, amount, balanceVersion.Add(1))
+}
+
+func debited(walletID, transactionID uuid.UUID) *events.WalletDebited {
+	amount, _ := valueobjects.NewMoney("1.00", valueobjects.USD)
+	return events.NewWalletDebited(walletID, amount, transactionID, amount, balanceVersion.Add(1))
+}
+
+// summaries возвращает опубликованные summary по кошельку.
+func summaries(publisher *memory.EventPublisher) map[uuid.UUID]*events.WalletBalanceSummary {
+	result := make(map[uuid.UUID]*events.WalletBalanceSummary)
+	for _, e := range publisher.Events() {
+		if s, ok := e.(*events.WalletBalanceSummary); ok {
+			result[s.WalletID] = s
+		}
+	}
+	return result
+}
+
+func TestSummarizer_ManyChangesOneSummary(t *testing.T) {
+	wallets := &walletStub{}
+	walletID := wallets.add(t, "120.50", "7.00")
+	s, publisher := newTestSummarizer(wallets, Config{})
+
+	var last uuid.UUID
+	for i := 0; i < 150; i++ {
+		last = uuid.New()
+		if i%3 == 0 {
+			s.Observe(debited(walletID, last))
+		} else {
+			s.Observe(credited(walletID, last))
+		}
+	}
+	// Прочие события не считаются изменениями баланса
+	s.Observe(events.NewWalletSuspended(walletID, "test"))
+
+	assert.Equal(t, 1, s.Flush(context.Background()))
+
+	require.Len(t, publisher.Events(), 1)
+	summary := summaries(publisher)[walletID]
+	require.NotNil(t, summary)
+	assert.Equal(t, 150, summary.ChangeCount)
+	assert.Equal(t, last, summary.LastTransactionID)
+	assert.Equal(t, "120.50 USD", summary.AvailableBalance.String())
+	assert.Equal(t, "7.00 USD", summary.PendingBalance.String())
+	assert.Equal(t, windowStart, summary.WindowStart)
+	assert.Equal(t, windowStart.Add(5*time.Second), summary.WindowEnd)
+
+	// Следующее окно пустое: без изменений summary не публикуется
+	assert.Equal(t, 0, s.Flush(context.Background()))
+	assert.Len(t, publisher.Events(), 1)
+}
+
+func TestSummarizer_DistinctWallets(t *testing.T) {
+	wallets := &walletStub{}
+	first := wallets.add(t, "10.00", "0")
+	second := wallets.add(t, "20.00", "0")
+	s, publisher := newTestSummarizer(wallets, Config{})
+
+	s.Observe(credited(first, uuid.New()))
+	s.Observe(credited(second, uuid.New()))
+	s.Observe(debited(second, uuid.New()))
+
+	assert.Equal(t, 2, s.Flush(context.Background()))
+
+	got := summaries(publisher)
+	require.Len(t, got, 2)
+	assert.Equal(t, 1, got[first].ChangeCount)
+	assert.Equal(t, "10.00 USD", got[first].AvailableBalance.String())
+	assert.Equal(t, 2, got[second].ChangeCount)
+	assert.Equal(t, "20.00 USD", got[second].AvailableBalance.String())
+}
+
+func TestSummarizer_LastTransactionByBalanceVersion(t *testing.T) {
+	wallets := &walletStub{}
+	walletID := wallets.add(t, "1.00", "0")
+	s, publisher := newTestSummarizer(wallets, Config{})
+
+	// Подписки на credited и debited независимы: событие может прийти позже более нового
+	newer, older := uuid.New(), uuid.New()
+	s.record(walletID, newer, 8)
+	s.record(walletID, older, 7)
+
+	s.Flush(context.Background())
+	assert.Equal(t, newer, summaries(publisher)[walletID].LastTransactionID)
+}
+
+func TestSummarizer_MaxWallets(t *testing.T) {
+	wallets := &walletStub{}
+	tracked := wallets.add(t, "1.00", "0")
+	overflow := wallets.add(t, "2.00", "0")
+	s, publisher := newTestSummarizer(wallets, Config{MaxWallets: 1})
+
+	s.Observe(credited(tracked, uuid.New()))
+	s.Observe(credited(overflow, uuid.New()))
+	s.Observe(credited(overflow, uuid.New()))
+	// Уже отслеживаемый кошелёк учитывается и после переполнения
+	s.Observe(credited(tracked, uuid.New()))
+
+	s.mu.Lock()
+	assert.Len(t, s.changes, 1)
+	assert.Equal(t, 2, s.droppedChanges)
+	s.mu.Unlock()
+
+	assert.Equal(t, 1, s.Flush(context.Background()))
+	got := summaries(publisher)
+	assert.Equal(t, 2, got[tracked].ChangeCount)
+	assert.NotContains(t, got, overflow)
+
+	// Лимит действует в пределах окна
+	s.Observe(credited(overflow, uuid.New()))
+	assert.Equal(t, 1, s.Flush(context.Background()))
+	assert.Contains(t, summaries(publisher), overflow)
+}
+
+func TestSummarizer_SkipsMissingWallet(t *testing.T) {
+	wallets := &walletStub{}
+	existing := wallets.add(t, "1.00", "0")
+	s, publisher := newTestSummarizer(wallets, Config{})
+
+	s.Observe(credited(existing, uuid.New()))
+	s.Observe(credited(uuid.New(), uuid.New()))
+
+	assert.Equal(t, 1, s.Flush(context.Background()))
+	assert.Contains(t, summaries(publisher), existing)
+}
+
+func TestSummarizer_StopFlushesCommittedEvents(t *testing.T) {
+	wallets := &walletStub{}
+	walletID := wallets.add(t, "42.00", "0")
+	s, publisher := newTestSummarizer(wallets, Config{Interval: time.Hour})
+
+	bus := eventbus.New(slog.New(slog.NewTextHandler(io.Discard, nil)), eventbus.Config{})
+	eventbus.Subscribe(bus, "balance-summary-credited", func(ctx context.Context, e *events.WalletCredited) error {
+		return s.Handle(ctx, e)
+	})
+	bus.Start()
+	s.Start()
+
+	for i := 0; i < 10; i++ {
+		bus.Publish(credited(walletID, uuid.New()))
+	}
+
+	// Порядок остановки как в контейнере: шина (drain), затем summary (flush)
+	require.NoError(t, bus.Stop(context.Background()))
+	require.NoError(t, s.Stop(context.Background()))
+
+	summary := summaries(publisher)[walletID]
+	require.NotNil(t, summary)
+	assert.Equal(t, 10, summary.ChangeCount)
+	assert.Equal(t, "42.00 USD", summary.AvailableBalance.String())
+}
+
+func TestSummarizer_DuplicateDeliveryCountedOnce(t *testing.T) {
+	wallets := &walletStub{}
+	walletID := wallets.add(t, "42.00", "0")
+	s, publisher := newTestSummarizer(wallets, Config{Interval: time.Hour})
+
+	bus := eventbus.New(slog.New(slog.NewTextHandler(io.Discard, nil)), eventbus.Config{})
+	s.Subscribe(bus, memory.NewDedupStore(memory.NewStore()))
+	bus.Start()
+
+	once := credited(walletID, uuid.New())
+	twice := debited(walletID, uuid.New())
+	thrice := credited(walletID, uuid.New())
+	bus.PublishAll(once, twice, twice, thrice, thrice, thrice)
+
+	require.NoError(t, bus.Stop(context.Background()))
+	require.NoError(t, s.Stop(context.Background()))
+
+	summary := summaries(publisher)[walletID]
+	require.NotNil(t, summary)
+	assert.Equal(t, 3, summary.ChangeCount)
+}
+
+func TestSummarizer_PublishesEveryInterval(t *testing.T) {
+	wallets := &walletStub{}
+	walletID := wallets.add(t, "1.00", "0")
+	s, publisher := newTestSummarizer(wallets, Config{Interval: 10 * time.Millisecond})
+	s.Start()
+	defer func() { _ = s.Stop(context.Background()) }()
+
+	s.Observe(credited(walletID, uuid.New()))
+
+	assert.Eventually(t, func() bool {
+		return len(summaries(publisher)) == 1
+	}, time.Second, 5*time.Millisecond)
+}
@@ -12,14 +12,14 @@ import (
 )
 
 // NewRedisClient creates and validates a Redis client connection.
-func NewRedisClient(cfg config.RedisConfig) (*redis.Client, error) {
+func NewRedisClient(ctx context.Context, cfg config.RedisConfig) (*redis.Client, error) {
 	rdb := redis.NewClient(&redis.Options{
 		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
 		Password: cfg.Password,
 		DB:       cfg.DB,
 	})
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	if err := rdb.Ping(ctx).Err(); err != nil {
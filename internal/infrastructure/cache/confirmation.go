@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check
+var _ ports.ConfirmationStore = (*RedisConfirmationStore)(nil)
+
+// RedisConfirmationStore keeps confirmation tokens in Redis so that a token
+// issued by one instance can be consumed on another.
+type RedisConfirmationStore struct {
+	rdb *redis.Client
+}
+
+// NewRedisConfirmationStore creates a new Redis-backed confirmation store.
+func NewRedisConfirmationStore(rdb *redis.Client) *RedisConfirmationStore {
+	return &RedisConfirmationStore{rdb: rdb}
+}
+
+// Issue stores the token with its request fingerprint for ttl.
+func (s *RedisConfirmationStore) Issue(ctx context.Context, token, fingerprint string, ttl time.Duration) error {
+	if err := s.rdb.Set(ctx, confirmationKey(token), fingerprint, ttl).Err(); err != nil {
+		return fmt.Errorf("confirmation: failed to issue token: %w", err)
+	}
+	return nil
+}
+
+// Consume deletes the token with GETDEL, so concurrent requests with the
+// same token cannot both succeed.
+func (s *RedisConfirmationStore) Consume(ctx context.Context, token string) (string, bool, error) {
+	fingerprint, err := s.rdb.GetDel(ctx, confirmationKey(token)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("confirmation: failed to consume token: %w", err)
+	}
+	return fingerprint, true, nil
+}
+
+func confirmationKey(token string) string {
+	return "confirm:" + token
+}
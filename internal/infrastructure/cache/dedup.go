@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check
+var _ ports.DedupStore = (*RedisDedupStore)(nil)
+
+// RedisDedupStore is a Redis fast path in front of a durable DedupStore.
+//
+// Redis only remembers events the durable store has already reported as
+// processed, so redeliveries after the first duplicate skip the database.
+// A fresh mark is never cached: it may still be rolled back with the
+// consumer's transaction. Redis errors fall through to the durable store.
+type RedisDedupStore struct {
+	rdb   *redis.Client
+	inner ports.DedupStore
+	ttl   time.Duration
+}
+
+// NewRedisDedupStore wraps inner; cached duplicates expire after ttl.
+func NewRedisDedupStore(rdb *redis.Client, inner ports.DedupStore, ttl time.Duration) *RedisDedupStore {
+	return &RedisDedupStore{rdb: rdb, inner: inner, ttl: ttl}
+}
+
+// MarkProcessed returns false from Redis for a known duplicate, otherwise
+// asks the durable store and caches a duplicate it reports.
+func (s *RedisDedupStore) MarkProcessed(ctx context.Context, consumer string, eventID uuid.UUID) (bool, error) {
+	key := dedupKey(consumer, eventID)
+	if n, err := s.rdb.Exists(ctx, key).Result(); err == nil && n > 0 {
+		return false, nil
+	}
+
+	marked, err := s.inner.MarkProcessed(ctx, consumer, eventID)
+	if err != nil {
+		return false, err
+	}
+	if !marked {
+		_ = s.rdb.Set(ctx, key, 1, s.ttl).Err()
+	}
+	return marked, nil
+}
+
+// Unmark removes the mark from both Redis and the durable store.
+func (s *RedisDedupStore) Unmark(ctx context.Context, consumer string, eventID uuid.UUID) error {
+	_ = s.rdb.Del(ctx, dedupKey(consumer, eventID)).Err()
+	return s.inner.Unmark(ctx, consumer, eventID)
+}
+
+// DeleteBefore purges the durable store; cached duplicates expire by TTL.
+func (s *RedisDedupStore) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return s.inner.DeleteBefore(ctx, cutoff)
+}
+
+func dedupKey(consumer string, eventID uuid.UUID) string {
+	return "dedup:" + consumer + ":" + eventID.String()
+}
@@ -0,0 +1,59 @@
+//go:build testcontainers
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+	"github.com/Haleralex/wallethub/internal/infrastructure/leaselock"
+)
+
+// setupRedis запускает Redis в контейнере и возвращает клиента.
+func setupRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	ctx := context.Background()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "redis:7-alpine",
+			ExposedPorts: []string{"6379/tcp"},
+			WaitingFor:   wait.ForLog("Ready to accept connections").WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = container.Terminate(context.Background()) })
+
+	endpoint, err := container.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	rdb := redis.NewClient(&redis.Options{Addr: endpoint})
+	t.Cleanup(func() { _ = rdb.Close() })
+	require.NoError(t, rdb.Ping(ctx).Err())
+
+	return rdb
+}
+
+func TestRedisLeaseLock_Integration_Conformance(t *testing.T) {
+	rdb := setupRedis(t)
+
+	porttest.RunLeaseLockTests(t, func(t *testing.T) porttest.LeaseLockHarness {
+		require.NoError(t, rdb.FlushDB(context.Background()).Err())
+		return porttest.LeaseLockHarness{
+			Lock:  leaselock.NewRedis(rdb),
+			Other: leaselock.NewRedis(rdb),
+			// Истечение lease в Redis: ключ владельца пропал, счётчик token остался
+			Break: func(t *testing.T, key string) {
+				require.NoError(t, rdb.Del(context.Background(), "lease:"+key).Err())
+			},
+		}
+	})
+}
@@ -0,0 +1,515 @@
+// Package eventbus - in-process шина domain events для внутренних потребителей.
+//
+// Шина раздаёт уже закоммиченные события компонентам того же процесса
+// (SSE, мониторинг, проекции, long-poll), чтобы им не приходилось
+// оборачивать EventPublisher каждому по отдельности.
+//
+// Гарантии:
+//   - Publish никогда не блокирует: у каждого подписчика своя буферизованная
+//     очередь, при переполнении событие для этого подписчика отбрасывается
+//     и учитывается в счётчике dropped
+//   - Медленный подписчик не задерживает остальных (отдельная горутина на подписку)
+//   - Stop дожидается обработки уже принятых событий (drain)
+//
+// В синхронном режиме (Config.Sync, см. delivery.go) Publish вызывает
+// обработчики сразу, в горутине запроса, но не дольше SyncBudget.
+//
+// Шина best-effort: для гарантированной доставки используется outbox.
+// Без outbox relay потерю событий при падении процесса закрывает spool
+// (Config.Spool, см. spool.go).
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// DefaultBufferSize - размер очереди подписчика по умолчанию.
+const DefaultBufferSize = 256
+
+// DefaultSyncBudget - лимит синхронной обработки событий по умолчанию.
+const DefaultSyncBudget = 250 * time.Millisecond
+
+// Config - настройки шины.
+type Config struct {
+	// BufferSize - ёмкость очереди каждого подписчика.
+	BufferSize int
+
+	// Sync включает синхронную доставку: Publish ждёт обработчиков.
+	Sync bool
+	// SyncBudget - сколько Publish (одно событие или события одного COMMIT)
+	// может ждать обработчиков. Не уложившиеся обработчики продолжают
+	// работу в фоне, оставшиеся события уходят в очереди подписчиков.
+	SyncBudget time.Duration
+
+	// Spool - журнал недоставленных событий (nil - не ведётся).
+	Spool *Spool
+}
+
+// Bus - потокобезопасный диспетчер событий.
+type Bus struct {
+	logger     *slog.Logger
+	bufferSize int
+	sync       bool
+	syncBudget time.Duration
+	spool      *Spool
+
+	// budgetExceeded - сколько раз синхронная доставка не уложилась в SyncBudget
+	budgetExceeded atomic.Uint64
+
+	mu      sync.RWMutex
+	subs    []*Subscription
+	started bool
+	closed  bool
+
+	// ctx передаётся обработчикам; отменяется, если Stop не дождался drain
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New создаёт шину. Обработчики начинают работать после Start.
+func New(logger *slog.Logger, cfg Config) *Bus {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultBufferSize
+	}
+	if cfg.SyncBudget <= 0 {
+		cfg.SyncBudget = DefaultSyncBudget
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Bus{
+		logger:     logger,
+		bufferSize: cfg.BufferSize,
+		sync:       cfg.Sync,
+		syncBudget: cfg.SyncBudget,
+		spool:      cfg.Spool,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// BudgetExceeded - сколько раз синхронная доставка не уложилась в SyncBudget.
+func (b *Bus) BudgetExceeded() uint64 {
+	return b.budgetExceeded.Load()
+}
+
+// ============================================
+// Subscriptions
+// ============================================
+
+// Subscription - подписка с собственной очередью и счётчиками.
+type Subscription struct {
+	bus     *Bus
+	name    string
+	match   func(events.DomainEvent) bool
+	handler ports.EventHandler
+	queue   chan delivery
+	once    sync.Once
+
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+	failed    atomic.Uint64
+}
+
+// Name возвращает имя подписчика (для логов и метрик).
+func (s *Subscription) Name() string {
+	return s.name
+}
+
+// Delivered - число событий, переданных обработчику.
+func (s *Subscription) Delivered() uint64 {
+	return s.delivered.Load()
+}
+
+// Dropped - число событий, отброшенных из-за переполнения очереди.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Failed - число событий, обработчик которых вернул ошибку или запаниковал.
+func (s *Subscription) Failed() uint64 {
+	return s.failed.Load()
+}
+
+// Unsubscribe отписывает подписчика. Уже принятые события будут обработаны.
+// Повторный вызов безопасен.
+func (s *Subscription) Unsubscribe() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	for i, sub := range s.bus.subs {
+		if sub == s {
+			s.bus.subs = append(s.bus.subs[:i], s.bus.subs[i+1:]...)
+			break
+		}
+	}
+	s.close()
+}
+
+// close закрывает очередь; вызывается под bus.mu.
+func (s *Subscription) close() {
+	s.once.Do(func() { close(s.queue) })
+}
+
+// Subscribe регистрирует типизированный обработчик для событий типа E.
+//
+// Example:
+//
+//	eventbus.Subscribe(bus, "velocity", func(ctx context.Context, e *events.WalletCredited) error {
+//	    return monitor.Track(ctx, e.WalletID, e.Amount)
+//	})
+func Subscribe[E events.DomainEvent](b *Bus, name string, handler func(ctx context.Context, event E) error) *Subscription {
+	return b.subscribe(name,
+		func(event events.DomainEvent) bool {
+			_, ok := event.(E)
+			return ok
+		},
+		func(ctx context.Context, event events.DomainEvent) error {
+			return handler(ctx, event.(E))
+		},
+	)
+}
+
+// SubscribeAll регистрирует обработчик для всех событий.
+func (b *Bus) SubscribeAll(name string, handler ports.EventHandler) *Subscription {
+	return b.subscribe(name, func(events.DomainEvent) bool { return true }, handler)
+}
+
+func (b *Bus) subscribe(name string, match func(events.DomainEvent) bool, handler ports.EventHandler) *Subscription {
+	sub := &Subscription{
+		bus:     b,
+		name:    name,
+		match:   match,
+		handler: handler,
+		queue:   make(chan delivery, b.bufferSize),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// После Stop подписка инертна: очередь закрыта, события не приходят
+	if b.closed {
+		sub.close()
+		return sub
+	}
+
+	b.subs = append(b.subs, sub)
+	if b.started {
+		b.runWorker(sub)
+	}
+	return sub
+}
+
+// ============================================
+// Publish
+// ============================================
+
+// Publish раздаёт событие подходящим подписчикам без блокировки.
+//
+// Если очередь подписчика заполнена, событие для него отбрасывается
+// (dropped++), остальные подписчики его получают. После Stop - no-op.
+// В синхронном режиме см. PublishAll.
+func (b *Bus) Publish(event events.DomainEvent) {
+	b.PublishAll(event)
+}
+
+// PublishAll раздаёт события по порядку.
+//
+// В синхронном режиме обработчики вызываются сразу, и PublishAll
+// возвращается, когда все они завершились или истёк SyncBudget на все
+// события вызова. Обработчик, не уложившийся в бюджет, получает отменённый
+// context и дорабатывает в фоне; остальные пары (подписчик, событие)
+// ставятся в очереди подписчиков, как в асинхронном режиме.
+//
+// Со spool события сначала записываются в журнал и удаляются из него,
+// когда их обработали все подписчики (см. PublishAllTracked).
+func (b *Bus) PublishAll(evts ...events.DomainEvent) {
+	if b.spool == nil || len(evts) == 0 {
+		b.publish(nil, evts)
+		return
+	}
+
+	seq := b.spool.append(evts)
+	b.PublishAllTracked(func(delivered bool) {
+		if delivered {
+			b.spool.ack(seq)
+		}
+	}, evts...)
+}
+
+// PublishAllTracked - PublishAll, сообщающий о завершении доставки.
+//
+// done вызывается один раз, когда все подходящие подписчики обработали
+// события (или не получили их). delivered=false - хотя бы одна пара
+// (подписчик, событие) не обработана: событие отброшено переполненной
+// очередью, опубликовано после Stop, или обработчик вернул ошибку
+// или запаниковал. done может вызываться из горутин подписчиков.
+func (b *Bus) PublishAllTracked(done func(delivered bool), evts ...events.DomainEvent) {
+	tr := newTracker(done)
+	b.publish(tr, evts)
+	tr.finish(true)
+}
+
+// publish раскладывает события по подписчикам; tr == nil - без учёта доставки.
+func (b *Bus) publish(tr *tracker, evts []events.DomainEvent) {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		tr.fail()
+		return
+	}
+	// До Start события копятся в очередях и в синхронном режиме
+	if !b.sync || !b.started {
+		defer b.mu.RUnlock()
+		for _, event := range evts {
+			for _, sub := range b.subs {
+				if sub.match(event) {
+					b.enqueue(sub, delivery{event: event, tracker: tr})
+				}
+			}
+		}
+		return
+	}
+	subs := append([]*Subscription(nil), b.subs...)
+	b.mu.RUnlock()
+
+	b.publishSync(subs, evts, tr)
+}
+
+// publishSync - синхронная доставка с общим бюджетом на вызов.
+func (b *Bus) publishSync(subs []*Subscription, evts []events.DomainEvent, tr *tracker) {
+	deadline := time.Now().Add(b.syncBudget)
+	exceeded := false
+
+	for _, event := range evts {
+		for _, sub := range subs {
+			if !sub.match(event) {
+				continue
+			}
+			if !exceeded && !b.dispatchWithin(sub, delivery{event: event, tracker: tr}, deadline) {
+				exceeded = true
+				b.budgetExceeded.Add(1)
+				b.logger.Warn("Event bus sync delivery exceeded budget, deferring remaining events",
+					slog.String("subscriber", sub.name),
+					slog.String("event_type", event.EventType()),
+					slog.Duration("budget", b.syncBudget),
+				)
+				continue
+			}
+			if exceeded {
+				b.enqueueOpen(sub, delivery{event: event, tracker: tr})
+			}
+		}
+	}
+}
+
+// dispatchWithin вызывает обработчик и ждёт его не дольше deadline.
+// false - бюджет исчерпан; обработчик дорабатывает в фоне (Stop его дождётся).
+// После Stop событие не доставляется.
+func (b *Bus) dispatchWithin(sub *Subscription, d delivery, deadline time.Time) bool {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		d.tracker.fail()
+		return true
+	}
+	b.wg.Add(1)
+	d.tracker.add()
+	b.mu.RUnlock()
+
+	ctx, cancel := context.WithDeadline(b.ctx, deadline)
+	done := make(chan struct{})
+
+	go func() {
+		defer b.wg.Done()
+		defer cancel()
+		defer close(done)
+		d.tracker.finish(b.dispatch(ctx, sub, d.event))
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// enqueueOpen ставит событие в очередь, если шина ещё не остановлена.
+func (b *Bus) enqueueOpen(sub *Subscription, d delivery) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.closed && slices.Contains(b.subs, sub) {
+		b.enqueue(sub, d)
+		return
+	}
+	d.tracker.fail()
+}
+
+// enqueue кладёт событие в очередь подписчика без блокировки; вызывается под b.mu.
+func (b *Bus) enqueue(sub *Subscription, d delivery) {
+	d.tracker.add()
+	select {
+	case sub.queue <- d:
+	default:
+		d.tracker.finish(false)
+		// Первый drop логируем, дальше только счётчик - не шумим на hot path
+		if sub.dropped.Add(1) == 1 {
+			b.logger.Warn("Event bus subscriber queue full, dropping events",
+				slog.String("subscriber", sub.name),
+				slog.String("event_type", d.event.EventType()),
+			)
+		}
+	}
+}
+
+// ============================================
+// Lifecycle
+// ============================================
+
+// Start запускает обработчики подписчиков. Не блокирует; повторный вызов - no-op.
+func (b *Bus) Start() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.started || b.closed {
+		return
+	}
+	b.started = true
+
+	for _, sub := range b.subs {
+		b.runWorker(sub)
+	}
+}
+
+// Stop перестаёт принимать события и ждёт, пока подписчики обработают
+// очереди. Если ctx истёк раньше, контекст обработчиков отменяется
+// и возвращается ошибка - оставшиеся горутины завершатся, как только
+// обработчики отреагируют на отмену.
+func (b *Bus) Stop(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		for _, sub := range b.subs {
+			sub.close()
+		}
+		b.subs = nil
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		b.cancel()
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return fmt.Errorf("event bus drain interrupted: %w", ctx.Err())
+	}
+}
+
+// runWorker запускает горутину подписчика; вызывается под b.mu.
+func (b *Bus) runWorker(sub *Subscription) {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for d := range sub.queue {
+			d.tracker.finish(b.dispatch(b.ctx, sub, d.event))
+		}
+	}()
+}
+
+// dispatch вызывает обработчик, изолируя ошибки и panic.
+// false - обработчик вернул ошибку или запаниковал.
+func (b *Bus) dispatch(ctx context.Context, sub *Subscription, event events.DomainEvent) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+			sub.failed.Add(1)
+			b.logger.Error("Event bus subscriber panicked",
+				slog.String("subscriber", sub.name),
+				slog.String("event_type", event.EventType()),
+				slog.Any("panic", r),
+			)
+		}
+	}()
+
+	sub.delivered.Add(1)
+	if err := sub.handler(ctx, event); err != nil {
+		sub.failed.Add(1)
+		b.logger.Error("Event bus subscriber failed",
+			slog.String("subscriber", sub.name),
+			slog.String("event_type", event.EventType()),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+	return true
+}
+
+// ============================================
+// Delivery tracking
+// ============================================
+
+// delivery - событие в очереди подписчика.
+type delivery struct {
+	event   events.DomainEvent
+	tracker *tracker // nil - доставка не отслеживается
+}
+
+// tracker считает незавершённые пары (подписчик, событие) одного
+// PublishAllTracked. Методы допускают nil.
+type tracker struct {
+	pending atomic.Int64
+	failed  atomic.Bool
+	done    func(delivered bool)
+}
+
+// newTracker создаёт счётчик с одной удерживающей ссылкой: done не
+// вызывается, пока публикующий не разложит все события и не вызовет finish.
+func newTracker(done func(delivered bool)) *tracker {
+	tr := &tracker{done: done}
+	tr.pending.Store(1)
+	return tr
+}
+
+func (t *tracker) add() {
+	if t != nil {
+		t.pending.Add(1)
+	}
+}
+
+// fail отмечает пару, которая не будет доставлена.
+func (t *tracker) fail() {
+	if t != nil {
+		t.failed.Store(true)
+	}
+}
+
+// finish завершает пару; последняя вызывает done.
+func (t *tracker) finish(ok bool) {
+	if t == nil {
+		return
+	}
+	if !ok {
+		t.fail()
+	}
+	if t.pending.Add(-1) == 0 {
+		t.done(!t.failed.Load())
+	}
+}
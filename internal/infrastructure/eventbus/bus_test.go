@@ -0,0 +1,214 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// Все тесты пакета проверяются на утечку горутин.
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func newTestBus(bufferSize int) *Bus {
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{BufferSize: bufferSize})
+}
+
+func credited() *events.WalletCredited {
+	amount, _ := valueobjects.NewMoneyFromInt(10, valueobjects.MustNewCurrency("USD"))
+	return events.NewWalletCredited(uuid.New(), amount, uuid.New(), amount, 1)
+}
+
+// recorder собирает полученные события.
+type recorder struct {
+	mu     sync.Mutex
+	events []events.DomainEvent
+}
+
+func (r *recorder) handle(_ context.Context, event events.DomainEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recorder) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events)
+}
+
+func stop(t *testing.T, bus *Bus) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, bus.Stop(ctx))
+}
+
+func TestBus_FanOut(t *testing.T) {
+	bus := newTestBus(16)
+	bus.Start()
+
+	first, second := &recorder{}, &recorder{}
+	bus.SubscribeAll("first", first.handle)
+	bus.SubscribeAll("second", second.handle)
+
+	var typed []*events.WalletCredited
+	var typedMu sync.Mutex
+	Subscribe(bus, "typed", func(_ context.Context, e *events.WalletCredited) error {
+		typedMu.Lock()
+		defer typedMu.Unlock()
+		typed = append(typed, e)
+		return nil
+	})
+
+	event := credited()
+	bus.Publish(event)
+	bus.Publish(events.NewWalletSuspended(uuid.New(), "fraud review"))
+
+	// Stop дожидается обработки очередей
+	stop(t, bus)
+
+	assert.Equal(t, 2, first.len())
+	assert.Equal(t, 2, second.len())
+	require.Len(t, typed, 1, "typed subscriber receives only its event type")
+	assert.Same(t, event, typed[0])
+}
+
+func TestBus_SlowSubscriberIsolation(t *testing.T) {
+	bus := newTestBus(2)
+	bus.Start()
+
+	release := make(chan struct{})
+	slow := bus.SubscribeAll("slow", func(ctx context.Context, _ events.DomainEvent) error {
+		<-release
+		return nil
+	})
+
+	fast := &recorder{}
+	fastSub := bus.SubscribeAll("fast", fast.handle)
+
+	// fast получает каждое событие сразу, пока slow висит на первом,
+	// а Publish не блокируется на переполненной очереди slow
+	const total = 20
+	for i := 1; i <= total; i++ {
+		start := time.Now()
+		bus.Publish(credited())
+		assert.Less(t, time.Since(start), 50*time.Millisecond, "Publish blocked on a slow subscriber")
+
+		require.Eventually(t, func() bool { return fast.len() == i }, time.Second, time.Millisecond,
+			"fast subscriber delayed by slow one")
+	}
+
+	assert.Positive(t, slow.Dropped())
+	assert.Zero(t, fastSub.Dropped())
+
+	close(release)
+	stop(t, bus)
+
+	// Принятые slow события обработаны, отброшенные - посчитаны
+	assert.Equal(t, uint64(total), slow.Delivered()+slow.Dropped())
+}
+
+func TestBus_Shutdown(t *testing.T) {
+	t.Run("DrainsQueuedEvents", func(t *testing.T) {
+		bus := newTestBus(64)
+		rec := &recorder{}
+		bus.SubscribeAll("late", rec.handle)
+
+		// До Start события копятся в очереди
+		for i := 0; i < 10; i++ {
+			bus.Publish(credited())
+		}
+		bus.Start()
+		stop(t, bus)
+
+		assert.Equal(t, 10, rec.len())
+
+		// После Stop Publish и Subscribe - no-op
+		bus.Publish(credited())
+		inert := bus.SubscribeAll("after-stop", rec.handle)
+		assert.Equal(t, 10, rec.len())
+		assert.Zero(t, inert.Delivered())
+	})
+
+	t.Run("DeadlineCancelsHandlers", func(t *testing.T) {
+		bus := newTestBus(4)
+		bus.Start()
+
+		started := make(chan struct{})
+		bus.SubscribeAll("stuck", func(ctx context.Context, _ events.DomainEvent) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		bus.Publish(credited())
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := bus.Stop(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// Обработчик получил отмену - горутина завершается (проверяет goleak)
+		bus.wg.Wait()
+	})
+
+	t.Run("StopIsIdempotent", func(t *testing.T) {
+		bus := newTestBus(4)
+		bus.Start()
+		stop(t, bus)
+		stop(t, bus)
+	})
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	bus := newTestBus(8)
+	bus.Start()
+
+	rec := &recorder{}
+	sub := bus.SubscribeAll("temporary", rec.handle)
+	bus.Publish(credited())
+
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	bus.Publish(credited())
+
+	stop(t, bus)
+	assert.Equal(t, 1, rec.len())
+}
+
+func TestBus_HandlerFailuresAreIsolated(t *testing.T) {
+	bus := newTestBus(8)
+	bus.Start()
+
+	failing := bus.SubscribeAll("failing", func(context.Context, events.DomainEvent) error {
+		return errors.New("projection unavailable")
+	})
+	panicking := bus.SubscribeAll("panicking", func(context.Context, events.DomainEvent) error {
+		panic("boom")
+	})
+	rec := &recorder{}
+	bus.SubscribeAll("healthy", rec.handle)
+
+	bus.Publish(credited())
+	bus.Publish(credited())
+	stop(t, bus)
+
+	assert.Equal(t, uint64(2), failing.Failed())
+	assert.Equal(t, uint64(2), panicking.Failed(), "worker survives a panic")
+	assert.Equal(t, 2, rec.len())
+}
@@ -0,0 +1,66 @@
+// Package eventbus - дедупликация at-least-once доставки на стороне потребителя.
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// WithDedup оборачивает обработчик потребителя consumer: событие, уже
+// отмеченное в store, пропускается без вызова handler.
+//
+// Режим best-effort - для обработчиков без собственной транзакции
+// (in-memory проекции, вызовы внешних API):
+//   - отметка пишется до handler и снимается, если handler вернул ошибку,
+//     чтобы повторная доставка обработала событие
+//   - процесс, упавший между отметкой и handler, теряет событие для этого
+//     потребителя
+//   - недоступный store не блокирует обработку: handler вызывается, ошибка
+//     store возвращается шине (ERROR в лог), возможен дубль
+//
+// Обработчику, пишущему в БД, нужен WithDedupInUnitOfWork.
+func WithDedup(consumer string, store ports.DedupStore, handler ports.EventHandler) ports.EventHandler {
+	return func(ctx context.Context, event events.DomainEvent) error {
+		marked, err := store.MarkProcessed(ctx, consumer, event.EventID())
+		if err != nil {
+			if handlerErr := handler(ctx, event); handlerErr != nil {
+				return handlerErr
+			}
+			return fmt.Errorf("dedup %s: failed to mark event %s: %w", consumer, event.EventID(), err)
+		}
+		if !marked {
+			return nil
+		}
+
+		if err := handler(ctx, event); err != nil {
+			if unmarkErr := store.Unmark(context.WithoutCancel(ctx), consumer, event.EventID()); unmarkErr != nil {
+				return errors.Join(err, fmt.Errorf("dedup %s: failed to unmark event %s: %w", consumer, event.EventID(), unmarkErr))
+			}
+			return err
+		}
+		return nil
+	}
+}
+
+// WithDedupInUnitOfWork - WithDedup для обработчика, который пишет через uow:
+// отметка и записи handler фиксируются одной транзакцией, поэтому эффект
+// события применяется ровно один раз, в том числе при падении процесса.
+// handler должен использовать переданный ему ctx (транзакцию).
+func WithDedupInUnitOfWork(consumer string, store ports.DedupStore, uow ports.UnitOfWork, handler ports.EventHandler) ports.EventHandler {
+	return func(ctx context.Context, event events.DomainEvent) error {
+		return uow.Execute(ctx, func(txCtx context.Context) error {
+			marked, err := store.MarkProcessed(txCtx, consumer, event.EventID())
+			if err != nil {
+				return fmt.Errorf("dedup %s: failed to mark event %s: %w", consumer, event.EventID(), err)
+			}
+			if !marked {
+				return nil
+			}
+			return handler(txCtx, event)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+func TestWithDedup_ExactlyOnceEffect(t *testing.T) {
+	for _, deliveries := range []int{2, 3} {
+		bus := newTestBus(16)
+		rec := &recorder{}
+		bus.SubscribeAll("projection", WithDedup("projection", memory.NewDedupStore(memory.NewStore()), rec.handle))
+		bus.Start()
+
+		event, other := credited(), credited()
+		for i := 0; i < deliveries; i++ {
+			bus.PublishAll(event)
+		}
+		bus.PublishAll(other)
+		stop(t, bus)
+
+		assert.Equal(t, []events.DomainEvent{event, other}, rec.events, "%d deliveries", deliveries)
+	}
+}
+
+func TestWithDedup_ConsumersAreIndependent(t *testing.T) {
+	store := memory.NewDedupStore(memory.NewStore())
+	projection, webhooks := &recorder{}, &recorder{}
+	handleProjection := WithDedup("projection", store, projection.handle)
+	handleWebhooks := WithDedup("webhooks", store, webhooks.handle)
+
+	event := credited()
+	for i := 0; i < 2; i++ {
+		require.NoError(t, handleProjection(context.Background(), event))
+		require.NoError(t, handleWebhooks(context.Background(), event))
+	}
+
+	assert.Equal(t, 1, projection.len())
+	assert.Equal(t, 1, webhooks.len())
+}
+
+func TestWithDedup_FailedHandlerIsRetriedOnRedelivery(t *testing.T) {
+	errHandler := errors.New("handler failed")
+	calls := 0
+	handle := WithDedup("webhooks", memory.NewDedupStore(memory.NewStore()), func(context.Context, events.DomainEvent) error {
+		calls++
+		if calls == 1 {
+			return errHandler
+		}
+		return nil
+	})
+
+	event := credited()
+	require.ErrorIs(t, handle(context.Background(), event), errHandler)
+	require.NoError(t, handle(context.Background(), event))
+	require.NoError(t, handle(context.Background(), event))
+
+	assert.Equal(t, 2, calls, "failure is retried, success is not repeated")
+}
+
+func TestWithDedupInUnitOfWork_ExactlyOnceEffect(t *testing.T) {
+	store := memory.NewStore()
+	outbox := memory.NewEventPublisher(store)
+	errHandler := errors.New("handler failed")
+	fail := true
+
+	// Обработчик пишет в то же хранилище: производное событие в outbox
+	handle := WithDedupInUnitOfWork("notifications", memory.NewDedupStore(store), memory.NewUnitOfWork(store),
+		func(txCtx context.Context, event events.DomainEvent) error {
+			if err := outbox.Publish(txCtx, events.NewWalletSuspended(event.AggregateID(), "derived")); err != nil {
+				return err
+			}
+			if fail {
+				return errHandler
+			}
+			return nil
+		})
+
+	event := credited()
+
+	// Ошибка откатывает и запись обработчика, и отметку
+	require.ErrorIs(t, handle(context.Background(), event), errHandler)
+	assert.Empty(t, outbox.Events())
+
+	fail = false
+	for i := 0; i < 3; i++ {
+		require.NoError(t, handle(context.Background(), event))
+	}
+	assert.Len(t, outbox.Events(), 1)
+}
@@ -0,0 +1,57 @@
+// Package eventbus - режимы доставки domain events.
+package eventbus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// DeliveryMode - способ доставки закоммиченных событий (config messaging.delivery).
+type DeliveryMode string
+
+const (
+	// DeliveryOutbox - события пишутся в outbox в транзакции и доставляются
+	// relay (notifier) асинхронно; шина раздаёт их в фоне. По умолчанию.
+	DeliveryOutbox DeliveryMode = "outbox"
+	// DeliverySync - outbox не используется: закоммиченные события раздаются
+	// подписчикам шины до ответа на запрос (в пределах SyncBudget).
+	// Для тестов и небольших инсталляций без relay.
+	DeliverySync DeliveryMode = "sync"
+	// DeliveryNone - события отбрасываются (эфемерные окружения).
+	DeliveryNone DeliveryMode = "none"
+)
+
+// ParseDeliveryMode разбирает режим; пустая строка - DeliveryOutbox.
+func ParseDeliveryMode(s string) (DeliveryMode, error) {
+	switch mode := DeliveryMode(s); mode {
+	case "":
+		return DeliveryOutbox, nil
+	case DeliveryOutbox, DeliverySync, DeliveryNone:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown event delivery mode %q (want outbox, sync or none)", s)
+	}
+}
+
+// Compile-time check
+var _ ports.EventPublisher = discardPublisher{}
+
+// discardPublisher принимает события и никуда их не сохраняет.
+type discardPublisher struct{}
+
+// DiscardPublisher возвращает EventPublisher без backend'а: в режиме sync
+// события получают только подписчики шины, в режиме none - никто.
+func DiscardPublisher() ports.EventPublisher {
+	return discardPublisher{}
+}
+
+func (discardPublisher) Publish(context.Context, events.DomainEvent) error {
+	return nil
+}
+
+func (discardPublisher) PublishBatch(context.Context, []events.DomainEvent) error {
+	return nil
+}
@@ -0,0 +1,133 @@
+package eventbus
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+func newSyncTestBus(budget time.Duration) *Bus {
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{BufferSize: 16, Sync: true, SyncBudget: budget})
+}
+
+func TestParseDeliveryMode(t *testing.T) {
+	for input, want := range map[string]DeliveryMode{
+		"":       DeliveryOutbox,
+		"outbox": DeliveryOutbox,
+		"sync":   DeliverySync,
+		"none":   DeliveryNone,
+	} {
+		got, err := ParseDeliveryMode(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseDeliveryMode("kafka")
+	assert.Error(t, err)
+}
+
+func TestBus_SyncDelivery(t *testing.T) {
+	bus := newSyncTestBus(time.Second)
+	bus.Start()
+	rec := &recorder{}
+	bus.SubscribeAll("recorder", rec.handle)
+
+	store := memory.NewStore()
+	publisher := WrapEventPublisher(DiscardPublisher(), bus)
+	uow := WrapUnitOfWork(memory.NewUnitOfWork(store), bus)
+
+	err := uow.Execute(context.Background(), func(txCtx context.Context) error {
+		require.NoError(t, publisher.Publish(txCtx, credited()))
+		assert.Zero(t, rec.len(), "nothing is delivered before commit")
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Без Stop и ожидания: обработчик отработал до возврата из Execute
+	assert.Equal(t, 1, rec.len())
+	assert.Empty(t, memory.NewEventPublisher(store).Events(), "sync mode bypasses the outbox")
+
+	stop(t, bus)
+}
+
+func TestBus_SyncBudget(t *testing.T) {
+	const budget = 50 * time.Millisecond
+	bus := newSyncTestBus(budget)
+	bus.Start()
+
+	release := make(chan struct{})
+	slow := bus.SubscribeAll("slow", func(ctx context.Context, _ events.DomainEvent) error {
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	fast := &recorder{}
+	bus.SubscribeAll("fast", fast.handle)
+
+	// Бюджет общий на вызов: второе событие не получает ещё 50ms
+	start := time.Now()
+	bus.PublishAll(credited(), credited())
+	elapsed := time.Since(start)
+
+	assert.GreaterOrEqual(t, elapsed, budget)
+	assert.Less(t, elapsed, budget+200*time.Millisecond, "slow subscriber held the caller past the budget")
+	assert.Equal(t, uint64(1), bus.BudgetExceeded())
+
+	// Оставшиеся события доставляются в фоне через очереди
+	require.Eventually(t, func() bool { return fast.len() == 2 }, time.Second, time.Millisecond)
+
+	close(release)
+	stop(t, bus)
+
+	// Первое событие slow получил с отменённым context, второе - из очереди
+	assert.Equal(t, uint64(2), slow.Delivered())
+	assert.Equal(t, uint64(1), slow.Failed())
+}
+
+func TestDeliveryModes_IdenticalEvents(t *testing.T) {
+	walletID := uuid.New()
+	batch := []events.DomainEvent{credited(), events.NewWalletSuspended(walletID, "fraud review")}
+	single := credited()
+
+	// run проводит одну и ту же транзакцию и возвращает, что получил подписчик
+	run := func(t *testing.T, bus *Bus, backend ports.EventPublisher) []events.DomainEvent {
+		bus.Start()
+		rec := &recorder{}
+		bus.SubscribeAll("recorder", rec.handle)
+
+		publisher := WrapEventPublisher(backend, bus)
+		uow := WrapUnitOfWork(memory.NewUnitOfWork(memory.NewStore()), bus)
+		err := uow.Execute(context.Background(), func(txCtx context.Context) error {
+			if err := publisher.PublishBatch(txCtx, batch); err != nil {
+				return err
+			}
+			return publisher.Publish(txCtx, single)
+		})
+		require.NoError(t, err)
+
+		stop(t, bus)
+		return rec.events
+	}
+
+	outbox := memory.NewEventPublisher(memory.NewStore())
+	viaOutbox := run(t, newTestBus(16), outbox)
+	viaSync := run(t, newSyncTestBus(time.Second), DiscardPublisher())
+
+	want := append(append([]events.DomainEvent(nil), batch...), single)
+	assert.Equal(t, want, viaOutbox)
+	assert.Equal(t, want, viaSync)
+	assert.Equal(t, want, outbox.Events(), "outbox stores the same events subscribers receive")
+}
@@ -0,0 +1,373 @@
+// Package eventbus - журнал (spool) событий, ещё не обработанных подписчиками.
+//
+// После COMMIT события существуют только в памяти процесса, пока их не
+// обработают подписчики шины (webhooks, проекции). В режиме outbox их
+// переживает outbox relay; без relay (messaging.delivery: sync) падение
+// процесса сразу после COMMIT теряет их. Spool закрывает это окно:
+//   - события одного PublishAll записываются в отдельный файл до раздачи
+//   - файл удаляется, когда событие обработали все подписчики
+//   - при старте (Bus.ReplaySpool) оставшиеся файлы раздаются заново;
+//     повтор безопасен для потребителей с dedup (WithDedup)
+//   - запись, которую подписчики не обработали за MaxReplays повторов
+//     (подписчик стабильно падает), удаляется (Dropped)
+//
+// Spool ограничен MaxEntries: при переполнении удаляется самая старая запись
+// (Dropped). Ошибка записи на диск не влияет на доставку - событие
+// раздаётся без журнала (WriteFailed).
+package eventbus
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// DefaultSpoolMaxEntries - сколько незавершённых записей хранит spool по умолчанию.
+const DefaultSpoolMaxEntries = 1024
+
+// DefaultSpoolMaxReplays - сколько раз запись раздаётся после рестартов по умолчанию.
+const DefaultSpoolMaxReplays = 5
+
+// spoolFileExt - расширение файлов записей; временные файлы - *.tmp.
+const spoolFileExt = ".json"
+
+// SpoolConfig - настройки spool.
+type SpoolConfig struct {
+	// Dir - каталог записей (создаётся при открытии).
+	Dir string
+	// MaxEntries - лимит незавершённых записей (0 - DefaultSpoolMaxEntries).
+	MaxEntries int
+	// MaxReplays - сколько раз запись раздаётся после рестартов, прежде чем
+	// её удалить (0 - DefaultSpoolMaxReplays).
+	MaxReplays int
+}
+
+// Spool - ограниченный журнал событий на диске, по файлу на PublishAll.
+type Spool struct {
+	logger     *slog.Logger
+	dir        string
+	maxEntries int
+	maxReplays int
+
+	mu        sync.Mutex
+	nextSeq   uint64
+	entries   []uint64 // Номера незавершённых записей по возрастанию
+	recovered []uint64 // Записи прошлого запуска, ждущие ReplaySpool
+
+	dropped     atomic.Uint64
+	writeFailed atomic.Uint64
+	unsupported atomic.Uint64
+}
+
+// spoolEntry - содержимое файла записи.
+type spoolEntry struct {
+	Events  []spooledEvent `json:"events"`
+	Replays int            `json:"replays,omitempty"` // Сколько раз запись раздавалась после рестарта
+}
+
+// OpenSpool открывает (создаёт) spool. Записи прошлого запуска остаются
+// на диске до ReplaySpool.
+func OpenSpool(logger *slog.Logger, cfg SpoolConfig) (*Spool, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("event spool dir is required")
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = DefaultSpoolMaxEntries
+	}
+	if cfg.MaxReplays <= 0 {
+		cfg.MaxReplays = DefaultSpoolMaxReplays
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create event spool dir: %w", err)
+	}
+
+	files, err := os.ReadDir(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event spool dir: %w", err)
+	}
+
+	s := &Spool{logger: logger, dir: cfg.Dir, maxEntries: cfg.MaxEntries, maxReplays: cfg.MaxReplays, nextSeq: 1}
+	for _, file := range files {
+		name := file.Name()
+		// Недописанный файл: процесс упал до rename, события не раздавались
+		if strings.HasSuffix(name, ".tmp") {
+			_ = os.Remove(filepath.Join(cfg.Dir, name))
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, spoolFileExt), 10, 64)
+		if err != nil || !strings.HasSuffix(name, spoolFileExt) {
+			continue
+		}
+		s.entries = append(s.entries, seq)
+		s.nextSeq = max(s.nextSeq, seq+1)
+	}
+	slices.Sort(s.entries)
+	s.recovered = slices.Clone(s.entries)
+
+	if len(s.recovered) > 0 {
+		logger.Info("Event spool has undelivered entries from the previous run",
+			slog.Int("entries", len(s.recovered)),
+			slog.String("dir", cfg.Dir),
+		)
+	}
+	return s, nil
+}
+
+// Pending - число незавершённых записей (включая ждущие ReplaySpool).
+func (s *Spool) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// Dropped - число записей, вытесненных при переполнении, нечитаемых или
+// не обработанных за MaxReplays повторов.
+func (s *Spool) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// WriteFailed - число PublishAll, события которых не удалось записать.
+func (s *Spool) WriteFailed() uint64 {
+	return s.writeFailed.Load()
+}
+
+// Unsupported - число событий, которые spool не умеет сериализовать
+// (доставлены без журнала).
+func (s *Spool) Unsupported() uint64 {
+	return s.unsupported.Load()
+}
+
+// append записывает события в новую запись. 0 - запись не создана
+// (нет поддерживаемых событий или ошибка диска).
+func (s *Spool) append(evts []events.DomainEvent) uint64 {
+	entry := spoolEntry{Events: make([]spooledEvent, 0, len(evts))}
+	for _, event := range evts {
+		spooled, ok, err := encodeSpooled(event)
+		if err != nil || !ok {
+			if s.unsupported.Add(1) == 1 {
+				s.logger.Warn("Event spool cannot store event, delivering without spool",
+					slog.String("event_type", event.EventType()),
+					slog.Any("error", err),
+				)
+			}
+			continue
+		}
+		entry.Events = append(entry.Events, spooled)
+	}
+	if len(entry.Events) == 0 {
+		return 0
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		s.writeFailure(err)
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seq := s.nextSeq
+	s.nextSeq++
+	if err := s.write(seq, data); err != nil {
+		s.writeFailure(err)
+		return 0
+	}
+	s.entries = append(s.entries, seq)
+
+	for len(s.entries) > s.maxEntries {
+		s.evictOldest()
+	}
+	return seq
+}
+
+// write сохраняет запись атомарно: временный файл, fsync, rename.
+func (s *Spool) write(seq uint64, data []byte) error {
+	tmp, err := os.CreateTemp(s.dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(seq))
+}
+
+func (s *Spool) writeFailure(err error) {
+	if s.writeFailed.Add(1) == 1 {
+		s.logger.Error("Event spool write failed, delivering without spool",
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// evictOldest удаляет самую старую запись; вызывается под s.mu.
+func (s *Spool) evictOldest() {
+	seq := s.entries[0]
+	s.entries = s.entries[1:]
+	s.recovered = slices.DeleteFunc(s.recovered, func(r uint64) bool { return r == seq })
+	_ = os.Remove(s.path(seq))
+
+	// Первое вытеснение логируем, дальше только счётчик
+	if s.dropped.Add(1) == 1 {
+		s.logger.Warn("Event spool full, dropping oldest entries",
+			slog.Int("max_entries", s.maxEntries),
+		)
+	}
+}
+
+// ack удаляет обработанную запись. Вытесненная запись - no-op.
+func (s *Spool) ack(seq uint64) {
+	if seq == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, found := slices.BinarySearch(s.entries, seq)
+	if !found {
+		return
+	}
+	s.entries = slices.Delete(s.entries, i, i+1)
+	if err := os.Remove(s.path(seq)); err != nil && !os.IsNotExist(err) {
+		s.logger.Warn("Failed to remove delivered event spool entry",
+			slog.Uint64("seq", seq),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// takeRecovered возвращает записи прошлого запуска (один раз).
+func (s *Spool) takeRecovered() []uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recovered := s.recovered
+	s.recovered = nil
+	return recovered
+}
+
+// claimReplay читает события записи для повтора и увеличивает счётчик
+// повторов на диске до раздачи: падение во время повтора тоже считается.
+// false - повторы исчерпаны, запись удалена.
+func (s *Spool) claimReplay(seq uint64) ([]events.DomainEvent, bool, error) {
+	data, err := os.ReadFile(s.path(seq))
+	if err != nil {
+		return nil, false, err
+	}
+
+	var entry spoolEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false, err
+	}
+
+	evts := make([]events.DomainEvent, 0, len(entry.Events))
+	for _, spooled := range entry.Events {
+		event, err := decodeSpooled(spooled)
+		if err != nil {
+			return nil, false, err
+		}
+		evts = append(evts, event)
+	}
+
+	if entry.Replays >= s.maxReplays {
+		s.logger.Warn("Dropping event spool entry not delivered after max replays",
+			slog.Uint64("seq", seq),
+			slog.Int("replays", entry.Replays),
+			slog.Int("events", len(evts)),
+		)
+		s.discard(seq)
+		return nil, false, nil
+	}
+
+	entry.Replays++
+	if data, err = json.Marshal(entry); err == nil {
+		s.mu.Lock()
+		err = s.write(seq, data)
+		s.mu.Unlock()
+	}
+	if err != nil {
+		// Запись всё равно раздаётся: без счётчика она повторится при следующем старте
+		s.writeFailure(err)
+	}
+	return evts, true, nil
+}
+
+// discard удаляет нечитаемую запись.
+func (s *Spool) discard(seq uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i, found := slices.BinarySearch(s.entries, seq); found {
+		s.entries = slices.Delete(s.entries, i, i+1)
+		_ = os.Remove(s.path(seq))
+		s.dropped.Add(1)
+	}
+}
+
+func (s *Spool) path(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", seq, spoolFileExt))
+}
+
+// ReplaySpool раздаёт подписчикам события, не обработанные в прошлом
+// запуске, и возвращает число записей. Вызывается один раз, после того как
+// подписались все потребители: события получат только текущие подписчики.
+//
+// Записи остаются в spool, пока их не обработают все подписчики, как и
+// новые, но не дольше MaxReplays рестартов: запись, на которой подписчик
+// стабильно падает, удаляется и считается в Dropped. Подписчики, уже
+// обработавшие событие, получат его повторно - их обработчики должны быть
+// обёрнуты WithDedup.
+func (b *Bus) ReplaySpool() int {
+	if b.spool == nil {
+		return 0
+	}
+
+	recovered := b.spool.takeRecovered()
+	for _, seq := range recovered {
+		evts, ok, err := b.spool.claimReplay(seq)
+		if err != nil {
+			b.logger.Error("Dropping unreadable event spool entry",
+				slog.Uint64("seq", seq),
+				slog.String("error", err.Error()),
+			)
+			b.spool.discard(seq)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		b.PublishAllTracked(func(delivered bool) {
+			if delivered {
+				b.spool.ack(seq)
+			}
+		}, evts...)
+	}
+
+	if len(recovered) > 0 {
+		b.logger.Info("Replayed event spool", slog.Int("entries", len(recovered)))
+	}
+	return len(recovered)
+}
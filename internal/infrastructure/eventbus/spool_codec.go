@@ -0,0 +1,193 @@
+// Package eventbus - сериализация событий для spool.
+package eventbus
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// spoolableEvents - типы событий, которые spool умеет восстановить.
+// События других типов доставляются как обычно, но в журнал не попадают.
+var spoolableEvents = registerSpoolable(
+	&events.UserCreated{},
+	&events.UserKYCApproved{},
+	&events.UserKYCRejected{},
+	&events.UserKYCRevoked{},
+	&events.UserAcceptedTerms{},
+	&events.WalletCreated{},
+	&events.WalletCredited{},
+	&events.WalletDebited{},
+	&events.WalletSuspended{},
+	&events.WalletClosed{},
+	&events.WalletLimitsUpdated{},
+	&events.WalletBalanceSummary{},
+	&events.WalletNoteAdded{},
+	&events.WalletMetadataQuotaWarning{},
+	&events.TransactionCreated{},
+	&events.TransactionCompleted{},
+	&events.TransactionFailed{},
+	&events.TransactionFlagged{},
+	&events.CurrencyExchanged{},
+	&events.SandboxResetRequested{},
+	&events.SandboxResetCompleted{},
+	&events.SuspiciousAuthActivity{},
+	&events.OperationSwitchChanged{},
+	&events.WebhookSubscriptionDisabled{},
+)
+
+var (
+	baseEventType = reflect.TypeOf(events.BaseEvent{})
+	moneyType     = reflect.TypeOf(valueobjects.Money{})
+	currencyType  = reflect.TypeOf(valueobjects.Currency{})
+)
+
+// registerSpoolable индексирует типы событий по имени Go-типа - оно пишется
+// в файл spool как kind и определяет, какую структуру восстановить.
+func registerSpoolable(prototypes ...events.DomainEvent) map[string]reflect.Type {
+	types := make(map[string]reflect.Type, len(prototypes))
+	for _, prototype := range prototypes {
+		t := reflect.TypeOf(prototype).Elem()
+		types[t.Name()] = t
+	}
+	return types
+}
+
+// spooledEvent - событие в файле spool.
+type spooledEvent struct {
+	Kind        string                     `json:"kind"` // Имя Go-типа события
+	ID          uuid.UUID                  `json:"id"`
+	Type        string                     `json:"type"`
+	OccurredAt  time.Time                  `json:"occurred_at"`
+	AggregateID uuid.UUID                  `json:"aggregate_id"`
+	Fields      map[string]json.RawMessage `json:"fields"`
+}
+
+// spooledMoney - Money в файле spool: точная дробь и код валюты.
+type spooledMoney struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// encodeSpooled сериализует событие. ok=false - тип не поддерживается spool.
+//
+// Стандартный json.Marshal теряет приватные поля BaseEvent и Money, поэтому
+// экспортируемые поля события пишутся по одному, а Money и Currency -
+// через их публичные конструкторы.
+func encodeSpooled(event events.DomainEvent) (spooledEvent, bool, error) {
+	v := reflect.ValueOf(event)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return spooledEvent{}, false, nil
+	}
+	v = v.Elem()
+	if spoolableEvents[v.Type().Name()] != v.Type() {
+		return spooledEvent{}, false, nil
+	}
+
+	fields := make(map[string]json.RawMessage, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() || field.Type == baseEventType {
+			continue
+		}
+		raw, err := encodeSpooledField(v.Field(i))
+		if err != nil {
+			return spooledEvent{}, false, fmt.Errorf("%s.%s: %w", v.Type().Name(), field.Name, err)
+		}
+		fields[field.Name] = raw
+	}
+
+	return spooledEvent{
+		Kind:        v.Type().Name(),
+		ID:          event.EventID(),
+		Type:        event.EventType(),
+		OccurredAt:  event.OccurredAt(),
+		AggregateID: event.AggregateID(),
+		Fields:      fields,
+	}, true, nil
+}
+
+func encodeSpooledField(v reflect.Value) (json.RawMessage, error) {
+	switch v.Type() {
+	case moneyType:
+		money := v.Interface().(valueobjects.Money)
+		// Нулевое значение Money (поле не заполнено) - без суммы
+		if money.Currency().Code() == "" {
+			return json.RawMessage("null"), nil
+		}
+		return json.Marshal(spooledMoney{Amount: money.Amount().RatString(), Currency: money.Currency().Code()})
+	case currencyType:
+		return json.Marshal(v.Interface().(valueobjects.Currency).Code())
+	default:
+		return json.Marshal(v.Interface())
+	}
+}
+
+// decodeSpooled восстанавливает событие из файла spool.
+func decodeSpooled(spooled spooledEvent) (events.DomainEvent, error) {
+	t, ok := spoolableEvents[spooled.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown spooled event kind %q", spooled.Kind)
+	}
+
+	ptr := reflect.New(t)
+	v := ptr.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Type == baseEventType {
+			v.Field(i).Set(reflect.ValueOf(events.RestoreBaseEvent(spooled.ID, spooled.Type, spooled.OccurredAt, spooled.AggregateID)))
+			continue
+		}
+		raw, ok := spooled.Fields[field.Name]
+		if !ok {
+			continue
+		}
+		if err := decodeSpooledField(raw, v.Field(i)); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", spooled.Kind, field.Name, err)
+		}
+	}
+
+	return ptr.Interface().(events.DomainEvent), nil
+}
+
+func decodeSpooledField(raw json.RawMessage, v reflect.Value) error {
+	switch v.Type() {
+	case moneyType:
+		var money *spooledMoney
+		if err := json.Unmarshal(raw, &money); err != nil || money == nil {
+			return err
+		}
+		currency, err := valueobjects.NewCurrency(money.Currency)
+		if err != nil {
+			return err
+		}
+		amount, err := valueobjects.NewMoney(money.Amount, currency)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(amount))
+		return nil
+	case currencyType:
+		var code string
+		if err := json.Unmarshal(raw, &code); err != nil || code == "" {
+			return err
+		}
+		currency, err := valueobjects.NewCurrency(code)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(currency))
+		return nil
+	default:
+		return json.Unmarshal(raw, v.Addr().Interface())
+	}
+}
@@ -0,0 +1,269 @@
+package eventbus
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+func openTestSpool(t *testing.T, dir string, maxEntries int) *Spool {
+	t.Helper()
+	spool, err := OpenSpool(slog.New(slog.NewTextHandler(io.Discard, nil)), SpoolConfig{Dir: dir, MaxEntries: maxEntries})
+	require.NoError(t, err)
+	return spool
+}
+
+func newSpooledBus(spool *Spool) *Bus {
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{BufferSize: 16, Spool: spool})
+}
+
+func spoolFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	return files
+}
+
+// TestSpool_CrashMidDeliveryIsReplayedExactlyOnce: процесс "падает", когда
+// потребитель обработал первое событие COMMIT, а второе ещё нет. После
+// рестарта запись раздаётся заново, и dedup потребителя пропускает первое.
+func TestSpool_CrashMidDeliveryIsReplayedExactlyOnce(t *testing.T) {
+	dir := t.TempDir()
+	dedup := memory.NewDedupStore(memory.NewStore())
+	first, second := credited(), credited()
+
+	// Первый запуск: второе событие висит в обработчике до "падения"
+	spool := openTestSpool(t, dir, 0)
+	bus := newSpooledBus(spool)
+	before := &recorder{}
+	inFlight, returned := make(chan struct{}), make(chan struct{})
+	bus.SubscribeAll("webhooks", WithDedup("webhooks", dedup, func(ctx context.Context, event events.DomainEvent) error {
+		if event.EventID() != second.EventID() {
+			return before.handle(ctx, event)
+		}
+		defer close(returned)
+		close(inFlight)
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	bus.Start()
+
+	bus.PublishAll(first, second)
+	<-inFlight
+
+	killCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, bus.Stop(killCtx))
+	<-returned
+	bus.wg.Wait()
+
+	require.Equal(t, 1, before.len())
+	assert.Equal(t, 1, spool.Pending(), "undelivered commit stays in the spool")
+
+	// Рестарт: новый процесс открывает тот же каталог
+	spool = openTestSpool(t, dir, 0)
+	bus = newSpooledBus(spool)
+	after := &recorder{}
+	bus.SubscribeAll("webhooks", WithDedup("webhooks", dedup, after.handle))
+	bus.Start()
+
+	assert.Equal(t, 1, bus.ReplaySpool())
+	assert.Zero(t, bus.ReplaySpool(), "replay runs once")
+	stop(t, bus)
+
+	require.Equal(t, 1, after.len(), "the first event is skipped by dedup")
+	replayed, ok := after.events[0].(*events.WalletCredited)
+	require.True(t, ok, "replayed event keeps its concrete type")
+	assert.Equal(t, second.EventID(), replayed.EventID())
+	assert.Equal(t, second.Amount.String(), replayed.Amount.String())
+	assert.Equal(t, second.BalanceVersion, replayed.BalanceVersion)
+
+	assert.Zero(t, spool.Pending())
+	assert.Empty(t, spoolFiles(t, dir))
+}
+
+func TestSpool_DeliveredEntriesAreRemoved(t *testing.T) {
+	dir := t.TempDir()
+	spool := openTestSpool(t, dir, 0)
+	bus := newSpooledBus(spool)
+	rec := &recorder{}
+	bus.SubscribeAll("projection", rec.handle)
+	bus.Start()
+
+	for i := 0; i < 5; i++ {
+		bus.PublishAll(credited(), credited())
+	}
+	stop(t, bus)
+
+	assert.Equal(t, 10, rec.len())
+	assert.Zero(t, spool.Pending())
+	assert.Empty(t, spoolFiles(t, dir))
+}
+
+func TestSpool_BoundedDropsOldest(t *testing.T) {
+	dir := t.TempDir()
+	spool := openTestSpool(t, dir, 2)
+	bus := newSpooledBus(spool)
+	stop(t, bus)
+
+	// После Stop события не доставляются и остаются в spool
+	published := []events.DomainEvent{credited(), credited(), credited()}
+	for _, event := range published {
+		bus.PublishAll(event)
+	}
+	assert.Equal(t, 2, spool.Pending())
+	assert.Equal(t, uint64(1), spool.Dropped())
+	assert.Len(t, spoolFiles(t, dir), 2)
+
+	spool = openTestSpool(t, dir, 2)
+	bus = newSpooledBus(spool)
+	rec := &recorder{}
+	bus.SubscribeAll("projection", rec.handle)
+	bus.Start()
+	assert.Equal(t, 2, bus.ReplaySpool())
+	stop(t, bus)
+
+	require.Equal(t, 2, rec.len())
+	assert.Equal(t, published[1].EventID(), rec.events[0].EventID())
+	assert.Equal(t, published[2].EventID(), rec.events[1].EventID())
+}
+
+func TestSpool_UnreadableEntryIsDropped(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000007.json"), []byte("{broken"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "123.tmp"), []byte("partial"), 0o600))
+
+	spool := openTestSpool(t, dir, 0)
+	bus := newSpooledBus(spool)
+	bus.Start()
+	assert.Equal(t, 1, bus.ReplaySpool())
+	stop(t, bus)
+
+	assert.Equal(t, uint64(1), spool.Dropped())
+	assert.Empty(t, spoolFiles(t, dir))
+
+	// Номера новых записей продолжают старые
+	assert.Equal(t, uint64(8), spool.append([]events.DomainEvent{credited()}))
+}
+
+// TestSpool_FailingSubscriberDropsAfterMaxReplays: запись, на которой
+// подписчик стабильно падает, раздаётся MaxReplays раз и затем удаляется.
+func TestSpool_FailingSubscriberDropsAfterMaxReplays(t *testing.T) {
+	dir := t.TempDir()
+	open := func() *Spool {
+		spool, err := OpenSpool(slog.New(slog.NewTextHandler(io.Discard, nil)), SpoolConfig{Dir: dir, MaxReplays: 2})
+		require.NoError(t, err)
+		return spool
+	}
+
+	spool := open()
+	bus := newSpooledBus(spool)
+	stop(t, bus)
+	bus.PublishAll(credited())
+	require.Equal(t, 1, spool.Pending())
+
+	var calls int
+	restart := func() *Spool {
+		spool := open()
+		bus := newSpooledBus(spool)
+		bus.SubscribeAll("webhooks", func(context.Context, events.DomainEvent) error {
+			calls++
+			return assert.AnError
+		})
+		bus.Start()
+		bus.ReplaySpool()
+		stop(t, bus)
+		return spool
+	}
+
+	for i := 1; i <= 2; i++ {
+		spool = restart()
+		assert.Equal(t, i, calls)
+		assert.Equal(t, 1, spool.Pending(), "failed replay %d keeps the entry", i)
+		assert.Zero(t, spool.Dropped())
+	}
+
+	spool = restart()
+	assert.Equal(t, 2, calls, "exhausted entry is not delivered again")
+	assert.Zero(t, spool.Pending())
+	assert.Equal(t, uint64(1), spool.Dropped())
+	assert.Empty(t, spoolFiles(t, dir))
+}
+
+func TestSpool_CodecRoundTrip(t *testing.T) {
+	usd := valueobjects.MustNewCurrency("USD")
+	amount, _ := valueobjects.NewMoney("10.125", usd)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	for _, event := range []events.DomainEvent{
+		credited(),
+		events.NewWalletCreated(uuid.New(), uuid.New(), usd),
+		events.NewTransactionCreated(uuid.New(), uuid.New(), uuid.New(), "DEPOSIT", amount, "key-1"),
+		events.NewTransactionFlagged(uuid.New(), uuid.New(), "WITHDRAW", amount, []string{"rule-1", "rule-2"}),
+		events.NewSandboxResetCompleted(uuid.New(), uuid.New(), map[string]int64{"wallets": 3}),
+		events.NewSuspiciousAuthActivity("api-key:abc", 7, now, 5*time.Minute),
+	} {
+		spooled, ok, err := encodeSpooled(event)
+		require.NoError(t, err)
+		require.True(t, ok, event.EventType())
+
+		decoded, err := decodeSpooled(spooled)
+		require.NoError(t, err)
+		assert.IsType(t, event, decoded)
+		assert.Equal(t, event.EventID(), decoded.EventID())
+		assert.Equal(t, event.EventType(), decoded.EventType())
+		assert.Equal(t, event.AggregateID(), decoded.AggregateID())
+		assert.True(t, event.OccurredAt().Equal(decoded.OccurredAt()))
+
+		// Повторная сериализация даёт те же поля - ничего не потеряно
+		again, _, err := encodeSpooled(decoded)
+		require.NoError(t, err)
+		assert.Equal(t, spooled.Fields, again.Fields, event.EventType())
+	}
+
+	// Чужие типы событий spool не сохраняет
+	_, ok, err := encodeSpooled(&genericTestEvent{})
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// genericTestEvent - событие вне реестра spool.
+type genericTestEvent struct {
+	events.BaseEvent
+}
+
+func TestBus_PublishAllTracked(t *testing.T) {
+	bus := newTestBus(8)
+	bus.SubscribeAll("failing", func(_ context.Context, event events.DomainEvent) error {
+		if _, ok := event.(*events.WalletSuspended); ok {
+			return assert.AnError
+		}
+		return nil
+	})
+	bus.Start()
+
+	results := make(chan bool, 3)
+	report := func(delivered bool) { results <- delivered }
+
+	bus.PublishAllTracked(report, credited(), credited())
+	assert.True(t, <-results)
+
+	bus.PublishAllTracked(report, credited(), events.NewWalletSuspended(uuid.New(), "fraud review"))
+	assert.False(t, <-results, "a failed handler keeps the events undelivered")
+
+	stop(t, bus)
+	bus.PublishAllTracked(report, credited())
+	assert.False(t, <-results, "nothing is delivered after Stop")
+}
@@ -0,0 +1,146 @@
+// Package eventbus - декораторы EventPublisher/UnitOfWork, дублирующие события в шину.
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// Compile-time checks
+var _ ports.EventPublisher = (*eventPublisher)(nil)
+var _ ports.UnitOfWork = (*unitOfWork)(nil)
+
+// pendingKey - буфер событий текущей транзакции в context.
+type pendingKey struct{}
+
+// pending - события, опубликованные внутри транзакции и ждущие COMMIT.
+type pending struct {
+	mu     sync.Mutex
+	events []events.DomainEvent
+}
+
+func (p *pending) add(evts ...events.DomainEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, evts...)
+}
+
+// ============================================
+// EventPublisher
+// ============================================
+
+type eventPublisher struct {
+	next ports.EventPublisher
+	bus  *Bus
+}
+
+// WrapEventPublisher дублирует успешно опубликованные события в шину.
+//
+// Внутри UnitOfWork, обёрнутого WrapUnitOfWork, события копятся до COMMIT
+// и при ROLLBACK в шину не попадают. Вне транзакции - раздаются сразу.
+func WrapEventPublisher(next ports.EventPublisher, bus *Bus) ports.EventPublisher {
+	return &eventPublisher{next: next, bus: bus}
+}
+
+func (p *eventPublisher) Publish(ctx context.Context, event events.DomainEvent) error {
+	if err := p.next.Publish(ctx, event); err != nil {
+		return err
+	}
+	p.tee(ctx, event)
+	return nil
+}
+
+func (p *eventPublisher) PublishBatch(ctx context.Context, evts []events.DomainEvent) error {
+	if err := p.next.PublishBatch(ctx, evts); err != nil {
+		// Частично опубликованный batch: в шину - только то, что дошло до backend'а
+		var partial *ports.PartialPublishError
+		if errors.As(err, &partial) {
+			p.tee(ctx, publishedEvents(evts, partial)...)
+		}
+		return err
+	}
+	// В шину - те же события, что сохранил backend (без повторов EventID)
+	p.tee(ctx, ports.DedupeEvents(evts)...)
+	return nil
+}
+
+// publishedEvents отбирает из batch события, перечисленные в partial.Published.
+func publishedEvents(evts []events.DomainEvent, partial *ports.PartialPublishError) []events.DomainEvent {
+	published := make(map[uuid.UUID]struct{}, len(partial.Published))
+	for _, id := range partial.Published {
+		published[id] = struct{}{}
+	}
+
+	var result []events.DomainEvent
+	for _, event := range ports.DedupeEvents(evts) {
+		if _, ok := published[event.EventID()]; ok {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+func (p *eventPublisher) tee(ctx context.Context, evts ...events.DomainEvent) {
+	if buf, ok := ctx.Value(pendingKey{}).(*pending); ok {
+		buf.add(evts...)
+		return
+	}
+	p.bus.PublishAll(evts...)
+}
+
+// ============================================
+// UnitOfWork
+// ============================================
+
+type unitOfWork struct {
+	next ports.UnitOfWork
+	bus  *Bus
+}
+
+// WrapUnitOfWork раздаёт в шину события транзакции после успешного COMMIT.
+//
+// Вложенные Execute используют буфер внешнего - события уходят
+// только после COMMIT самой внешней транзакции.
+func WrapUnitOfWork(uow ports.UnitOfWork, bus *Bus) ports.UnitOfWork {
+	return &unitOfWork{next: uow, bus: bus}
+}
+
+func (u *unitOfWork) Execute(ctx context.Context, fn func(context.Context) error) error {
+	if ctx.Value(pendingKey{}) != nil {
+		return u.next.Execute(ctx, fn)
+	}
+
+	buf := &pending{}
+	err := u.next.Execute(ctx, func(txCtx context.Context) error {
+		return fn(context.WithValue(txCtx, pendingKey{}, buf))
+	})
+	if err != nil {
+		// ROLLBACK - события не состоялись
+		return err
+	}
+
+	// В синхронном режиме - до возврата из Execute, в пределах SyncBudget
+	u.bus.PublishAll(buf.events...)
+	return nil
+}
+
+func (u *unitOfWork) ExecuteWithResult(ctx context.Context, fn func(context.Context) (interface{}, error)) (interface{}, error) {
+	var result interface{}
+
+	err := u.Execute(ctx, func(txCtx context.Context) error {
+		var fnErr error
+		result, fnErr = fn(txCtx)
+		return fnErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
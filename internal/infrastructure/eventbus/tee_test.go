@@ -0,0 +1,115 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+func TestTee(t *testing.T) {
+	setup := func(t *testing.T) (*Bus, *recorder, *memory.EventPublisher, func(context.Context, func(context.Context) error) error, func(context.Context, events.DomainEvent) error) {
+		bus := newTestBus(16)
+		bus.Start()
+		rec := &recorder{}
+		bus.SubscribeAll("recorder", rec.handle)
+
+		store := memory.NewStore()
+		outbox := memory.NewEventPublisher(store)
+		publisher := WrapEventPublisher(outbox, bus)
+		uow := WrapUnitOfWork(memory.NewUnitOfWork(store), bus)
+		return bus, rec, outbox, uow.Execute, publisher.Publish
+	}
+
+	t.Run("DeliversAfterCommit", func(t *testing.T) {
+		bus, rec, outbox, execute, publish := setup(t)
+
+		err := execute(context.Background(), func(txCtx context.Context) error {
+			require.NoError(t, publish(txCtx, credited()))
+			// Вложенная транзакция коммитится вместе с внешней
+			return execute(txCtx, func(nestedCtx context.Context) error {
+				require.NoError(t, publish(nestedCtx, credited()))
+				assert.Zero(t, rec.len(), "nothing is delivered before commit")
+				return nil
+			})
+		})
+		require.NoError(t, err)
+
+		stop(t, bus)
+		assert.Equal(t, 2, rec.len())
+		assert.Len(t, outbox.Events(), 2)
+	})
+
+	t.Run("RollbackDropsEvents", func(t *testing.T) {
+		bus, rec, outbox, execute, publish := setup(t)
+
+		err := execute(context.Background(), func(txCtx context.Context) error {
+			require.NoError(t, publish(txCtx, credited()))
+			return errors.New("insufficient funds")
+		})
+		require.Error(t, err)
+
+		stop(t, bus)
+		assert.Zero(t, rec.len())
+		assert.Empty(t, outbox.Events())
+	})
+
+	t.Run("OutsideTransactionDeliversImmediately", func(t *testing.T) {
+		bus, rec, _, _, publish := setup(t)
+
+		require.NoError(t, publish(context.Background(), credited()))
+
+		stop(t, bus)
+		assert.Equal(t, 1, rec.len())
+	})
+
+	t.Run("BatchDuplicatesDeliveredOnce", func(t *testing.T) {
+		bus := newTestBus(16)
+		bus.Start()
+		rec := &recorder{}
+		bus.SubscribeAll("recorder", rec.handle)
+		publisher := WrapEventPublisher(memory.NewEventPublisher(memory.NewStore()), bus)
+
+		a, b := credited(), credited()
+		require.NoError(t, publisher.PublishBatch(context.Background(), []events.DomainEvent{a, b, a}))
+
+		stop(t, bus)
+		assert.Equal(t, 2, rec.len())
+	})
+
+	t.Run("PartialBatchDeliversPublishedOnly", func(t *testing.T) {
+		bus := newTestBus(16)
+		bus.Start()
+		rec := &recorder{}
+		bus.SubscribeAll("recorder", rec.handle)
+		publisher := WrapEventPublisher(&partialPublisher{failAt: 1}, bus)
+
+		err := publisher.PublishBatch(context.Background(), []events.DomainEvent{credited(), credited(), credited()})
+		require.True(t, ports.IsPartialPublishError(err))
+
+		stop(t, bus)
+		assert.Equal(t, 1, rec.len())
+	})
+}
+
+// partialPublisher - неатомарный backend, падающий на событии failAt.
+type partialPublisher struct {
+	failAt int
+}
+
+func (p *partialPublisher) Publish(ctx context.Context, event events.DomainEvent) error {
+	return p.PublishBatch(ctx, []events.DomainEvent{event})
+}
+
+func (p *partialPublisher) PublishBatch(_ context.Context, evts []events.DomainEvent) error {
+	if p.failAt >= len(evts) {
+		return nil
+	}
+	return ports.NewPartialPublishError(evts, p.failAt, errors.New("broker unavailable"))
+}
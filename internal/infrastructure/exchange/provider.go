@@ -8,8 +8,13 @@ import (
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
 )
 
+// Source is the provider name recorded in rate snapshots.
+const Source = "exchangerate-api.com"
+
 // Provider fetches exchange rates from exchangerate-api.com with in-memory caching.
 type Provider struct {
 	apiKey    string
@@ -22,6 +27,7 @@ type Provider struct {
 
 type cacheEntry struct {
 	rates     map[string]*big.Rat
+	updatedAt time.Time // provider's timestamp of the rates
 	expiresAt time.Time
 }
 
@@ -30,6 +36,7 @@ type apiResponse struct {
 	Result          string             `json:"result"`
 	BaseCode        string             `json:"base_code"`
 	ConversionRates map[string]float64 `json:"conversion_rates"`
+	LastUpdateUnix  int64              `json:"time_last_update_unix"`
 }
 
 // NewProvider creates a new exchange rate provider.
@@ -45,30 +52,39 @@ func NewProvider(apiKey, apiURL string, cacheTTL time.Duration) *Provider {
 
 // GetRate returns the exchange rate from one currency to another.
 func (p *Provider) GetRate(ctx context.Context, from, to string) (*big.Rat, error) {
+	quote, err := p.Quote(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return quote.Rate, nil
+}
+
+// Quote returns the exchange rate with the API's last update time.
+func (p *Provider) Quote(ctx context.Context, from, to string) (*ports.RateQuote, error) {
 	if from == to {
-		return new(big.Rat).SetInt64(1), nil
+		return &ports.RateQuote{Rate: new(big.Rat).SetInt64(1), Source: Source, FetchedAt: time.Now().UTC()}, nil
 	}
 
 	// Check cache first
-	if rate := p.getCached(from, to); rate != nil {
-		return rate, nil
+	if quote := p.getCached(from, to); quote != nil {
+		return quote, nil
 	}
 
 	// Fetch from API
-	rates, err := p.fetchRates(ctx, from)
+	entry, err := p.fetchRates(ctx, from)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
 	}
 
-	rate, ok := rates[to]
+	rate, ok := entry.rates[to]
 	if !ok {
 		return nil, fmt.Errorf("exchange rate not available for %s → %s", from, to)
 	}
 
-	return rate, nil
+	return &ports.RateQuote{Rate: new(big.Rat).Set(rate), Source: Source, FetchedAt: entry.updatedAt}, nil
 }
 
-func (p *Provider) getCached(from, to string) *big.Rat {
+func (p *Provider) getCached(from, to string) *ports.RateQuote {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
@@ -83,10 +99,10 @@ func (p *Provider) getCached(from, to string) *big.Rat {
 	}
 
 	// Return a copy to avoid race conditions
-	return new(big.Rat).Set(rate)
+	return &ports.RateQuote{Rate: new(big.Rat).Set(rate), Source: Source, FetchedAt: entry.updatedAt}
 }
 
-func (p *Provider) fetchRates(ctx context.Context, baseCurrency string) (map[string]*big.Rat, error) {
+func (p *Provider) fetchRates(ctx context.Context, baseCurrency string) (*cacheEntry, error) {
 	url := fmt.Sprintf("%s/%s/latest/%s", p.apiURL, p.apiKey, baseCurrency)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -121,13 +137,21 @@ func (p *Provider) fetchRates(ctx context.Context, baseCurrency string) (map[str
 		rates[currency] = r
 	}
 
+	now := time.Now()
+	updatedAt := now.UTC()
+	if apiResp.LastUpdateUnix > 0 {
+		updatedAt = time.Unix(apiResp.LastUpdateUnix, 0).UTC()
+	}
+
 	// Update cache
-	p.mu.Lock()
-	p.cache[baseCurrency] = &cacheEntry{
+	entry := &cacheEntry{
 		rates:     rates,
-		expiresAt: time.Now().Add(p.cacheTTL),
+		updatedAt: updatedAt,
+		expiresAt: now.Add(p.cacheTTL),
 	}
+	p.mu.Lock()
+	p.cache[baseCurrency] = entry
 	p.mu.Unlock()
 
-	return rates, nil
+	return entry, nil
 }
@@ -0,0 +1,151 @@
+// Package faultinject - декораторы портов с точками внедрения.
+package faultinject
+
+import (
+	"context"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// Compile-time checks
+var _ ports.WalletRepository = (*walletRepository)(nil)
+var _ ports.TransactionRepository = (*transactionRepository)(nil)
+var _ ports.EventPublisher = (*eventPublisher)(nil)
+var _ ports.UnitOfWork = (*unitOfWork)(nil)
+
+// ============================================
+// WalletRepository
+// ============================================
+
+type walletRepository struct {
+	ports.WalletRepository
+	faults FaultInjector
+}
+
+// WrapWalletRepository добавляет точки wallet.save.before/after вокруг Save.
+func WrapWalletRepository(repo ports.WalletRepository, faults FaultInjector) ports.WalletRepository {
+	return &walletRepository{WalletRepository: repo, faults: faults}
+}
+
+func (r *walletRepository) Save(ctx context.Context, wallet *entities.Wallet) error {
+	if err := r.faults.Inject(ctx, PointBeforeWalletSave); err != nil {
+		return err
+	}
+	if err := r.WalletRepository.Save(ctx, wallet); err != nil {
+		return err
+	}
+	return r.faults.Inject(ctx, PointAfterWalletSave)
+}
+
+// ============================================
+// TransactionRepository
+// ============================================
+
+type transactionRepository struct {
+	ports.TransactionRepository
+	faults FaultInjector
+}
+
+// WrapTransactionRepository добавляет точки transaction.save.before/after вокруг Save.
+func WrapTransactionRepository(repo ports.TransactionRepository, faults FaultInjector) ports.TransactionRepository {
+	return &transactionRepository{TransactionRepository: repo, faults: faults}
+}
+
+func (r *transactionRepository) Save(ctx context.Context, tx *entities.Transaction) error {
+	if err := r.faults.Inject(ctx, PointBeforeTransactionSave); err != nil {
+		return err
+	}
+	if err := r.TransactionRepository.Save(ctx, tx); err != nil {
+		return err
+	}
+	return r.faults.Inject(ctx, PointAfterTransactionSave)
+}
+
+// ============================================
+// EventPublisher
+// ============================================
+
+type eventPublisher struct {
+	next   ports.EventPublisher
+	faults FaultInjector
+}
+
+// WrapEventPublisher добавляет точку events.publish.before перед публикацией.
+func WrapEventPublisher(publisher ports.EventPublisher, faults FaultInjector) ports.EventPublisher {
+	return &eventPublisher{next: publisher, faults: faults}
+}
+
+func (p *eventPublisher) Publish(ctx context.Context, event events.DomainEvent) error {
+	if err := p.faults.Inject(ctx, PointBeforeEventPublish); err != nil {
+		return err
+	}
+	return p.next.Publish(ctx, event)
+}
+
+func (p *eventPublisher) PublishBatch(ctx context.Context, evts []events.DomainEvent) error {
+	if err := p.faults.Inject(ctx, PointBeforeEventPublish); err != nil {
+		return err
+	}
+	return p.next.PublishBatch(ctx, evts)
+}
+
+// ============================================
+// UnitOfWork
+// ============================================
+
+// unitOfWorkKey - маркер внешнего Execute в context.
+type unitOfWorkKey struct{}
+
+type unitOfWork struct {
+	next   ports.UnitOfWork
+	faults FaultInjector
+}
+
+// WrapUnitOfWork добавляет точки uow.commit.before/after.
+//
+// uow.commit.before срабатывает после успешного fn, но до COMMIT:
+// ошибка или panic в этой точке приводят к ROLLBACK.
+// uow.commit.after срабатывает после COMMIT: данные уже сохранены,
+// но вызывающий код получает ошибку (имитация падения до ответа клиенту
+// и до post-commit обработки событий).
+//
+// Вложенные Execute точки не проходят - они не коммитят.
+func WrapUnitOfWork(uow ports.UnitOfWork, faults FaultInjector) ports.UnitOfWork {
+	return &unitOfWork{next: uow, faults: faults}
+}
+
+func (u *unitOfWork) Execute(ctx context.Context, fn func(context.Context) error) error {
+	if ctx.Value(unitOfWorkKey{}) != nil {
+		return u.next.Execute(ctx, fn)
+	}
+
+	err := u.next.Execute(ctx, func(txCtx context.Context) error {
+		txCtx = context.WithValue(txCtx, unitOfWorkKey{}, true)
+		if err := fn(txCtx); err != nil {
+			return err
+		}
+		return u.faults.Inject(txCtx, PointBeforeCommit)
+	})
+	if err != nil {
+		return err
+	}
+
+	return u.faults.Inject(ctx, PointAfterCommit)
+}
+
+func (u *unitOfWork) ExecuteWithResult(ctx context.Context, fn func(context.Context) (interface{}, error)) (interface{}, error) {
+	var result interface{}
+
+	err := u.Execute(ctx, func(txCtx context.Context) error {
+		var fnErr error
+		result, fnErr = fn(txCtx)
+		return fnErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
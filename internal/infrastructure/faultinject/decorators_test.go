@@ -0,0 +1,116 @@
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+func newTestWallet(t *testing.T, store *memory.Store) *entities.Wallet {
+	t.Helper()
+
+	user, err := entities.NewUser(uuid.NewString()+"@example.com", "Test User")
+	require.NoError(t, err)
+	require.NoError(t, memory.NewUserRepository(store).Save(context.Background(), user))
+
+	wallet, err := entities.NewWallet(user.ID(), valueobjects.MustNewCurrency("USD"))
+	require.NoError(t, err)
+	return wallet
+}
+
+func TestWrapWalletRepository(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	failure := errors.New("injected")
+
+	t.Run("BeforeSaveSkipsWrite", func(t *testing.T) {
+		faults := NewInjector().FailOn(PointBeforeWalletSave, 1, failure)
+		repo := WrapWalletRepository(memory.NewWalletRepository(store), faults)
+		wallet := newTestWallet(t, store)
+
+		assert.ErrorIs(t, repo.Save(ctx, wallet), failure)
+
+		_, err := repo.FindByID(ctx, wallet.ID())
+		assert.True(t, domainErrors.IsNotFound(err))
+		assert.Equal(t, 0, faults.Calls(PointAfterWalletSave))
+	})
+
+	t.Run("AfterSaveKeepsWrite", func(t *testing.T) {
+		faults := NewInjector().FailOn(PointAfterWalletSave, 1, failure)
+		repo := WrapWalletRepository(memory.NewWalletRepository(store), faults)
+		wallet := newTestWallet(t, store)
+
+		assert.ErrorIs(t, repo.Save(ctx, wallet), failure)
+
+		_, err := repo.FindByID(ctx, wallet.ID())
+		assert.NoError(t, err)
+	})
+}
+
+func TestWrapUnitOfWork(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("injected")
+
+	t.Run("BeforeCommitRollsBack", func(t *testing.T) {
+		store := memory.NewStore()
+		faults := NewInjector().FailOn(PointBeforeCommit, 1, failure)
+		uow := WrapUnitOfWork(memory.NewUnitOfWork(store), faults)
+		wallets := memory.NewWalletRepository(store)
+		wallet := newTestWallet(t, store)
+
+		err := uow.Execute(ctx, func(txCtx context.Context) error {
+			return wallets.Save(txCtx, wallet)
+		})
+		assert.ErrorIs(t, err, failure)
+
+		_, err = wallets.FindByID(ctx, wallet.ID())
+		assert.True(t, domainErrors.IsNotFound(err))
+	})
+
+	t.Run("AfterCommitKeepsChanges", func(t *testing.T) {
+		store := memory.NewStore()
+		faults := NewInjector().FailOn(PointAfterCommit, 1, failure)
+		uow := WrapUnitOfWork(memory.NewUnitOfWork(store), faults)
+		wallets := memory.NewWalletRepository(store)
+		wallet := newTestWallet(t, store)
+
+		err := uow.Execute(ctx, func(txCtx context.Context) error {
+			return wallets.Save(txCtx, wallet)
+		})
+		assert.ErrorIs(t, err, failure)
+
+		_, err = wallets.FindByID(ctx, wallet.ID())
+		assert.NoError(t, err)
+	})
+
+	t.Run("FnErrorSkipsCommitPoints", func(t *testing.T) {
+		faults := NewInjector()
+		uow := WrapUnitOfWork(memory.NewUnitOfWork(memory.NewStore()), faults)
+
+		err := uow.Execute(ctx, func(txCtx context.Context) error { return failure })
+		assert.ErrorIs(t, err, failure)
+		assert.Equal(t, 0, faults.Calls(PointBeforeCommit))
+		assert.Equal(t, 0, faults.Calls(PointAfterCommit))
+	})
+
+	t.Run("NestedExecuteSkipsCommitPoints", func(t *testing.T) {
+		faults := NewInjector()
+		uow := WrapUnitOfWork(memory.NewUnitOfWork(memory.NewStore()), faults)
+
+		err := uow.Execute(ctx, func(txCtx context.Context) error {
+			return uow.Execute(txCtx, func(context.Context) error { return nil })
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, faults.Calls(PointBeforeCommit))
+		assert.Equal(t, 1, faults.Calls(PointAfterCommit))
+	})
+}
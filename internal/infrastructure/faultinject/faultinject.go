@@ -0,0 +1,71 @@
+// Package faultinject - fault injection для тестирования failure paths денежных операций.
+//
+// Моки в unit тестах умеют вернуть ошибку ровно в одном месте, но не
+// позволяют проверить сценарии вида "wallet сохранён, транзакция упала,
+// процесс умер". Этот пакет даёт именованные точки внедрения на швах
+// между use case и инфраструктурой:
+//
+//	wallet.save.before / wallet.save.after
+//	transaction.save.before / transaction.save.after
+//	events.publish.before
+//	uow.commit.before
+//	uow.commit.after (коммит прошёл, post-commit обработка ещё нет)
+//
+// Точки расставлены декораторами над портами (WrapWalletRepository,
+// WrapUnitOfWork и т.д.), поэтому use cases о fault injection не знают.
+// В production декораторы не подключаются вовсе - накладных расходов нет.
+// Контейнер разрешает подключение только вне production окружения
+// (см. container.ContainerBuilder.WithFaultInjector).
+//
+// Пример:
+//
+//	faults := faultinject.NewInjector()
+//	faults.FailOn(faultinject.PointAfterWalletSave, 2, errors.New("crash"))
+//	walletRepo := faultinject.WrapWalletRepository(repo, faults)
+package faultinject
+
+import "context"
+
+// Point - имя точки внедрения.
+type Point string
+
+// Точки внедрения на критических швах денежных операций.
+const (
+	PointBeforeWalletSave      Point = "wallet.save.before"
+	PointAfterWalletSave       Point = "wallet.save.after"
+	PointBeforeTransactionSave Point = "transaction.save.before"
+	PointAfterTransactionSave  Point = "transaction.save.after"
+	PointBeforeEventPublish    Point = "events.publish.before"
+	PointBeforeCommit          Point = "uow.commit.before"
+	PointAfterCommit           Point = "uow.commit.after"
+)
+
+// Points возвращает все точки внедрения в порядке их прохождения операцией.
+func Points() []Point {
+	return []Point{
+		PointBeforeTransactionSave,
+		PointAfterTransactionSave,
+		PointBeforeWalletSave,
+		PointAfterWalletSave,
+		PointBeforeEventPublish,
+		PointBeforeCommit,
+		PointAfterCommit,
+	}
+}
+
+// FaultInjector решает, что произойдёт в точке внедрения.
+//
+// Inject вызывается при каждом прохождении точки. Реализация может
+// вернуть ошибку (операция падает в этом месте), подождать или
+// запаниковать (имитация падения процесса).
+type FaultInjector interface {
+	Inject(ctx context.Context, point Point) error
+}
+
+// Noop - FaultInjector, который ничего не делает.
+type Noop struct{}
+
+// Inject всегда возвращает nil.
+func (Noop) Inject(ctx context.Context, point Point) error {
+	return nil
+}
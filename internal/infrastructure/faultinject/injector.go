@@ -0,0 +1,143 @@
+// Package faultinject - программируемый FaultInjector для тестов.
+package faultinject
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Compile-time check
+var _ FaultInjector = (*Injector)(nil)
+var _ FaultInjector = Noop{}
+
+// Every - значение nth, при котором правило срабатывает на каждом вызове.
+const Every = 0
+
+// actionKind - что делает правило при срабатывании.
+type actionKind int
+
+const (
+	actionFail actionKind = iota
+	actionDelay
+	actionPanic
+)
+
+// rule - запрограммированная реакция на N-й вызов точки.
+type rule struct {
+	nth        int
+	kind       actionKind
+	err        error
+	delay      time.Duration
+	panicValue interface{}
+}
+
+func (r rule) matches(call int) bool {
+	return r.nth == Every || r.nth == call
+}
+
+// Injector - FaultInjector, который тесты программируют по точкам.
+//
+// Вызовы считаются по каждой точке отдельно, начиная с 1. Правило с
+// nth = Every срабатывает на каждом вызове. Если на вызов подходит
+// несколько правил, они применяются в порядке добавления: задержки
+// выполняются, первая ошибка или panic прерывает обработку.
+//
+// Thread-safe.
+type Injector struct {
+	mu    sync.Mutex
+	rules map[Point][]rule
+	calls map[Point]int
+}
+
+// NewInjector создаёт Injector без правил.
+func NewInjector() *Injector {
+	return &Injector{
+		rules: make(map[Point][]rule),
+		calls: make(map[Point]int),
+	}
+}
+
+// FailOn возвращает err на nth-м вызове точки.
+func (i *Injector) FailOn(point Point, nth int, err error) *Injector {
+	return i.add(point, rule{nth: nth, kind: actionFail, err: err})
+}
+
+// DelayOn задерживает nth-й вызов точки на d (или до отмены context).
+func (i *Injector) DelayOn(point Point, nth int, d time.Duration) *Injector {
+	return i.add(point, rule{nth: nth, kind: actionDelay, delay: d})
+}
+
+// PanicOn паникует с value на nth-м вызове точки.
+// Имитирует падение процесса посреди операции.
+func (i *Injector) PanicOn(point Point, nth int, value interface{}) *Injector {
+	return i.add(point, rule{nth: nth, kind: actionPanic, panicValue: value})
+}
+
+// Calls возвращает количество прохождений точки.
+func (i *Injector) Calls(point Point) int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.calls[point]
+}
+
+// Reset удаляет все правила и обнуляет счётчики.
+func (i *Injector) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.rules = make(map[Point][]rule)
+	i.calls = make(map[Point]int)
+}
+
+// Inject применяет правила, подходящие под текущий вызов точки.
+func (i *Injector) Inject(ctx context.Context, point Point) error {
+	i.mu.Lock()
+	i.calls[point]++
+	call := i.calls[point]
+
+	var matched []rule
+	for _, r := range i.rules[point] {
+		if r.matches(call) {
+			matched = append(matched, r)
+		}
+	}
+	i.mu.Unlock()
+
+	for _, r := range matched {
+		switch r.kind {
+		case actionDelay:
+			if err := sleep(ctx, r.delay); err != nil {
+				return err
+			}
+		case actionPanic:
+			panic(r.panicValue)
+		case actionFail:
+			return r.err
+		}
+	}
+
+	return nil
+}
+
+func (i *Injector) add(point Point, r rule) *Injector {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.rules[point] = append(i.rules[point], r)
+	return i
+}
+
+// sleep ждёт d или отмены context.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,79 @@
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoop_Inject(t *testing.T) {
+	for _, point := range Points() {
+		assert.NoError(t, Noop{}.Inject(context.Background(), point))
+	}
+}
+
+func TestInjector_FailOnNthCall(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("injected")
+	faults := NewInjector().FailOn(PointBeforeWalletSave, 2, failure)
+
+	assert.NoError(t, faults.Inject(ctx, PointBeforeWalletSave))
+	assert.ErrorIs(t, faults.Inject(ctx, PointBeforeWalletSave), failure)
+	assert.NoError(t, faults.Inject(ctx, PointBeforeWalletSave))
+
+	// Счётчики независимы по точкам
+	assert.NoError(t, faults.Inject(ctx, PointAfterWalletSave))
+	assert.Equal(t, 3, faults.Calls(PointBeforeWalletSave))
+	assert.Equal(t, 1, faults.Calls(PointAfterWalletSave))
+}
+
+func TestInjector_FailOnEveryCall(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("injected")
+	faults := NewInjector().FailOn(PointBeforeCommit, Every, failure)
+
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, faults.Inject(ctx, PointBeforeCommit), failure)
+	}
+}
+
+func TestInjector_DelayOn(t *testing.T) {
+	faults := NewInjector().DelayOn(PointAfterCommit, 1, 20*time.Millisecond)
+
+	start := time.Now()
+	assert.NoError(t, faults.Inject(context.Background(), PointAfterCommit))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	t.Run("CancelledContext", func(t *testing.T) {
+		faults := NewInjector().DelayOn(PointAfterCommit, Every, time.Hour)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.ErrorIs(t, faults.Inject(ctx, PointAfterCommit), context.Canceled)
+	})
+}
+
+func TestInjector_PanicOn(t *testing.T) {
+	faults := NewInjector().PanicOn(PointAfterTransactionSave, 1, "crash")
+
+	assert.PanicsWithValue(t, "crash", func() {
+		_ = faults.Inject(context.Background(), PointAfterTransactionSave)
+	})
+	assert.NotPanics(t, func() {
+		_ = faults.Inject(context.Background(), PointAfterTransactionSave)
+	})
+}
+
+func TestInjector_Reset(t *testing.T) {
+	ctx := context.Background()
+	faults := NewInjector().FailOn(PointBeforeCommit, Every, errors.New("injected"))
+	assert.Error(t, faults.Inject(ctx, PointBeforeCommit))
+
+	faults.Reset()
+
+	assert.NoError(t, faults.Inject(ctx, PointBeforeCommit))
+	assert.Equal(t, 1, faults.Calls(PointBeforeCommit))
+}
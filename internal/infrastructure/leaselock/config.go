@@ -0,0 +1,39 @@
+package leaselock
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/config"
+)
+
+// New создаёт LeaseLock по locks.backend: nil, nil - backend не задан
+// (блокировки job_locks, outbox relay без выбора лидера). Режим postgres
+// auto определяется по pool. rdb может быть nil, если backend не redis.
+func New(ctx context.Context, cfg config.LocksConfig, pool *pgxpool.Pool, rdb *redis.Client) (ports.LeaseLock, error) {
+	switch cfg.Backend {
+	case "":
+		return nil, nil
+	case "redis":
+		if rdb == nil {
+			return nil, fmt.Errorf("locks.backend redis: Redis is unavailable")
+		}
+		return NewRedis(rdb), nil
+	case "postgres":
+		mode, err := ParsePostgresMode(cfg.PostgresMode)
+		if err != nil {
+			return nil, err
+		}
+		if mode == PostgresModeAuto {
+			if mode, err = DetectPostgresMode(ctx, pool); err != nil {
+				return nil, fmt.Errorf("failed to detect postgres lease mode: %w", err)
+			}
+		}
+		return NewPostgres(pool, mode), nil
+	}
+	return nil, fmt.Errorf("unknown locks.backend %q", cfg.Backend)
+}
@@ -0,0 +1,152 @@
+// Package leaselock - реализации ports.LeaseLock.
+//
+//   - Postgres: advisory lock на выделенном соединении (session) или в
+//     открытой транзакции (xact - для pgBouncer в режиме transaction, где
+//     session-level advisory lock не переживает конец транзакции)
+//   - Redis: SET NX PX с продлением и снятием через Lua по владельцу
+//   - Memory: один процесс (тесты, локальный запуск)
+//
+// Все реализации держат lease одинаково: watchdog продлевает его каждые
+// ttl/3 и закрывает Lost, если продление невозможно - блокировку
+// перехватили, соединение оборвалось или за ttl не удалось достучаться до
+// хранилища (после этого lease мог перейти к другому экземпляру).
+package leaselock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check
+var _ ports.Lease = (*lease)(nil)
+
+// backend - операции хранилища над одним взятым lease. Вызовы
+// сериализованы lease: соединение Postgres нельзя использовать конкурентно.
+type backend interface {
+	// renew продлевает lease; ports.ErrLeaseLost - lease больше не наш.
+	renew(ctx context.Context) error
+	// check подтверждает владение без продления.
+	check(ctx context.Context) error
+	// release отпускает lease; lost - lease уже потерян (освободить только ресурсы).
+	release(ctx context.Context, lost bool) error
+}
+
+// lease - взятый lease с watchdog.
+type lease struct {
+	token int64
+	ttl   time.Duration
+
+	mu      sync.Mutex // сериализует вызовы backend
+	backend backend
+
+	lost     chan struct{}
+	lostOnce sync.Once
+
+	stop        chan struct{}
+	done        chan struct{}
+	releaseOnce sync.Once
+	releaseErr  error
+}
+
+// newLease запускает watchdog взятого lease.
+func newLease(token int64, ttl time.Duration, b backend) *lease {
+	l := &lease{
+		token:   token,
+		ttl:     ttl,
+		backend: b,
+		lost:    make(chan struct{}),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go l.watchdog()
+	return l
+}
+
+// Token возвращает fencing token.
+func (l *lease) Token() int64 { return l.token }
+
+// Lost закрывается при потере lease.
+func (l *lease) Lost() <-chan struct{} { return l.lost }
+
+// Check подтверждает владение lease в хранилище.
+func (l *lease) Check(ctx context.Context) error {
+	if l.isLost() {
+		return ports.ErrLeaseLost
+	}
+
+	l.mu.Lock()
+	err := l.backend.check(ctx)
+	l.mu.Unlock()
+
+	if errors.Is(err, ports.ErrLeaseLost) {
+		l.markLost()
+	}
+	return err
+}
+
+// Release останавливает watchdog и отпускает lease.
+func (l *lease) Release(ctx context.Context) error {
+	l.releaseOnce.Do(func() {
+		close(l.stop)
+		<-l.done
+
+		l.mu.Lock()
+		l.releaseErr = l.backend.release(ctx, l.isLost())
+		l.mu.Unlock()
+	})
+	return l.releaseErr
+}
+
+// watchdog продлевает lease каждые ttl/3, пока он не отпущен или не потерян.
+func (l *lease) watchdog() {
+	defer close(l.done)
+
+	interval := l.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	renewed := time.Now()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		l.mu.Lock()
+		err := l.backend.renew(ctx)
+		l.mu.Unlock()
+		cancel()
+
+		switch {
+		case err == nil:
+			renewed = time.Now()
+		case errors.Is(err, ports.ErrLeaseLost):
+			l.markLost()
+			return
+		case time.Since(renewed) >= l.ttl:
+			// Хранилище недоступно дольше ttl: lease мог истечь и перейти
+			// к другому экземпляру
+			l.markLost()
+			return
+		}
+	}
+}
+
+func (l *lease) markLost() {
+	l.lostOnce.Do(func() { close(l.lost) })
+}
+
+func (l *lease) isLost() bool {
+	select {
+	case <-l.lost:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,103 @@
+package leaselock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check
+var _ ports.LeaseLock = (*Memory)(nil)
+
+// memoryHolder - текущий владелец ключа.
+type memoryHolder struct {
+	token   int64
+	expires time.Time
+}
+
+// Memory - LeaseLock одного процесса. Несколько Memory не видят lease
+// друг друга; экземпляры, разделяющие один Memory, ведут себя как
+// экземпляры приложения над общим хранилищем.
+type Memory struct {
+	mu      sync.Mutex
+	holders map[string]memoryHolder
+	tokens  map[string]int64
+}
+
+// NewMemory создаёт пустой Memory.
+func NewMemory() *Memory {
+	return &Memory{
+		holders: make(map[string]memoryHolder),
+		tokens:  make(map[string]int64),
+	}
+}
+
+// TryAcquire берёт lease, если ключ свободен или lease владельца истёк.
+func (m *Memory) TryAcquire(_ context.Context, key string, ttl time.Duration) (ports.Lease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if holder, held := m.holders[key]; held && holder.expires.After(now) {
+		return nil, nil
+	}
+
+	m.tokens[key]++
+	token := m.tokens[key]
+	m.holders[key] = memoryHolder{token: token, expires: now.Add(ttl)}
+
+	return newLease(token, ttl, &memoryBackend{lock: m, key: key, token: token, ttl: ttl}), nil
+}
+
+// Break отнимает lease ключа у владельца, как истечение lease в хранилище
+// (тесты потери lease).
+func (m *Memory) Break(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.holders, key)
+}
+
+// memoryBackend - lease одного ключа Memory.
+type memoryBackend struct {
+	lock  *Memory
+	key   string
+	token int64
+	ttl   time.Duration
+}
+
+func (b *memoryBackend) renew(ctx context.Context) error {
+	b.lock.mu.Lock()
+	defer b.lock.mu.Unlock()
+
+	holder, held := b.lock.holders[b.key]
+	if !held || holder.token != b.token {
+		return ports.ErrLeaseLost
+	}
+	holder.expires = time.Now().Add(b.ttl)
+	b.lock.holders[b.key] = holder
+	return nil
+}
+
+func (b *memoryBackend) check(ctx context.Context) error {
+	b.lock.mu.Lock()
+	defer b.lock.mu.Unlock()
+
+	holder, held := b.lock.holders[b.key]
+	if !held || holder.token != b.token || !holder.expires.After(time.Now()) {
+		return ports.ErrLeaseLost
+	}
+	return nil
+}
+
+func (b *memoryBackend) release(ctx context.Context, _ bool) error {
+	b.lock.mu.Lock()
+	defer b.lock.mu.Unlock()
+
+	if holder, held := b.lock.holders[b.key]; held && holder.token == b.token {
+		delete(b.lock.holders, b.key)
+	}
+	return nil
+}
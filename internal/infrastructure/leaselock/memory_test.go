@@ -0,0 +1,25 @@
+package leaselock
+
+import (
+	"testing"
+
+	"go.uber.org/goleak"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+// Watchdog каждого lease останавливается Release
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestMemory_Conformance(t *testing.T) {
+	porttest.RunLeaseLockTests(t, func(t *testing.T) porttest.LeaseLockHarness {
+		lock := NewMemory()
+		return porttest.LeaseLockHarness{
+			Lock:  lock,
+			Other: lock,
+			Break: func(_ *testing.T, key string) { lock.Break(key) },
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package leaselock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check
+var _ ports.LeaseLock = (*Postgres)(nil)
+
+// PostgresMode - как Postgres держит advisory lock.
+type PostgresMode string
+
+const (
+	// PostgresModeAuto - режим по DetectPostgresMode.
+	PostgresModeAuto PostgresMode = "auto"
+
+	// PostgresModeSession - pg_try_advisory_lock на выделенном соединении
+	// пула. Требует прямого соединения или pgBouncer в режиме session.
+	PostgresModeSession PostgresMode = "session"
+
+	// PostgresModeXact - pg_try_advisory_xact_lock в открытой транзакции.
+	// Работает через pgBouncer в режиме transaction: открытая транзакция
+	// закрепляет серверное соединение.
+	PostgresModeXact PostgresMode = "xact"
+)
+
+// ParsePostgresMode проверяет значение конфигурации ("" - auto).
+func ParsePostgresMode(value string) (PostgresMode, error) {
+	switch mode := PostgresMode(value); mode {
+	case "":
+		return PostgresModeAuto, nil
+	case PostgresModeAuto, PostgresModeSession, PostgresModeXact:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown postgres lease mode %q (want auto, session or xact)", value)
+}
+
+// DetectPostgresMode определяет режим по пулу: два запроса одного
+// клиентского соединения, выполненные разными серверными процессами,
+// означают pooling в режиме transaction (pgBouncer), иначе - session.
+//
+// Совпадение pid не доказывает режим session: pgBouncer может выдать тот
+// же серверный процесс. Поэтому за pgBouncer режим лучше задать явно.
+func DetectPostgresMode(ctx context.Context, pool *pgxpool.Pool) (PostgresMode, error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	var first int32
+	if err := conn.QueryRow(ctx, "SELECT pg_backend_pid()").Scan(&first); err != nil {
+		return "", fmt.Errorf("failed to read backend pid: %w", err)
+	}
+	for range 3 {
+		var next int32
+		if err := conn.QueryRow(ctx, "SELECT pg_backend_pid()").Scan(&next); err != nil {
+			return "", fmt.Errorf("failed to read backend pid: %w", err)
+		}
+		if next != first {
+			return PostgresModeXact, nil
+		}
+	}
+	return PostgresModeSession, nil
+}
+
+// Postgres - LeaseLock на advisory lock.
+//
+// Advisory lock не истекает: lease держится, пока живо соединение или
+// транзакция, поэтому ttl задаёт только интервал проверки соединения
+// watchdog'ом. Упавший экземпляр теряет lease вместе с соединением.
+// Fencing token - nextval последовательности lease_fencing_tokens.
+type Postgres struct {
+	pool *pgxpool.Pool
+	mode PostgresMode
+}
+
+// NewPostgres создаёт Postgres LeaseLock; mode - session или xact (auto
+// разрешается вызывающим через DetectPostgresMode).
+func NewPostgres(pool *pgxpool.Pool, mode PostgresMode) *Postgres {
+	return &Postgres{pool: pool, mode: mode}
+}
+
+// pgExecutor - соединение или транзакция, держащие lock.
+type pgExecutor interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// TryAcquire берёт advisory lock ключа (hashtextextended) на выделенном
+// соединении или в транзакции.
+func (p *Postgres) TryAcquire(ctx context.Context, key string, ttl time.Duration) (ports.Lease, error) {
+	if p.mode == PostgresModeXact {
+		return p.tryAcquireXact(ctx, key, ttl)
+	}
+	return p.tryAcquireSession(ctx, key, ttl)
+}
+
+func (p *Postgres) tryAcquireSession(ctx context.Context, key string, ttl time.Duration) (ports.Lease, error) {
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("lease: failed to acquire connection for %q: %w", key, err)
+	}
+
+	token, err := acquireAdvisory(ctx, conn, "pg_try_advisory_lock", key)
+	if err != nil || token == 0 {
+		conn.Release()
+		return nil, err
+	}
+
+	b := &pgBackend{db: conn, finish: func(ctx context.Context, lost bool) error {
+		defer conn.Release()
+		if lost {
+			// Состояние сессии неизвестно: соединение не возвращается в пул
+			// с возможно живым lock
+			return conn.Conn().Close(ctx)
+		}
+		if _, err := conn.Exec(ctx, "SELECT pg_advisory_unlock(hashtextextended($1, 0))", key); err != nil {
+			_ = conn.Conn().Close(ctx)
+			return fmt.Errorf("lease: failed to release %q: %w", key, err)
+		}
+		return nil
+	}}
+	return newLease(token, ttl, b), nil
+}
+
+func (p *Postgres) tryAcquireXact(ctx context.Context, key string, ttl time.Duration) (ports.Lease, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("lease: failed to begin transaction for %q: %w", key, err)
+	}
+
+	token, err := acquireAdvisory(ctx, tx, "pg_try_advisory_xact_lock", key)
+	if err != nil || token == 0 {
+		_ = tx.Rollback(ctx)
+		return nil, err
+	}
+
+	// Lock снимается вместе с транзакцией
+	b := &pgBackend{db: tx, finish: func(ctx context.Context, _ bool) error {
+		if err := tx.Rollback(ctx); err != nil && err != pgx.ErrTxClosed {
+			return fmt.Errorf("lease: failed to release %q: %w", key, err)
+		}
+		return nil
+	}}
+	return newLease(token, ttl, b), nil
+}
+
+// acquireAdvisory вызывает tryLock и при успехе выдаёт fencing token (0 - ключ занят).
+func acquireAdvisory(ctx context.Context, db pgExecutor, tryLock, key string) (int64, error) {
+	var locked bool
+	if err := db.QueryRow(ctx, "SELECT "+tryLock+"(hashtextextended($1, 0))", key).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("lease: failed to acquire %q: %w", key, err)
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	var token int64
+	if err := db.QueryRow(ctx, "SELECT nextval('lease_fencing_tokens')").Scan(&token); err != nil {
+		return 0, fmt.Errorf("lease: failed to issue fencing token for %q: %w", key, err)
+	}
+	return token, nil
+}
+
+// pgBackend - advisory lock на соединении или в транзакции.
+type pgBackend struct {
+	db     pgExecutor
+	finish func(ctx context.Context, lost bool) error
+}
+
+// renew проверяет соединение: advisory lock жив, пока жива сессия.
+// Ошибка запроса означает, что соединение (и lock) потеряно - pgx
+// закрывает соединение и при отмене запроса.
+func (b *pgBackend) renew(ctx context.Context) error {
+	if _, err := b.db.Exec(ctx, "SELECT 1"); err != nil {
+		return fmt.Errorf("%w: %v", ports.ErrLeaseLost, err)
+	}
+	return nil
+}
+
+func (b *pgBackend) check(ctx context.Context) error {
+	return b.renew(ctx)
+}
+
+func (b *pgBackend) release(ctx context.Context, lost bool) error {
+	return b.finish(ctx, lost)
+}
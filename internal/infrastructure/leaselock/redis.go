@@ -0,0 +1,117 @@
+package leaselock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check
+var _ ports.LeaseLock = (*Redis)(nil)
+
+// Ключи Redis: lease:<key> - владелец с PX ttl; lease:fence:<key> -
+// счётчик fencing token без истечения (токены не повторяются после
+// истечения lease).
+const (
+	redisLeasePrefix = "lease:"
+	redisFencePrefix = "lease:fence:"
+)
+
+// luaAcquire берёт lease и выдаёт следующий fencing token (0 - ключ занят).
+var luaAcquire = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+// luaExtend продлевает lease, только если его держит владелец.
+var luaExtend = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// luaRelease удаляет lease, только если его держит владелец: истёкший и
+// перехваченный lease не снимается у нового владельца.
+var luaRelease = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Redis - LeaseLock на Redis.
+type Redis struct {
+	rdb *redis.Client
+}
+
+// NewRedis создаёт Redis LeaseLock.
+func NewRedis(rdb *redis.Client) *Redis {
+	return &Redis{rdb: rdb}
+}
+
+// TryAcquire берёт lease атомарно вместе с fencing token.
+func (r *Redis) TryAcquire(ctx context.Context, key string, ttl time.Duration) (ports.Lease, error) {
+	owner := uuid.NewString()
+	keys := []string{redisLeasePrefix + key, redisFencePrefix + key}
+
+	token, err := luaAcquire.Run(ctx, r.rdb, keys, owner, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("lease: failed to acquire %q: %w", key, err)
+	}
+	if token == 0 {
+		return nil, nil
+	}
+
+	return newLease(token, ttl, &redisBackend{rdb: r.rdb, key: keys[0], owner: owner, ttl: ttl}), nil
+}
+
+// redisBackend - lease одного ключа Redis.
+type redisBackend struct {
+	rdb   *redis.Client
+	key   string
+	owner string
+	ttl   time.Duration
+}
+
+func (b *redisBackend) renew(ctx context.Context) error {
+	extended, err := luaExtend.Run(ctx, b.rdb, []string{b.key}, b.owner, b.ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("lease: failed to extend %q: %w", b.key, err)
+	}
+	if extended == 0 {
+		return ports.ErrLeaseLost
+	}
+	return nil
+}
+
+func (b *redisBackend) check(ctx context.Context) error {
+	owner, err := b.rdb.Get(ctx, b.key).Result()
+	if err == redis.Nil {
+		return ports.ErrLeaseLost
+	}
+	if err != nil {
+		return fmt.Errorf("lease: failed to check %q: %w", b.key, err)
+	}
+	if owner != b.owner {
+		return ports.ErrLeaseLost
+	}
+	return nil
+}
+
+func (b *redisBackend) release(ctx context.Context, lost bool) error {
+	if lost {
+		return nil
+	}
+	if err := luaRelease.Run(ctx, b.rdb, []string{b.key}, b.owner).Err(); err != nil && err != redis.Nil {
+		return fmt.Errorf("lease: failed to release %q: %w", b.key, err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"log/slog"
 
 	"github.com/google/uuid"
@@ -16,7 +17,7 @@ import (
 // Handler processes domain events and sends Telegram notifications.
 type Handler struct {
 	telegram   *TelegramSender
-	walletRepo ports.WalletRepository
+	walletRepo ports.WalletReader
 	userRepo   ports.UserRepository
 	logger     *slog.Logger
 }
@@ -24,7 +25,7 @@ type Handler struct {
 // NewHandler creates a new notification handler.
 func NewHandler(
 	telegram *TelegramSender,
-	walletRepo ports.WalletRepository,
+	walletRepo ports.WalletReader,
 	userRepo ports.UserRepository,
 	logger *slog.Logger,
 ) *Handler {
@@ -96,6 +97,7 @@ func (h *Handler) HandleTransactionCompleted(ctx context.Context, msg *natsadapt
 	var payload struct {
 		TransactionID   string `json:"transaction_id"`
 		WalletID        string `json:"wallet_id"`
+		UserID          string `json:"user_id"` // Absent in events published before it was added
 		TransactionType string `json:"transaction_type"`
 		Amount          string `json:"amount"`
 		Currency        string `json:"currency"`
@@ -109,22 +111,27 @@ func (h *Handler) HandleTransactionCompleted(ctx context.Context, msg *natsadapt
 		return nil
 	}
 
-	walletID, err := uuid.Parse(payload.WalletID)
+	userID, err := uuid.Parse(payload.UserID)
 	if err != nil {
-		walletID, err = uuid.Parse(msg.AggregateID)
+		// Old payload without user_id - resolve the owner via the wallet
+		walletID, err := uuid.Parse(payload.WalletID)
 		if err != nil {
-			return fmt.Errorf("invalid wallet ID: %w", err)
+			walletID, err = uuid.Parse(msg.AggregateID)
+			if err != nil {
+				return fmt.Errorf("invalid wallet ID: %w", err)
+			}
 		}
-	}
 
-	wallet, err := h.walletRepo.FindByID(ctx, walletID)
-	if err != nil {
-		return fmt.Errorf("failed to find wallet %s: %w", walletID, err)
+		wallet, err := h.walletRepo.FindByID(ctx, walletID)
+		if err != nil {
+			return fmt.Errorf("failed to find wallet %s: %w", walletID, err)
+		}
+		userID = wallet.UserID()
 	}
 
-	user, err := h.userRepo.FindByID(ctx, wallet.UserID())
+	user, err := h.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("failed to find user %s: %w", wallet.UserID(), err)
+		return fmt.Errorf("failed to find user %s: %w", userID, err)
 	}
 
 	telegramID := user.TelegramID()
@@ -145,3 +152,50 @@ func (h *Handler) HandleTransactionCompleted(ctx context.Context, msg *natsadapt
 
 	return nil
 }
+
+// HandleWebhookSubscriptionDisabled handles webhook.subscription_disabled
+// events — tells the owner that a failing endpoint was disabled.
+func (h *Handler) HandleWebhookSubscriptionDisabled(ctx context.Context, msg *natsadapter.EventMessage) error {
+	var payload struct {
+		SubscriptionID      string `json:"subscription_id"`
+		UserID              string `json:"user_id"`
+		URL                 string `json:"url"`
+		ConsecutiveFailures int    `json:"consecutive_failures"`
+		LastFailureReason   string `json:"last_failure_reason"`
+	}
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal %s payload: %w", events.EventTypeWebhookDisabled, err)
+	}
+
+	userID, err := uuid.Parse(payload.UserID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	user, err := h.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to find user %s: %w", userID, err)
+	}
+
+	telegramID := user.TelegramID()
+	if telegramID == nil {
+		return nil
+	}
+
+	text := fmt.Sprintf(
+		"⚠️ <b>Webhook отключён</b>\n%s\nНеудачных доставок подряд: %d (%s).\nСобытия сохраняются в очереди; включите подписку, когда endpoint заработает.",
+		html.EscapeString(payload.URL), payload.ConsecutiveFailures, html.EscapeString(payload.LastFailureReason),
+	)
+
+	if err := h.telegram.SendMessage(*telegramID, text); err != nil {
+		return fmt.Errorf("failed to send telegram notification: %w", err)
+	}
+
+	h.logger.Info("Notification sent",
+		slog.String("event", events.EventTypeWebhookDisabled),
+		slog.String("subscription_id", payload.SubscriptionID),
+		slog.Int64("telegram_id", *telegramID),
+	)
+
+	return nil
+}
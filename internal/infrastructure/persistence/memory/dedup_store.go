@@ -0,0 +1,74 @@
+// Package memory - DedupStore implementation.
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check
+var _ ports.DedupStore = (*DedupStore)(nil)
+
+// processedEventKey - пара (аналог PRIMARY KEY processed_events).
+type processedEventKey struct {
+	consumer string
+	eventID  uuid.UUID
+}
+
+// DedupStore реализует ports.DedupStore поверх Store.
+// Отметки входят в snapshot UnitOfWork и откатываются вместе с транзакцией.
+type DedupStore struct {
+	store *Store
+}
+
+// NewDedupStore создаёт новый DedupStore.
+func NewDedupStore(store *Store) *DedupStore {
+	return &DedupStore{store: store}
+}
+
+// MarkProcessed записывает пару, если её ещё нет.
+func (s *DedupStore) MarkProcessed(ctx context.Context, consumer string, eventID uuid.UUID) (bool, error) {
+	defer recordQuery(ctx, time.Now())
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	key := processedEventKey{consumer: consumer, eventID: eventID}
+	if _, ok := s.store.processedEvents[key]; ok {
+		return false, nil
+	}
+	s.store.processedEvents[key] = time.Now().UTC()
+	return true, nil
+}
+
+// Unmark удаляет пару.
+func (s *DedupStore) Unmark(ctx context.Context, consumer string, eventID uuid.UUID) error {
+	defer recordQuery(ctx, time.Now())
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	delete(s.store.processedEvents, processedEventKey{consumer: consumer, eventID: eventID})
+	return nil
+}
+
+// DeleteBefore удаляет отметки старше cutoff.
+func (s *DedupStore) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	defer recordQuery(ctx, time.Now())
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	var deleted int64
+	for key, processedAt := range s.store.processedEvents {
+		if processedAt.Before(cutoff) {
+			delete(s.store.processedEvents, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
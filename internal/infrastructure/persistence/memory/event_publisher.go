@@ -0,0 +1,59 @@
+// Package memory - EventPublisher implementation в стиле transactional outbox.
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// Compile-time check
+var _ ports.EventPublisher = (*EventPublisher)(nil)
+
+// EventPublisher реализует ports.EventPublisher, записывая события в Store.
+//
+// Аналог postgres.OutboxRepository: события живут в том же хранилище,
+// что и данные, поэтому откат UnitOfWork удаляет и их.
+type EventPublisher struct {
+	store *Store
+}
+
+// NewEventPublisher создаёт новый EventPublisher.
+func NewEventPublisher(store *Store) *EventPublisher {
+	return &EventPublisher{store: store}
+}
+
+// Publish сохраняет одно событие.
+func (p *EventPublisher) Publish(ctx context.Context, event events.DomainEvent) error {
+	return p.PublishBatch(ctx, []events.DomainEvent{event})
+}
+
+// PublishBatch сохраняет события атомарно в порядке слайса,
+// повторы EventID внутри batch сохраняются один раз.
+func (p *EventPublisher) PublishBatch(ctx context.Context, evts []events.DomainEvent) error {
+	if len(evts) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	unique := ports.DedupeEvents(evts)
+
+	p.store.mu.Lock()
+	p.store.events = append(p.store.events, unique...)
+	p.store.mu.Unlock()
+
+	// Как outbox: один INSERT на batch
+	recordQuery(ctx, start)
+	ports.RequestStatsFromContext(ctx).RecordPublish(len(unique), time.Since(start))
+	return nil
+}
+
+// Events возвращает сохранённые события в порядке публикации.
+func (p *EventPublisher) Events() []events.DomainEvent {
+	p.store.mu.RLock()
+	defer p.store.mu.RUnlock()
+
+	return append([]events.DomainEvent(nil), p.store.events...)
+}
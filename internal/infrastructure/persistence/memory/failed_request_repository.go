@@ -0,0 +1,87 @@
+// Package memory - FailedRequestRepository implementation.
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// Compile-time check
+var _ ports.FailedRequestRepository = (*FailedRequestRepository)(nil)
+
+// FailedRequestRepository реализует ports.FailedRequestRepository поверх Store.
+//
+// FailedRequest неизменяем, поэтому записи хранятся без копирования.
+type FailedRequestRepository struct {
+	store *Store
+}
+
+// NewFailedRequestRepository создаёт новый FailedRequestRepository.
+func NewFailedRequestRepository(store *Store) *FailedRequestRepository {
+	return &FailedRequestRepository{store: store}
+}
+
+// Append добавляет образец.
+func (r *FailedRequestRepository) Append(ctx context.Context, sample *entities.FailedRequest) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.failedRequests = append(r.store.failedRequests, sample)
+	return nil
+}
+
+// List возвращает образцы с фильтрацией и пагинацией, новые первыми.
+func (r *FailedRequestRepository) List(ctx context.Context, filter ports.FailedRequestFilter, offset, limit int) ([]*entities.FailedRequest, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	result := make([]*entities.FailedRequest, 0)
+	for i := len(r.store.failedRequests) - 1; i >= 0; i-- {
+		sample := r.store.failedRequests[i]
+		if filter.ActorID != nil && sample.ActorID() != *filter.ActorID {
+			continue
+		}
+		if filter.Route != nil && sample.Route() != *filter.Route {
+			continue
+		}
+		if filter.OccurredFrom != nil && sample.OccurredAt().Before(*filter.OccurredFrom) {
+			continue
+		}
+		if filter.OccurredTo != nil && !sample.OccurredAt().Before(*filter.OccurredTo) {
+			continue
+		}
+		result = append(result, sample)
+	}
+	r.store.mu.RUnlock()
+
+	// Обход с конца: при равном occurred_at позже добавленные остаются первыми
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].OccurredAt().After(result[j].OccurredAt())
+	})
+	return paginate(result, offset, limit), nil
+}
+
+// DeleteBefore удаляет образцы старше cutoff.
+func (r *FailedRequestRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	kept := make([]*entities.FailedRequest, 0, len(r.store.failedRequests))
+	for _, sample := range r.store.failedRequests {
+		if !sample.OccurredAt().Before(cutoff) {
+			kept = append(kept, sample)
+		}
+	}
+
+	deleted := int64(len(r.store.failedRequests) - len(kept))
+	r.store.failedRequests = kept
+	return deleted, nil
+}
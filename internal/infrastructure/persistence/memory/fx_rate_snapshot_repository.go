@@ -0,0 +1,73 @@
+// Package memory - FXRateSnapshotRepository implementation.
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check
+var _ ports.FXRateSnapshotRepository = (*FXRateSnapshotRepository)(nil)
+
+// FXRateSnapshotRepository реализует ports.FXRateSnapshotRepository поверх Store.
+//
+// FXRateSnapshot неизменяем, поэтому записи хранятся без копирования.
+type FXRateSnapshotRepository struct {
+	store *Store
+}
+
+// NewFXRateSnapshotRepository создаёт новый FXRateSnapshotRepository.
+func NewFXRateSnapshotRepository(store *Store) *FXRateSnapshotRepository {
+	return &FXRateSnapshotRepository{store: store}
+}
+
+// Append сохраняет снимок.
+func (r *FXRateSnapshotRepository) Append(ctx context.Context, snapshot *entities.FXRateSnapshot) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.fxRateSnapshots[snapshot.ID()]; exists {
+		return domainErrors.NewDomainError("FX_RATE_SNAPSHOT_EXISTS", "fx rate snapshot already exists", nil)
+	}
+	r.store.fxRateSnapshots[snapshot.ID()] = snapshot
+	return nil
+}
+
+// FindByID загружает снимок по ID.
+func (r *FXRateSnapshotRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.FXRateSnapshot, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	snapshot, ok := r.store.fxRateSnapshots[id]
+	if !ok {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+	return snapshot, nil
+}
+
+// DeleteBefore удаляет снимки с fetched_at раньше cutoff.
+func (r *FXRateSnapshotRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var deleted int64
+	for id, snapshot := range r.store.fxRateSnapshots {
+		if snapshot.FetchedAt().Before(cutoff) {
+			delete(r.store.fxRateSnapshots, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
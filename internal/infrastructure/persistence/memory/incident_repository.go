@@ -0,0 +1,84 @@
+// Package memory - IncidentRepository implementation.
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check
+var _ ports.IncidentRepository = (*IncidentRepository)(nil)
+
+// IncidentRepository реализует ports.IncidentRepository поверх Store.
+type IncidentRepository struct {
+	store *Store
+}
+
+// NewIncidentRepository создаёт новый IncidentRepository.
+func NewIncidentRepository(store *Store) *IncidentRepository {
+	return &IncidentRepository{store: store}
+}
+
+// Save сохраняет копию инцидента.
+func (r *IncidentRepository) Save(ctx context.Context, incident *entities.Incident) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.incidents[incident.ID()] = cloneIncident(incident)
+	return nil
+}
+
+// FindByID возвращает копию инцидента.
+func (r *IncidentRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.Incident, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	incident, ok := r.store.incidents[id]
+	if !ok {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+
+	return cloneIncident(incident), nil
+}
+
+// ListVisible возвращает активные инциденты и решённые не раньше
+// resolvedSince по started_at DESC.
+func (r *IncidentRepository) ListVisible(ctx context.Context, resolvedSince time.Time) ([]*entities.Incident, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	result := make([]*entities.Incident, 0)
+	for _, incident := range r.store.incidents {
+		if resolvedAt := incident.ResolvedAt(); resolvedAt == nil || !resolvedAt.Before(resolvedSince) {
+			result = append(result, cloneIncident(incident))
+		}
+	}
+	r.store.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].StartedAt().Equal(result[j].StartedAt()) {
+			return result[i].StartedAt().After(result[j].StartedAt())
+		}
+		return result[i].ID().String() < result[j].ID().String()
+	})
+	return result, nil
+}
+
+// cloneIncident возвращает независимую копию инцидента.
+func cloneIncident(i *entities.Incident) *entities.Incident {
+	return entities.ReconstructIncident(
+		i.ID(), i.Title(), i.Severity(), i.Components(), i.StartedAt(), i.ResolvedAt(),
+		i.CreatedBy(), i.ResolvedBy(), i.CreatedAt(),
+	)
+}
@@ -0,0 +1,127 @@
+// Package memory - JobRepository implementation.
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check
+var _ ports.JobRepository = (*JobRepository)(nil)
+
+// jobLock - lease блокировки задачи.
+type jobLock struct {
+	owner       uuid.UUID
+	lockedUntil time.Time
+}
+
+// JobRepository реализует ports.JobRepository поверх Store.
+//
+// Несколько планировщиков над одним Store ведут себя как несколько
+// экземпляров приложения над одной БД.
+type JobRepository struct {
+	store *Store
+}
+
+// NewJobRepository создаёт новый JobRepository.
+func NewJobRepository(store *Store) *JobRepository {
+	return &JobRepository{store: store}
+}
+
+// TryLock берёт блокировку, если она свободна или её lease истёк.
+func (r *JobRepository) TryLock(ctx context.Context, job string, owner uuid.UUID, lease time.Duration) (bool, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now().UTC()
+	if current, held := r.store.jobLocks[job]; held && current.lockedUntil.After(now) {
+		return false, nil
+	}
+
+	r.store.jobLocks[job] = jobLock{owner: owner, lockedUntil: now.Add(lease)}
+	return true, nil
+}
+
+// Unlock снимает блокировку, если её держит owner.
+func (r *JobRepository) Unlock(ctx context.Context, job string, owner uuid.UUID) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if current, held := r.store.jobLocks[job]; held && current.owner == owner {
+		delete(r.store.jobLocks, job)
+	}
+	return nil
+}
+
+// StartRun записывает начало прогона.
+func (r *JobRepository) StartRun(ctx context.Context, run *ports.JobRun) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored := *run
+	stored.Status = ports.JobRunRunning
+	stored.FinishedAt = nil
+	r.store.jobRuns = append(r.store.jobRuns, stored)
+	return nil
+}
+
+// FinishRun записывает итог прогона.
+func (r *JobRepository) FinishRun(ctx context.Context, run *ports.JobRun) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i := range r.store.jobRuns {
+		if r.store.jobRuns[i].ID != run.ID {
+			continue
+		}
+		stored := &r.store.jobRuns[i]
+		stored.Status = run.Status
+		stored.Error = run.Error
+		stored.ItemsProcessed = run.ItemsProcessed
+		if run.FinishedAt != nil {
+			finishedAt := *run.FinishedAt
+			stored.FinishedAt = &finishedAt
+		}
+		return nil
+	}
+	return nil
+}
+
+// ListRuns возвращает последние прогоны задачи, новые первыми.
+func (r *JobRepository) ListRuns(ctx context.Context, job string, limit int) ([]*ports.JobRun, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	result := make([]*ports.JobRun, 0)
+	for i := len(r.store.jobRuns) - 1; i >= 0; i-- {
+		if r.store.jobRuns[i].Job != job {
+			continue
+		}
+		run := r.store.jobRuns[i]
+		if run.FinishedAt != nil {
+			finishedAt := *run.FinishedAt
+			run.FinishedAt = &finishedAt
+		}
+		result = append(result, &run)
+	}
+	r.store.mu.RUnlock()
+
+	// Обход с конца: при равном started_at позже добавленные остаются первыми
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartedAt.After(result[j].StartedAt)
+	})
+	return paginate(result, 0, limit), nil
+}
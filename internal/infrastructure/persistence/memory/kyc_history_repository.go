@@ -0,0 +1,60 @@
+// Package memory - KYCHistoryRepository implementation.
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check
+var _ ports.KYCHistoryRepository = (*KYCHistoryRepository)(nil)
+
+// KYCHistoryRepository реализует ports.KYCHistoryRepository поверх Store.
+//
+// KYCTransition неизменяем, поэтому записи хранятся без копирования.
+type KYCHistoryRepository struct {
+	store *Store
+}
+
+// NewKYCHistoryRepository создаёт новый KYCHistoryRepository.
+func NewKYCHistoryRepository(store *Store) *KYCHistoryRepository {
+	return &KYCHistoryRepository{store: store}
+}
+
+// Append добавляет запись в историю.
+func (r *KYCHistoryRepository) Append(ctx context.Context, transition *entities.KYCTransition) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[transition.UserID()]; !ok {
+		return domainErrors.NewDomainError("USER_NOT_FOUND", "user not found", nil)
+	}
+
+	r.store.kycHistory = append(r.store.kycHistory, transition)
+	return nil
+}
+
+// ListByUserID возвращает историю пользователя в порядке добавления.
+func (r *KYCHistoryRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.KYCTransition, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	result := make([]*entities.KYCTransition, 0)
+	for _, transition := range r.store.kycHistory {
+		if transition.UserID() == userID {
+			result = append(result, transition)
+		}
+	}
+
+	return result, nil
+}
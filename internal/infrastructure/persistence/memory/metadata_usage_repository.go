@@ -0,0 +1,82 @@
+// Package memory - MetadataUsageRepository implementation.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check
+var _ ports.MetadataUsageRepository = (*MetadataUsageRepository)(nil)
+
+// transactionMetadataUsage - учтённый объём metadata транзакции
+// (аналог строки transaction_metadata_usage).
+type transactionMetadataUsage struct {
+	walletID uuid.UUID
+	bytes    int64
+}
+
+// MetadataUsageRepository реализует ports.MetadataUsageRepository поверх Store.
+type MetadataUsageRepository struct {
+	store *Store
+}
+
+// NewMetadataUsageRepository создаёт новый MetadataUsageRepository.
+func NewMetadataUsageRepository(store *Store) *MetadataUsageRepository {
+	return &MetadataUsageRepository{store: store}
+}
+
+// AddTransactionMetadata учитывает metadata транзакции и возвращает новый
+// объём кошелька.
+func (r *MetadataUsageRepository) AddTransactionMetadata(ctx context.Context, walletID, transactionID uuid.UUID, bytes int64) (int64, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.wallets[walletID]; !ok {
+		return 0, domainErrors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", nil)
+	}
+	if _, ok := r.store.transactionMetadata[transactionID]; ok {
+		return 0, fmt.Errorf("metadata of transaction %s is already recorded", transactionID)
+	}
+
+	r.store.transactionMetadata[transactionID] = transactionMetadataUsage{walletID: walletID, bytes: bytes}
+	r.store.metadataUsage[walletID] += bytes
+	return r.store.metadataUsage[walletID], nil
+}
+
+// ReleaseTransactionMetadata удаляет учёт транзакций и вычитает его из
+// счётчиков их кошельков.
+func (r *MetadataUsageRepository) ReleaseTransactionMetadata(ctx context.Context, transactionIDs []uuid.UUID) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, id := range transactionIDs {
+		usage, ok := r.store.transactionMetadata[id]
+		if !ok {
+			continue
+		}
+		delete(r.store.transactionMetadata, id)
+		r.store.metadataUsage[usage.walletID] -= usage.bytes
+	}
+	return nil
+}
+
+// MetadataUsage возвращает объём metadata кошелька (0 без записей).
+func (r *MetadataUsageRepository) MetadataUsage(ctx context.Context, walletID uuid.UUID) (int64, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.metadataUsage[walletID], nil
+}
@@ -0,0 +1,68 @@
+// Package memory - OnboardingRepository implementation.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check
+var _ ports.OnboardingRepository = (*OnboardingRepository)(nil)
+
+// OnboardingRepository реализует ports.OnboardingRepository поверх Store.
+type OnboardingRepository struct {
+	store *Store
+}
+
+// NewOnboardingRepository создаёт новый OnboardingRepository.
+func NewOnboardingRepository(store *Store) *OnboardingRepository {
+	return &OnboardingRepository{store: store}
+}
+
+// Save сохраняет копию записи (аналог INSERT без ON CONFLICT).
+func (r *OnboardingRepository) Save(ctx context.Context, record *ports.OnboardingRecord) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[record.UserID]; !ok {
+		return domainErrors.NewDomainError("USER_NOT_FOUND", "user not found", nil)
+	}
+	if _, ok := r.store.onboardings[record.IdempotencyKey]; ok {
+		return fmt.Errorf("%w: onboarding %s", domainErrors.ErrEntityAlreadyExists, record.IdempotencyKey)
+	}
+
+	r.store.onboardings[record.IdempotencyKey] = cloneOnboardingRecord(record)
+	return nil
+}
+
+// FindByIdempotencyKey возвращает копию записи.
+func (r *OnboardingRepository) FindByIdempotencyKey(ctx context.Context, key uuid.UUID) (*ports.OnboardingRecord, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	record, ok := r.store.onboardings[key]
+	if !ok {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+
+	return cloneOnboardingRecord(record), nil
+}
+
+// cloneOnboardingRecord возвращает независимую копию записи.
+func cloneOnboardingRecord(record *ports.OnboardingRecord) *ports.OnboardingRecord {
+	clone := *record
+	clone.WalletIDs = slices.Clone(record.WalletIDs)
+	clone.TransactionIDs = slices.Clone(record.TransactionIDs)
+	return &clone
+}
@@ -0,0 +1,54 @@
+// Package memory - OperationSwitchRepository implementation.
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// Compile-time check
+var _ ports.OperationSwitchRepository = (*OperationSwitchRepository)(nil)
+
+// OperationSwitchRepository реализует ports.OperationSwitchRepository поверх Store.
+//
+// OperationSwitch неизменяем, поэтому записи хранятся без копирования.
+type OperationSwitchRepository struct {
+	store *Store
+}
+
+// NewOperationSwitchRepository создаёт новый OperationSwitchRepository.
+func NewOperationSwitchRepository(store *Store) *OperationSwitchRepository {
+	return &OperationSwitchRepository{store: store}
+}
+
+// Save сохраняет состояние выключателя.
+func (r *OperationSwitchRepository) Save(ctx context.Context, sw *entities.OperationSwitch) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.switches[sw.Operation()] = sw
+	return nil
+}
+
+// FindAll возвращает сохранённые выключатели по порядку операций.
+func (r *OperationSwitchRepository) FindAll(ctx context.Context) ([]*entities.OperationSwitch, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	result := make([]*entities.OperationSwitch, 0, len(r.store.switches))
+	for _, sw := range r.store.switches {
+		result = append(result, sw)
+	}
+	r.store.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Operation() < result[j].Operation()
+	})
+	return result, nil
+}
@@ -0,0 +1,101 @@
+// Package memory - PayeeRepository implementation.
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// Compile-time check
+var _ ports.PayeeRepository = (*PayeeRepository)(nil)
+
+// payeeKey - пара кошельков (аналог PRIMARY KEY wallet_payees).
+type payeeKey struct {
+	source, destination uuid.UUID
+}
+
+// payeeRecord - первый перевод пары (first_transaction_id, first_transfer_at).
+type payeeRecord struct {
+	transactionID uuid.UUID
+	at            time.Time
+}
+
+// PayeeRepository реализует ports.PayeeRepository поверх Store.
+type PayeeRepository struct {
+	store *Store
+}
+
+// NewPayeeRepository создаёт новый PayeeRepository.
+func NewPayeeRepository(store *Store) *PayeeRepository {
+	return &PayeeRepository{store: store}
+}
+
+// HasPriorCompletedTransfer проверяет, записана ли пара.
+func (r *PayeeRepository) HasPriorCompletedTransfer(ctx context.Context, sourceWalletID, destinationWalletID uuid.UUID) (bool, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	_, ok := r.store.payees[payeeKey{source: sourceWalletID, destination: destinationWalletID}]
+	return ok, nil
+}
+
+// FirstTransferAt возвращает время записи пары.
+func (r *PayeeRepository) FirstTransferAt(ctx context.Context, sourceWalletID, destinationWalletID uuid.UUID) (time.Time, bool, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	record, ok := r.store.payees[payeeKey{source: sourceWalletID, destination: destinationWalletID}]
+	return record.at, ok, nil
+}
+
+// TransferredTo суммирует незавершённые и завершённые переводы пары.
+func (r *PayeeRepository) TransferredTo(ctx context.Context, sourceWalletID, destinationWalletID uuid.UUID, currency valueobjects.Currency) (valueobjects.Money, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	total := valueobjects.Zero(currency)
+	for _, tx := range r.store.transactions {
+		dest := tx.DestinationWalletID()
+		if tx.WalletID() != sourceWalletID || dest == nil || *dest != destinationWalletID ||
+			tx.Type() != entities.TransactionTypeTransfer || tx.Amount().Currency() != currency {
+			continue
+		}
+		switch tx.Status() {
+		case entities.TransactionStatusPending, entities.TransactionStatusProcessing, entities.TransactionStatusCompleted:
+		default:
+			continue
+		}
+		var err error
+		if total, err = total.Add(tx.Amount()); err != nil {
+			return valueobjects.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// RecordPayee записывает пару, если её ещё нет.
+func (r *PayeeRepository) RecordPayee(ctx context.Context, sourceWalletID, destinationWalletID, transactionID uuid.UUID) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := payeeKey{source: sourceWalletID, destination: destinationWalletID}
+	if _, ok := r.store.payees[key]; !ok {
+		r.store.payees[key] = payeeRecord{transactionID: transactionID, at: clock.Now()}
+	}
+	return nil
+}
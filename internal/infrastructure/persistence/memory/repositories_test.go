@@ -0,0 +1,241 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// newRepositories создаёт репозитории над новым пустым Store.
+func newRepositories(t *testing.T) porttest.Repositories {
+	store := NewStore()
+	return porttest.Repositories{
+		Users:        NewUserRepository(store),
+		Wallets:      NewWalletRepository(store),
+		Transactions: NewTransactionRepository(store),
+	}
+}
+
+func TestUserRepository_Conformance(t *testing.T) {
+	porttest.RunUserRepositoryTests(t, newRepositories)
+}
+
+func TestWalletRepository_Conformance(t *testing.T) {
+	porttest.RunWalletRepositoryTests(t, newRepositories)
+}
+
+func TestTransactionRepository_Conformance(t *testing.T) {
+	porttest.RunTransactionRepositoryTests(t, newRepositories)
+}
+
+func TestTransactionEnvironment_Conformance(t *testing.T) {
+	porttest.RunTransactionEnvironmentTests(t, func(t *testing.T) porttest.TransactionEnvironmentHarness {
+		store := NewStore()
+		return porttest.TransactionEnvironmentHarness{
+			Repositories: porttest.Repositories{
+				Users:        NewUserRepository(store),
+				Wallets:      NewWalletRepository(store),
+				Transactions: NewTransactionRepository(store),
+			},
+			Sandbox:    NewTransactionRepository(store).WithEnvironment("sandbox"),
+			Production: NewTransactionRepository(store).WithEnvironment("production"),
+		}
+	})
+}
+
+func TestProcessingRecovery_Conformance(t *testing.T) {
+	porttest.RunProcessingRecoveryTests(t, func(t *testing.T) porttest.ProcessingRecoveryHarness {
+		store := NewStore()
+		return porttest.ProcessingRecoveryHarness{
+			Repositories: porttest.Repositories{
+				Users:        NewUserRepository(store),
+				Wallets:      NewWalletRepository(store),
+				Transactions: NewTransactionRepository(store),
+			},
+			Owner:         NewTransactionRepository(store).WithInstance("owner"),
+			OwnerInstance: "owner",
+			Recovery:      NewTransactionRepository(store),
+			Instances:     NewInstanceRepository(store),
+		}
+	})
+}
+
+func TestTransactionReceipts_Conformance(t *testing.T) {
+	porttest.RunTransactionReceiptTests(t, func(t *testing.T) porttest.TransactionReceiptHarness {
+		store := NewStore()
+		return porttest.TransactionReceiptHarness{
+			Repositories: porttest.Repositories{
+				Users:        NewUserRepository(store),
+				Wallets:      NewWalletRepository(store),
+				Transactions: NewTransactionRepository(store),
+			},
+			Receipts: NewTransactionRepository(store),
+		}
+	})
+}
+
+func TestTransactionActivity_Conformance(t *testing.T) {
+	porttest.RunTransactionActivityTests(t, func(t *testing.T) porttest.TransactionActivityHarness {
+		store := NewStore()
+		return porttest.TransactionActivityHarness{
+			Repositories: porttest.Repositories{
+				Users:        NewUserRepository(store),
+				Wallets:      NewWalletRepository(store),
+				Transactions: NewTransactionRepository(store),
+			},
+			Activity: NewTransactionRepository(store),
+		}
+	})
+}
+
+func TestTransactionBackfillRepository_Conformance(t *testing.T) {
+	porttest.RunTransactionBackfillRepositoryTests(t, newRepositories)
+}
+
+func TestSecurityEventRepository_Conformance(t *testing.T) {
+	porttest.RunSecurityEventRepositoryTests(t, func(t *testing.T) ports.SecurityEventRepository {
+		return NewSecurityEventRepository(NewStore())
+	})
+}
+
+func TestFailedRequestRepository_Conformance(t *testing.T) {
+	porttest.RunFailedRequestRepositoryTests(t, func(t *testing.T) ports.FailedRequestRepository {
+		return NewFailedRequestRepository(NewStore())
+	})
+}
+
+func TestOperationSwitchRepository_Conformance(t *testing.T) {
+	porttest.RunOperationSwitchRepositoryTests(t, func(t *testing.T) ports.OperationSwitchRepository {
+		return NewOperationSwitchRepository(NewStore())
+	})
+}
+
+func TestIncidentRepository_Conformance(t *testing.T) {
+	porttest.RunIncidentRepositoryTests(t, func(t *testing.T) ports.IncidentRepository {
+		return NewIncidentRepository(NewStore())
+	})
+}
+
+func TestFXRateSnapshotRepository_Conformance(t *testing.T) {
+	porttest.RunFXRateSnapshotRepositoryTests(t, func(t *testing.T) porttest.FXRateSnapshotHarness {
+		store := NewStore()
+		return porttest.FXRateSnapshotHarness{
+			Repository: NewFXRateSnapshotRepository(store),
+			UnitOfWork: NewUnitOfWork(store),
+		}
+	})
+}
+
+func TestEventPublisher_Conformance(t *testing.T) {
+	porttest.RunEventPublisherTests(t, func(t *testing.T) porttest.EventPublisherHarness {
+		publisher := NewEventPublisher(NewStore())
+		return porttest.EventPublisherHarness{
+			Publisher: publisher,
+			Delivered: func(t *testing.T) []events.DomainEvent { return publisher.Events() },
+		}
+	})
+}
+
+func TestJobRepository_Conformance(t *testing.T) {
+	porttest.RunJobRepositoryTests(t, func(t *testing.T) ports.JobRepository {
+		return NewJobRepository(NewStore())
+	})
+}
+
+func TestPayeeRepository_Conformance(t *testing.T) {
+	porttest.RunPayeeRepositoryTests(t, func(t *testing.T) porttest.PayeeHarness {
+		store := NewStore()
+		return porttest.PayeeHarness{
+			Repositories: porttest.Repositories{
+				Users:        NewUserRepository(store),
+				Wallets:      NewWalletRepository(store),
+				Transactions: NewTransactionRepository(store),
+			},
+			Payees: NewPayeeRepository(store),
+		}
+	})
+}
+
+func TestWalletNoteRepository_Conformance(t *testing.T) {
+	porttest.RunWalletNoteRepositoryTests(t, func(t *testing.T) porttest.WalletNoteHarness {
+		store := NewStore()
+		return porttest.WalletNoteHarness{
+			Repositories: porttest.Repositories{
+				Users:        NewUserRepository(store),
+				Wallets:      NewWalletRepository(store),
+				Transactions: NewTransactionRepository(store),
+			},
+			Notes: NewWalletNoteRepository(store),
+		}
+	})
+}
+
+func TestWalletSettingsRepository_Conformance(t *testing.T) {
+	porttest.RunWalletSettingsRepositoryTests(t, func(t *testing.T) porttest.WalletSettingsHarness {
+		store := NewStore()
+		return porttest.WalletSettingsHarness{
+			Repositories: porttest.Repositories{
+				Users:        NewUserRepository(store),
+				Wallets:      NewWalletRepository(store),
+				Transactions: NewTransactionRepository(store),
+			},
+			Settings: NewWalletSettingsRepository(store),
+		}
+	})
+}
+
+func TestMetadataUsageRepository_Conformance(t *testing.T) {
+	porttest.RunMetadataUsageRepositoryTests(t, func(t *testing.T) porttest.MetadataUsageHarness {
+		store := NewStore()
+		return porttest.MetadataUsageHarness{
+			Repositories: porttest.Repositories{
+				Users:        NewUserRepository(store),
+				Wallets:      NewWalletRepository(store),
+				Transactions: NewTransactionRepository(store),
+			},
+			Usage:      NewMetadataUsageRepository(store),
+			UnitOfWork: NewUnitOfWork(store),
+		}
+	})
+}
+
+func TestWebhookRepository_Conformance(t *testing.T) {
+	porttest.RunWebhookRepositoryTests(t, func(t *testing.T) porttest.WebhookHarness {
+		store := NewStore()
+		return porttest.WebhookHarness{
+			Repositories: porttest.Repositories{
+				Users:        NewUserRepository(store),
+				Wallets:      NewWalletRepository(store),
+				Transactions: NewTransactionRepository(store),
+			},
+			Subscriptions: NewWebhookSubscriptionRepository(store),
+			Deliveries:    NewWebhookDeliveryRepository(store),
+		}
+	})
+}
+
+func TestDedupStore_Conformance(t *testing.T) {
+	porttest.RunDedupStoreTests(t, func(t *testing.T) porttest.DedupHarness {
+		store := NewStore()
+		return porttest.DedupHarness{
+			Store:      NewDedupStore(store),
+			UnitOfWork: NewUnitOfWork(store),
+		}
+	})
+}
+
+func TestOnboardingRepository_Conformance(t *testing.T) {
+	porttest.RunOnboardingRepositoryTests(t, func(t *testing.T) porttest.OnboardingHarness {
+		store := NewStore()
+		return porttest.OnboardingHarness{
+			Repositories: porttest.Repositories{
+				Users:        NewUserRepository(store),
+				Wallets:      NewWalletRepository(store),
+				Transactions: NewTransactionRepository(store),
+			},
+			Onboardings: NewOnboardingRepository(store),
+		}
+	})
+}
@@ -0,0 +1,19 @@
+// Package memory - учёт запросов в ports.RequestStats и ports.UnitOfWorkGuard.
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// recordQuery учитывает вызов репозитория как один запрос к хранилищу -
+// postgres репозитории выполняют ровно один SQL statement на метод.
+// Вызов мимо активной транзакции запроса отдаётся ports.UnitOfWorkGuard.
+//
+//	defer recordQuery(ctx, time.Now())
+func recordQuery(ctx context.Context, start time.Time) {
+	ports.RequestStatsFromContext(ctx).RecordQuery(time.Since(start))
+	ports.UnitOfWorkGuardFromContext(ctx).CheckQuery(ctx, ctx.Value(txKey{}) != nil)
+}
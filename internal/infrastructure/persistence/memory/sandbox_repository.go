@@ -0,0 +1,117 @@
+// Package memory - SandboxRepository implementation.
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check
+var _ ports.SandboxRepository = (*SandboxRepository)(nil)
+
+// SandboxRepository реализует ports.SandboxRepository поверх Store.
+type SandboxRepository struct {
+	store *Store
+}
+
+// NewSandboxRepository создаёт новый SandboxRepository.
+func NewSandboxRepository(store *Store) *SandboxRepository {
+	return &SandboxRepository{store: store}
+}
+
+// PurgeUserData удаляет транзакции и кошельки пользователя (в этом порядке, как FK в postgres),
+// а также его webhook подписки с доставками.
+func (r *SandboxRepository) PurgeUserData(ctx context.Context, userID uuid.UUID) (ports.SandboxPurgeCounts, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	owned := make(map[uuid.UUID]struct{})
+	for id, wallet := range r.store.wallets {
+		if wallet.UserID() == userID {
+			owned[id] = struct{}{}
+		}
+	}
+
+	// Входящие переводы других пользователей держат "FK" на наши кошельки
+	var foreign int64
+	for _, tx := range r.store.transactions {
+		dest := tx.DestinationWalletID()
+		if dest == nil {
+			continue
+		}
+		_, ownDest := owned[*dest]
+		_, ownSource := owned[tx.WalletID()]
+		if ownDest && !ownSource {
+			foreign++
+		}
+	}
+	if foreign > 0 {
+		return nil, domainErrors.NewBusinessRuleViolation(
+			"CROSS_TENANT_REFERENCES",
+			"transactions of other users reference your wallets",
+			map[string]interface{}{"transactions": foreign},
+		)
+	}
+
+	counts := ports.SandboxPurgeCounts{"transactions": 0, "wallets": 0, "webhook_deliveries": 0, "webhook_subscriptions": 0}
+	for id, tx := range r.store.transactions {
+		if _, ok := owned[tx.WalletID()]; !ok {
+			continue
+		}
+		delete(r.store.transactions, id)
+		counts["transactions"]++
+	}
+	for key, id := range r.store.idempotencyKeys {
+		if _, ok := r.store.transactions[id]; !ok {
+			delete(r.store.idempotencyKeys, key)
+		}
+	}
+	// Заметки поддержки удаляются вместе с кошельком (ON DELETE CASCADE в postgres)
+	for id, note := range r.store.walletNotes {
+		if _, ok := owned[note.WalletID()]; ok {
+			delete(r.store.walletNotes, id)
+		}
+	}
+	// Настройки и представления входящих переводов - так же (ON DELETE CASCADE
+	// по кошельку и транзакции)
+	for id := range owned {
+		delete(r.store.walletSettings, id)
+		delete(r.store.metadataUsage, id)
+	}
+	for id, usage := range r.store.transactionMetadata {
+		if _, ok := owned[usage.walletID]; ok {
+			delete(r.store.transactionMetadata, id)
+		}
+	}
+	for id, view := range r.store.incomingViews {
+		_, ownWallet := owned[view.WalletID()]
+		_, txExists := r.store.transactions[id]
+		if ownWallet || !txExists {
+			delete(r.store.incomingViews, id)
+		}
+	}
+	for id := range owned {
+		delete(r.store.wallets, id)
+		counts["wallets"]++
+	}
+
+	// Webhook подписки - все, включая мягко удалённые
+	for id, subscription := range r.store.webhookSubscriptions {
+		if subscription.UserID() == userID {
+			delete(r.store.webhookSubscriptions, id)
+			counts["webhook_subscriptions"]++
+		}
+	}
+	for id, delivery := range r.store.webhookDeliveries {
+		if _, ok := r.store.webhookSubscriptions[delivery.SubscriptionID()]; !ok {
+			delete(r.store.webhookDeliveries, id)
+			counts["webhook_deliveries"]++
+		}
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,90 @@
+// Package memory - SecurityEventRepository implementation.
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// Compile-time check
+var _ ports.SecurityEventRepository = (*SecurityEventRepository)(nil)
+
+// SecurityEventRepository реализует ports.SecurityEventRepository поверх Store.
+//
+// SecurityEvent неизменяем, поэтому записи хранятся без копирования.
+type SecurityEventRepository struct {
+	store *Store
+}
+
+// NewSecurityEventRepository создаёт новый SecurityEventRepository.
+func NewSecurityEventRepository(store *Store) *SecurityEventRepository {
+	return &SecurityEventRepository{store: store}
+}
+
+// Append добавляет событие в журнал.
+func (r *SecurityEventRepository) Append(ctx context.Context, event *entities.SecurityEvent) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.securityEvents = append(r.store.securityEvents, event)
+	return nil
+}
+
+// List возвращает события с фильтрацией и пагинацией, новые первыми.
+func (r *SecurityEventRepository) List(ctx context.Context, filter ports.SecurityEventFilter, offset, limit int) ([]*entities.SecurityEvent, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	result := make([]*entities.SecurityEvent, 0)
+	for i := len(r.store.securityEvents) - 1; i >= 0; i-- {
+		event := r.store.securityEvents[i]
+		if filter.Principal != nil && event.Principal() != *filter.Principal {
+			continue
+		}
+		if filter.Type != nil && event.Type() != *filter.Type {
+			continue
+		}
+		if filter.IP != nil && event.IP() != *filter.IP {
+			continue
+		}
+		if filter.OccurredFrom != nil && event.OccurredAt().Before(*filter.OccurredFrom) {
+			continue
+		}
+		if filter.OccurredTo != nil && !event.OccurredAt().Before(*filter.OccurredTo) {
+			continue
+		}
+		result = append(result, event)
+	}
+	r.store.mu.RUnlock()
+
+	// Обход с конца: при равном occurred_at позже добавленные остаются первыми
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].OccurredAt().After(result[j].OccurredAt())
+	})
+	return paginate(result, offset, limit), nil
+}
+
+// DeleteBefore удаляет события старше cutoff.
+func (r *SecurityEventRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	kept := make([]*entities.SecurityEvent, 0, len(r.store.securityEvents))
+	for _, event := range r.store.securityEvents {
+		if !event.OccurredAt().Before(cutoff) {
+			kept = append(kept, event)
+		}
+	}
+
+	deleted := int64(len(r.store.securityEvents) - len(kept))
+	r.store.securityEvents = kept
+	return deleted, nil
+}
@@ -0,0 +1,322 @@
+// Package memory - in-memory реализации репозиториев.
+//
+// Используется в unit/conformance тестах и для локальных экспериментов
+// без PostgreSQL. Поведение повторяет postgres адаптер (см. контракт
+// в ports и проверки в ports/porttest): те же типы ошибок, та же
+// сортировка, тот же optimistic locking.
+//
+// Entities хранятся как snapshot'ы: Save копирует entity, Find* отдаёт
+// новую копию, поэтому изменения без Save не попадают в хранилище.
+package memory
+
+import (
+	"encoding/json"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// Store - общее хранилище для всех in-memory репозиториев.
+//
+// Репозитории одного Store видят данные друг друга, что позволяет
+// проверять "foreign keys" (wallet -> user, transaction -> wallet).
+type Store struct {
+	mu sync.RWMutex
+
+	// txMu сериализует UnitOfWork.Execute (см. unit_of_work.go)
+	txMu sync.Mutex
+
+	users        map[uuid.UUID]*entities.User
+	wallets      map[uuid.UUID]*entities.Wallet
+	transactions map[uuid.UUID]*entities.Transaction
+
+	// idempotencyKeys - индекс для unique constraint на (environment, idempotency_key)
+	idempotencyKeys map[idempotencyIndexKey]uuid.UUID
+
+	// kycHistory - append-only история KYC решений в порядке добавления
+	kycHistory []*entities.KYCTransition
+
+	// securityEvents - журнал событий аутентификации в порядке добавления
+	securityEvents []*entities.SecurityEvent
+
+	// events - аналог outbox таблицы (см. event_publisher.go)
+	events []events.DomainEvent
+
+	// payees - пары кошельков с завершённым переводом -> первый перевод
+	payees map[payeeKey]payeeRecord
+
+	// walletNotes - заметки поддержки, включая мягко удалённые
+	walletNotes map[uuid.UUID]*entities.WalletNote
+
+	// walletSettings / incomingViews - настройки представления кошельков и
+	// отрисованные по ним представления входящих переводов
+	walletSettings map[uuid.UUID]*entities.WalletSettings
+	incomingViews  map[uuid.UUID]*entities.IncomingTransferView
+
+	// metadataUsage / transactionMetadata - счётчики объёма metadata по
+	// кошелькам и учтённый объём каждой транзакции (см. metadata_usage_repository.go)
+	metadataUsage       map[uuid.UUID]int64
+	transactionMetadata map[uuid.UUID]transactionMetadataUsage
+
+	// processedEvents - отметки потребителей событий -> processed_at
+	processedEvents map[processedEventKey]time.Time
+
+	// switches - аварийные выключатели операций (аналог system_settings)
+	switches map[entities.TransactionType]*entities.OperationSwitch
+
+	// incidents - отметки инцидентов страницы статуса, включая решённые
+	incidents map[uuid.UUID]*entities.Incident
+
+	// fxRateSnapshots - снимки курсов, использованных операциями
+	fxRateSnapshots map[uuid.UUID]*entities.FXRateSnapshot
+
+	// webhookSubscriptions / webhookDeliveries - webhook подписки, включая
+	// мягко удалённые, и очередь/история их доставок
+	webhookSubscriptions map[uuid.UUID]*entities.WebhookSubscription
+	webhookDeliveries    map[uuid.UUID]*entities.WebhookDelivery
+
+	// onboardings - результаты onboarding по idempotency_key
+	onboardings map[uuid.UUID]*ports.OnboardingRecord
+
+	// jobLocks / jobRuns - блокировки и журнал фоновых задач (см.
+	// job_repository.go). Пишутся вне UnitOfWork и в snapshot не входят.
+	jobLocks map[string]jobLock
+	jobRuns  []ports.JobRun
+
+	// processingOwners / instances - владельцы PROCESSING транзакций и
+	// heartbeat экземпляров (см. transaction_recovery.go). Как и в postgres,
+	// claim пишется вне UnitOfWork, поэтому в snapshot не входят.
+	processingOwners map[uuid.UUID]string
+	instances        map[string]time.Time
+
+	// failedRequests - образцы неудачных денежных операций в порядке
+	// добавления. Пишутся вне UnitOfWork и в snapshot не входят.
+	failedRequests []*entities.FailedRequest
+
+	// receiptSequence - последнее выданное значение последовательности
+	// номеров чеков. Как nextval в postgres, не откатывается вместе с
+	// UnitOfWork и в snapshot не входит.
+	receiptSequence int64
+}
+
+// NewStore создаёт пустое хранилище.
+func NewStore() *Store {
+	return &Store{
+		users:           make(map[uuid.UUID]*entities.User),
+		wallets:         make(map[uuid.UUID]*entities.Wallet),
+		transactions:    make(map[uuid.UUID]*entities.Transaction),
+		idempotencyKeys: make(map[idempotencyIndexKey]uuid.UUID),
+		payees:          make(map[payeeKey]payeeRecord),
+		walletNotes:     make(map[uuid.UUID]*entities.WalletNote),
+		walletSettings:  make(map[uuid.UUID]*entities.WalletSettings),
+		incomingViews:   make(map[uuid.UUID]*entities.IncomingTransferView),
+		processedEvents: make(map[processedEventKey]time.Time),
+		switches:        make(map[entities.TransactionType]*entities.OperationSwitch),
+		incidents:       make(map[uuid.UUID]*entities.Incident),
+		fxRateSnapshots: make(map[uuid.UUID]*entities.FXRateSnapshot),
+		jobLocks:        make(map[string]jobLock),
+
+		processingOwners: make(map[uuid.UUID]string),
+		instances:        make(map[string]time.Time),
+
+		metadataUsage:       make(map[uuid.UUID]int64),
+		transactionMetadata: make(map[uuid.UUID]transactionMetadataUsage),
+
+		webhookSubscriptions: make(map[uuid.UUID]*entities.WebhookSubscription),
+		webhookDeliveries:    make(map[uuid.UUID]*entities.WebhookDelivery),
+
+		onboardings: make(map[uuid.UUID]*ports.OnboardingRecord),
+	}
+}
+
+// ============================================
+// Snapshot helpers
+// ============================================
+
+// storeState - содержимое Store на момент начала UnitOfWork.
+//
+// Entities в Store никогда не изменяются на месте (Save кладёт новую
+// копию), поэтому достаточно скопировать сами map'ы.
+type storeState struct {
+	users           map[uuid.UUID]*entities.User
+	wallets         map[uuid.UUID]*entities.Wallet
+	transactions    map[uuid.UUID]*entities.Transaction
+	idempotencyKeys map[idempotencyIndexKey]uuid.UUID
+	kycHistory      []*entities.KYCTransition
+	securityEvents  []*entities.SecurityEvent
+	events          []events.DomainEvent
+	payees          map[payeeKey]payeeRecord
+	walletNotes     map[uuid.UUID]*entities.WalletNote
+	walletSettings  map[uuid.UUID]*entities.WalletSettings
+	incomingViews   map[uuid.UUID]*entities.IncomingTransferView
+	processedEvents map[processedEventKey]time.Time
+	switches        map[entities.TransactionType]*entities.OperationSwitch
+	incidents       map[uuid.UUID]*entities.Incident
+	fxRateSnapshots map[uuid.UUID]*entities.FXRateSnapshot
+
+	metadataUsage       map[uuid.UUID]int64
+	transactionMetadata map[uuid.UUID]transactionMetadataUsage
+
+	webhookSubscriptions map[uuid.UUID]*entities.WebhookSubscription
+	webhookDeliveries    map[uuid.UUID]*entities.WebhookDelivery
+
+	onboardings map[uuid.UUID]*ports.OnboardingRecord
+}
+
+// snapshot запоминает текущее содержимое хранилища.
+func (s *Store) snapshot() storeState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return storeState{
+		users:           maps.Clone(s.users),
+		wallets:         maps.Clone(s.wallets),
+		transactions:    maps.Clone(s.transactions),
+		idempotencyKeys: maps.Clone(s.idempotencyKeys),
+		kycHistory:      append([]*entities.KYCTransition(nil), s.kycHistory...),
+		securityEvents:  append([]*entities.SecurityEvent(nil), s.securityEvents...),
+		events:          append([]events.DomainEvent(nil), s.events...),
+		payees:          maps.Clone(s.payees),
+		walletNotes:     maps.Clone(s.walletNotes),
+		walletSettings:  maps.Clone(s.walletSettings),
+		incomingViews:   maps.Clone(s.incomingViews),
+		processedEvents: maps.Clone(s.processedEvents),
+		switches:        maps.Clone(s.switches),
+		incidents:       maps.Clone(s.incidents),
+		fxRateSnapshots: maps.Clone(s.fxRateSnapshots),
+
+		metadataUsage:       maps.Clone(s.metadataUsage),
+		transactionMetadata: maps.Clone(s.transactionMetadata),
+
+		webhookSubscriptions: maps.Clone(s.webhookSubscriptions),
+		webhookDeliveries:    maps.Clone(s.webhookDeliveries),
+
+		onboardings: maps.Clone(s.onboardings),
+	}
+}
+
+// restore возвращает хранилище к состоянию snapshot'а.
+func (s *Store) restore(state storeState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = state.users
+	s.wallets = state.wallets
+	s.transactions = state.transactions
+	s.idempotencyKeys = state.idempotencyKeys
+	s.kycHistory = state.kycHistory
+	s.securityEvents = state.securityEvents
+	s.events = state.events
+	s.payees = state.payees
+	s.walletNotes = state.walletNotes
+	s.walletSettings = state.walletSettings
+	s.incomingViews = state.incomingViews
+	s.processedEvents = state.processedEvents
+	s.switches = state.switches
+	s.incidents = state.incidents
+	s.fxRateSnapshots = state.fxRateSnapshots
+	s.metadataUsage = state.metadataUsage
+	s.transactionMetadata = state.transactionMetadata
+	s.webhookSubscriptions = state.webhookSubscriptions
+	s.webhookDeliveries = state.webhookDeliveries
+	s.onboardings = state.onboardings
+}
+
+// cloneUser возвращает независимую копию пользователя.
+func cloneUser(u *entities.User) *entities.User {
+	var telegramID *int64
+	if id := u.TelegramID(); id != nil {
+		v := *id
+		telegramID = &v
+	}
+
+	return entities.ReconstructUser(
+		u.ID(), u.Email(), u.FullName(), u.KYCStatus(), telegramID, u.Jurisdiction(), u.LastKYCRejectionReason(),
+		u.TermsAcceptance(), u.CreatedAt(), u.UpdatedAt(),
+	)
+}
+
+// cloneWallet возвращает независимую копию кошелька.
+func cloneWallet(w *entities.Wallet) *entities.Wallet {
+	return entities.ReconstructWallet(
+		w.ID(),
+		w.UserID(),
+		w.Currency(),
+		w.WalletType(),
+		w.Status(),
+		w.AvailableBalance(),
+		w.PendingBalance(),
+		w.BalanceVersion(),
+		w.DailyLimit(),
+		w.MonthlyLimit(),
+		w.Jurisdiction(),
+		w.MaxPendingTransactions(),
+		w.CreatedAt(),
+		w.UpdatedAt(),
+	)
+}
+
+// cloneTransaction возвращает независимую копию транзакции.
+// Metadata копируется через JSON - так же, как она проходит через JSONB в postgres.
+func cloneTransaction(t *entities.Transaction) (*entities.Transaction, error) {
+	metadataJSON, err := json.Marshal(t.Metadata())
+	if err != nil {
+		return nil, err
+	}
+
+	var destinationWalletID *uuid.UUID
+	if id := t.DestinationWalletID(); id != nil {
+		v := *id
+		destinationWalletID = &v
+	}
+
+	return entities.ReconstructTransaction(
+		t.ID(),
+		t.WalletID(),
+		t.IdempotencyKey(),
+		t.Type(),
+		t.Status(),
+		t.Amount(),
+		t.FeeAmount(),
+		t.NetAmount(),
+		destinationWalletID,
+		t.ExternalReference(),
+		t.ExternalReferenceHash(),
+		t.Description(),
+		metadataJSON,
+		t.FailureReason(),
+		t.FailureCategory(),
+		t.RetryCount(),
+		t.NextRetryAt(),
+		t.Jurisdiction(),
+		t.CreatedByVersion(),
+		t.CreatedAt(),
+		t.UpdatedAt(),
+		t.ProcessedAt(),
+		t.CompletedAt(),
+		t.ReceiptNumber(),
+	)
+}
+
+// paginate применяет offset/limit к уже отсортированному срезу.
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return nil
+	}
+
+	end := len(items)
+	if limit >= 0 && offset+limit < end {
+		end = offset + limit
+	}
+
+	return items[offset:end]
+}
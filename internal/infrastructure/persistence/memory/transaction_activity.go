@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// Compile-time check
+var _ ports.TransactionActivityRepository = (*TransactionRepository)(nil)
+
+// activityKey - день и валюта агрегата.
+type activityKey struct {
+	date     time.Time
+	currency string
+}
+
+// DailyActivity агрегирует COMPLETED транзакции кошельков по дням в зоне
+// filter.Location (аналог GROUP BY в postgres).
+func (r *TransactionRepository) DailyActivity(ctx context.Context, filter ports.ActivityFilter) ([]ports.DailyActivity, error) {
+	defer recordQuery(ctx, time.Now())
+
+	wallets := make(map[uuid.UUID]bool, len(filter.WalletIDs))
+	for _, id := range filter.WalletIDs {
+		wallets[id] = true
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	days := make(map[activityKey]*ports.DailyActivity)
+	for _, tx := range r.store.transactions {
+		createdAt := tx.CreatedAt()
+		if tx.Status() != entities.TransactionStatusCompleted ||
+			createdAt.Before(filter.From) || !createdAt.Before(filter.To) {
+			continue
+		}
+		outgoing := wallets[tx.WalletID()]
+		incoming := tx.Type() == entities.TransactionTypeTransfer &&
+			tx.DestinationWalletID() != nil && wallets[*tx.DestinationWalletID()]
+		if !outgoing && !incoming {
+			continue
+		}
+
+		local := createdAt.In(filter.Location)
+		key := activityKey{
+			date:     time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC),
+			currency: tx.Amount().Currency().Code(),
+		}
+		day, ok := days[key]
+		if !ok {
+			zero := valueobjects.Zero(tx.Amount().Currency())
+			day = &ports.DailyActivity{Date: key.date, CurrencyCode: key.currency, Credit: zero, Debit: zero}
+			days[key] = day
+		}
+
+		day.Count++
+		var err error
+		if outgoing && tx.Type().IsKnown() {
+			if tx.Type().IsCredit() {
+				day.Credit, err = day.Credit.Add(tx.Amount())
+			} else {
+				day.Debit, err = day.Debit.Add(tx.Amount())
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		if incoming {
+			if day.Credit, err = day.Credit.Add(tx.NetAmount()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result := make([]ports.DailyActivity, 0, len(days))
+	for _, day := range days {
+		result = append(result, *day)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Date.Equal(result[j].Date) {
+			return result[i].Date.Before(result[j].Date)
+		}
+		return result[i].CurrencyCode < result[j].CurrencyCode
+	})
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check
+var _ ports.TransactionBackfillRepository = (*TransactionRepository)(nil)
+
+// ExistingIDs возвращает ID из ids, уже сохранённые в Store.
+func (r *TransactionRepository) ExistingIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]struct{}, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	existing := make(map[uuid.UUID]struct{})
+	for _, id := range ids {
+		if _, ok := r.store.transactions[id]; ok {
+			existing[id] = struct{}{}
+		}
+	}
+	return existing, nil
+}
+
+// InsertBatch записывает пачку целиком или ничего: все проверки до записи.
+func (r *TransactionRepository) InsertBatch(ctx context.Context, transactions []*entities.Transaction) error {
+	defer recordQuery(ctx, time.Now())
+
+	snapshots := make([]*entities.Transaction, 0, len(transactions))
+	for _, tx := range transactions {
+		snapshot, err := cloneTransaction(tx)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	ids := make(map[uuid.UUID]struct{}, len(snapshots))
+	keys := make(map[string]struct{}, len(snapshots))
+	// Перенесённые транзакции не привязаны к окружению (как COPY в postgres)
+	for _, tx := range snapshots {
+		if _, ok := r.store.transactions[tx.ID()]; ok {
+			return domainErrors.ErrDuplicateTransaction
+		}
+		if _, ok := ids[tx.ID()]; ok {
+			return domainErrors.ErrDuplicateTransaction
+		}
+		if _, ok := r.store.idempotencyKeys[idempotencyIndexKey{key: tx.IdempotencyKey()}]; ok {
+			return domainErrors.ErrDuplicateTransaction
+		}
+		if _, ok := keys[tx.IdempotencyKey()]; ok {
+			return domainErrors.ErrDuplicateTransaction
+		}
+		ids[tx.ID()] = struct{}{}
+		keys[tx.IdempotencyKey()] = struct{}{}
+
+		if _, ok := r.store.wallets[tx.WalletID()]; !ok {
+			return domainErrors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", nil)
+		}
+		if dest := tx.DestinationWalletID(); dest != nil {
+			if _, ok := r.store.wallets[*dest]; !ok {
+				return domainErrors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", nil)
+			}
+		}
+	}
+
+	for _, tx := range snapshots {
+		r.store.transactions[tx.ID()] = tx
+		r.store.idempotencyKeys[idempotencyIndexKey{key: tx.IdempotencyKey()}] = tx.ID()
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// Compile-time check
+var _ ports.TransactionReceiptRepository = (*TransactionRepository)(nil)
+
+// FindByReceiptNumber загружает транзакцию по номеру чека.
+func (r *TransactionRepository) FindByReceiptNumber(ctx context.Context, number string) (*entities.Transaction, error) {
+	defer recordQuery(ctx, time.Now())
+
+	transactions, err := r.filter(func(tx *entities.Transaction) bool {
+		return tx.ReceiptNumber() == number
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(transactions) == 0 {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+	return transactions[0], nil
+}
+
+// assignReceiptNumber выдаёт номер чека COMPLETED транзакции без номера
+// (аналог nextval(transaction_receipt_seq) в postgres). Номер, уже
+// сохранённый в Store, не меняется. Вызывается под store.mu.
+func (r *TransactionRepository) assignReceiptNumber(tx *entities.Transaction) error {
+	if tx.Status() != entities.TransactionStatusCompleted || tx.ReceiptNumber() != "" {
+		return nil
+	}
+	if stored, ok := r.store.transactions[tx.ID()]; ok && stored.ReceiptNumber() != "" {
+		return tx.AssignReceiptNumber(stored.ReceiptNumber())
+	}
+
+	r.store.receiptSequence++
+	number, err := valueobjects.NewReceiptNumber(r.store.receiptSequence)
+	if err != nil {
+		return fmt.Errorf("failed to format receipt number: %w", err)
+	}
+	return tx.AssignReceiptNumber(number)
+}
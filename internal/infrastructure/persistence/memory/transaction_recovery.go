@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// Compile-time checks
+var (
+	_ ports.ProcessingRecoveryRepository = (*TransactionRepository)(nil)
+	_ ports.InstanceRepository           = (*InstanceRepository)(nil)
+)
+
+// ClaimOrphaned переназначает instance транзакции PROCESSING упавших
+// экземпляров. Выбор и переназначение выполняются под одной блокировкой
+// Store, поэтому конкурирующие вызовы не получают одну транзакцию дважды.
+func (r *TransactionRepository) ClaimOrphaned(ctx context.Context, instance string, startedBefore, aliveSince time.Time, limit int) ([]uuid.UUID, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	orphaned := make([]*entities.Transaction, 0)
+	for id, tx := range r.store.transactions {
+		if tx.Status() != entities.TransactionStatusProcessing || !processingStartedAt(tx).Before(startedBefore) {
+			continue
+		}
+		if heartbeat, ok := r.store.instances[r.store.processingOwners[id]]; ok && !heartbeat.Before(aliveSince) {
+			continue
+		}
+		orphaned = append(orphaned, tx)
+	}
+
+	sort.Slice(orphaned, func(i, j int) bool {
+		return processingStartedAt(orphaned[i]).Before(processingStartedAt(orphaned[j]))
+	})
+	if limit >= 0 && len(orphaned) > limit {
+		orphaned = orphaned[:limit]
+	}
+
+	ids := make([]uuid.UUID, 0, len(orphaned))
+	for _, tx := range orphaned {
+		r.store.processingOwners[tx.ID()] = instance
+		ids = append(ids, tx.ID())
+	}
+	return ids, nil
+}
+
+// Release снимает владельца с транзакции, всё ещё PROCESSING у instance.
+func (r *TransactionRepository) Release(ctx context.Context, id uuid.UUID, instance string) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	tx, ok := r.store.transactions[id]
+	if ok && tx.Status() == entities.TransactionStatusProcessing && r.store.processingOwners[id] == instance {
+		delete(r.store.processingOwners, id)
+	}
+	return nil
+}
+
+// processingStartedAt - начало обработки (ProcessedAt, для старых записей UpdatedAt).
+func processingStartedAt(tx *entities.Transaction) time.Time {
+	if started := tx.ProcessedAt(); started != nil {
+		return *started
+	}
+	return tx.UpdatedAt()
+}
+
+// InstanceRepository реализует ports.InstanceRepository поверх Store.
+type InstanceRepository struct {
+	store *Store
+}
+
+// NewInstanceRepository создаёт новый InstanceRepository.
+func NewInstanceRepository(store *Store) *InstanceRepository {
+	return &InstanceRepository{store: store}
+}
+
+// Heartbeat записывает время последнего heartbeat экземпляра.
+func (r *InstanceRepository) Heartbeat(ctx context.Context, instance string, at time.Time) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.instances[instance] = at.UTC()
+	return nil
+}
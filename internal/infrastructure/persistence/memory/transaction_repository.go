@@ -0,0 +1,384 @@
+// Package memory - TransactionRepository implementation with idempotency support.
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// Compile-time check
+var _ ports.TransactionRepository = (*TransactionRepository)(nil)
+
+// idempotencyIndexKey - ключ идемпотентности в окружении
+// (аналог UNIQUE (environment, idempotency_key) в postgres).
+type idempotencyIndexKey struct {
+	environment string
+	key         string
+}
+
+// TransactionRepository реализует ports.TransactionRepository поверх Store.
+type TransactionRepository struct {
+	store       *Store
+	environment string // см. WithEnvironment
+	instance    string // см. WithInstance
+}
+
+// NewTransactionRepository создаёт новый TransactionRepository.
+// По умолчанию окружение не задано ("").
+func NewTransactionRepository(store *Store) *TransactionRepository {
+	return &TransactionRepository{store: store}
+}
+
+// WithEnvironment задаёт окружение, в котором создаются транзакции.
+// Ключи идемпотентности уникальны в пределах окружения; транзакции
+// без окружения ("", созданные до его учёта) видны из любого.
+func (r *TransactionRepository) WithEnvironment(environment string) *TransactionRepository {
+	r.environment = environment
+	return r
+}
+
+// WithInstance задаёт экземпляр, который записывается владельцем
+// транзакций, сохраняемых в PROCESSING (см. ports.ProcessingRecoveryRepository).
+func (r *TransactionRepository) WithInstance(instance string) *TransactionRepository {
+	r.instance = instance
+	return r
+}
+
+// Save сохраняет транзакцию (upsert по ID, idempotency key уникален в окружении).
+// Окружение существующей транзакции не меняется. COMPLETED транзакция без
+// номера чека получает его здесь (см. assignReceiptNumber).
+func (r *TransactionRepository) Save(ctx context.Context, tx *entities.Transaction) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.assignReceiptNumber(tx); err != nil {
+		return err
+	}
+
+	snapshot, err := cloneTransaction(tx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	if _, ok := r.store.transactions[tx.ID()]; ok {
+		r.store.transactions[tx.ID()] = snapshot
+		r.recordOwner(tx)
+		return nil
+	}
+
+	key := idempotencyIndexKey{environment: r.environment, key: tx.IdempotencyKey()}
+	if _, ok := r.store.idempotencyKeys[key]; ok {
+		return domainErrors.ErrDuplicateTransaction
+	}
+
+	if _, ok := r.store.wallets[tx.WalletID()]; !ok {
+		return domainErrors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", nil)
+	}
+	if dest := tx.DestinationWalletID(); dest != nil {
+		if _, ok := r.store.wallets[*dest]; !ok {
+			return domainErrors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", nil)
+		}
+	}
+
+	r.store.transactions[tx.ID()] = snapshot
+	r.store.idempotencyKeys[key] = tx.ID()
+	r.recordOwner(tx)
+	return nil
+}
+
+// recordOwner запоминает экземпляр-владелец PROCESSING транзакции;
+// владелец прочих статусов не меняется (как COALESCE в postgres).
+// Вызывается под store.mu.
+func (r *TransactionRepository) recordOwner(tx *entities.Transaction) {
+	if tx.Status() == entities.TransactionStatusProcessing && r.instance != "" {
+		r.store.processingOwners[tx.ID()] = r.instance
+	}
+}
+
+// FindByID загружает транзакцию по ID.
+func (r *TransactionRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tx, ok := r.store.transactions[id]
+	if !ok {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+
+	return cloneTransaction(tx)
+}
+
+// FindByIdempotencyKey находит транзакцию по ключу идемпотентности
+// в окружении репозитория (или среди транзакций без окружения).
+func (r *TransactionRepository) FindByIdempotencyKey(ctx context.Context, key string) (*entities.Transaction, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	id, ok := r.store.idempotencyKeys[idempotencyIndexKey{environment: r.environment, key: key}]
+	if !ok {
+		id, ok = r.store.idempotencyKeys[idempotencyIndexKey{key: key}]
+	}
+	if !ok {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+
+	tx, ok := r.store.transactions[id]
+	if !ok {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+
+	return cloneTransaction(tx)
+}
+
+// FindByExternalReference находит транзакции по хэшу внешней ссылки (created_at ASC).
+func (r *TransactionRepository) FindByExternalReference(ctx context.Context, reference string) ([]*entities.Transaction, error) {
+	defer recordQuery(ctx, time.Now())
+
+	hash := valueobjects.HashExternalReference(reference)
+	transactions, err := r.filter(func(tx *entities.Transaction) bool {
+		return tx.ExternalReferenceHash() == hash
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortByCreatedAt(transactions, false)
+	return transactions, nil
+}
+
+// FindByWalletID возвращает транзакции кошелька (created_at DESC) с пагинацией.
+func (r *TransactionRepository) FindByWalletID(ctx context.Context, walletID uuid.UUID, offset, limit int) ([]*entities.Transaction, error) {
+	defer recordQuery(ctx, time.Now())
+
+	transactions, err := r.filter(func(tx *entities.Transaction) bool {
+		return tx.WalletID() == walletID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortByCreatedAt(transactions, true)
+	return paginate(transactions, offset, limit), nil
+}
+
+// FindPendingByWallet возвращает pending транзакции кошелька (created_at ASC).
+func (r *TransactionRepository) FindPendingByWallet(ctx context.Context, walletID uuid.UUID) ([]*entities.Transaction, error) {
+	defer recordQuery(ctx, time.Now())
+
+	transactions, err := r.filter(func(tx *entities.Transaction) bool {
+		return tx.WalletID() == walletID && tx.Status() == entities.TransactionStatusPending
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortByCreatedAt(transactions, false)
+	return transactions, nil
+}
+
+// CountPendingByWallet возвращает число PENDING и PROCESSING транзакций кошелька.
+func (r *TransactionRepository) CountPendingByWallet(ctx context.Context, walletID uuid.UUID) (int, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := 0
+	for _, tx := range r.store.transactions {
+		if tx.WalletID() == walletID && tx.Status().IsInFlight() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CountOutcomes считает COMPLETED и FAILED транзакции, завершённые в [from, to).
+func (r *TransactionRepository) CountOutcomes(ctx context.Context, from, to time.Time) (ports.TransactionOutcomeCounts, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := ports.TransactionOutcomeCounts{Failed: make(map[entities.FailureCategory]int)}
+	for _, tx := range r.store.transactions {
+		completedAt := tx.CompletedAt()
+		if completedAt == nil || completedAt.Before(from) || !completedAt.Before(to) {
+			continue
+		}
+		switch tx.Status() {
+		case entities.TransactionStatusCompleted:
+			counts.Completed++
+		case entities.TransactionStatusFailed:
+			counts.Failed[tx.FailureCategory()]++
+		}
+	}
+	return counts, nil
+}
+
+// FindFailedRetryable возвращает failed транзакции, которые можно повторить:
+// лимит попыток не исчерпан и next_retry_at уже наступил.
+func (r *TransactionRepository) FindFailedRetryable(ctx context.Context, maxRetries int, limit int) ([]*entities.Transaction, error) {
+	defer recordQuery(ctx, time.Now())
+
+	now := time.Now()
+	transactions, err := r.filter(func(tx *entities.Transaction) bool {
+		return tx.Status() == entities.TransactionStatusFailed && tx.RetryCount() < maxRetries && tx.IsRetryDue(now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortByCreatedAt(transactions, false)
+	return paginate(transactions, 0, limit), nil
+}
+
+// List возвращает транзакции с фильтрацией (created_at DESC, id DESC) и пагинацией.
+func (r *TransactionRepository) List(ctx context.Context, filter ports.TransactionFilter, offset, limit int) ([]*entities.Transaction, error) {
+	defer recordQuery(ctx, time.Now())
+
+	transactions, err := r.list(filter, nil)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(transactions, offset, limit), nil
+}
+
+// ListBefore возвращает страницу List после курсора.
+func (r *TransactionRepository) ListBefore(ctx context.Context, filter ports.TransactionFilter, before *ports.TransactionCursor, limit int) ([]*entities.Transaction, error) {
+	defer recordQuery(ctx, time.Now())
+
+	transactions, err := r.list(filter, before)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(transactions, 0, limit), nil
+}
+
+// ListAfter возвращает транзакции в хронологическом порядке после курсора.
+func (r *TransactionRepository) ListAfter(ctx context.Context, filter ports.TransactionFilter, after *ports.TransactionCursor, limit int) ([]*entities.Transaction, error) {
+	defer recordQuery(ctx, time.Now())
+
+	transactions, err := r.list(filter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// list - в порядке DESC: всё, что стоит в нём до курсора, проведено после него
+	chronological := make([]*entities.Transaction, 0, len(transactions))
+	for i := len(transactions) - 1; i >= 0; i-- {
+		tx := transactions[i]
+		if after != nil && (listedAfter(tx, after) || tx.ID() == after.ID) {
+			continue
+		}
+		chronological = append(chronological, tx)
+	}
+	return paginate(chronological, 0, limit), nil
+}
+
+// list отбирает транзакции по фильтру в порядке List; before оставляет
+// только строки после курсора.
+func (r *TransactionRepository) list(filter ports.TransactionFilter, before *ports.TransactionCursor) ([]*entities.Transaction, error) {
+	// Фильтр по пользователю - через кошелёк транзакции, как JOIN в postgres
+	var userWallets map[uuid.UUID]bool
+	if filter.UserID != nil {
+		r.store.mu.RLock()
+		userWallets = make(map[uuid.UUID]bool)
+		for id, w := range r.store.wallets {
+			if w.UserID() == *filter.UserID {
+				userWallets[id] = true
+			}
+		}
+		r.store.mu.RUnlock()
+	}
+
+	transactions, err := r.filter(func(tx *entities.Transaction) bool {
+		if filter.WalletID != nil {
+			dest := tx.DestinationWalletID()
+			if tx.WalletID() != *filter.WalletID && (dest == nil || *dest != *filter.WalletID) {
+				return false
+			}
+		}
+		if userWallets != nil && !userWallets[tx.WalletID()] {
+			return false
+		}
+		if filter.Type != nil && tx.Type() != *filter.Type {
+			return false
+		}
+		if filter.Status != nil && tx.Status() != *filter.Status {
+			return false
+		}
+		if filter.CreatedFrom != nil && tx.CreatedAt().Before(*filter.CreatedFrom) {
+			return false
+		}
+		if filter.CreatedTo != nil && !tx.CreatedAt().Before(*filter.CreatedTo) {
+			return false
+		}
+		if before != nil && !listedAfter(tx, before) {
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(transactions, func(i, j int) bool {
+		return listedAfter(transactions[j], ports.CursorOf(transactions[i]))
+	})
+	return transactions, nil
+}
+
+// listedAfter - стоит ли tx после курсора в порядке created_at DESC, id DESC.
+func listedAfter(tx *entities.Transaction, cursor *ports.TransactionCursor) bool {
+	if !tx.CreatedAt().Equal(cursor.CreatedAt) {
+		return tx.CreatedAt().Before(cursor.CreatedAt)
+	}
+	id := tx.ID()
+	return bytes.Compare(id[:], cursor.ID[:]) < 0
+}
+
+// filter возвращает копии транзакций, удовлетворяющих условию.
+func (r *TransactionRepository) filter(match func(*entities.Transaction) bool) ([]*entities.Transaction, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var transactions []*entities.Transaction
+	for _, tx := range r.store.transactions {
+		if !match(tx) {
+			continue
+		}
+		snapshot, err := cloneTransaction(tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconstruct transaction: %w", err)
+		}
+		transactions = append(transactions, snapshot)
+	}
+
+	return transactions, nil
+}
+
+// sortByCreatedAt сортирует транзакции по created_at.
+func sortByCreatedAt(transactions []*entities.Transaction, desc bool) {
+	sort.SliceStable(transactions, func(i, j int) bool {
+		if desc {
+			return transactions[i].CreatedAt().After(transactions[j].CreatedAt())
+		}
+		return transactions[i].CreatedAt().Before(transactions[j].CreatedAt())
+	})
+}
@@ -0,0 +1,86 @@
+// Package memory - UnitOfWork implementation с откатом через snapshot.
+package memory
+
+import (
+	"context"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check
+var _ ports.UnitOfWork = (*UnitOfWork)(nil)
+
+// txKey - маркер активной транзакции в context.
+type txKey struct{}
+
+// UnitOfWork реализует ports.UnitOfWork поверх Store.
+//
+// Execute запоминает snapshot хранилища и восстанавливает его, если fn
+// вернула ошибку или запаниковала - так же, как ROLLBACK в postgres.
+// Транзакции сериализуются: одновременно выполняется только один Execute.
+//
+// Ограничение: записи через репозитории вне Execute, сделанные во время
+// чужой транзакции, будут потеряны при её откате.
+type UnitOfWork struct {
+	store *Store
+}
+
+// NewUnitOfWork создаёт новый UnitOfWork.
+func NewUnitOfWork(store *Store) *UnitOfWork {
+	return &UnitOfWork{store: store}
+}
+
+// Execute выполняет fn атомарно относительно Store.
+//
+// Повторный Execute с context без транзакции во время активного UnitOfWork
+// запроса возвращает ports.ErrNestedUnitOfWork (см. ports.UnitOfWorkGuard);
+// без guard такой вызов ждал бы txMu вечно.
+func (u *UnitOfWork) Execute(ctx context.Context, fn func(context.Context) error) error {
+	// Вложенный вызов - просто выполняем функцию в текущей транзакции
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	guard := ports.UnitOfWorkGuardFromContext(ctx)
+	if !guard.Enter() {
+		return ports.ErrNestedUnitOfWork
+	}
+	defer guard.Leave()
+
+	u.store.txMu.Lock()
+	defer u.store.txMu.Unlock()
+
+	state := u.store.snapshot()
+
+	defer func() {
+		if r := recover(); r != nil {
+			// Panic - откатываем и re-panic
+			u.store.restore(state)
+			panic(r)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, true)); err != nil {
+		u.store.restore(state)
+		return err
+	}
+
+	return nil
+}
+
+// ExecuteWithResult выполняет функцию и возвращает результат.
+func (u *UnitOfWork) ExecuteWithResult(ctx context.Context, fn func(context.Context) (interface{}, error)) (interface{}, error) {
+	var result interface{}
+
+	err := u.Execute(ctx, func(txCtx context.Context) error {
+		var fnErr error
+		result, fnErr = fn(txCtx)
+		return fnErr
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+func newTestUser(t *testing.T) *entities.User {
+	t.Helper()
+
+	user, err := entities.NewUser(uuid.NewString()+"@example.com", "Test User")
+	require.NoError(t, err)
+	return user
+}
+
+func TestUnitOfWork_CommitKeepsChanges(t *testing.T) {
+	store := NewStore()
+	users := NewUserRepository(store)
+	publisher := NewEventPublisher(store)
+	uow := NewUnitOfWork(store)
+	user := newTestUser(t)
+
+	err := uow.Execute(context.Background(), func(txCtx context.Context) error {
+		if err := users.Save(txCtx, user); err != nil {
+			return err
+		}
+		return publisher.Publish(txCtx, events.NewUserCreated(user.ID(), user.Email(), user.FullName()))
+	})
+	require.NoError(t, err)
+
+	_, err = users.FindByID(context.Background(), user.ID())
+	assert.NoError(t, err)
+	assert.Len(t, publisher.Events(), 1)
+}
+
+func TestUnitOfWork_ErrorRollsBack(t *testing.T) {
+	store := NewStore()
+	users := NewUserRepository(store)
+	publisher := NewEventPublisher(store)
+	uow := NewUnitOfWork(store)
+	user := newTestUser(t)
+	failure := errors.New("boom")
+
+	err := uow.Execute(context.Background(), func(txCtx context.Context) error {
+		require.NoError(t, users.Save(txCtx, user))
+		require.NoError(t, publisher.Publish(txCtx, events.NewUserCreated(user.ID(), user.Email(), user.FullName())))
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	_, err = users.FindByID(context.Background(), user.ID())
+	assert.True(t, domainErrors.IsNotFound(err))
+	assert.Empty(t, publisher.Events())
+}
+
+func TestUnitOfWork_PanicRollsBack(t *testing.T) {
+	store := NewStore()
+	users := NewUserRepository(store)
+	uow := NewUnitOfWork(store)
+	user := newTestUser(t)
+
+	assert.PanicsWithValue(t, "crash", func() {
+		_ = uow.Execute(context.Background(), func(txCtx context.Context) error {
+			require.NoError(t, users.Save(txCtx, user))
+			panic("crash")
+		})
+	})
+
+	_, err := users.FindByID(context.Background(), user.ID())
+	assert.True(t, domainErrors.IsNotFound(err))
+}
+
+func TestUnitOfWork_NestedExecuteJoinsOuter(t *testing.T) {
+	store := NewStore()
+	users := NewUserRepository(store)
+	uow := NewUnitOfWork(store)
+	user := newTestUser(t)
+	failure := errors.New("outer failed")
+
+	err := uow.Execute(context.Background(), func(txCtx context.Context) error {
+		// Вложенный Execute не коммитит самостоятельно
+		require.NoError(t, uow.Execute(txCtx, func(nestedCtx context.Context) error {
+			return users.Save(nestedCtx, user)
+		}))
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	_, err = users.FindByID(context.Background(), user.ID())
+	assert.True(t, domainErrors.IsNotFound(err))
+}
+
+// Use case захватил внешний ctx в замыкании и передал его репозиторию
+// внутри Execute: запрос выполнился мимо транзакции. Guard запроса это ловит.
+func TestUnitOfWork_GuardCatchesCapturedContext(t *testing.T) {
+	store := NewStore()
+	users := NewUserRepository(store)
+	uow := NewUnitOfWork(store)
+
+	t.Run("Logged", func(t *testing.T) {
+		guard := ports.NewUnitOfWorkGuard(nil, false)
+		ctx := ports.WithUnitOfWorkGuard(context.Background(), guard)
+		user := newTestUser(t)
+
+		err := uow.Execute(ctx, func(txCtx context.Context) error {
+			return users.Save(ctx, user) // должно быть txCtx
+		})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, guard.Violations())
+
+		// Тот же репозиторий после Execute и с txCtx - не нарушение
+		_, err = users.FindByID(ctx, user.ID())
+		require.NoError(t, err)
+		require.NoError(t, uow.Execute(ctx, func(txCtx context.Context) error {
+			_, err := users.FindByID(txCtx, user.ID())
+			return err
+		}))
+		assert.EqualValues(t, 1, guard.Violations())
+	})
+
+	t.Run("StrictPanics", func(t *testing.T) {
+		ctx := ports.WithUnitOfWorkGuard(context.Background(), ports.NewUnitOfWorkGuard(nil, true))
+		user := newTestUser(t)
+
+		assert.Panics(t, func() {
+			_ = uow.Execute(ctx, func(txCtx context.Context) error {
+				return users.Save(ctx, user)
+			})
+		})
+
+		// Паника откатила транзакцию, guard снова свободен
+		_, err := users.FindByID(context.Background(), user.ID())
+		assert.True(t, domainErrors.IsNotFound(err))
+		assert.NoError(t, uow.Execute(ctx, func(context.Context) error { return nil }))
+	})
+}
+
+func TestUnitOfWork_NestedExecuteWithOuterContext(t *testing.T) {
+	store := NewStore()
+	users := NewUserRepository(store)
+	uow := NewUnitOfWork(store)
+	ctx := ports.WithUnitOfWorkGuard(context.Background(), ports.NewUnitOfWorkGuard(nil, true))
+	user := newTestUser(t)
+
+	err := uow.Execute(ctx, func(txCtx context.Context) error {
+		// С txCtx - та же транзакция
+		if err := uow.Execute(txCtx, func(innerCtx context.Context) error {
+			return users.Save(innerCtx, user)
+		}); err != nil {
+			return err
+		}
+		// С внешним ctx - вторая транзакция; без guard здесь был бы deadlock
+		return uow.Execute(ctx, func(context.Context) error { return nil })
+	})
+	assert.ErrorIs(t, err, ports.ErrNestedUnitOfWork)
+
+	_, err = users.FindByID(context.Background(), user.ID())
+	assert.True(t, domainErrors.IsNotFound(err), "outer transaction must roll back")
+}
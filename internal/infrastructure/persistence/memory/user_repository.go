@@ -0,0 +1,172 @@
+// Package memory - UserRepository implementation.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// Compile-time check
+var _ ports.UserRepository = (*UserRepository)(nil)
+
+// UserRepository реализует ports.UserRepository поверх Store.
+// Email сравниваются по ключу EmailPolicy, как normalized_email в postgres.
+type UserRepository struct {
+	store       *Store
+	emailPolicy valueobjects.EmailPolicy
+}
+
+// NewUserRepository создаёт новый UserRepository с политикой email по умолчанию.
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store, emailPolicy: valueobjects.DefaultEmailPolicy()}
+}
+
+// WithEmailPolicy задаёт политику нормализации email.
+func (r *UserRepository) WithEmailPolicy(policy valueobjects.EmailPolicy) *UserRepository {
+	r.emailPolicy = policy
+	return r
+}
+
+// Save сохраняет пользователя (upsert по ID, нормализованный email уникален).
+func (r *UserRepository) Save(ctx context.Context, user *entities.User) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	normalized := r.emailPolicy.Normalize(user.Email())
+	for id, existing := range r.store.users {
+		if id != user.ID() && r.emailPolicy.Normalize(existing.Email()) == normalized {
+			return domainErrors.NewBusinessRuleViolation(
+				"EMAIL_ALREADY_EXISTS",
+				fmt.Sprintf("user with email %s already exists", user.Email()),
+				map[string]interface{}{"email": user.Email()},
+			)
+		}
+	}
+
+	r.store.users[user.ID()] = cloneUser(user)
+	return nil
+}
+
+// FindByID загружает пользователя по ID.
+func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+
+	return cloneUser(user), nil
+}
+
+// FindByEmail загружает пользователя по нормализованному email.
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
+	defer recordQuery(ctx, time.Now())
+
+	return r.findOne(r.emailMatcher(email))
+}
+
+// ExistsByEmail проверяет существование пользователя по нормализованному email.
+func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	defer recordQuery(ctx, time.Now())
+
+	_, err := r.findOne(r.emailMatcher(email))
+	if err != nil {
+		if domainErrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// FindByTelegramID загружает пользователя по Telegram ID.
+func (r *UserRepository) FindByTelegramID(ctx context.Context, telegramID int64) (*entities.User, error) {
+	defer recordQuery(ctx, time.Now())
+
+	return r.findOne(func(u *entities.User) bool {
+		return u.TelegramID() != nil && *u.TelegramID() == telegramID
+	})
+}
+
+// List возвращает пользователей (created_at DESC) с пагинацией.
+func (r *UserRepository) List(ctx context.Context, offset, limit int) ([]*entities.User, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := make([]*entities.User, 0, len(r.store.users))
+	for _, u := range r.store.users {
+		users = append(users, cloneUser(u))
+	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i].CreatedAt().After(users[j].CreatedAt())
+	})
+
+	return paginate(users, offset, limit), nil
+}
+
+// UserActivitySummary считает кошельки и транзакции пользователя.
+func (r *UserRepository) UserActivitySummary(ctx context.Context, userID uuid.UUID) (ports.UserActivity, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var activity ports.UserActivity
+	wallets := make(map[uuid.UUID]struct{})
+	for id, w := range r.store.wallets {
+		if w.UserID() == userID {
+			wallets[id] = struct{}{}
+		}
+	}
+	activity.WalletCount = len(wallets)
+
+	for _, tx := range r.store.transactions {
+		if _, ok := wallets[tx.WalletID()]; !ok {
+			continue
+		}
+		activity.TotalTransactions++
+		if createdAt := tx.CreatedAt(); activity.LastActivityAt == nil || createdAt.After(*activity.LastActivityAt) {
+			activity.LastActivityAt = &createdAt
+		}
+	}
+
+	return activity, nil
+}
+
+// emailMatcher сравнивает email пользователя с email по ключу EmailPolicy.
+func (r *UserRepository) emailMatcher(email string) func(*entities.User) bool {
+	normalized := r.emailPolicy.Normalize(email)
+	return func(u *entities.User) bool { return r.emailPolicy.Normalize(u.Email()) == normalized }
+}
+
+// findOne возвращает копию первого пользователя, удовлетворяющего условию.
+func (r *UserRepository) findOne(match func(*entities.User) bool) (*entities.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, u := range r.store.users {
+		if match(u) {
+			return cloneUser(u), nil
+		}
+	}
+
+	return nil, domainErrors.ErrEntityNotFound
+}
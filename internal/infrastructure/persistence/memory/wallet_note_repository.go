@@ -0,0 +1,105 @@
+// Package memory - WalletNoteRepository implementation.
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check
+var _ ports.WalletNoteRepository = (*WalletNoteRepository)(nil)
+
+// WalletNoteRepository реализует ports.WalletNoteRepository поверх Store.
+type WalletNoteRepository struct {
+	store *Store
+}
+
+// NewWalletNoteRepository создаёт новый WalletNoteRepository.
+func NewWalletNoteRepository(store *Store) *WalletNoteRepository {
+	return &WalletNoteRepository{store: store}
+}
+
+// Save сохраняет копию заметки. Удалённые заметки остаются в хранилище.
+func (r *WalletNoteRepository) Save(ctx context.Context, note *entities.WalletNote) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.wallets[note.WalletID()]; !ok {
+		return domainErrors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", nil)
+	}
+
+	r.store.walletNotes[note.ID()] = cloneWalletNote(note)
+	return nil
+}
+
+// FindByID возвращает копию неудалённой заметки.
+func (r *WalletNoteRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.WalletNote, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	note, ok := r.store.walletNotes[id]
+	if !ok || note.IsDeleted() {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+
+	return cloneWalletNote(note), nil
+}
+
+// ListByWallet возвращает неудалённые заметки кошелька: закреплённые первыми,
+// затем по created_at DESC.
+func (r *WalletNoteRepository) ListByWallet(ctx context.Context, walletID uuid.UUID, offset, limit int) ([]*entities.WalletNote, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	notes := make([]*entities.WalletNote, 0)
+	for _, note := range r.store.walletNotes {
+		if note.WalletID() == walletID && !note.IsDeleted() {
+			notes = append(notes, note)
+		}
+	}
+
+	sort.Slice(notes, func(i, j int) bool {
+		if notes[i].Pinned() != notes[j].Pinned() {
+			return notes[i].Pinned()
+		}
+		if !notes[i].CreatedAt().Equal(notes[j].CreatedAt()) {
+			return notes[i].CreatedAt().After(notes[j].CreatedAt())
+		}
+		return notes[i].ID().String() > notes[j].ID().String()
+	})
+
+	page := paginate(notes, offset, limit)
+	result := make([]*entities.WalletNote, 0, len(page))
+	for _, note := range page {
+		result = append(result, cloneWalletNote(note))
+	}
+
+	return result, nil
+}
+
+// cloneWalletNote возвращает независимую копию заметки.
+func cloneWalletNote(n *entities.WalletNote) *entities.WalletNote {
+	var deletedBy *uuid.UUID
+	if id := n.DeletedBy(); id != nil {
+		v := *id
+		deletedBy = &v
+	}
+
+	return entities.ReconstructWalletNote(
+		n.ID(), n.WalletID(), n.AuthorID(), n.Body(), n.Pinned(),
+		n.CreatedAt(), n.DeletedAt(), deletedBy,
+	)
+}
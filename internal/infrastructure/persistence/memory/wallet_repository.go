@@ -0,0 +1,190 @@
+// Package memory - WalletRepository implementation with optimistic locking.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// Compile-time check
+var _ ports.WalletRepository = (*WalletRepository)(nil)
+
+// WalletRepository реализует ports.WalletRepository поверх Store.
+//
+// Optimistic locking повторяет postgres: version = 0 - INSERT,
+// иначе UPDATE только если в хранилище лежит version - 1.
+type WalletRepository struct {
+	store *Store
+}
+
+// NewWalletRepository создаёт новый WalletRepository.
+func NewWalletRepository(store *Store) *WalletRepository {
+	return &WalletRepository{store: store}
+}
+
+// Save сохраняет кошелёк с проверкой версии (optimistic locking).
+func (r *WalletRepository) Save(ctx context.Context, wallet *entities.Wallet) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if wallet.BalanceVersion() == 0 {
+		return r.insert(wallet)
+	}
+
+	return r.update(wallet)
+}
+
+// insert создаёт новый кошелёк. Вызывается под write lock.
+func (r *WalletRepository) insert(wallet *entities.Wallet) error {
+	if _, ok := r.store.users[wallet.UserID()]; !ok {
+		return domainErrors.NewDomainError("USER_NOT_FOUND", "user not found", nil)
+	}
+
+	for _, existing := range r.store.wallets {
+		if existing.UserID() == wallet.UserID() && existing.Currency().Equals(wallet.Currency()) {
+			return domainErrors.NewBusinessRuleViolation(
+				"WALLET_ALREADY_EXISTS",
+				fmt.Sprintf("wallet for currency %s already exists", wallet.Currency().Code()),
+				map[string]interface{}{
+					"user_id":  wallet.UserID().String(),
+					"currency": wallet.Currency().Code(),
+				},
+			)
+		}
+	}
+
+	r.store.wallets[wallet.ID()] = cloneWallet(wallet)
+	return nil
+}
+
+// update обновляет кошелёк с optimistic locking. Вызывается под write lock.
+func (r *WalletRepository) update(wallet *entities.Wallet) error {
+	expectedVersion := wallet.BalanceVersion() - 1
+
+	existing, ok := r.store.wallets[wallet.ID()]
+	if !ok || existing.BalanceVersion() != expectedVersion {
+		return domainErrors.NewConcurrencyError(
+			"Wallet",
+			wallet.ID().String(),
+			fmt.Sprintf("wallet was modified by another transaction (expected version: %d)", expectedVersion),
+		)
+	}
+
+	r.store.wallets[wallet.ID()] = cloneWallet(wallet)
+	return nil
+}
+
+// FindByID загружает кошелёк по ID.
+func (r *WalletRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wallet, ok := r.store.wallets[id]
+	if !ok {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+
+	return cloneWallet(wallet), nil
+}
+
+// FindBalanceByID читает балансы кошелька без копирования entity.
+func (r *WalletRepository) FindBalanceByID(ctx context.Context, id uuid.UUID) (*ports.WalletBalance, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wallet, ok := r.store.wallets[id]
+	if !ok {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+
+	return &ports.WalletBalance{
+		WalletID:       wallet.ID(),
+		UserID:         wallet.UserID(),
+		CurrencyCode:   wallet.Currency().Code(),
+		AvailableCents: wallet.AvailableBalance().Cents(),
+		PendingCents:   wallet.PendingBalance().Cents(),
+		BalanceVersion: wallet.BalanceVersion(),
+	}, nil
+}
+
+// FindByUserAndCurrency находит кошелёк пользователя для конкретной валюты.
+func (r *WalletRepository) FindByUserAndCurrency(ctx context.Context, userID uuid.UUID, currency valueobjects.Currency) (*entities.Wallet, error) {
+	defer recordQuery(ctx, time.Now())
+
+	wallets := r.filter(ports.WalletFilter{UserID: &userID, Currency: &currency})
+	if len(wallets) == 0 {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+
+	return wallets[0], nil
+}
+
+// FindByUserID возвращает все кошельки пользователя (created_at ASC).
+func (r *WalletRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Wallet, error) {
+	defer recordQuery(ctx, time.Now())
+
+	wallets := r.filter(ports.WalletFilter{UserID: &userID})
+
+	sort.SliceStable(wallets, func(i, j int) bool {
+		return wallets[i].CreatedAt().Before(wallets[j].CreatedAt())
+	})
+
+	return wallets, nil
+}
+
+// ExistsByUserAndCurrency проверяет существование кошелька.
+func (r *WalletRepository) ExistsByUserAndCurrency(ctx context.Context, userID uuid.UUID, currency valueobjects.Currency) (bool, error) {
+	defer recordQuery(ctx, time.Now())
+
+	return len(r.filter(ports.WalletFilter{UserID: &userID, Currency: &currency})) > 0, nil
+}
+
+// List возвращает кошельки с фильтрацией (created_at DESC) и пагинацией.
+func (r *WalletRepository) List(ctx context.Context, filter ports.WalletFilter, offset, limit int) ([]*entities.Wallet, error) {
+	defer recordQuery(ctx, time.Now())
+
+	wallets := r.filter(filter)
+
+	sort.SliceStable(wallets, func(i, j int) bool {
+		return wallets[i].CreatedAt().After(wallets[j].CreatedAt())
+	})
+
+	return paginate(wallets, offset, limit), nil
+}
+
+// filter возвращает копии кошельков, удовлетворяющих фильтру (без сортировки).
+func (r *WalletRepository) filter(filter ports.WalletFilter) []*entities.Wallet {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var wallets []*entities.Wallet
+	for _, w := range r.store.wallets {
+		if filter.UserID != nil && w.UserID() != *filter.UserID {
+			continue
+		}
+		if filter.Currency != nil && !w.Currency().Equals(*filter.Currency) {
+			continue
+		}
+		if filter.Status != nil && w.Status() != *filter.Status {
+			continue
+		}
+		wallets = append(wallets, cloneWallet(w))
+	}
+
+	return wallets
+}
@@ -0,0 +1,116 @@
+// Package memory - WalletSettingsRepository implementation.
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check
+var _ ports.WalletSettingsRepository = (*WalletSettingsRepository)(nil)
+
+// WalletSettingsRepository реализует ports.WalletSettingsRepository поверх Store.
+type WalletSettingsRepository struct {
+	store *Store
+}
+
+// NewWalletSettingsRepository создаёт новый WalletSettingsRepository.
+func NewWalletSettingsRepository(store *Store) *WalletSettingsRepository {
+	return &WalletSettingsRepository{store: store}
+}
+
+// Save сохраняет копию настроек кошелька.
+func (r *WalletSettingsRepository) Save(ctx context.Context, settings *entities.WalletSettings) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.wallets[settings.WalletID()]; !ok {
+		return domainErrors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", nil)
+	}
+
+	r.store.walletSettings[settings.WalletID()] = cloneWalletSettings(settings)
+	return nil
+}
+
+// FindByWalletID возвращает копию настроек кошелька.
+func (r *WalletSettingsRepository) FindByWalletID(ctx context.Context, walletID uuid.UUID) (*entities.WalletSettings, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	settings, ok := r.store.walletSettings[walletID]
+	if !ok {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+
+	return cloneWalletSettings(settings), nil
+}
+
+// FindByWalletIDs возвращает копии настроек кошельков, у которых они есть.
+func (r *WalletSettingsRepository) FindByWalletIDs(ctx context.Context, walletIDs []uuid.UUID) (map[uuid.UUID]*entities.WalletSettings, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	result := make(map[uuid.UUID]*entities.WalletSettings, len(walletIDs))
+	for _, walletID := range walletIDs {
+		if settings, ok := r.store.walletSettings[walletID]; ok {
+			result[walletID] = cloneWalletSettings(settings)
+		}
+	}
+	return result, nil
+}
+
+// SaveIncomingView сохраняет копию представления входящего перевода.
+func (r *WalletSettingsRepository) SaveIncomingView(ctx context.Context, view *entities.IncomingTransferView) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.wallets[view.WalletID()]; !ok {
+		return domainErrors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", nil)
+	}
+
+	r.store.incomingViews[view.TransactionID()] = cloneIncomingTransferView(view)
+	return nil
+}
+
+// FindIncomingView возвращает копию представления перевода для получателя.
+func (r *WalletSettingsRepository) FindIncomingView(ctx context.Context, transactionID uuid.UUID) (*entities.IncomingTransferView, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	view, ok := r.store.incomingViews[transactionID]
+	if !ok {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+
+	return cloneIncomingTransferView(view), nil
+}
+
+// cloneWalletSettings возвращает независимую копию настроек.
+func cloneWalletSettings(s *entities.WalletSettings) *entities.WalletSettings {
+	return entities.ReconstructWalletSettings(
+		s.WalletID(), s.IncomingDescriptionTemplate(), s.AutoTags(), s.DisplayCurrency(), s.UpdatedBy(), s.UpdatedAt(),
+	)
+}
+
+// cloneIncomingTransferView возвращает независимую копию представления.
+func cloneIncomingTransferView(v *entities.IncomingTransferView) *entities.IncomingTransferView {
+	return entities.ReconstructIncomingTransferView(
+		v.TransactionID(), v.WalletID(), v.Description(), v.Tags(), v.CreatedAt(),
+	)
+}
@@ -0,0 +1,352 @@
+// Package memory - WebhookSubscriptionRepository и WebhookDeliveryRepository implementation.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// Compile-time checks
+var (
+	_ ports.WebhookSubscriptionRepository = (*WebhookSubscriptionRepository)(nil)
+	_ ports.WebhookDeliveryRepository     = (*WebhookDeliveryRepository)(nil)
+)
+
+// ============================================
+// Subscriptions
+// ============================================
+
+// WebhookSubscriptionRepository реализует ports.WebhookSubscriptionRepository поверх Store.
+type WebhookSubscriptionRepository struct {
+	store *Store
+}
+
+// NewWebhookSubscriptionRepository создаёт новый WebhookSubscriptionRepository.
+func NewWebhookSubscriptionRepository(store *Store) *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{store: store}
+}
+
+// Save сохраняет копию подписки с проверкой версии.
+func (r *WebhookSubscriptionRepository) Save(ctx context.Context, subscription *entities.WebhookSubscription) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[subscription.UserID()]; !ok {
+		return domainErrors.NewDomainError("USER_NOT_FOUND", "user not found", nil)
+	}
+
+	expectedVersion := subscription.Version() - 1
+	existing, ok := r.store.webhookSubscriptions[subscription.ID()]
+	switch {
+	case !ok && subscription.Version() == 1:
+	case ok && existing.Version() == expectedVersion:
+	default:
+		return domainErrors.NewConcurrencyError(
+			"WebhookSubscription",
+			subscription.ID().String(),
+			fmt.Sprintf("webhook subscription was modified by another transaction (expected version: %d)", expectedVersion),
+		)
+	}
+
+	r.store.webhookSubscriptions[subscription.ID()] = cloneWebhookSubscription(subscription)
+	return nil
+}
+
+// FindByID возвращает копию неудалённой подписки.
+func (r *WebhookSubscriptionRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.WebhookSubscription, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subscription, ok := r.store.webhookSubscriptions[id]
+	if !ok || subscription.IsDeleted() {
+		return nil, domainErrors.ErrEntityNotFound
+	}
+
+	return cloneWebhookSubscription(subscription), nil
+}
+
+// ListByUserID возвращает неудалённые подписки пользователя по created_at DESC.
+func (r *WebhookSubscriptionRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.WebhookSubscription, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subscriptions := make([]*entities.WebhookSubscription, 0)
+	for _, subscription := range r.store.webhookSubscriptions {
+		if subscription.UserID() == userID && !subscription.IsDeleted() {
+			subscriptions = append(subscriptions, cloneWebhookSubscription(subscription))
+		}
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if !subscriptions[i].CreatedAt().Equal(subscriptions[j].CreatedAt()) {
+			return subscriptions[i].CreatedAt().After(subscriptions[j].CreatedAt())
+		}
+		return subscriptions[i].ID().String() > subscriptions[j].ID().String()
+	})
+
+	return subscriptions, nil
+}
+
+// PurgeDeleted удаляет подписки, удалённые раньше cutoff, и их доставки.
+func (r *WebhookSubscriptionRepository) PurgeDeleted(ctx context.Context, cutoff time.Time) (int64, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, subscription := range r.store.webhookSubscriptions {
+		if deletedAt := subscription.DeletedAt(); deletedAt != nil && deletedAt.Before(cutoff) {
+			delete(r.store.webhookSubscriptions, id)
+			purged++
+		}
+	}
+	for id, delivery := range r.store.webhookDeliveries {
+		if _, ok := r.store.webhookSubscriptions[delivery.SubscriptionID()]; !ok {
+			delete(r.store.webhookDeliveries, id)
+		}
+	}
+
+	return purged, nil
+}
+
+// cloneWebhookSubscription возвращает независимую копию подписки.
+func cloneWebhookSubscription(s *entities.WebhookSubscription) *entities.WebhookSubscription {
+	return entities.ReconstructWebhookSubscription(
+		s.ID(), s.UserID(), s.URL(), s.Secret(), s.EventTypes(), s.Status(),
+		s.ConsecutiveFailures(), s.FailingSince(), s.LastFailureReason(), s.DisabledAt(),
+		s.Version(), s.CreatedAt(), s.UpdatedAt(), s.DeletedAt(),
+	)
+}
+
+// ============================================
+// Deliveries
+// ============================================
+
+// WebhookDeliveryRepository реализует ports.WebhookDeliveryRepository поверх Store.
+type WebhookDeliveryRepository struct {
+	store *Store
+}
+
+// NewWebhookDeliveryRepository создаёт новый WebhookDeliveryRepository.
+func NewWebhookDeliveryRepository(store *Store) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{store: store}
+}
+
+// Enqueue сохраняет копию доставки, если событие ещё не стоит в очереди подписки.
+func (r *WebhookDeliveryRepository) Enqueue(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.webhookSubscriptions[delivery.SubscriptionID()]; !ok {
+		return domainErrors.NewDomainError("WEBHOOK_SUBSCRIPTION_NOT_FOUND", "webhook subscription not found", nil)
+	}
+	for _, existing := range r.store.webhookDeliveries {
+		if existing.SubscriptionID() == delivery.SubscriptionID() && existing.EventID() == delivery.EventID() {
+			return nil
+		}
+	}
+
+	r.store.webhookDeliveries[delivery.ID()] = cloneWebhookDelivery(delivery)
+	return nil
+}
+
+// Save сохраняет копию доставки.
+func (r *WebhookDeliveryRepository) Save(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.webhookDeliveries[delivery.ID()]; !ok {
+		return domainErrors.ErrEntityNotFound
+	}
+
+	r.store.webhookDeliveries[delivery.ID()] = cloneWebhookDelivery(delivery)
+	return nil
+}
+
+// FindDue возвращает PENDING доставки активных подписок со сроком не позже
+// now по created_at ASC.
+func (r *WebhookDeliveryRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*entities.WebhookDelivery, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	due := make([]*entities.WebhookDelivery, 0)
+	for _, delivery := range r.store.webhookDeliveries {
+		if !delivery.IsPending() || delivery.NextAttemptAt().After(now) {
+			continue
+		}
+		if subscription, ok := r.store.webhookSubscriptions[delivery.SubscriptionID()]; !ok || !subscription.IsActive() {
+			continue
+		}
+		due = append(due, delivery)
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].CreatedAt().Equal(due[j].CreatedAt()) {
+			return due[i].CreatedAt().Before(due[j].CreatedAt())
+		}
+		return due[i].ID().String() < due[j].ID().String()
+	})
+
+	page := paginate(due, 0, limit)
+	result := make([]*entities.WebhookDelivery, 0, len(page))
+	for _, delivery := range page {
+		result = append(result, cloneWebhookDelivery(delivery))
+	}
+	return result, nil
+}
+
+// ListBySubscription возвращает доставки подписки по created_at DESC.
+func (r *WebhookDeliveryRepository) ListBySubscription(ctx context.Context, subscriptionID uuid.UUID, offset, limit int) ([]*entities.WebhookDelivery, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	deliveries := make([]*entities.WebhookDelivery, 0)
+	for _, delivery := range r.store.webhookDeliveries {
+		if delivery.SubscriptionID() == subscriptionID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].CreatedAt().Equal(deliveries[j].CreatedAt()) {
+			return deliveries[i].CreatedAt().After(deliveries[j].CreatedAt())
+		}
+		return deliveries[i].ID().String() > deliveries[j].ID().String()
+	})
+
+	page := paginate(deliveries, offset, limit)
+	result := make([]*entities.WebhookDelivery, 0, len(page))
+	for _, delivery := range page {
+		result = append(result, cloneWebhookDelivery(delivery))
+	}
+	return result, nil
+}
+
+// SkipPending отмечает PENDING доставки подписки SKIPPED.
+func (r *WebhookDeliveryRepository) SkipPending(ctx context.Context, subscriptionID uuid.UUID) (int64, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := clock.Now()
+	return r.updatePending(
+		func(d *entities.WebhookDelivery) bool { return d.SubscriptionID() == subscriptionID },
+		func(d *entities.WebhookDelivery) *entities.WebhookDelivery {
+			return withWebhookDeliveryStatus(d, entities.WebhookDeliverySkipped, d.NextAttemptAt(), &now)
+		},
+	), nil
+}
+
+// ReschedulePending переносит срок PENDING доставок подписки на at.
+func (r *WebhookDeliveryRepository) ReschedulePending(ctx context.Context, subscriptionID uuid.UUID, at time.Time) (int64, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.updatePending(
+		func(d *entities.WebhookDelivery) bool { return d.SubscriptionID() == subscriptionID },
+		func(d *entities.WebhookDelivery) *entities.WebhookDelivery {
+			return withWebhookDeliveryStatus(d, entities.WebhookDeliveryPending, at, nil)
+		},
+	), nil
+}
+
+// ExpireBacklog отмечает EXPIRED PENDING доставки подписок, отключённых
+// раньше disabledBefore.
+func (r *WebhookDeliveryRepository) ExpireBacklog(ctx context.Context, disabledBefore time.Time) (int64, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := clock.Now()
+	return r.updatePending(
+		func(d *entities.WebhookDelivery) bool {
+			subscription, ok := r.store.webhookSubscriptions[d.SubscriptionID()]
+			if !ok || subscription.Status() == entities.WebhookStatusActive {
+				return false
+			}
+			disabledAt := subscription.DisabledAt()
+			return disabledAt != nil && disabledAt.Before(disabledBefore)
+		},
+		func(d *entities.WebhookDelivery) *entities.WebhookDelivery {
+			return withWebhookDeliveryStatus(d, entities.WebhookDeliveryExpired, d.NextAttemptAt(), &now)
+		},
+	), nil
+}
+
+// DeleteFinishedBefore удаляет завершённые доставки с created_at раньше cutoff.
+func (r *WebhookDeliveryRepository) DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	defer recordQuery(ctx, time.Now())
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var deleted int64
+	for id, delivery := range r.store.webhookDeliveries {
+		if !delivery.IsPending() && delivery.CreatedAt().Before(cutoff) {
+			delete(r.store.webhookDeliveries, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// updatePending заменяет подходящие PENDING доставки. Вызывается под write lock.
+func (r *WebhookDeliveryRepository) updatePending(
+	match func(*entities.WebhookDelivery) bool,
+	update func(*entities.WebhookDelivery) *entities.WebhookDelivery,
+) int64 {
+	var updated int64
+	for id, delivery := range r.store.webhookDeliveries {
+		if delivery.IsPending() && match(delivery) {
+			r.store.webhookDeliveries[id] = update(delivery)
+			updated++
+		}
+	}
+	return updated
+}
+
+// withWebhookDeliveryStatus возвращает копию доставки с новым статусом и сроками.
+func withWebhookDeliveryStatus(
+	d *entities.WebhookDelivery,
+	status entities.WebhookDeliveryStatus,
+	nextAttemptAt time.Time,
+	finishedAt *time.Time,
+) *entities.WebhookDelivery {
+	return entities.ReconstructWebhookDelivery(
+		d.ID(), d.SubscriptionID(), d.EventID(), d.EventType(), d.Payload(), status,
+		d.Attempts(), d.LastError(), d.LastStatusCode(), nextAttemptAt, d.CreatedAt(), finishedAt,
+	)
+}
+
+// cloneWebhookDelivery возвращает независимую копию доставки.
+func cloneWebhookDelivery(d *entities.WebhookDelivery) *entities.WebhookDelivery {
+	return withWebhookDeliveryStatus(d, d.Status(), d.NextAttemptAt(), d.FinishedAt())
+}
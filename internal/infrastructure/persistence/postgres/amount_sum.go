@@ -0,0 +1,89 @@
+// Package postgres - SumAmounts: точное суммирование денежных колонок.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// Денежные колонки хранят BIGINT в минимальных единицах валюты (центы,
+// сатоши). Агрегаты над ними (статистика, лимиты, выписки) считаются только
+// через sumAmounts:
+//   - SUM(bigint) в PostgreSQL - NUMERIC, без приведения к float;
+//   - сумма читается строкой (::text) и разбирается в big.Int, поэтому ни
+//     драйвер, ни Go не проходят через float64 и переполнения int64 нет.
+//
+// Приведения ::float / ::double precision в SQL пакета и float колонки с
+// денежными именами в миграциях запрещены тестом (sql_money_lint_test.go).
+
+// AmountSumFilter - какие транзакции суммирует TransactionRepository.SumAmounts.
+type AmountSumFilter struct {
+	WalletID uuid.UUID
+	Currency valueobjects.Currency
+	Types    []entities.TransactionType   // пусто - все типы
+	Statuses []entities.TransactionStatus // пусто - все статусы
+	From     time.Time                    // created_at >= From; нулевое - без нижней границы
+	To       time.Time                    // created_at < To; нулевое - без верхней границы
+}
+
+// SumAmounts суммирует amount транзакций кошелька в валюте filter.Currency.
+// Без подходящих транзакций - ноль.
+func (r *TransactionRepository) SumAmounts(ctx context.Context, filter AmountSumFilter) (valueobjects.Money, error) {
+	query := newSelect(`SELECT COALESCE(SUM(amount), 0)::text FROM transactions`).
+		Where("wallet_id = ?", filter.WalletID).
+		Where("currency = ?", filter.Currency.Code())
+
+	if len(filter.Types) > 0 {
+		types := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			types[i] = string(t)
+		}
+		query.Where("transaction_type = ANY(?)", types)
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, s := range filter.Statuses {
+			statuses[i] = string(s)
+		}
+		query.Where("status = ANY(?)", statuses)
+	}
+	if !filter.From.IsZero() {
+		query.Where("created_at >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		query.Where("created_at < ?", filter.To.UTC())
+	}
+
+	sql, args := query.SQL()
+	return sumAmounts(ctx, r.getQuerier(ctx), filter.Currency, sql, args...)
+}
+
+// sumAmounts выполняет запрос с единственной колонкой - суммой минимальных
+// единиц в виде текста - и возвращает её как Money.
+func sumAmounts(ctx context.Context, q querier, currency valueobjects.Currency, query string, args ...any) (valueobjects.Money, error) {
+	var text string
+	if err := q.QueryRow(ctx, query, args...).Scan(&text); err != nil {
+		return valueobjects.Money{}, fmt.Errorf("failed to sum amounts: %w", err)
+	}
+	return moneyFromMinorUnitsText(text, currency)
+}
+
+// moneyFromMinorUnitsText разбирает целое число минимальных единиц любой
+// длины в Money.
+func moneyFromMinorUnitsText(text string, currency valueobjects.Currency) (valueobjects.Money, error) {
+	minor, ok := new(big.Int).SetString(text, 10)
+	if !ok {
+		return valueobjects.Money{}, fmt.Errorf("invalid amount sum %q", text)
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(currency.Decimals())), nil)
+	amount := new(big.Rat).SetFrac(minor, scale)
+	return valueobjects.NewMoney(amount.FloatString(currency.Decimals()), currency)
+}
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+func TestMoneyFromMinorUnitsText(t *testing.T) {
+	usd, err := valueobjects.NewCurrency("USD")
+	require.NoError(t, err)
+
+	money, err := moneyFromMinorUnitsText("100000", usd)
+	require.NoError(t, err)
+	assert.Equal(t, "1000.00 USD", money.String())
+
+	money, err = moneyFromMinorUnitsText("0", usd)
+	require.NoError(t, err)
+	assert.True(t, money.IsZero())
+
+	// Сумма за пределами int64 (SUM(bigint) в PostgreSQL - NUMERIC)
+	money, err = moneyFromMinorUnitsText("92233720368547758070", usd)
+	require.NoError(t, err)
+	assert.Equal(t, "922337203685477580.70 USD", money.String())
+
+	_, err = moneyFromMinorUnitsText("1.5e3", usd)
+	assert.Error(t, err)
+}
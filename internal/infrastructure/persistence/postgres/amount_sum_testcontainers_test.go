@@ -0,0 +1,120 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// setupMigratedDB поднимает отдельный контейнер со схемой из /migrations
+// (все up миграции по порядку) - в отличие от setupSharedTestDB, который
+// использует упрощённую тестовую схему.
+func setupMigratedDB(t *testing.T) *pgxpool.Pool {
+	ctx := context.Background()
+
+	container, err := postgres.Run(ctx,
+		"postgres:16-alpine",
+		postgres.WithDatabase("testdb"),
+		postgres.WithUsername("testuser"),
+		postgres.WithPassword("testpass"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(60*time.Second),
+		),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = container.Terminate(context.Background()) })
+
+	connStr, err := container.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+	pool, err := pgxpool.New(ctx, connStr)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	files, err := filepath.Glob(filepath.Join("..", "..", "..", "..", "migrations", "*.up.sql"))
+	require.NoError(t, err)
+	sort.Strings(files)
+	// По одной миграции на Exec: CREATE INDEX CONCURRENTLY нельзя
+	// выполнять в одном batch с другими запросами
+	for _, file := range files {
+		migration, err := os.ReadFile(file)
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx, string(migration))
+		require.NoError(t, err, file)
+	}
+
+	return pool
+}
+
+func TestAmountSum_Integration(t *testing.T) {
+	pool := setupMigratedDB(t)
+	ctx := context.Background()
+
+	t.Run("NoFloatMoneyColumns", func(t *testing.T) {
+		rows, err := pool.Query(ctx, `
+			SELECT table_name, column_name, data_type
+			FROM information_schema.columns
+			WHERE table_schema = 'public'
+			  AND column_name ~ '(amount|balance|limit|fee)'
+			  AND data_type IN ('real', 'double precision')
+		`)
+		require.NoError(t, err)
+		defer rows.Close()
+
+		for rows.Next() {
+			var table, column, dataType string
+			require.NoError(t, rows.Scan(&table, &column, &dataType))
+			t.Errorf("monetary column %s.%s has floating point type %s", table, column, dataType)
+		}
+		require.NoError(t, rows.Err())
+	})
+
+	t.Run("SumOfTenThousandDimesIsExact", func(t *testing.T) {
+		currency, err := valueobjects.NewCurrency("USD")
+		require.NoError(t, err)
+
+		user, err := entities.NewUser("amount-sum@example.com", "Amount Sum")
+		require.NoError(t, err)
+		require.NoError(t, NewUserRepository(pool).Save(ctx, user))
+		wallet, err := entities.NewWallet(user.ID(), currency)
+		require.NoError(t, err)
+		require.NoError(t, NewWalletRepository(pool).Save(ctx, wallet))
+
+		// 10 000 депозитов по 0.10 USD (10 центов)
+		_, err = pool.Exec(ctx, `
+			INSERT INTO transactions (id, wallet_id, idempotency_key, transaction_type, status, amount, currency)
+			SELECT gen_random_uuid(), $1, 'amount-sum-' || n, 'DEPOSIT', 'COMPLETED', 10, 'USD'
+			FROM generate_series(1, 10000) AS n
+		`, wallet.ID())
+		require.NoError(t, err)
+
+		repo := NewTransactionRepository(pool)
+		sum, err := repo.SumAmounts(ctx, AmountSumFilter{WalletID: wallet.ID(), Currency: currency})
+		require.NoError(t, err)
+		assert.Equal(t, "1000.00 USD", sum.String())
+
+		sum, err = repo.SumAmounts(ctx, AmountSumFilter{
+			WalletID: wallet.ID(),
+			Currency: currency,
+			Types:    []entities.TransactionType{entities.TransactionTypeWithdraw},
+		})
+		require.NoError(t, err)
+		assert.True(t, sum.IsZero())
+	})
+}
@@ -99,6 +99,9 @@ func NewConnectionPool(ctx context.Context, cfg Config) (*pgxpool.Pool, error) {
 	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
 	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
 
+	// Сессия в UTC: NOW() и timestamptz в текстовом виде не зависят от TZ сервера БД
+	poolConfig.ConnConfig.RuntimeParams["timezone"] = "UTC"
+
 	// Создаём пул
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
@@ -0,0 +1,76 @@
+// Package postgres - DedupStore implementation.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check: DedupStore implements ports.DedupStore
+var _ ports.DedupStore = (*DedupStore)(nil)
+
+// DedupStore реализует ports.DedupStore (таблица processed_events).
+type DedupStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewDedupStore создаёт новый DedupStore.
+func NewDedupStore(pool *pgxpool.Pool) *DedupStore {
+	return &DedupStore{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (s *DedupStore) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, s.pool)
+}
+
+// MarkProcessed вставляет пару; конфликт по PRIMARY KEY - событие уже обработано.
+// Параллельная вставка той же пары ждёт COMMIT/ROLLBACK первой транзакции.
+func (s *DedupStore) MarkProcessed(ctx context.Context, consumer string, eventID uuid.UUID) (bool, error) {
+	q := s.getQuerier(ctx)
+
+	query := `
+		INSERT INTO processed_events (consumer, event_id, processed_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (consumer, event_id) DO NOTHING
+	`
+
+	tag, err := q.Exec(ctx, query, consumer, eventID)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark event processed: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// Unmark удаляет пару.
+func (s *DedupStore) Unmark(ctx context.Context, consumer string, eventID uuid.UUID) error {
+	q := s.getQuerier(ctx)
+
+	if _, err := q.Exec(ctx, `DELETE FROM processed_events WHERE consumer = $1 AND event_id = $2`, consumer, eventID); err != nil {
+		return fmt.Errorf("failed to unmark processed event: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBefore удаляет отметки старше cutoff.
+func (s *DedupStore) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	q := s.getQuerier(ctx)
+
+	tag, err := q.Exec(ctx, `DELETE FROM processed_events WHERE processed_at < $1`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete processed events: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
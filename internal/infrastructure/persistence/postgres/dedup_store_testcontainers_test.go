@@ -0,0 +1,32 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+func TestDedupStore_Conformance(t *testing.T) {
+	porttest.RunDedupStoreTests(t, func(t *testing.T) porttest.DedupHarness {
+		tc := setupSharedTestDB(t)
+
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000024_create_processed_events.up.sql"))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(context.Background(), string(migration))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(context.Background(), "TRUNCATE processed_events")
+		require.NoError(t, err)
+
+		return porttest.DedupHarness{
+			Store:      NewDedupStore(tc.pool),
+			UnitOfWork: NewUnitOfWork(tc.pool),
+		}
+	})
+}
@@ -0,0 +1,137 @@
+// Package postgres - FailedRequestRepository implementation.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// Compile-time check: FailedRequestRepository implements ports.FailedRequestRepository
+var _ ports.FailedRequestRepository = (*FailedRequestRepository)(nil)
+
+// FailedRequestRepository реализует ports.FailedRequestRepository (таблица failed_requests).
+type FailedRequestRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewFailedRequestRepository создаёт новый FailedRequestRepository.
+func NewFailedRequestRepository(pool *pgxpool.Pool) *FailedRequestRepository {
+	return &FailedRequestRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *FailedRequestRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// Append добавляет образец.
+func (r *FailedRequestRepository) Append(ctx context.Context, sample *entities.FailedRequest) error {
+	q := r.getQuerier(ctx)
+
+	query := `
+		INSERT INTO failed_requests (
+			id, method, route, status_code, request_id, actor_id,
+			request_body, response_body, truncated, occurred_at
+		) VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10)
+	`
+
+	var actorID *uuid.UUID
+	if id := sample.ActorID(); id != uuid.Nil {
+		actorID = &id
+	}
+
+	_, err := q.Exec(ctx, query,
+		sample.ID(),
+		sample.Method(),
+		sample.Route(),
+		sample.StatusCode(),
+		sample.RequestID(),
+		actorID,
+		sample.RequestBody(),
+		sample.ResponseBody(),
+		sample.Truncated(),
+		sample.OccurredAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to append failed request: %w", err)
+	}
+
+	return nil
+}
+
+// List возвращает образцы с фильтрацией и пагинацией, новые первыми.
+func (r *FailedRequestRepository) List(ctx context.Context, filter ports.FailedRequestFilter, offset, limit int) ([]*entities.FailedRequest, error) {
+	q := r.getQuerier(ctx)
+
+	sq := newSelect(`
+		SELECT id, method, route, status_code, request_id, actor_id,
+			request_body, response_body, truncated, occurred_at
+		FROM failed_requests`)
+	whereOpt(sq, "actor_id = ?", filter.ActorID)
+	whereOpt(sq, "route = ?", filter.Route)
+	whereOptFunc(sq, "occurred_at >= ?", filter.OccurredFrom, time.Time.UTC)
+	whereOptFunc(sq, "occurred_at < ?", filter.OccurredTo, time.Time.UTC)
+	query, args := sq.OrderBy("occurred_at DESC, id DESC").Page(offset, limit).SQL()
+
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list failed requests: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*entities.FailedRequest, 0)
+	for rows.Next() {
+		var (
+			id                        uuid.UUID
+			method, route             string
+			statusCode                int
+			requestID                 *string
+			actorID                   *uuid.UUID
+			requestBody, responseBody string
+			truncated                 bool
+			occurredAt                time.Time
+		)
+		if err := rows.Scan(&id, &method, &route, &statusCode, &requestID, &actorID,
+			&requestBody, &responseBody, &truncated, &occurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan failed request row: %w", err)
+		}
+
+		actor := uuid.Nil
+		if actorID != nil {
+			actor = *actorID
+		}
+
+		result = append(result, entities.ReconstructFailedRequest(
+			id, method, route, statusCode, derefString(requestID), actor,
+			requestBody, responseBody, truncated, occurredAt,
+		))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating failed request rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// DeleteBefore удаляет образцы старше cutoff.
+func (r *FailedRequestRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	q := r.getQuerier(ctx)
+
+	tag, err := q.Exec(ctx, `DELETE FROM failed_requests WHERE occurred_at < $1`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete failed requests: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,111 @@
+// Package postgres - FXRateSnapshotRepository implementation.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check: FXRateSnapshotRepository implements ports.FXRateSnapshotRepository
+var _ ports.FXRateSnapshotRepository = (*FXRateSnapshotRepository)(nil)
+
+// FXRateSnapshotRepository реализует ports.FXRateSnapshotRepository (таблица fx_rate_snapshots).
+//
+// Курс передаётся и читается строкой (NUMERIC <-> text), без float.
+type FXRateSnapshotRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewFXRateSnapshotRepository создаёт новый FXRateSnapshotRepository.
+func NewFXRateSnapshotRepository(pool *pgxpool.Pool) *FXRateSnapshotRepository {
+	return &FXRateSnapshotRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *FXRateSnapshotRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// Append сохраняет снимок.
+func (r *FXRateSnapshotRepository) Append(ctx context.Context, snapshot *entities.FXRateSnapshot) error {
+	q := r.getQuerier(ctx)
+
+	query := `
+		INSERT INTO fx_rate_snapshots (id, base, quote, rate, source, fetched_at)
+		VALUES ($1, $2, $3, $4::numeric, $5, $6)
+	`
+
+	_, err := q.Exec(ctx, query,
+		snapshot.ID(),
+		snapshot.Base(),
+		snapshot.Quote(),
+		snapshot.Rate().FloatString(entities.FXRateScale),
+		snapshot.Source(),
+		snapshot.FetchedAt(),
+	)
+	if err != nil {
+		if isUniqueViolation(err, "fx_rate_snapshots_pkey") {
+			return domainErrors.NewDomainError("FX_RATE_SNAPSHOT_EXISTS", "fx rate snapshot already exists", err)
+		}
+		return fmt.Errorf("failed to append fx rate snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID загружает снимок по ID.
+func (r *FXRateSnapshotRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.FXRateSnapshot, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT id, base, quote, rate::text, source, fetched_at
+		FROM fx_rate_snapshots
+		WHERE id = $1
+	`
+
+	var (
+		snapshotID                uuid.UUID
+		base, quote, rate, source string
+		fetchedAt                 time.Time
+	)
+	err := q.QueryRow(ctx, query, id).Scan(&snapshotID, &base, &quote, &rate, &source, &fetchedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErrors.ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("failed to find fx rate snapshot: %w", err)
+	}
+
+	parsed, ok := new(big.Rat).SetString(rate)
+	if !ok {
+		return nil, fmt.Errorf("invalid rate %q in fx rate snapshot %s", rate, snapshotID)
+	}
+
+	return entities.ReconstructFXRateSnapshot(snapshotID, base, quote, parsed, source, fetchedAt), nil
+}
+
+// DeleteBefore удаляет снимки с fetched_at раньше cutoff.
+func (r *FXRateSnapshotRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	q := r.getQuerier(ctx)
+
+	tag, err := q.Exec(ctx, `DELETE FROM fx_rate_snapshots WHERE fetched_at < $1`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete fx rate snapshots: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
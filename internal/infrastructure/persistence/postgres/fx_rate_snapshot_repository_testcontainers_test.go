@@ -0,0 +1,33 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+func TestFXRateSnapshotRepository_Conformance(t *testing.T) {
+	porttest.RunFXRateSnapshotRepositoryTests(t, func(t *testing.T) porttest.FXRateSnapshotHarness {
+		tc := setupSharedTestDB(t)
+		ctx := context.Background()
+
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000029_create_fx_rate_snapshots.up.sql"))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(ctx, string(migration))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(ctx, "TRUNCATE fx_rate_snapshots")
+		require.NoError(t, err)
+
+		return porttest.FXRateSnapshotHarness{
+			Repository: NewFXRateSnapshotRepository(tc.pool),
+			UnitOfWork: NewUnitOfWork(tc.pool),
+		}
+	})
+}
@@ -4,6 +4,7 @@ package postgres
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -44,6 +45,9 @@ const (
 	// Serialization failures (for optimistic locking)
 	pgSerializationFailure = "40001"
 	pgDeadlockDetected     = "40P01"
+
+	// Schema
+	pgUndefinedTable = "42P01"
 )
 
 // isPgError проверяет, является ли ошибка PostgreSQL ошибкой с определённым кодом.
@@ -115,3 +119,28 @@ func isRetryableError(err error) bool {
 
 	return false
 }
+
+// derefString возвращает значение nullable-колонки или пустую строку для NULL.
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// derefInt возвращает значение nullable-колонки или 0 для NULL.
+func derefInt(n *int) int {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
+
+// utcPtr возвращает копию nullable-времени в UTC (nil для NULL).
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
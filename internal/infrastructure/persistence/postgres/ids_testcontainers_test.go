@@ -0,0 +1,131 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/ids"
+)
+
+// idStrategyResult - замер вставки rows строк с одной стратегией ID.
+type idStrategyResult struct {
+	elapsed    time.Duration
+	indexBytes int64
+	tableBytes int64
+}
+
+// measureIDStrategy вставляет rows строк пачками в отдельную таблицу с тем же
+// первичным ключом, что у transactions, и возвращает время и размер индекса.
+func measureIDStrategy(t *testing.T, tc *testContainer, table string, rows int, generate ids.Generator) idStrategyResult {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := tc.pool.Exec(ctx, fmt.Sprintf(`
+		DROP TABLE IF EXISTS %[1]s;
+		CREATE TABLE %[1]s (
+			id UUID PRIMARY KEY,
+			wallet_id UUID NOT NULL,
+			amount NUMERIC(20, 8) NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`, table))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = tc.pool.Exec(context.Background(), "DROP TABLE IF EXISTS "+table)
+	})
+
+	const batch = 1000
+	walletID := uuid.New()
+	started := time.Now()
+	for done := 0; done < rows; done += batch {
+		values := make([][]any, 0, batch)
+		for i := 0; i < batch; i++ {
+			values = append(values, []any{generate(), walletID, "10.00"})
+		}
+		_, err := tc.pool.CopyFrom(ctx, pgx.Identifier{table}, []string{"id", "wallet_id", "amount"}, pgx.CopyFromRows(values))
+		require.NoError(t, err)
+	}
+	elapsed := time.Since(started)
+
+	var result idStrategyResult
+	result.elapsed = elapsed
+	err = tc.pool.QueryRow(ctx,
+		`SELECT pg_relation_size($1::regclass), pg_relation_size($2::regclass)`,
+		table+"_pkey", table,
+	).Scan(&result.indexBytes, &result.tableBytes)
+	require.NoError(t, err)
+	return result
+}
+
+// TestTransactionIDs_InsertMeasurement сравнивает UUIDv4 и UUIDv7 на вставке:
+// случайные ключи раскалывают страницы B-tree индекса (fill ~70%), а
+// упорядоченные по времени дописываются в правый край индекса.
+func TestTransactionIDs_InsertMeasurement(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping insert measurement in short mode")
+	}
+	tc := setupSharedTestDB(t)
+	const rows = 200_000
+
+	v4 := measureIDStrategy(t, tc, "id_bench_v4", rows, uuid.New)
+	v7 := measureIDStrategy(t, tc, "id_bench_v7", rows, ids.V7)
+
+	for name, r := range map[string]idStrategyResult{"uuidv4": v4, "uuidv7": v7} {
+		t.Logf("%s: %d rows in %s (%.0f rows/s), pkey %d KiB, table %d KiB",
+			name, rows, r.elapsed, float64(rows)/r.elapsed.Seconds(), r.indexBytes/1024, r.tableBytes/1024)
+	}
+
+	// Размер индекса детерминирован в отличие от времени: UUIDv7 даёт
+	// заметно более плотный первичный ключ.
+	assert.Less(t, v7.indexBytes, v4.indexBytes*9/10, "UUIDv7 primary key should be at least 10% smaller")
+}
+
+// TestTransactionIDs_MixedVersions проверяет, что транзакции с ID обеих версий
+// (UUIDv4 до перехода, UUIDv7 после) сохраняются и читаются одинаково.
+func TestTransactionIDs_MixedVersions(t *testing.T) {
+	tc := setupSharedTestDB(t)
+	ctx := context.Background()
+
+	userRepo := NewUserRepository(tc.pool)
+	walletRepo := NewWalletRepository(tc.pool)
+	txRepo := NewTransactionRepository(tc.pool)
+
+	user, _ := entities.NewUser("ids@example.com", "IDs User")
+	require.NoError(t, userRepo.Save(ctx, user))
+	wallet, _ := entities.NewWallet(user.ID(), valueobjects.USD)
+	require.NoError(t, walletRepo.Save(ctx, wallet))
+
+	newTransaction := func() *entities.Transaction {
+		amount, _ := valueobjects.NewMoney("10.00", valueobjects.USD)
+		tx, err := entities.NewTransaction(wallet.ID(), uuid.NewString(), entities.TransactionTypeDeposit, amount, "ids")
+		require.NoError(t, err)
+		require.NoError(t, txRepo.Save(ctx, tx))
+		return tx
+	}
+
+	restore := ids.SetGenerator(uuid.New)
+	legacy := newTransaction()
+	restore()
+	created := newTransaction()
+
+	assert.Equal(t, uuid.Version(4), legacy.ID().Version())
+	assert.Equal(t, uuid.Version(7), created.ID().Version())
+
+	for _, id := range []uuid.UUID{legacy.ID(), created.ID()} {
+		parsed, err := uuid.Parse(id.String())
+		require.NoError(t, err)
+		found, err := txRepo.FindByID(ctx, parsed)
+		require.NoError(t, err)
+		assert.Equal(t, id, found.ID())
+	}
+}
@@ -0,0 +1,154 @@
+// Package postgres - IncidentRepository implementation.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check: IncidentRepository implements ports.IncidentRepository
+var _ ports.IncidentRepository = (*IncidentRepository)(nil)
+
+// incidentColumns - колонки incidents в порядке scanIncident.
+const incidentColumns = `id, title, severity, components, started_at, resolved_at, created_by, resolved_by, created_at`
+
+// IncidentRepository реализует ports.IncidentRepository (таблица incidents).
+type IncidentRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewIncidentRepository создаёт новый IncidentRepository.
+func NewIncidentRepository(pool *pgxpool.Pool) *IncidentRepository {
+	return &IncidentRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *IncidentRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// Save сохраняет инцидент (upsert по ID). Меняться может только решение.
+func (r *IncidentRepository) Save(ctx context.Context, incident *entities.Incident) error {
+	q := r.getQuerier(ctx)
+
+	components := make([]string, 0, len(incident.Components()))
+	for _, component := range incident.Components() {
+		components = append(components, string(component))
+	}
+
+	query := `
+		INSERT INTO incidents (` + incidentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO UPDATE SET
+			resolved_at = EXCLUDED.resolved_at,
+			resolved_by = EXCLUDED.resolved_by
+	`
+
+	_, err := q.Exec(ctx, query,
+		incident.ID(),
+		incident.Title(),
+		string(incident.Severity()),
+		components,
+		incident.StartedAt(),
+		incident.ResolvedAt(),
+		incident.CreatedBy(),
+		incident.ResolvedBy(),
+		incident.CreatedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save incident: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID загружает инцидент по ID.
+func (r *IncidentRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.Incident, error) {
+	q := r.getQuerier(ctx)
+
+	query := `SELECT ` + incidentColumns + ` FROM incidents WHERE id = $1`
+
+	incident, err := scanIncident(q.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErrors.ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("failed to find incident: %w", err)
+	}
+
+	return incident, nil
+}
+
+// ListVisible возвращает активные инциденты и решённые не раньше resolvedSince.
+func (r *IncidentRepository) ListVisible(ctx context.Context, resolvedSince time.Time) ([]*entities.Incident, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE resolved_at IS NULL OR resolved_at >= $1
+		ORDER BY started_at DESC, id
+	`
+
+	rows, err := q.Query(ctx, query, resolvedSince)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incidents: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*entities.Incident, 0)
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident row: %w", err)
+		}
+		result = append(result, incident)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate incidents: %w", err)
+	}
+
+	return result, nil
+}
+
+// scanIncident читает строку incidents (колонки incidentColumns).
+func scanIncident(row pgx.Row) (*entities.Incident, error) {
+	var (
+		id         uuid.UUID
+		title      string
+		severity   string
+		components []string
+		startedAt  time.Time
+		resolvedAt *time.Time
+		createdBy  uuid.UUID
+		resolvedBy *uuid.UUID
+		createdAt  time.Time
+	)
+	if err := row.Scan(&id, &title, &severity, &components, &startedAt, &resolvedAt, &createdBy, &resolvedBy, &createdAt); err != nil {
+		return nil, err
+	}
+
+	statusComponents := make([]entities.StatusComponent, len(components))
+	for i, component := range components {
+		statusComponents[i] = entities.StatusComponent(component)
+	}
+
+	return entities.ReconstructIncident(
+		id, title, entities.IncidentSeverity(severity), statusComponents,
+		startedAt, resolvedAt, createdBy, resolvedBy, createdAt,
+	), nil
+}
@@ -0,0 +1,31 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+func TestIncidentRepository_Conformance(t *testing.T) {
+	porttest.RunIncidentRepositoryTests(t, func(t *testing.T) ports.IncidentRepository {
+		tc := setupSharedTestDB(t)
+		ctx := context.Background()
+
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000037_create_incidents.up.sql"))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(ctx, string(migration))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(ctx, "DELETE FROM incidents")
+		require.NoError(t, err)
+
+		return NewIncidentRepository(tc.pool)
+	})
+}
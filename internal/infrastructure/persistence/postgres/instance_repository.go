@@ -0,0 +1,39 @@
+// Package postgres - InstanceRepository implementation.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check: InstanceRepository implements ports.InstanceRepository
+var _ ports.InstanceRepository = (*InstanceRepository)(nil)
+
+// InstanceRepository реализует ports.InstanceRepository (таблица instances).
+type InstanceRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewInstanceRepository создаёт новый InstanceRepository.
+func NewInstanceRepository(pool *pgxpool.Pool) *InstanceRepository {
+	return &InstanceRepository{pool: pool}
+}
+
+// Heartbeat записывает время последнего heartbeat экземпляра.
+func (r *InstanceRepository) Heartbeat(ctx context.Context, instance string, at time.Time) error {
+	query := `
+		INSERT INTO instances (instance_id, heartbeat_at)
+		VALUES ($1, $2)
+		ON CONFLICT (instance_id) DO UPDATE SET heartbeat_at = EXCLUDED.heartbeat_at
+	`
+
+	if _, err := withRequestStats(ctx, r.pool).Exec(ctx, query, instance, at.UTC()); err != nil {
+		return fmt.Errorf("failed to save instance heartbeat: %w", err)
+	}
+	return nil
+}
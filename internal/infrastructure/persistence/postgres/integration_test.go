@@ -24,6 +24,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"os"
 	"strconv"
 	"testing"
@@ -32,6 +33,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/Haleralex/wallethub/internal/application/ports"
 	"github.com/Haleralex/wallethub/internal/domain/entities"
 	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
 	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
@@ -350,6 +352,81 @@ func TestUnitOfWork_Execute_Rollback(t *testing.T) {
 	}
 }
 
+// Воспроизводит баг с захваченным ctx: запись через внешний ctx внутри
+// Execute шла мимо транзакции и переживала её откат.
+func TestUnitOfWork_Execute_CapturedContext(t *testing.T) {
+	ctx := context.Background()
+	cleanupUsers(t, ctx)
+
+	uow := NewUnitOfWork(testPool)
+	userRepo := NewUserRepository(testPool)
+
+	t.Run("Logged", func(t *testing.T) {
+		guard := ports.NewUnitOfWorkGuard(nil, false)
+		guarded := ports.WithUnitOfWorkGuard(ctx, guard)
+
+		user, err := entities.NewUser("captured@test.com", "Captured Context")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = uow.Execute(guarded, func(txCtx context.Context) error {
+			if err := userRepo.Save(guarded, user); err != nil { // должно быть txCtx
+				return err
+			}
+			return domainErrors.NewBusinessRuleViolation("TEST_ERROR", "intentional error", nil)
+		})
+		if err == nil {
+			t.Fatal("Expected error from UoW")
+		}
+
+		if guard.Violations() != 1 {
+			t.Errorf("Expected 1 violation, got %d", guard.Violations())
+		}
+		// Сам баг: запись мимо транзакции не откатилась
+		if _, err := userRepo.FindByID(ctx, user.ID()); err != nil {
+			t.Errorf("Write outside the transaction should survive rollback: %v", err)
+		}
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		guarded := ports.WithUnitOfWorkGuard(ctx, ports.NewUnitOfWorkGuard(nil, true))
+
+		user, err := entities.NewUser("strict@test.com", "Strict Guard")
+		if err != nil {
+			t.Fatal(err)
+		}
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected strict guard to panic")
+				}
+			}()
+			_ = uow.Execute(guarded, func(txCtx context.Context) error {
+				return userRepo.Save(guarded, user)
+			})
+		}()
+
+		if _, err := userRepo.FindByID(ctx, user.ID()); err == nil {
+			t.Error("Strict guard must stop the write outside the transaction")
+		}
+	})
+}
+
+func TestUnitOfWork_Execute_Nested(t *testing.T) {
+	ctx := ports.WithUnitOfWorkGuard(context.Background(), ports.NewUnitOfWorkGuard(nil, true))
+	uow := NewUnitOfWork(testPool)
+
+	err := uow.Execute(ctx, func(txCtx context.Context) error {
+		if err := uow.Execute(txCtx, func(context.Context) error { return nil }); err != nil {
+			return err
+		}
+		return uow.Execute(ctx, func(context.Context) error { return nil })
+	})
+	if !errors.Is(err, ports.ErrNestedUnitOfWork) {
+		t.Errorf("Expected ErrNestedUnitOfWork, got %v", err)
+	}
+}
+
 // ============================================
 // WalletRepository Integration Tests
 // ============================================
@@ -0,0 +1,175 @@
+// Package postgres - JobRepository implementation.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check: JobRepository implements ports.JobRepository
+var _ ports.JobRepository = (*JobRepository)(nil)
+
+// JobRepository реализует ports.JobRepository (таблицы job_locks, job_runs).
+//
+// Блокировка - строка job_locks с lease, а не advisory lock: она не
+// привязана к соединению пула и видна в БД при разборе инцидентов.
+type JobRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewJobRepository создаёт новый JobRepository.
+func NewJobRepository(pool *pgxpool.Pool) *JobRepository {
+	return &JobRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *JobRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// TryLock берёт блокировку, если строки нет или её lease истёк.
+// Перехват истёкшего lease - один UPSERT, поэтому два экземпляра
+// не могут взять блокировку одновременно.
+func (r *JobRepository) TryLock(ctx context.Context, job string, owner uuid.UUID, lease time.Duration) (bool, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		INSERT INTO job_locks (job_name, owner, locked_until)
+		VALUES ($1, $2, NOW() + make_interval(secs => $3))
+		ON CONFLICT (job_name) DO UPDATE
+		SET owner = EXCLUDED.owner, locked_until = EXCLUDED.locked_until
+		WHERE job_locks.locked_until <= NOW()
+		RETURNING owner
+	`
+
+	var locked uuid.UUID
+	err := q.QueryRow(ctx, query, job, owner, lease.Seconds()).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to lock job: %w", err)
+	}
+
+	return true, nil
+}
+
+// Unlock снимает блокировку, если её держит owner.
+func (r *JobRepository) Unlock(ctx context.Context, job string, owner uuid.UUID) error {
+	q := r.getQuerier(ctx)
+
+	_, err := q.Exec(ctx, `DELETE FROM job_locks WHERE job_name = $1 AND owner = $2`, job, owner)
+	if err != nil {
+		return fmt.Errorf("failed to unlock job: %w", err)
+	}
+
+	return nil
+}
+
+// StartRun записывает начало прогона.
+func (r *JobRepository) StartRun(ctx context.Context, run *ports.JobRun) error {
+	q := r.getQuerier(ctx)
+
+	query := `
+		INSERT INTO job_runs (id, job_name, trigger, instance, status, items_processed, started_at)
+		VALUES ($1, $2, $3, $4, $5, 0, $6)
+	`
+
+	_, err := q.Exec(ctx, query,
+		run.ID,
+		run.Job,
+		string(run.Trigger),
+		run.Instance,
+		string(ports.JobRunRunning),
+		run.StartedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert job run: %w", err)
+	}
+
+	return nil
+}
+
+// FinishRun записывает итог прогона.
+func (r *JobRepository) FinishRun(ctx context.Context, run *ports.JobRun) error {
+	q := r.getQuerier(ctx)
+
+	var finishedAt *time.Time
+	if run.FinishedAt != nil {
+		t := run.FinishedAt.UTC()
+		finishedAt = &t
+	}
+
+	query := `
+		UPDATE job_runs
+		SET status = $2, error = NULLIF($3, ''), items_processed = $4, finished_at = $5
+		WHERE id = $1
+	`
+
+	_, err := q.Exec(ctx, query, run.ID, string(run.Status), run.Error, run.ItemsProcessed, finishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update job run: %w", err)
+	}
+
+	return nil
+}
+
+// ListRuns возвращает последние прогоны задачи, новые первыми.
+func (r *JobRepository) ListRuns(ctx context.Context, job string, limit int) ([]*ports.JobRun, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT id, job_name, trigger, instance, status, error, items_processed, started_at, finished_at
+		FROM job_runs
+		WHERE job_name = $1
+		ORDER BY started_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := q.Query(ctx, query, job, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job runs: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*ports.JobRun, 0)
+	for rows.Next() {
+		var (
+			run             ports.JobRun
+			trigger, status string
+			runError        *string
+			finishedAt      *time.Time
+		)
+		if err := rows.Scan(&run.ID, &run.Job, &trigger, &run.Instance, &status, &runError,
+			&run.ItemsProcessed, &run.StartedAt, &finishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan job run row: %w", err)
+		}
+
+		run.Trigger = ports.JobTrigger(trigger)
+		run.Status = ports.JobRunStatus(status)
+		run.Error = derefString(runError)
+		run.StartedAt = run.StartedAt.UTC()
+		if finishedAt != nil {
+			t := finishedAt.UTC()
+			run.FinishedAt = &t
+		}
+		result = append(result, &run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating job run rows: %w", err)
+	}
+
+	return result, nil
+}
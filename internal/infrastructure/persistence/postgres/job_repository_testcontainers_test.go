@@ -0,0 +1,101 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+	"github.com/Haleralex/wallethub/internal/infrastructure/scheduler"
+)
+
+// setupJobDB возвращает БД со схемой job_locks / job_runs без записей.
+func setupJobDB(t *testing.T) *testContainer {
+	t.Helper()
+	ctx := context.Background()
+
+	tc := setupSharedTestDB(t)
+
+	migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000018_create_job_runs.up.sql"))
+	require.NoError(t, err)
+	_, err = tc.pool.Exec(ctx, string(migration))
+	require.NoError(t, err)
+
+	_, err = tc.pool.Exec(ctx, "TRUNCATE TABLE job_locks, job_runs")
+	require.NoError(t, err)
+
+	return tc
+}
+
+func TestJobRepository_Integration_Conformance(t *testing.T) {
+	porttest.RunJobRepositoryTests(t, func(t *testing.T) ports.JobRepository {
+		return NewJobRepository(setupJobDB(t).pool)
+	})
+}
+
+// Два планировщика над одной БД - как два экземпляра приложения
+func TestJobRepository_Integration_NoOverlapAcrossSchedulers(t *testing.T) {
+	tc := setupJobDB(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	var running, maxSeen, calls atomic.Int32
+	job := scheduler.Job{
+		Name:     "overlap-check",
+		Schedule: "@every 1s",
+		Timeout:  time.Minute,
+		// Прогон дольше интервала: без блокировки экземпляры пересеклись бы
+		Handler: func(ctx context.Context) (int, error) {
+			calls.Add(1)
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				seen := maxSeen.Load()
+				if n <= seen || maxSeen.CompareAndSwap(seen, n) {
+					break
+				}
+			}
+			time.Sleep(1500 * time.Millisecond)
+			return 1, nil
+		},
+	}
+
+	var instances []*scheduler.Scheduler
+	for _, name := range []string{"instance-a", "instance-b"} {
+		s := scheduler.New(logger, NewJobRepository(tc.pool), scheduler.Config{Instance: name})
+		require.NoError(t, s.Register(job))
+		instances = append(instances, s)
+	}
+	for _, s := range instances {
+		s.Start()
+	}
+
+	time.Sleep(4 * time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, s := range instances {
+		require.NoError(t, s.Stop(ctx))
+	}
+
+	assert.GreaterOrEqual(t, calls.Load(), int32(1))
+	assert.Equal(t, int32(1), maxSeen.Load(), "job must never run on two instances at once")
+
+	runs, err := NewJobRepository(tc.pool).ListRuns(ctx, "overlap-check", 100)
+	require.NoError(t, err)
+	assert.Len(t, runs, int(calls.Load()))
+	for i := 1; i < len(runs); i++ {
+		require.NotNil(t, runs[i].FinishedAt)
+		assert.False(t, runs[i].FinishedAt.After(runs[i-1].StartedAt), "runs must not overlap")
+	}
+}
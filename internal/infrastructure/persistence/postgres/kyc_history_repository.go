@@ -0,0 +1,107 @@
+// Package postgres - KYCHistoryRepository implementation.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check: KYCHistoryRepository implements ports.KYCHistoryRepository
+var _ ports.KYCHistoryRepository = (*KYCHistoryRepository)(nil)
+
+// KYCHistoryRepository реализует ports.KYCHistoryRepository (таблица user_kyc_history).
+type KYCHistoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewKYCHistoryRepository создаёт новый KYCHistoryRepository.
+func NewKYCHistoryRepository(pool *pgxpool.Pool) *KYCHistoryRepository {
+	return &KYCHistoryRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *KYCHistoryRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// Append добавляет запись в историю.
+func (r *KYCHistoryRepository) Append(ctx context.Context, transition *entities.KYCTransition) error {
+	q := r.getQuerier(ctx)
+
+	query := `
+		INSERT INTO user_kyc_history (id, user_id, from_status, to_status, reason, actor_id, occurred_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)
+	`
+
+	_, err := q.Exec(ctx, query,
+		transition.ID(),
+		transition.UserID(),
+		string(transition.FromStatus()),
+		string(transition.ToStatus()),
+		transition.Reason(),
+		transition.ActorID(),
+		transition.OccurredAt(),
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domainErrors.NewDomainError("USER_NOT_FOUND", "user not found", err)
+		}
+		return fmt.Errorf("failed to append KYC history: %w", err)
+	}
+
+	return nil
+}
+
+// ListByUserID возвращает историю пользователя в хронологическом порядке.
+func (r *KYCHistoryRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.KYCTransition, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT id, user_id, from_status, to_status, reason, actor_id, occurred_at
+		FROM user_kyc_history
+		WHERE user_id = $1
+		ORDER BY occurred_at ASC, id ASC
+	`
+
+	rows, err := q.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list KYC history: %w", err)
+	}
+	defer rows.Close()
+
+	transitions := make([]*entities.KYCTransition, 0)
+	for rows.Next() {
+		var (
+			id, ownerID, actorID uuid.UUID
+			fromStatus, toStatus string
+			reason               *string
+			occurredAt           time.Time
+		)
+		if err := rows.Scan(&id, &ownerID, &fromStatus, &toStatus, &reason, &actorID, &occurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan KYC history row: %w", err)
+		}
+
+		transitions = append(transitions, entities.ReconstructKYCTransition(
+			id, ownerID,
+			entities.KYCStatus(fromStatus), entities.KYCStatus(toStatus),
+			derefString(reason), actorID, occurredAt,
+		))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating KYC history rows: %w", err)
+	}
+
+	return transitions, nil
+}
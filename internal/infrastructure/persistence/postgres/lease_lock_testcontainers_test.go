@@ -0,0 +1,69 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+	"github.com/Haleralex/wallethub/internal/infrastructure/leaselock"
+)
+
+// setupLeaseDB возвращает БД с последовательностью lease_fencing_tokens.
+func setupLeaseDB(t *testing.T) *testContainer {
+	t.Helper()
+
+	tc := setupSharedTestDB(t)
+
+	migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000034_create_lease_fencing_tokens.up.sql"))
+	require.NoError(t, err)
+	_, err = tc.pool.Exec(context.Background(), string(migration))
+	require.NoError(t, err)
+
+	return tc
+}
+
+// breakAdvisoryLocks обрывает сессии, держащие advisory lock, - как
+// падение экземпляра или разрыв соединения с pgBouncer.
+func breakAdvisoryLocks(t *testing.T, tc *testContainer) {
+	t.Helper()
+
+	var terminated int
+	err := tc.pool.QueryRow(context.Background(), `
+		SELECT count(pg_terminate_backend(pid))
+		FROM pg_locks
+		WHERE locktype = 'advisory' AND granted AND pid <> pg_backend_pid()
+	`).Scan(&terminated)
+	require.NoError(t, err)
+	require.Positive(t, terminated, "the lease must be held by some session")
+}
+
+func TestLeaseLock_Integration_Conformance(t *testing.T) {
+	for _, mode := range []leaselock.PostgresMode{leaselock.PostgresModeSession, leaselock.PostgresModeXact} {
+		t.Run(string(mode), func(t *testing.T) {
+			porttest.RunLeaseLockTests(t, func(t *testing.T) porttest.LeaseLockHarness {
+				tc := setupLeaseDB(t)
+				return porttest.LeaseLockHarness{
+					Lock:  leaselock.NewPostgres(tc.pool, mode),
+					Other: leaselock.NewPostgres(tc.pool, mode),
+					Break: func(t *testing.T, _ string) { breakAdvisoryLocks(t, tc) },
+				}
+			})
+		})
+	}
+}
+
+// Прямое соединение без pgBouncer - session
+func TestLeaseLock_Integration_DetectMode(t *testing.T) {
+	tc := setupLeaseDB(t)
+
+	mode, err := leaselock.DetectPostgresMode(context.Background(), tc.pool)
+	require.NoError(t, err)
+	assert.Equal(t, leaselock.PostgresModeSession, mode)
+}
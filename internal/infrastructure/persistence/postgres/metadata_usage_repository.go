@@ -0,0 +1,120 @@
+// Package postgres - MetadataUsageRepository implementation.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check: MetadataUsageRepository implements ports.MetadataUsageRepository
+var _ ports.MetadataUsageRepository = (*MetadataUsageRepository)(nil)
+
+// MetadataUsageRepository реализует ports.MetadataUsageRepository
+// (таблицы wallet_metadata_usage и transaction_metadata_usage).
+//
+// Строка транзакции и счётчик кошелька меняются одним запросом, поэтому
+// счётчик точен и вне UnitOfWork; параллельные учёты одного кошелька
+// сериализуются блокировкой строки счётчика.
+type MetadataUsageRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewMetadataUsageRepository создаёт новый MetadataUsageRepository.
+func NewMetadataUsageRepository(pool *pgxpool.Pool) *MetadataUsageRepository {
+	return &MetadataUsageRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *MetadataUsageRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// AddTransactionMetadata учитывает metadata транзакции и возвращает новый
+// объём кошелька.
+func (r *MetadataUsageRepository) AddTransactionMetadata(ctx context.Context, walletID, transactionID uuid.UUID, bytes int64) (int64, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		WITH recorded AS (
+			INSERT INTO transaction_metadata_usage (transaction_id, wallet_id, metadata_bytes)
+			VALUES ($1, $2, $3)
+		)
+		INSERT INTO wallet_metadata_usage (wallet_id, metadata_bytes, updated_at)
+		VALUES ($2, $3, NOW())
+		ON CONFLICT (wallet_id) DO UPDATE SET
+			metadata_bytes = wallet_metadata_usage.metadata_bytes + EXCLUDED.metadata_bytes,
+			updated_at = EXCLUDED.updated_at
+		RETURNING metadata_bytes
+	`
+
+	var used int64
+	if err := q.QueryRow(ctx, query, transactionID, walletID, bytes).Scan(&used); err != nil {
+		if isForeignKeyViolation(err) {
+			return 0, domainErrors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", err)
+		}
+		if isUniqueViolation(err, "transaction_metadata_usage_pkey") {
+			return 0, fmt.Errorf("metadata of transaction %s is already recorded: %w", transactionID, err)
+		}
+		return 0, fmt.Errorf("failed to record metadata usage: %w", err)
+	}
+
+	return used, nil
+}
+
+// ReleaseTransactionMetadata удаляет учёт транзакций и вычитает его из
+// счётчиков их кошельков.
+func (r *MetadataUsageRepository) ReleaseTransactionMetadata(ctx context.Context, transactionIDs []uuid.UUID) error {
+	if len(transactionIDs) == 0 {
+		return nil
+	}
+
+	q := r.getQuerier(ctx)
+
+	query := `
+		WITH released AS (
+			DELETE FROM transaction_metadata_usage
+			WHERE transaction_id = ANY($1)
+			RETURNING wallet_id, metadata_bytes
+		), totals AS (
+			SELECT wallet_id, SUM(metadata_bytes) AS metadata_bytes
+			FROM released
+			GROUP BY wallet_id
+		)
+		UPDATE wallet_metadata_usage u SET
+			metadata_bytes = u.metadata_bytes - t.metadata_bytes,
+			updated_at = NOW()
+		FROM totals t
+		WHERE u.wallet_id = t.wallet_id
+	`
+
+	if _, err := q.Exec(ctx, query, transactionIDs); err != nil {
+		return fmt.Errorf("failed to release metadata usage: %w", err)
+	}
+	return nil
+}
+
+// MetadataUsage возвращает объём metadata кошелька (0 без записей).
+func (r *MetadataUsageRepository) MetadataUsage(ctx context.Context, walletID uuid.UUID) (int64, error) {
+	q := r.getQuerier(ctx)
+
+	var used int64
+	err := q.QueryRow(ctx, `SELECT metadata_bytes FROM wallet_metadata_usage WHERE wallet_id = $1`, walletID).Scan(&used)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to load metadata usage: %w", err)
+	}
+	return used, nil
+}
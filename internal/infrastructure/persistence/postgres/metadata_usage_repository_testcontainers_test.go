@@ -0,0 +1,31 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+func TestMetadataUsageRepository_Conformance(t *testing.T) {
+	porttest.RunMetadataUsageRepositoryTests(t, func(t *testing.T) porttest.MetadataUsageHarness {
+		tc := setupSharedTestDB(t)
+
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000041_create_metadata_usage.up.sql"))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(context.Background(), string(migration))
+		require.NoError(t, err)
+
+		return porttest.MetadataUsageHarness{
+			Repositories: newConformanceRepositories(t),
+			Usage:        NewMetadataUsageRepository(tc.pool),
+			UnitOfWork:   NewUnitOfWork(tc.pool),
+		}
+	})
+}
@@ -0,0 +1,112 @@
+// Package postgres - OnboardingRepository implementation.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check: OnboardingRepository implements ports.OnboardingRepository
+var _ ports.OnboardingRepository = (*OnboardingRepository)(nil)
+
+// OnboardingRepository реализует ports.OnboardingRepository (таблица onboardings).
+type OnboardingRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOnboardingRepository создаёт новый OnboardingRepository.
+func NewOnboardingRepository(pool *pgxpool.Pool) *OnboardingRepository {
+	return &OnboardingRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *OnboardingRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// Save вставляет запись; занятый ключ - ErrEntityAlreadyExists.
+func (r *OnboardingRepository) Save(ctx context.Context, record *ports.OnboardingRecord) error {
+	q := r.getQuerier(ctx)
+
+	query := `
+		INSERT INTO onboardings (
+			idempotency_key, partner_id, request_hash, user_id,
+			wallet_ids, transaction_ids, kyc_auto_approved, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := q.Exec(ctx, query,
+		record.IdempotencyKey,
+		record.PartnerID,
+		record.RequestHash,
+		record.UserID,
+		nonNilUUIDs(record.WalletIDs),
+		nonNilUUIDs(record.TransactionIDs),
+		record.KYCAutoApproved,
+		record.CreatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err, "onboardings_pkey") {
+			return fmt.Errorf("%w: onboarding %s", domainErrors.ErrEntityAlreadyExists, record.IdempotencyKey)
+		}
+		if isForeignKeyViolation(err) {
+			return domainErrors.NewDomainError("USER_NOT_FOUND", "user not found", err)
+		}
+		return fmt.Errorf("failed to save onboarding: %w", err)
+	}
+
+	return nil
+}
+
+// FindByIdempotencyKey загружает запись по ключу запроса.
+func (r *OnboardingRepository) FindByIdempotencyKey(ctx context.Context, key uuid.UUID) (*ports.OnboardingRecord, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT idempotency_key, partner_id, request_hash, user_id,
+		       wallet_ids, transaction_ids, kyc_auto_approved, created_at
+		FROM onboardings
+		WHERE idempotency_key = $1
+	`
+
+	var record ports.OnboardingRecord
+	err := q.QueryRow(ctx, query, key).Scan(
+		&record.IdempotencyKey,
+		&record.PartnerID,
+		&record.RequestHash,
+		&record.UserID,
+		&record.WalletIDs,
+		&record.TransactionIDs,
+		&record.KYCAutoApproved,
+		&record.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErrors.ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("failed to find onboarding: %w", err)
+	}
+
+	return &record, nil
+}
+
+// nonNilUUIDs заменяет nil на пустой slice: pgx кодирует nil slice как NULL,
+// а колонки массивов NOT NULL.
+func nonNilUUIDs(ids []uuid.UUID) []uuid.UUID {
+	if ids == nil {
+		return []uuid.UUID{}
+	}
+	return ids
+}
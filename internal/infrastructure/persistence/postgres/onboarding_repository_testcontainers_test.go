@@ -0,0 +1,30 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+func TestOnboardingRepository_Conformance(t *testing.T) {
+	porttest.RunOnboardingRepositoryTests(t, func(t *testing.T) porttest.OnboardingHarness {
+		tc := setupSharedTestDB(t)
+
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000045_create_onboardings.up.sql"))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(context.Background(), string(migration))
+		require.NoError(t, err)
+
+		return porttest.OnboardingHarness{
+			Repositories: newConformanceRepositories(t),
+			Onboardings:  NewOnboardingRepository(tc.pool),
+		}
+	})
+}
@@ -0,0 +1,133 @@
+// Package postgres - OperationSwitchRepository implementation.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// Compile-time check: OperationSwitchRepository implements ports.OperationSwitchRepository
+var _ ports.OperationSwitchRepository = (*OperationSwitchRepository)(nil)
+
+// operationSwitchKeyPrefix - префикс ключей выключателей в system_settings.
+const operationSwitchKeyPrefix = "operation_switch."
+
+// operationSwitchValue - JSON значение выключателя в system_settings.value.
+type operationSwitchValue struct {
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason"`
+}
+
+// OperationSwitchRepository реализует ports.OperationSwitchRepository
+// (строки operation_switch.<TYPE> таблицы system_settings).
+type OperationSwitchRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOperationSwitchRepository создаёт новый OperationSwitchRepository.
+func NewOperationSwitchRepository(pool *pgxpool.Pool) *OperationSwitchRepository {
+	return &OperationSwitchRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *OperationSwitchRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// Save сохраняет состояние выключателя (upsert по ключу).
+func (r *OperationSwitchRepository) Save(ctx context.Context, sw *entities.OperationSwitch) error {
+	q := r.getQuerier(ctx)
+
+	value, err := json.Marshal(operationSwitchValue{Enabled: sw.Enabled(), Reason: sw.Reason()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal operation switch: %w", err)
+	}
+
+	var updatedBy *uuid.UUID
+	if id := sw.UpdatedBy(); id != uuid.Nil {
+		updatedBy = &id
+	}
+
+	query := `
+		INSERT INTO system_settings (key, value, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE SET
+			value = EXCLUDED.value,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err = q.Exec(ctx, query,
+		operationSwitchKeyPrefix+string(sw.Operation()),
+		value,
+		updatedBy,
+		sw.UpdatedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save operation switch: %w", err)
+	}
+
+	return nil
+}
+
+// FindAll возвращает все сохранённые выключатели по порядку операций.
+func (r *OperationSwitchRepository) FindAll(ctx context.Context) ([]*entities.OperationSwitch, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT key, value, updated_by, updated_at
+		FROM system_settings
+		WHERE key LIKE 'operation_switch.%'
+		ORDER BY key
+	`
+
+	rows, err := q.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list operation switches: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*entities.OperationSwitch, 0)
+	for rows.Next() {
+		var (
+			key       string
+			raw       []byte
+			updatedBy *uuid.UUID
+			updatedAt time.Time
+		)
+		if err := rows.Scan(&key, &raw, &updatedBy, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan operation switch row: %w", err)
+		}
+
+		var value operationSwitchValue
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal operation switch %s: %w", key, err)
+		}
+
+		actor := uuid.Nil
+		if updatedBy != nil {
+			actor = *updatedBy
+		}
+
+		operation := entities.TransactionType(strings.TrimPrefix(key, operationSwitchKeyPrefix))
+		result = append(result, entities.ReconstructOperationSwitch(operation, value.Enabled, value.Reason, actor, updatedAt))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate operation switches: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,31 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+func TestOperationSwitchRepository_Conformance(t *testing.T) {
+	porttest.RunOperationSwitchRepositoryTests(t, func(t *testing.T) ports.OperationSwitchRepository {
+		tc := setupSharedTestDB(t)
+		ctx := context.Background()
+
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000028_create_system_settings.up.sql"))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(ctx, string(migration))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(ctx, "DELETE FROM system_settings")
+		require.NoError(t, err)
+
+		return NewOperationSwitchRepository(tc.pool)
+	})
+}
@@ -28,57 +28,80 @@ var _ ports.EventPublisher = (*OutboxRepository)(nil) // OutboxRepository так
 
 // OutboxRepository реализует ports.OutboxRepository.
 type OutboxRepository struct {
-	pool *pgxpool.Pool
+	pool            *pgxpool.Pool
+	producerVersion string // Версия сборки для producer_version ("" - NULL)
+	priorities      ports.OutboxPriorities
 }
 
 // NewOutboxRepository создаёт новый OutboxRepository.
-func NewOutboxRepository(pool *pgxpool.Pool) *OutboxRepository {
-	return &OutboxRepository{pool: pool}
+// producerVersion записывается в каждое сохраняемое событие
+// (ports.BuildInfo.ProducerVersion); "" - без версии.
+// priorities задаёт полосу доставки по типу события; nil - маппинг по умолчанию.
+func NewOutboxRepository(pool *pgxpool.Pool, producerVersion string, priorities ports.OutboxPriorities) *OutboxRepository {
+	if priorities == nil {
+		priorities = ports.DefaultOutboxPriorities()
+	}
+	return &OutboxRepository{pool: pool, producerVersion: producerVersion, priorities: priorities}
 }
 
 // getQuerier возвращает querier из context или pool.
 func (r *OutboxRepository) getQuerier(ctx context.Context) querier {
 	if tx := extractTx(ctx); tx != nil {
-		return tx
+		return withRequestStats(ctx, tx)
 	}
-	return r.pool
+	return withRequestStats(ctx, r.pool)
 }
 
 // Save сохраняет событие в outbox таблицу.
 // Должно выполняться в той же транзакции, что и бизнес-операция!
 func (r *OutboxRepository) Save(ctx context.Context, event events.DomainEvent) error {
-	q := r.getQuerier(ctx)
-
-	// Сериализуем событие в JSON
-	payload, err := serializeEvent(event)
-	if err != nil {
-		return fmt.Errorf("failed to serialize event: %w", err)
-	}
+	return r.insertEvents(ctx, []events.DomainEvent{event})
+}
 
-	// Определяем aggregate type из типа события
-	aggregateType := getAggregateType(event.EventType())
+// insertEvents сохраняет события одним INSERT - batch атомарен даже вне транзакции.
+// Порядок сохраняется через sequence (BIGSERIAL): строки VALUES получают
+// возрастающие значения в порядке слайса.
+func (r *OutboxRepository) insertEvents(ctx context.Context, eventsList []events.DomainEvent) error {
+	const columns = 11
 
 	query := `
 		INSERT INTO outbox (
 			id, aggregate_type, aggregate_id, event_type, event_version,
-			payload, status, partition_key, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-	`
+			payload, status, partition_key, created_at, producer_version, priority
+		) VALUES `
+	args := make([]any, 0, len(eventsList)*columns)
 
-	_, err = q.Exec(ctx, query,
-		event.EventID(),
-		aggregateType,
-		event.AggregateID(),
-		event.EventType(),
-		1, // Event version (можно расширить для версионирования схем)
-		payload,
-		"PENDING",
-		event.AggregateID().String(), // Partition key для Kafka ordering
-		event.OccurredAt(),
-	)
+	for i, event := range eventsList {
+		// Сериализуем событие в JSON
+		payload, err := serializeEvent(event)
+		if err != nil {
+			return fmt.Errorf("failed to serialize event %s: %w", event.EventType(), err)
+		}
 
-	if err != nil {
-		return fmt.Errorf("failed to save event to outbox: %w", err)
+		if i > 0 {
+			query += ", "
+		}
+		n := i * columns
+		query += fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, NULLIF($%d, ''), $%d)",
+			n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10, n+11)
+
+		args = append(args,
+			event.EventID(),
+			getAggregateType(event.EventType()), // Aggregate type из типа события
+			event.AggregateID(),
+			event.EventType(),
+			1, // Event version (можно расширить для версионирования схем)
+			payload,
+			"PENDING",
+			event.AggregateID().String(), // Partition key для Kafka ordering
+			event.OccurredAt(),
+			r.producerVersion,
+			string(r.priorities.For(event.EventType())),
+		)
+	}
+
+	if _, err := r.getQuerier(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to save events to outbox: %w", err)
 	}
 
 	return nil
@@ -87,18 +110,47 @@ func (r *OutboxRepository) Save(ctx context.Context, event events.DomainEvent) e
 // FindUnpublished возвращает события, которые ещё не опубликованы.
 // Используется poller'ом для публикации в Kafka.
 func (r *OutboxRepository) FindUnpublished(ctx context.Context, limit int) ([]events.DomainEvent, error) {
-	q := r.getQuerier(ctx)
-
 	query := `
-		SELECT id, aggregate_type, aggregate_id, event_type, payload, created_at
+		SELECT id, aggregate_type, aggregate_id, event_type, payload, created_at, producer_version, priority
 		FROM outbox
 		WHERE status = 'PENDING'
-		ORDER BY created_at ASC
+		ORDER BY sequence ASC
 		LIMIT $1
 		FOR UPDATE SKIP LOCKED
 	`
 
-	rows, err := q.Query(ctx, query, limit)
+	return r.findEvents(ctx, query, limit)
+}
+
+// FindUnpublishedByPriority возвращает события полосы priority и все более
+// ранние PENDING события тех же агрегатов.
+//
+// head - последнее PENDING событие полосы в агрегате; выбираются все PENDING
+// события агрегата до head включительно. Для каждого агрегата это префикс его
+// очереди, поэтому LIMIT не может пропустить старое событие ради нового.
+func (r *OutboxRepository) FindUnpublishedByPriority(ctx context.Context, priority ports.OutboxPriority, limit int) ([]events.DomainEvent, error) {
+	query := `
+		WITH heads AS (
+			SELECT aggregate_id, MAX(sequence) AS head
+			FROM outbox
+			WHERE status = 'PENDING' AND priority = $2
+			GROUP BY aggregate_id
+		)
+		SELECT o.id, o.aggregate_type, o.aggregate_id, o.event_type, o.payload, o.created_at, o.producer_version, o.priority
+		FROM outbox o
+		JOIN heads h ON h.aggregate_id = o.aggregate_id AND o.sequence <= h.head
+		WHERE o.status = 'PENDING'
+		ORDER BY o.sequence ASC
+		LIMIT $1
+		FOR UPDATE OF o SKIP LOCKED
+	`
+
+	return r.findEvents(ctx, query, limit, string(priority))
+}
+
+// findEvents выполняет SELECT по outbox и оборачивает строки в genericEvent.
+func (r *OutboxRepository) findEvents(ctx context.Context, query string, args ...any) ([]events.DomainEvent, error) {
+	rows, err := r.getQuerier(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find unpublished events: %w", err)
 	}
@@ -112,18 +164,23 @@ func (r *OutboxRepository) FindUnpublished(ctx context.Context, limit int) ([]ev
 			aggregateID              uuid.UUID
 			payload                  []byte
 			createdAt                time.Time
+			producerVersion          *string
+			priority                 string
 		)
 
-		if err := rows.Scan(&id, &aggregateType, &aggregateID, &eventType, &payload, &createdAt); err != nil {
+		if err := rows.Scan(&id, &aggregateType, &aggregateID, &eventType, &payload, &createdAt, &producerVersion, &priority); err != nil {
 			return nil, fmt.Errorf("failed to scan outbox row: %w", err)
 		}
 
 		// Десериализуем событие
-		event, err := deserializeEvent(eventType, payload, id, aggregateID, createdAt)
+		event, err := deserializeEvent(eventType, payload, id, aggregateID, createdAt, derefString(producerVersion))
 		if err != nil {
 			// Логируем ошибку, но продолжаем (corrupt events не должны блокировать processing)
 			continue
 		}
+		if ge, ok := event.(*genericEvent); ok {
+			ge.priority = ports.OutboxPriority(priority)
+		}
 
 		domainEvents = append(domainEvents, event)
 	}
@@ -135,26 +192,63 @@ func (r *OutboxRepository) FindUnpublished(ctx context.Context, limit int) ([]ev
 	return domainEvents, nil
 }
 
+// CountPending возвращает число PENDING событий по полосам; пустые полосы - 0.
+func (r *OutboxRepository) CountPending(ctx context.Context) (map[ports.OutboxPriority]int, error) {
+	query := `
+		SELECT priority, COUNT(*)
+		FROM outbox
+		WHERE status = 'PENDING'
+		GROUP BY priority
+	`
+
+	rows, err := r.getQuerier(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pending events: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[ports.OutboxPriority]int, len(ports.OutboxPriorityLanes))
+	for _, lane := range ports.OutboxPriorityLanes {
+		counts[lane] = 0
+	}
+	for rows.Next() {
+		var (
+			priority string
+			count    int
+		)
+		if err := rows.Scan(&priority, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan pending count: %w", err)
+		}
+		counts[ports.OutboxPriority(priority)] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pending counts: %w", err)
+	}
+
+	return counts, nil
+}
+
 // Publish реализует EventPublisher интерфейс.
 // В Outbox pattern это просто alias для Save - сохраняем событие в БД.
 func (r *OutboxRepository) Publish(ctx context.Context, event events.DomainEvent) error {
-	return r.Save(ctx, event)
+	return r.PublishBatch(ctx, []events.DomainEvent{event})
 }
 
 // PublishBatch реализует EventPublisher интерфейс.
-// Сохраняет несколько событий за один раз.
+// Сохраняет batch одним INSERT: атомарно, в порядке слайса, без повторов EventID.
 func (r *OutboxRepository) PublishBatch(ctx context.Context, eventsList []events.DomainEvent) error {
 	if len(eventsList) == 0 {
 		return nil
 	}
 
-	// В контексте транзакции сохраняем все события
-	for _, event := range eventsList {
-		if err := r.Save(ctx, event); err != nil {
-			return fmt.Errorf("failed to publish event %s: %w", event.EventType(), err)
-		}
+	start := time.Now()
+	unique := ports.DedupeEvents(eventsList)
+	if err := r.insertEvents(ctx, unique); err != nil {
+		return err
 	}
 
+	ports.RequestStatsFromContext(ctx).RecordPublish(len(unique), time.Since(start))
 	return nil
 }
 
@@ -173,7 +267,7 @@ func (r *OutboxRepository) MarkPublished(ctx context.Context, eventID string) er
 		WHERE id = $1 AND status = 'PENDING'
 	`
 
-	result, err := q.Exec(ctx, query, eventUUID, time.Now())
+	result, err := q.Exec(ctx, query, eventUUID, time.Now().UTC())
 	if err != nil {
 		return fmt.Errorf("failed to mark event as published: %w", err)
 	}
@@ -203,7 +297,7 @@ func (r *OutboxRepository) MarkFailed(ctx context.Context, eventID string, reaso
 		WHERE id = $1
 	`
 
-	_, err = q.Exec(ctx, query, eventUUID, time.Now(), reason)
+	_, err = q.Exec(ctx, query, eventUUID, time.Now().UTC(), reason)
 	if err != nil {
 		return fmt.Errorf("failed to mark event as failed: %w", err)
 	}
@@ -270,46 +364,64 @@ func serializeEvent(event events.DomainEvent) ([]byte, error) {
 	switch e := event.(type) {
 	case *events.WalletCredited:
 		data = map[string]interface{}{
-			"wallet_id":      e.WalletID.String(),
-			"amount":         e.Amount.String(),
-			"currency":       e.Amount.Currency().Code(),
-			"transaction_id": e.TransactionID.String(),
-			"balance_after":  e.BalanceAfter.String(),
+			"wallet_id":       e.WalletID.String(),
+			"amount":          e.Amount.String(),
+			"currency":        e.Amount.Currency().Code(),
+			"transaction_id":  e.TransactionID.String(),
+			"balance_after":   e.BalanceAfter.String(),
+			"balance_version": e.BalanceVersion,
 		}
 	case *events.WalletDebited:
 		data = map[string]interface{}{
-			"wallet_id":      e.WalletID.String(),
-			"amount":         e.Amount.String(),
-			"currency":       e.Amount.Currency().Code(),
-			"transaction_id": e.TransactionID.String(),
-			"balance_after":  e.BalanceAfter.String(),
+			"wallet_id":       e.WalletID.String(),
+			"amount":          e.Amount.String(),
+			"currency":        e.Amount.Currency().Code(),
+			"transaction_id":  e.TransactionID.String(),
+			"balance_after":   e.BalanceAfter.String(),
+			"balance_version": e.BalanceVersion,
 		}
 	case *events.TransactionCompleted:
 		data = map[string]interface{}{
 			"transaction_id":   e.TransactionID.String(),
 			"wallet_id":        e.WalletID.String(),
+			"user_id":          e.UserID.String(),
 			"transaction_type": e.TransactionType,
 			"amount":           e.Amount.String(),
 			"currency":         e.Amount.Currency().Code(),
+			"fee_amount":       e.FeeAmount.String(),
+			"net_amount":       e.NetAmount.String(),
 			"completed_at":     e.CompletedAt,
 		}
+		// Переводы: получатель и его владелец
+		if e.CounterpartyWalletID != uuid.Nil {
+			data["counterparty_wallet_id"] = e.CounterpartyWalletID.String()
+			data["counterparty_user_id"] = e.CounterpartyUserID.String()
+		}
+		if e.ReceiptNumber != "" {
+			data["receipt_number"] = e.ReceiptNumber
+		}
 	case *events.TransactionCreated:
 		data = map[string]interface{}{
 			"transaction_id":   e.TransactionID.String(),
 			"wallet_id":        e.WalletID.String(),
+			"user_id":          e.UserID.String(),
 			"transaction_type": e.TransactionType,
 			"amount":           e.Amount.String(),
 			"currency":         e.Amount.Currency().Code(),
+			"fee_amount":       e.FeeAmount.String(),
+			"net_amount":       e.NetAmount.String(),
 			"idempotency_key":  e.IdempotencyKey,
 		}
 	case *events.TransactionFailed:
 		data = map[string]interface{}{
 			"transaction_id":   e.TransactionID.String(),
 			"wallet_id":        e.WalletID.String(),
+			"user_id":          e.UserID.String(),
 			"transaction_type": e.TransactionType,
 			"amount":           e.Amount.String(),
 			"currency":         e.Amount.Currency().Code(),
 			"failure_reason":   e.FailureReason,
+			"failure_category": e.FailureCategory,
 			"is_retryable":     e.IsRetryable,
 		}
 	case *events.WalletCreated:
@@ -322,6 +434,29 @@ func serializeEvent(event events.DomainEvent) ([]byte, error) {
 			"email":     e.Email,
 			"full_name": e.FullName,
 		}
+	case *events.UserKYCApproved:
+		data = map[string]interface{}{
+			"user_id":  e.UserID.String(),
+			"actor_id": e.ActorID.String(),
+		}
+	case *events.UserKYCRejected:
+		data = map[string]interface{}{
+			"user_id":  e.UserID.String(),
+			"reason":   e.Reason,
+			"actor_id": e.ActorID.String(),
+		}
+	case *events.UserKYCRevoked:
+		data = map[string]interface{}{
+			"user_id":  e.UserID.String(),
+			"reason":   e.Reason,
+			"actor_id": e.ActorID.String(),
+		}
+	case *events.UserAcceptedTerms:
+		data = map[string]interface{}{
+			"user_id":     e.UserID.String(),
+			"version":     e.Version,
+			"accepted_at": e.AcceptedAt,
+		}
 	case *events.CurrencyExchanged:
 		data = map[string]interface{}{
 			"transaction_id":      e.TransactionID.String(),
@@ -333,6 +468,35 @@ func serializeEvent(event events.DomainEvent) ([]byte, error) {
 			"source_currency":     e.SourceCurrency,
 			"destination_currency": e.DestinationCurrency,
 		}
+	case *events.SandboxResetRequested:
+		data = map[string]interface{}{
+			"tenant_id":  e.TenantID.String(),
+			"actor_id":   e.ActorID.String(),
+			"expires_at": e.ExpiresAt,
+		}
+	case *events.SandboxResetCompleted:
+		data = map[string]interface{}{
+			"tenant_id": e.TenantID.String(),
+			"actor_id":  e.ActorID.String(),
+			"deleted":   e.Deleted,
+		}
+	case *events.OperationSwitchChanged:
+		data = map[string]interface{}{
+			"operation": e.Operation,
+			"enabled":   e.Enabled,
+			"reason":    e.Reason,
+			"actor_id":  e.ActorID.String(),
+		}
+	case *events.WebhookSubscriptionDisabled:
+		data = map[string]interface{}{
+			"subscription_id":      e.SubscriptionID.String(),
+			"user_id":              e.UserID.String(),
+			"url":                  e.URL,
+			"reason":               e.Reason,
+			"consecutive_failures": e.ConsecutiveFailures,
+			"failing_since":        e.FailingSince,
+			"last_failure_reason":  e.LastFailureReason,
+		}
 	default:
 		return json.Marshal(event)
 	}
@@ -342,16 +506,17 @@ func serializeEvent(event events.DomainEvent) ([]byte, error) {
 
 // deserializeEvent десериализует событие из JSON.
 // Создаёт конкретный тип события на основе eventType.
-func deserializeEvent(eventType string, payload []byte, eventID, aggregateID uuid.UUID, occurredAt time.Time) (events.DomainEvent, error) {
+func deserializeEvent(eventType string, payload []byte, eventID, aggregateID uuid.UUID, occurredAt time.Time, producerVersion string) (events.DomainEvent, error) {
 	// Для полноценной десериализации нужна регистрация типов событий
 	// Пока возвращаем generic обёртку
 
 	return &genericEvent{
-		id:          eventID,
-		eventType:   eventType,
-		occurredAt:  occurredAt,
-		aggregateID: aggregateID,
-		payload:     payload,
+		id:              eventID,
+		eventType:       eventType,
+		occurredAt:      occurredAt,
+		aggregateID:     aggregateID,
+		payload:         payload,
+		producerVersion: producerVersion,
 	}, nil
 }
 
@@ -362,6 +527,12 @@ type genericEvent struct {
 	occurredAt  time.Time
 	aggregateID uuid.UUID
 	payload     []byte
+
+	// producerVersion - версия сборки, сохранившей событие ("" для старых строк)
+	producerVersion string
+
+	// priority - полоса доставки из колонки outbox.priority
+	priority ports.OutboxPriority
 }
 
 func (e *genericEvent) EventID() uuid.UUID     { return e.id }
@@ -370,6 +541,12 @@ func (e *genericEvent) OccurredAt() time.Time  { return e.occurredAt }
 func (e *genericEvent) AggregateID() uuid.UUID { return e.aggregateID }
 func (e *genericEvent) Payload() []byte        { return e.payload }
 
+// ProducerVersion возвращает версию сборки, сохранившей событие.
+func (e *genericEvent) ProducerVersion() string { return e.producerVersion }
+
+// Priority возвращает полосу доставки события.
+func (e *genericEvent) Priority() ports.OutboxPriority { return e.priority }
+
 // getAggregateType определяет тип агрегата из типа события.
 func getAggregateType(eventType string) string {
 	switch {
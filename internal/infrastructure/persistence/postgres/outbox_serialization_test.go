@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// TestSerializeEvent_TransactionEvents фиксирует payload транзакционных
+// событий в outbox: потребители (уведомления, webhooks) читают эти ключи.
+func TestSerializeEvent_TransactionEvents(t *testing.T) {
+	transactionID := uuid.MustParse("0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a80")
+	walletID := uuid.MustParse("0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a81")
+	userID := uuid.MustParse("0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a82")
+	destinationWalletID := uuid.MustParse("0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a83")
+	destinationUserID := uuid.MustParse("0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a84")
+	amount, err := valueobjects.NewMoney("100.00", valueobjects.USD)
+	require.NoError(t, err)
+
+	completed := events.NewTransactionCompleted(transactionID, walletID, userID, "DEPOSIT", amount)
+	completed.CompletedAt = time.Date(2024, 3, 10, 9, 30, 0, 0, time.UTC)
+	transfer := events.NewTransactionCompleted(transactionID, walletID, userID, "TRANSFER", amount).
+		WithCounterparty(destinationWalletID, destinationUserID).
+		WithReceiptNumber("PB-7F3K-9Q2M")
+	transfer.CompletedAt = completed.CompletedAt
+
+	tests := []struct {
+		name     string
+		event    events.DomainEvent
+		expected string
+	}{
+		{
+			name:  "Created",
+			event: events.NewTransactionCreated(transactionID, walletID, userID, "DEPOSIT", amount, "key-1"),
+			expected: `{
+				"transaction_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a80",
+				"wallet_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a81",
+				"user_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a82",
+				"transaction_type": "DEPOSIT",
+				"amount": "100.00 USD",
+				"currency": "USD",
+				"fee_amount": "0.00 USD",
+				"net_amount": "100.00 USD",
+				"idempotency_key": "key-1"
+			}`,
+		},
+		{
+			name:  "Completed",
+			event: completed,
+			expected: `{
+				"transaction_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a80",
+				"wallet_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a81",
+				"user_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a82",
+				"transaction_type": "DEPOSIT",
+				"amount": "100.00 USD",
+				"currency": "USD",
+				"fee_amount": "0.00 USD",
+				"net_amount": "100.00 USD",
+				"completed_at": "2024-03-10T09:30:00Z"
+			}`,
+		},
+		{
+			name:  "CompletedTransfer",
+			event: transfer,
+			expected: `{
+				"transaction_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a80",
+				"wallet_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a81",
+				"user_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a82",
+				"counterparty_wallet_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a83",
+				"counterparty_user_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a84",
+				"receipt_number": "PB-7F3K-9Q2M",
+				"transaction_type": "TRANSFER",
+				"amount": "100.00 USD",
+				"currency": "USD",
+				"fee_amount": "0.00 USD",
+				"net_amount": "100.00 USD",
+				"completed_at": "2024-03-10T09:30:00Z"
+			}`,
+		},
+		{
+			name:  "Failed",
+			event: events.NewTransactionFailed(transactionID, walletID, userID, "WITHDRAW", amount, "TIMEOUT", "PROVIDER", true),
+			expected: `{
+				"transaction_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a80",
+				"wallet_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a81",
+				"user_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a82",
+				"transaction_type": "WITHDRAW",
+				"amount": "100.00 USD",
+				"currency": "USD",
+				"failure_reason": "TIMEOUT",
+				"failure_category": "PROVIDER",
+				"is_retryable": true
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := serializeEvent(tt.event)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(payload))
+		})
+	}
+}
+
+// TestSerializeEvent_WebhookSubscriptionDisabled фиксирует payload, который
+// читает уведомление владельца об отключённой подписке.
+func TestSerializeEvent_WebhookSubscriptionDisabled(t *testing.T) {
+	event := events.NewWebhookSubscriptionDisabled(
+		uuid.MustParse("0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a90"),
+		uuid.MustParse("0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a91"),
+		"https://example.com/hooks",
+		"consecutive_failures",
+		50,
+		time.Date(2024, 3, 10, 9, 30, 0, 0, time.UTC),
+		"HTTP 404",
+	)
+
+	payload, err := serializeEvent(event)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"subscription_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a90",
+		"user_id": "0190f5a2-7c41-7b3e-9d2a-1f4e5c6b7a91",
+		"url": "https://example.com/hooks",
+		"reason": "consecutive_failures",
+		"consecutive_failures": 50,
+		"failing_since": "2024-03-10T09:30:00Z",
+		"last_failure_reason": "HTTP 404"
+	}`, string(payload))
+}
@@ -0,0 +1,112 @@
+// Package postgres - PayeeRepository implementation.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// Compile-time check: PayeeRepository implements ports.PayeeRepository
+var _ ports.PayeeRepository = (*PayeeRepository)(nil)
+
+// PayeeRepository реализует ports.PayeeRepository (таблица wallet_payees).
+type PayeeRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPayeeRepository создаёт новый PayeeRepository.
+func NewPayeeRepository(pool *pgxpool.Pool) *PayeeRepository {
+	return &PayeeRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *PayeeRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// HasPriorCompletedTransfer проверяет наличие пары в wallet_payees.
+func (r *PayeeRepository) HasPriorCompletedTransfer(ctx context.Context, sourceWalletID, destinationWalletID uuid.UUID) (bool, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM wallet_payees
+			WHERE source_wallet_id = $1 AND destination_wallet_id = $2
+		)
+	`
+
+	var exists bool
+	if err := q.QueryRow(ctx, query, sourceWalletID, destinationWalletID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check wallet payee: %w", err)
+	}
+
+	return exists, nil
+}
+
+// FirstTransferAt читает first_transfer_at пары.
+func (r *PayeeRepository) FirstTransferAt(ctx context.Context, sourceWalletID, destinationWalletID uuid.UUID) (time.Time, bool, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT first_transfer_at FROM wallet_payees
+		WHERE source_wallet_id = $1 AND destination_wallet_id = $2
+	`
+
+	var at time.Time
+	if err := q.QueryRow(ctx, query, sourceWalletID, destinationWalletID).Scan(&at); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, fmt.Errorf("failed to load wallet payee: %w", err)
+	}
+
+	return at, true, nil
+}
+
+// TransferredTo суммирует незавершённые и завершённые переводы пары через sumAmounts.
+func (r *PayeeRepository) TransferredTo(ctx context.Context, sourceWalletID, destinationWalletID uuid.UUID, currency valueobjects.Currency) (valueobjects.Money, error) {
+	query := newSelect(`SELECT COALESCE(SUM(amount), 0)::text FROM transactions`).
+		Where("wallet_id = ?", sourceWalletID).
+		Where("destination_wallet_id = ?", destinationWalletID).
+		Where("currency = ?", currency.Code()).
+		Where("transaction_type = ?", string(entities.TransactionTypeTransfer)).
+		Where("status = ANY(?)", []string{
+			string(entities.TransactionStatusPending),
+			string(entities.TransactionStatusProcessing),
+			string(entities.TransactionStatusCompleted),
+		})
+
+	sql, args := query.SQL()
+	return sumAmounts(ctx, r.getQuerier(ctx), currency, sql, args...)
+}
+
+// RecordPayee добавляет пару; существующая запись не меняется.
+func (r *PayeeRepository) RecordPayee(ctx context.Context, sourceWalletID, destinationWalletID, transactionID uuid.UUID) error {
+	q := r.getQuerier(ctx)
+
+	query := `
+		INSERT INTO wallet_payees (source_wallet_id, destination_wallet_id, first_transaction_id, first_transfer_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (source_wallet_id, destination_wallet_id) DO NOTHING
+	`
+
+	if _, err := q.Exec(ctx, query, sourceWalletID, destinationWalletID, transactionID, clock.Now()); err != nil {
+		return fmt.Errorf("failed to record wallet payee: %w", err)
+	}
+
+	return nil
+}
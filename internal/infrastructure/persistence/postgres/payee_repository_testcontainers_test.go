@@ -0,0 +1,30 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+func TestPayeeRepository_Conformance(t *testing.T) {
+	porttest.RunPayeeRepositoryTests(t, func(t *testing.T) porttest.PayeeHarness {
+		tc := setupSharedTestDB(t)
+
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000019_create_wallet_payees.up.sql"))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(context.Background(), string(migration))
+		require.NoError(t, err)
+
+		return porttest.PayeeHarness{
+			Repositories: newConformanceRepositories(t),
+			Payees:       NewPayeeRepository(tc.pool),
+		}
+	})
+}
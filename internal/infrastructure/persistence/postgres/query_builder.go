@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// selectQuery - динамический SELECT для List/Search методов.
+//
+// Условия пишутся с плейсхолдером ?, builder сам нумерует параметры
+// ($1, $2, ...) в порядке добавления: ручной учёт argNum не нужен.
+// Литеральный ? (jsonb операторы) записывается как ??.
+//
+// Статические запросы горячего пути (FindByID, Save) остаются
+// написанными вручную.
+type selectQuery struct {
+	from    string // SELECT ... FROM ... [JOIN ...]
+	where   []string
+	args    []any
+	orderBy string
+	page    bool
+	offset  int
+	limit   int
+}
+
+// newSelect начинает запрос с готовой части SELECT ... FROM.
+func newSelect(from string) *selectQuery {
+	return &selectQuery{from: from}
+}
+
+// Where добавляет условие (через AND); число ? должно совпадать с числом args.
+func (q *selectQuery) Where(cond string, args ...any) *selectQuery {
+	q.where = append(q.where, cond)
+	q.args = append(q.args, args...)
+	return q
+}
+
+// Before добавляет keyset-условие курсора (cols) < (values) - следующая
+// страница при сортировке cols DESC.
+func (q *selectQuery) Before(cols []string, values ...any) *selectQuery {
+	return q.keyset("<", cols, values)
+}
+
+// After добавляет keyset-условие курсора (cols) > (values) - следующая
+// страница при сортировке cols ASC.
+func (q *selectQuery) After(cols []string, values ...any) *selectQuery {
+	return q.keyset(">", cols, values)
+}
+
+func (q *selectQuery) keyset(op string, cols []string, values []any) *selectQuery {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	return q.Where("("+strings.Join(cols, ", ")+") "+op+" ("+placeholders+")", values...)
+}
+
+// OrderBy задаёт ORDER BY. Выражение не параметризуется: значения из
+// запроса клиента передаются только через sortWhitelist.
+func (q *selectQuery) OrderBy(expr string) *selectQuery {
+	q.orderBy = expr
+	return q
+}
+
+// Page задаёт OFFSET/LIMIT (последними параметрами запроса).
+func (q *selectQuery) Page(offset, limit int) *selectQuery {
+	q.page, q.offset, q.limit = true, offset, limit
+	return q
+}
+
+// SQL собирает запрос и аргументы.
+func (q *selectQuery) SQL() (string, []any) {
+	var b strings.Builder
+	b.WriteString(q.from)
+	for i, cond := range q.where {
+		if i == 0 {
+			b.WriteString(" WHERE ")
+		} else {
+			b.WriteString(" AND ")
+		}
+		b.WriteString(cond)
+	}
+	if q.orderBy != "" {
+		b.WriteString(" ORDER BY ")
+		b.WriteString(q.orderBy)
+	}
+
+	args := q.args
+	if q.page {
+		b.WriteString(" OFFSET ? LIMIT ?")
+		args = append(args[:len(args):len(args)], q.offset, q.limit)
+	}
+
+	return numberPlaceholders(b.String()), args
+}
+
+// numberPlaceholders заменяет ? на $1, $2, ...; ?? даёт литеральный ?.
+func numberPlaceholders(query string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+
+	n := 0
+	for i := 0; i < len(query); i++ {
+		if query[i] != '?' {
+			b.WriteByte(query[i])
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == '?' {
+			b.WriteByte('?')
+			i++
+			continue
+		}
+		n++
+		b.WriteByte('$')
+		b.WriteString(strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+// whereOpt добавляет условие, если необязательный фильтр задан.
+func whereOpt[T any](q *selectQuery, cond string, value *T) {
+	if value != nil {
+		q.Where(cond, *value)
+	}
+}
+
+// whereOptFunc - whereOpt с преобразованием значения в аргумент запроса
+// (Currency.Code, time.Time.UTC, ...).
+func whereOptFunc[T, A any](q *selectQuery, cond string, value *T, arg func(T) A) {
+	if value != nil {
+		q.Where(cond, arg(*value))
+	}
+}
+
+// asString - аргумент для строковых enum'ов домена.
+func asString[S ~string](s S) string {
+	return string(s)
+}
+
+// sortWhitelist - допустимые ключи сортировки API и их выражения ORDER BY.
+// Ключ клиента в SQL не попадает.
+type sortWhitelist map[string]string
+
+// orderBy возвращает выражение для ключа; пустой ключ - fallback.
+func (w sortWhitelist) orderBy(key, fallback string) (string, error) {
+	if key == "" {
+		return fallback, nil
+	}
+	expr, ok := w[key]
+	if !ok {
+		return "", fmt.Errorf("unsupported sort key %q", key)
+	}
+	return expr, nil
+}
@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// squash схлопывает пробелы, чтобы сравнивать SQL без учёта форматирования.
+func squash(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+func TestSelectQuery_NumbersPlaceholders(t *testing.T) {
+	query, args := newSelect("SELECT id FROM t").
+		Where("a = ?", 1).
+		Where("(b = ? OR c = ?)", 2, 3).
+		Where("meta ?? 'key'").
+		OrderBy("id DESC").
+		Page(10, 20).
+		SQL()
+
+	assert.Equal(t, "SELECT id FROM t WHERE a = $1 AND (b = $2 OR c = $3) AND meta ? 'key' ORDER BY id DESC OFFSET $4 LIMIT $5", query)
+	assert.Equal(t, []any{1, 2, 3, 10, 20}, args)
+}
+
+func TestSelectQuery_NoPredicates(t *testing.T) {
+	query, args := newSelect("SELECT id FROM t").SQL()
+
+	assert.Equal(t, "SELECT id FROM t", query)
+	assert.Empty(t, args)
+}
+
+func TestSelectQuery_SQLIsRepeatable(t *testing.T) {
+	q := newSelect("SELECT id FROM t").Where("a = ?", 1).Page(0, 5)
+
+	first, firstArgs := q.SQL()
+	second, secondArgs := q.SQL()
+	assert.Equal(t, first, second)
+	assert.Equal(t, firstArgs, secondArgs)
+}
+
+func TestSelectQuery_Keyset(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	id := uuid.New()
+
+	query, args := newSelect("SELECT id FROM t").
+		Where("status = ?", "OK").
+		Before([]string{"created_at", "id"}, at, id).
+		OrderBy("created_at DESC, id DESC").
+		SQL()
+	assert.Equal(t, "SELECT id FROM t WHERE status = $1 AND (created_at, id) < ($2, $3) ORDER BY created_at DESC, id DESC", query)
+	assert.Equal(t, []any{"OK", at, id}, args)
+
+	query, _ = newSelect("SELECT id FROM t").After([]string{"seq"}, 7).SQL()
+	assert.Equal(t, "SELECT id FROM t WHERE (seq) > ($1)", query)
+}
+
+func TestSortWhitelist(t *testing.T) {
+	sorts := sortWhitelist{
+		"created_at": "created_at DESC, id DESC",
+		"amount":     "amount DESC, id DESC",
+	}
+
+	expr, err := sorts.orderBy("amount", "created_at DESC")
+	require.NoError(t, err)
+	assert.Equal(t, "amount DESC, id DESC", expr)
+
+	expr, err = sorts.orderBy("", "created_at DESC")
+	require.NoError(t, err)
+	assert.Equal(t, "created_at DESC", expr)
+
+	_, err = sorts.orderBy("amount; DROP TABLE t", "created_at DESC")
+	assert.Error(t, err)
+}
+
+func TestWalletListQuery(t *testing.T) {
+	userID := uuid.New()
+	usd := valueobjects.MustNewCurrency("USD")
+	status := entities.WalletStatusActive
+
+	tests := []struct {
+		name   string
+		filter ports.WalletFilter
+		where  string
+		args   []any
+	}{
+		{
+			name:  "no filters",
+			where: "",
+			args:  []any{},
+		},
+		{
+			name:   "user",
+			filter: ports.WalletFilter{UserID: &userID},
+			where:  " WHERE w.user_id = $1",
+			args:   []any{userID},
+		},
+		{
+			name:   "all filters",
+			filter: ports.WalletFilter{UserID: &userID, Currency: &usd, Status: &status},
+			where:  " WHERE w.user_id = $1 AND w.currency = $2 AND w.status = $3",
+			args:   []any{userID, "USD", string(status)},
+		},
+		{
+			name:   "status only",
+			filter: ports.WalletFilter{Status: &status},
+			where:  " WHERE w.status = $1",
+			args:   []any{string(status)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := walletListQuery("SELECT w.id FROM wallets w", tt.filter, 40, 20).SQL()
+
+			n := len(tt.args)
+			assert.Equal(t, "SELECT w.id FROM wallets w"+tt.where+
+				" ORDER BY w.created_at DESC OFFSET $"+strconv.Itoa(n+1)+" LIMIT $"+strconv.Itoa(n+2), query)
+			assert.Equal(t, append(tt.args, 40, 20), args)
+		})
+	}
+}
+
+func TestTransactionListQuery(t *testing.T) {
+	walletID := uuid.New()
+	userID := uuid.New()
+	txType := entities.TransactionTypeDeposit
+	status := entities.TransactionStatusCompleted
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.FixedZone("MSK", 3*3600))
+	to := from.Add(24 * time.Hour)
+
+	tests := []struct {
+		name   string
+		filter ports.TransactionFilter
+		join   bool
+		where  string
+		args   []any
+	}{
+		{
+			name:  "no filters",
+			where: "",
+			args:  []any{},
+		},
+		{
+			name:   "wallet matches either side",
+			filter: ports.TransactionFilter{WalletID: &walletID},
+			where:  " WHERE (t.wallet_id = $1 OR t.destination_wallet_id = $2)",
+			args:   []any{walletID, walletID},
+		},
+		{
+			name:   "user joins wallets",
+			filter: ports.TransactionFilter{UserID: &userID, Status: &status},
+			join:   true,
+			where:  " WHERE w.user_id = $1 AND t.status = $2",
+			args:   []any{userID, string(status)},
+		},
+		{
+			name: "all filters",
+			filter: ports.TransactionFilter{
+				WalletID: &walletID, UserID: &userID, Type: &txType, Status: &status,
+				CreatedFrom: &from, CreatedTo: &to,
+			},
+			join: true,
+			where: " WHERE (t.wallet_id = $1 OR t.destination_wallet_id = $2) AND w.user_id = $3" +
+				" AND t.transaction_type = $4 AND t.status = $5 AND t.created_at >= $6 AND t.created_at < $7",
+			args: []any{walletID, walletID, userID, string(txType), string(status), from.UTC(), to.UTC()},
+		},
+		{
+			name:   "created range in UTC",
+			filter: ports.TransactionFilter{CreatedFrom: &from},
+			where:  " WHERE t.created_at >= $1",
+			args:   []any{from.UTC()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := transactionListQuery(tt.filter, 0, 50).SQL()
+			query = squash(query)
+
+			require.True(t, strings.HasPrefix(query, "SELECT t.id, t.wallet_id,"), query)
+			from := "FROM transactions t"
+			if tt.join {
+				from += " JOIN wallets w ON t.wallet_id = w.id"
+			}
+			n := len(tt.args)
+			assert.True(t, strings.HasSuffix(query, from+tt.where+
+				" ORDER BY t.created_at DESC, t.id DESC OFFSET $"+strconv.Itoa(n+1)+" LIMIT $"+strconv.Itoa(n+2)), query)
+			assert.Equal(t, append(tt.args, 0, 50), args)
+		})
+	}
+}
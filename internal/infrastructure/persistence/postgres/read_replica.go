@@ -0,0 +1,143 @@
+// Package postgres - чтение с реплики и hedging медленных запросов.
+package postgres
+
+import (
+	"context"
+	"time"
+)
+
+// ReadGroup - группа read-only методов репозитория с общей настройкой hedging.
+type ReadGroup string
+
+const (
+	// ReadGroupWalletReads - точечные чтения кошельков (FindByID, FindBalanceByID, ...).
+	ReadGroupWalletReads ReadGroup = "wallet_reads"
+	// ReadGroupWalletLists - тяжёлые списки кошельков (List).
+	ReadGroupWalletLists ReadGroup = "wallet_lists"
+)
+
+// ReadHedging - настройки hedging чтений с реплики.
+//
+// Если реплика не ответила за Delay, тот же запрос уходит на primary и
+// возвращается первый ответ; проигравший запрос отменяется через context.
+type ReadHedging struct {
+	Delay         time.Duration      // 0 - hedging выключен
+	MaxConcurrent int                // Максимум одновременных hedge-запросов на primary; 0 - без hedging
+	Groups        map[ReadGroup]bool // Группы с hedging; нет записи - выключен
+
+	// OnHedge вызывается для каждого отправленного hedge-запроса (метрика).
+	// primaryWon - ответ primary пришёл первым. Может быть nil.
+	OnHedge func(group ReadGroup, primaryWon bool)
+}
+
+// ReadReplica направляет назначенные read-only запросы репозиториев на
+// реплику.
+//
+// Через реплику идут только методы, явно переданные в routeRead: они
+// идемпотентны и не пишут. Внутри UnitOfWork чтения всегда идут в
+// транзакцию на primary, без реплики и без hedging.
+type ReadReplica struct {
+	pool    querier
+	hedging ReadHedging
+	slots   chan struct{}
+}
+
+// NewReadReplica создаёт ReadReplica над пулом реплики.
+func NewReadReplica(pool querier, hedging ReadHedging) *ReadReplica {
+	r := &ReadReplica{pool: pool, hedging: hedging}
+	if hedging.MaxConcurrent > 0 {
+		r.slots = make(chan struct{}, hedging.MaxConcurrent)
+	}
+	return r
+}
+
+// hedges сообщает, включён ли hedging для группы.
+func (r *ReadReplica) hedges(group ReadGroup) bool {
+	return r.hedging.Delay > 0 && r.slots != nil && r.hedging.Groups[group]
+}
+
+// tryAcquire занимает слот hedge-запроса; false - лимит исчерпан.
+func (r *ReadReplica) tryAcquire() bool {
+	select {
+	case r.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *ReadReplica) release() {
+	<-r.slots
+}
+
+// readResult - ответ одной из попыток чтения.
+type readResult[T any] struct {
+	value   T
+	err     error
+	primary bool
+}
+
+// routeRead выполняет read-only fn на реплике, а при включённом hedging
+// для группы - ещё и на primary, если реплика не успела за Delay.
+//
+// primary - querier репозитория (getQuerier): транзакция UnitOfWork или пул.
+// Без реплики или внутри UnitOfWork fn выполняется на primary как раньше.
+//
+// Из двух ответов берётся первый успешный: если первым пришла ошибка,
+// а вторая попытка ещё идёт, ждём её (например, "не найдено" с отстающей
+// реплики при уже созданной на primary записи).
+func routeRead[T any](ctx context.Context, replica *ReadReplica, group ReadGroup, primary querier, fn func(context.Context, querier) (T, error)) (T, error) {
+	if replica == nil || hasTx(ctx) {
+		return fn(ctx, primary)
+	}
+
+	replicaQuerier := withRequestStats(ctx, replica.pool)
+	if !replica.hedges(group) {
+		return fn(ctx, replicaQuerier)
+	}
+
+	// Буфер на обе попытки: проигравшая горутина не блокируется после return
+	results := make(chan readResult[T], 2)
+
+	replicaCtx, cancelReplica := context.WithCancel(ctx)
+	defer cancelReplica()
+	go func() {
+		value, err := fn(replicaCtx, replicaQuerier)
+		results <- readResult[T]{value: value, err: err}
+	}()
+
+	timer := time.NewTimer(replica.hedging.Delay)
+	defer timer.Stop()
+
+	select {
+	case res := <-results:
+		return res.value, res.err
+	case <-timer.C:
+	}
+
+	// Лимит hedge-запросов исчерпан - primary не нагружаем, ждём реплику
+	if !replica.tryAcquire() {
+		res := <-results
+		return res.value, res.err
+	}
+
+	primaryCtx, cancelPrimary := context.WithCancel(ctx)
+	defer cancelPrimary()
+	go func() {
+		defer replica.release()
+		value, err := fn(primaryCtx, primary)
+		results <- readResult[T]{value: value, err: err, primary: true}
+	}()
+
+	res := <-results
+	if res.err != nil && ctx.Err() == nil {
+		if second := <-results; second.err == nil {
+			res = second
+		}
+	}
+
+	if replica.hedging.OnHedge != nil {
+		replica.hedging.OnHedge(group, res.primary)
+	}
+	return res.value, res.err
+}
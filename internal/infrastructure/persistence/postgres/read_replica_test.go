@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePool - пул, отвечающий своим именем через delay.
+// Отмена context прерывает ожидание и отмечается в cancelled.
+type fakePool struct {
+	name      string
+	delay     time.Duration
+	calls     atomic.Int32
+	cancelled chan struct{}
+}
+
+func newFakePool(name string, delay time.Duration) *fakePool {
+	return &fakePool{name: name, delay: delay, cancelled: make(chan struct{}, 1)}
+}
+
+func (p *fakePool) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	panic("fakePool: Exec is not a read")
+}
+
+func (p *fakePool) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	panic("fakePool: Query is not used")
+}
+
+func (p *fakePool) QueryRow(ctx context.Context, _ string, _ ...any) pgx.Row {
+	p.calls.Add(1)
+	return fakeRow{ctx: ctx, pool: p}
+}
+
+type fakeRow struct {
+	ctx  context.Context
+	pool *fakePool
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	select {
+	case <-time.After(r.pool.delay):
+		*dest[0].(*string) = r.pool.name
+		return nil
+	case <-r.ctx.Done():
+		r.pool.cancelled <- struct{}{}
+		return r.ctx.Err()
+	}
+}
+
+// fakeTx - транзакция UnitOfWork в context (методы не вызываются).
+type fakeTx struct{ pgx.Tx }
+
+func readName(ctx context.Context, q querier) (string, error) {
+	var name string
+	err := q.QueryRow(ctx, "SELECT name").Scan(&name)
+	return name, err
+}
+
+func hedgingFor(group ReadGroup, onHedge func(ReadGroup, bool)) ReadHedging {
+	return ReadHedging{
+		Delay:         20 * time.Millisecond,
+		MaxConcurrent: 1,
+		Groups:        map[ReadGroup]bool{group: true},
+		OnHedge:       onHedge,
+	}
+}
+
+func TestRouteRead_HedgeFiresAndPrimaryWins(t *testing.T) {
+	replicaPool := newFakePool("replica", time.Minute)
+	primaryPool := newFakePool("primary", 0)
+
+	var hedged, primaryWon atomic.Int32
+	replica := NewReadReplica(replicaPool, hedgingFor(ReadGroupWalletReads, func(group ReadGroup, won bool) {
+		assert.Equal(t, ReadGroupWalletReads, group)
+		hedged.Add(1)
+		if won {
+			primaryWon.Add(1)
+		}
+	}))
+
+	start := time.Now()
+	name, err := routeRead(context.Background(), replica, ReadGroupWalletReads, primaryPool, readName)
+	require.NoError(t, err)
+	assert.Equal(t, "primary", name)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(1), hedged.Load())
+	assert.Equal(t, int32(1), primaryWon.Load())
+
+	select {
+	case <-replicaPool.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the losing replica query to be cancelled")
+	}
+}
+
+func TestRouteRead_FastReplicaNotHedged(t *testing.T) {
+	replicaPool := newFakePool("replica", 0)
+	primaryPool := newFakePool("primary", 0)
+	replica := NewReadReplica(replicaPool, hedgingFor(ReadGroupWalletReads, nil))
+
+	name, err := routeRead(context.Background(), replica, ReadGroupWalletReads, primaryPool, readName)
+	require.NoError(t, err)
+	assert.Equal(t, "replica", name)
+	assert.Zero(t, primaryPool.calls.Load())
+}
+
+func TestRouteRead_ReplicaWinsAfterHedge(t *testing.T) {
+	replicaPool := newFakePool("replica", 40*time.Millisecond)
+	primaryPool := newFakePool("primary", time.Minute)
+
+	var primaryWon atomic.Bool
+	replica := NewReadReplica(replicaPool, hedgingFor(ReadGroupWalletReads, func(_ ReadGroup, won bool) {
+		primaryWon.Store(won)
+	}))
+
+	name, err := routeRead(context.Background(), replica, ReadGroupWalletReads, primaryPool, readName)
+	require.NoError(t, err)
+	assert.Equal(t, "replica", name)
+	assert.False(t, primaryWon.Load())
+	assert.Equal(t, int32(1), primaryPool.calls.Load())
+
+	select {
+	case <-primaryPool.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the losing primary query to be cancelled")
+	}
+}
+
+func TestRouteRead_GroupWithoutHedging(t *testing.T) {
+	replicaPool := newFakePool("replica", 60*time.Millisecond)
+	primaryPool := newFakePool("primary", 0)
+	replica := NewReadReplica(replicaPool, hedgingFor(ReadGroupWalletReads, nil))
+
+	name, err := routeRead(context.Background(), replica, ReadGroupWalletLists, primaryPool, readName)
+	require.NoError(t, err)
+	assert.Equal(t, "replica", name)
+	assert.Zero(t, primaryPool.calls.Load())
+}
+
+func TestRouteRead_ConcurrentHedgeLimit(t *testing.T) {
+	replicaPool := newFakePool("replica", 60*time.Millisecond)
+	primaryPool := newFakePool("primary", 0)
+	replica := NewReadReplica(replicaPool, hedgingFor(ReadGroupWalletReads, nil))
+	require.True(t, replica.tryAcquire())
+	defer replica.release()
+
+	name, err := routeRead(context.Background(), replica, ReadGroupWalletReads, primaryPool, readName)
+	require.NoError(t, err)
+	assert.Equal(t, "replica", name)
+	assert.Zero(t, primaryPool.calls.Load())
+}
+
+func TestRouteRead_UnitOfWorkUsesPrimary(t *testing.T) {
+	replicaPool := newFakePool("replica", 0)
+	primaryPool := newFakePool("primary", 0)
+	replica := NewReadReplica(replicaPool, hedgingFor(ReadGroupWalletReads, nil))
+
+	ctx := injectTx(context.Background(), fakeTx{})
+	name, err := routeRead(ctx, replica, ReadGroupWalletReads, primaryPool, readName)
+	require.NoError(t, err)
+	assert.Equal(t, "primary", name)
+	assert.Zero(t, replicaPool.calls.Load())
+}
+
+func TestRouteRead_WithoutReplica(t *testing.T) {
+	primaryPool := newFakePool("primary", 0)
+
+	name, err := routeRead(context.Background(), nil, ReadGroupWalletReads, primaryPool, readName)
+	require.NoError(t, err)
+	assert.Equal(t, "primary", name)
+}
@@ -0,0 +1,295 @@
+// Package postgres - snapshot-сессии чтения (REPEATABLE READ READ ONLY).
+package postgres
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check
+var _ ports.ReadSessions = (*ReadSessionManager)(nil)
+
+// Значения по умолчанию.
+const (
+	DefaultReadSessionTTL        = 60 * time.Second
+	DefaultReadSessionsPerClient = 2
+	DefaultReadSessionsTotal     = 4
+
+	// readSessionRollbackTimeout - ожидание ROLLBACK при закрытии сессии
+	readSessionRollbackTimeout = 5 * time.Second
+)
+
+// txBeginner - пул, открывающий транзакции сессий (*pgxpool.Pool).
+type txBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// ReadSessionConfig - лимиты сессий чтения.
+type ReadSessionConfig struct {
+	TTL          time.Duration // 0 - DefaultReadSessionTTL
+	MaxPerClient int           // 0 - DefaultReadSessionsPerClient
+	MaxTotal     int           // 0 - DefaultReadSessionsTotal; ограничивает занятые соединения пула
+	// Source - "replica" или "primary", возвращается клиенту как есть
+	Source string
+}
+
+// ReadSessionManager реализует ports.ReadSessions поверх пула.
+//
+// Сессия - открытая транзакция REPEATABLE READ READ ONLY на своём
+// соединении. Attach кладёт её в context так же, как UnitOfWork
+// (injectTx), поэтому репозитории читают через неё без изменений: getQuerier
+// берёт транзакцию из context, routeRead не уходит на реплику.
+//
+// Соединение pgx не допускает параллельных запросов, поэтому запросы одной
+// сессии выполняются по очереди. По истечении TTL сессия удаляется, а
+// транзакция откатывается после завершения текущего запроса.
+type ReadSessionManager struct {
+	db     txBeginner
+	config ReadSessionConfig
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	sessions  map[string]*readSession
+	perClient map[string]int
+	open      int // Открытые и открывающиеся сессии
+	closed    bool
+}
+
+// readSession - открытая транзакция сессии.
+type readSession struct {
+	clientID string
+	tx       pgx.Tx
+	timer    *time.Timer
+	busy     chan struct{} // Один слот: запрос, выполняющийся в сессии
+}
+
+// NewReadSessionManager создаёт менеджер сессий над пулом (реплики или primary).
+func NewReadSessionManager(db txBeginner, config ReadSessionConfig, logger *slog.Logger) *ReadSessionManager {
+	if config.TTL <= 0 {
+		config.TTL = DefaultReadSessionTTL
+	}
+	if config.MaxPerClient <= 0 {
+		config.MaxPerClient = DefaultReadSessionsPerClient
+	}
+	if config.MaxTotal <= 0 {
+		config.MaxTotal = DefaultReadSessionsTotal
+	}
+	if config.Source == "" {
+		config.Source = "primary"
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ReadSessionManager{
+		db:        db,
+		config:    config,
+		logger:    logger,
+		sessions:  make(map[string]*readSession),
+		perClient: make(map[string]int),
+	}
+}
+
+// Open открывает транзакцию сессии и фиксирует её snapshot.
+func (m *ReadSessionManager) Open(ctx context.Context, clientID string) (*ports.ReadSession, error) {
+	if err := m.reserve(clientID); err != nil {
+		return nil, err
+	}
+
+	token, tx, err := m.begin(ctx)
+	if err != nil {
+		m.unreserve(clientID)
+		return nil, err
+	}
+
+	session := &readSession{clientID: clientID, tx: tx, busy: make(chan struct{}, 1)}
+	expiresAt := time.Now().Add(m.config.TTL).UTC()
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		m.unreserve(clientID)
+		m.rollback(session)
+		return nil, fmt.Errorf("read sessions are closed")
+	}
+	m.sessions[token] = session
+	session.timer = time.AfterFunc(m.config.TTL, func() { m.expire(token) })
+	m.mu.Unlock()
+
+	return &ports.ReadSession{Token: token, ExpiresAt: expiresAt, Source: m.config.Source}, nil
+}
+
+// reserve занимает место сессии в лимитах клиента и инстанса.
+func (m *ReadSessionManager) reserve(clientID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.perClient[clientID] >= m.config.MaxPerClient {
+		return domainErrors.NewDomainError(ports.ReadSessionLimitCode,
+			fmt.Sprintf("At most %d read sessions per client, wait for one to expire", m.config.MaxPerClient), nil)
+	}
+	if m.open >= m.config.MaxTotal {
+		return domainErrors.NewDomainError(ports.ReadSessionLimitCode,
+			"Too many open read sessions, retry later", nil)
+	}
+	m.perClient[clientID]++
+	m.open++
+	return nil
+}
+
+// unreserve возвращает место сессии.
+func (m *ReadSessionManager) unreserve(clientID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.open--
+	if m.perClient[clientID]--; m.perClient[clientID] <= 0 {
+		delete(m.perClient, clientID)
+	}
+}
+
+// begin открывает транзакцию и выполняет первый запрос: в REPEATABLE READ
+// snapshot берётся первым запросом, а не BEGIN.
+func (m *ReadSessionManager) begin(ctx context.Context) (string, pgx.Tx, error) {
+	token, err := newReadSessionToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	tx, err := m.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to begin read session: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "SELECT 1"); err != nil {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+		return "", nil, fmt.Errorf("failed to take read session snapshot: %w", err)
+	}
+	return token, tx, nil
+}
+
+// Attach ждёт очереди сессии и возвращает context с её транзакцией.
+func (m *ReadSessionManager) Attach(ctx context.Context, clientID, token string) (context.Context, func(), error) {
+	session := m.lookup(clientID, token)
+	if session == nil {
+		return nil, nil, ports.ErrReadSessionNotFound
+	}
+
+	select {
+	case session.busy <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	// Пока ждали очереди, сессия могла истечь
+	if m.lookup(clientID, token) != session {
+		<-session.busy
+		return nil, nil, ports.ErrReadSessionNotFound
+	}
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			broken := sessionBroken(session.tx)
+			<-session.busy
+			// Ошибка запроса прерывает транзакцию: следующие чтения сессии
+			// невозможны, клиенту нужна новая сессия
+			if broken {
+				go m.expire(token)
+			}
+		})
+	}
+	return injectTx(ctx, session.tx), release, nil
+}
+
+// sessionBroken сообщает, что транзакция сессии прервана ошибкой или её
+// соединение закрыто (например, отменённым запросом).
+func sessionBroken(tx pgx.Tx) bool {
+	conn := tx.Conn()
+	if conn == nil {
+		return false
+	}
+	return conn.IsClosed() || conn.PgConn().TxStatus() == 'E'
+}
+
+// lookup возвращает сессию клиента по токену (nil - нет такой).
+func (m *ReadSessionManager) lookup(clientID, token string) *readSession {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session := m.sessions[token]
+	if session == nil || session.clientID != clientID {
+		return nil
+	}
+	return session
+}
+
+// expire удаляет сессию и откатывает её транзакцию после текущего запроса.
+func (m *ReadSessionManager) expire(token string) {
+	m.mu.Lock()
+	session := m.sessions[token]
+	if session == nil {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.sessions, token)
+	m.mu.Unlock()
+
+	// Слот не возвращается: закрытой сессией больше никто не пользуется
+	session.busy <- struct{}{}
+	m.rollback(session)
+
+	// Место освобождается только вместе с соединением
+	m.unreserve(session.clientID)
+}
+
+// rollback откатывает транзакцию сессии и возвращает соединение в пул.
+func (m *ReadSessionManager) rollback(session *readSession) {
+	ctx, cancel := context.WithTimeout(context.Background(), readSessionRollbackTimeout)
+	defer cancel()
+
+	if err := session.tx.Rollback(ctx); err != nil {
+		m.logger.Warn("Failed to roll back read session", slog.String("error", err.Error()))
+	}
+}
+
+// Close закрывает все сессии (остановка сервиса). Новые сессии не открываются.
+func (m *ReadSessionManager) Close() {
+	m.mu.Lock()
+	m.closed = true
+	tokens := make([]string, 0, len(m.sessions))
+	for token, session := range m.sessions {
+		session.timer.Stop()
+		tokens = append(tokens, token)
+	}
+	m.mu.Unlock()
+
+	for _, token := range tokens {
+		m.expire(token)
+	}
+}
+
+// size возвращает число занятых мест: открытые сессии и ещё не вернувшие
+// соединение.
+func (m *ReadSessionManager) size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.open
+}
+
+// newReadSessionToken генерирует случайный токен сессии (128 бит).
+func newReadSessionToken() (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf[:]), nil
+}
@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// fakeSessionTx - транзакция сессии без БД; учитывает ROLLBACK.
+type fakeSessionTx struct {
+	pgx.Tx
+
+	mu         sync.Mutex
+	rolledBack bool
+}
+
+func (t *fakeSessionTx) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.NewCommandTag("SELECT 1"), nil
+}
+
+func (t *fakeSessionTx) Conn() *pgx.Conn { return nil }
+
+func (t *fakeSessionTx) Rollback(context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rolledBack = true
+	return nil
+}
+
+func (t *fakeSessionTx) isRolledBack() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rolledBack
+}
+
+// fakeSessionDB выдаёт fakeSessionTx и запоминает опции транзакций.
+type fakeSessionDB struct {
+	mu   sync.Mutex
+	txs  []*fakeSessionTx
+	opts []pgx.TxOptions
+}
+
+func (db *fakeSessionDB) BeginTx(_ context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	tx := &fakeSessionTx{}
+	db.txs = append(db.txs, tx)
+	db.opts = append(db.opts, opts)
+	return tx, nil
+}
+
+func requireDomainCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var domainErr *domainErrors.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, code, domainErr.Code)
+}
+
+func TestReadSessionManager_AttachUsesSessionTx(t *testing.T) {
+	db := &fakeSessionDB{}
+	manager := NewReadSessionManager(db, ReadSessionConfig{Source: "replica"}, nil)
+	defer manager.Close()
+
+	session, err := manager.Open(context.Background(), "user:a")
+	require.NoError(t, err)
+	assert.Equal(t, "replica", session.Source)
+	assert.Len(t, session.Token, 32)
+	assert.Equal(t, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}, db.opts[0])
+
+	ctx, release, err := manager.Attach(context.Background(), "user:a", session.Token)
+	require.NoError(t, err)
+	defer release()
+	assert.Same(t, db.txs[0], extractTx(ctx))
+
+	// Токен другого клиента не подходит
+	_, _, err = manager.Attach(context.Background(), "user:b", session.Token)
+	assert.ErrorIs(t, err, ports.ErrReadSessionNotFound)
+	_, _, err = manager.Attach(context.Background(), "user:a", "unknown")
+	assert.ErrorIs(t, err, ports.ErrReadSessionNotFound)
+}
+
+// TestReadSessionManager_SerializesRequests проверяет, что второй запрос
+// сессии ждёт, пока первый освободит соединение.
+func TestReadSessionManager_SerializesRequests(t *testing.T) {
+	manager := NewReadSessionManager(&fakeSessionDB{}, ReadSessionConfig{}, nil)
+	defer manager.Close()
+
+	session, err := manager.Open(context.Background(), "user:a")
+	require.NoError(t, err)
+
+	_, release, err := manager.Attach(context.Background(), "user:a", session.Token)
+	require.NoError(t, err)
+
+	waitCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, _, err = manager.Attach(waitCtx, "user:a", session.Token)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+	release() // Повторный release - no-op
+
+	_, release, err = manager.Attach(context.Background(), "user:a", session.Token)
+	require.NoError(t, err)
+	release()
+}
+
+func TestReadSessionManager_Limits(t *testing.T) {
+	manager := NewReadSessionManager(&fakeSessionDB{}, ReadSessionConfig{MaxPerClient: 2, MaxTotal: 3}, nil)
+	defer manager.Close()
+	ctx := context.Background()
+
+	for range 2 {
+		_, err := manager.Open(ctx, "user:a")
+		require.NoError(t, err)
+	}
+	_, err := manager.Open(ctx, "user:a")
+	requireDomainCode(t, err, ports.ReadSessionLimitCode)
+
+	// Лимит клиента не влияет на других клиентов, но общий лимит - да
+	_, err = manager.Open(ctx, "user:b")
+	require.NoError(t, err)
+	_, err = manager.Open(ctx, "user:c")
+	requireDomainCode(t, err, ports.ReadSessionLimitCode)
+	assert.Equal(t, 3, manager.size())
+}
+
+// TestReadSessionManager_Expiry проверяет, что истёкшая сессия откатывает
+// транзакцию (соединение возвращается в пул) и освобождает лимит.
+func TestReadSessionManager_Expiry(t *testing.T) {
+	db := &fakeSessionDB{}
+	manager := NewReadSessionManager(db, ReadSessionConfig{TTL: 30 * time.Millisecond, MaxPerClient: 1}, nil)
+	defer manager.Close()
+	ctx := context.Background()
+
+	session, err := manager.Open(ctx, "user:a")
+	require.NoError(t, err)
+
+	// Запрос в процессе: откат ждёт его завершения
+	_, release, err := manager.Attach(ctx, "user:a", session.Token)
+	require.NoError(t, err)
+	time.Sleep(60 * time.Millisecond)
+	assert.False(t, db.txs[0].isRolledBack(), "rollback must wait for the in-flight request")
+
+	_, _, err = manager.Attach(ctx, "user:a", session.Token)
+	assert.ErrorIs(t, err, ports.ErrReadSessionNotFound)
+
+	release()
+	require.Eventually(t, func() bool { return manager.size() == 0 }, time.Second, 5*time.Millisecond)
+	assert.True(t, db.txs[0].isRolledBack())
+
+	// Место клиента освободилось
+	_, err = manager.Open(ctx, "user:a")
+	require.NoError(t, err)
+}
+
+func TestReadSessionManager_Close(t *testing.T) {
+	db := &fakeSessionDB{}
+	manager := NewReadSessionManager(db, ReadSessionConfig{}, nil)
+
+	_, err := manager.Open(context.Background(), "user:a")
+	require.NoError(t, err)
+
+	manager.Close()
+	assert.True(t, db.txs[0].isRolledBack())
+	assert.Equal(t, 0, manager.size())
+
+	_, err = manager.Open(context.Background(), "user:a")
+	assert.Error(t, err)
+	assert.True(t, db.txs[1].isRolledBack())
+	assert.False(t, errors.Is(err, ports.ErrReadSessionNotFound))
+}
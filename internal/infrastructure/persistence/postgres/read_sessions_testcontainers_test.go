@@ -0,0 +1,111 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// TestReadSessionManager_Integration_Snapshot проверяет, что записи,
+// закоммиченные после открытия сессии, не видны её чтениям через
+// репозитории, а чтения без сессии их видят.
+func TestReadSessionManager_Integration_Snapshot(t *testing.T) {
+	tc := setupSharedTestDB(t)
+	ctx := context.Background()
+
+	userRepo := NewUserRepository(tc.pool)
+	walletRepo := NewWalletRepository(tc.pool)
+	txRepo := NewTransactionRepository(tc.pool)
+	uow := NewUnitOfWork(tc.pool)
+
+	user, _ := entities.NewUser("snapshot-"+uuid.NewString()+"@example.com", "Snapshot User")
+	require.NoError(t, userRepo.Save(ctx, user))
+	wallet, _ := entities.NewWallet(user.ID(), valueobjects.USD)
+	require.NoError(t, walletRepo.Save(ctx, wallet))
+
+	manager := NewReadSessionManager(tc.pool, ReadSessionConfig{}, nil)
+	defer manager.Close()
+	clientID := "user:" + user.ID().String()
+
+	session, err := manager.Open(ctx, clientID)
+	require.NoError(t, err)
+
+	// Пополнение, закоммиченное после открытия сессии
+	amount, _ := valueobjects.NewMoney("25.00", valueobjects.USD)
+	deposit, _ := entities.NewTransaction(wallet.ID(), uuid.NewString(), entities.TransactionTypeDeposit, amount, "Deposit")
+	require.NoError(t, uow.Execute(ctx, func(txCtx context.Context) error {
+		if err := wallet.Credit(amount); err != nil {
+			return err
+		}
+		if err := walletRepo.Save(txCtx, wallet); err != nil {
+			return err
+		}
+		return txRepo.Save(txCtx, deposit)
+	}))
+
+	// Два "запроса страниц" сессии видят состояние на момент открытия
+	for range 2 {
+		sessionCtx, release, err := manager.Attach(ctx, clientID, session.Token)
+		require.NoError(t, err)
+
+		loaded, err := walletRepo.FindByID(sessionCtx, wallet.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "0.00 USD", loaded.AvailableBalance().String())
+
+		txs, err := txRepo.FindByWalletID(sessionCtx, wallet.ID(), 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, txs)
+		release()
+	}
+
+	// Без сессии запись видна
+	loaded, err := walletRepo.FindByID(ctx, wallet.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "25.00 USD", loaded.AvailableBalance().String())
+
+	// Транзакция сессии только для чтения; ошибка прерывает её, и сессия
+	// закрывается
+	sessionCtx, release, err := manager.Attach(ctx, clientID, session.Token)
+	require.NoError(t, err)
+	other, _ := entities.NewUser("snapshot-write-"+uuid.NewString()+"@example.com", "Write Attempt")
+	assert.Error(t, userRepo.Save(sessionCtx, other))
+	release()
+
+	require.Eventually(t, func() bool {
+		_, release, err := manager.Attach(ctx, clientID, session.Token)
+		if err == nil {
+			release()
+		}
+		return errors.Is(err, ports.ErrReadSessionNotFound)
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+// TestReadSessionManager_Integration_ExpiryReleasesConnection проверяет,
+// что истёкшая сессия возвращает соединение в пул.
+func TestReadSessionManager_Integration_ExpiryReleasesConnection(t *testing.T) {
+	tc := setupSharedTestDB(t)
+	ctx := context.Background()
+
+	baseline := tc.pool.Stat().AcquiredConns()
+	manager := NewReadSessionManager(tc.pool, ReadSessionConfig{TTL: 100 * time.Millisecond}, nil)
+	defer manager.Close()
+
+	_, err := manager.Open(ctx, "user:expiry")
+	require.NoError(t, err)
+	assert.Equal(t, baseline+1, tc.pool.Stat().AcquiredConns())
+
+	require.Eventually(t, func() bool {
+		return tc.pool.Stat().AcquiredConns() == baseline && manager.size() == 0
+	}, 5*time.Second, 20*time.Millisecond)
+}
@@ -25,8 +25,10 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
 
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
 	"github.com/Haleralex/wallethub/internal/domain/entities"
 	domerrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
 	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
 	"github.com/google/uuid"
 )
@@ -111,7 +113,7 @@ func cleanupTables(t *testing.T, pool *pgxpool.Pool) {
 	ctx := context.Background()
 
 	// Важно: очищаем в правильном порядке из-за foreign keys
-	tables := []string{"outbox_events", "transactions", "wallets", "users"}
+	tables := []string{"outbox", "transactions", "wallets", "users"}
 	for _, table := range tables {
 		_, err := pool.Exec(ctx, fmt.Sprintf("TRUNCATE TABLE %s CASCADE", table))
 		if err != nil {
@@ -471,7 +473,7 @@ func TestTransactionRepository_Integration_FindByIdempotencyKey(t *testing.T) {
 	t.Run("NotFound", func(t *testing.T) {
 		found, err := txRepo.FindByIdempotencyKey(ctx, uuid.New().String())
 
-		assert.NoError(t, err) // Repository returns nil, nil when not found
+		assert.True(t, domerrors.IsNotFound(err))
 		assert.Nil(t, found)
 	})
 }
@@ -635,3 +637,48 @@ func TestUnitOfWork_Integration_AtomicTransfer(t *testing.T) {
 	assert.Equal(t, "900.00 USD", w1.AvailableBalance().String(), "Wallet1 should have 900 USD")
 	assert.Equal(t, "100.00 USD", w2.AvailableBalance().String(), "Wallet2 should have 100 USD")
 }
+
+// ============================================
+// Conformance Tests (ports/porttest)
+// ============================================
+
+// newConformanceRepositories возвращает репозитории над очищенной БД.
+func newConformanceRepositories(t *testing.T) porttest.Repositories {
+	tc := setupSharedTestDB(t)
+	return porttest.Repositories{
+		Users:        NewUserRepository(tc.pool),
+		Wallets:      NewWalletRepository(tc.pool),
+		Transactions: NewTransactionRepository(tc.pool),
+	}
+}
+
+func TestUserRepository_Conformance(t *testing.T) {
+	porttest.RunUserRepositoryTests(t, newConformanceRepositories)
+}
+
+func TestWalletRepository_Conformance(t *testing.T) {
+	porttest.RunWalletRepositoryTests(t, newConformanceRepositories)
+}
+
+func TestTransactionRepository_Conformance(t *testing.T) {
+	porttest.RunTransactionRepositoryTests(t, newConformanceRepositories)
+}
+
+func TestTransactionBackfillRepository_Conformance(t *testing.T) {
+	porttest.RunTransactionBackfillRepositoryTests(t, newConformanceRepositories)
+}
+
+func TestOutboxRepository_EventPublisherConformance(t *testing.T) {
+	porttest.RunEventPublisherTests(t, func(t *testing.T) porttest.EventPublisherHarness {
+		tc := setupSharedTestDB(t)
+		outbox := NewOutboxRepository(tc.pool, "", nil)
+		return porttest.EventPublisherHarness{
+			Publisher: outbox,
+			Delivered: func(t *testing.T) []events.DomainEvent {
+				delivered, err := outbox.FindUnpublished(context.Background(), 100)
+				require.NoError(t, err)
+				return delivered
+			},
+		}
+	})
+}
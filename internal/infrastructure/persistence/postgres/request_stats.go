@@ -0,0 +1,54 @@
+// Package postgres - учёт SQL запросов в ports.RequestStats и ports.UnitOfWorkGuard.
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// statsQuerier считает запросы и время в БД для текущего HTTP запроса.
+//
+// Время Query - до получения первого ответа, без чтения rows.
+// BEGIN/COMMIT UnitOfWork не учитываются.
+type statsQuerier struct {
+	next  querier
+	stats *ports.RequestStats
+}
+
+// withRequestStats оборачивает querier, если в context есть RequestStats.
+// Без RequestStats возвращает q как есть.
+//
+// Через неё репозитории получают querier, поэтому здесь же запрос мимо
+// активной транзакции запроса отдаётся ports.UnitOfWorkGuard.
+func withRequestStats(ctx context.Context, q querier) querier {
+	ports.UnitOfWorkGuardFromContext(ctx).CheckQuery(ctx, hasTx(ctx))
+
+	stats := ports.RequestStatsFromContext(ctx)
+	if stats == nil {
+		return q
+	}
+	return &statsQuerier{next: q, stats: stats}
+}
+
+func (q *statsQuerier) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	start := time.Now()
+	defer func() { q.stats.RecordQuery(time.Since(start)) }()
+	return q.next.Exec(ctx, sql, arguments...)
+}
+
+func (q *statsQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	start := time.Now()
+	defer func() { q.stats.RecordQuery(time.Since(start)) }()
+	return q.next.Query(ctx, sql, args...)
+}
+
+func (q *statsQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	start := time.Now()
+	defer func() { q.stats.RecordQuery(time.Since(start)) }()
+	return q.next.QueryRow(ctx, sql, args...)
+}
@@ -0,0 +1,97 @@
+// Package postgres - SandboxRepository implementation.
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check: SandboxRepository implements ports.SandboxRepository
+var _ ports.SandboxRepository = (*SandboxRepository)(nil)
+
+// SandboxRepository реализует ports.SandboxRepository.
+type SandboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSandboxRepository создаёт новый SandboxRepository.
+func NewSandboxRepository(pool *pgxpool.Pool) *SandboxRepository {
+	return &SandboxRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *SandboxRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// PurgeUserData удаляет транзакции и кошельки пользователя (в этом порядке из-за FK),
+// а также его webhook подписки с доставками.
+func (r *SandboxRepository) PurgeUserData(ctx context.Context, userID uuid.UUID) (ports.SandboxPurgeCounts, error) {
+	q := r.getQuerier(ctx)
+
+	// Входящие переводы от других tenant'ов держат FK на наши кошельки -
+	// удалить кошельки, не трогая чужие строки, нельзя
+	var foreign int64
+	err := q.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM transactions t
+		JOIN wallets src ON src.id = t.wallet_id
+		JOIN wallets dst ON dst.id = t.destination_wallet_id
+		WHERE dst.user_id = $1 AND src.user_id <> $1
+	`, userID).Scan(&foreign)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check cross-tenant references: %w", err)
+	}
+	if foreign > 0 {
+		return nil, domainErrors.NewBusinessRuleViolation(
+			"CROSS_TENANT_REFERENCES",
+			"transactions of other users reference your wallets",
+			map[string]interface{}{"transactions": foreign},
+		)
+	}
+
+	txResult, err := q.Exec(ctx, `
+		DELETE FROM transactions
+		WHERE wallet_id IN (SELECT id FROM wallets WHERE user_id = $1)
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete transactions: %w", err)
+	}
+
+	walletResult, err := q.Exec(ctx, `DELETE FROM wallets WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete wallets: %w", err)
+	}
+
+	// Доставки удалились бы каскадом, но их число нужно в отчёте; подписки
+	// удаляются все, включая мягко удалённые - иначе они получат события
+	// новых данных tenant'а
+	deliveryResult, err := q.Exec(ctx, `
+		DELETE FROM webhook_deliveries
+		WHERE subscription_id IN (SELECT id FROM webhook_subscriptions WHERE user_id = $1)
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+
+	subscriptionResult, err := q.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete webhook subscriptions: %w", err)
+	}
+
+	return ports.SandboxPurgeCounts{
+		"transactions":          txResult.RowsAffected(),
+		"wallets":               walletResult.RowsAffected(),
+		"webhook_deliveries":    deliveryResult.RowsAffected(),
+		"webhook_subscriptions": subscriptionResult.RowsAffected(),
+	}, nil
+}
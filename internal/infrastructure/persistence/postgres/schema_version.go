@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrNoSchemaVersion - таблицы schema_migrations нет или она пуста:
+// миграции (cmd/migrate) ни разу не применялись к этой базе.
+var ErrNoSchemaVersion = errors.New("schema version unknown: no migrations applied")
+
+// SchemaVersion читает версию схемы, записанную golang-migrate (cmd/migrate).
+// dirty - последняя миграция упала посередине и схема в промежуточном
+// состоянии; работать с такой базой нельзя до migrate force.
+func SchemaVersion(ctx context.Context, pool *pgxpool.Pool) (version int64, dirty bool, err error) {
+	err = pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) || isPgError(err, pgUndefinedTable) {
+		return 0, false, ErrNoSchemaVersion
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, dirty, nil
+}
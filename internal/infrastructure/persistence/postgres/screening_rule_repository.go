@@ -0,0 +1,73 @@
+// Package postgres - ScreeningRuleRepository implementation.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check: ScreeningRuleRepository implements ports.ScreeningRuleRepository
+var _ ports.ScreeningRuleRepository = (*ScreeningRuleRepository)(nil)
+
+// ScreeningRuleRepository реализует ports.ScreeningRuleRepository (таблица screening_rules).
+type ScreeningRuleRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewScreeningRuleRepository создаёт новый ScreeningRuleRepository.
+func NewScreeningRuleRepository(pool *pgxpool.Pool) *ScreeningRuleRepository {
+	return &ScreeningRuleRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *ScreeningRuleRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// ListEnabled возвращает включённые правила в порядке ID.
+// Условия не валидируются - это делает screening.NewRuleSet при старте.
+func (r *ScreeningRuleRepository) ListEnabled(ctx context.Context) ([]ports.ScreeningRule, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT id, description, action, conditions
+		FROM screening_rules
+		WHERE enabled
+		ORDER BY id
+	`
+
+	rows, err := q.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list screening rules: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]ports.ScreeningRule, 0)
+	for rows.Next() {
+		var (
+			rule       ports.ScreeningRule
+			conditions []byte
+		)
+		if err := rows.Scan(&rule.ID, &rule.Description, &rule.Action, &conditions); err != nil {
+			return nil, fmt.Errorf("failed to scan screening rule row: %w", err)
+		}
+		if err := json.Unmarshal(conditions, &rule.Conditions); err != nil {
+			return nil, fmt.Errorf("failed to decode conditions of screening rule %q: %w", rule.ID, err)
+		}
+		result = append(result, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating screening rule rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,116 @@
+// Package postgres - SecurityEventRepository implementation.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// Compile-time check: SecurityEventRepository implements ports.SecurityEventRepository
+var _ ports.SecurityEventRepository = (*SecurityEventRepository)(nil)
+
+// SecurityEventRepository реализует ports.SecurityEventRepository (таблица security_events).
+type SecurityEventRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSecurityEventRepository создаёт новый SecurityEventRepository.
+func NewSecurityEventRepository(pool *pgxpool.Pool) *SecurityEventRepository {
+	return &SecurityEventRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *SecurityEventRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// Append добавляет событие в журнал.
+func (r *SecurityEventRepository) Append(ctx context.Context, event *entities.SecurityEvent) error {
+	q := r.getQuerier(ctx)
+
+	query := `
+		INSERT INTO security_events (id, principal, event_type, ip, user_agent, occurred_at)
+		VALUES ($1, NULLIF($2, ''), $3, NULLIF($4, ''), NULLIF($5, ''), $6)
+	`
+
+	_, err := q.Exec(ctx, query,
+		event.ID(),
+		event.Principal(),
+		string(event.Type()),
+		event.IP(),
+		event.UserAgent(),
+		event.OccurredAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to append security event: %w", err)
+	}
+
+	return nil
+}
+
+// List возвращает события с фильтрацией и пагинацией, новые первыми.
+func (r *SecurityEventRepository) List(ctx context.Context, filter ports.SecurityEventFilter, offset, limit int) ([]*entities.SecurityEvent, error) {
+	q := r.getQuerier(ctx)
+
+	sq := newSelect(`
+		SELECT id, principal, event_type, ip, user_agent, occurred_at
+		FROM security_events`)
+	whereOpt(sq, "principal = ?", filter.Principal)
+	whereOptFunc(sq, "event_type = ?", filter.Type, asString)
+	whereOpt(sq, "ip = ?", filter.IP)
+	whereOptFunc(sq, "occurred_at >= ?", filter.OccurredFrom, time.Time.UTC)
+	whereOptFunc(sq, "occurred_at < ?", filter.OccurredTo, time.Time.UTC)
+	query, args := sq.OrderBy("occurred_at DESC, id DESC").Page(offset, limit).SQL()
+
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list security events: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*entities.SecurityEvent, 0)
+	for rows.Next() {
+		var (
+			id                       uuid.UUID
+			principal, ip, userAgent *string
+			eventType                string
+			occurredAt               time.Time
+		)
+		if err := rows.Scan(&id, &principal, &eventType, &ip, &userAgent, &occurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan security event row: %w", err)
+		}
+
+		result = append(result, entities.ReconstructSecurityEvent(
+			id, derefString(principal), entities.SecurityEventType(eventType),
+			derefString(ip), derefString(userAgent), occurredAt,
+		))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating security event rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// DeleteBefore удаляет события старше cutoff.
+func (r *SecurityEventRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	q := r.getQuerier(ctx)
+
+	tag, err := q.Exec(ctx, `DELETE FROM security_events WHERE occurred_at < $1`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete security events: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
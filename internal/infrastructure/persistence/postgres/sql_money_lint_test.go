@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Денежные значения - BIGINT в минимальных единицах, агрегаты - NUMERIC
+// (см. amount_sum.go). Тесты ниже не дают вернуть float в схему и запросы.
+
+var (
+	// floatMoneyColumn - колонка с денежным именем и float типом
+	// (CREATE TABLE, ADD COLUMN, ALTER COLUMN ... TYPE).
+	floatMoneyColumn = regexp.MustCompile(`(?i)\b(\w*(?:amount|balance|limit|fee)\w*)\s+(?:(?:SET\s+DATA\s+)?TYPE\s+)?(real|float[48]?|double\s+precision)\b`)
+	// floatCast - приведение к float: ::float8, ::double precision, CAST(... AS real).
+	floatCast = regexp.MustCompile(`(?i)::\s*(?:real|float[48]?|double\s+precision)\b|\bAS\s+(?:real|float[48]?|double\s+precision)\s*\)`)
+	// sqlLineComment - комментарий -- до конца строки.
+	sqlLineComment = regexp.MustCompile(`--[^\n]*`)
+)
+
+// floatMoneyViolations возвращает найденные в SQL float колонки и приведения.
+func floatMoneyViolations(sql string) []string {
+	sql = sqlLineComment.ReplaceAllString(sql, "")
+
+	var found []string
+	for _, match := range floatMoneyColumn.FindAllString(sql, -1) {
+		found = append(found, strings.Join(strings.Fields(match), " "))
+	}
+	for _, match := range floatCast.FindAllString(sql, -1) {
+		found = append(found, strings.Join(strings.Fields(match), " "))
+	}
+	return found
+}
+
+func TestFloatMoneyViolations(t *testing.T) {
+	for _, sql := range []string{
+		"ALTER TABLE wallets ADD COLUMN daily_limit DOUBLE PRECISION",
+		"CREATE TABLE t (fee_amount real NOT NULL)",
+		"ALTER TABLE wallets ALTER COLUMN balance TYPE float8",
+		"SELECT SUM(amount)::float FROM transactions",
+		"SELECT AVG(amount)::double precision FROM transactions",
+		"SELECT CAST(SUM(amount) AS float8) FROM transactions",
+	} {
+		assert.NotEmpty(t, floatMoneyViolations(sql), sql)
+	}
+
+	for _, sql := range []string{
+		"ALTER TABLE wallets ADD COLUMN daily_limit BIGINT NOT NULL DEFAULT 0",
+		"SELECT COALESCE(SUM(amount), 0)::text FROM transactions",
+		"rate NUMERIC(24, 12) NOT NULL",
+		"score REAL NOT NULL",
+		"-- amount DOUBLE PRECISION в комментарии не считается",
+	} {
+		assert.Empty(t, floatMoneyViolations(sql), sql)
+	}
+}
+
+// TestMigrations_NoFloatMoney проверяет миграции: и рабочие (/migrations),
+// и схему тестового контейнера.
+func TestMigrations_NoFloatMoney(t *testing.T) {
+	var files []string
+	for _, dir := range []string{
+		filepath.Join("..", "..", "..", "..", "migrations"),
+		filepath.Join("..", "migrations"),
+	} {
+		matches, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+		require.NoError(t, err)
+		require.NotEmpty(t, matches, dir)
+		files = append(files, matches...)
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Empty(t, floatMoneyViolations(string(content)), file)
+	}
+}
+
+// TestRepositorySQL_NoFloatCasts проверяет строковые литералы пакета:
+// запросы репозиториев не приводят суммы к float.
+func TestRepositorySQL_NoFloatCasts(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		require.NoError(t, err)
+
+		ast.Inspect(parsed, func(node ast.Node) bool {
+			lit, ok := node.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			value, err := strconv.Unquote(lit.Value)
+			require.NoError(t, err)
+			assert.Empty(t, floatMoneyViolations(value), fset.Position(lit.Pos()).String())
+			return true
+		})
+	}
+}
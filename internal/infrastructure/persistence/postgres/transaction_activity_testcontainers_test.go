@@ -0,0 +1,30 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+func TestTransactionActivity_Conformance(t *testing.T) {
+	porttest.RunTransactionActivityTests(t, func(t *testing.T) porttest.TransactionActivityHarness {
+		tc := setupReceiptDB(t)
+
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000044_create_transactions_destination_index.up.sql"))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(context.Background(), string(migration))
+		require.NoError(t, err)
+
+		return porttest.TransactionActivityHarness{
+			Repositories: newConformanceRepositories(t),
+			Activity:     NewTransactionRepository(tc.pool),
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check
+var _ ports.TransactionBackfillRepository = (*TransactionRepository)(nil)
+
+// backfillColumns - колонки transactions в порядке значений backfillRow.
+// environment не пишется (остаётся пустым): перенесённые транзакции созданы до
+// учёта окружения и их ключи видны из любого (см. WithEnvironment).
+var backfillColumns = []string{
+	"id", "wallet_id", "idempotency_key", "transaction_type", "status",
+	"amount", "fee_amount", "net_amount", "currency", "destination_wallet_id", "external_reference",
+	"external_reference_hash", "description", "metadata", "failure_reason", "failure_category", "retry_count", "next_retry_at", "jurisdiction",
+	"created_by_version", "created_at", "updated_at", "processed_at", "completed_at",
+}
+
+// ExistingIDs возвращает ID из ids, уже сохранённые в transactions.
+func (r *TransactionRepository) ExistingIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]struct{}, error) {
+	q := r.getQuerier(ctx)
+
+	rows, err := q.Query(ctx, `SELECT id FROM transactions WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query existing transactions: %w", err)
+	}
+	defer rows.Close()
+
+	existing := make(map[uuid.UUID]struct{})
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction id: %w", err)
+		}
+		existing[id] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate existing transactions: %w", err)
+	}
+	return existing, nil
+}
+
+// InsertBatch записывает пачку через COPY - на порядок быстрее INSERT по
+// строке. COPY атомарен: при ошибке любой строки не записывается ничего.
+// Триггер updated_at срабатывает только на UPDATE, поэтому исходные
+// timestamps сохраняются как есть.
+func (r *TransactionRepository) InsertBatch(ctx context.Context, transactions []*entities.Transaction) error {
+	rows := make([][]any, 0, len(transactions))
+	for _, tx := range transactions {
+		row, err := backfillRow(tx)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+
+	var err error
+	if tx := extractTx(ctx); tx != nil {
+		_, err = tx.CopyFrom(ctx, pgx.Identifier{"transactions"}, backfillColumns, pgx.CopyFromRows(rows))
+	} else {
+		_, err = r.pool.CopyFrom(ctx, pgx.Identifier{"transactions"}, backfillColumns, pgx.CopyFromRows(rows))
+	}
+
+	if err != nil {
+		if isUniqueViolation(err, "") {
+			return domainErrors.ErrDuplicateTransaction
+		}
+		if isForeignKeyViolation(err) {
+			return domainErrors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", err)
+		}
+		return fmt.Errorf("failed to copy transactions: %w", err)
+	}
+	return nil
+}
+
+// backfillRow - значения строки COPY (пустые строки - NULL, как NULLIF в Save).
+func backfillRow(tx *entities.Transaction) ([]any, error) {
+	metadataJSON, err := json.Marshal(tx.Metadata())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata of transaction %s: %w", tx.ID(), err)
+	}
+
+	return []any{
+		tx.ID(),
+		tx.WalletID(),
+		tx.IdempotencyKey(),
+		string(tx.Type()),
+		string(tx.Status()),
+		tx.Amount().Cents(),
+		tx.FeeAmount().Cents(),
+		tx.NetAmount().Cents(),
+		tx.Amount().Currency().Code(),
+		tx.DestinationWalletID(),
+		tx.ExternalReference(),
+		nullIfEmpty(tx.ExternalReferenceHash()),
+		tx.Description(),
+		metadataJSON,
+		tx.FailureReason(),
+		nullIfEmpty(string(tx.FailureCategory())),
+		tx.RetryCount(),
+		tx.NextRetryAt(),
+		nullIfEmpty(tx.Jurisdiction()),
+		nullIfEmpty(tx.CreatedByVersion()),
+		tx.CreatedAt(),
+		tx.UpdatedAt(),
+		tx.ProcessedAt(),
+		tx.CompletedAt(),
+	}, nil
+}
+
+// nullIfEmpty превращает пустую строку в NULL.
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,170 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/usecases/backfill"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+const (
+	backfillFixtureRows    = 10_000
+	backfillFixtureWallets = 50
+	backfillBatchSize      = 2_500
+
+	// backfillBudget - бюджет на импорт фикстуры. COPY укладывается в
+	// доли секунды; запас на медленные CI раннеры.
+	backfillBudget = 15 * time.Second
+)
+
+// backfillFixture - 10k записей legacy выгрузки и ожидаемые балансы кошельков.
+type backfillFixture struct {
+	records  []dtos.BackfillTransactionRecord
+	expected []dtos.BackfillExpectedBalance
+}
+
+// newBackfillFixture создаёт кошельки и историю: депозиты, переводы с
+// комиссией и проваленные выводы (не влияют на баланс).
+func newBackfillFixture(t *testing.T, tc *testContainer) backfillFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	userRepo := NewUserRepository(tc.pool)
+	walletRepo := NewWalletRepository(tc.pool)
+	usd, _ := valueobjects.NewCurrency("USD")
+
+	wallets := make([]string, 0, backfillFixtureWallets)
+	for i := 0; i < backfillFixtureWallets; i++ {
+		user, err := entities.NewUser(uuid.NewString()+"@example.com", "Legacy User")
+		require.NoError(t, err)
+		require.NoError(t, userRepo.Save(ctx, user))
+		wallet, err := entities.NewWallet(user.ID(), usd)
+		require.NoError(t, err)
+		require.NoError(t, walletRepo.Save(ctx, wallet))
+		wallets = append(wallets, wallet.ID().String())
+	}
+
+	var fixture backfillFixture
+	balances := make(map[string]int64, len(wallets))
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < backfillFixtureRows; i++ {
+		createdAt := start.Add(time.Duration(i) * time.Minute)
+		completedAt := createdAt.Add(time.Second)
+		source := wallets[i%len(wallets)]
+		record := dtos.BackfillTransactionRecord{
+			ID:           uuid.NewString(),
+			WalletID:     source,
+			Status:       string(entities.TransactionStatusCompleted),
+			CurrencyCode: "USD",
+			CreatedAt:    createdAt,
+			CompletedAt:  &completedAt,
+		}
+
+		switch i % 4 {
+		case 0, 1:
+			record.Type = string(entities.TransactionTypeDeposit)
+			record.AmountCents = 10_000
+			balances[source] += 10_000
+		case 2:
+			dest := wallets[(i+1)%len(wallets)]
+			record.Type = string(entities.TransactionTypeTransfer)
+			record.DestinationWalletID = &dest
+			record.AmountCents = 1_000
+			record.FeeCents = 10
+			balances[source] -= 1_000
+			balances[dest] += 990
+		case 3:
+			record.Type = string(entities.TransactionTypeWithdraw)
+			record.Status = string(entities.TransactionStatusFailed)
+			record.CompletedAt = nil
+			record.AmountCents = 500
+			record.FailureReason = "TIMEOUT"
+		}
+		fixture.records = append(fixture.records, record)
+	}
+
+	for walletID, cents := range balances {
+		fixture.expected = append(fixture.expected, dtos.BackfillExpectedBalance{WalletID: walletID, BalanceCents: cents})
+	}
+	return fixture
+}
+
+// importFixture импортирует записи пачками, как cmd/backfill.
+func importFixture(t *testing.T, uc *backfill.BackfillTransactionsUseCase, records []dtos.BackfillTransactionRecord) (int, int, *backfill.Reconciliation) {
+	t.Helper()
+
+	reconciliation := backfill.NewReconciliation()
+	imported, skipped := 0, 0
+	for start := 0; start < len(records); start += backfillBatchSize {
+		end := min(start+backfillBatchSize, len(records))
+		result, err := uc.Execute(context.Background(), dtos.BackfillTransactionsCommand{Records: records[start:end]})
+		require.NoError(t, err)
+		reconciliation.Add(result)
+		imported += result.Imported
+		skipped += result.Skipped
+	}
+	return imported, skipped, reconciliation
+}
+
+func TestBackfillTransactions_Integration_Fixture(t *testing.T) {
+	tc := setupSharedTestDB(t)
+	ctx := context.Background()
+	fixture := newBackfillFixture(t, tc)
+	uc := backfill.NewBackfillTransactionsUseCase(NewTransactionRepository(tc.pool))
+
+	started := time.Now()
+	imported, skipped, reconciliation := importFixture(t, uc, fixture.records)
+	elapsed := time.Since(started)
+
+	assert.Equal(t, backfillFixtureRows, imported)
+	assert.Zero(t, skipped)
+	assert.Less(t, elapsed, backfillBudget, "importing %d rows took %s", backfillFixtureRows, elapsed)
+	t.Logf("imported %d rows in %s", imported, elapsed)
+
+	report, err := reconciliation.Check(fixture.expected)
+	require.NoError(t, err)
+	assert.Equal(t, backfillFixtureWallets, report.Wallets)
+	assert.Empty(t, report.Mismatches)
+
+	t.Run("RowsKeepLegacyData", func(t *testing.T) {
+		var legacy int
+		err := tc.pool.QueryRow(ctx,
+			`SELECT COUNT(*) FROM transactions WHERE metadata->>'source' = 'legacy'`).Scan(&legacy)
+		require.NoError(t, err)
+		assert.Equal(t, backfillFixtureRows, legacy)
+
+		first := fixture.records[0]
+		loaded, err := NewTransactionRepository(tc.pool).FindByID(ctx, uuid.MustParse(first.ID))
+		require.NoError(t, err)
+		assert.True(t, first.CreatedAt.Equal(loaded.CreatedAt()))
+		assert.True(t, first.CompletedAt.Equal(loaded.UpdatedAt()), "updated_at must not be touched by triggers")
+	})
+
+	t.Run("NoEventsNoBalanceChanges", func(t *testing.T) {
+		var outbox, balance int64
+		require.NoError(t, tc.pool.QueryRow(ctx, `SELECT COUNT(*) FROM outbox`).Scan(&outbox))
+		require.NoError(t, tc.pool.QueryRow(ctx, `SELECT COALESCE(SUM(available_balance), 0) FROM wallets`).Scan(&balance))
+		assert.Zero(t, outbox)
+		assert.Zero(t, balance)
+	})
+
+	t.Run("RerunSkipsImported", func(t *testing.T) {
+		imported, skipped, reconciliation := importFixture(t, uc, fixture.records)
+		assert.Zero(t, imported)
+		assert.Equal(t, backfillFixtureRows, skipped)
+
+		report, err := reconciliation.Check(fixture.expected)
+		require.NoError(t, err)
+		assert.Empty(t, report.Mismatches)
+	})
+}
@@ -0,0 +1,119 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/usecases/transaction"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// TestTransactionRepository_Integration_UnknownEnumValues моделирует rolling
+// deploy: строку с типом CHARGEBACK записал более новый релиз, а читает
+// текущая версия, которая этот тип не знает.
+func TestTransactionRepository_Integration_UnknownEnumValues(t *testing.T) {
+	tc := setupSharedTestDB(t)
+	ctx := context.Background()
+
+	// Ограничение типа снимает миграция нового релиза
+	_, err := tc.pool.Exec(ctx, "ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_transaction_type_check")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := tc.pool.Exec(ctx, "DELETE FROM transactions WHERE transaction_type = 'CHARGEBACK'")
+		require.NoError(t, err)
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000006_add_exchange_transaction_type.up.sql"))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(ctx, string(migration))
+		require.NoError(t, err)
+	})
+
+	user, err := entities.NewUser("chargeback@example.com", "Chargeback User")
+	require.NoError(t, err)
+	require.NoError(t, NewUserRepository(tc.pool).Save(ctx, user))
+	wallet, err := entities.NewWallet(user.ID(), valueobjects.USD)
+	require.NoError(t, err)
+	require.NoError(t, NewWalletRepository(tc.pool).Save(ctx, wallet))
+
+	repo := NewTransactionRepository(tc.pool)
+	amount, err := valueobjects.NewMoney("10.00", valueobjects.USD)
+	require.NoError(t, err)
+	deposit, err := entities.NewTransaction(wallet.ID(), uuid.NewString(), entities.TransactionTypeDeposit, amount, "deposit")
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, deposit))
+
+	chargebackID := uuid.New()
+	_, err = tc.pool.Exec(ctx, `
+		INSERT INTO transactions (id, wallet_id, idempotency_key, transaction_type, status, amount, currency, description)
+		VALUES ($1, $2, $3, 'CHARGEBACK', 'PENDING', 1000, 'USD', 'chargeback')`,
+		chargebackID, wallet.ID(), uuid.NewString(),
+	)
+	require.NoError(t, err)
+
+	walletID := wallet.ID().String()
+
+	t.Run("Read", func(t *testing.T) {
+		tx, err := repo.FindByID(ctx, chargebackID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.TransactionType("CHARGEBACK"), tx.Type())
+		assert.False(t, tx.Type().IsKnown())
+
+		dto := dtos.ToTransactionDTO(tx)
+		assert.Equal(t, "CHARGEBACK", dto.Type)
+		assert.Equal(t, "PENDING", dto.Status)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		result, err := transaction.NewListTransactionsUseCase(repo).Execute(ctx, dtos.ListTransactionsQuery{WalletID: &walletID, Limit: 10})
+		require.NoError(t, err)
+		assert.Len(t, result.Transactions, 2)
+
+		chargeback := "CHARGEBACK"
+		result, err = transaction.NewListTransactionsUseCase(repo).Execute(ctx, dtos.ListTransactionsQuery{WalletID: &walletID, Type: &chargeback, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, result.Transactions, 1, "unknown type is an ordinary filter term")
+		assert.Equal(t, chargebackID.String(), result.Transactions[0].ID)
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		result, err := transaction.NewExportTransactionsUseCase(repo, 0).Execute(ctx, dtos.ExportTransactionsQuery{WalletID: &walletID})
+		require.NoError(t, err)
+
+		directions := make(map[string]string)
+		for row, err := range result.Rows {
+			require.NoError(t, err)
+			directions[row.Type] = row.Direction
+		}
+		assert.Equal(t, map[string]string{"DEPOSIT": dtos.TransactionDirectionIn, "CHARGEBACK": ""}, directions)
+	})
+
+	t.Run("TransitionsRejected", func(t *testing.T) {
+		for name, transition := range map[string]func(*entities.Transaction) error{
+			"StartProcessing": (*entities.Transaction).StartProcessing,
+			"Cancel":          (*entities.Transaction).Cancel,
+			"MarkFailed": func(tx *entities.Transaction) error {
+				return tx.MarkFailed("INVALID_ACCOUNT", entities.FailureCategoryClient)
+			},
+		} {
+			tx, err := repo.FindByID(ctx, chargebackID)
+			require.NoError(t, err)
+
+			err = transition(tx)
+			var violation *domainErrors.BusinessRuleViolation
+			require.True(t, errors.As(err, &violation), "%s: %v", name, err)
+			assert.Equal(t, "UNSUPPORTED_TRANSACTION", violation.Rule, name)
+			assert.Equal(t, entities.TransactionStatusPending, tx.Status(), name)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+func TestTransactionEnvironment_Conformance(t *testing.T) {
+	porttest.RunTransactionEnvironmentTests(t, func(t *testing.T) porttest.TransactionEnvironmentHarness {
+		tc := setupSharedTestDB(t)
+		ctx := context.Background()
+
+		// По одной миграции на Exec: CREATE INDEX CONCURRENTLY нельзя
+		// выполнять в одном batch с другими запросами
+		for _, name := range []string{
+			"000030_add_transaction_environment.up.sql",
+			"000031_create_transactions_environment_idempotency_index.up.sql",
+			"000032_drop_transactions_idempotency_key_unique.up.sql",
+		} {
+			migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", name))
+			require.NoError(t, err)
+			_, err = tc.pool.Exec(ctx, string(migration))
+			require.NoError(t, err, name)
+		}
+		// Тестовая схема держит ключ уникальным в кошельке - снимаем и это ограничение
+		_, err := tc.pool.Exec(ctx, "ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_idempotency_unique")
+		require.NoError(t, err)
+
+		return porttest.TransactionEnvironmentHarness{
+			Repositories: newConformanceRepositories(t),
+			Sandbox:      NewTransactionRepository(tc.pool).WithEnvironment("sandbox"),
+			Production:   NewTransactionRepository(tc.pool).WithEnvironment("production"),
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// Compile-time check
+var _ ports.TransactionReceiptRepository = (*TransactionRepository)(nil)
+
+// FindByReceiptNumber загружает транзакцию по номеру чека
+// (idx_transactions_receipt_number, миграция 000040).
+func (r *TransactionRepository) FindByReceiptNumber(ctx context.Context, number string) (*entities.Transaction, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT id, wallet_id, idempotency_key, transaction_type, status,
+			   amount, fee_amount, net_amount, currency, destination_wallet_id, external_reference,
+			   external_reference_hash, description, metadata, failure_reason, failure_category, retry_count, next_retry_at, jurisdiction, created_by_version,
+			   created_at, updated_at, processed_at, completed_at, receipt_number
+		FROM transactions
+		WHERE receipt_number = $1
+	`
+
+	return r.scanTransaction(q.QueryRow(ctx, query, number))
+}
+
+// assignReceiptNumber выдаёт номер чека COMPLETED транзакции без номера.
+// nextval не откатывается и не выдаёт одно значение дважды, поэтому
+// параллельные завершения получают разные номера без повторных попыток
+// (пропуски в последовательности допустимы). Номер, уже сохранённый
+// в строке, Save не перезаписывает.
+func (r *TransactionRepository) assignReceiptNumber(ctx context.Context, q querier, tx *entities.Transaction) error {
+	if tx.Status() != entities.TransactionStatusCompleted || tx.ReceiptNumber() != "" {
+		return nil
+	}
+
+	var sequence int64
+	if err := q.QueryRow(ctx, `SELECT nextval('transaction_receipt_seq')`).Scan(&sequence); err != nil {
+		return fmt.Errorf("failed to allocate receipt number: %w", err)
+	}
+
+	number, err := valueobjects.NewReceiptNumber(sequence)
+	if err != nil {
+		return fmt.Errorf("failed to format receipt number: %w", err)
+	}
+	return tx.AssignReceiptNumber(number)
+}
@@ -0,0 +1,46 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+// setupReceiptDB применяет миграции номеров чеков.
+func setupReceiptDB(t *testing.T) *testContainer {
+	tc := setupSharedTestDB(t)
+	ctx := context.Background()
+
+	// По одной миграции на Exec: CREATE INDEX CONCURRENTLY нельзя
+	// выполнять в одном batch с другими запросами
+	for _, name := range []string{
+		"000030_add_transaction_environment.up.sql",
+		"000035_add_transaction_processing_owner.up.sql",
+		"000039_add_transaction_receipts.up.sql",
+		"000040_create_transactions_receipt_index.up.sql",
+	} {
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", name))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(ctx, string(migration))
+		require.NoError(t, err, name)
+	}
+
+	return tc
+}
+
+func TestTransactionReceipts_Conformance(t *testing.T) {
+	porttest.RunTransactionReceiptTests(t, func(t *testing.T) porttest.TransactionReceiptHarness {
+		tc := setupReceiptDB(t)
+		return porttest.TransactionReceiptHarness{
+			Repositories: newConformanceRepositories(t),
+			Receipts:     NewTransactionRepository(tc.pool),
+		}
+	})
+}
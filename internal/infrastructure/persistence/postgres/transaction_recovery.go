@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check
+var _ ports.ProcessingRecoveryRepository = (*TransactionRepository)(nil)
+
+// ClaimOrphaned переназначает instance транзакции PROCESSING упавших
+// экземпляров. FOR UPDATE SKIP LOCKED разводит конкурирующие вызовы, а
+// новый владелец жив - строка не выдаётся повторно.
+func (r *TransactionRepository) ClaimOrphaned(ctx context.Context, instance string, startedBefore, aliveSince time.Time, limit int) ([]uuid.UUID, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		UPDATE transactions SET processing_instance_id = $1
+		WHERE id IN (
+			SELECT t.id FROM transactions t
+			WHERE t.status = 'PROCESSING'
+			  AND COALESCE(t.processed_at, t.updated_at) < $2
+			  AND NOT EXISTS (
+				SELECT 1 FROM instances i
+				WHERE i.instance_id = t.processing_instance_id AND i.heartbeat_at >= $3
+			  )
+			ORDER BY COALESCE(t.processed_at, t.updated_at)
+			LIMIT $4
+			FOR UPDATE OF t SKIP LOCKED
+		)
+		AND status = 'PROCESSING'
+		RETURNING id
+	`
+
+	rows, err := q.Query(ctx, query, instance, startedBefore.UTC(), aliveSince.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim orphaned transactions: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan orphaned transaction id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating orphaned transaction rows: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Release снимает владельца с транзакции, всё ещё PROCESSING у instance.
+func (r *TransactionRepository) Release(ctx context.Context, id uuid.UUID, instance string) error {
+	q := r.getQuerier(ctx)
+
+	query := `
+		UPDATE transactions SET processing_instance_id = NULL
+		WHERE id = $1 AND status = 'PROCESSING' AND processing_instance_id = $2
+	`
+
+	if _, err := q.Exec(ctx, query, id, instance); err != nil {
+		return fmt.Errorf("failed to release orphaned transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+// setupRecoveryDB применяет миграции владельцев PROCESSING транзакций.
+func setupRecoveryDB(t *testing.T) *testContainer {
+	tc := setupSharedTestDB(t)
+	ctx := context.Background()
+
+	// По одной миграции на Exec: CREATE INDEX CONCURRENTLY нельзя
+	// выполнять в одном batch с другими запросами
+	for _, name := range []string{
+		"000030_add_transaction_environment.up.sql",
+		"000035_add_transaction_processing_owner.up.sql",
+		"000036_create_transactions_processing_index.up.sql",
+	} {
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", name))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(ctx, string(migration))
+		require.NoError(t, err, name)
+	}
+	_, err := tc.pool.Exec(ctx, "TRUNCATE instances")
+	require.NoError(t, err)
+
+	return tc
+}
+
+func TestProcessingRecovery_Conformance(t *testing.T) {
+	porttest.RunProcessingRecoveryTests(t, func(t *testing.T) porttest.ProcessingRecoveryHarness {
+		tc := setupRecoveryDB(t)
+		return porttest.ProcessingRecoveryHarness{
+			Repositories:  newConformanceRepositories(t),
+			Owner:         NewTransactionRepository(tc.pool).WithInstance("owner"),
+			OwnerInstance: "owner",
+			Recovery:      NewTransactionRepository(tc.pool),
+			Instances:     NewInstanceRepository(tc.pool),
+		}
+	})
+}
+
+// Осиротевшая строка, записанная напрямую (экземпляр упал между
+// StartProcessing и финальным Save), достаётся ровно одному из двух
+// одновременно работающих sweeper'ов.
+func TestProcessingRecovery_Integration_DirectOrphanClaimedOnce(t *testing.T) {
+	tc := setupRecoveryDB(t)
+	ctx := context.Background()
+	repos := newConformanceRepositories(t)
+	instances := NewInstanceRepository(tc.pool)
+
+	user := uuid.New()
+	wallet := uuid.New()
+	_, err := tc.pool.Exec(ctx, `INSERT INTO users (id, email, full_name) VALUES ($1, $2, 'Orphan Owner')`,
+		user, user.String()+"@example.com")
+	require.NoError(t, err)
+	_, err = tc.pool.Exec(ctx, `INSERT INTO wallets (id, user_id, currency) VALUES ($1, $2, 'USD')`, wallet, user)
+	require.NoError(t, err)
+
+	orphan := uuid.New()
+	_, err = tc.pool.Exec(ctx, `
+		INSERT INTO transactions (id, wallet_id, idempotency_key, transaction_type, status, amount, currency,
+			processing_instance_id, processed_at, updated_at)
+		VALUES ($1, $2, $3, 'WITHDRAW', 'PROCESSING', 1000, 'USD', 'crashed-instance', $4, $4)`,
+		orphan, wallet, uuid.NewString(), time.Now().UTC().Add(-time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, instances.Heartbeat(ctx, "crashed-instance", time.Now().UTC().Add(-time.Hour)))
+
+	var (
+		mu      sync.Mutex
+		claimed []uuid.UUID
+		wg      sync.WaitGroup
+	)
+	for _, sweeper := range []string{"sweeper-a", "sweeper-b"} {
+		require.NoError(t, instances.Heartbeat(ctx, sweeper, time.Now().UTC()))
+		recovery := repos.Transactions.(*TransactionRepository)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids, err := recovery.ClaimOrphaned(ctx, sweeper,
+				time.Now().UTC().Add(-5*time.Minute), time.Now().UTC().Add(-time.Minute), 10)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			claimed = append(claimed, ids...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, []uuid.UUID{orphan}, claimed)
+}
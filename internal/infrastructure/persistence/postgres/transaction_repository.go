@@ -24,31 +24,66 @@ var _ ports.TransactionRepository = (*TransactionRepository)(nil)
 // TransactionRepository реализует ports.TransactionRepository.
 //
 // Ключевые особенности:
-// - Idempotency через unique idempotency_key
+// - Idempotency через unique (environment, idempotency_key) (см. WithEnvironment)
+// - Владелец PROCESSING транзакции для восстановления после падения (см. WithInstance)
 // - Metadata хранится как JSONB
 // - Amount хранится как BIGINT (cents/satoshis)
 type TransactionRepository struct {
-	pool *pgxpool.Pool
+	pool        *pgxpool.Pool
+	environment string
+	instance    string
 }
 
 // NewTransactionRepository создаёт новый TransactionRepository.
+// По умолчанию окружение не задано ("").
 func NewTransactionRepository(pool *pgxpool.Pool) *TransactionRepository {
 	return &TransactionRepository{pool: pool}
 }
 
+// WithEnvironment задаёт окружение (app.environment), которым помечаются
+// новые транзакции. Ключ идемпотентности уникален в пределах окружения:
+// sandbox и production в одной базе не видят ключи друг друга.
+// Транзакции с пустым environment (созданные до миграции 000030 и
+// перенесённые backfill'ом) видны из любого окружения.
+func (r *TransactionRepository) WithEnvironment(environment string) *TransactionRepository {
+	r.environment = environment
+	return r
+}
+
+// WithInstance задаёт экземпляр, который записывается владельцем
+// транзакций, сохраняемых в PROCESSING (processing_instance_id). По нему
+// задача processing-recovery отличает транзакции упавших экземпляров.
+func (r *TransactionRepository) WithInstance(instance string) *TransactionRepository {
+	r.instance = instance
+	return r
+}
+
+// processingInstance - владелец для сохраняемой транзакции: только для PROCESSING.
+func (r *TransactionRepository) processingInstance(tx *entities.Transaction) string {
+	if tx.Status() != entities.TransactionStatusProcessing {
+		return ""
+	}
+	return r.instance
+}
+
 // getQuerier возвращает querier из context или pool.
 func (r *TransactionRepository) getQuerier(ctx context.Context) querier {
 	if tx := extractTx(ctx); tx != nil {
-		return tx
+		return withRequestStats(ctx, tx)
 	}
-	return r.pool
+	return withRequestStats(ctx, r.pool)
 }
 
 // Save сохраняет транзакцию.
 // Для новых транзакций - INSERT, для существующих - UPDATE.
+// COMPLETED транзакция без номера чека получает его здесь (см. assignReceiptNumber).
 func (r *TransactionRepository) Save(ctx context.Context, tx *entities.Transaction) error {
 	q := r.getQuerier(ctx)
 
+	if err := r.assignReceiptNumber(ctx, q, tx); err != nil {
+		return err
+	}
+
 	// Сериализуем metadata в JSON
 	metadataJSON, err := json.Marshal(tx.Metadata())
 	if err != nil {
@@ -58,20 +93,27 @@ func (r *TransactionRepository) Save(ctx context.Context, tx *entities.Transacti
 	query := `
 		INSERT INTO transactions (
 			id, wallet_id, idempotency_key, transaction_type, status,
-			amount, currency, destination_wallet_id, external_reference,
-			description, metadata, failure_reason, retry_count,
-			created_at, updated_at, processed_at, completed_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+			amount, fee_amount, net_amount, currency, destination_wallet_id, external_reference,
+			external_reference_hash, description, metadata, failure_reason, failure_category, retry_count, next_retry_at, jurisdiction,
+			created_by_version, created_at, updated_at, processed_at, completed_at, environment, processing_instance_id,
+			receipt_number
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''), $13, $14, $15, NULLIF($16, ''), $17, $18, NULLIF($19, ''), NULLIF($20, ''), $21, $22, $23, $24, $25, NULLIF($26, ''),
+			NULLIF($27, ''))
 		ON CONFLICT (id) DO UPDATE SET
 			status = EXCLUDED.status,
 			external_reference = EXCLUDED.external_reference,
+			external_reference_hash = EXCLUDED.external_reference_hash,
 			description = EXCLUDED.description,
 			metadata = EXCLUDED.metadata,
 			failure_reason = EXCLUDED.failure_reason,
+			failure_category = EXCLUDED.failure_category,
 			retry_count = EXCLUDED.retry_count,
+			next_retry_at = EXCLUDED.next_retry_at,
 			updated_at = EXCLUDED.updated_at,
 			processed_at = EXCLUDED.processed_at,
-			completed_at = EXCLUDED.completed_at
+			completed_at = EXCLUDED.completed_at,
+			processing_instance_id = COALESCE(EXCLUDED.processing_instance_id, transactions.processing_instance_id),
+			receipt_number = COALESCE(transactions.receipt_number, EXCLUDED.receipt_number)
 	`
 
 	_, err = q.Exec(ctx, query,
@@ -81,22 +123,33 @@ func (r *TransactionRepository) Save(ctx context.Context, tx *entities.Transacti
 		string(tx.Type()),
 		string(tx.Status()),
 		tx.Amount().Cents(),
+		tx.FeeAmount().Cents(),
+		tx.NetAmount().Cents(),
 		tx.Amount().Currency().Code(),
 		tx.DestinationWalletID(),
 		tx.ExternalReference(),
+		tx.ExternalReferenceHash(),
 		tx.Description(),
 		metadataJSON,
 		tx.FailureReason(),
+		string(tx.FailureCategory()),
 		tx.RetryCount(),
+		tx.NextRetryAt(),
+		tx.Jurisdiction(),
+		tx.CreatedByVersion(),
 		tx.CreatedAt(),
 		tx.UpdatedAt(),
 		tx.ProcessedAt(),
 		tx.CompletedAt(),
+		r.environment,
+		r.processingInstance(tx),
+		tx.ReceiptNumber(),
 	)
 
 	if err != nil {
-		// Проверяем на duplicate idempotency key
-		if isUniqueViolation(err, "transactions_idempotency_key_unique") {
+		// Проверяем на duplicate idempotency key (старый constraint - до миграции 000032)
+		if isUniqueViolation(err, "transactions_environment_idempotency_key_unique") ||
+			isUniqueViolation(err, "transactions_idempotency_key_unique") {
 			return domainErrors.ErrDuplicateTransaction
 		}
 		if isForeignKeyViolation(err) {
@@ -114,9 +167,9 @@ func (r *TransactionRepository) FindByID(ctx context.Context, id uuid.UUID) (*en
 
 	query := `
 		SELECT id, wallet_id, idempotency_key, transaction_type, status,
-			   amount, currency, destination_wallet_id, external_reference,
-			   description, metadata, failure_reason, retry_count,
-			   created_at, updated_at, processed_at, completed_at
+			   amount, fee_amount, net_amount, currency, destination_wallet_id, external_reference,
+			   external_reference_hash, description, metadata, failure_reason, failure_category, retry_count, next_retry_at, jurisdiction, created_by_version,
+			   created_at, updated_at, processed_at, completed_at, receipt_number
 		FROM transactions
 		WHERE id = $1
 	`
@@ -126,27 +179,47 @@ func (r *TransactionRepository) FindByID(ctx context.Context, id uuid.UUID) (*en
 
 // FindByIdempotencyKey находит транзакцию по ключу идемпотентности.
 // Критично для предотвращения дубликатов!
+// Ищет в окружении репозитория и среди транзакций без окружения;
+// транзакция своего окружения важнее.
 func (r *TransactionRepository) FindByIdempotencyKey(ctx context.Context, key string) (*entities.Transaction, error) {
 	q := r.getQuerier(ctx)
 
 	query := `
 		SELECT id, wallet_id, idempotency_key, transaction_type, status,
-			   amount, currency, destination_wallet_id, external_reference,
-			   description, metadata, failure_reason, retry_count,
-			   created_at, updated_at, processed_at, completed_at
+			   amount, fee_amount, net_amount, currency, destination_wallet_id, external_reference,
+			   external_reference_hash, description, metadata, failure_reason, failure_category, retry_count, next_retry_at, jurisdiction, created_by_version,
+			   created_at, updated_at, processed_at, completed_at, receipt_number
+		FROM transactions
+		WHERE idempotency_key = $1 AND environment IN ($2, '')
+		ORDER BY environment DESC
+		LIMIT 1
+	`
+
+	// Not found - ErrEntityNotFound (см. контракт ports.TransactionRepository)
+	return r.scanTransaction(q.QueryRow(ctx, query, key, r.environment))
+}
+
+// FindByExternalReference находит транзакции по хэшу внешней ссылки.
+func (r *TransactionRepository) FindByExternalReference(ctx context.Context, reference string) ([]*entities.Transaction, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT id, wallet_id, idempotency_key, transaction_type, status,
+			   amount, fee_amount, net_amount, currency, destination_wallet_id, external_reference,
+			   external_reference_hash, description, metadata, failure_reason, failure_category, retry_count, next_retry_at, jurisdiction, created_by_version,
+			   created_at, updated_at, processed_at, completed_at, receipt_number
 		FROM transactions
-		WHERE idempotency_key = $1
+		WHERE external_reference_hash = $1
+		ORDER BY created_at ASC
 	`
 
-	tx, err := r.scanTransaction(q.QueryRow(ctx, query, key))
+	rows, err := q.Query(ctx, query, valueobjects.HashExternalReference(reference))
 	if err != nil {
-		if domainErrors.IsNotFound(err) {
-			return nil, nil // Not found is expected - return nil without error
-		}
-		return nil, err
+		return nil, fmt.Errorf("failed to find transactions by external reference: %w", err)
 	}
+	defer rows.Close()
 
-	return tx, nil
+	return r.scanTransactions(rows)
 }
 
 // FindByWalletID возвращает транзакции кошелька с пагинацией.
@@ -155,9 +228,9 @@ func (r *TransactionRepository) FindByWalletID(ctx context.Context, walletID uui
 
 	query := `
 		SELECT id, wallet_id, idempotency_key, transaction_type, status,
-			   amount, currency, destination_wallet_id, external_reference,
-			   description, metadata, failure_reason, retry_count,
-			   created_at, updated_at, processed_at, completed_at
+			   amount, fee_amount, net_amount, currency, destination_wallet_id, external_reference,
+			   external_reference_hash, description, metadata, failure_reason, failure_category, retry_count, next_retry_at, jurisdiction, created_by_version,
+			   created_at, updated_at, processed_at, completed_at, receipt_number
 		FROM transactions
 		WHERE wallet_id = $1
 		ORDER BY created_at DESC
@@ -179,9 +252,9 @@ func (r *TransactionRepository) FindPendingByWallet(ctx context.Context, walletI
 
 	query := `
 		SELECT id, wallet_id, idempotency_key, transaction_type, status,
-			   amount, currency, destination_wallet_id, external_reference,
-			   description, metadata, failure_reason, retry_count,
-			   created_at, updated_at, processed_at, completed_at
+			   amount, fee_amount, net_amount, currency, destination_wallet_id, external_reference,
+			   external_reference_hash, description, metadata, failure_reason, failure_category, retry_count, next_retry_at, jurisdiction, created_by_version,
+			   created_at, updated_at, processed_at, completed_at, receipt_number
 		FROM transactions
 		WHERE wallet_id = $1 AND status = 'PENDING'
 		ORDER BY created_at ASC
@@ -196,22 +269,75 @@ func (r *TransactionRepository) FindPendingByWallet(ctx context.Context, walletI
 	return r.scanTransactions(rows)
 }
 
-// FindFailedRetryable возвращает failed транзакции, которые можно повторить.
+// CountPendingByWallet возвращает число PENDING и PROCESSING транзакций кошелька.
+// Покрывается индексом idx_transactions_wallet_status.
+func (r *TransactionRepository) CountPendingByWallet(ctx context.Context, walletID uuid.UUID) (int, error) {
+	q := r.getQuerier(ctx)
+
+	var count int
+	err := q.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM transactions
+		WHERE wallet_id = $1 AND status IN ('PENDING', 'PROCESSING')
+	`, walletID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending transactions: %w", err)
+	}
+	return count, nil
+}
+
+// CountOutcomes считает COMPLETED и FAILED транзакции, завершённые в [from, to).
+func (r *TransactionRepository) CountOutcomes(ctx context.Context, from, to time.Time) (ports.TransactionOutcomeCounts, error) {
+	q := r.getQuerier(ctx)
+
+	rows, err := q.Query(ctx, `
+		SELECT status, COALESCE(failure_category, ''), COUNT(*)
+		FROM transactions
+		WHERE status IN ('COMPLETED', 'FAILED') AND completed_at >= $1 AND completed_at < $2
+		GROUP BY status, failure_category
+	`, from, to)
+	if err != nil {
+		return ports.TransactionOutcomeCounts{}, fmt.Errorf("failed to count transaction outcomes: %w", err)
+	}
+	defer rows.Close()
+
+	counts := ports.TransactionOutcomeCounts{Failed: make(map[entities.FailureCategory]int)}
+	for rows.Next() {
+		var status, category string
+		var count int
+		if err := rows.Scan(&status, &category, &count); err != nil {
+			return ports.TransactionOutcomeCounts{}, fmt.Errorf("failed to scan transaction outcome: %w", err)
+		}
+		if entities.TransactionStatus(status) == entities.TransactionStatusCompleted {
+			counts.Completed += count
+		} else {
+			counts.Failed[entities.FailureCategory(category)] += count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return ports.TransactionOutcomeCounts{}, fmt.Errorf("failed to count transaction outcomes: %w", err)
+	}
+	return counts, nil
+}
+
+// FindFailedRetryable возвращает failed транзакции, которые можно повторить:
+// лимит попыток не исчерпан и next_retry_at уже наступил.
 func (r *TransactionRepository) FindFailedRetryable(ctx context.Context, maxRetries int, limit int) ([]*entities.Transaction, error) {
 	q := r.getQuerier(ctx)
 
 	query := `
 		SELECT id, wallet_id, idempotency_key, transaction_type, status,
-			   amount, currency, destination_wallet_id, external_reference,
-			   description, metadata, failure_reason, retry_count,
-			   created_at, updated_at, processed_at, completed_at
+			   amount, fee_amount, net_amount, currency, destination_wallet_id, external_reference,
+			   external_reference_hash, description, metadata, failure_reason, failure_category, retry_count, next_retry_at, jurisdiction, created_by_version,
+			   created_at, updated_at, processed_at, completed_at, receipt_number
 		FROM transactions
 		WHERE status = 'FAILED' AND retry_count < $1
+		  AND (next_retry_at IS NULL OR next_retry_at <= $3)
 		ORDER BY created_at ASC
 		LIMIT $2
 	`
 
-	rows, err := q.Query(ctx, query, maxRetries, limit)
+	rows, err := q.Query(ctx, query, maxRetries, limit, time.Now().UTC())
 	if err != nil {
 		return nil, fmt.Errorf("failed to find retryable transactions: %w", err)
 	}
@@ -224,53 +350,47 @@ func (r *TransactionRepository) FindFailedRetryable(ctx context.Context, maxRetr
 func (r *TransactionRepository) List(ctx context.Context, filter ports.TransactionFilter, offset, limit int) ([]*entities.Transaction, error) {
 	q := r.getQuerier(ctx)
 
-	// Строим динамический запрос
-	query := `
-		SELECT t.id, t.wallet_id, t.idempotency_key, t.transaction_type, t.status,
-			   t.amount, t.currency, t.destination_wallet_id, t.external_reference,
-			   t.description, t.metadata, t.failure_reason, t.retry_count,
-			   t.created_at, t.updated_at, t.processed_at, t.completed_at
-		FROM transactions t
-	`
+	query, args := transactionListQuery(filter, offset, limit).SQL()
 
-	// Для фильтра по user_id нужен JOIN
-	if filter.UserID != nil {
-		query += " JOIN wallets w ON t.wallet_id = w.id"
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
 	}
+	defer rows.Close()
 
-	query += " WHERE 1=1"
+	return r.scanTransactions(rows)
+}
 
-	args := []interface{}{}
-	argNum := 1
+// ListBefore возвращает страницу List после курсора (keyset по created_at, id).
+func (r *TransactionRepository) ListBefore(ctx context.Context, filter ports.TransactionFilter, before *ports.TransactionCursor, limit int) ([]*entities.Transaction, error) {
+	q := r.getQuerier(ctx)
 
-	if filter.WalletID != nil {
-		query += fmt.Sprintf(" AND (t.wallet_id = $%d OR t.destination_wallet_id = $%d)", argNum, argNum)
-		args = append(args, *filter.WalletID)
-		argNum++
-	}
+	query, args := transactionFilterQuery(filter, before).
+		OrderBy("t.created_at DESC, t.id DESC").
+		Page(0, limit).
+		SQL()
 
-	if filter.UserID != nil {
-		query += fmt.Sprintf(" AND w.user_id = $%d", argNum)
-		args = append(args, *filter.UserID)
-		argNum++
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
 	}
+	defer rows.Close()
 
-	if filter.Type != nil {
-		query += fmt.Sprintf(" AND t.transaction_type = $%d", argNum)
-		args = append(args, string(*filter.Type))
-		argNum++
-	}
+	return r.scanTransactions(rows)
+}
 
-	if filter.Status != nil {
-		query += fmt.Sprintf(" AND t.status = $%d", argNum)
-		args = append(args, string(*filter.Status))
-		argNum++
-	}
+// ListAfter возвращает транзакции в хронологическом порядке после курсора
+// (keyset по created_at, id).
+func (r *TransactionRepository) ListAfter(ctx context.Context, filter ports.TransactionFilter, after *ports.TransactionCursor, limit int) ([]*entities.Transaction, error) {
+	q := r.getQuerier(ctx)
 
-	query += fmt.Sprintf(" ORDER BY t.created_at DESC OFFSET $%d LIMIT $%d", argNum, argNum+1)
-	args = append(args, offset, limit)
+	query := transactionFilterQuery(filter, nil)
+	if after != nil {
+		query.After([]string{"t.created_at", "t.id"}, after.CreatedAt.UTC(), after.ID)
+	}
+	sql, args := query.OrderBy("t.created_at ASC, t.id ASC").Page(0, limit).SQL()
 
-	rows, err := q.Query(ctx, query, args...)
+	rows, err := q.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list transactions: %w", err)
 	}
@@ -279,20 +399,60 @@ func (r *TransactionRepository) List(ctx context.Context, filter ports.Transacti
 	return r.scanTransactions(rows)
 }
 
+// transactionListQuery строит запрос List по фильтру.
+func transactionListQuery(filter ports.TransactionFilter, offset, limit int) *selectQuery {
+	return transactionFilterQuery(filter, nil).OrderBy("t.created_at DESC, t.id DESC").Page(offset, limit)
+}
+
+// transactionFilterQuery строит SELECT транзакций по фильтру без сортировки;
+// before добавляет keyset-условие курсора.
+func transactionFilterQuery(filter ports.TransactionFilter, before *ports.TransactionCursor) *selectQuery {
+	from := `
+		SELECT t.id, t.wallet_id, t.idempotency_key, t.transaction_type, t.status,
+			   t.amount, t.fee_amount, t.net_amount, t.currency, t.destination_wallet_id, t.external_reference,
+			   t.external_reference_hash, t.description, t.metadata, t.failure_reason, t.failure_category, t.retry_count, t.next_retry_at, t.jurisdiction, t.created_by_version,
+			   t.created_at, t.updated_at, t.processed_at, t.completed_at, t.receipt_number
+		FROM transactions t
+	`
+
+	// Для фильтра по user_id нужен JOIN
+	if filter.UserID != nil {
+		from += " JOIN wallets w ON t.wallet_id = w.id"
+	}
+
+	q := newSelect(from)
+	if filter.WalletID != nil {
+		q.Where("(t.wallet_id = ? OR t.destination_wallet_id = ?)", *filter.WalletID, *filter.WalletID)
+	}
+	whereOpt(q, "w.user_id = ?", filter.UserID)
+	whereOptFunc(q, "t.transaction_type = ?", filter.Type, asString)
+	whereOptFunc(q, "t.status = ?", filter.Status, asString)
+	whereOptFunc(q, "t.created_at >= ?", filter.CreatedFrom, time.Time.UTC)
+	whereOptFunc(q, "t.created_at < ?", filter.CreatedTo, time.Time.UTC)
+	if before != nil {
+		q.Before([]string{"t.created_at", "t.id"}, before.CreatedAt.UTC(), before.ID)
+	}
+	return q
+}
+
 // scanTransaction сканирует одну строку в Transaction entity.
 func (r *TransactionRepository) scanTransaction(row pgx.Row) (*entities.Transaction, error) {
 	var (
 		id, walletID                         uuid.UUID
 		idempotencyKey, txTypeStr, statusStr string
-		amountCents                          int64
+		amountCents, feeCents, netCents      int64
 		currencyCode                         string
 		destinationWalletID                  *uuid.UUID
-		externalReference, description       *string
+		externalReference, extRefHash        *string
+		description                          *string
 		metadataJSON                         []byte
-		failureReason                        *string
+		failureReason, failureCategory       *string
 		retryCount                           int
+		nextRetryAt                          *time.Time
+		jurisdiction, createdByVersion       *string
 		createdAt, updatedAt                 time.Time
 		processedAt, completedAt             *time.Time
+		receiptNumber                        *string
 	)
 
 	err := row.Scan(
@@ -302,17 +462,25 @@ func (r *TransactionRepository) scanTransaction(row pgx.Row) (*entities.Transact
 		&txTypeStr,
 		&statusStr,
 		&amountCents,
+		&feeCents,
+		&netCents,
 		&currencyCode,
 		&destinationWalletID,
 		&externalReference,
+		&extRefHash,
 		&description,
 		&metadataJSON,
 		&failureReason,
+		&failureCategory,
 		&retryCount,
+		&nextRetryAt,
+		&jurisdiction,
+		&createdByVersion,
 		&createdAt,
 		&updatedAt,
 		&processedAt,
 		&completedAt,
+		&receiptNumber,
 	)
 
 	if err != nil {
@@ -333,6 +501,16 @@ func (r *TransactionRepository) scanTransaction(row pgx.Row) (*entities.Transact
 		return nil, fmt.Errorf("failed to convert amount: %w", err)
 	}
 
+	feeAmount, err := valueobjects.NewMoneyFromCents(feeCents, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert fee amount: %w", err)
+	}
+
+	netAmount, err := valueobjects.NewMoneyFromCents(netCents, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert net amount: %w", err)
+	}
+
 	// Handle nullable strings
 	extRef := ""
 	if externalReference != nil {
@@ -357,16 +535,24 @@ func (r *TransactionRepository) scanTransaction(row pgx.Row) (*entities.Transact
 		entities.TransactionType(txTypeStr),
 		entities.TransactionStatus(statusStr),
 		amount,
+		feeAmount,
+		netAmount,
 		destinationWalletID,
 		extRef,
+		derefString(extRefHash),
 		desc,
 		metadataJSON,
 		failReason,
+		entities.FailureCategory(derefString(failureCategory)),
 		retryCount,
+		nextRetryAt,
+		derefString(jurisdiction),
+		derefString(createdByVersion),
 		createdAt,
 		updatedAt,
 		processedAt,
 		completedAt,
+		derefString(receiptNumber),
 	)
 
 	if err != nil {
@@ -384,15 +570,19 @@ func (r *TransactionRepository) scanTransactions(rows pgx.Rows) ([]*entities.Tra
 		var (
 			id, walletID                         uuid.UUID
 			idempotencyKey, txTypeStr, statusStr string
-			amountCents                          int64
+			amountCents, feeCents, netCents      int64
 			currencyCode                         string
 			destinationWalletID                  *uuid.UUID
-			externalReference, description       *string
+			externalReference, extRefHash        *string
+			description                          *string
 			metadataJSON                         []byte
-			failureReason                        *string
+			failureReason, failureCategory       *string
 			retryCount                           int
+			nextRetryAt                          *time.Time
+			jurisdiction, createdByVersion       *string
 			createdAt, updatedAt                 time.Time
 			processedAt, completedAt             *time.Time
+			receiptNumber                        *string
 		)
 
 		err := rows.Scan(
@@ -402,17 +592,25 @@ func (r *TransactionRepository) scanTransactions(rows pgx.Rows) ([]*entities.Tra
 			&txTypeStr,
 			&statusStr,
 			&amountCents,
+			&feeCents,
+			&netCents,
 			&currencyCode,
 			&destinationWalletID,
 			&externalReference,
+			&extRefHash,
 			&description,
 			&metadataJSON,
 			&failureReason,
+			&failureCategory,
 			&retryCount,
+			&nextRetryAt,
+			&jurisdiction,
+			&createdByVersion,
 			&createdAt,
 			&updatedAt,
 			&processedAt,
 			&completedAt,
+			&receiptNumber,
 		)
 
 		if err != nil {
@@ -421,6 +619,8 @@ func (r *TransactionRepository) scanTransactions(rows pgx.Rows) ([]*entities.Tra
 
 		currency, _ := valueobjects.NewCurrency(currencyCode)
 		amount, _ := valueobjects.NewMoneyFromCents(amountCents, currency)
+		feeAmount, _ := valueobjects.NewMoneyFromCents(feeCents, currency)
+		netAmount, _ := valueobjects.NewMoneyFromCents(netCents, currency)
 
 		extRef := ""
 		if externalReference != nil {
@@ -444,16 +644,24 @@ func (r *TransactionRepository) scanTransactions(rows pgx.Rows) ([]*entities.Tra
 			entities.TransactionType(txTypeStr),
 			entities.TransactionStatus(statusStr),
 			amount,
+			feeAmount,
+			netAmount,
 			destinationWalletID,
 			extRef,
+			derefString(extRefHash),
 			desc,
 			metadataJSON,
 			failReason,
+			entities.FailureCategory(derefString(failureCategory)),
 			retryCount,
+			nextRetryAt,
+			derefString(jurisdiction),
+			derefString(createdByVersion),
 			createdAt,
 			updatedAt,
 			processedAt,
 			completedAt,
+			derefString(receiptNumber),
 		)
 
 		if err != nil {
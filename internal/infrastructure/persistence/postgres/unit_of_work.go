@@ -77,6 +77,9 @@ func NewUnitOfWorkWithIsolation(pool *pgxpool.Pool, isolation pgx.TxIsoLevel) *U
 // - Если panic: ROLLBACK + re-panic
 //
 // ВАЖНО: Все repositories внутри fn должны использовать переданный txCtx!
+// Повторный Execute с context без транзакции во время активного UnitOfWork
+// запроса возвращает ports.ErrNestedUnitOfWork вместо второго соединения
+// (см. ports.UnitOfWorkGuard).
 func (u *UnitOfWork) Execute(ctx context.Context, fn func(context.Context) error) error {
 	// Проверяем, есть ли уже транзакция в context (nested transaction)
 	if hasTx(ctx) {
@@ -85,6 +88,12 @@ func (u *UnitOfWork) Execute(ctx context.Context, fn func(context.Context) error
 		return fn(ctx)
 	}
 
+	guard := ports.UnitOfWorkGuardFromContext(ctx)
+	if !guard.Enter() {
+		return ports.ErrNestedUnitOfWork
+	}
+	defer guard.Leave()
+
 	// Начинаем новую транзакцию
 	tx, err := u.pool.BeginTx(ctx, u.opts)
 	if err != nil {
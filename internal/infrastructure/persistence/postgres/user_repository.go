@@ -15,6 +15,7 @@ import (
 	"github.com/Haleralex/wallethub/internal/application/ports"
 	"github.com/Haleralex/wallethub/internal/domain/entities"
 	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
 )
 
 // Compile-time check: UserRepository implements ports.UserRepository
@@ -24,13 +25,24 @@ var _ ports.UserRepository = (*UserRepository)(nil)
 //
 // Thread-safe: использует connection pool.
 // Transaction-aware: автоматически использует транзакцию из context если есть.
+//
+// Уникальность email проверяется по normalized_email - ключу из EmailPolicy.
+// Сам email хранится в том виде, в каком его ввёл пользователь.
 type UserRepository struct {
-	pool *pgxpool.Pool
+	pool        *pgxpool.Pool
+	emailPolicy valueobjects.EmailPolicy
 }
 
-// NewUserRepository создаёт новый UserRepository.
+// NewUserRepository создаёт новый UserRepository с политикой email по умолчанию.
 func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
-	return &UserRepository{pool: pool}
+	return &UserRepository{pool: pool, emailPolicy: valueobjects.DefaultEmailPolicy()}
+}
+
+// WithEmailPolicy задаёт политику нормализации email.
+// Политика должна совпадать с той, по которой заполнен normalized_email.
+func (r *UserRepository) WithEmailPolicy(policy valueobjects.EmailPolicy) *UserRepository {
+	r.emailPolicy = policy
+	return r
 }
 
 // querier - абстракция для выполнения запросов.
@@ -44,9 +56,9 @@ type querier interface {
 // getQuerier возвращает querier из context (transaction) или pool.
 func (r *UserRepository) getQuerier(ctx context.Context) querier {
 	if tx := extractTx(ctx); tx != nil {
-		return tx
+		return withRequestStats(ctx, tx)
 	}
-	return r.pool
+	return withRequestStats(ctx, r.pool)
 }
 
 // Save сохраняет пользователя (INSERT или UPDATE).
@@ -55,29 +67,46 @@ func (r *UserRepository) Save(ctx context.Context, user *entities.User) error {
 	q := r.getQuerier(ctx)
 
 	query := `
-		INSERT INTO users (id, email, full_name, kyc_status, telegram_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO users (id, email, full_name, kyc_status, telegram_id, jurisdiction, last_kyc_rejection_reason, created_at, updated_at, normalized_email, tos_version, tos_accepted_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9, $10, $11, $12)
 		ON CONFLICT (id) DO UPDATE SET
 			email = EXCLUDED.email,
+			normalized_email = EXCLUDED.normalized_email,
 			full_name = EXCLUDED.full_name,
 			kyc_status = EXCLUDED.kyc_status,
 			telegram_id = EXCLUDED.telegram_id,
+			jurisdiction = EXCLUDED.jurisdiction,
+			last_kyc_rejection_reason = EXCLUDED.last_kyc_rejection_reason,
+			tos_version = EXCLUDED.tos_version,
+			tos_accepted_at = EXCLUDED.tos_accepted_at,
 			updated_at = EXCLUDED.updated_at
 	`
 
+	// NULL tos_version - пользователь до учёта ToS (grandfathered)
+	terms := user.TermsAcceptance()
+	var termsVersion *int
+	if !terms.Grandfathered {
+		termsVersion = &terms.Version
+	}
+
 	_, err := q.Exec(ctx, query,
 		user.ID(),
 		user.Email(),
 		user.FullName(),
 		string(user.KYCStatus()),
 		user.TelegramID(),
+		user.Jurisdiction(),
+		user.LastKYCRejectionReason(),
 		user.CreatedAt(),
 		user.UpdatedAt(),
+		r.emailPolicy.Normalize(user.Email()),
+		termsVersion,
+		terms.AcceptedAt,
 	)
 
 	if err != nil {
 		// Проверяем на duplicate email (UNIQUE constraint violation)
-		if isUniqueViolation(err, "users_email_unique") {
+		if isUniqueViolation(err, "users_email_unique") || isUniqueViolation(err, "users_normalized_email_unique") {
 			return domainErrors.NewBusinessRuleViolation(
 				"EMAIL_ALREADY_EXISTS",
 				fmt.Sprintf("user with email %s already exists", user.Email()),
@@ -98,6 +127,10 @@ func scanUser(scanner interface{ Scan(dest ...any) error }) (*entities.User, err
 		fullName             string
 		kycStatus            string
 		telegramID           *int64
+		jurisdiction         *string
+		lastRejectionReason  *string
+		termsVersion         *int
+		termsAcceptedAt      *time.Time
 		createdAt, updatedAt time.Time
 	)
 
@@ -107,8 +140,12 @@ func scanUser(scanner interface{ Scan(dest ...any) error }) (*entities.User, err
 		&fullName,
 		&kycStatus,
 		&telegramID,
+		&jurisdiction,
+		&lastRejectionReason,
 		&createdAt,
 		&updatedAt,
+		&termsVersion,
+		&termsAcceptedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -118,11 +155,18 @@ func scanUser(scanner interface{ Scan(dest ...any) error }) (*entities.User, err
 		userID, email, fullName,
 		entities.KYCStatus(kycStatus),
 		telegramID,
+		derefString(jurisdiction),
+		derefString(lastRejectionReason),
+		entities.TermsAcceptance{
+			Version:       derefInt(termsVersion),
+			AcceptedAt:    termsAcceptedAt,
+			Grandfathered: termsVersion == nil,
+		},
 		createdAt, updatedAt,
 	), nil
 }
 
-const userColumns = `id, email, full_name, kyc_status, telegram_id, created_at, updated_at`
+const userColumns = `id, email, full_name, kyc_status, telegram_id, jurisdiction, last_kyc_rejection_reason, created_at, updated_at, tos_version, tos_accepted_at`
 
 // FindByID загружает пользователя по ID.
 func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
@@ -141,13 +185,13 @@ func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.
 	return user, nil
 }
 
-// FindByEmail загружает пользователя по email.
+// FindByEmail загружает пользователя по нормализованному email.
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
 	q := r.getQuerier(ctx)
 
-	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
+	query := `SELECT ` + userColumns + ` FROM users WHERE normalized_email = $1`
 
-	user, err := scanUser(q.QueryRow(ctx, query, email))
+	user, err := scanUser(q.QueryRow(ctx, query, r.emailPolicy.Normalize(email)))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domainErrors.ErrEntityNotFound
@@ -158,15 +202,15 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entiti
 	return user, nil
 }
 
-// ExistsByEmail проверяет существование пользователя по email.
+// ExistsByEmail проверяет существование пользователя по нормализованному email.
 // Оптимизированный запрос без загрузки всех полей.
 func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
 	q := r.getQuerier(ctx)
 
-	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE normalized_email = $1)`
 
 	var exists bool
-	err := q.QueryRow(ctx, query, email).Scan(&exists)
+	err := q.QueryRow(ctx, query, r.emailPolicy.Normalize(email)).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("failed to check email existence: %w", err)
 	}
@@ -218,3 +262,30 @@ func (r *UserRepository) List(ctx context.Context, offset, limit int) ([]*entiti
 
 	return users, nil
 }
+
+// UserActivitySummary считает кошельки и транзакции пользователя одним запросом.
+//
+// План: Index Scan по idx_wallets_user_id, для каждого кошелька - Index Only
+// Scan по idx_transactions_wallet_created (wallet_id, created_at). Поэтому
+// агрегаты ссылаются только на колонки индекса: COUNT(t.wallet_id), а не
+// COUNT(t.id), иначе каждая транзакция читалась бы из heap.
+func (r *UserRepository) UserActivitySummary(ctx context.Context, userID uuid.UUID) (ports.UserActivity, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT COUNT(DISTINCT w.id), COUNT(t.wallet_id), MAX(t.created_at)
+		FROM wallets w
+		LEFT JOIN transactions t ON t.wallet_id = w.id
+		WHERE w.user_id = $1
+	`
+
+	var activity ports.UserActivity
+	if err := q.QueryRow(ctx, query, userID).Scan(
+		&activity.WalletCount, &activity.TotalTransactions, &activity.LastActivityAt,
+	); err != nil {
+		return ports.UserActivity{}, fmt.Errorf("failed to summarize user activity: %w", err)
+	}
+	activity.LastActivityAt = utcPtr(activity.LastActivityAt)
+
+	return activity, nil
+}
@@ -0,0 +1,330 @@
+// Package postgres - перенос балансов кошельков в модель holds/ledger.
+//
+// Балансы переезжают из колонок wallets в таблицы wallet_balances и
+// wallet_holds без простоя. WalletRepository проходит фазы:
+//
+//	off                 - только старые колонки
+//	dual_write          - запись в обе схемы в одной транзакции, чтение старое
+//	shadow_read_compare - как dual_write, каждое чтение сверяет обе схемы
+//	new_read            - чтение из новых таблиц для NewReadPercent кошельков
+//	new_only            - только новые таблицы; балансы в wallets не обновляются
+//
+// Между dual_write и new_read периодически запускается сверка выборки
+// кошельков (SampleDivergence). Переключать чтение стоит только после
+// того, как сверка стабильно показывает ноль расхождений.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// WalletMigrationPhase - фаза переноса балансов кошельков.
+type WalletMigrationPhase string
+
+const (
+	WalletMigrationOff               WalletMigrationPhase = "off"
+	WalletMigrationDualWrite         WalletMigrationPhase = "dual_write"
+	WalletMigrationShadowReadCompare WalletMigrationPhase = "shadow_read_compare"
+	WalletMigrationNewRead           WalletMigrationPhase = "new_read"
+	WalletMigrationNewOnly           WalletMigrationPhase = "new_only"
+)
+
+// WalletMigrationPhases - все фазы в порядке прохождения.
+var WalletMigrationPhases = []WalletMigrationPhase{
+	WalletMigrationOff,
+	WalletMigrationDualWrite,
+	WalletMigrationShadowReadCompare,
+	WalletMigrationNewRead,
+	WalletMigrationNewOnly,
+}
+
+// ParseWalletMigrationPhase разбирает фазу из конфигурации. Пустая строка - off.
+func ParseWalletMigrationPhase(value string) (WalletMigrationPhase, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if normalized == "" {
+		return WalletMigrationOff, nil
+	}
+
+	for _, phase := range WalletMigrationPhases {
+		if string(phase) == normalized {
+			return phase, nil
+		}
+	}
+	return "", fmt.Errorf("invalid wallet migration phase %q", value)
+}
+
+// KeepsBothStores возвращает true, если обе схемы пишутся и их можно сверять.
+func (p WalletMigrationPhase) KeepsBothStores() bool {
+	return p == WalletMigrationDualWrite || p == WalletMigrationShadowReadCompare || p == WalletMigrationNewRead
+}
+
+// WalletMigration - настройки переноса балансов для WalletRepository.
+type WalletMigration struct {
+	Phase WalletMigrationPhase
+
+	// NewReadPercent - доля кошельков (0-100), читаемых из новых таблиц в фазе new_read.
+	// Кошелёк выбирается по хэшу ID, поэтому один кошелёк всегда читается одинаково.
+	NewReadPercent int
+
+	// OnShadowMismatch вызывается, когда чтение нашло расхождение схем (метрика). Может быть nil.
+	OnShadowMismatch func()
+}
+
+// NewWalletMigration создаёт настройки из значений конфигурации.
+func NewWalletMigration(phase string, newReadPercent int) (WalletMigration, error) {
+	parsed, err := ParseWalletMigrationPhase(phase)
+	if err != nil {
+		return WalletMigration{}, err
+	}
+	if newReadPercent < 0 || newReadPercent > 100 {
+		return WalletMigration{}, fmt.Errorf("wallet migration new_read_percent must be within 0-100, got %d", newReadPercent)
+	}
+	return WalletMigration{Phase: parsed, NewReadPercent: newReadPercent}, nil
+}
+
+// WithMigration задаёт фазу переноса балансов.
+func (r *WalletRepository) WithMigration(migration WalletMigration) *WalletRepository {
+	if migration.Phase == "" {
+		migration.Phase = WalletMigrationOff
+	}
+	r.migration = migration
+	return r
+}
+
+// writesOld - балансы пишутся в колонки wallets.
+func (m WalletMigration) writesOld() bool {
+	return m.Phase != WalletMigrationNewOnly
+}
+
+// writesNew - балансы пишутся в wallet_balances / wallet_holds.
+func (m WalletMigration) writesNew() bool {
+	return m.Phase != WalletMigrationOff
+}
+
+// joinsNew - чтение подтягивает новые таблицы.
+func (m WalletMigration) joinsNew() bool {
+	return m.Phase == WalletMigrationShadowReadCompare || m.Phase == WalletMigrationNewRead || m.Phase == WalletMigrationNewOnly
+}
+
+// comparesReads - чтение сверяет обе схемы.
+func (m WalletMigration) comparesReads() bool {
+	return m.Phase == WalletMigrationShadowReadCompare || m.Phase == WalletMigrationNewRead
+}
+
+// readsNew - балансы кошелька берутся из новых таблиц.
+func (m WalletMigration) readsNew(walletID uuid.UUID) bool {
+	switch m.Phase {
+	case WalletMigrationNewOnly:
+		return true
+	case WalletMigrationNewRead:
+		return walletBucket(walletID) < m.NewReadPercent
+	default:
+		return false
+	}
+}
+
+// walletBucket - стабильная корзина кошелька 0-99.
+func walletBucket(walletID uuid.UUID) int {
+	h := fnv.New32a()
+	_, _ = h.Write(walletID[:])
+	return int(h.Sum32() % 100)
+}
+
+// ============================================
+// Reads
+// ============================================
+
+// resolveBalances выбирает, из какой схемы отдать балансы строки,
+// и сообщает о расхождении, если фаза сверяет чтения.
+func (r *WalletRepository) resolveBalances(row *walletRow) error {
+	if !r.migration.joinsNew() {
+		return nil
+	}
+
+	hasLedger := row.ledgerAvailable != nil && row.ledgerHeld != nil && row.ledgerVersion != nil
+	if r.migration.comparesReads() && r.migration.OnShadowMismatch != nil {
+		if !hasLedger || len(row.divergentFields()) > 0 {
+			r.migration.OnShadowMismatch()
+		}
+	}
+
+	if !r.migration.readsNew(row.id) {
+		return nil
+	}
+	if !hasLedger {
+		// В new_read старые колонки ещё пишутся и остаются верными
+		if r.migration.writesOld() {
+			return nil
+		}
+		return fmt.Errorf("wallet %s has no ledger balance", row.id)
+	}
+
+	row.available = *row.ledgerAvailable
+	row.pending = *row.ledgerHeld
+	row.version = *row.ledgerVersion
+	return nil
+}
+
+// ============================================
+// Writes
+// ============================================
+
+// saveMigrating сохраняет кошелёк в обе схемы (или только в новую в new_only)
+// в одной транзакции: в транзакции из ctx или в собственной.
+func (r *WalletRepository) saveMigrating(ctx context.Context, wallet *entities.Wallet) error {
+	return r.inTx(ctx, func(q querier) error {
+		if wallet.BalanceVersion() == 0 {
+			if err := r.insert(ctx, q, wallet); err != nil {
+				return err
+			}
+			return r.upsertLedger(ctx, q, wallet)
+		}
+
+		if !r.migration.writesOld() {
+			if err := r.updateLedger(ctx, q, wallet); err != nil {
+				return err
+			}
+			return r.updateAttributes(ctx, q, wallet)
+		}
+
+		if err := r.update(ctx, q, wallet); err != nil {
+			return err
+		}
+		// Версию уже проверил UPDATE wallets; upsert заодно догоняет
+		// кошельки, которых не было в backfill
+		return r.upsertLedger(ctx, q, wallet)
+	})
+}
+
+// inTx выполняет fn в транзакции из ctx, а без неё - в новой транзакции.
+func (r *WalletRepository) inTx(ctx context.Context, fn func(q querier) error) error {
+	if hasTx(ctx) {
+		return fn(r.getQuerier(ctx))
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(withRequestStats(ctx, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// upsertLedger записывает балансы в новые таблицы без проверки версии.
+func (r *WalletRepository) upsertLedger(ctx context.Context, q querier, wallet *entities.Wallet) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO wallet_balances (wallet_id, available_balance, balance_version, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (wallet_id) DO UPDATE SET
+			available_balance = EXCLUDED.available_balance,
+			balance_version = EXCLUDED.balance_version,
+			updated_at = EXCLUDED.updated_at
+	`,
+		wallet.ID(),
+		wallet.AvailableBalance().Cents(),
+		wallet.BalanceVersion(),
+		wallet.UpdatedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert wallet balance: %w", err)
+	}
+
+	_, err = q.Exec(ctx, `
+		INSERT INTO wallet_holds (wallet_id, held_balance, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (wallet_id) DO UPDATE SET
+			held_balance = EXCLUDED.held_balance,
+			updated_at = EXCLUDED.updated_at
+	`,
+		wallet.ID(),
+		wallet.PendingBalance().Cents(),
+		wallet.UpdatedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert wallet holds: %w", err)
+	}
+
+	return nil
+}
+
+// updateLedger обновляет новые таблицы с optimistic locking по wallet_balances.balance_version.
+func (r *WalletRepository) updateLedger(ctx context.Context, q querier, wallet *entities.Wallet) error {
+	expectedVersion := wallet.BalanceVersion() - 1
+
+	result, err := q.Exec(ctx, `
+		UPDATE wallet_balances SET
+			available_balance = $2,
+			balance_version = $3,
+			updated_at = $4
+		WHERE wallet_id = $1 AND balance_version = $5
+	`,
+		wallet.ID(),
+		wallet.AvailableBalance().Cents(),
+		wallet.BalanceVersion(),
+		wallet.UpdatedAt(),
+		expectedVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update wallet balance: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domainErrors.NewConcurrencyError(
+			"Wallet",
+			wallet.ID().String(),
+			fmt.Sprintf("wallet was modified by another transaction (expected version: %d)", expectedVersion),
+		)
+	}
+
+	_, err = q.Exec(ctx, `
+		UPDATE wallet_holds SET held_balance = $2, updated_at = $3
+		WHERE wallet_id = $1
+	`,
+		wallet.ID(),
+		wallet.PendingBalance().Cents(),
+		wallet.UpdatedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update wallet holds: %w", err)
+	}
+
+	return nil
+}
+
+// updateAttributes обновляет в wallets всё, кроме балансов (фаза new_only).
+func (r *WalletRepository) updateAttributes(ctx context.Context, q querier, wallet *entities.Wallet) error {
+	_, err := q.Exec(ctx, `
+		UPDATE wallets SET
+			status = $2,
+			daily_limit = $3,
+			monthly_limit = $4,
+			updated_at = $5,
+			max_pending_transactions = $6
+		WHERE id = $1
+	`,
+		wallet.ID(),
+		string(wallet.Status()),
+		wallet.DailyLimit().Cents(),
+		wallet.MonthlyLimit().Cents(),
+		wallet.UpdatedAt(),
+		wallet.MaxPendingTransactions(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update wallet: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWalletMigrationPhase(t *testing.T) {
+	for _, phase := range WalletMigrationPhases {
+		parsed, err := ParseWalletMigrationPhase(string(phase))
+		require.NoError(t, err)
+		assert.Equal(t, phase, parsed)
+	}
+
+	parsed, err := ParseWalletMigrationPhase("")
+	require.NoError(t, err)
+	assert.Equal(t, WalletMigrationOff, parsed)
+
+	parsed, err = ParseWalletMigrationPhase(" Dual_Write ")
+	require.NoError(t, err)
+	assert.Equal(t, WalletMigrationDualWrite, parsed)
+
+	_, err = ParseWalletMigrationPhase("new_first")
+	assert.Error(t, err)
+}
+
+func TestNewWalletMigration_PercentRange(t *testing.T) {
+	_, err := NewWalletMigration("new_read", 101)
+	assert.Error(t, err)
+
+	_, err = NewWalletMigration("new_read", -1)
+	assert.Error(t, err)
+
+	migration, err := NewWalletMigration("new_read", 25)
+	require.NoError(t, err)
+	assert.Equal(t, WalletMigrationNewRead, migration.Phase)
+	assert.Equal(t, 25, migration.NewReadPercent)
+}
+
+func TestWalletMigration_PhaseMatrix(t *testing.T) {
+	tests := []struct {
+		phase                         WalletMigrationPhase
+		writesOld, writesNew, joins   bool
+		comparesReads, keepsBothStore bool
+	}{
+		{WalletMigrationOff, true, false, false, false, false},
+		{WalletMigrationDualWrite, true, true, false, false, true},
+		{WalletMigrationShadowReadCompare, true, true, true, true, true},
+		{WalletMigrationNewRead, true, true, true, true, true},
+		{WalletMigrationNewOnly, false, true, true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.phase), func(t *testing.T) {
+			m := WalletMigration{Phase: tt.phase}
+			assert.Equal(t, tt.writesOld, m.writesOld())
+			assert.Equal(t, tt.writesNew, m.writesNew())
+			assert.Equal(t, tt.joins, m.joinsNew())
+			assert.Equal(t, tt.comparesReads, m.comparesReads())
+			assert.Equal(t, tt.keepsBothStore, tt.phase.KeepsBothStores())
+		})
+	}
+}
+
+func TestWalletMigration_ReadsNewByPercent(t *testing.T) {
+	ids := make([]uuid.UUID, 1000)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	count := func(m WalletMigration) int {
+		n := 0
+		for _, id := range ids {
+			if m.readsNew(id) {
+				n++
+			}
+		}
+		return n
+	}
+
+	assert.Zero(t, count(WalletMigration{Phase: WalletMigrationShadowReadCompare, NewReadPercent: 100}))
+	assert.Zero(t, count(WalletMigration{Phase: WalletMigrationNewRead, NewReadPercent: 0}))
+	assert.Equal(t, len(ids), count(WalletMigration{Phase: WalletMigrationNewRead, NewReadPercent: 100}))
+	assert.Equal(t, len(ids), count(WalletMigration{Phase: WalletMigrationNewOnly}))
+	assert.InDelta(t, 300, count(WalletMigration{Phase: WalletMigrationNewRead, NewReadPercent: 30}), 60)
+
+	// Выбор стабилен: увеличение процента только добавляет кошельки
+	low := WalletMigration{Phase: WalletMigrationNewRead, NewReadPercent: 10}
+	high := WalletMigration{Phase: WalletMigrationNewRead, NewReadPercent: 50}
+	for _, id := range ids {
+		if low.readsNew(id) {
+			assert.True(t, high.readsNew(id))
+		}
+	}
+}
+
+func TestWalletRow_DivergentFields(t *testing.T) {
+	available, held, version := int64(100), int64(0), int64(3)
+	row := walletRow{available: 100, pending: 5, version: 3,
+		ledgerAvailable: &available, ledgerHeld: &held, ledgerVersion: &version}
+
+	assert.Equal(t, []string{"pending_balance"}, row.divergentFields())
+
+	row.pending = 0
+	assert.Empty(t, row.divergentFields())
+}
@@ -0,0 +1,312 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+	"github.com/Haleralex/wallethub/internal/application/usecases/transaction"
+	"github.com/Haleralex/wallethub/internal/application/usecases/wallet"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// ============================================
+// Wallet Migration Helpers
+// ============================================
+
+// setupWalletMigrationDB возвращает очищенную БД со схемой wallet_balances / wallet_holds.
+func setupWalletMigrationDB(t *testing.T) *testContainer {
+	t.Helper()
+
+	tc := setupSharedTestDB(t)
+
+	migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000017_create_wallet_ledger_balances.up.sql"))
+	require.NoError(t, err)
+	_, err = tc.pool.Exec(context.Background(), string(migration))
+	require.NoError(t, err)
+
+	return tc
+}
+
+// migratingWalletRepository создаёт WalletRepository в указанной фазе.
+func migratingWalletRepository(t *testing.T, tc *testContainer, phase WalletMigrationPhase, percent int) *WalletRepository {
+	t.Helper()
+
+	migration, err := NewWalletMigration(string(phase), percent)
+	require.NoError(t, err)
+	return NewWalletRepository(tc.pool).WithMigration(migration)
+}
+
+// walletScenarioResult - то, что use cases видят после сценария.
+type walletScenarioResult struct {
+	source, destination dtos.WalletDTO
+}
+
+// runWalletScenario создаёт два кошелька, пополняет и переводит через use cases.
+func runWalletScenario(t *testing.T, tc *testContainer, walletRepo ports.WalletRepository) walletScenarioResult {
+	t.Helper()
+	ctx := context.Background()
+
+	userRepo := NewUserRepository(tc.pool)
+	transactionRepo := NewTransactionRepository(tc.pool)
+	publisher := NewOutboxRepository(tc.pool, "", nil)
+	uow := NewUnitOfWork(tc.pool)
+
+	createWallet := wallet.NewCreateWalletUseCase(userRepo, walletRepo, publisher, uow, nil)
+	credit := wallet.NewCreditWalletUseCase(walletRepo, transactionRepo, publisher, uow, nil, nil, ports.BuildInfo{}, ports.SensitiveDataPolicy{}, nil, nil)
+	transfer := transaction.NewTransferBetweenWalletsUseCase(walletRepo, transactionRepo, publisher, uow, nil, nil, nil, nil, nil, ports.BuildInfo{}, nil, nil, nil, nil, nil)
+	getWallet := wallet.NewGetWalletUseCase(walletRepo, transactionRepo, ports.PendingTransactionsPolicy{}, ports.MetadataQuota{}, ports.DisplayCurrency{})
+
+	var walletIDs []string
+	for i := 0; i < 2; i++ {
+		user, err := entities.NewUser(uuid.NewString()+"@example.com", "Migration User")
+		require.NoError(t, err)
+		require.NoError(t, userRepo.Save(ctx, user))
+
+		created, err := createWallet.Execute(ctx, dtos.CreateWalletCommand{UserID: user.ID().String(), CurrencyCode: "USD"})
+		require.NoError(t, err)
+		walletIDs = append(walletIDs, created.ID)
+	}
+
+	_, err := credit.Execute(ctx, dtos.CreditWalletCommand{
+		WalletID:       walletIDs[0],
+		Amount:         "250.00",
+		IdempotencyKey: uuid.NewString(),
+		Description:    "Top up",
+	})
+	require.NoError(t, err)
+
+	_, err = transfer.Execute(ctx, dtos.TransferFundsCommand{
+		SourceWalletID:      walletIDs[0],
+		DestinationWalletID: walletIDs[1],
+		Amount:              "100.00",
+		IdempotencyKey:      uuid.NewString(),
+		Description:         "Transfer",
+	})
+	require.NoError(t, err)
+
+	source, err := getWallet.Execute(ctx, dtos.GetWalletQuery{WalletID: walletIDs[0]})
+	require.NoError(t, err)
+	destination, err := getWallet.Execute(ctx, dtos.GetWalletQuery{WalletID: walletIDs[1]})
+	require.NoError(t, err)
+
+	return walletScenarioResult{source: *source, destination: *destination}
+}
+
+// ============================================
+// Wallet Migration Tests
+// ============================================
+
+// Каждая фаза обязана проходить тот же контракт, что и старая схема.
+func TestWalletRepository_MigrationPhases_Conformance(t *testing.T) {
+	for _, phase := range WalletMigrationPhases {
+		t.Run(string(phase), func(t *testing.T) {
+			porttest.RunWalletRepositoryTests(t, func(t *testing.T) porttest.Repositories {
+				tc := setupWalletMigrationDB(t)
+				return porttest.Repositories{
+					Users:        NewUserRepository(tc.pool),
+					Wallets:      migratingWalletRepository(t, tc, phase, 100),
+					Transactions: NewTransactionRepository(tc.pool),
+				}
+			})
+		})
+	}
+}
+
+// Use cases видят одинаковые балансы и версии в любой фазе.
+func TestWalletRepository_MigrationPhases_UseCasesUnchanged(t *testing.T) {
+	tc := setupWalletMigrationDB(t)
+	baseline := runWalletScenario(t, tc, NewWalletRepository(tc.pool))
+
+	for _, phase := range WalletMigrationPhases {
+		t.Run(string(phase), func(t *testing.T) {
+			tc := setupWalletMigrationDB(t)
+			got := runWalletScenario(t, tc, migratingWalletRepository(t, tc, phase, 50))
+
+			for _, pair := range [][2]dtos.WalletDTO{
+				{baseline.source, got.source},
+				{baseline.destination, got.destination},
+			} {
+				want, actual := pair[0], pair[1]
+				assert.Equal(t, want.AvailableBalance, actual.AvailableBalance)
+				assert.Equal(t, want.PendingBalance, actual.PendingBalance)
+				assert.Equal(t, want.BalanceVersion, actual.BalanceVersion)
+				assert.Equal(t, want.Status, actual.Status)
+			}
+		})
+	}
+}
+
+func TestWalletRepository_DualWrite_SameTransaction(t *testing.T) {
+	tc := setupWalletMigrationDB(t)
+	ctx := context.Background()
+	repo := migratingWalletRepository(t, tc, WalletMigrationDualWrite, 0)
+	uow := NewUnitOfWork(tc.pool)
+
+	user, _ := entities.NewUser("dual-write@example.com", "Dual Write")
+	require.NoError(t, NewUserRepository(tc.pool).Save(ctx, user))
+	usd, _ := valueobjects.NewCurrency("USD")
+	w, _ := entities.NewWallet(user.ID(), usd)
+	require.NoError(t, repo.Save(ctx, w))
+
+	t.Run("WritesBothSchemas", func(t *testing.T) {
+		amount, _ := valueobjects.NewMoney("42.00", usd)
+		require.NoError(t, w.Credit(amount))
+		require.NoError(t, repo.Save(ctx, w))
+
+		var available, version int64
+		require.NoError(t, tc.pool.QueryRow(ctx,
+			`SELECT available_balance, balance_version FROM wallet_balances WHERE wallet_id = $1`, w.ID(),
+		).Scan(&available, &version))
+		assert.Equal(t, int64(4200), available)
+		assert.Equal(t, int64(1), version)
+	})
+
+	t.Run("RollbackDiscardsBothSchemas", func(t *testing.T) {
+		err := uow.Execute(ctx, func(txCtx context.Context) error {
+			loaded, err := repo.FindByID(txCtx, w.ID())
+			if err != nil {
+				return err
+			}
+			amount, _ := valueobjects.NewMoney("1.00", usd)
+			if err := loaded.Credit(amount); err != nil {
+				return err
+			}
+			if err := repo.Save(txCtx, loaded); err != nil {
+				return err
+			}
+			return assert.AnError
+		})
+		require.ErrorIs(t, err, assert.AnError)
+
+		report, err := repo.SampleDivergence(ctx, 10)
+		require.NoError(t, err)
+		assert.Zero(t, report.Divergent())
+
+		loaded, err := repo.FindByID(ctx, w.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "42.00 USD", loaded.AvailableBalance().String())
+	})
+}
+
+func TestWalletRepository_SampleDivergence(t *testing.T) {
+	tc := setupWalletMigrationDB(t)
+	ctx := context.Background()
+	legacy := NewWalletRepository(tc.pool)
+	dual := migratingWalletRepository(t, tc, WalletMigrationDualWrite, 0)
+
+	user, _ := entities.NewUser("divergence@example.com", "Divergence")
+	require.NoError(t, NewUserRepository(tc.pool).Save(ctx, user))
+
+	usd, _ := valueobjects.NewCurrency("USD")
+	eur, _ := valueobjects.NewCurrency("EUR")
+	synced, _ := entities.NewWallet(user.ID(), usd)
+	require.NoError(t, dual.Save(ctx, synced))
+
+	// Кошелёк, записанный до включения dual_write, в новой схеме отсутствует
+	unsynced, _ := entities.NewWallet(user.ID(), eur)
+	require.NoError(t, legacy.Save(ctx, unsynced))
+
+	report, err := dual.SampleDivergence(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Sampled)
+	require.Equal(t, 1, report.Divergent())
+	assert.Equal(t, unsynced.ID(), report.Divergences[0].WalletID)
+	assert.True(t, report.Divergences[0].Missing)
+
+	// Изменение в обход dual_write - расхождение по балансу
+	amount, _ := valueobjects.NewMoney("5.00", usd)
+	require.NoError(t, synced.Credit(amount))
+	require.NoError(t, legacy.Save(ctx, synced))
+
+	report, err = dual.SampleDivergence(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Divergent())
+
+	// Следующая запись через dual_write догоняет новую схему
+	reloaded, err := dual.FindByID(ctx, unsynced.ID())
+	require.NoError(t, err)
+	amount, _ = valueobjects.NewMoney("1.00", eur)
+	require.NoError(t, reloaded.Credit(amount))
+	require.NoError(t, dual.Save(ctx, reloaded))
+
+	report, err = dual.SampleDivergence(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Divergent())
+	assert.Equal(t, synced.ID(), report.Divergences[0].WalletID)
+	assert.ElementsMatch(t, []string{"available_balance", "balance_version"}, report.Divergences[0].Fields)
+}
+
+func TestWalletRepository_ShadowReadCompare_ReportsMismatch(t *testing.T) {
+	tc := setupWalletMigrationDB(t)
+	ctx := context.Background()
+
+	mismatches := 0
+	migration, err := NewWalletMigration(string(WalletMigrationShadowReadCompare), 0)
+	require.NoError(t, err)
+	migration.OnShadowMismatch = func() { mismatches++ }
+	repo := NewWalletRepository(tc.pool).WithMigration(migration)
+
+	user, _ := entities.NewUser("shadow@example.com", "Shadow")
+	require.NoError(t, NewUserRepository(tc.pool).Save(ctx, user))
+	usd, _ := valueobjects.NewCurrency("USD")
+	w, _ := entities.NewWallet(user.ID(), usd)
+	require.NoError(t, repo.Save(ctx, w))
+
+	_, err = repo.FindByID(ctx, w.ID())
+	require.NoError(t, err)
+	assert.Zero(t, mismatches)
+
+	_, err = tc.pool.Exec(ctx, `UPDATE wallet_balances SET available_balance = 999 WHERE wallet_id = $1`, w.ID())
+	require.NoError(t, err)
+
+	loaded, err := repo.FindByID(ctx, w.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 1, mismatches)
+	assert.True(t, loaded.AvailableBalance().IsZero(), "shadow_read_compare must still return the old schema")
+}
+
+func TestWalletRepository_NewOnly_ReadsAndLocksOnNewSchema(t *testing.T) {
+	tc := setupWalletMigrationDB(t)
+	ctx := context.Background()
+	dual := migratingWalletRepository(t, tc, WalletMigrationDualWrite, 0)
+	newOnly := migratingWalletRepository(t, tc, WalletMigrationNewOnly, 0)
+
+	user, _ := entities.NewUser("new-only@example.com", "New Only")
+	require.NoError(t, NewUserRepository(tc.pool).Save(ctx, user))
+	usd, _ := valueobjects.NewCurrency("USD")
+	w, _ := entities.NewWallet(user.ID(), usd)
+	require.NoError(t, dual.Save(ctx, w))
+
+	amount, _ := valueobjects.NewMoney("10.00", usd)
+	require.NoError(t, w.Credit(amount))
+	require.NoError(t, newOnly.Save(ctx, w))
+
+	// Старые колонки больше не обновляются
+	var oldAvailable int64
+	require.NoError(t, tc.pool.QueryRow(ctx, `SELECT available_balance FROM wallets WHERE id = $1`, w.ID()).Scan(&oldAvailable))
+	assert.Zero(t, oldAvailable)
+
+	loaded, err := newOnly.FindByID(ctx, w.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "10.00 USD", loaded.AvailableBalance().String())
+
+	// Устаревшая версия отклоняется по wallet_balances.balance_version
+	stale, err := newOnly.FindByID(ctx, w.ID())
+	require.NoError(t, err)
+	require.NoError(t, loaded.Credit(amount))
+	require.NoError(t, newOnly.Save(ctx, loaded))
+	require.NoError(t, stale.Credit(amount))
+	assert.Error(t, newOnly.Save(ctx, stale))
+}
@@ -0,0 +1,145 @@
+// Package postgres - WalletNoteRepository implementation.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check: WalletNoteRepository implements ports.WalletNoteRepository
+var _ ports.WalletNoteRepository = (*WalletNoteRepository)(nil)
+
+// WalletNoteRepository реализует ports.WalletNoteRepository (таблица wallet_notes).
+type WalletNoteRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWalletNoteRepository создаёт новый WalletNoteRepository.
+func NewWalletNoteRepository(pool *pgxpool.Pool) *WalletNoteRepository {
+	return &WalletNoteRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *WalletNoteRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// Save вставляет заметку или обновляет pinned/deleted_at/deleted_by.
+// Тело, автор и кошелёк заметки после создания не меняются.
+func (r *WalletNoteRepository) Save(ctx context.Context, note *entities.WalletNote) error {
+	q := r.getQuerier(ctx)
+
+	query := `
+		INSERT INTO wallet_notes (id, wallet_id, author_id, body, pinned, created_at, deleted_at, deleted_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET
+			pinned = EXCLUDED.pinned,
+			deleted_at = EXCLUDED.deleted_at,
+			deleted_by = EXCLUDED.deleted_by
+	`
+
+	_, err := q.Exec(ctx, query,
+		note.ID(),
+		note.WalletID(),
+		note.AuthorID(),
+		note.Body(),
+		note.Pinned(),
+		note.CreatedAt(),
+		note.DeletedAt(),
+		note.DeletedBy(),
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domainErrors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", err)
+		}
+		return fmt.Errorf("failed to save wallet note: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID загружает неудалённую заметку.
+func (r *WalletNoteRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.WalletNote, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT id, wallet_id, author_id, body, pinned, created_at, deleted_at, deleted_by
+		FROM wallet_notes
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	note, err := scanWalletNote(q.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErrors.ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("failed to find wallet note: %w", err)
+	}
+
+	return note, nil
+}
+
+// ListByWallet возвращает неудалённые заметки кошелька: закреплённые первыми,
+// затем по created_at DESC.
+func (r *WalletNoteRepository) ListByWallet(ctx context.Context, walletID uuid.UUID, offset, limit int) ([]*entities.WalletNote, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT id, wallet_id, author_id, body, pinned, created_at, deleted_at, deleted_by
+		FROM wallet_notes
+		WHERE wallet_id = $1 AND deleted_at IS NULL
+		ORDER BY pinned DESC, created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := q.Query(ctx, query, walletID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallet notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := make([]*entities.WalletNote, 0)
+	for rows.Next() {
+		note, err := scanWalletNote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan wallet note row: %w", err)
+		}
+		notes = append(notes, note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating wallet note rows: %w", err)
+	}
+
+	return notes, nil
+}
+
+// scanWalletNote читает одну строку wallet_notes.
+func scanWalletNote(row pgx.Row) (*entities.WalletNote, error) {
+	var (
+		id, walletID, authorID uuid.UUID
+		body                   string
+		pinned                 bool
+		createdAt              time.Time
+		deletedAt              *time.Time
+		deletedBy              *uuid.UUID
+	)
+	if err := row.Scan(&id, &walletID, &authorID, &body, &pinned, &createdAt, &deletedAt, &deletedBy); err != nil {
+		return nil, err
+	}
+
+	return entities.ReconstructWalletNote(id, walletID, authorID, body, pinned, createdAt, deletedAt, deletedBy), nil
+}
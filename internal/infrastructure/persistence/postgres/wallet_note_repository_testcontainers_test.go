@@ -0,0 +1,30 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+func TestWalletNoteRepository_Conformance(t *testing.T) {
+	porttest.RunWalletNoteRepositoryTests(t, func(t *testing.T) porttest.WalletNoteHarness {
+		tc := setupSharedTestDB(t)
+
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000022_create_wallet_notes.up.sql"))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(context.Background(), string(migration))
+		require.NoError(t, err)
+
+		return porttest.WalletNoteHarness{
+			Repositories: newConformanceRepositories(t),
+			Notes:        NewWalletNoteRepository(tc.pool),
+		}
+	})
+}
@@ -30,21 +30,35 @@ var _ ports.WalletRepository = (*WalletRepository)(nil)
 // - Optimistic Locking через balance_version
 // - Money хранится как BIGINT (cents/satoshis)
 // - Currency хранится как VARCHAR
+// - Балансы переносятся в wallet_balances / wallet_holds по фазам (см. WithMigration)
 type WalletRepository struct {
-	pool *pgxpool.Pool
+	pool      *pgxpool.Pool
+	migration WalletMigration
+	replica   *ReadReplica // nil - все чтения с primary
 }
 
 // NewWalletRepository создаёт новый WalletRepository.
+// По умолчанию фаза переноса балансов - off.
 func NewWalletRepository(pool *pgxpool.Pool) *WalletRepository {
-	return &WalletRepository{pool: pool}
+	return &WalletRepository{
+		pool:      pool,
+		migration: WalletMigration{Phase: WalletMigrationOff},
+	}
+}
+
+// WithReadReplica направляет чтения кошельков вне UnitOfWork на реплику
+// (wallet_reads - точечные, wallet_lists - List).
+func (r *WalletRepository) WithReadReplica(replica *ReadReplica) *WalletRepository {
+	r.replica = replica
+	return r
 }
 
 // getQuerier возвращает querier из context или pool.
 func (r *WalletRepository) getQuerier(ctx context.Context) querier {
 	if tx := extractTx(ctx); tx != nil {
-		return tx
+		return withRequestStats(ctx, tx)
 	}
-	return r.pool
+	return withRequestStats(ctx, r.pool)
 }
 
 // Save сохраняет кошелёк с проверкой версии (optimistic locking).
@@ -54,6 +68,10 @@ func (r *WalletRepository) getQuerier(ctx context.Context) querier {
 // - Если изменилась - возвращаем ConcurrencyError
 // - Клиент должен перечитать wallet и повторить операцию
 func (r *WalletRepository) Save(ctx context.Context, wallet *entities.Wallet) error {
+	if r.migration.writesNew() {
+		return r.saveMigrating(ctx, wallet)
+	}
+
 	q := r.getQuerier(ctx)
 
 	// Для нового кошелька (version = 0) делаем INSERT
@@ -71,8 +89,9 @@ func (r *WalletRepository) insert(ctx context.Context, q querier, wallet *entiti
 		INSERT INTO wallets (
 			id, user_id, currency, wallet_type, status,
 			available_balance, pending_balance, balance_version,
-			daily_limit, monthly_limit, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			daily_limit, monthly_limit, jurisdiction, created_at, updated_at,
+			max_pending_transactions
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), $12, $13, $14)
 	`
 
 	_, err := q.Exec(ctx, query,
@@ -86,8 +105,10 @@ func (r *WalletRepository) insert(ctx context.Context, q querier, wallet *entiti
 		wallet.BalanceVersion(),
 		wallet.DailyLimit().Cents(),
 		wallet.MonthlyLimit().Cents(),
+		wallet.Jurisdiction(),
 		wallet.CreatedAt(),
 		wallet.UpdatedAt(),
+		wallet.MaxPendingTransactions(),
 	)
 
 	if err != nil {
@@ -122,7 +143,8 @@ func (r *WalletRepository) update(ctx context.Context, q querier, wallet *entiti
 			balance_version = $5,
 			daily_limit = $6,
 			monthly_limit = $7,
-			updated_at = $8
+			updated_at = $8,
+			max_pending_transactions = $10
 		WHERE id = $1 AND balance_version = $9
 	`
 
@@ -140,6 +162,7 @@ func (r *WalletRepository) update(ctx context.Context, q querier, wallet *entiti
 		wallet.MonthlyLimit().Cents(),
 		wallet.UpdatedAt(),
 		expectedVersion,
+		wallet.MaxPendingTransactions(),
 	)
 
 	if err != nil {
@@ -170,17 +193,11 @@ func (r *WalletRepository) FindByID(ctx context.Context, id uuid.UUID) (*entitie
 	)
 	defer span.End()
 
-	q := r.getQuerier(ctx)
+	query := r.selectWallets() + ` WHERE w.id = $1`
 
-	query := `
-		SELECT id, user_id, currency, wallet_type, status,
-			   available_balance, pending_balance, balance_version,
-			   daily_limit, monthly_limit, created_at, updated_at
-		FROM wallets
-		WHERE id = $1
-	`
-
-	wallet, err := r.scanWallet(q.QueryRow(ctx, query, id))
+	wallet, err := routeRead(ctx, r.replica, ReadGroupWalletReads, r.getQuerier(ctx), func(ctx context.Context, q querier) (*entities.Wallet, error) {
+		return r.scanWallet(q.QueryRow(ctx, query, id))
+	})
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -190,247 +207,292 @@ func (r *WalletRepository) FindByID(ctx context.Context, id uuid.UUID) (*entitie
 	return wallet, nil
 }
 
+// FindBalanceByID читает только колонки балансов, версию и владельца.
+// Балансы берутся из той же схемы, что и в FindByID (см. WithMigration).
+func (r *WalletRepository) FindBalanceByID(ctx context.Context, id uuid.UUID) (*ports.WalletBalance, error) {
+	return routeRead(ctx, r.replica, ReadGroupWalletReads, r.getQuerier(ctx), func(ctx context.Context, q querier) (*ports.WalletBalance, error) {
+		return r.findBalanceByID(ctx, q, id)
+	})
+}
+
+func (r *WalletRepository) findBalanceByID(ctx context.Context, q querier, id uuid.UUID) (*ports.WalletBalance, error) {
+	query := `SELECT w.id, w.user_id, w.currency, w.available_balance, w.pending_balance, w.balance_version`
+	if r.migration.joinsNew() {
+		query += ledgerColumns + ` FROM wallets w` + ledgerJoin
+	} else {
+		query += ` FROM wallets w`
+	}
+	query += ` WHERE w.id = $1`
+
+	var w walletRow
+	targets := []any{&w.id, &w.userID, &w.currencyCode, &w.available, &w.pending, &w.version}
+	if r.migration.joinsNew() {
+		targets = append(targets, &w.ledgerAvailable, &w.ledgerHeld, &w.ledgerVersion)
+	}
+	if err := q.QueryRow(ctx, query, id).Scan(targets...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErrors.ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("failed to find wallet balance: %w", err)
+	}
+
+	if err := r.resolveBalances(&w); err != nil {
+		return nil, err
+	}
+
+	return &ports.WalletBalance{
+		WalletID:       w.id,
+		UserID:         w.userID,
+		CurrencyCode:   w.currencyCode,
+		AvailableCents: w.available,
+		PendingCents:   w.pending,
+		BalanceVersion: w.version,
+	}, nil
+}
+
 // FindByUserAndCurrency находит кошелёк пользователя для конкретной валюты.
 func (r *WalletRepository) FindByUserAndCurrency(ctx context.Context, userID uuid.UUID, currency valueobjects.Currency) (*entities.Wallet, error) {
-	q := r.getQuerier(ctx)
+	query := r.selectWallets() + ` WHERE w.user_id = $1 AND w.currency = $2`
 
-	query := `
-		SELECT id, user_id, currency, wallet_type, status,
-			   available_balance, pending_balance, balance_version,
-			   daily_limit, monthly_limit, created_at, updated_at
-		FROM wallets
-		WHERE user_id = $1 AND currency = $2
-	`
-
-	return r.scanWallet(q.QueryRow(ctx, query, userID, currency.Code()))
+	return routeRead(ctx, r.replica, ReadGroupWalletReads, r.getQuerier(ctx), func(ctx context.Context, q querier) (*entities.Wallet, error) {
+		return r.scanWallet(q.QueryRow(ctx, query, userID, currency.Code()))
+	})
 }
 
 // FindByUserID возвращает все кошельки пользователя.
 func (r *WalletRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Wallet, error) {
-	q := r.getQuerier(ctx)
-
-	query := `
-		SELECT id, user_id, currency, wallet_type, status,
-			   available_balance, pending_balance, balance_version,
-			   daily_limit, monthly_limit, created_at, updated_at
-		FROM wallets
-		WHERE user_id = $1
-		ORDER BY created_at ASC
+	query := r.selectWallets() + `
+		WHERE w.user_id = $1
+		ORDER BY w.created_at ASC
 	`
 
-	rows, err := q.Query(ctx, query, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find wallets by user: %w", err)
-	}
-	defer rows.Close()
+	return routeRead(ctx, r.replica, ReadGroupWalletReads, r.getQuerier(ctx), func(ctx context.Context, q querier) ([]*entities.Wallet, error) {
+		rows, err := q.Query(ctx, query, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find wallets by user: %w", err)
+		}
+		defer rows.Close()
 
-	return r.scanWallets(rows)
+		return r.scanWallets(rows)
+	})
 }
 
 // ExistsByUserAndCurrency проверяет существование кошелька.
 func (r *WalletRepository) ExistsByUserAndCurrency(ctx context.Context, userID uuid.UUID, currency valueobjects.Currency) (bool, error) {
-	q := r.getQuerier(ctx)
-
 	query := `SELECT EXISTS(SELECT 1 FROM wallets WHERE user_id = $1 AND currency = $2)`
 
-	var exists bool
-	err := q.QueryRow(ctx, query, userID, currency.Code()).Scan(&exists)
-	if err != nil {
-		return false, fmt.Errorf("failed to check wallet existence: %w", err)
-	}
-
-	return exists, nil
+	return routeRead(ctx, r.replica, ReadGroupWalletReads, r.getQuerier(ctx), func(ctx context.Context, q querier) (bool, error) {
+		var exists bool
+		if err := q.QueryRow(ctx, query, userID, currency.Code()).Scan(&exists); err != nil {
+			return false, fmt.Errorf("failed to check wallet existence: %w", err)
+		}
+		return exists, nil
+	})
 }
 
 // List возвращает кошельки с фильтрацией и пагинацией.
 func (r *WalletRepository) List(ctx context.Context, filter ports.WalletFilter, offset, limit int) ([]*entities.Wallet, error) {
-	q := r.getQuerier(ctx)
-
-	// Строим динамический запрос с фильтрами
-	query := `
-		SELECT id, user_id, currency, wallet_type, status,
-			   available_balance, pending_balance, balance_version,
-			   daily_limit, monthly_limit, created_at, updated_at
-		FROM wallets
-		WHERE 1=1
-	`
+	query, args := walletListQuery(r.selectWallets(), filter, offset, limit).SQL()
 
-	args := []interface{}{}
-	argNum := 1
+	return routeRead(ctx, r.replica, ReadGroupWalletLists, r.getQuerier(ctx), func(ctx context.Context, q querier) ([]*entities.Wallet, error) {
+		rows, err := q.Query(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list wallets: %w", err)
+		}
+		defer rows.Close()
 
-	if filter.UserID != nil {
-		query += fmt.Sprintf(" AND user_id = $%d", argNum)
-		args = append(args, *filter.UserID)
-		argNum++
-	}
+		return r.scanWallets(rows)
+	})
+}
 
-	if filter.Currency != nil {
-		query += fmt.Sprintf(" AND currency = $%d", argNum)
-		args = append(args, filter.Currency.Code())
-		argNum++
-	}
+// walletListQuery строит запрос List по фильтру.
+func walletListQuery(selectWallets string, filter ports.WalletFilter, offset, limit int) *selectQuery {
+	q := newSelect(selectWallets)
+	whereOpt(q, "w.user_id = ?", filter.UserID)
+	whereOptFunc(q, "w.currency = ?", filter.Currency, valueobjects.Currency.Code)
+	whereOptFunc(q, "w.status = ?", filter.Status, asString)
+	return q.OrderBy("w.created_at DESC").Page(offset, limit)
+}
 
-	if filter.Status != nil {
-		query += fmt.Sprintf(" AND status = $%d", argNum)
-		args = append(args, string(*filter.Status))
-		argNum++
+// ============================================
+// Scanning
+// ============================================
+
+// walletColumns - колонки wallets в порядке walletRow.targets.
+const walletColumns = `
+	w.id, w.user_id, w.currency, w.wallet_type, w.status,
+	w.available_balance, w.pending_balance, w.balance_version,
+	w.daily_limit, w.monthly_limit, w.jurisdiction, w.created_at, w.updated_at,
+	w.max_pending_transactions`
+
+// ledgerColumns - балансы из новой схемы; NULL, если строки ещё нет.
+const ledgerColumns = `,
+	b.available_balance, h.held_balance, b.balance_version`
+
+// ledgerJoin подключает новую схему балансов.
+const ledgerJoin = `
+	LEFT JOIN wallet_balances b ON b.wallet_id = w.id
+	LEFT JOIN wallet_holds h ON h.wallet_id = w.id`
+
+// selectWallets возвращает SELECT ... FROM wallets w для текущей фазы переноса.
+// Условия запроса дописываются вызывающим с алиасом w.
+func (r *WalletRepository) selectWallets() string {
+	if r.migration.joinsNew() {
+		return `SELECT` + walletColumns + ledgerColumns + ` FROM wallets w` + ledgerJoin
 	}
+	return `SELECT` + walletColumns + ` FROM wallets w`
+}
 
-	query += fmt.Sprintf(" ORDER BY created_at DESC OFFSET $%d LIMIT $%d", argNum, argNum+1)
-	args = append(args, offset, limit)
+// walletRow - строка кошелька в виде колонок БД.
+type walletRow struct {
+	id, userID                             uuid.UUID
+	currencyCode, walletTypeStr, statusStr string
+	available, pending                     int64
+	version                                int64
+	dailyLimitCents, monthlyLimitCents     int64
+	jurisdiction                           *string
+	createdAt, updatedAt                   time.Time
+	maxPendingTransactions                 *int
+
+	// Новая схема (только если фаза подключает wallet_balances / wallet_holds)
+	ledgerAvailable, ledgerHeld, ledgerVersion *int64
+}
 
-	rows, err := q.Query(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list wallets: %w", err)
+// targets возвращает адреса полей для Scan в порядке selectWallets.
+func (w *walletRow) targets(withLedger bool) []any {
+	targets := []any{
+		&w.id,
+		&w.userID,
+		&w.currencyCode,
+		&w.walletTypeStr,
+		&w.statusStr,
+		&w.available,
+		&w.pending,
+		&w.version,
+		&w.dailyLimitCents,
+		&w.monthlyLimitCents,
+		&w.jurisdiction,
+		&w.createdAt,
+		&w.updatedAt,
+		&w.maxPendingTransactions,
+	}
+	if withLedger {
+		targets = append(targets, &w.ledgerAvailable, &w.ledgerHeld, &w.ledgerVersion)
 	}
-	defer rows.Close()
+	return targets
+}
 
-	return r.scanWallets(rows)
+// divergentFields возвращает колонки, в которых схемы расходятся.
+// Отсутствие строки в новой схеме здесь не учитывается.
+func (w *walletRow) divergentFields() []string {
+	var fields []string
+	if w.ledgerAvailable != nil && *w.ledgerAvailable != w.available {
+		fields = append(fields, "available_balance")
+	}
+	if w.ledgerHeld != nil && *w.ledgerHeld != w.pending {
+		fields = append(fields, "pending_balance")
+	}
+	if w.ledgerVersion != nil && *w.ledgerVersion != w.version {
+		fields = append(fields, "balance_version")
+	}
+	return fields
 }
 
 // scanWallet сканирует одну строку в Wallet entity.
 func (r *WalletRepository) scanWallet(row pgx.Row) (*entities.Wallet, error) {
-	var (
-		id, userID                             uuid.UUID
-		currencyCode, walletTypeStr, statusStr string
-		availableBalance, pendingBalance       int64
-		balanceVersion                         int64
-		dailyLimitCents, monthlyLimitCents     int64
-		createdAt, updatedAt                   time.Time
-	)
-
-	err := row.Scan(
-		&id,
-		&userID,
-		&currencyCode,
-		&walletTypeStr,
-		&statusStr,
-		&availableBalance,
-		&pendingBalance,
-		&balanceVersion,
-		&dailyLimitCents,
-		&monthlyLimitCents,
-		&createdAt,
-		&updatedAt,
-	)
-
-	if err != nil {
+	var w walletRow
+	if err := row.Scan(w.targets(r.migration.joinsNew())...); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domainErrors.ErrEntityNotFound
 		}
 		return nil, fmt.Errorf("failed to scan wallet: %w", err)
 	}
 
+	return r.toEntity(&w)
+}
+
+// scanWallets сканирует несколько строк в список Wallet entities.
+func (r *WalletRepository) scanWallets(rows pgx.Rows) ([]*entities.Wallet, error) {
+	var wallets []*entities.Wallet
+
+	for rows.Next() {
+		var w walletRow
+		if err := rows.Scan(w.targets(r.migration.joinsNew())...); err != nil {
+			return nil, fmt.Errorf("failed to scan wallet row: %w", err)
+		}
+
+		wallet, err := r.toEntity(&w)
+		if err != nil {
+			return nil, err
+		}
+		wallets = append(wallets, wallet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating wallet rows: %w", err)
+	}
+
+	return wallets, nil
+}
+
+// toEntity восстанавливает Wallet entity из строки.
+func (r *WalletRepository) toEntity(w *walletRow) (*entities.Wallet, error) {
+	if err := r.resolveBalances(w); err != nil {
+		return nil, err
+	}
+
 	// Reconstruct value objects
-	currency, err := valueobjects.NewCurrency(currencyCode)
+	currency, err := valueobjects.NewCurrency(w.currencyCode)
 	if err != nil {
 		return nil, fmt.Errorf("invalid currency in database: %w", err)
 	}
 
 	// Конвертируем cents обратно в Money
-	available, err := valueobjects.NewMoneyFromCents(availableBalance, currency)
+	available, err := valueobjects.NewMoneyFromCents(w.available, currency)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert available balance: %w", err)
 	}
 
-	pending, err := valueobjects.NewMoneyFromCents(pendingBalance, currency)
+	pending, err := valueobjects.NewMoneyFromCents(w.pending, currency)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert pending balance: %w", err)
 	}
 
-	dailyLimit, err := valueobjects.NewMoneyFromCents(dailyLimitCents, currency)
+	dailyLimit, err := valueobjects.NewMoneyFromCents(w.dailyLimitCents, currency)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert daily limit: %w", err)
 	}
 
-	monthlyLimit, err := valueobjects.NewMoneyFromCents(monthlyLimitCents, currency)
+	monthlyLimit, err := valueobjects.NewMoneyFromCents(w.monthlyLimitCents, currency)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert monthly limit: %w", err)
 	}
 
+	// Тип и статус новее этой версии читаются как есть, битые - ошибка
+	if !entities.IsWellFormedEnum(w.walletTypeStr) {
+		return nil, fmt.Errorf("malformed wallet type in database: %q", w.walletTypeStr)
+	}
+	if !entities.IsWellFormedEnum(w.statusStr) {
+		return nil, fmt.Errorf("malformed wallet status in database: %q", w.statusStr)
+	}
+
 	// Reconstruct domain entity
 	wallet := entities.ReconstructWallet(
-		id,
-		userID,
+		w.id,
+		w.userID,
 		currency,
-		entities.WalletType(walletTypeStr),
-		entities.WalletStatus(statusStr),
+		entities.WalletType(w.walletTypeStr),
+		entities.WalletStatus(w.statusStr),
 		available,
 		pending,
-		balanceVersion,
+		w.version,
 		dailyLimit,
 		monthlyLimit,
-		createdAt,
-		updatedAt,
+		derefString(w.jurisdiction),
+		w.maxPendingTransactions,
+		w.createdAt,
+		w.updatedAt,
 	)
 
 	return wallet, nil
 }
-
-// scanWallets сканирует несколько строк в список Wallet entities.
-func (r *WalletRepository) scanWallets(rows pgx.Rows) ([]*entities.Wallet, error) {
-	var wallets []*entities.Wallet
-
-	for rows.Next() {
-		var (
-			id, userID                             uuid.UUID
-			currencyCode, walletTypeStr, statusStr string
-			availableBalance, pendingBalance       int64
-			balanceVersion                         int64
-			dailyLimitCents, monthlyLimitCents     int64
-			createdAt, updatedAt                   time.Time
-		)
-
-		err := rows.Scan(
-			&id,
-			&userID,
-			&currencyCode,
-			&walletTypeStr,
-			&statusStr,
-			&availableBalance,
-			&pendingBalance,
-			&balanceVersion,
-			&dailyLimitCents,
-			&monthlyLimitCents,
-			&createdAt,
-			&updatedAt,
-		)
-
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan wallet row: %w", err)
-		}
-
-		currency, err := valueobjects.NewCurrency(currencyCode)
-		if err != nil {
-			return nil, fmt.Errorf("invalid currency in database: %w", err)
-		}
-
-		available, _ := valueobjects.NewMoneyFromCents(availableBalance, currency)
-		pending, _ := valueobjects.NewMoneyFromCents(pendingBalance, currency)
-		dailyLimit, _ := valueobjects.NewMoneyFromCents(dailyLimitCents, currency)
-		monthlyLimit, _ := valueobjects.NewMoneyFromCents(monthlyLimitCents, currency)
-
-		wallet := entities.ReconstructWallet(
-			id,
-			userID,
-			currency,
-			entities.WalletType(walletTypeStr),
-			entities.WalletStatus(statusStr),
-			available,
-			pending,
-			balanceVersion,
-			dailyLimit,
-			monthlyLimit,
-			createdAt,
-			updatedAt,
-		)
-
-		wallets = append(wallets, wallet)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating wallet rows: %w", err)
-	}
-
-	return wallets, nil
-}
@@ -0,0 +1,197 @@
+// Package postgres - WalletSettingsRepository implementation.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check: WalletSettingsRepository implements ports.WalletSettingsRepository
+var _ ports.WalletSettingsRepository = (*WalletSettingsRepository)(nil)
+
+// WalletSettingsRepository реализует ports.WalletSettingsRepository
+// (таблицы wallet_settings и incoming_transfer_views).
+type WalletSettingsRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWalletSettingsRepository создаёт новый WalletSettingsRepository.
+func NewWalletSettingsRepository(pool *pgxpool.Pool) *WalletSettingsRepository {
+	return &WalletSettingsRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *WalletSettingsRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// Save сохраняет настройки кошелька (upsert по wallet_id).
+func (r *WalletSettingsRepository) Save(ctx context.Context, settings *entities.WalletSettings) error {
+	q := r.getQuerier(ctx)
+
+	query := `
+		INSERT INTO wallet_settings (wallet_id, incoming_description_template, auto_tags, display_currency, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (wallet_id) DO UPDATE SET
+			incoming_description_template = EXCLUDED.incoming_description_template,
+			auto_tags = EXCLUDED.auto_tags,
+			display_currency = EXCLUDED.display_currency,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := q.Exec(ctx, query,
+		settings.WalletID(),
+		settings.IncomingDescriptionTemplate(),
+		settings.AutoTags(),
+		settings.DisplayCurrency(),
+		settings.UpdatedBy(),
+		settings.UpdatedAt(),
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domainErrors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", err)
+		}
+		return fmt.Errorf("failed to save wallet settings: %w", err)
+	}
+
+	return nil
+}
+
+// FindByWalletID загружает настройки кошелька.
+func (r *WalletSettingsRepository) FindByWalletID(ctx context.Context, walletID uuid.UUID) (*entities.WalletSettings, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT incoming_description_template, auto_tags, display_currency, updated_by, updated_at
+		FROM wallet_settings
+		WHERE wallet_id = $1
+	`
+
+	var (
+		template        string
+		tags            []string
+		displayCurrency string
+		updatedBy       uuid.UUID
+		updatedAt       time.Time
+	)
+	err := q.QueryRow(ctx, query, walletID).Scan(&template, &tags, &displayCurrency, &updatedBy, &updatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErrors.ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("failed to find wallet settings: %w", err)
+	}
+
+	return entities.ReconstructWalletSettings(walletID, template, tags, displayCurrency, updatedBy, updatedAt), nil
+}
+
+// FindByWalletIDs загружает настройки нескольких кошельков одним запросом.
+func (r *WalletSettingsRepository) FindByWalletIDs(ctx context.Context, walletIDs []uuid.UUID) (map[uuid.UUID]*entities.WalletSettings, error) {
+	result := make(map[uuid.UUID]*entities.WalletSettings, len(walletIDs))
+	if len(walletIDs) == 0 {
+		return result, nil
+	}
+
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT wallet_id, incoming_description_template, auto_tags, display_currency, updated_by, updated_at
+		FROM wallet_settings
+		WHERE wallet_id = ANY($1)
+	`
+
+	rows, err := q.Query(ctx, query, walletIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find wallet settings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			walletID        uuid.UUID
+			template        string
+			tags            []string
+			displayCurrency string
+			updatedBy       uuid.UUID
+			updatedAt       time.Time
+		)
+		if err := rows.Scan(&walletID, &template, &tags, &displayCurrency, &updatedBy, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan wallet settings: %w", err)
+		}
+		result[walletID] = entities.ReconstructWalletSettings(walletID, template, tags, displayCurrency, updatedBy, updatedAt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate wallet settings: %w", err)
+	}
+
+	return result, nil
+}
+
+// SaveIncomingView сохраняет представление входящего перевода. Представление
+// отрисовывается один раз при переводе, повторная запись его не меняет.
+func (r *WalletSettingsRepository) SaveIncomingView(ctx context.Context, view *entities.IncomingTransferView) error {
+	q := r.getQuerier(ctx)
+
+	query := `
+		INSERT INTO incoming_transfer_views (transaction_id, wallet_id, description, tags, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (transaction_id) DO NOTHING
+	`
+
+	_, err := q.Exec(ctx, query,
+		view.TransactionID(),
+		view.WalletID(),
+		view.Description(),
+		view.Tags(),
+		view.CreatedAt(),
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domainErrors.NewDomainError("WALLET_NOT_FOUND", "wallet not found", err)
+		}
+		return fmt.Errorf("failed to save incoming transfer view: %w", err)
+	}
+
+	return nil
+}
+
+// FindIncomingView загружает представление перевода для получателя.
+func (r *WalletSettingsRepository) FindIncomingView(ctx context.Context, transactionID uuid.UUID) (*entities.IncomingTransferView, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT wallet_id, description, tags, created_at
+		FROM incoming_transfer_views
+		WHERE transaction_id = $1
+	`
+
+	var (
+		walletID    uuid.UUID
+		description string
+		tags        []string
+		createdAt   time.Time
+	)
+	err := q.QueryRow(ctx, query, transactionID).Scan(&walletID, &description, &tags, &createdAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErrors.ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("failed to find incoming transfer view: %w", err)
+	}
+
+	return entities.ReconstructIncomingTransferView(transactionID, walletID, description, tags, createdAt), nil
+}
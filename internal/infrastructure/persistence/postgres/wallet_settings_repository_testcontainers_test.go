@@ -0,0 +1,30 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+func TestWalletSettingsRepository_Conformance(t *testing.T) {
+	porttest.RunWalletSettingsRepositoryTests(t, func(t *testing.T) porttest.WalletSettingsHarness {
+		tc := setupSharedTestDB(t)
+
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000038_create_wallet_settings.up.sql"))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(context.Background(), string(migration))
+		require.NoError(t, err)
+
+		return porttest.WalletSettingsHarness{
+			Repositories: newConformanceRepositories(t),
+			Settings:     NewWalletSettingsRepository(tc.pool),
+		}
+	})
+}
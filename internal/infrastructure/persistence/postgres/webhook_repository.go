@@ -0,0 +1,417 @@
+// Package postgres - WebhookSubscriptionRepository и WebhookDeliveryRepository implementation.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	domainErrors "github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// Compile-time checks
+var (
+	_ ports.WebhookSubscriptionRepository = (*WebhookSubscriptionRepository)(nil)
+	_ ports.WebhookDeliveryRepository     = (*WebhookDeliveryRepository)(nil)
+)
+
+// ============================================
+// Subscriptions
+// ============================================
+
+// WebhookSubscriptionRepository реализует ports.WebhookSubscriptionRepository
+// (таблица webhook_subscriptions).
+type WebhookSubscriptionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWebhookSubscriptionRepository создаёт новый WebhookSubscriptionRepository.
+func NewWebhookSubscriptionRepository(pool *pgxpool.Pool) *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *WebhookSubscriptionRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// Save создаёт подписку (version = 1) или обновляет её с optimistic locking.
+func (r *WebhookSubscriptionRepository) Save(ctx context.Context, subscription *entities.WebhookSubscription) error {
+	q := r.getQuerier(ctx)
+
+	if subscription.Version() == 1 {
+		return r.insert(ctx, q, subscription)
+	}
+	return r.update(ctx, q, subscription)
+}
+
+// insert создаёт новую подписку.
+func (r *WebhookSubscriptionRepository) insert(ctx context.Context, q querier, s *entities.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (
+			id, user_id, url, secret, event_types, status,
+			consecutive_failures, failing_since, last_failure_reason, disabled_at,
+			version, created_at, updated_at, deleted_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`
+
+	_, err := q.Exec(ctx, query,
+		s.ID(), s.UserID(), s.URL(), s.Secret(), s.EventTypes(), string(s.Status()),
+		s.ConsecutiveFailures(), s.FailingSince(), s.LastFailureReason(), s.DisabledAt(),
+		s.Version(), s.CreatedAt(), s.UpdatedAt(), s.DeletedAt(),
+	)
+	if err != nil {
+		if isUniqueViolation(err, "webhook_subscriptions_pkey") {
+			return domainErrors.NewConcurrencyError("WebhookSubscription", s.ID().String(), "webhook subscription already exists")
+		}
+		if isForeignKeyViolation(err) {
+			return domainErrors.NewDomainError("USER_NOT_FOUND", "user not found", err)
+		}
+		return fmt.Errorf("failed to insert webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// update обновляет подписку, если в БД предыдущая версия.
+func (r *WebhookSubscriptionRepository) update(ctx context.Context, q querier, s *entities.WebhookSubscription) error {
+	query := `
+		UPDATE webhook_subscriptions SET
+			url = $2,
+			event_types = $3,
+			status = $4,
+			consecutive_failures = $5,
+			failing_since = $6,
+			last_failure_reason = $7,
+			disabled_at = $8,
+			version = $9,
+			updated_at = $10,
+			deleted_at = $11
+		WHERE id = $1 AND version = $12
+	`
+
+	expectedVersion := s.Version() - 1
+	result, err := q.Exec(ctx, query,
+		s.ID(), s.URL(), s.EventTypes(), string(s.Status()),
+		s.ConsecutiveFailures(), s.FailingSince(), s.LastFailureReason(), s.DisabledAt(),
+		s.Version(), s.UpdatedAt(), s.DeletedAt(), expectedVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domainErrors.NewConcurrencyError(
+			"WebhookSubscription",
+			s.ID().String(),
+			fmt.Sprintf("webhook subscription was modified by another transaction (expected version: %d)", expectedVersion),
+		)
+	}
+
+	return nil
+}
+
+const webhookSubscriptionColumns = `
+	id, user_id, url, secret, event_types, status,
+	consecutive_failures, failing_since, last_failure_reason, disabled_at,
+	version, created_at, updated_at, deleted_at
+`
+
+// FindByID загружает неудалённую подписку.
+func (r *WebhookSubscriptionRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.WebhookSubscription, error) {
+	q := r.getQuerier(ctx)
+
+	query := `SELECT ` + webhookSubscriptionColumns + `
+		FROM webhook_subscriptions
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	subscription, err := scanWebhookSubscription(q.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErrors.ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("failed to find webhook subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// ListByUserID возвращает неудалённые подписки пользователя по created_at DESC.
+func (r *WebhookSubscriptionRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.WebhookSubscription, error) {
+	q := r.getQuerier(ctx)
+
+	query := `SELECT ` + webhookSubscriptionColumns + `
+		FROM webhook_subscriptions
+		WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := q.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*entities.WebhookSubscription, 0)
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// PurgeDeleted удаляет подписки, удалённые раньше cutoff; доставки удаляются
+// каскадом (ON DELETE CASCADE).
+func (r *WebhookSubscriptionRepository) PurgeDeleted(ctx context.Context, cutoff time.Time) (int64, error) {
+	q := r.getQuerier(ctx)
+
+	result, err := q.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE deleted_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge webhook subscriptions: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// scanWebhookSubscription читает строку в порядке webhookSubscriptionColumns.
+func scanWebhookSubscription(row pgx.Row) (*entities.WebhookSubscription, error) {
+	var (
+		id, userID          uuid.UUID
+		url, secret         string
+		eventTypes          []string
+		status              string
+		consecutiveFailures int
+		failingSince        *time.Time
+		lastFailureReason   string
+		disabledAt          *time.Time
+		version             int64
+		createdAt           time.Time
+		updatedAt           time.Time
+		deletedAt           *time.Time
+	)
+	if err := row.Scan(
+		&id, &userID, &url, &secret, &eventTypes, &status,
+		&consecutiveFailures, &failingSince, &lastFailureReason, &disabledAt,
+		&version, &createdAt, &updatedAt, &deletedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return entities.ReconstructWebhookSubscription(
+		id, userID, url, secret, eventTypes, entities.WebhookSubscriptionStatus(status),
+		consecutiveFailures, failingSince, lastFailureReason, disabledAt,
+		version, createdAt, updatedAt, deletedAt,
+	), nil
+}
+
+// ============================================
+// Deliveries
+// ============================================
+
+// WebhookDeliveryRepository реализует ports.WebhookDeliveryRepository
+// (таблица webhook_deliveries).
+type WebhookDeliveryRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWebhookDeliveryRepository создаёт новый WebhookDeliveryRepository.
+func NewWebhookDeliveryRepository(pool *pgxpool.Pool) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *WebhookDeliveryRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// Enqueue ставит событие в очередь подписки; повтор того же события
+// игнорируется (webhook_deliveries_event_unique).
+func (r *WebhookDeliveryRepository) Enqueue(ctx context.Context, d *entities.WebhookDelivery) error {
+	q := r.getQuerier(ctx)
+
+	query := `
+		INSERT INTO webhook_deliveries (
+			id, subscription_id, event_id, event_type, payload, status,
+			attempts, last_error, last_status_code, next_attempt_at, created_at, finished_at
+		) VALUES ($1, $2, $3, $4, $5::json, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+
+	_, err := q.Exec(ctx, query,
+		d.ID(), d.SubscriptionID(), d.EventID(), d.EventType(), string(d.Payload()), string(d.Status()),
+		d.Attempts(), d.LastError(), d.LastStatusCode(), d.NextAttemptAt(), d.CreatedAt(), d.FinishedAt(),
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domainErrors.NewDomainError("WEBHOOK_SUBSCRIPTION_NOT_FOUND", "webhook subscription not found", err)
+		}
+		return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// Save сохраняет результат попытки доставки.
+func (r *WebhookDeliveryRepository) Save(ctx context.Context, d *entities.WebhookDelivery) error {
+	q := r.getQuerier(ctx)
+
+	query := `
+		UPDATE webhook_deliveries SET
+			status = $2,
+			attempts = $3,
+			last_error = $4,
+			last_status_code = $5,
+			next_attempt_at = $6,
+			finished_at = $7
+		WHERE id = $1
+	`
+
+	result, err := q.Exec(ctx, query,
+		d.ID(), string(d.Status()), d.Attempts(), d.LastError(), d.LastStatusCode(), d.NextAttemptAt(), d.FinishedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domainErrors.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+const webhookDeliveryColumns = `
+	d.id, d.subscription_id, d.event_id, d.event_type, d.payload::text, d.status,
+	d.attempts, d.last_error, d.last_status_code, d.next_attempt_at, d.created_at, d.finished_at
+`
+
+// FindDue возвращает PENDING доставки активных подписок со сроком не позже now.
+func (r *WebhookDeliveryRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*entities.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.status = 'PENDING'
+		  AND d.next_attempt_at <= $1
+		  AND s.status = 'ACTIVE'
+		  AND s.deleted_at IS NULL
+		ORDER BY d.created_at, d.id
+		LIMIT $2
+	`
+
+	return r.query(ctx, query, now, limit)
+}
+
+// ListBySubscription возвращает доставки подписки по created_at DESC.
+func (r *WebhookDeliveryRepository) ListBySubscription(ctx context.Context, subscriptionID uuid.UUID, offset, limit int) ([]*entities.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries d
+		WHERE d.subscription_id = $1
+		ORDER BY d.created_at DESC, d.id DESC
+		OFFSET $2 LIMIT $3
+	`
+
+	return r.query(ctx, query, subscriptionID, offset, limit)
+}
+
+// SkipPending отмечает PENDING доставки подписки SKIPPED.
+func (r *WebhookDeliveryRepository) SkipPending(ctx context.Context, subscriptionID uuid.UUID) (int64, error) {
+	return r.exec(ctx, "skip webhook deliveries", `
+		UPDATE webhook_deliveries SET status = 'SKIPPED', finished_at = $2
+		WHERE subscription_id = $1 AND status = 'PENDING'
+	`, subscriptionID, clock.Now())
+}
+
+// ReschedulePending переносит срок PENDING доставок подписки на at.
+func (r *WebhookDeliveryRepository) ReschedulePending(ctx context.Context, subscriptionID uuid.UUID, at time.Time) (int64, error) {
+	return r.exec(ctx, "reschedule webhook deliveries", `
+		UPDATE webhook_deliveries SET next_attempt_at = $2
+		WHERE subscription_id = $1 AND status = 'PENDING'
+	`, subscriptionID, at)
+}
+
+// ExpireBacklog отмечает EXPIRED PENDING доставки подписок, отключённых
+// раньше disabledBefore.
+func (r *WebhookDeliveryRepository) ExpireBacklog(ctx context.Context, disabledBefore time.Time) (int64, error) {
+	return r.exec(ctx, "expire webhook backlog", `
+		UPDATE webhook_deliveries d SET status = 'EXPIRED', finished_at = $2
+		FROM webhook_subscriptions s
+		WHERE s.id = d.subscription_id
+		  AND d.status = 'PENDING'
+		  AND s.status <> 'ACTIVE'
+		  AND s.disabled_at < $1
+	`, disabledBefore, clock.Now())
+}
+
+// DeleteFinishedBefore удаляет завершённые доставки с created_at раньше cutoff.
+func (r *WebhookDeliveryRepository) DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return r.exec(ctx, "delete webhook deliveries", `
+		DELETE FROM webhook_deliveries
+		WHERE status <> 'PENDING' AND created_at < $1
+	`, cutoff)
+}
+
+// exec выполняет массовое изменение и возвращает число затронутых строк.
+func (r *WebhookDeliveryRepository) exec(ctx context.Context, op, query string, args ...any) (int64, error) {
+	result, err := r.getQuerier(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to %s: %w", op, err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// query читает доставки в порядке webhookDeliveryColumns.
+func (r *WebhookDeliveryRepository) query(ctx context.Context, query string, args ...any) ([]*entities.WebhookDelivery, error) {
+	rows, err := r.getQuerier(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*entities.WebhookDelivery, 0)
+	for rows.Next() {
+		var (
+			id, subscriptionID, eventID uuid.UUID
+			eventType, payload, status  string
+			attempts, lastStatusCode    int
+			lastError                   string
+			nextAttemptAt, createdAt    time.Time
+			finishedAt                  *time.Time
+		)
+		if err := rows.Scan(
+			&id, &subscriptionID, &eventID, &eventType, &payload, &status,
+			&attempts, &lastError, &lastStatusCode, &nextAttemptAt, &createdAt, &finishedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, entities.ReconstructWebhookDelivery(
+			id, subscriptionID, eventID, eventType, []byte(payload), entities.WebhookDeliveryStatus(status),
+			attempts, lastError, lastStatusCode, nextAttemptAt, createdAt, finishedAt,
+		))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,31 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports/porttest"
+)
+
+func TestWebhookRepository_Conformance(t *testing.T) {
+	porttest.RunWebhookRepositoryTests(t, func(t *testing.T) porttest.WebhookHarness {
+		tc := setupSharedTestDB(t)
+
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000043_create_webhooks.up.sql"))
+		require.NoError(t, err)
+		_, err = tc.pool.Exec(context.Background(), string(migration))
+		require.NoError(t, err)
+
+		return porttest.WebhookHarness{
+			Repositories:  newConformanceRepositories(t),
+			Subscriptions: NewWebhookSubscriptionRepository(tc.pool),
+			Deliveries:    NewWebhookDeliveryRepository(tc.pool),
+		}
+	})
+}
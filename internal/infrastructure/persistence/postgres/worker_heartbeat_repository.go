@@ -0,0 +1,122 @@
+// Package postgres - WorkerHeartbeatRepository implementation.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time check: WorkerHeartbeatRepository implements ports.WorkerHeartbeatRepository
+var _ ports.WorkerHeartbeatRepository = (*WorkerHeartbeatRepository)(nil)
+
+// WorkerHeartbeatRepository реализует ports.WorkerHeartbeatRepository (таблица workers_heartbeat).
+type WorkerHeartbeatRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWorkerHeartbeatRepository создаёт новый WorkerHeartbeatRepository.
+func NewWorkerHeartbeatRepository(pool *pgxpool.Pool) *WorkerHeartbeatRepository {
+	return &WorkerHeartbeatRepository{pool: pool}
+}
+
+// getQuerier возвращает querier из context (transaction) или pool.
+func (r *WorkerHeartbeatRepository) getQuerier(ctx context.Context) querier {
+	if tx := extractTx(ctx); tx != nil {
+		return withRequestStats(ctx, tx)
+	}
+	return withRequestStats(ctx, r.pool)
+}
+
+// Save записывает heartbeat экземпляра одним batch UPSERT.
+func (r *WorkerHeartbeatRepository) Save(ctx context.Context, statuses []ports.WorkerStatus) error {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO workers_heartbeat (worker, instance, state, last_run_at, last_success_at,
+			items_processed, last_error, stale_after_seconds, heartbeat_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9)
+		ON CONFLICT (worker, instance) DO UPDATE
+		SET state = EXCLUDED.state,
+			last_run_at = EXCLUDED.last_run_at,
+			last_success_at = EXCLUDED.last_success_at,
+			items_processed = EXCLUDED.items_processed,
+			last_error = EXCLUDED.last_error,
+			stale_after_seconds = EXCLUDED.stale_after_seconds,
+			heartbeat_at = EXCLUDED.heartbeat_at
+	`
+
+	batch := &pgx.Batch{}
+	for _, s := range statuses {
+		batch.Queue(query,
+			s.Worker,
+			s.Instance,
+			string(s.State),
+			utcPtr(s.LastRunAt),
+			utcPtr(s.LastSuccessAt),
+			s.ItemsProcessed,
+			s.LastError,
+			int(s.StaleAfter.Seconds()),
+			s.LastHeartbeatAt.UTC(),
+		)
+	}
+
+	if err := r.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to save worker heartbeats: %w", err)
+	}
+	return nil
+}
+
+// ListSince возвращает heartbeat, обновлённые не раньше since.
+func (r *WorkerHeartbeatRepository) ListSince(ctx context.Context, since time.Time) ([]ports.WorkerStatus, error) {
+	q := r.getQuerier(ctx)
+
+	query := `
+		SELECT worker, instance, state, last_run_at, last_success_at,
+			items_processed, last_error, stale_after_seconds, heartbeat_at
+		FROM workers_heartbeat
+		WHERE heartbeat_at >= $1
+		ORDER BY worker, instance
+	`
+
+	rows, err := q.Query(ctx, query, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worker heartbeats: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]ports.WorkerStatus, 0)
+	for rows.Next() {
+		var (
+			s                 ports.WorkerStatus
+			state             string
+			lastError         *string
+			staleAfterSeconds int
+		)
+		if err := rows.Scan(&s.Worker, &s.Instance, &state, &s.LastRunAt, &s.LastSuccessAt,
+			&s.ItemsProcessed, &lastError, &staleAfterSeconds, &s.LastHeartbeatAt); err != nil {
+			return nil, fmt.Errorf("failed to scan worker heartbeat row: %w", err)
+		}
+
+		s.State = ports.WorkerState(state)
+		s.LastError = derefString(lastError)
+		s.StaleAfter = time.Duration(staleAfterSeconds) * time.Second
+		s.LastRunAt = utcPtr(s.LastRunAt)
+		s.LastSuccessAt = utcPtr(s.LastSuccessAt)
+		s.LastHeartbeatAt = s.LastHeartbeatAt.UTC()
+		result = append(result, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating worker heartbeat rows: %w", err)
+	}
+
+	return result, nil
+}
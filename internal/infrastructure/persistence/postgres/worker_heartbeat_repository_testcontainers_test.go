@@ -0,0 +1,58 @@
+//go:build testcontainers
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+func TestWorkerHeartbeatRepository_Integration_SaveAndList(t *testing.T) {
+	tc := setupSharedTestDB(t)
+	ctx := context.Background()
+
+	migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", "000033_create_workers_heartbeat.up.sql"))
+	require.NoError(t, err)
+	_, err = tc.pool.Exec(ctx, string(migration))
+	require.NoError(t, err)
+	_, err = tc.pool.Exec(ctx, "TRUNCATE workers_heartbeat")
+	require.NoError(t, err)
+
+	repo := NewWorkerHeartbeatRepository(tc.pool)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	lastRun := now.Add(-time.Second)
+
+	require.NoError(t, repo.Save(ctx, []ports.WorkerStatus{
+		{Worker: "outbox-relay", Instance: "notifier-1", State: ports.WorkerRunning, LastRunAt: &lastRun,
+			LastHeartbeatAt: now, StaleAfter: time.Minute},
+		{Worker: "balance-summary", Instance: "api-1", State: ports.WorkerIdle,
+			LastHeartbeatAt: now.Add(-48 * time.Hour)},
+	}))
+
+	// Повторное сохранение обновляет строку экземпляра
+	require.NoError(t, repo.Save(ctx, []ports.WorkerStatus{
+		{Worker: "outbox-relay", Instance: "notifier-1", State: ports.WorkerIdle, LastRunAt: &lastRun,
+			LastSuccessAt: &now, ItemsProcessed: 4, LastError: "", LastHeartbeatAt: now, StaleAfter: time.Minute},
+	}))
+
+	statuses, err := repo.ListSince(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+
+	relay := statuses[0]
+	assert.Equal(t, ports.WorkerIdle, relay.State)
+	assert.Equal(t, 4, relay.ItemsProcessed)
+	assert.Empty(t, relay.LastError)
+	assert.Equal(t, time.Minute, relay.StaleAfter)
+	require.NotNil(t, relay.LastSuccessAt)
+	assert.True(t, relay.LastSuccessAt.Equal(now))
+	assert.True(t, relay.LastHeartbeatAt.Equal(now))
+}
@@ -1,4 +1,15 @@
 // Package poller provides an outbox poller that reads unpublished events and publishes to NATS.
+//
+// Events are delivered in two lanes driven by one goroutine:
+//   - the main loop drains the outbox in insertion order;
+//   - the HIGH loop runs more often with a smaller batch and picks only
+//     aggregates that have a pending HIGH event, together with their older
+//     pending events, so a bulk backlog cannot delay a critical event and
+//     per-aggregate order is never broken.
+//
+// With a leader lock only the instance holding the "outbox-relay" lease
+// runs the main loop (the NORMAL and LOW backlog); every instance keeps
+// polling the HIGH lane.
 package poller
 
 import (
@@ -9,17 +20,34 @@ import (
 
 	natsadapter "github.com/Haleralex/wallethub/internal/adapters/nats"
 	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/events"
 )
 
+// Publisher publishes an outbox event to the broker.
+type Publisher interface {
+	Publish(ctx context.Context, msg *natsadapter.EventMessage) error
+}
+
 // OutboxPoller reads unpublished events from the outbox and publishes them to NATS.
 type OutboxPoller struct {
 	outboxRepo   ports.OutboxRepository
-	publisher    *natsadapter.Publisher
+	publisher    Publisher
 	logger       *slog.Logger
 	pollInterval time.Duration
 	batchSize    int
 	maxRetries   int
 	stopCh       chan struct{}
+
+	highPollInterval time.Duration
+	highBatchSize    int
+
+	onQueueDepth func(priority string, depth int)
+	onDelivered  func(priority string, latency time.Duration)
+	heartbeat    ports.WorkerHeartbeat
+
+	leader    ports.LeaseLock
+	leaderTTL time.Duration
+	lease     ports.Lease // held leadership; touched only by the Start goroutine
 }
 
 // Config holds outbox poller configuration.
@@ -27,23 +55,69 @@ type Config struct {
 	PollInterval time.Duration
 	BatchSize    int
 	MaxRetries   int
+
+	// HighPollInterval enables the HIGH lane loop; 0 disables it.
+	HighPollInterval time.Duration
+	HighBatchSize    int
+
+	// OnQueueDepth reports pending events per lane after each main poll. May be nil.
+	OnQueueDepth func(priority string, depth int)
+
+	// OnDelivered reports the latency from insert to publication. May be nil.
+	OnDelivered func(priority string, latency time.Duration)
+
+	// Heartbeat receives a report for every main poll. May be nil.
+	Heartbeat ports.WorkerHeartbeat
+
+	// Leader elects the single instance running the main loop. nil - every
+	// instance runs it.
+	Leader ports.LeaseLock
+
+	// LeaderTTL is how long leadership outlives a crashed leader; 0 - DefaultLeaderTTL.
+	LeaderTTL time.Duration
 }
 
+// DefaultLeaderTTL is the leadership lease used when Config.LeaderTTL is 0.
+const DefaultLeaderTTL = 30 * time.Second
+
+// leaderKey is the lease key of the outbox relay leader.
+const leaderKey = "outbox-relay"
+
 // New creates a new OutboxPoller.
 func New(
 	outboxRepo ports.OutboxRepository,
-	publisher *natsadapter.Publisher,
+	publisher Publisher,
 	logger *slog.Logger,
 	cfg Config,
 ) *OutboxPoller {
+	highBatchSize := cfg.HighBatchSize
+	if highBatchSize <= 0 {
+		highBatchSize = cfg.BatchSize
+	}
+	heartbeat := cfg.Heartbeat
+	if heartbeat == nil {
+		heartbeat = ports.NoopWorkerHeartbeat{}
+	}
+	leaderTTL := cfg.LeaderTTL
+	if leaderTTL <= 0 {
+		leaderTTL = DefaultLeaderTTL
+	}
+
 	return &OutboxPoller{
-		outboxRepo:   outboxRepo,
-		publisher:    publisher,
-		logger:       logger,
-		pollInterval: cfg.PollInterval,
-		batchSize:    cfg.BatchSize,
-		maxRetries:   cfg.MaxRetries,
-		stopCh:       make(chan struct{}),
+		outboxRepo:       outboxRepo,
+		publisher:        publisher,
+		logger:           logger,
+		pollInterval:     cfg.PollInterval,
+		batchSize:        cfg.BatchSize,
+		maxRetries:       cfg.MaxRetries,
+		stopCh:           make(chan struct{}),
+		highPollInterval: cfg.HighPollInterval,
+		highBatchSize:    highBatchSize,
+		onQueueDepth:     cfg.OnQueueDepth,
+		onDelivered:      cfg.OnDelivered,
+		heartbeat:        heartbeat,
+		leader:           cfg.Leader,
+		leaderTTL:        leaderTTL,
 	}
 }
 
@@ -52,19 +126,36 @@ func (p *OutboxPoller) Start(ctx context.Context) {
 	p.logger.Info("Outbox poller started",
 		slog.Duration("interval", p.pollInterval),
 		slog.Int("batch_size", p.batchSize),
+		slog.Duration("high_interval", p.highPollInterval),
+		slog.Int("high_batch_size", p.highBatchSize),
+		slog.Bool("leader_election", p.leader != nil),
 	)
+	defer p.resign()
 
 	ticker := time.NewTicker(p.pollInterval)
 	defer ticker.Stop()
 
+	// Both lanes share this goroutine, so they never publish concurrently
+	// and cannot reorder events of one aggregate between them.
+	var highC <-chan time.Time
+	if p.highPollInterval > 0 {
+		highTicker := time.NewTicker(p.highPollInterval)
+		defer highTicker.Stop()
+		highC = highTicker.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
+			p.heartbeat.Stopped()
 			p.logger.Info("Outbox poller stopped (context cancelled)")
 			return
 		case <-p.stopCh:
+			p.heartbeat.Stopped()
 			p.logger.Info("Outbox poller stopped")
 			return
+		case <-highC:
+			p.pollHigh(ctx)
 		case <-ticker.C:
 			p.poll(ctx)
 		}
@@ -76,20 +167,119 @@ func (p *OutboxPoller) Stop() {
 	close(p.stopCh)
 }
 
+// poll delivers the next batch in insertion order and reports lane depths.
+// Without leadership the poll is skipped: the leader drains the backlog.
 func (p *OutboxPoller) poll(ctx context.Context) {
-	events, err := p.outboxRepo.FindUnpublished(ctx, p.batchSize)
+	if !p.lead(ctx) {
+		p.heartbeat.Beat()
+		return
+	}
+	if p.lease != nil {
+		ctx = ports.ContextWithLease(ctx, p.lease)
+	}
+
+	p.heartbeat.RunStarted()
+
+	pending, err := p.outboxRepo.FindUnpublished(ctx, p.batchSize)
 	if err != nil {
 		p.logger.Error("Failed to find unpublished events", slog.String("error", err.Error()))
+		p.heartbeat.RunFinished(0, err)
 		return
 	}
 
-	if len(events) == 0 {
+	p.heartbeat.RunFinished(p.deliver(ctx, pending), nil)
+	p.reportQueueDepth(ctx)
+}
+
+// pollHigh delivers pending HIGH events together with the older pending
+// events of their aggregates.
+func (p *OutboxPoller) pollHigh(ctx context.Context) {
+	pending, err := p.outboxRepo.FindUnpublishedByPriority(ctx, ports.OutboxPriorityHigh, p.highBatchSize)
+	if err != nil {
+		p.logger.Error("Failed to find unpublished HIGH events", slog.String("error", err.Error()))
 		return
 	}
 
-	p.logger.Debug("Found unpublished events", slog.Int("count", len(events)))
+	p.deliver(ctx, pending)
+}
+
+// lead reports whether this instance runs the main loop, acquiring
+// leadership when it is free.
+func (p *OutboxPoller) lead(ctx context.Context) bool {
+	if p.leader == nil {
+		return true
+	}
+
+	if p.lease != nil {
+		select {
+		case <-p.lease.Lost():
+			p.logger.Warn("Outbox relay leadership lost")
+			p.resign()
+		default:
+			return true
+		}
+	}
+
+	lease, err := p.leader.TryAcquire(ctx, leaderKey, p.leaderTTL)
+	if err != nil {
+		p.logger.Error("Failed to acquire outbox relay leadership", slog.String("error", err.Error()))
+		return false
+	}
+	if lease == nil {
+		return false
+	}
+
+	p.lease = lease
+	p.logger.Info("Outbox relay leadership acquired", slog.Int64("fencing_token", lease.Token()))
+	return true
+}
+
+// resign releases leadership so another instance takes over without
+// waiting for the lease to expire.
+func (p *OutboxPoller) resign() {
+	if p.lease == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := p.lease.Release(ctx); err != nil {
+		p.logger.Warn("Failed to release outbox relay leadership", slog.String("error", err.Error()))
+	}
+	p.lease = nil
+}
+
+func (p *OutboxPoller) reportQueueDepth(ctx context.Context) {
+	if p.onQueueDepth == nil {
+		return
+	}
+
+	depth, err := p.outboxRepo.CountPending(ctx)
+	if err != nil {
+		p.logger.Error("Failed to count pending events", slog.String("error", err.Error()))
+		return
+	}
+	for priority, n := range depth {
+		p.onQueueDepth(string(priority), n)
+	}
+}
+
+// deliver publishes pending events and returns how many were marked published.
+func (p *OutboxPoller) deliver(ctx context.Context, pending []events.DomainEvent) int {
+	if len(pending) == 0 {
+		return 0
+	}
+
+	p.logger.Debug("Found unpublished events", slog.Int("count", len(pending)))
+
+	delivered := 0
+	for _, event := range pending {
+		// A former leader stops at once: the new one delivers the rest
+		if lease := ports.LeaseFromContext(ctx); lease != nil && isLost(lease) {
+			p.logger.Warn("Outbox relay leadership lost mid-batch", slog.Int("delivered", delivered))
+			break
+		}
 
-	for _, event := range events {
 		// Use raw payload from outbox if available (genericEvent stores it),
 		// otherwise fall back to JSON marshaling.
 		var payload []byte
@@ -119,6 +309,15 @@ func (p *OutboxPoller) poll(ctx context.Context) {
 			OccurredAt:  event.OccurredAt(),
 		}
 
+		// Версия сборки, сохранившей событие в outbox (не версия poller'а)
+		type producerVersioner interface {
+			ProducerVersion() string
+		}
+		if pv, ok := event.(producerVersioner); ok && pv.ProducerVersion() != "" {
+			version := pv.ProducerVersion()
+			msg.ProducerVersion = &version
+		}
+
 		if err := p.publisher.Publish(ctx, msg); err != nil {
 			p.logger.Error("Failed to publish event to NATS",
 				slog.String("event_id", event.EventID().String()),
@@ -135,10 +334,37 @@ func (p *OutboxPoller) poll(ctx context.Context) {
 			)
 			continue
 		}
+		delivered++
+
+		if p.onDelivered != nil {
+			p.onDelivered(string(priorityOf(event)), time.Since(event.OccurredAt()))
+		}
 
 		p.logger.Debug("Event published and marked",
 			slog.String("event_id", event.EventID().String()),
 			slog.String("type", event.EventType()),
 		)
 	}
+
+	return delivered
+}
+
+func isLost(lease ports.Lease) bool {
+	select {
+	case <-lease.Lost():
+		return true
+	default:
+		return false
+	}
+}
+
+// priorityOf returns the lane stored with the outbox row (NORMAL if unknown).
+func priorityOf(event events.DomainEvent) ports.OutboxPriority {
+	type prioritized interface {
+		Priority() ports.OutboxPriority
+	}
+	if pe, ok := event.(prioritized); ok && pe.Priority() != "" {
+		return pe.Priority()
+	}
+	return ports.OutboxPriorityNormal
 }
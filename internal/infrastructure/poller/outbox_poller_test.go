@@ -0,0 +1,403 @@
+package poller
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	natsadapter "github.com/Haleralex/wallethub/internal/adapters/nats"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/leaselock"
+)
+
+// ============================================
+// Fakes
+// ============================================
+
+// laneEvent - событие outbox с полосой, как его возвращает postgres.OutboxRepository.
+type laneEvent struct {
+	id          uuid.UUID
+	aggregateID uuid.UUID
+	eventType   string
+	occurredAt  time.Time
+	priority    ports.OutboxPriority
+}
+
+func (e *laneEvent) EventID() uuid.UUID             { return e.id }
+func (e *laneEvent) EventType() string              { return e.eventType }
+func (e *laneEvent) OccurredAt() time.Time          { return e.occurredAt }
+func (e *laneEvent) AggregateID() uuid.UUID         { return e.aggregateID }
+func (e *laneEvent) Priority() ports.OutboxPriority { return e.priority }
+func (e *laneEvent) Payload() []byte                { return []byte("{}") }
+
+type outboxRow struct {
+	event     *laneEvent
+	sequence  int
+	published bool
+}
+
+// fakeOutbox повторяет семантику запросов postgres.OutboxRepository в памяти.
+type fakeOutbox struct {
+	mu   sync.Mutex
+	rows []*outboxRow
+}
+
+func (f *fakeOutbox) enqueue(aggregateID uuid.UUID, priority ports.OutboxPriority) *laneEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	event := &laneEvent{
+		id:          uuid.New(),
+		aggregateID: aggregateID,
+		eventType:   "test." + string(priority),
+		occurredAt:  time.Now(),
+		priority:    priority,
+	}
+	f.rows = append(f.rows, &outboxRow{event: event, sequence: len(f.rows) + 1})
+	return event
+}
+
+func (f *fakeOutbox) Save(_ context.Context, _ events.DomainEvent) error {
+	return errors.New("not implemented")
+}
+
+func (f *fakeOutbox) FindUnpublished(_ context.Context, limit int) ([]events.DomainEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var result []events.DomainEvent
+	for _, row := range f.rows {
+		if len(result) == limit {
+			break
+		}
+		if !row.published {
+			result = append(result, row.event)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeOutbox) FindUnpublishedByPriority(_ context.Context, priority ports.OutboxPriority, limit int) ([]events.DomainEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	heads := make(map[uuid.UUID]int)
+	for _, row := range f.rows {
+		if !row.published && row.event.priority == priority {
+			heads[row.event.aggregateID] = row.sequence
+		}
+	}
+
+	var result []events.DomainEvent
+	for _, row := range f.rows {
+		if len(result) == limit {
+			break
+		}
+		head, ok := heads[row.event.aggregateID]
+		if !row.published && ok && row.sequence <= head {
+			result = append(result, row.event)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeOutbox) CountPending(_ context.Context) (map[ports.OutboxPriority]int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	counts := map[ports.OutboxPriority]int{}
+	for _, lane := range ports.OutboxPriorityLanes {
+		counts[lane] = 0
+	}
+	for _, row := range f.rows {
+		if !row.published {
+			counts[row.event.priority]++
+		}
+	}
+	return counts, nil
+}
+
+func (f *fakeOutbox) MarkPublished(_ context.Context, eventID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, row := range f.rows {
+		if row.event.id.String() == eventID && !row.published {
+			row.published = true
+			return nil
+		}
+	}
+	return errors.New("event not found or already published")
+}
+
+func (f *fakeOutbox) MarkFailed(_ context.Context, _ string, _ string) error {
+	return nil
+}
+
+// fakePublisher запоминает порядок и время публикации.
+type fakePublisher struct {
+	mu          sync.Mutex
+	order       []string
+	publishedAt map[string]time.Time
+}
+
+func newFakePublisher() *fakePublisher {
+	return &fakePublisher{publishedAt: make(map[string]time.Time)}
+}
+
+func (p *fakePublisher) Publish(_ context.Context, msg *natsadapter.EventMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.order = append(p.order, msg.EventID)
+	p.publishedAt[msg.EventID] = time.Now()
+	return nil
+}
+
+func (p *fakePublisher) published(event *laneEvent) (time.Time, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	at, ok := p.publishedAt[event.id.String()]
+	return at, ok
+}
+
+func (p *fakePublisher) snapshot() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]string(nil), p.order...)
+}
+
+func startPoller(t *testing.T, outbox *fakeOutbox, publisher *fakePublisher, cfg Config) {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	p := New(outbox, publisher, logger, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.Start(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// ============================================
+// Tests
+// ============================================
+
+func TestOutboxPoller_HighLaneBypassesBacklog(t *testing.T) {
+	const (
+		backlog    = 20000
+		aggregates = 500
+		bound      = 300 * time.Millisecond
+	)
+
+	outbox := &fakeOutbox{}
+	publisher := newFakePublisher()
+
+	// Агрегат с HIGH событием: его старые LOW и NORMAL события стоят в
+	// середине backlog и должны уйти раньше HIGH
+	hot := uuid.New()
+	bulk := make([]uuid.UUID, aggregates)
+	for i := range bulk {
+		bulk[i] = uuid.New()
+	}
+
+	var hotLow, hotNormal *laneEvent
+	for i := 0; i < backlog; i++ {
+		switch i {
+		case backlog / 2:
+			hotLow = outbox.enqueue(hot, ports.OutboxPriorityLow)
+		case backlog/2 + 1:
+			hotNormal = outbox.enqueue(hot, ports.OutboxPriorityNormal)
+		default:
+			outbox.enqueue(bulk[i%aggregates], ports.OutboxPriorityLow)
+		}
+	}
+
+	// Основной цикл разбирал бы backlog ~4s (20000 / 50 за 10ms)
+	startPoller(t, outbox, publisher, Config{
+		PollInterval:     10 * time.Millisecond,
+		BatchSize:        50,
+		HighPollInterval: 5 * time.Millisecond,
+		HighBatchSize:    10,
+	})
+
+	high := outbox.enqueue(hot, ports.OutboxPriorityHigh)
+
+	require.Eventually(t, func() bool {
+		_, ok := publisher.published(high)
+		return ok
+	}, 2*time.Second, time.Millisecond, "HIGH event was not delivered")
+
+	publishedAt, _ := publisher.published(high)
+	assert.Less(t, publishedAt.Sub(high.OccurredAt()), bound, "HIGH event delivery latency")
+	assert.Less(t, len(publisher.snapshot()), backlog, "HIGH event must not wait for the backlog")
+
+	// Порядок внутри агрегата важнее приоритета
+	lowAt, ok := publisher.published(hotLow)
+	require.True(t, ok, "older LOW event of the aggregate must be delivered first")
+	normalAt, ok := publisher.published(hotNormal)
+	require.True(t, ok, "older NORMAL event of the aggregate must be delivered first")
+	assert.False(t, normalAt.Before(lowAt))
+	assert.False(t, publishedAt.Before(normalAt))
+
+	assertAggregateOrder(t, outbox, publisher.snapshot())
+}
+
+func TestOutboxPoller_ReportsLaneMetrics(t *testing.T) {
+	outbox := &fakeOutbox{}
+	publisher := newFakePublisher()
+
+	var (
+		mu        sync.Mutex
+		depth     = map[string]int{}
+		delivered = map[string]int{}
+	)
+
+	high := outbox.enqueue(uuid.New(), ports.OutboxPriorityHigh)
+	outbox.enqueue(uuid.New(), ports.OutboxPriorityLow)
+	outbox.enqueue(uuid.New(), ports.OutboxPriorityLow)
+
+	startPoller(t, outbox, publisher, Config{
+		PollInterval:     10 * time.Millisecond,
+		BatchSize:        1,
+		HighPollInterval: time.Millisecond,
+		HighBatchSize:    1,
+		OnQueueDepth: func(priority string, n int) {
+			mu.Lock()
+			defer mu.Unlock()
+			depth[priority] = n
+		},
+		OnDelivered: func(priority string, latency time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			delivered[priority]++
+		},
+	})
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return delivered[string(ports.OutboxPriorityLow)] == 2 && depth[string(ports.OutboxPriorityLow)] == 0
+	}, 2*time.Second, time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, delivered[string(ports.OutboxPriorityHigh)])
+	_, ok := publisher.published(high)
+	assert.True(t, ok)
+}
+
+func TestOutboxPoller_LeaderRunsMainLoop(t *testing.T) {
+	outbox := &fakeOutbox{}
+	lock := leaselock.NewMemory()
+	publishers := []*fakePublisher{newFakePublisher(), newFakePublisher()}
+
+	for _, publisher := range publishers {
+		startPoller(t, outbox, publisher, Config{
+			PollInterval:     5 * time.Millisecond,
+			BatchSize:        10,
+			HighPollInterval: 5 * time.Millisecond,
+			Leader:           lock,
+			LeaderTTL:        150 * time.Millisecond,
+		})
+	}
+
+	// leaderOf ждёт доставки событий и возвращает единственного публиковавшего
+	leaderOf := func(pending []*laneEvent) int {
+		t.Helper()
+		leader := -1
+		require.Eventually(t, func() bool {
+			for _, event := range pending {
+				_, first := publishers[0].published(event)
+				_, second := publishers[1].published(event)
+				if !first && !second {
+					return false
+				}
+			}
+			return true
+		}, 2*time.Second, time.Millisecond)
+
+		for i, publisher := range publishers {
+			if len(publisher.snapshot()) > 0 {
+				leader = i
+			}
+		}
+		return leader
+	}
+
+	var batch []*laneEvent
+	for i := 0; i < 20; i++ {
+		batch = append(batch, outbox.enqueue(uuid.New(), ports.OutboxPriorityLow))
+	}
+	leader := leaderOf(batch)
+	follower := 1 - leader
+	assert.Empty(t, publishers[follower].snapshot(), "only the leader polls the main lane")
+
+	// HIGH полосу опрашивают все экземпляры
+	high := outbox.enqueue(uuid.New(), ports.OutboxPriorityHigh)
+	require.Eventually(t, func() bool {
+		_, first := publishers[0].published(high)
+		_, second := publishers[1].published(high)
+		return first || second
+	}, 2*time.Second, time.Millisecond)
+
+	// Лидер потерял lease: основной цикл переходит к одному экземпляру,
+	// который и разбирает дальнейший backlog
+	lock.Break(leaderKey)
+	time.Sleep(100 * time.Millisecond)
+	delivered := [2]int{len(publishers[0].snapshot()), len(publishers[1].snapshot())}
+
+	batch = batch[:0]
+	for i := 0; i < 20; i++ {
+		batch = append(batch, outbox.enqueue(uuid.New(), ports.OutboxPriorityLow))
+	}
+	leaderOf(batch)
+	grown := 0
+	for i, publisher := range publishers {
+		if len(publisher.snapshot()) > delivered[i] {
+			grown++
+		}
+	}
+	assert.Equal(t, 1, grown, "a single new leader must take over the main lane")
+}
+
+// assertAggregateOrder проверяет, что события каждого агрегата опубликованы
+// в порядке записи в outbox.
+func assertAggregateOrder(t *testing.T, outbox *fakeOutbox, order []string) {
+	t.Helper()
+
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+
+	rows := make(map[string]*outboxRow, len(outbox.rows))
+	for _, row := range outbox.rows {
+		rows[row.event.id.String()] = row
+	}
+
+	sequences := make(map[uuid.UUID][]int)
+	for _, id := range order {
+		row := rows[id]
+		sequences[row.event.aggregateID] = append(sequences[row.event.aggregateID], row.sequence)
+	}
+	for aggregateID, seq := range sequences {
+		assert.True(t, sort.IntsAreSorted(seq), "aggregate %s delivered out of order", aggregateID)
+	}
+}
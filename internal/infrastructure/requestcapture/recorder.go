@@ -0,0 +1,259 @@
+// Package requestcapture - асинхронная запись образцов неудачных денежных операций.
+//
+// HTTP middleware передаёт запрос и ответ каждой неудачной (4xx/5xx)
+// мутирующей операции с кошельками и транзакциями, recorder очищает тела
+// от чувствительных данных и пишет их в FailedRequestRepository - поддержка
+// видит, что именно отправил клиент и что ответил API.
+//
+// Гарантии:
+//   - Record никогда не блокирует запрос: очередь ограничена, при
+//     переполнении образец отбрасывается (OnDrop("queue_full"))
+//   - Очистка и запись выполняются в фоновой горутине; ошибка записи
+//     не доходит до запроса: образец отбрасывается (OnDrop("write_failed"))
+//   - В хранилище попадают только очищенные тела (см. redactor)
+//   - Stop дожидается записи уже принятых образцов (drain)
+//
+// Запись best-effort: образцы нужны для разбора обращений, а не для аудита.
+package requestcapture
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// DefaultQueueSize - ёмкость очереди, если Config.QueueSize не задан.
+const DefaultQueueSize = 256
+
+// Причины отбрасывания образца (label метрики).
+const (
+	DropQueueFull   = "queue_full"
+	DropWriteFailed = "write_failed"
+)
+
+const (
+	// writeTimeout ограничивает одну запись в хранилище
+	writeTimeout = 5 * time.Second
+
+	// maintenanceInterval - как часто удаляются образцы старше Retention
+	maintenanceInterval = time.Hour
+)
+
+// Config - настройки recorder'а.
+type Config struct {
+	// QueueSize - ёмкость очереди записи.
+	QueueSize int
+
+	// SensitiveFields - имена полей JSON, значения которых маскируются
+	// (без учёта регистра, на любой вложенности).
+	SensitiveFields []string
+
+	// Retention - сколько хранить образцы; 0 - не удалять.
+	Retention time.Duration
+
+	// OnDrop вызывается для каждого отброшенного образца (метрика). Может быть nil.
+	OnDrop func(reason string)
+}
+
+// Recorder - реализация ports.FailedRequestRecorder.
+type Recorder struct {
+	logger    *slog.Logger
+	repo      ports.FailedRequestRepository
+	redactor  *redactor
+	retention time.Duration
+	onDrop    func(reason string)
+	now       func() time.Time
+
+	queue   chan ports.FailedRequestCapture
+	mu      sync.RWMutex
+	started bool
+	closed  bool
+
+	queueFull   atomic.Uint64
+	writeFailed atomic.Uint64
+	ctx         context.Context
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+// Compile-time check
+var _ ports.FailedRequestRecorder = (*Recorder)(nil)
+
+// New создаёт recorder. Образцы пишутся после Start.
+func New(logger *slog.Logger, repo ports.FailedRequestRepository, cfg Config) *Recorder {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultQueueSize
+	}
+	if cfg.OnDrop == nil {
+		cfg.OnDrop = func(string) {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Recorder{
+		logger:    logger,
+		repo:      repo,
+		redactor:  newRedactor(cfg.SensitiveFields),
+		retention: cfg.Retention,
+		onDrop:    cfg.OnDrop,
+		now:       time.Now,
+		queue:     make(chan ports.FailedRequestCapture, cfg.QueueSize),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// ============================================
+// Record
+// ============================================
+
+// Record ставит образец в очередь без блокировки. После Stop - no-op.
+func (r *Recorder) Record(capture ports.FailedRequestCapture) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return
+	}
+
+	select {
+	case r.queue <- capture:
+	default:
+		r.drop(&r.queueFull, DropQueueFull, capture, nil)
+	}
+}
+
+// Dropped - число образцов, отброшенных по любой причине.
+func (r *Recorder) Dropped() uint64 {
+	return r.queueFull.Load() + r.writeFailed.Load()
+}
+
+// drop учитывает отброшенный образец. Первый drop каждой причины
+// логируем, дальше только метрика - не шумим при недоступной БД.
+func (r *Recorder) drop(counter *atomic.Uint64, reason string, capture ports.FailedRequestCapture, err error) {
+	r.onDrop(reason)
+	if counter.Add(1) != 1 {
+		return
+	}
+
+	attrs := []any{
+		slog.String("reason", reason),
+		slog.String("route", capture.Route),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	r.logger.Warn("Failed request sample dropped", attrs...)
+}
+
+// ============================================
+// Lifecycle
+// ============================================
+
+// Start запускает запись и очистку по retention. Не блокирует; повторный вызов - no-op.
+func (r *Recorder) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started || r.closed {
+		return
+	}
+	r.started = true
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.run()
+	}()
+}
+
+// Stop перестаёт принимать образцы и ждёт записи уже принятых.
+// Если ctx истёк раньше, запись прерывается и возвращается ошибка.
+func (r *Recorder) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		r.cancel()
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		return fmt.Errorf("failed request recorder drain interrupted: %w", ctx.Err())
+	}
+}
+
+// run - единственная горутина recorder'а: очистка, запись и обслуживание.
+func (r *Recorder) run() {
+	ticker := time.NewTicker(maintenanceInterval)
+	defer ticker.Stop()
+	r.purgeExpired()
+
+	for {
+		select {
+		case capture, ok := <-r.queue:
+			if !ok {
+				return
+			}
+			r.handle(capture)
+		case <-ticker.C:
+			r.purgeExpired()
+		}
+	}
+}
+
+// handle очищает тела и пишет образец.
+func (r *Recorder) handle(capture ports.FailedRequestCapture) {
+	ctx, cancel := context.WithTimeout(r.ctx, writeTimeout)
+	defer cancel()
+
+	sample := entities.NewFailedRequest(entities.FailedRequestSample{
+		Method:       capture.Method,
+		Route:        capture.Route,
+		StatusCode:   capture.StatusCode,
+		RequestID:    capture.RequestID,
+		ActorID:      capture.ActorID,
+		RequestBody:  r.redactor.redact(capture.RequestBody),
+		ResponseBody: r.redactor.redact(capture.ResponseBody),
+		Truncated:    capture.Truncated,
+		OccurredAt:   capture.OccurredAt,
+	})
+
+	if err := r.repo.Append(ctx, sample); err != nil {
+		r.drop(&r.writeFailed, DropWriteFailed, capture, err)
+	}
+}
+
+// purgeExpired удаляет образцы старше retention (если она задана).
+func (r *Recorder) purgeExpired() {
+	if r.retention <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.ctx, writeTimeout)
+	defer cancel()
+
+	deleted, err := r.repo.DeleteBefore(ctx, r.now().UTC().Add(-r.retention))
+	if err != nil {
+		r.logger.Error("Failed to purge expired failed request samples", slog.String("error", err.Error()))
+		return
+	}
+	if deleted > 0 {
+		r.logger.Info("Purged expired failed request samples", slog.Int64("deleted", deleted))
+	}
+}
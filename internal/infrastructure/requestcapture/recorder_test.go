@@ -0,0 +1,186 @@
+package requestcapture
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// Все тесты пакета проверяются на утечку горутин.
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+var defaultFields = []string{"password", "pin", "cvv", "card_number", "iban"}
+
+func TestRedactor(t *testing.T) {
+	r := newRedactor(defaultFields)
+
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "SensitiveFieldsAtAnyDepth",
+			body:     `{"amount":"10.00","PIN":1234,"payer":{"card_number":"4111111111111111","name":"Ann"},"items":[{"cvv":"123"}]}`,
+			expected: `{"amount":"10.00","PIN":"[REDACTED]","payer":{"card_number":"[REDACTED]","name":"Ann"},"items":[{"cvv":"[REDACTED]"}]}`,
+		},
+		{
+			name:     "ObjectValueMaskedWhole",
+			body:     `{"iban":{"country":"DE","number":"89370400440532013000"}}`,
+			expected: `{"iban":"[REDACTED]"}`,
+		},
+		{
+			name:     "PANInFreeTextField",
+			body:     `{"description":"card 4111 1111 1111 1111 declined"}`,
+			expected: `{"description":"card 411111******1111 declined"}`,
+		},
+		{
+			name:     "NumbersKeepPrecision",
+			body:     `{"amount":100.10,"note":"<b>"}`,
+			expected: `{"amount":100.10,"note":"<b>"}`,
+		},
+		{
+			name:     "TruncatedJSONFallsBackToPattern",
+			body:     `{"amount":"10.00","password":"hunt\"er2","pin":12`,
+			expected: `{"amount":"10.00","password":"[REDACTED]","pin":"[REDACTED]"`,
+		},
+		{
+			name:     "UnterminatedValueAtCut",
+			body:     `{"password":"hunt`,
+			expected: `{"password":"[REDACTED]"`,
+		},
+		{
+			name:     "PlainText",
+			body:     "iban DE89370400440532013000 rejected",
+			expected: "iban DE89**************3000 rejected",
+		},
+		{
+			name:     "Empty",
+			body:     "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, r.redact([]byte(tt.body)))
+		})
+	}
+}
+
+type testRecorder struct {
+	*Recorder
+	store *memory.Store
+	drops map[string]int
+}
+
+func newTestRecorder(repo ports.FailedRequestRepository, cfg Config) *testRecorder {
+	store := memory.NewStore()
+	if repo == nil {
+		repo = memory.NewFailedRequestRepository(store)
+	}
+	drops := make(map[string]int)
+	cfg.OnDrop = func(reason string) { drops[reason]++ }
+	if cfg.SensitiveFields == nil {
+		cfg.SensitiveFields = defaultFields
+	}
+
+	return &testRecorder{
+		Recorder: New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, cfg),
+		store:    store,
+		drops:    drops,
+	}
+}
+
+func stop(t *testing.T, r *Recorder) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, r.Stop(ctx))
+}
+
+func debitCapture(body string) ports.FailedRequestCapture {
+	return ports.FailedRequestCapture{
+		Method:       "POST",
+		Route:        "/api/v1/wallets/{id}/debit",
+		StatusCode:   422,
+		RequestID:    "req-1",
+		ActorID:      uuid.New(),
+		RequestBody:  []byte(body),
+		ResponseBody: []byte(`{"success":false,"error":{"code":"INSUFFICIENT_FUNDS"}}`),
+		OccurredAt:   time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestRecorder_StoresRedactedSample(t *testing.T) {
+	r := newTestRecorder(nil, Config{})
+	r.Start()
+
+	capture := debitCapture(`{"amount":"10.00","pin":"0000"}`)
+	r.Record(capture)
+	stop(t, r.Recorder)
+
+	stored, err := memory.NewFailedRequestRepository(r.store).List(context.Background(), ports.FailedRequestFilter{}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, `{"amount":"10.00","pin":"[REDACTED]"}`, stored[0].RequestBody())
+	assert.Equal(t, string(capture.ResponseBody), stored[0].ResponseBody())
+	assert.Equal(t, capture.ActorID, stored[0].ActorID())
+	assert.Equal(t, capture.Route, stored[0].Route())
+	assert.Equal(t, 422, stored[0].StatusCode())
+	assert.True(t, capture.OccurredAt.Equal(stored[0].OccurredAt()))
+	assert.NotContains(t, stored[0].RequestBody(), "0000")
+}
+
+func TestRecorder_QueueOverflowDropsWithoutBlocking(t *testing.T) {
+	r := newTestRecorder(nil, Config{QueueSize: 1})
+
+	// Воркер не запущен: очередь заполняется, лишние образцы отбрасываются сразу
+	for range 3 {
+		r.Record(debitCapture(`{}`))
+	}
+	assert.Equal(t, uint64(2), r.Dropped())
+	assert.Equal(t, 2, r.drops[DropQueueFull])
+
+	r.Start()
+	stop(t, r.Recorder)
+
+	stored, err := memory.NewFailedRequestRepository(r.store).List(context.Background(), ports.FailedRequestFilter{}, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, stored, 1)
+}
+
+// failingRepository отказывает в записи.
+type failingRepository struct {
+	ports.FailedRequestRepository
+}
+
+func (failingRepository) Append(context.Context, *entities.FailedRequest) error {
+	return errors.New("database unavailable")
+}
+
+func TestRecorder_WriteFailureIsDropped(t *testing.T) {
+	r := newTestRecorder(failingRepository{}, Config{})
+	r.Start()
+
+	r.Record(debitCapture(strings.Repeat("x", 10)))
+	stop(t, r.Recorder)
+
+	assert.Equal(t, 1, r.drops[DropWriteFailed])
+	assert.Equal(t, uint64(1), r.Dropped())
+}
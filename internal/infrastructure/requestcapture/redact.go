@@ -0,0 +1,157 @@
+package requestcapture
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Haleralex/wallethub/internal/domain/valueobjects"
+)
+
+// RedactedValue заменяет значение чувствительного поля.
+const RedactedValue = "[REDACTED]"
+
+// redactor маскирует чувствительные данные в теле запроса или ответа.
+//
+//   - Значение поля JSON с именем из списка (без учёта регистра, на любой
+//     вложенности) заменяется на RedactedValue целиком, даже если это объект
+//   - Номера карт и IBAN маскируются в любом месте тела
+//     (valueobjects.RedactSensitiveData)
+//
+// Обрезанное по лимиту или не-JSON тело не разбирается: поля маскируются
+// по шаблону "field": value, включая значение, оборванное на границе.
+type redactor struct {
+	fields  map[string]struct{}
+	pattern *regexp.Regexp // nil - список полей пуст
+}
+
+func newRedactor(fields []string) *redactor {
+	r := &redactor{fields: make(map[string]struct{}, len(fields))}
+	quoted := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		if _, ok := r.fields[field]; ok {
+			continue
+		}
+		r.fields[field] = struct{}{}
+		quoted = append(quoted, regexp.QuoteMeta(field))
+	}
+	if len(quoted) > 0 {
+		// "field": "строка с \" экранированием" | число/литерал; незакрытая
+		// строка в конце обрезанного тела маскируется до конца
+		r.pattern = regexp.MustCompile(`(?i)("(?:` + strings.Join(quoted, "|") + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^\s,}\]]+)`)
+	}
+	return r
+}
+
+// redact возвращает тело с замаскированными чувствительными данными.
+func (r *redactor) redact(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	text, ok := r.redactJSON(body)
+	if !ok {
+		text = string(body)
+		if r.pattern != nil {
+			text = r.pattern.ReplaceAllString(text, `${1}"`+RedactedValue+`"`)
+		}
+	}
+
+	text, _ = valueobjects.RedactSensitiveData(text)
+	return text
+}
+
+// redactJSON переписывает тело как JSON, сохраняя порядок ключей, и
+// маскирует поля по имени. false - тело не JSON (или обрезано).
+func (r *redactor) redactJSON(body []byte) (string, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var out bytes.Buffer
+	if err := r.rewrite(decoder, &out); err != nil {
+		return "", false
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return "", false
+	}
+	return out.String(), true
+}
+
+// rewrite копирует одно JSON значение из decoder в out.
+func (r *redactor) rewrite(decoder *json.Decoder, out *bytes.Buffer) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '[' {
+			out.WriteByte('[')
+			for i := 0; decoder.More(); i++ {
+				if i > 0 {
+					out.WriteByte(',')
+				}
+				if err := r.rewrite(decoder, out); err != nil {
+					return err
+				}
+			}
+			out.WriteByte(']')
+			_, err := decoder.Token()
+			return err
+		}
+
+		out.WriteByte('{')
+		for i := 0; decoder.More(); i++ {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := keyToken.(string)
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			writeJSONString(out, key)
+			out.WriteByte(':')
+
+			if _, sensitive := r.fields[strings.ToLower(key)]; sensitive {
+				var skipped json.RawMessage
+				if err := decoder.Decode(&skipped); err != nil {
+					return err
+				}
+				writeJSONString(out, RedactedValue)
+				continue
+			}
+			if err := r.rewrite(decoder, out); err != nil {
+				return err
+			}
+		}
+		out.WriteByte('}')
+		_, err := decoder.Token()
+		return err
+	case string:
+		writeJSONString(out, t)
+	case json.Number:
+		out.WriteString(t.String())
+	case bool:
+		out.WriteString(strconv.FormatBool(t))
+	case nil:
+		out.WriteString("null")
+	}
+	return nil
+}
+
+// writeJSONString пишет строку в JSON без HTML-экранирования.
+func writeJSONString(out *bytes.Buffer, s string) {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	out.Truncate(out.Len() - 1) // Encode добавляет перевод строки
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	// Воскресенье, 1 марта 2026
+	from := time.Date(2026, 3, 1, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want []time.Time
+	}{
+		{"*/15 * * * *", []time.Time{
+			time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC),
+			time.Date(2026, 3, 1, 10, 45, 0, 0, time.UTC),
+		}},
+		{"0 3 * * *", []time.Time{
+			time.Date(2026, 3, 2, 3, 0, 0, 0, time.UTC),
+			time.Date(2026, 3, 3, 3, 0, 0, 0, time.UTC),
+		}},
+		{"30 9 * * 1-5", []time.Time{
+			time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC),
+			time.Date(2026, 3, 3, 9, 30, 0, 0, time.UTC),
+		}},
+		{"0 0 1,15 * *", []time.Time{
+			time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		{"0 12 * * 7", []time.Time{
+			time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+			time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC),
+		}},
+		// День месяца ИЛИ день недели, если ограничены оба
+		{"0 0 10 * 3", []time.Time{
+			time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+		}},
+		{"0 0 29 2 *", []time.Time{
+			time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		}},
+		{"@hourly", []time.Time{
+			time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC),
+		}},
+		{"@every 90s", []time.Time{
+			time.Date(2026, 3, 1, 10, 19, 0, 0, time.UTC),
+			time.Date(2026, 3, 1, 10, 20, 30, 0, time.UTC),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			require.NoError(t, err)
+
+			next := from
+			for _, want := range tt.want {
+				next = schedule.Next(next)
+				assert.Equal(t, want, next)
+			}
+		})
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"0 0 30 2 *",
+		"@every 10ms",
+		"@every soon",
+		"@sometimes",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			_, err := ParseSchedule(spec)
+			assert.Error(t, err)
+		})
+	}
+}
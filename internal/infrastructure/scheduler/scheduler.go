@@ -0,0 +1,525 @@
+// Package scheduler - периодические фоновые задачи по cron расписанию.
+//
+// Задача регистрируется с именем, расписанием (переопределяется
+// конфигурацией), таймаутом и обработчиком. Перед каждым прогоном
+// планировщик берёт блокировку задачи - lease в job_locks
+// (ports.JobRepository) или, если задан Config.Lock, ports.LeaseLock
+// (advisory lock Postgres, Redis), - поэтому при нескольких экземплярах
+// приложения одну задачу в каждый момент выполняет не больше одного из
+// них. Прогоны пишутся в job_runs: время, статус, ошибка, число
+// обработанных элементов.
+//
+// Lease из Config.Lock продлевается, пока задача работает. Если он потерян,
+// ctx обработчика отменяется, а ports.CheckLease(ctx) возвращает
+// ports.ErrLeaseLost: шаги задачи проверяют его перед коммитом, чтобы
+// экземпляр, уже потерявший задачу, не записал результат поверх нового
+// владельца.
+//
+// Пропущенные запуски (приложение было остановлено) по умолчанию не
+// догоняются: следующий запуск считается от момента старта. Job.CatchUp
+// включает один догоняющий прогон при старте.
+package scheduler
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+)
+
+// Compile-time check
+var _ ports.JobScheduler = (*Scheduler)(nil)
+
+// Значения по умолчанию для незаданных полей Config и Job.
+const (
+	DefaultTimeout     = 5 * time.Minute
+	DefaultHistorySize = 10
+	DefaultLeaseTTL    = 30 * time.Second
+)
+
+const (
+	// leaseMargin добавляется к таймауту задачи: lease не истекает,
+	// пока обработчик ещё может работать
+	leaseMargin = time.Minute
+
+	// storeTimeout ограничивает запись блокировок и журнала прогонов
+	storeTimeout = 5 * time.Second
+)
+
+// Handler выполняет один прогон задачи и возвращает число обработанных элементов.
+// ctx отменяется по таймауту задачи и при остановке планировщика.
+type Handler func(ctx context.Context) (itemsProcessed int, err error)
+
+// Job - описание фоновой задачи.
+type Job struct {
+	Name     string
+	Schedule string        // cron выражение, см. ParseSchedule
+	Timeout  time.Duration // 0 - DefaultTimeout
+	Handler  Handler
+
+	// CatchUp - если по расписанию был пропущен хотя бы один запуск,
+	// выполнить при старте один прогон (без поочерёдного догона каждого)
+	CatchUp bool
+}
+
+// Config - настройки планировщика.
+type Config struct {
+	// Instance идентифицирует экземпляр в job_runs; пусто - hostname-pid
+	Instance string
+
+	// Schedules переопределяет расписания задач по имени
+	Schedules map[string]string
+
+	// HistorySize - сколько последних прогонов задачи отдаёт Jobs
+	HistorySize int
+
+	// Heartbeat возвращает heartbeat задачи; period - интервал между
+	// ближайшими запусками по расписанию. nil - heartbeat не сообщаются.
+	Heartbeat func(job string, period time.Duration) ports.WorkerHeartbeat
+
+	// Lock заменяет блокировки job_locks; nil - блокировки в repo.
+	// Ключ lease - "job:<имя задачи>".
+	Lock ports.LeaseLock
+
+	// LeaseTTL - ttl lease из Lock: через сколько задачу упавшего
+	// экземпляра сможет взять другой. 0 - DefaultLeaseTTL.
+	LeaseTTL time.Duration
+}
+
+// Scheduler запускает зарегистрированные задачи по расписанию.
+type Scheduler struct {
+	logger      *slog.Logger
+	repo        ports.JobRepository
+	instance    string
+	overrides   map[string]string
+	historySize int
+	heartbeat   func(job string, period time.Duration) ports.WorkerHeartbeat
+	locker      ports.LeaseLock
+	leaseTTL    time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	jobs    []*registeredJob
+	byName  map[string]*registeredJob
+	started bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// registeredJob - задача с разобранным расписанием.
+type registeredJob struct {
+	Job
+	spec      string
+	schedule  Schedule
+	heartbeat ports.WorkerHeartbeat
+	nextRun   time.Time // guarded by Scheduler.mu; нулевое - не запланирована
+}
+
+// New создаёт планировщик. Задачи регистрируются до Start.
+func New(logger *slog.Logger, repo ports.JobRepository, cfg Config) *Scheduler {
+	if cfg.Instance == "" {
+		host, _ := os.Hostname()
+		cfg.Instance = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if cfg.HistorySize <= 0 {
+		cfg.HistorySize = DefaultHistorySize
+	}
+	if cfg.LeaseTTL <= 0 {
+		cfg.LeaseTTL = DefaultLeaseTTL
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		logger:      logger,
+		repo:        repo,
+		instance:    cfg.Instance,
+		overrides:   cfg.Schedules,
+		historySize: cfg.HistorySize,
+		heartbeat:   cfg.Heartbeat,
+		locker:      cfg.Lock,
+		leaseTTL:    cfg.LeaseTTL,
+		now:         func() time.Time { return time.Now().UTC() },
+		byName:      make(map[string]*registeredJob),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Register добавляет задачу. Расписание из Config.Schedules имеет приоритет
+// над Job.Schedule; невалидное расписание - ошибка.
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" {
+		return fmt.Errorf("job name is required")
+	}
+	if job.Handler == nil {
+		return fmt.Errorf("job %s: handler is required", job.Name)
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = DefaultTimeout
+	}
+
+	spec := job.Schedule
+	if override, ok := s.overrides[job.Name]; ok && override != "" {
+		spec = override
+	}
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", job.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("job %s: scheduler already started", job.Name)
+	}
+	if _, exists := s.byName[job.Name]; exists {
+		return fmt.Errorf("job %s: already registered", job.Name)
+	}
+
+	registered := &registeredJob{Job: job, spec: spec, schedule: schedule, heartbeat: ports.NoopWorkerHeartbeat{}}
+	if s.heartbeat != nil {
+		registered.heartbeat = s.heartbeat(job.Name, schedulePeriod(schedule, s.now()))
+	}
+	s.jobs = append(s.jobs, registered)
+	s.byName[job.Name] = registered
+	return nil
+}
+
+// Start запускает расписания всех задач. Не блокирует; повторный вызов - no-op.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started || s.ctx.Err() != nil {
+		return
+	}
+	s.started = true
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.loop(job)
+		}()
+	}
+
+	s.logger.Info("Job scheduler started",
+		slog.String("instance", s.instance),
+		slog.Int("jobs", len(s.jobs)),
+	)
+}
+
+// Stop прерывает текущие прогоны и ждёт их завершения.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.cancel()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("job scheduler stop interrupted: %w", ctx.Err())
+	}
+}
+
+// Jobs возвращает задачи в порядке регистрации с последними прогонами.
+func (s *Scheduler) Jobs(ctx context.Context) ([]ports.JobInfo, error) {
+	s.mu.Lock()
+	infos := make([]ports.JobInfo, len(s.jobs))
+	for i, job := range s.jobs {
+		infos[i] = ports.JobInfo{
+			Name:     job.Name,
+			Schedule: job.spec,
+			Timeout:  job.Timeout,
+		}
+		if !job.nextRun.IsZero() {
+			next := job.nextRun
+			infos[i].NextRunAt = &next
+		}
+	}
+	s.mu.Unlock()
+
+	for i := range infos {
+		runs, err := s.repo.ListRuns(ctx, infos[i].Name, s.historySize)
+		if err != nil {
+			return nil, err
+		}
+		infos[i].RecentRuns = runs
+	}
+
+	return infos, nil
+}
+
+// Trigger запускает задачу вне расписания. Блокировка берётся синхронно,
+// обработчик выполняется в фоне.
+func (s *Scheduler) Trigger(ctx context.Context, name string) (*ports.JobRun, error) {
+	s.mu.Lock()
+	job, ok := s.byName[name]
+	if !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%w: job %s", errors.ErrEntityNotFound, name)
+	}
+	if s.ctx.Err() != nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("job scheduler stopped")
+	}
+	// Под mu: Stop не начнёт ждать, пока прогон не учтён в wg
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	run, lease, err := s.begin(ctx, job, ports.JobTriggerManual)
+	if err != nil {
+		s.wg.Done()
+		return nil, err
+	}
+	started := *run
+
+	go func() {
+		defer s.wg.Done()
+		s.finish(job, run, lease)
+	}()
+
+	return &started, nil
+}
+
+// loop ждёт очередной запуск по расписанию до остановки планировщика.
+func (s *Scheduler) loop(job *registeredJob) {
+	if job.CatchUp && s.missedRun(job) {
+		s.runScheduled(job)
+	}
+
+	for {
+		next := job.schedule.Next(s.now())
+		if next.IsZero() {
+			s.logger.Warn("Job schedule never fires again", slog.String("job", job.Name))
+			return
+		}
+		s.setNextRun(job, next)
+
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			s.setNextRun(job, time.Time{})
+			job.heartbeat.Stopped()
+			return
+		case <-timer.C:
+		}
+
+		s.runScheduled(job)
+	}
+}
+
+// missedRun - был ли пропущен запуск с момента последнего прогона.
+func (s *Scheduler) missedRun(job *registeredJob) bool {
+	ctx, cancel := context.WithTimeout(s.ctx, storeTimeout)
+	defer cancel()
+
+	runs, err := s.repo.ListRuns(ctx, job.Name, 1)
+	if err != nil {
+		s.logger.Error("Failed to load last job run", slog.String("job", job.Name), slog.String("error", err.Error()))
+		return false
+	}
+	if len(runs) == 0 {
+		return false
+	}
+
+	due := job.schedule.Next(runs[0].StartedAt)
+	return !due.IsZero() && !due.After(s.now())
+}
+
+// runScheduled выполняет прогон по расписанию, если задачу не выполняет
+// другой экземпляр.
+func (s *Scheduler) runScheduled(job *registeredJob) {
+	ctx, cancel := context.WithTimeout(s.ctx, storeTimeout)
+	run, lease, err := s.begin(ctx, job, ports.JobTriggerSchedule)
+	cancel()
+
+	if errors.IsBusinessRuleViolation(err) {
+		// Цикл этого экземпляра жив, прогон выполняет другой
+		job.heartbeat.Beat()
+		s.logger.Debug("Job is running elsewhere, skipping", slog.String("job", job.Name))
+		return
+	}
+	if err != nil {
+		if s.ctx.Err() == nil {
+			job.heartbeat.RunFinished(0, err)
+			s.logger.Error("Failed to start job", slog.String("job", job.Name), slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	s.finish(job, run, lease)
+}
+
+// begin берёт блокировку задачи и записывает начало прогона. lease - nil,
+// если блокировка в job_locks.
+func (s *Scheduler) begin(ctx context.Context, job *registeredJob, trigger ports.JobTrigger) (*ports.JobRun, ports.Lease, error) {
+	run := &ports.JobRun{
+		ID:        uuid.New(),
+		Job:       job.Name,
+		Trigger:   trigger,
+		Instance:  s.instance,
+		Status:    ports.JobRunRunning,
+		StartedAt: s.now(),
+	}
+
+	lease, err := s.lock(ctx, job, run)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.repo.StartRun(ctx, run); err != nil {
+		s.unlock(job, run, lease)
+		return nil, nil, fmt.Errorf("failed to record job run: %w", err)
+	}
+
+	return run, lease, nil
+}
+
+// lock берёт блокировку задачи для прогона: lease из Config.Lock или
+// строку job_locks. Занятая блокировка - BusinessRuleViolation JOB_RUNNING.
+func (s *Scheduler) lock(ctx context.Context, job *registeredJob, run *ports.JobRun) (ports.Lease, error) {
+	var (
+		lease  ports.Lease
+		locked bool
+		err    error
+	)
+	if s.locker != nil {
+		lease, err = s.locker.TryAcquire(ctx, "job:"+job.Name, s.leaseTTL)
+		locked = lease != nil
+	} else {
+		locked, err = s.repo.TryLock(ctx, job.Name, run.ID, job.Timeout+leaseMargin)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock job %s: %w", job.Name, err)
+	}
+	if !locked {
+		return nil, errors.NewBusinessRuleViolation(
+			"JOB_RUNNING",
+			"job is already running",
+			map[string]interface{}{"job": job.Name},
+		)
+	}
+	return lease, nil
+}
+
+// finish выполняет обработчик, снимает блокировку и записывает итог.
+func (s *Scheduler) finish(job *registeredJob, run *ports.JobRun, lease ports.Lease) {
+	job.heartbeat.RunStarted()
+	items, err := s.handle(job, lease)
+	job.heartbeat.RunFinished(items, err)
+
+	finishedAt := s.now()
+	run.FinishedAt = &finishedAt
+	run.ItemsProcessed = items
+	run.Status = ports.JobRunSucceeded
+	if err != nil {
+		run.Status = ports.JobRunFailed
+		run.Error = err.Error()
+	}
+
+	// Блокировка снимается до записи итога: завершённый прогон означает,
+	// что задачу уже можно запустить снова
+	s.unlock(job, run, lease)
+
+	// Итог пишется и после Stop: иначе прогон навсегда останется RUNNING
+	storeCtx, storeCancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer storeCancel()
+	if err := s.repo.FinishRun(storeCtx, run); err != nil {
+		s.logger.Error("Failed to record job result", slog.String("job", job.Name), slog.String("error", err.Error()))
+	}
+
+	attrs := []any{
+		slog.String("job", job.Name),
+		slog.String("trigger", string(run.Trigger)),
+		slog.Int("items", items),
+		slog.Duration("duration", finishedAt.Sub(run.StartedAt)),
+	}
+	if err != nil {
+		s.logger.Error("Job failed", append(attrs, slog.String("error", err.Error()))...)
+		return
+	}
+	s.logger.Info("Job completed", attrs...)
+}
+
+// handle вызывает обработчик с таймаутом задачи. Потеря lease отменяет
+// ctx обработчика, а ошибка прогона оборачивает ports.ErrLeaseLost.
+func (s *Scheduler) handle(job *registeredJob, lease ports.Lease) (int, error) {
+	timeoutCtx, cancelTimeout := context.WithTimeout(s.ctx, job.Timeout)
+	defer cancelTimeout()
+	ctx, cancel := context.WithCancelCause(timeoutCtx)
+	defer cancel(nil)
+
+	if lease != nil {
+		ctx = ports.ContextWithLease(ctx, lease)
+		go func() {
+			select {
+			case <-lease.Lost():
+				cancel(ports.ErrLeaseLost)
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	items, err := job.Handler(ctx)
+	if err != nil && context.Cause(ctx) == ports.ErrLeaseLost {
+		s.logger.Warn("Job lease lost, run abandoned", slog.String("job", job.Name))
+		if !stderrors.Is(err, ports.ErrLeaseLost) {
+			err = fmt.Errorf("%w: %w", ports.ErrLeaseLost, err)
+		}
+	}
+	return items, err
+}
+
+func (s *Scheduler) unlock(job *registeredJob, run *ports.JobRun, lease ports.Lease) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	var err error
+	if lease != nil {
+		err = lease.Release(ctx)
+	} else {
+		err = s.repo.Unlock(ctx, job.Name, run.ID)
+	}
+	if err != nil {
+		s.logger.Error("Failed to unlock job", slog.String("job", job.Name), slog.String("error", err.Error()))
+	}
+}
+
+// schedulePeriod - интервал между двумя ближайшими запусками после now
+// (0 - расписание больше не сработает).
+func schedulePeriod(schedule Schedule, now time.Time) time.Duration {
+	next := schedule.Next(now)
+	if next.IsZero() {
+		return 0
+	}
+	after := schedule.Next(next)
+	if after.IsZero() {
+		return 0
+	}
+	return after.Sub(next)
+}
+
+func (s *Scheduler) setNextRun(job *registeredJob, next time.Time) {
+	s.mu.Lock()
+	job.nextRun = next
+	s.mu.Unlock()
+}
@@ -0,0 +1,419 @@
+package scheduler
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/infrastructure/leaselock"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// Все тесты пакета проверяются на утечку горутин.
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// newInstance создаёт планировщик "экземпляра" над общим репозиторием.
+func newInstance(t *testing.T, repo ports.JobRepository, name string, jobs ...Job) *Scheduler {
+	t.Helper()
+
+	s := New(discardLogger(), repo, Config{Instance: name})
+	for _, job := range jobs {
+		require.NoError(t, s.Register(job))
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, s.Stop(ctx))
+	})
+	return s
+}
+
+// waitFinished ждёт, пока последний прогон задачи не завершится.
+func waitFinished(t *testing.T, repo ports.JobRepository, job string) *ports.JobRun {
+	t.Helper()
+
+	var last *ports.JobRun
+	require.Eventually(t, func() bool {
+		runs, err := repo.ListRuns(context.Background(), job, 1)
+		if err != nil || len(runs) == 0 || runs[0].Status == ports.JobRunRunning {
+			return false
+		}
+		last = runs[0]
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+	return last
+}
+
+// blockingJob - задача, которая ждёт release и считает одновременные прогоны.
+type blockingJob struct {
+	release  chan struct{}
+	started  chan struct{}
+	calls    atomic.Int32
+	inFlight atomic.Int32
+	maxSeen  atomic.Int32
+}
+
+func newBlockingJob() *blockingJob {
+	return &blockingJob{release: make(chan struct{}), started: make(chan struct{}, 16)}
+}
+
+func (b *blockingJob) handle(ctx context.Context) (int, error) {
+	b.calls.Add(1)
+	current := b.inFlight.Add(1)
+	defer b.inFlight.Add(-1)
+	for {
+		seen := b.maxSeen.Load()
+		if current <= seen || b.maxSeen.CompareAndSwap(seen, current) {
+			break
+		}
+	}
+	b.started <- struct{}{}
+
+	select {
+	case <-b.release:
+		return 3, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func TestScheduler_Trigger_OverlapAcrossInstances(t *testing.T) {
+	repo := memory.NewJobRepository(memory.NewStore())
+	job := newBlockingJob()
+	definition := Job{Name: "sweeper", Schedule: "@daily", Timeout: time.Minute, Handler: job.handle}
+
+	first := newInstance(t, repo, "instance-a", definition)
+	second := newInstance(t, repo, "instance-b", definition)
+	ctx := context.Background()
+
+	run, err := first.Trigger(ctx, "sweeper")
+	require.NoError(t, err)
+	assert.Equal(t, ports.JobRunRunning, run.Status)
+	assert.Equal(t, ports.JobTriggerManual, run.Trigger)
+	assert.Equal(t, "instance-a", run.Instance)
+	<-job.started
+
+	// Пока первый экземпляр выполняет задачу, ни второй, ни он сам её не запускают
+	for _, s := range []*Scheduler{second, first} {
+		_, err := s.Trigger(ctx, "sweeper")
+		var violation *errors.BusinessRuleViolation
+		require.True(t, stderrors.As(err, &violation), "expected JOB_RUNNING, got %v", err)
+		assert.Equal(t, "JOB_RUNNING", violation.Rule)
+	}
+
+	close(job.release)
+	finished := waitFinished(t, repo, "sweeper")
+	assert.Equal(t, run.ID, finished.ID)
+	assert.Equal(t, ports.JobRunSucceeded, finished.Status)
+	assert.Equal(t, 3, finished.ItemsProcessed)
+	assert.NotNil(t, finished.FinishedAt)
+
+	// После завершения задачу может взять любой экземпляр
+	next, err := second.Trigger(ctx, "sweeper")
+	require.NoError(t, err)
+	<-job.started
+	assert.Equal(t, "instance-b", next.Instance)
+	waitFinished(t, repo, "sweeper")
+
+	assert.Equal(t, int32(2), job.calls.Load())
+	assert.Equal(t, int32(1), job.maxSeen.Load())
+}
+
+func TestScheduler_Schedule_OverlapAcrossInstances(t *testing.T) {
+	repo := memory.NewJobRepository(memory.NewStore())
+	job := newBlockingJob()
+	close(job.release)
+
+	// Каждый прогон дольше интервала: без блокировки экземпляры пересеклись бы
+	definition := Job{Name: "accrual", Schedule: "@every 1s", Timeout: time.Minute, Handler: func(ctx context.Context) (int, error) {
+		defer time.Sleep(1500 * time.Millisecond)
+		return job.handle(ctx)
+	}}
+
+	first := newInstance(t, repo, "instance-a", definition)
+	second := newInstance(t, repo, "instance-b", definition)
+	first.Start()
+	second.Start()
+
+	time.Sleep(3500 * time.Millisecond)
+
+	assert.GreaterOrEqual(t, job.calls.Load(), int32(1))
+	assert.Equal(t, int32(1), job.maxSeen.Load(), "job must never run on two instances at once")
+}
+
+func TestScheduler_FailedRunIsRecorded(t *testing.T) {
+	repo := memory.NewJobRepository(memory.NewStore())
+	s := newInstance(t, repo, "instance-a", Job{
+		Name:     "archival",
+		Schedule: "@daily",
+		Handler: func(ctx context.Context) (int, error) {
+			return 2, stderrors.New("archive bucket unavailable")
+		},
+	})
+
+	_, err := s.Trigger(context.Background(), "archival")
+	require.NoError(t, err)
+
+	run := waitFinished(t, repo, "archival")
+	assert.Equal(t, ports.JobRunFailed, run.Status)
+	assert.Equal(t, "archive bucket unavailable", run.Error)
+	assert.Equal(t, 2, run.ItemsProcessed)
+
+	// Блокировка снята и после ошибки
+	_, err = s.Trigger(context.Background(), "archival")
+	require.NoError(t, err)
+	waitFinished(t, repo, "archival")
+}
+
+func TestScheduler_TimeoutCancelsHandler(t *testing.T) {
+	repo := memory.NewJobRepository(memory.NewStore())
+	s := newInstance(t, repo, "instance-a", Job{
+		Name:     "dormancy",
+		Schedule: "@daily",
+		Timeout:  50 * time.Millisecond,
+		Handler: func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		},
+	})
+
+	_, err := s.Trigger(context.Background(), "dormancy")
+	require.NoError(t, err)
+
+	run := waitFinished(t, repo, "dormancy")
+	assert.Equal(t, ports.JobRunFailed, run.Status)
+	assert.Contains(t, run.Error, "deadline exceeded")
+}
+
+// newLeaseInstance создаёт экземпляр, блокирующий задачи через LeaseLock.
+func newLeaseInstance(t *testing.T, repo ports.JobRepository, lock ports.LeaseLock, name string, jobs ...Job) *Scheduler {
+	t.Helper()
+
+	s := New(discardLogger(), repo, Config{Instance: name, Lock: lock, LeaseTTL: 150 * time.Millisecond})
+	for _, job := range jobs {
+		require.NoError(t, s.Register(job))
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, s.Stop(ctx))
+	})
+	return s
+}
+
+func TestScheduler_LeaseLock(t *testing.T) {
+	t.Run("OverlapAcrossInstances", func(t *testing.T) {
+		repo := memory.NewJobRepository(memory.NewStore())
+		lock := leaselock.NewMemory()
+		job := newBlockingJob()
+		definition := Job{Name: "sweeper", Schedule: "@daily", Timeout: time.Minute, Handler: job.handle}
+
+		first := newLeaseInstance(t, repo, lock, "instance-a", definition)
+		second := newLeaseInstance(t, repo, lock, "instance-b", definition)
+		ctx := context.Background()
+
+		_, err := first.Trigger(ctx, "sweeper")
+		require.NoError(t, err)
+		<-job.started
+
+		// Прогон дольше ttl: watchdog продлевает lease
+		time.Sleep(500 * time.Millisecond)
+		_, err = second.Trigger(ctx, "sweeper")
+		var violation *errors.BusinessRuleViolation
+		require.True(t, stderrors.As(err, &violation), "expected JOB_RUNNING, got %v", err)
+
+		close(job.release)
+		assert.Equal(t, ports.JobRunSucceeded, waitFinished(t, repo, "sweeper").Status)
+
+		// Lease отпущен после прогона
+		_, err = second.Trigger(ctx, "sweeper")
+		require.NoError(t, err)
+		waitFinished(t, repo, "sweeper")
+	})
+
+	t.Run("LostLeaseStopsRun", func(t *testing.T) {
+		repo := memory.NewJobRepository(memory.NewStore())
+		lock := leaselock.NewMemory()
+		started := make(chan struct{})
+		var committed atomic.Bool
+
+		s := newLeaseInstance(t, repo, lock, "instance-a", Job{
+			Name:     "settlement",
+			Schedule: "@daily",
+			Handler: func(ctx context.Context) (int, error) {
+				require.NoError(t, ports.CheckLease(ctx))
+				close(started)
+
+				<-ctx.Done()
+				// Шаг проверяет lease перед коммитом
+				if err := ports.CheckLease(ctx); err != nil {
+					return 0, err
+				}
+				committed.Store(true)
+				return 1, nil
+			},
+		})
+
+		_, err := s.Trigger(context.Background(), "settlement")
+		require.NoError(t, err)
+		<-started
+		lock.Break("job:settlement")
+
+		run := waitFinished(t, repo, "settlement")
+		assert.Equal(t, ports.JobRunFailed, run.Status)
+		assert.Contains(t, run.Error, ports.ErrLeaseLost.Error())
+		assert.False(t, committed.Load(), "stale holder must not commit")
+	})
+}
+
+func TestScheduler_MissedRuns(t *testing.T) {
+	tests := []struct {
+		name     string
+		catchUp  bool
+		wantRuns int
+	}{
+		{name: "NotBackfilledByDefault", catchUp: false, wantRuns: 1},
+		{name: "CatchUpRunsOnce", catchUp: true, wantRuns: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewJobRepository(memory.NewStore())
+			ctx := context.Background()
+
+			// Последний прогон - три часа назад: при @hourly пропущено три запуска
+			finishedAt := time.Now().UTC().Add(-3 * time.Hour)
+			last := &ports.JobRun{ID: uuid.New(), Job: "snapshots", Trigger: ports.JobTriggerSchedule,
+				Instance: "old", StartedAt: finishedAt, Status: ports.JobRunRunning}
+			require.NoError(t, repo.StartRun(ctx, last))
+			last.Status = ports.JobRunSucceeded
+			last.FinishedAt = &finishedAt
+			require.NoError(t, repo.FinishRun(ctx, last))
+
+			var calls atomic.Int32
+			s := newInstance(t, repo, "instance-a", Job{
+				Name:     "snapshots",
+				Schedule: "@hourly",
+				CatchUp:  tt.catchUp,
+				Handler: func(ctx context.Context) (int, error) {
+					calls.Add(1)
+					return 0, nil
+				},
+			})
+			s.Start()
+
+			require.Eventually(t, func() bool {
+				jobs, err := s.Jobs(ctx)
+				return err == nil && jobs[0].NextRunAt != nil
+			}, 5*time.Second, 10*time.Millisecond)
+
+			runs, err := repo.ListRuns(ctx, "snapshots", 10)
+			require.NoError(t, err)
+			assert.Len(t, runs, tt.wantRuns)
+			assert.Equal(t, int32(tt.wantRuns-1), calls.Load())
+		})
+	}
+}
+
+func TestScheduler_Jobs(t *testing.T) {
+	repo := memory.NewJobRepository(memory.NewStore())
+	noop := func(ctx context.Context) (int, error) { return 0, nil }
+
+	s := New(discardLogger(), repo, Config{
+		Instance:  "instance-a",
+		Schedules: map[string]string{"outbox-cleanup": "0 4 * * *"},
+	})
+	require.NoError(t, s.Register(Job{Name: "wallet-compare", Schedule: "*/10 * * * *", Handler: noop}))
+	require.NoError(t, s.Register(Job{Name: "outbox-cleanup", Schedule: "@hourly", Timeout: time.Minute, Handler: noop}))
+
+	jobs, err := s.Jobs(context.Background())
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "wallet-compare", jobs[0].Name)
+	assert.Equal(t, DefaultTimeout, jobs[0].Timeout)
+	assert.Nil(t, jobs[0].NextRunAt, "not scheduled before Start")
+	assert.Equal(t, "outbox-cleanup", jobs[1].Name)
+	assert.Equal(t, "0 4 * * *", jobs[1].Schedule, "config override wins")
+
+	_, err = s.Trigger(context.Background(), "unknown")
+	assert.True(t, errors.IsNotFound(err), "expected not found, got %v", err)
+
+	require.NoError(t, s.Stop(context.Background()))
+	_, err = s.Trigger(context.Background(), "wallet-compare")
+	assert.Error(t, err)
+}
+
+func TestScheduler_Register_Invalid(t *testing.T) {
+	noop := func(ctx context.Context) (int, error) { return 0, nil }
+	s := New(discardLogger(), memory.NewJobRepository(memory.NewStore()), Config{
+		Schedules: map[string]string{"overridden": "not a schedule"},
+	})
+
+	assert.Error(t, s.Register(Job{Schedule: "@daily", Handler: noop}))
+	assert.Error(t, s.Register(Job{Name: "no-handler", Schedule: "@daily"}))
+	assert.Error(t, s.Register(Job{Name: "bad", Schedule: "* * *", Handler: noop}))
+	assert.Error(t, s.Register(Job{Name: "overridden", Schedule: "@daily", Handler: noop}))
+
+	require.NoError(t, s.Register(Job{Name: "dup", Schedule: "@daily", Handler: noop}))
+	assert.Error(t, s.Register(Job{Name: "dup", Schedule: "@daily", Handler: noop}))
+}
+
+// recordingHeartbeat считает отчёты задачи.
+type recordingHeartbeat struct {
+	started, finished, beats atomic.Int32
+	items                    atomic.Int32
+}
+
+func (h *recordingHeartbeat) RunStarted() { h.started.Add(1) }
+func (h *recordingHeartbeat) RunFinished(items int, _ error) {
+	h.items.Store(int32(items))
+	h.finished.Add(1)
+}
+func (h *recordingHeartbeat) Beat()    { h.beats.Add(1) }
+func (h *recordingHeartbeat) Stopped() {}
+
+func TestScheduler_Heartbeat(t *testing.T) {
+	repo := memory.NewJobRepository(memory.NewStore())
+	heartbeat := &recordingHeartbeat{}
+	var period time.Duration
+
+	s := New(discardLogger(), repo, Config{
+		Instance: "a",
+		Heartbeat: func(job string, p time.Duration) ports.WorkerHeartbeat {
+			assert.Equal(t, "archival", job)
+			period = p
+			return heartbeat
+		},
+	})
+	require.NoError(t, s.Register(Job{Name: "archival", Schedule: "@every 10m", Handler: func(context.Context) (int, error) {
+		return 5, nil
+	}}))
+	t.Cleanup(func() { require.NoError(t, s.Stop(context.Background())) })
+
+	assert.Equal(t, 10*time.Minute, period)
+
+	_, err := s.Trigger(context.Background(), "archival")
+	require.NoError(t, err)
+	waitFinished(t, repo, "archival")
+
+	require.Eventually(t, func() bool { return heartbeat.finished.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), heartbeat.started.Load())
+	assert.Equal(t, int32(5), heartbeat.items.Load())
+}
@@ -0,0 +1,70 @@
+package securitylog
+
+import (
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// failureWindow - счётчик неудач одного principal в текущем окне.
+type failureWindow struct {
+	start   time.Time
+	count   int
+	alerted bool
+}
+
+// failureDetector считает неудачные попытки по principal в фиксированных
+// (tumbling) окнах: окно открывает первая неудача, следующая неудача после
+// его конца открывает новое. Не потокобезопасен - вызывается из одной горутины.
+type failureDetector struct {
+	threshold int
+	window    time.Duration
+	windows   map[string]*failureWindow
+}
+
+func newFailureDetector(threshold int, window time.Duration) *failureDetector {
+	return &failureDetector{
+		threshold: threshold,
+		window:    window,
+		windows:   make(map[string]*failureWindow),
+	}
+}
+
+// observe учитывает событие и возвращает алерт, если principal только что
+// превысил порог в текущем окне. Успешные входы и события без principal
+// (запрос без учётных данных) не считаются.
+func (d *failureDetector) observe(event *entities.SecurityEvent) *events.SuspiciousAuthActivity {
+	if event.Principal() == "" || !isFailure(event.Type()) {
+		return nil
+	}
+
+	at := event.OccurredAt()
+	w, ok := d.windows[event.Principal()]
+	if !ok || !at.Before(w.start.Add(d.window)) {
+		w = &failureWindow{start: at}
+		d.windows[event.Principal()] = w
+	}
+
+	w.count++
+	if w.count <= d.threshold || w.alerted {
+		return nil
+	}
+
+	w.alerted = true
+	return events.NewSuspiciousAuthActivity(event.Principal(), w.count, w.start, d.window)
+}
+
+// prune забывает окна, закончившиеся до now.
+func (d *failureDetector) prune(now time.Time) {
+	for principal, w := range d.windows {
+		if !now.Before(w.start.Add(d.window)) {
+			delete(d.windows, principal)
+		}
+	}
+}
+
+func isFailure(t entities.SecurityEventType) bool {
+	return t == entities.SecurityEventAuthFailed || t == entities.SecurityEventScopeDenied ||
+		t == entities.SecurityEventPolicyDenied
+}
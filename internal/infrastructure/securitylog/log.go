@@ -0,0 +1,284 @@
+// Package securitylog - асинхронный журнал событий аутентификации.
+//
+// Auth middleware сообщает о каждом исходе проверки (AUTH_FAILED,
+// AUTH_SUCCEEDED, SCOPE_DENIED, POLICY_DENIED), журнал пишет их в SecurityEventRepository
+// и следит за подбором учётных данных.
+//
+// Гарантии:
+//   - Record никогда не блокирует запрос: очередь ограничена, при
+//     переполнении событие отбрасывается (OnDrop("queue_full"))
+//   - Ошибка записи в хранилище не доходит до запроса: событие
+//     отбрасывается (OnDrop("write_failed"))
+//   - Неудачные попытки считаются по principal в фиксированном окне;
+//     SuspiciousAuthActivity публикуется один раз на окно
+//   - Stop дожидается записи уже принятых событий (drain)
+//
+// Журнал best-effort: он нужен для мониторинга, а не для аудита платежей.
+package securitylog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// Значения по умолчанию для незаданных полей Config.
+const (
+	DefaultQueueSize        = 1024
+	DefaultFailureThreshold = 10
+	DefaultFailureWindow    = 5 * time.Minute
+)
+
+// Причины отбрасывания события (label метрики).
+const (
+	DropQueueFull   = "queue_full"
+	DropWriteFailed = "write_failed"
+)
+
+const (
+	// writeTimeout ограничивает одну запись в хранилище
+	writeTimeout = 5 * time.Second
+
+	// maintenanceInterval - как часто удаляются события старше Retention
+	// и забываются закончившиеся окна детектора
+	maintenanceInterval = time.Hour
+)
+
+// Config - настройки журнала.
+type Config struct {
+	// QueueSize - ёмкость очереди записи.
+	QueueSize int
+
+	// FailureThreshold - алерт, когда неудач у principal больше этого числа за FailureWindow.
+	FailureThreshold int
+	FailureWindow    time.Duration
+
+	// Retention - сколько хранить события; 0 - не удалять.
+	Retention time.Duration
+
+	// OnDrop вызывается для каждого отброшенного события (метрика). Может быть nil.
+	OnDrop func(reason string)
+}
+
+// Log - реализация ports.SecurityEventRecorder.
+type Log struct {
+	logger    *slog.Logger
+	repo      ports.SecurityEventRepository
+	publisher ports.EventPublisher
+	detector  *failureDetector
+	retention time.Duration
+	onDrop    func(reason string)
+	now       func() time.Time
+
+	queue   chan *entities.SecurityEvent
+	mu      sync.RWMutex
+	started bool
+	closed  bool
+
+	queueFull   atomic.Uint64
+	writeFailed atomic.Uint64
+	ctx         context.Context
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+// Compile-time check
+var _ ports.SecurityEventRecorder = (*Log)(nil)
+
+// New создаёт журнал. События пишутся после Start.
+//
+// publisher получает SuspiciousAuthActivity (outbox); события пишутся
+// вне UnitOfWork, поэтому publisher должен работать без транзакции в ctx.
+func New(logger *slog.Logger, repo ports.SecurityEventRepository, publisher ports.EventPublisher, cfg Config) *Log {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultQueueSize
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = DefaultFailureThreshold
+	}
+	if cfg.FailureWindow <= 0 {
+		cfg.FailureWindow = DefaultFailureWindow
+	}
+	if cfg.OnDrop == nil {
+		cfg.OnDrop = func(string) {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Log{
+		logger:    logger,
+		repo:      repo,
+		publisher: publisher,
+		detector:  newFailureDetector(cfg.FailureThreshold, cfg.FailureWindow),
+		retention: cfg.Retention,
+		onDrop:    cfg.OnDrop,
+		now:       time.Now,
+		queue:     make(chan *entities.SecurityEvent, cfg.QueueSize),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// ============================================
+// Record
+// ============================================
+
+// Record ставит событие в очередь без блокировки. После Stop - no-op.
+func (l *Log) Record(event *entities.SecurityEvent) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.closed {
+		return
+	}
+
+	select {
+	case l.queue <- event:
+	default:
+		l.drop(&l.queueFull, DropQueueFull, event, nil)
+	}
+}
+
+// Dropped - число событий, отброшенных по любой причине.
+func (l *Log) Dropped() uint64 {
+	return l.queueFull.Load() + l.writeFailed.Load()
+}
+
+// drop учитывает отброшенное событие. Первый drop каждой причины
+// логируем, дальше только метрика - не шумим при недоступной БД.
+func (l *Log) drop(counter *atomic.Uint64, reason string, event *entities.SecurityEvent, err error) {
+	l.onDrop(reason)
+	if counter.Add(1) != 1 {
+		return
+	}
+
+	attrs := []any{
+		slog.String("reason", reason),
+		slog.String("event_type", string(event.Type())),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	l.logger.Warn("Security event dropped", attrs...)
+}
+
+// ============================================
+// Lifecycle
+// ============================================
+
+// Start запускает запись и очистку по retention. Не блокирует; повторный вызов - no-op.
+func (l *Log) Start() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.started || l.closed {
+		return
+	}
+	l.started = true
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		l.run()
+	}()
+}
+
+// Stop перестаёт принимать события и ждёт записи уже принятых.
+// Если ctx истёк раньше, запись прерывается и возвращается ошибка.
+func (l *Log) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.queue)
+	}
+	l.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		l.cancel()
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return fmt.Errorf("security log drain interrupted: %w", ctx.Err())
+	}
+}
+
+// run - единственная горутина журнала: запись, детектор и обслуживание.
+// Детектор используется только отсюда и поэтому без блокировок.
+func (l *Log) run() {
+	ticker := time.NewTicker(maintenanceInterval)
+	defer ticker.Stop()
+	l.purgeExpired()
+
+	for {
+		select {
+		case event, ok := <-l.queue:
+			if !ok {
+				return
+			}
+			l.handle(event)
+		case <-ticker.C:
+			l.purgeExpired()
+			l.detector.prune(l.now())
+		}
+	}
+}
+
+// handle пишет событие и проверяет порог неудачных попыток.
+func (l *Log) handle(event *entities.SecurityEvent) {
+	ctx, cancel := context.WithTimeout(l.ctx, writeTimeout)
+	defer cancel()
+
+	if err := l.repo.Append(ctx, event); err != nil {
+		l.drop(&l.writeFailed, DropWriteFailed, event, err)
+	}
+
+	// Порог считается и для незаписанных событий: подбор при недоступной БД
+	// тоже должен подниматься алертом
+	alert := l.detector.observe(event)
+	if alert == nil {
+		return
+	}
+
+	l.logger.Warn("Suspicious authentication activity",
+		slog.String("principal", alert.Principal),
+		slog.Int("failures", alert.Failures),
+		slog.Duration("window", alert.Window),
+	)
+	if err := l.publisher.Publish(ctx, alert); err != nil {
+		l.logger.Error("Failed to publish suspicious auth activity",
+			slog.String("principal", alert.Principal),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// purgeExpired удаляет события старше retention (если она задана).
+func (l *Log) purgeExpired() {
+	if l.retention <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(l.ctx, writeTimeout)
+	defer cancel()
+
+	deleted, err := l.repo.DeleteBefore(ctx, l.now().UTC().Add(-l.retention))
+	if err != nil {
+		l.logger.Error("Failed to purge expired security events", slog.String("error", err.Error()))
+		return
+	}
+	if deleted > 0 {
+		l.logger.Info("Purged expired security events", slog.Int64("deleted", deleted))
+	}
+}
@@ -0,0 +1,272 @@
+package securitylog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// Все тесты пакета проверяются на утечку горутин.
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+var windowStart = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func failedAt(principal string, offset time.Duration) *entities.SecurityEvent {
+	return entities.ReconstructSecurityEvent(uuid.New(), principal, entities.SecurityEventAuthFailed, "10.0.0.1", "test", windowStart.Add(offset))
+}
+
+// dropCounter собирает причины из OnDrop.
+type dropCounter struct {
+	mu      sync.Mutex
+	reasons map[string]int
+}
+
+func (d *dropCounter) onDrop(reason string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.reasons == nil {
+		d.reasons = make(map[string]int)
+	}
+	d.reasons[reason]++
+}
+
+func (d *dropCounter) count(reason string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.reasons[reason]
+}
+
+// failingRepository отказывает в записи.
+type failingRepository struct {
+	ports.SecurityEventRepository
+}
+
+func (failingRepository) Append(context.Context, *entities.SecurityEvent) error {
+	return errors.New("database unavailable")
+}
+
+type testLog struct {
+	*Log
+	store     *memory.Store
+	publisher *memory.EventPublisher
+	drops     *dropCounter
+}
+
+func newTestLog(repo ports.SecurityEventRepository, cfg Config) *testLog {
+	store := memory.NewStore()
+	if repo == nil {
+		repo = memory.NewSecurityEventRepository(store)
+	}
+	publisher := memory.NewEventPublisher(store)
+	drops := &dropCounter{}
+	cfg.OnDrop = drops.onDrop
+
+	return &testLog{
+		Log:       New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, publisher, cfg),
+		store:     store,
+		publisher: publisher,
+		drops:     drops,
+	}
+}
+
+func stop(t *testing.T, l *Log) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, l.Stop(ctx))
+}
+
+func suspiciousEvents(publisher *memory.EventPublisher) []*events.SuspiciousAuthActivity {
+	var result []*events.SuspiciousAuthActivity
+	for _, e := range publisher.Events() {
+		if s, ok := e.(*events.SuspiciousAuthActivity); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// ============================================
+// Threshold
+// ============================================
+
+func TestFailureDetector_FiresOncePerWindow(t *testing.T) {
+	d := newFailureDetector(3, 5*time.Minute)
+	principal := uuid.NewString()
+
+	var alerts []*events.SuspiciousAuthActivity
+	observe := func(offset time.Duration) {
+		if alert := d.observe(failedAt(principal, offset)); alert != nil {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	// Порог 3: четвёртая неудача поднимает алерт, дальнейшие в том же окне - нет
+	for i := range 10 {
+		observe(time.Duration(i) * time.Second)
+	}
+	require.Len(t, alerts, 1)
+	assert.Equal(t, 4, alerts[0].Failures)
+	assert.True(t, windowStart.Equal(alerts[0].WindowStart))
+
+	// Новое окно - новый счёт и ещё один алерт
+	for i := range 4 {
+		observe(5*time.Minute + time.Duration(i)*time.Second)
+	}
+	require.Len(t, alerts, 2)
+	assert.True(t, windowStart.Add(5*time.Minute).Equal(alerts[1].WindowStart))
+}
+
+func TestFailureDetector_IgnoresSuccessAndAnonymous(t *testing.T) {
+	d := newFailureDetector(1, time.Minute)
+	principal := uuid.NewString()
+
+	succeeded := entities.ReconstructSecurityEvent(uuid.New(), principal, entities.SecurityEventAuthSucceeded, "", "", windowStart)
+	for range 5 {
+		assert.Nil(t, d.observe(succeeded))
+		assert.Nil(t, d.observe(failedAt("", 0)))
+	}
+
+	// SCOPE_DENIED считается неудачей наравне с AUTH_FAILED
+	denied := entities.ReconstructSecurityEvent(uuid.New(), principal, entities.SecurityEventScopeDenied, "", "", windowStart)
+	assert.Nil(t, d.observe(denied))
+	assert.NotNil(t, d.observe(failedAt(principal, time.Second)))
+}
+
+func TestFailureDetector_CountsPrincipalsSeparately(t *testing.T) {
+	d := newFailureDetector(2, time.Minute)
+	first, second := uuid.NewString(), "key:abcd1234"
+
+	for i := range 2 {
+		assert.Nil(t, d.observe(failedAt(first, time.Duration(i)*time.Second)))
+		assert.Nil(t, d.observe(failedAt(second, time.Duration(i)*time.Second)))
+	}
+
+	assert.NotNil(t, d.observe(failedAt(second, 3*time.Second)))
+	assert.Nil(t, d.observe(failedAt(first, 2*time.Minute)), "expired window starts over")
+}
+
+func TestFailureDetector_Prune(t *testing.T) {
+	d := newFailureDetector(2, time.Minute)
+	d.observe(failedAt("stale", 0))
+	d.observe(failedAt("active", 50*time.Second))
+
+	d.prune(windowStart.Add(time.Minute))
+
+	assert.NotContains(t, d.windows, "stale")
+	assert.Contains(t, d.windows, "active")
+}
+
+func TestLog_PublishesSuspiciousActivityOnce(t *testing.T) {
+	l := newTestLog(nil, Config{FailureThreshold: 3, FailureWindow: time.Minute})
+	l.Start()
+
+	principal := uuid.NewString()
+	for i := range 8 {
+		l.Record(failedAt(principal, time.Duration(i)*time.Second))
+	}
+	stop(t, l.Log)
+
+	alerts := suspiciousEvents(l.publisher)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, principal, alerts[0].Principal)
+	assert.Equal(t, principal, alerts[0].AggregateID().String())
+
+	stored, err := memory.NewSecurityEventRepository(l.store).List(context.Background(), ports.SecurityEventFilter{}, 0, 100)
+	require.NoError(t, err)
+	assert.Len(t, stored, 8)
+}
+
+// ============================================
+// Drops
+// ============================================
+
+func TestLog_QueueOverflowDropsWithoutBlocking(t *testing.T) {
+	l := newTestLog(nil, Config{QueueSize: 2})
+
+	// Воркер не запущен: очередь заполняется, лишние события отбрасываются сразу
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 5 {
+			l.Record(failedAt("", time.Duration(i)*time.Second))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Record blocked on a full queue")
+	}
+
+	assert.Equal(t, uint64(3), l.Dropped())
+	assert.Equal(t, 3, l.drops.count(DropQueueFull))
+
+	// Принятые события записываются при drain
+	l.Start()
+	stop(t, l.Log)
+
+	stored, err := memory.NewSecurityEventRepository(l.store).List(context.Background(), ports.SecurityEventFilter{}, 0, 100)
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+}
+
+func TestLog_WriteFailureIsDropped(t *testing.T) {
+	l := newTestLog(failingRepository{}, Config{FailureThreshold: 1})
+	l.Start()
+
+	principal := uuid.NewString()
+	l.Record(failedAt(principal, 0))
+	l.Record(failedAt(principal, time.Second))
+	stop(t, l.Log)
+
+	assert.Equal(t, 2, l.drops.count(DropWriteFailed))
+	assert.Equal(t, uint64(2), l.Dropped())
+	assert.Len(t, suspiciousEvents(l.publisher), 1, "threshold still counts unwritten failures")
+}
+
+func TestLog_RecordAfterStopIsNoop(t *testing.T) {
+	l := newTestLog(nil, Config{})
+	l.Start()
+	stop(t, l.Log)
+
+	l.Record(failedAt("", 0))
+	assert.Zero(t, l.Dropped())
+}
+
+// ============================================
+// Retention
+// ============================================
+
+func TestLog_PurgesExpiredOnStart(t *testing.T) {
+	l := newTestLog(nil, Config{Retention: time.Hour})
+	l.now = func() time.Time { return windowStart.Add(2 * time.Hour) }
+
+	repo := memory.NewSecurityEventRepository(l.store)
+	ctx := context.Background()
+	require.NoError(t, repo.Append(ctx, failedAt("", 0)))
+	require.NoError(t, repo.Append(ctx, failedAt("", 90*time.Minute)))
+
+	l.Start()
+	stop(t, l.Log)
+
+	stored, err := repo.List(ctx, ports.SecurityEventFilter{}, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.True(t, windowStart.Add(90*time.Minute).Equal(stored[0].OccurredAt()))
+}
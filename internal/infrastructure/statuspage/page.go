@@ -0,0 +1,286 @@
+// Package statuspage - данные публичной страницы статуса (GET /status).
+//
+// Page раз в Interval вычисляет грубые статусы компонентов (api, database,
+// payouts, deposits) и список инцидентов и держит результат в памяти.
+// Запрос страницы читает только память, поэтому переживает недоступность
+// БД: если хранилище не читается, отдаются последние известные выключатели
+// и инциденты с признаком Stale.
+//
+// Источники статусов:
+//   - database: ping БД (ошибка - down)
+//   - payouts / deposits: выключатели операций (entities.OperationSwitchesFor
+//     выплаты и депозита; выключен - degraded), БД недоступна - down
+//   - api: устаревшие heartbeat фоновых компонентов или недоступная БД -
+//     degraded (сам ответ означает, что API работает)
+package statuspage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+)
+
+// Compile-time check
+var _ ports.StatusPage = (*Page)(nil)
+
+// DefaultInterval - как часто обновляется состояние по умолчанию.
+const DefaultInterval = 5 * time.Second
+
+// refreshTimeout ограничивает одно обновление: ping и чтение хранилища
+const refreshTimeout = 3 * time.Second
+
+// Config - источники и настройки страницы статуса.
+type Config struct {
+	// Ping проверяет доступность БД; nil - database всегда operational
+	Ping func(ctx context.Context) error
+
+	// Switches - выключатели операций; nil - операции всегда включены
+	Switches ports.OperationSwitchRepository
+
+	// Incidents - отметки инцидентов; nil - инцидентов нет
+	Incidents ports.IncidentRepository
+
+	// Workers - heartbeat фоновых компонентов этого экземпляра; nil - не проверяются
+	Workers ports.WorkerMonitor
+
+	// Interval - как часто обновляется состояние
+	Interval time.Duration
+}
+
+// Page реализует ports.StatusPage: состояние в памяти, обновляемое в фоне.
+type Page struct {
+	logger   *slog.Logger
+	cfg      Config
+	interval time.Duration
+	now      func() time.Time
+
+	mu        sync.RWMutex
+	snapshot  ports.StatusSnapshot
+	switches  []*entities.OperationSwitch // последние прочитанные
+	incidents []*entities.Incident        // последние прочитанные
+
+	lifecycle sync.Mutex
+	started   bool
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// New создаёт Page. До первого Refresh состояние Stale, все компоненты operational.
+func New(logger *slog.Logger, cfg Config) *Page {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Page{
+		logger:   logger,
+		cfg:      cfg,
+		interval: interval,
+		now:      time.Now,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	p.snapshot = p.compute(nil, time.Time{}, true)
+	return p
+}
+
+// Snapshot возвращает последнее вычисленное состояние.
+func (p *Page) Snapshot() ports.StatusSnapshot {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.snapshot
+}
+
+// Refresh пересчитывает состояние. Ошибка чтения выключателей или
+// инцидентов не сбрасывает последнее известное состояние: оно остаётся
+// с признаком Stale, а UpdatedAt - временем последнего успешного чтения.
+func (p *Page) Refresh(ctx context.Context) {
+	now := p.now().UTC()
+
+	var pingErr error
+	if p.cfg.Ping != nil {
+		pingErr = p.cfg.Ping(ctx)
+	}
+
+	switches, switchesErr := p.loadSwitches(ctx)
+	incidents, incidentsErr := p.loadIncidents(ctx, now)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stale := switchesErr != nil || incidentsErr != nil
+	if switchesErr == nil {
+		p.switches = switches
+	}
+	if incidentsErr == nil {
+		p.incidents = incidents
+	}
+
+	updatedAt := p.snapshot.UpdatedAt
+	if !stale {
+		updatedAt = now
+	} else {
+		p.logger.Warn("Status page refresh failed, serving last known state",
+			slog.Any("switches_error", switchesErr),
+			slog.Any("incidents_error", incidentsErr))
+	}
+
+	p.snapshot = p.compute(pingErr, updatedAt, stale)
+}
+
+// loadSwitches читает выключатели операций.
+func (p *Page) loadSwitches(ctx context.Context) ([]*entities.OperationSwitch, error) {
+	if p.cfg.Switches == nil {
+		return nil, nil
+	}
+	switches, err := p.cfg.Switches.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load operation switches: %w", err)
+	}
+	return switches, nil
+}
+
+// loadIncidents читает активные и недавно решённые инциденты.
+func (p *Page) loadIncidents(ctx context.Context, now time.Time) ([]*entities.Incident, error) {
+	if p.cfg.Incidents == nil {
+		return nil, nil
+	}
+	incidents, err := p.cfg.Incidents.ListVisible(ctx, now.Add(-entities.IncidentResolvedVisibility))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load incidents: %w", err)
+	}
+	return incidents, nil
+}
+
+// compute собирает состояние из последних известных данных. Вызывается под p.mu.
+func (p *Page) compute(pingErr error, updatedAt time.Time, stale bool) ports.StatusSnapshot {
+	disabled := make(map[entities.TransactionType]bool)
+	for _, sw := range p.switches {
+		if !sw.Enabled() {
+			disabled[sw.Operation()] = true
+		}
+	}
+
+	databaseDown := pingErr != nil
+	statuses := map[entities.StatusComponent]ports.ServiceStatus{
+		entities.StatusComponentAPI:      p.apiStatus(databaseDown),
+		entities.StatusComponentDatabase: ports.ServiceOperational,
+		entities.StatusComponentPayouts:  operationStatus(entities.TransactionTypePayout, disabled, databaseDown),
+		entities.StatusComponentDeposits: operationStatus(entities.TransactionTypeDeposit, disabled, databaseDown),
+	}
+	if databaseDown {
+		statuses[entities.StatusComponentDatabase] = ports.ServiceDown
+	}
+
+	snapshot := ports.StatusSnapshot{
+		Status:     ports.ServiceOperational,
+		Components: make([]ports.ComponentStatus, 0, len(entities.StatusComponents)),
+		Incidents:  p.incidents,
+		Stale:      stale,
+		UpdatedAt:  updatedAt,
+	}
+	for _, component := range entities.StatusComponents {
+		status := statuses[component]
+		snapshot.Components = append(snapshot.Components, ports.ComponentStatus{Component: component, Status: status})
+		snapshot.Status = snapshot.Status.Worse(status)
+	}
+	return snapshot
+}
+
+// apiStatus - API отвечает, поэтому худший статус - degraded.
+func (p *Page) apiStatus(databaseDown bool) ports.ServiceStatus {
+	if databaseDown {
+		return ports.ServiceDegraded
+	}
+	if p.cfg.Workers != nil {
+		now := p.now().UTC()
+		for _, w := range p.cfg.Workers.Local() {
+			if w.Stale(now) {
+				return ports.ServiceDegraded
+			}
+		}
+	}
+	return ports.ServiceOperational
+}
+
+// operationStatus - статус движения средств вида txType: недоступная БД -
+// down, любой выключатель операции (entities.OperationSwitchesFor) - degraded.
+func operationStatus(txType entities.TransactionType, disabled map[entities.TransactionType]bool, databaseDown bool) ports.ServiceStatus {
+	if databaseDown {
+		return ports.ServiceDown
+	}
+	for _, operation := range entities.OperationSwitchesFor(txType) {
+		if disabled[operation] {
+			return ports.ServiceDegraded
+		}
+	}
+	return ports.ServiceOperational
+}
+
+// ============================================
+// Lifecycle
+// ============================================
+
+// Start синхронно выполняет первое обновление и запускает фоновое.
+func (p *Page) Start() {
+	p.lifecycle.Lock()
+	defer p.lifecycle.Unlock()
+
+	if p.started || p.ctx.Err() != nil {
+		return
+	}
+	p.started = true
+
+	ctx, cancel := context.WithTimeout(p.ctx, refreshTimeout)
+	p.Refresh(ctx)
+	cancel()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.run()
+	}()
+}
+
+// Stop останавливает фоновое обновление.
+func (p *Page) Stop(ctx context.Context) error {
+	p.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("status page stop interrupted: %w", ctx.Err())
+	}
+}
+
+// run обновляет состояние раз в interval до Stop.
+func (p *Page) run() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(p.ctx, refreshTimeout)
+			p.Refresh(ctx)
+			cancel()
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package statuspage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+)
+
+// Все тесты пакета проверяются на утечку горутин.
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+var errOutage = errors.New("connection refused")
+
+// outage - переключаемая недоступность БД для ping и репозиториев.
+type outage struct {
+	mu   sync.Mutex
+	down bool
+}
+
+func (o *outage) set(down bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.down = down
+}
+
+func (o *outage) err() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.down {
+		return errOutage
+	}
+	return nil
+}
+
+type flakySwitches struct {
+	ports.OperationSwitchRepository
+	outage *outage
+}
+
+func (r flakySwitches) FindAll(ctx context.Context) ([]*entities.OperationSwitch, error) {
+	if err := r.outage.err(); err != nil {
+		return nil, err
+	}
+	return r.OperationSwitchRepository.FindAll(ctx)
+}
+
+type flakyIncidents struct {
+	ports.IncidentRepository
+	outage *outage
+}
+
+func (r flakyIncidents) ListVisible(ctx context.Context, resolvedSince time.Time) ([]*entities.Incident, error) {
+	if err := r.outage.err(); err != nil {
+		return nil, err
+	}
+	return r.IncidentRepository.ListVisible(ctx, resolvedSince)
+}
+
+// workersStub - heartbeat компонентов этого экземпляра.
+type workersStub struct {
+	ports.WorkerMonitor
+	local []ports.WorkerStatus
+}
+
+func (w workersStub) Local() []ports.WorkerStatus {
+	return w.local
+}
+
+type harness struct {
+	outage    *outage
+	switches  *memory.OperationSwitchRepository
+	incidents *memory.IncidentRepository
+	page      *Page
+}
+
+func newHarness(workers ports.WorkerMonitor) *harness {
+	store := memory.NewStore()
+	h := &harness{
+		outage:    &outage{},
+		switches:  memory.NewOperationSwitchRepository(store),
+		incidents: memory.NewIncidentRepository(store),
+	}
+	h.page = New(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{
+		Ping:      func(context.Context) error { return h.outage.err() },
+		Switches:  flakySwitches{OperationSwitchRepository: h.switches, outage: h.outage},
+		Incidents: flakyIncidents{IncidentRepository: h.incidents, outage: h.outage},
+		Workers:   workers,
+	})
+	return h
+}
+
+func (h *harness) disable(t *testing.T, operation entities.TransactionType) {
+	t.Helper()
+	sw, err := entities.NewOperationSwitch(operation, false, "provider outage", uuid.New())
+	require.NoError(t, err)
+	require.NoError(t, h.switches.Save(context.Background(), sw))
+}
+
+func statuses(snapshot ports.StatusSnapshot) map[entities.StatusComponent]ports.ServiceStatus {
+	result := make(map[entities.StatusComponent]ports.ServiceStatus)
+	for _, c := range snapshot.Components {
+		result[c.Component] = c.Status
+	}
+	return result
+}
+
+func TestPage_DegradationMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		disabled []entities.TransactionType
+		stale    bool
+		want     map[entities.StatusComponent]ports.ServiceStatus
+		overall  ports.ServiceStatus
+	}{
+		{
+			name:    "AllOperational",
+			overall: ports.ServiceOperational,
+			want: map[entities.StatusComponent]ports.ServiceStatus{
+				entities.StatusComponentAPI:      ports.ServiceOperational,
+				entities.StatusComponentDatabase: ports.ServiceOperational,
+				entities.StatusComponentPayouts:  ports.ServiceOperational,
+				entities.StatusComponentDeposits: ports.ServiceOperational,
+			},
+		},
+		{
+			name:     "WithdrawSwitchDegradesPayouts",
+			disabled: []entities.TransactionType{entities.TransactionTypeWithdraw},
+			overall:  ports.ServiceDegraded,
+			want: map[entities.StatusComponent]ports.ServiceStatus{
+				entities.StatusComponentAPI:      ports.ServiceOperational,
+				entities.StatusComponentDatabase: ports.ServiceOperational,
+				entities.StatusComponentPayouts:  ports.ServiceDegraded,
+				entities.StatusComponentDeposits: ports.ServiceOperational,
+			},
+		},
+		{
+			name:     "PayoutSwitchDegradesPayouts",
+			disabled: []entities.TransactionType{entities.TransactionTypePayout},
+			overall:  ports.ServiceDegraded,
+			want: map[entities.StatusComponent]ports.ServiceStatus{
+				entities.StatusComponentPayouts:  ports.ServiceDegraded,
+				entities.StatusComponentDeposits: ports.ServiceOperational,
+			},
+		},
+		{
+			name:     "DepositSwitchDegradesDeposits",
+			disabled: []entities.TransactionType{entities.TransactionTypeDeposit},
+			overall:  ports.ServiceDegraded,
+			want: map[entities.StatusComponent]ports.ServiceStatus{
+				entities.StatusComponentPayouts:  ports.ServiceOperational,
+				entities.StatusComponentDeposits: ports.ServiceDegraded,
+			},
+		},
+		{
+			name:     "TransferSwitchAffectsNeither",
+			disabled: []entities.TransactionType{entities.TransactionTypeTransfer},
+			overall:  ports.ServiceOperational,
+			want: map[entities.StatusComponent]ports.ServiceStatus{
+				entities.StatusComponentPayouts:  ports.ServiceOperational,
+				entities.StatusComponentDeposits: ports.ServiceOperational,
+			},
+		},
+		{
+			name:    "StaleWorkerDegradesAPI",
+			stale:   true,
+			overall: ports.ServiceDegraded,
+			want: map[entities.StatusComponent]ports.ServiceStatus{
+				entities.StatusComponentAPI:     ports.ServiceDegraded,
+				entities.StatusComponentPayouts: ports.ServiceOperational,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			heartbeatAt := time.Now().UTC()
+			if tt.stale {
+				heartbeatAt = heartbeatAt.Add(-time.Hour)
+			}
+			h := newHarness(workersStub{local: []ports.WorkerStatus{{
+				Worker: "outbox-relay", State: ports.WorkerIdle, LastHeartbeatAt: heartbeatAt, StaleAfter: time.Minute,
+			}}})
+			for _, operation := range tt.disabled {
+				h.disable(t, operation)
+			}
+
+			h.page.Refresh(context.Background())
+			snapshot := h.page.Snapshot()
+
+			got := statuses(snapshot)
+			for component, want := range tt.want {
+				assert.Equal(t, want, got[component], component)
+			}
+			assert.Equal(t, tt.overall, snapshot.Status)
+			assert.False(t, snapshot.Stale)
+		})
+	}
+}
+
+func TestPage_ServesLastKnownStateDuringOutage(t *testing.T) {
+	h := newHarness(nil)
+	ctx := context.Background()
+
+	h.disable(t, entities.TransactionTypeWithdraw)
+	incident, err := entities.NewIncident("Delayed payouts", entities.IncidentSeverityMajor,
+		[]entities.StatusComponent{entities.StatusComponentPayouts}, time.Time{}, uuid.New())
+	require.NoError(t, err)
+	require.NoError(t, h.incidents.Save(ctx, incident))
+
+	h.page.Refresh(ctx)
+	before := h.page.Snapshot()
+	require.False(t, before.Stale)
+	require.Len(t, before.Incidents, 1)
+
+	// БД недоступна: incidents и выключатели - последние известные
+	h.outage.set(true)
+	h.page.Refresh(ctx)
+	during := h.page.Snapshot()
+
+	assert.True(t, during.Stale)
+	assert.Equal(t, before.UpdatedAt, during.UpdatedAt, "UpdatedAt is the last successful read")
+	require.Len(t, during.Incidents, 1)
+	assert.Equal(t, incident.ID(), during.Incidents[0].ID())
+
+	got := statuses(during)
+	assert.Equal(t, ports.ServiceDown, got[entities.StatusComponentDatabase])
+	assert.Equal(t, ports.ServiceDown, got[entities.StatusComponentPayouts])
+	assert.Equal(t, ports.ServiceDown, got[entities.StatusComponentDeposits])
+	assert.Equal(t, ports.ServiceDegraded, got[entities.StatusComponentAPI])
+	assert.Equal(t, ports.ServiceDown, during.Status)
+
+	// БД вернулась: выключатель WITHDRAW по-прежнему учитывается
+	h.outage.set(false)
+	h.page.Refresh(ctx)
+	after := h.page.Snapshot()
+
+	assert.False(t, after.Stale)
+	assert.True(t, after.UpdatedAt.After(before.UpdatedAt) || after.UpdatedAt.Equal(before.UpdatedAt))
+	assert.Equal(t, ports.ServiceDegraded, statuses(after)[entities.StatusComponentPayouts])
+}
+
+func TestPage_OutageBeforeFirstReadIsStale(t *testing.T) {
+	h := newHarness(nil)
+	h.outage.set(true)
+
+	h.page.Start()
+	t.Cleanup(func() { require.NoError(t, h.page.Stop(context.Background())) })
+
+	snapshot := h.page.Snapshot()
+	assert.True(t, snapshot.Stale)
+	assert.True(t, snapshot.UpdatedAt.IsZero())
+	assert.Empty(t, snapshot.Incidents)
+	assert.Equal(t, ports.ServiceDown, statuses(snapshot)[entities.StatusComponentDatabase])
+}
+
+func TestPage_ResolvedIncidentListedForADay(t *testing.T) {
+	h := newHarness(nil)
+	ctx := context.Background()
+
+	resolvedAt := time.Now().UTC().Add(-23 * time.Hour)
+	recent := entities.ReconstructIncident(uuid.New(), "Recent", entities.IncidentSeverityMinor,
+		[]entities.StatusComponent{entities.StatusComponentAPI}, resolvedAt.Add(-time.Hour), &resolvedAt, uuid.New(), nil, resolvedAt)
+	oldAt := time.Now().UTC().Add(-25 * time.Hour)
+	old := entities.ReconstructIncident(uuid.New(), "Old", entities.IncidentSeverityMinor,
+		[]entities.StatusComponent{entities.StatusComponentAPI}, oldAt.Add(-time.Hour), &oldAt, uuid.New(), nil, oldAt)
+	require.NoError(t, h.incidents.Save(ctx, recent))
+	require.NoError(t, h.incidents.Save(ctx, old))
+
+	h.page.Refresh(ctx)
+	snapshot := h.page.Snapshot()
+	require.Len(t, snapshot.Incidents, 1)
+	assert.Equal(t, recent.ID(), snapshot.Incidents[0].ID())
+}
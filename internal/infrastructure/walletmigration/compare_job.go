@@ -0,0 +1,191 @@
+// Package walletmigration - фоновая сверка схем балансов кошельков.
+//
+// Пока WalletRepository пишет балансы и в колонки wallets, и в
+// wallet_balances / wallet_holds (фазы dual_write, shadow_read_compare,
+// new_read), CompareJob периодически берёт случайную выборку кошельков
+// и сообщает о расхождениях: в лог (WARN с примерами) и в OnReport (метрика).
+//
+// Ноль расхождений на нескольких прогонах подряд - сигнал, что можно
+// переключать чтение на новую схему.
+package walletmigration
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/postgres"
+)
+
+// Значения по умолчанию для незаданных полей Config.
+const (
+	DefaultInterval   = 10 * time.Minute
+	DefaultSampleSize = 500
+)
+
+const (
+	// compareTimeout ограничивает один прогон сверки
+	compareTimeout = time.Minute
+
+	// loggedExamples - сколько расходящихся кошельков перечислять в логе
+	loggedExamples = 10
+)
+
+// Sampler сверяет выборку кошельков (postgres.WalletRepository).
+type Sampler interface {
+	SampleDivergence(ctx context.Context, sampleSize int) (*postgres.WalletDivergenceReport, error)
+}
+
+// Config - настройки сверки.
+type Config struct {
+	Interval   time.Duration
+	SampleSize int
+
+	// OnReport вызывается после каждого успешного прогона (метрика). Может быть nil.
+	OnReport func(sampled, divergent int)
+
+	// Heartbeat получает отчёт о прогонах собственного таймера (Start).
+	// Прогоны через CompareOnce сообщает вызывающий. Может быть nil.
+	Heartbeat ports.WorkerHeartbeat
+}
+
+// CompareJob периодически сверяет схемы балансов.
+type CompareJob struct {
+	logger     *slog.Logger
+	sampler    Sampler
+	interval   time.Duration
+	sampleSize int
+	onReport   func(sampled, divergent int)
+	heartbeat  ports.WorkerHeartbeat
+
+	mu      sync.Mutex
+	started bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewCompareJob создаёт сверку. Прогоны начинаются после Start.
+func NewCompareJob(logger *slog.Logger, sampler Sampler, cfg Config) *CompareJob {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.SampleSize <= 0 {
+		cfg.SampleSize = DefaultSampleSize
+	}
+	if cfg.OnReport == nil {
+		cfg.OnReport = func(int, int) {}
+	}
+	if cfg.Heartbeat == nil {
+		cfg.Heartbeat = ports.NoopWorkerHeartbeat{}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &CompareJob{
+		logger:     logger,
+		sampler:    sampler,
+		interval:   cfg.Interval,
+		sampleSize: cfg.SampleSize,
+		onReport:   cfg.OnReport,
+		heartbeat:  cfg.Heartbeat,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start запускает периодическую сверку. Не блокирует; повторный вызов - no-op.
+func (j *CompareJob) Start() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.started || j.ctx.Err() != nil {
+		return
+	}
+	j.started = true
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		j.run()
+	}()
+}
+
+// Stop прерывает текущий прогон и ждёт завершения горутины.
+func (j *CompareJob) Stop(ctx context.Context) error {
+	j.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		j.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("wallet migration compare job stop interrupted: %w", ctx.Err())
+	}
+}
+
+// run - первый прогон сразу, дальше раз в interval.
+func (j *CompareJob) run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.heartbeat.RunStarted()
+		report, err := j.CompareOnce(j.ctx)
+		sampled := 0
+		if report != nil {
+			sampled = report.Sampled
+		}
+		j.heartbeat.RunFinished(sampled, err)
+
+		select {
+		case <-j.ctx.Done():
+			j.heartbeat.Stopped()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CompareOnce выполняет один прогон сверки и сообщает о результате.
+func (j *CompareJob) CompareOnce(ctx context.Context) (*postgres.WalletDivergenceReport, error) {
+	ctx, cancel := context.WithTimeout(ctx, compareTimeout)
+	defer cancel()
+
+	report, err := j.sampler.SampleDivergence(ctx, j.sampleSize)
+	if err != nil {
+		if j.ctx.Err() == nil {
+			j.logger.Error("Wallet balance comparison failed", slog.String("error", err.Error()))
+		}
+		return nil, err
+	}
+
+	j.onReport(report.Sampled, report.Divergent())
+	if report.Divergent() == 0 {
+		j.logger.Info("Wallet balance comparison found no divergence", slog.Int("sampled", report.Sampled))
+		return report, nil
+	}
+
+	examples := report.Divergences
+	if len(examples) > loggedExamples {
+		examples = examples[:loggedExamples]
+	}
+	walletIDs := make([]string, 0, len(examples))
+	for _, d := range examples {
+		walletIDs = append(walletIDs, d.WalletID.String())
+	}
+
+	j.logger.Warn("Wallet balance schemas diverge",
+		slog.Int("sampled", report.Sampled),
+		slog.Int("divergent", report.Divergent()),
+		slog.Any("wallet_ids", walletIDs),
+	)
+	return report, nil
+}
@@ -0,0 +1,116 @@
+package walletmigration
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/postgres"
+)
+
+// Все тесты пакета проверяются на утечку горутин.
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// fakeSampler возвращает заранее заданный отчёт и считает вызовы.
+type fakeSampler struct {
+	mu      sync.Mutex
+	calls   int
+	sizes   []int
+	report  *postgres.WalletDivergenceReport
+	err     error
+	sampled chan struct{}
+}
+
+func (s *fakeSampler) SampleDivergence(_ context.Context, sampleSize int) (*postgres.WalletDivergenceReport, error) {
+	s.mu.Lock()
+	s.calls++
+	s.sizes = append(s.sizes, sampleSize)
+	s.mu.Unlock()
+
+	if s.sampled != nil {
+		select {
+		case s.sampled <- struct{}{}:
+		default:
+		}
+	}
+	return s.report, s.err
+}
+
+func TestCompareJob_CompareOnce_ReportsDivergence(t *testing.T) {
+	sampler := &fakeSampler{report: &postgres.WalletDivergenceReport{
+		Sampled: 3,
+		Divergences: []postgres.WalletDivergence{
+			{WalletID: uuid.New(), Fields: []string{"available_balance"}},
+			{WalletID: uuid.New(), Missing: true},
+		},
+	}}
+
+	var sampled, divergent int
+	job := NewCompareJob(discardLogger(), sampler, Config{
+		SampleSize: 3,
+		OnReport:   func(s, d int) { sampled, divergent = s, d },
+	})
+
+	report, err := job.CompareOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Divergent())
+	assert.Equal(t, 3, sampled)
+	assert.Equal(t, 2, divergent)
+	assert.Equal(t, []int{3}, sampler.sizes)
+}
+
+func TestCompareJob_CompareOnce_SamplerError(t *testing.T) {
+	reported := false
+	job := NewCompareJob(discardLogger(), &fakeSampler{err: errors.New("db down")}, Config{
+		OnReport: func(int, int) { reported = true },
+	})
+
+	_, err := job.CompareOnce(context.Background())
+	assert.Error(t, err)
+	assert.False(t, reported, "failed run must not report a sample")
+}
+
+func TestCompareJob_Defaults(t *testing.T) {
+	job := NewCompareJob(discardLogger(), &fakeSampler{}, Config{})
+
+	assert.Equal(t, DefaultInterval, job.interval)
+	assert.Equal(t, DefaultSampleSize, job.sampleSize)
+}
+
+func TestCompareJob_StartRunsImmediatelyAndStops(t *testing.T) {
+	sampler := &fakeSampler{
+		report:  &postgres.WalletDivergenceReport{Sampled: 1},
+		sampled: make(chan struct{}, 1),
+	}
+	job := NewCompareJob(discardLogger(), sampler, Config{Interval: time.Hour})
+
+	job.Start()
+	job.Start() // no-op
+
+	select {
+	case <-sampler.sampled:
+	case <-time.After(time.Second):
+		t.Fatal("first comparison did not run on start")
+	}
+
+	require.NoError(t, job.Stop(context.Background()))
+	assert.Equal(t, 1, sampler.calls)
+
+	job.Start() // после Stop не запускается
+	require.NoError(t, job.Stop(context.Background()))
+}
@@ -0,0 +1,305 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/errors"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// Значения по умолчанию для незаданных полей Config.
+const (
+	DefaultBatchSize      = 100
+	DefaultRequestTimeout = 10 * time.Second
+	DefaultMaxAttempts    = 10
+	DefaultRetryBaseDelay = 30 * time.Second
+	DefaultRetryMaxDelay  = time.Hour
+)
+
+// Заголовки запроса доставки.
+const (
+	HeaderEvent     = "X-PayBridge-Event"
+	HeaderDelivery  = "X-PayBridge-Delivery"
+	HeaderSignature = "X-PayBridge-Signature"
+)
+
+// maxResponseDrain - сколько байт ответа читается, чтобы переиспользовать соединение
+const maxResponseDrain = 64 << 10
+
+// Config - настройки доставки.
+type Config struct {
+	// BatchSize - сколько доставок отправляется за один запуск.
+	BatchSize int
+
+	// RequestTimeout ограничивает один запрос к endpoint.
+	RequestTimeout time.Duration
+
+	// MaxAttempts - попыток на доставку, после последней она FAILED.
+	MaxAttempts int
+
+	// RetryBaseDelay / RetryMaxDelay - экспоненциальная задержка повтора
+	// после n-й неудачной попытки: base * 2^(n-1), не больше max.
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+
+	// Health - когда подписка отключается автоматически. Нулевое значение -
+	// entities.DefaultWebhookHealthPolicy.
+	Health entities.WebhookHealthPolicy
+}
+
+// Dispatcher отправляет подошедшие доставки и ведёт здоровье подписок.
+type Dispatcher struct {
+	logger     *slog.Logger
+	subs       ports.WebhookSubscriptionRepository
+	deliveries ports.WebhookDeliveryRepository
+	uow        ports.UnitOfWork
+	publisher  ports.EventPublisher
+	client     *http.Client
+	cfg        Config
+}
+
+// NewDispatcher создаёт Dispatcher. client == nil - http.Client с
+// cfg.RequestTimeout. Редиректы не выполняются: ответ 3xx - неудача.
+//
+// publisher получает WebhookSubscriptionDisabled внутри uow, вместе с
+// сохранением отключённой подписки.
+func NewDispatcher(
+	logger *slog.Logger,
+	subs ports.WebhookSubscriptionRepository,
+	deliveries ports.WebhookDeliveryRepository,
+	uow ports.UnitOfWork,
+	publisher ports.EventPublisher,
+	client *http.Client,
+	cfg Config,
+) *Dispatcher {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = DefaultRequestTimeout
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.RetryBaseDelay <= 0 {
+		cfg.RetryBaseDelay = DefaultRetryBaseDelay
+	}
+	if cfg.RetryMaxDelay <= 0 {
+		cfg.RetryMaxDelay = DefaultRetryMaxDelay
+	}
+	if cfg.Health == (entities.WebhookHealthPolicy{}) {
+		cfg.Health = entities.DefaultWebhookHealthPolicy
+	}
+	httpClient := http.Client{Timeout: cfg.RequestTimeout}
+	if client != nil {
+		httpClient = *client
+	}
+	httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &Dispatcher{
+		logger:     logger,
+		subs:       subs,
+		deliveries: deliveries,
+		uow:        uow,
+		publisher:  publisher,
+		client:     &httpClient,
+		cfg:        cfg,
+	}
+}
+
+// DeliverDue отправляет до BatchSize подошедших доставок активных подписок
+// и возвращает число выполненных попыток.
+//
+// После неудачи остальные доставки той же подписки ждут следующего
+// запуска. Доставка, результат которой не удалось сохранить (например,
+// владелец одновременно изменил подписку), остаётся PENDING и будет
+// отправлена повторно - получатель должен быть идемпотентен по
+// X-PayBridge-Delivery.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	due, err := d.deliveries.FindDue(ctx, clock.Now(), d.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find due webhook deliveries: %w", err)
+	}
+
+	attempted := 0
+	paused := make(map[uuid.UUID]bool)
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			return attempted, ctx.Err()
+		}
+		if paused[delivery.SubscriptionID()] {
+			continue
+		}
+
+		subscription, err := d.subs.FindByID(ctx, delivery.SubscriptionID())
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return attempted, fmt.Errorf("failed to load webhook subscription: %w", err)
+			}
+			paused[delivery.SubscriptionID()] = true
+			continue
+		}
+		if !subscription.IsActive() {
+			paused[delivery.SubscriptionID()] = true
+			continue
+		}
+
+		statusCode, failure := d.send(ctx, subscription, delivery)
+		attempted++
+
+		active, err := d.record(ctx, delivery, statusCode, failure)
+		if err != nil {
+			d.logger.Error("Failed to record webhook delivery attempt",
+				slog.String("delivery_id", delivery.ID().String()),
+				slog.String("subscription_id", delivery.SubscriptionID().String()),
+				slog.String("error", err.Error()),
+			)
+			paused[delivery.SubscriptionID()] = true
+			continue
+		}
+		if failure != "" || !active {
+			paused[delivery.SubscriptionID()] = true
+		}
+	}
+
+	return attempted, nil
+}
+
+// send отправляет доставку. Возвращает HTTP статус (0 - ответа нет) и
+// причину неудачи ("" - успех, ответ 2xx).
+func (d *Dispatcher) send(ctx context.Context, subscription *entities.WebhookSubscription, delivery *entities.WebhookDelivery) (int, string) {
+	payload := delivery.Payload()
+
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.RequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL(), bytes.NewReader(payload))
+	if err != nil {
+		return 0, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PayBridge-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType())
+	req.Header.Set(HeaderDelivery, delivery.ID().String())
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret(), payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseDrain))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Sprintf("HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, ""
+}
+
+// record сохраняет результат попытки и здоровье подписки одной транзакцией.
+// Возвращает, осталась ли подписка активной.
+func (d *Dispatcher) record(ctx context.Context, delivery *entities.WebhookDelivery, statusCode int, failure string) (bool, error) {
+	active := true
+
+	err := d.uow.Execute(ctx, func(txCtx context.Context) error {
+		if failure == "" {
+			delivery.MarkDelivered(statusCode)
+		} else {
+			delivery.MarkAttemptFailed(statusCode, failure, clock.Now().Add(d.retryDelay(delivery.Attempts()+2)), d.cfg.MaxAttempts)
+		}
+		if err := d.deliveries.Save(txCtx, delivery); err != nil {
+			return fmt.Errorf("failed to save webhook delivery: %w", err)
+		}
+
+		// Подписка перечитывается в транзакции: её мог изменить владелец
+		subscription, err := d.subs.FindByID(txCtx, delivery.SubscriptionID())
+		if err != nil {
+			if errors.IsNotFound(err) {
+				active = false
+				return nil
+			}
+			return fmt.Errorf("failed to load webhook subscription: %w", err)
+		}
+
+		var disabledReason string
+		if failure == "" {
+			if !subscription.RecordDeliverySuccess() {
+				active = subscription.IsActive()
+				return nil
+			}
+		} else {
+			disabledReason = subscription.RecordDeliveryFailure(failure, d.cfg.Health)
+		}
+		active = subscription.IsActive()
+
+		if err := d.subs.Save(txCtx, subscription); err != nil {
+			return fmt.Errorf("failed to save webhook subscription: %w", err)
+		}
+		if disabledReason == "" {
+			return nil
+		}
+
+		d.logger.Warn("Webhook subscription disabled after repeated delivery failures",
+			slog.String("subscription_id", subscription.ID().String()),
+			slog.String("user_id", subscription.UserID().String()),
+			slog.String("reason", disabledReason),
+			slog.Int("consecutive_failures", subscription.ConsecutiveFailures()),
+		)
+
+		var failingSince time.Time
+		if since := subscription.FailingSince(); since != nil {
+			failingSince = *since
+		}
+		event := events.NewWebhookSubscriptionDisabled(
+			subscription.ID(),
+			subscription.UserID(),
+			subscription.URL(),
+			disabledReason,
+			subscription.ConsecutiveFailures(),
+			failingSince,
+			subscription.LastFailureReason(),
+		)
+		if err := d.publisher.Publish(txCtx, event); err != nil {
+			return fmt.Errorf("failed to publish webhook subscription disabled: %w", err)
+		}
+		return nil
+	})
+
+	return active, err
+}
+
+// retryDelay - задержка перед попыткой attempt (с 2): base * 2^(attempt-2).
+func (d *Dispatcher) retryDelay(attempt int) time.Duration {
+	delay := d.cfg.RetryBaseDelay
+	for i := 2; i < attempt; i++ {
+		delay *= 2
+		if delay >= d.cfg.RetryMaxDelay {
+			return d.cfg.RetryMaxDelay
+		}
+	}
+	return min(delay, d.cfg.RetryMaxDelay)
+}
+
+// Sign возвращает значение X-PayBridge-Signature: "sha256=" и hex
+// HMAC-SHA256 тела запроса на секрете подписки.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,276 @@
+package webhooks_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	webhookuc "github.com/Haleralex/wallethub/internal/application/usecases/webhooks"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/persistence/memory"
+	"github.com/Haleralex/wallethub/internal/infrastructure/webhooks"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// endpoint - webhook получатель теста: отвечает status и запоминает запросы.
+type endpoint struct {
+	mu       sync.Mutex
+	status   int
+	secret   string
+	received []string // X-PayBridge-Delivery принятых (2xx) запросов
+	requests int
+	badSigs  int
+}
+
+func (e *endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.requests++
+	if r.Header.Get(webhooks.HeaderSignature) != webhooks.Sign(e.secret, body) {
+		e.badSigs++
+	}
+	if e.status >= 200 && e.status < 300 {
+		e.received = append(e.received, r.Header.Get(webhooks.HeaderDelivery))
+	}
+	w.WriteHeader(e.status)
+}
+
+func (e *endpoint) set(status int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.status = status
+}
+
+func (e *endpoint) counts() (requests, received, badSigs int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.requests, len(e.received), e.badSigs
+}
+
+type dispatcherHarness struct {
+	fake       *clock.Fake
+	ctx        context.Context
+	userID     uuid.UUID
+	endpoint   *endpoint
+	fanout     *webhooks.Fanout
+	dispatcher *webhooks.Dispatcher
+	publisher  *memory.EventPublisher
+	deliveries *memory.WebhookDeliveryRepository
+	update     *webhookuc.UpdateWebhookUseCase
+	created    *dtos.WebhookSubscriptionCreatedDTO
+}
+
+// newDispatcherHarness создаёт пользователя с подпиской на endpoint, который
+// отвечает 404. Подписка отключается после трёх неудач подряд.
+func newDispatcherHarness(t *testing.T) *dispatcherHarness {
+	t.Helper()
+
+	fake := clock.NewFake(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	t.Cleanup(clock.SetClock(fake))
+
+	store := memory.NewStore()
+	user, err := entities.NewUser("owner@example.com", "Owner")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := memory.NewUserRepository(store).Save(context.Background(), user); err != nil {
+		t.Fatalf("Save user error = %v", err)
+	}
+	ctx := ports.WithActor(context.Background(), ports.Actor{ID: user.ID(), Role: "user"})
+
+	ep := &endpoint{status: http.StatusNotFound}
+	server := httptest.NewServer(ep)
+	t.Cleanup(server.Close)
+
+	subs := memory.NewWebhookSubscriptionRepository(store)
+	deliveries := memory.NewWebhookDeliveryRepository(store)
+	uow := memory.NewUnitOfWork(store)
+	publisher := memory.NewEventPublisher(store)
+
+	created, err := webhookuc.NewCreateWebhookUseCase(subs).Execute(ctx, dtos.CreateWebhookCommand{URL: server.URL + "/hooks"})
+	if err != nil {
+		t.Fatalf("Create webhook error = %v", err)
+	}
+	ep.secret = created.Secret
+
+	return &dispatcherHarness{
+		fake:     fake,
+		ctx:      ctx,
+		userID:   user.ID(),
+		endpoint: ep,
+		fanout:   webhooks.NewFanout(subs, deliveries, uow, 24*time.Hour),
+		dispatcher: webhooks.NewDispatcher(slog.New(slog.DiscardHandler), subs, deliveries, uow, publisher, server.Client(), webhooks.Config{
+			RetryBaseDelay: time.Second,
+			RetryMaxDelay:  time.Minute,
+			Health:         entities.WebhookHealthPolicy{MaxConsecutiveFailures: 3, MaxFailingDuration: 72 * time.Hour},
+		}),
+		publisher:  publisher,
+		deliveries: deliveries,
+		update:     webhookuc.NewUpdateWebhookUseCase(subs, deliveries, uow),
+		created:    created,
+	}
+}
+
+// emit ставит в очередь n событий владельца.
+func (h *dispatcherHarness) emit(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := h.fanout.Handle(context.Background(), events.NewUserKYCApproved(h.userID, uuid.New())); err != nil {
+			t.Fatalf("Fanout.Handle() error = %v", err)
+		}
+		h.fake.Advance(time.Millisecond)
+	}
+}
+
+// runUntilIdle запускает доставку, пропуская задержки повторов, пока есть попытки.
+func (h *dispatcherHarness) runUntilIdle(t *testing.T) int {
+	t.Helper()
+	total := 0
+	for i := 0; i < 50; i++ {
+		attempted, err := h.dispatcher.DeliverDue(context.Background())
+		if err != nil {
+			t.Fatalf("DeliverDue() error = %v", err)
+		}
+		if attempted == 0 {
+			return total
+		}
+		total += attempted
+		h.fake.Advance(time.Minute)
+	}
+	t.Fatal("deliveries did not settle")
+	return total
+}
+
+// disable доводит подписку до DISABLED_AUTO через неудачные доставки.
+func (h *dispatcherHarness) disable(t *testing.T) {
+	t.Helper()
+	h.emit(t, 2)
+	if attempted := h.runUntilIdle(t); attempted != 3 {
+		t.Fatalf("attempts before auto-disable = %d, want 3", attempted)
+	}
+}
+
+func (h *dispatcherHarness) statuses(t *testing.T) map[entities.WebhookDeliveryStatus]int {
+	t.Helper()
+	deliveries, err := h.deliveries.ListBySubscription(context.Background(), uuid.MustParse(h.created.ID), 0, 100)
+	if err != nil {
+		t.Fatalf("ListBySubscription() error = %v", err)
+	}
+	counts := make(map[entities.WebhookDeliveryStatus]int)
+	for _, delivery := range deliveries {
+		counts[delivery.Status()]++
+	}
+	return counts
+}
+
+func TestDispatcher_AutoDisablesFailingEndpoint(t *testing.T) {
+	h := newDispatcherHarness(t)
+	h.disable(t)
+
+	published := h.publisher.Events()
+	if len(published) != 1 {
+		t.Fatalf("published %d events, want 1", len(published))
+	}
+	disabled, ok := published[0].(*events.WebhookSubscriptionDisabled)
+	if !ok {
+		t.Fatalf("published %T, want *events.WebhookSubscriptionDisabled", published[0])
+	}
+	if disabled.Reason != entities.WebhookDisabledConsecutiveFailures || disabled.ConsecutiveFailures != 3 || disabled.LastFailureReason != "HTTP 404" {
+		t.Errorf("unexpected event: %+v", disabled)
+	}
+
+	// Доставки остановлены сразу, очередь сохранена
+	h.emit(t, 1)
+	h.fake.Advance(time.Hour)
+	if attempted := h.runUntilIdle(t); attempted != 0 {
+		t.Errorf("disabled subscription got %d attempts", attempted)
+	}
+	if requests, _, badSigs := h.endpoint.counts(); requests != 3 || badSigs != 0 {
+		t.Errorf("endpoint requests = %d, bad signatures = %d", requests, badSigs)
+	}
+	if pending := h.statuses(t)[entities.WebhookDeliveryPending]; pending != 3 {
+		t.Errorf("pending backlog = %d, want 3", pending)
+	}
+}
+
+func TestDispatcher_ResumeDeliversBacklog(t *testing.T) {
+	h := newDispatcherHarness(t)
+	h.disable(t)
+	h.emit(t, 1)
+
+	h.endpoint.set(http.StatusNoContent)
+	active := true
+	resumed, err := h.update.Execute(h.ctx, dtos.UpdateWebhookCommand{SubscriptionID: h.created.ID, Active: &active})
+	if err != nil {
+		t.Fatalf("Update webhook error = %v", err)
+	}
+	if resumed.Status != string(entities.WebhookStatusActive) || resumed.ConsecutiveFailures != 0 {
+		t.Errorf("resumed subscription: %+v", resumed)
+	}
+
+	// Задержки повторов сброшены: вся очередь доставляется одним запуском
+	attempted, err := h.dispatcher.DeliverDue(context.Background())
+	if err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	if attempted != 3 {
+		t.Errorf("attempts after resume = %d, want 3", attempted)
+	}
+	if _, received, _ := h.endpoint.counts(); received != 3 {
+		t.Errorf("endpoint received %d deliveries, want 3", received)
+	}
+	if counts := h.statuses(t); counts[entities.WebhookDeliveryDelivered] != 3 || counts[entities.WebhookDeliveryPending] != 0 {
+		t.Errorf("delivery statuses = %v", counts)
+	}
+}
+
+func TestDispatcher_ResumeSkippingBacklog(t *testing.T) {
+	h := newDispatcherHarness(t)
+	h.disable(t)
+
+	h.endpoint.set(http.StatusOK)
+	active := true
+	if _, err := h.update.Execute(h.ctx, dtos.UpdateWebhookCommand{SubscriptionID: h.created.ID, Active: &active, SkipBacklog: true}); err != nil {
+		t.Fatalf("Update webhook error = %v", err)
+	}
+	if attempted := h.runUntilIdle(t); attempted != 0 {
+		t.Errorf("skipped backlog got %d attempts", attempted)
+	}
+	if counts := h.statuses(t); counts[entities.WebhookDeliverySkipped] != 2 {
+		t.Errorf("delivery statuses = %v", counts)
+	}
+
+	// Новые события доставляются
+	h.emit(t, 1)
+	if attempted := h.runUntilIdle(t); attempted != 1 {
+		t.Errorf("attempts for a new event = %d, want 1", attempted)
+	}
+}
+
+func TestFanout_BacklogGrace(t *testing.T) {
+	h := newDispatcherHarness(t)
+	disable := false
+	if _, err := h.update.Execute(h.ctx, dtos.UpdateWebhookCommand{SubscriptionID: h.created.ID, Active: &disable}); err != nil {
+		t.Fatalf("Update webhook error = %v", err)
+	}
+
+	h.emit(t, 1)
+	h.fake.Advance(24 * time.Hour)
+	h.emit(t, 1)
+
+	if pending := h.statuses(t)[entities.WebhookDeliveryPending]; pending != 1 {
+		t.Errorf("pending = %d, want only the event within the grace period", pending)
+	}
+}
@@ -0,0 +1,73 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+	"github.com/Haleralex/wallethub/internal/domain/entities"
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/infrastructure/eventbus"
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// FanoutConsumerName - имя подписчика шины.
+const FanoutConsumerName = "webhook-fanout"
+
+// DefaultBacklogGrace - сколько отключённая подписка копит очередь.
+const DefaultBacklogGrace = 7 * 24 * time.Hour
+
+// Fanout ставит события в очереди webhook подписок их владельцев.
+//
+// Очередь пополняется и у отключённой подписки в пределах backlogGrace
+// после отключения: повторное включение продолжает доставку с места
+// остановки. Повторная доставка события шиной не создаёт дублей
+// (Enqueue идемпотентен по паре подписка/событие).
+type Fanout struct {
+	subs         ports.WebhookSubscriptionRepository
+	deliveries   ports.WebhookDeliveryRepository
+	uow          ports.UnitOfWork
+	backlogGrace time.Duration
+}
+
+// NewFanout создаёт Fanout. backlogGrace <= 0 - DefaultBacklogGrace.
+func NewFanout(subs ports.WebhookSubscriptionRepository, deliveries ports.WebhookDeliveryRepository, uow ports.UnitOfWork, backlogGrace time.Duration) *Fanout {
+	if backlogGrace <= 0 {
+		backlogGrace = DefaultBacklogGrace
+	}
+	return &Fanout{subs: subs, deliveries: deliveries, uow: uow, backlogGrace: backlogGrace}
+}
+
+// Subscribe подписывает Fanout на все события шины.
+func (f *Fanout) Subscribe(bus *eventbus.Bus) *eventbus.Subscription {
+	return bus.SubscribeAll(FanoutConsumerName, f.Handle)
+}
+
+// Handle - ports.EventHandler: ставит событие в очередь подходящих подписок.
+// События без webhook представления игнорируются.
+func (f *Fanout) Handle(ctx context.Context, event events.DomainEvent) error {
+	userID, payload, ok, err := Payload(event)
+	if err != nil || !ok {
+		return err
+	}
+
+	return f.uow.Execute(ctx, func(txCtx context.Context) error {
+		subscriptions, err := f.subs.ListByUserID(txCtx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to list webhook subscriptions: %w", err)
+		}
+
+		now := clock.Now()
+		for _, subscription := range subscriptions {
+			if !subscription.QueuesAt(now, f.backlogGrace) || !subscription.Subscribed(event.EventType()) {
+				continue
+			}
+			delivery := entities.NewWebhookDelivery(subscription.ID(), event.EventID(), event.EventType(), payload)
+			if err := f.deliveries.Enqueue(txCtx, delivery); err != nil {
+				return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,113 @@
+// Package webhooks - доставка доменных событий на HTTP endpoints
+// пользователей.
+//
+// Поток:
+//   - Fanout слушает внутреннюю шину и ставит событие в очередь каждой
+//     подписки владельца (webhook_deliveries, статус PENDING)
+//   - Dispatcher по расписанию (job webhook-deliveries) отправляет
+//     подошедшие доставки POST-запросом с HMAC подписью и повторяет неудачные
+//     с экспоненциальной задержкой
+//   - Подписка, endpoint которой отвечает ошибкой слишком долго
+//     (entities.WebhookHealthPolicy), отключается автоматически
+//     (DISABLED_AUTO) с событием WebhookSubscriptionDisabled; её очередь
+//     сохраняется на webhooks.backlog_grace
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Haleralex/wallethub/internal/domain/events"
+)
+
+// envelope - тело запроса доставки.
+type envelope struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	Data       map[string]any `json:"data"`
+}
+
+// Payload возвращает владельца события и тело запроса доставки.
+// ok=false - тип события не доставляется webhooks (ports.WebhookEventTypes).
+//
+// Тело сериализуется один раз при постановке в очередь: повторные попытки
+// отправляют те же байты с той же подписью.
+func Payload(event events.DomainEvent) (userID uuid.UUID, payload []byte, ok bool, err error) {
+	var data map[string]any
+
+	switch e := event.(type) {
+	case *events.TransactionCreated:
+		userID = e.UserID
+		data = map[string]any{
+			"transaction_id":   e.TransactionID.String(),
+			"wallet_id":        e.WalletID.String(),
+			"transaction_type": e.TransactionType,
+			"amount":           e.Amount.String(),
+			"currency":         e.Amount.Currency().Code(),
+			"fee_amount":       e.FeeAmount.String(),
+			"net_amount":       e.NetAmount.String(),
+		}
+	case *events.TransactionCompleted:
+		userID = e.UserID
+		data = map[string]any{
+			"transaction_id":   e.TransactionID.String(),
+			"wallet_id":        e.WalletID.String(),
+			"transaction_type": e.TransactionType,
+			"amount":           e.Amount.String(),
+			"currency":         e.Amount.Currency().Code(),
+			"fee_amount":       e.FeeAmount.String(),
+			"net_amount":       e.NetAmount.String(),
+			"completed_at":     e.CompletedAt.UTC(),
+		}
+		if e.CounterpartyWalletID != uuid.Nil {
+			data["counterparty_wallet_id"] = e.CounterpartyWalletID.String()
+		}
+		if e.ReceiptNumber != "" {
+			data["receipt_number"] = e.ReceiptNumber
+		}
+	case *events.TransactionFailed:
+		userID = e.UserID
+		data = map[string]any{
+			"transaction_id":   e.TransactionID.String(),
+			"wallet_id":        e.WalletID.String(),
+			"transaction_type": e.TransactionType,
+			"amount":           e.Amount.String(),
+			"currency":         e.Amount.Currency().Code(),
+			"failure_reason":   e.FailureReason,
+			"failure_category": e.FailureCategory,
+			"is_retryable":     e.IsRetryable,
+		}
+	case *events.WalletCreated:
+		userID = e.UserID
+		data = map[string]any{
+			"wallet_id": e.AggregateID().String(),
+			"currency":  e.Currency.Code(),
+		}
+	case *events.UserKYCApproved:
+		userID = e.UserID
+		data = map[string]any{}
+	case *events.UserKYCRejected:
+		userID = e.UserID
+		data = map[string]any{"reason": e.Reason}
+	case *events.UserKYCRevoked:
+		userID = e.UserID
+		data = map[string]any{"reason": e.Reason}
+	default:
+		return uuid.Nil, nil, false, nil
+	}
+
+	payload, err = json.Marshal(envelope{
+		ID:         event.EventID().String(),
+		Type:       event.EventType(),
+		OccurredAt: event.OccurredAt().UTC(),
+		Data:       data,
+	})
+	if err != nil {
+		return uuid.Nil, nil, false, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+	return userID, payload, true, nil
+}
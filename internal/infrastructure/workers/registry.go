@@ -0,0 +1,351 @@
+// Package workers - реестр heartbeat фоновых компонентов экземпляра.
+//
+// Каждый фоновый компонент (outbox relay, сверка балансов, дебаунс
+// summary, задачи планировщика) регистрируется в Registry и сообщает о
+// своих прогонах через *Worker (ports.WorkerHeartbeat). Отчёт - запись
+// нескольких полей под собственным мьютексом компонента: циклы не ждут
+// друг друга и не обращаются к БД.
+//
+// С подключённым ports.WorkerHeartbeatRepository реестр раз в
+// PersistInterval пишет heartbeat в workers_heartbeat, и Workers
+// возвращает вид всего кластера. Устаревший heartbeat - предупреждение
+// в readiness, а не отказ.
+//
+// С Config.Instances реестр с тем же интервалом отмечает в instances, что
+// экземпляр жив: по этой отметке задача processing-recovery отличает
+// транзакции упавших экземпляров от выполняющихся.
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Compile-time checks
+var (
+	_ ports.WorkerMonitor   = (*Registry)(nil)
+	_ ports.WorkerHeartbeat = (*Worker)(nil)
+)
+
+// Значения по умолчанию для незаданных полей Config.
+const (
+	DefaultPersistInterval = 15 * time.Second
+	DefaultClusterWindow   = 24 * time.Hour
+)
+
+// storeTimeout ограничивает запись и чтение workers_heartbeat
+const storeTimeout = 5 * time.Second
+
+// Config - настройки реестра.
+type Config struct {
+	// Instance идентифицирует экземпляр; пусто - hostname-pid
+	Instance string
+
+	// PersistInterval - как часто heartbeat пишутся в хранилище
+	PersistInterval time.Duration
+
+	// ClusterWindow - heartbeat других экземпляров старше окна не
+	// показываются (экземпляр давно остановлен)
+	ClusterWindow time.Duration
+
+	// Instances - liveness экземпляра; nil - не пишется
+	Instances ports.InstanceRepository
+}
+
+// Registry - heartbeat фоновых компонентов экземпляра.
+type Registry struct {
+	logger          *slog.Logger
+	repo            ports.WorkerHeartbeatRepository // nil - только этот экземпляр
+	instances       ports.InstanceRepository        // nil - liveness экземпляра не пишется
+	instance        string
+	persistInterval time.Duration
+	clusterWindow   time.Duration
+	now             func() time.Time
+
+	mu      sync.RWMutex
+	workers []*Worker
+	byName  map[string]*Worker
+
+	lifecycle sync.Mutex
+	started   bool
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewRegistry создаёт реестр. repo может быть nil - heartbeat не
+// сохраняются, Workers возвращает только этот экземпляр.
+func NewRegistry(logger *slog.Logger, repo ports.WorkerHeartbeatRepository, cfg Config) *Registry {
+	if cfg.Instance == "" {
+		host, _ := os.Hostname()
+		cfg.Instance = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if cfg.PersistInterval <= 0 {
+		cfg.PersistInterval = DefaultPersistInterval
+	}
+	if cfg.ClusterWindow <= 0 {
+		cfg.ClusterWindow = DefaultClusterWindow
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Registry{
+		logger:          logger,
+		repo:            repo,
+		instances:       cfg.Instances,
+		instance:        cfg.Instance,
+		persistInterval: cfg.PersistInterval,
+		clusterWindow:   cfg.ClusterWindow,
+		now:             func() time.Time { return time.Now().UTC() },
+		byName:          make(map[string]*Worker),
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+// Register добавляет компонент с порогом устаревания heartbeat
+// (0 - не устаревает). Повторная регистрация имени возвращает уже
+// зарегистрированный Worker с новым порогом.
+func (r *Registry) Register(name string, staleAfter time.Duration) *Worker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if w, ok := r.byName[name]; ok {
+		w.mu.Lock()
+		w.status.StaleAfter = staleAfter
+		w.mu.Unlock()
+		return w
+	}
+
+	w := &Worker{
+		now: r.now,
+		status: ports.WorkerStatus{
+			Worker:          name,
+			Instance:        r.instance,
+			State:           ports.WorkerIdle,
+			LastHeartbeatAt: r.now(),
+			StaleAfter:      staleAfter,
+		},
+	}
+	r.workers = append(r.workers, w)
+	r.byName[name] = w
+	return w
+}
+
+// Instance - идентификатор экземпляра.
+func (r *Registry) Instance() string {
+	return r.instance
+}
+
+// Local возвращает heartbeat компонентов экземпляра в порядке регистрации.
+func (r *Registry) Local() []ports.WorkerStatus {
+	r.mu.RLock()
+	workers := r.workers
+	r.mu.RUnlock()
+
+	statuses := make([]ports.WorkerStatus, len(workers))
+	for i, w := range workers {
+		statuses[i] = w.Status()
+	}
+	return statuses
+}
+
+// Workers возвращает heartbeat этого экземпляра и, если подключено
+// хранилище, остальных экземпляров (по worker, затем instance).
+func (r *Registry) Workers(ctx context.Context) ([]ports.WorkerStatus, error) {
+	statuses := r.Local()
+	if r.repo == nil {
+		return statuses, nil
+	}
+
+	stored, err := r.repo.ListSince(ctx, r.now().Add(-r.clusterWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worker heartbeats: %w", err)
+	}
+	for _, s := range stored {
+		if s.Instance != r.instance {
+			statuses = append(statuses, s)
+		}
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		if statuses[i].Worker != statuses[j].Worker {
+			return statuses[i].Worker < statuses[j].Worker
+		}
+		return statuses[i].Instance < statuses[j].Instance
+	})
+	return statuses, nil
+}
+
+// Start запускает периодическую запись heartbeat. Без хранилищ - no-op.
+func (r *Registry) Start() {
+	r.lifecycle.Lock()
+	defer r.lifecycle.Unlock()
+
+	if (r.repo == nil && r.instances == nil) || r.started || r.ctx.Err() != nil {
+		return
+	}
+	r.started = true
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.run()
+	}()
+}
+
+// Stop останавливает запись и сохраняет последнее состояние компонентов.
+func (r *Registry) Stop(ctx context.Context) error {
+	r.lifecycle.Lock()
+	r.cancel()
+	started := r.started
+	r.lifecycle.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("worker registry stop interrupted: %w", ctx.Err())
+	}
+
+	if started {
+		return r.persist(ctx)
+	}
+	return nil
+}
+
+func (r *Registry) run() {
+	ticker := time.NewTicker(r.persistInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(r.ctx, storeTimeout)
+		if err := r.persist(ctx); err != nil && r.ctx.Err() == nil {
+			r.logger.Warn("Failed to persist worker heartbeats", slog.String("error", err.Error()))
+		}
+		cancel()
+	}
+}
+
+func (r *Registry) persist(ctx context.Context) error {
+	if r.instances != nil {
+		if err := r.instances.Heartbeat(ctx, r.instance, r.now()); err != nil {
+			return err
+		}
+	}
+
+	statuses := r.Local()
+	if r.repo == nil || len(statuses) == 0 {
+		return nil
+	}
+	return r.repo.Save(ctx, statuses)
+}
+
+// ============================================
+// Worker
+// ============================================
+
+// Worker - heartbeat одного компонента. Методы nil *Worker - no-op,
+// поэтому компонент может хранить его без проверок.
+type Worker struct {
+	now func() time.Time
+
+	mu     sync.Mutex
+	status ports.WorkerStatus
+}
+
+// RunStarted отмечает начало прогона.
+func (w *Worker) RunStarted() {
+	if w == nil {
+		return
+	}
+	now := w.now()
+
+	w.mu.Lock()
+	w.status.State = ports.WorkerRunning
+	w.status.LastRunAt = &now
+	w.status.LastHeartbeatAt = now
+	w.mu.Unlock()
+}
+
+// RunFinished отмечает окончание прогона и его итог.
+func (w *Worker) RunFinished(itemsProcessed int, err error) {
+	if w == nil {
+		return
+	}
+	now := w.now()
+
+	w.mu.Lock()
+	w.status.State = ports.WorkerIdle
+	w.status.ItemsProcessed = itemsProcessed
+	w.status.LastHeartbeatAt = now
+	if err != nil {
+		w.status.LastError = err.Error()
+	} else {
+		w.status.LastError = ""
+		w.status.LastSuccessAt = &now
+	}
+	w.mu.Unlock()
+}
+
+// Beat отмечает, что компонент жив, не меняя итог последнего прогона.
+func (w *Worker) Beat() {
+	if w == nil {
+		return
+	}
+	now := w.now()
+
+	w.mu.Lock()
+	if w.status.State == ports.WorkerStopped {
+		w.status.State = ports.WorkerIdle
+	}
+	w.status.LastHeartbeatAt = now
+	w.mu.Unlock()
+}
+
+// Stopped отмечает остановку компонента.
+func (w *Worker) Stopped() {
+	if w == nil {
+		return
+	}
+	now := w.now()
+
+	w.mu.Lock()
+	w.status.State = ports.WorkerStopped
+	w.status.LastHeartbeatAt = now
+	w.mu.Unlock()
+}
+
+// Status возвращает копию последнего heartbeat.
+func (w *Worker) Status() ports.WorkerStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	status := w.status
+	if status.LastRunAt != nil {
+		t := *status.LastRunAt
+		status.LastRunAt = &t
+	}
+	if status.LastSuccessAt != nil {
+		t := *status.LastSuccessAt
+		status.LastSuccessAt = &t
+	}
+	return status
+}
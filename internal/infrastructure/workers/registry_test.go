@@ -0,0 +1,284 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/Haleralex/wallethub/internal/application/ports"
+)
+
+// Все тесты пакета проверяются на утечку горутин.
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// fakeClock - управляемое время реестра.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func newTestRegistry(repo ports.WorkerHeartbeatRepository) (*Registry, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	r := NewRegistry(discardLogger(), repo, Config{Instance: "instance-a", PersistInterval: 10 * time.Millisecond})
+	r.now = clock.Now
+	return r, clock
+}
+
+// fakeHeartbeatRepo - workers_heartbeat в памяти.
+type fakeHeartbeatRepo struct {
+	mu    sync.Mutex
+	rows  map[string]ports.WorkerStatus
+	saves int
+}
+
+func newFakeHeartbeatRepo(rows ...ports.WorkerStatus) *fakeHeartbeatRepo {
+	repo := &fakeHeartbeatRepo{rows: make(map[string]ports.WorkerStatus)}
+	for _, row := range rows {
+		repo.rows[row.Worker+"/"+row.Instance] = row
+	}
+	return repo
+}
+
+func (r *fakeHeartbeatRepo) Save(_ context.Context, statuses []ports.WorkerStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range statuses {
+		r.rows[s.Worker+"/"+s.Instance] = s
+	}
+	r.saves++
+	return nil
+}
+
+func (r *fakeHeartbeatRepo) ListSince(_ context.Context, since time.Time) ([]ports.WorkerStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []ports.WorkerStatus
+	for _, s := range r.rows {
+		if !s.LastHeartbeatAt.Before(since) {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeHeartbeatRepo) Saves() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.saves
+}
+
+func TestWorker_RunLifecycle(t *testing.T) {
+	r, clock := newTestRegistry(nil)
+	w := r.Register("outbox-relay", time.Minute)
+
+	w.RunStarted()
+	status := w.Status()
+	assert.Equal(t, ports.WorkerRunning, status.State)
+	require.NotNil(t, status.LastRunAt)
+	assert.Nil(t, status.LastSuccessAt)
+
+	clock.Advance(time.Second)
+	w.RunFinished(7, nil)
+	status = w.Status()
+	assert.Equal(t, ports.WorkerIdle, status.State)
+	assert.Equal(t, 7, status.ItemsProcessed)
+	require.NotNil(t, status.LastSuccessAt)
+	assert.Equal(t, clock.Now(), *status.LastSuccessAt)
+
+	clock.Advance(time.Second)
+	w.RunStarted()
+	w.RunFinished(0, errors.New("nats unavailable"))
+	status = w.Status()
+	assert.Equal(t, "nats unavailable", status.LastError)
+	assert.Equal(t, clock.Now().Add(-time.Second), *status.LastSuccessAt, "failed run keeps last success")
+
+	w.Stopped()
+	assert.Equal(t, ports.WorkerStopped, w.Status().State)
+}
+
+func TestWorker_NilIsNoop(t *testing.T) {
+	var w *Worker
+	assert.NotPanics(t, func() {
+		w.RunStarted()
+		w.RunFinished(1, nil)
+		w.Beat()
+		w.Stopped()
+	})
+}
+
+func TestRegistry_StaleDetection(t *testing.T) {
+	r, clock := newTestRegistry(nil)
+	relay := r.Register("outbox-relay", 30*time.Second)
+	stopped := r.Register("balance-summary", 30*time.Second)
+	r.Register("outbox-cleanup", 0)
+
+	relay.RunStarted()
+	relay.RunFinished(3, nil)
+	stopped.Stopped()
+
+	clock.Advance(20 * time.Second)
+	for _, s := range r.Local() {
+		assert.False(t, s.Stale(clock.Now()), s.Worker)
+	}
+
+	clock.Advance(20 * time.Second)
+	stale := map[string]bool{}
+	for _, s := range r.Local() {
+		stale[s.Worker] = s.Stale(clock.Now())
+	}
+	assert.True(t, stale["outbox-relay"], "no heartbeat beyond threshold")
+	assert.False(t, stale["balance-summary"], "stopped worker is not stale")
+	assert.False(t, stale["outbox-cleanup"], "zero threshold never goes stale")
+
+	// Пропуск прогона (задачу выполняет другой экземпляр) - тоже heartbeat
+	relay.Beat()
+	assert.False(t, relay.Status().Stale(clock.Now()))
+	assert.Equal(t, 3, relay.Status().ItemsProcessed)
+}
+
+func TestRegistry_RegisterReturnsExistingWorker(t *testing.T) {
+	r, _ := newTestRegistry(nil)
+
+	first := r.Register("outbox-relay", time.Minute)
+	second := r.Register("outbox-relay", 2*time.Minute)
+
+	assert.Same(t, first, second)
+	require.Len(t, r.Local(), 1)
+	assert.Equal(t, 2*time.Minute, r.Local()[0].StaleAfter)
+}
+
+func TestRegistry_WorkersIncludesOtherInstances(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := newFakeHeartbeatRepo(
+		ports.WorkerStatus{Worker: "outbox-relay", Instance: "notifier-1", State: ports.WorkerIdle, LastHeartbeatAt: now.Add(-time.Minute), StaleAfter: 10 * time.Second},
+		// Собственная строка из прошлого сохранения заменяется состоянием из памяти
+		ports.WorkerStatus{Worker: "balance-summary", Instance: "instance-a", State: ports.WorkerStopped, LastHeartbeatAt: now.Add(-time.Hour)},
+		// Давно остановленный экземпляр вне окна кластера
+		ports.WorkerStatus{Worker: "balance-summary", Instance: "instance-gone", State: ports.WorkerIdle, LastHeartbeatAt: now.Add(-48 * time.Hour)},
+	)
+	r, _ := newTestRegistry(repo)
+	r.Register("balance-summary", time.Minute).RunFinished(2, nil)
+
+	statuses, err := r.Workers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+
+	assert.Equal(t, "balance-summary", statuses[0].Worker)
+	assert.Equal(t, "instance-a", statuses[0].Instance)
+	assert.Equal(t, ports.WorkerIdle, statuses[0].State)
+	assert.Equal(t, "outbox-relay", statuses[1].Worker)
+	assert.Equal(t, "notifier-1", statuses[1].Instance)
+	assert.True(t, statuses[1].Stale(now))
+}
+
+func TestRegistry_PersistsUntilStop(t *testing.T) {
+	repo := newFakeHeartbeatRepo()
+	r, _ := newTestRegistry(repo)
+	w := r.Register("outbox-relay", time.Minute)
+	r.Start()
+
+	require.Eventually(t, func() bool { return repo.Saves() > 0 }, 5*time.Second, 5*time.Millisecond)
+
+	w.Stopped()
+	require.NoError(t, r.Stop(context.Background()))
+
+	rows, err := repo.ListSince(context.Background(), time.Time{})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, ports.WorkerStopped, rows[0].State, "final state saved on Stop")
+}
+
+// fakeInstanceRepo - instances в памяти.
+type fakeInstanceRepo struct {
+	mu         sync.Mutex
+	heartbeats map[string]time.Time
+}
+
+func (r *fakeInstanceRepo) Heartbeat(_ context.Context, instance string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.heartbeats == nil {
+		r.heartbeats = make(map[string]time.Time)
+	}
+	r.heartbeats[instance] = at
+	return nil
+}
+
+func (r *fakeInstanceRepo) Last(instance string) (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	at, ok := r.heartbeats[instance]
+	return at, ok
+}
+
+func TestRegistry_HeartbeatsInstanceWithoutWorkers(t *testing.T) {
+	instances := &fakeInstanceRepo{}
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	r := NewRegistry(discardLogger(), nil, Config{
+		Instance: "instance-a", PersistInterval: 10 * time.Millisecond, Instances: instances,
+	})
+	r.now = clock.Now
+	r.Start()
+
+	// Экземпляр жив, даже если фоновых компонентов нет и workers_heartbeat не пишется
+	require.Eventually(t, func() bool {
+		_, ok := instances.Last("instance-a")
+		return ok
+	}, 5*time.Second, 5*time.Millisecond)
+
+	clock.Advance(time.Minute)
+	require.Eventually(t, func() bool {
+		at, _ := instances.Last("instance-a")
+		return at.Equal(clock.Now())
+	}, 5*time.Second, 5*time.Millisecond)
+
+	require.NoError(t, r.Stop(context.Background()))
+}
+
+func TestRegistry_ConcurrentHeartbeats(t *testing.T) {
+	r := NewRegistry(discardLogger(), nil, Config{Instance: "instance-a"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := r.Register("outbox-relay", time.Minute)
+			for j := 0; j < 1000; j++ {
+				w.RunStarted()
+				w.RunFinished(j, nil)
+				_ = r.Local()
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, r.Local(), 1)
+	assert.Equal(t, ports.WorkerIdle, r.Local()[0].State)
+}
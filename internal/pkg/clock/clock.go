@@ -0,0 +1,83 @@
+// Package clock supplies the current time to domain entities and events.
+//
+// Entities stamp CreatedAt/UpdatedAt and events stamp OccurredAt through
+// Now instead of time.Now, so tests can freeze or step time (SetClock,
+// Fake) instead of sleeping between operations.
+//
+// Wall-clock time is for display and retention only. Two operations within
+// the same clock tick get equal or nanosecond-apart timestamps, so ordering
+// of changes of one wallet uses the balance version, never timestamps.
+package clock
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock returns the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the wall clock. It is the default.
+type System struct{}
+
+// Now returns time.Now in UTC.
+func (System) Now() time.Time {
+	return time.Now().UTC()
+}
+
+var current atomic.Pointer[Clock]
+
+func init() {
+	var c Clock = System{}
+	current.Store(&c)
+}
+
+// SetClock replaces the clock for the whole process and returns a function
+// that restores the previous one. Intended for tests:
+//
+//	defer clock.SetClock(clock.NewFake(time.Unix(0, 0)))()
+func SetClock(c Clock) (restore func()) {
+	previous := current.Swap(&c)
+	return func() { current.Store(previous) }
+}
+
+// Now returns the current time of the process clock in UTC.
+func Now() time.Time {
+	return (*current.Load()).Now().UTC()
+}
+
+// Fake is a manually controlled clock for tests. It only moves on Advance
+// or Set. Safe for concurrent use.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake returns a Fake clock stopped at start.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start.UTC()}
+}
+
+// Now returns the current fake time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance moves the clock forward by d (backwards if d is negative).
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// Set moves the clock to t.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = t.UTC()
+}
@@ -0,0 +1,45 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetClock_RestoresPrevious(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+
+	restore := SetClock(fake)
+	if got := Now(); !got.Equal(start) {
+		t.Fatalf("Now() = %v, want %v", got, start)
+	}
+
+	fake.Advance(time.Second)
+	if got := Now(); !got.Equal(start.Add(time.Second)) {
+		t.Errorf("Now() after Advance = %v, want %v", got, start.Add(time.Second))
+	}
+
+	restore()
+	if _, ok := (*current.Load()).(System); !ok {
+		t.Error("restore must bring back the system clock")
+	}
+}
+
+func TestNow_UTC(t *testing.T) {
+	local := time.FixedZone("UTC+3", 3*60*60)
+	defer SetClock(NewFake(time.Date(2026, 1, 1, 3, 0, 0, 0, local)))()
+
+	if got := Now(); got.Location() != time.UTC {
+		t.Errorf("Now() location = %v, want UTC", got.Location())
+	}
+}
+
+func TestFake_Set(t *testing.T) {
+	fake := NewFake(time.Unix(0, 0))
+	target := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	fake.Set(target)
+	if got := fake.Now(); !got.Equal(target) {
+		t.Errorf("Now() = %v, want %v", got, target)
+	}
+}
@@ -0,0 +1,66 @@
+// Package csvsafe writes CSV that is safe to open in spreadsheet programs.
+//
+// A cell starting with =, +, -, @, a tab or a carriage return is evaluated
+// as a formula by Excel, LibreOffice and Google Sheets (CSV/formula
+// injection): a transaction description such as =HYPERLINK(...) would run
+// on the analyst's machine. Escape prefixes such cells with a single quote,
+// which spreadsheets display as text and do not evaluate. Values written by
+// the service itself (amounts like -10.00) must stay numeric and go through
+// Writer.WriteRaw instead.
+package csvsafe
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// Escape neutralizes a user-controlled cell value.
+func Escape(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	}
+	return value
+}
+
+// Writer is an encoding/csv Writer that escapes user-controlled cells.
+type Writer struct {
+	w *csv.Writer
+}
+
+// NewWriter returns a Writer that writes comma-separated records to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: csv.NewWriter(w)}
+}
+
+// Write writes one record, escaping every cell with Escape.
+func (w *Writer) Write(record []string) error {
+	escaped := make([]string, len(record))
+	for i, value := range record {
+		escaped[i] = Escape(value)
+	}
+	return w.w.Write(escaped)
+}
+
+// WriteRaw writes one record as is. Only for cells produced by the service
+// (headers, amounts, IDs, timestamps).
+func (w *Writer) WriteRaw(record []string) error {
+	return w.w.Write(record)
+}
+
+// Comment writes a trailing "# ..." line, e.g. a truncation notice. The text
+// is a single cell so that CSV readers keep the column count of the line
+// predictable.
+func (w *Writer) Comment(text string) error {
+	return w.w.Write([]string{"# " + strings.ReplaceAll(text, "\n", " ")})
+}
+
+// Flush writes buffered records to the underlying io.Writer.
+func (w *Writer) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
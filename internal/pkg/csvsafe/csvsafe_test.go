@@ -0,0 +1,51 @@
+package csvsafe
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := map[string]string{
+		"":                  "",
+		"coffee":            "coffee",
+		"=HYPERLINK(\"x\")": "'=HYPERLINK(\"x\")",
+		"+1":                "'+1",
+		"-2+3":              "'-2+3",
+		"@SUM(A1:A2)":       "'@SUM(A1:A2)",
+		"\tcmd":             "'\tcmd",
+		"\rcmd":             "'\rcmd",
+		"a=b":               "a=b",
+		"'already quoted":   "'already quoted",
+	}
+	for in, want := range tests {
+		if got := Escape(in); got != want {
+			t.Errorf("Escape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := w.WriteRaw([]string{"amount", "description"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRaw([]string{"-10.00"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write([]string{"=1+1", "a,b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Comment("truncated\nafter 2 rows"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "amount,description\n-10.00\n'=1+1,\"a,b\"\n# truncated after 2 rows\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,65 @@
+package ids
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDefaultGenerator_IsV7(t *testing.T) {
+	for _, id := range []uuid.UUID{NewTransactionID(), NewEventID()} {
+		if id.Version() != 7 || id.Variant() != uuid.RFC4122 {
+			t.Errorf("id %s: version %d, variant %s, want RFC 4122 v7", id, id.Version(), id.Variant())
+		}
+	}
+	if NewTransactionID() == NewTransactionID() {
+		t.Error("consecutive IDs must differ")
+	}
+}
+
+func TestSequence_Deterministic(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	first, second := Sequence(start), Sequence(start)
+
+	var previous uuid.UUID
+	for i := 0; i < 3; i++ {
+		id := first()
+		if id != second() {
+			t.Fatalf("step %d: sequences with the same start differ", i)
+		}
+		if id.Version() != 7 || id.Variant() != uuid.RFC4122 {
+			t.Errorf("id %s: version %d, variant %s, want RFC 4122 v7", id, id.Version(), id.Variant())
+		}
+		if bytes.Compare(id[:], previous[:]) <= 0 {
+			t.Errorf("id %s does not sort after %s", id, previous)
+		}
+		if parsed, err := uuid.Parse(id.String()); err != nil || parsed != id {
+			t.Errorf("uuid.Parse(%s) = %s, %v", id, parsed, err)
+		}
+		previous = id
+	}
+
+	sec, _ := first().Time().UnixTime()
+	if sec != start.Unix() {
+		t.Errorf("timestamp = %d, want %d", sec, start.Unix())
+	}
+}
+
+func TestSetGenerator_Restore(t *testing.T) {
+	fixed := uuid.MustParse("0190a6c8-0000-7000-8000-000000000001")
+	restore := SetGenerator(func() uuid.UUID { return fixed })
+
+	if got := NewTransactionID(); got != fixed {
+		t.Errorf("NewTransactionID() = %s, want injected %s", got, fixed)
+	}
+	if got := NewEventID(); got != fixed {
+		t.Errorf("NewEventID() = %s, want injected %s", got, fixed)
+	}
+
+	restore()
+	if got := NewTransactionID(); got == fixed || got.Version() != 7 {
+		t.Errorf("NewTransactionID() after restore = %s, want generated v7", got)
+	}
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// Stable field names. Log retention indexes and alert queries are built on
+// these keys, so an existing name must never change: add a new field instead.
+// Use the typed helpers below rather than ad-hoc slog.String calls so the
+// same value is always logged under the same key in the same format.
+const (
+	FieldWalletID            = "wallet_id"
+	FieldDestinationWalletID = "destination_wallet_id"
+	FieldTransactionID       = "transaction_id"
+	FieldUserID              = "user_id"
+	FieldAmount              = "amount"
+	FieldComponent           = "component"
+	FieldError               = "error"
+	FieldSuppressed          = "suppressed"
+)
+
+// WalletID returns the wallet_id field.
+func WalletID(id uuid.UUID) slog.Attr {
+	return slog.Any(FieldWalletID, lazyString{id})
+}
+
+// DestinationWalletID returns the destination_wallet_id field of a transfer.
+func DestinationWalletID(id uuid.UUID) slog.Attr {
+	return slog.Any(FieldDestinationWalletID, lazyString{id})
+}
+
+// TransactionID returns the transaction_id field.
+func TransactionID(id uuid.UUID) slog.Attr {
+	return slog.Any(FieldTransactionID, lazyString{id})
+}
+
+// UserID returns the user_id field.
+func UserID(id uuid.UUID) slog.Attr {
+	return slog.Any(FieldUserID, lazyString{id})
+}
+
+// Amount returns the amount field formatted by the value's String method,
+// e.g. "100.50 USD" for valueobjects.Money.
+func Amount(m fmt.Stringer) slog.Attr {
+	return slog.Any(FieldAmount, lazyString{m})
+}
+
+// Component returns the component field.
+func Component(name string) slog.Attr {
+	return slog.String(FieldComponent, name)
+}
+
+// Err returns the error field. A nil error yields an empty attribute,
+// which handlers omit.
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+	return slog.String(FieldError, err.Error())
+}
+
+// lazyString defers String until a handler actually writes the record, so
+// disabled debug logs on hot paths do not format IDs and amounts.
+type lazyString struct {
+	v fmt.Stringer
+}
+
+// LogValue implements slog.LogValuer.
+func (s lazyString) LogValue() slog.Value {
+	return slog.StringValue(s.v.String())
+}
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type testMoney struct{}
+
+func (testMoney) String() string { return "100.50 USD" }
+
+// newGoldenLogger returns a JSON logger without timestamps so records can
+// be compared byte for byte.
+func newGoldenLogger(buf *bytes.Buffer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+// TestFields_GoldenLines fixes the field names and value formats: changing
+// them breaks retention queries, so a failure here is a breaking change.
+func TestFields_GoldenLines(t *testing.T) {
+	walletID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	transactionID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	userID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+
+	tests := []struct {
+		name   string
+		attrs  []slog.Attr
+		golden string
+	}{
+		{
+			name:   "MoneyPath",
+			attrs:  []slog.Attr{WalletID(walletID), DestinationWalletID(walletID), TransactionID(transactionID), Amount(testMoney{})},
+			golden: `{"level":"INFO","msg":"event","wallet_id":"11111111-1111-1111-1111-111111111111","destination_wallet_id":"11111111-1111-1111-1111-111111111111","transaction_id":"22222222-2222-2222-2222-222222222222","amount":"100.50 USD"}`,
+		},
+		{
+			name:   "UserAndComponent",
+			attrs:  []slog.Attr{UserID(userID), Component("transfer")},
+			golden: `{"level":"INFO","msg":"event","user_id":"33333333-3333-3333-3333-333333333333","component":"transfer"}`,
+		},
+		{
+			name:   "Error",
+			attrs:  []slog.Attr{Err(errors.New("insufficient funds"))},
+			golden: `{"level":"INFO","msg":"event","error":"insufficient funds"}`,
+		},
+		{
+			name:   "NilErrorOmitted",
+			attrs:  []slog.Attr{Err(nil)},
+			golden: `{"level":"INFO","msg":"event"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			newGoldenLogger(&buf, slog.LevelInfo).LogAttrs(context.Background(), slog.LevelInfo, "event", tt.attrs...)
+			assert.Equal(t, tt.golden+"\n", buf.String())
+		})
+	}
+}
+
+type countingStringer struct{ calls *int }
+
+func (s countingStringer) String() string {
+	*s.calls++
+	return "1.00 USD"
+}
+
+func TestFields_LazyFormatting(t *testing.T) {
+	var buf bytes.Buffer
+	calls := 0
+
+	newGoldenLogger(&buf, slog.LevelInfo).Debug("skipped", Amount(countingStringer{&calls}))
+
+	assert.Zero(t, calls, "disabled records must not format values")
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+)
+
+// ParseLevel parses debug, info, warn (warning) or error, case-insensitive.
+// An empty string is info.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", s)
+	}
+}
+
+// Levels holds the default log level and per-component overrides. Set
+// replaces both atomically, so levels can be reloaded while loggers are in
+// use.
+type Levels struct {
+	state atomic.Pointer[levelState]
+}
+
+type levelState struct {
+	def        slog.Level
+	components map[string]slog.Level
+}
+
+// NewLevels creates Levels from a default level and component overrides
+// (component name -> level).
+func NewLevels(def string, components map[string]string) (*Levels, error) {
+	l := &Levels{}
+	if err := l.Set(def, components); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Set replaces the levels. On error the previous levels are kept.
+func (l *Levels) Set(def string, components map[string]string) error {
+	state := &levelState{components: make(map[string]slog.Level, len(components))}
+
+	var err error
+	if state.def, err = ParseLevel(def); err != nil {
+		return err
+	}
+	for name, s := range components {
+		level, err := ParseLevel(s)
+		if err != nil {
+			return fmt.Errorf("component %s: %w", name, err)
+		}
+		state.components[name] = level
+	}
+
+	l.state.Store(state)
+	return nil
+}
+
+// Level returns the level of component, or the default level if the
+// component has no override.
+func (l *Levels) Level(component string) slog.Level {
+	state := l.state.Load()
+	if level, ok := state.components[component]; ok {
+		return level
+	}
+	return state.def
+}
+
+// LeveledHandler filters records by the level of its component. The
+// wrapped handler should accept every level (slog.LevelDebug): filtering
+// is done here.
+type LeveledHandler struct {
+	next      slog.Handler
+	levels    *Levels
+	component string
+}
+
+// NewLeveledHandler wraps next with the default level of levels. Use
+// WithComponent to derive component loggers.
+func NewLeveledHandler(next slog.Handler, levels *Levels) *LeveledHandler {
+	return &LeveledHandler{next: next, levels: levels}
+}
+
+// Enabled reports whether level is at or above the component level.
+func (h *LeveledHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.levels.Level(h.component) && h.next.Enabled(ctx, level)
+}
+
+// Handle passes the record to the wrapped handler.
+func (h *LeveledHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler with the given attributes.
+func (h *LeveledHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LeveledHandler{next: h.next.WithAttrs(attrs), levels: h.levels, component: h.component}
+}
+
+// WithGroup returns a handler with the given group.
+func (h *LeveledHandler) WithGroup(name string) slog.Handler {
+	return &LeveledHandler{next: h.next.WithGroup(name), levels: h.levels, component: h.component}
+}
+
+// WithComponent returns a logger that adds the component field and, if l
+// is backed by a LeveledHandler, uses the level configured for the
+// component (log.components). A nil l stays nil.
+func WithComponent(l *slog.Logger, name string) *slog.Logger {
+	if l == nil {
+		return nil
+	}
+
+	l = l.With(Component(name))
+	if h, ok := l.Handler().(*LeveledHandler); ok {
+		return slog.New(&LeveledHandler{next: h.next, levels: h.levels, component: name})
+	}
+	return l
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"debug", slog.LevelDebug, false},
+		{"", slog.LevelInfo, false},
+		{"INFO", slog.LevelInfo, false},
+		{"warning", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", slog.LevelInfo, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLevel(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLeveledHandler_ComponentLevels(t *testing.T) {
+	levels, err := NewLevels("info", map[string]string{"transfer": "debug", "outbox": "error"})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	root := slog.New(NewLeveledHandler(newGoldenLogger(&buf, slog.LevelDebug).Handler(), levels))
+	transfer := WithComponent(root, "transfer")
+	outbox := WithComponent(root, "outbox")
+
+	root.Debug("root debug")
+	transfer.Debug("transfer debug")
+	outbox.Warn("outbox warn")
+	outbox.Error("outbox error")
+
+	assert.Equal(t,
+		`{"level":"DEBUG","msg":"transfer debug","component":"transfer"}`+"\n"+
+			`{"level":"ERROR","msg":"outbox error","component":"outbox"}`+"\n",
+		buf.String())
+}
+
+func TestLevels_SetReloads(t *testing.T) {
+	levels, err := NewLevels("info", nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	transfer := WithComponent(slog.New(NewLeveledHandler(newGoldenLogger(&buf, slog.LevelDebug).Handler(), levels)), "transfer")
+
+	transfer.Debug("before reload")
+	require.NoError(t, levels.Set("info", map[string]string{"transfer": "debug"}))
+	transfer.Debug("after reload")
+
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+	assert.Contains(t, buf.String(), "after reload")
+
+	t.Run("InvalidKeepsPrevious", func(t *testing.T) {
+		assert.Error(t, levels.Set("info", map[string]string{"transfer": "loud"}))
+		assert.Equal(t, slog.LevelDebug, levels.Level("transfer"))
+	})
+}
+
+func TestWithComponent_PlainHandler(t *testing.T) {
+	var buf bytes.Buffer
+	WithComponent(newGoldenLogger(&buf, slog.LevelInfo), "fraud").Info("checked")
+
+	assert.Equal(t, `{"level":"INFO","msg":"checked","component":"fraud"}`+"\n", buf.String())
+	assert.Nil(t, WithComponent(nil, "fraud"))
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Sampler thins out high-volume log lines: of each message it writes the
+// 1st, (N+1)th, (2N+1)th... occurrence. Every written record carries the
+// suppressed field with the number of occurrences dropped since the
+// previous one, so totals can still be reconstructed from retained logs.
+//
+// Occurrences are counted per message, so messages should be constant
+// strings with variable data in attributes. Records below the logger's
+// level are neither written nor counted. A nil Sampler writes every record.
+type Sampler struct {
+	every uint64
+
+	mu      sync.Mutex
+	counts  map[string]uint64
+	dropped map[string]uint64
+}
+
+// NewSampler creates a sampler that writes every Nth occurrence of a
+// message. every <= 1 writes all of them.
+func NewSampler(every int) *Sampler {
+	return &Sampler{
+		every:   uint64(max(every, 1)),
+		counts:  make(map[string]uint64),
+		dropped: make(map[string]uint64),
+	}
+}
+
+// Log writes the record if this occurrence of msg is sampled.
+func (s *Sampler) Log(ctx context.Context, l *slog.Logger, level slog.Level, msg string, attrs ...slog.Attr) {
+	if l == nil || !l.Enabled(ctx, level) {
+		return
+	}
+
+	suppressed, ok := s.sample(msg)
+	if !ok {
+		return
+	}
+
+	// Report the caller of Log as the source, not the Sampler
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.AddAttrs(attrs...)
+	r.AddAttrs(slog.Uint64(FieldSuppressed, suppressed))
+	_ = l.Handler().Handle(ctx, r)
+}
+
+// sample counts an occurrence of msg and reports whether to write it and
+// how many occurrences were dropped before it.
+func (s *Sampler) sample(msg string) (uint64, bool) {
+	if s == nil {
+		return 0, true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.counts[msg]
+	s.counts[msg] = n + 1
+	if n%s.every != 0 {
+		s.dropped[msg]++
+		return 0, false
+	}
+
+	suppressed := s.dropped[msg]
+	s.dropped[msg] = 0
+	return suppressed, true
+}
@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampledRecords decodes the suppressed field of each written record.
+func sampledRecords(t *testing.T, buf *bytes.Buffer) []uint64 {
+	t.Helper()
+
+	var suppressed []uint64
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		suppressed = append(suppressed, uint64(record[FieldSuppressed].(float64)))
+	}
+	return suppressed
+}
+
+func TestSampler_EveryNth(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	logger := newGoldenLogger(&buf, slog.LevelDebug)
+	sampler := NewSampler(3)
+
+	for range 10 {
+		sampler.Log(ctx, logger, slog.LevelDebug, "transfer completed")
+	}
+
+	// Occurrences 1, 4, 7 and 10 are written
+	assert.Equal(t, []uint64{0, 2, 2, 2}, sampledRecords(t, &buf))
+	assert.True(t, strings.HasPrefix(buf.String(),
+		`{"level":"DEBUG","msg":"transfer completed","suppressed":0}`))
+}
+
+func TestSampler_PerMessage(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	logger := newGoldenLogger(&buf, slog.LevelDebug)
+	sampler := NewSampler(2)
+
+	sampler.Log(ctx, logger, slog.LevelDebug, "a")
+	sampler.Log(ctx, logger, slog.LevelDebug, "b")
+	sampler.Log(ctx, logger, slog.LevelDebug, "a")
+	sampler.Log(ctx, logger, slog.LevelDebug, "a")
+
+	assert.Equal(t, []uint64{0, 0, 1}, sampledRecords(t, &buf))
+}
+
+func TestSampler_DisabledLevelNotCounted(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	logger := newGoldenLogger(&buf, slog.LevelInfo)
+	sampler := NewSampler(3)
+
+	for range 5 {
+		sampler.Log(ctx, logger, slog.LevelDebug, "hot path")
+	}
+	sampler.Log(ctx, logger, slog.LevelInfo, "hot path")
+
+	assert.Equal(t, []uint64{0}, sampledRecords(t, &buf))
+}
+
+func TestSampler_NilWritesAll(t *testing.T) {
+	var buf bytes.Buffer
+	var sampler *Sampler
+
+	for range 3 {
+		sampler.Log(context.Background(), newGoldenLogger(&buf, slog.LevelInfo), slog.LevelInfo, "event")
+	}
+
+	assert.Equal(t, []uint64{0, 0, 0}, sampledRecords(t, &buf))
+}
+
+func TestSampler_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	var buf syncBuffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	sampler := NewSampler(10)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			for range 100 {
+				sampler.Log(ctx, logger, slog.LevelDebug, "event")
+			}
+		})
+	}
+	wg.Wait()
+
+	suppressed := sampledRecords(t, &buf.buf)
+	total := uint64(len(suppressed))
+	for _, n := range suppressed {
+		total += n
+	}
+	assert.Len(t, suppressed, 100)
+	assert.Equal(t, uint64(1000-9), total, "the last 9 occurrences are pending")
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
@@ -0,0 +1,96 @@
+// Package statemachine declares entity status transitions as data.
+//
+// A Table lists, for every state, the states it may move to. Entities keep
+// their table next to the status type and consult it before every status
+// change, so the table is the single source of truth for what is legal:
+// a new operation that needs an unlisted transition fails loudly instead of
+// silently adding an edge through another if-statement.
+//
+// A transition of a state to itself is legal only when the table lists it
+// (re-applying an idempotent operation such as suspending a suspended wallet).
+// A state without outgoing transitions is final.
+package statemachine
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Table maps each state to the states it may transition to.
+type Table[S ~string] map[S][]S
+
+// Transition is one allowed edge of a table.
+type Transition[S ~string] struct {
+	From S `json:"from"`
+	To   S `json:"to"`
+}
+
+// InvalidTransitionError reports a transition the table does not allow.
+type InvalidTransitionError struct {
+	From string
+	To   string
+}
+
+// Error implements error.
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("invalid state transition from %s to %s", e.From, e.To)
+}
+
+// CanTransition reports whether the table allows from -> to.
+func (t Table[S]) CanTransition(from, to S) bool {
+	for _, target := range t[from] {
+		if target == to {
+			return true
+		}
+	}
+	return false
+}
+
+// MustTransition returns an *InvalidTransitionError naming both states when
+// the table does not allow from -> to, and nil otherwise.
+func (t Table[S]) MustTransition(from, to S) error {
+	if t.CanTransition(from, to) {
+		return nil
+	}
+	return &InvalidTransitionError{From: string(from), To: string(to)}
+}
+
+// Targets returns the states reachable from from in one step, in table order.
+func (t Table[S]) Targets(from S) []S {
+	return append([]S(nil), t[from]...)
+}
+
+// IsFinal reports whether no transition leaves the state.
+func (t Table[S]) IsFinal(state S) bool {
+	return len(t[state]) == 0
+}
+
+// States returns every state that appears in the table, sorted.
+func (t Table[S]) States() []S {
+	seen := make(map[S]struct{}, len(t))
+	for from, targets := range t {
+		seen[from] = struct{}{}
+		for _, to := range targets {
+			seen[to] = struct{}{}
+		}
+	}
+
+	states := make([]S, 0, len(seen))
+	for state := range seen {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i] < states[j] })
+	return states
+}
+
+// Transitions returns every allowed edge, ordered by source state and then
+// by table order of the targets.
+func (t Table[S]) Transitions() []Transition[S] {
+	var transitions []Transition[S]
+	for _, from := range t.States() {
+		for _, to := range t[from] {
+			transitions = append(transitions, Transition[S]{From: from, To: to})
+		}
+	}
+	return transitions
+}
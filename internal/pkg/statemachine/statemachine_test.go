@@ -0,0 +1,86 @@
+package statemachine
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type light string
+
+const (
+	red    light = "RED"
+	green  light = "GREEN"
+	yellow light = "YELLOW"
+	off    light = "OFF"
+)
+
+var lights = Table[light]{
+	red:    {green, off},
+	green:  {yellow, off},
+	yellow: {red, yellow, off},
+	off:    {},
+}
+
+func TestTable_CanTransition(t *testing.T) {
+	allowed := map[[2]light]bool{
+		{red, green}: true, {red, off}: true,
+		{green, yellow}: true, {green, off}: true,
+		{yellow, red}: true, {yellow, yellow}: true, {yellow, off}: true,
+	}
+
+	for _, from := range []light{red, green, yellow, off} {
+		for _, to := range []light{red, green, yellow, off} {
+			want := allowed[[2]light{from, to}]
+			if got := lights.CanTransition(from, to); got != want {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+
+	if lights.CanTransition("UNKNOWN", red) {
+		t.Error("CanTransition from a state missing in the table must be false")
+	}
+}
+
+func TestTable_MustTransition(t *testing.T) {
+	if err := lights.MustTransition(red, green); err != nil {
+		t.Fatalf("MustTransition(RED, GREEN) error = %v, want nil", err)
+	}
+
+	err := lights.MustTransition(off, red)
+	var invalid *InvalidTransitionError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("MustTransition(OFF, RED) error = %v, want *InvalidTransitionError", err)
+	}
+	if invalid.From != "OFF" || invalid.To != "RED" {
+		t.Errorf("error names %s -> %s, want OFF -> RED", invalid.From, invalid.To)
+	}
+	if err.Error() != "invalid state transition from OFF to RED" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestTable_Introspection(t *testing.T) {
+	if !reflect.DeepEqual(lights.States(), []light{green, off, red, yellow}) {
+		t.Errorf("States() = %v", lights.States())
+	}
+	if !lights.IsFinal(off) || lights.IsFinal(red) {
+		t.Error("only OFF is final")
+	}
+
+	targets := lights.Targets(red)
+	targets[0] = off
+	if lights[red][0] != green {
+		t.Error("Targets must return a copy")
+	}
+
+	want := []Transition[light]{
+		{green, yellow}, {green, off},
+		{red, green}, {red, off},
+		{yellow, red}, {yellow, yellow}, {yellow, off},
+	}
+	if got := lights.Transitions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Transitions() = %v, want %v", got, want)
+	}
+}
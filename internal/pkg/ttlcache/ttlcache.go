@@ -0,0 +1,78 @@
+// Package ttlcache is a small bounded in-memory cache whose entries expire
+// a fixed TTL after they were set. Expiry uses clock.Now(), so tests drive
+// it with clock.NewFake.
+//
+// The cache is meant for lookups that are cheap to repeat (owners, statuses):
+// when it is full, expired entries are dropped, and if none have expired the
+// whole cache is cleared instead of tracking recency.
+package ttlcache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+// Cache maps keys to values for ttl. It is safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[K]entry[V]
+}
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// New returns a cache holding at most maxEntries values for ttl each.
+// ttl <= 0 disables caching: Set is a no-op and Get always misses.
+func New[K comparable, V any](ttl time.Duration, maxEntries int) *Cache[K, V] {
+	return &Cache[K, V]{ttl: ttl, maxEntries: maxEntries, entries: make(map[K]entry[V])}
+}
+
+// Get returns the value for key unless it is missing or expired.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if !ok || !clock.Now().Before(e.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value for key until clock.Now() + ttl, replacing any previous value.
+func (c *Cache[K, V]) Set(key K, value V) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	now := clock.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			clear(c.entries)
+		}
+	}
+	c.entries[key] = entry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+// Len returns the number of stored entries, including expired ones not yet dropped.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
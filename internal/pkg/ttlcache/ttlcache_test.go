@@ -0,0 +1,76 @@
+package ttlcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/pkg/clock"
+)
+
+func TestCache_Expires(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer clock.SetClock(fake)()
+
+	c := New[string, int](time.Minute, 10)
+	c.Set("a", 1)
+
+	if got, ok := c.Get("a"); !ok || got != 1 {
+		t.Fatalf("Get(a) = %d, %v, want 1, true", got, ok)
+	}
+
+	fake.Advance(time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get(a) after ttl must miss")
+	}
+}
+
+func TestCache_ZeroTTLDisablesCaching(t *testing.T) {
+	c := New[string, int](0, 10)
+	c.Set("a", 1)
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get must miss when ttl is 0")
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", c.Len())
+	}
+}
+
+func TestCache_FullDropsExpiredFirst(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer clock.SetClock(fake)()
+
+	c := New[string, int](time.Minute, 2)
+	c.Set("old", 1)
+	fake.Advance(30 * time.Second)
+	c.Set("fresh", 2)
+	fake.Advance(30 * time.Second)
+
+	// "old" expired and makes room; "fresh" stays
+	c.Set("new", 3)
+	if c.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", c.Len())
+	}
+	if got, ok := c.Get("fresh"); !ok || got != 2 {
+		t.Errorf("Get(fresh) = %d, %v, want 2, true", got, ok)
+	}
+
+	// Nothing expired: the cache is cleared before the new entry
+	c.Set("newest", 4)
+	if c.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", c.Len())
+	}
+	if got, ok := c.Get("newest"); !ok || got != 4 {
+		t.Errorf("Get(newest) = %d, %v, want 4, true", got, ok)
+	}
+}
+
+func TestCache_ReplacingKeyDoesNotEvict(t *testing.T) {
+	c := New[string, int](time.Minute, 1)
+	c.Set("a", 1)
+	c.Set("a", 2)
+
+	if got, ok := c.Get("a"); !ok || got != 2 {
+		t.Errorf("Get(a) = %d, %v, want 2, true", got, ok)
+	}
+}
@@ -0,0 +1,6 @@
+-- Remove jurisdiction retention tags
+DROP INDEX IF EXISTS idx_transactions_jurisdiction_created;
+DROP INDEX IF EXISTS idx_wallets_jurisdiction_created;
+ALTER TABLE transactions DROP COLUMN IF EXISTS jurisdiction;
+ALTER TABLE wallets DROP COLUMN IF EXISTS jurisdiction;
+ALTER TABLE users DROP COLUMN IF EXISTS jurisdiction;
@@ -0,0 +1,37 @@
+-- Tag users, wallets and transactions with an ISO 3166-1 alpha-2 jurisdiction
+-- so data retention can be evaluated per record.
+--
+-- Wallets and transactions store a denormalized copy taken at creation time:
+-- if a user later moves country, existing records keep their original tag.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS jurisdiction VARCHAR(2)
+    CHECK (jurisdiction ~ '^[A-Z]{2}$');
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS jurisdiction VARCHAR(2)
+    CHECK (jurisdiction ~ '^[A-Z]{2}$');
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS jurisdiction VARCHAR(2)
+    CHECK (jurisdiction ~ '^[A-Z]{2}$');
+
+-- Backfill. The fallback for users is read from the optional
+-- paybridge.default_jurisdiction setting, e.g.:
+--   ALTER DATABASE paybridge SET paybridge.default_jurisdiction = 'DE';
+-- Rows left NULL are resolved to compliance.default_jurisdiction by the application.
+UPDATE users
+SET jurisdiction = NULLIF(current_setting('paybridge.default_jurisdiction', true), '')
+WHERE jurisdiction IS NULL;
+
+UPDATE wallets w
+SET jurisdiction = u.jurisdiction
+FROM users u
+WHERE w.user_id = u.id AND w.jurisdiction IS NULL;
+
+UPDATE transactions t
+SET jurisdiction = w.jurisdiction
+FROM wallets w
+WHERE t.wallet_id = w.id AND t.jurisdiction IS NULL;
+
+-- Retention scans filter by jurisdiction and age
+CREATE INDEX IF NOT EXISTS idx_wallets_jurisdiction_created ON wallets (jurisdiction, created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_jurisdiction_created ON transactions (jurisdiction, created_at);
+
+COMMENT ON COLUMN users.jurisdiction IS 'Current ISO 3166-1 alpha-2 jurisdiction of the user';
+COMMENT ON COLUMN wallets.jurisdiction IS 'Owner jurisdiction at wallet creation (retention tag)';
+COMMENT ON COLUMN transactions.jurisdiction IS 'Source wallet jurisdiction at transaction creation (retention tag)';
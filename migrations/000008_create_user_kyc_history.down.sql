@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_user_kyc_history_user_occurred;
+DROP TABLE IF EXISTS user_kyc_history;
+
+ALTER TABLE users DROP COLUMN IF EXISTS last_kyc_rejection_reason;
@@ -0,0 +1,21 @@
+-- KYC decisions history and the last rejection reason on users
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_kyc_rejection_reason TEXT;
+
+COMMENT ON COLUMN users.last_kyc_rejection_reason IS 'Reason of the most recent KYC rejection, kept after approval';
+
+CREATE TABLE IF NOT EXISTS user_kyc_history (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL
+        CHECK (from_status IN ('UNVERIFIED', 'PENDING', 'VERIFIED', 'REJECTED')),
+    to_status VARCHAR(20) NOT NULL
+        CHECK (to_status IN ('UNVERIFIED', 'PENDING', 'VERIFIED', 'REJECTED')),
+    reason TEXT,
+    actor_id UUID NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_kyc_history_user_occurred ON user_kyc_history (user_id, occurred_at);
+
+COMMENT ON TABLE user_kyc_history IS 'Append-only history of KYC decisions';
+COMMENT ON COLUMN user_kyc_history.actor_id IS 'Reviewer who made the decision';
@@ -0,0 +1,6 @@
+-- Remove transaction amount breakdown
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_amount_breakdown_check;
+ALTER TABLE transactions DROP COLUMN IF EXISTS net_amount;
+ALTER TABLE transactions DROP COLUMN IF EXISTS fee_amount;
+
+COMMENT ON COLUMN transactions.amount IS 'Amount in minor currency units (cents/satoshis)';
@@ -0,0 +1,25 @@
+-- Explicit amount breakdown for transactions that carry fees.
+--
+--   amount     - gross: what the initiator requested
+--   fee_amount - sum of linked FEE transactions (0 when no fee)
+--   net_amount - what the counterparty receives
+--
+-- Sender-pays: net = amount, the payer is debited amount + fee.
+-- Deducted:    net = amount - fee, the payer is debited amount.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS fee_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS net_amount BIGINT;
+
+-- Existing transactions had no fees
+UPDATE transactions SET net_amount = amount WHERE net_amount IS NULL;
+
+ALTER TABLE transactions ALTER COLUMN net_amount SET NOT NULL;
+ALTER TABLE transactions ADD CONSTRAINT transactions_amount_breakdown_check
+    CHECK (
+        fee_amount >= 0
+        AND net_amount > 0
+        AND (net_amount = amount OR net_amount + fee_amount = amount)
+    );
+
+COMMENT ON COLUMN transactions.amount IS 'Gross amount in minor currency units (cents/satoshis)';
+COMMENT ON COLUMN transactions.fee_amount IS 'Fee in minor units, charged via linked FEE transaction';
+COMMENT ON COLUMN transactions.net_amount IS 'Net amount received by the counterparty, in minor units';
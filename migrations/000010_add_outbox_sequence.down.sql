@@ -0,0 +1,3 @@
+-- Remove outbox insertion order
+DROP INDEX IF EXISTS idx_outbox_pending_sequence;
+ALTER TABLE outbox DROP COLUMN IF EXISTS sequence;
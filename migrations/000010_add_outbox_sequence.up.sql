@@ -0,0 +1,10 @@
+-- Insertion order for outbox events.
+-- created_at comes from the event's OccurredAt and can tie or disagree with
+-- the order of a PublishBatch call; sequence follows the VALUES order.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS sequence BIGSERIAL;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending_sequence
+    ON outbox (sequence)
+    WHERE status = 'PENDING';
+
+COMMENT ON COLUMN outbox.sequence IS 'Insertion order; events of one aggregate are delivered in this order';
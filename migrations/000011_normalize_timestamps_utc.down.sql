@@ -0,0 +1,3 @@
+-- Nothing to revert: TIMESTAMPTZ is the schema declared by the CREATE TABLE
+-- migrations. Converting back to naive timestamps would lose the zone.
+SELECT 1;
@@ -0,0 +1,36 @@
+-- All timestamps are stored as TIMESTAMPTZ and compared in UTC.
+--
+-- The CREATE TABLE migrations already declare TIMESTAMPTZ, but databases
+-- created by hand or restored from older dumps may still have
+-- TIMESTAMP WITHOUT TIME ZONE columns. Such values were rendered in the
+-- server's TZ and shifted between endpoints.
+--
+-- Backfill: legacy naive values are interpreted as UTC (the application has
+-- always written UTC instants). If a database was written with a non-UTC
+-- session TimeZone, correct those rows manually before applying, e.g.
+--   UPDATE transactions SET created_at = (created_at AT TIME ZONE 'Europe/Moscow') AT TIME ZONE 'UTC';
+-- Columns that are already TIMESTAMPTZ are not touched.
+DO $$
+DECLARE
+    col RECORD;
+BEGIN
+    FOR col IN
+        SELECT table_name, column_name
+        FROM information_schema.columns
+        WHERE table_schema = current_schema()
+          AND data_type = 'timestamp without time zone'
+          AND (table_name, column_name) IN (
+              ('users', 'created_at'), ('users', 'updated_at'),
+              ('wallets', 'created_at'), ('wallets', 'updated_at'),
+              ('transactions', 'created_at'), ('transactions', 'updated_at'),
+              ('transactions', 'processed_at'), ('transactions', 'completed_at'),
+              ('outbox', 'created_at'), ('outbox', 'published_at'), ('outbox', 'failed_at'),
+              ('user_kyc_history', 'occurred_at')
+          )
+    LOOP
+        EXECUTE format(
+            'ALTER TABLE %I ALTER COLUMN %I TYPE TIMESTAMPTZ USING %I AT TIME ZONE ''UTC''',
+            col.table_name, col.column_name, col.column_name
+        );
+    END LOOP;
+END $$;
@@ -0,0 +1,3 @@
+-- Remove build version stamps
+ALTER TABLE outbox DROP COLUMN IF EXISTS producer_version;
+ALTER TABLE transactions DROP COLUMN IF EXISTS created_by_version;
@@ -0,0 +1,8 @@
+-- Build version that produced a row, for incident forensics.
+-- The value comes from the binary's build info (version+git commit), never
+-- from request input. Rows created before this migration stay NULL.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS created_by_version TEXT;
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS producer_version TEXT;
+
+COMMENT ON COLUMN transactions.created_by_version IS 'Build version (version+commit) that created the transaction; NULL for older rows';
+COMMENT ON COLUMN outbox.producer_version IS 'Build version (version+commit) that produced the event; NULL for older rows';
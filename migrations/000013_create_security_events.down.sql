@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_security_events_principal_occurred;
+DROP INDEX IF EXISTS idx_security_events_occurred;
+DROP TABLE IF EXISTS security_events;
@@ -0,0 +1,18 @@
+-- Authentication / authorization outcomes for security monitoring.
+-- Written asynchronously by the HTTP auth middleware; rows are removed by
+-- the retention sweep, never updated.
+CREATE TABLE IF NOT EXISTS security_events (
+    id UUID PRIMARY KEY,
+    principal TEXT,
+    event_type VARCHAR(20) NOT NULL
+        CHECK (event_type IN ('AUTH_FAILED', 'AUTH_SUCCEEDED', 'SCOPE_DENIED')),
+    ip TEXT,
+    user_agent TEXT,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_security_events_occurred ON security_events (occurred_at);
+CREATE INDEX IF NOT EXISTS idx_security_events_principal_occurred ON security_events (principal, occurred_at);
+
+COMMENT ON TABLE security_events IS 'Append-only log of auth outcomes, pruned by retention';
+COMMENT ON COLUMN security_events.principal IS 'User ID, or key:<prefix> of an unverifiable credential; NULL when no credential was sent';
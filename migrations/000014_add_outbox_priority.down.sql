@@ -0,0 +1,4 @@
+-- Remove outbox delivery lanes
+DROP INDEX IF EXISTS idx_outbox_pending_aggregate;
+DROP INDEX IF EXISTS idx_outbox_pending_priority;
+ALTER TABLE outbox DROP COLUMN IF EXISTS priority;
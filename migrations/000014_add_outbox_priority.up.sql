@@ -0,0 +1,15 @@
+-- Delivery lane for outbox events, assigned from the event type at insert.
+-- The relay drains HIGH rows in a separate faster loop; older PENDING rows
+-- of the same aggregate are delivered with them, so per-aggregate order holds.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS priority VARCHAR(10) NOT NULL DEFAULT 'NORMAL'
+    CHECK (priority IN ('HIGH', 'NORMAL', 'LOW'));
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending_priority
+    ON outbox (priority, aggregate_id, sequence)
+    WHERE status = 'PENDING';
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending_aggregate
+    ON outbox (aggregate_id, sequence)
+    WHERE status = 'PENDING';
+
+COMMENT ON COLUMN outbox.priority IS 'Delivery lane: HIGH, NORMAL, LOW';
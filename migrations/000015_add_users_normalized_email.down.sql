@@ -0,0 +1,3 @@
+-- Remove email uniqueness key
+DROP INDEX IF EXISTS users_normalized_email_unique;
+ALTER TABLE users DROP COLUMN IF EXISTS normalized_email;
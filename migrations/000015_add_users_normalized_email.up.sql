@@ -0,0 +1,33 @@
+-- Uniqueness key for user emails (valueobjects.EmailPolicy).
+-- email keeps the address as entered; normalized_email is lowercased and,
+-- for gmail, has plus-tags and dots in the local part stripped.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS normalized_email VARCHAR(255);
+
+-- Backfill with the default policy. Existing users that collapse to the same
+-- key keep their lowercased email (unique already), oldest user wins the key.
+WITH candidates AS (
+    SELECT id, created_at,
+        CASE
+            WHEN split_part(lower(email), '@', 2) IN ('gmail.com', 'googlemail.com')
+                AND left(email, 1) <> '"'
+            THEN replace(split_part(split_part(lower(email), '@', 1), '+', 1), '.', '')
+                || '@' || split_part(lower(email), '@', 2)
+            ELSE lower(email)
+        END AS normalized
+    FROM users
+),
+ranked AS (
+    SELECT id, normalized,
+        ROW_NUMBER() OVER (PARTITION BY normalized ORDER BY created_at, id) AS rn
+    FROM candidates
+)
+UPDATE users u
+SET normalized_email = CASE WHEN r.rn = 1 THEN r.normalized ELSE lower(u.email) END
+FROM ranked r
+WHERE r.id = u.id;
+
+ALTER TABLE users ALTER COLUMN normalized_email SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_normalized_email_unique ON users (normalized_email);
+
+COMMENT ON COLUMN users.normalized_email IS 'Uniqueness key of email: lowercased, gmail plus-tags and dots stripped';
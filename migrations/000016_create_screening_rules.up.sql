@@ -0,0 +1,19 @@
+-- Transaction screening rules (application/screening).
+-- Loaded at startup together with the rules from configuration; a rule that
+-- fails validation aborts startup. Conditions are ANDed:
+--   [{"attribute": "amount", "operator": "gt", "value": "10000"},
+--    {"attribute": "country", "operator": "in", "values": ["IR", "KP"]}]
+CREATE TABLE IF NOT EXISTS screening_rules (
+    id VARCHAR(64) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    action VARCHAR(10) NOT NULL
+        CHECK (action IN ('BLOCK', 'FLAG')),
+    conditions JSONB NOT NULL
+        CHECK (jsonb_typeof(conditions) = 'array'),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE screening_rules IS 'Data-driven block/flag rules applied to transactions before commit';
+COMMENT ON COLUMN screening_rules.conditions IS 'JSON array of {attribute, operator, value | values}, combined with AND';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS wallet_holds;
+DROP TABLE IF EXISTS wallet_balances;
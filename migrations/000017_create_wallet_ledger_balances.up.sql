@@ -0,0 +1,32 @@
+-- New balance model for wallets: available funds and holds live outside the
+-- wallets row. Filled by WalletRepository according to the wallet migration
+-- phase (off -> dual_write -> shadow_read_compare -> new_read -> new_only);
+-- wallets.available_balance / pending_balance stay authoritative until reads
+-- are flipped to the new path.
+CREATE TABLE IF NOT EXISTS wallet_balances (
+    wallet_id UUID PRIMARY KEY REFERENCES wallets(id) ON DELETE CASCADE,
+    available_balance BIGINT NOT NULL DEFAULT 0
+        CHECK (available_balance >= 0),
+    balance_version BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS wallet_holds (
+    wallet_id UUID PRIMARY KEY REFERENCES wallets(id) ON DELETE CASCADE,
+    held_balance BIGINT NOT NULL DEFAULT 0
+        CHECK (held_balance >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Backfill existing wallets; rows written later by dual_write are upserted
+INSERT INTO wallet_balances (wallet_id, available_balance, balance_version, updated_at)
+SELECT id, available_balance, balance_version, updated_at FROM wallets
+ON CONFLICT (wallet_id) DO NOTHING;
+
+INSERT INTO wallet_holds (wallet_id, held_balance, updated_at)
+SELECT id, pending_balance, updated_at FROM wallets
+ON CONFLICT (wallet_id) DO NOTHING;
+
+COMMENT ON TABLE wallet_balances IS 'Available balance of a wallet in the holds/ledger model';
+COMMENT ON TABLE wallet_holds IS 'Funds on hold (pending) per wallet in the holds/ledger model';
+COMMENT ON COLUMN wallet_balances.balance_version IS 'Version for optimistic locking once reads use the new path';
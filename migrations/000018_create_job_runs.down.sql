@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_job_runs_job_started;
+DROP TABLE IF EXISTS job_runs;
+DROP TABLE IF EXISTS job_locks;
@@ -0,0 +1,28 @@
+-- Background job scheduler: one lease row per job guarantees that a given
+-- job runs on at most one instance at a time; job_runs keeps the run history
+-- shown by GET /api/v1/admin/jobs.
+CREATE TABLE IF NOT EXISTS job_locks (
+    job_name TEXT PRIMARY KEY,
+    owner UUID NOT NULL,
+    locked_until TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS job_runs (
+    id UUID PRIMARY KEY,
+    job_name TEXT NOT NULL,
+    trigger VARCHAR(10) NOT NULL
+        CHECK (trigger IN ('SCHEDULE', 'MANUAL')),
+    instance TEXT NOT NULL,
+    status VARCHAR(10) NOT NULL
+        CHECK (status IN ('RUNNING', 'SUCCEEDED', 'FAILED')),
+    error TEXT,
+    items_processed INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_started ON job_runs (job_name, started_at DESC);
+
+COMMENT ON TABLE job_locks IS 'Lease per job; an expired lease may be taken over by another instance';
+COMMENT ON COLUMN job_locks.owner IS 'ID of the job run holding the lease';
+COMMENT ON TABLE job_runs IS 'History of background job runs';
@@ -0,0 +1,24 @@
+-- Destinations a wallet has already transferred to. The new-payee check in
+-- TransferBetweenWalletsUseCase restricts transfers to wallets without a row
+-- here; the first completed transfer records the pair.
+CREATE TABLE IF NOT EXISTS wallet_payees (
+    source_wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    destination_wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    first_transaction_id UUID NOT NULL,
+    first_transfer_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (source_wallet_id, destination_wallet_id)
+);
+
+-- Backfill from completed transfers so existing payees are not treated as new
+INSERT INTO wallet_payees (source_wallet_id, destination_wallet_id, first_transaction_id, first_transfer_at)
+SELECT DISTINCT ON (wallet_id, destination_wallet_id)
+    wallet_id, destination_wallet_id, id, COALESCE(completed_at, created_at)
+FROM transactions
+WHERE transaction_type = 'TRANSFER'
+  AND status = 'COMPLETED'
+  AND destination_wallet_id IS NOT NULL
+ORDER BY wallet_id, destination_wallet_id, COALESCE(completed_at, created_at)
+ON CONFLICT (source_wallet_id, destination_wallet_id) DO NOTHING;
+
+COMMENT ON TABLE wallet_payees IS 'Source/destination wallet pairs with at least one completed transfer';
+COMMENT ON COLUMN wallet_payees.first_transaction_id IS 'First completed TRANSFER between the pair';
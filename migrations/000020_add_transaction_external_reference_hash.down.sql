@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_external_reference_hash;
+ALTER TABLE transactions DROP COLUMN IF EXISTS external_reference_hash;
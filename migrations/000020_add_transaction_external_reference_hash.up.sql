@@ -0,0 +1,15 @@
+-- SHA-256 (hex) of the external reference as received from the provider.
+-- Card numbers and IBANs in external_reference are masked before storage;
+-- FindByExternalReference matches the hash so exact lookup keeps working.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS external_reference_hash VARCHAR(64);
+
+-- Existing rows were stored verbatim, so the hash of the stored value is the hash of the original
+UPDATE transactions
+SET external_reference_hash = encode(sha256(convert_to(external_reference, 'UTF8')), 'hex')
+WHERE external_reference IS NOT NULL AND external_reference_hash IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_external_reference_hash
+    ON transactions (external_reference_hash)
+    WHERE external_reference_hash IS NOT NULL;
+
+COMMENT ON COLUMN transactions.external_reference_hash IS 'SHA-256 hex of the original external reference (lookup after PAN/IBAN masking)';
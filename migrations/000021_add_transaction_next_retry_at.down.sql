@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_transactions_failed_retryable;
+CREATE INDEX IF NOT EXISTS idx_transactions_failed_retryable
+    ON transactions (created_at)
+    WHERE status = 'FAILED' AND retry_count < 3;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS next_retry_at;
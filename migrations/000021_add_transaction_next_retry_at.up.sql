@@ -0,0 +1,11 @@
+-- Earliest time the next retry of a failed transaction may start.
+-- Set by Retry with exponential backoff (1m, 5m, 30m, 2h); NULL = immediately.
+-- FindFailedRetryable returns only rows whose next_retry_at has passed.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS next_retry_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS idx_transactions_failed_retryable;
+CREATE INDEX IF NOT EXISTS idx_transactions_failed_retryable
+    ON transactions (next_retry_at, created_at)
+    WHERE status = 'FAILED';
+
+COMMENT ON COLUMN transactions.next_retry_at IS 'Earliest time of the next retry (exponential backoff), NULL = immediately';
@@ -0,0 +1,22 @@
+-- Internal support notes on wallets. Visible to admins only, never to the
+-- wallet owner. Deletion is soft: deleted_at/deleted_by are set and the row
+-- stays for audit.
+CREATE TABLE IF NOT EXISTS wallet_notes (
+    id UUID PRIMARY KEY,
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL,
+    body TEXT NOT NULL CHECK (char_length(body) BETWEEN 1 AND 2000),
+    pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    deleted_by UUID
+);
+
+-- List order of a wallet: pinned first, newest first
+CREATE INDEX IF NOT EXISTS idx_wallet_notes_wallet_list
+    ON wallet_notes (wallet_id, pinned DESC, created_at DESC)
+    WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE wallet_notes IS 'Support notes on wallets; soft-deleted rows are kept for audit';
+COMMENT ON COLUMN wallet_notes.author_id IS 'Admin who wrote the note';
+COMMENT ON COLUMN wallet_notes.deleted_by IS 'Author or superadmin who deleted the note';
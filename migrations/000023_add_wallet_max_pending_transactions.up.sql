@@ -0,0 +1,8 @@
+-- Per-wallet override of the cap on simultaneously PENDING/PROCESSING
+-- transactions (transactions.max_pending_per_wallet). Set by admins for
+-- high-volume merchants via PUT /users/{id}/wallets/{currency}; NULL = configured default.
+-- The cap is checked with COUNT over idx_transactions_wallet_status.
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS max_pending_transactions INTEGER
+    CHECK (max_pending_transactions IS NULL OR max_pending_transactions > 0);
+
+COMMENT ON COLUMN wallets.max_pending_transactions IS 'Override of the pending transactions cap, NULL = configured default';
@@ -0,0 +1,16 @@
+-- Events already handled by internal consumers (projections, webhook and
+-- notification dispatchers). Outbox delivery is at-least-once; a consumer
+-- wrapped with eventbus.WithDedup records (consumer, event_id) before its
+-- handler runs and skips events that are already recorded.
+-- Rows older than messaging.dedup_retention are purged by the
+-- processed-events-purge job.
+CREATE TABLE IF NOT EXISTS processed_events (
+    consumer     VARCHAR(100) NOT NULL,
+    event_id     UUID NOT NULL,
+    processed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT processed_events_pkey PRIMARY KEY (consumer, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_events_processed_at ON processed_events (processed_at);
+
+COMMENT ON TABLE processed_events IS 'Consumer-side dedup of at-least-once event delivery';
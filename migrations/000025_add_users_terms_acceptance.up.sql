@@ -0,0 +1,16 @@
+-- Terms-of-service version accepted by the user (POST /users/{id}/accept-terms).
+-- Debits, transfers and payouts are rejected with TERMS_ACCEPTANCE_REQUIRED
+-- while the accepted version is older than terms.required_version.
+--
+-- Backfill: rows that exist before this migration keep tos_version NULL -
+-- they are grandfathered and treated as having accepted
+-- terms.grandfathered_version until the user accepts explicitly.
+-- The default is set after the column is added, so only new users get 0
+-- (never accepted).
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tos_version INTEGER
+    CHECK (tos_version IS NULL OR tos_version >= 0);
+ALTER TABLE users ALTER COLUMN tos_version SET DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tos_accepted_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN users.tos_version IS 'Accepted terms-of-service version, 0 = never accepted, NULL = grandfathered';
+COMMENT ON COLUMN users.tos_accepted_at IS 'When tos_version was accepted, NULL = never accepted explicitly';
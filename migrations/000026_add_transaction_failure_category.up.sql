@@ -0,0 +1,21 @@
+-- Who caused a transaction failure, set together with failure_reason:
+--   CLIENT   - the customer (insufficient funds, invalid or closed account)
+--   PROVIDER - the external provider (outage, timeout, rejection)
+--   SYSTEM   - our own infrastructure (database, bugs)
+--   FRAUD    - fraud or compliance controls
+-- NULL for transactions that are not FAILED.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS failure_category VARCHAR(16)
+    CHECK (failure_category IS NULL OR failure_category IN ('CLIENT', 'PROVIDER', 'SYSTEM', 'FRAUD'));
+
+-- Best-effort backfill of existing failures: known provider reason codes
+-- (entities.FailureCategoryForReason), everything else was reported by
+-- the provider callback.
+UPDATE transactions
+SET failure_category = CASE
+        WHEN failure_reason IN ('INVALID_ACCOUNT', 'ACCOUNT_CLOSED', 'INSUFFICIENT_BALANCE') THEN 'CLIENT'
+        WHEN failure_reason IN ('FRAUD_DETECTED', 'BLACKLISTED') THEN 'FRAUD'
+        ELSE 'PROVIDER'
+    END
+WHERE status = 'FAILED' AND failure_category IS NULL;
+
+COMMENT ON COLUMN transactions.failure_category IS 'Failure classification: CLIENT, PROVIDER, SYSTEM or FRAUD (NULL unless FAILED)';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_failed_requests_route_occurred;
+DROP INDEX IF EXISTS idx_failed_requests_actor_occurred;
+DROP INDEX IF EXISTS idx_failed_requests_occurred;
+DROP TABLE IF EXISTS failed_requests;
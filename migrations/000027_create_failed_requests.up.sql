@@ -0,0 +1,25 @@
+-- Redacted request/response samples of failed money operations (4xx/5xx on
+-- mutating wallet and transaction endpoints) for support investigations.
+-- Written asynchronously by the HTTP middleware; rows are removed by the
+-- retention sweep, never updated.
+CREATE TABLE IF NOT EXISTS failed_requests (
+    id UUID PRIMARY KEY,
+    method VARCHAR(10) NOT NULL,
+    route TEXT NOT NULL,
+    status_code SMALLINT NOT NULL CHECK (status_code BETWEEN 400 AND 599),
+    request_id TEXT,
+    actor_id UUID,
+    request_body TEXT NOT NULL DEFAULT '',
+    response_body TEXT NOT NULL DEFAULT '',
+    truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_failed_requests_occurred ON failed_requests (occurred_at);
+CREATE INDEX IF NOT EXISTS idx_failed_requests_actor_occurred ON failed_requests (actor_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_failed_requests_route_occurred ON failed_requests (route, occurred_at);
+
+COMMENT ON TABLE failed_requests IS 'Redacted samples of failed money operation requests, pruned by retention';
+COMMENT ON COLUMN failed_requests.route IS 'Route template, e.g. /api/v1/wallets/{id}/debit';
+COMMENT ON COLUMN failed_requests.actor_id IS 'Authenticated user; NULL for anonymous requests';
+COMMENT ON COLUMN failed_requests.truncated IS 'A body exceeded the capture size cap and was cut';
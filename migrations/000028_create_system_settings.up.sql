@@ -0,0 +1,15 @@
+-- Runtime operational settings changed by admins without a deploy.
+-- Each row is one setting; value holds its JSON state. Instances cache the
+-- table and re-read it on a short interval.
+--
+-- Operation kill switches use keys 'operation_switch.<TRANSACTION_TYPE>' with
+-- value {"enabled": bool, "reason": string}; a missing row means enabled.
+CREATE TABLE IF NOT EXISTS system_settings (
+    key TEXT PRIMARY KEY,
+    value JSONB NOT NULL,
+    updated_by UUID,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE system_settings IS 'Runtime operational settings (kill switches), cached by every instance';
+COMMENT ON COLUMN system_settings.updated_by IS 'Admin who made the last change';
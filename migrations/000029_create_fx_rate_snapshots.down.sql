@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_fx_rate_snapshots_fetched;
+DROP TABLE IF EXISTS fx_rate_snapshots;
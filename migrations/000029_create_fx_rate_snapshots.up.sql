@@ -0,0 +1,21 @@
+-- Exchange rates used by FX-affecting money operations, recorded when the
+-- rate provider is consulted. A transaction references its snapshot through
+-- metadata->>'fx_rate_snapshot_id'; reports and idempotent replays read the
+-- rate from here instead of asking the provider again. Rows are never
+-- updated, only removed by the retention sweep.
+CREATE TABLE IF NOT EXISTS fx_rate_snapshots (
+    id UUID PRIMARY KEY,
+    base VARCHAR(10) NOT NULL,
+    quote VARCHAR(10) NOT NULL,
+    rate NUMERIC(24, 12) NOT NULL CHECK (rate > 0),
+    source TEXT NOT NULL,
+    fetched_at TIMESTAMPTZ NOT NULL,
+    CHECK (base <> quote)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fx_rate_snapshots_fetched ON fx_rate_snapshots (fetched_at);
+
+COMMENT ON TABLE fx_rate_snapshots IS 'Immutable exchange rates used by money operations, pruned by retention';
+COMMENT ON COLUMN fx_rate_snapshots.rate IS 'Units of quote per one unit of base, as used by the operation';
+COMMENT ON COLUMN fx_rate_snapshots.source IS 'Rate provider, e.g. exchangerate-api.com';
+COMMENT ON COLUMN fx_rate_snapshots.fetched_at IS 'Provider timestamp of the rate';
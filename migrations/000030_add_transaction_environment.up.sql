@@ -0,0 +1,16 @@
+-- Environment (app.environment) the transaction was created in. Idempotency
+-- keys are unique per environment, so sandbox and production sharing one
+-- database cannot replay each other's transactions.
+--
+-- A constant default does not rewrite the table. Existing rows keep '' -
+-- the migration cannot tell which environment created them - and '' rows
+-- are matched by FindByIdempotencyKey from every environment, so retries of
+-- requests made before the upgrade still replay.
+--
+-- The unique index moves to (environment, idempotency_key) in 000031 and
+-- 000032; each is a separate migration because CREATE INDEX CONCURRENTLY
+-- cannot run inside a transaction block and golang-migrate sends a whole
+-- file as one (implicitly transactional) statement batch.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS environment VARCHAR(32) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN transactions.environment IS 'app.environment at creation; idempotency keys are unique per environment, empty = created before tagging (matched from every environment)';
@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS transactions_environment_idempotency_key_unique;
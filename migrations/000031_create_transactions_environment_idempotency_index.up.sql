@@ -0,0 +1,15 @@
+-- Online build of the new unique index: CONCURRENTLY does not block writes
+-- to transactions while the index is built.
+--
+-- Migration notes (online index changes):
+--   - CREATE/DROP INDEX CONCURRENTLY must be the only statement in its file:
+--     it cannot run inside a transaction block
+--   - if the build fails (or is interrupted) PostgreSQL leaves an INVALID
+--     index behind and IF NOT EXISTS would skip it on retry; drop it first:
+--       DROP INDEX CONCURRENTLY IF EXISTS transactions_environment_idempotency_key_unique;
+--     then force the version back with `migrate force 30` and run up again
+--   - the old constraint is dropped only in the next migration, so the
+--     unique check on idempotency_key alone stays in force until the new
+--     index is valid
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS transactions_environment_idempotency_key_unique
+    ON transactions (environment, idempotency_key);
@@ -0,0 +1,4 @@
+-- Fails if a key already exists in more than one environment.
+-- Builds the index under a write lock; on a large table build it
+-- CONCURRENTLY by hand first and attach it with ADD CONSTRAINT ... USING INDEX.
+ALTER TABLE transactions ADD CONSTRAINT transactions_idempotency_key_unique UNIQUE (idempotency_key);
@@ -0,0 +1,4 @@
+-- The same key may now exist once per environment. Dropping a constraint
+-- only takes a brief ACCESS EXCLUSIVE lock; lookups by idempotency key use
+-- transactions_environment_idempotency_key_unique from 000031.
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_idempotency_key_unique;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_workers_heartbeat_at;
+DROP TABLE IF EXISTS workers_heartbeat;
@@ -0,0 +1,23 @@
+-- Heartbeats of background workers (outbox relay, balance comparison, balance
+-- summary, scheduler jobs). Each instance periodically upserts one row per
+-- worker, so GET /api/v1/admin/workers can show the whole cluster. Rows of
+-- stopped instances simply stop being refreshed.
+CREATE TABLE IF NOT EXISTS workers_heartbeat (
+    worker TEXT NOT NULL,
+    instance TEXT NOT NULL,
+    state VARCHAR(10) NOT NULL
+        CHECK (state IN ('RUNNING', 'IDLE', 'STOPPED')),
+    last_run_at TIMESTAMPTZ,
+    last_success_at TIMESTAMPTZ,
+    items_processed INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    stale_after_seconds INTEGER NOT NULL DEFAULT 0,
+    heartbeat_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (worker, instance)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workers_heartbeat_at ON workers_heartbeat (heartbeat_at);
+
+COMMENT ON TABLE workers_heartbeat IS 'Last reported heartbeat of each background worker per instance';
+COMMENT ON COLUMN workers_heartbeat.items_processed IS 'Items processed by the last finished run';
+COMMENT ON COLUMN workers_heartbeat.stale_after_seconds IS 'Heartbeat older than this is reported as stale; 0 never goes stale';
@@ -0,0 +1 @@
+DROP SEQUENCE IF EXISTS lease_fencing_tokens;
@@ -0,0 +1,6 @@
+-- Fencing tokens of Postgres advisory-lock leases (locks.backend: postgres).
+-- Advisory locks carry no value, so every acquired lease takes the next
+-- number of this sequence: a lease acquired later always has a larger token
+-- than any earlier lease of the same key. nextval is not rolled back with
+-- the transaction holding an xact lock, so tokens are never reused.
+CREATE SEQUENCE IF NOT EXISTS lease_fencing_tokens AS BIGINT;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS instances;
+ALTER TABLE transactions DROP COLUMN IF EXISTS processing_instance_id;
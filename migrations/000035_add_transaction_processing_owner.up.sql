@@ -0,0 +1,21 @@
+-- Crash recovery for transactions left in PROCESSING.
+--
+-- processing_instance_id is the instance that saved the transaction in
+-- PROCESSING; processed_at already records when processing started. Each
+-- instance periodically upserts its row in instances, and the
+-- processing-recovery job only touches PROCESSING rows whose instance has
+-- stopped heartbeating. Rows saved before this migration have no owner and
+-- are treated as orphaned once they pass the age threshold.
+--
+-- Adding a nullable column without a default does not rewrite the table.
+-- The partial index used by the sweep is built concurrently in 000036.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS processing_instance_id TEXT;
+
+CREATE TABLE IF NOT EXISTS instances (
+    instance_id TEXT PRIMARY KEY,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    heartbeat_at TIMESTAMPTZ NOT NULL
+);
+
+COMMENT ON COLUMN transactions.processing_instance_id IS 'Instance that saved the transaction in PROCESSING; NULL = saved before owners were tracked';
+COMMENT ON TABLE instances IS 'Liveness of application instances; PROCESSING transactions of an instance without a fresh heartbeat are orphaned';
@@ -0,0 +1,5 @@
+-- Online build of the index behind the processing-recovery sweep (see
+-- 000031 for the CONCURRENTLY notes: this must stay the only statement in
+-- the file, and a failed build leaves an INVALID index to drop first).
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transactions_processing_started
+    ON transactions (processed_at) WHERE status = 'PROCESSING';
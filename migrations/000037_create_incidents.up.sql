@@ -0,0 +1,24 @@
+-- Incident markers shown on the public status page (GET /status). An
+-- incident is active until resolved_at is set; resolved incidents stay
+-- listed for 24 hours. created_by/resolved_by are kept for audit and are
+-- never exposed publicly.
+CREATE TABLE IF NOT EXISTS incidents (
+    id UUID PRIMARY KEY,
+    title TEXT NOT NULL CHECK (char_length(title) BETWEEN 1 AND 200),
+    severity TEXT NOT NULL CHECK (severity IN ('MINOR', 'MAJOR', 'CRITICAL')),
+    components TEXT[] NOT NULL CHECK (cardinality(components) > 0),
+    started_at TIMESTAMPTZ NOT NULL,
+    resolved_at TIMESTAMPTZ,
+    created_by UUID NOT NULL,
+    resolved_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Status page: active incidents and the ones resolved within the last day
+CREATE INDEX IF NOT EXISTS idx_incidents_visible
+    ON incidents (resolved_at DESC NULLS FIRST, started_at DESC);
+
+COMMENT ON TABLE incidents IS 'Customer-facing incident markers of the public status page';
+COMMENT ON COLUMN incidents.components IS 'Affected status page components: api, database, payouts, deposits';
+COMMENT ON COLUMN incidents.created_by IS 'Admin who opened the incident';
+COMMENT ON COLUMN incidents.resolved_by IS 'Admin who resolved the incident';
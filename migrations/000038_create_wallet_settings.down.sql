@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS incoming_transfer_views;
+DROP TABLE IF EXISTS wallet_settings;
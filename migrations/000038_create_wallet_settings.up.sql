@@ -0,0 +1,30 @@
+-- Owner-managed presentation settings of a wallet. The incoming description
+-- template is rendered into incoming_transfer_views at transfer time; auto
+-- tags are copied into transactions.metadata->>'wallet_tags'.
+CREATE TABLE IF NOT EXISTS wallet_settings (
+    wallet_id UUID PRIMARY KEY REFERENCES wallets(id) ON DELETE CASCADE,
+    incoming_description_template TEXT NOT NULL DEFAULT ''
+        CHECK (char_length(incoming_description_template) <= 200),
+    auto_tags TEXT[] NOT NULL DEFAULT '{}'
+        CHECK (cardinality(auto_tags) <= 10),
+    updated_by UUID NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Destination's view of an incoming transfer. The canonical transaction keeps
+-- the sender's description and metadata.
+CREATE TABLE IF NOT EXISTS incoming_transfer_views (
+    transaction_id UUID PRIMARY KEY REFERENCES transactions(id) ON DELETE CASCADE,
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_incoming_transfer_views_wallet
+    ON incoming_transfer_views (wallet_id, created_at DESC);
+
+COMMENT ON TABLE wallet_settings IS 'Presentation settings managed by the wallet owner';
+COMMENT ON COLUMN wallet_settings.incoming_description_template IS 'Description shown to the owner for incoming transfers; {external_reference} is substituted';
+COMMENT ON COLUMN wallet_settings.auto_tags IS 'Copied into metadata.wallet_tags of every transaction touching the wallet';
+COMMENT ON TABLE incoming_transfer_views IS 'Destination-side description and tags of a transfer, rendered from wallet_settings';
@@ -0,0 +1,2 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS receipt_number;
+DROP SEQUENCE IF EXISTS transaction_receipt_seq;
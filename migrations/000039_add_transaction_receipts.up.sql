@@ -0,0 +1,17 @@
+-- Receipt numbers of completed transactions ("PB-7F3K-9Q2M").
+--
+-- The repository takes nextval(transaction_receipt_seq) when it saves a
+-- COMPLETED transaction without a number and formats it in the application
+-- (valueobjects.NewReceiptNumber: scrambled sequence value, Crockford
+-- base32, Luhn mod 32 check character). A sequence never hands out a value
+-- twice, even across concurrent transactions or rollbacks, so numbers are
+-- unique without retries; gaps are expected. Transactions completed before
+-- this migration have no number.
+--
+-- Adding a nullable column without a default does not rewrite the table.
+-- The unique index is built concurrently in 000040.
+CREATE SEQUENCE IF NOT EXISTS transaction_receipt_seq;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS receipt_number VARCHAR(16);
+
+COMMENT ON COLUMN transactions.receipt_number IS 'Human-readable receipt number, assigned once at completion; NULL = not completed or completed before receipts';
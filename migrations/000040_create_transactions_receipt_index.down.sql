@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS idx_transactions_receipt_number;
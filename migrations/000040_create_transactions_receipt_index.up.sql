@@ -0,0 +1,5 @@
+-- Online build of the unique index behind receipt lookups (see 000031 for
+-- the CONCURRENTLY notes: this must stay the only statement in the file,
+-- and a failed build leaves an INVALID index to drop first).
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS idx_transactions_receipt_number
+    ON transactions (receipt_number) WHERE receipt_number IS NOT NULL;
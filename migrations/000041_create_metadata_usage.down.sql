@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS transaction_metadata_usage;
+DROP TABLE IF EXISTS wallet_metadata_usage;
@@ -0,0 +1,24 @@
+-- Cumulative size of client metadata per wallet, maintained in the same
+-- transaction that creates the ledger transaction. The per-transaction rows
+-- make the counter exactly the sum of what is stored and let archival
+-- subtract precisely what was added.
+CREATE TABLE IF NOT EXISTS wallet_metadata_usage (
+    wallet_id UUID PRIMARY KEY REFERENCES wallets(id) ON DELETE CASCADE,
+    metadata_bytes BIGINT NOT NULL DEFAULT 0 CHECK (metadata_bytes >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- No foreign key to transactions: archival removes these rows itself while
+-- decrementing the wallet counter, a cascade would skip the decrement.
+CREATE TABLE IF NOT EXISTS transaction_metadata_usage (
+    transaction_id UUID PRIMARY KEY,
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    metadata_bytes BIGINT NOT NULL CHECK (metadata_bytes > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_metadata_usage_wallet
+    ON transaction_metadata_usage (wallet_id);
+
+COMMENT ON TABLE wallet_metadata_usage IS 'Total client metadata bytes of the wallet transactions, for the metadata quota';
+COMMENT ON TABLE transaction_metadata_usage IS 'Client metadata bytes counted for each transaction; deleted by archival';
@@ -0,0 +1,2 @@
+-- Remove the wallet display currency preference
+ALTER TABLE wallet_settings DROP COLUMN IF EXISTS display_currency;
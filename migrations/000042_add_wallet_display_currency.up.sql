@@ -0,0 +1,6 @@
+-- Preferred display currency of a wallet. Reads of the wallet carry an
+-- approximate converted balance; the preference never affects money movement.
+-- Empty means no conversion.
+ALTER TABLE wallet_settings ADD COLUMN IF NOT EXISTS display_currency VARCHAR(10) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN wallet_settings.display_currency IS 'Currency balances are converted to for display only; empty = none';
@@ -0,0 +1,67 @@
+-- Webhook subscriptions of users and the delivery queue/history.
+--
+-- A subscription is ACTIVE, DISABLED_MANUAL (owner turned it off) or
+-- DISABLED_AUTO (too many consecutive delivery failures). Disabled
+-- subscriptions receive no deliveries, but their pending deliveries are kept
+-- for a grace period so re-enabling resumes the backlog. Deletion is soft:
+-- deleted_at is set and the row stays with its delivery history until the
+-- retention purge removes both.
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL CHECK (char_length(url) BETWEEN 1 AND 2048),
+    secret TEXT NOT NULL,
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'ACTIVE'
+        CHECK (status IN ('ACTIVE', 'DISABLED_MANUAL', 'DISABLED_AUTO')),
+    consecutive_failures INT NOT NULL DEFAULT 0 CHECK (consecutive_failures >= 0),
+    failing_since TIMESTAMPTZ,
+    last_failure_reason TEXT NOT NULL DEFAULT '',
+    disabled_at TIMESTAMPTZ,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_user
+    ON webhook_subscriptions (user_id, created_at DESC)
+    WHERE deleted_at IS NULL;
+
+-- Retention purge of soft-deleted subscriptions
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_deleted
+    ON webhook_subscriptions (deleted_at)
+    WHERE deleted_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSON NOT NULL,
+    status TEXT NOT NULL DEFAULT 'PENDING'
+        CHECK (status IN ('PENDING', 'DELIVERED', 'FAILED', 'SKIPPED', 'EXPIRED')),
+    attempts INT NOT NULL DEFAULT 0 CHECK (attempts >= 0),
+    last_error TEXT NOT NULL DEFAULT '',
+    last_status_code INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    CONSTRAINT webhook_deliveries_event_unique UNIQUE (subscription_id, event_id)
+);
+
+-- Delivery worker: due pending deliveries in creation order
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due
+    ON webhook_deliveries (next_attempt_at, created_at)
+    WHERE status = 'PENDING';
+
+-- History of a subscription, newest first
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription
+    ON webhook_deliveries (subscription_id, created_at DESC);
+
+COMMENT ON TABLE webhook_subscriptions IS 'Webhook endpoints of users; soft-deleted rows keep their delivery history until the retention purge';
+COMMENT ON COLUMN webhook_subscriptions.event_types IS 'Delivered event types; empty means all supported types';
+COMMENT ON COLUMN webhook_subscriptions.failing_since IS 'First failure of the current run of consecutive failures';
+COMMENT ON COLUMN webhook_subscriptions.version IS 'Optimistic locking: the delivery worker and the owner update the same row';
+COMMENT ON TABLE webhook_deliveries IS 'Webhook delivery queue (PENDING) and history';
+COMMENT ON COLUMN webhook_deliveries.payload IS 'Exact request body (JSON, not JSONB): retries resend the signed bytes';
@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS idx_transactions_destination_created;
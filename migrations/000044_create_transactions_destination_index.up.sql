@@ -0,0 +1,6 @@
+-- Online build of the index behind incoming transfers in the dashboard
+-- activity heatmap (see 000031 for the CONCURRENTLY notes: this must stay
+-- the only statement in the file, and a failed build leaves an INVALID
+-- index to drop first). Outgoing rows use idx_transactions_wallet_created.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transactions_destination_created
+    ON transactions (destination_wallet_id, created_at) WHERE destination_wallet_id IS NOT NULL;
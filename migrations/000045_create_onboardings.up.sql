@@ -0,0 +1,24 @@
+-- Results of partner onboarding requests (POST /api/v1/onboarding), keyed by
+-- the request's idempotency key. A replay of the key returns the recorded
+-- user, wallets and initial credits instead of onboarding again; a replay
+-- with a different request (request_hash) is rejected.
+CREATE TABLE IF NOT EXISTS onboardings (
+    idempotency_key UUID PRIMARY KEY,
+    partner_id UUID NOT NULL,
+    request_hash TEXT NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    wallet_ids UUID[] NOT NULL DEFAULT '{}',
+    transaction_ids UUID[] NOT NULL DEFAULT '{}',
+    kyc_auto_approved BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_onboardings_partner_created
+    ON onboardings (partner_id, created_at DESC);
+
+COMMENT ON TABLE onboardings IS 'Partner onboarding results for idempotent replays';
+COMMENT ON COLUMN onboardings.partner_id IS 'Authenticated caller that onboarded the user';
+COMMENT ON COLUMN onboardings.request_hash IS 'SHA-256 of the normalized request; a replay must match it';
+COMMENT ON COLUMN onboardings.wallet_ids IS 'Created wallets in the order of the requested currencies';
+COMMENT ON COLUMN onboardings.transaction_ids IS 'Initial credit transactions in the order of the requested currencies';
+COMMENT ON COLUMN onboardings.kyc_auto_approved IS 'KYC was approved by a trusted partner during onboarding';
@@ -0,0 +1,7 @@
+-- Revert: fold POLICY_DENIED back into SCOPE_DENIED and drop it from the allowed types.
+UPDATE security_events SET event_type = 'SCOPE_DENIED' WHERE event_type = 'POLICY_DENIED';
+
+ALTER TABLE security_events DROP CONSTRAINT IF EXISTS security_events_event_type_check;
+
+ALTER TABLE security_events ADD CONSTRAINT security_events_event_type_check
+    CHECK (event_type IN ('AUTH_FAILED', 'AUTH_SUCCEEDED', 'SCOPE_DENIED'));
@@ -0,0 +1,6 @@
+-- Add POLICY_DENIED: the route's owner or role policy rejected an
+-- authenticated request. SCOPE_DENIED stays for API key scope failures.
+ALTER TABLE security_events DROP CONSTRAINT IF EXISTS security_events_event_type_check;
+
+ALTER TABLE security_events ADD CONSTRAINT security_events_event_type_check
+    CHECK (event_type IN ('AUTH_FAILED', 'AUTH_SUCCEEDED', 'SCOPE_DENIED', 'POLICY_DENIED'));
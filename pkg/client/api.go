@@ -0,0 +1,228 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+)
+
+// ============================================
+// Response types
+// ============================================
+
+// Типы ответов - те же DTO, что сериализуют handlers.
+type (
+	Wallet          = dtos.WalletDTO
+	WalletOperation = dtos.WalletOperationDTO
+	TransferResult  = dtos.TransferResultDTO
+	Transaction     = dtos.TransactionDTO
+	TransactionList = dtos.TransactionListDTO
+	Pagination      = dtos.PaginationDTO
+)
+
+// ============================================
+// Request types
+// ============================================
+
+// CreditRequest - пополнение кошелька.
+// Пустой IdempotencyKey заменяется NewIdempotencyKey().
+type CreditRequest struct {
+	Amount            string `json:"amount"` // Decimal string: "100.50"
+	IdempotencyKey    string `json:"idempotency_key"`
+	Description       string `json:"description"`
+	ExternalReference string `json:"external_reference,omitempty"`
+}
+
+// DebitRequest - списание с кошелька.
+// Пустой IdempotencyKey заменяется NewIdempotencyKey().
+type DebitRequest struct {
+	Amount            string `json:"amount"`
+	IdempotencyKey    string `json:"idempotency_key"`
+	Description       string `json:"description"`
+	ExternalReference string `json:"external_reference,omitempty"`
+}
+
+// TransferRequest - перевод с кошелька на другой кошелёк.
+// Пустой IdempotencyKey заменяется NewIdempotencyKey().
+type TransferRequest struct {
+	DestinationWalletID string `json:"destination_wallet_id"`
+	Amount              string `json:"amount"`
+	IdempotencyKey      string `json:"idempotency_key"`
+	Description         string `json:"description"`
+
+	// ConfirmNewPayee подтверждает первый перевод на этот кошелёк
+	// (иначе возможен ErrNewPayeeConfirmationRequired)
+	ConfirmNewPayee bool `json:"confirm_new_payee,omitempty"`
+}
+
+// ListTransactionsParams - фильтры и пагинация списка транзакций.
+// Нулевые поля не передаются.
+type ListTransactionsParams struct {
+	WalletID    string
+	Type        string // DEPOSIT, WITHDRAW, TRANSFER, ...
+	Status      string // PENDING, COMPLETED, FAILED, ...
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Page        int // С 1
+	PerPage     int // До 100
+}
+
+func (p ListTransactionsParams) values() url.Values {
+	q := url.Values{}
+	if p.WalletID != "" {
+		q.Set("wallet_id", p.WalletID)
+	}
+	if p.Type != "" {
+		q.Set("type", p.Type)
+	}
+	if p.Status != "" {
+		q.Set("status", p.Status)
+	}
+	if !p.CreatedFrom.IsZero() {
+		q.Set("created_from", p.CreatedFrom.Format(time.RFC3339Nano))
+	}
+	if !p.CreatedTo.IsZero() {
+		q.Set("created_to", p.CreatedTo.Format(time.RFC3339Nano))
+	}
+	if p.Page > 0 {
+		q.Set("page", strconv.Itoa(p.Page))
+	}
+	if p.PerPage > 0 {
+		q.Set("per_page", strconv.Itoa(p.PerPage))
+	}
+	return q
+}
+
+// ListWalletsParams - фильтры списка кошельков. Нулевые поля не передаются.
+type ListWalletsParams struct {
+	UserID       string
+	CurrencyCode string
+	Status       string // ACTIVE, SUSPENDED, LOCKED, CLOSED
+	PerPage      int    // До 100
+}
+
+func (p ListWalletsParams) values() url.Values {
+	q := url.Values{}
+	if p.UserID != "" {
+		q.Set("user_id", p.UserID)
+	}
+	if p.CurrencyCode != "" {
+		q.Set("currency_code", p.CurrencyCode)
+	}
+	if p.Status != "" {
+		q.Set("status", p.Status)
+	}
+	if p.PerPage > 0 {
+		q.Set("per_page", strconv.Itoa(p.PerPage))
+	}
+	return q
+}
+
+// ============================================
+// Wallets
+// ============================================
+
+// ListWallets возвращает Pager по списку кошельков:
+//
+//	pager := c.ListWallets(client.ListWalletsParams{CurrencyCode: "USD"})
+//	for pager.Next(ctx) {
+//		for _, wallet := range pager.Items() { ... }
+//	}
+//	if err := pager.Err(); err != nil { ... }
+func (c *Client) ListWallets(params ListWalletsParams) *Pager[Wallet] {
+	return newPager[Wallet](c, "/wallets", params.values())
+}
+
+// CreateWallet создаёт кошелёк текущего пользователя в валюте currencyCode.
+func (c *Client) CreateWallet(ctx context.Context, currencyCode string) (*Wallet, error) {
+	var wallet Wallet
+	body := map[string]string{"currency_code": currencyCode}
+	if _, err := c.do(ctx, http.MethodPost, "/wallets", nil, body, &wallet); err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// GetWallet возвращает кошелёк по ID.
+func (c *Client) GetWallet(ctx context.Context, walletID string) (*Wallet, error) {
+	var wallet Wallet
+	if _, err := c.do(ctx, http.MethodGet, "/wallets/"+url.PathEscape(walletID), nil, nil, &wallet); err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// Credit пополняет кошелёк. Повтор с тем же ключом возвращает исходную
+// операцию с IdempotentReplay = true.
+func (c *Client) Credit(ctx context.Context, walletID string, req CreditRequest) (*WalletOperation, error) {
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = NewIdempotencyKey()
+	}
+	var op WalletOperation
+	if _, err := c.do(ctx, http.MethodPost, "/wallets/"+url.PathEscape(walletID)+"/credit", nil, req, &op); err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// Debit списывает с кошелька. Повтор с тем же ключом возвращает исходную
+// операцию с IdempotentReplay = true.
+func (c *Client) Debit(ctx context.Context, walletID string, req DebitRequest) (*WalletOperation, error) {
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = NewIdempotencyKey()
+	}
+	var op WalletOperation
+	if _, err := c.do(ctx, http.MethodPost, "/wallets/"+url.PathEscape(walletID)+"/debit", nil, req, &op); err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// Transfer переводит средства с кошелька sourceWalletID.
+func (c *Client) Transfer(ctx context.Context, sourceWalletID string, req TransferRequest) (*TransferResult, error) {
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = NewIdempotencyKey()
+	}
+	var result TransferResult
+	if _, err := c.do(ctx, http.MethodPost, "/wallets/"+url.PathEscape(sourceWalletID)+"/transfer", nil, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ============================================
+// Transactions
+// ============================================
+
+// GetTransaction возвращает транзакцию по ID.
+func (c *Client) GetTransaction(ctx context.Context, transactionID string) (*Transaction, error) {
+	var tx Transaction
+	if _, err := c.do(ctx, http.MethodGet, "/transactions/"+url.PathEscape(transactionID), nil, nil, &tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// GetTransactionByIdempotencyKey возвращает транзакцию по ключу идемпотентности.
+// Нужен, чтобы узнать исход операции, ответ на которую потерян.
+func (c *Client) GetTransactionByIdempotencyKey(ctx context.Context, key string) (*Transaction, error) {
+	var tx Transaction
+	if _, err := c.do(ctx, http.MethodGet, "/transactions/by-key/"+url.PathEscape(key), nil, nil, &tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// ListTransactions возвращает страницу транзакций и мета-информацию пагинации.
+func (c *Client) ListTransactions(ctx context.Context, params ListTransactionsParams) (*TransactionList, *PageMeta, error) {
+	var list TransactionList
+	info, err := c.do(ctx, http.MethodGet, "/transactions", params.values(), nil, &list)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &list, info.Meta, nil
+}
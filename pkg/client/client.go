@@ -0,0 +1,334 @@
+// Package client - Go SDK для PayBridge API (/api/v1).
+//
+// Типы запросов описаны здесь, типы ответов - алиасы DTO из
+// internal/application/dtos: клиент декодирует ровно то, что отдают
+// handlers. Contract тесты (client_contract_test.go) гоняют клиент против
+// in-process сервера из internal/e2e, поэтому расхождение с API ломает
+// go test, а не интеграцию в проде.
+//
+// Пример:
+//
+//	c, err := client.NewClient("https://api.paybridge.example", client.WithToken(jwt))
+//	wallet, err := c.CreateWallet(ctx, "USD")
+//	op, err := c.Credit(ctx, wallet.ID, client.CreditRequest{
+//		Amount:      "100.00",
+//		Description: "Top up",
+//	})
+//	if errors.Is(err, client.ErrNotFound) { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiPrefix - префикс версии API.
+const apiPrefix = "/api/v1"
+
+// ============================================
+// Options
+// ============================================
+
+// RetryPolicy - повторы при 409 CONCURRENCY_ERROR и 429.
+//
+// Задержка - Retry-After ответа, если сервер его прислал, иначе
+// экспоненциальный backoff с jitter: BaseDelay, 2*BaseDelay, ... до MaxDelay.
+type RetryPolicy struct {
+	MaxAttempts int           // Всего попыток, включая первую; 1 - без повторов
+	BaseDelay   time.Duration // Задержка перед первым повтором
+	MaxDelay    time.Duration // Потолок задержки; Retry-After больше него не ждём и возвращаем ошибку
+}
+
+// DefaultRetryPolicy - 4 попытки, 100ms..5s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+	}
+}
+
+// Option настраивает Client.
+type Option func(*Client)
+
+// WithToken - аутентификация JWT access token'ом (Authorization: Bearer).
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.credential = token
+	}
+}
+
+// WithAPIKey - аутентификация API ключом сервиса.
+//
+// Ключ передаётся в том же заголовке Authorization: Bearer, что и JWT:
+// сервер различает их на стороне TokenValidator.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.credential = key
+	}
+}
+
+// WithHTTPClient задаёт http.Client (таймауты, transport, tracing).
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetryPolicy заменяет DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithUserAgent задаёт User-Agent (имя и версия вызывающего сервиса).
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// ============================================
+// Client
+// ============================================
+
+// Client - клиент PayBridge API. Безопасен для конкурентного использования.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	credential string
+	retry      RetryPolicy
+	userAgent  string
+
+	// sleep ждёт перед повтором (подменяется в тестах)
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewClient создаёт клиент. baseURL - адрес сервера без /api/v1.
+func NewClient(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: absolute http(s) URL required", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		retry:      DefaultRetryPolicy(),
+		userAgent:  "paybridge-go-client",
+		sleep:      sleepContext,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	if c.retry.BaseDelay <= 0 {
+		c.retry.BaseDelay = DefaultRetryPolicy().BaseDelay
+	}
+	if c.retry.MaxDelay <= 0 {
+		c.retry.MaxDelay = DefaultRetryPolicy().MaxDelay
+	}
+	return c, nil
+}
+
+// envelope - общий формат ответа API.
+type envelope struct {
+	Success   bool            `json:"success"`
+	Data      json.RawMessage `json:"data"`
+	Error     *errorBody      `json:"error"`
+	Meta      *PageMeta       `json:"meta"`
+	RequestID string          `json:"request_id"`
+
+	Pagination *Pagination `json:"pagination"`
+}
+
+type errorBody struct {
+	Code       string                 `json:"code"`
+	Message    string                 `json:"message"`
+	Details    map[string]interface{} `json:"details"`
+	Fields     []FieldError           `json:"fields"`
+	RetryAfter int                    `json:"retry_after"`
+}
+
+// PageMeta - пагинация списков.
+type PageMeta struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+// pageInfo - пагинация ответа списка: meta (page/per_page) или
+// pagination единого конверта страниц. Поля nil у ответов не-списков.
+type pageInfo struct {
+	Meta       *PageMeta
+	Pagination *Pagination
+}
+
+// do выполняет запрос с повторами и декодирует data в out (если out != nil).
+// Тело запроса сериализуется один раз и отправляется заново в каждой попытке.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) (pageInfo, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return pageInfo{}, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		info, retryAfter, err := c.attempt(ctx, method, path, query, payload, out)
+		if err == nil {
+			return info, nil
+		}
+
+		apiErr, ok := err.(*APIError)
+		if !ok || !apiErr.retryable() || attempt >= c.retry.MaxAttempts || retryAfter > c.retry.MaxDelay {
+			return pageInfo{}, err
+		}
+		if err := c.sleep(ctx, c.backoff(attempt, retryAfter)); err != nil {
+			return pageInfo{}, err
+		}
+	}
+}
+
+// attempt - одна попытка запроса. retryAfter - значение Retry-After ответа.
+func (c *Client) attempt(ctx context.Context, method, path string, query url.Values, payload []byte, out interface{}) (pageInfo, time.Duration, error) {
+	u := c.baseURL.JoinPath(apiPrefix, path)
+	u.RawQuery = query.Encode()
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return pageInfo{}, 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.credential != "" {
+		req.Header.Set("Authorization", "Bearer "+c.credential)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return pageInfo{}, 0, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return pageInfo{}, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var env envelope
+	decodeErr := json.Unmarshal(raw, &env)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := newAPIError(resp, env, decodeErr)
+		return pageInfo{}, parseRetryAfter(resp.Header.Get("Retry-After"), apiErr.RetryAfter), apiErr
+	}
+	if decodeErr != nil {
+		return pageInfo{}, 0, fmt.Errorf("failed to decode response (status %d): %w", resp.StatusCode, decodeErr)
+	}
+	if out != nil && len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, out); err != nil {
+			return pageInfo{}, 0, fmt.Errorf("failed to decode response data: %w", err)
+		}
+	}
+	return pageInfo{Meta: env.Meta, Pagination: env.Pagination}, 0, nil
+}
+
+// newAPIError собирает APIError из ответа; тело не в формате API
+// заменяется кодом по HTTP статусу.
+func newAPIError(resp *http.Response, env envelope, decodeErr error) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  env.RequestID,
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-ID")
+	}
+
+	if decodeErr == nil && env.Error != nil && env.Error.Code != "" {
+		apiErr.Code = env.Error.Code
+		apiErr.Message = env.Error.Message
+		apiErr.Details = env.Error.Details
+		apiErr.Fields = env.Error.Fields
+		apiErr.RetryAfter = env.Error.RetryAfter
+		return apiErr
+	}
+
+	apiErr.Code = statusCodes[resp.StatusCode]
+	if apiErr.Code == "" {
+		apiErr.Code = "HTTP_" + strconv.Itoa(resp.StatusCode)
+	}
+	apiErr.Message = http.StatusText(resp.StatusCode)
+	return apiErr
+}
+
+// parseRetryAfter читает Retry-After (секунды или HTTP-date),
+// при отсутствии - поле retry_after тела ошибки.
+func parseRetryAfter(header string, bodySeconds int) time.Duration {
+	if header != "" {
+		if seconds, err := strconv.Atoi(strings.TrimSpace(header)); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(header); err == nil {
+			if d := time.Until(at); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+	return time.Duration(bodySeconds) * time.Second
+}
+
+// backoff - задержка перед повтором номер attempt (с 1).
+func (c *Client) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	delay := c.retry.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > c.retry.MaxDelay {
+		delay = c.retry.MaxDelay
+	}
+	// Jitter в пределах [delay/2, delay): повторы конкурентов не совпадают
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + rand.N(half)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
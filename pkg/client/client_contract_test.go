@@ -0,0 +1,274 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/domain/events"
+	"github.com/Haleralex/wallethub/internal/e2e"
+	"github.com/Haleralex/wallethub/internal/infrastructure/eventbus"
+	"github.com/Haleralex/wallethub/pkg/client"
+)
+
+// ============================================
+// Contract tests: SDK против настоящих handlers
+// ============================================
+
+func newContractClient(t *testing.T) (*client.Client, *e2e.Server) {
+	t.Helper()
+
+	server := e2e.NewServer(t)
+	c, err := client.NewClient(server.URL, client.WithToken(server.SeedUser(t)))
+	require.NoError(t, err)
+	return c, server
+}
+
+func TestContract_WalletLifecycle(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newContractClient(t)
+
+	source, err := c.CreateWallet(ctx, "USD")
+	require.NoError(t, err)
+	assert.NotEmpty(t, source.ID)
+	assert.Equal(t, "USD", source.CurrencyCode)
+	assert.Equal(t, "ACTIVE", source.Status)
+
+	destination, err := c.CreateWallet(ctx, "EUR")
+	require.NoError(t, err)
+
+	// Credit с явным ключом
+	key := client.NewIdempotencyKey()
+	credit, err := c.Credit(ctx, source.ID, client.CreditRequest{
+		Amount:            "100.00",
+		IdempotencyKey:    key,
+		Description:       "Top up",
+		ExternalReference: "pi_123",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "100.00 USD", credit.Wallet.AvailableBalance)
+	assert.NotEmpty(t, credit.TransactionID)
+	assert.False(t, credit.IdempotentReplay)
+
+	// Повтор с тем же ключом - replay, деньги не двигаются
+	replay, err := c.Credit(ctx, source.ID, client.CreditRequest{
+		Amount:         "100.00",
+		IdempotencyKey: key,
+		Description:    "Top up",
+	})
+	require.NoError(t, err)
+	assert.True(t, replay.IdempotentReplay)
+	assert.Equal(t, credit.TransactionID, replay.TransactionID)
+
+	// Debit с автоматическим ключом
+	debit, err := c.Debit(ctx, source.ID, client.DebitRequest{Amount: "30.00", Description: "Payout"})
+	require.NoError(t, err)
+	assert.Equal(t, "70.00 USD", debit.Wallet.AvailableBalance)
+
+	wallet, err := c.GetWallet(ctx, source.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "70.00 USD", wallet.AvailableBalance)
+
+	tx, err := c.GetTransaction(ctx, debit.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, source.ID, tx.WalletID)
+	assert.Equal(t, "WITHDRAW", tx.Type)
+	assert.Equal(t, "30.00 USD", tx.Amount)
+
+	byKey, err := c.GetTransactionByIdempotencyKey(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, credit.TransactionID, byKey.ID)
+
+	// Перевод в другую валюту запрещён бизнес-правилом
+	_, err = c.Transfer(ctx, source.ID, client.TransferRequest{
+		DestinationWalletID: destination.ID,
+		Amount:              "10.00",
+		Description:         "Cross-currency",
+	})
+	assert.ErrorIs(t, err, client.ErrBusinessRule)
+	var apiErr *client.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+	assert.NotEmpty(t, apiErr.RequestID)
+}
+
+func TestContract_Transfer(t *testing.T) {
+	ctx := context.Background()
+	c, server := newContractClient(t)
+	recipient, err := client.NewClient(server.URL, client.WithToken(server.SeedUser(t)))
+	require.NoError(t, err)
+
+	source, err := c.CreateWallet(ctx, "USD")
+	require.NoError(t, err)
+	destination, err := recipient.CreateWallet(ctx, "USD")
+	require.NoError(t, err)
+
+	_, err = c.Credit(ctx, source.ID, client.CreditRequest{Amount: "50.00", Description: "Top up"})
+	require.NoError(t, err)
+
+	var (
+		mu        sync.Mutex
+		completed []*events.TransactionCompleted
+	)
+	eventbus.Subscribe(server.Bus, "contract", func(_ context.Context, e *events.TransactionCompleted) error {
+		mu.Lock()
+		defer mu.Unlock()
+		completed = append(completed, e)
+		return nil
+	})
+
+	result, err := c.Transfer(ctx, source.ID, client.TransferRequest{
+		DestinationWalletID: destination.ID,
+		Amount:              "20.00",
+		Description:         "Rent",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "30.00 USD", result.SourceWallet.AvailableBalance)
+	assert.Equal(t, "20.00 USD", result.DestinationWallet.AvailableBalance)
+	assert.Equal(t, "COMPLETED", result.Status)
+	assert.NotEmpty(t, result.TransactionID)
+
+	// Сервер в режиме sync: событие обработано до ответа, без ожидания
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, completed, 1)
+	assert.Equal(t, result.TransactionID, completed[0].TransactionID.String())
+}
+
+func TestContract_ListTransactions(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newContractClient(t)
+
+	wallet, err := c.CreateWallet(ctx, "USD")
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := c.Credit(ctx, wallet.ID, client.CreditRequest{Amount: "1.00", Description: "Top up"})
+		require.NoError(t, err)
+	}
+	_, err = c.Debit(ctx, wallet.ID, client.DebitRequest{Amount: "1.00", Description: "Payout"})
+	require.NoError(t, err)
+
+	list, meta, err := c.ListTransactions(ctx, client.ListTransactionsParams{
+		WalletID: wallet.ID,
+		Type:     "DEPOSIT",
+		Page:     1,
+		PerPage:  2,
+	})
+	require.NoError(t, err)
+	assert.Len(t, list.Transactions, 2)
+	for _, tx := range list.Transactions {
+		assert.Equal(t, "DEPOSIT", tx.Type)
+	}
+	require.NotNil(t, meta)
+	assert.Equal(t, 1, meta.Page)
+	assert.Equal(t, 2, meta.PerPage)
+	assert.Equal(t, list.TotalCount, meta.Total)
+
+	next, _, err := c.ListTransactions(ctx, client.ListTransactionsParams{
+		WalletID: wallet.ID,
+		Type:     "DEPOSIT",
+		Page:     2,
+		PerPage:  2,
+	})
+	require.NoError(t, err)
+	require.Len(t, next.Transactions, 1)
+	assert.NotContains(t, []string{list.Transactions[0].ID, list.Transactions[1].ID}, next.Transactions[0].ID)
+
+	// Окно created_from/created_to в будущем - пусто
+	future := time.Now().Add(time.Hour)
+	list, _, err = c.ListTransactions(ctx, client.ListTransactionsParams{
+		WalletID:    wallet.ID,
+		CreatedFrom: future,
+		CreatedTo:   future.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, list.Transactions)
+}
+
+func TestContract_ListWallets(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newContractClient(t)
+
+	created := map[string]bool{}
+	for _, currency := range []string{"USD", "EUR", "GBP"} {
+		wallet, err := c.CreateWallet(ctx, currency)
+		require.NoError(t, err)
+		created[wallet.ID] = true
+	}
+
+	pager := c.ListWallets(client.ListWalletsParams{PerPage: 2})
+	require.True(t, pager.Next(ctx))
+	first := pager.Items()
+	assert.Len(t, first, 2)
+	assert.True(t, pager.Pagination().HasMore)
+
+	rest, err := pager.All(ctx)
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.False(t, pager.Pagination().HasMore)
+
+	listed := map[string]bool{}
+	for _, wallet := range append(first, rest...) {
+		listed[wallet.ID] = true
+	}
+	assert.Equal(t, created, listed)
+
+	usd, err := c.ListWallets(client.ListWalletsParams{CurrencyCode: "USD"}).All(ctx)
+	require.NoError(t, err)
+	require.Len(t, usd, 1)
+	assert.Equal(t, "USD", usd[0].CurrencyCode)
+}
+
+func TestContract_Errors(t *testing.T) {
+	ctx := context.Background()
+	c, server := newContractClient(t)
+
+	wallet, err := c.CreateWallet(ctx, "USD")
+	require.NoError(t, err)
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := c.GetTransaction(ctx, uuid.NewString())
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := c.Credit(ctx, wallet.ID, client.CreditRequest{Amount: "-5", Description: "Bad"})
+		assert.ErrorIs(t, err, client.ErrValidation)
+
+		var apiErr *client.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		assert.NotEmpty(t, apiErr.Fields)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		anonymous, err := client.NewClient(server.URL)
+		require.NoError(t, err)
+
+		_, err = anonymous.GetWallet(ctx, wallet.ID)
+		assert.ErrorIs(t, err, client.ErrUnauthorized)
+	})
+
+	t.Run("forbidden for another user's wallet", func(t *testing.T) {
+		other, err := client.NewClient(server.URL, client.WithAPIKey(server.SeedUser(t)))
+		require.NoError(t, err)
+
+		_, err = other.Debit(ctx, wallet.ID, client.DebitRequest{Amount: "1.00", Description: "Steal"})
+		assert.ErrorIs(t, err, client.ErrForbidden)
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		_, err := c.Debit(ctx, wallet.ID, client.DebitRequest{Amount: "1.00", Description: "Payout"})
+		assert.ErrorIs(t, err, client.ErrInsufficientBalance)
+		assert.ErrorIs(t, err, client.ErrBusinessRule)
+		assert.False(t, errors.Is(err, client.ErrInternal))
+	})
+}
@@ -0,0 +1,423 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Haleralex/wallethub/internal/adapters/http/common"
+)
+
+// ============================================
+// Helpers
+// ============================================
+
+// scriptedServer отвечает по очереди заготовленными ответами и
+// запоминает тела запросов.
+type scriptedServer struct {
+	mu        sync.Mutex
+	responses []func(w http.ResponseWriter)
+	bodies    []map[string]interface{}
+	headers   []http.Header
+}
+
+func (s *scriptedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	s.bodies = append(s.bodies, body)
+	s.headers = append(s.headers, r.Header.Clone())
+
+	respond := s.responses[0]
+	if len(s.responses) > 1 {
+		s.responses = s.responses[1:]
+	}
+	respond(w)
+}
+
+func apiError(status int, code string, header map[string]string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for k, v := range header {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":    false,
+			"error":      map[string]interface{}{"code": code, "message": code},
+			"request_id": "req-1",
+		})
+	}
+}
+
+func okData(data interface{}) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": data})
+	}
+}
+
+// newScriptedClient возвращает клиент, который не спит, а записывает задержки.
+func newScriptedClient(t *testing.T, server *scriptedServer, opts ...Option) (*Client, *[]time.Duration) {
+	t.Helper()
+
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	c, err := NewClient(ts.URL, opts...)
+	require.NoError(t, err)
+
+	var delays []time.Duration
+	c.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return c, &delays
+}
+
+// ============================================
+// Retry
+// ============================================
+
+func TestClient_RetriesConcurrencyConflictWithSameIdempotencyKey(t *testing.T) {
+	server := &scriptedServer{responses: []func(http.ResponseWriter){
+		apiError(http.StatusConflict, "CONCURRENCY_ERROR", nil),
+		apiError(http.StatusConflict, "CONCURRENCY_ERROR", nil),
+		okData(map[string]interface{}{"transaction_id": "tx-1"}),
+	}}
+	c, delays := newScriptedClient(t, server)
+
+	op, err := c.Credit(context.Background(), "wallet-1", CreditRequest{Amount: "1.00", Description: "Top up"})
+	require.NoError(t, err)
+	assert.Equal(t, "tx-1", op.TransactionID)
+
+	require.Len(t, server.bodies, 3)
+	key := server.bodies[0]["idempotency_key"]
+	assert.NotEmpty(t, key)
+	for _, body := range server.bodies {
+		assert.Equal(t, key, body["idempotency_key"], "retries must reuse the generated key")
+	}
+
+	// Экспоненциальный backoff с jitter в [delay/2, delay)
+	require.Len(t, *delays, 2)
+	assert.GreaterOrEqual(t, (*delays)[0], 50*time.Millisecond)
+	assert.Less(t, (*delays)[0], 100*time.Millisecond)
+	assert.GreaterOrEqual(t, (*delays)[1], 100*time.Millisecond)
+	assert.Less(t, (*delays)[1], 200*time.Millisecond)
+}
+
+func TestClient_HonorsRetryAfter(t *testing.T) {
+	server := &scriptedServer{responses: []func(http.ResponseWriter){
+		apiError(http.StatusTooManyRequests, "TOO_MANY_REQUESTS", map[string]string{"Retry-After": "2"}),
+		okData(map[string]interface{}{"id": "tx-1"}),
+	}}
+	c, delays := newScriptedClient(t, server)
+
+	tx, err := c.GetTransaction(context.Background(), "tx-1")
+	require.NoError(t, err)
+	assert.Equal(t, "tx-1", tx.ID)
+	assert.Equal(t, []time.Duration{2 * time.Second}, *delays)
+}
+
+func TestClient_RetryAfterAboveMaxDelayIsReturned(t *testing.T) {
+	server := &scriptedServer{responses: []func(http.ResponseWriter){
+		apiError(http.StatusTooManyRequests, "WALLET_BUSY", map[string]string{"Retry-After": "60"}),
+	}}
+	c, delays := newScriptedClient(t, server)
+
+	_, err := c.GetWallet(context.Background(), "wallet-1")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.ErrorIs(t, err, ErrWalletBusy)
+	assert.Empty(t, *delays)
+	assert.Len(t, server.bodies, 1)
+}
+
+func TestClient_StopsAfterMaxAttempts(t *testing.T) {
+	server := &scriptedServer{responses: []func(http.ResponseWriter){
+		apiError(http.StatusConflict, "CONCURRENCY_ERROR", nil),
+	}}
+	c, _ := newScriptedClient(t, server, WithRetryPolicy(RetryPolicy{MaxAttempts: 2}))
+
+	_, err := c.Debit(context.Background(), "wallet-1", DebitRequest{Amount: "1.00", Description: "Payout"})
+	assert.ErrorIs(t, err, ErrConcurrency)
+	assert.Len(t, server.bodies, 2)
+}
+
+func TestClient_DoesNotRetryOtherErrors(t *testing.T) {
+	for _, code := range []string{"CONFLICT", "DUPLICATE_REQUEST"} {
+		t.Run(code, func(t *testing.T) {
+			server := &scriptedServer{responses: []func(http.ResponseWriter){
+				apiError(http.StatusConflict, code, nil),
+			}}
+			c, delays := newScriptedClient(t, server)
+
+			_, err := c.CreateWallet(context.Background(), "USD")
+			assert.Error(t, err)
+			assert.Empty(t, *delays)
+			assert.Len(t, server.bodies, 1)
+		})
+	}
+}
+
+func TestClient_RetryHonorsContext(t *testing.T) {
+	server := &scriptedServer{responses: []func(http.ResponseWriter){
+		apiError(http.StatusTooManyRequests, "TOO_MANY_REQUESTS", map[string]string{"Retry-After": "1"}),
+	}}
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	c, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = c.GetWallet(ctx, "wallet-1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// ============================================
+// Pager
+// ============================================
+
+// pagedServer отдаёт страницы по очереди и запоминает query запросов.
+func pagedServer(t *testing.T, pages ...map[string]interface{}) (*Client, *[]url.Values) {
+	t.Helper()
+
+	var queries []url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		page := pages[len(queries)-1]
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":    true,
+			"data":       page["data"],
+			"pagination": page["pagination"],
+		})
+	}))
+	t.Cleanup(ts.Close)
+
+	c, err := NewClient(ts.URL)
+	require.NoError(t, err)
+	return c, &queries
+}
+
+func TestPager_OffsetMode(t *testing.T) {
+	c, queries := pagedServer(t,
+		map[string]interface{}{
+			"data":       []map[string]string{{"id": "w1"}, {"id": "w2"}},
+			"pagination": map[string]interface{}{"mode": "offset", "offset": 0, "limit": 2, "has_more": true},
+		},
+		map[string]interface{}{
+			"data":       []map[string]string{{"id": "w3"}},
+			"pagination": map[string]interface{}{"mode": "offset", "offset": 2, "limit": 2, "has_more": false},
+		},
+	)
+
+	wallets, err := c.ListWallets(ListWalletsParams{Status: "ACTIVE", PerPage: 2}).All(context.Background())
+	require.NoError(t, err)
+	require.Len(t, wallets, 3)
+	assert.Equal(t, "w3", wallets[2].ID)
+
+	require.Len(t, *queries, 2)
+	assert.Empty(t, (*queries)[0].Get("page"))
+	assert.Equal(t, "2", (*queries)[1].Get("page"))
+	assert.Equal(t, "2", (*queries)[1].Get("per_page"))
+	assert.Equal(t, "ACTIVE", (*queries)[1].Get("status"))
+}
+
+func TestPager_CursorMode(t *testing.T) {
+	c, queries := pagedServer(t,
+		map[string]interface{}{
+			"data":       []map[string]string{{"id": "e1"}},
+			"pagination": map[string]interface{}{"mode": "cursor", "limit": 1, "next_cursor": "abc", "has_more": true},
+		},
+		map[string]interface{}{
+			"data":       []map[string]string{},
+			"pagination": map[string]interface{}{"mode": "cursor", "limit": 1, "has_more": false},
+		},
+	)
+
+	pager := newPager[Wallet](c, "/events", nil)
+	ctx := context.Background()
+
+	require.True(t, pager.Next(ctx))
+	assert.Len(t, pager.Items(), 1)
+	require.True(t, pager.Next(ctx))
+	assert.Empty(t, pager.Items())
+	assert.False(t, pager.Next(ctx))
+	require.NoError(t, pager.Err())
+
+	require.Len(t, *queries, 2)
+	assert.Equal(t, "abc", (*queries)[1].Get(CursorQueryParam))
+	assert.Empty(t, (*queries)[1].Get("page"))
+}
+
+func TestPager_ResponseWithoutPagination(t *testing.T) {
+	server := &scriptedServer{responses: []func(w http.ResponseWriter){
+		okData(map[string]interface{}{"wallets": []interface{}{}}),
+	}}
+	c, _ := newScriptedClient(t, server)
+
+	pager := c.ListWallets(ListWalletsParams{})
+	assert.False(t, pager.Next(context.Background()))
+	assert.Error(t, pager.Err())
+}
+
+// ============================================
+// Errors
+// ============================================
+
+func TestAPIError_Is(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     *APIError
+		matches []error
+		not     []error
+	}{
+		{
+			name:    "wallet not found",
+			err:     &APIError{StatusCode: http.StatusNotFound, Code: "WALLET_NOT_FOUND"},
+			matches: []error{ErrNotFound, ErrWalletNotFound},
+			not:     []error{ErrUserNotFound, ErrValidation},
+		},
+		{
+			name:    "business rule by details.rule",
+			err:     &APIError{StatusCode: http.StatusUnprocessableEntity, Code: "BUSINESS_RULE_VIOLATION", Details: map[string]interface{}{"rule": "EMAIL_ALREADY_EXISTS"}},
+			matches: []error{ErrBusinessRule, ErrEmailAlreadyExists},
+			not:     []error{ErrInsufficientBalance},
+		},
+		{
+			name:    "screening block by details.rule",
+			err:     &APIError{StatusCode: http.StatusUnprocessableEntity, Code: "BUSINESS_RULE_VIOLATION", Details: map[string]interface{}{"rule": "TRANSACTION_SCREENED"}},
+			matches: []error{ErrBusinessRule, ErrTransactionScreened},
+			not:     []error{ErrInsufficientBalance},
+		},
+		{
+			name:    "new payee limit by details.rule",
+			err:     &APIError{StatusCode: http.StatusUnprocessableEntity, Code: "BUSINESS_RULE_VIOLATION", Details: map[string]interface{}{"rule": "NEW_PAYEE_LIMIT_EXCEEDED"}},
+			matches: []error{ErrBusinessRule, ErrNewPayeeLimitExceeded},
+			not:     []error{ErrNewPayeeConfirmationRequired},
+		},
+		{
+			name:    "terms acceptance by details.rule",
+			err:     &APIError{StatusCode: http.StatusUnprocessableEntity, Code: "BUSINESS_RULE_VIOLATION", Details: map[string]interface{}{"rule": "TERMS_ACCEPTANCE_REQUIRED"}},
+			matches: []error{ErrBusinessRule, ErrTermsAcceptanceRequired},
+			not:     []error{ErrUserNotVerified},
+		},
+		{
+			name:    "concurrency is a conflict",
+			err:     &APIError{StatusCode: http.StatusConflict, Code: "CONCURRENCY_ERROR"},
+			matches: []error{ErrConflict, ErrConcurrency},
+		},
+		{
+			name: "unknown code",
+			err:  &APIError{StatusCode: http.StatusTeapot, Code: "HTTP_418"},
+			not:  []error{ErrInternal, ErrBadRequest},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error = tt.err
+			for _, target := range tt.matches {
+				assert.ErrorIs(t, err, target)
+			}
+			for _, target := range tt.not {
+				assert.False(t, errors.Is(err, target), "must not match %v", target)
+			}
+		})
+	}
+}
+
+// Каждый код из каталога HTTP слоя должен иметь sentinel
+func TestCodeErrors_CoverServerCatalog(t *testing.T) {
+	catalog := []string{
+		common.ErrCodeValidation,
+		common.ErrCodeNotFound,
+		common.ErrCodeBadRequest,
+		common.ErrCodeUnauthorized,
+		common.ErrCodeForbidden,
+		common.ErrCodeConflict,
+		common.ErrCodeTooManyRequests,
+		common.ErrCodeBusinessRule,
+		common.ErrCodeDuplicateRequest,
+		common.ErrCodeInternal,
+		common.ErrCodeConcurrency,
+		common.ErrCodeTimeout,
+		common.ErrCodeUnavailable,
+	}
+	for _, code := range catalog {
+		assert.NotEmpty(t, codeErrors[code], "no sentinel for %s", code)
+	}
+}
+
+func TestClient_NonAPIErrorBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}))
+	t.Cleanup(ts.Close)
+
+	c, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	_, err = c.GetWallet(context.Background(), "wallet-1")
+	assert.ErrorIs(t, err, ErrUnavailable)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+}
+
+// ============================================
+// Options
+// ============================================
+
+func TestNewClient_Validation(t *testing.T) {
+	for _, raw := range []string{"", "api.example.com", "ftp://api.example.com", "http://"} {
+		_, err := NewClient(raw)
+		assert.Error(t, err, raw)
+	}
+
+	c, err := NewClient("https://api.example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com", c.baseURL.String())
+}
+
+func TestClient_SendsCredentials(t *testing.T) {
+	for name, opt := range map[string]Option{
+		"token":   WithToken("jwt-token"),
+		"api key": WithAPIKey("jwt-token"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := &scriptedServer{responses: []func(http.ResponseWriter){okData(map[string]interface{}{})}}
+			c, _ := newScriptedClient(t, server, opt, WithUserAgent("ledger-sync/1.2"))
+
+			_, err := c.GetWallet(context.Background(), "wallet-1")
+			require.NoError(t, err)
+			assert.Equal(t, "Bearer jwt-token", server.headers[0].Get("Authorization"))
+			assert.Equal(t, "ledger-sync/1.2", server.headers[0].Get("User-Agent"))
+		})
+	}
+}
+
+func TestIdempotencyKeyFor(t *testing.T) {
+	key := IdempotencyKeyFor("order", "42", "payout")
+
+	assert.Equal(t, key, IdempotencyKeyFor("order", "42", "payout"))
+	assert.NotEqual(t, key, IdempotencyKeyFor("order", "42", "refund"))
+	assert.NotEqual(t, IdempotencyKeyFor("ab", "c"), IdempotencyKeyFor("a", "bc"))
+	assert.Len(t, key, 36)
+	assert.NotEqual(t, NewIdempotencyKey(), NewIdempotencyKey())
+}
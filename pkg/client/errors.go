@@ -0,0 +1,161 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ============================================
+// Sentinel errors
+// ============================================
+
+// Ошибки API, с которыми сравнивают через errors.Is:
+//
+//	_, err := c.Debit(ctx, walletID, req)
+//	if errors.Is(err, client.ErrInsufficientBalance) { ... }
+//
+// Одному коду может соответствовать несколько sentinel'ов: WALLET_NOT_FOUND
+// - это и ErrNotFound, и ErrWalletNotFound.
+var (
+	ErrValidation       = errors.New("paybridge: validation failed")
+	ErrBadRequest       = errors.New("paybridge: bad request")
+	ErrUnauthorized     = errors.New("paybridge: unauthorized")
+	ErrForbidden        = errors.New("paybridge: forbidden")
+	ErrNotFound         = errors.New("paybridge: not found")
+	ErrConflict         = errors.New("paybridge: conflict")
+	ErrConcurrency      = errors.New("paybridge: concurrent modification")
+	ErrRateLimited      = errors.New("paybridge: rate limited")
+	ErrBusinessRule     = errors.New("paybridge: business rule violation")
+	ErrDuplicateRequest = errors.New("paybridge: duplicate request")
+	ErrInternal         = errors.New("paybridge: internal server error")
+	ErrTimeout          = errors.New("paybridge: timeout")
+	ErrUnavailable      = errors.New("paybridge: service unavailable")
+
+	ErrUserNotFound        = errors.New("paybridge: user not found")
+	ErrWalletNotFound      = errors.New("paybridge: wallet not found")
+	ErrTransactionNotFound = errors.New("paybridge: transaction not found")
+	ErrInsufficientBalance = errors.New("paybridge: insufficient balance")
+	ErrUserNotVerified     = errors.New("paybridge: user not verified")
+	ErrWalletBusy          = errors.New("paybridge: wallet busy")
+	ErrConcurrencyLimited  = errors.New("paybridge: too many concurrent requests")
+	ErrEmailAlreadyExists  = errors.New("paybridge: email already exists")
+	ErrTransactionScreened = errors.New("paybridge: transaction blocked by screening rule")
+
+	ErrNewPayeeConfirmationRequired = errors.New("paybridge: new payee confirmation required")
+	ErrNewPayeeLimitExceeded        = errors.New("paybridge: new payee limit exceeded")
+	ErrTermsAcceptanceRequired      = errors.New("paybridge: terms of service acceptance required")
+)
+
+// codeErrors - каталог кодов ошибок API (error.code и error.details.rule).
+var codeErrors = map[string][]error{
+	"VALIDATION_ERROR":        {ErrValidation},
+	"BAD_REQUEST":             {ErrBadRequest},
+	"UNAUTHORIZED":            {ErrUnauthorized},
+	"ACTOR_REQUIRED":          {ErrUnauthorized},
+	"FORBIDDEN":               {ErrForbidden},
+	"POLICY_DENIED":           {ErrForbidden},
+	"NOT_FOUND":               {ErrNotFound},
+	"USER_NOT_FOUND":          {ErrNotFound, ErrUserNotFound},
+	"WALLET_NOT_FOUND":        {ErrNotFound, ErrWalletNotFound},
+	"TRANSACTION_NOT_FOUND":   {ErrNotFound, ErrTransactionNotFound},
+	"CONFLICT":                {ErrConflict},
+	"CONCURRENCY_ERROR":       {ErrConflict, ErrConcurrency},
+	"TOO_MANY_REQUESTS":       {ErrRateLimited},
+	"RATE_LIMITED":            {ErrRateLimited},
+	"WALLET_BUSY":             {ErrRateLimited, ErrWalletBusy},
+	"BUSINESS_RULE_VIOLATION": {ErrBusinessRule},
+	"INSUFFICIENT_BALANCE":    {ErrBusinessRule, ErrInsufficientBalance},
+	"USER_NOT_VERIFIED":       {ErrBusinessRule, ErrUserNotVerified},
+	"EMAIL_ALREADY_EXISTS":    {ErrConflict, ErrEmailAlreadyExists},
+	"TRANSACTION_SCREENED":    {ErrBusinessRule, ErrTransactionScreened},
+	"DUPLICATE_REQUEST":       {ErrDuplicateRequest},
+	"INTERNAL_ERROR":          {ErrInternal},
+	"TIMEOUT":                 {ErrTimeout},
+	"SERVICE_UNAVAILABLE":     {ErrUnavailable},
+
+	"NEW_PAYEE_CONFIRMATION_REQUIRED": {ErrBusinessRule, ErrNewPayeeConfirmationRequired},
+	"NEW_PAYEE_LIMIT_EXCEEDED":        {ErrBusinessRule, ErrNewPayeeLimitExceeded},
+	"TERMS_ACCEPTANCE_REQUIRED":       {ErrBusinessRule, ErrTermsAcceptanceRequired},
+	"CONCURRENCY_LIMIT_EXCEEDED":      {ErrRateLimited, ErrConcurrencyLimited},
+}
+
+// statusCodes - код по HTTP статусу, если тело ответа не в формате API
+// (например, 404 от router'а или 502 от балансировщика).
+var statusCodes = map[int]string{
+	http.StatusBadRequest:          "BAD_REQUEST",
+	http.StatusUnauthorized:        "UNAUTHORIZED",
+	http.StatusForbidden:           "FORBIDDEN",
+	http.StatusNotFound:            "NOT_FOUND",
+	http.StatusConflict:            "CONFLICT",
+	http.StatusTooManyRequests:     "TOO_MANY_REQUESTS",
+	http.StatusInternalServerError: "INTERNAL_ERROR",
+	http.StatusBadGateway:          "SERVICE_UNAVAILABLE",
+	http.StatusServiceUnavailable:  "SERVICE_UNAVAILABLE",
+	http.StatusGatewayTimeout:      "TIMEOUT",
+}
+
+// ============================================
+// APIError
+// ============================================
+
+// FieldError - ошибка валидации конкретного поля запроса.
+// Field - путь до поля ("amount", "items[3].idempotency_key"), Code - REQUIRED,
+// INVALID_FORMAT, INVALID_VALUE, OUT_OF_RANGE, INVALID_TYPE или UNKNOWN_FIELD.
+// Сервер возвращает все нарушения запроса сразу.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Code    string `json:"code"`
+}
+
+// APIError - ошибка, которую вернул сервер.
+//
+// Через errors.As доступны код, поля и request_id (для обращения в поддержку),
+// через errors.Is - сравнение с sentinel'ами пакета.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    map[string]interface{}
+	Fields     []FieldError
+	RetryAfter int // Секунд до повтора (429), 0 - не задано
+	RequestID  string
+}
+
+func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("paybridge: %d %s: %s (request_id %s)", e.StatusCode, e.Code, e.Message, e.RequestID)
+	}
+	return fmt.Sprintf("paybridge: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Rule возвращает правило BUSINESS_RULE_VIOLATION (details.rule), иначе "".
+func (e *APIError) Rule() string {
+	rule, _ := e.Details["rule"].(string)
+	return rule
+}
+
+// Is сопоставляет код ошибки (и правило бизнес-ошибки) с sentinel'ами.
+func (e *APIError) Is(target error) bool {
+	for _, code := range []string{e.Code, e.Rule()} {
+		for _, sentinel := range codeErrors[code] {
+			if sentinel == target {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// retryable сообщает, можно ли повторить запрос без изменений:
+// конфликт версий (409 CONCURRENCY_ERROR) и превышение лимитов (429).
+func (e *APIError) retryable() bool {
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests:
+		return true
+	case e.StatusCode == http.StatusConflict && e.Code == "CONCURRENCY_ERROR":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package client
+
+import "github.com/google/uuid"
+
+// idempotencyNamespace - namespace для детерминированных ключей (UUID v5).
+var idempotencyNamespace = uuid.MustParse("6f1c1b7e-3c55-4b0e-9a43-2f1d5c9a8e01")
+
+// NewIdempotencyKey возвращает случайный ключ идемпотентности (UUID v4).
+//
+// Credit, Debit и Transfer генерируют ключ сами, если он не задан, и
+// используют его во всех повторах одного вызова. Явный ключ нужен, чтобы
+// повторить операцию после перезапуска процесса: сохраните его до вызова.
+func NewIdempotencyKey() string {
+	return uuid.NewString()
+}
+
+// IdempotencyKeyFor возвращает детерминированный ключ (UUID v5) для
+// бизнес-идентификатора операции, например ID заказа:
+//
+//	key := client.IdempotencyKeyFor("order", orderID, "payout")
+//
+// Одинаковые части дают одинаковый ключ, поэтому повторная обработка того
+// же заказа не спишет деньги дважды. Части разделяются нулевым байтом:
+// ("ab", "c") и ("a", "bc") дают разные ключи.
+func IdempotencyKeyFor(parts ...string) string {
+	var name []byte
+	for i, part := range parts {
+		if i > 0 {
+			name = append(name, 0)
+		}
+		name = append(name, part...)
+	}
+	return uuid.NewSHA1(idempotencyNamespace, name).String()
+}
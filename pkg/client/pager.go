@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Haleralex/wallethub/internal/application/dtos"
+)
+
+// ============================================
+// Pagination
+// ============================================
+
+// CursorQueryParam - query параметр следующей страницы cursor-списков.
+const CursorQueryParam = "cursor"
+
+// Pager обходит список в конверте {data, pagination} страница за страницей.
+//
+// Режим пагинации скрыт: для offset-списков следующий запрос сдвигает
+// page, для cursor-списков передаёт next_cursor. Обход заканчивается,
+// когда сервер вернул has_more = false.
+type Pager[T any] struct {
+	client *Client
+	path   string
+
+	next       url.Values // Запрос следующей страницы; nil - страниц больше нет
+	items      []T
+	pagination *Pagination
+	err        error
+}
+
+func newPager[T any](c *Client, path string, query url.Values) *Pager[T] {
+	if query == nil {
+		query = url.Values{}
+	}
+	return &Pager[T]{client: c, path: path, next: query}
+}
+
+// Next загружает следующую страницу. false - страниц больше нет
+// или запрос завершился ошибкой (см. Err).
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.err != nil || p.next == nil {
+		return false
+	}
+
+	var items []T
+	info, err := p.client.do(ctx, http.MethodGet, p.path, p.next, nil, &items)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	if info.Pagination == nil {
+		p.err = fmt.Errorf("response of %s has no pagination", p.path)
+		return false
+	}
+
+	p.items = items
+	p.pagination = info.Pagination
+	p.next = nextPageQuery(p.next, info.Pagination)
+	return true
+}
+
+// Items возвращает элементы текущей страницы.
+func (p *Pager[T]) Items() []T {
+	return p.items
+}
+
+// Pagination возвращает описание текущей страницы (nil до первого Next).
+func (p *Pager[T]) Pagination() *Pagination {
+	return p.pagination
+}
+
+// Err возвращает ошибку, остановившую обход.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// All обходит оставшиеся страницы и возвращает их элементы одним списком.
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for p.Next(ctx) {
+		all = append(all, p.items...)
+	}
+	return all, p.err
+}
+
+// nextPageQuery строит запрос страницы, следующей за pagination.
+func nextPageQuery(current url.Values, pagination *Pagination) url.Values {
+	if !pagination.HasMore {
+		return nil
+	}
+
+	next := make(url.Values, len(current)+1)
+	for key, values := range current {
+		next[key] = values
+	}
+
+	switch pagination.Mode {
+	case dtos.PaginationModeCursor:
+		if pagination.NextCursor == "" {
+			return nil
+		}
+		next.Set(CursorQueryParam, pagination.NextCursor)
+	default:
+		if pagination.Limit <= 0 {
+			return nil
+		}
+		offset := 0
+		if pagination.Offset != nil {
+			offset = *pagination.Offset
+		}
+		// Номер страницы считается по фактическим offset/limit ответа:
+		// сервер мог урезать per_page до своего максимума
+		next.Set("per_page", strconv.Itoa(pagination.Limit))
+		next.Set("page", strconv.Itoa(offset/pagination.Limit+2))
+	}
+	return next
+}